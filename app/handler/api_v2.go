@@ -0,0 +1,690 @@
+package handler
+
+import (
+	"net/http"
+
+	"waverless/internal/model"
+	"waverless/internal/service"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/modelregistry"
+	"waverless/pkg/response"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The v2 list APIs share one shape: {items, nextCursor, total?} envelopes paged with the limit
+// and cursor query parameters, camelCase fields and RFC3339 UTC timestamps (null when unset).
+
+// bindPageRequest parses the limit and cursor query parameters, answering 400 when they are invalid
+func bindPageRequest(c *gin.Context) (response.PageRequest, bool) {
+	req, err := response.ParsePageRequest(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	return req, true
+}
+
+// TaskItem is a task in a v2 task list
+type TaskItem struct {
+	ID              string        `json:"id"`
+	Status          string        `json:"status"`
+	Endpoint        string        `json:"endpoint"`
+	WorkerID        string        `json:"workerId,omitempty"`
+	Error           string        `json:"error,omitempty"`
+	DelayTimeMs     int64         `json:"delayTimeMs"`     // Queue wait, 0 until started
+	ExecutionTimeMs int64         `json:"executionTimeMs"` // Execution time, 0 until completed
+	CreatedAt       response.Time `json:"createdAt"`
+	StartedAt       response.Time `json:"startedAt"`
+	CompletedAt     response.Time `json:"completedAt"`
+}
+
+func newTaskItem(task *model.Task) TaskItem {
+	item := TaskItem{
+		ID:          task.ID,
+		Status:      string(task.Status),
+		Endpoint:    task.Endpoint,
+		WorkerID:    task.WorkerID,
+		Error:       task.Error,
+		CreatedAt:   response.NewTime(task.CreatedAt),
+		StartedAt:   response.NewTimePtr(task.StartedAt),
+		CompletedAt: response.NewTimePtr(task.CompletedAt),
+	}
+	if task.StartedAt != nil {
+		item.DelayTimeMs = task.StartedAt.Sub(task.CreatedAt).Milliseconds()
+		if task.CompletedAt != nil {
+			item.ExecutionTimeMs = task.CompletedAt.Sub(*task.StartedAt).Milliseconds()
+		}
+	}
+	return item
+}
+
+// ListTasksV2 lists tasks, newest first
+// @Summary List tasks (v2)
+// @Tags tasks
+// @Produce json
+// @Param status query string false "Task status"
+// @Param endpoint query string false "Endpoint name"
+// @Param worker_id query string false "Worker ID"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[TaskItem]
+// @Router /api/v2/tasks [get]
+func (h *TaskHandler) ListTasksV2(c *gin.Context) {
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	tasks, total, err := h.taskService.ListTaskRecords(c.Request.Context(), c.Query("status"), c.Query("endpoint"), "", c.Query("worker_id"), page.Limit, page.Offset)
+	if err != nil {
+		logger.ErrorCtx(c.Request.Context(), "failed to list tasks: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(tasks, page, &total), newTaskItem))
+}
+
+// EndpointItem is an endpoint in a v2 endpoint list
+type EndpointItem struct {
+	Name          string                   `json:"name"`
+	DisplayName   string                   `json:"displayName,omitempty"`
+	Provider      string                   `json:"provider,omitempty"`
+	SpecName      string                   `json:"specName"`
+	SpecOverride  *interfaces.SpecOverride `json:"specOverride,omitempty"` // Resources added to the spec
+	Image         string                   `json:"image"`
+	Status        string                   `json:"status"`
+	HealthStatus  string                   `json:"healthStatus,omitempty"`
+	Replicas      int                      `json:"replicas"`
+	ReadyReplicas int                      `json:"readyReplicas"`
+	MinReplicas   int                      `json:"minReplicas"`
+	MaxReplicas   int                      `json:"maxReplicas"`
+	CreatedAt     response.Time            `json:"createdAt"`
+	UpdatedAt     response.Time            `json:"updatedAt"`
+}
+
+func newEndpointItem(meta *interfaces.EndpointMetadata) EndpointItem {
+	return EndpointItem{
+		Name:          meta.Name,
+		DisplayName:   meta.DisplayName,
+		Provider:      meta.Provider,
+		SpecName:      meta.SpecName,
+		SpecOverride:  meta.SpecOverride,
+		Image:         meta.Image,
+		Status:        meta.Status,
+		HealthStatus:  meta.HealthStatus,
+		Replicas:      meta.Replicas,
+		ReadyReplicas: meta.ReadyReplicas,
+		MinReplicas:   meta.MinReplicas,
+		MaxReplicas:   meta.MaxReplicas,
+		CreatedAt:     response.NewTime(meta.CreatedAt),
+		UpdatedAt:     response.NewTime(meta.UpdatedAt),
+	}
+}
+
+// ListEndpointsV2 lists endpoints, ordered by name
+// @Summary List endpoints (v2)
+// @Tags Endpoints
+// @Produce json
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[EndpointItem]
+// @Router /api/v2/endpoints [get]
+func (h *EndpointHandler) ListEndpointsV2(c *gin.Context) {
+	if h.endpointService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "endpoint service unavailable"})
+		return
+	}
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	endpoints, total, err := h.endpointService.ListEndpointsPage(c.Request.Context(), page.Limit, page.Offset)
+	if err != nil {
+		respondProviderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(endpoints, page, &total), newEndpointItem))
+}
+
+// WorkerQuarantineItem is the quarantine of a worker in a v2 worker list
+type WorkerQuarantineItem struct {
+	Reason string        `json:"reason"`
+	By     string        `json:"by,omitempty"`
+	At     response.Time `json:"at"`
+	BanID  *int64        `json:"banId,omitempty"`
+}
+
+// WorkerItem is a worker in a v2 worker list
+type WorkerItem struct {
+	ID            string                `json:"id"`
+	Endpoint      string                `json:"endpoint"`
+	PodName       string                `json:"podName,omitempty"`
+	NodeName      string                `json:"nodeName,omitempty"`
+	ImageDigest   string                `json:"imageDigest,omitempty"`
+	Status        string                `json:"status"`
+	Version       string                `json:"version,omitempty"`
+	Concurrency   int                   `json:"concurrency"`
+	CurrentJobs   int                   `json:"currentJobs"`
+	FailureType   string                `json:"failureType,omitempty"`
+	FailureReason string                `json:"failureReason,omitempty"`
+	Quarantine    *WorkerQuarantineItem `json:"quarantine,omitempty"`
+	RegisteredAt  response.Time         `json:"registeredAt"`
+	LastHeartbeat response.Time         `json:"lastHeartbeat"`
+	LastTaskTime  response.Time         `json:"lastTaskTime"`
+	PodStartedAt  response.Time         `json:"podStartedAt"`
+	TerminatedAt  response.Time         `json:"terminatedAt"`
+}
+
+func newWorkerItem(worker *mysqlModel.Worker) WorkerItem {
+	item := WorkerItem{
+		ID:            worker.WorkerID,
+		Endpoint:      worker.Endpoint,
+		PodName:       worker.PodName,
+		NodeName:      worker.NodeName(),
+		ImageDigest:   worker.ImageDigest(),
+		Status:        worker.Status,
+		Version:       worker.Version,
+		Concurrency:   worker.Concurrency,
+		CurrentJobs:   worker.CurrentJobs,
+		FailureType:   worker.FailureType,
+		FailureReason: worker.FailureReason,
+		RegisteredAt:  response.NewTime(worker.CreatedAt),
+		LastHeartbeat: response.NewTime(worker.LastHeartbeat),
+		LastTaskTime:  response.NewTimePtr(worker.LastTaskTime),
+		PodStartedAt:  response.NewTimePtr(worker.PodStartedAt),
+		TerminatedAt:  response.NewTimePtr(worker.TerminatedAt),
+	}
+	if q := service.WorkerQuarantineOf(worker); q != nil {
+		item.Quarantine = &WorkerQuarantineItem{Reason: q.Reason, By: q.By, At: response.NewTime(q.At), BanID: q.BanID}
+	}
+	return item
+}
+
+// ListEndpointWorkersV2 lists the active workers of an endpoint, oldest first
+// @Summary List endpoint workers (v2)
+// @Tags Endpoints
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[WorkerItem]
+// @Router /api/v2/endpoints/{name}/workers [get]
+func (h *EndpointHandler) ListEndpointWorkersV2(c *gin.Context) {
+	if h.workerService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "worker service unavailable"})
+		return
+	}
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	endpoint := c.Param("name")
+	workers, total, err := h.workerService.ListEndpointWorkers(c.Request.Context(), endpoint, page.Limit, page.Offset)
+	if err != nil {
+		logger.ErrorCtx(c.Request.Context(), "Failed to get workers for endpoint %s: %v", endpoint, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(workers, page, &total), newWorkerItem))
+}
+
+// WorkerBanItem is a ban in a v2 worker ban list
+type WorkerBanItem struct {
+	ID        int64         `json:"id"`
+	Kind      string        `json:"kind"`
+	Value     string        `json:"value"`
+	Reason    string        `json:"reason,omitempty"`
+	CreatedBy string        `json:"createdBy,omitempty"`
+	CreatedAt response.Time `json:"createdAt"`
+	Workers   []string      `json:"workers"` // Workers the ban quarantines
+}
+
+func newWorkerBanItem(ban *service.WorkerBanDetail) WorkerBanItem {
+	return WorkerBanItem{
+		ID:        ban.ID,
+		Kind:      ban.Kind,
+		Value:     ban.Value,
+		Reason:    ban.Reason,
+		CreatedBy: ban.CreatedBy,
+		CreatedAt: response.NewTime(ban.CreatedAt),
+		Workers:   ban.Workers,
+	}
+}
+
+// ListWorkerBansV2 lists the node and image bans, newest first
+// @Summary List worker bans (v2)
+// @Tags worker
+// @Produce json
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[WorkerBanItem]
+// @Router /api/v2/worker-bans [get]
+func (h *WorkerHandler) ListWorkerBansV2(c *gin.Context) {
+	if h.quarantineService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker quarantine not available"})
+		return
+	}
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	bans, total, err := h.quarantineService.ListBansPage(c.Request.Context(), page.Limit, page.Offset)
+	if err != nil {
+		respondQuarantineError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(bans, page, &total), newWorkerBanItem))
+}
+
+// OperationItem is an operation in a v2 operation list
+type OperationItem struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Target      string                 `json:"target,omitempty"`
+	Status      string                 `json:"status"`
+	Progress    int                    `json:"progress"` // Percent
+	Message     string                 `json:"message,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	Result      map[string]interface{} `json:"result,omitempty"`
+	CreatedBy   string                 `json:"createdBy,omitempty"`
+	CreatedAt   response.Time          `json:"createdAt"`
+	StartedAt   response.Time          `json:"startedAt"`
+	CompletedAt response.Time          `json:"completedAt"`
+	UpdatedAt   response.Time          `json:"updatedAt"`
+}
+
+func newOperationItem(op *mysqlModel.Operation) OperationItem {
+	return OperationItem{
+		ID:          op.ID,
+		Type:        op.Type,
+		Target:      op.Target,
+		Status:      op.Status,
+		Progress:    op.Progress,
+		Message:     op.Message,
+		Error:       op.Error,
+		Result:      op.Result,
+		CreatedBy:   op.CreatedBy,
+		CreatedAt:   response.NewTime(op.CreatedAt),
+		StartedAt:   response.NewTimePtr(op.StartedAt),
+		CompletedAt: response.NewTimePtr(op.CompletedAt),
+		UpdatedAt:   response.NewTime(op.UpdatedAt),
+	}
+}
+
+// ListOperationsV2 lists operations, newest first, including the backfill, replay and batch jobs
+// @Summary List operations (v2)
+// @Tags operations
+// @Produce json
+// @Param type query string false "Operation type"
+// @Param target query string false "Target, e.g. the endpoint"
+// @Param status query string false "Operation status"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[OperationItem]
+// @Router /api/v2/operations [get]
+func (h *OperationHandler) ListOperationsV2(c *gin.Context) {
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	filter := mysql.OperationFilter{
+		Type:   c.Query("type"),
+		Target: c.Query("target"),
+		Status: c.Query("status"),
+	}
+	total, err := h.operationService.Count(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ops, err := h.operationService.List(c.Request.Context(), filter, page.Limit, page.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(ops, page, &total), newOperationItem))
+}
+
+// ChangeRequestItem is a change request in a v2 change request list; secrets in the payload
+// are masked
+type ChangeRequestItem struct {
+	ID            int64                  `json:"id"`
+	Endpoint      string                 `json:"endpoint"`
+	Operation     string                 `json:"operation"`
+	Payload       map[string]interface{} `json:"payload,omitempty"`
+	Status        string                 `json:"status"`
+	RequestedBy   string                 `json:"requestedBy"`
+	ReviewedBy    string                 `json:"reviewedBy,omitempty"`
+	ReviewComment string                 `json:"reviewComment,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+	CreatedAt     response.Time          `json:"createdAt"`
+	ExpiresAt     response.Time          `json:"expiresAt"`
+	ReviewedAt    response.Time          `json:"reviewedAt"`
+	ExecutedAt    response.Time          `json:"executedAt"`
+}
+
+func newChangeRequestItem(cr *mysqlModel.ChangeRequest) ChangeRequestItem {
+	cr = service.RedactChangeRequest(cr)
+	return ChangeRequestItem{
+		ID:            cr.ID,
+		Endpoint:      cr.Endpoint,
+		Operation:     cr.Operation,
+		Payload:       cr.Payload,
+		Status:        cr.Status,
+		RequestedBy:   cr.RequestedBy,
+		ReviewedBy:    cr.ReviewedBy,
+		ReviewComment: cr.ReviewComment,
+		Error:         cr.Error,
+		CreatedAt:     response.NewTime(cr.CreatedAt),
+		ExpiresAt:     response.NewTime(cr.ExpiresAt),
+		ReviewedAt:    response.NewTimePtr(cr.ReviewedAt),
+		ExecutedAt:    response.NewTimePtr(cr.ExecutedAt),
+	}
+}
+
+// ListChangeRequestsV2 lists change requests, newest first
+// @Summary List change requests (v2)
+// @Tags change-requests
+// @Produce json
+// @Param endpoint query string false "Endpoint name"
+// @Param status query string false "Change request status"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[ChangeRequestItem]
+// @Router /api/v2/change-requests [get]
+func (h *ChangeRequestHandler) ListChangeRequestsV2(c *gin.Context) {
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	endpoint, status := c.Query("endpoint"), c.Query("status")
+	requests, err := h.changeRequestService.List(c.Request.Context(), endpoint, status, page.Limit, page.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	total, err := h.changeRequestService.Count(c.Request.Context(), endpoint, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(requests, page, &total), newChangeRequestItem))
+}
+
+// ReplayJobItem is a replay job in a v2 replay job list
+type ReplayJobItem struct {
+	ID             int64         `json:"id"`
+	Status         string        `json:"status"`
+	SourceEndpoint string        `json:"sourceEndpoint"`
+	TargetEndpoint string        `json:"targetEndpoint"`
+	From           response.Time `json:"from"`
+	To             response.Time `json:"to"`
+	Statuses       []string      `json:"statuses,omitempty"` // Task statuses replayed (empty = all finished)
+	RateLimit      int           `json:"rateLimit"`          // Tasks per second
+	MaxTasks       int           `json:"maxTasks"`
+	Submitted      int64         `json:"submitted"`
+	Skipped        int64         `json:"skipped"`
+	LastError      string        `json:"lastError,omitempty"`
+	CreatedBy      string        `json:"createdBy,omitempty"`
+	CreatedAt      response.Time `json:"createdAt"`
+	StartedAt      response.Time `json:"startedAt"`
+	CompletedAt    response.Time `json:"completedAt"`
+}
+
+func newReplayJobItem(job *mysqlModel.TaskReplayJob) ReplayJobItem {
+	return ReplayJobItem{
+		ID:             job.ID,
+		Status:         job.Status,
+		SourceEndpoint: job.SourceEndpoint,
+		TargetEndpoint: job.TargetEndpoint,
+		From:           response.NewTime(job.FromTime),
+		To:             response.NewTime(job.ToTime),
+		Statuses:       job.Statuses,
+		RateLimit:      job.RateLimit,
+		MaxTasks:       job.MaxTasks,
+		Submitted:      job.Submitted,
+		Skipped:        job.Skipped,
+		LastError:      job.LastError,
+		CreatedBy:      job.CreatedBy,
+		CreatedAt:      response.NewTime(job.CreatedAt),
+		StartedAt:      response.NewTimePtr(job.StartedAt),
+		CompletedAt:    response.NewTimePtr(job.CompletedAt),
+	}
+}
+
+// ListReplayJobsV2 lists replay jobs, newest first
+// @Summary List replay jobs (v2)
+// @Tags replays
+// @Produce json
+// @Param status query string false "Replay job status"
+// @Param target query string false "Endpoint replayed into"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[ReplayJobItem]
+// @Router /api/v2/replays [get]
+func (h *TaskReplayHandler) ListReplayJobsV2(c *gin.Context) {
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	status, target := c.Query("status"), c.Query("target")
+	jobs, err := h.replayService.ListJobs(c.Request.Context(), status, target, page.Limit, page.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	total, err := h.replayService.CountJobs(c.Request.Context(), status, target)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(jobs, page, &total), newReplayJobItem))
+}
+
+// BatchJobItem is a batch job in a v2 job list
+type BatchJobItem struct {
+	Name         string        `json:"name"`
+	SpecName     string        `json:"specName"`
+	Image        string        `json:"image"`
+	GPUCount     int           `json:"gpuCount"`
+	GPUType      string        `json:"gpuType,omitempty"`
+	Status       string        `json:"status"`
+	Message      string        `json:"message,omitempty"`
+	ExitCode     *int          `json:"exitCode,omitempty"`
+	GPUHours     float64       `json:"gpuHours"`
+	CreatedBy    string        `json:"createdBy,omitempty"`
+	ScheduleName string        `json:"scheduleName,omitempty"` // Recurring schedule that started the run
+	Attempt      int           `json:"attempt,omitempty"`
+	CreatedAt    response.Time `json:"createdAt"`
+	ScheduledAt  response.Time `json:"scheduledAt"`
+	StartedAt    response.Time `json:"startedAt"`
+	CompletedAt  response.Time `json:"completedAt"`
+}
+
+func newBatchJobItem(job *mysqlModel.BatchJob) BatchJobItem {
+	return BatchJobItem{
+		Name:         job.Name,
+		SpecName:     job.SpecName,
+		Image:        job.Image,
+		GPUCount:     job.GpuCount,
+		GPUType:      job.GPUType,
+		Status:       job.Status,
+		Message:      job.Message,
+		ExitCode:     job.ExitCode,
+		GPUHours:     job.GPUHours,
+		CreatedBy:    job.CreatedBy,
+		ScheduleName: job.ScheduleName,
+		Attempt:      job.Attempt,
+		CreatedAt:    response.NewTime(job.CreatedAt),
+		ScheduledAt:  response.NewTimePtr(job.ScheduledAt),
+		StartedAt:    response.NewTimePtr(job.StartedAt),
+		CompletedAt:  response.NewTimePtr(job.CompletedAt),
+	}
+}
+
+// ListJobsV2 lists batch jobs, newest first
+// @Summary List batch jobs (v2)
+// @Tags jobs
+// @Produce json
+// @Param status query string false "Job status"
+// @Param limit query int false "Page size (default 50, max 100)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[BatchJobItem]
+// @Router /api/v2/jobs [get]
+func (h *BatchJobHandler) ListJobsV2(c *gin.Context) {
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	status := c.Query("status")
+	jobs, err := h.jobService.List(c.Request.Context(), status, page.Limit, page.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	total, err := h.jobService.Count(c.Request.Context(), status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(jobs, page, &total), newBatchJobItem))
+}
+
+// LoadTestReportItem is a report in a v2 load test report list, without its curves
+type LoadTestReportItem struct {
+	ID           int64         `json:"id"`
+	LoadTest     string        `json:"loadTest"`
+	Endpoint     string        `json:"endpoint"`
+	OperationID  string        `json:"operationId,omitempty"`
+	Status       string        `json:"status"`
+	Image        string        `json:"image"`
+	ModelVersion string        `json:"modelVersion,omitempty"`
+	TriggeredBy  string        `json:"triggeredBy,omitempty"`
+	Requests     int           `json:"requests"`
+	ErrorRate    float64       `json:"errorRate"`
+	P50Ms        float64       `json:"p50Ms"`
+	P99Ms        float64       `json:"p99Ms"`
+	AchievedRPS  float64       `json:"achievedRps"`
+	PeakReplicas int           `json:"peakReplicas"`
+	BaselineID   int64         `json:"baselineId,omitempty"`
+	Regressed    bool          `json:"regressed"`
+	Error        string        `json:"error,omitempty"`
+	StartedAt    response.Time `json:"startedAt"`
+	FinishedAt   response.Time `json:"finishedAt"`
+}
+
+func newLoadTestReportItem(report *mysqlModel.LoadTestReport) LoadTestReportItem {
+	return LoadTestReportItem{
+		ID:           report.ID,
+		LoadTest:     report.LoadTest,
+		Endpoint:     report.Endpoint,
+		OperationID:  report.OperationID,
+		Status:       report.Status,
+		Image:        report.Image,
+		ModelVersion: report.ModelVersion,
+		TriggeredBy:  report.TriggeredBy,
+		Requests:     report.Requests,
+		ErrorRate:    report.ErrorRate,
+		P50Ms:        report.P50Ms,
+		P99Ms:        report.P99Ms,
+		AchievedRPS:  report.AchievedRPS,
+		PeakReplicas: report.PeakReplicas,
+		BaselineID:   report.BaselineID,
+		Regressed:    report.Regressed,
+		Error:        report.Error,
+		StartedAt:    response.NewTime(report.StartedAt),
+		FinishedAt:   response.NewTimePtr(report.FinishedAt),
+	}
+}
+
+// ListReportsV2 lists the reports of a load test, newest first
+// @Summary List load test reports (v2)
+// @Tags load-tests
+// @Produce json
+// @Param name path string true "Load test name"
+// @Param limit query int false "Page size (default 50, max 365)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[LoadTestReportItem]
+// @Router /api/v2/load-tests/{name}/reports [get]
+func (h *LoadTestHandler) ListReportsV2(c *gin.Context) {
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	name := c.Param("name")
+	reports, err := h.loadTestService.Reports(c.Request.Context(), name, page.Limit, page.Offset)
+	if err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+	total, err := h.loadTestService.CountReports(c.Request.Context(), name)
+	if err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(reports, page, &total), newLoadTestReportItem))
+}
+
+// ModelVersionItem is a model registry version in a v2 model version list
+type ModelVersionItem struct {
+	Registry string            `json:"registry"`
+	Model    string            `json:"model"`
+	Version  string            `json:"version"`
+	Stage    string            `json:"stage,omitempty"` // Approval stage or status in the registry
+	Image    string            `json:"image"`           // Empty when the version cannot be deployed
+	Env      map[string]string `json:"env,omitempty"`
+	URL      string            `json:"url,omitempty"`
+}
+
+func newModelVersionItem(mv *modelregistry.ModelVersion) ModelVersionItem {
+	return ModelVersionItem{
+		Registry: mv.Registry,
+		Model:    mv.Model,
+		Version:  mv.Version,
+		Stage:    mv.Stage,
+		Image:    mv.Image,
+		Env:      mv.Env,
+		URL:      mv.URL,
+	}
+}
+
+// ListModelVersionsV2 lists the versions of a model in the model registry, newest first. The
+// registry does not count versions, so the page has no total.
+// @Summary List model versions (v2)
+// @Tags Endpoints
+// @Produce json
+// @Param model path string true "Registered model name"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.Page[ModelVersionItem]
+// @Router /api/v2/models/{model}/versions [get]
+func (h *EndpointHandler) ListModelVersionsV2(c *gin.Context) {
+	if h.endpointService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "endpoint service unavailable"})
+		return
+	}
+	page, ok := bindPageRequest(c)
+	if !ok {
+		return
+	}
+
+	// One extra version tells whether another page exists
+	versions, err := h.endpointService.ListModelVersions(c.Request.Context(), c.Param("model"), page.Limit+1, page.Offset)
+	if err != nil {
+		respondModelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.Map(response.NewPage(versions, page, nil), newModelVersionItem))
+}
@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	"waverless/internal/service"
 	endpointsvc "waverless/internal/service/endpoint"
 	"waverless/pkg/autoscaler"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +20,7 @@ import (
 type AutoScalerHandler struct{
 	manager         *autoscaler.Manager
 	endpointService *endpointsvc.Service
+	changeRequests  *service.ChangeRequestService // Approval gate for protected endpoints (optional)
 }
 
 // NewAutoScalerHandler creates autoscaler handler
@@ -26,6 +31,15 @@ func NewAutoScalerHandler(manager *autoscaler.Manager, endpointService *endpoint
 	}
 }
 
+// SetChangeRequestService holds autoscaling and replica schedule changes of protected endpoints for approval
+func (h *AutoScalerHandler) SetChangeRequestService(svc *service.ChangeRequestService) {
+	h.changeRequests = svc
+	svc.RegisterExecutor(model.ChangeOpUpdateAutoscaling, h.executeUpdateAutoscaling)
+	svc.RegisterExecutor(model.ChangeOpCreateReplicaSchedule, h.executeCreateReplicaSchedule)
+	svc.RegisterExecutor(model.ChangeOpUpdateReplicaSchedule, h.executeUpdateReplicaSchedule)
+	svc.RegisterExecutor(model.ChangeOpDeleteReplicaSchedule, h.executeDeleteReplicaSchedule)
+}
+
 // GetStatus gets autoscaler status
 // @Summary Get autoscaler status
 // @Description Get current autoscaler system status, including cluster resources, endpoint status, etc.
@@ -93,6 +107,17 @@ func (h *AutoScalerHandler) GetRecentEvents(c *gin.Context) {
 	c.JSON(http.StatusOK, events)
 }
 
+// GetStartupTimes gets the learned startup time per spec and image
+// @Summary Get startup time estimates
+// @Description Typical time (p90) from scale-up to worker ready per spec, and per spec and image, learned from cold-start metrics
+// @Tags AutoScaler
+// @Produce json
+// @Success 200 {array} autoscaler.StartupTime
+// @Router /api/v1/autoscaler/startup-times [get]
+func (h *AutoScalerHandler) GetStartupTimes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.manager.GetStartupTimes(c.Request.Context()))
+}
+
 // Enable enables autoscaler
 // @Summary Enable autoscaler
 // @Description Enable autoscaling functionality
@@ -187,6 +212,13 @@ func (h *AutoScalerHandler) UpdateEndpointConfig(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
 		return
 	}
+	if err := validateAutoscalingUpdate(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if holdForApproval(c, h.changeRequests, name, model.ChangeOpUpdateAutoscaling, nil, updates) {
+		return
+	}
 
 	// Get existing metadata to preserve fields not being updated
 	existingMeta, err := h.endpointService.GetEndpoint(c.Request.Context(), name)
@@ -197,72 +229,122 @@ func (h *AutoScalerHandler) UpdateEndpointConfig(c *gin.Context) {
 	}
 
 	// Merge autoscaling config updates into existing metadata
+	mergeAutoscalingUpdate(existingMeta, &updates)
+
+	// Update metadata
+	if err := h.endpointService.UpdateEndpoint(c.Request.Context(), existingMeta); err != nil {
+		logger.ErrorCtx(c.Request.Context(), "failed to update endpoint config: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "endpoint config updated: %s (maxReplicas=%d, minReplicas=%d, priority=%d)",
+		name, existingMeta.MaxReplicas, existingMeta.MinReplicas, existingMeta.Priority)
+	c.JSON(http.StatusOK, gin.H{"status": "updated"})
+}
+
+// validateAutoscalingUpdate checks the fields of an autoscaling update that can be rejected
+func validateAutoscalingUpdate(updates *interfaces.EndpointMetadata) error {
+	if updates.EvaluationInterval > 0 && updates.EvaluationInterval < autoscaler.MinEvaluationInterval {
+		return fmt.Errorf("evaluationInterval must be at least %d seconds", autoscaler.MinEvaluationInterval)
+	}
+	if updates.MetricSources != nil {
+		return autoscaler.ValidateMetricSources(updates.MetricSources)
+	}
+	return nil
+}
+
+// mergeAutoscalingUpdate merges a validated autoscaling update into the endpoint metadata
+func mergeAutoscalingUpdate(meta, updates *interfaces.EndpointMetadata) {
 	// Only update fields that are explicitly provided (non-zero)
 	if updates.MinReplicas >= 0 {
-		existingMeta.MinReplicas = updates.MinReplicas
+		meta.MinReplicas = updates.MinReplicas
 	}
 	if updates.MaxReplicas > 0 {
-		existingMeta.MaxReplicas = updates.MaxReplicas
+		meta.MaxReplicas = updates.MaxReplicas
 	}
 	if updates.ScaleUpThreshold > 0 {
-		existingMeta.ScaleUpThreshold = updates.ScaleUpThreshold
+		meta.ScaleUpThreshold = updates.ScaleUpThreshold
 	}
 	if updates.ScaleDownIdleTime > 0 {
-		existingMeta.ScaleDownIdleTime = updates.ScaleDownIdleTime
+		meta.ScaleDownIdleTime = updates.ScaleDownIdleTime
 	}
 	if updates.ScaleUpCooldown > 0 {
-		existingMeta.ScaleUpCooldown = updates.ScaleUpCooldown
+		meta.ScaleUpCooldown = updates.ScaleUpCooldown
 	}
 	if updates.ScaleDownCooldown > 0 {
-		existingMeta.ScaleDownCooldown = updates.ScaleDownCooldown
+		meta.ScaleDownCooldown = updates.ScaleDownCooldown
 	}
 	if updates.Priority >= 0 {
-		existingMeta.Priority = updates.Priority
+		meta.Priority = updates.Priority
 	}
 	if updates.HighLoadThreshold > 0 {
-		existingMeta.HighLoadThreshold = updates.HighLoadThreshold
+		meta.HighLoadThreshold = updates.HighLoadThreshold
 	}
 	if updates.PriorityBoost > 0 {
-		existingMeta.PriorityBoost = updates.PriorityBoost
+		meta.PriorityBoost = updates.PriorityBoost
 	}
 	if updates.EnableDynamicPrio != nil {
-		existingMeta.EnableDynamicPrio = updates.EnableDynamicPrio
+		meta.EnableDynamicPrio = updates.EnableDynamicPrio
 	}
 	// Update autoscaler enabled override (three-state: nil/default, "disabled", "enabled")
 	if updates.AutoscalerEnabled != nil {
-		existingMeta.AutoscalerEnabled = updates.AutoscalerEnabled
+		meta.AutoscalerEnabled = updates.AutoscalerEnabled
+	}
+	if updates.CustomMetricName != "" {
+		meta.CustomMetricName = updates.CustomMetricName
+	}
+	if updates.CustomMetricTarget > 0 {
+		meta.CustomMetricTarget = updates.CustomMetricTarget
+	}
+	if updates.EvaluationInterval > 0 {
+		meta.EvaluationInterval = updates.EvaluationInterval
+	}
+	if updates.QueueWaitSLO > 0 {
+		meta.QueueWaitSLO = updates.QueueWaitSLO
+	}
+	if updates.MetricSources != nil {
+		meta.MetricSources = updates.MetricSources
 	}
 
 	// Also update basic fields if provided
 	if updates.DisplayName != "" {
-		existingMeta.DisplayName = updates.DisplayName
+		meta.DisplayName = updates.DisplayName
 	}
 	if updates.Description != "" {
-		existingMeta.Description = updates.Description
+		meta.Description = updates.Description
 	}
 	if updates.SpecName != "" {
-		existingMeta.SpecName = updates.SpecName
+		meta.SpecName = updates.SpecName
 	}
 	if updates.Image != "" {
-		existingMeta.Image = updates.Image
+		meta.Image = updates.Image
 	}
 	if updates.Replicas > 0 {
-		existingMeta.Replicas = updates.Replicas
+		meta.Replicas = updates.Replicas
 	}
 	if updates.TaskTimeout > 0 {
-		existingMeta.TaskTimeout = updates.TaskTimeout
+		meta.TaskTimeout = updates.TaskTimeout
 	}
+}
 
-	// Update metadata
-	if err := h.endpointService.UpdateEndpoint(c.Request.Context(), existingMeta); err != nil {
-		logger.ErrorCtx(c.Request.Context(), "failed to update endpoint config: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+func (h *AutoScalerHandler) executeUpdateAutoscaling(ctx context.Context, cr *model.ChangeRequest) error {
+	var updates interfaces.EndpointMetadata
+	if err := service.DecodeChangePayload(cr, &updates); err != nil {
+		return err
 	}
-
-	logger.InfoCtx(c.Request.Context(), "endpoint config updated: %s (maxReplicas=%d, minReplicas=%d, priority=%d)",
-		name, existingMeta.MaxReplicas, existingMeta.MinReplicas, existingMeta.Priority)
-	c.JSON(http.StatusOK, gin.H{"status": "updated"})
+	if err := validateAutoscalingUpdate(&updates); err != nil {
+		return err
+	}
+	meta, err := h.endpointService.GetEndpoint(ctx, cr.Endpoint)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return fmt.Errorf("endpoint %s not found", cr.Endpoint)
+	}
+	mergeAutoscalingUpdate(meta, &updates)
+	return h.endpointService.UpdateEndpoint(ctx, meta)
 }
 
 // GetEndpointConfig gets endpoint autoscaling configuration
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"waverless/internal/service"
+	"waverless/pkg/autoscaler"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListReplicaSchedules lists the replica schedules of an endpoint with their current state
+// @Summary List replica schedules
+// @Description Cron-based windows overriding the min/max replicas of the endpoint, with whether each is active and when it next starts
+// @Tags AutoScaler
+// @Param name path string true "Endpoint name"
+// @Produce json
+// @Success 200 {array} autoscaler.ReplicaScheduleStatus
+// @Router /api/v1/autoscaler/endpoints/{name}/schedules [get]
+func (h *AutoScalerHandler) ListReplicaSchedules(c *gin.Context) {
+	name := c.Param("name")
+	meta, ok := h.getScheduledEndpoint(c, name)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, autoscaler.DescribeReplicaSchedules(c.Request.Context(), name, meta.ReplicaSchedules, time.Now()))
+}
+
+// CreateReplicaSchedule adds a replica schedule to an endpoint
+// @Summary Create replica schedule
+// @Tags AutoScaler
+// @Accept json
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param schedule body interfaces.ReplicaSchedule true "Replica schedule"
+// @Success 201 {object} interfaces.ReplicaSchedule
+// @Router /api/v1/autoscaler/endpoints/{name}/schedules [post]
+func (h *AutoScalerHandler) CreateReplicaSchedule(c *gin.Context) {
+	var schedule interfaces.ReplicaSchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := c.Param("name")
+	if !validReplicaSchedule(c, schedule) {
+		return
+	}
+	if holdForApproval(c, h.changeRequests, name, model.ChangeOpCreateReplicaSchedule, nil, schedule) {
+		return
+	}
+	meta, ok := h.getScheduledEndpoint(c, name)
+	if !ok {
+		return
+	}
+	if replicaScheduleIndex(meta.ReplicaSchedules, schedule.Name) >= 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("replica schedule %s already exists", schedule.Name)})
+		return
+	}
+	if !h.saveReplicaSchedules(c, meta, append(meta.ReplicaSchedules, schedule)) {
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Replica schedule created: endpoint=%s, schedule=%s, cron=%q, duration=%dm, min=%d, max=%d, by=%s",
+		name, schedule.Name, schedule.Cron, schedule.DurationMinutes, schedule.MinReplicas, schedule.MaxReplicas, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateReplicaSchedule replaces a replica schedule of an endpoint
+// @Summary Update replica schedule
+// @Tags AutoScaler
+// @Accept json
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param schedule path string true "Schedule name"
+// @Param body body interfaces.ReplicaSchedule true "Replica schedule (name is taken from the path)"
+// @Success 200 {object} interfaces.ReplicaSchedule
+// @Router /api/v1/autoscaler/endpoints/{name}/schedules/{schedule} [put]
+func (h *AutoScalerHandler) UpdateReplicaSchedule(c *gin.Context) {
+	var schedule interfaces.ReplicaSchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	schedule.Name = c.Param("schedule")
+	name := c.Param("name")
+	if !validReplicaSchedule(c, schedule) {
+		return
+	}
+	if holdForApproval(c, h.changeRequests, name, model.ChangeOpUpdateReplicaSchedule, nil, schedule) {
+		return
+	}
+	meta, ok := h.getScheduledEndpoint(c, name)
+	if !ok {
+		return
+	}
+	i := replicaScheduleIndex(meta.ReplicaSchedules, schedule.Name)
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("replica schedule %s not found", schedule.Name)})
+		return
+	}
+	schedules := append([]interfaces.ReplicaSchedule{}, meta.ReplicaSchedules...)
+	schedules[i] = schedule
+	if !h.saveReplicaSchedules(c, meta, schedules) {
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Replica schedule updated: endpoint=%s, schedule=%s, cron=%q, duration=%dm, min=%d, max=%d, by=%s",
+		name, schedule.Name, schedule.Cron, schedule.DurationMinutes, schedule.MinReplicas, schedule.MaxReplicas, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteReplicaSchedule removes a replica schedule from an endpoint
+// @Summary Delete replica schedule
+// @Tags AutoScaler
+// @Param name path string true "Endpoint name"
+// @Param schedule path string true "Schedule name"
+// @Success 200 {object} map[string]string
+// @Router /api/v1/autoscaler/endpoints/{name}/schedules/{schedule} [delete]
+func (h *AutoScalerHandler) DeleteReplicaSchedule(c *gin.Context) {
+	name, scheduleName := c.Param("name"), c.Param("schedule")
+	if holdForApproval(c, h.changeRequests, name, model.ChangeOpDeleteReplicaSchedule, nil, replicaScheduleRef{Name: scheduleName}) {
+		return
+	}
+	meta, ok := h.getScheduledEndpoint(c, name)
+	if !ok {
+		return
+	}
+	i := replicaScheduleIndex(meta.ReplicaSchedules, scheduleName)
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("replica schedule %s not found", scheduleName)})
+		return
+	}
+	schedules := append(append([]interfaces.ReplicaSchedule{}, meta.ReplicaSchedules[:i]...), meta.ReplicaSchedules[i+1:]...)
+	if !h.saveReplicaSchedules(c, meta, schedules) {
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Replica schedule deleted: endpoint=%s, schedule=%s, by=%s", name, scheduleName, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusOK, gin.H{"message": "replica schedule deleted"})
+}
+
+// getScheduledEndpoint loads the endpoint whose schedules are managed, answering 404 when it doesn't exist
+func (h *AutoScalerHandler) getScheduledEndpoint(c *gin.Context, name string) (*interfaces.EndpointMetadata, bool) {
+	meta, err := h.endpointService.GetEndpoint(c.Request.Context(), name)
+	if err != nil || meta == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "endpoint not found"})
+		return nil, false
+	}
+	return meta, true
+}
+
+// validReplicaSchedule rejects a schedule that is invalid on its own before it is held for
+// approval; the full list is validated again when it is saved
+func validReplicaSchedule(c *gin.Context, schedule interfaces.ReplicaSchedule) bool {
+	if err := autoscaler.ValidateReplicaSchedules([]interfaces.ReplicaSchedule{schedule}); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// saveReplicaSchedules validates and stores the replica schedules of an endpoint
+func (h *AutoScalerHandler) saveReplicaSchedules(c *gin.Context, meta *interfaces.EndpointMetadata, schedules []interfaces.ReplicaSchedule) bool {
+	if err := autoscaler.ValidateReplicaSchedules(schedules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	meta.ReplicaSchedules = schedules
+	if err := h.endpointService.UpdateEndpoint(c.Request.Context(), meta); err != nil {
+		logger.ErrorCtx(c.Request.Context(), "failed to update replica schedules of %s: %v", meta.Name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// replicaScheduleRef names the replica schedule a held deletion removes
+type replicaScheduleRef struct {
+	Name string `json:"name"`
+}
+
+func (h *AutoScalerHandler) executeCreateReplicaSchedule(ctx context.Context, cr *model.ChangeRequest) error {
+	var schedule interfaces.ReplicaSchedule
+	if err := service.DecodeChangePayload(cr, &schedule); err != nil {
+		return err
+	}
+	return h.applyReplicaSchedules(ctx, cr.Endpoint, func(schedules []interfaces.ReplicaSchedule) ([]interfaces.ReplicaSchedule, error) {
+		if replicaScheduleIndex(schedules, schedule.Name) >= 0 {
+			return nil, fmt.Errorf("replica schedule %s already exists", schedule.Name)
+		}
+		return append(schedules, schedule), nil
+	})
+}
+
+func (h *AutoScalerHandler) executeUpdateReplicaSchedule(ctx context.Context, cr *model.ChangeRequest) error {
+	var schedule interfaces.ReplicaSchedule
+	if err := service.DecodeChangePayload(cr, &schedule); err != nil {
+		return err
+	}
+	return h.applyReplicaSchedules(ctx, cr.Endpoint, func(schedules []interfaces.ReplicaSchedule) ([]interfaces.ReplicaSchedule, error) {
+		i := replicaScheduleIndex(schedules, schedule.Name)
+		if i < 0 {
+			return nil, fmt.Errorf("replica schedule %s not found", schedule.Name)
+		}
+		schedules = append([]interfaces.ReplicaSchedule{}, schedules...)
+		schedules[i] = schedule
+		return schedules, nil
+	})
+}
+
+func (h *AutoScalerHandler) executeDeleteReplicaSchedule(ctx context.Context, cr *model.ChangeRequest) error {
+	var ref replicaScheduleRef
+	if err := service.DecodeChangePayload(cr, &ref); err != nil {
+		return err
+	}
+	return h.applyReplicaSchedules(ctx, cr.Endpoint, func(schedules []interfaces.ReplicaSchedule) ([]interfaces.ReplicaSchedule, error) {
+		i := replicaScheduleIndex(schedules, ref.Name)
+		if i < 0 {
+			return nil, fmt.Errorf("replica schedule %s not found", ref.Name)
+		}
+		return append(append([]interfaces.ReplicaSchedule{}, schedules[:i]...), schedules[i+1:]...), nil
+	})
+}
+
+// applyReplicaSchedules changes the replica schedules of an endpoint when a held change is approved
+func (h *AutoScalerHandler) applyReplicaSchedules(ctx context.Context, endpoint string, change func([]interfaces.ReplicaSchedule) ([]interfaces.ReplicaSchedule, error)) error {
+	meta, err := h.endpointService.GetEndpoint(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return fmt.Errorf("endpoint %s not found", endpoint)
+	}
+	schedules, err := change(meta.ReplicaSchedules)
+	if err != nil {
+		return err
+	}
+	if err := autoscaler.ValidateReplicaSchedules(schedules); err != nil {
+		return err
+	}
+	meta.ReplicaSchedules = schedules
+	return h.endpointService.UpdateEndpoint(ctx, meta)
+}
+
+func replicaScheduleIndex(schedules []interfaces.ReplicaSchedule, name string) int {
+	for i, schedule := range schedules {
+		if schedule.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchJobHandler handles one-off jobs
+type BatchJobHandler struct {
+	jobService *service.BatchJobService
+}
+
+// NewBatchJobHandler creates a new batch job handler
+func NewBatchJobHandler(jobService *service.BatchJobService) *BatchJobHandler {
+	return &BatchJobHandler{jobService: jobService}
+}
+
+// respondBatchJobError maps "invalid" errors to 400, "not found" to 404 and a provider
+// without job support to 501; provider errors keep their kind
+func respondBatchJobError(c *gin.Context, err error) {
+	switch msg := err.Error(); {
+	case strings.HasPrefix(msg, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	case strings.HasPrefix(msg, "job ") && strings.HasSuffix(msg, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "not supported"):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": msg})
+	default:
+		respondProviderError(c, err)
+	}
+}
+
+// SubmitJob starts a one-off job
+// POST /api/v1/jobs
+func (h *BatchJobHandler) SubmitJob(c *gin.Context) {
+	var req service.SubmitBatchJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requestedBy := c.GetHeader(RequestedByHeader)
+	job, err := h.jobService.Submit(c.Request.Context(), &req, requestedBy)
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Job submitted: name=%s, spec=%s, image=%s, gpus=%d, by=%s",
+		job.Name, job.SpecName, job.Image, job.GpuCount, requestedBy)
+	c.JSON(http.StatusCreated, job)
+}
+
+// ListJobs lists the most recent jobs
+// GET /api/v1/jobs?status=running&limit=50
+func (h *BatchJobHandler) ListJobs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	jobs, err := h.jobService.List(c.Request.Context(), c.Query("status"), limit, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+// GetJob gets a job with its current state
+// GET /api/v1/jobs/:name
+func (h *BatchJobHandler) GetJob(c *gin.Context) {
+	job, err := h.jobService.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelJob stops a pending or running job
+// POST /api/v1/jobs/:name/cancel
+func (h *BatchJobHandler) CancelJob(c *gin.Context) {
+	job, err := h.jobService.Cancel(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Job cancelled: name=%s, by=%s", job.Name, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusOK, job)
+}
+
+// GetJobLogs returns the logs of a job as plain text. With follow=true the response streams
+// new lines until the job exits or the client disconnects.
+// GET /api/v1/jobs/:name/logs?lines=100&follow=true
+func (h *BatchJobHandler) GetJobLogs(c *gin.Context) {
+	lines, err := strconv.Atoi(c.DefaultQuery("lines", "100"))
+	if err != nil {
+		lines = 100
+	}
+	follow := c.Query("follow") == "true"
+
+	stream, err := h.jobService.Logs(c.Request.Context(), c.Param("name"), follow, lines)
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+	defer stream.Close()
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := stream.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
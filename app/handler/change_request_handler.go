@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeRequestHandler handles review of changes held for approval
+type ChangeRequestHandler struct {
+	changeRequestService *service.ChangeRequestService
+}
+
+// NewChangeRequestHandler creates a new change request handler
+func NewChangeRequestHandler(changeRequestService *service.ChangeRequestService) *ChangeRequestHandler {
+	return &ChangeRequestHandler{changeRequestService: changeRequestService}
+}
+
+// ReviewChangeRequest optional request body for approving or rejecting a change request.
+// The reviewer is the identity of the X-Approval-Token header.
+type ReviewChangeRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// ListChangeRequests lists change requests, newest first
+// GET /api/v1/change-requests?endpoint=&status=&limit=
+func (h *ChangeRequestHandler) ListChangeRequests(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	requests, err := h.changeRequestService.List(c.Request.Context(), c.Query("endpoint"), c.Query("status"), limit, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	redacted := make([]*model.ChangeRequest, len(requests))
+	for i, cr := range requests {
+		redacted[i] = service.RedactChangeRequest(cr)
+	}
+	c.JSON(http.StatusOK, gin.H{"changeRequests": redacted})
+}
+
+// GetChangeRequest gets a change request
+// GET /api/v1/change-requests/:id
+func (h *ChangeRequestHandler) GetChangeRequest(c *gin.Context) {
+	id, ok := changeRequestID(c)
+	if !ok {
+		return
+	}
+	cr, err := h.changeRequestService.Get(c.Request.Context(), id)
+	if err != nil {
+		respondChangeRequestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, service.RedactChangeRequest(cr))
+}
+
+// ApproveChangeRequest approves a pending change request and applies it
+// POST /api/v1/change-requests/:id/approve
+func (h *ChangeRequestHandler) ApproveChangeRequest(c *gin.Context) {
+	id, ok := changeRequestID(c)
+	if !ok {
+		return
+	}
+	var req ReviewChangeRequest
+	if !bindReviewRequest(c, &req) {
+		return
+	}
+
+	cr, err := h.changeRequestService.Approve(c.Request.Context(), id, c.GetHeader(ApprovalTokenHeader), req.Comment)
+	if err != nil {
+		respondChangeRequestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, service.RedactChangeRequest(cr))
+}
+
+// RejectChangeRequest rejects (or, by the requester, withdraws) a pending change request
+// POST /api/v1/change-requests/:id/reject
+func (h *ChangeRequestHandler) RejectChangeRequest(c *gin.Context) {
+	id, ok := changeRequestID(c)
+	if !ok {
+		return
+	}
+	var req ReviewChangeRequest
+	if !bindReviewRequest(c, &req) {
+		return
+	}
+
+	cr, err := h.changeRequestService.Reject(c.Request.Context(), id, c.GetHeader(ApprovalTokenHeader), req.Comment)
+	if err != nil {
+		respondChangeRequestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, service.RedactChangeRequest(cr))
+}
+
+func bindReviewRequest(c *gin.Context, req *ReviewChangeRequest) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func changeRequestID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid change request id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func respondChangeRequestError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrApprovalUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, service.ErrApprovalForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, service.ErrChangeRequestClosed):
+		status = http.StatusConflict
+	case strings.Contains(err.Error(), "not found"):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"waverless/internal/service"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CircuitBreakerHandler handles the circuit breakers of endpoints
+type CircuitBreakerHandler struct {
+	circuitBreaker *service.CircuitBreakerService
+	changeRequests *service.ChangeRequestService // Approval gate for protected endpoints (optional)
+}
+
+// NewCircuitBreakerHandler creates a new circuit breaker handler
+func NewCircuitBreakerHandler(circuitBreaker *service.CircuitBreakerService) *CircuitBreakerHandler {
+	return &CircuitBreakerHandler{circuitBreaker: circuitBreaker}
+}
+
+// SetChangeRequestService holds closing the circuit of protected endpoints for approval
+func (h *CircuitBreakerHandler) SetChangeRequestService(svc *service.ChangeRequestService) {
+	h.changeRequests = svc
+	svc.RegisterExecutor(model.ChangeOpCloseCircuit, h.executeClose)
+}
+
+// CloseEndpointCircuit closes the circuit breaker of an endpoint without waiting for a probe,
+// e.g. after a fixed image was deployed
+// POST /api/v1/endpoints/:name/circuit/close
+func (h *CircuitBreakerHandler) CloseEndpointCircuit(c *gin.Context) {
+	if holdForApproval(c, h.changeRequests, c.Param("name"), model.ChangeOpCloseCircuit, nil, nil) {
+		return
+	}
+	if err := h.circuitBreaker.Close(c.Request.Context(), c.Param("name"), c.GetHeader(RequestedByHeader)); err != nil {
+		respondPauseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "circuit breaker closed"})
+}
+
+func (h *CircuitBreakerHandler) executeClose(ctx context.Context, cr *model.ChangeRequest) error {
+	return h.circuitBreaker.Close(ctx, cr.Endpoint, cr.RequestedBy)
+}
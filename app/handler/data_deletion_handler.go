@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubjectKeyHeader tags a submitted task with the data subject it belongs to
+const SubjectKeyHeader = "X-Subject-Key"
+
+// DataDeletionHandler handles data deletion requests by subject key
+type DataDeletionHandler struct {
+	deletionService *service.DataDeletionService
+}
+
+// NewDataDeletionHandler creates a new data deletion handler
+func NewDataDeletionHandler(deletionService *service.DataDeletionService) *DataDeletionHandler {
+	return &DataDeletionHandler{deletionService: deletionService}
+}
+
+// CreateDeletion purges or anonymizes all task data of a subject and returns the report
+// POST /api/v1/data-deletions
+func (h *DataDeletionHandler) CreateDeletion(c *gin.Context) {
+	var req service.DataDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.deletionService.Delete(c.Request.Context(), &req, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListDeletions lists data deletion requests with their reports, newest first
+// GET /api/v1/data-deletions
+func (h *DataDeletionHandler) ListDeletions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	requests, err := h.deletionService.List(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"requests": requests})
+}
+
+// GetDeletion gets a data deletion request with its report
+// GET /api/v1/data-deletions/:id
+func (h *DataDeletionHandler) GetDeletion(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	req, err := h.deletionService.Get(c.Request.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, req)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"waverless/internal/service"
+	"waverless/pkg/backup"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BackupPassphraseHeader carries the passphrase that seals/opens registry credentials.
+// A header keeps it out of access logs that record query strings.
+const BackupPassphraseHeader = "X-Backup-Passphrase"
+
+// DisasterRecoveryHandler handles control-plane export/import APIs
+type DisasterRecoveryHandler struct {
+	drService     *service.DisasterRecoveryService
+	bundleService *service.ControlPlaneBundleService
+}
+
+// NewDisasterRecoveryHandler creates a new disaster recovery handler
+func NewDisasterRecoveryHandler(drService *service.DisasterRecoveryService) *DisasterRecoveryHandler {
+	return &DisasterRecoveryHandler{drService: drService}
+}
+
+// SetBundleService enables the control-plane config bundle API
+func (h *DisasterRecoveryHandler) SetBundleService(svc *service.ControlPlaneBundleService) {
+	h.bundleService = svc
+}
+
+// Export downloads the control-plane state as a versioned archive
+// @Summary Export control-plane state
+// @Description Export endpoints, autoscaler configs, specs and registry mirrors; registry credentials are included (AES-256-GCM sealed) when X-Backup-Passphrase is set
+// @Tags Admin
+// @Produce json
+// @Param X-Backup-Passphrase header string false "Passphrase sealing registry credentials (min 12 chars)"
+// @Success 200 {object} backup.Archive
+// @Router /api/v1/admin/dr/export [get]
+func (h *DisasterRecoveryHandler) Export(c *gin.Context) {
+	archive, err := h.drService.Export(c.Request.Context(), c.GetHeader(BackupPassphraseHeader))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Control-plane state exported: endpoints=%d, specs=%d, credentials=%v",
+		len(archive.Endpoints), len(archive.Specs), archive.Credentials != nil)
+	filename := fmt.Sprintf("waverless-state-%s.json", archive.ExportedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.JSON(http.StatusOK, archive)
+}
+
+// Import restores control-plane state from an archive
+// @Summary Import control-plane state
+// @Description Upsert archived state into this installation (nothing is deleted). With dryRun=true, only return the per-item diff.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Backup-Passphrase header string false "Passphrase the credentials were sealed with"
+// @Param dryRun query bool false "Only compute the diff"
+// @Param archive body backup.Archive true "Exported archive"
+// @Success 200 {object} backup.Plan
+// @Router /api/v1/admin/dr/import [post]
+func (h *DisasterRecoveryHandler) Import(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+
+	var archive backup.Archive
+	if err := c.ShouldBindJSON(&archive); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan, err := h.drService.Import(c.Request.Context(), &archive, c.GetHeader(BackupPassphraseHeader), dryRun)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if plan == nil || errors.Is(err, backup.ErrWrongPassphrase) || errors.Is(err, backup.ErrPassphraseRequired) {
+			status = http.StatusBadRequest
+		}
+		// A partial plan shows how far a failed import got
+		c.JSON(status, gin.H{"error": err.Error(), "plan": plan})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Control-plane state import (dryRun=%v): created=%d, updated=%d, unchanged=%d",
+		dryRun, plan.Created, plan.Updated, plan.Unchanged)
+	c.JSON(http.StatusOK, plan)
+}
+
+// ExportBundle downloads the effective configuration of this control plane (config file,
+// specs, templates) for redeploying it elsewhere
+// @Summary Export control-plane config bundle
+// @Description Effective config.yaml (credentials redacted), specs.yaml and deployment templates as JSON, Helm values or the waverless-config ConfigMap. Endpoint data is exported by /admin/dr/export.
+// @Tags Admin
+// @Produce json
+// @Param format query string false "json (default), values or configmap"
+// @Success 200 {object} service.ControlPlaneBundle
+// @Router /api/v1/admin/control-plane/bundle [get]
+func (h *DisasterRecoveryHandler) ExportBundle(c *gin.Context) {
+	if h.bundleService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "control-plane bundle export not configured"})
+		return
+	}
+	format := c.DefaultQuery("format", service.BundleFormatJSON)
+	if format != service.BundleFormatJSON && format != service.BundleFormatValues && format != service.BundleFormatConfigMap {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format: must be json, values or configmap"})
+		return
+	}
+
+	bundle, err := h.bundleService.Bundle(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Control-plane config bundle exported: format=%s, files=%d, redacted=%d, by=%s",
+		format, len(bundle.Files), len(bundle.Redacted), c.GetHeader(RequestedByHeader))
+
+	stamp := bundle.GeneratedAt.Format("20060102-150405")
+	var body []byte
+	switch format {
+	case service.BundleFormatValues:
+		body, err = bundle.Values()
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "waverless-values-"+stamp+".yaml"))
+	case service.BundleFormatConfigMap:
+		body, err = bundle.ConfigMap()
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "waverless-config-"+stamp+".yaml"))
+	default:
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "waverless-bundle-"+stamp+".json"))
+		c.JSON(http.StatusOK, bundle)
+		return
+	}
+	if err != nil {
+		c.Header("Content-Disposition", "")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/yaml", body)
+}
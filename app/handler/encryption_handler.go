@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"waverless/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EncryptionHandler handles task payload encryption status and per-project key rotation
+type EncryptionHandler struct {
+	encryptionService *service.TaskEncryptionService
+}
+
+// NewEncryptionHandler creates a new encryption handler
+func NewEncryptionHandler(encryptionService *service.TaskEncryptionService) *EncryptionHandler {
+	return &EncryptionHandler{encryptionService: encryptionService}
+}
+
+// GetEndpointStatus reports whether an endpoint's task payloads are encrypted and with which project
+// GET /api/v1/endpoints/:name/encryption
+func (h *EncryptionHandler) GetEndpointStatus(c *gin.Context) {
+	status, err := h.encryptionService.EndpointStatus(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// ListKeys lists the data key versions of a project, newest first (no key material)
+// GET /api/v1/encryption/projects/:project/keys
+func (h *EncryptionHandler) ListKeys(c *gin.Context) {
+	keys, err := h.encryptionService.ListKeys(c.Request.Context(), c.Param("project"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"project": c.Param("project"), "keys": keys})
+}
+
+// RotateKey creates a new data key version of a project; existing payloads stay readable
+// POST /api/v1/encryption/projects/:project/rotate
+func (h *EncryptionHandler) RotateKey(c *gin.Context) {
+	key, err := h.encryptionService.RotateKey(c.Request.Context(), c.Param("project"), c.GetHeader(RequestedByHeader))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"waverless/internal/service"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/deploy/k8s"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestedByHeader carries the caller name for the audit log
+const RequestedByHeader = "X-Requested-By"
+
+// ApprovalTokenHeader carries the token of a configured requester or approver; changes to
+// protected endpoints and their review are attributed to the identity it belongs to
+const ApprovalTokenHeader = "X-Approval-Token"
+
+// SetChangeRequestService enables the approval gate for protected endpoints and
+// registers how approved changes are applied
+func (h *EndpointHandler) SetChangeRequestService(svc *service.ChangeRequestService) {
+	h.changeRequests = svc
+	svc.RegisterExecutor(model.ChangeOpDeploy, h.executeDeploy)
+	svc.RegisterExecutor(model.ChangeOpUpdateDeployment, h.executeUpdateDeployment)
+	svc.RegisterExecutor(model.ChangeOpUpdateConfig, h.executeUpdateConfig)
+	svc.RegisterExecutor(model.ChangeOpDelete, h.executeDelete)
+	svc.RegisterExecutor(model.ChangeOpUpdateEnv, h.executeUpdateEnv)
+	svc.RegisterExecutor(model.ChangeOpSaveHooks, h.executeSaveHooks)
+	svc.RegisterExecutor(model.ChangeOpDeleteHooks, h.executeDeleteHooks)
+	svc.RegisterExecutor(model.ChangeOpPauseDispatch, h.executePauseDispatch)
+	svc.RegisterExecutor(model.ChangeOpResumeDispatch, h.executeResumeDispatch)
+}
+
+// holdForApproval stores the operation as a change request and responds 202 when the
+// endpoint is protected. It reports whether the response has been written.
+func (h *EndpointHandler) holdForApproval(c *gin.Context, endpoint, operation string, labels map[string]string, payload interface{}) bool {
+	return holdForApproval(c, h.changeRequests, endpoint, operation, labels, payload)
+}
+
+// holdForApproval is the approval gate shared by the handlers of endpoint settings. svc is
+// nil while the gate is disabled.
+func holdForApproval(c *gin.Context, svc *service.ChangeRequestService, endpoint, operation string, labels map[string]string, payload interface{}) bool {
+	if svc == nil {
+		return false
+	}
+	ctx := c.Request.Context()
+
+	required, err := svc.RequiresApproval(ctx, endpoint, labels)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return true
+	}
+	if !required {
+		return false
+	}
+
+	cr, err := svc.Submit(ctx, endpoint, operation, payload, c.GetHeader(ApprovalTokenHeader))
+	if errors.Is(err, service.ErrApprovalUnauthorized) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("endpoint %s requires approval, send your approval token in the %s header: %v", endpoint, ApprovalTokenHeader, err)})
+		return true
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return true
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       "change requires approval by a second person",
+		"changeRequest": service.RedactChangeRequest(cr),
+	})
+	return true
+}
+
+func (h *EndpointHandler) executeDeploy(ctx context.Context, cr *model.ChangeRequest) error {
+	var req k8s.DeployAppRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	lineage, err := h.resolveDeployModel(ctx, &req)
+	if err != nil {
+		return err
+	}
+	_, err = h.endpointService.Deploy(ctx, toProviderDeployRequest(req), h.buildMetadataFromRequest(ctx, req, lineage))
+	return err
+}
+
+func (h *EndpointHandler) executeUpdateDeployment(ctx context.Context, cr *model.ChangeRequest) error {
+	var req interfaces.UpdateDeploymentRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	req.Endpoint = cr.Endpoint
+	if req.BlueGreen != nil {
+		if h.operations == nil {
+			return endpointsvc.ErrBlueGreenUnsupported
+		}
+		_, err := h.operations.StartBlueGreen(ctx, &req, cr.RequestedBy)
+		return err
+	}
+	_, err := h.endpointService.UpdateDeployment(ctx, &req)
+	return err
+}
+
+func (h *EndpointHandler) executeUpdateConfig(ctx context.Context, cr *model.ChangeRequest) error {
+	var req interfaces.UpdateEndpointConfigRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	meta, err := h.endpointService.GetEndpoint(ctx, cr.Endpoint)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return fmt.Errorf("endpoint %s not found", cr.Endpoint)
+	}
+	if err := applyConfigUpdate(meta, &req); err != nil {
+		return err
+	}
+	return h.endpointService.SaveEndpoint(ctx, meta)
+}
+
+func (h *EndpointHandler) executeDelete(ctx context.Context, cr *model.ChangeRequest) error {
+	return h.endpointService.DeleteDeployment(ctx, cr.Endpoint)
+}
+
+func (h *EndpointHandler) executeSaveHooks(ctx context.Context, cr *model.ChangeRequest) error {
+	if h.lifecycleHooks == nil {
+		return fmt.Errorf("lifecycle hooks not configured")
+	}
+	var req service.SaveLifecycleHooksRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	_, err := h.lifecycleHooks.SaveHooks(ctx, cr.Endpoint, &req, cr.RequestedBy)
+	return err
+}
+
+func (h *EndpointHandler) executeDeleteHooks(ctx context.Context, cr *model.ChangeRequest) error {
+	if h.lifecycleHooks == nil {
+		return fmt.Errorf("lifecycle hooks not configured")
+	}
+	return h.lifecycleHooks.DeleteHooks(ctx, cr.Endpoint)
+}
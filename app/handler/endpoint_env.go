@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/maintenance"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetEndpointEnv returns the env of an endpoint with its version; secrets are listed by name
+// GET /api/v1/endpoints/:name/env
+func (h *EndpointHandler) GetEndpointEnv(c *gin.Context) {
+	env, err := h.endpointService.GetEnv(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondEnvError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, env)
+}
+
+// PatchEndpointEnv sets and unsets individual env vars based on an env version. A change
+// based on an outdated version is rejected with 409; read the env again and retry.
+// PATCH /api/v1/endpoints/:name/env
+func (h *EndpointHandler) PatchEndpointEnv(c *gin.Context) {
+	name := c.Param("name")
+
+	var patch endpointsvc.EnvPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if h.holdForApproval(c, name, model.ChangeOpUpdateEnv, nil, patch) {
+		return
+	}
+
+	env, change, err := h.endpointService.PatchEnv(c.Request.Context(), name, &patch, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		respondEnvError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"env": env, "change": change})
+}
+
+// ListEndpointEnvChanges returns the env change history of an endpoint, newest first
+// GET /api/v1/endpoints/:name/env/history
+func (h *EndpointHandler) ListEndpointEnvChanges(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	changes, err := h.endpointService.ListEnvChanges(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"changes": changes})
+}
+
+func (h *EndpointHandler) executeUpdateEnv(ctx context.Context, cr *model.ChangeRequest) error {
+	var patch endpointsvc.EnvPatch
+	if err := service.DecodeChangePayload(cr, &patch); err != nil {
+		return err
+	}
+	_, _, err := h.endpointService.PatchEnv(ctx, cr.Endpoint, &patch, cr.RequestedBy)
+	return err
+}
+
+func respondEnvError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, endpointsvc.ErrEnvVersionConflict):
+		status = http.StatusConflict
+	case errors.Is(err, maintenance.ErrBrakeEngaged):
+		status = http.StatusServiceUnavailable
+	case strings.HasPrefix(err.Error(), "invalid"):
+		status = http.StatusBadRequest
+	case strings.HasSuffix(err.Error(), "not found"):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EndpointGroupHandler handles endpoint group APIs (shared capacity pools)
+type EndpointGroupHandler struct {
+	groupService *service.EndpointGroupService
+}
+
+// NewEndpointGroupHandler creates a new endpoint group handler
+func NewEndpointGroupHandler(groupService *service.EndpointGroupService) *EndpointGroupHandler {
+	return &EndpointGroupHandler{groupService: groupService}
+}
+
+// respondGroupError maps "not found" errors to 404, everything else to the given status
+func respondGroupError(c *gin.Context, err error, status int) {
+	if strings.Contains(err.Error(), "not found") {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// ListGroups lists endpoint groups
+// GET /api/v1/endpoint-groups
+func (h *EndpointGroupHandler) ListGroups(c *gin.Context) {
+	groups, err := h.groupService.ListGroups(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
+
+// GetGroup gets an endpoint group
+// GET /api/v1/endpoint-groups/:name
+func (h *EndpointGroupHandler) GetGroup(c *gin.Context) {
+	group, err := h.groupService.GetGroup(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondGroupError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// UpsertGroup creates or updates an endpoint group and its budget
+// PUT /api/v1/endpoint-groups/:name
+func (h *EndpointGroupHandler) UpsertGroup(c *gin.Context) {
+	var req service.UpsertEndpointGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := h.groupService.UpsertGroup(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Endpoint group updated: %s (maxReplicas=%d, maxGpuCount=%d)", group.Name, group.MaxReplicas, group.MaxGPUCount)
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup deletes an endpoint group and detaches its members
+// DELETE /api/v1/endpoint-groups/:name
+func (h *EndpointGroupHandler) DeleteGroup(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.groupService.DeleteGroup(c.Request.Context(), name); err != nil {
+		respondGroupError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Endpoint group deleted: %s", name)
+	c.JSON(http.StatusOK, gin.H{"message": "endpoint group deleted", "name": name})
+}
+
+// AddMember assigns an endpoint to the group
+// PUT /api/v1/endpoint-groups/:name/members/:endpoint
+func (h *EndpointGroupHandler) AddMember(c *gin.Context) {
+	name, endpoint := c.Param("name"), c.Param("endpoint")
+	if err := h.groupService.AddMember(c.Request.Context(), name, endpoint); err != nil {
+		respondGroupError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "endpoint added to group", "name": name, "endpoint": endpoint})
+}
+
+// RemoveMember detaches an endpoint from the group
+// DELETE /api/v1/endpoint-groups/:name/members/:endpoint
+func (h *EndpointGroupHandler) RemoveMember(c *gin.Context) {
+	name, endpoint := c.Param("name"), c.Param("endpoint")
+	if err := h.groupService.RemoveMember(c.Request.Context(), name, endpoint); err != nil {
+		respondGroupError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "endpoint removed from group", "name": name, "endpoint": endpoint})
+}
+
+// GetGroupStatus returns aggregate replicas, GPUs and queue depth against the group budget
+// GET /api/v1/endpoint-groups/:name/status
+func (h *EndpointGroupHandler) GetGroupStatus(c *gin.Context) {
+	status, err := h.groupService.GetGroupStatus(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondGroupError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// GetGroupUsage returns GPU usage of the group members
+// GET /api/v1/endpoint-groups/:name/usage?start_time=2026-10-01&end_time=2026-10-15
+func (h *EndpointGroupHandler) GetGroupUsage(c *gin.Context) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -7)
+	if s := c.Query("start_time"); s != "" {
+		t, err := parseGPUUsageTime(s, false, time.UTC)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_time, expected RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		from = t
+	}
+	if s := c.Query("end_time"); s != "" {
+		t, err := parseGPUUsageTime(s, true, time.UTC)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_time, expected RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		to = t
+	}
+
+	usage, err := h.groupService.GetGroupUsage(c.Request.Context(), c.Param("name"), from, to)
+	if err != nil {
+		respondGroupError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -13,12 +16,18 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
 
+	domainModel "waverless/internal/model"
 	"waverless/internal/service"
 	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/autoscaler"
+	"waverless/pkg/dataplane"
 	"waverless/pkg/deploy/k8s"
 	"waverless/pkg/interfaces"
+	"waverless/pkg/knobs"
 	"waverless/pkg/logger"
+	"waverless/pkg/profile"
 	"waverless/pkg/status"
+	"waverless/pkg/store/mysql/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,6 +38,13 @@ type EndpointHandler struct {
 	deploymentProvider interfaces.DeploymentProvider
 	endpointService    *endpointsvc.Service
 	workerService      *service.WorkerService
+	invokeSigner       *dataplane.Signer
+	invokeTokenTTL     time.Duration
+	changeRequests     *service.ChangeRequestService
+	logRedaction       *service.LogRedactionService
+	lifecycleHooks     *service.LifecycleHookService
+	snapshots          *service.EndpointSnapshotService
+	operations         *service.OperationService
 }
 
 // NewEndpointHandler creates endpoint handler
@@ -40,6 +56,54 @@ func NewEndpointHandler(deploymentProvider interfaces.DeploymentProvider, endpoi
 	}
 }
 
+// SetInvokeSigner enables issuing data plane invoke tokens
+func (h *EndpointHandler) SetInvokeSigner(signer *dataplane.Signer, ttl time.Duration) {
+	h.invokeSigner = signer
+	h.invokeTokenTTL = ttl
+}
+
+// SetLogRedactionService masks live pod logs with the endpoint's redaction rules
+func (h *EndpointHandler) SetLogRedactionService(svc *service.LogRedactionService) {
+	h.logRedaction = svc
+}
+
+// IssueInvokeTokenRequest request body for issuing an invoke token
+type IssueInvokeTokenRequest struct {
+	Method string `json:"method" binding:"required"` // HTTP method of the worker request, e.g. POST
+	Path   string `json:"path" binding:"required"`   // Path of the worker request, e.g. /run
+}
+
+// IssueInvokeToken issues a short-lived token for one direct request to an endpoint's workers
+// @Summary Issue invoke token
+// @Description Sign a token the proxy sends as X-Waverless-Invoke-Token when calling a worker pod directly; workers verify it with WAVERLESS_INVOKE_KEY
+// @Tags Endpoints
+// @Accept json
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param request body IssueInvokeTokenRequest true "Request to sign"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/endpoints/{name}/invoke-token [post]
+func (h *EndpointHandler) IssueInvokeToken(c *gin.Context) {
+	if h.invokeSigner == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "data plane tokens are not enabled (dataPlane.tokenSecret)"})
+		return
+	}
+
+	var req IssueInvokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint := c.Param("name")
+	token, expiresAt := h.invokeSigner.Sign(endpoint, req.Method, req.Path, h.invokeTokenTTL)
+	c.JSON(http.StatusOK, gin.H{
+		"header":    dataplane.TokenHeader,
+		"token":     token,
+		"expiresAt": expiresAt,
+	})
+}
+
 // CreateEndpoint deploys a new endpoint (including metadata and K8s deployment)
 // @Summary Create endpoint
 // @Description Create a new endpoint: write metadata and trigger K8s deployment
@@ -47,7 +111,10 @@ func NewEndpointHandler(deploymentProvider interfaces.DeploymentProvider, endpoi
 // @Accept json
 // @Produce json
 // @Param request body k8s.DeployAppRequest true "Deployment configuration"
+// @Param dryRun query bool false "Run all validation and the provider dry run, return the would-be changes without deploying"
+// @Param async query bool false "Deploy in the background and return an operation to poll at /api/v1/operations/{id}"
 // @Success 200 {object} map[string]interface{}
+// @Success 202 {object} map[string]interface{}
 // @Router /api/v1/endpoints [post]
 func (h *EndpointHandler) CreateEndpoint(c *gin.Context) {
 	var req k8s.DeployAppRequest
@@ -56,6 +123,15 @@ func (h *EndpointHandler) CreateEndpoint(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := knobs.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lineage, err := h.resolveDeployModel(c.Request.Context(), &req)
+	if err != nil {
+		respondModelError(c, err)
+		return
+	}
 
 	logger.InfoCtx(c.Request.Context(), "[INFO] Creating endpoint: endpoint=%s, spec=%s, image=%s, replicas=%d, gpuCount=%d, taskTimeout=%d",
 		req.Endpoint, req.SpecName, req.Image, req.Replicas, req.GpuCount, req.TaskTimeout)
@@ -76,35 +152,44 @@ func (h *EndpointHandler) CreateEndpoint(c *gin.Context) {
 		}
 	}
 
-	providerReq := &interfaces.DeployRequest{
-		Endpoint:      req.Endpoint,
-		SpecName:      req.SpecName,
-		Image:         req.Image,
-		Replicas:      req.Replicas,
-		GpuCount:      req.GpuCount,
-		TaskTimeout:   req.TaskTimeout,
-		Env:           req.Env,
-		VolumeMounts:  req.VolumeMounts,
-		ShmSize:       req.ShmSize,
-		EnablePtrace:  req.EnablePtrace,
-		ValidateImage: req.ValidateImage,
-	}
-	if req.RegistryCredential != nil {
-		providerReq.RegistryCredential = &interfaces.RegistryCredential{
-			Registry: req.RegistryCredential.Registry,
-			Username: req.RegistryCredential.Username,
-			Password: req.RegistryCredential.Password,
+	providerReq := toProviderDeployRequest(req)
+	metadata := h.buildMetadataFromRequest(c.Request.Context(), req, lineage)
+
+	if dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false")); dryRun {
+		plan, err := h.endpointService.DryRunDeploy(c.Request.Context(), providerReq, metadata)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
 		}
+		logger.InfoCtx(c.Request.Context(), "[INFO] Dry-run create endpoint: endpoint=%s, valid=%v, errors=%d, warnings=%d",
+			req.Endpoint, plan.Valid, len(plan.Errors), len(plan.Warnings))
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
+	if h.holdForApproval(c, req.Endpoint, model.ChangeOpDeploy, req.Labels, req) {
+		return
 	}
 
-	metadata := h.buildMetadataFromRequest(c, req)
+	if asyncRequested(c) {
+		if h.operations == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "async operations not available"})
+			return
+		}
+		op, err := h.operations.StartDeploy(c.Request.Context(), providerReq, metadata, c.GetHeader(RequestedByHeader))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		respondOperationStarted(c, op)
+		return
+	}
 
 	resp, err := h.endpointService.Deploy(c.Request.Context(), providerReq, metadata)
 
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), "[ERROR] Failed to deploy app %s: %v", req.Endpoint, err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":    err.Error(),
+		respondProviderError(c, err, gin.H{
 			"endpoint": req.Endpoint,
 			"spec":     req.SpecName,
 			"details":  fmt.Sprintf("Deployment failed: %v", err),
@@ -121,6 +206,89 @@ func (h *EndpointHandler) CreateEndpoint(c *gin.Context) {
 	})
 }
 
+// toProviderDeployRequest converts a deploy request to the provider request
+func toProviderDeployRequest(req k8s.DeployAppRequest) *interfaces.DeployRequest {
+	providerReq := &interfaces.DeployRequest{
+		Endpoint:         req.Endpoint,
+		Provider:         req.Provider,
+		SpecName:         req.SpecName,
+		Image:            req.Image,
+		Replicas:         req.Replicas,
+		GpuCount:         req.GpuCount,
+		TaskTimeout:      req.TaskTimeout,
+		Env:              req.Env,
+		Labels:           req.Labels,
+		VolumeMounts:     req.VolumeMounts,
+		Storage:          req.Storage,
+		InitContainers:   req.InitContainers,
+		Sidecars:         req.Sidecars,
+		ShmSize:          req.ShmSize,
+		EphemeralStorage: req.EphemeralStorage,
+		EnablePtrace:     req.EnablePtrace,
+		ValidateImage:    req.ValidateImage,
+		CopyImage:        req.CopyImage,
+		RunPodCompat:     req.RunPodCompat,
+
+		EgressPolicy:             req.EgressPolicy,
+		RestrictedServiceAccount: req.RestrictedServiceAccount,
+		SpecOverride:             req.SpecOverride,
+	}
+	if req.RegistryCredential != nil {
+		providerReq.RegistryCredential = &interfaces.RegistryCredential{
+			Registry: req.RegistryCredential.Registry,
+			Username: req.RegistryCredential.Username,
+			Password: req.RegistryCredential.Password,
+		}
+	}
+	return providerReq
+}
+
+// DiffEndpoint diffs the live endpoint against a proposed deploy request
+// @Summary Diff endpoint against a proposed deployment
+// @Description Structured diff of image, resources, env, volumes and autoscaler settings between the live endpoint and a proposed deploy request. Nothing is applied.
+// @Tags Endpoints
+// @Accept json
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param request body k8s.DeployAppRequest true "Proposed deployment configuration"
+// @Success 200 {object} endpointsvc.DeploymentDiff
+// @Router /api/v1/endpoints/{name}/diff [post]
+func (h *EndpointHandler) DiffEndpoint(c *gin.Context) {
+	name := c.Param("name")
+	var req k8s.DeployAppRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Endpoint != name {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("endpoint %q in body does not match %q in path", req.Endpoint, name)})
+		return
+	}
+	if err := knobs.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if h.endpointService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "endpoint service not configured"})
+		return
+	}
+	lineage, err := h.resolveDeployModel(c.Request.Context(), &req)
+	if err != nil {
+		respondModelError(c, err)
+		return
+	}
+	if req.TaskTimeout == 0 {
+		req.TaskTimeout = 3600
+	}
+
+	diff, err := h.endpointService.Diff(c.Request.Context(), toProviderDeployRequest(req), h.buildMetadataFromRequest(c.Request.Context(), req, lineage))
+	if err != nil {
+		respondProviderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
 // PreviewDeploymentYAML previews endpoint deployment YAML
 // @Summary Preview endpoint deployment YAML
 // @Description Preview K8s deployment YAML for endpoint
@@ -136,26 +304,40 @@ func (h *EndpointHandler) PreviewDeploymentYAML(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if _, err := h.resolveDeployModel(c.Request.Context(), &req); err != nil {
+		respondModelError(c, err)
+		return
+	}
 	if req.TaskTimeout == 0 {
 		req.TaskTimeout = 3600
 	}
 
 	providerReq := &interfaces.DeployRequest{
-		Endpoint:     req.Endpoint,
-		SpecName:     req.SpecName,
-		Image:        req.Image,
-		Replicas:     req.Replicas,
-		GpuCount:     req.GpuCount,
-		TaskTimeout:  req.TaskTimeout,
-		Env:          req.Env,
-		VolumeMounts: req.VolumeMounts,
-		ShmSize:      req.ShmSize,
-		EnablePtrace: req.EnablePtrace,
+		Endpoint:         req.Endpoint,
+		Provider:         req.Provider,
+		SpecName:         req.SpecName,
+		Image:            req.Image,
+		Replicas:         req.Replicas,
+		GpuCount:         req.GpuCount,
+		TaskTimeout:      req.TaskTimeout,
+		Env:              req.Env,
+		VolumeMounts:     req.VolumeMounts,
+		Storage:          req.Storage,
+		InitContainers:   req.InitContainers,
+		Sidecars:         req.Sidecars,
+		ShmSize:          req.ShmSize,
+		EphemeralStorage: req.EphemeralStorage,
+		EnablePtrace:     req.EnablePtrace,
+		RunPodCompat:     req.RunPodCompat,
+
+		EgressPolicy:             req.EgressPolicy,
+		RestrictedServiceAccount: req.RestrictedServiceAccount,
+		SpecOverride:             req.SpecOverride,
 	}
 
 	yaml, err := h.deploymentProvider.PreviewDeploymentYAML(c.Request.Context(), providerReq)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
@@ -185,9 +367,19 @@ func (h *EndpointHandler) GetEndpoint(c *gin.Context) {
 		return
 	}
 
-	// Runtime status (namespace, readyReplicas, availableReplicas, shmSize, volumeMounts)
+	// Runtime status (namespace, readyReplicas, availableReplicas, shmSize, ephemeralStorage, volumeMounts)
 	// is already loaded from runtime_state JSON field in fromMySQLEndpoint
 
+	// Provisioned PVCs are read live so their phase and capacity are current
+	if provisioner, ok := endpointProvider[interfaces.StorageProvisioner](c.Request.Context(), h.deploymentProvider, name); ok {
+		storage, err := provisioner.GetEndpointStorage(c.Request.Context(), name)
+		if err != nil {
+			logger.WarnCtx(c.Request.Context(), "Failed to get storage of endpoint %s: %v", name, err)
+		} else if len(storage) > 0 {
+			metadata.Storage = storage
+		}
+	}
+
 	c.JSON(http.StatusOK, metadata)
 }
 
@@ -206,7 +398,7 @@ func (h *EndpointHandler) ListEndpoints(c *gin.Context) {
 
 	endpoints, err := h.endpointService.ListEndpoints(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
@@ -223,8 +415,12 @@ func (h *EndpointHandler) ListEndpoints(c *gin.Context) {
 func (h *EndpointHandler) DeleteEndpoint(c *gin.Context) {
 	name := c.Param("name")
 
+	if h.holdForApproval(c, name, model.ChangeOpDelete, nil, nil) {
+		return
+	}
+
 	if err := h.endpointService.DeleteDeployment(c.Request.Context(), name); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
@@ -242,26 +438,48 @@ func (h *EndpointHandler) DeleteEndpoint(c *gin.Context) {
 // @Param name path string true "Endpoint name"
 // @Param lines query int false "Number of log lines" default(100)
 // @Param pod_name query string false "Pod name (optional, get specific Pod logs if specified)"
+// @Param follow query bool false "Stream new lines of all pods (or pod_name) as server-sent events"
+// @Param container query string false "Container other than the worker, e.g. sidecar-downloader or init-model"
 // @Success 200 {string} string
 // @Router /api/v1/endpoints/{name}/logs [get]
 func (h *EndpointHandler) GetEndpointLogs(c *gin.Context) {
 	name := c.Param("name")
 	linesStr := c.DefaultQuery("lines", "100")
 	podName := c.Query("pod_name")
+	container := c.Query("container")
 
 	lines, err := strconv.Atoi(linesStr)
 	if err != nil {
 		lines = 100
 	}
 
+	if c.Query("follow") == "true" {
+		h.followEndpointLogs(c, name, lines, podName, container)
+		return
+	}
+
 	var logs string
-	if podName != "" {
+	if container != "" {
+		reader, ok := endpointProvider[interfaces.ContainerLogReader](c.Request.Context(), h.deploymentProvider, name)
+		if !ok {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "the provider of this endpoint cannot read container logs"})
+			return
+		}
+		logs, err = reader.GetContainerLogs(c.Request.Context(), name, lines, podName, container)
+	} else if podName != "" {
 		logs, err = h.deploymentProvider.GetAppLogs(c.Request.Context(), name, lines, podName)
 	} else {
 		logs, err = h.deploymentProvider.GetAppLogs(c.Request.Context(), name, lines)
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
+		return
+	}
+
+	// Fail closed: without the rules the raw lines may leak PII
+	logs, err = h.logRedaction.RedactText(c.Request.Context(), name, logs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to redact logs: %v", err)})
 		return
 	}
 
@@ -278,7 +496,7 @@ func (h *EndpointHandler) GetEndpointLogs(c *gin.Context) {
 func (h *EndpointHandler) ListSpecs(c *gin.Context) {
 	specs, err := h.deploymentProvider.ListSpecs(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
@@ -331,14 +549,55 @@ func (h *EndpointHandler) UpdateEndpoint(c *gin.Context) {
 
 	// Get existing metadata
 	existingMeta, err := h.endpointService.GetEndpoint(c.Request.Context(), name)
-	if err != nil {
+	if err != nil || existingMeta == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "endpoint not found"})
 		return
 	}
 
+	if err := applyConfigUpdate(existingMeta, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var labels map[string]string
+	if req.Labels != nil {
+		labels = *req.Labels
+	}
+	if h.holdForApproval(c, name, model.ChangeOpUpdateConfig, labels, req) {
+		return
+	}
+
+	// Save the updated metadata
+	// This will update both endpoints table and autoscaler_configs table
+	if err := h.endpointService.SaveEndpoint(c.Request.Context(), existingMeta); err != nil {
+		respondProviderError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Endpoint configuration updated: %s", name)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Endpoint configuration updated successfully",
+		"name":    name,
+	})
+}
+
+// applyConfigUpdate applies the fields set in req to existingMeta
+func applyConfigUpdate(existingMeta *interfaces.EndpointMetadata, req *interfaces.UpdateEndpointConfigRequest) error {
 	// Apply updates - only update fields that are explicitly provided (not nil)
 	// Using pointers allows us to distinguish between "not provided" and "set to zero/empty"
 	// This prevents concurrent updates from overwriting each other's changes
+	if err := knobs.Validate(req); err != nil {
+		return err
+	}
+
+	// The profile is expanded first so that fields set in the same request override it
+	if req.Profile != nil {
+		if p, ok := profile.Get(*req.Profile); ok {
+			p.Apply(existingMeta)
+		} else {
+			existingMeta.Profile = ""
+		}
+	}
 
 	// Basic metadata
 	if req.DisplayName != nil {
@@ -388,33 +647,47 @@ func (h *EndpointHandler) UpdateEndpoint(c *gin.Context) {
 	if req.AutoscalerEnabled != nil {
 		existingMeta.AutoscalerEnabled = req.AutoscalerEnabled
 	}
+	if req.CustomMetricName != nil {
+		existingMeta.CustomMetricName = *req.CustomMetricName
+	}
+	if req.CustomMetricTarget != nil {
+		existingMeta.CustomMetricTarget = *req.CustomMetricTarget
+	}
+	if req.EvaluationInterval != nil {
+		existingMeta.EvaluationInterval = *req.EvaluationInterval
+	}
+	if req.QueueWaitSLO != nil {
+		existingMeta.QueueWaitSLO = *req.QueueWaitSLO
+	}
+	if req.MetricSources != nil {
+		if err := autoscaler.ValidateMetricSources(*req.MetricSources); err != nil {
+			return err
+		}
+		existingMeta.MetricSources = *req.MetricSources
+	}
 	if req.ImagePrefix != nil {
 		existingMeta.ImagePrefix = *req.ImagePrefix
 	}
-
-	// Save the updated metadata
-	// This will update both endpoints table and autoscaler_configs table
-	if err := h.endpointService.SaveEndpoint(c.Request.Context(), existingMeta); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if req.Labels != nil {
+		existingMeta.Labels = *req.Labels
 	}
 
-	logger.InfoCtx(c.Request.Context(), "Endpoint configuration updated: %s", name)
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Endpoint configuration updated successfully",
-		"name":    name,
-	})
+	return nil
 }
 
 // UpdateEndpointDeployment updates endpoint deployment (image, replicas, etc.)
 // @Summary Update endpoint deployment
 // @Description Update endpoint's K8s deployment, such as upgrading image, adjusting replica count, etc.
+// @Description With blueGreen set, the update always runs as a blue/green operation (202): new workers in a second deployment take all tasks for a bake window before the live deployment is updated, and are removed again on regression
 // @Tags Endpoints
 // @Accept json
 // @Produce json
 // @Param name path string true "Endpoint name"
 // @Param request body interfaces.UpdateDeploymentRequest true "Update request"
+// @Param dryRun query bool false "Run all validation and the provider dry run, return the would-be changes and a field-level diff (image, resources, env, volumes, tolerations) without updating"
+// @Param async query bool false "Update in the background, wait for the rollout and return an operation to poll at /api/v1/operations/{id}"
 // @Success 200 {object} map[string]interface{}
+// @Success 202 {object} map[string]interface{}
 // @Router /api/v1/endpoints/{name}/deployment [patch]
 func (h *EndpointHandler) UpdateEndpointDeployment(c *gin.Context) {
 	name := c.Param("name")
@@ -429,15 +702,49 @@ func (h *EndpointHandler) UpdateEndpointDeployment(c *gin.Context) {
 	// Ensure name matches URL param
 	req.Endpoint = name
 
+	if dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false")); dryRun {
+		plan, err := h.endpointService.DryRunUpdateDeployment(c.Request.Context(), &req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		logger.InfoCtx(c.Request.Context(), "Dry-run update deployment: endpoint=%s, valid=%v, errors=%d, warnings=%d",
+			name, plan.Valid, len(plan.Errors), len(plan.Warnings))
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
+	if h.holdForApproval(c, name, model.ChangeOpUpdateDeployment, nil, req) {
+		return
+	}
+
 	logger.InfoCtx(c.Request.Context(), "Updating deployment: endpoint=%s, spec=%s, image=%s, replicas=%v",
 		name, req.SpecName, req.Image, req.Replicas)
 
+	if req.BlueGreen != nil {
+		h.startBlueGreen(c, &req)
+		return
+	}
+
+	if asyncRequested(c) {
+		if h.operations == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "async operations not available"})
+			return
+		}
+		op, err := h.operations.StartRollout(c.Request.Context(), &req, c.GetHeader(RequestedByHeader))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		respondOperationStarted(c, op)
+		return
+	}
+
 	resp, err := h.endpointService.UpdateDeployment(c.Request.Context(), &req)
 
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), "Failed to update deployment %s: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":    err.Error(),
+		respondProviderError(c, err, gin.H{
 			"endpoint": name,
 			"details":  fmt.Sprintf("Update failed: %v", err),
 		})
@@ -460,7 +767,11 @@ func (h *EndpointHandler) getEndpointFromRuntimeOnly(c *gin.Context, name string
 	}
 
 	app, err := h.deploymentProvider.GetApp(c.Request.Context(), name)
-	if err != nil || app == nil {
+	if err != nil && !errors.Is(err, interfaces.ErrNotFound) {
+		respondProviderError(c, err)
+		return
+	}
+	if app == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "endpoint not found"})
 		return
 	}
@@ -475,6 +786,7 @@ func (h *EndpointHandler) getEndpointFromRuntimeOnly(c *gin.Context, name string
 		ReadyReplicas:     int(app.ReadyReplicas),
 		AvailableReplicas: int(app.AvailableReplicas),
 		ShmSize:           app.ShmSize,
+		EphemeralStorage:  app.EphemeralStorage,
 		VolumeMounts:      app.VolumeMounts,
 		HealthStatus:      "HEALTHY", // Default to HEALTHY when metadata unavailable
 	}
@@ -491,7 +803,7 @@ func (h *EndpointHandler) listEndpointsFromRuntimeOnly(c *gin.Context) {
 
 	apps, err := h.deploymentProvider.ListApps(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
@@ -507,6 +819,7 @@ func (h *EndpointHandler) listEndpointsFromRuntimeOnly(c *gin.Context) {
 			ReadyReplicas:     int(app.ReadyReplicas),
 			AvailableReplicas: int(app.AvailableReplicas),
 			ShmSize:           app.ShmSize,
+			EphemeralStorage:  app.EphemeralStorage,
 			VolumeMounts:      app.VolumeMounts,
 			HealthStatus:      "HEALTHY", // Default to HEALTHY when metadata unavailable
 		}
@@ -515,12 +828,14 @@ func (h *EndpointHandler) listEndpointsFromRuntimeOnly(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
-func (h *EndpointHandler) buildMetadataFromRequest(c *gin.Context, req k8s.DeployAppRequest) *interfaces.EndpointMetadata {
+// buildMetadataFromRequest builds the metadata of a deployed endpoint; lineage is the model
+// registry version the image was resolved from (nil = deployed by image)
+func (h *EndpointHandler) buildMetadataFromRequest(ctx context.Context, req k8s.DeployAppRequest, lineage *interfaces.ModelLineage) *interfaces.EndpointMetadata {
 	if h.endpointService == nil {
 		return nil
 	}
 
-	existingMeta, err := h.endpointService.GetEndpoint(c.Request.Context(), req.Endpoint)
+	existingMeta, err := h.endpointService.GetEndpoint(ctx, req.Endpoint)
 	if err != nil || existingMeta == nil {
 		enableDynamicPrio := true
 		if req.EnableDynamicPrio != nil {
@@ -537,7 +852,7 @@ func (h *EndpointHandler) buildMetadataFromRequest(c *gin.Context, req k8s.Deplo
 			maxPendingTasks = 1
 		}
 
-		return &interfaces.EndpointMetadata{
+		metadata := &interfaces.EndpointMetadata{
 			Name:              req.Endpoint,
 			DisplayName:       req.Endpoint,
 			SpecName:          req.SpecName,
@@ -548,7 +863,9 @@ func (h *EndpointHandler) buildMetadataFromRequest(c *gin.Context, req k8s.Deplo
 			TaskTimeout:       req.TaskTimeout,
 			MaxPendingTasks:   maxPendingTasks,
 			Env:               req.Env,
+			Labels:            req.Labels,
 			EnablePtrace:      req.EnablePtrace,
+			RunPodCompat:      req.RunPodCompat == nil || *req.RunPodCompat,
 			Status:            "Deploying",
 			MinReplicas:       req.MinReplicas,
 			MaxReplicas:       maxReplicas,
@@ -560,12 +877,16 @@ func (h *EndpointHandler) buildMetadataFromRequest(c *gin.Context, req k8s.Deplo
 			EnableDynamicPrio: &enableDynamicPrio,
 			HighLoadThreshold: req.HighLoadThreshold,
 			PriorityBoost:     req.PriorityBoost,
+			Model:             lineage,
 		}
+		applyDeployProfile(metadata, req)
+		return metadata
 	}
 
 	metadata := existingMeta
 	metadata.SpecName = req.SpecName
 	metadata.Image = req.Image
+	metadata.Model = lineage
 	metadata.Replicas = req.Replicas
 	if req.GpuCount > 0 {
 		metadata.GpuCount = req.GpuCount
@@ -575,7 +896,13 @@ func (h *EndpointHandler) buildMetadataFromRequest(c *gin.Context, req k8s.Deplo
 		metadata.MaxPendingTasks = req.MaxPendingTasks
 	}
 	metadata.Env = req.Env
+	if req.Labels != nil {
+		metadata.Labels = req.Labels
+	}
 	metadata.EnablePtrace = req.EnablePtrace
+	if req.RunPodCompat != nil {
+		metadata.RunPodCompat = *req.RunPodCompat
+	}
 	metadata.Status = "Deploying"
 
 	if req.MaxReplicas > 0 {
@@ -606,6 +933,7 @@ func (h *EndpointHandler) buildMetadataFromRequest(c *gin.Context, req k8s.Deplo
 			metadata.PriorityBoost = req.PriorityBoost
 		}
 	}
+	applyDeployProfile(metadata, req)
 
 	return metadata
 }
@@ -646,6 +974,7 @@ func (h *EndpointHandler) GetEndpointWorkers(c *gin.Context) {
 		LastHeartbeat     string   `json:"last_heartbeat"`
 		LastTaskTime      string   `json:"last_task_time,omitempty"`
 		Version           string   `json:"version,omitempty"`
+		Capabilities      []string `json:"capabilities,omitempty"`
 		RegisteredAt      string   `json:"registered_at"`
 		PodPhase          string   `json:"podPhase,omitempty"`
 		PodStatus         string   `json:"podStatus,omitempty"`
@@ -653,6 +982,7 @@ func (h *EndpointHandler) GetEndpointWorkers(c *gin.Context) {
 		PodMessage        string   `json:"podMessage,omitempty"`
 		PodIP             string   `json:"podIP,omitempty"`
 		PodNodeName       string   `json:"podNodeName,omitempty"`
+		GPUType           string   `json:"gpuType,omitempty"` // GPU type of the node when the spec has GPU type fallbacks
 		PodCreatedAt      string   `json:"podCreatedAt,omitempty"`
 		PodStartedAt      string   `json:"podStartedAt,omitempty"`
 		PodRestartCount   int32    `json:"podRestartCount,omitempty"`
@@ -661,6 +991,8 @@ func (h *EndpointHandler) GetEndpointWorkers(c *gin.Context) {
 		FailureType       string `json:"failureType,omitempty"`
 		FailureReason     string `json:"failureReason,omitempty"`
 		FailureSuggestion string `json:"failureSuggestion,omitempty"`
+		// Set while the worker receives no new tasks
+		Quarantine *domainModel.WorkerQuarantine `json:"quarantine,omitempty"`
 	}
 
 	result := make([]WorkerWithPodInfo, 0, len(workers))
@@ -685,7 +1017,9 @@ func (h *EndpointHandler) GetEndpointWorkers(c *gin.Context) {
 			JobsInProgress: jobsInProgress,
 			LastHeartbeat:  worker.LastHeartbeat.Format("2006-01-02T15:04:05Z07:00"),
 			Version:        worker.Version,
+			Capabilities:   worker.CapabilityList(),
 			RegisteredAt:   worker.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Quarantine:     service.WorkerQuarantineOf(worker),
 		}
 		if worker.LastTaskTime != nil {
 			workerWithPod.LastTaskTime = worker.LastTaskTime.Format("2006-01-02T15:04:05Z07:00")
@@ -723,6 +1057,9 @@ func (h *EndpointHandler) GetEndpointWorkers(c *gin.Context) {
 			if v, ok := rs["nodeName"].(string); ok {
 				workerWithPod.PodNodeName = v
 			}
+			if v, ok := rs["gpuType"].(string); ok {
+				workerWithPod.GPUType = v
+			}
 			if v, ok := rs["createdAt"].(string); ok {
 				workerWithPod.PodCreatedAt = v
 			}
@@ -773,6 +1110,7 @@ func (h *EndpointHandler) GetEndpointWorkersForSync(c *gin.Context) {
 		LastHeartbeat     string   `json:"last_heartbeat"`
 		LastTaskTime      string   `json:"last_task_time,omitempty"`
 		Version           string   `json:"version,omitempty"`
+		Capabilities      []string `json:"capabilities,omitempty"`
 		RegisteredAt      string   `json:"registered_at"`
 		PodPhase          string   `json:"podPhase,omitempty"`
 		PodStatus         string   `json:"podStatus,omitempty"`
@@ -780,6 +1118,7 @@ func (h *EndpointHandler) GetEndpointWorkersForSync(c *gin.Context) {
 		PodMessage        string   `json:"podMessage,omitempty"`
 		PodIP             string   `json:"podIP,omitempty"`
 		PodNodeName       string   `json:"podNodeName,omitempty"`
+		GPUType           string   `json:"gpuType,omitempty"` // GPU type of the node when the spec has GPU type fallbacks
 		PodCreatedAt      string   `json:"podCreatedAt,omitempty"`
 		PodStartedAt      string   `json:"podStartedAt,omitempty"`
 		PodRestartCount   int32    `json:"podRestartCount,omitempty"`
@@ -790,6 +1129,8 @@ func (h *EndpointHandler) GetEndpointWorkersForSync(c *gin.Context) {
 		FailureReason     string `json:"failureReason,omitempty"`
 		FailureSuggestion string `json:"failureSuggestion,omitempty"`
 		FailureOccurredAt string `json:"failureOccurredAt,omitempty"`
+		// Set while the worker receives no new tasks
+		Quarantine *domainModel.WorkerQuarantine `json:"quarantine,omitempty"`
 	}
 
 	result := make([]WorkerWithPodInfo, 0, len(workers))
@@ -814,7 +1155,9 @@ func (h *EndpointHandler) GetEndpointWorkersForSync(c *gin.Context) {
 			JobsInProgress: jobsInProgress,
 			LastHeartbeat:  worker.LastHeartbeat.Format("2006-01-02T15:04:05Z07:00"),
 			Version:        worker.Version,
+			Capabilities:   worker.CapabilityList(),
 			RegisteredAt:   worker.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Quarantine:     service.WorkerQuarantineOf(worker),
 		}
 		if worker.LastTaskTime != nil {
 			workerWithPod.LastTaskTime = worker.LastTaskTime.Format("2006-01-02T15:04:05Z07:00")
@@ -861,6 +1204,9 @@ func (h *EndpointHandler) GetEndpointWorkersForSync(c *gin.Context) {
 			if v, ok := rs["nodeName"].(string); ok {
 				workerWithPod.PodNodeName = v
 			}
+			if v, ok := rs["gpuType"].(string); ok {
+				workerWithPod.GPUType = v
+			}
 			if v, ok := rs["createdAt"].(string); ok {
 				workerWithPod.PodCreatedAt = v
 			}
@@ -887,6 +1233,7 @@ var upgrader = websocket.Upgrader{
 // @Tags Endpoints
 // @Param name path string true "Endpoint name"
 // @Param worker_id query string true "Worker ID (Pod Name)"
+// @Param container query string false "Sidecar or debug container (default: the worker)"
 // @Router /api/v1/endpoints/{name}/workers/exec [get]
 func (h *EndpointHandler) ExecWorker(c *gin.Context) {
 	workerID := c.Query("worker_id")
@@ -896,7 +1243,7 @@ func (h *EndpointHandler) ExecWorker(c *gin.Context) {
 	}
 
 	// Get K8s provider
-	k8sProvider, ok := h.deploymentProvider.(*k8s.K8sDeploymentProvider)
+	k8sProvider, ok := endpointProvider[*k8s.K8sDeploymentProvider](c.Request.Context(), h.deploymentProvider, c.Param("name"))
 	if !ok {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "K8s provider not available"})
 		return
@@ -921,8 +1268,25 @@ func (h *EndpointHandler) ExecWorker(c *gin.Context) {
 
 	// Get endpoint name from URL path parameter
 	endpointName := c.Param("name")
-	// Default container name: {endpoint}-worker
+	// Default container name: {endpoint}-worker; debug containers and sidecars are opt-in via ?container=
 	containerName := endpointName + "-worker"
+	command := []string{"/bin/bash"}
+	if container := c.Query("container"); container != "" {
+		switch {
+		case k8s.IsDebugContainerName(container):
+			command = k8s.DebugShellCommand()
+			logger.InfoCtx(c.Request.Context(), "[AUDIT] debug container exec: endpoint=%s, pod=%s, container=%s, clientIP=%s",
+				endpointName, workerID, container, c.ClientIP())
+		case k8s.IsSidecarContainerName(container):
+			command = k8s.SidecarShellCommand()
+			logger.InfoCtx(c.Request.Context(), "[AUDIT] sidecar exec: endpoint=%s, pod=%s, container=%s, clientIP=%s",
+				endpointName, workerID, container, c.ClientIP())
+		default:
+			ws.WriteMessage(websocket.TextMessage, []byte("Error: only debug and sidecar containers can be selected\n"))
+			return
+		}
+		containerName = container
+	}
 
 	// Create exec request
 	req := clientset.CoreV1().RESTClient().Post().
@@ -932,7 +1296,7 @@ func (h *EndpointHandler) ExecWorker(c *gin.Context) {
 		SubResource("exec").
 		VersionedParams(&corev1.PodExecOptions{
 			Container: containerName,
-			Command:   []string{"/bin/bash"},
+			Command:   command,
 			Stdin:     true,
 			Stdout:    true,
 			Stderr:    true,
@@ -1006,13 +1370,50 @@ func (h *EndpointHandler) ListPVCs(c *gin.Context) {
 	pvcs, err := h.deploymentProvider.ListPVCs(c.Request.Context())
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), "[ERROR] Failed to list PVCs: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, pvcs)
 }
 
+// GetInformerStats returns the size of the K8s informer caches
+// @Summary Informer cache stats
+// @Description Object counts, estimated memory and queue depth of the deployment/pod informers
+// @Tags K8s
+// @Produce json
+// @Success 200 {object} k8s.InformerCacheStats
+// @Router /api/v1/k8s/informers [get]
+func (h *EndpointHandler) GetInformerStats(c *gin.Context) {
+	k8sProvider, ok := anyProvider[*k8s.K8sDeploymentProvider](h.deploymentProvider)
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "K8s provider not available"})
+		return
+	}
+	c.JSON(http.StatusOK, k8sProvider.InformerStats())
+}
+
+// ListSharedStorage lists shared storage and the endpoints using each
+// @Summary List shared volumes
+// @Description Shared PVCs with their consumers; a PVC is only deleted with its last consumer
+// @Tags K8s
+// @Produce json
+// @Success 200 {array} interfaces.StorageStatus
+// @Router /api/v1/k8s/shared-volumes [get]
+func (h *EndpointHandler) ListSharedStorage(c *gin.Context) {
+	provisioner, ok := anyProvider[interfaces.StorageProvisioner](h.deploymentProvider)
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "K8s provider not available"})
+		return
+	}
+	storage, err := provisioner.ListSharedStorage(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, storage)
+}
+
 // GetDefaultEnv returns default environment variables from wavespeed-config ConfigMap
 // @Summary Get default environment variables
 // @Description Get default environment variables from wavespeed-config ConfigMap
@@ -1030,7 +1431,7 @@ func (h *EndpointHandler) GetDefaultEnv(c *gin.Context) {
 	env, err := h.deploymentProvider.GetDefaultEnv(c.Request.Context())
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), "[ERROR] Failed to get default env: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetLifecycleHookService enables the lifecycle hook API (for dependency injection)
+func (h *EndpointHandler) SetLifecycleHookService(svc *service.LifecycleHookService) {
+	h.lifecycleHooks = svc
+}
+
+// GetEndpointHooks returns the lifecycle hooks of an endpoint in execution order
+// GET /api/v1/endpoints/:name/hooks
+func (h *EndpointHandler) GetEndpointHooks(c *gin.Context) {
+	if !h.requireLifecycleHooks(c) {
+		return
+	}
+	name := c.Param("name")
+	hooks, err := h.lifecycleHooks.GetHooks(c.Request.Context(), name)
+	if err != nil {
+		respondHookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"endpoint": name, "hooks": hooks})
+}
+
+// SaveEndpointHooks replaces the lifecycle hooks of an endpoint
+// PUT /api/v1/endpoints/:name/hooks
+func (h *EndpointHandler) SaveEndpointHooks(c *gin.Context) {
+	if !h.requireLifecycleHooks(c) {
+		return
+	}
+	name := c.Param("name")
+
+	var req service.SaveLifecycleHooksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if h.holdForApproval(c, name, model.ChangeOpSaveHooks, nil, req) {
+		return
+	}
+
+	requestedBy := c.GetHeader(RequestedByHeader)
+	hooks, err := h.lifecycleHooks.SaveHooks(c.Request.Context(), name, &req, requestedBy)
+	if err != nil {
+		respondHookError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Lifecycle hooks saved: endpoint=%s, hooks=%d, by=%s", name, len(hooks), requestedBy)
+	c.JSON(http.StatusOK, gin.H{"endpoint": name, "hooks": hooks})
+}
+
+// DeleteEndpointHooks removes the lifecycle hooks of an endpoint; the run history is kept
+// DELETE /api/v1/endpoints/:name/hooks
+func (h *EndpointHandler) DeleteEndpointHooks(c *gin.Context) {
+	if !h.requireLifecycleHooks(c) {
+		return
+	}
+	name := c.Param("name")
+	if h.holdForApproval(c, name, model.ChangeOpDeleteHooks, nil, nil) {
+		return
+	}
+	if err := h.lifecycleHooks.DeleteHooks(c.Request.Context(), name); err != nil {
+		respondHookError(c, err)
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Lifecycle hooks removed: endpoint=%s, by=%s", name, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusOK, gin.H{"message": "lifecycle hooks removed", "endpoint": name})
+}
+
+// GetEndpointHookRuns returns the recent lifecycle hook runs of an endpoint, newest first
+// GET /api/v1/endpoints/:name/hooks/runs
+func (h *EndpointHandler) GetEndpointHookRuns(c *gin.Context) {
+	if !h.requireLifecycleHooks(c) {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	runs, err := h.lifecycleHooks.ListRuns(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+func (h *EndpointHandler) requireLifecycleHooks(c *gin.Context) bool {
+	if h.lifecycleHooks == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "lifecycle hooks not configured"})
+		return false
+	}
+	return true
+}
+
+func respondHookError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		status = http.StatusBadRequest
+	case strings.Contains(err.Error(), "not found"):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"waverless/pkg/iac"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetEndpointResource returns the infrastructure-as-code representation of an endpoint,
+// the state a Terraform provider reads back (runtime status is not part of it)
+// GET /api/v1/endpoints/:name/resource
+func (h *EndpointHandler) GetEndpointResource(c *gin.Context) {
+	name := c.Param("name")
+	resource, err := h.endpointService.GetResource(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if resource == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "endpoint not found"})
+		return
+	}
+	c.JSON(http.StatusOK, resource)
+}
+
+// ExportTerraform exports endpoints as Terraform configuration with import blocks
+// GET /api/v1/endpoints/export/terraform?format=hcl|json&endpoints=a,b
+func (h *EndpointHandler) ExportTerraform(c *gin.Context) {
+	format := c.DefaultQuery("format", iac.FormatHCL)
+	if format != iac.FormatHCL && format != iac.FormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format: must be hcl or json"})
+		return
+	}
+	var names []string
+	for _, name := range strings.Split(c.Query("endpoints"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	resources, err := h.endpointService.ExportResources(c.Request.Context(), names)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Endpoints exported as Terraform: format=%s, endpoints=%d, by=%s",
+		format, len(resources), c.GetHeader(RequestedByHeader))
+
+	if format == iac.FormatJSON {
+		body, err := iac.RenderJSON(resources)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="waverless_endpoints.tf.json"`)
+		c.Data(http.StatusOK, "application/json", body)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="waverless_endpoints.tf"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", iac.RenderHCL(resources))
+}
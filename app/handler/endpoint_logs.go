@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"waverless/pkg/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// logStreamHeartbeat is how often an idle log stream sends a comment, so proxies keep it open
+const logStreamHeartbeat = 15 * time.Second
+
+// followEndpointLogs streams the logs of an endpoint's pods as server-sent events until the
+// client disconnects. Every line is a "log" event carrying {"pod", "line"}; an "end" event is
+// sent when the followed pod's log ends. container selects a sidecar instead of the worker.
+func (h *EndpointHandler) followEndpointLogs(c *gin.Context, name string, lines int, podName, container string) {
+	ctx := c.Request.Context()
+	streamer, ok := endpointProvider[interfaces.AppLogStreamer](ctx, h.deploymentProvider, name)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "the provider of this endpoint cannot stream logs"})
+		return
+	}
+	// Fail closed before the stream starts: without the rules the raw lines may leak PII
+	if _, err := h.logRedaction.RedactText(ctx, name, ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to redact logs: %v", err)})
+		return
+	}
+
+	// The request context is cancelled when the client disconnects, which closes the pod streams
+	stream, err := streamer.StreamAppLogs(ctx, name, lines, podName, container)
+	if err != nil {
+		respondProviderError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	heartbeat := time.NewTicker(logStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case line, ok := <-stream:
+			if !ok {
+				c.SSEvent("end", gin.H{"reason": "log ended"})
+				return false
+			}
+			// Redaction rules are cached, so masking line by line is cheap
+			redacted, err := h.logRedaction.RedactText(ctx, name, line.Line)
+			if err != nil {
+				c.SSEvent("error", gin.H{"error": fmt.Sprintf("failed to redact logs: %v", err)})
+				return false
+			}
+			line.Line = redacted
+			c.SSEvent("log", line)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/deploy/k8s"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/modelregistry"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errInvalidModelDeploy marks deploy requests whose image and model do not fit together
+var errInvalidModelDeploy = errors.New("invalid deploy request")
+
+// resolveDeployModel resolves the model of a deploy request in the model registry: the image
+// is set to the version's image, the version is pinned and the version's env is added under
+// the request env. A request without a model must name an image. An image set next to a model
+// must be the version's image, so a resolved request resolves again to the same deployment.
+// It returns the lineage to record on the endpoint, nil for requests without a model.
+func (h *EndpointHandler) resolveDeployModel(ctx context.Context, req *k8s.DeployAppRequest) (*interfaces.ModelLineage, error) {
+	if req.ModelName == "" {
+		if req.Image == "" {
+			return nil, fmt.Errorf("%w: image or modelName is required", errInvalidModelDeploy)
+		}
+		return nil, nil
+	}
+	if h.endpointService == nil {
+		return nil, endpointsvc.ErrModelRegistryNotConfigured
+	}
+
+	mv, err := h.endpointService.ResolveModel(ctx, req.ModelName, req.ModelVersion)
+	if err != nil {
+		return nil, err
+	}
+	if req.Image != "" && req.Image != mv.Image {
+		return nil, fmt.Errorf("%w: image %s is not the image %s of model %s version %s",
+			errInvalidModelDeploy, req.Image, mv.Image, mv.Model, mv.Version)
+	}
+
+	req.Image = mv.Image
+	req.ModelVersion = mv.Version
+	if len(mv.Env) > 0 {
+		req.Env = endpointsvc.MergeEnv(mv.Env, req.Env)
+	}
+	logger.InfoCtx(ctx, "Resolved model %s version %s of %s to image %s for endpoint %s",
+		mv.Model, mv.Version, mv.Registry, mv.Image, req.Endpoint)
+	return endpointsvc.ModelLineage(mv), nil
+}
+
+// GetModelVersion resolves a model version in the model registry
+// @Summary Resolve model version
+// @Description Image and env an endpoint would be deployed with for a model version; "latest" resolves the latest approved version
+// @Tags Endpoints
+// @Produce json
+// @Param model path string true "Registered model name"
+// @Param version path string true "Model version, or latest"
+// @Success 200 {object} modelregistry.ModelVersion
+// @Router /api/v1/models/{model}/versions/{version} [get]
+func (h *EndpointHandler) GetModelVersion(c *gin.Context) {
+	mv, err := h.endpointService.ResolveModel(c.Request.Context(), c.Param("model"), c.Param("version"))
+	if err != nil {
+		respondModelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, mv)
+}
+
+// modelDeployRequest selects the model version to move an endpoint to
+type modelDeployRequest struct {
+	Version string `json:"version,omitempty"` // Model version (empty or "latest" = latest approved version)
+}
+
+// DeployEndpointModel moves an endpoint deployed from the model registry to another version of
+// its model, by default the latest approved one
+// @Summary Deploy model version
+// @Description Update an endpoint deployed by modelName to another version of its model (default: latest approved). The image is replaced and the version's env is set over the current env.
+// @Tags Endpoints
+// @Accept json
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param request body modelDeployRequest false "Model version"
+// @Param async query bool false "Update in the background and return an operation to poll at /api/v1/operations/{id}"
+// @Success 200 {object} map[string]interface{}
+// @Success 202 {object} map[string]interface{}
+// @Router /api/v1/endpoints/{name}/model [post]
+func (h *EndpointHandler) DeployEndpointModel(c *gin.Context) {
+	name := c.Param("name")
+	var body modelDeployRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	req, mv, err := h.endpointService.ModelVersionUpdate(c.Request.Context(), name, body.Version)
+	if err != nil {
+		respondModelError(c, err)
+		return
+	}
+	if req == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":  fmt.Sprintf("endpoint already runs model %s version %s", mv.Model, mv.Version),
+			"endpoint": name,
+			"model":    mv,
+		})
+		return
+	}
+
+	if h.holdForApproval(c, name, model.ChangeOpUpdateDeployment, nil, req) {
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Deploying model %s version %s to endpoint %s: image=%s, by=%s",
+		mv.Model, mv.Version, name, mv.Image, c.GetHeader(RequestedByHeader))
+
+	if asyncRequested(c) {
+		if h.operations == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "async operations not available"})
+			return
+		}
+		op, err := h.operations.StartRollout(c.Request.Context(), req, c.GetHeader(RequestedByHeader))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		respondOperationStarted(c, op)
+		return
+	}
+
+	resp, err := h.endpointService.UpdateDeployment(c.Request.Context(), req)
+	if err != nil {
+		logger.ErrorCtx(c.Request.Context(), "Failed to deploy model %s version %s to %s: %v", mv.Model, mv.Version, name, err)
+		respondProviderError(c, err, gin.H{"endpoint": name})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":  resp.Message,
+		"endpoint": resp.Endpoint,
+		"model":    mv,
+	})
+}
+
+// respondModelError maps model registry errors to HTTP statuses; registry failures are 502
+func respondModelError(c *gin.Context, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, endpointsvc.ErrModelRegistryNotConfigured):
+		status = http.StatusNotImplemented
+	case errors.Is(err, modelregistry.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, modelregistry.ErrNoImage):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, endpointsvc.ErrNoModelLineage), errors.Is(err, errInvalidModelDeploy):
+		status = http.StatusBadRequest
+	case strings.HasSuffix(err.Error(), "not found"):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
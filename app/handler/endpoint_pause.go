@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PauseEndpointDispatch stops handing tasks of an endpoint to workers without touching its
+// replicas. In queue mode (default) submissions keep queueing; in reject mode they get 503.
+// POST /api/v1/endpoints/:name/pause
+func (h *EndpointHandler) PauseEndpointDispatch(c *gin.Context) {
+	var req endpointsvc.PauseRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if h.holdForApproval(c, c.Param("name"), model.ChangeOpPauseDispatch, nil, req) {
+		return
+	}
+
+	meta, err := h.endpointService.PauseDispatch(c.Request.Context(), c.Param("name"), &req, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		respondPauseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, meta)
+}
+
+// ResumeEndpointDispatch resumes task dispatch of a paused endpoint
+// POST /api/v1/endpoints/:name/resume
+func (h *EndpointHandler) ResumeEndpointDispatch(c *gin.Context) {
+	if h.holdForApproval(c, c.Param("name"), model.ChangeOpResumeDispatch, nil, nil) {
+		return
+	}
+	meta, err := h.endpointService.ResumeDispatch(c.Request.Context(), c.Param("name"), c.GetHeader(RequestedByHeader))
+	if err != nil {
+		respondPauseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, meta)
+}
+
+func (h *EndpointHandler) executePauseDispatch(ctx context.Context, cr *model.ChangeRequest) error {
+	var req endpointsvc.PauseRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	_, err := h.endpointService.PauseDispatch(ctx, cr.Endpoint, &req, cr.RequestedBy)
+	return err
+}
+
+func (h *EndpointHandler) executeResumeDispatch(ctx context.Context, cr *model.ChangeRequest) error {
+	_, err := h.endpointService.ResumeDispatch(ctx, cr.Endpoint, cr.RequestedBy)
+	return err
+}
+
+func respondPauseError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		status = http.StatusBadRequest
+	case strings.HasSuffix(err.Error(), "not found"):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"waverless/pkg/deploy/k8s"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/knobs"
+	"waverless/pkg/profile"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListProfiles lists the endpoint resource profiles with the settings they expand to
+// GET /api/v1/endpoint-profiles
+func (h *EndpointHandler) ListProfiles(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"profiles": profile.List()})
+}
+
+// ListSettings lists the tunable per-endpoint settings with their types, defaults and valid ranges
+// GET /api/v1/endpoint-settings
+func (h *EndpointHandler) ListSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"settings": knobs.List()})
+}
+
+// applyDeployProfile expands the request's profile on meta; settings given explicitly in the
+// request override it
+func applyDeployProfile(meta *interfaces.EndpointMetadata, req k8s.DeployAppRequest) {
+	p, ok := profile.Get(req.Profile)
+	if !ok {
+		return
+	}
+	p.Apply(meta)
+
+	if req.MinReplicas > 0 {
+		meta.MinReplicas = req.MinReplicas
+	}
+	if req.ScaleUpThreshold > 0 {
+		meta.ScaleUpThreshold = req.ScaleUpThreshold
+	}
+	if req.ScaleDownIdleTime > 0 {
+		meta.ScaleDownIdleTime = req.ScaleDownIdleTime
+	}
+	if req.ScaleUpCooldown > 0 {
+		meta.ScaleUpCooldown = req.ScaleUpCooldown
+	}
+	if req.ScaleDownCooldown > 0 {
+		meta.ScaleDownCooldown = req.ScaleDownCooldown
+	}
+	if req.MaxPendingTasks > 0 {
+		meta.MaxPendingTasks = req.MaxPendingTasks
+	}
+	if req.Priority > 0 {
+		meta.Priority = req.Priority
+	}
+	if req.EnableDynamicPrio != nil {
+		meta.EnableDynamicPrio = req.EnableDynamicPrio
+	}
+	if req.HighLoadThreshold > 0 {
+		meta.HighLoadThreshold = req.HighLoadThreshold
+	}
+	if req.PriorityBoost > 0 {
+		meta.PriorityBoost = req.PriorityBoost
+	}
+}
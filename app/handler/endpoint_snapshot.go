@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetSnapshotService enables endpoint snapshots
+func (h *EndpointHandler) SetSnapshotService(svc *service.EndpointSnapshotService) {
+	h.snapshots = svc
+}
+
+// GetEndpointSnapshot captures the full state of an endpoint in one document
+// @Summary Endpoint snapshot
+// @Description Metadata, autoscaler config, live deployment, workers, last 50 scaling decisions and failed tasks, for attaching to incident tickets. Sections that cannot be read are listed in errors.
+// @Tags Endpoints
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param download query bool false "Send as an attachment"
+// @Success 200 {object} service.EndpointSnapshot
+// @Router /api/v1/endpoints/{name}/snapshot [get]
+func (h *EndpointHandler) GetEndpointSnapshot(c *gin.Context) {
+	if h.snapshots == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "endpoint snapshots not configured"})
+		return
+	}
+	name := c.Param("name")
+
+	snapshot, err := h.snapshots.Capture(c.Request.Context(), name)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if len(snapshot.Errors) > 0 {
+		logger.WarnCtx(c.Request.Context(), "Snapshot of endpoint %s is partial: %v", name, snapshot.Errors)
+	}
+
+	if c.Query("download") == "true" {
+		filename := fmt.Sprintf("%s-snapshot-%s.json", name, snapshot.CapturedAt.Format("20060102-150405"))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
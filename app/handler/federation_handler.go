@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FederationHandler handles federated endpoint APIs (logical endpoints backed by several regions)
+type FederationHandler struct {
+	federationService *service.FederationService
+}
+
+// NewFederationHandler creates a new federation handler
+func NewFederationHandler(federationService *service.FederationService) *FederationHandler {
+	return &FederationHandler{federationService: federationService}
+}
+
+// respondFederationError maps "not found" errors to 404, everything else to the given status
+func respondFederationError(c *gin.Context, err error, status int) {
+	if strings.Contains(err.Error(), "not found") {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// ListFederations lists federated endpoints
+// GET /api/v1/federations
+func (h *FederationHandler) ListFederations(c *gin.Context) {
+	federations, err := h.federationService.ListFederations(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"federations": federations})
+}
+
+// GetFederation gets a federated endpoint with its members
+// GET /api/v1/federations/:name
+func (h *FederationHandler) GetFederation(c *gin.Context) {
+	federation, err := h.federationService.GetFederation(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondFederationError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, federation)
+}
+
+// SaveFederation creates or updates a federated endpoint, replacing its members
+// PUT /api/v1/federations/:name
+func (h *FederationHandler) SaveFederation(c *gin.Context) {
+	var req service.SaveFederationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	federation, err := h.federationService.SaveFederation(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Federated endpoint saved: %s (%d members)", federation.Name, len(federation.Members))
+	c.JSON(http.StatusOK, federation)
+}
+
+// DeleteFederation deletes a federated endpoint (backing endpoints are kept)
+// DELETE /api/v1/federations/:name
+func (h *FederationHandler) DeleteFederation(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.federationService.DeleteFederation(c.Request.Context(), name); err != nil {
+		respondFederationError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Federated endpoint deleted: %s", name)
+	c.JSON(http.StatusOK, gin.H{"message": "federated endpoint deleted", "name": name})
+}
+
+// UpdateRegionWeights overrides the traffic weight of whole regions (gradual region migration)
+// PUT /api/v1/federations/:name/weights
+func (h *FederationHandler) UpdateRegionWeights(c *gin.Context) {
+	var req service.UpdateRegionWeightsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	federation, err := h.federationService.UpdateRegionWeights(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		respondFederationError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, federation)
+}
+
+// GetFederationStatus returns the members in routing order and the current target
+// GET /api/v1/federations/:name/status?region=eu-west
+func (h *FederationHandler) GetFederationStatus(c *gin.Context) {
+	status, err := h.federationService.GetFederationStatus(c.Request.Context(), c.Param("name"), c.Query("region"))
+	if err != nil {
+		respondFederationError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// GetFederationUsage returns GPU usage rolled up per region
+// GET /api/v1/federations/:name/usage?start_time=2026-10-01&end_time=2026-10-15
+func (h *FederationHandler) GetFederationUsage(c *gin.Context) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -7)
+	if s := c.Query("start_time"); s != "" {
+		t, err := parseGPUUsageTime(s, false, time.UTC)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_time, expected RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		from = t
+	}
+	if s := c.Query("end_time"); s != "" {
+		t, err := parseGPUUsageTime(s, true, time.UTC)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_time, expected RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		to = t
+	}
+
+	usage, err := h.federationService.GetFederationUsage(c.Request.Context(), c.Param("name"), from, to)
+	if err != nil {
+		respondFederationError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
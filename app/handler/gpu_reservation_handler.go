@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"waverless/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GPUReservationHandler handles time-boxed GPU reservation APIs
+type GPUReservationHandler struct {
+	reservationService *service.GPUReservationService
+}
+
+// NewGPUReservationHandler creates a new GPU reservation handler
+func NewGPUReservationHandler(reservationService *service.GPUReservationService) *GPUReservationHandler {
+	return &GPUReservationHandler{reservationService: reservationService}
+}
+
+// parseReservationRange parses timezone and start_time/end_time (RFC3339, or YYYY-MM-DD in the
+// timezone), defaulting to the next 30 days
+func parseReservationRange(c *gin.Context) (time.Time, time.Time, *time.Location, error) {
+	loc := time.UTC
+	if tz := c.Query("timezone"); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, errors.New("invalid timezone")
+		}
+		loc = l
+	}
+
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 30)
+	if s := c.Query("start_time"); s != "" {
+		t, err := parseGPUUsageTime(s, false, loc)
+		if err != nil {
+			return start, end, loc, errors.New("invalid start_time, expected RFC3339 or YYYY-MM-DD")
+		}
+		start = t
+	}
+	if s := c.Query("end_time"); s != "" {
+		t, err := parseGPUUsageTime(s, true, loc)
+		if err != nil {
+			return start, end, loc, errors.New("invalid end_time, expected RFC3339 or YYYY-MM-DD")
+		}
+		end = t
+	}
+	if !start.Before(end) {
+		return start, end, loc, errors.New("start_time must be before end_time")
+	}
+	return start, end, loc, nil
+}
+
+// ListReservations lists reservations overlapping a time range
+// GET /api/v1/gpu-reservations?start_time=xxx&end_time=xxx
+func (h *GPUReservationHandler) ListReservations(c *gin.Context) {
+	start, end, _, err := parseReservationRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reservations, err := h.reservationService.ListReservations(c.Request.Context(), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reservations": reservations, "total": len(reservations), "start_time": start, "end_time": end})
+}
+
+// GetCalendar returns reservations grouped by day
+// GET /api/v1/gpu-reservations/calendar?start_time=2026-11-01&end_time=2026-11-30&timezone=Asia/Shanghai
+func (h *GPUReservationHandler) GetCalendar(c *gin.Context) {
+	start, end, loc, err := parseReservationRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	calendar, err := h.reservationService.GetCalendar(c.Request.Context(), start, end, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"calendar": calendar, "timezone": loc.String()})
+}
+
+// GetReservation gets a reservation
+// GET /api/v1/gpu-reservations/:name
+func (h *GPUReservationHandler) GetReservation(c *gin.Context) {
+	reservation, err := h.reservationService.GetReservation(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondGroupError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, reservation)
+}
+
+// CreateReservation reserves GPU capacity for an endpoint during a time window
+// POST /api/v1/gpu-reservations
+func (h *GPUReservationHandler) CreateReservation(c *gin.Context) {
+	var req service.CreateGPUReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reservation, err := h.reservationService.CreateReservation(c.Request.Context(), &req, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "already exists") {
+			status = http.StatusConflict
+		}
+		respondGroupError(c, err, status)
+		return
+	}
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// CancelReservation cancels a scheduled or running reservation
+// DELETE /api/v1/gpu-reservations/:name
+func (h *GPUReservationHandler) CancelReservation(c *gin.Context) {
+	reservation, err := h.reservationService.CancelReservation(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondGroupError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, reservation)
+}
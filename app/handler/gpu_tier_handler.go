@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GPUTierHandler handles the GPU tiers of heterogeneous endpoints
+type GPUTierHandler struct {
+	gpuTierService *service.GPUTierService
+	changeRequests *service.ChangeRequestService // Approval gate for protected endpoints (optional)
+}
+
+// NewGPUTierHandler creates a new GPU tier handler
+func NewGPUTierHandler(gpuTierService *service.GPUTierService) *GPUTierHandler {
+	return &GPUTierHandler{gpuTierService: gpuTierService}
+}
+
+// SetChangeRequestService holds tier changes of protected endpoints for approval
+func (h *GPUTierHandler) SetChangeRequestService(svc *service.ChangeRequestService) {
+	h.changeRequests = svc
+	svc.RegisterExecutor(model.ChangeOpSaveGPUTiers, h.executeSave)
+	svc.RegisterExecutor(model.ChangeOpDeleteGPUTiers, h.executeDelete)
+}
+
+// respondGPUTierError maps "not found" errors to 404, everything else to the given status
+func respondGPUTierError(c *gin.Context, err error, status int) {
+	if strings.Contains(err.Error(), "not found") {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// GetTiers gets the GPU tiers of an endpoint in routing order
+// GET /api/v1/endpoints/:name/gpu-tiers
+func (h *GPUTierHandler) GetTiers(c *gin.Context) {
+	detail, err := h.gpuTierService.GetTiers(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondGPUTierError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+// SaveTiers replaces the GPU tiers of an endpoint
+// PUT /api/v1/endpoints/:name/gpu-tiers
+func (h *GPUTierHandler) SaveTiers(c *gin.Context) {
+	var req service.SaveGPUTiersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if holdForApproval(c, h.changeRequests, c.Param("name"), model.ChangeOpSaveGPUTiers, nil, req) {
+		return
+	}
+
+	detail, err := h.gpuTierService.SaveTiers(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		respondGPUTierError(c, err, status)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "GPU tiers saved: endpoint=%s, tiers=%d", detail.Endpoint, len(detail.Tiers))
+	c.JSON(http.StatusOK, detail)
+}
+
+// DeleteTiers removes the GPU tiers of an endpoint (tier endpoints are kept)
+// DELETE /api/v1/endpoints/:name/gpu-tiers
+func (h *GPUTierHandler) DeleteTiers(c *gin.Context) {
+	name := c.Param("name")
+	if holdForApproval(c, h.changeRequests, name, model.ChangeOpDeleteGPUTiers, nil, nil) {
+		return
+	}
+	if err := h.gpuTierService.DeleteTiers(c.Request.Context(), name); err != nil {
+		respondGPUTierError(c, err, http.StatusInternalServerError)
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "GPU tiers removed: endpoint=%s", name)
+	c.JSON(http.StatusOK, gin.H{"message": "GPU tiers removed", "endpoint": name})
+}
+
+func (h *GPUTierHandler) executeSave(ctx context.Context, cr *model.ChangeRequest) error {
+	var req service.SaveGPUTiersRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	_, err := h.gpuTierService.SaveTiers(ctx, cr.Endpoint, &req)
+	return err
+}
+
+func (h *GPUTierHandler) executeDelete(ctx context.Context, cr *model.ChangeRequest) error {
+	return h.gpuTierService.DeleteTiers(ctx, cr.Endpoint)
+}
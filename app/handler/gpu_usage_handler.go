@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"waverless/internal/service"
+	"waverless/pkg/config"
+	"waverless/pkg/gpuusage"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GPUUsageHandler handles GPU usage API requests
+type GPUUsageHandler struct {
+	gpuUsageService *service.GPUUsageService
+	operations      *service.OperationService
+}
+
+// NewGPUUsageHandler creates a new GPU usage handler
+func NewGPUUsageHandler(gpuUsageService *service.GPUUsageService) *GPUUsageHandler {
+	return &GPUUsageHandler{gpuUsageService: gpuUsageService}
+}
+
+// gpuUsageSummary totals over the returned buckets
+type gpuUsageSummary struct {
+	TotalTasks     int     `json:"total_tasks"`
+	CompletedTasks int     `json:"completed_tasks"`
+	FailedTasks    int     `json:"failed_tasks"`
+	TotalGPUHours  float64 `json:"total_gpu_hours"`
+	MaxGPUCount    int     `json:"max_gpu_count"`
+}
+
+func (s *gpuUsageSummary) add(totalTasks, completed, failed int, gpuHours float64, maxGPU int) {
+	s.TotalTasks += totalTasks
+	s.CompletedTasks += completed
+	s.FailedTasks += failed
+	s.TotalGPUHours += gpuHours
+	if maxGPU > s.MaxGPUCount {
+		s.MaxGPUCount = maxGPU
+	}
+}
+
+// reportingLocation resolves the reporting timezone: the timezone query parameter overrides the
+// timezone of the project the queried endpoint belongs to, which falls back to the configured one
+func (h *GPUUsageHandler) reportingLocation(c *gin.Context) (*time.Location, error) {
+	tz := c.Query("timezone")
+	if tz == "" {
+		return h.gpuUsageService.ReportingLocationFor(c.Request.Context(), c.Query("scope_type"), c.Query("scope_value"))
+	}
+	loc, err := config.LoadReportingLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %s: %v", tz, err)
+	}
+	return loc, nil
+}
+
+// parseGPUUsageTimeRange parses timezone and start_time/end_time (RFC3339, or YYYY-MM-DD in the reporting timezone),
+// defaulting to the last defaultRange
+func (h *GPUUsageHandler) parseGPUUsageTimeRange(c *gin.Context, defaultRange time.Duration) (time.Time, time.Time, *time.Location, error) {
+	loc, err := h.reportingLocation(c)
+	if err != nil {
+		return time.Time{}, time.Time{}, nil, err
+	}
+
+	end := time.Now().In(loc)
+	start := end.Add(-defaultRange)
+
+	if s := c.Query("start_time"); s != "" {
+		t, err := parseGPUUsageTime(s, false, loc)
+		if err != nil {
+			return start, end, loc, errors.New("invalid start_time, expected RFC3339 or YYYY-MM-DD")
+		}
+		start = t
+	}
+	if s := c.Query("end_time"); s != "" {
+		t, err := parseGPUUsageTime(s, true, loc)
+		if err != nil {
+			return start, end, loc, errors.New("invalid end_time, expected RFC3339 or YYYY-MM-DD")
+		}
+		end = t
+	}
+	if !start.Before(end) {
+		return start, end, loc, errors.New("start_time must be before end_time")
+	}
+	return start, end, loc, nil
+}
+
+// parseGPUUsageTime parses an RFC3339 timestamp or a date; dates are local days in loc,
+// and an end date is inclusive (converted to the start of the following day)
+func parseGPUUsageTime(s string, endOfDay bool, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// GetMinuteStats returns minute-level GPU usage statistics
+// GET /api/v1/gpu-usage/minute?scope_type=endpoint&scope_value=xxx&start_time=xxx&end_time=xxx&timezone=Asia/Shanghai
+func (h *GPUUsageHandler) GetMinuteStats(c *gin.Context) {
+	start, end, loc, err := h.parseGPUUsageTimeRange(c, time.Hour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.gpuUsageService.GetMinuteStats(c.Request.Context(), c.Query("scope_type"), c.Query("scope_value"), start, end, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var summary gpuUsageSummary
+	for _, s := range stats {
+		summary.add(s.TotalTasks, s.CompletedTasks, s.FailedTasks, s.TotalGPUHours, s.MaxGPUCount)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": stats, "total": len(stats), "start_time": start, "end_time": end, "timezone": loc.String(), "summary": summary})
+}
+
+// GetHourlyStats returns hourly GPU usage statistics
+// GET /api/v1/gpu-usage/hourly?scope_type=endpoint&scope_value=xxx&start_time=xxx&end_time=xxx&timezone=Asia/Shanghai
+func (h *GPUUsageHandler) GetHourlyStats(c *gin.Context) {
+	start, end, loc, err := h.parseGPUUsageTimeRange(c, 24*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.gpuUsageService.GetHourlyStats(c.Request.Context(), c.Query("scope_type"), c.Query("scope_value"), start, end, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var summary gpuUsageSummary
+	for _, s := range stats {
+		summary.add(s.TotalTasks, s.CompletedTasks, s.FailedTasks, s.TotalGPUHours, s.MaxGPUCount)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": stats, "total": len(stats), "start_time": start, "end_time": end, "timezone": loc.String(), "summary": summary})
+}
+
+// GetDailyStats returns daily GPU usage statistics
+// GET /api/v1/gpu-usage/daily?scope_type=endpoint&scope_value=xxx&start_time=xxx&end_time=xxx&timezone=Asia/Shanghai
+func (h *GPUUsageHandler) GetDailyStats(c *gin.Context) {
+	start, end, loc, err := h.parseGPUUsageTimeRange(c, 30*24*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.gpuUsageService.GetDailyStats(c.Request.Context(), c.Query("scope_type"), c.Query("scope_value"), start, end, loc)
+	if errors.Is(err, gpuusage.ErrBeyondHourlyRetention) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var summary gpuUsageSummary
+	for _, s := range stats {
+		summary.add(s.TotalTasks, s.CompletedTasks, s.FailedTasks, s.TotalGPUHours, s.MaxGPUCount)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": stats, "total": len(stats), "start_time": start, "end_time": end, "timezone": loc.String(), "summary": summary})
+}
+
+// GetTopScopes ranks endpoints, specs, workers or GPU types by usage, e.g. to find underutilized or failing workers
+// GET /api/v1/gpu-usage/top?scope_type=worker&granularity=hourly&order_by=failure_rate&order=desc&limit=10&start_time=xxx&end_time=xxx
+func (h *GPUUsageHandler) GetTopScopes(c *gin.Context) {
+	start, end, _, err := h.parseGPUUsageTimeRange(c, 24*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scopeType := c.DefaultQuery("scope_type", model.GPUUsageScopeWorker)
+	switch scopeType {
+	case model.GPUUsageScopeEndpoint, model.GPUUsageScopeSpec, model.GPUUsageScopeWorker, model.GPUUsageScopeGPUType:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scope_type must be one of endpoint, spec, worker, gpu_type"})
+		return
+	}
+
+	granularity := c.DefaultQuery("granularity", model.GPUUsageGranularityHourly)
+	switch granularity {
+	case model.GPUUsageGranularityMinute, model.GPUUsageGranularityHourly, model.GPUUsageGranularityDaily:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be one of minute, hourly, daily"})
+		return
+	}
+
+	orderBy := c.DefaultQuery("order_by", "gpu_hours")
+	if !mysql.IsValidGPUUsageRankingOrder(orderBy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order_by must be one of gpu_hours, tasks, failed_tasks, failure_rate, utilization"})
+		return
+	}
+	ascending := c.DefaultQuery("order", "desc") == "asc"
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	rankings, err := h.gpuUsageService.GetTopScopes(c.Request.Context(), granularity, scopeType, start, end, orderBy, ascending, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        rankings,
+		"total":       len(rankings),
+		"scope_type":  scopeType,
+		"granularity": granularity,
+		"order_by":    orderBy,
+		"start_time":  start,
+		"end_time":    end,
+	})
+}
+
+// TriggerAggregation re-aggregates existing records for a time range (catch-up)
+// POST /api/v1/gpu-usage/aggregate?granularity=minute&start_time=xxx&end_time=xxx
+func (h *GPUUsageHandler) TriggerAggregation(c *gin.Context) {
+	start, end, _, err := h.parseGPUUsageTimeRange(c, time.Hour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	granularity := c.DefaultQuery("granularity", model.GPUUsageGranularityMinute)
+	result, err := h.gpuUsageService.Reaggregate(c.Request.Context(), granularity, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "aggregation completed",
+		"granularity": granularity,
+		"start_time":  start,
+		"end_time":    end,
+		"result":      result,
+	})
+}
+
+// Backfill creates missing GPU usage records for finished tasks and re-aggregates them. With
+// async=true it starts a resumable, throttled backfill job instead and returns it as an
+// operation to poll for progress and the final consistency report; max_tasks applies to
+// synchronous backfills only.
+// POST /api/v1/gpu-usage/backfill?start_time=xxx&end_time=xxx&batch_size=500&max_tasks=0&async=false&rate_limit=200
+func (h *GPUUsageHandler) Backfill(c *gin.Context) {
+	start, end, _, err := h.parseGPUUsageTimeRange(c, 24*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if asyncRequested(c) {
+		h.startBackfillJob(c, start, end)
+		return
+	}
+
+	batchSize, _ := strconv.Atoi(c.DefaultQuery("batch_size", "500"))
+	maxTasks, _ := strconv.Atoi(c.DefaultQuery("max_tasks", "0"))
+	result, err := h.gpuUsageService.Backfill(c.Request.Context(), start, end, batchSize, maxTasks)
+	if err != nil {
+		if errors.Is(err, gpuusage.ErrBackfillInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// startBackfillJob starts a resumable backfill job of [start, end) and responds with its operation
+func (h *GPUUsageHandler) startBackfillJob(c *gin.Context, start, end time.Time) {
+	if h.operations == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "async operations not available"})
+		return
+	}
+	batchSize, err := strconv.Atoi(c.DefaultQuery("batch_size", strconv.Itoa(gpuusage.DefaultBackfillJobBatchSize)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch_size"})
+		return
+	}
+	rateLimit, err := strconv.Atoi(c.DefaultQuery("rate_limit", strconv.Itoa(gpuusage.DefaultBackfillJobRateLimit)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rate_limit"})
+		return
+	}
+
+	op, err := h.operations.StartGPUUsageBackfill(c.Request.Context(), start, end, batchSize, rateLimit, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		if errors.Is(err, gpuusage.ErrBackfillJobActive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	respondOperationStarted(c, op)
+}
+
+// GetAggregationStatus returns aggregation watermarks and lag per granularity
+// GET /api/v1/gpu-usage/aggregation/status
+func (h *GPUUsageHandler) GetAggregationStatus(c *gin.Context) {
+	status, err := h.gpuUsageService.GetAggregationStatus(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"waverless/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HedgingHandler serves the costs and savings of hedged execution
+type HedgingHandler struct {
+	hedgingService *service.HedgingService
+}
+
+// NewHedgingHandler creates a new hedging handler
+func NewHedgingHandler(hedgingService *service.HedgingService) *HedgingHandler {
+	return &HedgingHandler{hedgingService: hedgingService}
+}
+
+// GetEndpointHedgeStatistics returns how many tasks of an endpoint were hedged, which copy
+// reported first, the execution time the hedges cost and the latency they saved (default: last 24h)
+// GET /api/v1/statistics/endpoints/:endpoint/hedges?start_time=2026-10-14&end_time=2026-10-15
+func (h *HedgingHandler) GetEndpointHedgeStatistics(c *gin.Context) {
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	if s := c.Query("start_time"); s != "" {
+		t, err := parseGPUUsageTime(s, false, time.UTC)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_time, expected RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		from = t
+	}
+	if s := c.Query("end_time"); s != "" {
+		t, err := parseGPUUsageTime(s, true, time.UTC)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_time, expected RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		to = t
+	}
+
+	stats, err := h.hedgingService.Stats(c.Request.Context(), c.Param("endpoint"), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
@@ -1,16 +1,20 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"waverless/internal/service"
 	endpointsvc "waverless/internal/service/endpoint"
 	"waverless/pkg/config"
 	"waverless/pkg/image"
+	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
 	"waverless/pkg/notification"
 )
@@ -20,6 +24,7 @@ type ImageHandler struct {
 	endpointService *endpointsvc.Service
 	imageChecker    *image.Checker
 	notifier        *notification.FeishuNotifier
+	integrations    *service.IntegrationService
 }
 
 // NewImageHandler creates a new image handler
@@ -31,6 +36,22 @@ func NewImageHandler(endpointService *endpointsvc.Service, dockerConfig *config.
 	}
 }
 
+// SetIntegrationService publishes image updates to subscribed integrations
+func (h *ImageHandler) SetIntegrationService(integrations *service.IntegrationService) {
+	h.integrations = integrations
+}
+
+// notifyImageUpdate sends an image update to Feishu and to subscribed integrations
+func (h *ImageHandler) notifyImageUpdate(ctx context.Context, update *notification.ImageUpdateNotification) error {
+	h.integrations.Publish(ctx, &notification.Event{
+		Type:      notification.EventImageUpdate,
+		Endpoint:  update.Endpoint,
+		CreatedAt: update.DetectedAt,
+		Data:      update,
+	})
+	return h.notifier.SendImageUpdateNotification(ctx, update)
+}
+
 // DockerHubWebhookPayload represents the payload from DockerHub webhook
 type DockerHubWebhookPayload struct {
 	PushData struct {
@@ -183,7 +204,7 @@ func (h *ImageHandler) DockerHubWebhook(c *gin.Context) {
 				DetectionType: "webhook",
 			}
 
-			if err := h.notifier.SendImageUpdateNotification(ctx, notification); err != nil {
+			if err := h.notifyImageUpdate(ctx, notification); err != nil {
 				logger.ErrorCtx(ctx, "[Image Webhook] Failed to send Feishu notification for endpoint %s: %v", endpoint.Name, err)
 			} else {
 				notifiedCount++
@@ -306,7 +327,7 @@ func (h *ImageHandler) CheckImageUpdate(c *gin.Context) {
 			DetectionType: "manual",
 		}
 
-		if err := h.notifier.SendImageUpdateNotification(ctx, notif); err != nil {
+		if err := h.notifyImageUpdate(ctx, notif); err != nil {
 			logger.ErrorCtx(ctx, "Failed to send Feishu notification for endpoint %s: %v", name, err)
 		}
 	}
@@ -435,7 +456,7 @@ func (h *ImageHandler) CheckAllImagesUpdate(c *gin.Context) {
 				DetectionType: "manual",
 			}
 
-			if err := h.notifier.SendImageUpdateNotification(ctx, notification); err != nil {
+			if err := h.notifyImageUpdate(ctx, notification); err != nil {
 				logger.ErrorCtx(ctx, "Failed to send Feishu notification for endpoint %s: %v", endpoint.Name, err)
 			} else {
 				notifiedCount++
@@ -451,3 +472,140 @@ func (h *ImageHandler) CheckAllImagesUpdate(c *gin.Context) {
 		"results":       results,
 	})
 }
+
+// ValidateImage validates an image reference and reports format, existence, platform, size and digest
+// @Summary Validate image
+// @Description Validate an image reference against its registry. When endpoint is given the result is stored in its validation history.
+// Private registries: pass X-Registry-Username / X-Registry-Password headers.
+// @Tags Images
+// @Produce json
+// @Param image query string true "Image reference"
+// @Param endpoint query string false "Endpoint to record the result for"
+// @Success 200 {object} interfaces.ImageValidationResult
+// @Router /api/v1/images/validate [get]
+func (h *ImageHandler) ValidateImage(c *gin.Context) {
+	ctx := c.Request.Context()
+	imageRef := strings.TrimSpace(c.Query("image"))
+	if imageRef == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "image is required"})
+		return
+	}
+	endpoint := c.Query("endpoint")
+
+	var cred *interfaces.RegistryCredential
+	if username := c.GetHeader("X-Registry-Username"); username != "" {
+		cred = &interfaces.RegistryCredential{
+			Username: username,
+			Password: c.GetHeader("X-Registry-Password"),
+		}
+	}
+
+	result, err := h.endpointService.ValidateImage(ctx, endpoint, imageRef, cred)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"image":             imageRef,
+		"endpoint":          endpoint,
+		"result":            result,
+		"platformSupported": image.SupportsPlatform(result.Platforms, image.DefaultPlatform),
+	})
+}
+
+// GetImageValidationHistory returns image validation outcomes recorded for an endpoint
+// @Summary Image validation history
+// @Description List recent image validation results for an endpoint (deploy-time and API-triggered), most recent first
+// @Tags Endpoints
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param limit query int false "Max records (default 50)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/endpoints/{name}/image-validations [get]
+func (h *ImageHandler) GetImageValidationHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("name")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	records, err := h.endpointService.ListImageValidations(ctx, name, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	lastVerified, err := h.endpointService.GetLastVerifiedImage(ctx, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"endpoint":     name,
+		"lastVerified": lastVerified,
+		"validations":  records,
+	})
+}
+
+// GetImageCopyHistory returns internal registry copies made for an endpoint
+// @Summary Image copy history
+// @Description List images copied into the internal registry for an endpoint (source, digest, internal reference), most recent first
+// @Tags Endpoints
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param limit query int false "Max records (default 50)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/endpoints/{name}/image-copies [get]
+func (h *ImageHandler) GetImageCopyHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("name")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	records, err := h.endpointService.ListImageCopies(ctx, name, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"endpoint": name,
+		"copies":   records,
+	})
+}
+
+// GetImageProvenance returns the upstream origin of an internal image reference
+// @Summary Image provenance
+// @Description Look up the upstream image and digest an internal registry reference was copied from
+// @Tags Images
+// @Produce json
+// @Param image query string true "Internal image reference (pinned by digest)"
+// @Success 200 {object} model.ImageCopyRecord
+// @Router /api/v1/images/provenance [get]
+func (h *ImageHandler) GetImageProvenance(c *gin.Context) {
+	ctx := c.Request.Context()
+	imageRef := strings.TrimSpace(c.Query("image"))
+	if imageRef == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "image is required"})
+		return
+	}
+
+	record, err := h.endpointService.GetImageProvenance(ctx, imageRef)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if record == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no copy record found for image"})
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IntegrationHandler handles the external webhooks/integrations registry
+type IntegrationHandler struct {
+	integrationService *service.IntegrationService
+}
+
+// NewIntegrationHandler creates a new integration handler
+func NewIntegrationHandler(integrationService *service.IntegrationService) *IntegrationHandler {
+	return &IntegrationHandler{integrationService: integrationService}
+}
+
+// List lists all integrations with their delivery state
+// GET /api/v1/integrations
+func (h *IntegrationHandler) List(c *gin.Context) {
+	integrations, err := h.integrationService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"integrations": integrations})
+}
+
+// Get gets an integration
+// GET /api/v1/integrations/:name
+func (h *IntegrationHandler) Get(c *gin.Context) {
+	integration, err := h.integrationService.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, integration)
+}
+
+// Upsert creates or updates an integration
+// PUT /api/v1/integrations/:name
+func (h *IntegrationHandler) Upsert(c *gin.Context) {
+	var req service.UpsertIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	integration, err := h.integrationService.Upsert(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, integration)
+}
+
+// Delete removes an integration
+// DELETE /api/v1/integrations/:name
+func (h *IntegrationHandler) Delete(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.integrationService.Delete(c.Request.Context(), name); err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "integration deleted", "name": name})
+}
+
+// Test sends a test event to an integration
+// POST /api/v1/integrations/:name/test
+func (h *IntegrationHandler) Test(c *gin.Context) {
+	delivery, err := h.integrationService.Test(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+func respondIntegrationError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if strings.Contains(err.Error(), "not found") {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
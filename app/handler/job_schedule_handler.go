@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobScheduleHandler handles recurring job schedules
+type JobScheduleHandler struct {
+	scheduleService *service.JobScheduleService
+}
+
+// NewJobScheduleHandler creates a new job schedule handler
+func NewJobScheduleHandler(scheduleService *service.JobScheduleService) *JobScheduleHandler {
+	return &JobScheduleHandler{scheduleService: scheduleService}
+}
+
+// SaveSchedule creates or replaces a schedule
+// PUT /api/v1/job-schedules/:name
+func (h *JobScheduleHandler) SaveSchedule(c *gin.Context) {
+	var req service.SaveJobScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requestedBy := c.GetHeader(RequestedByHeader)
+	schedule, err := h.scheduleService.Save(c.Request.Context(), c.Param("name"), &req, requestedBy)
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Job schedule saved: name=%s, cron=%s, timezone=%s, spec=%s, image=%s, suspended=%v, by=%s",
+		schedule.Name, schedule.Cron, schedule.Timezone, schedule.SpecName, schedule.Image, schedule.Suspended, requestedBy)
+	c.JSON(http.StatusOK, schedule)
+}
+
+// ListSchedules lists all schedules
+// GET /api/v1/job-schedules
+func (h *JobScheduleHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.scheduleService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
+}
+
+// GetSchedule gets a schedule
+// GET /api/v1/job-schedules/:name
+func (h *JobScheduleHandler) GetSchedule(c *gin.Context) {
+	schedule, err := h.scheduleService.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule deletes a schedule; its active runs keep running
+// DELETE /api/v1/job-schedules/:name
+func (h *JobScheduleHandler) DeleteSchedule(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.scheduleService.Delete(c.Request.Context(), name); err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Job schedule deleted: name=%s, by=%s", name, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusOK, gin.H{"message": "job schedule deleted"})
+}
+
+// SuspendSchedule stops starting new runs
+// POST /api/v1/job-schedules/:name/suspend
+func (h *JobScheduleHandler) SuspendSchedule(c *gin.Context) {
+	h.setSuspended(c, true)
+}
+
+// ResumeSchedule starts runs again from the next cron time
+// POST /api/v1/job-schedules/:name/resume
+func (h *JobScheduleHandler) ResumeSchedule(c *gin.Context) {
+	h.setSuspended(c, false)
+}
+
+func (h *JobScheduleHandler) setSuspended(c *gin.Context, suspended bool) {
+	schedule, err := h.scheduleService.SetSuspended(c.Request.Context(), c.Param("name"), suspended)
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Job schedule suspended=%v: name=%s, by=%s", suspended, schedule.Name, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusOK, schedule)
+}
+
+// TriggerSchedule starts a run now
+// POST /api/v1/job-schedules/:name/run
+func (h *JobScheduleHandler) TriggerSchedule(c *gin.Context) {
+	job, err := h.scheduleService.Trigger(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Job schedule triggered: name=%s, job=%s, by=%s", c.Param("name"), job.Name, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusCreated, job)
+}
+
+// ListRuns lists the run history of a schedule, newest first
+// GET /api/v1/job-schedules/:name/runs?limit=20
+func (h *JobScheduleHandler) ListRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	runs, err := h.scheduleService.Runs(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		respondBatchJobError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoadTestHandler handles load tests of staging endpoints and their reports
+type LoadTestHandler struct {
+	loadTestService *service.LoadTestService
+}
+
+// NewLoadTestHandler creates a new load test handler
+func NewLoadTestHandler(loadTestService *service.LoadTestService) *LoadTestHandler {
+	return &LoadTestHandler{loadTestService: loadTestService}
+}
+
+// respondLoadTestError maps validation errors to 400, unknown load tests and reports to 404
+// and runs already in progress to 409
+func respondLoadTestError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch msg := err.Error(); {
+	case errors.Is(err, service.ErrLoadTestInProgress):
+		status = http.StatusConflict
+	case strings.HasPrefix(msg, "invalid"):
+		status = http.StatusBadRequest
+	case strings.HasPrefix(msg, "load test ") && strings.HasSuffix(msg, "not found"):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// SaveLoadTest creates or replaces a load test
+// PUT /api/v1/load-tests/:name
+func (h *LoadTestHandler) SaveLoadTest(c *gin.Context) {
+	var req service.SaveLoadTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requestedBy := c.GetHeader(RequestedByHeader)
+	test, err := h.loadTestService.Save(c.Request.Context(), c.Param("name"), &req, requestedBy)
+	if err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Load test saved: name=%s, endpoint=%s, cron=%s, stages=%d, payloads=%d, suspended=%v, by=%s",
+		test.Name, test.Endpoint, test.Cron, len(test.Profile), len(test.Payloads), test.Suspended, requestedBy)
+	c.JSON(http.StatusOK, test)
+}
+
+// ListLoadTests lists all load tests
+// GET /api/v1/load-tests
+func (h *LoadTestHandler) ListLoadTests(c *gin.Context) {
+	tests, err := h.loadTestService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"loadTests": tests})
+}
+
+// GetLoadTest gets a load test
+// GET /api/v1/load-tests/:name
+func (h *LoadTestHandler) GetLoadTest(c *gin.Context) {
+	test, err := h.loadTestService.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, test)
+}
+
+// DeleteLoadTest deletes a load test; a run in progress keeps running and reports are kept
+// DELETE /api/v1/load-tests/:name
+func (h *LoadTestHandler) DeleteLoadTest(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.loadTestService.Delete(c.Request.Context(), name); err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Load test deleted: name=%s, by=%s", name, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusOK, gin.H{"message": "load test deleted"})
+}
+
+// RunLoadTest starts a run now as a long-running operation
+// POST /api/v1/load-tests/:name/run
+func (h *LoadTestHandler) RunLoadTest(c *gin.Context) {
+	requestedBy := c.GetHeader(RequestedByHeader)
+	run, err := h.loadTestService.Trigger(c.Request.Context(), c.Param("name"), requestedBy)
+	if err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Load test started: name=%s, endpoint=%s, image=%s, report=%d, operation=%s, by=%s",
+		run.Report.LoadTest, run.Report.Endpoint, run.Report.Image, run.Report.ID, run.Operation.ID, requestedBy)
+	location := "/api/v1/operations/" + run.Operation.ID
+	c.Header("Location", location)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "load test started",
+		"operation": run.Operation,
+		"report":    run.Report,
+		"pollUrl":   location,
+	})
+}
+
+// ListReports lists the reports of a load test, newest first, without their curves
+// GET /api/v1/load-tests/:name/reports?limit=30
+func (h *LoadTestHandler) ListReports(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	reports, err := h.loadTestService.Reports(c.Request.Context(), c.Param("name"), limit, 0)
+	if err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
+// GetReport gets a report with its latency/error curves and autoscaler behavior
+// GET /api/v1/load-tests/:name/reports/:id
+func (h *LoadTestHandler) GetReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+	report, err := h.loadTestService.GetReport(c.Request.Context(), c.Param("name"), id)
+	if err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// CompareReport compares a report with a baseline report, by default the one picked when
+// the run finished
+// GET /api/v1/load-tests/:name/reports/:id/compare?baseline=12
+func (h *LoadTestHandler) CompareReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+	var baselineID int64
+	if v := c.Query("baseline"); v != "" {
+		if baselineID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid baseline report id"})
+			return
+		}
+	}
+	comparison, err := h.loadTestService.Compare(c.Request.Context(), c.Param("name"), id, baselineID)
+	if err != nil {
+		respondLoadTestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, comparison)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"waverless/internal/service"
+	"waverless/pkg/logship"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogHandler serves worker logs shipped to external storage
+type LogHandler struct {
+	logService *service.LogService
+}
+
+// NewLogHandler creates a new log handler
+func NewLogHandler(logService *service.LogService) *LogHandler {
+	return &LogHandler{logService: logService}
+}
+
+// QueryEndpointLogs queries shipped worker logs of an endpoint (also for deleted pods)
+// @Summary Query shipped endpoint logs
+// @Description Query worker logs forwarded to Loki/Elasticsearch. Unlike /logs this works after the pod is gone.
+// @Tags Endpoints
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param worker query string false "Worker (pod name)"
+// @Param task_id query string false "Task ID"
+// @Param contains query string false "Substring filter"
+// @Param start query string false "Start time (RFC3339, default: end - 1h)"
+// @Param end query string false "End time (RFC3339, default: now)"
+// @Param limit query int false "Max lines (default 500, max 5000)"
+// @Param direction query string false "backward (newest first, default) or forward"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/endpoints/{name}/logs/history [get]
+func (h *LogHandler) QueryEndpointLogs(c *gin.Context) {
+	q := &logship.Query{
+		Endpoint: c.Param("name"),
+		Worker:   c.Query("worker"),
+		TaskID:   c.Query("task_id"),
+		Contains: c.Query("contains"),
+		Forward:  c.Query("direction") == "forward",
+	}
+
+	if s := c.Query("start"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start, expected RFC3339"})
+			return
+		}
+		q.Start = t
+	}
+	if s := c.Query("end"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end, expected RFC3339"})
+			return
+		}
+		q.End = t
+	}
+	if !q.Start.IsZero() && !q.End.IsZero() && !q.Start.Before(q.End) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be before end"})
+		return
+	}
+	if s := c.Query("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		q.Limit = limit
+	}
+
+	entries, err := h.logService.QueryLogs(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"endpoint": q.Endpoint,
+		"backend":  h.logService.Backend(),
+		"start":    q.Start,
+		"end":      q.End,
+		"count":    len(entries),
+		"entries":  entries,
+	})
+}
+
+// GetTaskLogs returns the log slice of a single task
+// @Summary Get task logs
+// @Description Returns the worker log lines between the task's start/end markers. Falls back to the task's execution window when the worker emits no markers.
+// @Tags Tasks
+// @Produce json
+// @Param task_id path string true "Task ID"
+// @Param limit query int false "Max lines (default 500, max 5000)"
+// @Success 200 {object} service.TaskLogs
+// @Router /api/v1/tasks/{task_id}/logs [get]
+func (h *LogHandler) GetTaskLogs(c *gin.Context) {
+	taskID := c.Param("task_id")
+
+	limit := 0
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	logs, err := h.logService.GetTaskLogs(c.Request.Context(), taskID, limit)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	if logs == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no logs found for task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
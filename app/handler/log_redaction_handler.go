@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/redact"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogRedactionHandler handles versioned per-endpoint log redaction rules
+type LogRedactionHandler struct {
+	redactionService *service.LogRedactionService
+	changeRequests   *service.ChangeRequestService // Approval gate for protected endpoints (optional)
+}
+
+// NewLogRedactionHandler creates a new log redaction handler
+func NewLogRedactionHandler(redactionService *service.LogRedactionService) *LogRedactionHandler {
+	return &LogRedactionHandler{redactionService: redactionService}
+}
+
+// SetChangeRequestService holds redaction rule changes of protected endpoints for approval
+func (h *LogRedactionHandler) SetChangeRequestService(svc *service.ChangeRequestService) {
+	h.changeRequests = svc
+	svc.RegisterExecutor(model.ChangeOpSaveLogRedaction, h.executeSave)
+}
+
+// GetRules gets the current log redaction rules of an endpoint
+// GET /api/v1/endpoints/:name/log-redaction
+func (h *LogRedactionHandler) GetRules(c *gin.Context) {
+	current, err := h.redactionService.GetCurrent(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error(), "builtins": redact.Builtins()})
+		return
+	}
+	c.JSON(http.StatusOK, current)
+}
+
+// ListVersions lists every rule version of an endpoint with who changed it, newest first
+// GET /api/v1/endpoints/:name/log-redaction/versions
+func (h *LogRedactionHandler) ListVersions(c *gin.Context) {
+	versions, err := h.redactionService.ListVersions(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// SaveRules stores new log redaction rules of an endpoint as the next version
+// PUT /api/v1/endpoints/:name/log-redaction
+func (h *LogRedactionHandler) SaveRules(c *gin.Context) {
+	var req service.SaveLogRedactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if holdForApproval(c, h.changeRequests, c.Param("name"), model.ChangeOpSaveLogRedaction, nil, req) {
+		return
+	}
+
+	saved, err := h.redactionService.Save(c.Request.Context(), c.Param("name"), &req, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+func (h *LogRedactionHandler) executeSave(ctx context.Context, cr *model.ChangeRequest) error {
+	var req service.SaveLogRedactionRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	_, err := h.redactionService.Save(ctx, cr.Endpoint, &req, cr.RequestedBy)
+	return err
+}
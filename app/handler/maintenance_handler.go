@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+	"waverless/pkg/maintenance"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceHandler handles the read-only switch, emergency brake and worker integrity APIs
+type MaintenanceHandler struct {
+	readOnly  *maintenance.Switch
+	brake     *service.EmergencyBrakeService
+	integrity *service.WorkerIntegrityService
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(readOnly *maintenance.Switch) *MaintenanceHandler {
+	return &MaintenanceHandler{readOnly: readOnly}
+}
+
+// SetEmergencyBrakeService sets the emergency brake service (for dependency injection)
+func (h *MaintenanceHandler) SetEmergencyBrakeService(brake *service.EmergencyBrakeService) {
+	h.brake = brake
+}
+
+// SetWorkerIntegrityService sets the worker integrity service (for dependency injection)
+func (h *MaintenanceHandler) SetWorkerIntegrityService(integrity *service.WorkerIntegrityService) {
+	h.integrity = integrity
+}
+
+// SetReadOnlyRequest request body for toggling read-only mode
+type SetReadOnlyRequest struct {
+	ReadOnly    *bool  `json:"readOnly" binding:"required"`
+	Reason      string `json:"reason"`      // Returned to rejected callers
+	RequestedBy string `json:"requestedBy"` // Operator identity for the audit log
+}
+
+// GetReadOnly returns the read-only status
+// @Summary Get read-only mode
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} maintenance.State
+// @Router /api/v1/admin/read-only [get]
+func (h *MaintenanceHandler) GetReadOnly(c *gin.Context) {
+	c.JSON(http.StatusOK, h.readOnly.Current())
+}
+
+// SetReadOnly turns read-only mode on or off for all replicas
+// @Summary Set read-only mode
+// @Description While on, mutating API calls get 503 with the reason; reads, metrics and task status stay available
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body SetReadOnlyRequest true "Read-only switch"
+// @Success 200 {object} maintenance.State
+// @Router /api/v1/admin/read-only [put]
+func (h *MaintenanceHandler) SetReadOnly(c *gin.Context) {
+	var req SetReadOnlyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := h.readOnly.Set(c.Request.Context(), *req.ReadOnly, req.Reason, req.RequestedBy)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, maintenance.ErrForcedByConfig) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error(), "state": state})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Read-only mode set to %v by %q: %s", state.ReadOnly, req.RequestedBy, req.Reason)
+	c.JSON(http.StatusOK, state)
+}
+
+// EngageBrakeRequest request body for engaging the emergency brake
+type EngageBrakeRequest struct {
+	Reason      string `json:"reason" binding:"required"` // Returned to rejected rollouts
+	DrainToMin  bool   `json:"drainToMin"`                // Scale every endpoint down to its minReplicas
+	RequestedBy string `json:"requestedBy"`               // Operator identity, defaults to the X-Requested-By header
+}
+
+// ReleaseBrakeRequest request body for releasing the emergency brake
+type ReleaseBrakeRequest struct {
+	RequestedBy string `json:"requestedBy"` // Operator identity, defaults to the X-Requested-By header
+}
+
+// GetEmergencyBrake returns the emergency brake status
+// @Summary Get emergency brake
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} maintenance.BrakeState
+// @Router /api/v1/admin/emergency-brake [get]
+func (h *MaintenanceHandler) GetEmergencyBrake(c *gin.Context) {
+	c.JSON(http.StatusOK, h.brake.Current())
+}
+
+// EngageEmergencyBrake freezes autoscaling and rollouts on all replicas
+// @Summary Engage emergency brake
+// @Description While engaged, the autoscaler makes no decisions and deploys, deployment updates and env changes get 503. Manual scaling stays available. The brake stays engaged until released.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body EngageBrakeRequest true "Emergency brake"
+// @Success 200 {object} service.EmergencyBrakeResult
+// @Router /api/v1/admin/emergency-brake [post]
+func (h *MaintenanceHandler) EngageEmergencyBrake(c *gin.Context) {
+	var req EngageBrakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.RequestedBy == "" {
+		req.RequestedBy = c.GetHeader(RequestedByHeader)
+	}
+
+	result, err := h.brake.Engage(c.Request.Context(), req.Reason, req.RequestedBy, req.DrainToMin)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ReleaseEmergencyBrake lifts the emergency brake on all replicas
+// @Summary Release emergency brake
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body ReleaseBrakeRequest false "Release"
+// @Success 200 {object} maintenance.BrakeState
+// @Router /api/v1/admin/emergency-brake/release [post]
+func (h *MaintenanceHandler) ReleaseEmergencyBrake(c *gin.Context) {
+	var req ReleaseBrakeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.RequestedBy == "" {
+		req.RequestedBy = c.GetHeader(RequestedByHeader)
+	}
+
+	state, err := h.brake.Release(c.Request.Context(), req.RequestedBy)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, maintenance.ErrBrakeNotEngaged):
+			status = http.StatusConflict
+		case strings.HasPrefix(err.Error(), "invalid"):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error(), "state": state})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// CheckWorkerIntegrity cross-checks the workers table against live pods without changing anything
+// @Summary Check worker integrity
+// @Description Lists active worker rows whose pod no longer exists and live pods without a worker row. Mismatches younger than the grace period are not reported.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} service.WorkerIntegrityReport
+// @Router /api/v1/admin/worker-integrity [get]
+func (h *MaintenanceHandler) CheckWorkerIntegrity(c *gin.Context) {
+	if h.integrity == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "worker integrity check requires a deployment provider"})
+		return
+	}
+	report, err := h.integrity.Check(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ReconcileWorkerIntegrity runs an integrity pass now, closing out worker rows whose pod is gone
+// @Summary Reconcile worker integrity
+// @Description Marks orphan worker rows OFFLINE (their tasks are reclaimed by the orphaned task cleanup). Pods without a row are only reported.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} service.WorkerIntegrityReport
+// @Router /api/v1/admin/worker-integrity/reconcile [post]
+func (h *MaintenanceHandler) ReconcileWorkerIntegrity(c *gin.Context) {
+	if h.integrity == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "worker integrity check requires a deployment provider"})
+		return
+	}
+	report, err := h.integrity.Reconcile(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Worker integrity reconciled by %q: %d orphan workers, %d unregistered pods",
+		c.GetHeader(RequestedByHeader), len(report.OrphanWorkers), len(report.UnregisteredPods))
+	c.JSON(http.StatusOK, report)
+}
+
+// GetWorkerIntegrityStats returns the integrity counters of this replica
+// @Summary Get worker integrity stats
+// @Description Discrepancies found by the last pass and orphan rows closed out since startup, for alerting
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} service.WorkerIntegrityStats
+// @Router /api/v1/admin/worker-integrity/stats [get]
+func (h *MaintenanceHandler) GetWorkerIntegrityStats(c *gin.Context) {
+	if h.integrity == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "worker integrity check requires a deployment provider"})
+		return
+	}
+	c.JSON(http.StatusOK, h.integrity.Stats())
+}
@@ -14,6 +14,8 @@ import (
 // MonitoringHandler handles monitoring API requests
 type MonitoringHandler struct {
 	monitoringService *service.MonitoringService
+	anomalyService    *service.AnomalyService
+	quotaService      *service.QuotaService
 }
 
 // NewMonitoringHandler creates a new monitoring handler
@@ -21,6 +23,16 @@ func NewMonitoringHandler(monitoringService *service.MonitoringService) *Monitor
 	return &MonitoringHandler{monitoringService: monitoringService}
 }
 
+// SetAnomalyService enables the anomaly evaluation API
+func (h *MonitoringHandler) SetAnomalyService(svc *service.AnomalyService) {
+	h.anomalyService = svc
+}
+
+// SetQuotaService enables the quota status API
+func (h *MonitoringHandler) SetQuotaService(svc *service.QuotaService) {
+	h.quotaService = svc
+}
+
 // GetRealtimeMetrics returns real-time metrics for an endpoint
 // GET /v1/endpoints/:endpoint/metrics/realtime
 func (h *MonitoringHandler) GetRealtimeMetrics(c *gin.Context) {
@@ -112,6 +124,68 @@ func (h *MonitoringHandler) GetStats(c *gin.Context) {
 	})
 }
 
+// GetColdStartStats returns cold start metrics (pod created -> started -> registered -> first task) with p50/p95
+// GET /v1/:endpoint/metrics/cold-starts?from=xxx&to=xxx
+// GET /api/v1/monitoring/cold-starts?group_by=endpoint|spec&endpoint=xxx&from=xxx&to=xxx
+func (h *MonitoringHandler) GetColdStartStats(c *gin.Context) {
+	endpoint := c.Param("endpoint")
+	if endpoint == "" {
+		endpoint = c.Query("endpoint")
+	}
+
+	groupBy := c.DefaultQuery("group_by", monitoring.ColdStartGroupByEndpoint)
+	if groupBy != monitoring.ColdStartGroupByEndpoint && groupBy != monitoring.ColdStartGroupBySpec {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be endpoint or spec"})
+		return
+	}
+
+	// Parse time range (default: last 7 days)
+	to := time.Now()
+	from := to.Add(-7 * 24 * time.Hour)
+	if fromStr := c.Query("from"); fromStr != "" {
+		if t, err := time.Parse(time.RFC3339, fromStr); err == nil {
+			from = t
+		} else if t, err := time.Parse("2006-01-02", fromStr); err == nil {
+			from = t
+		}
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		if t, err := time.Parse(time.RFC3339, toStr); err == nil {
+			to = t
+		} else if t, err := time.Parse("2006-01-02", toStr); err == nil {
+			to = t.Add(24*time.Hour - time.Second)
+		}
+	}
+
+	stats, err := h.monitoringService.GetColdStartStats(c.Request.Context(), endpoint, groupBy, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"endpoint": endpoint,
+		"group_by": groupBy,
+		"time_range": gin.H{
+			"from": from,
+			"to":   to,
+		},
+		"stats": stats,
+	})
+}
+
+// GetRuntimeStateSyncStats returns how many endpoint and worker runtime_state writes were skipped
+// because the pod or deployment state had not materially changed
+// GET /api/v1/monitoring/runtime-state-sync
+func (h *MonitoringHandler) GetRuntimeStateSyncStats(c *gin.Context) {
+	stats := h.monitoringService.RuntimeStateSyncStats()
+	if stats == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "runtime state sync is not available"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
 // fillMissingMinutes fills gaps with zero-value entries
 func fillMissingMinutes(data interface{}, from, to time.Time) interface{} {
 	stats, ok := data.([]*monitoring.MinuteStatResponse)
@@ -190,3 +264,55 @@ func fillMissingDays(data interface{}, from, to time.Time) interface{} {
 	})
 	return stats
 }
+
+// GetAnomalies evaluates an endpoint's recent usage against its baseline (nothing is raised)
+// GET /v1/endpoints/:endpoint/metrics/anomalies
+func (h *MonitoringHandler) GetAnomalies(c *gin.Context) {
+	if h.anomalyService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "anomaly detection not enabled"})
+		return
+	}
+
+	report, err := h.anomalyService.Evaluate(c.Request.Context(), c.Param("endpoint"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetQuotaStatus returns the quota usage and headroom forecast of every project
+// GET /v1/monitoring/quotas
+func (h *MonitoringHandler) GetQuotaStatus(c *gin.Context) {
+	if h.quotaService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "quota forecasting not enabled"})
+		return
+	}
+
+	statuses, err := h.quotaService.Status(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"projects": statuses})
+}
+
+// GetProjectQuota returns the quota usage and headroom forecast of one project
+// GET /v1/monitoring/quotas/:project
+func (h *MonitoringHandler) GetProjectQuota(c *gin.Context) {
+	if h.quotaService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "quota forecasting not enabled"})
+		return
+	}
+
+	status, err := h.quotaService.Project(c.Request.Context(), c.Param("project"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if status == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no quota configured for project " + c.Param("project")})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"waverless/pkg/deploy/novita"
+	"waverless/pkg/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NovitaHandler handles Novita provider resources (registry auths)
+type NovitaHandler struct {
+	provider *novita.NovitaDeploymentProvider
+}
+
+// NewNovitaHandler creates a new Novita handler
+func NewNovitaHandler(provider *novita.NovitaDeploymentProvider) *NovitaHandler {
+	return &NovitaHandler{provider: provider}
+}
+
+// ListRegistryAuths lists registry auths and which auth each endpoint uses
+// GET /api/v1/novita/registry-auths
+func (h *NovitaHandler) ListRegistryAuths(c *gin.Context) {
+	auths, err := h.provider.ListRegistryAuths(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoints := make(map[string]string)
+	for _, auth := range auths {
+		for _, endpoint := range auth.Endpoints {
+			endpoints[endpoint] = auth.ID
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"registryAuths": auths, "endpoints": endpoints})
+}
+
+// GetRateLimit returns the queue and wait metrics of the API rate limiter
+// GET /api/v1/novita/rate-limit
+func (h *NovitaHandler) GetRateLimit(c *gin.Context) {
+	stats := h.provider.RateLimitStats()
+	if stats == nil {
+		c.JSON(http.StatusOK, gin.H{"enabled": false})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"enabled": true, "stats": stats})
+}
+
+// RotateRegistryAuth replaces the auths of a registry and username with a new password
+// POST /api/v1/novita/registry-auths/rotate
+func (h *NovitaHandler) RotateRegistryAuth(c *gin.Context) {
+	var cred interfaces.RegistryCredential
+	if err := c.ShouldBindJSON(&cred); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if cred.Registry == "" || cred.Username == "" || cred.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "registry, username and password are required"})
+		return
+	}
+
+	rotation, err := h.provider.RotateRegistryAuth(c.Request.Context(), &cred)
+	if err != nil {
+		// Partial rotation: the new auth exists but some endpoints still use the old one
+		if rotation != nil {
+			c.JSON(http.StatusMultiStatus, gin.H{"rotation": rotation, "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rotation)
+}
+
+// GarbageCollectRegistryAuths deletes registry auths no endpoint references
+// POST /api/v1/novita/registry-auths/gc
+func (h *NovitaHandler) GarbageCollectRegistryAuths(c *gin.Context) {
+	deleted, err := h.provider.GarbageCollectRegistryAuths(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "deleted": deleted})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
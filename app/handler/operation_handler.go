@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/operation"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OperationHandler handles long-running operations (async deploys, rollouts and load tests)
+// and the backfill, replay and batch jobs exposed as operations
+type OperationHandler struct {
+	operationService *service.OperationService
+}
+
+// NewOperationHandler creates a new operation handler
+func NewOperationHandler(operationService *service.OperationService) *OperationHandler {
+	return &OperationHandler{operationService: operationService}
+}
+
+// SetOperationService enables ?async=true on endpoint deploys and deployment updates
+func (h *EndpointHandler) SetOperationService(svc *service.OperationService) {
+	h.operations = svc
+}
+
+// SetOperationService enables ?async=true on GPU usage backfills
+func (h *GPUUsageHandler) SetOperationService(svc *service.OperationService) {
+	h.operations = svc
+}
+
+// asyncRequested reports whether the request asks to run as a long-running operation
+func asyncRequested(c *gin.Context) bool {
+	async, _ := strconv.ParseBool(c.DefaultQuery("async", "false"))
+	return async
+}
+
+// respondOperationStarted responds 202 with the pending operation and where to poll it
+func respondOperationStarted(c *gin.Context, op *model.Operation) {
+	location := "/api/v1/operations/" + op.ID
+	c.Header("Location", location)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "operation started",
+		"operation": op,
+		"pollUrl":   location,
+	})
+}
+
+// respondOperationError maps unknown operations to 404 and finished ones to 409
+func respondOperationError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, operation.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, operation.ErrFinished):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// startBlueGreen starts a blue/green update as an operation; it always runs asynchronously
+func (h *EndpointHandler) startBlueGreen(c *gin.Context, req *interfaces.UpdateDeploymentRequest) {
+	if h.operations == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "async operations not available"})
+		return
+	}
+	op, err := h.operations.StartBlueGreen(c.Request.Context(), req, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, endpointsvc.ErrBlueGreenUnsupported):
+			status = http.StatusNotImplemented
+		case errors.Is(err, service.ErrBlueGreenInProgress):
+			status = http.StatusConflict
+		case strings.HasPrefix(err.Error(), "invalid"):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Blue/green update started: endpoint=%s, operation=%s, image=%s, by=%s", req.Endpoint, op.ID, req.Image, c.GetHeader(RequestedByHeader))
+	respondOperationStarted(c, op)
+}
+
+// GetOperation returns the status, progress, error and result of an operation. GPU usage
+// backfills, replay jobs and batch jobs are operations with the IDs gpu-usage-backfill-<id>,
+// replay-<id> and job-<name>.
+// GET /api/v1/operations/:id
+func (h *OperationHandler) GetOperation(c *gin.Context) {
+	op, err := h.operationService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondOperationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
+
+// ListOperations lists recent operations, newest first, including the backfill, replay and
+// batch jobs (types gpu_usage_backfill, task_replay and batch_job)
+// GET /api/v1/operations?type=rollout&target=flux&status=running&limit=50
+func (h *OperationHandler) ListOperations(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultOperationListLimit)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	filter := mysql.OperationFilter{
+		Type:   c.Query("type"),
+		Target: c.Query("target"),
+		Status: c.Query("status"),
+	}
+	ops, err := h.operationService.List(c.Request.Context(), filter, limit, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"operations": ops})
+}
+
+// CancelOperation cancels a pending or running operation
+// POST /api/v1/operations/:id/cancel
+func (h *OperationHandler) CancelOperation(c *gin.Context) {
+	id := c.Param("id")
+	op, err := h.operationService.Cancel(c.Request.Context(), id, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		respondOperationError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Operation cancelled: id=%s, type=%s, target=%s, by=%s", id, op.Type, op.Target, c.GetHeader(RequestedByHeader))
+	c.JSON(http.StatusOK, op)
+}
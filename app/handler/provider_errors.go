@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/maintenance"
+)
+
+// providerErrorStatus maps classified provider errors to HTTP statuses (500 if unclassified)
+func providerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, interfaces.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, interfaces.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, interfaces.ErrQuotaExceeded), errors.Is(err, interfaces.ErrThrottled):
+		return http.StatusTooManyRequests
+	case errors.Is(err, interfaces.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, maintenance.ErrBrakeEngaged):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, endpointsvc.ErrHookBlocked):
+		return http.StatusFailedDependency
+	}
+	return http.StatusInternalServerError
+}
+
+// respondProviderError writes err with the status of its provider error kind.
+// extra fields are merged into the response body.
+func respondProviderError(c *gin.Context, err error, extra ...gin.H) {
+	status := providerErrorStatus(err)
+	body := gin.H{"error": err.Error()}
+	if kind := interfaces.ProviderErrorKind(err); kind != nil {
+		body["reason"] = kind.Error()
+	}
+	for _, fields := range extra {
+		for k, v := range fields {
+			body[k] = v
+		}
+	}
+	if errors.Is(err, interfaces.ErrThrottled) {
+		c.Header("Retry-After", "5")
+	}
+	c.JSON(status, body)
+}
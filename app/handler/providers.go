@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"context"
+
+	"waverless/pkg/interfaces"
+)
+
+// endpointProvider returns the deployment provider serving endpoint as a T, e.g. a concrete
+// provider or an optional capability
+func endpointProvider[T any](ctx context.Context, p interfaces.DeploymentProvider, endpoint string) (T, bool) {
+	resolved, ok := interfaces.ResolveProvider(ctx, p, endpoint, "").(T)
+	return resolved, ok
+}
+
+// anyProvider returns the first configured deployment provider that is a T, the default first
+func anyProvider[T any](p interfaces.DeploymentProvider) (T, bool) {
+	if router, ok := p.(interfaces.ProviderRouter); ok {
+		for _, candidate := range router.Providers() {
+			if found, ok := candidate.(T); ok {
+				return found, true
+			}
+		}
+		var zero T
+		return zero, false
+	}
+	found, ok := p.(T)
+	return found, ok
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegistryMirrorHandler handles registry mirror mapping APIs
+type RegistryMirrorHandler struct {
+	mirrorService *service.RegistryMirrorService
+}
+
+// NewRegistryMirrorHandler creates a new registry mirror handler
+func NewRegistryMirrorHandler(mirrorService *service.RegistryMirrorService) *RegistryMirrorHandler {
+	return &RegistryMirrorHandler{mirrorService: mirrorService}
+}
+
+// ListMirrors lists config and API-managed mirror mappings
+// GET /api/v1/registry-mirrors
+func (h *RegistryMirrorHandler) ListMirrors(c *gin.Context) {
+	mirrors, err := h.mirrorService.ListMirrors(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"mirrors": mirrors})
+}
+
+// UpsertMirror creates or updates a mirror mapping
+// PUT /api/v1/registry-mirrors
+func (h *RegistryMirrorHandler) UpsertMirror(c *gin.Context) {
+	var req service.UpsertRegistryMirrorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mirror, err := h.mirrorService.UpsertMirror(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Registry mirror updated: %s -> %s (enabled=%v)", mirror.Upstream, mirror.Mirror, mirror.Enabled)
+	c.JSON(http.StatusOK, mirror)
+}
+
+// DeleteMirror removes an API-managed mirror mapping
+// DELETE /api/v1/registry-mirrors/:upstream
+func (h *RegistryMirrorHandler) DeleteMirror(c *gin.Context) {
+	upstream := c.Param("upstream")
+	if err := h.mirrorService.DeleteMirror(c.Request.Context(), upstream); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Registry mirror deleted: %s", upstream)
+	c.JSON(http.StatusOK, gin.H{"message": "registry mirror deleted", "upstream": upstream})
+}
+
+// ResolveImage previews the image reference used at render time
+// GET /api/v1/registry-mirrors/resolve?image=xxx
+func (h *RegistryMirrorHandler) ResolveImage(c *gin.Context) {
+	image := strings.TrimSpace(c.Query("image"))
+	if image == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "image is required"})
+		return
+	}
+
+	resolved, mirrored, err := h.mirrorService.ResolveImage(c.Request.Context(), image)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"image":    image,
+		"resolved": resolved,
+		"mirrored": mirrored,
+	})
+}
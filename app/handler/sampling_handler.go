@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SamplingHandler handles task input/output sampling rules
+type SamplingHandler struct {
+	samplingService *service.SamplingService
+	changeRequests  *service.ChangeRequestService // Approval gate for protected endpoints (optional)
+}
+
+// NewSamplingHandler creates a new sampling handler
+func NewSamplingHandler(samplingService *service.SamplingService) *SamplingHandler {
+	return &SamplingHandler{samplingService: samplingService}
+}
+
+// SetChangeRequestService holds sampling rule changes of protected endpoints for approval
+func (h *SamplingHandler) SetChangeRequestService(svc *service.ChangeRequestService) {
+	h.changeRequests = svc
+	svc.RegisterExecutor(model.ChangeOpSaveSampling, h.executeUpsert)
+	svc.RegisterExecutor(model.ChangeOpDeleteSampling, h.executeDelete)
+}
+
+// ListRules lists all sampling rules with the capture counters
+// GET /api/v1/sampling
+func (h *SamplingHandler) ListRules(c *gin.Context) {
+	rules, err := h.samplingService.ListRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	stats := h.samplingService.Stats()
+	c.JSON(http.StatusOK, gin.H{"rules": rules, "capturing": stats != nil, "stats": stats})
+}
+
+// GetRule gets the sampling rule of an endpoint
+// GET /api/v1/endpoints/:name/sampling
+func (h *SamplingHandler) GetRule(c *gin.Context) {
+	rule, err := h.samplingService.GetRule(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpsertRule creates or updates the sampling rule of an endpoint
+// PUT /api/v1/endpoints/:name/sampling
+func (h *SamplingHandler) UpsertRule(c *gin.Context) {
+	var req service.UpsertSamplingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if holdForApproval(c, h.changeRequests, c.Param("name"), model.ChangeOpSaveSampling, nil, req) {
+		return
+	}
+
+	rule, err := h.samplingService.UpsertRule(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Sampling rule updated: endpoint=%s, rate=%v, enabled=%v", rule.Endpoint, rule.SampleRate, rule.Enabled)
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule stops sampling an endpoint
+// DELETE /api/v1/endpoints/:name/sampling
+func (h *SamplingHandler) DeleteRule(c *gin.Context) {
+	name := c.Param("name")
+	if holdForApproval(c, h.changeRequests, name, model.ChangeOpDeleteSampling, nil, nil) {
+		return
+	}
+	if err := h.samplingService.DeleteRule(c.Request.Context(), name); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "sampling rule deleted", "endpoint": name})
+}
+
+func (h *SamplingHandler) executeUpsert(ctx context.Context, cr *model.ChangeRequest) error {
+	var req service.UpsertSamplingRuleRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	_, err := h.samplingService.UpsertRule(ctx, cr.Endpoint, &req)
+	return err
+}
+
+func (h *SamplingHandler) executeDelete(ctx context.Context, cr *model.ChangeRequest) error {
+	return h.samplingService.DeleteRule(ctx, cr.Endpoint)
+}
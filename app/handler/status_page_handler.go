@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusPageHandler serves the client-visible endpoint status for external status pages
+type StatusPageHandler struct {
+	statusPageService *service.StatusPageService
+}
+
+// NewStatusPageHandler creates a new status page handler
+func NewStatusPageHandler(statusPageService *service.StatusPageService) *StatusPageHandler {
+	return &StatusPageHandler{statusPageService: statusPageService}
+}
+
+// GetProjectStatus gets the status of the exposed endpoints of a project
+// GET /status/v1/projects/:project
+func (h *StatusPageHandler) GetProjectStatus(c *gin.Context) {
+	page, err := h.statusPageService.Project(c.Request.Context(), c.Param("project"))
+	if err != nil {
+		respondStatusPageError(c, err)
+		return
+	}
+	c.Header("Access-Control-Allow-Origin", "*") // Status pages fetch from the browser
+	c.JSON(http.StatusOK, page)
+}
+
+// GetEndpointStatus gets the status of one exposed endpoint of a project
+// GET /status/v1/projects/:project/endpoints/:endpoint
+func (h *StatusPageHandler) GetEndpointStatus(c *gin.Context) {
+	status, err := h.statusPageService.Endpoint(c.Request.Context(), c.Param("project"), c.Param("endpoint"))
+	if err != nil {
+		respondStatusPageError(c, err)
+		return
+	}
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.JSON(http.StatusOK, status)
+}
+
+// respondStatusPageError hides internal errors: the API is reachable without credentials
+func respondStatusPageError(c *gin.Context, err error) {
+	if strings.HasSuffix(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	logger.ErrorCtx(c.Request.Context(), "status page: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "status unavailable"})
+}
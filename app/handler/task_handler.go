@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 	"waverless/internal/model"
 	"waverless/internal/service"
 	"waverless/pkg/logger"
+	"waverless/pkg/wake"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +19,7 @@ import (
 type TaskHandler struct {
 	taskService   *service.TaskService
 	workerService *service.WorkerService
+	wakeService   *service.WakeService
 }
 
 // NewTaskHandler creates task handler
@@ -27,6 +30,11 @@ func NewTaskHandler(taskService *service.TaskService, workerService *service.Wor
 	}
 }
 
+// SetWakeService enables waiting for the cold start of endpoints scaled to zero on submit
+func (h *TaskHandler) SetWakeService(svc *service.WakeService) {
+	h.wakeService = svc
+}
+
 // Status gets task status
 // @Summary Get task status
 // @Description Get task status by task ID
@@ -82,8 +90,10 @@ func (h *TaskHandler) Cancel(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param endpoint path string true "Endpoint name"
+// @Param wait_ready query string false "Seconds (or true for the cold start budget) to wait for a worker of an endpoint scaled to zero"
 // @Param request body model.SubmitRequest true "Task request"
 // @Success 200 {object} model.SubmitResponse
+// @Success 202 {object} model.SubmitResponse "Endpoint still cold, see Retry-After"
 // @Router /{endpoint}/submit [post]
 func (h *TaskHandler) SubmitWithEndpoint(c *gin.Context) {
 	endpoint := c.Param("endpoint")
@@ -92,6 +102,16 @@ func (h *TaskHandler) SubmitWithEndpoint(c *gin.Context) {
 		return
 	}
 
+	// Validated before submitting so a bad parameter doesn't leave a task behind
+	var wait time.Duration
+	if h.wakeService != nil {
+		var err error
+		if wait, err = wake.ParseWait(c.Query("wait_ready"), h.wakeService.Budget()); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	var req model.SubmitRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		logger.ErrorCtx(c.Request.Context(), "invalid request: %v", err)
@@ -101,17 +121,47 @@ func (h *TaskHandler) SubmitWithEndpoint(c *gin.Context) {
 
 	// Set endpoint
 	req.Endpoint = endpoint
+	req.ClientIP = c.ClientIP()
+	if req.Subject == "" {
+		req.Subject = c.GetHeader(SubjectKeyHeader)
+	}
 
 	resp, err := h.taskService.SubmitTask(c.Request.Context(), &req)
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), "failed to submit task: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(submitErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	if resp.Waking && h.wakeService != nil {
+		h.respondWaking(c, endpoint, resp, wait)
+		return
+	}
 	c.JSON(http.StatusOK, resp)
 }
 
+// respondWaking answers a submission to an endpoint scaled to zero: 200 once a worker takes
+// tasks within wait, otherwise 202 Accepted with the expected rest of the cold start as Retry-After
+func (h *TaskHandler) respondWaking(c *gin.Context, endpoint string, resp *model.SubmitResponse, wait time.Duration) {
+	ctx := c.Request.Context()
+	start := time.Now()
+	if wait > 0 {
+		ready, err := h.wakeService.WaitReady(ctx, endpoint, wait)
+		if err != nil {
+			logger.WarnCtx(ctx, "failed to wait for endpoint readiness, endpoint: %s, error: %v", endpoint, err)
+		}
+		if ready {
+			resp.Waking = false
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+	}
+
+	retryAfter := h.wakeService.RetryAfter(ctx, endpoint, time.Since(start))
+	c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	c.JSON(http.StatusAccepted, resp)
+}
+
 // SubmitSyncWithEndpoint submits task synchronously to specified endpoint
 // @Summary Submit task synchronously to specified endpoint
 // @Description Submit task to specified endpoint and wait for result
@@ -139,6 +189,10 @@ func (h *TaskHandler) SubmitSyncWithEndpoint(c *gin.Context) {
 
 	// Set endpoint
 	req.Endpoint = endpoint
+	req.ClientIP = c.ClientIP()
+	if req.Subject == "" {
+		req.Subject = c.GetHeader(SubjectKeyHeader)
+	}
 
 	// Read wait timeout from query parameter (milliseconds), if not set wait indefinitely
 	var timeout time.Duration
@@ -154,13 +208,39 @@ func (h *TaskHandler) SubmitSyncWithEndpoint(c *gin.Context) {
 	resp, err := h.taskService.SubmitTaskSync(c.Request.Context(), &req, timeout)
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), "failed to submit task sync: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(submitErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+// submitErrorStatus maps a submission error to its HTTP status: 503 while the endpoint is
+// paused in reject mode or its circuit breaker is open, 500 otherwise
+func submitErrorStatus(err error) int {
+	if errors.Is(err, service.ErrEndpointPaused) || errors.Is(err, service.ErrCircuitOpen) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// RequireRunPodCompat rejects the RunPod client API paths (/v2/:endpoint/run, ...) for endpoints
+// with the RunPod compatibility layer disabled
+func (h *TaskHandler) RequireRunPodCompat(c *gin.Context) {
+	endpoint := c.Param("endpoint")
+	enabled, err := h.taskService.RunPodCompatEnabled(c.Request.Context(), endpoint)
+	if err != nil {
+		logger.ErrorCtx(c.Request.Context(), "failed to check runpod compatibility, endpoint: %s, error: %v", endpoint, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !enabled {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("runpod compatibility is disabled for endpoint %s, use /v1/%s", endpoint, endpoint)})
+		return
+	}
+	c.Next()
+}
+
 // ListTasks gets task list
 // @Summary Get task list
 // @Description Get task list, supports filtering by status, endpoint and task_id, supports pagination, returns tasks array and total count
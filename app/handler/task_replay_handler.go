@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaskReplayHandler handles task replays
+type TaskReplayHandler struct {
+	replayService *service.TaskReplayService
+}
+
+// NewTaskReplayHandler creates a new task replay handler
+func NewTaskReplayHandler(replayService *service.TaskReplayService) *TaskReplayHandler {
+	return &TaskReplayHandler{replayService: replayService}
+}
+
+// ReplayTask submits the input of a past task again, to its endpoint or the one in the body
+// POST /api/v1/tasks/:task_id/replay
+func (h *TaskReplayHandler) ReplayTask(c *gin.Context) {
+	var req service.ReplayTaskRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	task, err := h.replayService.ReplayTask(c.Request.Context(), c.Param("task_id"), &req, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		respondReplayError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// CreateReplayJob starts replaying the tasks of a time window into a target endpoint
+// POST /api/v1/replays
+func (h *TaskReplayHandler) CreateReplayJob(c *gin.Context) {
+	var req service.ReplayJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.replayService.CreateJob(c.Request.Context(), &req, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		respondReplayError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListReplayJobs lists recent replay jobs, newest first
+// GET /api/v1/replays
+func (h *TaskReplayHandler) ListReplayJobs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	jobs, err := h.replayService.ListJobs(c.Request.Context(), "", "", limit, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+// GetReplayJob gets a replay job with the status counts of the tasks it submitted
+// GET /api/v1/replays/:id
+func (h *TaskReplayHandler) GetReplayJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	job, err := h.replayService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		respondReplayError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelReplayJob stops a pending or running replay job
+// POST /api/v1/replays/:id/cancel
+func (h *TaskReplayHandler) CancelReplayJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	job, err := h.replayService.CancelJob(c.Request.Context(), id, c.GetHeader(RequestedByHeader))
+	if err != nil {
+		respondReplayError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// GetEndpointReplayStatistics counts the replayed tasks of an endpoint by status; they are
+// left out of the endpoint's regular statistics
+// GET /api/v1/statistics/endpoints/:endpoint/replays
+func (h *TaskReplayHandler) GetEndpointReplayStatistics(c *gin.Context) {
+	endpoint := c.Param("endpoint")
+	counts, err := h.replayService.EndpointStatistics(c.Request.Context(), endpoint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"endpoint": endpoint, "replays": counts})
+}
+
+func respondReplayError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		status = http.StatusBadRequest
+	case strings.HasSuffix(err.Error(), "not found"):
+		status = http.StatusNotFound
+	case strings.Contains(err.Error(), service.ErrEndpointPaused.Error()):
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransformHandler handles versioned endpoint input/output transforms
+type TransformHandler struct {
+	transformService *service.TransformService
+	changeRequests   *service.ChangeRequestService // Approval gate for protected endpoints (optional)
+}
+
+// NewTransformHandler creates a new transform handler
+func NewTransformHandler(transformService *service.TransformService) *TransformHandler {
+	return &TransformHandler{transformService: transformService}
+}
+
+// SetChangeRequestService holds transform changes of protected endpoints for approval
+func (h *TransformHandler) SetChangeRequestService(svc *service.ChangeRequestService) {
+	h.changeRequests = svc
+	svc.RegisterExecutor(model.ChangeOpSaveTransform, h.executeSave)
+	svc.RegisterExecutor(model.ChangeOpRollbackTransform, h.executeRollback)
+}
+
+// GetTransform gets the current transform of an endpoint
+// GET /api/v1/endpoints/:name/transforms
+func (h *TransformHandler) GetTransform(c *gin.Context) {
+	current, err := h.transformService.GetCurrent(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, current)
+}
+
+// ListVersions lists every transform version of an endpoint, newest first
+// GET /api/v1/endpoints/:name/transforms/versions
+func (h *TransformHandler) ListVersions(c *gin.Context) {
+	versions, err := h.transformService.ListVersions(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// SaveTransform stores a new transform version of an endpoint
+// PUT /api/v1/endpoints/:name/transforms
+func (h *TransformHandler) SaveTransform(c *gin.Context) {
+	var req service.SaveTransformRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if holdForApproval(c, h.changeRequests, c.Param("name"), model.ChangeOpSaveTransform, nil, req) {
+		return
+	}
+
+	saved, err := h.transformService.Save(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Endpoint transform saved: endpoint=%s, version=%d", saved.Endpoint, saved.Version)
+	c.JSON(http.StatusOK, saved)
+}
+
+// Rollback restores an earlier transform version as a new version
+// POST /api/v1/endpoints/:name/transforms/rollback
+func (h *TransformHandler) Rollback(c *gin.Context) {
+	var req service.RollbackTransformRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if holdForApproval(c, h.changeRequests, c.Param("name"), model.ChangeOpRollbackTransform, nil, req) {
+		return
+	}
+
+	saved, err := h.transformService.Rollback(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "Endpoint transform rolled back: endpoint=%s, from=%d, version=%d", saved.Endpoint, req.Version, saved.Version)
+	c.JSON(http.StatusOK, saved)
+}
+
+func (h *TransformHandler) executeSave(ctx context.Context, cr *model.ChangeRequest) error {
+	var req service.SaveTransformRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	_, err := h.transformService.Save(ctx, cr.Endpoint, &req)
+	return err
+}
+
+func (h *TransformHandler) executeRollback(ctx context.Context, cr *model.ChangeRequest) error {
+	var req service.RollbackTransformRequest
+	if err := service.DecodeChangePayload(cr, &req); err != nil {
+		return err
+	}
+	_, err := h.transformService.Rollback(ctx, cr.Endpoint, &req)
+	return err
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondBroadcastError maps "invalid" errors to 400, "not found" errors to 404
+func respondBroadcastError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if strings.HasPrefix(err.Error(), "invalid") {
+		status = http.StatusBadRequest
+	} else if strings.Contains(err.Error(), "not found") {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// CreateBroadcast sends a control message (e.g. flush the result cache after a weights update)
+// to every worker of an endpoint through the heartbeat. Not held for approval: a broadcast
+// expires with its TTL and changes nothing that is deployed.
+// POST /api/v1/endpoints/:name/broadcasts
+func (h *WorkerHandler) CreateBroadcast(c *gin.Context) {
+	if h.broadcastService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker broadcasts not available"})
+		return
+	}
+
+	var req service.CreateBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := c.Param("name")
+	requestedBy := c.GetHeader(RequestedByHeader)
+	detail, err := h.broadcastService.Create(c.Request.Context(), name, &req, requestedBy)
+	if err != nil {
+		respondBroadcastError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Worker broadcast sent: endpoint=%s, id=%d, type=%s, workers=%d, skipped=%d, by=%s",
+		name, detail.ID, detail.Type, detail.Summary.Workers, len(detail.Skipped), requestedBy)
+	c.JSON(http.StatusCreated, detail)
+}
+
+// ListBroadcasts lists the recent broadcasts of an endpoint with their acknowledgement counts
+// GET /api/v1/endpoints/:name/broadcasts
+func (h *WorkerHandler) ListBroadcasts(c *gin.Context) {
+	if h.broadcastService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker broadcasts not available"})
+		return
+	}
+
+	broadcasts, err := h.broadcastService.List(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondBroadcastError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"endpoint": c.Param("name"), "broadcasts": broadcasts})
+}
+
+// GetBroadcast gets a broadcast with the acknowledgement of each worker
+// GET /api/v1/endpoints/:name/broadcasts/:id
+func (h *WorkerHandler) GetBroadcast(c *gin.Context) {
+	if h.broadcastService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker broadcasts not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid broadcast id"})
+		return
+	}
+	detail, err := h.broadcastService.Get(c.Request.Context(), c.Param("name"), id)
+	if err != nil {
+		respondBroadcastError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+// PushWorkerConfig changes the log level or feature flags of one worker or of every worker of an
+// endpoint at runtime, without a redeploy. Not held for approval: it is meant for debugging
+// during an incident, is delivered as a broadcast and is not stored on the endpoint.
+// POST /api/v1/endpoints/:name/worker-config
+func (h *WorkerHandler) PushWorkerConfig(c *gin.Context) {
+	if h.broadcastService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker broadcasts not available"})
+		return
+	}
+
+	var req service.WorkerConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := c.Param("name")
+	requestedBy := c.GetHeader(RequestedByHeader)
+	detail, err := h.broadcastService.PushWorkerConfig(c.Request.Context(), name, &req, requestedBy)
+	if err != nil {
+		respondBroadcastError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Worker config pushed: endpoint=%s, worker=%s, logLevel=%s, featureFlags=%v, id=%d, workers=%d, skipped=%d, by=%s",
+		name, req.WorkerID, req.LogLevel, req.FeatureFlags, detail.ID, detail.Summary.Workers, len(detail.Skipped), requestedBy)
+	c.JSON(http.StatusCreated, detail)
+}
@@ -2,13 +2,18 @@ package handler
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"waverless/internal/model"
 	"waverless/internal/service"
+	"waverless/pkg/deploy/k8s"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/scheduler"
 	"waverless/pkg/status"
 	mysqlModel "waverless/pkg/store/mysql/model"
 
@@ -20,6 +25,10 @@ type WorkerHandler struct {
 	workerService      *service.WorkerService
 	taskService        *service.TaskService
 	deploymentProvider interfaces.DeploymentProvider
+	workerEventService *service.WorkerEventService
+	handshakeService   *service.HandshakeService
+	broadcastService   *service.WorkerBroadcastService
+	quarantineService  *service.WorkerQuarantineService
 }
 
 // NewWorkerHandler creates a new worker handler
@@ -31,6 +40,26 @@ func NewWorkerHandler(workerService *service.WorkerService, taskService *service
 	}
 }
 
+// SetWorkerEventService sets the worker event service (audit trail for debug attaches)
+func (h *WorkerHandler) SetWorkerEventService(svc *service.WorkerEventService) {
+	h.workerEventService = svc
+}
+
+// SetHandshakeService enables the worker startup handshake and endpoint warnings
+func (h *WorkerHandler) SetHandshakeService(svc *service.HandshakeService) {
+	h.handshakeService = svc
+}
+
+// SetBroadcastService enables endpoint broadcasts to workers
+func (h *WorkerHandler) SetBroadcastService(svc *service.WorkerBroadcastService) {
+	h.broadcastService = svc
+}
+
+// SetQuarantineService enables worker quarantine and node/image bans
+func (h *WorkerHandler) SetQuarantineService(svc *service.WorkerQuarantineService) {
+	h.quarantineService = svc
+}
+
 // WorkerWithPodInfo Worker info (includes Pod status)
 type WorkerWithPodInfo struct {
 	model.Worker
@@ -40,13 +69,14 @@ type WorkerWithPodInfo struct {
 	PodMessage        string `json:"podMessage,omitempty"`        // Status message
 	PodIP             string `json:"podIP,omitempty"`             // Pod IP
 	PodNodeName       string `json:"podNodeName,omitempty"`       // Node name
+	GPUType           string `json:"gpuType,omitempty"`           // GPU type of the node when the spec has GPU type fallbacks
 	PodCreatedAt      string `json:"podCreatedAt,omitempty"`      // Pod creation time
 	PodStartedAt      string `json:"podStartedAt,omitempty"`      // Pod start time
 	PodRestartCount   int32  `json:"podRestartCount,omitempty"`   // Container restart count
 	DeletionTimestamp string `json:"deletionTimestamp,omitempty"` // Set when pod is terminating
 
 	// Failure information fields (Requirements 6.1, 6.2)
-	FailureType       string `json:"failureType,omitempty"`       // IMAGE_PULL_FAILED, CONTAINER_CRASH, RESOURCE_LIMIT, TIMEOUT, UNKNOWN
+	FailureType       string `json:"failureType,omitempty"`       // IMAGE_PULL_FAILED, CONTAINER_CRASH, RESOURCE_LIMIT, TIMEOUT, GPU_UNHEALTHY, UNKNOWN
 	FailureReason     string `json:"failureReason,omitempty"`     // Sanitized user-friendly failure message
 	FailureSuggestion string `json:"failureSuggestion,omitempty"` // Actionable suggestion for the user
 }
@@ -60,7 +90,13 @@ type WorkerWithPodInfo struct {
 // @Param worker_id query string true "Worker ID"
 // @Param endpoint query string false "Endpoint that worker belongs to"
 // @Param job_in_progress query []string false "List of task IDs in progress"
-// @Success 200 {object} map[string]string
+// @Param capabilities query string false "Comma-separated worker capabilities (streaming, cancellation, checkpointing, batch); also accepted via X-Worker-Capabilities header"
+// @Param custom_metric query number false "Custom autoscaling gauge (e.g. internal batch queue length); also accepted via X-Worker-Custom-Metric header"
+// @Param ack query []int false "IDs of broadcasts the worker applied"
+// @Param nack query []string false "Broadcasts the worker could not apply, as id:error"
+// @Param gpu_health query string false "Result of the worker's GPU health check: ok or unhealthy; also accepted via X-Worker-GPU-Health header"
+// @Param gpu_health_reason query string false "What failed the GPU health check; also accepted via X-Worker-GPU-Health-Reason header"
+// @Success 200 {object} model.HeartbeatResponse
 // @Router /ping [get]
 func (h *WorkerHandler) Heartbeat(c *gin.Context) {
 	// Get endpoint from URL path (required)
@@ -84,15 +120,181 @@ func (h *WorkerHandler) Heartbeat(c *gin.Context) {
 		WorkerID:       workerID,
 		JobsInProgress: jobsInProgress,
 		Version:        version,
+		Capabilities:   workerCapabilities(c),
+		CustomMetric:   workerCustomMetric(c),
+		BroadcastAcks:  workerBroadcastAcks(c),
+		GPUHealth:      workerGPUHealth(c),
 	}
 
-	if err := h.workerService.HandleHeartbeat(c.Request.Context(), req, endpoint); err != nil {
+	resp, err := h.workerService.HandleHeartbeat(c.Request.Context(), req, endpoint)
+	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), "failed to handle heartbeat: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handshake handles the worker startup handshake: contract version negotiation and a check of
+// the env/volume layout the worker sees. Mismatches are returned and recorded as endpoint warnings.
+// @Summary Worker startup handshake
+// @Tags worker
+// @Accept json
+// @Produce json
+// @Param worker_id path string true "Worker ID"
+// @Param request body model.HandshakeRequest true "SDK, contract version and visible env/volumes"
+// @Success 200 {object} model.HandshakeResponse
+// @Router /v2/{endpoint}/handshake/{worker_id} [post]
+func (h *WorkerHandler) Handshake(c *gin.Context) {
+	if h.handshakeService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker handshake not available"})
+		return
+	}
+
+	var req model.HandshakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.handshakeService.Handshake(c.Request.Context(), c.Param("endpoint"), c.Param("worker_id"), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetEndpointWarnings lists the structured warnings recorded for an endpoint
+// GET /api/v1/endpoints/:name/warnings
+func (h *WorkerHandler) GetEndpointWarnings(c *gin.Context) {
+	if h.handshakeService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "endpoint warnings not available"})
+		return
+	}
+
+	warnings, err := h.handshakeService.Warnings(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"endpoint": c.Param("name"), "warnings": warnings})
+}
+
+// GetScheduler lists the task assignment policies of the endpoints and the comparison of
+// shadow policies with the policies they shadow
+// GET /api/v1/scheduler
+func (h *WorkerHandler) GetScheduler(c *gin.Context) {
+	policies := h.workerService.SchedulerPolicies()
+	if policies == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "scheduler policies not available"})
+		return
+	}
+	def, endpoints, shadows := policies.Assignments()
+	c.JSON(http.StatusOK, gin.H{
+		"policy":    def,
+		"endpoints": endpoints,
+		"shadow":    shadows,
+		"available": scheduler.Available(),
+		"shadowing": policies.ShadowReport(),
+	})
+}
+
+// workerCapabilities extracts announced capabilities from the capabilities query
+// parameter or the X-Worker-Capabilities header. Returns nil if none were announced.
+func workerCapabilities(c *gin.Context) []string {
+	values, ok := c.GetQueryArray("capabilities")
+	if !ok {
+		header, present := c.Request.Header["X-Worker-Capabilities"]
+		if !present {
+			return nil
+		}
+		values = header
+	}
+
+	capabilities, unknown := model.ParseWorkerCapabilities(values)
+	if len(unknown) > 0 {
+		logger.WarnCtx(c.Request.Context(), "ignoring unknown worker capabilities, worker_id: %s, capabilities: %v", c.Param("worker_id"), unknown)
+	}
+	return capabilities
+}
+
+// workerCustomMetric extracts the custom autoscaling gauge from the custom_metric query
+// parameter or the X-Worker-Custom-Metric header. Returns nil if absent or not a finite number.
+func workerCustomMetric(c *gin.Context) *float64 {
+	raw, ok := c.GetQuery("custom_metric")
+	if !ok {
+		raw = c.GetHeader("X-Worker-Custom-Metric")
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		logger.WarnCtx(c.Request.Context(), "ignoring invalid worker custom metric, worker_id: %s, value: %q", c.Param("worker_id"), raw)
+		return nil
+	}
+	return &value
+}
+
+// workerGPUHealth extracts the GPU health check result from the gpu_health query parameter
+// (ok or unhealthy) or the X-Worker-GPU-Health header. Unknown values are ignored.
+func workerGPUHealth(c *gin.Context) *model.GPUHealth {
+	raw, ok := c.GetQuery("gpu_health")
+	if !ok {
+		raw = c.GetHeader("X-Worker-GPU-Health")
+	}
+	reason, ok := c.GetQuery("gpu_health_reason")
+	if !ok {
+		reason = c.GetHeader("X-Worker-GPU-Health-Reason")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "":
+		return nil
+	case "ok", "healthy":
+		return &model.GPUHealth{Healthy: true}
+	case "unhealthy":
+		return &model.GPUHealth{Healthy: false, Reason: strings.TrimSpace(reason)}
+	default:
+		logger.WarnCtx(c.Request.Context(), "ignoring invalid worker GPU health, worker_id: %s, value: %q", c.Param("worker_id"), raw)
+		return nil
+	}
+}
+
+// workerBroadcastAcks extracts broadcast acknowledgements from the repeated ack (id) and nack
+// (id:error) query parameters. Malformed values are ignored; the broadcast is simply sent again.
+func workerBroadcastAcks(c *gin.Context) []*model.BroadcastAck {
+	var acks []*model.BroadcastAck
+	for _, raw := range c.QueryArray("ack") {
+		id, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			logger.WarnCtx(c.Request.Context(), "ignoring invalid broadcast ack, worker_id: %s, value: %q", c.Param("worker_id"), raw)
+			continue
+		}
+		acks = append(acks, &model.BroadcastAck{ID: id})
+	}
+	for _, raw := range c.QueryArray("nack") {
+		idPart, message, _ := strings.Cut(raw, ":")
+		id, err := strconv.ParseInt(strings.TrimSpace(idPart), 10, 64)
+		if err != nil {
+			logger.WarnCtx(c.Request.Context(), "ignoring invalid broadcast nack, worker_id: %s, value: %q", c.Param("worker_id"), raw)
+			continue
+		}
+		message = strings.TrimSpace(message)
+		if message == "" {
+			message = "failed"
+		}
+		acks = append(acks, &model.BroadcastAck{ID: id, Error: message})
+	}
+	return acks
 }
 
 // PullJobs pulls tasks from queue (compatible with runpod job-take interface)
@@ -104,6 +306,7 @@ func (h *WorkerHandler) Heartbeat(c *gin.Context) {
 // @Param endpoint query string false "Endpoint that worker belongs to"
 // @Param job_in_progress query string false "Whether there are tasks in progress (0 or 1)"
 // @Param batch_size query int false "Batch pull count"
+// @Param wait query int false "Seconds to wait for a task before returning 204 (long-poll)"
 // @Success 200 {array} model.JobInfo
 // @Success 204 "No tasks available"
 // @Router /job-take [get]
@@ -143,11 +346,20 @@ func (h *WorkerHandler) PullJobs(c *gin.Context) {
 		}
 	}
 
+	// Long-poll: wait=N holds the request up to N seconds (capped by worker.long_poll_max_wait) until a task arrives
+	waitSeconds := 0
+	if w := c.Query("wait"); w != "" {
+		if _, err := fmt.Sscanf(w, "%d", &waitSeconds); err != nil {
+			waitSeconds = 0
+		}
+	}
+
 	req := &model.JobPullRequest{
 		WorkerID:            workerID,
 		JobsInProgress:      jobsInProgress,
 		JobsInProgressCount: jobsInProgressCount,
 		BatchSize:           batchSize,
+		WaitSeconds:         waitSeconds,
 	}
 
 	resp, err := h.workerService.PullJobs(c.Request.Context(), req, endpoint)
@@ -326,6 +538,7 @@ func (h *WorkerHandler) GetWorkerList(c *gin.Context) {
 				workerWithPod.PodMessage = pod.Message
 				workerWithPod.PodIP = pod.IP
 				workerWithPod.PodNodeName = pod.NodeName
+				workerWithPod.GPUType = pod.GPUType
 				workerWithPod.PodCreatedAt = pod.CreatedAt
 				workerWithPod.PodStartedAt = pod.StartedAt
 				workerWithPod.PodRestartCount = pod.RestartCount
@@ -354,6 +567,7 @@ func (h *WorkerHandler) GetWorkerList(c *gin.Context) {
 			PodMessage:        pod.Message,
 			PodIP:             pod.IP,
 			PodNodeName:       pod.NodeName,
+			GPUType:           pod.GPUType,
 			PodCreatedAt:      pod.CreatedAt,
 			PodStartedAt:      pod.StartedAt,
 			PodRestartCount:   pod.RestartCount,
@@ -392,13 +606,77 @@ func (h *WorkerHandler) DescribeWorker(c *gin.Context) {
 	podDetail, err := h.deploymentProvider.DescribePod(ctx, endpoint, podName)
 	if err != nil {
 		logger.ErrorCtx(ctx, "failed to describe pod %s: %v", podName, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, podDetail)
 }
 
+// AttachDebugContainerRequest request body for attaching a debug container
+type AttachDebugContainerRequest struct {
+	Image       string `json:"image,omitempty"`           // Debug image (default: configured k8s.debug_image)
+	TTLSeconds  int    `json:"ttlSeconds,omitempty"`      // Lifetime in seconds (default 900, capped at k8s.debug_max_ttl)
+	Reason      string `json:"reason" binding:"required"` // Why the worker is being debugged (audit)
+	RequestedBy string `json:"requestedBy,omitempty"`     // Operator requesting the attach (audit)
+}
+
+// AttachDebugContainer injects an ephemeral debug container into a worker pod
+// @Summary Attach debug container
+// @Description Inject a time-limited ephemeral container with profiling tools into a worker pod. It shares the worker's process namespace; open a shell with the exec WebSocket using container=<containerName>. The attach is recorded as a WORKER_DEBUG_ATTACHED worker event.
+// @Tags worker
+// @Accept json
+// @Produce json
+// @Param name path string true "Endpoint name"
+// @Param pod_name path string true "Pod name"
+// @Param request body AttachDebugContainerRequest true "Debug container options"
+// @Success 200 {object} k8s.DebugContainerInfo
+// @Router /endpoints/:name/workers/:pod_name/debug [post]
+func (h *WorkerHandler) AttachDebugContainer(c *gin.Context) {
+	ctx := c.Request.Context()
+	endpoint := c.Param("name")
+	podName := c.Param("pod_name")
+
+	var req AttachDebugContainerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	k8sProvider, ok := endpointProvider[*k8s.K8sDeploymentProvider](ctx, h.deploymentProvider, endpoint)
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "K8s provider not available"})
+		return
+	}
+
+	info, err := k8sProvider.AttachDebugContainer(ctx, &k8s.DebugContainerRequest{
+		Endpoint: endpoint,
+		PodName:  podName,
+		Image:    req.Image,
+		TTL:      time.Duration(req.TTLSeconds) * time.Second,
+	})
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to attach debug container: endpoint=%s, pod=%s, error=%v", endpoint, podName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.InfoCtx(ctx, "[AUDIT] debug container attached: endpoint=%s, pod=%s, container=%s, image=%s, expiresAt=%s, requestedBy=%s, clientIP=%s, reason=%s",
+		endpoint, podName, info.ContainerName, info.Image, info.ExpiresAt.Format(time.RFC3339), req.RequestedBy, c.ClientIP(), req.Reason)
+	if h.workerEventService != nil {
+		h.workerEventService.RecordWorkerDebugAttached(ctx, podName, endpoint, map[string]interface{}{
+			"container":   info.ContainerName,
+			"image":       info.Image,
+			"expiresAt":   info.ExpiresAt,
+			"requestedBy": req.RequestedBy,
+			"clientIP":    c.ClientIP(),
+			"reason":      req.Reason,
+		})
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
 // GetWorkerYAML gets worker Pod YAML
 // @Summary Get worker Pod YAML
 // @Description Get worker Pod YAML (similar to kubectl get pod -o yaml)
@@ -426,7 +704,7 @@ func (h *WorkerHandler) GetWorkerYAML(c *gin.Context) {
 	yamlData, err := h.deploymentProvider.GetPodYAML(ctx, endpoint, podName)
 	if err != nil {
 		logger.ErrorCtx(ctx, "failed to get pod yaml %s: %v", podName, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondProviderError(c, err)
 		return
 	}
 
@@ -437,25 +715,28 @@ func (h *WorkerHandler) GetWorkerYAML(c *gin.Context) {
 // WorkerDetailResponse represents the response for worker detail API
 // Includes failure information for Requirements 6.1, 6.2
 type WorkerDetailResponse struct {
-	ID                   int64   `json:"id"`
-	WorkerID             string  `json:"workerId"`
-	Endpoint             string  `json:"endpoint"`
-	PodName              string  `json:"podName,omitempty"`
-	Status               string  `json:"status"`
-	Concurrency          int     `json:"concurrency"`
-	CurrentJobs          int     `json:"currentJobs"`
-	Version              string  `json:"version,omitempty"`
-	LastHeartbeat        string  `json:"lastHeartbeat"`
-	TotalTasksCompleted  int64   `json:"totalTasksCompleted"`
-	TotalTasksFailed     int64   `json:"totalTasksFailed"`
-	TotalExecutionTimeMs int64   `json:"totalExecutionTimeMs"`
-	CreatedAt            string  `json:"createdAt"`
-	UpdatedAt            string  `json:"updatedAt"`
-	TerminatedAt         *string `json:"terminatedAt,omitempty"`
-	PodStartedAt         *string `json:"podStartedAt,omitempty"`
+	ID                   int64    `json:"id"`
+	WorkerID             string   `json:"workerId"`
+	Endpoint             string   `json:"endpoint"`
+	PodName              string   `json:"podName,omitempty"`
+	Status               string   `json:"status"`
+	Concurrency          int      `json:"concurrency"`
+	CurrentJobs          int      `json:"currentJobs"`
+	Version              string   `json:"version,omitempty"`
+	LastHeartbeat        string   `json:"lastHeartbeat"`
+	Capabilities         []string `json:"capabilities,omitempty"` // Announced worker capabilities
+	TotalTasksCompleted  int64    `json:"totalTasksCompleted"`
+	TotalTasksFailed     int64    `json:"totalTasksFailed"`
+	TotalExecutionTimeMs int64    `json:"totalExecutionTimeMs"`
+	CreatedAt            string   `json:"createdAt"`
+	UpdatedAt            string   `json:"updatedAt"`
+	TerminatedAt         *string  `json:"terminatedAt,omitempty"`
+	PodStartedAt         *string  `json:"podStartedAt,omitempty"`
+
+	Quarantine *model.WorkerQuarantine `json:"quarantine,omitempty"` // Set while the worker receives no new tasks
 
 	// Failure information fields (Requirements 6.1, 6.2)
-	FailureType       string  `json:"failureType,omitempty"`       // IMAGE_PULL_FAILED, CONTAINER_CRASH, RESOURCE_LIMIT, TIMEOUT, UNKNOWN
+	FailureType       string  `json:"failureType,omitempty"`       // IMAGE_PULL_FAILED, CONTAINER_CRASH, RESOURCE_LIMIT, TIMEOUT, GPU_UNHEALTHY, UNKNOWN
 	FailureReason     string  `json:"failureReason,omitempty"`     // Sanitized user-friendly failure message
 	FailureSuggestion string  `json:"failureSuggestion,omitempty"` // Actionable suggestion for the user
 	FailureOccurredAt *string `json:"failureOccurredAt,omitempty"` // Timestamp when failure was detected
@@ -496,6 +777,8 @@ func (h *WorkerHandler) GetWorkerByID(c *gin.Context) {
 		TotalExecutionTimeMs: worker.TotalExecutionTimeMs,
 		CreatedAt:            worker.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:            worker.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Capabilities:         worker.CapabilityList(),
+		Quarantine:           service.WorkerQuarantineOf(worker),
 	}
 
 	// Add terminated timestamp if available
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"waverless/internal/service"
+	"waverless/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuarantineWorkerRequest quarantines a worker
+type QuarantineWorkerRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// respondQuarantineError maps "invalid" errors to 400, "not found" errors to 404 and
+// "already banned" errors to 409
+func respondQuarantineError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		status = http.StatusBadRequest
+	case strings.Contains(err.Error(), "not found"):
+		status = http.StatusNotFound
+	case strings.Contains(err.Error(), "already banned"):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// QuarantineWorker stops a worker from receiving new tasks; running tasks finish and the pod is
+// kept for inspection
+// POST /api/v1/workers/:id/quarantine
+func (h *WorkerHandler) QuarantineWorker(c *gin.Context) {
+	if h.quarantineService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker quarantine not available"})
+		return
+	}
+
+	var req QuarantineWorkerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workerID := c.Param("id")
+	requestedBy := c.GetHeader(RequestedByHeader)
+	quarantine, err := h.quarantineService.Quarantine(c.Request.Context(), workerID, req.Reason, requestedBy)
+	if err != nil {
+		respondQuarantineError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Worker quarantined: worker=%s, reason=%s, by=%s", workerID, quarantine.Reason, requestedBy)
+	c.JSON(http.StatusOK, gin.H{"workerId": workerID, "quarantine": quarantine})
+}
+
+// ReleaseWorker lifts the manual quarantine of a worker
+// DELETE /api/v1/workers/:id/quarantine
+func (h *WorkerHandler) ReleaseWorker(c *gin.Context) {
+	if h.quarantineService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker quarantine not available"})
+		return
+	}
+
+	workerID := c.Param("id")
+	requestedBy := c.GetHeader(RequestedByHeader)
+	if err := h.quarantineService.Release(c.Request.Context(), workerID); err != nil {
+		respondQuarantineError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Worker released from quarantine: worker=%s, by=%s", workerID, requestedBy)
+	c.JSON(http.StatusOK, gin.H{"workerId": workerID, "released": true})
+}
+
+// CreateWorkerBan bans a node or image digest from receiving new tasks and quarantines the
+// workers it matches
+// POST /api/v1/worker-bans
+func (h *WorkerHandler) CreateWorkerBan(c *gin.Context) {
+	if h.quarantineService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker quarantine not available"})
+		return
+	}
+
+	var req service.CreateWorkerBanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requestedBy := c.GetHeader(RequestedByHeader)
+	detail, err := h.quarantineService.CreateBan(c.Request.Context(), &req, requestedBy)
+	if err != nil {
+		respondQuarantineError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Worker ban created: id=%d, kind=%s, value=%s, reason=%s, quarantined=%d, by=%s",
+		detail.ID, detail.Kind, detail.Value, detail.Reason, len(detail.Workers), requestedBy)
+	c.JSON(http.StatusCreated, detail)
+}
+
+// ListWorkerBans lists the node and image bans with the workers they quarantine
+// GET /api/v1/worker-bans
+func (h *WorkerHandler) ListWorkerBans(c *gin.Context) {
+	if h.quarantineService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker quarantine not available"})
+		return
+	}
+
+	bans, err := h.quarantineService.ListBans(c.Request.Context())
+	if err != nil {
+		respondQuarantineError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bans": bans})
+}
+
+// DeleteWorkerBan lifts a ban and releases the workers it quarantined
+// DELETE /api/v1/worker-bans/:id
+func (h *WorkerHandler) DeleteWorkerBan(c *gin.Context) {
+	if h.quarantineService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "worker quarantine not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ban id"})
+		return
+	}
+	requestedBy := c.GetHeader(RequestedByHeader)
+	detail, err := h.quarantineService.DeleteBan(c.Request.Context(), id)
+	if err != nil {
+		respondQuarantineError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), "[AUDIT] Worker ban deleted: id=%d, kind=%s, value=%s, released=%d, by=%s",
+		detail.ID, detail.Kind, detail.Value, len(detail.Workers), requestedBy)
+	c.JSON(http.StatusOK, detail)
+}
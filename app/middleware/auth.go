@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
@@ -38,3 +39,27 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// StatusPageAuth requires the status page token as a bearer token or ?token= query parameter.
+// Without a configured token the status API is unauthenticated.
+func StatusPageAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := config.GlobalConfig.StatusPage.Token
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if got == "" {
+			got = c.Query("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
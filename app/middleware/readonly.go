@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+
+	"waverless/pkg/maintenance"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readOnlyRetryAfter is the Retry-After hint (seconds) sent with rejected requests
+const readOnlyRetryAfter = "60"
+
+// Routes that change state despite using GET (workers pulling tasks assign them)
+var mutatingGetRoutes = map[string]bool{
+	"/v2/:endpoint/job-take/:worker_id":       true,
+	"/v2/:endpoint/job-take-batch/:worker_id": true,
+}
+
+// Non-GET routes that stay available in read-only mode
+var readOnlyExemptRoutes = map[string]bool{
+	"/api/v1/admin/read-only":               true, // Turning read-only mode off
+	"/api/v1/admin/emergency-brake":         true, // Incidents don't wait for maintenance to end
+	"/api/v1/admin/emergency-brake/release": true,
+	"/api/v1/endpoints/preview":             true, // Renders YAML only
+	"/api/v1/endpoints/:name/invoke-token":  true, // Signs a token, stores nothing
+}
+
+// ReadOnly rejects mutating requests with 503 while the maintenance switch is on.
+// Reads, metrics and task status stay available.
+func ReadOnly(sw *maintenance.Switch) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		readOnly, reason := sw.ReadOnly()
+		if !readOnly || !isMutatingRequest(c) || readOnlyExemptRoutes[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		if reason == "" {
+			reason = "maintenance in progress"
+		}
+		c.Header("Retry-After", readOnlyRetryAfter)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":    "service is in read-only mode",
+			"reason":   reason,
+			"readOnly": true,
+		})
+	}
+}
+
+// isMutatingRequest reports whether the request may change state
+func isMutatingRequest(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return mutatingGetRoutes[c.FullPath()]
+	default:
+		return true
+	}
+}
@@ -3,65 +3,95 @@ package router
 import (
 	"waverless/app/handler"
 	"waverless/app/middleware"
+	"waverless/pkg/maintenance"
+	"waverless/pkg/metrics"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Handlers are the HTTP handlers the router serves. A nil handler leaves its routes out
+// where the route group checks for it.
+type Handlers struct {
+	TaskHandler        *handler.TaskHandler
+	WorkerHandler      *handler.WorkerHandler
+	EndpointHandler    *handler.EndpointHandler
+	AutoscalerHandler  *handler.AutoScalerHandler
+	StatisticsHandler  *handler.StatisticsHandler
+	SpecHandler        *handler.SpecHandler
+	ImageHandler       *handler.ImageHandler
+	MonitoringHandler  *handler.MonitoringHandler
+	GPUUsageHandler    *handler.GPUUsageHandler
+	MirrorHandler      *handler.RegistryMirrorHandler
+	LogHandler         *handler.LogHandler
+	DRHandler          *handler.DisasterRecoveryHandler
+	MaintHandler       *handler.MaintenanceHandler
+	GroupHandler       *handler.EndpointGroupHandler
+	ReservationHandler *handler.GPUReservationHandler
+	FederationHandler  *handler.FederationHandler
+	SamplingHandler    *handler.SamplingHandler
+	TransformHandler   *handler.TransformHandler
+	RedactionHandler   *handler.LogRedactionHandler
+	EncryptionHandler  *handler.EncryptionHandler
+	DeletionHandler    *handler.DataDeletionHandler
+	ReplayHandler      *handler.TaskReplayHandler
+	StatusPageHandler  *handler.StatusPageHandler
+	HedgingHandler     *handler.HedgingHandler
+	CircuitHandler     *handler.CircuitBreakerHandler
+	ChangeHandler      *handler.ChangeRequestHandler
+	IntegrationHandler *handler.IntegrationHandler
+	NovitaHandler      *handler.NovitaHandler
+	GPUTierHandler     *handler.GPUTierHandler
+	BatchJobHandler    *handler.BatchJobHandler
+	ScheduleHandler    *handler.JobScheduleHandler
+	OperationHandler   *handler.OperationHandler
+	LoadTestHandler    *handler.LoadTestHandler
+}
+
 // Router Router
 type Router struct {
-	taskHandler       *handler.TaskHandler
-	workerHandler     *handler.WorkerHandler
-	endpointHandler   *handler.EndpointHandler
-	autoscalerHandler *handler.AutoScalerHandler
-	statisticsHandler *handler.StatisticsHandler
-	specHandler       *handler.SpecHandler
-	imageHandler      *handler.ImageHandler
-	monitoringHandler *handler.MonitoringHandler
+	Handlers
+	readOnly *maintenance.Switch
 }
 
 // NewRouter creates a new Router
-func NewRouter(taskHandler *handler.TaskHandler, workerHandler *handler.WorkerHandler, endpointHandler *handler.EndpointHandler, autoscalerHandler *handler.AutoScalerHandler, statisticsHandler *handler.StatisticsHandler, specHandler *handler.SpecHandler, imageHandler *handler.ImageHandler, monitoringHandler *handler.MonitoringHandler) *Router {
-	return &Router{
-		taskHandler:       taskHandler,
-		workerHandler:     workerHandler,
-		endpointHandler:   endpointHandler,
-		autoscalerHandler: autoscalerHandler,
-		statisticsHandler: statisticsHandler,
-		specHandler:       specHandler,
-		imageHandler:      imageHandler,
-		monitoringHandler: monitoringHandler,
-	}
+func NewRouter(handlers Handlers, readOnly *maintenance.Switch) *Router {
+	return &Router{Handlers: handlers, readOnly: readOnly}
 }
 
 // Setup sets up routes
 func (r *Router) Setup(engine *gin.Engine) {
 	engine.Use(middleware.Recovery())
 	engine.Use(middleware.Logger())
+	if r.readOnly != nil {
+		engine.Use(middleware.ReadOnly(r.readOnly)) // Reject mutating requests during maintenance
+	}
 	// V1 API - Client task management interface
 	v1 := engine.Group("/v1")
 	{
 		// Global task query interface (no endpoint required)
-		v1.GET("/status/:task_id", r.taskHandler.Status)
-		v1.POST("/cancel/:task_id", r.taskHandler.Cancel)
-		v1.GET("/tasks", r.taskHandler.ListTasks) // List tasks with optional filtering
+		v1.GET("/status/:task_id", r.TaskHandler.Status)
+		v1.POST("/cancel/:task_id", r.TaskHandler.Cancel)
+		v1.GET("/tasks", r.TaskHandler.ListTasks) // List tasks with optional filtering
 
 		// Worker management interface
-		v1.GET("/workers", r.workerHandler.GetWorkerList)
+		v1.GET("/workers", r.WorkerHandler.GetWorkerList)
 
 		// Endpoint-specific routes (endpoint required)
 		endpoint := v1.Group("/:endpoint")
 		{
-			endpoint.POST("/run", r.taskHandler.SubmitWithEndpoint)
-			endpoint.POST("/runsync", r.taskHandler.SubmitSyncWithEndpoint)
-			endpoint.GET("/status/:task_id", r.taskHandler.Status)       // Reuse existing
-			endpoint.POST("/cancel/:task_id", r.taskHandler.Cancel)      // Reuse existing
-			endpoint.GET("/stats", r.taskHandler.GetEndpointStats)       // endpoint statistics
-			endpoint.GET("/check", r.taskHandler.CheckSubmitEligibility) // check if task submission is recommended
+			endpoint.POST("/run", r.TaskHandler.SubmitWithEndpoint)
+			endpoint.POST("/runsync", r.TaskHandler.SubmitSyncWithEndpoint)
+			endpoint.GET("/status/:task_id", r.TaskHandler.Status)       // Reuse existing
+			endpoint.POST("/cancel/:task_id", r.TaskHandler.Cancel)      // Reuse existing
+			endpoint.GET("/stats", r.TaskHandler.GetEndpointStats)       // endpoint statistics
+			endpoint.GET("/check", r.TaskHandler.CheckSubmitEligibility) // check if task submission is recommended
 
 			// Monitoring APIs
-			if r.monitoringHandler != nil {
-				endpoint.GET("/metrics/realtime", r.monitoringHandler.GetRealtimeMetrics)
-				endpoint.GET("/metrics/stats", r.monitoringHandler.GetStats)
+			if r.MonitoringHandler != nil {
+				endpoint.GET("/metrics/realtime", r.MonitoringHandler.GetRealtimeMetrics)
+				endpoint.GET("/metrics/stats", r.MonitoringHandler.GetStats)
+				endpoint.GET("/metrics/cold-starts", r.MonitoringHandler.GetColdStartStats)
+				endpoint.GET("/metrics/anomalies", r.MonitoringHandler.GetAnomalies)
 			}
 		}
 	}
@@ -71,133 +101,520 @@ func (r *Router) Setup(engine *gin.Engine) {
 	v2.Use(middleware.AuthMiddleware()) // Add simple token authentication
 	{
 		// Task pulling
-		v2.GET("/job-take/:worker_id", r.workerHandler.PullJobs)
-		v2.GET("/job-take-batch/:worker_id", r.workerHandler.PullJobs) // Batch pull
+		v2.GET("/job-take/:worker_id", r.WorkerHandler.PullJobs)
+		v2.GET("/job-take-batch/:worker_id", r.WorkerHandler.PullJobs) // Batch pull
+
+		// Startup handshake (contract version and env/volume layout)
+		v2.POST("/handshake/:worker_id", r.WorkerHandler.Handshake)
 
 		// Heartbeat
-		v2.GET("/ping/:worker_id", r.workerHandler.Heartbeat)
+		v2.GET("/ping/:worker_id", r.WorkerHandler.Heartbeat)
 
 		// Result submission (task_id in URL path)
-		v2.POST("/job-done/:worker_id/:task_id", r.workerHandler.SubmitResult)
-		v2.POST("/job-stream/:worker_id/:task_id", r.workerHandler.SubmitResult)
+		v2.POST("/job-done/:worker_id/:task_id", r.WorkerHandler.SubmitResult)
+		v2.POST("/job-stream/:worker_id/:task_id", r.WorkerHandler.SubmitResult)
+
+		// RunPod client API paths (only for endpoints with runpodCompat enabled)
+		v2.POST("/run", r.TaskHandler.RequireRunPodCompat, r.TaskHandler.SubmitWithEndpoint)
+		v2.POST("/runsync", r.TaskHandler.RequireRunPodCompat, r.TaskHandler.SubmitSyncWithEndpoint)
+		v2.GET("/status/:task_id", r.TaskHandler.RequireRunPodCompat, r.TaskHandler.Status)
+		v2.POST("/cancel/:task_id", r.TaskHandler.RequireRunPodCompat, r.TaskHandler.Cancel)
+	}
+
+	// Status page API for external status pages (token optional, only exposed endpoints)
+	if r.StatusPageHandler != nil {
+		statusPage := engine.Group("/status/v1")
+		statusPage.Use(middleware.StatusPageAuth())
+		{
+			statusPage.GET("/projects/:project", r.StatusPageHandler.GetProjectStatus)                      // Health, queue saturation and 24h success rate per endpoint
+			statusPage.GET("/projects/:project/endpoints/:endpoint", r.StatusPageHandler.GetEndpointStatus) // Single endpoint
+		}
+	}
+
+	// Maintenance APIs (available regardless of deployment provider)
+	if r.MaintHandler != nil {
+		admin := engine.Group("/api/v1/admin")
+		{
+			admin.GET("/read-only", r.MaintHandler.GetReadOnly) // Read-only status
+			admin.PUT("/read-only", r.MaintHandler.SetReadOnly) // Toggle read-only mode on all replicas
+
+			admin.GET("/emergency-brake", r.MaintHandler.GetEmergencyBrake)              // Emergency brake status
+			admin.POST("/emergency-brake", r.MaintHandler.EngageEmergencyBrake)          // Freeze autoscaling and rollouts
+			admin.POST("/emergency-brake/release", r.MaintHandler.ReleaseEmergencyBrake) // Lift the brake
+
+			admin.GET("/worker-integrity", r.MaintHandler.CheckWorkerIntegrity)                // Worker rows without pods, pods without rows
+			admin.POST("/worker-integrity/reconcile", r.MaintHandler.ReconcileWorkerIntegrity) // Close out worker rows whose pod is gone
+			admin.GET("/worker-integrity/stats", r.MaintHandler.GetWorkerIntegrityStats)       // Counters for alerting
+		}
 	}
 
 	// API v1 - Endpoint management interface (K8s or Novita, if enabled)
-	if r.endpointHandler != nil {
+	if r.EndpointHandler != nil {
 		api := engine.Group("/api/v1")
 		{
 			// Worker detail API (by database ID, regardless of status)
-			api.GET("/workers/:id", r.workerHandler.GetWorkerByID)
+			api.GET("/workers/:id", r.WorkerHandler.GetWorkerByID)
+
+			// Worker quarantine and node/image bans (incident triage)
+			api.POST("/workers/:id/quarantine", r.WorkerHandler.QuarantineWorker) // Stop new tasks, keep the pod
+			api.DELETE("/workers/:id/quarantine", r.WorkerHandler.ReleaseWorker)  // Lift a manual quarantine
+			api.GET("/worker-bans", r.WorkerHandler.ListWorkerBans)               // Bans with the workers they quarantine
+			api.POST("/worker-bans", r.WorkerHandler.CreateWorkerBan)             // Ban a node or image digest
+			api.DELETE("/worker-bans/:id", r.WorkerHandler.DeleteWorkerBan)       // Lift a ban and release its workers
+
+			// Task assignment policies and shadow policy comparison
+			api.GET("/scheduler", r.WorkerHandler.GetScheduler)
+
+			// Model registry versions endpoints can be deployed from by modelName
+			api.GET("/models/:model/versions/:version", r.EndpointHandler.GetModelVersion)
 
 			// Endpoint lifecycle management
 			endpoints := api.Group("/endpoints")
 			{
-				endpoints.POST("", r.endpointHandler.CreateEndpoint)                               // Create endpoint (metadata + deployment)
-				endpoints.POST("/preview", r.endpointHandler.PreviewDeploymentYAML)                // Preview YAML
-				endpoints.GET("", r.endpointHandler.ListEndpoints)                                 // List endpoints
-				endpoints.GET("/:name", r.endpointHandler.GetEndpoint)                             // Get endpoint detail
-				endpoints.PUT("/:name", r.endpointHandler.UpdateEndpoint)                          // Update metadata
-				endpoints.PATCH("/:name/deployment", r.endpointHandler.UpdateEndpointDeployment)   // Update deployment
-				endpoints.DELETE("/:name", r.endpointHandler.DeleteEndpoint)                       // Delete endpoint
-				endpoints.GET("/:name/logs", r.endpointHandler.GetEndpointLogs)                    // Logs
-				endpoints.GET("/:name/workers", r.endpointHandler.GetEndpointWorkers)              // Workers
-				endpoints.GET("/:name/workers/sync", r.endpointHandler.GetEndpointWorkersForSync)  // Workers for Portal sync (includes recently terminated)
-				endpoints.GET("/:name/workers/:pod_name/describe", r.workerHandler.DescribeWorker) // Describe Worker (Pod detail)
-				endpoints.GET("/:name/workers/:pod_name/yaml", r.workerHandler.GetWorkerYAML)      // Get Worker Pod YAML
-				endpoints.GET("/:name/workers/exec", r.endpointHandler.ExecWorker)                 // Worker Exec (WebSocket)
+				endpoints.POST("", r.EndpointHandler.CreateEndpoint)                             // Create endpoint (metadata + deployment)
+				endpoints.POST("/preview", r.EndpointHandler.PreviewDeploymentYAML)              // Preview YAML
+				endpoints.GET("", r.EndpointHandler.ListEndpoints)                               // List endpoints
+				endpoints.GET("/:name", r.EndpointHandler.GetEndpoint)                           // Get endpoint detail
+				endpoints.PUT("/:name", r.EndpointHandler.UpdateEndpoint)                        // Update metadata
+				endpoints.PATCH("/:name/deployment", r.EndpointHandler.UpdateEndpointDeployment) // Update deployment
+				endpoints.GET("/:name/env", r.EndpointHandler.GetEndpointEnv)                    // Env with version (secrets by name only)
+				endpoints.PATCH("/:name/env", r.EndpointHandler.PatchEndpointEnv)                // Set/unset individual env vars (409 on version conflict)
+				endpoints.GET("/:name/env/history", r.EndpointHandler.ListEndpointEnvChanges)    // Env change history
+				endpoints.GET("/:name/hooks", r.EndpointHandler.GetEndpointHooks)                // Lifecycle hooks in execution order
+				endpoints.PUT("/:name/hooks", r.EndpointHandler.SaveEndpointHooks)               // Replace lifecycle hooks
+				endpoints.DELETE("/:name/hooks", r.EndpointHandler.DeleteEndpointHooks)          // Remove lifecycle hooks (runs are kept)
+				endpoints.GET("/:name/hooks/runs", r.EndpointHandler.GetEndpointHookRuns)        // Recent hook runs, newest first
+				endpoints.POST("/:name/pause", r.EndpointHandler.PauseEndpointDispatch)          // Pause task dispatch (replicas kept; queue or reject submissions)
+				endpoints.POST("/:name/resume", r.EndpointHandler.ResumeEndpointDispatch)        // Resume task dispatch
+				endpoints.POST("/:name/diff", r.EndpointHandler.DiffEndpoint)                    // Diff live state against a proposed deploy request
+				endpoints.POST("/:name/model", r.EndpointHandler.DeployEndpointModel)            // Move to another model registry version (default: latest approved)
+				endpoints.GET("/:name/resource", r.EndpointHandler.GetEndpointResource)          // Infrastructure-as-code representation (Terraform provider state)
+				endpoints.GET("/:name/snapshot", r.EndpointHandler.GetEndpointSnapshot)          // Full state in one document for incident tickets
+				endpoints.GET("/export/terraform", r.EndpointHandler.ExportTerraform)            // Export endpoints as Terraform config with import blocks
+				endpoints.DELETE("/:name", r.EndpointHandler.DeleteEndpoint)                     // Delete endpoint
+				endpoints.GET("/:name/logs", r.EndpointHandler.GetEndpointLogs)                  // Logs
+				if r.LogHandler != nil {
+					endpoints.GET("/:name/logs/history", r.LogHandler.QueryEndpointLogs) // Shipped logs (survive pod deletion)
+				}
+				if r.CircuitHandler != nil {
+					endpoints.POST("/:name/circuit/close", r.CircuitHandler.CloseEndpointCircuit) // Close the circuit breaker without waiting for a probe
+				}
+				endpoints.GET("/:name/workers", r.EndpointHandler.GetEndpointWorkers)                  // Workers
+				endpoints.GET("/:name/workers/sync", r.EndpointHandler.GetEndpointWorkersForSync)      // Workers for Portal sync (includes recently terminated)
+				endpoints.GET("/:name/workers/:pod_name/describe", r.WorkerHandler.DescribeWorker)     // Describe Worker (Pod detail)
+				endpoints.GET("/:name/warnings", r.WorkerHandler.GetEndpointWarnings)                  // Structured warnings (e.g. worker handshake mismatches)
+				endpoints.GET("/:name/workers/:pod_name/yaml", r.WorkerHandler.GetWorkerYAML)          // Get Worker Pod YAML
+				endpoints.GET("/:name/workers/exec", r.EndpointHandler.ExecWorker)                     // Worker Exec (WebSocket)
+				endpoints.POST("/:name/invoke-token", r.EndpointHandler.IssueInvokeToken)              // Signed token for direct worker requests
+				endpoints.POST("/:name/workers/:pod_name/debug", r.WorkerHandler.AttachDebugContainer) // Attach ephemeral debug container
+
+				// Control messages to the workers of an endpoint, delivered through the heartbeat
+				endpoints.POST("/:name/broadcasts", r.WorkerHandler.CreateBroadcast)     // Send a broadcast (e.g. flush result cache)
+				endpoints.GET("/:name/broadcasts", r.WorkerHandler.ListBroadcasts)       // Recent broadcasts with acknowledgement counts
+				endpoints.GET("/:name/broadcasts/:id", r.WorkerHandler.GetBroadcast)     // Per-worker acknowledgements
+				endpoints.POST("/:name/worker-config", r.WorkerHandler.PushWorkerConfig) // Switch worker log level / feature flags at runtime
+
+				// Input/output sampling rule
+				if r.SamplingHandler != nil {
+					endpoints.GET("/:name/sampling", r.SamplingHandler.GetRule)       // Get sampling rule
+					endpoints.PUT("/:name/sampling", r.SamplingHandler.UpsertRule)    // Create or update sampling rule
+					endpoints.DELETE("/:name/sampling", r.SamplingHandler.DeleteRule) // Stop sampling
+				}
+
+				// GPU tiers of heterogeneous endpoints (route tasks to cheaper specs by rule)
+				if r.GPUTierHandler != nil {
+					endpoints.GET("/:name/gpu-tiers", r.GPUTierHandler.GetTiers)       // Get tiers in routing order
+					endpoints.PUT("/:name/gpu-tiers", r.GPUTierHandler.SaveTiers)      // Replace tiers
+					endpoints.DELETE("/:name/gpu-tiers", r.GPUTierHandler.DeleteTiers) // Remove tiers (tier endpoints are kept)
+				}
+
+				// Versioned input/output transforms
+				if r.TransformHandler != nil {
+					endpoints.GET("/:name/transforms", r.TransformHandler.GetTransform)          // Get current transform
+					endpoints.PUT("/:name/transforms", r.TransformHandler.SaveTransform)         // Save as new version
+					endpoints.GET("/:name/transforms/versions", r.TransformHandler.ListVersions) // List versions
+					endpoints.POST("/:name/transforms/rollback", r.TransformHandler.Rollback)    // Restore an earlier version
+				}
+
+				// Versioned log redaction rules (applied to log APIs and shipping)
+				if r.RedactionHandler != nil {
+					endpoints.GET("/:name/log-redaction", r.RedactionHandler.GetRules)              // Get current rules
+					endpoints.PUT("/:name/log-redaction", r.RedactionHandler.SaveRules)             // Save as new version (audited)
+					endpoints.GET("/:name/log-redaction/versions", r.RedactionHandler.ListVersions) // Rule change history
+				}
+
+				// Task payload encryption at rest
+				if r.EncryptionHandler != nil {
+					endpoints.GET("/:name/encryption", r.EncryptionHandler.GetEndpointStatus) // Encryption status and project
+				}
 
 				// Image update check
-				if r.imageHandler != nil {
-					endpoints.POST("/:name/check-image", r.imageHandler.CheckImageUpdate) // Check image update for specific endpoint
-					endpoints.POST("/check-images", r.imageHandler.CheckAllImagesUpdate)  // Check image updates for all endpoints
+				if r.ImageHandler != nil {
+					endpoints.POST("/:name/check-image", r.ImageHandler.CheckImageUpdate)               // Check image update for specific endpoint
+					endpoints.POST("/check-images", r.ImageHandler.CheckAllImagesUpdate)                // Check image updates for all endpoints
+					endpoints.GET("/:name/image-validations", r.ImageHandler.GetImageValidationHistory) // Image validation history
+					endpoints.GET("/:name/image-copies", r.ImageHandler.GetImageCopyHistory)            // Internal registry copy history (provenance)
+				}
+			}
+
+			// Endpoint resource profiles (presets of autoscaler, queue and dispatch settings)
+			api.GET("/endpoint-profiles", r.EndpointHandler.ListProfiles)
+
+			// Tunable per-endpoint settings with types, defaults and valid ranges (for forms and client-side validation)
+			api.GET("/endpoint-settings", r.EndpointHandler.ListSettings)
+
+			// Endpoint group APIs (shared replica / GPU budget)
+			if r.GroupHandler != nil {
+				groups := api.Group("/endpoint-groups")
+				{
+					groups.GET("", r.GroupHandler.ListGroups)                              // List groups
+					groups.GET("/:name", r.GroupHandler.GetGroup)                          // Get group
+					groups.PUT("/:name", r.GroupHandler.UpsertGroup)                       // Create or update group budget
+					groups.DELETE("/:name", r.GroupHandler.DeleteGroup)                    // Delete group (members are detached)
+					groups.GET("/:name/status", r.GroupHandler.GetGroupStatus)             // Aggregate replicas, GPUs and queue depth
+					groups.GET("/:name/usage", r.GroupHandler.GetGroupUsage)               // Aggregate GPU usage
+					groups.PUT("/:name/members/:endpoint", r.GroupHandler.AddMember)       // Add endpoint to group
+					groups.DELETE("/:name/members/:endpoint", r.GroupHandler.RemoveMember) // Remove endpoint from group
+				}
+			}
+
+			// GPU reservations (capacity held for scheduled launches)
+			if r.ReservationHandler != nil {
+				reservations := api.Group("/gpu-reservations")
+				{
+					reservations.GET("", r.ReservationHandler.ListReservations)           // List reservations in a time range
+					reservations.GET("/calendar", r.ReservationHandler.GetCalendar)       // Reservations grouped by day
+					reservations.GET("/:name", r.ReservationHandler.GetReservation)       // Get reservation
+					reservations.POST("", r.ReservationHandler.CreateReservation)         // Reserve capacity
+					reservations.DELETE("/:name", r.ReservationHandler.CancelReservation) // Cancel reservation
+				}
+			}
+
+			// One-off batch jobs (fine-tuning, evaluation runs outside endpoints)
+			if r.BatchJobHandler != nil {
+				batchJobs := api.Group("/jobs")
+				{
+					batchJobs.POST("", r.BatchJobHandler.SubmitJob)              // Start a job
+					batchJobs.GET("", r.BatchJobHandler.ListJobs)                // List jobs
+					batchJobs.GET("/:name", r.BatchJobHandler.GetJob)            // Get job with live state
+					batchJobs.POST("/:name/cancel", r.BatchJobHandler.CancelJob) // Stop a pending or running job
+					batchJobs.GET("/:name/logs", r.BatchJobHandler.GetJobLogs)   // Logs as text (follow=true streams)
+				}
+			}
+
+			// Recurring job schedules (cron, concurrency policy, retries, run history)
+			if r.ScheduleHandler != nil {
+				schedules := api.Group("/job-schedules")
+				{
+					schedules.GET("", r.ScheduleHandler.ListSchedules)                  // List schedules
+					schedules.GET("/:name", r.ScheduleHandler.GetSchedule)              // Get schedule with next run
+					schedules.PUT("/:name", r.ScheduleHandler.SaveSchedule)             // Create or replace schedule
+					schedules.DELETE("/:name", r.ScheduleHandler.DeleteSchedule)        // Delete schedule (runs are kept)
+					schedules.POST("/:name/suspend", r.ScheduleHandler.SuspendSchedule) // Stop starting runs
+					schedules.POST("/:name/resume", r.ScheduleHandler.ResumeSchedule)   // Start runs again
+					schedules.POST("/:name/run", r.ScheduleHandler.TriggerSchedule)     // Start a run now
+					schedules.GET("/:name/runs", r.ScheduleHandler.ListRuns)            // Run history
+				}
+			}
+
+			// Long-running operations (async deploys, rollouts, backfills, replay jobs and batch jobs)
+			if r.OperationHandler != nil {
+				operations := api.Group("/operations")
+				{
+					operations.GET("", r.OperationHandler.ListOperations)              // Recent operations
+					operations.GET("/:id", r.OperationHandler.GetOperation)            // Status, progress, error and result
+					operations.POST("/:id/cancel", r.OperationHandler.CancelOperation) // Cancel a pending/running operation
+				}
+			}
+
+			// Load tests of staging endpoints (RPS profile, reports, regression comparison)
+			if r.LoadTestHandler != nil {
+				loadTests := api.Group("/load-tests")
+				{
+					loadTests.GET("", r.LoadTestHandler.ListLoadTests)                           // List load tests
+					loadTests.GET("/:name", r.LoadTestHandler.GetLoadTest)                       // Get load test with next run
+					loadTests.PUT("/:name", r.LoadTestHandler.SaveLoadTest)                      // Create or replace load test
+					loadTests.DELETE("/:name", r.LoadTestHandler.DeleteLoadTest)                 // Delete load test (reports are kept)
+					loadTests.POST("/:name/run", r.LoadTestHandler.RunLoadTest)                  // Start a run now (async operation)
+					loadTests.GET("/:name/reports", r.LoadTestHandler.ListReports)               // Report history
+					loadTests.GET("/:name/reports/:id", r.LoadTestHandler.GetReport)             // Curves and autoscaler behavior
+					loadTests.GET("/:name/reports/:id/compare", r.LoadTestHandler.CompareReport) // Compare with a baseline report
+				}
+			}
+
+			// Per-project data keys of task payload encryption
+			if r.EncryptionHandler != nil {
+				encryption := api.Group("/encryption")
+				{
+					encryption.GET("/projects/:project/keys", r.EncryptionHandler.ListKeys)     // List data key versions
+					encryption.POST("/projects/:project/rotate", r.EncryptionHandler.RotateKey) // Rotate data key (audited)
+				}
+			}
+
+			// Data deletion by subject key (purge or anonymize task data and samples)
+			if r.DeletionHandler != nil {
+				deletions := api.Group("/data-deletions")
+				{
+					deletions.POST("", r.DeletionHandler.CreateDeletion) // Erase a subject's data (audited)
+					deletions.GET("", r.DeletionHandler.ListDeletions)   // List requests with reports
+					deletions.GET("/:id", r.DeletionHandler.GetDeletion) // Get request report
+				}
+			}
+
+			// Change requests held for a second approver (protected endpoints)
+			if r.ChangeHandler != nil {
+				changes := api.Group("/change-requests")
+				{
+					changes.GET("", r.ChangeHandler.ListChangeRequests)                // List change requests
+					changes.GET("/:id", r.ChangeHandler.GetChangeRequest)              // Get change request
+					changes.POST("/:id/approve", r.ChangeHandler.ApproveChangeRequest) // Approve and apply
+					changes.POST("/:id/reject", r.ChangeHandler.RejectChangeRequest)   // Reject or withdraw
+				}
+			}
+
+			// External webhooks/integrations registry
+			if r.IntegrationHandler != nil {
+				integrations := api.Group("/integrations")
+				{
+					integrations.GET("", r.IntegrationHandler.List)             // List integrations with delivery state
+					integrations.GET("/:name", r.IntegrationHandler.Get)        // Get integration
+					integrations.PUT("/:name", r.IntegrationHandler.Upsert)     // Create or update integration
+					integrations.DELETE("/:name", r.IntegrationHandler.Delete)  // Delete integration
+					integrations.POST("/:name/test", r.IntegrationHandler.Test) // Send a test event
+				}
+			}
+
+			// Sampling overview (all rules and capture counters)
+			if r.SamplingHandler != nil {
+				api.GET("/sampling", r.SamplingHandler.ListRules)
+			}
+
+			// Federated endpoint APIs (logical endpoints backed by several regions)
+			if r.FederationHandler != nil {
+				federations := api.Group("/federations")
+				{
+					federations.GET("", r.FederationHandler.ListFederations)                   // List federated endpoints
+					federations.GET("/:name", r.FederationHandler.GetFederation)               // Get federated endpoint with members
+					federations.PUT("/:name", r.FederationHandler.SaveFederation)              // Create or update (replaces members)
+					federations.DELETE("/:name", r.FederationHandler.DeleteFederation)         // Delete (backing endpoints are kept)
+					federations.PUT("/:name/weights", r.FederationHandler.UpdateRegionWeights) // Per-region traffic weights
+					federations.GET("/:name/status", r.FederationHandler.GetFederationStatus)  // Members in routing order
+					federations.GET("/:name/usage", r.FederationHandler.GetFederationUsage)    // GPU usage per region
+				}
+			}
+
+			// Image APIs
+			if r.ImageHandler != nil {
+				images := api.Group("/images")
+				{
+					images.GET("/validate", r.ImageHandler.ValidateImage)        // Validate image (format, existence, platform, size, digest)
+					images.GET("/provenance", r.ImageHandler.GetImageProvenance) // Upstream origin of an internal image copy
 				}
 			}
 
 			// Task history APIs
 			tasks := api.Group("/tasks")
 			{
-				tasks.GET("/:task_id/execution-history", r.taskHandler.GetTaskExecutionHistory) // Get execution history (extend field)
-				tasks.GET("/:task_id/events", r.taskHandler.GetTaskEvents)                      // Get all events
-				tasks.GET("/:task_id/timeline", r.taskHandler.GetTaskTimeline)                  // Get timeline
+				tasks.GET("/:task_id/execution-history", r.TaskHandler.GetTaskExecutionHistory) // Get execution history (extend field)
+				tasks.GET("/:task_id/events", r.TaskHandler.GetTaskEvents)                      // Get all events
+				tasks.GET("/:task_id/timeline", r.TaskHandler.GetTaskTimeline)                  // Get timeline
+				if r.LogHandler != nil {
+					tasks.GET("/:task_id/logs", r.LogHandler.GetTaskLogs) // Log slice of the task (shipped logs)
+				}
+				if r.ReplayHandler != nil {
+					tasks.POST("/:task_id/replay", r.ReplayHandler.ReplayTask) // Submit the task's input again (marked as a replay)
+				}
+			}
+
+			// Task replay jobs (time window replayed at a fixed rate)
+			if r.ReplayHandler != nil {
+				replays := api.Group("/replays")
+				{
+					replays.POST("", r.ReplayHandler.CreateReplayJob)            // Replay a time window into a target endpoint
+					replays.GET("", r.ReplayHandler.ListReplayJobs)              // Recent replay jobs
+					replays.GET("/:id", r.ReplayHandler.GetReplayJob)            // Progress and replayed task statuses
+					replays.POST("/:id/cancel", r.ReplayHandler.CancelReplayJob) // Stop submitting
+				}
 			}
 
 			// Spec management APIs (CRUD, from database)
-			if r.specHandler != nil {
+			if r.SpecHandler != nil {
 				specs := api.Group("/specs")
 				{
-					specs.GET("/capacity", r.specHandler.ListSpecsWithCapacity) // List specs with capacity (must be before /:name)
-					specs.POST("", r.specHandler.CreateSpec)                    // Create spec
-					specs.GET("", r.specHandler.ListSpecs)                      // List specs
-					specs.GET("/:name", r.specHandler.GetSpec)                  // Get spec
-					specs.GET("/:name/capacity", r.specHandler.GetSpecCapacity) // Get spec capacity
-					specs.PUT("/:name", r.specHandler.UpdateSpec)               // Update spec
-					specs.DELETE("/:name", r.specHandler.DeleteSpec)            // Delete spec
+					specs.GET("/capacity", r.SpecHandler.ListSpecsWithCapacity) // List specs with capacity (must be before /:name)
+					specs.POST("", r.SpecHandler.CreateSpec)                    // Create spec
+					specs.GET("", r.SpecHandler.ListSpecs)                      // List specs
+					specs.GET("/:name", r.SpecHandler.GetSpec)                  // Get spec
+					specs.GET("/:name/capacity", r.SpecHandler.GetSpecCapacity) // Get spec capacity
+					specs.PUT("/:name", r.SpecHandler.UpdateSpec)               // Update spec
+					specs.DELETE("/:name", r.SpecHandler.DeleteSpec)            // Delete spec
 				}
 			}
 
 			// K8s resources APIs
 			k8s := api.Group("/k8s")
 			{
-				k8s.GET("/pvcs", r.endpointHandler.ListPVCs) // List PVCs
+				k8s.GET("/pvcs", r.EndpointHandler.ListPVCs)                    // List PVCs
+				k8s.GET("/informers", r.EndpointHandler.GetInformerStats)       // Informer cache size and queue depth
+				k8s.GET("/shared-volumes", r.EndpointHandler.ListSharedStorage) // Shared volumes and their consumers
+			}
+
+			// Novita resources APIs (registry auth lifecycle)
+			if r.NovitaHandler != nil {
+				novita := api.Group("/novita")
+				{
+					novita.GET("/registry-auths", r.NovitaHandler.ListRegistryAuths)               // List auths and the endpoints using them
+					novita.POST("/registry-auths/rotate", r.NovitaHandler.RotateRegistryAuth)      // Rotate a registry credential
+					novita.POST("/registry-auths/gc", r.NovitaHandler.GarbageCollectRegistryAuths) // Delete unreferenced auths
+					novita.GET("/rate-limit", r.NovitaHandler.GetRateLimit)                        // API rate limiter queue and wait metrics
+				}
+			}
+
+			// Registry mirror APIs (applied to images at render time)
+			if r.MirrorHandler != nil {
+				mirrors := api.Group("/registry-mirrors")
+				{
+					mirrors.GET("", r.MirrorHandler.ListMirrors)               // List mappings
+					mirrors.PUT("", r.MirrorHandler.UpsertMirror)              // Create or update mapping
+					mirrors.GET("/resolve", r.MirrorHandler.ResolveImage)      // Preview rewritten image
+					mirrors.DELETE("/:upstream", r.MirrorHandler.DeleteMirror) // Delete mapping
+				}
+			}
+
+			// Admin APIs
+			if r.DRHandler != nil {
+				admin := api.Group("/admin")
+				{
+					admin.GET("/dr/export", r.DRHandler.Export)                  // Export control-plane state archive
+					admin.POST("/dr/import", r.DRHandler.Import)                 // Import archive (?dryRun=true for diff only)
+					admin.GET("/control-plane/bundle", r.DRHandler.ExportBundle) // Effective config, specs and templates (?format=json|values|configmap)
+				}
 			}
 
 			// Configuration APIs
 			config := api.Group("/config")
 			{
-				config.GET("/default-env", r.endpointHandler.GetDefaultEnv) // Get default environment variables from ConfigMap
+				config.GET("/default-env", r.EndpointHandler.GetDefaultEnv) // Get default environment variables from ConfigMap
 			}
 
 			// Webhook APIs
-			if r.imageHandler != nil {
+			if r.ImageHandler != nil {
 				webhooks := api.Group("/webhooks")
 				{
-					webhooks.POST("/dockerhub", r.imageHandler.DockerHubWebhook) // DockerHub webhook
+					webhooks.POST("/dockerhub", r.ImageHandler.DockerHubWebhook) // DockerHub webhook
 				}
 			}
 
 			// AutoScaler management
-			if r.autoscalerHandler != nil {
+			if r.AutoscalerHandler != nil {
 				autoscaler := api.Group("/autoscaler")
 				{
 					// Full status (legacy, prefer using separate endpoints below)
-					autoscaler.GET("/status", r.autoscalerHandler.GetStatus)
+					autoscaler.GET("/status", r.AutoscalerHandler.GetStatus)
 
 					// Lightweight endpoints for better performance
-					autoscaler.GET("/cluster-resources", r.autoscalerHandler.GetClusterResources) // Cluster resources only
-					autoscaler.GET("/recent-events", r.autoscalerHandler.GetRecentEvents)         // Recent events only
+					autoscaler.GET("/cluster-resources", r.AutoscalerHandler.GetClusterResources) // Cluster resources only
+					autoscaler.GET("/recent-events", r.AutoscalerHandler.GetRecentEvents)         // Recent events only
+
+					autoscaler.GET("/startup-times", r.AutoscalerHandler.GetStartupTimes) // Learned startup time per spec/image
 
 					// Control
-					autoscaler.POST("/enable", r.autoscalerHandler.Enable)
-					autoscaler.POST("/disable", r.autoscalerHandler.Disable)
-					autoscaler.POST("/trigger", r.autoscalerHandler.TriggerScale)
-					autoscaler.POST("/trigger/:name", r.autoscalerHandler.TriggerScale)
+					autoscaler.POST("/enable", r.AutoscalerHandler.Enable)
+					autoscaler.POST("/disable", r.AutoscalerHandler.Disable)
+					autoscaler.POST("/trigger", r.AutoscalerHandler.TriggerScale)
+					autoscaler.POST("/trigger/:name", r.AutoscalerHandler.TriggerScale)
 
 					// Configuration
-					autoscaler.GET("/config", r.autoscalerHandler.GetGlobalConfig)
-					autoscaler.PUT("/config", r.autoscalerHandler.UpdateGlobalConfig)
-					autoscaler.GET("/endpoints", r.autoscalerHandler.ListEndpoints)
-					autoscaler.GET("/endpoints/:name", r.autoscalerHandler.GetEndpointConfig)
-					autoscaler.PUT("/endpoints/:name", r.autoscalerHandler.UpdateEndpointConfig)
+					autoscaler.GET("/config", r.AutoscalerHandler.GetGlobalConfig)
+					autoscaler.PUT("/config", r.AutoscalerHandler.UpdateGlobalConfig)
+					autoscaler.GET("/endpoints", r.AutoscalerHandler.ListEndpoints)
+					autoscaler.GET("/endpoints/:name", r.AutoscalerHandler.GetEndpointConfig)
+					autoscaler.PUT("/endpoints/:name", r.AutoscalerHandler.UpdateEndpointConfig)
+
+					// Replica schedules (cron-based min/max replica windows)
+					autoscaler.GET("/endpoints/:name/schedules", r.AutoscalerHandler.ListReplicaSchedules)
+					autoscaler.POST("/endpoints/:name/schedules", r.AutoscalerHandler.CreateReplicaSchedule)
+					autoscaler.PUT("/endpoints/:name/schedules/:schedule", r.AutoscalerHandler.UpdateReplicaSchedule)
+					autoscaler.DELETE("/endpoints/:name/schedules/:schedule", r.AutoscalerHandler.DeleteReplicaSchedule)
 
 					// History
-					autoscaler.GET("/history/:name", r.autoscalerHandler.GetHistory)
+					autoscaler.GET("/history/:name", r.AutoscalerHandler.GetHistory)
 				}
 			}
 
 			// Statistics APIs
-			if r.statisticsHandler != nil {
+			if r.StatisticsHandler != nil {
 				statistics := api.Group("/statistics")
 				{
-					statistics.GET("/overview", r.statisticsHandler.GetOverview)                      // Global statistics
-					statistics.GET("/endpoints", r.statisticsHandler.GetTopEndpoints)                 // Top endpoints by task volume
-					statistics.GET("/endpoints/:endpoint", r.statisticsHandler.GetEndpointStatistics) // Specific endpoint statistics
+					statistics.GET("/overview", r.StatisticsHandler.GetOverview)                      // Global statistics
+					statistics.GET("/endpoints", r.StatisticsHandler.GetTopEndpoints)                 // Top endpoints by task volume
+					statistics.GET("/endpoints/:endpoint", r.StatisticsHandler.GetEndpointStatistics) // Specific endpoint statistics
+					if r.ReplayHandler != nil {
+						statistics.GET("/endpoints/:endpoint/replays", r.ReplayHandler.GetEndpointReplayStatistics) // Replayed tasks by status
+					}
+					if r.HedgingHandler != nil {
+						statistics.GET("/endpoints/:endpoint/hedges", r.HedgingHandler.GetEndpointHedgeStatistics) // Hedged tasks: wins, cost and savings
+					}
+				}
+			}
+
+			// Monitoring APIs (cross-endpoint)
+			if r.MonitoringHandler != nil {
+				monitoring := api.Group("/monitoring")
+				{
+					monitoring.GET("/cold-starts", r.MonitoringHandler.GetColdStartStats)               // Cold start p50/p95 by endpoint or spec
+					monitoring.GET("/runtime-state-sync", r.MonitoringHandler.GetRuntimeStateSyncStats) // runtime_state writes vs skipped unchanged syncs
+					monitoring.GET("/quotas", r.MonitoringHandler.GetQuotaStatus)                       // Project quota usage and days-to-exhaustion forecast
+					monitoring.GET("/quotas/:project", r.MonitoringHandler.GetProjectQuota)
+				}
+			}
+
+			// GPU usage APIs
+			if r.GPUUsageHandler != nil {
+				gpuUsage := api.Group("/gpu-usage")
+				{
+					gpuUsage.GET("/minute", r.GPUUsageHandler.GetMinuteStats)                   // Minute-level statistics
+					gpuUsage.GET("/hourly", r.GPUUsageHandler.GetHourlyStats)                   // Hourly statistics
+					gpuUsage.GET("/daily", r.GPUUsageHandler.GetDailyStats)                     // Daily statistics
+					gpuUsage.GET("/top", r.GPUUsageHandler.GetTopScopes)                        // Top-N endpoints/specs/workers/GPU types
+					gpuUsage.GET("/aggregation/status", r.GPUUsageHandler.GetAggregationStatus) // Aggregation watermark and lag
+					gpuUsage.POST("/aggregate", r.GPUUsageHandler.TriggerAggregation)           // Re-aggregate a time range
+					gpuUsage.POST("/backfill", r.GPUUsageHandler.Backfill)                      // Backfill missing records (async=true: resumable job)
 				}
 			}
 		}
+
+		// API v2 - list responses share the {items, nextCursor, total} envelope and RFC3339 timestamps
+		apiV2 := engine.Group("/api/v2")
+		{
+			apiV2.GET("/tasks", r.TaskHandler.ListTasksV2)                                 // Tasks, newest first
+			apiV2.GET("/endpoints", r.EndpointHandler.ListEndpointsV2)                     // Endpoints
+			apiV2.GET("/endpoints/:name/workers", r.EndpointHandler.ListEndpointWorkersV2) // Workers of an endpoint
+			apiV2.GET("/worker-bans", r.WorkerHandler.ListWorkerBansV2)                    // Node and image bans, newest first
+			apiV2.GET("/models/:model/versions", r.EndpointHandler.ListModelVersionsV2)    // Model registry versions, newest first
+			if r.OperationHandler != nil {
+				apiV2.GET("/operations", r.OperationHandler.ListOperationsV2) // Operations and jobs, newest first
+			}
+			if r.ChangeHandler != nil {
+				apiV2.GET("/change-requests", r.ChangeHandler.ListChangeRequestsV2) // Change requests, newest first
+			}
+			if r.ReplayHandler != nil {
+				apiV2.GET("/replays", r.ReplayHandler.ListReplayJobsV2) // Replay jobs, newest first
+			}
+			if r.BatchJobHandler != nil {
+				apiV2.GET("/jobs", r.BatchJobHandler.ListJobsV2) // Batch jobs, newest first
+			}
+			if r.LoadTestHandler != nil {
+				apiV2.GET("/load-tests/:name/reports", r.LoadTestHandler.ListReportsV2) // Load test reports, newest first
+			}
+		}
 	}
 
 	// Health check
 	engine.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
+
+	// Control-plane self-metrics in the Prometheus text format
+	engine.GET("/metrics", gin.WrapH(metrics.Handler()))
 }
@@ -0,0 +1,122 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"waverless/app/handler"
+	"waverless/internal/service"
+	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChangeRequests records the change requests the approval gate stores
+type fakeChangeRequests struct {
+	created []*model.ChangeRequest
+}
+
+func (f *fakeChangeRequests) Create(ctx context.Context, cr *model.ChangeRequest) error {
+	cr.ID = int64(len(f.created) + 1)
+	f.created = append(f.created, cr)
+	return nil
+}
+
+func (f *fakeChangeRequests) Get(ctx context.Context, id int64) (*model.ChangeRequest, error) {
+	return nil, nil
+}
+
+func (f *fakeChangeRequests) List(ctx context.Context, endpoint, status string, limit, offset int) ([]*model.ChangeRequest, error) {
+	return nil, nil
+}
+
+func (f *fakeChangeRequests) Count(ctx context.Context, endpoint, status string) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeChangeRequests) Transition(ctx context.Context, id int64, from, to string, updates map[string]interface{}) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeChangeRequests) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+// protectedEndpoints labels every endpoint for production
+type protectedEndpoints struct{}
+
+func (protectedEndpoints) GetEndpoint(ctx context.Context, name string) (*interfaces.EndpointMetadata, error) {
+	return &interfaces.EndpointMetadata{Name: name, Labels: map[string]string{"environment": "prod"}}, nil
+}
+
+const approverToken = "alice-token-0123456789"
+
+// Changes to a protected endpoint are held for approval on every route that mutates it. The
+// handlers have no services behind them, so a route that skipped the gate would not answer 202.
+func TestProtectedEndpointRoutesHeldForApproval(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &fakeChangeRequests{}
+	changes, err := service.NewChangeRequestService(store, protectedEndpoints{}, config.ApprovalConfig{
+		Enabled:   true,
+		Labels:    map[string]string{"environment": "prod"},
+		Approvers: []config.ApprovalIdentity{{Name: "alice", Token: approverToken}, {Name: "bob", Token: "bob-token-0123456789"}},
+		TTL:       time.Hour,
+	})
+	require.NoError(t, err)
+
+	endpoints := handler.NewEndpointHandler(nil, nil, nil)
+	endpoints.SetChangeRequestService(changes)
+	autoscaling := handler.NewAutoScalerHandler(nil, nil)
+	autoscaling.SetChangeRequestService(changes)
+	circuit := handler.NewCircuitBreakerHandler(nil)
+	circuit.SetChangeRequestService(changes)
+
+	engine := gin.New()
+	NewRouter(Handlers{
+		EndpointHandler:   endpoints,
+		AutoscalerHandler: autoscaling,
+		CircuitHandler:    circuit,
+	}, nil).Setup(engine)
+
+	schedule := `{"name":"business-hours","cron":"0 9 * * 1-5","durationMinutes":600,"minReplicas":2,"maxReplicas":8}`
+	tests := []struct {
+		method    string
+		path      string
+		body      string
+		operation string
+	}{
+		{http.MethodPost, "/api/v1/endpoints/flux/pause", `{"mode":"reject"}`, model.ChangeOpPauseDispatch},
+		{http.MethodPost, "/api/v1/endpoints/flux/resume", "", model.ChangeOpResumeDispatch},
+		{http.MethodPost, "/api/v1/endpoints/flux/circuit/close", "", model.ChangeOpCloseCircuit},
+		{http.MethodPut, "/api/v1/autoscaler/endpoints/flux", `{"minReplicas":0,"maxReplicas":4}`, model.ChangeOpUpdateAutoscaling},
+		{http.MethodPost, "/api/v1/autoscaler/endpoints/flux/schedules", schedule, model.ChangeOpCreateReplicaSchedule},
+		{http.MethodPut, "/api/v1/autoscaler/endpoints/flux/schedules/business-hours", schedule, model.ChangeOpUpdateReplicaSchedule},
+		{http.MethodDelete, "/api/v1/autoscaler/endpoints/flux/schedules/business-hours", "", model.ChangeOpDeleteReplicaSchedule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			store.created = nil
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(handler.ApprovalTokenHeader, approverToken)
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+			require.Len(t, store.created, 1)
+			assert.Equal(t, tt.operation, store.created[0].Operation)
+			assert.Equal(t, "flux", store.created[0].Endpoint)
+			assert.Equal(t, "alice", store.created[0].RequestedBy)
+		})
+	}
+}
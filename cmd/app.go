@@ -14,9 +14,14 @@ import (
 	"waverless/pkg/autoscaler"
 	"waverless/pkg/capacity"
 	"waverless/pkg/config"
+	"waverless/pkg/coordination"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/longpoll"
+	"waverless/pkg/maintenance"
 	"waverless/pkg/monitoring"
+	"waverless/pkg/provider"
+	"waverless/pkg/resource"
 	mysqlstore "waverless/pkg/store/mysql"
 	redisstore "waverless/pkg/store/redis"
 
@@ -32,6 +37,7 @@ type Application struct {
 
 	// Business providers
 	deploymentProvider interfaces.DeploymentProvider
+	providers          *provider.ProviderRegistry
 
 	// Service layer
 	endpointService      *endpointsvc.Service
@@ -41,16 +47,80 @@ type Application struct {
 	statisticsService    *service.StatisticsService
 	specService          *service.SpecService
 	monitoringService    *service.MonitoringService
+	gpuUsageService      *service.GPUUsageService
+	mirrorService        *service.RegistryMirrorService
+	logService           *service.LogService
+	drService            *service.DisasterRecoveryService
+	groupService         *service.EndpointGroupService
+	reservationService   *service.GPUReservationService
+	federationService    *service.FederationService
+	gpuTierService       *service.GPUTierService
+	batchJobService      *service.BatchJobService
+	scheduleService      *service.JobScheduleService
+	samplingService      *service.SamplingService
+	transformService     *service.TransformService
+	redactionService     *service.LogRedactionService
+	encryptionService    *service.TaskEncryptionService
+	deletionService      *service.DataDeletionService
+	replayService        *service.TaskReplayService
+	statusPageService    *service.StatusPageService
+	hedgingService       *service.HedgingService
+	wakeService          *service.WakeService
+	wakeSignals          *longpoll.Hub
+	circuitBreaker       *service.CircuitBreakerService
+	changeService        *service.ChangeRequestService
+	integrationService   *service.IntegrationService
+	handshakeService     *service.HandshakeService
+	broadcastService     *service.WorkerBroadcastService
+	quarantineService    *service.WorkerQuarantineService
+	operationService     *service.OperationService
+	loadTestService      *service.LoadTestService
+	diskPressureService  *service.DiskPressureService
+	anomalyService       *service.AnomalyService
+	quotaService         *service.QuotaService
+	integrityService     *service.WorkerIntegrityService
+	hookService          *service.LifecycleHookService
 
 	// Handler layer
-	taskHandler       *handler.TaskHandler
-	workerHandler     *handler.WorkerHandler
-	endpointHandler   *handler.EndpointHandler
-	autoscalerHandler *handler.AutoScalerHandler
-	statisticsHandler *handler.StatisticsHandler
-	specHandler       *handler.SpecHandler
-	imageHandler      *handler.ImageHandler
-	monitoringHandler *handler.MonitoringHandler
+	taskHandler        *handler.TaskHandler
+	workerHandler      *handler.WorkerHandler
+	endpointHandler    *handler.EndpointHandler
+	autoscalerHandler  *handler.AutoScalerHandler
+	statisticsHandler  *handler.StatisticsHandler
+	specHandler        *handler.SpecHandler
+	imageHandler       *handler.ImageHandler
+	monitoringHandler  *handler.MonitoringHandler
+	gpuUsageHandler    *handler.GPUUsageHandler
+	mirrorHandler      *handler.RegistryMirrorHandler
+	logHandler         *handler.LogHandler
+	drHandler          *handler.DisasterRecoveryHandler
+	maintHandler       *handler.MaintenanceHandler
+	groupHandler       *handler.EndpointGroupHandler
+	reservationHandler *handler.GPUReservationHandler
+	federationHandler  *handler.FederationHandler
+	gpuTierHandler     *handler.GPUTierHandler
+	batchJobHandler    *handler.BatchJobHandler
+	scheduleHandler    *handler.JobScheduleHandler
+	operationHandler   *handler.OperationHandler
+	loadTestHandler    *handler.LoadTestHandler
+	samplingHandler    *handler.SamplingHandler
+	transformHandler   *handler.TransformHandler
+	redactionHandler   *handler.LogRedactionHandler
+	encryptionHandler  *handler.EncryptionHandler
+	deletionHandler    *handler.DataDeletionHandler
+	replayHandler      *handler.TaskReplayHandler
+	statusPageHandler  *handler.StatusPageHandler
+	hedgingHandler     *handler.HedgingHandler
+	circuitHandler     *handler.CircuitBreakerHandler
+	changeHandler      *handler.ChangeRequestHandler
+	integrationHandler *handler.IntegrationHandler
+	novitaHandler      *handler.NovitaHandler
+
+	// Read-only switch for maintenance windows
+	readOnlySwitch *maintenance.Switch
+
+	// Emergency brake freezing autoscaling and rollouts during incidents
+	emergencyBrake *maintenance.Brake
 
 	// Monitoring
 	monitoringCollector *monitoring.Collector
@@ -61,6 +131,9 @@ type Application struct {
 	// Auto-scaler
 	autoscalerMgr *autoscaler.Manager
 
+	// Resource releaser (failed worker cleanup and endpoint health derivation)
+	resourceReleaser *resource.ResourceReleaser
+
 	// HTTP server
 	httpServer *http.Server
 	ginEngine  *gin.Engine
@@ -68,6 +141,11 @@ type Application struct {
 	// Background tasks
 	jobsManager *jobs.Manager
 
+	// Coordination: only the replica holding the leader lease acts on watch
+	// events and runs leader controllers (nil for API-only replicas)
+	leaderElector     *coordination.LeaderElector
+	leaderControllers []leaderController
+
 	// Context management
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -100,6 +178,7 @@ func (app *Application) Initialize() error {
 		{"Logging", app.initLogger},
 		{"MySQL", app.initMySQL},
 		{"Redis", app.initRedis},
+		{"Coordination", app.initCoordination},
 		{"Business Providers", app.initProviders},
 		{"Service Layer", app.initServices},
 		{"Background Tasks", app.initJobs},
@@ -124,7 +203,7 @@ func (app *Application) Initialize() error {
 func (app *Application) Start() error {
 	logger.InfoCtx(app.ctx, "Starting application components...")
 
-	// 1. Start background tasks
+	// 1. Start background tasks (lock-guarded per run, not started on API-only replicas)
 	if app.jobsManager != nil {
 		logger.InfoCtx(app.ctx, "Starting background task manager")
 		app.jobsManager.Start()
@@ -135,8 +214,8 @@ func (app *Application) Start() error {
 		}()
 	}
 
-	// 2. Start AutoScaler
-	if app.autoscalerMgr != nil {
+	// 2. Start AutoScaler (guarded by its own global lock per cycle)
+	if app.autoscalerMgr != nil && app.config.Coordination.RunsControllers() {
 		if err := app.autoscalerMgr.Start(app.ctx); err != nil {
 			logger.ErrorCtx(app.ctx, "Failed to start autoscaler: %v", err)
 		} else {
@@ -144,7 +223,18 @@ func (app *Application) Start() error {
 		}
 	}
 
-	// 3. Start HTTP server
+	// 3. Campaign for the leader lease; controllers run only while it is held
+	if app.leaderElector != nil {
+		app.wg.Add(1)
+		go func() {
+			defer app.wg.Done()
+			app.leaderElector.Run(app.ctx, coordination.LeaderCallbacks{
+				OnStartedLeading: app.runLeaderControllers,
+			})
+		}()
+	}
+
+	// 4. Start HTTP server
 	app.wg.Add(1)
 	go func() {
 		defer app.wg.Done()
@@ -213,6 +303,32 @@ func (app *Application) Shutdown(timeout time.Duration) error {
 	return nil
 }
 
+// leaderController is a long-running loop that must only run on the leader replica
+type leaderController struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// registerLeaderController registers a loop started when this replica acquires the
+// leader lease; its context is cancelled when leadership is lost
+func (app *Application) registerLeaderController(name string, run func(ctx context.Context)) {
+	app.leaderControllers = append(app.leaderControllers, leaderController{name: name, run: run})
+}
+
+// runLeaderControllers starts all leader controllers with the leader context
+func (app *Application) runLeaderControllers(ctx context.Context) {
+	for _, c := range app.leaderControllers {
+		logger.InfoCtx(ctx, "Starting leader controller: %s", c.name)
+		go c.run(ctx)
+	}
+}
+
+// isLeader reports whether this replica should act on watch events.
+// Informer caches run on every replica; their side effects only on the leader.
+func (app *Application) isLeader() bool {
+	return app.leaderElector != nil && app.leaderElector.IsLeader()
+}
+
 // registerCleanup registers cleanup function
 func (app *Application) registerCleanup(cleanup func()) {
 	app.cleanupFuncs = append(app.cleanupFuncs, cleanup)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"waverless/app/handler"
@@ -14,14 +15,30 @@ import (
 	"waverless/pkg/autoscaler"
 	"waverless/pkg/capacity"
 	"waverless/pkg/config"
+	"waverless/pkg/coordination"
+	"waverless/pkg/dataplane"
 	"waverless/pkg/deploy/k8s"
+	"waverless/pkg/discovery"
 	"waverless/pkg/deploy/novita"
+	"waverless/pkg/deploy/runpod"
+	"waverless/pkg/federation"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/logship"
+	"waverless/pkg/longpoll"
+	"waverless/pkg/maintenance"
+	"waverless/pkg/modelregistry"
 	"waverless/pkg/monitoring"
+	"waverless/pkg/notification"
 	"waverless/pkg/provider"
+	"waverless/pkg/ratelimit"
 	"waverless/pkg/resource"
+	"waverless/pkg/sampling"
+	"waverless/pkg/scheduler"
+	"waverless/pkg/envelope"
+	"waverless/pkg/export"
 	mysqlstore "waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
 	redisstore "waverless/pkg/store/redis"
 
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
@@ -93,6 +110,32 @@ func (app *Application) initRedis() error {
 	return nil
 }
 
+// initCoordination sets up the controller leader lease.
+// API-only replicas skip it: they never act on watch events or run controllers.
+func (app *Application) initCoordination() error {
+	cfg := app.config.Coordination
+	if !cfg.RunsControllers() {
+		logger.InfoCtx(app.ctx, "Running as API replica (role=%s): watch callbacks, controllers and background jobs are disabled", cfg.Role)
+		return nil
+	}
+
+	var lease coordination.Lease
+	if app.redisClient != nil && app.redisClient.GetClient() != nil {
+		lease = coordination.NewRedisLease(app.redisClient.GetClient(), "controller")
+	} else {
+		logger.WarnCtx(app.ctx, "Redis not available, using in-process leader lease (single-instance mode)")
+		lease = coordination.NewMemoryLease()
+	}
+
+	app.leaderElector = coordination.NewLeaderElector(lease, coordination.ElectorConfig{
+		Identity:      cfg.Identity,
+		LeaseTTL:      cfg.LeaseTTL,
+		RenewInterval: cfg.RenewInterval,
+	})
+	logger.InfoCtx(app.ctx, "Controller leader election enabled (role=%s, identity=%s, leaseTTL=%v)", cfg.Role, cfg.Identity, cfg.LeaseTTL)
+	return nil
+}
+
 // initProviders initializes business providers
 func (app *Application) initProviders() error {
 	// Initialize Provider Factory
@@ -105,9 +148,22 @@ func (app *Application) initProviders() error {
 	}
 
 	app.deploymentProvider = providers.Deployment
+	app.providers = providers.Registry
+	logger.InfoCtx(app.ctx, "Deployment providers: %v (default: %s)", app.providers.Names(), app.providers.DefaultName())
+
+	// Route existing endpoints to the provider they are pinned to
+	if router, ok := providers.Deployment.(*provider.Router); ok && app.mysqlRepo != nil {
+		router.SetEndpointLookup(func(ctx context.Context, endpoint string) (string, bool, error) {
+			ep, err := app.mysqlRepo.Endpoint.Get(ctx, endpoint)
+			if err != nil || ep == nil || ep.Status == "deleted" {
+				return "", false, err
+			}
+			return ep.Provider, true, nil
+		})
+	}
 
 	// Register cleanup for K8s provider
-	if k8sProv, ok := providers.Deployment.(*k8s.K8sDeploymentProvider); ok {
+	if k8sProv, ok := app.providers.Get("k8s").(*k8s.K8sDeploymentProvider); ok {
 		app.registerCleanup(func() {
 			k8sProv.Close()
 			logger.InfoCtx(app.ctx, "K8s deployment provider has been closed")
@@ -126,6 +182,13 @@ func (app *Application) initServices() error {
 		app.mysqlRepo.Task,
 		app.deploymentProvider,
 	)
+	app.workerService.SetEndpointRepository(app.mysqlRepo.Endpoint)
+	app.workerService.SetEndpointGroupRepository(app.mysqlRepo.EndpointGroup)
+	policies, err := scheduler.NewPolicies(app.config.Scheduler.Policy, app.config.Scheduler.Endpoints, app.config.Scheduler.Shadow)
+	if err != nil {
+		return fmt.Errorf("invalid scheduler config: %w", err)
+	}
+	app.workerService.SetSchedulerPolicies(policies)
 
 	// Initialize worker event service for monitoring
 	app.workerEventService = service.NewWorkerEventService(app.mysqlRepo.Monitoring)
@@ -139,6 +202,20 @@ func (app *Application) initServices() error {
 		app.workerService,
 		app.deploymentProvider,
 	)
+	app.endpointService.SetImageValidationRepository(app.mysqlRepo.ImageValidation)
+	app.endpointService.SetImageCopyRepository(app.mysqlRepo.ImageCopy)
+	if app.deploymentProvider != nil && !app.config.Enrichment.Disabled {
+		providerName := "k8s"
+		if app.config.Providers != nil && app.config.Providers.Deployment != "" {
+			providerName = app.config.Providers.Deployment
+		}
+		app.endpointService.SetRuntimeEnricher(endpointsvc.NewRuntimeEnricher(app.deploymentProvider, endpointsvc.EnrichmentOptions{
+			Provider:    providerName,
+			Concurrency: app.config.Enrichment.ProviderConcurrency[strings.ToLower(providerName)],
+			CallTimeout: app.config.Enrichment.CallTimeout,
+			Budget:      app.config.Enrichment.Budget,
+		}))
+	}
 
 	// Initialize task service
 	app.taskService = service.NewTaskService(
@@ -154,6 +231,27 @@ func (app *Application) initServices() error {
 	// Set worker service on task service (for worker stats recording)
 	app.taskService.SetWorkerService(app.workerService)
 
+	// Wake long-polling worker pulls when tasks are queued (across replicas via Redis pub/sub)
+	taskSignals := longpoll.NewHub(nil)
+	if app.redisClient != nil {
+		taskSignals = longpoll.NewHub(app.redisClient.GetClient())
+	}
+	go taskSignals.Run(app.ctx)
+	app.taskService.SetTaskSignals(taskSignals)
+	app.workerService.SetLongPoll(taskSignals,
+		time.Duration(app.config.Worker.LongPollMaxWait)*time.Second,
+		time.Duration(app.config.Worker.LongPollRecheck)*time.Second)
+
+	// Scale endpoints at zero replicas up as soon as they get a task; the autoscaler of the
+	// controller replica listens (see initAutoScaler)
+	app.wakeSignals = longpoll.NewChannelHub(nil, "waverless:endpoints:wake")
+	if app.redisClient != nil {
+		app.wakeSignals = longpoll.NewChannelHub(app.redisClient.GetClient(), "waverless:endpoints:wake")
+	}
+	go app.wakeSignals.Run(app.ctx)
+	app.taskService.SetWakeSignals(app.wakeSignals)
+	app.wakeService = service.NewWakeService(app.config.Wake, app.mysqlRepo.Worker, app.mysqlRepo.Monitoring)
+
 	// Initialize statistics service
 	app.statisticsService = service.NewStatisticsService(app.mysqlRepo.TaskStatistics, app.mysqlRepo.Worker)
 
@@ -165,6 +263,165 @@ func (app *Application) initServices() error {
 
 	// Initialize monitoring service
 	app.monitoringService = service.NewMonitoringService(app.mysqlRepo.Monitoring)
+	app.monitoringService.SetRepository(app.mysqlRepo)
+
+	// Initialize GPU usage service and record usage on task completion
+	app.gpuUsageService = service.NewGPUUsageService(app.mysqlRepo, app.config.Reporting)
+	app.taskService.SetGPUUsageService(app.gpuUsageService)
+
+	// Initialize endpoint group service (shared capacity pools)
+	app.groupService = service.NewEndpointGroupService(app.mysqlRepo.EndpointGroup, app.endpointService, app.mysqlRepo.Task, app.gpuUsageService)
+
+	// Initialize GPU reservation service (capacity held for scheduled launches)
+	app.reservationService = service.NewGPUReservationService(app.mysqlRepo.GPUReservation, app.endpointService, app.deploymentProvider, app.config.AutoScaler.MaxGPUCount)
+
+	// Initialize federation service (logical endpoints routed across regions)
+	app.federationService = service.NewFederationService(app.mysqlRepo.Federation, app.endpointService, app.mysqlRepo.Task, app.gpuUsageService)
+	if resolver, err := federation.NewSourceRegionResolver(app.config.Federation.SourceRegions); err != nil {
+		logger.WarnCtx(app.ctx, "Federation source regions disabled: %v", err)
+	} else {
+		app.federationService.SetSourceRegionResolver(resolver)
+	}
+	app.taskService.SetFederationService(app.federationService)
+
+	// Initialize GPU tiers (heterogeneous endpoints route tasks to cheaper specs by rule)
+	app.gpuTierService = service.NewGPUTierService(app.mysqlRepo.GPUTier, app.endpointService)
+	app.taskService.SetGPUTierService(app.gpuTierService)
+
+	// Initialize batch jobs (one-off GPU runs outside endpoints)
+	app.batchJobService = service.NewBatchJobService(app.mysqlRepo.BatchJob, app.mysqlRepo.GPUUsage, app.providers.Default())
+
+	// Initialize task sampling (rules are always manageable, capturing needs a datasets store)
+	sampler, sampleStore := app.createSampler()
+	app.samplingService = service.NewSamplingService(app.mysqlRepo.SamplingRule, sampler)
+	app.taskService.SetSamplingService(app.samplingService)
+
+	// Initialize data deletion by subject key (tasks, task events and sampled copies)
+	app.deletionService = service.NewDataDeletionService(app.mysqlRepo.DataDeletion, app.mysqlRepo.Task)
+	if sampler != nil {
+		sampler.SetUploadHook(app.deletionService.IndexSamples)
+		if rewriter, ok := sampleStore.(export.ObjectRewriter); ok {
+			app.deletionService.SetSampleStore(rewriter)
+		}
+	}
+
+	// Initialize task replays (single tasks and throttled time windows)
+	app.replayService = service.NewTaskReplayService(app.mysqlRepo.TaskReplay, app.mysqlRepo.Task, app.taskService)
+
+	// Initialize endpoint input/output transforms
+	app.transformService = service.NewTransformService(app.mysqlRepo.Transform)
+	app.taskService.SetTransformService(app.transformService)
+
+	// Initialize per-endpoint log redaction rules (applied to log APIs and log shipping)
+	app.redactionService = service.NewLogRedactionService(app.mysqlRepo.LogRedaction)
+
+	// Initialize task payload encryption at rest (per-project data keys)
+	if err := app.setupTaskEncryption(); err != nil {
+		return fmt.Errorf("failed to setup task encryption: %w", err)
+	}
+
+	// Initialize change requests (second approver for protected endpoints)
+	changeService, err := service.NewChangeRequestService(app.mysqlRepo.ChangeRequest, app.endpointService, app.config.Approval)
+	if err != nil {
+		return err
+	}
+	app.changeService = changeService
+
+	// Initialize integrations registry (signed webhooks for task and endpoint events)
+	app.integrationService = service.NewIntegrationService(app.mysqlRepo.Integration)
+	app.taskService.SetIntegrationService(app.integrationService)
+
+	// Publish env changes of endpoints (the env activity feed) to integrations
+	app.endpointService.SetEnvChangeHook(func(ctx context.Context, change *mysqlModel.EndpointEnvChange) {
+		app.integrationService.Publish(ctx, &notification.Event{
+			Type:      notification.EventEnvChanged,
+			Endpoint:  change.Endpoint,
+			CreatedAt: change.CreatedAt,
+			Data:      change,
+		})
+	})
+
+	// Initialize service discovery (Consul or external-dns registration of endpoints)
+	if err := app.setupDiscovery(); err != nil {
+		return fmt.Errorf("failed to setup service discovery: %w", err)
+	}
+
+	// Initialize the model registry (endpoints deployed by model name and version)
+	if err := app.setupModelRegistry(); err != nil {
+		return fmt.Errorf("failed to setup model registry: %w", err)
+	}
+
+	// Initialize lifecycle hooks (pre-deploy, post-deploy and pre-delete HTTP calls or jobs)
+	app.hookService = service.NewLifecycleHookService(app.mysqlRepo.LifecycleHook, app.endpointService, app.providers.Default())
+	app.hookService.SetIntegrationService(app.integrationService)
+	app.endpointService.SetLifecycleHooks(app.hookService)
+
+	// Initialize recurring job schedules (cron-started batch jobs, outcomes published to integrations)
+	app.scheduleService = service.NewJobScheduleService(app.mysqlRepo.JobSchedule, app.mysqlRepo.BatchJob, app.batchJobService, app.integrationService)
+	app.batchJobService.SetScheduleService(app.scheduleService)
+
+	// Initialize worker startup handshake (contract negotiation, endpoint warnings)
+	app.handshakeService = service.NewHandshakeService(app.mysqlRepo.EndpointWarning, app.mysqlRepo.Worker, app.endpointService, app.deploymentProvider)
+
+	// Initialize worker broadcasts (control messages delivered through the heartbeat)
+	app.broadcastService = service.NewWorkerBroadcastService(app.mysqlRepo.WorkerBroadcast, app.mysqlRepo.Worker, app.endpointService)
+	app.workerService.SetBroadcastService(app.broadcastService)
+
+	// Initialize worker quarantine and node/image bans (incident triage)
+	app.quarantineService = service.NewWorkerQuarantineService(app.mysqlRepo.Worker, app.mysqlRepo.WorkerBan, app.deploymentProvider)
+	app.workerService.SetQuarantineService(app.quarantineService)
+
+	// Initialize long-running operations (async deploys, rollouts and backfills, pollable on any replica);
+	// backfill, replay and batch jobs are listed, polled and cancelled as operations too
+	app.operationService = service.NewOperationService(app.mysqlRepo.Operation, app.config.Coordination.Identity, app.endpointService)
+	app.operationService.SetGPUUsageService(app.gpuUsageService)
+	app.operationService.SetTaskReplayService(app.replayService)
+	app.operationService.SetBatchJobService(app.batchJobService)
+	app.operationService.SetBlueGreenService(service.NewBlueGreenService(app.endpointService, app.mysqlRepo.Worker, app.quarantineService))
+
+	// Initialize load tests of staging endpoints (runs are operations)
+	if app.config.LoadTest.Enabled {
+		app.loadTestService = service.NewLoadTestService(app.mysqlRepo.LoadTest, app.mysqlRepo.Task, app.mysqlRepo.ScalingEvent,
+			app.taskService, app.endpointService, app.integrationService, app.config.LoadTest)
+		app.loadTestService.SetOperationService(app.operationService)
+	}
+
+	// Initialize disk pressure handling (alerts, optional ephemeral storage bump)
+	app.diskPressureService = service.NewDiskPressureService(app.endpointService, app.deploymentProvider, app.integrationService, app.config.K8s.DiskPressure)
+
+	// Initialize the workers table vs live pods integrity check (closes out rows of missed pod deletes)
+	if app.deploymentProvider != nil {
+		app.integrityService = service.NewWorkerIntegrityService(app.mysqlRepo.Worker, app.mysqlRepo.Endpoint, app.deploymentProvider,
+			time.Duration(app.config.Worker.IntegrityGrace)*time.Second, time.Duration(app.config.Worker.HeartbeatTimeout)*time.Second)
+		app.integrityService.SetWorkerEventService(app.workerEventService)
+	}
+
+	// Initialize usage anomaly detection (alerts via integrations, warnings on the endpoint)
+	if app.config.Anomaly.Enabled {
+		app.anomalyService = service.NewAnomalyService(app.mysqlRepo.Monitoring, app.mysqlRepo.GPUUsage, app.mysqlRepo.EndpointWarning, app.integrationService, app.config.Anomaly)
+	}
+
+	// Initialize project quota forecasting (warnings via integrations)
+	if app.config.Quota.Enabled {
+		app.quotaService = service.NewQuotaService(app.mysqlRepo.Endpoint, app.mysqlRepo.Spec, app.mysqlRepo.Monitoring, app.integrationService, app.config.Quota)
+	}
+
+	// Initialize the status page API (client-visible endpoint status)
+	if app.config.StatusPage.Enabled {
+		app.statusPageService = service.NewStatusPageService(app.mysqlRepo.Endpoint, app.mysqlRepo.Task, app.mysqlRepo.Monitoring, app.config.StatusPage)
+	}
+
+	// Initialize hedged execution (duplicates slow tasks of latency-critical endpoints)
+	if app.config.Hedging.Enabled {
+		app.hedgingService = service.NewHedgingService(app.config.Hedging, app.mysqlRepo.Task, app.mysqlRepo.Worker, app.mysqlRepo.Endpoint, app.taskService)
+	}
+
+	// Initialize the endpoint circuit breaker. Closing a breaker by hand stays available while
+	// it is disabled, for breakers tripped before.
+	app.circuitBreaker = service.NewCircuitBreakerService(app.config.CircuitBreaker, app.mysqlRepo.Endpoint, app.mysqlRepo.Task)
+	if app.config.CircuitBreaker.Enabled {
+		app.taskService.SetCircuitBreaker(app.circuitBreaker)
+	}
 
 	// Initialize monitoring collector
 	app.monitoringCollector = monitoring.NewCollector(app.mysqlRepo.Monitoring, app.mysqlRepo.Worker, app.mysqlRepo.Task)
@@ -172,24 +429,94 @@ func (app *Application) initServices() error {
 	// Get K8s deployment provider for draining check
 	var k8sDeployProvider *k8s.K8sDeploymentProvider
 	if app.config.K8s.Enabled {
-		if k8sProv, ok := app.deploymentProvider.(*k8s.K8sDeploymentProvider); ok {
+		if k8sProv, ok := app.providers.Get("k8s").(*k8s.K8sDeploymentProvider); ok {
 			k8sDeployProvider = k8sProv
 		}
 	}
 
+	// Initialize registry mirror service and apply mirrors when rendering K8s deployments
+	app.mirrorService = service.NewRegistryMirrorService(app.mysqlRepo.RegistryMirror, app.config.K8s.RegistryMirrors)
+	if k8sDeployProvider != nil {
+		k8sDeployProvider.SetImageRewriter(app.mirrorService)
+	}
+
+	// Initialize disaster recovery export/import (registry credentials live in K8s secrets)
+	var credentialStore service.RegistryCredentialStore
+	if k8sDeployProvider != nil {
+		credentialStore = k8sDeployProvider
+	}
+	app.drService = service.NewDisasterRecoveryService(app.mysqlRepo, credentialStore)
+
+	// Initialize worker log shipping (pod logs -> Loki / Elasticsearch)
+	if app.config.LogShipping.Enabled {
+		if k8sDeployProvider == nil {
+			logger.WarnCtx(app.ctx, "Log shipping requires the K8s provider, disabled")
+		} else if sink, err := createLogSink(&app.config.LogShipping); err != nil {
+			logger.ErrorCtx(app.ctx, "Failed to create log sink, log shipping disabled: %v", err)
+		} else {
+			var checkpoints logship.CheckpointStore
+			if app.redisClient != nil {
+				checkpoints = logship.NewRedisCheckpointStore(app.redisClient.GetClient())
+			}
+			source := k8s.NewPodLogSource(k8sDeployProvider, app.config.LogShipping.MaxBytesPerPoll)
+			shipper := logship.NewShipper(source, sink, checkpoints)
+			if app.redisClient != nil {
+				shipper.SetSegmentIndex(logship.NewRedisSegmentIndex(app.redisClient.GetClient()))
+			}
+			app.logService = service.NewLogService(shipper, app.mysqlRepo.Task, app.mysqlRepo.Worker)
+			app.logService.SetRedactionService(app.redactionService)
+			logger.InfoCtx(app.ctx, "Log shipping enabled (backend: %s, interval: %v)", sink.Name(), app.config.LogShipping.Interval)
+		}
+	}
+
 	// Get Novita deployment provider for status sync
 	var novitaDeployProvider *novita.NovitaDeploymentProvider
 	if app.config.Novita.Enabled {
-		if novitaProv, ok := app.deploymentProvider.(*novita.NovitaDeploymentProvider); ok {
+		if novitaProv, ok := app.providers.Get("novita").(*novita.NovitaDeploymentProvider); ok {
 			novitaDeployProvider = novitaProv
 			// Inject spec service for database access
 			if app.specService != nil {
 				novitaDeployProvider.SetSpecRepository(app.specService)
 				logger.InfoCtx(app.ctx, "Spec service injected into Novita provider - specs will be read from database first")
 			}
+			// Throttle API calls per API key; replicas sharing the key share the limit through Redis
+			rl := app.config.Novita.RateLimit
+			var bucket ratelimit.Bucket = ratelimit.NewMemoryBucket()
+			if app.redisClient != nil && app.redisClient.GetClient() != nil {
+				bucket = ratelimit.NewRedisBucket(app.redisClient.GetClient())
+			}
+			novitaDeployProvider.SetRateLimiter(ratelimit.NewLimiter(bucket, ratelimit.Key("novita", app.config.Novita.APIKey),
+				rl.QPS, rl.Burst, time.Duration(rl.MaxWait)*time.Second))
 		}
 	}
 
+	// Get RunPod deployment provider for status sync
+	var runpodDeployProvider *runpod.RunPodDeploymentProvider
+	if app.config.RunPod.Enabled {
+		if runpodProv, ok := app.providers.Get("runpod").(*runpod.RunPodDeploymentProvider); ok {
+			runpodDeployProvider = runpodProv
+			if app.specService != nil {
+				runpodDeployProvider.SetSpecRepository(app.specService)
+				logger.InfoCtx(app.ctx, "Spec service injected into RunPod provider - specs will be read from database first")
+			}
+			rl := app.config.RunPod.RateLimit
+			var bucket ratelimit.Bucket = ratelimit.NewMemoryBucket()
+			if app.redisClient != nil && app.redisClient.GetClient() != nil {
+				bucket = ratelimit.NewRedisBucket(app.redisClient.GetClient())
+			}
+			runpodDeployProvider.SetRateLimiter(ratelimit.NewLimiter(bucket, ratelimit.Key("runpod", app.config.RunPod.APIKey),
+				rl.QPS, rl.Burst, time.Duration(rl.MaxWait)*time.Second))
+		}
+	}
+
+	// Setup Resource Releaser for automatic cleanup of failed workers
+	// This monitors workers with IMAGE_PULL_FAILED status and terminates them after timeout
+	// It is created before the watchers below so their events can trigger immediate health recomputes
+	// Validates: Requirements 5.1, 5.2, 5.3, 5.4
+	if err := app.setupResourceReleaser(); err != nil {
+		logger.WarnCtx(app.ctx, "Failed to setup resource releaser: %v (non-critical, continuing)", err)
+	}
+
 	// Setup Pod watcher for graceful shutdown (when K8s is enabled)
 	if err := app.setupPodWatcher(k8sDeployProvider); err != nil {
 		logger.WarnCtx(app.ctx, "Failed to setup pod watcher: %v (non-critical, continuing)", err)
@@ -202,6 +529,12 @@ func (app *Application) initServices() error {
 		// Non-critical feature, continue startup
 	}
 
+	// Setup node maintenance watcher for cluster node drains (when K8s is enabled)
+	if err := app.setupNodeMaintenanceWatcher(k8sDeployProvider); err != nil {
+		logger.WarnCtx(app.ctx, "Failed to setup node maintenance watcher: %v (non-critical, continuing)", err)
+		// Non-critical feature, continue startup
+	}
+
 	// Setup Deployment watcher for optimized rolling updates (when K8s is enabled)
 	if err := app.setupDeploymentWatcher(k8sDeployProvider); err != nil {
 		logger.WarnCtx(app.ctx, "Failed to setup deployment watcher: %v (non-critical, continuing)", err)
@@ -215,6 +548,23 @@ func (app *Application) initServices() error {
 		logger.WarnCtx(app.ctx, "Failed to setup pod status watcher: %v (non-critical, continuing)", err)
 	}
 
+	// A new leader forgets the runtime states it persisted when it last led, since another replica
+	// may have written since; the replay below then rewrites every endpoint and worker once
+	if app.mysqlRepo != nil {
+		app.registerLeaderController("runtime-state-sync-reset", func(ctx context.Context) {
+			app.mysqlRepo.ResetRuntimeStateSync()
+		})
+	}
+
+	// A new leader replays the informer cache so events ignored as a follower are not lost
+	if k8sDeployProvider != nil {
+		app.registerLeaderController("k8s-watch-replay", func(ctx context.Context) {
+			if err := k8sDeployProvider.ReplayWatchState(ctx); err != nil {
+				logger.WarnCtx(ctx, "Failed to replay K8s watch state after acquiring leadership: %v", err)
+			}
+		})
+	}
+
 	// Start pod cleanup job for stuck terminating pods (when K8s is enabled)
 	if err := app.startPodCleanupJob(k8sDeployProvider); err != nil {
 		logger.WarnCtx(app.ctx, "Failed to start pod cleanup job: %v (non-critical, continuing)", err)
@@ -227,16 +577,28 @@ func (app *Application) initServices() error {
 	}
 
 	// Setup Novita status watcher for endpoint status sync (when Novita is enabled)
-	if err := app.setupNovitaStatusWatcher(novitaDeployProvider); err != nil {
-		logger.WarnCtx(app.ctx, "Failed to setup Novita status watcher: %v (non-critical, continuing)", err)
-		// Non-critical feature, continue startup
+	if novitaDeployProvider != nil {
+		if err := app.setupPollingStatusWatcher("Novita", novitaDeployProvider); err != nil {
+			logger.WarnCtx(app.ctx, "Failed to setup Novita status watcher: %v (non-critical, continuing)", err)
+			// Non-critical feature, continue startup
+		}
+
+		// Setup Novita pod status watcher for worker runtime state sync
+		if err := app.setupPollingPodStatusWatcher("Novita", novitaDeployProvider); err != nil {
+			logger.WarnCtx(app.ctx, "Failed to setup Novita pod status watcher: %v (non-critical, continuing)", err)
+			// Non-critical feature, continue startup
+		}
 	}
 
-	// Setup Novita pod status watcher for worker runtime state sync (when Novita is enabled)
-	if err := app.setupNovitaPodStatusWatcher(novitaDeployProvider); err != nil {
-		logger.WarnCtx(app.ctx, "Failed to setup Novita pod status watcher: %v (non-critical, continuing)", err)
-		// Non-critical feature, continue startup
-  }
+	// Setup RunPod status and pod status watchers (when RunPod is enabled)
+	if runpodDeployProvider != nil {
+		if err := app.setupPollingStatusWatcher("RunPod", runpodDeployProvider); err != nil {
+			logger.WarnCtx(app.ctx, "Failed to setup RunPod status watcher: %v (non-critical, continuing)", err)
+		}
+		if err := app.setupPollingPodStatusWatcher("RunPod", runpodDeployProvider); err != nil {
+			logger.WarnCtx(app.ctx, "Failed to setup RunPod pod status watcher: %v (non-critical, continuing)", err)
+		}
+	}
 	// Setup Novita Worker status monitor for failure detection and tracking (when Novita is enabled)
 	// This monitors worker status changes and updates worker failure information in the database
 	// Validates: Requirements 3.1, 3.2, 3.3, 3.4
@@ -244,13 +606,6 @@ func (app *Application) initServices() error {
 		logger.WarnCtx(app.ctx, "Failed to setup Novita worker status monitor: %v (non-critical, continuing)", err)
 	}
 
-	// Setup Resource Releaser for automatic cleanup of failed workers
-	// This monitors workers with IMAGE_PULL_FAILED status and terminates them after timeout
-	// Validates: Requirements 5.1, 5.2, 5.3, 5.4
-	if err := app.setupResourceReleaser(); err != nil {
-		logger.WarnCtx(app.ctx, "Failed to setup resource releaser: %v (non-critical, continuing)", err)
-	}
-
 	return nil
 }
 
@@ -266,9 +621,17 @@ func (app *Application) setupPodWatcher(k8sProvider *k8s.K8sDeploymentProvider)
 
 	// Register Pod terminating callback
 	err := k8sProvider.WatchPodTerminating(app.ctx, func(podName, endpoint string) {
+		if !app.isLeader() {
+			return
+		}
 		logger.InfoCtx(app.ctx, "🔔 Pod %s (endpoint: %s) marked for deletion, draining worker...",
 			podName, endpoint)
 
+		// Ship the remaining log lines while the pod (and its logs) still exist
+		if app.logService != nil {
+			go app.logService.FlushWorker(app.ctx, endpoint, podName)
+		}
+
 		// 1. Find Worker by PodName
 		worker, err := app.workerService.GetWorkerByPodName(app.ctx, endpoint, podName)
 		if err != nil {
@@ -318,6 +681,9 @@ func (app *Application) setupSpotInterruptionWatcher(k8sProvider *k8s.K8sDeploym
 	logger.InfoCtx(app.ctx, "Setting up spot interruption watcher...")
 
 	err := k8sProvider.WatchSpotInterruption(app.ctx, func(podName, endpoint, reason string) {
+		if !app.isLeader() {
+			return
+		}
 		logger.WarnCtx(app.ctx, "🚨 SPOT INTERRUPTION detected for Pod %s (endpoint: %s), reason: %s",
 			podName, endpoint, reason)
 
@@ -360,17 +726,78 @@ func (app *Application) setupSpotInterruptionWatcher(k8sProvider *k8s.K8sDeploym
 	return nil
 }
 
-// setupNovitaStatusWatcher sets up Novita status watcher for endpoint status sync
-func (app *Application) setupNovitaStatusWatcher(novitaProvider *novita.NovitaDeploymentProvider) error {
-	if novitaProvider == nil {
-		logger.InfoCtx(app.ctx, "Novita provider not available, skipping status watcher setup")
+// setupNodeMaintenanceWatcher sets up detection of worker pods removed by node maintenance
+// (kubectl drain, NoExecute taints, graceful node shutdown). The pod watcher already drains the
+// worker; this records node maintenance as the drain reason and warns when the evicting component
+// shortened the grace period below the task timeout.
+func (app *Application) setupNodeMaintenanceWatcher(k8sProvider *k8s.K8sDeploymentProvider) error {
+	if k8sProvider == nil {
+		logger.InfoCtx(app.ctx, "K8s provider not available, skipping node maintenance watcher setup")
 		return nil
 	}
 
-	logger.InfoCtx(app.ctx, "Setting up Novita status watcher for endpoint status sync...")
+	logger.InfoCtx(app.ctx, "Setting up node maintenance watcher...")
+
+	err := k8sProvider.WatchNodeMaintenance(app.ctx, func(podName, endpoint string, maintenance *k8s.NodeMaintenance) {
+		if !app.isLeader() {
+			return
+		}
+		reason := maintenance.Reason()
+		logger.WarnCtx(app.ctx, "🔧 NODE MAINTENANCE for Pod %s (endpoint: %s): %s", podName, endpoint, reason)
+
+		worker, err := app.workerService.GetWorkerByPodName(app.ctx, endpoint, podName)
+		if err != nil {
+			logger.WarnCtx(app.ctx, "Node maintenance detected but worker not found for pod %s: %v", podName, err)
+			return
+		}
+
+		// Mark Worker as DRAINING with node maintenance as the reason shown in its status
+		if err := app.workerService.DrainWorker(app.ctx, worker.ID, reason); err != nil {
+			logger.ErrorCtx(app.ctx, "Failed to mark worker %s as draining for node maintenance: %v", worker.ID, err)
+			return
+		}
+
+		logger.InfoCtx(app.ctx, "✅ Worker %s (Pod: %s) marked as DRAINING due to node maintenance", worker.ID, podName)
+
+		if len(worker.JobsInProgress) > 0 {
+			if maintenance.Shortened() {
+				logger.WarnCtx(app.ctx, "⚠️ Worker %s has %d jobs in progress but the drain allows only %s of the %s grace period: %v",
+					worker.ID, len(worker.JobsInProgress), maintenance.Grace, maintenance.FullGrace, worker.JobsInProgress)
+			} else {
+				logger.InfoCtx(app.ctx, "📋 Worker %s has %d jobs in progress that can finish within %s: %v",
+					worker.ID, len(worker.JobsInProgress), maintenance.Grace, worker.JobsInProgress)
+			}
+		}
+
+		if err := k8sProvider.MarkPodDraining(app.ctx, podName); err != nil {
+			logger.WarnCtx(app.ctx, "Failed to mark pod %s as draining: %v", podName, err)
+		}
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to setup node maintenance watcher: %w", err)
+	}
+
+	logger.InfoCtx(app.ctx, "✅ Node maintenance watcher setup complete")
+	return nil
+}
+
+// pollingWatchProvider is a serverless provider (Novita, RunPod) that polls its API for replica and worker changes
+type pollingWatchProvider interface {
+	WatchReplicas(ctx context.Context, callback interfaces.ReplicaCallback) error
+	WatchPodStatusChange(ctx context.Context, callback func(workerID, endpoint string, info *interfaces.PodInfo)) error
+	WatchPodDelete(ctx context.Context, callback func(workerID, endpoint string)) error
+}
+
+// setupPollingStatusWatcher sets up a serverless provider's status watcher for endpoint status sync
+func (app *Application) setupPollingStatusWatcher(name string, pollingProvider pollingWatchProvider) error {
+	logger.InfoCtx(app.ctx, "Setting up %s status watcher for endpoint status sync...", name)
 
 	// Register replica watch callback to sync status to database
-	err := novitaProvider.WatchReplicas(app.ctx, func(event interfaces.ReplicaEvent) {
+	err := pollingProvider.WatchReplicas(app.ctx, func(event interfaces.ReplicaEvent) {
+		if !app.isLeader() {
+			return
+		}
 		endpoint := event.Name
 
 		// Calculate status based on replica state
@@ -390,37 +817,38 @@ func (app *Application) setupNovitaStatusWatcher(novitaProvider *novita.NovitaDe
 			}
 
 			if err := app.mysqlRepo.Endpoint.UpdateRuntimeState(app.ctx, endpoint, status, runtimeState); err != nil {
-				logger.ErrorCtx(app.ctx, "Failed to update Novita endpoint runtime state: %v", err)
+				logger.ErrorCtx(app.ctx, "Failed to update %s endpoint runtime state: %v", name, err)
 			}
 		}
+
+		app.triggerHealthRecompute(endpoint, resource.HealthTriggerDeploymentStatus)
 	})
 
 	if err != nil {
-		logger.WarnCtx(app.ctx, "Failed to register Novita status watcher: %v", err)
+		logger.WarnCtx(app.ctx, "Failed to register %s status watcher: %v", name, err)
 		return err
 	}
 
 	return nil
 }
 
-// setupNovitaPodStatusWatcher syncs Novita worker runtime state to worker table
-func (app *Application) setupNovitaPodStatusWatcher(novitaProvider *novita.NovitaDeploymentProvider) error {
-	if novitaProvider == nil {
-		return nil
-	}
-
-	logger.InfoCtx(app.ctx, "Setting up Novita pod status watcher for worker runtime sync...")
+// setupPollingPodStatusWatcher syncs a serverless provider's worker runtime state to worker table
+func (app *Application) setupPollingPodStatusWatcher(name string, pollingProvider pollingWatchProvider) error {
+	logger.InfoCtx(app.ctx, "Setting up %s pod status watcher for worker runtime sync...", name)
 
 	// Watch worker status changes
-	err := novitaProvider.WatchPodStatusChange(app.ctx, func(workerID, endpoint string, info *interfaces.PodInfo) {
-		// For Novita, workerID is the Novita Worker ID (used as podName)
+	err := pollingProvider.WatchPodStatusChange(app.ctx, func(workerID, endpoint string, info *interfaces.PodInfo) {
+		if !app.isLeader() {
+			return
+		}
+		// For serverless providers, workerID is the provider's Worker ID (used as podName)
 		podName := workerID
 
 		// Check if worker already exists in database
 		existingWorker, _ := app.mysqlRepo.Worker.GetByPodName(app.ctx, endpoint, podName)
 		isNewWorker := existingWorker == nil
 
-		// Parse timestamps from PodInfo (Novita provider generates these locally)
+		// Parse timestamps from PodInfo (serverless providers generate these locally)
 		var createdAt, startedAt *time.Time
 		if info.CreatedAt != "" {
 			if t, err := time.Parse(time.RFC3339, info.CreatedAt); err == nil {
@@ -434,7 +862,7 @@ func (app *Application) setupNovitaPodStatusWatcher(novitaProvider *novita.Novit
 		}
 		logger.WarnCtx(app.ctx, "existingWorker: %v", existingWorker)
 		// If worker already exists in database, preserve existing timestamps
-		// This handles the case where Novita provider restarts and loses in-memory state
+		// This handles the case where the provider restarts and loses in-memory state
 		// We should not overwrite billing timestamps that were already recorded
 		if existingWorker != nil {
 			if existingWorker.PodCreatedAt != nil {
@@ -446,9 +874,9 @@ func (app *Application) setupNovitaPodStatusWatcher(novitaProvider *novita.Novit
 		}
 
 		// Create or update worker (status STARTING until heartbeat)
-		// Novita doesn't provide IP/NodeName, but now we have timestamps for billing
-		if err := app.mysqlRepo.Worker.UpsertFromPod(app.ctx, podName, endpoint, info.Phase, info.Status, info.Reason, info.Message, "", "", createdAt, startedAt); err != nil {
-			logger.WarnCtx(app.ctx, "Failed to upsert worker from Novita worker %s: %v", workerID, err)
+		// Serverless providers don't provide IP/NodeName/image digest, but now we have timestamps for billing
+		if err := app.mysqlRepo.Worker.UpsertFromPod(app.ctx, podName, endpoint, info.Phase, info.Status, info.Reason, info.Message, "", "", "", "", createdAt, startedAt); err != nil {
+			logger.WarnCtx(app.ctx, "Failed to upsert worker from %s worker %s: %v", name, workerID, err)
 		}
 
 		// Record WORKER_STARTED event for new workers only
@@ -458,25 +886,29 @@ func (app *Application) setupNovitaPodStatusWatcher(novitaProvider *novita.Novit
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to setup Novita pod status watcher: %w", err)
+		return fmt.Errorf("failed to setup %s pod status watcher: %w", name, err)
 	}
 
 	// Watch worker deletions to mark workers as OFFLINE
-	err = novitaProvider.WatchPodDelete(app.ctx, func(workerID, endpoint string) {
+	err = pollingProvider.WatchPodDelete(app.ctx, func(workerID, endpoint string) {
+		if !app.isLeader() {
+			return
+		}
 		podName := workerID
 		// Record WORKER_OFFLINE event before marking offline
 		if app.workerEventService != nil {
 			app.workerEventService.RecordWorkerOffline(app.ctx, workerID, endpoint, podName)
 		}
 		if err := app.mysqlRepo.Worker.MarkOfflineByPodName(app.ctx, podName); err != nil {
-			logger.WarnCtx(app.ctx, "Failed to mark worker offline for deleted Novita worker %s: %v", workerID, err)
+			logger.WarnCtx(app.ctx, "Failed to mark worker offline for deleted %s worker %s: %v", name, workerID, err)
 		}
+		app.triggerHealthRecompute(endpoint, resource.HealthTriggerWorkerDeleted)
 	})
 	if err != nil {
-		logger.WarnCtx(app.ctx, "Failed to setup Novita pod delete watcher: %v", err)
+		logger.WarnCtx(app.ctx, "Failed to setup %s pod delete watcher: %v", name, err)
 	}
 
-	logger.InfoCtx(app.ctx, "Novita pod status watcher registered successfully")
+	logger.InfoCtx(app.ctx, "%s pod status watcher registered successfully", name)
 	return nil
 }
 
@@ -493,6 +925,9 @@ func (app *Application) setupDeploymentWatcher(k8sProvider *k8s.K8sDeploymentPro
 
 	// Register Deployment spec change callback
 	err := k8sProvider.WatchDeploymentSpecChange(app.ctx, func(endpoint string) {
+		if !app.isLeader() {
+			return
+		}
 		logger.InfoCtx(app.ctx, "🔄 Deployment spec changed for endpoint %s, setting pod deletion priorities...", endpoint)
 
 		// Get all workers for this endpoint
@@ -552,6 +987,9 @@ func (app *Application) setupDeploymentWatcher(k8sProvider *k8s.K8sDeploymentPro
 
 	// Register deployment status change callback to sync status to database
 	err = k8sProvider.WatchDeploymentStatusChange(app.ctx, func(endpoint string, deployment *appsv1.Deployment) {
+		if !app.isLeader() {
+			return
+		}
 		// Calculate status
 		status := "Pending"
 		if deployment.Status.AvailableReplicas == *deployment.Spec.Replicas && *deployment.Spec.Replicas > 0 {
@@ -571,11 +1009,16 @@ func (app *Application) setupDeploymentWatcher(k8sProvider *k8s.K8sDeploymentPro
 				"readyReplicas":     deployment.Status.ReadyReplicas,
 				"availableReplicas": deployment.Status.AvailableReplicas,
 			}
-			// Extract shmSize and volumeMounts
+			// Extract shmSize, ephemeralStorage and volumeMounts
 			if info := k8s.DeploymentToAppInfo(deployment); info != nil {
 				if info.ShmSize != "" {
 					runtimeState["shmSize"] = info.ShmSize
 				}
+				if info.EphemeralStorage != "" {
+					runtimeState["ephemeralStorage"] = info.EphemeralStorage
+				}
+				// Always written so that removing the override clears it (runtime state is merged)
+				runtimeState["specOverride"] = info.SpecOverride
 				if len(info.VolumeMounts) > 0 {
 					runtimeState["volumeMounts"] = info.VolumeMounts
 				}
@@ -584,6 +1027,8 @@ func (app *Application) setupDeploymentWatcher(k8sProvider *k8s.K8sDeploymentPro
 				logger.ErrorCtx(app.ctx, "Failed to update endpoint runtime state: %v", err)
 			}
 		}
+
+		app.triggerHealthRecompute(endpoint, resource.HealthTriggerDeploymentStatus)
 	})
 
 	if err != nil {
@@ -598,22 +1043,124 @@ func (app *Application) setupDeploymentWatcher(k8sProvider *k8s.K8sDeploymentPro
 func (app *Application) initHandlers() error {
 	// Initialize handlers
 	app.taskHandler = handler.NewTaskHandler(app.taskService, app.workerService)
+	app.taskHandler.SetWakeService(app.wakeService)
 	app.workerHandler = handler.NewWorkerHandler(app.workerService, app.taskService, app.deploymentProvider)
+	app.workerHandler.SetWorkerEventService(app.workerEventService)
+	app.workerHandler.SetHandshakeService(app.handshakeService)
+	app.workerHandler.SetBroadcastService(app.broadcastService)
+	app.workerHandler.SetQuarantineService(app.quarantineService)
 	app.statisticsHandler = handler.NewStatisticsHandler(app.statisticsService, app.workerService)
 	app.monitoringHandler = handler.NewMonitoringHandler(app.monitoringService)
+	if app.anomalyService != nil {
+		app.monitoringHandler.SetAnomalyService(app.anomalyService)
+	}
+	if app.quotaService != nil {
+		app.monitoringHandler.SetQuotaService(app.quotaService)
+	}
+	app.gpuUsageHandler = handler.NewGPUUsageHandler(app.gpuUsageService)
+	app.gpuUsageHandler.SetOperationService(app.operationService)
+	app.mirrorHandler = handler.NewRegistryMirrorHandler(app.mirrorService)
+	app.drHandler = handler.NewDisasterRecoveryHandler(app.drService)
+	app.drHandler.SetBundleService(service.NewControlPlaneBundleService(app.config, app.specService))
+	app.groupHandler = handler.NewEndpointGroupHandler(app.groupService)
+	app.reservationHandler = handler.NewGPUReservationHandler(app.reservationService)
+	app.federationHandler = handler.NewFederationHandler(app.federationService)
+	app.gpuTierHandler = handler.NewGPUTierHandler(app.gpuTierService)
+	app.batchJobHandler = handler.NewBatchJobHandler(app.batchJobService)
+	app.scheduleHandler = handler.NewJobScheduleHandler(app.scheduleService)
+	app.operationHandler = handler.NewOperationHandler(app.operationService)
+	if app.loadTestService != nil {
+		app.loadTestHandler = handler.NewLoadTestHandler(app.loadTestService)
+	}
+	app.samplingHandler = handler.NewSamplingHandler(app.samplingService)
+	app.transformHandler = handler.NewTransformHandler(app.transformService)
+	app.redactionHandler = handler.NewLogRedactionHandler(app.redactionService)
+	if app.encryptionService != nil {
+		app.encryptionHandler = handler.NewEncryptionHandler(app.encryptionService)
+	}
+	app.deletionHandler = handler.NewDataDeletionHandler(app.deletionService)
+	app.replayHandler = handler.NewTaskReplayHandler(app.replayService)
+	if app.statusPageService != nil {
+		app.statusPageHandler = handler.NewStatusPageHandler(app.statusPageService)
+	}
+	if app.hedgingService != nil {
+		app.hedgingHandler = handler.NewHedgingHandler(app.hedgingService)
+	}
+	app.circuitHandler = handler.NewCircuitBreakerHandler(app.circuitBreaker)
+	app.changeHandler = handler.NewChangeRequestHandler(app.changeService)
+	if app.config.Approval.Enabled {
+		// Endpoint settings that change what production runs are gated too, as are pause/resume
+		// and closing the circuit. Worker config and broadcasts are not: they expire or are undone.
+		app.transformHandler.SetChangeRequestService(app.changeService)
+		app.gpuTierHandler.SetChangeRequestService(app.changeService)
+		app.samplingHandler.SetChangeRequestService(app.changeService)
+		app.redactionHandler.SetChangeRequestService(app.changeService)
+		app.circuitHandler.SetChangeRequestService(app.changeService)
+	}
+	app.integrationHandler = handler.NewIntegrationHandler(app.integrationService)
+	if novitaProv, ok := app.providers.Get("novita").(*novita.NovitaDeploymentProvider); ok {
+		app.novitaHandler = handler.NewNovitaHandler(novitaProv)
+	}
 
-	// Initialize Endpoint Handler (for K8s or Novita)
-	if app.config.K8s.Enabled || app.config.Novita.Enabled {
+	// Read-only switch, shared through Redis so a toggle reaches every replica
+	var readOnlyStore maintenance.Store = maintenance.NewMemoryStore()
+	if app.redisClient != nil && app.redisClient.GetClient() != nil {
+		readOnlyStore = maintenance.NewRedisStore(app.redisClient.GetClient())
+	}
+	app.readOnlySwitch = maintenance.NewSwitch(app.config.Maintenance, readOnlyStore)
+	if err := app.readOnlySwitch.Refresh(app.ctx); err != nil {
+		logger.WarnCtx(app.ctx, "Failed to load read-only state: %v", err)
+	}
+	go app.readOnlySwitch.Run(app.ctx)
+	app.maintHandler = handler.NewMaintenanceHandler(app.readOnlySwitch)
+
+	// Emergency brake, shared the same way; the autoscaler picks it up in initAutoScaler
+	var brakeStore maintenance.BrakeStore = maintenance.NewMemoryBrakeStore()
+	if app.redisClient != nil && app.redisClient.GetClient() != nil {
+		brakeStore = maintenance.NewRedisBrakeStore(app.redisClient.GetClient())
+	}
+	app.emergencyBrake = maintenance.NewBrake(app.config.Maintenance, brakeStore)
+	if err := app.emergencyBrake.Refresh(app.ctx); err != nil {
+		logger.WarnCtx(app.ctx, "Failed to load emergency brake: %v", err)
+	}
+	go app.emergencyBrake.Run(app.ctx)
+	app.endpointService.SetRolloutGuard(app.emergencyBrake.CheckRollout)
+	app.maintHandler.SetEmergencyBrakeService(service.NewEmergencyBrakeService(app.emergencyBrake, app.endpointService))
+	app.maintHandler.SetWorkerIntegrityService(app.integrityService)
+	if app.logService != nil {
+		app.logHandler = handler.NewLogHandler(app.logService)
+	}
+
+	// Initialize Endpoint Handler (for K8s, Novita or RunPod)
+	if app.config.K8s.Enabled || app.config.Novita.Enabled || app.config.RunPod.Enabled {
 		if app.deploymentProvider == nil {
 			logger.ErrorCtx(app.ctx, "Deployment provider is enabled but provider is nil")
 		} else {
 			app.endpointHandler = handler.NewEndpointHandler(app.deploymentProvider, app.endpointService, app.workerService)
+			if app.config.DataPlane.TokenSecret != "" {
+				signer, err := dataplane.NewSigner(app.config.DataPlane.TokenSecret)
+				if err != nil {
+					return fmt.Errorf("invalid data plane config: %w", err)
+				}
+				app.endpointHandler.SetInvokeSigner(signer, app.config.DataPlane.TokenTTL)
+			}
+			app.endpointHandler.SetLogRedactionService(app.redactionService)
+			app.endpointHandler.SetLifecycleHookService(app.hookService)
+			app.endpointHandler.SetSnapshotService(service.NewEndpointSnapshotService(app.endpointService, app.mysqlRepo, app.deploymentProvider))
+			app.endpointHandler.SetOperationService(app.operationService)
+			if app.config.Approval.Enabled {
+				app.endpointHandler.SetChangeRequestService(app.changeService)
+				logger.InfoCtx(app.ctx, "Approval required for endpoints labeled %v", app.config.Approval.Labels)
+			}
 			if app.config.K8s.Enabled {
 				logger.InfoCtx(app.ctx, "Endpoint handler initialized for K8s")
 			}
 			if app.config.Novita.Enabled {
 				logger.InfoCtx(app.ctx, "Endpoint handler initialized for Novita")
 			}
+			if app.config.RunPod.Enabled {
+				logger.InfoCtx(app.ctx, "Endpoint handler initialized for RunPod")
+			}
 		}
 	}
 
@@ -626,6 +1173,7 @@ func (app *Application) initHandlers() error {
 	// Initialize Image Handler (for DockerHub webhook and image update checking)
 	if app.endpointService != nil {
 		app.imageHandler = handler.NewImageHandler(app.endpointService, &app.config.Docker)
+		app.imageHandler.SetIntegrationService(app.integrationService)
 		logger.InfoCtx(app.ctx, "Image handler initialized")
 	}
 
@@ -646,7 +1194,7 @@ func (app *Application) initAutoScaler() error {
 
 	// Get spec manager from K8s deployment provider
 	var specManager *k8s.SpecManager
-	if k8sProvider, ok := app.deploymentProvider.(*k8s.K8sDeploymentProvider); ok {
+	if k8sProvider, ok := app.providers.Get("k8s").(*k8s.K8sDeploymentProvider); ok {
 		specManager = k8sProvider.GetSpecManager()
 		// Inject spec service into spec manager for database access
 		// SpecService implements SpecRepositoryInterface
@@ -679,8 +1227,25 @@ func (app *Application) initAutoScaler() error {
 		specManager,
 		app.mysqlRepo.Endpoint,
 	)
+	app.autoscalerMgr.SetEndpointGroupRepository(app.mysqlRepo.EndpointGroup)
+	app.autoscalerMgr.SetGPUReservationRepository(app.mysqlRepo.GPUReservation)
+	app.autoscalerMgr.SetEmergencyBrake(app.emergencyBrake)
+	app.autoscalerMgr.SetProviderRateLimit(app.config.AutoScaler.ProviderQPS, app.config.AutoScaler.ProviderBurst)
+	app.autoscalerMgr.SetStartupModel(autoscaler.NewStartupModel(app.mysqlRepo.Monitoring))
+	app.autoscalerMgr.SetMetricReader(interfaces.MetricSourceLatency, autoscaler.NewLatencyReader(app.mysqlRepo.Monitoring, app.config.AutoScaler.Metrics.LatencyWindow))
+	if metricsCfg := app.config.AutoScaler.Metrics; metricsCfg.PrometheusURL != "" {
+		promReader := autoscaler.NewPrometheusReader(autoscaler.NewPrometheusClient(metricsCfg.PrometheusURL, metricsCfg.QueryTimeout), app.config.K8s.Namespace, metricsCfg.GPUUtilizationQuery)
+		app.autoscalerMgr.SetMetricReader(interfaces.MetricSourceGPUUtilization, promReader)
+		app.autoscalerMgr.SetMetricReader(interfaces.MetricSourcePrometheus, promReader)
+		logger.InfoCtx(app.ctx, "Autoscaler metric sources read from Prometheus at %s", metricsCfg.PrometheusURL)
+	}
+	// A no-op on replicas where the autoscaler doesn't run
+	app.wakeSignals.OnSignal(app.autoscalerMgr.Wake)
 
 	app.autoscalerHandler = handler.NewAutoScalerHandler(app.autoscalerMgr, app.endpointService)
+	if app.config.Approval.Enabled {
+		app.autoscalerHandler.SetChangeRequestService(app.changeService)
+	}
 
 	return nil
 }
@@ -702,6 +1267,9 @@ func (app *Application) setupPodStatusWatcher(k8sProvider *k8s.K8sDeploymentProv
 	failureDetector := k8s.NewK8sWorkerStatusMonitor(k8sProvider.GetManager(), app.mysqlRepo.Worker)
 
 	err := k8sProvider.WatchPodStatusChange(app.ctx, func(podName, endpoint string, info *interfaces.PodInfo) {
+		if !app.isLeader() {
+			return
+		}
 		var createdAt, startedAt *time.Time
 		if info.CreatedAt != "" {
 			if t, err := time.Parse(time.RFC3339, info.CreatedAt); err == nil {
@@ -719,7 +1287,7 @@ func (app *Application) setupPodStatusWatcher(k8sProvider *k8s.K8sDeploymentProv
 		isNewWorker := existingWorker == nil
 
 		// 1. Create or update worker (status STARTING until heartbeat)
-		if err := app.mysqlRepo.Worker.UpsertFromPod(app.ctx, podName, endpoint, info.Phase, info.Status, info.Reason, info.Message, info.IP, info.NodeName, createdAt, startedAt); err != nil {
+		if err := app.mysqlRepo.Worker.UpsertFromPod(app.ctx, podName, endpoint, info.Phase, info.Status, info.Reason, info.Message, info.IP, info.NodeName, info.ImageDigest, info.GPUType, createdAt, startedAt); err != nil {
 			logger.WarnCtx(app.ctx, "Failed to upsert worker from pod %s: %v", podName, err)
 		}
 
@@ -739,7 +1307,14 @@ func (app *Application) setupPodStatusWatcher(k8sProvider *k8s.K8sDeploymentProv
 				logger.ErrorCtx(app.ctx, "Failed to update worker failure: pod=%s, error=%v", podName, err)
 			} else {
 				logger.InfoCtx(app.ctx, "✅ Worker failure recorded in database: pod=%s, type=%s", podName, failureInfo.Type)
+				app.triggerHealthRecompute(endpoint, resource.HealthTriggerWorkerFailure)
 			}
+
+			// Evicted for disk usage: alert and optionally raise the ephemeral storage limit
+			app.diskPressureService.HandleFailure(app.ctx, endpoint, podName, failureInfo)
+		} else if existingWorker != nil && existingWorker.FailureType != "" {
+			// Previously failed worker reported a healthy status again
+			app.triggerHealthRecompute(endpoint, resource.HealthTriggerWorkerRecovered)
 		}
 	})
 
@@ -749,6 +1324,9 @@ func (app *Application) setupPodStatusWatcher(k8sProvider *k8s.K8sDeploymentProv
 
 	// Watch pod deletions to mark workers as OFFLINE
 	err = k8sProvider.WatchPodDelete(app.ctx, func(podName, endpoint string) {
+		if !app.isLeader() {
+			return
+		}
 		// Record WORKER_OFFLINE event before marking offline
 		if app.workerEventService != nil {
 			app.workerEventService.RecordWorkerOffline(app.ctx, podName, endpoint, podName)
@@ -756,6 +1334,7 @@ func (app *Application) setupPodStatusWatcher(k8sProvider *k8s.K8sDeploymentProv
 		if err := app.mysqlRepo.Worker.MarkOfflineByPodName(app.ctx, podName); err != nil {
 			logger.WarnCtx(app.ctx, "Failed to mark worker offline for deleted pod %s: %v", podName, err)
 		}
+		app.triggerHealthRecompute(endpoint, resource.HealthTriggerWorkerDeleted)
 	})
 	if err != nil {
 		logger.WarnCtx(app.ctx, "Failed to setup pod delete watcher: %v", err)
@@ -789,29 +1368,26 @@ func (app *Application) setupNovitaWorkerStatusMonitor(novitaProvider *novita.No
 	// Create the worker status monitor
 	statusMonitor := novita.NewNovitaWorkerStatusMonitor(client, app.mysqlRepo.Worker)
 
-	// Start the status monitor in a goroutine
+	// Run the status monitor on the leader replica
 	// It will poll for worker status changes and update worker failure information
-	go func() {
-		logger.InfoCtx(app.ctx, "Starting Novita worker status monitor...")
+	app.registerLeaderController("novita-worker-status-monitor", func(ctx context.Context) {
+		logger.InfoCtx(ctx, "Starting Novita worker status monitor...")
 
 		// The callback is invoked when a worker enters a failed state
 		// The monitor already updates the database internally, but we can add additional logging here
-		err := statusMonitor.WatchWorkerStatus(app.ctx, func(workerID, endpoint string, info *interfaces.WorkerFailureInfo) {
-			logger.WarnCtx(app.ctx, "🚨 Novita worker failure detected and recorded: worker=%s, endpoint=%s, type=%s, reason=%s",
+		err := statusMonitor.WatchWorkerStatus(ctx, func(workerID, endpoint string, info *interfaces.WorkerFailureInfo) {
+			logger.WarnCtx(ctx, "🚨 Novita worker failure detected and recorded: worker=%s, endpoint=%s, type=%s, reason=%s",
 				workerID, endpoint, info.Type, info.SanitizedMsg)
 
-			// Additional actions can be added here in the future:
-			// - Send alerts/notifications
-			// - Update endpoint health status
-			// - Trigger resource cleanup
+			app.triggerHealthRecompute(endpoint, resource.HealthTriggerWorkerFailure)
 		})
 
 		if err != nil && err != context.Canceled {
-			logger.ErrorCtx(app.ctx, "Novita worker status monitor stopped with error: %v", err)
+			logger.ErrorCtx(ctx, "Novita worker status monitor stopped with error: %v", err)
 		} else {
-			logger.InfoCtx(app.ctx, "Novita worker status monitor stopped gracefully")
+			logger.InfoCtx(ctx, "Novita worker status monitor stopped gracefully")
 		}
-	}()
+	})
 
 	logger.InfoCtx(app.ctx, "✅ Novita worker status monitor setup completed")
 	return nil
@@ -848,6 +1424,10 @@ func (app *Application) setupResourceReleaser() error {
 	if app.config.ResourceReleaser.MaxRetries > 0 {
 		releaserConfig.MaxRetries = app.config.ResourceReleaser.MaxRetries
 	}
+	if app.config.ResourceReleaser.HealthRecomputeDebounce > 0 {
+		releaserConfig.HealthRecomputeDebounce = app.config.ResourceReleaser.HealthRecomputeDebounce
+	}
+	releaserConfig.AvoidUnhealthyGPUNodes = app.config.ResourceReleaser.AvoidUnhealthyGPUNodes
 
 	// Create the resource releaser
 	releaser := resource.NewResourceReleaser(
@@ -857,21 +1437,89 @@ func (app *Application) setupResourceReleaser() error {
 		releaserConfig,
 	)
 
-	// Start the releaser in a goroutine
-	go func() {
-		logger.InfoCtx(app.ctx, "Starting resource releaser with config: imagePullTimeout=%v, checkInterval=%v, maxRetries=%d",
+	// Notify on health transitions (event-triggered and periodic recomputes)
+	notifier := notification.NewFeishuNotifier()
+	releaser.SetHealthChangeHandler(func(ctx context.Context, event *resource.HealthChangeEvent) {
+		healthNotification := &notification.EndpointHealthNotification{
+			Endpoint:       event.Endpoint,
+			PreviousStatus: event.PreviousStatus,
+			Status:         event.Status,
+			Reason:         event.Reason,
+			Message:        event.Message,
+			Trigger:        event.Trigger,
+			ChangedAt:      event.ChangedAt,
+		}
+		go func() {
+			if err := notifier.SendEndpointHealthNotification(context.Background(), healthNotification); err != nil {
+				logger.WarnCtx(ctx, "Failed to send health change notification for endpoint %s: %v", event.Endpoint, err)
+			}
+		}()
+		app.integrationService.Publish(ctx, &notification.Event{
+			Type:      notification.EventEndpointHealth,
+			Endpoint:  event.Endpoint,
+			CreatedAt: event.ChangedAt,
+			Data:      healthNotification,
+		})
+	})
+	app.resourceReleaser = releaser
+
+	// Run the releaser on the leader replica (restarted on the new leader after failover)
+	app.registerLeaderController("resource-releaser", func(ctx context.Context) {
+		logger.InfoCtx(ctx, "Starting resource releaser with config: imagePullTimeout=%v, checkInterval=%v, maxRetries=%d",
 			releaserConfig.ImagePullTimeout, releaserConfig.CheckInterval, releaserConfig.MaxRetries)
-		releaser.Start(app.ctx)
-	}()
+		releaser.Start(ctx)
+	})
 
 	logger.InfoCtx(app.ctx, "✅ Resource releaser setup completed")
 	return nil
 }
 
+// triggerHealthRecompute schedules a debounced endpoint health recompute if the releaser is available.
+func (app *Application) triggerHealthRecompute(endpoint, trigger string) {
+	if app.resourceReleaser == nil {
+		return
+	}
+	app.resourceReleaser.TriggerHealthRecompute(endpoint, trigger)
+}
+
 // initHTTPServer initializes HTTP server
 func (app *Application) initHTTPServer() error {
 	// Initialize router
-	r := router.NewRouter(app.taskHandler, app.workerHandler, app.endpointHandler, app.autoscalerHandler, app.statisticsHandler, app.specHandler, app.imageHandler, app.monitoringHandler)
+	r := router.NewRouter(router.Handlers{
+		TaskHandler:        app.taskHandler,
+		WorkerHandler:      app.workerHandler,
+		EndpointHandler:    app.endpointHandler,
+		AutoscalerHandler:  app.autoscalerHandler,
+		StatisticsHandler:  app.statisticsHandler,
+		SpecHandler:        app.specHandler,
+		ImageHandler:       app.imageHandler,
+		MonitoringHandler:  app.monitoringHandler,
+		GPUUsageHandler:    app.gpuUsageHandler,
+		MirrorHandler:      app.mirrorHandler,
+		LogHandler:         app.logHandler,
+		DRHandler:          app.drHandler,
+		MaintHandler:       app.maintHandler,
+		GroupHandler:       app.groupHandler,
+		ReservationHandler: app.reservationHandler,
+		FederationHandler:  app.federationHandler,
+		SamplingHandler:    app.samplingHandler,
+		TransformHandler:   app.transformHandler,
+		RedactionHandler:   app.redactionHandler,
+		EncryptionHandler:  app.encryptionHandler,
+		DeletionHandler:    app.deletionHandler,
+		ReplayHandler:      app.replayHandler,
+		StatusPageHandler:  app.statusPageHandler,
+		HedgingHandler:     app.hedgingHandler,
+		CircuitHandler:     app.circuitHandler,
+		ChangeHandler:      app.changeHandler,
+		IntegrationHandler: app.integrationHandler,
+		NovitaHandler:      app.novitaHandler,
+		GPUTierHandler:     app.gpuTierHandler,
+		BatchJobHandler:    app.batchJobHandler,
+		ScheduleHandler:    app.scheduleHandler,
+		OperationHandler:   app.operationHandler,
+		LoadTestHandler:    app.loadTestHandler,
+	}, app.readOnlySwitch)
 
 	// Set Gin mode
 	gin.SetMode(app.config.Server.Mode)
@@ -999,6 +1647,162 @@ func createEC2Client(ctx context.Context, awsCfg *config.AWSConfig) (*ec2.Client
 	return ec2.NewFromConfig(cfg), cfg.Region, nil
 }
 
+// createSampler creates and starts the task sampler, nil if sampling is disabled or misconfigured
+// setupTaskEncryption installs the task payload cipher when encryption is enabled. Payloads are
+// decrypted on read, so the KMS configuration must stay in place while encrypted rows exist.
+func (app *Application) setupTaskEncryption() error {
+	cfg := &app.config.Encryption
+	if !cfg.Enabled {
+		return nil
+	}
+
+	var kms envelope.KMS
+	if cfg.Vault.Address != "" {
+		kms = envelope.NewVaultTransit(cfg.Vault.Address, cfg.Vault.Token, cfg.Vault.Mount, cfg.Vault.KeyPrefix)
+	} else {
+		local, err := envelope.NewLocalKMS(cfg.MasterKey)
+		if err != nil {
+			return err
+		}
+		kms = local
+	}
+
+	app.encryptionService = service.NewTaskEncryptionService(kms, app.mysqlRepo.EncryptionKey, app.mysqlRepo.Endpoint, cfg.ProjectLabel)
+	app.mysqlRepo.Task.SetPayloadCipher(app.encryptionService)
+	app.samplingService.SetEncryptionService(app.encryptionService)
+
+	logger.InfoCtx(app.ctx, "task payload encryption enabled (kms: %s, project label: %s)", kms.Name(), cfg.ProjectLabel)
+	return nil
+}
+
+func (app *Application) setupDiscovery() error {
+	cfg := &app.config.Discovery
+	if cfg.Mode == "" {
+		return nil
+	}
+
+	address, err := discovery.ParseAddress(cfg.Address)
+	if err != nil {
+		return err
+	}
+
+	var registrar discovery.Registrar
+	switch cfg.Mode {
+	case config.DiscoveryModeConsul:
+		registrar = discovery.NewConsulRegistrar(cfg.Consul.Address, cfg.Consul.Token, cfg.Consul.Tags, address)
+	case config.DiscoveryModeExternalDNS:
+		k8sProv, ok := app.providers.Get("k8s").(*k8s.K8sDeploymentProvider)
+		if !ok {
+			return fmt.Errorf("discovery mode %s requires the k8s deployment provider", cfg.Mode)
+		}
+		registrar, err = discovery.NewExternalDNSRegistrar(k8sProv.GetClientset(), k8sProv.GetNamespace(), cfg.ExternalDNS.Domain, cfg.ExternalDNS.TTL, address)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid discovery mode %q: must be %s or %s", cfg.Mode, config.DiscoveryModeConsul, config.DiscoveryModeExternalDNS)
+	}
+	app.endpointService.SetDiscovery(registrar)
+
+	// Register endpoints that existed before discovery was enabled
+	if app.config.Coordination.RunsControllers() {
+		go func() {
+			registered, err := app.endpointService.SyncDiscovery(app.ctx)
+			if err != nil {
+				logger.WarnCtx(app.ctx, "Failed to sync endpoints to %s: %v", registrar.Name(), err)
+				return
+			}
+			logger.InfoCtx(app.ctx, "Synced %d endpoints to %s", registered, registrar.Name())
+		}()
+	}
+
+	logger.InfoCtx(app.ctx, "service discovery enabled (mode: %s, address: %s)", registrar.Name(), address.URL)
+	return nil
+}
+
+func (app *Application) setupModelRegistry() error {
+	cfg := &app.config.ModelRegistry
+	if cfg.Kind == "" {
+		return nil
+	}
+	if cfg.Address == "" {
+		return fmt.Errorf("model registry %s requires an address", cfg.Kind)
+	}
+
+	var registry modelregistry.Registry
+	switch cfg.Kind {
+	case config.ModelRegistryMLflow:
+		registry = modelregistry.NewMLflowRegistry(cfg.Address, cfg.Token, cfg.ApprovedStage, cfg.ApprovedAlias)
+	case config.ModelRegistryHTTP:
+		registry = modelregistry.NewHTTPRegistry(cfg.Address, cfg.Token)
+	default:
+		return fmt.Errorf("invalid model registry kind %q: must be %s or %s", cfg.Kind, config.ModelRegistryMLflow, config.ModelRegistryHTTP)
+	}
+	app.endpointService.SetModelRegistry(registry)
+
+	logger.InfoCtx(app.ctx, "model registry enabled (kind: %s, address: %s)", registry.Name(), cfg.Address)
+	return nil
+}
+
+func (app *Application) createSampler() (*sampling.Sampler, export.ObjectStore) {
+	cfg := &app.config.Sampling
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	sink, err := createExportSink(app.ctx, &config.ExportConfig{Sink: cfg.Store, Local: cfg.Local, S3: cfg.S3})
+	if err != nil {
+		logger.ErrorCtx(app.ctx, "failed to create sampling store, task sampling disabled: %v", err)
+		return nil, nil
+	}
+	store, ok := sink.(export.ObjectStore)
+	if !ok {
+		logger.ErrorCtx(app.ctx, "sampling store %s does not support objects, task sampling disabled", sink.Name())
+		return nil, nil
+	}
+
+	patterns, unknown := sampling.NewPatternScrubber(cfg.Scrubbers)
+	if len(unknown) > 0 {
+		logger.WarnCtx(app.ctx, "unknown sampling scrubbers ignored: %v (available: %v)", unknown, sampling.BuiltinPatternNames())
+	}
+	sampler := sampling.NewSampler(store, sampling.Options{
+		BatchSize:     cfg.BatchSize,
+		FlushInterval: cfg.FlushInterval,
+		QueueSize:     cfg.QueueSize,
+	}, sampling.NewFieldScrubber(cfg.ScrubFields), patterns)
+	sampler.Start(app.ctx)
+	app.registerCleanup(sampler.Wait) // Flush buffered samples on shutdown
+
+	logger.InfoCtx(app.ctx, "task sampling enabled (store: %s)", store.Name())
+	return sampler, store
+}
+
+// createExportSink creates the analytics export sink from configuration
+func createExportSink(ctx context.Context, cfg *config.ExportConfig) (export.Sink, error) {
+	switch cfg.Sink {
+	case "local":
+		return export.NewLocalSink(cfg.Local.Dir)
+	case "s3":
+		var opts []func(*awsconfig.LoadOptions) error
+		if cfg.S3.Region != "" {
+			opts = append(opts, awsconfig.WithRegion(cfg.S3.Region))
+		}
+		if cfg.S3.AccessKeyID != "" && cfg.S3.SecretAccessKey != "" {
+			opts = append(opts, awsconfig.WithCredentialsProvider(
+				credentials.NewStaticCredentialsProvider(cfg.S3.AccessKeyID, cfg.S3.SecretAccessKey, ""),
+			))
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return export.NewS3Sink(cfg.S3.Bucket, awsCfg.Region, cfg.S3.Prefix, cfg.S3.Endpoint, awsCfg.Credentials)
+	case "bigquery":
+		return export.NewBigQuerySink(cfg.BigQuery.ProjectID, cfg.BigQuery.Dataset, cfg.BigQuery.TablePrefix, cfg.BigQuery.AccessToken)
+	default:
+		return nil, fmt.Errorf("unsupported export sink: %s", cfg.Sink)
+	}
+}
+
 // k8sPodCountAdapter adapts k8s provider to capacity.PodCountProvider
 type k8sPodCountAdapter struct {
 	provider *k8s.K8sDeploymentProvider
@@ -1019,3 +1823,16 @@ func (a *k8sPodCountAdapter) GetPodCountsBySpec(ctx context.Context) (map[string
 	}
 	return result, nil
 }
+
+// createLogSink creates the worker log shipping sink from configuration
+func createLogSink(cfg *config.LogShippingConfig) (logship.Sink, error) {
+	switch cfg.Backend {
+	case "loki":
+		return logship.NewLokiSink(cfg.Loki.URL, cfg.Loki.TenantID, cfg.Loki.Username, cfg.Loki.Password)
+	case "elasticsearch":
+		es := cfg.Elasticsearch
+		return logship.NewElasticsearchSink(es.URL, es.Index, es.Username, es.Password, es.APIKey)
+	default:
+		return nil, fmt.Errorf("unsupported log shipping backend: %s", cfg.Backend)
+	}
+}
@@ -9,8 +9,11 @@ import (
 
 	"waverless/internal/jobs"
 	"waverless/internal/service"
+	endpointsvc "waverless/internal/service/endpoint"
 	"waverless/pkg/autoscaler"
 	"waverless/pkg/deploy/k8s"
+	"waverless/pkg/export"
+	"waverless/pkg/journal"
 	"waverless/pkg/logger"
 	"waverless/pkg/monitoring"
 	mysqlstore "waverless/pkg/store/mysql"
@@ -22,6 +25,12 @@ func (app *Application) initJobs() error {
 		return nil
 	}
 
+	// API-only replicas serve requests only; background jobs run on controller replicas
+	if !app.config.Coordination.RunsControllers() {
+		logger.InfoCtx(app.ctx, "API replica, skipping background task registration")
+		return nil
+	}
+
 	manager := jobs.NewManager(app.ctx)
 
 	workerInterval := time.Duration(app.config.Worker.HeartbeatTimeout) * time.Second
@@ -66,6 +75,119 @@ func (app *Application) initJobs() error {
 		manager.Register(newDataRetentionCleanupJob(24*time.Hour, app.mysqlRepo, dataCleanupLock))
 	}
 
+	// Register GPU usage aggregation (minute -> hourly -> daily, driven by watermarks)
+	if app.gpuUsageService != nil {
+		gpuUsageAggLock := autoscaler.NewRedisDistributedLock(redisClient, "gpu-usage:aggregation-lock")
+		manager.Register(newGPUUsageAggregationJob(time.Minute, app.gpuUsageService, gpuUsageAggLock))
+
+		// Backfill jobs run in slices shorter than the interval; the cursor is saved after every batch
+		gpuUsageBackfillLock := autoscaler.NewRedisDistributedLock(redisClient, "gpu-usage:backfill-lock")
+		manager.Register(newGPUUsageBackfillJob(30*time.Second, 25*time.Second, app.gpuUsageService, gpuUsageBackfillLock))
+	}
+
+	// Register task replay jobs (slices shorter than the interval; the cursor is saved after every batch)
+	if app.replayService != nil {
+		replayLock := autoscaler.NewRedisDistributedLock(redisClient, "task-replay:lock")
+		manager.Register(newTaskReplayJob(30*time.Second, 25*time.Second, app.replayService, replayLock))
+	}
+
+	// Register hedged execution (duplicates overdue tasks of latency-critical endpoints)
+	if app.hedgingService != nil {
+		hedgingLock := autoscaler.NewRedisDistributedLock(redisClient, "hedging:lock")
+		manager.Register(newTaskHedgingJob(app.config.Hedging.Interval, app.hedgingService, hedgingLock))
+	}
+
+	// Register the endpoint circuit breaker (trips on failure storms, probes until a task succeeds)
+	if app.config.CircuitBreaker.Enabled {
+		circuitLock := autoscaler.NewRedisDistributedLock(redisClient, "circuit-breaker:lock")
+		manager.Register(newCircuitBreakerJob(app.config.CircuitBreaker.Interval, app.circuitBreaker, circuitLock))
+	}
+
+	// Register analytics export (ships raw records to object storage / BigQuery)
+	if app.config.Export.Enabled {
+		sink, err := createExportSink(app.ctx, &app.config.Export)
+		if err != nil {
+			logger.ErrorCtx(app.ctx, "failed to create export sink, analytics export disabled: %v", err)
+		} else {
+			exporter := export.NewExporter(app.mysqlRepo.Export, sink, app.config.Export.Datasets, app.config.Export.BatchSize)
+			exportLock := autoscaler.NewRedisDistributedLock(redisClient, "export:analytics-lock")
+			manager.Register(newAnalyticsExportJob(app.config.Export.Interval, exporter, exportLock))
+			logger.InfoCtx(app.ctx, "analytics export enabled (sink: %s, interval: %v)", sink.Name(), app.config.Export.Interval)
+		}
+	}
+
+	// Register task journal relay (task_events -> Redis Stream for external consumers)
+	if app.config.TaskJournal.Enabled && redisClient != nil {
+		cfg := app.config.TaskJournal
+		stream := journal.NewStream(redisClient, cfg.Stream, cfg.MaxLen, cfg.Groups)
+		relay := journal.NewRelay(app.mysqlRepo.Export, stream, cfg.BatchSize)
+		journalLock := autoscaler.NewRedisDistributedLock(redisClient, "task-journal:lock")
+		manager.Register(newTaskJournalJob(cfg.Interval, relay, journalLock))
+		logger.InfoCtx(app.ctx, "task journal enabled (stream: %s, groups: %v, interval: %v)", cfg.Stream, cfg.Groups, cfg.Interval)
+	}
+
+	// Register worker log shipping (pod logs -> Loki / Elasticsearch)
+	if app.logService != nil {
+		logShippingLock := autoscaler.NewRedisDistributedLock(redisClient, "logship:lock")
+		manager.Register(newLogShippingJob(app.config.LogShipping.Interval, app.logService, logShippingLock))
+	}
+
+	// Register replica reconciliation (desired replicas in metadata -> provider)
+	if app.deploymentProvider != nil && app.config.AutoScaler.ReconcileInterval > 0 {
+		reconciler := endpointsvc.NewReplicaReconciler(app.deploymentProvider, app.mysqlRepo.Endpoint,
+			time.Duration(app.config.AutoScaler.ReconcileSettle)*time.Second)
+		reconcileLock := autoscaler.NewRedisDistributedLock(redisClient, "replicas:reconcile-lock")
+		manager.Register(newReplicaReconcileJob(time.Duration(app.config.AutoScaler.ReconcileInterval)*time.Second, reconciler, reconcileLock))
+	}
+
+	// Register the workers table vs live pods integrity check
+	if app.integrityService != nil && app.config.Worker.IntegrityInterval > 0 {
+		integrityLock := autoscaler.NewRedisDistributedLock(redisClient, "workers:integrity-lock")
+		manager.Register(newWorkerIntegrityJob(time.Duration(app.config.Worker.IntegrityInterval)*time.Second, app.integrityService, integrityLock))
+	}
+
+	// Register usage anomaly detection
+	if app.anomalyService != nil {
+		anomalyLock := autoscaler.NewRedisDistributedLock(redisClient, "anomaly:detection-lock")
+		manager.Register(newAnomalyDetectionJob(app.config.Anomaly.Interval, app.anomalyService, anomalyLock))
+	}
+
+	// Register project quota forecasting
+	if app.quotaService != nil {
+		quotaLock := autoscaler.NewRedisDistributedLock(redisClient, "quota:forecast-lock")
+		manager.Register(newQuotaForecastJob(app.config.Quota.Interval, app.quotaService, quotaLock))
+	}
+
+	// Register GPU reservation lifecycle (start converts reservations into replicas, end releases them)
+	if app.reservationService != nil {
+		reservationLock := autoscaler.NewRedisDistributedLock(redisClient, "gpu-reservations:lock")
+		manager.Register(newGPUReservationJob(time.Minute, app.reservationService, reservationLock))
+	}
+
+	// Register batch job sync (state, log tail and GPU usage of finished jobs)
+	if app.batchJobService != nil {
+		batchJobLock := autoscaler.NewRedisDistributedLock(redisClient, "batch-jobs:sync-lock")
+		manager.Register(newBatchJobSyncJob(30*time.Second, app.batchJobService, batchJobLock))
+	}
+
+	// Register recurring job schedules (due runs, retries, run history trimming)
+	if app.scheduleService != nil {
+		scheduleLock := autoscaler.NewRedisDistributedLock(redisClient, "job-schedules:lock")
+		manager.Register(newJobScheduleJob(15*time.Second, app.scheduleService, scheduleLock))
+	}
+
+	// Register stale operation cleanup (fails operations of replicas that stopped sending heartbeats)
+	if app.operationService != nil {
+		operationLock := autoscaler.NewRedisDistributedLock(redisClient, "operations:stale-lock")
+		manager.Register(newStaleOperationJob(time.Minute, app.operationService, operationLock))
+	}
+
+	// Register load tests (due runs, orphaned reports, report history trimming)
+	if app.loadTestService != nil {
+		loadTestLock := autoscaler.NewRedisDistributedLock(redisClient, "load-tests:lock")
+		manager.Register(newLoadTestJob(15*time.Second, app.loadTestService, loadTestLock))
+	}
+
 	app.jobsManager = manager
 	return nil
 }
@@ -205,23 +327,23 @@ func (app *Application) startPodCleanupJob(k8sProvider *k8s.K8sDeploymentProvide
 
 	logger.InfoCtx(app.ctx, "Starting pod cleanup job for stuck terminating pods...")
 
-	// Start background task, check every 15 seconds
-	go func() {
+	// Start background task on the leader replica, check every 15 seconds
+	app.registerLeaderController("pod-cleanup", func(ctx context.Context) {
 		ticker := time.NewTicker(15 * time.Second)
 		defer ticker.Stop()
 
 		for {
 			select {
-			case <-app.ctx.Done():
-				logger.InfoCtx(app.ctx, "Pod cleanup job stopped")
+			case <-ctx.Done():
+				logger.InfoCtx(ctx, "Pod cleanup job stopped")
 				return
 			case <-ticker.C:
 				app.cleanupStuckTerminatingPods(k8sProvider)
 			}
 		}
-	}()
+	})
 
-	logger.InfoCtx(app.ctx, "✅ Pod cleanup job started")
+	logger.InfoCtx(app.ctx, "✅ Pod cleanup job registered (runs on the leader replica)")
 	return nil
 }
 
@@ -518,5 +640,626 @@ func (j *dataRetentionCleanupJob) Run(ctx context.Context) error {
 		logger.InfoCtx(ctx, "cleaned up %d old worker events (older than %d days)", workerEventRows, retentionDays)
 	}
 
+	// Clean old GPU usage records (aggregated statistics are kept longer)
+	gpuRecordRetentionDays := 30
+	gpuRows, _ := j.repo.GPUUsage.CleanupOldRecords(ctx, time.Now().AddDate(0, 0, -gpuRecordRetentionDays))
+	if gpuRows > 0 {
+		logger.InfoCtx(ctx, "cleaned up %d old gpu usage records (older than %d days)", gpuRows, gpuRecordRetentionDays)
+	}
+
+	// Clean old image validation history
+	imageValidationRetentionDays := 30
+	imageValidationRows, _ := j.repo.ImageValidation.CleanupOldRecords(ctx, time.Now().AddDate(0, 0, -imageValidationRetentionDays))
+	if imageValidationRows > 0 {
+		logger.InfoCtx(ctx, "cleaned up %d old image validation records (older than %d days)", imageValidationRows, imageValidationRetentionDays)
+	}
+
+	return nil
+}
+
+// gpuUsageAggregationJob advances GPU usage aggregation watermarks every minute.
+// Only the replica holding the lock aggregates; progress is persisted so a new leader resumes where the last stopped.
+type gpuUsageAggregationJob struct {
+	interval        time.Duration
+	gpuUsageService *service.GPUUsageService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newGPUUsageAggregationJob(interval time.Duration, svc *service.GPUUsageService, lock autoscaler.DistributedLock) jobs.Job {
+	return &gpuUsageAggregationJob{
+		interval:        interval,
+		gpuUsageService: svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *gpuUsageAggregationJob) Name() string { return "gpu-usage-aggregation" }
+
+func (j *gpuUsageAggregationJob) Interval() time.Duration { return j.interval }
+
+func (j *gpuUsageAggregationJob) Run(ctx context.Context) error {
+	if j.gpuUsageService == nil {
+		return fmt.Errorf("gpu usage service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running gpu usage aggregation, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+	return j.gpuUsageService.AggregatePending(ctx)
+}
+
+// gpuUsageBackfillJob advances the active GPU usage backfill job by one time-boxed slice.
+// Progress is stored after every batch, so a restart or a new lock holder continues from the cursor.
+type gpuUsageBackfillJob struct {
+	interval        time.Duration
+	budget          time.Duration
+	gpuUsageService *service.GPUUsageService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newGPUUsageBackfillJob(interval, budget time.Duration, svc *service.GPUUsageService, lock autoscaler.DistributedLock) jobs.Job {
+	return &gpuUsageBackfillJob{
+		interval:        interval,
+		budget:          budget,
+		gpuUsageService: svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *gpuUsageBackfillJob) Name() string { return "gpu-usage-backfill" }
+
+func (j *gpuUsageBackfillJob) Interval() time.Duration { return j.interval }
+
+func (j *gpuUsageBackfillJob) Run(ctx context.Context) error {
+	if j.gpuUsageService == nil {
+		return fmt.Errorf("gpu usage service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running the gpu usage backfill job, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+	return j.gpuUsageService.RunBackfillJob(ctx, j.budget)
+}
+
+// taskReplayJob advances the active task replay jobs by one time-boxed slice.
+// Progress is stored after every batch, so a restart or a new lock holder continues from the cursor.
+type taskReplayJob struct {
+	interval        time.Duration
+	budget          time.Duration
+	replayService   *service.TaskReplayService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newTaskReplayJob(interval, budget time.Duration, svc *service.TaskReplayService, lock autoscaler.DistributedLock) jobs.Job {
+	return &taskReplayJob{
+		interval:        interval,
+		budget:          budget,
+		replayService:   svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *taskReplayJob) Name() string { return "task-replay" }
+
+func (j *taskReplayJob) Interval() time.Duration { return j.interval }
+
+func (j *taskReplayJob) Run(ctx context.Context) error {
+	if j.replayService == nil {
+		return fmt.Errorf("task replay service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running the task replay job, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+	return j.replayService.RunJobs(ctx, j.budget)
+}
+
+// taskHedgingJob hedges the overdue tasks of latency-critical endpoints
+type taskHedgingJob struct {
+	interval        time.Duration
+	hedgingService  *service.HedgingService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newTaskHedgingJob(interval time.Duration, svc *service.HedgingService, lock autoscaler.DistributedLock) jobs.Job {
+	return &taskHedgingJob{
+		interval:        interval,
+		hedgingService:  svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *taskHedgingJob) Name() string { return "task-hedging" }
+
+func (j *taskHedgingJob) Interval() time.Duration { return j.interval }
+
+func (j *taskHedgingJob) Run(ctx context.Context) error {
+	if j.hedgingService == nil {
+		return fmt.Errorf("hedging service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running the task hedging job, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+	return j.hedgingService.Run(ctx)
+}
+
+// circuitBreakerJob trips and probes the circuit breakers of endpoints
+type circuitBreakerJob struct {
+	interval        time.Duration
+	circuitBreaker  *service.CircuitBreakerService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newCircuitBreakerJob(interval time.Duration, svc *service.CircuitBreakerService, lock autoscaler.DistributedLock) jobs.Job {
+	return &circuitBreakerJob{
+		interval:        interval,
+		circuitBreaker:  svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *circuitBreakerJob) Name() string { return "circuit-breaker" }
+
+func (j *circuitBreakerJob) Interval() time.Duration { return j.interval }
+
+func (j *circuitBreakerJob) Run(ctx context.Context) error {
+	if j.circuitBreaker == nil {
+		return fmt.Errorf("circuit breaker not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running the circuit breaker job, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+	return j.circuitBreaker.Run(ctx)
+}
+
+// analyticsExportJob periodically exports raw records to external analytics storage
+type analyticsExportJob struct {
+	interval        time.Duration
+	exporter        *export.Exporter
+	distributedLock autoscaler.DistributedLock
+}
+
+func newAnalyticsExportJob(interval time.Duration, exporter *export.Exporter, lock autoscaler.DistributedLock) jobs.Job {
+	return &analyticsExportJob{
+		interval:        interval,
+		exporter:        exporter,
+		distributedLock: lock,
+	}
+}
+
+func (j *analyticsExportJob) Name() string { return "analytics-export" }
+
+func (j *analyticsExportJob) Interval() time.Duration { return j.interval }
+
+func (j *analyticsExportJob) Run(ctx context.Context) error {
+	if j.exporter == nil {
+		return fmt.Errorf("exporter not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running analytics export, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+	return j.exporter.ExportPending(ctx)
+}
+
+// taskJournalJob periodically relays new task events to the task journal stream
+type taskJournalJob struct {
+	interval        time.Duration
+	relay           *journal.Relay
+	distributedLock autoscaler.DistributedLock
+}
+
+func newTaskJournalJob(interval time.Duration, relay *journal.Relay, lock autoscaler.DistributedLock) jobs.Job {
+	return &taskJournalJob{
+		interval:        interval,
+		relay:           relay,
+		distributedLock: lock,
+	}
+}
+
+func (j *taskJournalJob) Name() string { return "task-journal" }
+
+func (j *taskJournalJob) Interval() time.Duration { return j.interval }
+
+func (j *taskJournalJob) Run(ctx context.Context) error {
+	if j.relay == nil {
+		return fmt.Errorf("task journal relay not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running the task journal relay, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+	return j.relay.RelayPending(ctx)
+}
+
+// logShippingJob periodically forwards new worker log lines to the log store
+type logShippingJob struct {
+	interval        time.Duration
+	logService      *service.LogService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newLogShippingJob(interval time.Duration, svc *service.LogService, lock autoscaler.DistributedLock) jobs.Job {
+	return &logShippingJob{
+		interval:        interval,
+		logService:      svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *logShippingJob) Name() string { return "log-shipping" }
+
+func (j *logShippingJob) Interval() time.Duration { return j.interval }
+
+func (j *logShippingJob) Run(ctx context.Context) error {
+	if j.logService == nil {
+		return fmt.Errorf("log service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is shipping worker logs, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+	return j.logService.ShipPending(ctx)
+}
+
+// workerIntegrityJob closes out worker rows whose pods are gone and reports pods without a row
+type workerIntegrityJob struct {
+	interval        time.Duration
+	service         *service.WorkerIntegrityService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newWorkerIntegrityJob(interval time.Duration, svc *service.WorkerIntegrityService, lock autoscaler.DistributedLock) jobs.Job {
+	return &workerIntegrityJob{
+		interval:        interval,
+		service:         svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *workerIntegrityJob) Name() string { return "worker-integrity" }
+
+func (j *workerIntegrityJob) Interval() time.Duration { return j.interval }
+
+func (j *workerIntegrityJob) Run(ctx context.Context) error {
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is checking worker integrity, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	report, err := j.service.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	if len(report.OrphanWorkers) > 0 || len(report.UnregisteredPods) > 0 || len(report.Failed) > 0 {
+		logger.WarnCtx(ctx, "worker integrity: endpoints=%d workers=%d pods=%d orphanWorkers=%d unregisteredPods=%d failed=%v",
+			report.Endpoints, report.Workers, report.Pods, len(report.OrphanWorkers), len(report.UnregisteredPods), report.Failed)
+	}
+	return nil
+}
+
+// replicaReconcileJob converges provider replicas to the desired count in endpoint metadata
+type replicaReconcileJob struct {
+	interval        time.Duration
+	reconciler      *endpointsvc.ReplicaReconciler
+	distributedLock autoscaler.DistributedLock
+}
+
+func newReplicaReconcileJob(interval time.Duration, reconciler *endpointsvc.ReplicaReconciler, lock autoscaler.DistributedLock) jobs.Job {
+	return &replicaReconcileJob{
+		interval:        interval,
+		reconciler:      reconciler,
+		distributedLock: lock,
+	}
+}
+
+func (j *replicaReconcileJob) Name() string { return "replica-reconcile" }
+
+func (j *replicaReconcileJob) Interval() time.Duration { return j.interval }
+
+func (j *replicaReconcileJob) Run(ctx context.Context) error {
+	if j.reconciler == nil {
+		return fmt.Errorf("replica reconciler not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is reconciling replicas, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	report, err := j.reconciler.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	if len(report.Converged) > 0 || len(report.Failed) > 0 {
+		logger.InfoCtx(ctx, "replica reconcile: checked=%d inSync=%d settling=%d converged=%v failed=%v",
+			report.Checked, report.InSync, report.Settling, report.Converged, report.Failed)
+	}
 	return nil
 }
+
+// gpuReservationJob starts and completes GPU reservations at their window boundaries
+type gpuReservationJob struct {
+	interval           time.Duration
+	reservationService *service.GPUReservationService
+	distributedLock    autoscaler.DistributedLock
+}
+
+func newGPUReservationJob(interval time.Duration, svc *service.GPUReservationService, lock autoscaler.DistributedLock) jobs.Job {
+	return &gpuReservationJob{
+		interval:           interval,
+		reservationService: svc,
+		distributedLock:    lock,
+	}
+}
+
+func (j *gpuReservationJob) Name() string { return "gpu-reservations" }
+
+func (j *gpuReservationJob) Interval() time.Duration { return j.interval }
+
+func (j *gpuReservationJob) Run(ctx context.Context) error {
+	if j.reservationService == nil {
+		return fmt.Errorf("GPU reservation service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is reconciling GPU reservations, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	return j.reservationService.Reconcile(ctx)
+}
+
+// batchJobSyncJob follows one-off jobs to completion
+type batchJobSyncJob struct {
+	interval        time.Duration
+	batchJobService *service.BatchJobService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newBatchJobSyncJob(interval time.Duration, svc *service.BatchJobService, lock autoscaler.DistributedLock) jobs.Job {
+	return &batchJobSyncJob{
+		interval:        interval,
+		batchJobService: svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *batchJobSyncJob) Name() string { return "batch-job-sync" }
+
+func (j *batchJobSyncJob) Interval() time.Duration { return j.interval }
+
+func (j *batchJobSyncJob) Run(ctx context.Context) error {
+	if j.batchJobService == nil {
+		return fmt.Errorf("batch job service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is syncing batch jobs, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	return j.batchJobService.Sync(ctx)
+}
+
+// jobScheduleJob starts the due runs and retries of recurring job schedules
+type jobScheduleJob struct {
+	interval        time.Duration
+	scheduleService *service.JobScheduleService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newJobScheduleJob(interval time.Duration, svc *service.JobScheduleService, lock autoscaler.DistributedLock) jobs.Job {
+	return &jobScheduleJob{
+		interval:        interval,
+		scheduleService: svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *jobScheduleJob) Name() string { return "job-schedules" }
+
+func (j *jobScheduleJob) Interval() time.Duration { return j.interval }
+
+func (j *jobScheduleJob) Run(ctx context.Context) error {
+	if j.scheduleService == nil {
+		return fmt.Errorf("job schedule service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running job schedules, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	return j.scheduleService.Reconcile(ctx)
+}
+
+// anomalyDetectionJob checks endpoint usage for anomalies
+type anomalyDetectionJob struct {
+	interval        time.Duration
+	anomalyService  *service.AnomalyService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newAnomalyDetectionJob(interval time.Duration, svc *service.AnomalyService, lock autoscaler.DistributedLock) jobs.Job {
+	return &anomalyDetectionJob{
+		interval:        interval,
+		anomalyService:  svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *anomalyDetectionJob) Name() string { return "anomaly-detection" }
+
+func (j *anomalyDetectionJob) Interval() time.Duration { return j.interval }
+
+func (j *anomalyDetectionJob) Run(ctx context.Context) error {
+	if j.anomalyService == nil {
+		return fmt.Errorf("anomaly service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running anomaly detection, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	return j.anomalyService.DetectAll(ctx)
+}
+
+// quotaForecastJob warns about projects approaching their quotas
+type quotaForecastJob struct {
+	interval        time.Duration
+	quotaService    *service.QuotaService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newQuotaForecastJob(interval time.Duration, svc *service.QuotaService, lock autoscaler.DistributedLock) jobs.Job {
+	return &quotaForecastJob{
+		interval:        interval,
+		quotaService:    svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *quotaForecastJob) Name() string { return "quota-forecast" }
+
+func (j *quotaForecastJob) Interval() time.Duration { return j.interval }
+
+func (j *quotaForecastJob) Run(ctx context.Context) error {
+	if j.quotaService == nil {
+		return fmt.Errorf("quota service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running quota forecasting, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	return j.quotaService.CheckAll(ctx)
+}
+
+// staleOperationJob fails long-running operations whose owner stopped sending heartbeats
+type staleOperationJob struct {
+	interval         time.Duration
+	operationService *service.OperationService
+	distributedLock  autoscaler.DistributedLock
+}
+
+func newStaleOperationJob(interval time.Duration, svc *service.OperationService, lock autoscaler.DistributedLock) jobs.Job {
+	return &staleOperationJob{
+		interval:         interval,
+		operationService: svc,
+		distributedLock:  lock,
+	}
+}
+
+func (j *staleOperationJob) Name() string { return "stale-operations" }
+
+func (j *staleOperationJob) Interval() time.Duration { return j.interval }
+
+func (j *staleOperationJob) Run(ctx context.Context) error {
+	if j.operationService == nil {
+		return fmt.Errorf("operation service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is failing stale operations, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	return j.operationService.FailStale(ctx)
+}
+
+// loadTestJob starts the due runs of load tests and closes the reports of stopped runs
+type loadTestJob struct {
+	interval        time.Duration
+	loadTestService *service.LoadTestService
+	distributedLock autoscaler.DistributedLock
+}
+
+func newLoadTestJob(interval time.Duration, svc *service.LoadTestService, lock autoscaler.DistributedLock) jobs.Job {
+	return &loadTestJob{
+		interval:        interval,
+		loadTestService: svc,
+		distributedLock: lock,
+	}
+}
+
+func (j *loadTestJob) Name() string { return "load-tests" }
+
+func (j *loadTestJob) Interval() time.Duration { return j.interval }
+
+func (j *loadTestJob) Run(ctx context.Context) error {
+	if j.loadTestService == nil {
+		return fmt.Errorf("load test service not configured")
+	}
+	if j.distributedLock != nil {
+		acquired, err := j.distributedLock.TryLock(ctx)
+		if err != nil || !acquired {
+			logger.DebugCtx(ctx, "another instance is running load tests, skipping this cycle")
+			return nil
+		}
+		defer j.distributedLock.Unlock(ctx)
+	}
+
+	return j.loadTestService.Reconcile(ctx)
+}
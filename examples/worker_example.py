@@ -5,18 +5,39 @@ This example demonstrates how to use runpod-python client to connect to Waverles
 Fully compatible with existing runpod code, only environment variables need to be modified.
 """
 
+import json
 import os
 import time
+from functools import wraps
 from runpod.serverless import start
 
 # Configure Waverless service address
 # $ID will be replaced with actual worker ID (handled automatically by runpod client)
 os.environ["RUNPOD_WEBHOOK_GET_JOB"] = "http://localhost:8080/runpod/job-take/$ID"
-os.environ["RUNPOD_WEBHOOK_PING"] = "http://localhost:8080/runpod/ping/$ID"
+# Capabilities announce optional features: streaming, cancellation, checkpointing, batch.
+# Waverless only sends cancellations (heartbeat response "cancel" list) to workers with
+# "cancellation", and only hands out multiple jobs per pull to workers with "batch".
+# Omit the parameter to keep the legacy behavior.
+os.environ["RUNPOD_WEBHOOK_PING"] = "http://localhost:8080/runpod/ping/$ID?capabilities=streaming"
 os.environ["RUNPOD_POD_ID"] = "worker-001"  # Worker ID
 os.environ["RUNPOD_PING_INTERVAL"] = "10000"  # Heartbeat interval 10 seconds
 
+# Task log markers
+# Waverless slices worker logs per task using these lines, so
+# GET /api/v1/tasks/{id}/logs returns only this task's output (requires log shipping)
+def with_task_log_markers(fn):
+    """Wrap a (non-generator) handler with task start/end log markers"""
+    @wraps(fn)
+    def wrapper(job):
+        print(json.dumps({"waverless_task": "start", "task_id": job["id"]}), flush=True)
+        try:
+            return fn(job)
+        finally:
+            print(json.dumps({"waverless_task": "end", "task_id": job["id"]}), flush=True)
+    return wrapper
+
 # Task handler function
+@with_task_log_markers
 def handler(job):
     """
     Main function to handle tasks
@@ -102,12 +123,98 @@ def concurrency_modifier(current_concurrency):
     """
     return 2  # Set concurrency to 2
 
+# Direct-HTTP mode: verify invoke tokens
+# When dataPlane.tokenSecret is set, Waverless injects WAVERLESS_INVOKE_KEY (hex) into the pod.
+# Requests sent straight to the pod carry X-Waverless-Invoke-Token, issued by
+# POST /api/v1/endpoints/{name}/invoke-token; reject anything that does not verify.
+def verify_invoke_token(token, method, path, endpoint=None, now=None):
+    """Return True if token was signed for this endpoint, method and path and has not expired"""
+    import base64
+    import hashlib
+    import hmac
+
+    key_hex = os.environ.get("WAVERLESS_INVOKE_KEY")
+    if not key_hex:
+        return True  # Tokens not enabled for this endpoint
+    endpoint = endpoint or os.environ.get("WAVERLESS_ENDPOINT_ID", "")
+    try:
+        version, expiry, sig = token.split(".")
+    except (AttributeError, ValueError):
+        return False
+    if version != "v1" or not expiry.isdigit() or int(expiry) < int(now or time.time()):
+        return False
+    message = "\n".join(["v1", endpoint, method.upper(), path, expiry]).encode()
+    digest = hmac.new(bytes.fromhex(key_hex), message, hashlib.sha256).digest()
+    expected = base64.urlsafe_b64encode(digest).rstrip(b"=").decode()
+    return hmac.compare_digest(expected, sig)
+
+# GPU health checks
+# Report a failed check on the heartbeat (gpu_health=unhealthy); Waverless drains the worker
+# and replaces its pod, see "Worker GPU Health Checks" in docs/USER_GUIDE.md
+def check_gpu_health(timeout=10):
+    """Return None if the GPUs look healthy, otherwise what failed"""
+    import subprocess
+
+    try:
+        subprocess.run(["nvidia-smi"], capture_output=True, check=True, timeout=timeout)
+    except subprocess.TimeoutExpired:
+        return f"nvidia-smi did not respond within {timeout}s"
+    except (OSError, subprocess.CalledProcessError) as e:
+        return f"nvidia-smi failed: {e}"
+
+    try:
+        import pynvml
+    except ImportError:
+        return None  # ECC counters need nvidia-ml-py
+    pynvml.nvmlInit()
+    try:
+        for i in range(pynvml.nvmlDeviceGetCount()):
+            device = pynvml.nvmlDeviceGetHandleByIndex(i)
+            try:
+                errors = pynvml.nvmlDeviceGetTotalEccErrors(
+                    device, pynvml.NVML_MEMORY_ERROR_TYPE_UNCORRECTED, pynvml.NVML_VOLATILE_ECC)
+            except pynvml.NVMLError_NotSupported:
+                continue  # ECC disabled or not supported on this GPU
+            if errors > 0:
+                return f"{errors} uncorrectable ECC errors on GPU {i}"
+    finally:
+        pynvml.nvmlShutdown()
+    return None
+
+def start_gpu_health_checks(interval=60):
+    """Check the GPUs every interval seconds in the background and report failures"""
+    import threading
+    import urllib.parse
+    import urllib.request
+
+    ping_url = os.environ["RUNPOD_WEBHOOK_PING"].replace("$ID", os.environ["RUNPOD_POD_ID"])
+
+    def loop():
+        while True:
+            reason = check_gpu_health()
+            if reason:
+                params = urllib.parse.urlencode({"gpu_health": "unhealthy", "gpu_health_reason": reason})
+                separator = "&" if "?" in ping_url else "?"
+                request = urllib.request.Request(f"{ping_url}{separator}{params}")
+                if os.environ.get("RUNPOD_AI_API_KEY"):
+                    request.add_header("Authorization", os.environ["RUNPOD_AI_API_KEY"])
+                try:
+                    urllib.request.urlopen(request, timeout=10).close()
+                except OSError as e:
+                    print(f"Failed to report GPU health: {e}")
+            time.sleep(interval)
+
+    threading.Thread(target=loop, daemon=True).start()
+
 # Start Worker
 if __name__ == "__main__":
     print("Starting Waverless Worker...")
     print(f"Worker ID: {os.environ['RUNPOD_POD_ID']}")
     print(f"Server URL: {os.environ['RUNPOD_WEBHOOK_GET_JOB']}")
 
+    # Report GPU faults so the worker gets replaced
+    # start_gpu_health_checks()
+
     # Basic start
     # start({"handler": handler})
 
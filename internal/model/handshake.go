@@ -0,0 +1,71 @@
+package model
+
+// Worker contract versions accepted by the startup handshake. The contract covers the
+// /v2 job-take, ping and job-done routes and the env layout workers are configured with.
+const (
+	WorkerContractVersion    = 1 // Current contract
+	MinWorkerContractVersion = 1 // Oldest contract still served
+)
+
+// Worker SDKs known to the handshake
+const (
+	WorkerSDKRunPod    = "runpod"    // runpod-python, configured via RUNPOD_* env
+	WorkerSDKWaverless = "waverless" // wavespeed-python, configured via WAVERLESS_* env
+)
+
+// WorkerContractEnv env vars each SDK needs to reach the control plane. They are injected
+// into every worker pod; a worker missing one will never pull a job or report a result.
+var WorkerContractEnv = map[string][]string{
+	WorkerSDKRunPod: {
+		"RUNPOD_POD_ID",
+		"RUNPOD_ENDPOINT_ID",
+		"RUNPOD_WEBHOOK_GET_JOB",
+		"RUNPOD_WEBHOOK_PING",
+		"RUNPOD_WEBHOOK_POST_OUTPUT",
+	},
+	WorkerSDKWaverless: {
+		"WAVERLESS_POD_ID",
+		"WAVERLESS_ENDPOINT_ID",
+		"WAVERLESS_WEBHOOK_GET_JOB",
+		"WAVERLESS_WEBHOOK_PING",
+		"WAVERLESS_WEBHOOK_POST_OUTPUT",
+	},
+}
+
+// Handshake warning codes
+const (
+	HandshakeWarningContractUnsupported = "CONTRACT_UNSUPPORTED" // Contract version outside the served range
+	HandshakeWarningContractOutdated    = "CONTRACT_OUTDATED"    // Served, but older than the current contract
+	HandshakeWarningUnknownSDK          = "UNKNOWN_SDK"          // SDK name not known, env layout not checked
+	HandshakeWarningMissingEnv          = "MISSING_ENV"          // Contract or endpoint env vars not visible to the worker
+	HandshakeWarningMissingVolume       = "MISSING_VOLUME"       // Configured volume mounts not visible to the worker
+)
+
+// HandshakeRequest is sent by a worker once at startup, before it pulls jobs.
+// Only env var names are reported, never their values.
+type HandshakeRequest struct {
+	SDK             string   `json:"sdk"`                   // runpod (default), waverless
+	SDKVersion      string   `json:"sdk_version,omitempty"` // e.g. 1.7.0
+	ContractVersion int      `json:"contract_version"`      // Worker contract version the SDK implements
+	Env             []string `json:"env,omitempty"`         // Names of the env vars visible to the worker
+	Volumes         []string `json:"volumes,omitempty"`     // Mount paths visible to the worker
+}
+
+// HandshakeWarning is a mismatch between what the worker reported and what the endpoint expects
+type HandshakeWarning struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Missing []string `json:"missing,omitempty"` // Missing env var names or mount paths
+}
+
+// HandshakeResponse tells the worker which contract to speak and what did not match.
+// Workers are not refused: mismatches are returned and recorded as endpoint warnings.
+type HandshakeResponse struct {
+	Compatible         bool               `json:"compatible"`       // Contract version is served
+	ContractVersion    int                `json:"contract_version"` // Contract version to speak
+	MinContractVersion int                `json:"min_contract_version"`
+	MaxContractVersion int                `json:"max_contract_version"`
+	ExpectedEnv        []string           `json:"expected_env"`
+	ExpectedVolumes    []string           `json:"expected_volumes,omitempty"`
+	Warnings           []HandshakeWarning `json:"warnings,omitempty"`
+}
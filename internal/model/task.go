@@ -37,12 +37,34 @@ type SubmitRequest struct {
 	Input      map[string]interface{} `json:"input" binding:"required"`
 	WebhookURL string                 `json:"webhook,omitempty"`
 	Endpoint   string                 `json:"endpoint,omitempty"` // Specify endpoint, internal use
+	Routing    *RoutingHints          `json:"routing,omitempty"`  // Routing hints, used by federated and heterogeneous endpoints
+	ClientIP   string                 `json:"-"`                  // Source IP, internal use (region fallback)
+	Subject    string                 `json:"subject,omitempty"`  // Data subject key for deletion requests (or X-Subject-Key header)
+}
+
+// RoutingHints are client preferences for picking the region of a federated endpoint and
+// the GPU tier of a heterogeneous endpoint
+type RoutingHints struct {
+	Region    string         `json:"region,omitempty"`    // Preferred region
+	LatencyMs map[string]int `json:"latencyMs,omitempty"` // Measured latency per region, lower is preferred
+	Precision string         `json:"precision,omitempty"` // Requested precision (e.g. "fp16", "int8"), matched against GPU tier rules
+}
+
+// RequestedPrecision returns the precision asked for by the hints, empty without hints
+func (h *RoutingHints) RequestedPrecision() string {
+	if h == nil {
+		return ""
+	}
+	return h.Precision
 }
 
 // SubmitResponse submit task response
 type SubmitResponse struct {
-	ID     string     `json:"id"`
-	Status TaskStatus `json:"status"`
+	ID       string     `json:"id"`
+	Status   TaskStatus `json:"status"`
+	Endpoint string     `json:"endpoint,omitempty"` // Backing endpoint, set when submitted to a federated endpoint or routed to a GPU tier
+	Region   string     `json:"region,omitempty"`   // Region of the backing endpoint
+	Waking   bool       `json:"waking,omitempty"`   // Endpoint was scaled to zero, the task waits for a cold start
 }
 
 // StatusResponse task status response
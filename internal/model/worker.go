@@ -1,6 +1,8 @@
 package model
 
 import (
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -28,14 +30,123 @@ type Worker struct {
 	Version         string       `json:"version,omitempty"`
 	RegisteredAt    time.Time    `json:"registered_at"`
 	PodName         string       `json:"pod_name,omitempty"` // K8s pod name (from RUNPOD_POD_ID env)
+	Capabilities    []string     `json:"capabilities,omitempty"` // Capabilities announced by the worker (nil if never declared)
+	CustomMetric    *float64     `json:"custom_metric,omitempty"`    // Last custom autoscaling gauge (nil if never reported)
+	CustomMetricAt  time.Time    `json:"custom_metric_at,omitempty"` // Time of the last custom metric report
+	DrainReason     string       `json:"drain_reason,omitempty"`     // Why the worker is DRAINING (e.g. node maintenance)
+	Quarantine      *WorkerQuarantine `json:"quarantine,omitempty"`    // Set while the worker receives no new tasks
+}
+
+// WorkerQuarantine why a worker receives no new tasks; its pod is kept for inspection
+type WorkerQuarantine struct {
+	Reason string    `json:"reason"`
+	By     string    `json:"by,omitempty"`     // Operator who quarantined the worker or created the ban
+	At     time.Time `json:"at"`
+	BanID  *int64    `json:"ban_id,omitempty"` // Node or image ban that matched the worker, nil if quarantined manually
+}
+
+// WorkerCapability feature a worker can announce at registration
+type WorkerCapability string
+
+const (
+	WorkerCapabilityStreaming     WorkerCapability = "streaming"     // Sends partial results via /stream
+	WorkerCapabilityCancellation  WorkerCapability = "cancellation"  // Stops jobs listed in the heartbeat response "cancel" field
+	WorkerCapabilityCheckpointing WorkerCapability = "checkpointing" // Persists progress and can resume a re-queued job
+	WorkerCapabilityBatch         WorkerCapability = "batch"         // Accepts more than one job per pull
+	WorkerCapabilityBroadcast     WorkerCapability = "broadcast"     // Applies and acknowledges the heartbeat response "broadcasts" field
+)
+
+// knownWorkerCapabilities capabilities accepted from workers
+var knownWorkerCapabilities = map[WorkerCapability]bool{
+	WorkerCapabilityStreaming:     true,
+	WorkerCapabilityCancellation:  true,
+	WorkerCapabilityCheckpointing: true,
+	WorkerCapabilityBatch:         true,
+	WorkerCapabilityBroadcast:     true,
+}
+
+// ParseWorkerCapabilities normalizes announced capabilities.
+// Values may be comma separated; names are trimmed, lowercased, deduplicated and sorted.
+// Unknown names are returned separately so newer workers don't break older control planes.
+func ParseWorkerCapabilities(values []string) (capabilities []string, unknown []string) {
+	seen := make(map[string]bool)
+	capabilities = make([]string, 0, len(values))
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			if !knownWorkerCapabilities[WorkerCapability(name)] {
+				unknown = append(unknown, name)
+				continue
+			}
+			capabilities = append(capabilities, name)
+		}
+	}
+	sort.Strings(capabilities)
+	return capabilities, unknown
+}
+
+// HasCapability reports whether the worker announced the capability
+func (w *Worker) HasCapability(capability WorkerCapability) bool {
+	return HasWorkerCapability(w.Capabilities, capability)
+}
+
+// CapabilitiesDeclared reports whether the worker announced its capabilities at all.
+// Workers that never declared capabilities keep the legacy behavior.
+func (w *Worker) CapabilitiesDeclared() bool {
+	return w.Capabilities != nil
+}
+
+// HasWorkerCapability reports whether the capability is in the list
+func HasWorkerCapability(capabilities []string, capability WorkerCapability) bool {
+	for _, c := range capabilities {
+		if c == string(capability) {
+			return true
+		}
+	}
+	return false
 }
 
 // HeartbeatRequest heartbeat request
 type HeartbeatRequest struct {
-	WorkerID       string   `json:"worker_id" binding:"required"`
-	JobsInProgress []string `json:"job_in_progress"` // Field name consistent with runpod
-	Concurrency    int      `json:"concurrency"`
-	Version        string   `json:"version,omitempty"`
+	WorkerID       string          `json:"worker_id" binding:"required"`
+	JobsInProgress []string        `json:"job_in_progress"` // Field name consistent with runpod
+	Concurrency    int             `json:"concurrency"`
+	Version        string          `json:"version,omitempty"`
+	Capabilities   []string        `json:"capabilities,omitempty"`   // nil when the worker did not announce capabilities
+	CustomMetric   *float64        `json:"custom_metric,omitempty"`  // Custom autoscaling gauge, nil when not reported
+	BroadcastAcks  []*BroadcastAck `json:"broadcast_acks,omitempty"` // Broadcasts the worker applied (or failed to apply) since the last heartbeat
+	GPUHealth      *GPUHealth      `json:"gpu_health,omitempty"`     // Result of the worker's GPU health check, nil when not reported
+}
+
+// GPUHealth the result of a worker's own GPU health check (e.g. NVML ECC errors, nvidia-smi responsiveness)
+type GPUHealth struct {
+	Healthy bool   `json:"healthy"`
+	Reason  string `json:"reason,omitempty"` // What failed, e.g. "3 uncorrectable ECC errors on GPU 0"
+}
+
+// BroadcastAck a worker's acknowledgement of a broadcast
+type BroadcastAck struct {
+	ID    int64  `json:"id"`
+	Error string `json:"error,omitempty"` // Set when the worker could not apply the broadcast
+}
+
+// HeartbeatResponse heartbeat response
+type HeartbeatResponse struct {
+	Status     string              `json:"status"`
+	Cancel     []string            `json:"cancel,omitempty"`     // Jobs in progress that were cancelled (only for workers with cancellation capability)
+	Broadcasts []*BroadcastMessage `json:"broadcasts,omitempty"` // Unacknowledged broadcasts, oldest first (only for workers with broadcast capability)
+}
+
+// BroadcastMessage a control message for every worker of an endpoint (e.g. flush a cache after a
+// model weights update). It is repeated on each heartbeat until the worker acknowledges it.
+type BroadcastMessage struct {
+	ID      int64                  `json:"id"`
+	Type    string                 `json:"type"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
 }
 
 // JobPullRequest job pull request
@@ -44,6 +155,7 @@ type JobPullRequest struct {
 	JobsInProgress      []string `json:"job_in_progress"`       // Task ID list
 	JobsInProgressCount int      `json:"job_in_progress_count"` // Task count (when IDs not available)
 	BatchSize           int      `json:"batch_size"`            // Batch pull count
+	WaitSeconds         int      `json:"wait"`                  // Long-poll: hold an empty pull up to this many seconds
 }
 
 // JobPullResponse job pull response
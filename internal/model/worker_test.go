@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWorkerCapabilities(t *testing.T) {
+	capabilities, unknown := ParseWorkerCapabilities([]string{" Streaming,cancellation ", "batch", "streaming", "", "gpu-direct"})
+	assert.Equal(t, []string{"batch", "cancellation", "streaming"}, capabilities)
+	assert.Equal(t, []string{"gpu-direct"}, unknown)
+
+	// An explicit empty declaration is distinct from no declaration
+	capabilities, unknown = ParseWorkerCapabilities([]string{""})
+	assert.NotNil(t, capabilities)
+	assert.Empty(t, capabilities)
+	assert.Empty(t, unknown)
+}
+
+func TestWorkerHasCapability(t *testing.T) {
+	legacy := &Worker{}
+	assert.False(t, legacy.CapabilitiesDeclared())
+	assert.False(t, legacy.HasCapability(WorkerCapabilityCancellation))
+
+	worker := &Worker{Capabilities: []string{"cancellation"}}
+	assert.True(t, worker.CapabilitiesDeclared())
+	assert.True(t, worker.HasCapability(WorkerCapabilityCancellation))
+	assert.False(t, worker.HasCapability(WorkerCapabilityBatch))
+}
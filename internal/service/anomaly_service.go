@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"waverless/pkg/anomaly"
+	"waverless/pkg/config"
+	"waverless/pkg/logger"
+	"waverless/pkg/notification"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+// AnomalyReport is the anomaly evaluation of one endpoint
+type AnomalyReport struct {
+	Endpoint      string            `json:"endpoint"`
+	Sensitivity   string            `json:"sensitivity"`
+	WindowStart   time.Time         `json:"windowStart"`
+	WindowEnd     time.Time         `json:"windowEnd"`
+	Current       anomaly.Sample    `json:"current"`
+	BaselineHours int               `json:"baselineHours"` // Hourly buckets with data in the baseline period
+	Findings      []anomaly.Finding `json:"findings"`
+}
+
+// AnomalyService detects usage anomalies from the monitoring and GPU usage statistics and
+// raises them as endpoint warnings and endpoint.anomaly integration events. A warning stays
+// while the anomaly is detected and is resolved once it is gone; alerts of the same kind are
+// sent at most once per cooldown and endpoint.
+type AnomalyService struct {
+	monitoringRepo     *mysql.MonitoringRepository
+	gpuUsageRepo       *mysql.GPUUsageRepository
+	warningRepo        *mysql.EndpointWarningRepository
+	integrationService *IntegrationService // nil = no alerts
+	config             config.AnomalyConfig
+
+	mu        sync.Mutex
+	lastAlert map[string]time.Time // endpoint/kind -> last alert
+}
+
+// NewAnomalyService creates a new anomaly service
+func NewAnomalyService(monitoringRepo *mysql.MonitoringRepository, gpuUsageRepo *mysql.GPUUsageRepository, warningRepo *mysql.EndpointWarningRepository, integrationService *IntegrationService, cfg config.AnomalyConfig) *AnomalyService {
+	if _, err := anomaly.ParseSensitivity(cfg.Sensitivity); err != nil {
+		logger.WarnCtx(context.Background(), "anomaly detection: %v, using normal", err)
+		cfg.Sensitivity = string(anomaly.SensitivityNormal)
+	}
+	for endpoint, s := range cfg.Endpoints {
+		if _, err := anomaly.ParseSensitivity(s); err != nil {
+			logger.WarnCtx(context.Background(), "anomaly detection: endpoint %s: %v, using the default", endpoint, err)
+			delete(cfg.Endpoints, endpoint)
+		}
+	}
+	return &AnomalyService{
+		monitoringRepo:     monitoringRepo,
+		gpuUsageRepo:       gpuUsageRepo,
+		warningRepo:        warningRepo,
+		integrationService: integrationService,
+		config:             cfg,
+		lastAlert:          make(map[string]time.Time),
+	}
+}
+
+// DetectAll evaluates every endpoint and raises or resolves its anomalies
+func (s *AnomalyService) DetectAll(ctx context.Context) error {
+	endpoints, err := s.monitoringRepo.GetAllEndpoints(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list endpoints: %w", err)
+	}
+
+	now := time.Now()
+	for _, endpoint := range endpoints {
+		report, err := s.Evaluate(ctx, endpoint, now)
+		if err != nil {
+			logger.WarnCtx(ctx, "anomaly detection: endpoint %s skipped: %v", endpoint, err)
+			continue
+		}
+		s.raise(ctx, report, now)
+	}
+	return nil
+}
+
+// Evaluate compares the recent window of an endpoint with its baseline without raising anything
+func (s *AnomalyService) Evaluate(ctx context.Context, endpoint string, now time.Time) (*AnomalyReport, error) {
+	sensitivity, _ := anomaly.ParseSensitivity(s.config.SensitivityFor(endpoint))
+	windowEnd := now.Truncate(time.Minute) // The current minute is not aggregated yet
+	report := &AnomalyReport{
+		Endpoint:    endpoint,
+		Sensitivity: string(sensitivity),
+		WindowStart: windowEnd.Add(-s.config.Window),
+		WindowEnd:   windowEnd,
+		Findings:    make([]anomaly.Finding, 0),
+	}
+	thresholds, ok := anomaly.ThresholdsFor(sensitivity)
+	if !ok {
+		return report, nil
+	}
+
+	current, err := s.windowSample(ctx, endpoint, report.WindowStart, report.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+	baselineEnd := windowEnd.Truncate(time.Hour)
+	baseline, err := s.baselineSamples(ctx, endpoint, baselineEnd.AddDate(0, 0, -s.config.BaselineDays), baselineEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Current = current
+	report.BaselineHours = len(baseline)
+	report.Findings = append(report.Findings, anomaly.Detect(current, baseline, thresholds)...)
+	return report, nil
+}
+
+// windowSample sums the minute statistics of [from, to), GPU hours scaled to an hour
+func (s *AnomalyService) windowSample(ctx context.Context, endpoint string, from, to time.Time) (anomaly.Sample, error) {
+	var sample anomaly.Sample
+	stats, err := s.monitoringRepo.GetMinuteStats(ctx, endpoint, from, to)
+	if err != nil {
+		return sample, fmt.Errorf("failed to get minute stats: %w", err)
+	}
+	var waitTotal float64
+	for _, st := range stats {
+		finished := st.TasksCompleted + st.TasksFailed + st.TasksTimeout
+		sample.Completed += st.TasksCompleted
+		sample.Failed += st.TasksFailed + st.TasksTimeout
+		waitTotal += st.AvgQueueWaitMs * float64(finished)
+	}
+	if finished := sample.Completed + sample.Failed; finished > 0 {
+		sample.QueueWaitMs = waitTotal / float64(finished)
+	}
+
+	if s.gpuUsageRepo != nil {
+		gpuStats, err := s.gpuUsageRepo.GetMinuteStats(ctx, mysqlModel.GPUUsageScopeEndpoint, endpoint, from, to)
+		if err != nil {
+			return sample, fmt.Errorf("failed to get GPU usage minute stats: %w", err)
+		}
+		for _, st := range gpuStats {
+			sample.GPUHours += st.TotalGPUHours
+		}
+		sample.GPUHours *= float64(time.Hour) / float64(to.Sub(from))
+	}
+	return sample, nil
+}
+
+// baselineSamples returns one sample per hour of [from, to) with statistics
+func (s *AnomalyService) baselineSamples(ctx context.Context, endpoint string, from, to time.Time) ([]anomaly.Sample, error) {
+	byHour := make(map[int64]*anomaly.Sample) // Unix hour start -> sample
+	sampleAt := func(hour time.Time) *anomaly.Sample {
+		key := hour.Unix()
+		if byHour[key] == nil {
+			byHour[key] = &anomaly.Sample{}
+		}
+		return byHour[key]
+	}
+
+	stats, err := s.monitoringRepo.GetHourlyStats(ctx, endpoint, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hourly stats: %w", err)
+	}
+	for _, st := range stats {
+		sample := sampleAt(st.StatHour)
+		sample.Completed = st.TasksCompleted
+		sample.Failed = st.TasksFailed + st.TasksTimeout
+		sample.QueueWaitMs = st.AvgQueueWaitMs
+	}
+
+	if s.gpuUsageRepo != nil {
+		gpuStats, err := s.gpuUsageRepo.GetHourlyStats(ctx, mysqlModel.GPUUsageScopeEndpoint, endpoint, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GPU usage hourly stats: %w", err)
+		}
+		for _, st := range gpuStats {
+			sampleAt(st.TimeBucket).GPUHours = st.TotalGPUHours
+		}
+	}
+
+	samples := make([]anomaly.Sample, 0, len(byHour))
+	for _, sample := range byHour {
+		samples = append(samples, *sample)
+	}
+	return samples, nil
+}
+
+// raise records the findings of a report as endpoint warnings, resolves the anomalies that are
+// gone and alerts on findings outside their cooldown
+func (s *AnomalyService) raise(ctx context.Context, report *AnomalyReport, now time.Time) {
+	codes := make([]string, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		codes = append(codes, string(f.Kind))
+		if err := s.warningRepo.Record(ctx, &mysqlModel.EndpointWarning{
+			Endpoint: report.Endpoint,
+			Code:     string(f.Kind),
+			Source:   mysqlModel.EndpointWarningSourceAnomaly,
+			Message:  f.Message,
+			Details: mysqlModel.JSONStringArray{
+				fmt.Sprintf("window: %s - %s", report.WindowStart.Format(time.RFC3339), report.WindowEnd.Format(time.RFC3339)),
+				fmt.Sprintf("current: %.4g, baseline median: %.4g, threshold: %.4g (%d baseline hours)", f.Current, f.Baseline, f.Threshold, f.Samples),
+				"sensitivity: " + report.Sensitivity,
+			},
+			Occurrences: 1,
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+		}); err != nil {
+			logger.WarnCtx(ctx, "failed to record anomaly %s for endpoint %s: %v", f.Kind, report.Endpoint, err)
+		}
+
+		if !s.acquire(report.Endpoint, f.Kind, now) {
+			continue
+		}
+		logger.WarnCtx(ctx, "usage anomaly on endpoint %s: %s: %s", report.Endpoint, f.Kind, f.Message)
+		s.integrationService.Publish(ctx, &notification.Event{
+			Type:      notification.EventAnomaly,
+			Endpoint:  report.Endpoint,
+			CreatedAt: now,
+			Data: &notification.AnomalyNotification{
+				Endpoint:    report.Endpoint,
+				Kind:        string(f.Kind),
+				Message:     f.Message,
+				Current:     f.Current,
+				Baseline:    f.Baseline,
+				Threshold:   f.Threshold,
+				Window:      s.config.Window.String(),
+				Sensitivity: report.Sensitivity,
+				DetectedAt:  now,
+			},
+		})
+	}
+
+	if n, err := s.warningRepo.Resolve(ctx, report.Endpoint, mysqlModel.EndpointWarningSourceAnomaly, codes); err != nil {
+		logger.WarnCtx(ctx, "failed to resolve anomalies for endpoint %s: %v", report.Endpoint, err)
+	} else if n > 0 {
+		logger.InfoCtx(ctx, "resolved %d anomaly warning(s) for endpoint %s", n, report.Endpoint)
+	}
+}
+
+// acquire reports whether an alert of the kind is out of its cooldown and starts a new one
+func (s *AnomalyService) acquire(endpoint string, kind anomaly.Kind, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.Join([]string{endpoint, string(kind)}, "/")
+	if last, ok := s.lastAlert[key]; ok && now.Sub(last) < s.config.Cooldown {
+		return false
+	}
+	s.lastAlert[key] = now
+	return true
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"waverless/pkg/gpuusage"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+const (
+	// batchJobListLimit is the default and maximum number of listed jobs
+	batchJobListLimit = 100
+	// batchJobLogTailLines is how many log lines are kept when a job finishes
+	batchJobLogTailLines = 500
+	// batchJobLogTailBytes bounds the kept log tail
+	batchJobLogTailBytes = 256 * 1024
+	// batchJobMaxTimeout bounds the run time of a job
+	batchJobMaxTimeout = 7 * 24 * time.Hour
+)
+
+// batchJobNamePattern job names become Kubernetes object names (DNS-1123 labels)
+var batchJobNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// SubmitBatchJobRequest is a one-off run of an image on a spec
+type SubmitBatchJobRequest struct {
+	Name           string                   `json:"name" binding:"required"`     // Lowercase letters, digits and '-', at most 63 characters
+	SpecName       string                   `json:"specName" binding:"required"` // Spec to run on
+	Image          string                   `json:"image" binding:"required"`
+	Command        []string                 `json:"command,omitempty"` // Entrypoint override (empty = image entrypoint)
+	Args           []string                 `json:"args,omitempty"`
+	Env            map[string]string        `json:"env,omitempty"`
+	GpuCount       int                      `json:"gpuCount,omitempty"` // Default 1 on GPU specs
+	VolumeMounts   []interfaces.VolumeMount `json:"volumeMounts,omitempty"`
+	ShmSize        string                   `json:"shmSize,omitempty"`        // Default: spec shmSize
+	TimeoutSeconds int                      `json:"timeoutSeconds,omitempty"` // The job fails when it runs longer (0 = no limit)
+
+	// Set for the runs of a job schedule
+	ScheduleName string     `json:"-"`
+	Attempt      int        `json:"-"`
+	ScheduledAt  *time.Time `json:"-"`
+}
+
+// BatchJobService runs one-off jobs (fine-tuning, evaluation) that don't fit the
+// endpoint/queue model: the image runs to completion once on the spec's nodes, its logs can
+// be streamed and its GPU time is recorded with the task GPU usage when it finishes
+type BatchJobService struct {
+	repo            *mysql.BatchJobRepository
+	gpuUsageRepo    *mysql.GPUUsageRepository
+	deployProvider  interfaces.DeploymentProvider
+	scheduleService *JobScheduleService
+}
+
+// NewBatchJobService creates a new batch job service
+func NewBatchJobService(repo *mysql.BatchJobRepository, gpuUsageRepo *mysql.GPUUsageRepository, deployProvider interfaces.DeploymentProvider) *BatchJobService {
+	return &BatchJobService{
+		repo:           repo,
+		gpuUsageRepo:   gpuUsageRepo,
+		deployProvider: deployProvider,
+	}
+}
+
+// SetScheduleService sets the job schedule service told about finished scheduled runs (for dependency injection)
+func (s *BatchJobService) SetScheduleService(scheduleService *JobScheduleService) {
+	s.scheduleService = scheduleService
+}
+
+// runner returns the provider's job support
+func (s *BatchJobService) runner() (interfaces.JobRunner, error) {
+	runner, ok := s.deployProvider.(interfaces.JobRunner)
+	if !ok {
+		return nil, fmt.Errorf("jobs are not supported by the deployment provider")
+	}
+	return runner, nil
+}
+
+// Submit validates and starts a job
+func (s *BatchJobService) Submit(ctx context.Context, req *SubmitBatchJobRequest, createdBy string) (*mysqlModel.BatchJob, error) {
+	runner, err := s.runner()
+	if err != nil {
+		return nil, err
+	}
+	spec, gpuCount, volumeMounts, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.Get(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("invalid job: job %s already exists", req.Name)
+	}
+
+	job := &mysqlModel.BatchJob{
+		Name:           req.Name,
+		SpecName:       req.SpecName,
+		Image:          req.Image,
+		Command:        req.Command,
+		Args:           req.Args,
+		Env:            mysqlModel.StringMapToJSONMap(req.Env),
+		VolumeMounts:   mysqlModel.StringMapToJSONMap(volumeMounts),
+		GpuCount:       gpuCount,
+		GPUType:        spec.Resources.GPUType,
+		ShmSize:        req.ShmSize,
+		TimeoutSeconds: req.TimeoutSeconds,
+		Status:         interfaces.JobPhasePending,
+		CreatedBy:      createdBy,
+		ScheduleName:   req.ScheduleName,
+		Attempt:        req.Attempt,
+		ScheduledAt:    req.ScheduledAt,
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	err = runner.RunJob(ctx, &interfaces.JobRequest{
+		Name:           req.Name,
+		SpecName:       req.SpecName,
+		Image:          req.Image,
+		Command:        req.Command,
+		Args:           req.Args,
+		Env:            req.Env,
+		GpuCount:       gpuCount,
+		VolumeMounts:   req.VolumeMounts,
+		ShmSize:        req.ShmSize,
+		TimeoutSeconds: req.TimeoutSeconds,
+	})
+	if err != nil {
+		// Nothing runs, free the name for a corrected submission
+		if delErr := s.repo.Delete(ctx, req.Name); delErr != nil {
+			logger.WarnCtx(ctx, "failed to remove job %s after failed start: %v", req.Name, delErr)
+		}
+		return nil, fmt.Errorf("failed to start job %s: %w", req.Name, err)
+	}
+	return job, nil
+}
+
+// validate checks a job request and returns its spec, GPU count and volume mounts (mount path
+// -> PVC name)
+func (s *BatchJobService) validate(ctx context.Context, req *SubmitBatchJobRequest) (*interfaces.SpecInfo, int, map[string]string, error) {
+	if !batchJobNamePattern.MatchString(req.Name) {
+		return nil, 0, nil, fmt.Errorf("invalid job name %q: must consist of lowercase alphanumeric characters or '-', start and end with an alphanumeric character, at most 63 characters", req.Name)
+	}
+	if req.GpuCount < 0 {
+		return nil, 0, nil, fmt.Errorf("invalid job: gpuCount must be >= 0")
+	}
+	if req.TimeoutSeconds < 0 || time.Duration(req.TimeoutSeconds)*time.Second > batchJobMaxTimeout {
+		return nil, 0, nil, fmt.Errorf("invalid job: timeoutSeconds must be between 0 and %d", int(batchJobMaxTimeout.Seconds()))
+	}
+	mountPaths := make(map[string]bool, len(req.VolumeMounts))
+	volumeMounts := make(map[string]string, len(req.VolumeMounts))
+	for _, vm := range req.VolumeMounts {
+		if vm.PVCName == "" || !strings.HasPrefix(vm.MountPath, "/") {
+			return nil, 0, nil, fmt.Errorf("invalid job: volume mounts need a pvcName and an absolute mountPath")
+		}
+		if mountPaths[vm.MountPath] {
+			return nil, 0, nil, fmt.Errorf("invalid job: mount path %s is used more than once", vm.MountPath)
+		}
+		mountPaths[vm.MountPath] = true
+		volumeMounts[vm.MountPath] = vm.PVCName
+	}
+	spec, err := s.deployProvider.GetSpec(ctx, req.SpecName)
+	if err != nil || spec == nil {
+		return nil, 0, nil, fmt.Errorf("invalid job: spec %s not found", req.SpecName)
+	}
+	gpuCount := 0
+	if spec.Category == "gpu" {
+		gpuCount = max(req.GpuCount, 1)
+	}
+	return spec, gpuCount, volumeMounts, nil
+}
+
+// Get returns a job, with its state refreshed from the provider while it runs
+func (s *BatchJobService) Get(ctx context.Context, name string) (*mysqlModel.BatchJob, error) {
+	job, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job %s not found", name)
+	}
+	s.refresh(ctx, job)
+	return job, nil
+}
+
+// refresh updates an unfinished job from the provider; failures are logged only
+func (s *BatchJobService) refresh(ctx context.Context, job *mysqlModel.BatchJob) {
+	if !isActiveBatchJob(job) {
+		return
+	}
+	if runner, err := s.runner(); err == nil {
+		if err := s.syncJob(ctx, runner, job); err != nil {
+			logger.WarnCtx(ctx, "failed to refresh job %s: %v", job.Name, err)
+		}
+	}
+}
+
+// List returns the most recent jobs, optionally filtered by status, skipping the first offset
+func (s *BatchJobService) List(ctx context.Context, status string, limit, offset int) ([]*mysqlModel.BatchJob, error) {
+	if limit <= 0 || limit > batchJobListLimit {
+		limit = batchJobListLimit
+	}
+	return s.repo.List(ctx, status, limit, offset)
+}
+
+// Count returns the number of jobs, optionally filtered by status
+func (s *BatchJobService) Count(ctx context.Context, status string) (int64, error) {
+	return s.repo.Count(ctx, status)
+}
+
+// Cancel stops a pending or running job. Its GPU time up to now is recorded.
+func (s *BatchJobService) Cancel(ctx context.Context, name string) (*mysqlModel.BatchJob, error) {
+	runner, err := s.runner()
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job %s not found", name)
+	}
+	if !isActiveBatchJob(job) {
+		return nil, fmt.Errorf("invalid cancel: job %s already %s", name, job.Status)
+	}
+
+	// Keep the logs before the pod is deleted
+	logs := s.logTail(ctx, runner, name)
+	if err := runner.DeleteJob(ctx, name); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	job.Status = mysqlModel.BatchJobStatusCancelled
+	job.Message = "cancelled"
+	job.CompletedAt = &now
+	job.Logs = logs
+	if err := s.finish(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Logs returns the logs of a job. While the provider keeps the job they come from its pod
+// (following them if asked); afterwards the tail stored when the job finished is returned.
+func (s *BatchJobService) Logs(ctx context.Context, name string, follow bool, tailLines int) (io.ReadCloser, error) {
+	job, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job %s not found", name)
+	}
+	runner, err := s.runner()
+	if err != nil {
+		return nil, err
+	}
+	stream, err := runner.StreamJobLogs(ctx, name, follow && isActiveBatchJob(job), tailLines)
+	if err == nil {
+		return stream, nil
+	}
+	if isActiveBatchJob(job) || job.Logs == "" {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(job.Logs)), nil
+}
+
+// Sync refreshes the state of all unfinished jobs; finished jobs get their log tail and GPU
+// usage recorded
+func (s *BatchJobService) Sync(ctx context.Context) error {
+	runner, err := s.runner()
+	if err != nil {
+		return nil
+	}
+	jobs, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := s.syncJob(ctx, runner, job); err != nil {
+			logger.WarnCtx(ctx, "failed to sync job %s: %v", job.Name, err)
+		}
+	}
+	return nil
+}
+
+// syncJob applies the provider state of an unfinished job
+func (s *BatchJobService) syncJob(ctx context.Context, runner interfaces.JobRunner, job *mysqlModel.BatchJob) error {
+	status, err := runner.GetJobStatus(ctx, job.Name)
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		// Removed outside Waverless
+		now := time.Now()
+		job.Status = interfaces.JobPhaseFailed
+		job.Message = "job no longer exists in the deployment provider"
+		job.CompletedAt = &now
+		return s.finish(ctx, job)
+	}
+
+	job.PodName = status.PodName
+	job.Message = status.Message
+	if status.StartedAt != nil {
+		job.StartedAt = status.StartedAt
+	}
+	if status.ExitCode != nil {
+		exitCode := int(*status.ExitCode)
+		job.ExitCode = &exitCode
+	}
+
+	if status.Phase == interfaces.JobPhaseSucceeded || status.Phase == interfaces.JobPhaseFailed {
+		job.Status = status.Phase
+		job.CompletedAt = status.CompletedAt
+		if job.CompletedAt == nil {
+			now := time.Now()
+			job.CompletedAt = &now
+		}
+		job.Logs = s.logTail(ctx, runner, job.Name)
+		return s.finish(ctx, job)
+	}
+
+	if status.Phase != job.Status {
+		logger.InfoCtx(ctx, "job %s is %s", job.Name, status.Phase)
+	}
+	job.Status = status.Phase
+	_, err = s.repo.Update(ctx, job.Name, map[string]interface{}{
+		"status":     job.Status,
+		"message":    truncateBatchJobMessage(job.Message),
+		"pod_name":   job.PodName,
+		"started_at": job.StartedAt,
+	})
+	return err
+}
+
+// finish stores the final state of a job and records its GPU usage
+func (s *BatchJobService) finish(ctx context.Context, job *mysqlModel.BatchJob) error {
+	if job.StartedAt != nil && job.CompletedAt != nil && job.GpuCount > 0 {
+		job.GPUHours = float64(job.GpuCount) * max(job.CompletedAt.Sub(*job.StartedAt), 0).Hours()
+	}
+	updated, err := s.repo.Update(ctx, job.Name, map[string]interface{}{
+		"status":       job.Status,
+		"message":      truncateBatchJobMessage(job.Message),
+		"pod_name":     job.PodName,
+		"exit_code":    job.ExitCode,
+		"started_at":   job.StartedAt,
+		"completed_at": job.CompletedAt,
+		"gpu_hours":    job.GPUHours,
+		"logs":         job.Logs,
+	})
+	if err != nil || !updated {
+		// Not updated: another replica finished the job first
+		return err
+	}
+	logger.InfoCtx(ctx, "job %s %s, gpu_hours: %.4f, message: %s", job.Name, job.Status, job.GPUHours, job.Message)
+
+	if job.GPUHours > 0 && s.gpuUsageRepo != nil {
+		duration := job.CompletedAt.Sub(*job.StartedAt)
+		_, err := s.gpuUsageRepo.CreateRecord(ctx, &mysqlModel.GPUUsageRecord{
+			TaskID:          "job:" + job.Name,
+			Endpoint:        "job:" + job.Name,
+			WorkerID:        job.PodName,
+			SpecName:        job.SpecName,
+			GPUCount:        job.GpuCount,
+			GPUType:         job.GPUType,
+			GPUMemoryGB:     gpuusage.ParseGPUMemoryGB(job.GPUType),
+			StartedAt:       *job.StartedAt,
+			CompletedAt:     *job.CompletedAt,
+			DurationSeconds: int(duration.Seconds()),
+			GPUHours:        job.GPUHours,
+			Status:          job.Status,
+		})
+		if err != nil {
+			logger.WarnCtx(ctx, "failed to record gpu usage for job %s: %v", job.Name, err)
+		}
+	}
+	if job.ScheduleName != "" && s.scheduleService != nil {
+		s.scheduleService.runFinished(ctx, job)
+	}
+	return nil
+}
+
+// logTail returns the last lines of a job's logs, empty when they can't be read
+func (s *BatchJobService) logTail(ctx context.Context, runner interfaces.JobRunner, name string) string {
+	stream, err := runner.StreamJobLogs(ctx, name, false, batchJobLogTailLines)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to read logs of job %s: %v", name, err)
+		return ""
+	}
+	defer stream.Close()
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(stream, batchJobLogTailBytes)); err != nil {
+		logger.WarnCtx(ctx, "failed to read logs of job %s: %v", name, err)
+	}
+	return buf.String()
+}
+
+func isActiveBatchJob(job *mysqlModel.BatchJob) bool {
+	return job.Status == interfaces.JobPhasePending || job.Status == interfaces.JobPhaseRunning
+}
+
+func truncateBatchJobMessage(message string) string {
+	if len(message) > 1024 {
+		return message[:1024]
+	}
+	return message
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/constants"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// Blue/green defaults, used for unset options
+const (
+	DefaultBlueGreenReadyTimeout   = 15 * time.Minute
+	DefaultBlueGreenBakeWindow     = 5 * time.Minute
+	DefaultBlueGreenMaxFailureRate = 0.2
+	DefaultBlueGreenMinTasks       = 10
+
+	blueGreenPollInterval = 10 * time.Second
+	// blueGreenCleanupTimeout bounds the rollback after the operation's context is done
+	blueGreenCleanupTimeout = time.Minute
+	// blueGreenQuarantineReason marks the old workers taken out of task routing
+	blueGreenQuarantineReason = "blue/green update: tasks shifted to the new workers"
+)
+
+// Progress of blue/green updates by phase
+const (
+	progressCandidateDeployed = 10
+	progressCandidateReady    = 40
+	progressBaked             = 70
+)
+
+// BlueGreenService runs blue/green updates: the update is deployed as a second deployment,
+// tasks move to its workers once all of them are ready, and the live deployment is only
+// updated after they stayed healthy for the bake window. Otherwise the old workers take tasks
+// again and the candidate is deleted.
+type BlueGreenService struct {
+	endpointService   *endpointsvc.Service
+	workerRepo        *mysql.WorkerRepository
+	quarantineService *WorkerQuarantineService
+	pollInterval      time.Duration
+}
+
+// NewBlueGreenService creates a new blue/green update service
+func NewBlueGreenService(endpointService *endpointsvc.Service, workerRepo *mysql.WorkerRepository, quarantineService *WorkerQuarantineService) *BlueGreenService {
+	return &BlueGreenService{
+		endpointService:   endpointService,
+		workerRepo:        workerRepo,
+		quarantineService: quarantineService,
+		pollInterval:      blueGreenPollInterval,
+	}
+}
+
+// blueGreenOptions are BlueGreenOptions with the defaults applied
+type blueGreenOptions struct {
+	readyTimeout         time.Duration
+	bakeWindow           time.Duration
+	maxFailureRate       float64
+	minTasks             int64
+	maxImagePullFailures int
+}
+
+func resolveBlueGreenOptions(opts *interfaces.BlueGreenOptions) blueGreenOptions {
+	resolved := blueGreenOptions{
+		readyTimeout:   DefaultBlueGreenReadyTimeout,
+		bakeWindow:     DefaultBlueGreenBakeWindow,
+		maxFailureRate: DefaultBlueGreenMaxFailureRate,
+		minTasks:       DefaultBlueGreenMinTasks,
+	}
+	if opts == nil {
+		return resolved
+	}
+	if opts.ReadyTimeout > 0 {
+		resolved.readyTimeout = time.Duration(opts.ReadyTimeout) * time.Second
+	}
+	if opts.BakeWindow > 0 {
+		resolved.bakeWindow = time.Duration(opts.BakeWindow) * time.Second
+	}
+	if opts.MaxFailureRate > 0 {
+		resolved.maxFailureRate = opts.MaxFailureRate
+	}
+	if opts.MinTasks > 0 {
+		resolved.minTasks = int64(opts.MinTasks)
+	}
+	if opts.MaxImagePullFailures > 0 {
+		resolved.maxImagePullFailures = opts.MaxImagePullFailures
+	}
+	return resolved
+}
+
+// ValidateBlueGreen checks that an update can run as a blue/green update
+func (s *BlueGreenService) ValidateBlueGreen(ctx context.Context, req *interfaces.UpdateDeploymentRequest) error {
+	opts := req.BlueGreen
+	if opts == nil {
+		return fmt.Errorf("invalid blue/green update: options are required")
+	}
+	if opts.ReadyTimeout < 0 || opts.BakeWindow < 0 || opts.MinTasks < 0 || opts.MaxImagePullFailures < 0 {
+		return fmt.Errorf("invalid blue/green update: timeouts and thresholds must not be negative")
+	}
+	if opts.MaxFailureRate < 0 || opts.MaxFailureRate > 1 {
+		return fmt.Errorf("invalid blue/green update: maxFailureRate must be between 0 and 1")
+	}
+	if req.Replicas != nil && *req.Replicas == 0 {
+		return fmt.Errorf("invalid blue/green update: replicas must not be 0")
+	}
+	if !s.endpointService.SupportsBlueGreen(ctx, req.Endpoint) {
+		return endpointsvc.ErrBlueGreenUnsupported
+	}
+	return nil
+}
+
+// blueGreenRollback is the cause of an automatic rollback
+type blueGreenRollback struct {
+	reason string
+}
+
+func (e *blueGreenRollback) Error() string {
+	return "rolled back: " + e.reason
+}
+
+// IsBlueGreenRollback reports whether a blue/green update failed because the new workers
+// regressed (as opposed to an error running it)
+func IsBlueGreenRollback(err error) bool {
+	var rollback *blueGreenRollback
+	return errors.As(err, &rollback)
+}
+
+// Run performs a blue/green update, reporting progress. On any failure, including ctx being
+// cancelled, the old workers take tasks again and the candidate is deleted.
+func (s *BlueGreenService) Run(ctx context.Context, req *interfaces.UpdateDeploymentRequest, by string, report func(percent int, message string)) error {
+	opts := resolveBlueGreenOptions(req.BlueGreen)
+	update := *req
+	update.BlueGreen = nil
+	name := update.Endpoint
+
+	report(0, "deploying new workers")
+	resolved, err := s.endpointService.DeployCandidate(ctx, &update)
+	if err != nil {
+		return err
+	}
+
+	var blue []string
+	rollback := func(cause error) error {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), blueGreenCleanupTimeout)
+		defer cancel()
+		s.release(cleanupCtx, blue)
+		if err := s.endpointService.DeleteCandidate(cleanupCtx, name); err != nil {
+			logger.ErrorCtx(ctx, "blue/green update of %s: failed to delete the candidate deployment: %v", name, err)
+		}
+		logger.WarnCtx(ctx, "blue/green update of %s rolled back: %v", name, cause)
+		return cause
+	}
+
+	report(progressCandidateDeployed, "waiting for new workers")
+	green, err := s.waitForCandidate(ctx, name, opts, report)
+	if err != nil {
+		return rollback(err)
+	}
+
+	blue, err = s.shiftTasks(ctx, name, green, by)
+	if err != nil {
+		return rollback(err)
+	}
+	report(progressCandidateReady, fmt.Sprintf("tasks shifted to %d new workers, baking for %s", len(green), opts.bakeWindow))
+
+	if err := s.bake(ctx, name, green, opts, report); err != nil {
+		return rollback(err)
+	}
+
+	report(progressBaked, "promoting the update to the live deployment")
+	if _, err := s.endpointService.PromoteCandidate(ctx, &update, resolved); err != nil {
+		return rollback(err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, opts.readyTimeout)
+	defer cancel()
+	err = s.endpointService.WaitForRollout(waitCtx, name, s.pollInterval, func(status *interfaces.AppStatus) {
+		report(progressBaked, fmt.Sprintf("live deployment: %d/%d workers ready (%s)", status.ReadyReplicas, status.TotalReplicas, status.Status))
+	})
+
+	// The live deployment runs the update now (or is getting there), so the candidate goes
+	// either way; old workers that survived the rollout take tasks again
+	cleanupCtx, cleanupCancel := context.WithTimeout(context.WithoutCancel(ctx), blueGreenCleanupTimeout)
+	defer cleanupCancel()
+	s.release(cleanupCtx, blue)
+	if deleteErr := s.endpointService.DeleteCandidate(cleanupCtx, name); deleteErr != nil {
+		logger.ErrorCtx(ctx, "blue/green update of %s: failed to delete the candidate deployment: %v", name, deleteErr)
+	}
+	if err != nil {
+		return fmt.Errorf("update promoted but the live deployment did not roll out: %w", err)
+	}
+	report(100, "blue/green update complete")
+	return nil
+}
+
+// waitForCandidate waits until the candidate rolled out and every one of its workers
+// registered and takes tasks, and returns them. Image pull failures beyond the threshold end
+// the wait early.
+func (s *BlueGreenService) waitForCandidate(ctx context.Context, name string, opts blueGreenOptions, report func(int, string)) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.readyTimeout)
+	defer cancel()
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := s.endpointService.CandidateStatus(ctx, name)
+		if err == nil {
+			var green []string
+			green, err = s.endpointService.CandidateWorkers(ctx, name)
+			if err == nil {
+				workers := s.getWorkers(ctx, green)
+				if pulls := countImagePullFailures(workers); pulls > opts.maxImagePullFailures {
+					return nil, &blueGreenRollback{reason: fmt.Sprintf("%d new workers failed to pull the image", pulls)}
+				}
+				ready := countReady(workers)
+				report(progressCandidateDeployed, fmt.Sprintf("%d/%d new workers ready", ready, status.TotalReplicas))
+				if status.RolloutComplete && status.TotalReplicas > 0 && len(green) == int(status.TotalReplicas) && ready == len(green) {
+					return green, nil
+				}
+			}
+		}
+		if err != nil {
+			logger.WarnCtx(ctx, "blue/green update of %s: failed to check the new workers: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, &blueGreenRollback{reason: fmt.Sprintf("new workers not ready within %s", opts.readyTimeout)}
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// shiftTasks quarantines the endpoint's old workers so that only the new ones get tasks, and
+// returns the workers it quarantined
+func (s *BlueGreenService) shiftTasks(ctx context.Context, name string, green []string, by string) ([]string, error) {
+	workers, err := s.workerRepo.GetByEndpoint(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	isGreen := make(map[string]bool, len(green))
+	for _, id := range green {
+		isGreen[id] = true
+	}
+
+	var blue []string
+	for _, worker := range workers {
+		if isGreen[worker.WorkerID] || worker.QuarantinedAt != nil {
+			continue
+		}
+		if _, err := s.quarantineService.Quarantine(ctx, worker.WorkerID, blueGreenQuarantineReason, by); err != nil {
+			return blue, err
+		}
+		blue = append(blue, worker.WorkerID)
+	}
+	return blue, nil
+}
+
+// bake watches the new workers for the bake window and fails if their task failure rate or
+// image pull failures cross the thresholds
+func (s *BlueGreenService) bake(ctx context.Context, name string, green []string, opts blueGreenOptions, report func(int, string)) error {
+	baseline := make(map[string]*model.Worker, len(green))
+	for _, worker := range s.getWorkers(ctx, green) {
+		baseline[worker.WorkerID] = worker
+	}
+
+	deadline := time.Now().Add(opts.bakeWindow)
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		workers := s.getWorkers(ctx, green)
+		if pulls := countImagePullFailures(workers); pulls > opts.maxImagePullFailures {
+			return &blueGreenRollback{reason: fmt.Sprintf("%d new workers failed to pull the image", pulls)}
+		}
+		var completed, failed int64
+		for _, worker := range workers {
+			completed += worker.TotalTasksCompleted
+			failed += worker.TotalTasksFailed
+			if before, ok := baseline[worker.WorkerID]; ok {
+				completed -= before.TotalTasksCompleted
+				failed -= before.TotalTasksFailed
+			}
+		}
+		if total := completed + failed; total >= opts.minTasks && total > 0 {
+			if rate := float64(failed) / float64(total); rate > opts.maxFailureRate {
+				return &blueGreenRollback{reason: fmt.Sprintf("new workers failed %d of %d tasks (%.0f%% > %.0f%%)", failed, total, rate*100, opts.maxFailureRate*100)}
+			}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			logger.InfoCtx(ctx, "blue/green update of %s baked: %d tasks completed, %d failed", name, completed, failed)
+			return nil
+		}
+		elapsed := opts.bakeWindow - remaining
+		percent := progressCandidateReady + int(float64(progressBaked-progressCandidateReady)*elapsed.Seconds()/opts.bakeWindow.Seconds())
+		report(percent, fmt.Sprintf("baking: %d tasks completed, %d failed, %s left", completed, failed, remaining.Round(time.Second)))
+	}
+}
+
+// release lets the given workers take tasks again, skipping those already gone
+func (s *BlueGreenService) release(ctx context.Context, workerIDs []string) {
+	for _, id := range workerIDs {
+		if err := s.quarantineService.Release(ctx, id); err != nil {
+			logger.WarnCtx(ctx, "blue/green update: failed to release worker %s: %v", id, err)
+		}
+	}
+}
+
+// getWorkers returns the worker records of the given workers, skipping unregistered ones
+func (s *BlueGreenService) getWorkers(ctx context.Context, workerIDs []string) []*model.Worker {
+	workers := make([]*model.Worker, 0, len(workerIDs))
+	for _, id := range workerIDs {
+		if worker, err := s.workerRepo.Get(ctx, id); err == nil {
+			workers = append(workers, worker)
+		}
+	}
+	return workers
+}
+
+// countReady counts the workers that registered and take tasks
+func countReady(workers []*model.Worker) int {
+	ready := 0
+	for _, worker := range workers {
+		if worker.RegisteredAt == nil || worker.QuarantinedAt != nil {
+			continue
+		}
+		if worker.Status == constants.WorkerStatusOnline.String() || worker.Status == constants.WorkerStatusBusy.String() {
+			ready++
+		}
+	}
+	return ready
+}
+
+// countImagePullFailures counts the workers that failed to pull their image
+func countImagePullFailures(workers []*model.Worker) int {
+	count := 0
+	for _, worker := range workers {
+		if worker.FailureType == string(interfaces.FailureTypeImagePull) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
+)
+
+var (
+	// ErrApprovalUnauthorized is returned when the caller has no valid approval token
+	ErrApprovalUnauthorized = errors.New("approval token required")
+	// ErrApprovalForbidden is returned when the reviewer may not approve a change request
+	ErrApprovalForbidden = errors.New("approval not allowed")
+	// ErrChangeRequestClosed is returned when a change request is no longer pending
+	ErrChangeRequestClosed = errors.New("change request is not pending")
+)
+
+// ChangeExecutor applies the operation of an approved change request
+type ChangeExecutor func(ctx context.Context, cr *model.ChangeRequest) error
+
+// ChangeRequestStore is the part of ChangeRequestRepository the service uses
+type ChangeRequestStore interface {
+	Create(ctx context.Context, cr *model.ChangeRequest) error
+	Get(ctx context.Context, id int64) (*model.ChangeRequest, error)
+	List(ctx context.Context, endpoint, status string, limit, offset int) ([]*model.ChangeRequest, error)
+	Count(ctx context.Context, endpoint, status string) (int64, error)
+	Transition(ctx context.Context, id int64, from, to string, updates map[string]interface{}) (bool, error)
+	ExpirePending(ctx context.Context, now time.Time) (int64, error)
+}
+
+// EndpointLookup reads the metadata whose labels decide whether an endpoint is protected
+// (implemented by the endpoint service)
+type EndpointLookup interface {
+	GetEndpoint(ctx context.Context, name string) (*interfaces.EndpointMetadata, error)
+}
+
+// approvalIdentity is a configured requester or approver
+type approvalIdentity struct {
+	name     string
+	token    []byte
+	approver bool
+}
+
+// ChangeRequestService holds mutating operations on protected endpoints until a second
+// identity approves them. Every step is written to the change_requests table and the audit log.
+type ChangeRequestService struct {
+	repo            ChangeRequestStore
+	endpointService EndpointLookup
+	cfg             config.ApprovalConfig
+	identities      []approvalIdentity
+	executors       map[string]ChangeExecutor
+}
+
+// NewChangeRequestService creates a new change request service. With the gate enabled it
+// fails unless approvers are configured, so a missing list cannot leave the gate open.
+func NewChangeRequestService(repo ChangeRequestStore, endpointService EndpointLookup, cfg config.ApprovalConfig) (*ChangeRequestService, error) {
+	identities, err := approvalIdentities(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ChangeRequestService{
+		repo:            repo,
+		endpointService: endpointService,
+		cfg:             cfg,
+		identities:      identities,
+		executors:       make(map[string]ChangeExecutor),
+	}, nil
+}
+
+// approvalIdentities validates the configured approvers and requesters
+func approvalIdentities(cfg config.ApprovalConfig) ([]approvalIdentity, error) {
+	if cfg.Enabled && len(cfg.Approvers) == 0 {
+		return nil, fmt.Errorf("invalid approval config: approvers are required when approval is enabled")
+	}
+	if cfg.Enabled && len(cfg.Approvers)+len(cfg.Requesters) < 2 {
+		return nil, fmt.Errorf("invalid approval config: at least two identities are needed, nobody can approve their own change")
+	}
+
+	identities := make([]approvalIdentity, 0, len(cfg.Approvers)+len(cfg.Requesters))
+	names := make(map[string]bool)
+	tokens := make(map[string]bool)
+	add := func(id config.ApprovalIdentity, approver bool) error {
+		name := strings.TrimSpace(id.Name)
+		if name == "" {
+			return fmt.Errorf("invalid approval config: every approver and requester needs a name")
+		}
+		if len(id.Token) < 16 {
+			return fmt.Errorf("invalid approval config: token of %s must be at least 16 characters", name)
+		}
+		if names[name] {
+			return fmt.Errorf("invalid approval config: %s is listed twice", name)
+		}
+		if tokens[id.Token] {
+			return fmt.Errorf("invalid approval config: token of %s is shared with another identity", name)
+		}
+		names[name], tokens[id.Token] = true, true
+		identities = append(identities, approvalIdentity{name: name, token: []byte(id.Token), approver: approver})
+		return nil
+	}
+	for _, id := range cfg.Approvers {
+		if err := add(id, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range cfg.Requesters {
+		if err := add(id, false); err != nil {
+			return nil, err
+		}
+	}
+	return identities, nil
+}
+
+// authenticate returns the identity an approval token belongs to
+func (s *ChangeRequestService) authenticate(token string) (*approvalIdentity, error) {
+	if token == "" {
+		return nil, ErrApprovalUnauthorized
+	}
+	var found *approvalIdentity
+	// Every token is compared, so the time taken does not reveal which one matched
+	for i := range s.identities {
+		if subtle.ConstantTimeCompare([]byte(token), s.identities[i].token) == 1 {
+			found = &s.identities[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: unknown token", ErrApprovalUnauthorized)
+	}
+	return found, nil
+}
+
+// RegisterExecutor sets how approved change requests of an operation are applied
+func (s *ChangeRequestService) RegisterExecutor(operation string, executor ChangeExecutor) {
+	s.executors[operation] = executor
+}
+
+// RequiresApproval reports whether a change to an endpoint must be approved: the gate is
+// enabled and the endpoint carries a protected label, now or after the change
+func (s *ChangeRequestService) RequiresApproval(ctx context.Context, endpoint string, proposedLabels map[string]string) (bool, error) {
+	if !s.cfg.Enabled {
+		return false, nil
+	}
+	if s.isProtected(proposedLabels) {
+		return true, nil
+	}
+	if s.endpointService == nil {
+		return false, nil
+	}
+	meta, err := s.endpointService.GetEndpoint(ctx, endpoint)
+	if err != nil {
+		return false, fmt.Errorf("failed to get endpoint: %w", err)
+	}
+	return meta != nil && s.isProtected(meta.Labels), nil
+}
+
+func (s *ChangeRequestService) isProtected(labels map[string]string) bool {
+	for k, v := range s.cfg.Labels {
+		if labels[k] == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Submit stores an operation as a pending change request of the identity token belongs to
+func (s *ChangeRequestService) Submit(ctx context.Context, endpoint, operation string, payload interface{}, token string) (*model.ChangeRequest, error) {
+	requester, err := s.authenticate(token)
+	if err != nil {
+		return nil, err
+	}
+	requestedBy := requester.name
+	if _, ok := s.executors[operation]; !ok {
+		return nil, fmt.Errorf("unsupported change operation: %s", operation)
+	}
+
+	cr := &model.ChangeRequest{
+		Endpoint:    endpoint,
+		Operation:   operation,
+		Status:      model.ChangeStatusPending,
+		RequestedBy: requestedBy,
+		ExpiresAt:   time.Now().Add(s.cfg.TTL),
+	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode change payload: %w", err)
+		}
+		if err := json.Unmarshal(data, &cr.Payload); err != nil {
+			return nil, fmt.Errorf("failed to encode change payload: %w", err)
+		}
+	}
+	if err := s.repo.Create(ctx, cr); err != nil {
+		return nil, err
+	}
+
+	logger.InfoCtx(ctx, "[AUDIT] change request created: id=%d, endpoint=%s, operation=%s, requestedBy=%s",
+		cr.ID, cr.Endpoint, cr.Operation, cr.RequestedBy)
+	return cr, nil
+}
+
+// Get returns a change request
+func (s *ChangeRequestService) Get(ctx context.Context, id int64) (*model.ChangeRequest, error) {
+	s.expire(ctx)
+	cr, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cr == nil {
+		return nil, fmt.Errorf("change request %d not found", id)
+	}
+	return cr, nil
+}
+
+// List returns change requests, newest first, skipping the first offset
+func (s *ChangeRequestService) List(ctx context.Context, endpoint, status string, limit, offset int) ([]*model.ChangeRequest, error) {
+	s.expire(ctx)
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.List(ctx, endpoint, status, limit, offset)
+}
+
+// Count returns the number of change requests, optionally filtered by endpoint and status
+func (s *ChangeRequestService) Count(ctx context.Context, endpoint, status string) (int64, error) {
+	return s.repo.Count(ctx, endpoint, status)
+}
+
+// Approve approves a pending change request and applies it. The approver is the identity
+// token belongs to. The returned request carries the outcome; an execution failure is
+// recorded on it, not returned as an error.
+func (s *ChangeRequestService) Approve(ctx context.Context, id int64, token, comment string) (*model.ChangeRequest, error) {
+	reviewer, err := s.authenticate(token)
+	if err != nil {
+		return nil, err
+	}
+	approver := reviewer.name
+	cr, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cr.Status != model.ChangeStatusPending {
+		return nil, fmt.Errorf("%w: %s", ErrChangeRequestClosed, cr.Status)
+	}
+	if approver == cr.RequestedBy {
+		return nil, fmt.Errorf("%w: requester cannot approve their own change", ErrApprovalForbidden)
+	}
+	if !reviewer.approver {
+		return nil, fmt.Errorf("%w: %s is not an approver", ErrApprovalForbidden, approver)
+	}
+	executor, ok := s.executors[cr.Operation]
+	if !ok {
+		return nil, fmt.Errorf("unsupported change operation: %s", cr.Operation)
+	}
+
+	now := time.Now()
+	claimed, err := s.repo.Transition(ctx, cr.ID, model.ChangeStatusPending, model.ChangeStatusApproved, map[string]interface{}{
+		"reviewed_by":    approver,
+		"review_comment": comment,
+		"reviewed_at":    now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("%w: reviewed concurrently", ErrChangeRequestClosed)
+	}
+	cr.Status, cr.ReviewedBy, cr.ReviewComment, cr.ReviewedAt = model.ChangeStatusApproved, approver, comment, &now
+	logger.InfoCtx(ctx, "[AUDIT] change request approved: id=%d, endpoint=%s, operation=%s, requestedBy=%s, approvedBy=%s",
+		cr.ID, cr.Endpoint, cr.Operation, cr.RequestedBy, approver)
+
+	execErr := executor(ctx, cr)
+	executedAt := time.Now()
+	updates := map[string]interface{}{"executed_at": executedAt}
+	cr.Status, cr.ExecutedAt = model.ChangeStatusExecuted, &executedAt
+	if execErr != nil {
+		cr.Status, cr.Error = model.ChangeStatusFailed, execErr.Error()
+		if len(cr.Error) > 1024 {
+			cr.Error = cr.Error[:1024]
+		}
+		updates["error"] = cr.Error
+		logger.ErrorCtx(ctx, "[AUDIT] change request failed: id=%d, endpoint=%s, operation=%s, error=%v",
+			cr.ID, cr.Endpoint, cr.Operation, execErr)
+	} else {
+		logger.InfoCtx(ctx, "[AUDIT] change request executed: id=%d, endpoint=%s, operation=%s",
+			cr.ID, cr.Endpoint, cr.Operation)
+	}
+	if _, err := s.repo.Transition(ctx, cr.ID, model.ChangeStatusApproved, cr.Status, updates); err != nil {
+		logger.ErrorCtx(ctx, "failed to record change request %d outcome: %v", cr.ID, err)
+	}
+	return cr, nil
+}
+
+// Reject rejects a pending change request; the requester may withdraw their own
+func (s *ChangeRequestService) Reject(ctx context.Context, id int64, token, comment string) (*model.ChangeRequest, error) {
+	identity, err := s.authenticate(token)
+	if err != nil {
+		return nil, err
+	}
+	reviewer := identity.name
+	cr, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cr.Status != model.ChangeStatusPending {
+		return nil, fmt.Errorf("%w: %s", ErrChangeRequestClosed, cr.Status)
+	}
+	if reviewer != cr.RequestedBy && !identity.approver {
+		return nil, fmt.Errorf("%w: %s is not an approver", ErrApprovalForbidden, reviewer)
+	}
+
+	now := time.Now()
+	rejected, err := s.repo.Transition(ctx, cr.ID, model.ChangeStatusPending, model.ChangeStatusRejected, map[string]interface{}{
+		"reviewed_by":    reviewer,
+		"review_comment": comment,
+		"reviewed_at":    now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !rejected {
+		return nil, fmt.Errorf("%w: reviewed concurrently", ErrChangeRequestClosed)
+	}
+	cr.Status, cr.ReviewedBy, cr.ReviewComment, cr.ReviewedAt = model.ChangeStatusRejected, reviewer, comment, &now
+
+	logger.InfoCtx(ctx, "[AUDIT] change request rejected: id=%d, endpoint=%s, operation=%s, requestedBy=%s, rejectedBy=%s, comment=%s",
+		cr.ID, cr.Endpoint, cr.Operation, cr.RequestedBy, reviewer, comment)
+	return cr, nil
+}
+
+func (s *ChangeRequestService) expire(ctx context.Context) {
+	if n, err := s.repo.ExpirePending(ctx, time.Now()); err != nil {
+		logger.WarnCtx(ctx, "failed to expire change requests: %v", err)
+	} else if n > 0 {
+		logger.InfoCtx(ctx, "[AUDIT] %d change request(s) expired without review", n)
+	}
+}
+
+// DecodeChangePayload decodes the stored request body of a change request into v
+func DecodeChangePayload(cr *model.ChangeRequest, v interface{}) error {
+	data, err := json.Marshal(cr.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to decode change payload: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode change payload: %w", err)
+	}
+	return nil
+}
+
+// RedactChangeRequest returns a copy of a change request safe to return from the API
+// (registry passwords in deploy payloads and secret env values are masked)
+func RedactChangeRequest(cr *model.ChangeRequest) *model.ChangeRequest {
+	redacted := *cr
+	cred, hasCred := cr.Payload["registryCredential"].(map[string]interface{})
+	secrets, hasSecrets := cr.Payload["secrets"].(map[string]interface{})
+	if !hasCred && !hasSecrets {
+		return &redacted
+	}
+
+	payload := make(model.JSONMap, len(cr.Payload))
+	for k, v := range cr.Payload {
+		payload[k] = v
+	}
+	if hasCred {
+		masked := make(map[string]interface{}, len(cred))
+		for k, v := range cred {
+			masked[k] = v
+		}
+		if _, ok := masked["password"]; ok {
+			masked["password"] = "[REDACTED]"
+		}
+		payload["registryCredential"] = masked
+	}
+	if hasSecrets {
+		masked := make(map[string]interface{}, len(secrets))
+		for k := range secrets {
+			masked[k] = "[REDACTED]"
+		}
+		payload["secrets"] = masked
+	}
+	redacted.Payload = payload
+	return &redacted
+}
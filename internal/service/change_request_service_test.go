@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChangeRequests keeps change requests in memory with the status checks of the repository
+type fakeChangeRequests struct {
+	requests map[int64]*model.ChangeRequest
+	nextID   int64
+	// raced makes the next Transition lose against another replica
+	raced bool
+}
+
+func (f *fakeChangeRequests) Create(ctx context.Context, cr *model.ChangeRequest) error {
+	f.nextID++
+	cr.ID = f.nextID
+	stored := *cr
+	f.requests[cr.ID] = &stored
+	return nil
+}
+
+func (f *fakeChangeRequests) Get(ctx context.Context, id int64) (*model.ChangeRequest, error) {
+	cr, ok := f.requests[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *cr
+	return &copied, nil
+}
+
+func (f *fakeChangeRequests) List(ctx context.Context, endpoint, status string, limit, offset int) ([]*model.ChangeRequest, error) {
+	return nil, nil
+}
+
+func (f *fakeChangeRequests) Count(ctx context.Context, endpoint, status string) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeChangeRequests) Transition(ctx context.Context, id int64, from, to string, updates map[string]interface{}) (bool, error) {
+	cr := f.requests[id]
+	if f.raced {
+		f.raced = false
+		return false, nil
+	}
+	if cr == nil || cr.Status != from {
+		return false, nil
+	}
+	cr.Status = to
+	if reviewer, ok := updates["reviewed_by"].(string); ok {
+		cr.ReviewedBy = reviewer
+	}
+	if msg, ok := updates["error"].(string); ok {
+		cr.Error = msg
+	}
+	return true, nil
+}
+
+func (f *fakeChangeRequests) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+const (
+	aliceToken = "alice-token-0123456789"
+	bobToken   = "bob-token-0123456789"
+	ciToken    = "ci-token-0123456789"
+)
+
+var testApprovalConfig = config.ApprovalConfig{
+	Enabled:    true,
+	Approvers:  []config.ApprovalIdentity{{Name: "alice", Token: aliceToken}, {Name: "bob", Token: bobToken}},
+	Requesters: []config.ApprovalIdentity{{Name: "ci", Token: ciToken}},
+	TTL:        time.Hour,
+}
+
+// newTestChangeRequestService returns a service whose "test_op" executor records what it applied
+func newTestChangeRequestService(t *testing.T, execErr error) (*ChangeRequestService, *fakeChangeRequests, *[]*model.ChangeRequest) {
+	t.Helper()
+	svc, err := NewChangeRequestService(nil, nil, testApprovalConfig)
+	require.NoError(t, err)
+	store := &fakeChangeRequests{requests: map[int64]*model.ChangeRequest{}}
+	svc.repo = store
+
+	applied := []*model.ChangeRequest{}
+	svc.RegisterExecutor("test_op", func(ctx context.Context, cr *model.ChangeRequest) error {
+		applied = append(applied, cr)
+		return execErr
+	})
+	return svc, store, &applied
+}
+
+func submitTestChange(t *testing.T, svc *ChangeRequestService, token string) *model.ChangeRequest {
+	t.Helper()
+	cr, err := svc.Submit(context.Background(), "flux", "test_op", map[string]interface{}{"image": "repo/app:v2"}, token)
+	require.NoError(t, err)
+	return cr
+}
+
+func TestNewChangeRequestService_Config(t *testing.T) {
+	token := func(s string) string { return s + "-0123456789abcdef" }
+	tests := []struct {
+		name    string
+		cfg     config.ApprovalConfig
+		wantErr string
+	}{
+		{
+			name: "disabled without approvers",
+			cfg:  config.ApprovalConfig{},
+		},
+		{
+			name:    "enabled without approvers",
+			cfg:     config.ApprovalConfig{Enabled: true, Requesters: []config.ApprovalIdentity{{Name: "ci", Token: token("ci")}, {Name: "ops", Token: token("ops")}}},
+			wantErr: "approvers are required when approval is enabled",
+		},
+		{
+			name:    "a single identity",
+			cfg:     config.ApprovalConfig{Enabled: true, Approvers: []config.ApprovalIdentity{{Name: "alice", Token: token("alice")}}},
+			wantErr: "at least two identities are needed",
+		},
+		{
+			name:    "short token",
+			cfg:     config.ApprovalConfig{Enabled: true, Approvers: []config.ApprovalIdentity{{Name: "alice", Token: "short"}, {Name: "bob", Token: token("bob")}}},
+			wantErr: "token of alice must be at least 16 characters",
+		},
+		{
+			name:    "missing name",
+			cfg:     config.ApprovalConfig{Enabled: true, Approvers: []config.ApprovalIdentity{{Name: " ", Token: token("x")}, {Name: "bob", Token: token("bob")}}},
+			wantErr: "needs a name",
+		},
+		{
+			name:    "name listed twice",
+			cfg:     config.ApprovalConfig{Enabled: true, Approvers: []config.ApprovalIdentity{{Name: "alice", Token: token("a")}}, Requesters: []config.ApprovalIdentity{{Name: "alice", Token: token("b")}}},
+			wantErr: "alice is listed twice",
+		},
+		{
+			name:    "shared token",
+			cfg:     config.ApprovalConfig{Enabled: true, Approvers: []config.ApprovalIdentity{{Name: "alice", Token: token("a")}, {Name: "bob", Token: token("a")}}},
+			wantErr: "token of bob is shared with another identity",
+		},
+		{
+			name: "approvers and requesters",
+			cfg:  testApprovalConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := NewChangeRequestService(nil, nil, tt.cfg)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Nil(t, svc)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, svc)
+		})
+	}
+}
+
+func TestChangeRequest_SubmitIdentity(t *testing.T) {
+	svc, _, _ := newTestChangeRequestService(t, nil)
+
+	cr := submitTestChange(t, svc, ciToken)
+	assert.Equal(t, "ci", cr.RequestedBy)
+	assert.Equal(t, model.ChangeStatusPending, cr.Status)
+	assert.Equal(t, "repo/app:v2", cr.Payload["image"])
+
+	for _, token := range []string{"", "alice", "not-a-configured-token"} {
+		_, err := svc.Submit(context.Background(), "flux", "test_op", nil, token)
+		assert.ErrorIs(t, err, ErrApprovalUnauthorized, "token %q", token)
+	}
+	_, err := svc.Submit(context.Background(), "flux", "unknown_op", nil, ciToken)
+	assert.EqualError(t, err, "unsupported change operation: unknown_op")
+}
+
+func TestChangeRequest_ApprovalRules(t *testing.T) {
+	t.Run("requester cannot approve their own change", func(t *testing.T) {
+		svc, store, applied := newTestChangeRequestService(t, nil)
+		cr := submitTestChange(t, svc, aliceToken)
+
+		_, err := svc.Approve(context.Background(), cr.ID, aliceToken, "")
+		assert.ErrorIs(t, err, ErrApprovalForbidden)
+		assert.Contains(t, err.Error(), "cannot approve their own change")
+		assert.Equal(t, model.ChangeStatusPending, store.requests[cr.ID].Status)
+		assert.Empty(t, *applied)
+	})
+
+	t.Run("requesters cannot approve", func(t *testing.T) {
+		svc, store, applied := newTestChangeRequestService(t, nil)
+		cr := submitTestChange(t, svc, aliceToken)
+
+		_, err := svc.Approve(context.Background(), cr.ID, ciToken, "")
+		assert.ErrorIs(t, err, ErrApprovalForbidden)
+		assert.Contains(t, err.Error(), "ci is not an approver")
+		assert.Equal(t, model.ChangeStatusPending, store.requests[cr.ID].Status)
+		assert.Empty(t, *applied)
+	})
+
+	t.Run("unauthenticated reviews", func(t *testing.T) {
+		svc, _, _ := newTestChangeRequestService(t, nil)
+		cr := submitTestChange(t, svc, ciToken)
+
+		// A name is not a token
+		_, err := svc.Approve(context.Background(), cr.ID, "bob", "")
+		assert.ErrorIs(t, err, ErrApprovalUnauthorized)
+		_, err = svc.Reject(context.Background(), cr.ID, "", "")
+		assert.ErrorIs(t, err, ErrApprovalUnauthorized)
+	})
+
+	t.Run("unknown change request", func(t *testing.T) {
+		svc, _, _ := newTestChangeRequestService(t, nil)
+		_, err := svc.Approve(context.Background(), 42, bobToken, "")
+		assert.EqualError(t, err, "change request 42 not found")
+	})
+}
+
+func TestChangeRequest_Approve(t *testing.T) {
+	t.Run("approved change is applied", func(t *testing.T) {
+		svc, store, applied := newTestChangeRequestService(t, nil)
+		cr := submitTestChange(t, svc, ciToken)
+
+		approved, err := svc.Approve(context.Background(), cr.ID, bobToken, "LGTM")
+		require.NoError(t, err)
+		assert.Equal(t, model.ChangeStatusExecuted, approved.Status)
+		assert.Equal(t, "bob", approved.ReviewedBy)
+		assert.Equal(t, "LGTM", approved.ReviewComment)
+		assert.NotNil(t, approved.ExecutedAt)
+		assert.Equal(t, model.ChangeStatusExecuted, store.requests[cr.ID].Status)
+		require.Len(t, *applied, 1)
+		assert.Equal(t, "repo/app:v2", (*applied)[0].Payload["image"])
+		assert.Equal(t, "ci", (*applied)[0].RequestedBy)
+	})
+
+	t.Run("failed change is recorded", func(t *testing.T) {
+		svc, store, _ := newTestChangeRequestService(t, errors.New("deployment not found"))
+		cr := submitTestChange(t, svc, ciToken)
+
+		approved, err := svc.Approve(context.Background(), cr.ID, aliceToken, "")
+		require.NoError(t, err)
+		assert.Equal(t, model.ChangeStatusFailed, approved.Status)
+		assert.Equal(t, "deployment not found", approved.Error)
+		assert.Equal(t, model.ChangeStatusFailed, store.requests[cr.ID].Status)
+		assert.Equal(t, "deployment not found", store.requests[cr.ID].Error)
+	})
+
+	t.Run("second approval", func(t *testing.T) {
+		svc, _, applied := newTestChangeRequestService(t, nil)
+		cr := submitTestChange(t, svc, ciToken)
+
+		_, err := svc.Approve(context.Background(), cr.ID, bobToken, "")
+		require.NoError(t, err)
+		_, err = svc.Approve(context.Background(), cr.ID, aliceToken, "")
+		assert.ErrorIs(t, err, ErrChangeRequestClosed)
+		_, err = svc.Reject(context.Background(), cr.ID, aliceToken, "")
+		assert.ErrorIs(t, err, ErrChangeRequestClosed)
+		assert.Len(t, *applied, 1)
+	})
+
+	t.Run("approved concurrently by another replica", func(t *testing.T) {
+		svc, store, applied := newTestChangeRequestService(t, nil)
+		cr := submitTestChange(t, svc, ciToken)
+		store.raced = true
+
+		_, err := svc.Approve(context.Background(), cr.ID, bobToken, "")
+		assert.ErrorIs(t, err, ErrChangeRequestClosed)
+		assert.Contains(t, err.Error(), "reviewed concurrently")
+		assert.Empty(t, *applied)
+	})
+}
+
+func TestChangeRequest_Reject(t *testing.T) {
+	t.Run("approver rejects", func(t *testing.T) {
+		svc, _, applied := newTestChangeRequestService(t, nil)
+		cr := submitTestChange(t, svc, ciToken)
+
+		rejected, err := svc.Reject(context.Background(), cr.ID, bobToken, "wrong tag")
+		require.NoError(t, err)
+		assert.Equal(t, model.ChangeStatusRejected, rejected.Status)
+		assert.Equal(t, "bob", rejected.ReviewedBy)
+
+		_, err = svc.Reject(context.Background(), cr.ID, bobToken, "")
+		assert.ErrorIs(t, err, ErrChangeRequestClosed)
+		_, err = svc.Approve(context.Background(), cr.ID, aliceToken, "")
+		assert.ErrorIs(t, err, ErrChangeRequestClosed)
+		assert.Empty(t, *applied)
+	})
+
+	t.Run("requester withdraws", func(t *testing.T) {
+		svc, _, _ := newTestChangeRequestService(t, nil)
+		cr := submitTestChange(t, svc, ciToken)
+
+		rejected, err := svc.Reject(context.Background(), cr.ID, ciToken, "")
+		require.NoError(t, err)
+		assert.Equal(t, "ci", rejected.ReviewedBy)
+	})
+
+	t.Run("requesters cannot reject the changes of others", func(t *testing.T) {
+		cfg := testApprovalConfig
+		cfg.Requesters = append(cfg.Requesters, config.ApprovalIdentity{Name: "ops", Token: "ops-token-0123456789"})
+		svc, err := NewChangeRequestService(nil, nil, cfg)
+		require.NoError(t, err)
+		svc.repo = &fakeChangeRequests{requests: map[int64]*model.ChangeRequest{}}
+		svc.RegisterExecutor("test_op", func(ctx context.Context, cr *model.ChangeRequest) error { return nil })
+		cr := submitTestChange(t, svc, ciToken)
+
+		_, err = svc.Reject(context.Background(), cr.ID, "ops-token-0123456789", "")
+		assert.ErrorIs(t, err, ErrApprovalForbidden)
+	})
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"waverless/internal/model"
+	"waverless/pkg/circuit"
+	"waverless/pkg/config"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+)
+
+// ErrCircuitOpen is returned for submissions to an endpoint whose circuit breaker is open
+var ErrCircuitOpen = errors.New("endpoint circuit breaker is open")
+
+// CircuitBreakerService trips the circuit breaker of endpoints failing nearly every task and
+// probes them until a task succeeds. The breaker state lives on the endpoint row: TaskService
+// rejects submissions through Admit and the autoscaler holds scale-up while it is not closed.
+type CircuitBreakerService struct {
+	cfg          config.CircuitBreakerConfig
+	policy       circuit.Policy
+	endpointRepo *mysql.EndpointRepository
+	taskRepo     *mysql.TaskRepository
+}
+
+// NewCircuitBreakerService creates a new circuit breaker service
+func NewCircuitBreakerService(cfg config.CircuitBreakerConfig, endpointRepo *mysql.EndpointRepository, taskRepo *mysql.TaskRepository) *CircuitBreakerService {
+	return &CircuitBreakerService{
+		cfg:          cfg,
+		policy:       circuit.Policy{MinTasks: cfg.MinTasks, FailureRate: cfg.FailureRate},
+		endpointRepo: endpointRepo,
+		taskRepo:     taskRepo,
+	}
+}
+
+// Admit checks a submission against the circuit breaker of its endpoint. It returns
+// ErrCircuitOpen while the breaker is open; a half-open breaker admits taskID if no other
+// task is its probe yet.
+func (s *CircuitBreakerService) Admit(ctx context.Context, endpoint *mysql.Endpoint, taskID string) error {
+	switch endpoint.CircuitState {
+	case circuit.StateOpen:
+		return circuitOpenError(endpoint)
+	case circuit.StateHalfOpen:
+		ok, err := s.endpointRepo.ClaimCircuitProbe(ctx, endpoint.Endpoint, taskID)
+		if err != nil {
+			// Fail open: the breaker protects capacity, not correctness
+			logger.WarnCtx(ctx, "%v, endpoint: %s", err, endpoint.Endpoint)
+			return nil
+		}
+		if !ok {
+			return circuitOpenError(endpoint)
+		}
+		logger.InfoCtx(ctx, "task admitted as circuit breaker probe, task_id: %s, endpoint: %s", taskID, endpoint.Endpoint)
+	}
+	return nil
+}
+
+func circuitOpenError(endpoint *mysql.Endpoint) error {
+	return fmt.Errorf("%w: endpoint '%s' is failing nearly every task (%s), retry later", ErrCircuitOpen, endpoint.Endpoint, endpoint.CircuitReason)
+}
+
+// Run trips the breakers of endpoints failing nearly every task and moves tripped breakers on
+func (s *CircuitBreakerService) Run(ctx context.Context) error {
+	broken, err := s.endpointRepo.ListCircuitBroken(ctx)
+	if err != nil {
+		return err
+	}
+	isBroken := make(map[string]bool, len(broken))
+	for _, ep := range broken {
+		isBroken[ep.Endpoint] = true
+		if err := s.advance(ctx, ep); err != nil {
+			logger.WarnCtx(ctx, "failed to update circuit breaker, endpoint: %s, error: %v", ep.Endpoint, err)
+		}
+	}
+
+	windowStart := time.Now().Add(-s.cfg.Window)
+	counts, err := s.taskRepo.CountFinishedByEndpoint(ctx, windowStart)
+	if err != nil {
+		return err
+	}
+	for _, c := range counts {
+		if isBroken[c.Endpoint] {
+			continue
+		}
+		if trip, _ := s.policy.Trip(c.Failed, c.Completed+c.Failed); !trip {
+			continue
+		}
+		if err := s.trip(ctx, c.Endpoint, windowStart); err != nil {
+			logger.WarnCtx(ctx, "failed to trip circuit breaker, endpoint: %s, error: %v", c.Endpoint, err)
+		}
+	}
+	return nil
+}
+
+// trip opens the breaker of an endpoint whose tasks finished since windowStart mostly failed.
+// Failures from before the breaker last closed are not counted again.
+func (s *CircuitBreakerService) trip(ctx context.Context, endpoint string, windowStart time.Time) error {
+	ep, err := s.endpointRepo.Get(ctx, endpoint)
+	if err != nil || ep == nil {
+		return err
+	}
+	since := windowStart
+	if ep.CircuitSince != nil && ep.CircuitSince.After(windowStart) {
+		since = *ep.CircuitSince
+	}
+	counts, err := s.taskRepo.CountFinishedSince(ctx, endpoint, since)
+	if err != nil {
+		return err
+	}
+	trip, reason := s.policy.Trip(counts.Failed, counts.Completed+counts.Failed)
+	if !trip {
+		return nil
+	}
+	ok, err := s.endpointRepo.TripCircuit(ctx, endpoint, reason)
+	if ok {
+		logger.WarnCtx(ctx, "[AUDIT] endpoint circuit breaker tripped: endpoint=%s, reason=%s", endpoint, reason)
+	}
+	return err
+}
+
+// advance probes an open breaker once its delay passed and decides a half-open one from the
+// tasks started since it turned half-open
+func (s *CircuitBreakerService) advance(ctx context.Context, ep *mysql.Endpoint) error {
+	since := time.Now()
+	if ep.CircuitSince != nil {
+		since = *ep.CircuitSince
+	}
+
+	switch ep.CircuitState {
+	case circuit.StateOpen:
+		delay := circuit.ProbeDelay(ep.CircuitFailedProbes, s.cfg.ProbeAfter, s.cfg.MaxProbeAfter)
+		if time.Since(since) < delay {
+			return nil
+		}
+		ok, err := s.endpointRepo.HalfOpenCircuit(ctx, ep.Endpoint)
+		if ok {
+			logger.InfoCtx(ctx, "[AUDIT] endpoint circuit breaker half-open, admitting a probe: endpoint=%s", ep.Endpoint)
+		}
+		return err
+
+	case circuit.StateHalfOpen:
+		counts, err := s.taskRepo.CountFinishedStartedSince(ctx, ep.Endpoint, since)
+		if err != nil {
+			return err
+		}
+		switch circuit.Judge(counts.Completed, counts.Failed) {
+		case circuit.VerdictClose:
+			ok, err := s.endpointRepo.CloseCircuit(ctx, ep.Endpoint)
+			if ok {
+				logger.InfoCtx(ctx, "[AUDIT] endpoint circuit breaker closed, probe succeeded: endpoint=%s", ep.Endpoint)
+			}
+			return err
+		case circuit.VerdictReopen:
+			ok, err := s.endpointRepo.ReopenCircuit(ctx, ep.Endpoint)
+			if ok {
+				logger.WarnCtx(ctx, "[AUDIT] endpoint circuit breaker reopened, probe failed: endpoint=%s, next probe in %v", ep.Endpoint,
+					circuit.ProbeDelay(ep.CircuitFailedProbes+1, s.cfg.ProbeAfter, s.cfg.MaxProbeAfter))
+			}
+			return err
+		}
+		return s.releaseLostProbe(ctx, ep)
+	}
+	return nil
+}
+
+// releaseLostProbe lets a half-open breaker admit another probe when its probe task was never
+// queued (the submission failed after admission) or was cancelled
+func (s *CircuitBreakerService) releaseLostProbe(ctx context.Context, ep *mysql.Endpoint) error {
+	if ep.CircuitProbeTask == "" {
+		return nil
+	}
+	task, err := s.taskRepo.Get(ctx, ep.CircuitProbeTask)
+	if err != nil {
+		return err
+	}
+	if task != nil && task.Status != string(model.TaskStatusCancelled) {
+		return nil
+	}
+	return s.endpointRepo.ReleaseCircuitProbe(ctx, ep.Endpoint, ep.CircuitProbeTask)
+}
+
+// Close closes the circuit breaker of an endpoint by hand, e.g. after a fix was deployed
+func (s *CircuitBreakerService) Close(ctx context.Context, endpoint, closedBy string) error {
+	ep, err := s.endpointRepo.Get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if ep == nil {
+		return fmt.Errorf("endpoint %s not found", endpoint)
+	}
+	ok, err := s.endpointRepo.CloseCircuit(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if ok {
+		logger.InfoCtx(ctx, "[AUDIT] endpoint circuit breaker closed: endpoint=%s, closedBy=%q", endpoint, closedBy)
+	}
+	return nil
+}
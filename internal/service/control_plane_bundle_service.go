@@ -0,0 +1,262 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Control-plane bundle formats
+const (
+	BundleFormatJSON      = "json"      // ControlPlaneBundle as JSON
+	BundleFormatValues    = "values"    // Helm values.yaml
+	BundleFormatConfigMap = "configmap" // waverless-config ConfigMap manifest (k8s/waverless-configmap.yaml)
+)
+
+// ControlPlaneBundle is the effective configuration of a running control plane: the files of
+// its config directory as they would have to be written to redeploy it elsewhere.
+// Endpoint data is not part of it, that is what the DR export is for.
+type ControlPlaneBundle struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Namespace   string    `json:"namespace"`
+	// Files maps paths relative to the config directory (config.yaml, specs.yaml,
+	// templates/deployment.yaml) to their content
+	Files map[string]string `json:"files"`
+	// Redacted lists the dotted config.yaml paths whose credentials were replaced by
+	// REDACTED; they must be filled in before the bundle is deployed
+	Redacted []string `json:"redacted"`
+}
+
+// bundleSpec is a spec in the specs.yaml format
+type bundleSpec struct {
+	Name         string                 `yaml:"name"`
+	DisplayName  string                 `yaml:"displayName"`
+	Category     string                 `yaml:"category"`
+	ResourceType string                 `yaml:"resourceType,omitempty"`
+	Resources    bundleSpecResources    `yaml:"resources"`
+	Platforms    map[string]interface{} `yaml:"platforms,omitempty"`
+}
+
+// bundleSpecResources are spec resources in the specs.yaml format
+type bundleSpecResources struct {
+	CPU              string `yaml:"cpu,omitempty"`
+	Memory           string `yaml:"memory"`
+	GPU              string `yaml:"gpu,omitempty"`
+	GPUType          string `yaml:"gpuType,omitempty"`
+	EphemeralStorage string `yaml:"ephemeralStorage"`
+	ShmSize          string `yaml:"shmSize,omitempty"`
+}
+
+// ControlPlaneBundleService synthesizes the config bundle of this installation
+type ControlPlaneBundleService struct {
+	cfg         *config.Config
+	specService *SpecService // nil = specs.yaml is copied from the config directory
+}
+
+// NewControlPlaneBundleService creates a new control-plane bundle service
+func NewControlPlaneBundleService(cfg *config.Config, specService *SpecService) *ControlPlaneBundleService {
+	return &ControlPlaneBundleService{cfg: cfg, specService: specService}
+}
+
+// configDir returns the directory holding specs.yaml and templates
+func (s *ControlPlaneBundleService) configDir() string {
+	if s.cfg.K8s.ConfigDir != "" {
+		return s.cfg.K8s.ConfigDir
+	}
+	return "./config"
+}
+
+// Bundle builds the bundle. config.yaml is the configuration in effect (environment overrides
+// and defaults applied), specs.yaml holds the specs from the database, which take priority
+// over the file, and templates are copied as they are.
+func (s *ControlPlaneBundleService) Bundle(ctx context.Context) (*ControlPlaneBundle, error) {
+	configYAML, redacted, err := s.cfg.EffectiveYAML()
+	if err != nil {
+		return nil, err
+	}
+	bundle := &ControlPlaneBundle{
+		GeneratedAt: time.Now().UTC(),
+		Namespace:   s.cfg.K8s.Namespace,
+		Files:       map[string]string{"config.yaml": string(configYAML)},
+		Redacted:    redacted,
+	}
+	if bundle.Redacted == nil {
+		bundle.Redacted = []string{}
+	}
+
+	specsYAML, err := s.specsYAML(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if specsYAML != nil {
+		bundle.Files["specs.yaml"] = string(specsYAML)
+	}
+
+	templateDir := filepath.Join(s.configDir(), "templates")
+	entries, err := os.ReadDir(templateDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(templateDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", entry.Name(), err)
+		}
+		bundle.Files[path.Join("templates", entry.Name())] = string(data)
+	}
+
+	return bundle, nil
+}
+
+// specsYAML renders the database specs as specs.yaml, falling back to the file of the config
+// directory when the database has none. Returns nil if there are no specs at all.
+func (s *ControlPlaneBundleService) specsYAML(ctx context.Context) ([]byte, error) {
+	var specs []*interfaces.SpecInfo
+	if s.specService != nil {
+		var err error
+		if specs, err = s.specService.ListSpecs(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list specs: %w", err)
+		}
+	}
+
+	if len(specs) == 0 {
+		data, err := os.ReadFile(filepath.Join(s.configDir(), "specs.yaml"))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read specs.yaml: %w", err)
+		}
+		return data, nil
+	}
+
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	file := struct {
+		Specs []bundleSpec `yaml:"specs"`
+	}{Specs: make([]bundleSpec, 0, len(specs))}
+	for _, spec := range specs {
+		file.Specs = append(file.Specs, bundleSpec{
+			Name:         spec.Name,
+			DisplayName:  spec.DisplayName,
+			Category:     spec.Category,
+			ResourceType: spec.ResourceType,
+			Resources: bundleSpecResources{
+				CPU:              spec.Resources.CPU,
+				Memory:           spec.Resources.Memory,
+				GPU:              spec.Resources.GPU,
+				GPUType:          spec.Resources.GPUType,
+				EphemeralStorage: spec.Resources.EphemeralStorage,
+				ShmSize:          spec.Resources.ShmSize,
+			},
+			Platforms: spec.Platforms,
+		})
+	}
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal specs: %w", err)
+	}
+	return data, nil
+}
+
+// configMapKey returns the ConfigMap key of a bundle file; keys cannot contain "/", so
+// templates are keyed by file name and mounted under templates/ by the Deployment
+func configMapKey(file string) string {
+	return path.Base(file)
+}
+
+// sortedFiles returns the bundle file paths in a stable order
+func (b *ControlPlaneBundle) sortedFiles() []string {
+	files := make([]string, 0, len(b.Files))
+	for file := range b.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// blockNode returns a literal block scalar, so files stay readable inside the manifest
+func blockNode(content string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Style: yaml.LiteralStyle, Value: content}
+}
+
+func mappingNode(pairs ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Content: pairs}
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+// marshalManifest encodes a node with the two-space indentation of the k8s manifests
+func marshalManifest(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// bundleHeader lists the credentials to fill in before deploying
+func (b *ControlPlaneBundle) bundleHeader() string {
+	header := fmt.Sprintf("Generated by waverless at %s.", b.GeneratedAt.Format(time.RFC3339))
+	if len(b.Redacted) > 0 {
+		header += "\nReplace REDACTED in config.yaml before deploying:"
+		for _, p := range b.Redacted {
+			header += "\n  " + p
+		}
+	}
+	return header
+}
+
+// Values renders the bundle as Helm values: `config` holds the files keyed like the
+// waverless-config ConfigMap, `namespace` the namespace the control plane runs in
+func (b *ControlPlaneBundle) Values() ([]byte, error) {
+	files := mappingNode()
+	for _, file := range b.sortedFiles() {
+		files.Content = append(files.Content, scalarNode(configMapKey(file)), blockNode(b.Files[file]))
+	}
+	values := mappingNode(
+		scalarNode("namespace"), scalarNode(b.Namespace),
+		scalarNode("config"), files,
+	)
+	values.HeadComment = b.bundleHeader()
+	return marshalManifest(values)
+}
+
+// ConfigMap renders the bundle as the waverless-config ConfigMap manifest
+func (b *ControlPlaneBundle) ConfigMap() ([]byte, error) {
+	data := mappingNode()
+	for _, file := range b.sortedFiles() {
+		data.Content = append(data.Content, scalarNode(configMapKey(file)), blockNode(b.Files[file]))
+	}
+	metadata := mappingNode(scalarNode("name"), scalarNode("waverless-config"))
+	if b.Namespace != "" {
+		metadata.Content = append(metadata.Content, scalarNode("namespace"), scalarNode(b.Namespace))
+	}
+	manifest := mappingNode(
+		scalarNode("apiVersion"), scalarNode("v1"),
+		scalarNode("kind"), scalarNode("ConfigMap"),
+		scalarNode("metadata"), metadata,
+		scalarNode("data"), data,
+	)
+	manifest.HeadComment = b.bundleHeader()
+	return marshalManifest(manifest)
+}
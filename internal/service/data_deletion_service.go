@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"waverless/pkg/constants"
+	"waverless/pkg/export"
+	"waverless/pkg/logger"
+	"waverless/pkg/sampling"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// dataDeletionBatchSize is the number of tasks erased per transaction
+const dataDeletionBatchSize = 500
+
+// DataDeletionRequest erases the task data of a data subject
+type DataDeletionRequest struct {
+	Subject string `json:"subject" binding:"required"` // Subject key the tasks were submitted with
+	Mode    string `json:"mode,omitempty"`             // purge (default) or anonymize
+}
+
+// DataDeletionReport is the outcome of a data deletion request
+type DataDeletionReport struct {
+	TasksMatched           int      `json:"tasksMatched"`
+	TasksPurged            int64    `json:"tasksPurged"`
+	TasksAnonymized        int64    `json:"tasksAnonymized"`
+	ActiveTasksSkipped     []string `json:"activeTasksSkipped,omitempty"` // Pending/in progress; run the request again once they finish
+	SampleObjectsScanned   int      `json:"sampleObjectsScanned"`
+	SampleObjectsRewritten int      `json:"sampleObjectsRewritten"`
+	SampleObjectsDeleted   int      `json:"sampleObjectsDeleted"` // Objects left without samples
+	SamplesErased          int      `json:"samplesErased"`
+	Errors                 []string `json:"errors,omitempty"`
+}
+
+// DataDeletionService answers data deletion requests: it purges or anonymizes the tasks
+// submitted with a subject key, including their events and sampled copies, and keeps a
+// report of every request. Only the SHA-256 of subject keys is ever stored.
+type DataDeletionService struct {
+	repo     *mysql.DataDeletionRepository
+	taskRepo *mysql.TaskRepository
+	samples  export.ObjectRewriter // nil = no rewritable sampling store
+}
+
+// NewDataDeletionService creates a new data deletion service
+func NewDataDeletionService(repo *mysql.DataDeletionRepository, taskRepo *mysql.TaskRepository) *DataDeletionService {
+	return &DataDeletionService{repo: repo, taskRepo: taskRepo}
+}
+
+// SetSampleStore sets the sampling store whose objects are rewritten (for dependency injection)
+func (s *DataDeletionService) SetSampleStore(store export.ObjectRewriter) {
+	s.samples = store
+}
+
+// SubjectHash returns the stored form of a subject key, "" for no subject
+func SubjectHash(subject string) string {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(subject))
+	return hex.EncodeToString(sum[:])
+}
+
+// Delete erases the data of a subject and stores the report. Active tasks are skipped and
+// listed in the report (status partial); running the request again later erases them.
+func (s *DataDeletionService) Delete(ctx context.Context, req *DataDeletionRequest, requestedBy string) (*model.DataDeletionRequest, error) {
+	hash := SubjectHash(req.Subject)
+	if hash == "" {
+		return nil, fmt.Errorf("invalid request: subject is required")
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = model.DataDeletionModePurge
+	}
+	if mode != model.DataDeletionModePurge && mode != model.DataDeletionModeAnonymize {
+		return nil, fmt.Errorf("invalid request: mode must be %s or %s", model.DataDeletionModePurge, model.DataDeletionModeAnonymize)
+	}
+
+	record := &model.DataDeletionRequest{
+		SubjectHash: hash,
+		Mode:        mode,
+		Status:      model.DataDeletionStatusPartial,
+		RequestedBy: requestedBy,
+	}
+	if err := s.repo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	report := &DataDeletionReport{}
+	taskErr := s.eraseTasks(ctx, hash, mode, report)
+	if taskErr != nil {
+		report.Errors = append(report.Errors, taskErr.Error())
+	}
+	s.eraseSamples(ctx, hash, mode == model.DataDeletionModeAnonymize, report)
+
+	switch {
+	case taskErr != nil:
+		record.Status = model.DataDeletionStatusFailed
+	case len(report.ActiveTasksSkipped) > 0 || len(report.Errors) > 0:
+		record.Status = model.DataDeletionStatusPartial
+	default:
+		record.Status = model.DataDeletionStatusCompleted
+	}
+	now := time.Now()
+	record.CompletedAt = &now
+	reportMap, err := toReportMap(report)
+	if err != nil {
+		return nil, err
+	}
+	record.Report = reportMap
+	if err := s.repo.Update(ctx, record); err != nil {
+		return nil, err
+	}
+
+	logger.InfoCtx(ctx, "[AUDIT] data deletion: id=%d, subject=%s, mode=%s, status=%s, requestedBy=%q, tasks=%d, purged=%d, anonymized=%d, skipped=%d, samples=%d",
+		record.ID, hash, mode, record.Status, requestedBy, report.TasksMatched, report.TasksPurged, report.TasksAnonymized,
+		len(report.ActiveTasksSkipped), report.SamplesErased)
+	return record, nil
+}
+
+// Get returns a data deletion request with its report
+func (s *DataDeletionService) Get(ctx context.Context, id int64) (*model.DataDeletionRequest, error) {
+	req, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, fmt.Errorf("data deletion request %d not found", id)
+	}
+	return req, nil
+}
+
+// List returns data deletion requests, newest first
+func (s *DataDeletionService) List(ctx context.Context, limit int) ([]*model.DataDeletionRequest, error) {
+	return s.repo.List(ctx, "", limit)
+}
+
+// IndexSamples records which uploaded sample object holds samples of data subjects
+// (sampling.UploadHook)
+func (s *DataDeletionService) IndexSamples(ctx context.Context, key string, samples []*sampling.Sample) {
+	var entries []*model.SampleSubject
+	for _, sample := range samples {
+		if sample.Subject == "" {
+			continue
+		}
+		entries = append(entries, &model.SampleSubject{
+			SubjectHash: sample.Subject,
+			TaskID:      sample.TaskID,
+			Endpoint:    sample.Endpoint,
+			ObjectKey:   key,
+		})
+	}
+	if err := s.repo.IndexSamples(ctx, entries); err != nil {
+		logger.ErrorCtx(ctx, "failed to index %d subject samples of %s: %v", len(entries), key, err)
+	}
+}
+
+// eraseTasks purges or anonymizes the finished tasks of a subject in batches
+func (s *DataDeletionService) eraseTasks(ctx context.Context, hash, mode string, report *DataDeletionReport) error {
+	var afterID int64
+	for {
+		tasks, err := s.taskRepo.ListBySubject(ctx, hash, afterID, dataDeletionBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+		afterID = tasks[len(tasks)-1].ID
+		report.TasksMatched += len(tasks)
+
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			if task.Status == constants.TaskStatusPending.String() || task.Status == constants.TaskStatusInProgress.String() {
+				report.ActiveTasksSkipped = append(report.ActiveTasksSkipped, task.TaskID)
+				continue
+			}
+			ids = append(ids, task.TaskID)
+		}
+
+		if mode == model.DataDeletionModeAnonymize {
+			n, err := s.taskRepo.AnonymizeTasks(ctx, ids)
+			if err != nil {
+				return err
+			}
+			report.TasksAnonymized += n
+		} else {
+			n, err := s.taskRepo.PurgeTasks(ctx, ids)
+			if err != nil {
+				return err
+			}
+			report.TasksPurged += n
+		}
+	}
+}
+
+// eraseSamples rewrites every indexed sample object holding samples of a subject
+func (s *DataDeletionService) eraseSamples(ctx context.Context, hash string, anonymize bool, report *DataDeletionReport) {
+	keys, err := s.repo.ListSampleObjects(ctx, hash)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return
+	}
+	if len(keys) == 0 {
+		return
+	}
+	if s.samples == nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("%d sample objects not erased: sampling store is not configured or does not support rewriting", len(keys)))
+		return
+	}
+
+	for _, key := range keys {
+		report.SampleObjectsScanned++
+		if err := s.eraseSampleObject(ctx, key, hash, anonymize, report); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("sample object %s: %v", key, err))
+			continue
+		}
+		if err := s.repo.DeleteSampleIndex(ctx, hash, key); err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	}
+}
+
+func (s *DataDeletionService) eraseSampleObject(ctx context.Context, key, hash string, anonymize bool, report *DataDeletionReport) error {
+	data, err := s.samples.GetObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	out, erased, remaining, err := sampling.EraseSubject(data, hash, anonymize)
+	if err != nil {
+		return err
+	}
+	report.SamplesErased += erased
+	switch {
+	case erased == 0:
+		return nil
+	case remaining == 0:
+		if err := s.samples.DeleteObject(ctx, key); err != nil {
+			return err
+		}
+		report.SampleObjectsDeleted++
+	default:
+		if _, err := s.samples.PutObject(ctx, key, out, "application/x-ndjson"); err != nil {
+			return err
+		}
+		report.SampleObjectsRewritten++
+	}
+	return nil
+}
+
+func toReportMap(report *DataDeletionReport) (model.JSONMap, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	var m model.JSONMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
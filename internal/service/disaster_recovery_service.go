@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/backup"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// RegistryCredentialStore reads and restores endpoint image pull secrets (implemented by the K8s provider)
+type RegistryCredentialStore interface {
+	ListRegistryCredentials(ctx context.Context) ([]backup.RegistryCredential, error)
+	RestoreRegistryCredential(ctx context.Context, cred backup.RegistryCredential) error
+}
+
+// DisasterRecoveryService exports the control-plane state to a versioned archive and
+// imports it into another installation. Import only writes metadata (and registry
+// secrets); endpoints are redeployed by the regular deployment flow afterwards.
+type DisasterRecoveryService struct {
+	repo        *mysql.Repository
+	credentials RegistryCredentialStore // nil = credentials are not exported or restored
+}
+
+// NewDisasterRecoveryService creates a new disaster recovery service
+func NewDisasterRecoveryService(repo *mysql.Repository, credentials RegistryCredentialStore) *DisasterRecoveryService {
+	return &DisasterRecoveryService{repo: repo, credentials: credentials}
+}
+
+// Export builds an archive of the current state. Registry credentials are included
+// (sealed with passphrase) only when a passphrase is given.
+func (s *DisasterRecoveryService) Export(ctx context.Context, passphrase string) (*backup.Archive, error) {
+	endpoints, err := s.repo.Endpoint.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	autoscalerConfigs, err := s.repo.AutoscalerConfig.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := s.repo.Spec.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mirrors, err := s.repo.RegistryMirror.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &backup.Archive{
+		Version:           backup.ArchiveVersion,
+		ExportedAt:        time.Now().UTC(),
+		Endpoints:         endpoints,
+		AutoscalerConfigs: autoscalerConfigs,
+		Specs:             specs,
+		RegistryMirrors:   mirrors,
+	}
+
+	if passphrase != "" {
+		if s.credentials == nil {
+			return nil, fmt.Errorf("registry credentials are only available with the K8s provider")
+		}
+		creds, err := s.credentials.ListRegistryCredentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		archive.Credentials, err = backup.SealCredentials(creds, passphrase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return archive, nil
+}
+
+// Import applies an archive. Items are upserted by name and never deleted; in dry-run
+// mode the returned plan lists what would change without writing anything.
+func (s *DisasterRecoveryService) Import(ctx context.Context, archive *backup.Archive, passphrase string, dryRun bool) (*backup.Plan, error) {
+	if err := archive.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Open credentials up front so a wrong passphrase fails before anything is written
+	var creds []backup.RegistryCredential
+	if archive.Credentials != nil {
+		if s.credentials == nil {
+			return nil, fmt.Errorf("archive contains registry credentials but the K8s provider is not enabled")
+		}
+		var err error
+		creds, err = backup.OpenCredentials(archive.Credentials, passphrase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan := &backup.Plan{DryRun: dryRun}
+	// Order matters: endpoints reference specs, autoscaler configs reference endpoints
+	steps := []func(context.Context, *backup.Archive, *backup.Plan) error{
+		s.importSpecs,
+		s.importRegistryMirrors,
+		s.importEndpoints,
+		s.importAutoscalerConfigs,
+	}
+	for _, step := range steps {
+		if err := step(ctx, archive, plan); err != nil {
+			return plan, err
+		}
+	}
+	if err := s.importCredentials(ctx, creds, plan); err != nil {
+		return plan, err
+	}
+
+	if !dryRun {
+		logger.InfoCtx(ctx, "Imported control-plane archive (exported at %s): created=%d, updated=%d, unchanged=%d",
+			archive.ExportedAt.Format(time.RFC3339), plan.Created, plan.Updated, plan.Unchanged)
+	}
+	return plan, nil
+}
+
+func (s *DisasterRecoveryService) importSpecs(ctx context.Context, archive *backup.Archive, plan *backup.Plan) error {
+	for _, spec := range archive.Specs {
+		existing, err := s.repo.Spec.Get(ctx, spec.Name)
+		if err != nil {
+			return err
+		}
+		change, err := backup.Compare(backup.KindSpec, spec.Name, existing != nil, existing, spec)
+		if err != nil {
+			return err
+		}
+		plan.Add(change)
+		if plan.DryRun || change.Action == backup.ActionUnchanged {
+			continue
+		}
+
+		desired := *spec
+		if existing != nil {
+			desired.ID, desired.CreatedAt = existing.ID, existing.CreatedAt
+			err = s.repo.Spec.Update(ctx, &desired)
+		} else {
+			desired.ID = 0
+			err = s.repo.Spec.Create(ctx, &desired)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import spec %s: %w", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *DisasterRecoveryService) importRegistryMirrors(ctx context.Context, archive *backup.Archive, plan *backup.Plan) error {
+	for _, mirror := range archive.RegistryMirrors {
+		existing, err := s.repo.RegistryMirror.Get(ctx, mirror.Upstream)
+		if err != nil {
+			return err
+		}
+		change, err := backup.Compare(backup.KindRegistryMirror, mirror.Upstream, existing != nil, existing, mirror)
+		if err != nil {
+			return err
+		}
+		plan.Add(change)
+		if plan.DryRun || change.Action == backup.ActionUnchanged {
+			continue
+		}
+
+		desired := &model.RegistryMirror{
+			Upstream:    mirror.Upstream,
+			Mirror:      mirror.Mirror,
+			Enabled:     mirror.Enabled,
+			Description: mirror.Description,
+		}
+		if err := s.repo.RegistryMirror.Upsert(ctx, desired); err != nil {
+			return fmt.Errorf("failed to import registry mirror %s: %w", mirror.Upstream, err)
+		}
+	}
+	return nil
+}
+
+func (s *DisasterRecoveryService) importEndpoints(ctx context.Context, archive *backup.Archive, plan *backup.Plan) error {
+	for _, endpoint := range archive.Endpoints {
+		existing, err := s.repo.Endpoint.Get(ctx, endpoint.Endpoint)
+		if err != nil {
+			return err
+		}
+		change, err := backup.Compare(backup.KindEndpoint, endpoint.Endpoint, existing != nil, existing, endpoint)
+		if err != nil {
+			return err
+		}
+		plan.Add(change)
+		if plan.DryRun || change.Action == backup.ActionUnchanged {
+			continue
+		}
+
+		desired := *endpoint
+		if existing != nil {
+			// Keep state observed by this installation
+			desired.ID, desired.CreatedAt = existing.ID, existing.CreatedAt
+			desired.RuntimeState = existing.RuntimeState
+			desired.HealthStatus, desired.HealthReason, desired.HealthMessage = existing.HealthStatus, existing.HealthReason, existing.HealthMessage
+			desired.LastHealthCheckAt = existing.LastHealthCheckAt
+			desired.ImageLastChecked, desired.LatestImage = existing.ImageLastChecked, existing.LatestImage
+			err = s.repo.Endpoint.Update(ctx, &desired)
+		} else {
+			desired.ID = 0
+			desired.RuntimeState = nil
+			desired.HealthStatus, desired.HealthReason, desired.HealthMessage = string(model.HealthStatusHealthy), "", nil
+			desired.LastHealthCheckAt, desired.ImageLastChecked, desired.LatestImage = nil, nil, ""
+			err = s.repo.Endpoint.Create(ctx, &desired)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import endpoint %s: %w", endpoint.Endpoint, err)
+		}
+	}
+	return nil
+}
+
+func (s *DisasterRecoveryService) importAutoscalerConfigs(ctx context.Context, archive *backup.Archive, plan *backup.Plan) error {
+	for _, cfg := range archive.AutoscalerConfigs {
+		existing, err := s.repo.AutoscalerConfig.Get(ctx, cfg.Endpoint)
+		if err != nil {
+			return err
+		}
+		change, err := backup.Compare(backup.KindAutoscalerConfig, cfg.Endpoint, existing != nil, existing, cfg)
+		if err != nil {
+			return err
+		}
+		plan.Add(change)
+		if plan.DryRun || change.Action == backup.ActionUnchanged {
+			continue
+		}
+
+		desired := *cfg
+		desired.ID = 0
+		if existing != nil {
+			desired.LastTaskTime, desired.LastScaleTime, desired.FirstPendingTime = existing.LastTaskTime, existing.LastScaleTime, existing.FirstPendingTime
+		} else {
+			desired.LastTaskTime, desired.LastScaleTime, desired.FirstPendingTime = nil, nil, nil
+		}
+		if err := s.repo.AutoscalerConfig.CreateOrUpdate(ctx, &desired); err != nil {
+			return fmt.Errorf("failed to import autoscaler config %s: %w", cfg.Endpoint, err)
+		}
+	}
+	return nil
+}
+
+func (s *DisasterRecoveryService) importCredentials(ctx context.Context, creds []backup.RegistryCredential, plan *backup.Plan) error {
+	if len(creds) == 0 {
+		return nil
+	}
+	current, err := s.credentials.ListRegistryCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string][]byte, len(current))
+	for _, c := range current {
+		existing[c.Endpoint] = c.DockerConfigJSON
+	}
+
+	for _, cred := range creds {
+		// Secret contents are never echoed back, only whether they differ
+		change := backup.Change{Kind: backup.KindCredential, Name: cred.Endpoint, Action: backup.ActionCreate}
+		if data, ok := existing[cred.Endpoint]; ok {
+			change.Action = backup.ActionUnchanged
+			if !bytes.Equal(data, cred.DockerConfigJSON) {
+				change.Action = backup.ActionUpdate
+			}
+		}
+		plan.Add(change)
+		if plan.DryRun || change.Action == backup.ActionUnchanged {
+			continue
+		}
+		if err := s.credentials.RestoreRegistryCredential(ctx, cred); err != nil {
+			return fmt.Errorf("failed to restore registry credential for %s: %w", cred.Endpoint, err)
+		}
+	}
+	return nil
+}
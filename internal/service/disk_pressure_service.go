@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/config"
+	"waverless/pkg/deploy/k8s"
+	"waverless/pkg/failure"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/notification"
+)
+
+const (
+	defaultDiskPressureBumpFactor = 1.5
+	defaultMaxEphemeralStorage    = "200Gi"
+	defaultDiskPressureCooldown   = 30 * time.Minute
+)
+
+// DiskPressureService reacts to worker pods evicted for disk usage. Every endpoint gets at most
+// one alert (endpoint.disk_pressure event) per cooldown; when auto bump is enabled and the pod
+// exceeded its own ephemeral-storage limit, the limit of the endpoint is raised as well.
+// Node disk exhaustion is only alerted, a larger limit would not help there.
+type DiskPressureService struct {
+	endpointService    *endpointsvc.Service
+	deployProvider     interfaces.DeploymentProvider
+	integrationService *IntegrationService // nil = no alerts
+	config             config.DiskPressureConfig
+
+	mu          sync.Mutex
+	lastHandled map[string]time.Time // endpoint -> last alert or bump
+}
+
+// NewDiskPressureService creates a new disk pressure service
+func NewDiskPressureService(endpointService *endpointsvc.Service, deployProvider interfaces.DeploymentProvider, integrationService *IntegrationService, cfg config.DiskPressureConfig) *DiskPressureService {
+	if cfg.BumpFactor <= 1 {
+		cfg.BumpFactor = defaultDiskPressureBumpFactor
+	}
+	if cfg.MaxEphemeralStorage == "" {
+		cfg.MaxEphemeralStorage = defaultMaxEphemeralStorage
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultDiskPressureCooldown
+	}
+	return &DiskPressureService{
+		endpointService:    endpointService,
+		deployProvider:     deployProvider,
+		integrationService: integrationService,
+		config:             cfg,
+		lastHandled:        make(map[string]time.Time),
+	}
+}
+
+// HandleFailure alerts on (and optionally bumps the limit for) a disk pressure failure of a
+// worker pod. Other failures are ignored.
+func (s *DiskPressureService) HandleFailure(ctx context.Context, endpoint, podName string, info *interfaces.WorkerFailureInfo) {
+	if s == nil || info == nil || info.Cause != failure.ReasonDiskPressure {
+		return
+	}
+	if !s.acquire(endpoint) {
+		return
+	}
+
+	event := &notification.DiskPressureNotification{
+		Endpoint:   endpoint,
+		Pod:        podName,
+		Scope:      info.Details["scope"],
+		Message:    info.Message,
+		OccurredAt: info.OccurredAt,
+	}
+	if s.deployProvider != nil {
+		if app, err := s.deployProvider.GetApp(ctx, endpoint); err != nil {
+			logger.WarnCtx(ctx, "disk pressure: failed to get app %s: %v", endpoint, err)
+		} else if app != nil {
+			event.EphemeralStorage = app.EphemeralStorage
+		}
+	}
+
+	if s.config.AutoBump && event.Scope == failure.DiskScopeLimit && event.EphemeralStorage != "" {
+		event.BumpedTo = s.bump(ctx, endpoint, event.EphemeralStorage)
+	}
+
+	logger.WarnCtx(ctx, "disk pressure on endpoint %s, pod: %s, scope: %s, ephemeral storage: %s, bumped to: %s",
+		endpoint, podName, event.Scope, event.EphemeralStorage, event.BumpedTo)
+	s.integrationService.Publish(ctx, &notification.Event{
+		Type:      notification.EventDiskPressure,
+		Endpoint:  endpoint,
+		CreatedAt: event.OccurredAt,
+		Data:      event,
+	})
+}
+
+// bump raises the ephemeral-storage limit of an endpoint and returns the new limit, empty when
+// it was not raised
+func (s *DiskPressureService) bump(ctx context.Context, endpoint, current string) string {
+	bumped, ok, err := k8s.BumpEphemeralStorage(current, s.config.BumpFactor, s.config.MaxEphemeralStorage)
+	if err != nil {
+		logger.WarnCtx(ctx, "disk pressure: cannot bump ephemeral storage %s of endpoint %s: %v", current, endpoint, err)
+		return ""
+	}
+	if !ok {
+		logger.WarnCtx(ctx, "disk pressure: ephemeral storage of endpoint %s already at the maximum %s", endpoint, s.config.MaxEphemeralStorage)
+		return ""
+	}
+
+	if _, err := s.endpointService.UpdateDeployment(ctx, &interfaces.UpdateDeploymentRequest{
+		Endpoint:         endpoint,
+		EphemeralStorage: &bumped,
+	}); err != nil {
+		logger.ErrorCtx(ctx, "disk pressure: failed to bump ephemeral storage of endpoint %s to %s: %v", endpoint, bumped, err)
+		return ""
+	}
+	logger.InfoCtx(ctx, "disk pressure: raised ephemeral storage of endpoint %s from %s to %s", endpoint, current, bumped)
+	return bumped
+}
+
+// acquire reports whether the endpoint is out of its cooldown and starts a new one
+func (s *DiskPressureService) acquire(endpoint string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := s.lastHandled[endpoint]; ok && now.Sub(last) < s.config.Cooldown {
+		return false
+	}
+	s.lastHandled[endpoint] = now
+	return true
+}
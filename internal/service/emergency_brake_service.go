@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/logger"
+	"waverless/pkg/maintenance"
+)
+
+// DrainedEndpoint is an endpoint scaled down to its minimum when the brake was engaged
+type DrainedEndpoint struct {
+	Endpoint string `json:"endpoint"`
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	Error    string `json:"error,omitempty"`
+}
+
+// EmergencyBrakeResult is the brake state after engaging it, with the endpoints drained
+type EmergencyBrakeResult struct {
+	State   maintenance.BrakeState `json:"state"`
+	Drained []DrainedEndpoint      `json:"drained,omitempty"`
+}
+
+// EmergencyBrakeService engages and releases the cluster-wide emergency brake. The brake
+// itself is enforced by the autoscaler and the endpoint service, which check it before acting.
+type EmergencyBrakeService struct {
+	brake           *maintenance.Brake
+	endpointService *endpointsvc.Service
+}
+
+// NewEmergencyBrakeService creates a new emergency brake service
+func NewEmergencyBrakeService(brake *maintenance.Brake, endpointService *endpointsvc.Service) *EmergencyBrakeService {
+	return &EmergencyBrakeService{brake: brake, endpointService: endpointService}
+}
+
+// Current returns the brake state
+func (s *EmergencyBrakeService) Current() maintenance.BrakeState {
+	return s.brake.Current()
+}
+
+// Engage freezes autoscaling and rollouts on every replica. With drainToMin, endpoints
+// running more than their minReplicas are scaled down to it; failures are reported per
+// endpoint and do not undo the brake.
+func (s *EmergencyBrakeService) Engage(ctx context.Context, reason, engagedBy string, drainToMin bool) (*EmergencyBrakeResult, error) {
+	state, err := s.brake.Engage(ctx, reason, engagedBy, drainToMin)
+	if err != nil {
+		return nil, err
+	}
+	logger.WarnCtx(ctx, "[AUDIT] Emergency brake engaged by %q (drainToMin=%v): %s", engagedBy, drainToMin, reason)
+
+	result := &EmergencyBrakeResult{State: state}
+	if drainToMin {
+		result.Drained = s.drainToMin(ctx)
+	}
+	return result, nil
+}
+
+// Release lifts the brake on every replica
+func (s *EmergencyBrakeService) Release(ctx context.Context, releasedBy string) (maintenance.BrakeState, error) {
+	state, err := s.brake.Release(ctx, releasedBy)
+	if err != nil {
+		return state, err
+	}
+	logger.InfoCtx(ctx, "[AUDIT] Emergency brake released by %q, engaged by %q since %v: %s", releasedBy, state.EngagedBy, state.Since, state.Reason)
+	return state, nil
+}
+
+// drainToMin scales every endpoint above its minReplicas down to it
+func (s *EmergencyBrakeService) drainToMin(ctx context.Context) []DrainedEndpoint {
+	if s.endpointService == nil {
+		return nil
+	}
+	endpoints, err := s.endpointService.ListEndpoints(ctx)
+	if err != nil {
+		logger.ErrorCtx(ctx, "Emergency brake drain: failed to list endpoints: %v", err)
+		return []DrainedEndpoint{{Error: err.Error()}}
+	}
+
+	var drained []DrainedEndpoint
+	for _, meta := range endpoints {
+		// Drain from the desired count; listed replicas may be the live count
+		ep, err := s.endpointService.GetEndpointOnly(ctx, meta.Name)
+		if err != nil || ep == nil || ep.Replicas <= meta.MinReplicas {
+			continue
+		}
+		result := DrainedEndpoint{Endpoint: meta.Name, From: ep.Replicas, To: meta.MinReplicas}
+		if err := s.endpointService.ScaleDown(ctx, meta.Name, ep.Replicas-meta.MinReplicas); err != nil {
+			result.Error = err.Error()
+			logger.WarnCtx(ctx, "Emergency brake drain: failed to scale down endpoint %s: %v", meta.Name, err)
+		} else {
+			logger.InfoCtx(ctx, "[AUDIT] Emergency brake drained endpoint %s from %d to %d replicas", meta.Name, result.From, result.To)
+		}
+		drained = append(drained, result)
+	}
+	return drained
+}
@@ -0,0 +1,118 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"waverless/pkg/hooks"
+	"waverless/pkg/interfaces"
+)
+
+// ErrBlueGreenUnsupported is returned when the endpoint's provider cannot run a candidate deployment
+var ErrBlueGreenUnsupported = errors.New("deployment provider does not support blue/green updates")
+
+// candidateDeployer returns the provider of an endpoint as a CandidateDeployer
+func (s *Service) candidateDeployer(ctx context.Context, name string) (interfaces.CandidateDeployer, error) {
+	if s.deployment == nil || s.deployment.provider == nil {
+		return nil, fmt.Errorf("deployment provider not configured")
+	}
+	deployer, ok := interfaces.ResolveProvider(ctx, s.deployment.provider, name, "").(interfaces.CandidateDeployer)
+	if !ok {
+		return nil, ErrBlueGreenUnsupported
+	}
+	return deployer, nil
+}
+
+// SupportsBlueGreen reports whether the provider of an endpoint can run blue/green updates.
+func (s *Service) SupportsBlueGreen(ctx context.Context, name string) bool {
+	_, err := s.candidateDeployer(ctx, name)
+	return err == nil
+}
+
+// DeployCandidate starts the green side of a blue/green update: the live deployment with req
+// applied, next to it. It runs the pre-deploy hooks and returns req with its image resolved,
+// to be passed to PromoteCandidate.
+func (s *Service) DeployCandidate(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.UpdateDeploymentRequest, error) {
+	if req == nil {
+		return nil, fmt.Errorf("update request is nil")
+	}
+	deployer, err := s.candidateDeployer(ctx, req.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRollout(); err != nil {
+		return nil, err
+	}
+	if err := s.runHooks(ctx, req.Endpoint, hooks.PhasePreDeploy, hooks.OperationUpdate); err != nil {
+		return nil, err
+	}
+
+	resolved, err := s.deployment.resolveUpdateImage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := deployer.DeployCandidate(ctx, resolved); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// CandidateStatus returns the status of an endpoint's candidate deployment.
+func (s *Service) CandidateStatus(ctx context.Context, name string) (*interfaces.AppStatus, error) {
+	deployer, err := s.candidateDeployer(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return deployer.GetCandidateStatus(ctx, name)
+}
+
+// CandidateWorkers returns the worker IDs of an endpoint's candidate deployment.
+func (s *Service) CandidateWorkers(ctx context.Context, name string) ([]string, error) {
+	deployer, err := s.candidateDeployer(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return deployer.ListCandidateWorkers(ctx, name)
+}
+
+// DeleteCandidate deletes an endpoint's candidate deployment, if any.
+func (s *Service) DeleteCandidate(ctx context.Context, name string) error {
+	deployer, err := s.candidateDeployer(ctx, name)
+	if err != nil {
+		return err
+	}
+	return deployer.DeleteCandidate(ctx, name)
+}
+
+// PromoteCandidate applies a blue/green update to the live deployment once its candidate
+// proved healthy: the update is applied as resolved by DeployCandidate, recorded as req, and
+// the post-deploy hooks run. The candidate is left for the caller to delete.
+func (s *Service) PromoteCandidate(ctx context.Context, req, resolved *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error) {
+	if s.deployment == nil || s.deployment.provider == nil {
+		return nil, fmt.Errorf("deployment provider not configured")
+	}
+	if req == nil || resolved == nil {
+		return nil, fmt.Errorf("update request is nil")
+	}
+	s.deployment.resetHealthOnImageChange(ctx, req)
+
+	var resp *interfaces.DeployResponse
+	var err error
+	if s.env == nil || req.Env == nil {
+		resp, err = s.deployment.applyUpdate(ctx, req, resolved)
+	} else {
+		before, _ := s.env.plainEnv(ctx, req.Endpoint)
+		resp, err = s.deployment.applyUpdate(ctx, req, resolved)
+		if err == nil {
+			s.env.recordReplace(ctx, req.Endpoint, before, *req.Env)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.runHooks(ctx, req.Endpoint, hooks.PhasePostDeploy, hooks.OperationUpdate); err != nil {
+		return resp, fmt.Errorf("deployment updated but %w", err)
+	}
+	return resp, nil
+}
@@ -2,13 +2,17 @@ package endpoint
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"strings"
+	"time"
 
 	"waverless/pkg/config"
 	"waverless/pkg/image"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
 	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
 )
 
 // DeploymentManager wraps all runtime deployment operations.
@@ -18,6 +22,10 @@ type DeploymentManager struct {
 	endpointRepo   *mysql.EndpointRepository
 	imageValidator *image.ImageValidator
 	imageConfig    *config.ImageValidationConfig
+	validationRepo *mysql.ImageValidationRepository
+	copyConfig     *config.ImageCopyConfig
+	imageCopier    *image.ImageCopier
+	copyRepo       *mysql.ImageCopyRepository
 }
 
 // NewDeploymentManager creates a deployment manager.
@@ -40,13 +48,246 @@ func NewDeploymentManager(provider interfaces.DeploymentProvider, metadata *Meta
 		SkipOnTimeout: imgConfig.SkipOnTimeout,
 	}
 
-	return &DeploymentManager{
+	m := &DeploymentManager{
 		provider:       provider,
 		metadata:       metadata,
 		endpointRepo:   endpointRepo,
 		imageValidator: image.NewImageValidator(validatorConfig),
 		imageConfig:    imgConfig,
 	}
+	if config.GlobalConfig != nil && config.GlobalConfig.ImageCopy.Registry != "" {
+		m.copyConfig = &config.GlobalConfig.ImageCopy
+		m.imageCopier = image.NewImageCopier(m.copyConfig.Timeout)
+	}
+	return m
+}
+
+// SetImageValidationRepository enables persisting image validation outcomes per endpoint.
+func (m *DeploymentManager) SetImageValidationRepository(repo *mysql.ImageValidationRepository) {
+	m.validationRepo = repo
+}
+
+// ValidateImage runs format and registry checks for an image without deploying.
+// When endpoint is non-empty the outcome is recorded in the endpoint's validation history.
+func (m *DeploymentManager) ValidateImage(ctx context.Context, endpoint, imageRef string, cred *interfaces.RegistryCredential) (*interfaces.ImageValidationResult, error) {
+	if m.imageValidator == nil {
+		return nil, fmt.Errorf("image validator not configured")
+	}
+	if imageRef == "" {
+		return nil, fmt.Errorf("image is required")
+	}
+
+	result, err := m.imageValidator.CheckImageExists(ctx, imageRef, cred)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint != "" {
+		m.recordImageValidation(ctx, endpoint, imageRef, model.ImageValidationSourceAPI, result, false)
+	}
+	return result, nil
+}
+
+// ListImageValidations returns the validation history of an endpoint, most recent first.
+func (m *DeploymentManager) ListImageValidations(ctx context.Context, endpoint string, limit int) ([]*model.ImageValidationRecord, error) {
+	if m.validationRepo == nil {
+		return nil, fmt.Errorf("image validation history not configured")
+	}
+	return m.validationRepo.ListByEndpoint(ctx, endpoint, limit)
+}
+
+// GetLastVerifiedImage returns the most recent successful validation of an endpoint's image.
+func (m *DeploymentManager) GetLastVerifiedImage(ctx context.Context, endpoint string) (*model.ImageValidationRecord, error) {
+	if m.validationRepo == nil {
+		return nil, nil
+	}
+	return m.validationRepo.GetLastVerified(ctx, endpoint)
+}
+
+// recordImageValidation persists a validation outcome. Failures are logged and never block deploys.
+func (m *DeploymentManager) recordImageValidation(ctx context.Context, endpoint, imageRef, source string, result *interfaces.ImageValidationResult, rejected bool) {
+	if m.validationRepo == nil || result == nil {
+		return
+	}
+
+	checkedAt := result.CheckedAt
+	if checkedAt.IsZero() {
+		checkedAt = time.Now()
+	}
+	record := &model.ImageValidationRecord{
+		Endpoint:   endpoint,
+		Image:      imageRef,
+		Source:     source,
+		Valid:      result.Valid,
+		Exists:     result.Exists,
+		Accessible: result.Accessible,
+		Rejected:   rejected,
+		Digest:     result.Digest,
+		MediaType:  result.MediaType,
+		SizeBytes:  result.SizeBytes,
+		Platforms:  model.JSONStringArray(result.Platforms),
+		Error:      truncateString(result.Error, 1024),
+		Warning:    truncateString(result.Warning, 1024),
+		DurationMs: result.DurationMs,
+		CacheHit:   result.CacheHit,
+		CheckedAt:  checkedAt,
+	}
+	if err := m.validationRepo.Create(ctx, record); err != nil {
+		logger.WarnCtx(ctx, "Failed to record image validation for endpoint %s: %v", endpoint, err)
+	}
+}
+
+// SetImageCopyRepository enables persisting provenance of images copied into the internal registry.
+func (m *DeploymentManager) SetImageCopyRepository(repo *mysql.ImageCopyRepository) {
+	m.copyRepo = repo
+}
+
+// ListImageCopies returns the image copy history of an endpoint, most recent first.
+func (m *DeploymentManager) ListImageCopies(ctx context.Context, endpoint string, limit int) ([]*model.ImageCopyRecord, error) {
+	if m.copyRepo == nil {
+		return nil, fmt.Errorf("image copy history not configured")
+	}
+	return m.copyRepo.ListByEndpoint(ctx, endpoint, limit)
+}
+
+// GetImageProvenance returns the upstream origin of an internal image reference (nil if unknown).
+func (m *DeploymentManager) GetImageProvenance(ctx context.Context, targetImage string) (*model.ImageCopyRecord, error) {
+	if m.copyRepo == nil {
+		return nil, fmt.Errorf("image copy history not configured")
+	}
+	return m.copyRepo.GetByTargetImage(ctx, targetImage)
+}
+
+// shouldCopyImage decides whether an image is copied into the internal registry.
+// The request-level flag overrides the config default; trusted registries are never copied.
+func (m *DeploymentManager) shouldCopyImage(requested *bool, imageRef string) (bool, error) {
+	enabled := m.copyConfig != nil && m.copyConfig.Enabled
+	if requested != nil {
+		enabled = *requested
+	}
+	if !enabled || imageRef == "" {
+		return false, nil
+	}
+	if m.imageCopier == nil {
+		return false, fmt.Errorf("image copy requested but no internal registry is configured")
+	}
+
+	registry := image.ImageRegistry(imageRef)
+	if registry == image.NormalizeRegistry(m.copyConfig.Registry) {
+		return false, nil
+	}
+	for _, trusted := range m.copyConfig.TrustedRegistries {
+		if registry == image.NormalizeRegistry(trusted) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// copyImageToInternal copies an image into the internal registry, records provenance
+// and returns the internal reference pinned by digest.
+func (m *DeploymentManager) copyImageToInternal(ctx context.Context, endpoint, imageRef string, cred *interfaces.RegistryCredential) (string, error) {
+	if cred == nil {
+		cred = dockerRegistryCredential(image.ImageRegistry(imageRef))
+	}
+	target := image.CopyTarget{
+		Registry: m.copyConfig.Registry,
+		Prefix:   m.copyConfig.Prefix,
+		Insecure: m.copyConfig.Insecure,
+	}
+	if m.copyConfig.Username != "" {
+		target.Credential = &interfaces.RegistryCredential{
+			Registry: m.copyConfig.Registry,
+			Username: m.copyConfig.Username,
+			Password: m.copyConfig.Password,
+		}
+	}
+
+	logger.InfoCtx(ctx, "Copying image %s into internal registry %s for endpoint %s", imageRef, m.copyConfig.Registry, endpoint)
+	start := time.Now()
+	result, err := m.imageCopier.Copy(ctx, imageRef, cred, target)
+
+	record := &model.ImageCopyRecord{
+		Endpoint:    endpoint,
+		SourceImage: imageRef,
+		Status:      model.ImageCopyStatusSucceeded,
+		DurationMs:  time.Since(start).Milliseconds(),
+		CreatedAt:   time.Now(),
+	}
+	if err != nil {
+		record.Status = model.ImageCopyStatusFailed
+		record.Error = truncateString(err.Error(), 1024)
+	} else {
+		record.SourceDigest = result.SourceDigest
+		record.TargetImage = result.TargetImage
+		record.MediaType = result.MediaType
+		record.BlobsCopied = result.BlobsCopied
+		record.BlobsSkipped = result.BlobsSkipped
+		record.BytesCopied = result.BytesCopied
+		logger.InfoCtx(ctx, "Copied image %s -> %s (blobs copied=%d, skipped=%d, bytes=%d, %dms)",
+			imageRef, result.TargetImage, result.BlobsCopied, result.BlobsSkipped, result.BytesCopied, record.DurationMs)
+	}
+	if m.copyRepo != nil {
+		if recErr := m.copyRepo.Create(ctx, record); recErr != nil {
+			logger.WarnCtx(ctx, "Failed to record image copy for endpoint %s: %v", endpoint, recErr)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return result.TargetImage, nil
+}
+
+// resolveDeployImage returns the image to deploy: the internal copy when copying applies,
+// otherwise the requested image. Copy failures only block the deploy when FailOnError is set.
+func (m *DeploymentManager) resolveDeployImage(ctx context.Context, endpoint, imageRef string, requested *bool, cred *interfaces.RegistryCredential) (string, error) {
+	copyImage, err := m.shouldCopyImage(requested, imageRef)
+	if err != nil {
+		return "", err
+	}
+	if !copyImage {
+		return imageRef, nil
+	}
+
+	internal, err := m.copyImageToInternal(ctx, endpoint, imageRef, cred)
+	if err != nil {
+		if m.copyConfig.FailOnError {
+			return "", fmt.Errorf("failed to copy image into internal registry: %w", err)
+		}
+		logger.WarnCtx(ctx, "Image copy failed for endpoint %s, deploying original image %s: %v", endpoint, imageRef, err)
+		return imageRef, nil
+	}
+	return internal, nil
+}
+
+// dockerRegistryCredential looks up credentials for a registry in the docker config section.
+func dockerRegistryCredential(registry string) *interfaces.RegistryCredential {
+	if config.GlobalConfig == nil {
+		return nil
+	}
+	for key, auth := range config.GlobalConfig.Docker.Registries {
+		host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://"), "/v1/")
+		if image.NormalizeRegistry(host) != registry {
+			continue
+		}
+		username, password := auth.Username, auth.Password
+		if username == "" && auth.Auth != "" {
+			if decoded, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil {
+				if parts := strings.SplitN(string(decoded), ":", 2); len(parts) == 2 {
+					username, password = parts[0], parts[1]
+				}
+			}
+		}
+		return &interfaces.RegistryCredential{Registry: registry, Username: username, Password: password}
+	}
+	return nil
+}
+
+// truncateString limits s to max bytes to fit column sizes.
+func truncateString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
 }
 
 // Deploy provisions runtime resources and persists metadata on success.
@@ -69,10 +310,27 @@ func (m *DeploymentManager) Deploy(ctx context.Context, req *interfaces.DeployRe
 		return nil, fmt.Errorf("deploy request is nil")
 	}
 
+	// Pin the endpoint to its deployment provider; an endpoint cannot move between providers
+	provider, _, err := m.routeProvider(ctx, req.Endpoint, req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	req.Provider = provider
+
+	// Redeploys keep the endpoint's RunPod compatibility setting unless the request changes it
+	if req.RunPodCompat == nil && metadata != nil {
+		req.RunPodCompat = &metadata.RunPodCompat
+	}
+
 	// Step 1: Always validate image format (Requirements 1.1, 1.2)
 	if m.imageValidator != nil && req.Image != "" {
 		if err := m.imageValidator.ValidateImageFormat(req.Image); err != nil {
 			logger.WarnCtx(ctx, "Image format validation failed for endpoint %s: %v", req.Endpoint, err)
+			m.recordImageValidation(ctx, req.Endpoint, req.Image, model.ImageValidationSourceDeploy, &interfaces.ImageValidationResult{
+				Valid:     false,
+				Error:     err.Error(),
+				CheckedAt: time.Now(),
+			}, true)
 			return nil, fmt.Errorf("image format validation failed: %w", err)
 		}
 		logger.InfoCtx(ctx, "Image format validation passed for endpoint %s, image: %s", req.Endpoint, req.Image)
@@ -133,6 +391,10 @@ func (m *DeploymentManager) Deploy(ctx context.Context, req *interfaces.DeployRe
 			// SkipOnTimeout is true, proceed with warning
 			logger.WarnCtx(ctx, "Image validation error for endpoint %s, proceeding with warning: %v", req.Endpoint, err)
 		} else if result != nil {
+			// Record the outcome so the UI can explain rejections and show when the image was last verified
+			rejected := !result.Valid || (result.Error != "" && (!result.Exists || !result.Accessible))
+			m.recordImageValidation(ctx, req.Endpoint, req.Image, model.ImageValidationSourceDeploy, result, rejected)
+
 			// Handle validation result
 			if !result.Valid {
 				// Format is invalid (should not happen as we validated above, but handle anyway)
@@ -166,8 +428,21 @@ func (m *DeploymentManager) Deploy(ctx context.Context, req *interfaces.DeployRe
 		logger.InfoCtx(ctx, "Skipping image existence check for endpoint %s (validation disabled or no image)", req.Endpoint)
 	}
 
-	// Step 3: Proceed with deployment
-	resp, err := m.provider.Deploy(ctx, req)
+	// Step 3: Optionally copy the image into the internal registry and deploy it by digest.
+	// Metadata keeps the requested image; the copy record links it to the internal reference.
+	deployReq := req
+	deployImage, err := m.resolveDeployImage(ctx, req.Endpoint, req.Image, req.CopyImage, req.RegistryCredential)
+	if err != nil {
+		return nil, err
+	}
+	if deployImage != req.Image {
+		copied := *req
+		copied.Image = deployImage
+		deployReq = &copied
+	}
+
+	// Step 4: Proceed with deployment
+	resp, err := m.provider.Deploy(ctx, deployReq)
 	if err != nil {
 		return nil, err
 	}
@@ -179,6 +454,9 @@ func (m *DeploymentManager) Deploy(ctx context.Context, req *interfaces.DeployRe
 		if metadata.SpecName == "" {
 			metadata.SpecName = req.SpecName
 		}
+		if metadata.Provider == "" {
+			metadata.Provider = req.Provider
+		}
 		if metadata.Image == "" {
 			metadata.Image = req.Image
 		}
@@ -196,6 +474,14 @@ func (m *DeploymentManager) Deploy(ctx context.Context, req *interfaces.DeployRe
 	return resp, nil
 }
 
+// routeProvider returns the name and provider serving endpoint (see interfaces.ProviderRouter)
+func (m *DeploymentManager) routeProvider(ctx context.Context, endpoint, requested string) (string, interfaces.DeploymentProvider, error) {
+	if router, ok := m.provider.(interfaces.ProviderRouter); ok {
+		return router.Route(ctx, endpoint, requested)
+	}
+	return requested, m.provider, nil
+}
+
 // Update orchestrates deployment updates and metadata synchronization.
 // If the endpoint is UNHEALTHY due to image issues and the update is trying to scale up
 // without changing the image, the update will be blocked.
@@ -227,17 +513,49 @@ func (m *DeploymentManager) Update(ctx context.Context, req *interfaces.UpdateDe
 		}
 	}
 
-	// If image is being changed, reset health status to HEALTHY
-	// This allows the endpoint to be redeployed with the new image
-	if m.endpointRepo != nil && req.Image != "" {
-		logger.InfoCtx(ctx, "Image changed for endpoint %s, resetting health status to HEALTHY", req.Endpoint)
-		if err := m.endpointRepo.UpdateHealthStatus(ctx, req.Endpoint, "HEALTHY", ""); err != nil {
-			logger.WarnCtx(ctx, "Failed to reset health status for endpoint %s: %v", req.Endpoint, err)
-			// Don't fail the update, just log the warning
-		}
+	m.resetHealthOnImageChange(ctx, req)
+
+	updateReq, err := m.resolveUpdateImage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return m.applyUpdate(ctx, req, updateReq)
+}
+
+// resetHealthOnImageChange resets the health status to HEALTHY when an update changes the image.
+// This allows the endpoint to be redeployed with the new image
+func (m *DeploymentManager) resetHealthOnImageChange(ctx context.Context, req *interfaces.UpdateDeploymentRequest) {
+	if m.endpointRepo == nil || req.Image == "" {
+		return
+	}
+	logger.InfoCtx(ctx, "Image changed for endpoint %s, resetting health status to HEALTHY", req.Endpoint)
+	if err := m.endpointRepo.UpdateHealthStatus(ctx, req.Endpoint, string(model.HealthStatusHealthy), model.HealthReasonNone, ""); err != nil {
+		// Don't fail the update, just log the warning
+		logger.WarnCtx(ctx, "Failed to reset health status for endpoint %s: %v", req.Endpoint, err)
+	}
+}
+
+// resolveUpdateImage returns req with its image replaced by the image to deploy (e.g. the
+// internal registry copy), or req itself when that is the same
+func (m *DeploymentManager) resolveUpdateImage(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.UpdateDeploymentRequest, error) {
+	if req.Image == "" {
+		return req, nil
+	}
+	deployImage, err := m.resolveDeployImage(ctx, req.Endpoint, req.Image, req.CopyImage, nil)
+	if err != nil {
+		return nil, err
+	}
+	if deployImage == req.Image {
+		return req, nil
 	}
+	copied := *req
+	copied.Image = deployImage
+	return &copied, nil
+}
 
-	resp, err := m.provider.UpdateDeployment(ctx, req)
+// applyUpdate updates the deployment with updateReq and records req in the metadata
+func (m *DeploymentManager) applyUpdate(ctx context.Context, req, updateReq *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error) {
+	resp, err := m.provider.UpdateDeployment(ctx, updateReq)
 	if err != nil {
 		return nil, err
 	}
@@ -245,30 +563,7 @@ func (m *DeploymentManager) Update(ctx context.Context, req *interfaces.UpdateDe
 	if m.metadata != nil {
 		meta, err := m.metadata.Get(ctx, req.Endpoint)
 		if err == nil && meta != nil {
-			if req.SpecName != "" {
-				meta.SpecName = req.SpecName
-			}
-			if req.Image != "" {
-				meta.Image = req.Image
-			}
-			if req.Replicas != nil {
-				meta.Replicas = *req.Replicas
-				// Update status based on replicas
-				if *req.Replicas == 0 {
-					meta.Status = "Stopped"
-				} else if meta.Status == "Stopped" || meta.Status == "Deploying" {
-					meta.Status = "Pending"
-				}
-			}
-			if req.TaskTimeout != nil {
-				meta.TaskTimeout = *req.TaskTimeout
-			}
-			if req.EnablePtrace != nil {
-				meta.EnablePtrace = *req.EnablePtrace
-			}
-			if req.Env != nil {
-				meta.Env = *req.Env
-			}
+			applyUpdateToMetadata(meta, req)
 			if err := m.metadata.Save(ctx, meta); err != nil {
 				return resp, fmt.Errorf("deployment updated but failed to persist metadata: %w", err)
 			}
@@ -278,6 +573,39 @@ func (m *DeploymentManager) Update(ctx context.Context, req *interfaces.UpdateDe
 	return resp, nil
 }
 
+// applyUpdateToMetadata copies the fields set in an update request into endpoint metadata
+func applyUpdateToMetadata(meta *interfaces.EndpointMetadata, req *interfaces.UpdateDeploymentRequest) {
+	if req.SpecName != "" {
+		meta.SpecName = req.SpecName
+	}
+	if req.Image != "" {
+		meta.Image = req.Image
+		// An image set by hand is no longer the registry version's
+		meta.Model = req.Model
+	}
+	if req.Replicas != nil {
+		meta.Replicas = *req.Replicas
+		// Update status based on replicas
+		if *req.Replicas == 0 {
+			meta.Status = "Stopped"
+		} else if meta.Status == "Stopped" || meta.Status == "Deploying" {
+			meta.Status = "Pending"
+		}
+	}
+	if req.TaskTimeout != nil {
+		meta.TaskTimeout = *req.TaskTimeout
+	}
+	if req.EnablePtrace != nil {
+		meta.EnablePtrace = *req.EnablePtrace
+	}
+	if req.RunPodCompat != nil {
+		meta.RunPodCompat = *req.RunPodCompat
+	}
+	if req.Env != nil {
+		meta.Env = *req.Env
+	}
+}
+
 // Delete destroys runtime resources and metadata.
 func (m *DeploymentManager) Delete(ctx context.Context, name string) error {
 	if m.provider == nil {
@@ -15,6 +15,9 @@ type mockDeploymentProvider struct {
 	deployFunc           func(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error)
 	updateDeploymentFunc func(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error)
 	deleteAppFunc        func(ctx context.Context, name string) error
+	listAppsFunc         func(ctx context.Context) ([]*interfaces.AppInfo, error)
+	getAppStatusFunc     func(ctx context.Context, endpoint string) (*interfaces.AppStatus, error)
+	scaleAppFunc         func(ctx context.Context, endpoint string, replicas int) error
 }
 
 func (m *mockDeploymentProvider) Deploy(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
@@ -50,6 +53,9 @@ func (m *mockDeploymentProvider) GetApp(ctx context.Context, name string) (*inte
 	return nil, nil
 }
 func (m *mockDeploymentProvider) ListApps(ctx context.Context) ([]*interfaces.AppInfo, error) {
+	if m.listAppsFunc != nil {
+		return m.listAppsFunc(ctx)
+	}
 	return nil, nil
 }
 func (m *mockDeploymentProvider) GetAppLogs(ctx context.Context, name string, lines int, podNames ...string) (string, error) {
@@ -71,9 +77,15 @@ func (m *mockDeploymentProvider) GetDefaultEnv(ctx context.Context) (map[string]
 	return nil, nil
 }
 func (m *mockDeploymentProvider) ScaleApp(ctx context.Context, endpoint string, replicas int) error {
+	if m.scaleAppFunc != nil {
+		return m.scaleAppFunc(ctx, endpoint, replicas)
+	}
 	return nil
 }
 func (m *mockDeploymentProvider) GetAppStatus(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+	if m.getAppStatusFunc != nil {
+		return m.getAppStatusFunc(ctx, endpoint)
+	}
 	return nil, nil
 }
 func (m *mockDeploymentProvider) WatchReplicas(ctx context.Context, callback interfaces.ReplicaCallback) error {
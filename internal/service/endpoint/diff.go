@@ -0,0 +1,175 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"waverless/pkg/interfaces"
+)
+
+// Field diff operations
+const (
+	DiffOpAdded   = "added"
+	DiffOpRemoved = "removed"
+	DiffOpChanged = "changed"
+)
+
+// FieldDiff is one field that differs between the live and the proposed deployment
+type FieldDiff struct {
+	Field    string      `json:"field"` // e.g. "image", "limits.nvidia.com/gpu", "HF_HOME", "/models"
+	Op       string      `json:"op"`    // added, removed, changed
+	Current  interface{} `json:"current,omitempty"`
+	Proposed interface{} `json:"proposed,omitempty"`
+}
+
+// DeploymentDiff is a reviewable diff between the live state of an endpoint and a proposed deploy request
+type DeploymentDiff struct {
+	Endpoint        string      `json:"endpoint"`
+	Exists          bool        `json:"exists"`          // The endpoint is deployed; otherwise every field is added
+	Changed         bool        `json:"changed"`         // At least one field differs
+	RuntimeCompared bool        `json:"runtimeCompared"` // Resources and volumes were compared on the provider's live and rendered resources
+	Image           []FieldDiff `json:"image"`
+	Resources       []FieldDiff `json:"resources"`
+	Env             []FieldDiff `json:"env"`
+	Volumes         []FieldDiff `json:"volumes"`
+	Tolerations     []FieldDiff `json:"tolerations"`
+	Autoscaler      []FieldDiff `json:"autoscaler"`
+}
+
+// Diff compares the live deployment and metadata of an endpoint with what req and
+// proposed would deploy. Nothing is validated or applied.
+func (m *DeploymentManager) Diff(ctx context.Context, req *interfaces.DeployRequest, proposed *interfaces.EndpointMetadata) (*DeploymentDiff, error) {
+	if m.provider == nil {
+		return nil, fmt.Errorf("deployment provider not configured")
+	}
+	if req == nil {
+		return nil, fmt.Errorf("deploy request is nil")
+	}
+	if req.RunPodCompat == nil && proposed != nil {
+		req.RunPodCompat = &proposed.RunPodCompat
+	}
+
+	var current *interfaces.EndpointMetadata
+	if m.metadata != nil {
+		meta, err := m.metadata.Get(ctx, req.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get endpoint metadata: %w", err)
+		}
+		current = meta
+	}
+
+	diff := &DeploymentDiff{Endpoint: req.Endpoint, Exists: current != nil}
+
+	var liveView, proposedView *interfaces.DeploymentView
+	if viewer, ok := interfaces.ResolveProvider(ctx, m.provider, req.Endpoint, req.Provider).(interfaces.DeploymentViewer); ok {
+		live, rendered, err := viewer.ViewDeployment(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		liveView, proposedView = live, rendered
+		diff.RuntimeCompared = true
+		diff.Exists = diff.Exists || live != nil
+	} else {
+		// Without the provider's view only what metadata records can be compared
+		if current != nil {
+			liveView = &interfaces.DeploymentView{Image: current.Image, Env: current.Env}
+		}
+		proposedView = &interfaces.DeploymentView{Image: req.Image, Env: req.Env}
+	}
+	if liveView == nil {
+		liveView = &interfaces.DeploymentView{}
+	}
+
+	diff.compareViews(liveView, proposedView)
+	if proposed != nil {
+		diff.Autoscaler = autoscalerDiff(current, proposed)
+		diff.Changed = diff.Changed || len(diff.Autoscaler) > 0
+	}
+	return diff, nil
+}
+
+// viewDiff diffs the runtime state of a deployed endpoint before and after an update
+func viewDiff(endpoint string, current, proposed *interfaces.DeploymentView) *DeploymentDiff {
+	diff := &DeploymentDiff{Endpoint: endpoint, Exists: true, RuntimeCompared: true, Autoscaler: []FieldDiff{}}
+	diff.compareViews(current, proposed)
+	return diff
+}
+
+// compareViews fills the runtime sections of the diff
+func (d *DeploymentDiff) compareViews(live, proposed *interfaces.DeploymentView) {
+	d.Image = diffValues([]string{"image"}, []interface{}{live.Image}, []interface{}{proposed.Image}, live.Image != "")
+	d.Resources = diffStringMaps(live.Resources, proposed.Resources)
+	d.Env = diffStringMaps(live.Env, proposed.Env)
+	d.Volumes = diffStringMaps(live.Volumes, proposed.Volumes)
+	d.Tolerations = diffStringMaps(live.Tolerations, proposed.Tolerations)
+	d.Changed = len(d.Image)+len(d.Resources)+len(d.Env)+len(d.Volumes)+len(d.Tolerations) > 0
+}
+
+// autoscalerDiff compares the scaling settings of stored and proposed metadata
+func autoscalerDiff(current, proposed *interfaces.EndpointMetadata) []FieldDiff {
+	exists := current != nil
+	if current == nil {
+		current = &interfaces.EndpointMetadata{}
+	}
+	names := []string{
+		"replicas", "minReplicas", "maxReplicas", "scaleUpThreshold", "scaleDownIdleTime",
+		"scaleUpCooldown", "scaleDownCooldown", "priority", "enableDynamicPrio", "highLoadThreshold", "priorityBoost",
+	}
+	settings := func(meta *interfaces.EndpointMetadata) []interface{} {
+		var dynamicPrio interface{}
+		if meta.EnableDynamicPrio != nil {
+			dynamicPrio = *meta.EnableDynamicPrio
+		}
+		return []interface{}{
+			meta.Replicas, meta.MinReplicas, meta.MaxReplicas, meta.ScaleUpThreshold, meta.ScaleDownIdleTime,
+			meta.ScaleUpCooldown, meta.ScaleDownCooldown, meta.Priority, dynamicPrio, meta.HighLoadThreshold, meta.PriorityBoost,
+		}
+	}
+	return diffValues(names, settings(current), settings(proposed), exists)
+}
+
+// diffValues compares named values pairwise. Without a current state every set value is added.
+func diffValues(names []string, current, proposed []interface{}, exists bool) []FieldDiff {
+	diffs := []FieldDiff{}
+	for i, name := range names {
+		cur, prop := current[i], proposed[i]
+		switch {
+		case !exists && prop != nil && !reflect.ValueOf(prop).IsZero():
+			diffs = append(diffs, FieldDiff{Field: name, Op: DiffOpAdded, Proposed: prop})
+		case exists && !reflect.DeepEqual(cur, prop):
+			diffs = append(diffs, FieldDiff{Field: name, Op: DiffOpChanged, Current: cur, Proposed: prop})
+		}
+	}
+	return diffs
+}
+
+// diffStringMaps compares two maps key by key, in key order
+func diffStringMaps(current, proposed map[string]string) []FieldDiff {
+	keys := make([]string, 0, len(current)+len(proposed))
+	for k := range current {
+		keys = append(keys, k)
+	}
+	for k := range proposed {
+		if _, ok := current[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	diffs := []FieldDiff{}
+	for _, k := range keys {
+		cur, inCurrent := current[k]
+		prop, inProposed := proposed[k]
+		switch {
+		case !inCurrent:
+			diffs = append(diffs, FieldDiff{Field: k, Op: DiffOpAdded, Proposed: prop})
+		case !inProposed:
+			diffs = append(diffs, FieldDiff{Field: k, Op: DiffOpRemoved, Current: cur})
+		case cur != prop:
+			diffs = append(diffs, FieldDiff{Field: k, Op: DiffOpChanged, Current: cur, Proposed: prop})
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,88 @@
+package endpoint
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"waverless/pkg/interfaces"
+)
+
+// mockViewerProvider adds a runtime view to mockDeploymentProvider
+type mockViewerProvider struct {
+	mockDeploymentProvider
+	current, proposed *interfaces.DeploymentView
+}
+
+func (m *mockViewerProvider) ViewDeployment(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeploymentView, *interfaces.DeploymentView, error) {
+	return m.current, m.proposed, nil
+}
+
+func (m *mockViewerProvider) LiveDeployment(ctx context.Context, endpoint string) (*interfaces.DeploymentView, error) {
+	return m.current, nil
+}
+
+func TestDeploymentManager_Diff(t *testing.T) {
+	provider := &mockViewerProvider{
+		current: &interfaces.DeploymentView{
+			Image:     "flux:1",
+			Resources: map[string]string{"limits.nvidia.com/gpu": "1", "requests.cpu": "4"},
+			Env:       map[string]string{"A": "1", "OLD": "x"},
+			Volumes:   map[string]string{"/models": "pvc:models"},
+		},
+		proposed: &interfaces.DeploymentView{
+			Image:     "flux:2",
+			Resources: map[string]string{"limits.nvidia.com/gpu": "2", "requests.cpu": "4"},
+			Env:       map[string]string{"A": "1", "NEW": "y"},
+			Volumes:   map[string]string{"/models": "pvc:models"},
+		},
+	}
+
+	diff, err := NewDeploymentManager(provider, nil, nil).Diff(context.Background(), &interfaces.DeployRequest{Endpoint: "flux"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !diff.Exists || !diff.Changed || !diff.RuntimeCompared {
+		t.Errorf("unexpected flags: %+v", diff)
+	}
+
+	wantImage := []FieldDiff{{Field: "image", Op: DiffOpChanged, Current: "flux:1", Proposed: "flux:2"}}
+	if !reflect.DeepEqual(diff.Image, wantImage) {
+		t.Errorf("image diff = %+v", diff.Image)
+	}
+	wantResources := []FieldDiff{{Field: "limits.nvidia.com/gpu", Op: DiffOpChanged, Current: "1", Proposed: "2"}}
+	if !reflect.DeepEqual(diff.Resources, wantResources) {
+		t.Errorf("resources diff = %+v", diff.Resources)
+	}
+	wantEnv := []FieldDiff{
+		{Field: "NEW", Op: DiffOpAdded, Proposed: "y"},
+		{Field: "OLD", Op: DiffOpRemoved, Current: "x"},
+	}
+	if !reflect.DeepEqual(diff.Env, wantEnv) {
+		t.Errorf("env diff = %+v", diff.Env)
+	}
+	if len(diff.Volumes) != 0 {
+		t.Errorf("volumes diff = %+v", diff.Volumes)
+	}
+}
+
+func TestAutoscalerDiff(t *testing.T) {
+	enabled := true
+	proposed := &interfaces.EndpointMetadata{Replicas: 0, MaxReplicas: 10, EnableDynamicPrio: &enabled}
+
+	// New endpoint: only the settings that are set show up
+	want := []FieldDiff{
+		{Field: "maxReplicas", Op: DiffOpAdded, Proposed: 10},
+		{Field: "enableDynamicPrio", Op: DiffOpAdded, Proposed: true},
+	}
+	if got := autoscalerDiff(nil, proposed); !reflect.DeepEqual(got, want) {
+		t.Errorf("new endpoint diff = %+v", got)
+	}
+
+	// Existing endpoint: scaling to zero is a change, not a removal
+	current := &interfaces.EndpointMetadata{Replicas: 2, MaxReplicas: 10, EnableDynamicPrio: &enabled}
+	want = []FieldDiff{{Field: "replicas", Op: DiffOpChanged, Current: 2, Proposed: 0}}
+	if got := autoscalerDiff(current, proposed); !reflect.DeepEqual(got, want) {
+		t.Errorf("existing endpoint diff = %+v", got)
+	}
+}
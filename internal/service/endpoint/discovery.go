@@ -0,0 +1,57 @@
+package endpoint
+
+import (
+	"context"
+
+	"waverless/pkg/discovery"
+	"waverless/pkg/logger"
+)
+
+// SetDiscovery registers endpoints in service discovery when they are deployed and
+// deregisters them when they are deleted (for dependency injection)
+func (s *Service) SetDiscovery(registrar discovery.Registrar) {
+	s.discovery = registrar
+}
+
+// registerDiscovery registers a deployed endpoint. Discovery is best effort: a failure is
+// logged and repaired by the next deploy or SyncDiscovery.
+func (s *Service) registerDiscovery(ctx context.Context, endpoint string) {
+	if s.discovery == nil || endpoint == "" {
+		return
+	}
+	if err := s.discovery.Register(ctx, endpoint); err != nil {
+		logger.WarnCtx(ctx, "Failed to register endpoint %s in %s: %v", endpoint, s.discovery.Name(), err)
+	}
+}
+
+// deregisterDiscovery removes the registration of a deleted endpoint
+func (s *Service) deregisterDiscovery(ctx context.Context, endpoint string) {
+	if s.discovery == nil || endpoint == "" {
+		return
+	}
+	if err := s.discovery.Deregister(ctx, endpoint); err != nil {
+		logger.WarnCtx(ctx, "Failed to deregister endpoint %s from %s: %v", endpoint, s.discovery.Name(), err)
+	}
+}
+
+// SyncDiscovery registers all existing endpoints, e.g. after discovery was enabled or the
+// discovery backend lost its state. It returns the number of registered endpoints.
+func (s *Service) SyncDiscovery(ctx context.Context) (int, error) {
+	if s.discovery == nil || s.metadata == nil {
+		return 0, nil
+	}
+	endpoints, err := s.metadata.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	registered := 0
+	for _, ep := range endpoints {
+		if err := s.discovery.Register(ctx, ep.Name); err != nil {
+			logger.WarnCtx(ctx, "Failed to register endpoint %s in %s: %v", ep.Name, s.discovery.Name(), err)
+			continue
+		}
+		registered++
+	}
+	return registered, nil
+}
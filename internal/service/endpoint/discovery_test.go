@@ -0,0 +1,62 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"waverless/pkg/interfaces"
+)
+
+// fakeRegistrar records registrations
+type fakeRegistrar struct {
+	registered   []string
+	deregistered []string
+	err          error
+}
+
+func (f *fakeRegistrar) Name() string { return "fake" }
+
+func (f *fakeRegistrar) Register(ctx context.Context, endpoint string) error {
+	f.registered = append(f.registered, endpoint)
+	return f.err
+}
+
+func (f *fakeRegistrar) Deregister(ctx context.Context, endpoint string) error {
+	f.deregistered = append(f.deregistered, endpoint)
+	return f.err
+}
+
+func TestService_Discovery(t *testing.T) {
+	provider := &mockDeploymentProvider{
+		deployFunc: func(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
+			if req.Endpoint == "broken" {
+				return nil, errors.New("deploy failed")
+			}
+			return &interfaces.DeployResponse{Endpoint: req.Endpoint}, nil
+		},
+	}
+	s := newHookTestService(provider, &fakeLifecycleHooks{})
+	registrar := &fakeRegistrar{}
+	s.SetDiscovery(registrar)
+
+	if _, err := s.Deploy(context.Background(), &interfaces.DeployRequest{Endpoint: "demo", Image: "nginx"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Deploy(context.Background(), &interfaces.DeployRequest{Endpoint: "broken", Image: "nginx"}, nil); err == nil {
+		t.Fatal("expected deploy error")
+	}
+	if !reflect.DeepEqual(registrar.registered, []string{"demo"}) {
+		t.Errorf("registered = %v, want only the deployed endpoint", registrar.registered)
+	}
+
+	// Discovery failures never fail the operation
+	registrar.err = errors.New("consul unavailable")
+	if err := s.DeleteDeployment(context.Background(), "demo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(registrar.deregistered, []string{"demo"}) {
+		t.Errorf("deregistered = %v", registrar.deregistered)
+	}
+}
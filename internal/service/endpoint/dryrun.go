@@ -0,0 +1,246 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/store/mysql/model"
+)
+
+// DeployPlan is the outcome of a dry-run deploy or update: every check that ran, the
+// resources that would change and the problems the real request would hit. Nothing is written.
+type DeployPlan struct {
+	DryRun         bool                        `json:"dryRun"`
+	Endpoint       string                      `json:"endpoint"`
+	Valid          bool                        `json:"valid"`              // No check failed
+	Errors         []string                    `json:"errors,omitempty"`   // Problems that block or break the request
+	Warnings       []string                    `json:"warnings,omitempty"` // Request would proceed, but worth a look
+	Changes        []interfaces.ResourceChange `json:"changes"`            // Metadata and runtime resources that would change
+	Manifest       string                      `json:"manifest,omitempty"` // Rendered runtime resources
+	ProviderDryRun bool                        `json:"providerDryRun"`     // The provider validated the resources itself
+	Diff           *DeploymentDiff             `json:"diff,omitempty"`     // Field-level runtime diff of an update, when the provider describes deployments
+}
+
+func (p *DeployPlan) addError(format string, args ...interface{}) {
+	p.Errors = append(p.Errors, fmt.Sprintf(format, args...))
+}
+
+func (p *DeployPlan) addWarning(format string, args ...interface{}) {
+	p.Warnings = append(p.Warnings, fmt.Sprintf(format, args...))
+}
+
+// DryRunDeploy runs the checks of Deploy (image format and existence, image copy policy)
+// plus the provider dry run, and compares metadata with what is stored, without deploying,
+// copying images or recording validation history.
+func (m *DeploymentManager) DryRunDeploy(ctx context.Context, req *interfaces.DeployRequest, metadata *interfaces.EndpointMetadata) (*DeployPlan, error) {
+	if m.provider == nil {
+		return nil, fmt.Errorf("deployment provider not configured")
+	}
+	if req == nil {
+		return nil, fmt.Errorf("deploy request is nil")
+	}
+
+	if req.RunPodCompat == nil && metadata != nil {
+		req.RunPodCompat = &metadata.RunPodCompat
+	}
+
+	plan := &DeployPlan{DryRun: true, Endpoint: req.Endpoint}
+	if req.Endpoint == "" {
+		plan.addError("endpoint name is required")
+	}
+
+	shouldValidateImage := m.imageConfig != nil && m.imageConfig.Enabled
+	if req.ValidateImage != nil {
+		shouldValidateImage = *req.ValidateImage
+	}
+	m.dryRunImage(ctx, plan, req.Image, shouldValidateImage, req.CopyImage, req.RegistryCredential)
+
+	if metadata != nil && m.metadata != nil {
+		current, err := m.metadata.Get(ctx, req.Endpoint)
+		if err != nil {
+			plan.addWarning("could not load current metadata: %v", err)
+		} else {
+			plan.Changes = append(plan.Changes, metadataChange(req.Endpoint, current, metadata))
+		}
+	}
+
+	name, provider, err := m.routeProvider(ctx, req.Endpoint, req.Provider)
+	if err != nil {
+		plan.addError("%v", err)
+	} else if runner, ok := provider.(interfaces.DeploymentDryRunner); ok {
+		req.Provider = name
+		result, err := runner.DryRunDeploy(ctx, req)
+		plan.addProviderResult(result, err)
+	} else {
+		req.Provider = name
+		plan.addWarning("deployment provider has no dry-run support, runtime resources were not validated")
+		if manifest, err := provider.PreviewDeploymentYAML(ctx, req); err != nil {
+			plan.addError("%v", err)
+		} else {
+			plan.Manifest = manifest
+		}
+	}
+
+	plan.Valid = len(plan.Errors) == 0
+	return plan, nil
+}
+
+// DryRunUpdate runs the checks of Update plus image validation and the provider dry run
+// without changing the deployment, the health status or the metadata
+func (m *DeploymentManager) DryRunUpdate(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*DeployPlan, error) {
+	if m.provider == nil {
+		return nil, fmt.Errorf("deployment provider not configured")
+	}
+	if req == nil {
+		return nil, fmt.Errorf("update request is nil")
+	}
+
+	plan := &DeployPlan{DryRun: true, Endpoint: req.Endpoint}
+
+	var current *interfaces.EndpointMetadata
+	if m.metadata != nil {
+		meta, err := m.metadata.Get(ctx, req.Endpoint)
+		if err != nil {
+			plan.addWarning("could not load current metadata: %v", err)
+		}
+		current = meta
+	}
+
+	// Same gate as Update: scaling up an endpoint blocked by a broken image is refused
+	if m.endpointRepo != nil && req.Replicas != nil && req.Image == "" && current != nil && *req.Replicas > current.Replicas {
+		blocked, reason, err := m.endpointRepo.IsBlockedDueToImageFailure(ctx, req.Endpoint)
+		if err == nil && blocked {
+			plan.addError("%v: %s - please update the image configuration before scaling up", ErrEndpointBlockedDueToImageFailure, reason)
+		}
+	}
+
+	if req.Image != "" {
+		shouldValidateImage := m.imageConfig != nil && m.imageConfig.Enabled
+		m.dryRunImage(ctx, plan, req.Image, shouldValidateImage, req.CopyImage, nil)
+		if current != nil && current.HealthStatus != "" && current.HealthStatus != string(model.HealthStatusHealthy) {
+			plan.addWarning("health status %s will be reset to HEALTHY because the image changes", current.HealthStatus)
+		}
+	}
+
+	if current != nil {
+		desired := *current
+		applyUpdateToMetadata(&desired, req)
+		plan.Changes = append(plan.Changes, metadataChange(req.Endpoint, current, &desired))
+	}
+
+	if runner, ok := interfaces.ResolveProvider(ctx, m.provider, req.Endpoint, "").(interfaces.DeploymentDryRunner); ok {
+		result, err := runner.DryRunUpdateDeployment(ctx, req)
+		plan.addProviderResult(result, err)
+	} else {
+		plan.addWarning("deployment provider has no dry-run support, runtime resources were not validated")
+	}
+
+	plan.Valid = len(plan.Errors) == 0
+	return plan, nil
+}
+
+// dryRunImage validates an image the way Deploy does, without recording the outcome,
+// and reports whether it would be copied into the internal registry
+func (m *DeploymentManager) dryRunImage(ctx context.Context, plan *DeployPlan, imageRef string, validate bool, copyImage *bool, cred *interfaces.RegistryCredential) {
+	if imageRef == "" {
+		return
+	}
+	if m.imageValidator != nil {
+		if err := m.imageValidator.ValidateImageFormat(imageRef); err != nil {
+			plan.addError("image format validation failed: %v", err)
+			return
+		}
+		if validate {
+			result, err := m.imageValidator.CheckImageExists(ctx, imageRef, cred)
+			switch {
+			case err != nil && (m.imageConfig == nil || !m.imageConfig.SkipOnTimeout):
+				plan.addError("image validation failed: %v", err)
+			case err != nil:
+				plan.addWarning("image validation error, the deploy would proceed: %v", err)
+			case result == nil:
+			case !result.Valid:
+				plan.addError("image validation failed: %s", result.Error)
+			case result.Error != "" && !result.Exists:
+				plan.addError("image not found or inaccessible: %s", result.Error)
+			case result.Error != "" && !result.Accessible:
+				plan.addError("image not accessible: %s", result.Error)
+			case result.Warning != "":
+				plan.addWarning("image validation: %s", result.Warning)
+			}
+		}
+	}
+
+	copyToInternal, err := m.shouldCopyImage(copyImage, imageRef)
+	if err != nil {
+		plan.addError("%v", err)
+	} else if copyToInternal {
+		plan.addWarning("image will be copied into the internal registry %s and deployed by digest", m.copyConfig.Registry)
+	}
+}
+
+// addProviderResult merges the provider's dry run into the plan
+func (p *DeployPlan) addProviderResult(result *interfaces.DryRunResult, err error) {
+	if err != nil {
+		p.addError("%v", err)
+		return
+	}
+	p.ProviderDryRun = true
+	if result == nil {
+		return
+	}
+	p.Changes = append(p.Changes, result.Changes...)
+	p.Warnings = append(p.Warnings, result.Warnings...)
+	p.Manifest = result.Manifest
+	if result.Current != nil && result.Proposed != nil {
+		p.Diff = viewDiff(p.Endpoint, result.Current, result.Proposed)
+	}
+}
+
+// metadataChange compares the configuration fields of stored and desired endpoint metadata
+func metadataChange(name string, current, desired *interfaces.EndpointMetadata) interfaces.ResourceChange {
+	change := interfaces.ResourceChange{Kind: "Endpoint", Name: name}
+	if current == nil {
+		change.Action = interfaces.ChangeActionCreate
+		return change
+	}
+
+	fields := []struct {
+		name string
+		a, b interface{}
+	}{
+		{"specName", current.SpecName, desired.SpecName},
+		{"image", current.Image, desired.Image},
+		{"imagePrefix", current.ImagePrefix, desired.ImagePrefix},
+		{"replicas", current.Replicas, desired.Replicas},
+		{"gpuCount", current.GpuCount, desired.GpuCount},
+		{"taskTimeout", current.TaskTimeout, desired.TaskTimeout},
+		{"maxPendingTasks", current.MaxPendingTasks, desired.MaxPendingTasks},
+		{"env", current.Env, desired.Env},
+		{"enablePtrace", current.EnablePtrace, desired.EnablePtrace},
+		{"runpodCompat", current.RunPodCompat, desired.RunPodCompat},
+		{"minReplicas", current.MinReplicas, desired.MinReplicas},
+		{"maxReplicas", current.MaxReplicas, desired.MaxReplicas},
+		{"scaleUpThreshold", current.ScaleUpThreshold, desired.ScaleUpThreshold},
+		{"scaleDownIdleTime", current.ScaleDownIdleTime, desired.ScaleDownIdleTime},
+		{"scaleUpCooldown", current.ScaleUpCooldown, desired.ScaleUpCooldown},
+		{"scaleDownCooldown", current.ScaleDownCooldown, desired.ScaleDownCooldown},
+		{"priority", current.Priority, desired.Priority},
+		{"enableDynamicPrio", current.EnableDynamicPrio, desired.EnableDynamicPrio},
+		{"highLoadThreshold", current.HighLoadThreshold, desired.HighLoadThreshold},
+		{"priorityBoost", current.PriorityBoost, desired.PriorityBoost},
+		{"status", current.Status, desired.Status},
+	}
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.a, f.b) {
+			change.Fields = append(change.Fields, f.name)
+		}
+	}
+
+	change.Action = interfaces.ChangeActionUnchanged
+	if len(change.Fields) > 0 {
+		change.Action = interfaces.ChangeActionUpdate
+	}
+	return change
+}
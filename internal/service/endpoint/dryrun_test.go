@@ -0,0 +1,144 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
+)
+
+// mockDryRunProvider adds dry-run support to mockDeploymentProvider
+type mockDryRunProvider struct {
+	mockDeploymentProvider
+	result *interfaces.DryRunResult
+	err    error
+}
+
+func (m *mockDryRunProvider) DryRunDeploy(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DryRunResult, error) {
+	return m.result, m.err
+}
+
+func (m *mockDryRunProvider) DryRunUpdateDeployment(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DryRunResult, error) {
+	return m.result, m.err
+}
+
+func TestDeploymentManager_DryRunDeploy(t *testing.T) {
+	config.GlobalConfig = &config.Config{ImageValidation: config.ImageValidationConfig{Enabled: false}}
+	ctx := context.Background()
+
+	t.Run("provider dry run is merged", func(t *testing.T) {
+		provider := &mockDryRunProvider{result: &interfaces.DryRunResult{
+			Changes:  []interfaces.ResourceChange{{Kind: "Deployment", Name: "flux", Action: interfaces.ChangeActionCreate}},
+			Warnings: []string{"replicas not set"},
+			Manifest: "kind: Deployment",
+		}}
+		provider.deployFunc = func(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
+			t.Fatal("dry run must not deploy")
+			return nil, nil
+		}
+
+		plan, err := NewDeploymentManager(provider, nil, nil).DryRunDeploy(ctx, &interfaces.DeployRequest{Endpoint: "flux", Image: "nginx:latest"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !plan.Valid || !plan.ProviderDryRun || !plan.DryRun {
+			t.Errorf("expected a valid provider-checked plan, got %+v", plan)
+		}
+		if len(plan.Changes) != 1 || plan.Changes[0].Action != interfaces.ChangeActionCreate {
+			t.Errorf("unexpected changes: %+v", plan.Changes)
+		}
+		if plan.Manifest != "kind: Deployment" || len(plan.Warnings) != 1 {
+			t.Errorf("unexpected manifest/warnings: %q %v", plan.Manifest, plan.Warnings)
+		}
+	})
+
+	t.Run("all problems are reported together", func(t *testing.T) {
+		provider := &mockDryRunProvider{err: errors.New("exceeded quota: gpu")}
+
+		plan, err := NewDeploymentManager(provider, nil, nil).DryRunDeploy(ctx, &interfaces.DeployRequest{Endpoint: "flux", Image: "INVALID IMAGE"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if plan.Valid {
+			t.Error("expected an invalid plan")
+		}
+		if len(plan.Errors) != 2 {
+			t.Errorf("expected image and provider errors, got %v", plan.Errors)
+		}
+	})
+
+	t.Run("provider without dry-run support", func(t *testing.T) {
+		plan, err := NewDeploymentManager(&mockDeploymentProvider{}, nil, nil).DryRunDeploy(ctx, &interfaces.DeployRequest{Endpoint: "flux", Image: "nginx:latest"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !plan.Valid || plan.ProviderDryRun || len(plan.Warnings) != 1 {
+			t.Errorf("expected a valid plan with a warning, got %+v", plan)
+		}
+	})
+}
+
+func TestDeploymentManager_DryRunUpdateDiff(t *testing.T) {
+	provider := &mockDryRunProvider{result: &interfaces.DryRunResult{
+		Changes: []interfaces.ResourceChange{{Kind: "Deployment", Name: "flux", Action: interfaces.ChangeActionUpdate, Fields: []string{"image", "tolerations"}}},
+		Current: &interfaces.DeploymentView{
+			Image:       "flux:1",
+			Env:         map[string]string{"A": "1"},
+			Tolerations: map[string]string{"nvidia.com/gpu:NoSchedule": "Exists"},
+		},
+		Proposed: &interfaces.DeploymentView{
+			Image:       "flux:2",
+			Env:         map[string]string{"A": "1"},
+			Tolerations: map[string]string{"nvidia.com/gpu:NoSchedule": "Exists", "spot:NoSchedule": "Equal true"},
+		},
+	}}
+	provider.updateDeploymentFunc = func(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error) {
+		t.Fatal("dry run must not update")
+		return nil, nil
+	}
+
+	plan, err := NewDeploymentManager(provider, nil, nil).DryRunUpdate(context.Background(), &interfaces.UpdateDeploymentRequest{Endpoint: "flux"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Diff == nil || !plan.Diff.Changed {
+		t.Fatalf("expected a changed diff, got %+v", plan.Diff)
+	}
+	if len(plan.Diff.Image) != 1 || plan.Diff.Image[0].Current != "flux:1" || plan.Diff.Image[0].Proposed != "flux:2" {
+		t.Errorf("unexpected image diff: %+v", plan.Diff.Image)
+	}
+	if len(plan.Diff.Env) != 0 {
+		t.Errorf("unchanged env must not be reported: %+v", plan.Diff.Env)
+	}
+	if len(plan.Diff.Tolerations) != 1 || plan.Diff.Tolerations[0].Field != "spot:NoSchedule" || plan.Diff.Tolerations[0].Op != DiffOpAdded {
+		t.Errorf("unexpected toleration diff: %+v", plan.Diff.Tolerations)
+	}
+
+	// Providers that cannot describe deployments return no diff
+	provider.result = &interfaces.DryRunResult{}
+	plan, err = NewDeploymentManager(provider, nil, nil).DryRunUpdate(context.Background(), &interfaces.UpdateDeploymentRequest{Endpoint: "flux"})
+	if err != nil || plan.Diff != nil {
+		t.Errorf("expected no diff, got %+v (err %v)", plan.Diff, err)
+	}
+}
+
+func TestMetadataChange(t *testing.T) {
+	if change := metadataChange("flux", nil, &interfaces.EndpointMetadata{}); change.Action != interfaces.ChangeActionCreate {
+		t.Errorf("expected create, got %s", change.Action)
+	}
+
+	current := &interfaces.EndpointMetadata{Image: "a:1", Replicas: 1, Env: map[string]string{"A": "1"}, Status: "Running", ReadyReplicas: 1}
+	desired := *current
+	desired.ReadyReplicas = 0 // runtime state is ignored
+	if change := metadataChange("flux", current, &desired); change.Action != interfaces.ChangeActionUnchanged {
+		t.Errorf("expected unchanged, got %s %v", change.Action, change.Fields)
+	}
+
+	applyUpdateToMetadata(&desired, &interfaces.UpdateDeploymentRequest{Image: "a:2", Env: &map[string]string{"A": "2"}})
+	change := metadataChange("flux", current, &desired)
+	if change.Action != interfaces.ChangeActionUpdate || len(change.Fields) != 2 || change.Fields[0] != "image" || change.Fields[1] != "env" {
+		t.Errorf("unexpected change: %+v", change)
+	}
+}
@@ -0,0 +1,208 @@
+package endpoint
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// Enrichment defaults
+const (
+	DefaultEnrichmentConcurrency = 16
+	DefaultEnrichmentCallTimeout = 100 * time.Millisecond
+	DefaultEnrichmentBudget      = 250 * time.Millisecond
+)
+
+// defaultProviderConcurrency holds per-provider limits for providers with known rate profiles.
+// K8s status comes from the informer cache; Novita calls hit a rate-limited remote API.
+var defaultProviderConcurrency = map[string]int{
+	"k8s":        64,
+	"kubernetes": 64,
+	"novita":     8,
+}
+
+// EnrichmentOptions configures a RuntimeEnricher
+type EnrichmentOptions struct {
+	Provider    string        // Provider name, used to pick the default concurrency
+	Concurrency int           // Max in-flight status calls against the provider
+	CallTimeout time.Duration // Timeout of a single provider call
+	Budget      time.Duration // Timeout of one Enrich call
+}
+
+// EnrichmentResult summarizes one Enrich call
+type EnrichmentResult struct {
+	Enriched int           // Endpoints updated with live status
+	Missed   int           // Endpoints left with persisted status (error, timeout or unknown to provider)
+	Batched  bool          // Served by a single ListApps call
+	Duration time.Duration // Wall time spent
+}
+
+// RuntimeEnricher overlays live provider status onto endpoint metadata.
+// The concurrency limit is shared by all concurrent Enrich calls, so parallel list
+// requests cannot multiply the load on the provider.
+type RuntimeEnricher struct {
+	provider    interfaces.DeploymentProvider
+	slots       chan struct{}
+	callTimeout time.Duration
+	budget      time.Duration
+}
+
+// NewRuntimeEnricher creates an enricher for the given provider
+func NewRuntimeEnricher(provider interfaces.DeploymentProvider, opts EnrichmentOptions) *RuntimeEnricher {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultProviderConcurrency[strings.ToLower(opts.Provider)]
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultEnrichmentConcurrency
+	}
+	if opts.CallTimeout <= 0 {
+		opts.CallTimeout = DefaultEnrichmentCallTimeout
+	}
+	if opts.Budget <= 0 {
+		opts.Budget = DefaultEnrichmentBudget
+	}
+	return &RuntimeEnricher{
+		provider:    provider,
+		slots:       make(chan struct{}, concurrency),
+		callTimeout: opts.CallTimeout,
+		budget:      opts.Budget,
+	}
+}
+
+type statusResult struct {
+	index  int
+	status *interfaces.AppStatus
+}
+
+// Enrich updates status and replica counts of the given endpoints in place.
+// One ListApps call is tried first; if it fails, endpoints are looked up individually
+// in parallel. Endpoints not resolved within the budget keep their persisted state.
+func (e *RuntimeEnricher) Enrich(ctx context.Context, endpoints []*interfaces.EndpointMetadata) EnrichmentResult {
+	start := time.Now()
+	result := EnrichmentResult{}
+	if e == nil || e.provider == nil || len(endpoints) == 0 {
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.budget)
+	defer cancel()
+
+	if statuses, ok := e.listStatuses(ctx); ok {
+		result.Batched = true
+		for _, meta := range endpoints {
+			if status, found := statuses[meta.Name]; found {
+				applyAppStatus(meta, status)
+				result.Enriched++
+			} else {
+				result.Missed++
+			}
+		}
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// Buffered so late workers never block after the budget is spent
+	results := make(chan statusResult, len(endpoints))
+	for i, meta := range endpoints {
+		go e.lookup(ctx, i, meta.Name, results)
+	}
+
+	pending := len(endpoints)
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			if r.status != nil {
+				applyAppStatus(endpoints[r.index], r.status)
+				result.Enriched++
+			}
+		case <-ctx.Done():
+			logger.WarnCtx(ctx, "endpoint enrichment budget of %v exhausted, %d of %d endpoints use persisted status",
+				e.budget, pending, len(endpoints))
+			pending = 0
+		}
+	}
+	result.Missed = len(endpoints) - result.Enriched
+	result.Duration = time.Since(start)
+	return result
+}
+
+// listStatuses fetches all app statuses with one provider call
+func (e *RuntimeEnricher) listStatuses(ctx context.Context) (map[string]*interfaces.AppStatus, bool) {
+	if !e.acquire(ctx) {
+		return nil, false
+	}
+	defer e.release()
+
+	callCtx, cancel := context.WithTimeout(ctx, e.callTimeout)
+	defer cancel()
+
+	apps, err := e.provider.ListApps(callCtx)
+	if err != nil {
+		logger.WarnCtx(ctx, "batched endpoint status lookup failed, falling back to per-endpoint lookups: %v", err)
+		return nil, false
+	}
+
+	statuses := make(map[string]*interfaces.AppStatus, len(apps))
+	for _, app := range apps {
+		if app == nil {
+			continue
+		}
+		statuses[app.Name] = &interfaces.AppStatus{
+			Endpoint:          app.Name,
+			Status:            app.Status,
+			ReadyReplicas:     app.ReadyReplicas,
+			AvailableReplicas: app.AvailableReplicas,
+			TotalReplicas:     app.Replicas,
+		}
+	}
+	return statuses, true
+}
+
+// lookup fetches the status of one endpoint once a concurrency slot is free
+func (e *RuntimeEnricher) lookup(ctx context.Context, index int, name string, results chan<- statusResult) {
+	r := statusResult{index: index}
+	defer func() { results <- r }()
+
+	if !e.acquire(ctx) {
+		return
+	}
+	defer e.release()
+
+	callCtx, cancel := context.WithTimeout(ctx, e.callTimeout)
+	defer cancel()
+
+	status, err := e.provider.GetAppStatus(callCtx, name)
+	if err == nil {
+		r.status = status
+	}
+}
+
+func (e *RuntimeEnricher) acquire(ctx context.Context) bool {
+	select {
+	case e.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (e *RuntimeEnricher) release() {
+	<-e.slots
+}
+
+// applyAppStatus overlays live status; desired replicas stay as stored in metadata
+func applyAppStatus(meta *interfaces.EndpointMetadata, status *interfaces.AppStatus) {
+	if status == nil {
+		return
+	}
+	if status.Status != "" {
+		meta.Status = status.Status
+	}
+	meta.ReadyReplicas = int(status.ReadyReplicas)
+	meta.AvailableReplicas = int(status.AvailableReplicas)
+}
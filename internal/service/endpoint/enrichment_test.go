@@ -0,0 +1,119 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"waverless/pkg/interfaces"
+)
+
+func endpointList(n int) []*interfaces.EndpointMetadata {
+	endpoints := make([]*interfaces.EndpointMetadata, n)
+	for i := range endpoints {
+		endpoints[i] = &interfaces.EndpointMetadata{Name: fmt.Sprintf("ep-%d", i), Status: "Persisted"}
+	}
+	return endpoints
+}
+
+func TestRuntimeEnricher_Batched(t *testing.T) {
+	provider := &mockDeploymentProvider{
+		listAppsFunc: func(ctx context.Context) ([]*interfaces.AppInfo, error) {
+			return []*interfaces.AppInfo{{Name: "ep-0", Status: "Running", ReadyReplicas: 2, AvailableReplicas: 2}}, nil
+		},
+		getAppStatusFunc: func(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+			t.Fatalf("unexpected per-endpoint lookup for %s", endpoint)
+			return nil, nil
+		},
+	}
+	endpoints := endpointList(2)
+
+	result := NewRuntimeEnricher(provider, EnrichmentOptions{Provider: "k8s"}).Enrich(context.Background(), endpoints)
+
+	if !result.Batched || result.Enriched != 1 || result.Missed != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if endpoints[0].Status != "Running" || endpoints[0].ReadyReplicas != 2 {
+		t.Errorf("ep-0 not enriched: %+v", endpoints[0])
+	}
+	if endpoints[1].Status != "Persisted" {
+		t.Errorf("ep-1 should keep persisted status, got %s", endpoints[1].Status)
+	}
+}
+
+func TestRuntimeEnricher_ParallelFallbackRespectsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	provider := &mockDeploymentProvider{
+		listAppsFunc: func(ctx context.Context) ([]*interfaces.AppInfo, error) {
+			return nil, errors.New("list not supported")
+		},
+		getAppStatusFunc: func(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			return &interfaces.AppStatus{Endpoint: endpoint, Status: "Running", ReadyReplicas: 1}, nil
+		},
+	}
+	endpoints := endpointList(500)
+
+	enricher := NewRuntimeEnricher(provider, EnrichmentOptions{Concurrency: 50, Budget: 2 * time.Second})
+	result := enricher.Enrich(context.Background(), endpoints)
+
+	if result.Batched || result.Enriched != 500 || result.Missed != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 50 {
+		t.Errorf("concurrency limit exceeded: %d in flight", max)
+	}
+	// 500 calls of 2ms with 50 slots take ~20ms; serial lookups would take over a second
+	if result.Duration > 300*time.Millisecond {
+		t.Errorf("enrichment too slow: %v", result.Duration)
+	}
+}
+
+func TestRuntimeEnricher_BudgetExhausted(t *testing.T) {
+	provider := &mockDeploymentProvider{
+		listAppsFunc: func(ctx context.Context) ([]*interfaces.AppInfo, error) {
+			return nil, errors.New("unavailable")
+		},
+		getAppStatusFunc: func(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+			if endpoint == "ep-0" {
+				return &interfaces.AppStatus{Status: "Running"}, nil
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	endpoints := endpointList(3)
+
+	start := time.Now()
+	result := NewRuntimeEnricher(provider, EnrichmentOptions{CallTimeout: time.Second, Budget: 50 * time.Millisecond}).
+		Enrich(context.Background(), endpoints)
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("budget not enforced, took %v", elapsed)
+	}
+	if result.Enriched != 1 || result.Missed != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if endpoints[1].Status != "Persisted" || endpoints[2].Status != "Persisted" {
+		t.Errorf("timed out endpoints should keep persisted status")
+	}
+}
+
+func TestRuntimeEnricher_NilIsNoop(t *testing.T) {
+	var enricher *RuntimeEnricher
+	endpoints := endpointList(1)
+	if result := enricher.Enrich(context.Background(), endpoints); result.Enriched != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,311 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// ErrEnvVersionConflict is returned when an env change is based on an outdated env version
+var ErrEnvVersionConflict = errors.New("env version conflict")
+
+// envNamePattern matches the env var names the env API accepts
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvPrefixes mark env vars injected by waverless, which deployments keep on every update
+var reservedEnvPrefixes = []string{"RUNPOD_", "WAVERLESS_"}
+
+// EndpointEnv is the env of an endpoint. Secret values are never returned.
+type EndpointEnv struct {
+	Endpoint string            `json:"endpoint"`
+	Version  int               `json:"version"` // Version the next change must be based on
+	Env      map[string]string `json:"env"`     // Plain env vars
+	Secrets  []string          `json:"secrets"` // Names of secret env vars
+}
+
+// EnvPatch changes individual env vars of an endpoint
+type EnvPatch struct {
+	Version *int              `json:"version" binding:"required"` // Env version the change is based on
+	Set     map[string]string `json:"set,omitempty"`              // Plain env vars to add or change
+	Secrets map[string]string `json:"secrets,omitempty"`          // Secret env vars to add or change
+	Unset   []string          `json:"unset,omitempty"`            // Env vars to remove
+}
+
+// EnvChangeHook is called after an env change was saved and applied
+type EnvChangeHook func(ctx context.Context, change *model.EndpointEnvChange)
+
+// EnvManager applies versioned changes to individual env vars of endpoints. Secret env vars
+// are only stored in the provider's secret store; the database keeps their names.
+type EnvManager struct {
+	provider     interfaces.DeploymentProvider
+	endpointRepo *mysql.EndpointRepository
+	hook         EnvChangeHook
+}
+
+// NewEnvManager creates an env manager.
+func NewEnvManager(provider interfaces.DeploymentProvider, endpointRepo *mysql.EndpointRepository) *EnvManager {
+	return &EnvManager{provider: provider, endpointRepo: endpointRepo}
+}
+
+// SetHook sets the hook called after every env change.
+func (m *EnvManager) SetHook(hook EnvChangeHook) {
+	m.hook = hook
+}
+
+// Get returns the env of an endpoint and its version.
+func (m *EnvManager) Get(ctx context.Context, name string) (*EndpointEnv, error) {
+	ep, err := m.endpointRepo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if ep == nil {
+		return nil, fmt.Errorf("endpoint %s not found", name)
+	}
+	return toEndpointEnv(ep.Endpoint, ep.EnvVersion, mysql.JSONMapToStringMap(ep.Env), ep.SecretEnv), nil
+}
+
+// History returns the env changes of an endpoint, newest first.
+func (m *EnvManager) History(ctx context.Context, name string, limit int) ([]*model.EndpointEnvChange, error) {
+	return m.endpointRepo.ListEnvChanges(ctx, name, limit)
+}
+
+// Patch applies an env change based on patch.Version. It fails with ErrEnvVersionConflict
+// when the env changed since that version. A change without effect keeps the version.
+func (m *EnvManager) Patch(ctx context.Context, name string, patch *EnvPatch, changedBy string) (*EndpointEnv, *model.EndpointEnvChange, error) {
+	if err := validateEnvPatch(patch); err != nil {
+		return nil, nil, err
+	}
+	ep, err := m.endpointRepo.Get(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ep == nil {
+		return nil, nil, fmt.Errorf("endpoint %s not found", name)
+	}
+	if ep.EnvVersion != *patch.Version {
+		return nil, nil, fmt.Errorf("%w: endpoint %s is at env version %d, the change is based on %d", ErrEnvVersionConflict, name, ep.EnvVersion, *patch.Version)
+	}
+
+	plainBefore := mysql.JSONMapToStringMap(ep.Env)
+	secretBefore := make(map[string]bool, len(ep.SecretEnv))
+	for _, k := range ep.SecretEnv {
+		secretBefore[k] = true
+	}
+
+	plainAfter := make(map[string]string, len(plainBefore)+len(patch.Set))
+	for k, v := range plainBefore {
+		plainAfter[k] = v
+	}
+	secretAfter := make(map[string]bool, len(secretBefore)+len(patch.Secrets))
+	for k := range secretBefore {
+		secretAfter[k] = true
+	}
+	for k, v := range patch.Set {
+		plainAfter[k] = v
+		delete(secretAfter, k)
+	}
+	for k := range patch.Secrets {
+		secretAfter[k] = true
+		delete(plainAfter, k)
+	}
+	for _, k := range patch.Unset {
+		delete(plainAfter, k)
+		delete(secretAfter, k)
+	}
+
+	changes := envChanges(plainBefore, plainAfter, secretBefore, secretAfter, patch.Secrets)
+	if len(changes) == 0 {
+		return toEndpointEnv(name, ep.EnvVersion, plainBefore, ep.SecretEnv), nil, nil
+	}
+
+	var secretUnset []string
+	for k := range secretBefore {
+		if !secretAfter[k] {
+			secretUnset = append(secretUnset, k)
+		}
+	}
+	sort.Strings(secretUnset)
+	syncer, canSync := interfaces.ResolveProvider(ctx, m.provider, name, "").(interfaces.SecretEnvSyncer)
+	if (len(patch.Secrets) > 0 || len(secretUnset) > 0) && !canSync {
+		return nil, nil, fmt.Errorf("invalid request: the deployment provider does not support secret env vars")
+	}
+
+	secrets := sortedSet(secretAfter)
+	change := &model.EndpointEnvChange{Source: model.EnvChangeSourceAPI, Changes: changes, ChangedBy: changedBy}
+	ok, err := m.endpointRepo.UpdateEnv(ctx, name, ep.EnvVersion, mysql.StringMapToJSONMap(plainAfter), mysql.JSONStringArray(secrets), change)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: env of endpoint %s was changed concurrently", ErrEnvVersionConflict, name)
+	}
+	logger.InfoCtx(ctx, "[AUDIT] endpoint env changed: endpoint=%s, version=%d, changes=%d, changedBy=%q",
+		name, change.Version, len(changes), changedBy)
+
+	// Secret references first: a var moving between plain and secret is never defined twice
+	if len(patch.Secrets) > 0 || len(secretUnset) > 0 {
+		if err := syncer.SyncSecretEnv(ctx, name, patch.Secrets, secretUnset); err != nil {
+			return nil, nil, fmt.Errorf("env version %d saved but secret env vars were not applied: %w", change.Version, err)
+		}
+	}
+	if !equalStringMaps(plainBefore, plainAfter) {
+		if _, err := m.provider.UpdateDeployment(ctx, &interfaces.UpdateDeploymentRequest{Endpoint: name, Env: &plainAfter}); err != nil {
+			return nil, nil, fmt.Errorf("env version %d saved but not applied to the deployment: %w", change.Version, err)
+		}
+	}
+
+	if m.hook != nil {
+		m.hook(ctx, change)
+	}
+	return toEndpointEnv(name, change.Version, plainAfter, secrets), change, nil
+}
+
+// recordReplace records an env replaced by a deploy or deployment update as the next env
+// version. Failures are logged; the deployment already succeeded.
+func (m *EnvManager) recordReplace(ctx context.Context, name string, before, after map[string]string) {
+	changes := envChanges(before, after, nil, nil, nil)
+	if len(changes) == 0 {
+		return
+	}
+	change := &model.EndpointEnvChange{Source: model.EnvChangeSourceDeploy, Changes: changes}
+	if err := m.endpointRepo.BumpEnvVersion(ctx, name, change); err != nil {
+		logger.WarnCtx(ctx, "Failed to record env change of endpoint %s: %v", name, err)
+		return
+	}
+	if m.hook != nil {
+		m.hook(ctx, change)
+	}
+}
+
+// plainEnv returns the stored plain env of an endpoint, ok=false when it does not exist
+func (m *EnvManager) plainEnv(ctx context.Context, name string) (map[string]string, bool) {
+	ep, err := m.endpointRepo.Get(ctx, name)
+	if err != nil || ep == nil {
+		return nil, false
+	}
+	return mysql.JSONMapToStringMap(ep.Env), true
+}
+
+func validateEnvPatch(patch *EnvPatch) error {
+	if patch == nil || patch.Version == nil {
+		return fmt.Errorf("invalid request: version is required")
+	}
+	if len(patch.Set)+len(patch.Secrets)+len(patch.Unset) == 0 {
+		return fmt.Errorf("invalid request: set, secrets or unset is required")
+	}
+	names := make([]string, 0, len(patch.Set)+len(patch.Secrets)+len(patch.Unset))
+	for k := range patch.Set {
+		names = append(names, k)
+	}
+	for k := range patch.Secrets {
+		names = append(names, k)
+	}
+	names = append(names, patch.Unset...)
+
+	seen := make(map[string]bool, len(names))
+	for _, k := range names {
+		if !envNamePattern.MatchString(k) {
+			return fmt.Errorf("invalid request: invalid env var name %q", k)
+		}
+		for _, prefix := range reservedEnvPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				return fmt.Errorf("invalid request: env var %s uses the reserved prefix %s", k, prefix)
+			}
+		}
+		if seen[k] {
+			return fmt.Errorf("invalid request: env var %s is changed more than once", k)
+		}
+		seen[k] = true
+	}
+	return nil
+}
+
+// envChanges lists the env vars that differ, in name order. Values of vars that are secret
+// before or after are left out; a secret is changed whenever it is in secretSet.
+func envChanges(plainBefore, plainAfter map[string]string, secretBefore, secretAfter map[string]bool, secretSet map[string]string) model.EnvVarChanges {
+	names := make(map[string]bool)
+	for k := range plainBefore {
+		names[k] = true
+	}
+	for k := range plainAfter {
+		names[k] = true
+	}
+	for k := range secretBefore {
+		names[k] = true
+	}
+	for k := range secretAfter {
+		names[k] = true
+	}
+
+	changes := model.EnvVarChanges{}
+	for _, k := range sortedSet(names) {
+		oldValue, oldPlain := plainBefore[k]
+		newValue, newPlain := plainAfter[k]
+		oldSecret, newSecret := secretBefore[k], secretAfter[k]
+		secret := oldSecret || newSecret
+		existed, exists := oldPlain || oldSecret, newPlain || newSecret
+
+		change := model.EnvVarChange{Name: k, Secret: secret}
+		switch {
+		case !existed && exists:
+			change.Op = DiffOpAdded
+		case existed && !exists:
+			change.Op = DiffOpRemoved
+		case oldSecret != newSecret:
+			change.Op = DiffOpChanged
+		case secret:
+			if _, ok := secretSet[k]; !ok {
+				continue
+			}
+			change.Op = DiffOpChanged
+		case oldValue != newValue:
+			change.Op = DiffOpChanged
+		default:
+			continue
+		}
+		if !secret {
+			change.Old, change.New = oldValue, newValue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func toEndpointEnv(name string, version int, env map[string]string, secrets []string) *EndpointEnv {
+	if env == nil {
+		env = map[string]string{}
+	}
+	if secrets == nil {
+		secrets = []string{}
+	}
+	return &EndpointEnv{Endpoint: name, Version: version, Env: env, Secrets: secrets}
+}
+
+func sortedSet(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
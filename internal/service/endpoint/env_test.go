@@ -0,0 +1,61 @@
+package endpoint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+func TestEnvChanges(t *testing.T) {
+	plainBefore := map[string]string{"MODEL": "dev", "DEBUG": "1", "HF_TOKEN": "plain", "KEEP": "x"}
+	plainAfter := map[string]string{"MODEL": "schnell", "KEEP": "x", "STEPS": "20"}
+	secretBefore := map[string]bool{"API_KEY": true, "DB_PASSWORD": true}
+	secretAfter := map[string]bool{"API_KEY": true, "DB_PASSWORD": true, "HF_TOKEN": true}
+	secretSet := map[string]string{"HF_TOKEN": "hf_123", "API_KEY": "new"}
+
+	got := envChanges(plainBefore, plainAfter, secretBefore, secretAfter, secretSet)
+	want := model.EnvVarChanges{
+		{Name: "API_KEY", Op: DiffOpChanged, Secret: true},
+		{Name: "DEBUG", Op: DiffOpRemoved, Old: "1"},
+		{Name: "HF_TOKEN", Op: DiffOpChanged, Secret: true},
+		{Name: "MODEL", Op: DiffOpChanged, Old: "dev", New: "schnell"},
+		{Name: "STEPS", Op: DiffOpAdded, New: "20"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("envChanges() = %+v, want %+v", got, want)
+	}
+	for _, c := range got {
+		if strings.Contains(c.Old+c.New, "hf_123") || strings.Contains(c.Old+c.New, "plain") {
+			t.Fatalf("secret value leaked into change %+v", c)
+		}
+	}
+
+	if got := envChanges(plainBefore, plainBefore, secretBefore, secretBefore, nil); len(got) != 0 {
+		t.Fatalf("expected no changes, got %+v", got)
+	}
+}
+
+func TestValidateEnvPatch(t *testing.T) {
+	version := 3
+	valid := &EnvPatch{Version: &version, Set: map[string]string{"MODEL": "dev"}, Secrets: map[string]string{"HF_TOKEN": "x"}, Unset: []string{"OLD"}}
+	if err := validateEnvPatch(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := map[string]*EnvPatch{
+		"missing version":  {Set: map[string]string{"A": "1"}},
+		"no changes":       {Version: &version},
+		"invalid name":     {Version: &version, Set: map[string]string{"1A": "1"}},
+		"reserved prefix":  {Version: &version, Set: map[string]string{"WAVERLESS_ENDPOINT_ID": "x"}},
+		"set and unset":    {Version: &version, Set: map[string]string{"A": "1"}, Unset: []string{"A"}},
+		"set plain secret": {Version: &version, Set: map[string]string{"A": "1"}, Secrets: map[string]string{"A": "2"}},
+	}
+	for name, patch := range tests {
+		err := validateEnvPatch(patch)
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid request") {
+			t.Errorf("%s: expected invalid request error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrHookBlocked is returned when a lifecycle hook with the block failure policy failed
+var ErrHookBlocked = errors.New("blocked by lifecycle hook")
+
+// LifecycleHooks runs the hooks of an endpoint at a lifecycle point (see pkg/hooks).
+// RunHooks returns an error wrapping ErrHookBlocked when a blocking hook failed.
+type LifecycleHooks interface {
+	RunHooks(ctx context.Context, endpoint, phase, operation string) error
+	DeleteHooks(ctx context.Context, endpoint string) error
+}
+
+// SetLifecycleHooks sets the hooks run around deploys, deployment updates and deletes (for dependency injection)
+func (s *Service) SetLifecycleHooks(hooks LifecycleHooks) {
+	s.hooks = hooks
+}
+
+// runHooks runs the hooks of an endpoint for a phase, if any
+func (s *Service) runHooks(ctx context.Context, endpoint, phase, operation string) error {
+	if s.hooks == nil || endpoint == "" {
+		return nil
+	}
+	return s.hooks.RunHooks(ctx, endpoint, phase, operation)
+}
@@ -0,0 +1,157 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"waverless/pkg/config"
+	"waverless/pkg/hooks"
+	"waverless/pkg/interfaces"
+)
+
+// fakeLifecycleHooks records hook runs and fails the phases listed in failPhases
+type fakeLifecycleHooks struct {
+	failPhases map[string]bool
+	calls      []string
+	deleted    []string
+}
+
+func (f *fakeLifecycleHooks) RunHooks(ctx context.Context, endpoint, phase, operation string) error {
+	f.calls = append(f.calls, phase+"/"+operation)
+	if f.failPhases[phase] {
+		return fmt.Errorf("%w: %s hook check failed", ErrHookBlocked, phase)
+	}
+	return nil
+}
+
+func (f *fakeLifecycleHooks) DeleteHooks(ctx context.Context, endpoint string) error {
+	f.deleted = append(f.deleted, endpoint)
+	return nil
+}
+
+func newHookTestService(provider *mockDeploymentProvider, lifecycle *fakeLifecycleHooks) *Service {
+	config.GlobalConfig = &config.Config{
+		ImageValidation: config.ImageValidationConfig{
+			Enabled: false,
+		},
+	}
+	s := &Service{deployment: NewDeploymentManager(provider, nil, nil)}
+	s.SetLifecycleHooks(lifecycle)
+	return s
+}
+
+func TestService_Deploy_RunsHooks(t *testing.T) {
+	deployed := false
+	provider := &mockDeploymentProvider{
+		deployFunc: func(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
+			deployed = true
+			return &interfaces.DeployResponse{Endpoint: req.Endpoint}, nil
+		},
+	}
+	lifecycle := &fakeLifecycleHooks{}
+	s := newHookTestService(provider, lifecycle)
+
+	if _, err := s.Deploy(context.Background(), &interfaces.DeployRequest{Endpoint: "demo", Image: "nginx"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deployed {
+		t.Error("expected the provider to deploy")
+	}
+	want := []string{"pre_deploy/deploy", "post_deploy/deploy"}
+	if !reflect.DeepEqual(lifecycle.calls, want) {
+		t.Errorf("hook calls = %v, want %v", lifecycle.calls, want)
+	}
+}
+
+func TestService_Deploy_BlockedByPreDeployHook(t *testing.T) {
+	deployed := false
+	provider := &mockDeploymentProvider{
+		deployFunc: func(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
+			deployed = true
+			return &interfaces.DeployResponse{Endpoint: req.Endpoint}, nil
+		},
+	}
+	lifecycle := &fakeLifecycleHooks{failPhases: map[string]bool{hooks.PhasePreDeploy: true}}
+	s := newHookTestService(provider, lifecycle)
+
+	_, err := s.Deploy(context.Background(), &interfaces.DeployRequest{Endpoint: "demo", Image: "nginx"}, nil)
+	if !errors.Is(err, ErrHookBlocked) {
+		t.Fatalf("expected ErrHookBlocked, got: %v", err)
+	}
+	if deployed {
+		t.Error("a blocked pre-deploy hook must not deploy")
+	}
+}
+
+func TestService_Deploy_PostDeployHookFailure(t *testing.T) {
+	provider := &mockDeploymentProvider{
+		deployFunc: func(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
+			return &interfaces.DeployResponse{Endpoint: req.Endpoint}, nil
+		},
+	}
+	lifecycle := &fakeLifecycleHooks{failPhases: map[string]bool{hooks.PhasePostDeploy: true}}
+	s := newHookTestService(provider, lifecycle)
+
+	resp, err := s.Deploy(context.Background(), &interfaces.DeployRequest{Endpoint: "demo", Image: "nginx"}, nil)
+	if !errors.Is(err, ErrHookBlocked) {
+		t.Fatalf("expected ErrHookBlocked, got: %v", err)
+	}
+	if resp == nil {
+		t.Error("expected the deploy response of the completed deploy")
+	}
+}
+
+func TestService_UpdateDeployment_ReplicasOnlySkipsHooks(t *testing.T) {
+	provider := &mockDeploymentProvider{
+		updateDeploymentFunc: func(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error) {
+			return &interfaces.DeployResponse{Endpoint: req.Endpoint}, nil
+		},
+	}
+	lifecycle := &fakeLifecycleHooks{failPhases: map[string]bool{hooks.PhasePreDeploy: true}}
+	s := newHookTestService(provider, lifecycle)
+
+	replicas := 3
+	if _, err := s.UpdateDeployment(context.Background(), &interfaces.UpdateDeploymentRequest{Endpoint: "demo", Replicas: &replicas}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lifecycle.calls) != 0 {
+		t.Errorf("scaling must not run hooks, got %v", lifecycle.calls)
+	}
+
+	if _, err := s.UpdateDeployment(context.Background(), &interfaces.UpdateDeploymentRequest{Endpoint: "demo", Image: "nginx:2"}); !errors.Is(err, ErrHookBlocked) {
+		t.Errorf("expected ErrHookBlocked for an image update, got: %v", err)
+	}
+}
+
+func TestService_DeleteDeployment_RunsPreDeleteHooks(t *testing.T) {
+	var deleted []string
+	provider := &mockDeploymentProvider{
+		deleteAppFunc: func(ctx context.Context, name string) error {
+			deleted = append(deleted, name)
+			return nil
+		},
+	}
+	lifecycle := &fakeLifecycleHooks{}
+	s := newHookTestService(provider, lifecycle)
+
+	if err := s.DeleteDeployment(context.Background(), "demo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(lifecycle.calls, []string{"pre_delete/delete"}) {
+		t.Errorf("hook calls = %v", lifecycle.calls)
+	}
+	if !reflect.DeepEqual(deleted, []string{"demo"}) || !reflect.DeepEqual(lifecycle.deleted, []string{"demo"}) {
+		t.Errorf("expected the endpoint and its hooks to be deleted, got %v / %v", deleted, lifecycle.deleted)
+	}
+
+	lifecycle.failPhases = map[string]bool{hooks.PhasePreDelete: true}
+	if err := s.DeleteDeployment(context.Background(), "other"); !errors.Is(err, ErrHookBlocked) {
+		t.Fatalf("expected ErrHookBlocked, got: %v", err)
+	}
+	if len(deleted) != 1 {
+		t.Error("a blocked pre-delete hook must not delete the endpoint")
+	}
+}
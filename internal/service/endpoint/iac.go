@@ -0,0 +1,54 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"waverless/pkg/iac"
+)
+
+// GetResource returns the infrastructure-as-code representation of an endpoint, nil if it does not exist
+func (s *Service) GetResource(ctx context.Context, name string) (*iac.EndpointResource, error) {
+	meta, err := s.GetEndpoint(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil || meta.Status == "deleted" {
+		return nil, nil
+	}
+	return iac.FromMetadata(meta), nil
+}
+
+// ExportResources returns the infrastructure-as-code representation of the named endpoints
+// (all when names is empty), sorted by name
+func (s *Service) ExportResources(ctx context.Context, names []string) ([]*iac.EndpointResource, error) {
+	if s.metadata == nil {
+		return nil, fmt.Errorf("metadata manager not configured")
+	}
+
+	var resources []*iac.EndpointResource
+	if len(names) == 0 {
+		endpoints, err := s.metadata.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, meta := range endpoints {
+			resources = append(resources, iac.FromMetadata(meta))
+		}
+	} else {
+		for _, name := range names {
+			resource, err := s.GetResource(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			if resource == nil {
+				return nil, fmt.Errorf("endpoint %s not found", name)
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Name < resources[j].Name })
+	return resources, nil
+}
@@ -13,6 +13,8 @@ type endpointRepository interface {
 	Update(ctx context.Context, endpoint *mysql.Endpoint) error
 	Delete(ctx context.Context, name string) error
 	List(ctx context.Context) ([]*mysql.Endpoint, error)
+	ListPage(ctx context.Context, limit, offset int) ([]*mysql.Endpoint, error)
+	Count(ctx context.Context) (int64, error)
 }
 
 type autoscalerConfigRepository interface {
@@ -64,6 +64,11 @@ func (m *MetadataManager) Save(ctx context.Context, endpoint *interfaces.Endpoin
 		}
 	} else {
 		// Update existing record - only modify fields from mysqlEndpoint, preserve others
+		if existing.Provider == "" || existing.Status == "deleted" {
+			// Endpoints are pinned once; rows created before providers were recorded get pinned
+			// now and a deleted endpoint may come back on another provider
+			existing.Provider = mysqlEndpoint.Provider
+		}
 		existing.SpecName = mysqlEndpoint.SpecName
 		existing.Description = mysqlEndpoint.Description
 		existing.Image = mysqlEndpoint.Image
@@ -71,10 +76,15 @@ func (m *MetadataManager) Save(ctx context.Context, endpoint *interfaces.Endpoin
 		existing.ImageDigest = mysqlEndpoint.ImageDigest
 		existing.ImageLastChecked = mysqlEndpoint.ImageLastChecked
 		existing.LatestImage = mysqlEndpoint.LatestImage
+		existing.ModelRegistry = mysqlEndpoint.ModelRegistry
+		existing.ModelName = mysqlEndpoint.ModelName
+		existing.ModelVersion = mysqlEndpoint.ModelVersion
+		existing.ModelURL = mysqlEndpoint.ModelURL
 		existing.Replicas = mysqlEndpoint.Replicas
 		existing.GpuCount = mysqlEndpoint.GpuCount
 		existing.TaskTimeout = mysqlEndpoint.TaskTimeout
 		existing.EnablePtrace = mysqlEndpoint.EnablePtrace
+		existing.RunPodCompatOff = mysqlEndpoint.RunPodCompatOff
 		existing.Env = mysqlEndpoint.Env
 		existing.Labels = mysqlEndpoint.Labels
 		existing.Status = mysqlEndpoint.Status
@@ -153,7 +163,28 @@ func (m *MetadataManager) List(ctx context.Context) ([]*interfaces.EndpointMetad
 	if err != nil {
 		return nil, err
 	}
+	return m.withAutoscalerConfigs(ctx, mysqlEndpoints), nil
+}
+
+// ListPage returns a page of the stored endpoints, ordered by name, and their total.
+func (m *MetadataManager) ListPage(ctx context.Context, limit, offset int) ([]*interfaces.EndpointMetadata, int64, error) {
+	if m.endpointRepo == nil {
+		return nil, 0, fmt.Errorf("endpoint repository not configured")
+	}
 
+	total, err := m.endpointRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	mysqlEndpoints, err := m.endpointRepo.ListPage(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return m.withAutoscalerConfigs(ctx, mysqlEndpoints), total, nil
+}
+
+// withAutoscalerConfigs converts stored endpoints to metadata, merging their autoscaler configs.
+func (m *MetadataManager) withAutoscalerConfigs(ctx context.Context, mysqlEndpoints []*mysql.Endpoint) []*interfaces.EndpointMetadata {
 	// Batch load autoscaler configs for these endpoints only
 	configMap := make(map[string]*mysql.AutoscalerConfig)
 	if m.autoscalerConfigRepo != nil && len(mysqlEndpoints) > 0 {
@@ -178,7 +209,7 @@ func (m *MetadataManager) List(ctx context.Context) ([]*interfaces.EndpointMetad
 		results = append(results, meta)
 	}
 
-	return results, nil
+	return results
 }
 
 // Delete performs a soft delete on the endpoint metadata.
@@ -219,6 +250,17 @@ func (m *MetadataManager) saveAutoscalerConfig(ctx context.Context, meta *interf
 		PriorityBoost:     meta.PriorityBoost,                        // 0 is valid (no boost)
 		Enabled:           true,
 		AutoscalerEnabled: meta.AutoscalerEnabled,
+
+		// Custom metric scaling (target 0 = disabled)
+		CustomMetricName:   meta.CustomMetricName,
+		CustomMetricTarget: meta.CustomMetricTarget,
+
+		GroupName:          meta.GroupName,
+		EvaluationInterval: meta.EvaluationInterval, // 0 = global interval
+		QueueWaitSLO:       meta.QueueWaitSLO,       // 0 = none
+		MetricSources:      mysql.FromMetricSourcesDomain(meta.MetricSources),
+		ReplicaSchedules:   mysql.FromReplicaSchedulesDomain(meta.ReplicaSchedules),
+		Profile:            meta.Profile,
 	}
 
 	// CRITICAL: Copy time tracking fields (for autoscaler decisions)
@@ -240,8 +282,9 @@ func (m *MetadataManager) saveAutoscalerConfig(ctx context.Context, meta *interf
 }
 
 func toMySQLEndpoint(endpoint *interfaces.EndpointMetadata) *mysql.Endpoint {
-	return &mysql.Endpoint{
+	mysqlEndpoint := &mysql.Endpoint{
 		Endpoint:         endpoint.Name,
+		Provider:         endpoint.Provider,
 		SpecName:         endpoint.SpecName,
 		Description:      endpoint.Description,
 		Image:            endpoint.Image,
@@ -253,17 +296,26 @@ func toMySQLEndpoint(endpoint *interfaces.EndpointMetadata) *mysql.Endpoint {
 		GpuCount:         endpoint.GpuCount,
 		TaskTimeout:      endpoint.TaskTimeout,
 		EnablePtrace:     endpoint.EnablePtrace,
+		RunPodCompatOff:  !endpoint.RunPodCompat,
 		Env:              mysql.StringMapToJSONMap(endpoint.Env),
 		Labels:           mysql.StringMapToJSONMap(endpoint.Labels),
 		Status:           endpoint.Status,
 		CreatedAt:        endpoint.CreatedAt,
 		UpdatedAt:        endpoint.UpdatedAt,
 	}
+	if endpoint.Model != nil {
+		mysqlEndpoint.ModelRegistry = endpoint.Model.Registry
+		mysqlEndpoint.ModelName = endpoint.Model.Name
+		mysqlEndpoint.ModelVersion = endpoint.Model.Version
+		mysqlEndpoint.ModelURL = endpoint.Model.URL
+	}
+	return mysqlEndpoint
 }
 
 func fromMySQLEndpoint(endpoint *mysql.Endpoint) *interfaces.EndpointMetadata {
 	meta := &interfaces.EndpointMetadata{
 		Name:              endpoint.Endpoint,
+		Provider:          endpoint.Provider,
 		SpecName:          endpoint.SpecName,
 		Description:       endpoint.Description,
 		Image:             endpoint.Image,
@@ -275,15 +327,35 @@ func fromMySQLEndpoint(endpoint *mysql.Endpoint) *interfaces.EndpointMetadata {
 		GpuCount:          endpoint.GpuCount,
 		TaskTimeout:       endpoint.TaskTimeout,
 		EnablePtrace:      endpoint.EnablePtrace,
+		RunPodCompat:      !endpoint.RunPodCompatOff,
 		MaxPendingTasks:   endpoint.MaxPendingTasks,
 		Env:               mysql.JSONMapToStringMap(endpoint.Env),
+		EnvVersion:        endpoint.EnvVersion,
+		SecretEnv:         endpoint.SecretEnv,
+		DispatchPaused:    endpoint.DispatchPaused,
+		PauseMode:         endpoint.PauseMode,
+		PauseReason:       endpoint.PauseReason,
+		PausedBy:          endpoint.PausedBy,
+		PausedAt:          endpoint.PausedAt,
+		CircuitState:      endpoint.CircuitState,
+		CircuitReason:     endpoint.CircuitReason,
+		CircuitSince:      endpoint.CircuitSince,
 		Labels:            mysql.JSONMapToStringMap(endpoint.Labels),
 		Status:            endpoint.Status,
 		HealthStatus:      endpoint.HealthStatus,
+		HealthReason:      endpoint.HealthReason,
 		LastHealthCheckAt: endpoint.LastHealthCheckAt,
 		CreatedAt:         endpoint.CreatedAt,
 		UpdatedAt:         endpoint.UpdatedAt,
 	}
+	if endpoint.ModelName != "" {
+		meta.Model = &interfaces.ModelLineage{
+			Registry: endpoint.ModelRegistry,
+			Name:     endpoint.ModelName,
+			Version:  endpoint.ModelVersion,
+			URL:      endpoint.ModelURL,
+		}
+	}
 	// Set health message if present
 	if endpoint.HealthMessage != nil {
 		meta.HealthMessage = *endpoint.HealthMessage
@@ -302,6 +374,17 @@ func fromMySQLEndpoint(endpoint *mysql.Endpoint) *interfaces.EndpointMetadata {
 		if shm, ok := endpoint.RuntimeState["shmSize"].(string); ok {
 			meta.ShmSize = shm
 		}
+		if storage, ok := endpoint.RuntimeState["ephemeralStorage"].(string); ok {
+			meta.EphemeralStorage = storage
+		}
+		if so, ok := endpoint.RuntimeState["specOverride"].(map[string]interface{}); ok {
+			override := &interfaces.SpecOverride{}
+			override.ExtraCPU, _ = so["extraCpu"].(string)
+			override.ExtraMemory, _ = so["extraMemory"].(string)
+			if !override.IsZero() {
+				meta.SpecOverride = override
+			}
+		}
 		if vm, ok := endpoint.RuntimeState["volumeMounts"].([]interface{}); ok {
 			for _, v := range vm {
 				if m, ok := v.(map[string]interface{}); ok {
@@ -335,6 +418,14 @@ func mergeAutoscalerConfig(meta *interfaces.EndpointMetadata, cfg *mysql.Autosca
 	meta.PriorityBoost = cfg.PriorityBoost
 	meta.EnableDynamicPrio = &cfg.EnableDynamicPrio
 	meta.AutoscalerEnabled = cfg.AutoscalerEnabled
+	meta.CustomMetricName = cfg.CustomMetricName
+	meta.CustomMetricTarget = cfg.CustomMetricTarget
+	meta.GroupName = cfg.GroupName
+	meta.EvaluationInterval = cfg.EvaluationInterval
+	meta.QueueWaitSLO = cfg.QueueWaitSLO
+	meta.MetricSources = mysql.ToMetricSourcesDomain(cfg.MetricSources)
+	meta.ReplicaSchedules = mysql.ToReplicaSchedulesDomain(cfg.ReplicaSchedules)
+	meta.Profile = cfg.Profile
 
 	// CRITICAL: Copy time tracking fields (for autoscaler decisions)
 	if cfg.LastTaskTime != nil {
@@ -0,0 +1,98 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/modelregistry"
+)
+
+// ErrModelRegistryNotConfigured is returned when an endpoint is deployed by model but no model
+// registry is configured
+var ErrModelRegistryNotConfigured = errors.New("model registry not configured")
+
+// ErrNoModelLineage is returned when an endpoint that was deployed by image is moved to a model version
+var ErrNoModelLineage = errors.New("endpoint was not deployed from the model registry")
+
+// SetModelRegistry lets endpoints be deployed by model name and version (for dependency injection)
+func (s *Service) SetModelRegistry(registry modelregistry.Registry) {
+	s.models = registry
+}
+
+// ResolveModel returns a version of a model from the model registry, or its latest approved
+// version when version is empty or "latest".
+func (s *Service) ResolveModel(ctx context.Context, model, version string) (*modelregistry.ModelVersion, error) {
+	if s.models == nil {
+		return nil, ErrModelRegistryNotConfigured
+	}
+	return modelregistry.Resolve(ctx, s.models, model, version)
+}
+
+// ListModelVersions returns up to limit versions of a model from the model registry, newest
+// first, skipping the first offset.
+func (s *Service) ListModelVersions(ctx context.Context, model string, limit, offset int) ([]*modelregistry.ModelVersion, error) {
+	if s.models == nil {
+		return nil, ErrModelRegistryNotConfigured
+	}
+	return modelregistry.ListVersions(ctx, s.models, model, limit, offset)
+}
+
+// ModelVersionUpdate builds the update moving an endpoint deployed from the model registry to
+// another version of its model, the latest approved one when version is empty or "latest".
+// The env of the version is set over the current env. The request is nil when the endpoint
+// already runs that version.
+func (s *Service) ModelVersionUpdate(ctx context.Context, name, version string) (*interfaces.UpdateDeploymentRequest, *modelregistry.ModelVersion, error) {
+	meta, err := s.GetEndpoint(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if meta == nil {
+		return nil, nil, fmt.Errorf("endpoint %s not found", name)
+	}
+	if meta.Model == nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNoModelLineage, name)
+	}
+
+	mv, err := s.ResolveModel(ctx, meta.Model.Name, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mv.Version == meta.Model.Version && mv.Image == meta.Image {
+		return nil, mv, nil
+	}
+
+	req := &interfaces.UpdateDeploymentRequest{
+		Endpoint: name,
+		Image:    mv.Image,
+		Model:    ModelLineage(mv),
+	}
+	if len(mv.Env) > 0 {
+		env := MergeEnv(meta.Env, mv.Env)
+		req.Env = &env
+	}
+	return req, mv, nil
+}
+
+// ModelLineage returns the lineage recorded on an endpoint deployed from a model version
+func ModelLineage(mv *modelregistry.ModelVersion) *interfaces.ModelLineage {
+	return &interfaces.ModelLineage{
+		Registry: mv.Registry,
+		Name:     mv.Model,
+		Version:  mv.Version,
+		URL:      mv.URL,
+	}
+}
+
+// MergeEnv returns base with overlay set over it; base is not modified
+func MergeEnv(base, overlay map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,55 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"waverless/pkg/interfaces"
+)
+
+func TestApplyUpdateToMetadata_ModelLineage(t *testing.T) {
+	lineage := &interfaces.ModelLineage{Registry: "mlflow", Name: "flux", Version: "3"}
+	meta := &interfaces.EndpointMetadata{Image: "repo/flux:v2", Model: &interfaces.ModelLineage{Registry: "mlflow", Name: "flux", Version: "2"}}
+
+	// Updates that keep the image keep the lineage
+	replicas := 2
+	applyUpdateToMetadata(meta, &interfaces.UpdateDeploymentRequest{Replicas: &replicas})
+	if meta.Model == nil || meta.Model.Version != "2" {
+		t.Fatalf("expected lineage to be kept, got %+v", meta.Model)
+	}
+
+	applyUpdateToMetadata(meta, &interfaces.UpdateDeploymentRequest{Image: "repo/flux:v3", Model: lineage})
+	if meta.Model != lineage {
+		t.Fatalf("expected lineage of the new version, got %+v", meta.Model)
+	}
+
+	// An image set by hand drops the lineage
+	applyUpdateToMetadata(meta, &interfaces.UpdateDeploymentRequest{Image: "repo/flux:hotfix"})
+	if meta.Model != nil {
+		t.Fatalf("expected lineage to be cleared, got %+v", meta.Model)
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	base := map[string]string{"A": "1", "B": "2"}
+	merged := MergeEnv(base, map[string]string{"B": "3", "C": "4"})
+
+	if want := map[string]string{"A": "1", "B": "3", "C": "4"}; !reflect.DeepEqual(merged, want) {
+		t.Errorf("expected %v, got %v", want, merged)
+	}
+	if base["B"] != "2" {
+		t.Error("expected base to be left unmodified")
+	}
+	if got := MergeEnv(nil, nil); got == nil || len(got) != 0 {
+		t.Errorf("expected empty map, got %v", got)
+	}
+}
+
+func TestResolveModel_NotConfigured(t *testing.T) {
+	s := &Service{}
+	if _, err := s.ResolveModel(context.Background(), "flux", "latest"); !errors.Is(err, ErrModelRegistryNotConfigured) {
+		t.Errorf("expected ErrModelRegistryNotConfigured, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package endpoint
+
+import (
+	"strings"
+	"testing"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+func TestValidatePauseRequest(t *testing.T) {
+	tests := []struct {
+		req  *PauseRequest
+		want string
+	}{
+		{nil, model.PauseModeQueue},
+		{&PauseRequest{}, model.PauseModeQueue},
+		{&PauseRequest{Mode: "queue", Reason: "bad model version"}, model.PauseModeQueue},
+		{&PauseRequest{Mode: "reject"}, model.PauseModeReject},
+	}
+	for _, tt := range tests {
+		got, err := validatePauseRequest(tt.req)
+		if err != nil || got != tt.want {
+			t.Errorf("validatePauseRequest(%+v) = %q, %v, want %q", tt.req, got, err, tt.want)
+		}
+	}
+
+	invalid := []*PauseRequest{
+		{Mode: "drop"},
+		{Reason: strings.Repeat("x", maxPauseReasonLength+1)},
+	}
+	for _, req := range invalid {
+		if _, err := validatePauseRequest(req); err == nil || !strings.HasPrefix(err.Error(), "invalid request") {
+			t.Errorf("validatePauseRequest(mode=%q): expected invalid request error, got %v", req.Mode, err)
+		}
+	}
+}
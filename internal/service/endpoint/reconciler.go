@@ -0,0 +1,146 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// DefaultReplicaSettle is how long drift must persist before the reconciler acts.
+// It covers the gap between a provider scale call and the metadata write that follows it
+// (graceful scale-down confirms a worker is idle for up to 30s before scaling).
+const DefaultReplicaSettle = time.Minute
+
+// ReplicaReconcileReport summarizes one reconcile pass
+type ReplicaReconcileReport struct {
+	Checked   int      `json:"checked"`   // Endpoints with a deployment at the provider
+	InSync    int      `json:"inSync"`    // Provider replicas equal the desired count
+	Settling  int      `json:"settling"`  // Drift seen, waiting for the settle period
+	Converged []string `json:"converged"` // Endpoints scaled to their desired count
+	Failed    []string `json:"failed"`    // Endpoints whose scale call failed (retried next pass)
+}
+
+// driftObservation remembers when a given desired/actual mismatch was first seen
+type driftObservation struct {
+	desired int
+	actual  int
+	since   time.Time
+}
+
+// ReplicaReconciler converges provider replicas to the desired count stored in endpoint
+// metadata (endpoints.replicas). Scale paths record the desired count first, so a scale
+// call lost to a conflict, a provider hiccup or a restart is repaired on a later pass.
+type ReplicaReconciler struct {
+	provider     interfaces.DeploymentProvider
+	endpointRepo endpointRepository
+	settle       time.Duration
+
+	mu    sync.Mutex
+	drift map[string]driftObservation
+	now   func() time.Time
+}
+
+// NewReplicaReconciler creates a replica reconciler
+func NewReplicaReconciler(provider interfaces.DeploymentProvider, endpointRepo endpointRepository, settle time.Duration) *ReplicaReconciler {
+	if settle <= 0 {
+		settle = DefaultReplicaSettle
+	}
+	return &ReplicaReconciler{
+		provider:     provider,
+		endpointRepo: endpointRepo,
+		settle:       settle,
+		drift:        make(map[string]driftObservation),
+		now:          time.Now,
+	}
+}
+
+// Reconcile runs one pass over all endpoints. Endpoints without a deployment at the
+// provider are skipped: creating workloads is left to the deploy path.
+func (r *ReplicaReconciler) Reconcile(ctx context.Context) (*ReplicaReconcileReport, error) {
+	if r.provider == nil || r.endpointRepo == nil {
+		return nil, fmt.Errorf("replica reconciler not configured")
+	}
+
+	endpoints, err := r.endpointRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints: %w", err)
+	}
+	apps, err := r.provider.ListApps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider apps: %w", err)
+	}
+	actual := make(map[string]int, len(apps))
+	for _, app := range apps {
+		if app != nil {
+			actual[app.Name] = int(app.Replicas)
+		}
+	}
+
+	report := &ReplicaReconcileReport{}
+	now := r.now()
+	seen := make(map[string]struct{}, len(endpoints))
+
+	for _, ep := range endpoints {
+		current, deployed := actual[ep.Endpoint]
+		if !deployed {
+			continue
+		}
+		report.Checked++
+		seen[ep.Endpoint] = struct{}{}
+
+		if current == ep.Replicas {
+			report.InSync++
+			r.clearDrift(ep.Endpoint)
+			continue
+		}
+
+		if !r.driftSettled(ep.Endpoint, ep.Replicas, current, now) {
+			report.Settling++
+			continue
+		}
+
+		logger.WarnCtx(ctx, "replica drift for %s: provider has %d, desired %d, converging", ep.Endpoint, current, ep.Replicas)
+		if err := r.provider.ScaleApp(ctx, ep.Endpoint, ep.Replicas); err != nil {
+			logger.ErrorCtx(ctx, "failed to converge replicas for %s: %v", ep.Endpoint, err)
+			report.Failed = append(report.Failed, ep.Endpoint)
+			continue
+		}
+		r.clearDrift(ep.Endpoint)
+		report.Converged = append(report.Converged, ep.Endpoint)
+	}
+
+	// Forget endpoints that were deleted or undeployed
+	r.mu.Lock()
+	for name := range r.drift {
+		if _, ok := seen[name]; !ok {
+			delete(r.drift, name)
+		}
+	}
+	r.mu.Unlock()
+
+	return report, nil
+}
+
+// driftSettled records the mismatch and reports whether it has persisted for the settle
+// period. A different mismatch (either side moved) restarts the clock.
+func (r *ReplicaReconciler) driftSettled(name string, desired, actual int, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	obs, ok := r.drift[name]
+	if !ok || obs.desired != desired || obs.actual != actual {
+		r.drift[name] = driftObservation{desired: desired, actual: actual, since: now}
+		return false
+	}
+	return now.Sub(obs.since) >= r.settle
+}
+
+func (r *ReplicaReconciler) clearDrift(name string) {
+	r.mu.Lock()
+	delete(r.drift, name)
+	r.mu.Unlock()
+}
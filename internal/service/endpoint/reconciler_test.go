@@ -0,0 +1,121 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/store/mysql"
+)
+
+// stubEndpointRepository serves a fixed endpoint list
+type stubEndpointRepository struct {
+	endpoints []*mysql.Endpoint
+}
+
+func (r *stubEndpointRepository) Create(ctx context.Context, endpoint *mysql.Endpoint) error {
+	return nil
+}
+func (r *stubEndpointRepository) Get(ctx context.Context, name string) (*mysql.Endpoint, error) {
+	return nil, nil
+}
+func (r *stubEndpointRepository) Update(ctx context.Context, endpoint *mysql.Endpoint) error {
+	return nil
+}
+func (r *stubEndpointRepository) Delete(ctx context.Context, name string) error { return nil }
+func (r *stubEndpointRepository) List(ctx context.Context) ([]*mysql.Endpoint, error) {
+	return r.endpoints, nil
+}
+func (r *stubEndpointRepository) ListPage(ctx context.Context, limit, offset int) ([]*mysql.Endpoint, error) {
+	if offset >= len(r.endpoints) {
+		return nil, nil
+	}
+	page := r.endpoints[offset:]
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page, nil
+}
+func (r *stubEndpointRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.endpoints)), nil
+}
+
+func TestReplicaReconciler_ConvergesAfterSettle(t *testing.T) {
+	repo := &stubEndpointRepository{endpoints: []*mysql.Endpoint{
+		{Endpoint: "drifted", Replicas: 3},
+		{Endpoint: "in-sync", Replicas: 1},
+		{Endpoint: "not-deployed", Replicas: 2},
+	}}
+	scaled := map[string]int{}
+	failNext := true
+	provider := &mockDeploymentProvider{
+		listAppsFunc: func(ctx context.Context) ([]*interfaces.AppInfo, error) {
+			return []*interfaces.AppInfo{{Name: "drifted", Replicas: 1}, {Name: "in-sync", Replicas: 1}}, nil
+		},
+		scaleAppFunc: func(ctx context.Context, endpoint string, replicas int) error {
+			if failNext {
+				failNext = false
+				return errors.New("conflict")
+			}
+			scaled[endpoint] = replicas
+			return nil
+		},
+	}
+
+	now := time.Now()
+	reconciler := NewReplicaReconciler(provider, repo, time.Minute)
+	reconciler.now = func() time.Time { return now }
+
+	// First sighting only starts the settle clock
+	report, err := reconciler.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if report.Checked != 2 || report.InSync != 1 || report.Settling != 1 || len(scaled) != 0 {
+		t.Fatalf("unexpected first pass: %+v, scaled=%v", report, scaled)
+	}
+
+	// Settled, but the provider call fails: reported and retried next pass
+	now = now.Add(time.Minute)
+	report, _ = reconciler.Reconcile(context.Background())
+	if len(report.Failed) != 1 || report.Failed[0] != "drifted" {
+		t.Fatalf("expected failure to be reported: %+v", report)
+	}
+
+	report, _ = reconciler.Reconcile(context.Background())
+	if len(report.Converged) != 1 || scaled["drifted"] != 3 {
+		t.Fatalf("expected drifted to converge to 3: %+v, scaled=%v", report, scaled)
+	}
+	if _, ok := scaled["not-deployed"]; ok {
+		t.Errorf("endpoints without a deployment must not be scaled")
+	}
+}
+
+func TestReplicaReconciler_MovingDriftRestartsSettle(t *testing.T) {
+	repo := &stubEndpointRepository{endpoints: []*mysql.Endpoint{{Endpoint: "ep", Replicas: 2}}}
+	actual := int32(4)
+	provider := &mockDeploymentProvider{
+		listAppsFunc: func(ctx context.Context) ([]*interfaces.AppInfo, error) {
+			return []*interfaces.AppInfo{{Name: "ep", Replicas: actual}}, nil
+		},
+		scaleAppFunc: func(ctx context.Context, endpoint string, replicas int) error {
+			t.Fatalf("scale must wait for a stable drift")
+			return nil
+		},
+	}
+
+	now := time.Now()
+	reconciler := NewReplicaReconciler(provider, repo, time.Minute)
+	reconciler.now = func() time.Time { return now }
+
+	reconciler.Reconcile(context.Background())
+	// A scale-down in flight changes the provider count: the clock restarts
+	now = now.Add(time.Minute)
+	actual = 3
+	report, _ := reconciler.Reconcile(context.Background())
+	if report.Settling != 1 {
+		t.Fatalf("expected drift to be settling again: %+v", report)
+	}
+}
@@ -0,0 +1,52 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/interfaces"
+)
+
+// DefaultRolloutPollInterval is how often WaitForRollout checks the provider
+const DefaultRolloutPollInterval = 5 * time.Second
+
+// WaitForRollout waits until every replica of an endpoint runs its latest spec and is
+// available, reporting each status it sees. Status errors are retried on the next poll; the
+// wait ends with ctx.
+func (s *Service) WaitForRollout(ctx context.Context, name string, interval time.Duration, report func(*interfaces.AppStatus)) error {
+	if s.deployment == nil || s.deployment.provider == nil {
+		return fmt.Errorf("deployment provider not configured")
+	}
+	if interval <= 0 {
+		interval = DefaultRolloutPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		status, err := s.deployment.provider.GetAppStatus(ctx, name)
+		switch {
+		case err != nil:
+			lastErr = err
+		case status != nil:
+			lastErr = nil
+			if report != nil {
+				report(status)
+			}
+			if status.RolloutComplete {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w (last status error: %v)", ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
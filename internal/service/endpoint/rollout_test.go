@@ -0,0 +1,54 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"waverless/pkg/interfaces"
+)
+
+func TestWaitForRollout(t *testing.T) {
+	polls := 0
+	provider := &mockDeploymentProvider{
+		getAppStatusFunc: func(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+			polls++
+			switch polls {
+			case 1:
+				return nil, errors.New("informer not synced")
+			case 2:
+				return &interfaces.AppStatus{Endpoint: endpoint, ReadyReplicas: 1, TotalReplicas: 2}, nil
+			default:
+				return &interfaces.AppStatus{Endpoint: endpoint, ReadyReplicas: 2, TotalReplicas: 2, RolloutComplete: true}, nil
+			}
+		},
+	}
+	s := NewService(nil, nil, nil, nil, provider)
+
+	var reported []int32
+	err := s.WaitForRollout(context.Background(), "flux", time.Millisecond, func(status *interfaces.AppStatus) {
+		reported = append(reported, status.ReadyReplicas)
+	})
+	if err != nil {
+		t.Fatalf("WaitForRollout() error = %v", err)
+	}
+	if len(reported) != 2 || reported[0] != 1 || reported[1] != 2 {
+		t.Errorf("reported ready replicas = %v, want [1 2]", reported)
+	}
+}
+
+func TestWaitForRollout_ContextDone(t *testing.T) {
+	provider := &mockDeploymentProvider{
+		getAppStatusFunc: func(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+			return &interfaces.AppStatus{Endpoint: endpoint, TotalReplicas: 1}, nil
+		},
+	}
+	s := NewService(nil, nil, nil, nil, provider)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.WaitForRollout(ctx, "flux", time.Millisecond, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForRollout() error = %v, want deadline exceeded", err)
+	}
+}
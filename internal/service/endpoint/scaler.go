@@ -131,11 +131,9 @@ func (m *ScalerManager) scaleTo(ctx context.Context, name string, next func(int)
 		return fmt.Errorf("endpoint not found: %s", name)
 	}
 
+	// Record the desired count first: if the provider call is lost, the replica
+	// reconciler converges the provider to it on a later pass
 	target := next(current.Replicas)
-	if err := m.provider.ScaleApp(ctx, name, target); err != nil {
-		return err
-	}
-
 	if err := m.endpointRepo.UpdateReplicas(ctx, name, target); err != nil {
 		return err
 	}
@@ -144,5 +142,9 @@ func (m *ScalerManager) scaleTo(ctx context.Context, name string, next func(int)
 		_ = m.autoscalerConfigRepo.UpdateReplicas(ctx, name, target)
 	}
 
+	if err := m.provider.ScaleApp(ctx, name, target); err != nil {
+		return fmt.Errorf("desired replicas recorded (%d), provider scale pending reconciliation: %w", target, err)
+	}
+
 	return nil
 }
@@ -3,10 +3,15 @@ package endpoint
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
+	"waverless/pkg/discovery"
+	"waverless/pkg/hooks"
 	"waverless/pkg/interfaces"
+	"waverless/pkg/modelregistry"
 	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
 )
 
 // Service coordinates endpoint metadata, deployment, and scaling responsibilities.
@@ -14,8 +19,18 @@ type Service struct {
 	metadata   *MetadataManager
 	deployment *DeploymentManager
 	scaler     *ScalerManager
+	enricher   *RuntimeEnricher
+	env        *EnvManager
+	pause      *PauseManager
+	guard      RolloutGuard
+	hooks      LifecycleHooks
+	discovery  discovery.Registrar
+	models     modelregistry.Registry
 }
 
+// RolloutGuard returns an error while rollouts are not allowed, nil otherwise.
+type RolloutGuard func() error
+
 // NewService wires all managers together into a single facade that handlers
 // and other components can depend on.
 func NewService(
@@ -29,11 +44,16 @@ func NewService(
 	deployment := NewDeploymentManager(deploymentProvider, metadata, endpointRepo)
 	scaler := NewScalerManager(deploymentProvider, endpointRepo, autoscalerConfigRepo)
 
-	return &Service{
+	s := &Service{
 		metadata:   metadata,
 		deployment: deployment,
 		scaler:     scaler,
 	}
+	if endpointRepo != nil {
+		s.env = NewEnvManager(deploymentProvider, endpointRepo)
+		s.pause = NewPauseManager(endpointRepo, metadata)
+	}
+	return s
 }
 
 // SaveEndpoint persists endpoint metadata and autoscaler configuration.
@@ -61,11 +81,36 @@ func (s *Service) GetEndpointOnly(ctx context.Context, name string) (*mysql.Endp
 }
 
 // ListEndpoints lists all endpoints, combining metadata and autoscaler config.
+// With a runtime enricher set, status and replica counts are refreshed from the provider.
 func (s *Service) ListEndpoints(ctx context.Context) ([]*interfaces.EndpointMetadata, error) {
 	if s.metadata == nil {
 		return nil, fmt.Errorf("metadata manager not configured")
 	}
-	return s.metadata.List(ctx)
+	endpoints, err := s.metadata.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.enricher.Enrich(ctx, endpoints)
+	return endpoints, nil
+}
+
+// ListEndpointsPage lists a page of the endpoints, ordered by name, and their total.
+// Only the endpoints of the page are enriched.
+func (s *Service) ListEndpointsPage(ctx context.Context, limit, offset int) ([]*interfaces.EndpointMetadata, int64, error) {
+	if s.metadata == nil {
+		return nil, 0, fmt.Errorf("metadata manager not configured")
+	}
+	endpoints, total, err := s.metadata.ListPage(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.enricher.Enrich(ctx, endpoints)
+	return endpoints, total, nil
+}
+
+// SetRuntimeEnricher enables live runtime status on endpoint lists.
+func (s *Service) SetRuntimeEnricher(enricher *RuntimeEnricher) {
+	s.enricher = enricher
 }
 
 // UpdateEndpoint updates endpoint metadata.
@@ -85,28 +130,250 @@ func (s *Service) DeleteEndpoint(ctx context.Context, name string) error {
 	return s.metadata.Delete(ctx, name)
 }
 
+// SetRolloutGuard sets the check run before deploys, deployment updates and env changes.
+func (s *Service) SetRolloutGuard(guard RolloutGuard) {
+	s.guard = guard
+}
+
+// checkRollout runs the rollout guard, if any.
+func (s *Service) checkRollout() error {
+	if s.guard == nil {
+		return nil
+	}
+	return s.guard()
+}
+
 // Deploy triggers a deployment through the provider and persists metadata.
 func (s *Service) Deploy(ctx context.Context, req *interfaces.DeployRequest, metadata *interfaces.EndpointMetadata) (*interfaces.DeployResponse, error) {
 	if s.deployment == nil {
 		return nil, fmt.Errorf("deployment manager not configured")
 	}
-	return s.deployment.Deploy(ctx, req, metadata)
+	if err := s.checkRollout(); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return s.deployment.Deploy(ctx, req, metadata)
+	}
+	if err := s.runHooks(ctx, req.Endpoint, hooks.PhasePreDeploy, hooks.OperationDeploy); err != nil {
+		return nil, err
+	}
+
+	var resp *interfaces.DeployResponse
+	var err error
+	if s.env == nil || metadata == nil {
+		resp, err = s.deployment.Deploy(ctx, req, metadata)
+	} else {
+		before, _ := s.env.plainEnv(ctx, req.Endpoint)
+		resp, err = s.deployment.Deploy(ctx, req, metadata)
+		if err == nil {
+			s.env.recordReplace(ctx, req.Endpoint, before, metadata.Env)
+		}
+	}
+	if err != nil {
+		return resp, err
+	}
+	s.registerDiscovery(ctx, req.Endpoint)
+	if err := s.runHooks(ctx, req.Endpoint, hooks.PhasePostDeploy, hooks.OperationDeploy); err != nil {
+		return resp, fmt.Errorf("deployment succeeded but %w", err)
+	}
+	return resp, nil
 }
 
 // UpdateDeployment updates deployment fields (image/spec/replicas).
+// Updates of the replica count alone are scaling, not rollouts, and pass the rollout guard.
 func (s *Service) UpdateDeployment(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error) {
 	if s.deployment == nil {
 		return nil, fmt.Errorf("deployment manager not configured")
 	}
-	return s.deployment.Update(ctx, req)
+	if req == nil || !isReplicasOnly(req) {
+		if err := s.checkRollout(); err != nil {
+			return nil, err
+		}
+	}
+	if req == nil {
+		return s.deployment.Update(ctx, req)
+	}
+	// Scaling is not a rollout and runs no hooks
+	rollout := !isReplicasOnly(req)
+	if rollout {
+		if err := s.runHooks(ctx, req.Endpoint, hooks.PhasePreDeploy, hooks.OperationUpdate); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *interfaces.DeployResponse
+	var err error
+	if s.env == nil || req.Env == nil {
+		resp, err = s.deployment.Update(ctx, req)
+	} else {
+		before, _ := s.env.plainEnv(ctx, req.Endpoint)
+		resp, err = s.deployment.Update(ctx, req)
+		if err == nil {
+			s.env.recordReplace(ctx, req.Endpoint, before, *req.Env)
+		}
+	}
+	if err != nil || !rollout {
+		return resp, err
+	}
+	if err := s.runHooks(ctx, req.Endpoint, hooks.PhasePostDeploy, hooks.OperationUpdate); err != nil {
+		return resp, fmt.Errorf("deployment updated but %w", err)
+	}
+	return resp, nil
+}
+
+// isReplicasOnly reports whether an update changes nothing but the replica count.
+func isReplicasOnly(req *interfaces.UpdateDeploymentRequest) bool {
+	scaleOnly := interfaces.UpdateDeploymentRequest{Endpoint: req.Endpoint, Replicas: req.Replicas}
+	return req.Replicas != nil && reflect.DeepEqual(*req, scaleOnly)
+}
+
+// DryRunDeploy validates a deploy and reports what it would change, without deploying.
+func (s *Service) DryRunDeploy(ctx context.Context, req *interfaces.DeployRequest, metadata *interfaces.EndpointMetadata) (*DeployPlan, error) {
+	if s.deployment == nil {
+		return nil, fmt.Errorf("deployment manager not configured")
+	}
+	return s.deployment.DryRunDeploy(ctx, req, metadata)
+}
+
+// DryRunUpdateDeployment validates a deployment update and reports what it would change, without applying it.
+func (s *Service) DryRunUpdateDeployment(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*DeployPlan, error) {
+	if s.deployment == nil {
+		return nil, fmt.Errorf("deployment manager not configured")
+	}
+	return s.deployment.DryRunUpdate(ctx, req)
+}
+
+// Diff compares the live state of an endpoint with a proposed deploy request.
+func (s *Service) Diff(ctx context.Context, req *interfaces.DeployRequest, proposed *interfaces.EndpointMetadata) (*DeploymentDiff, error) {
+	if s.deployment == nil {
+		return nil, fmt.Errorf("deployment manager not configured")
+	}
+	return s.deployment.Diff(ctx, req, proposed)
+}
+
+// GetEnv returns the env of an endpoint and its version.
+func (s *Service) GetEnv(ctx context.Context, name string) (*EndpointEnv, error) {
+	if s.env == nil {
+		return nil, fmt.Errorf("env manager not configured")
+	}
+	return s.env.Get(ctx, name)
+}
+
+// PatchEnv sets and unsets individual env vars of an endpoint, based on an env version.
+func (s *Service) PatchEnv(ctx context.Context, name string, patch *EnvPatch, changedBy string) (*EndpointEnv, *model.EndpointEnvChange, error) {
+	if s.env == nil {
+		return nil, nil, fmt.Errorf("env manager not configured")
+	}
+	if err := s.checkRollout(); err != nil {
+		return nil, nil, err
+	}
+	return s.env.Patch(ctx, name, patch, changedBy)
+}
+
+// ListEnvChanges returns the env change history of an endpoint, newest first.
+func (s *Service) ListEnvChanges(ctx context.Context, name string, limit int) ([]*model.EndpointEnvChange, error) {
+	if s.env == nil {
+		return nil, fmt.Errorf("env manager not configured")
+	}
+	return s.env.History(ctx, name, limit)
+}
+
+// SetEnvChangeHook sets the hook called after every env change.
+func (s *Service) SetEnvChangeHook(hook EnvChangeHook) {
+	if s.env != nil {
+		s.env.SetHook(hook)
+	}
+}
+
+// PauseDispatch pauses task dispatch of an endpoint without touching its replicas.
+func (s *Service) PauseDispatch(ctx context.Context, name string, req *PauseRequest, pausedBy string) (*interfaces.EndpointMetadata, error) {
+	if s.pause == nil {
+		return nil, fmt.Errorf("pause manager not configured")
+	}
+	return s.pause.Pause(ctx, name, req, pausedBy)
+}
+
+// ResumeDispatch resumes task dispatch of a paused endpoint.
+func (s *Service) ResumeDispatch(ctx context.Context, name string, resumedBy string) (*interfaces.EndpointMetadata, error) {
+	if s.pause == nil {
+		return nil, fmt.Errorf("pause manager not configured")
+	}
+	return s.pause.Resume(ctx, name, resumedBy)
+}
+
+// SetImageValidationRepository enables per-endpoint image validation history.
+func (s *Service) SetImageValidationRepository(repo *mysql.ImageValidationRepository) {
+	if s.deployment != nil {
+		s.deployment.SetImageValidationRepository(repo)
+	}
+}
+
+// ValidateImage checks an image reference against its registry, recording the outcome for the endpoint if given.
+func (s *Service) ValidateImage(ctx context.Context, endpoint, image string, cred *interfaces.RegistryCredential) (*interfaces.ImageValidationResult, error) {
+	if s.deployment == nil {
+		return nil, fmt.Errorf("deployment manager not configured")
+	}
+	return s.deployment.ValidateImage(ctx, endpoint, image, cred)
+}
+
+// ListImageValidations returns the image validation history of an endpoint.
+func (s *Service) ListImageValidations(ctx context.Context, endpoint string, limit int) ([]*model.ImageValidationRecord, error) {
+	if s.deployment == nil {
+		return nil, fmt.Errorf("deployment manager not configured")
+	}
+	return s.deployment.ListImageValidations(ctx, endpoint, limit)
+}
+
+// GetLastVerifiedImage returns the most recent successful image validation of an endpoint.
+func (s *Service) GetLastVerifiedImage(ctx context.Context, endpoint string) (*model.ImageValidationRecord, error) {
+	if s.deployment == nil {
+		return nil, fmt.Errorf("deployment manager not configured")
+	}
+	return s.deployment.GetLastVerifiedImage(ctx, endpoint)
+}
+
+// SetImageCopyRepository enables provenance tracking of images copied into the internal registry.
+func (s *Service) SetImageCopyRepository(repo *mysql.ImageCopyRepository) {
+	if s.deployment != nil {
+		s.deployment.SetImageCopyRepository(repo)
+	}
+}
+
+// ListImageCopies returns the internal registry copy history of an endpoint.
+func (s *Service) ListImageCopies(ctx context.Context, endpoint string, limit int) ([]*model.ImageCopyRecord, error) {
+	if s.deployment == nil {
+		return nil, fmt.Errorf("deployment manager not configured")
+	}
+	return s.deployment.ListImageCopies(ctx, endpoint, limit)
+}
+
+// GetImageProvenance returns the upstream origin of an internal image reference.
+func (s *Service) GetImageProvenance(ctx context.Context, targetImage string) (*model.ImageCopyRecord, error) {
+	if s.deployment == nil {
+		return nil, fmt.Errorf("deployment manager not configured")
+	}
+	return s.deployment.GetImageProvenance(ctx, targetImage)
 }
 
 // DeleteDeployment removes runtime deployment resources and metadata.
+// The endpoint's lifecycle hooks run first and are removed with it, as is its discovery registration.
 func (s *Service) DeleteDeployment(ctx context.Context, name string) error {
 	if s.deployment == nil {
 		return fmt.Errorf("deployment manager not configured")
 	}
-	return s.deployment.Delete(ctx, name)
+	if err := s.runHooks(ctx, name, hooks.PhasePreDelete, hooks.OperationDelete); err != nil {
+		return err
+	}
+	if err := s.deployment.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.deregisterDiscovery(ctx, name)
+	if s.hooks != nil {
+		if err := s.hooks.DeleteHooks(ctx, name); err != nil {
+			return fmt.Errorf("endpoint deleted but failed to remove its lifecycle hooks: %w", err)
+		}
+	}
+	return nil
 }
 
 // ScaleUp increases replicas by the provided delta.
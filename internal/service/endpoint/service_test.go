@@ -0,0 +1,66 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/store/mysql"
+)
+
+func TestIsReplicasOnly(t *testing.T) {
+	replicas := 2
+	env := map[string]string{"A": "1"}
+	tests := []struct {
+		req  interfaces.UpdateDeploymentRequest
+		want bool
+	}{
+		{interfaces.UpdateDeploymentRequest{Endpoint: "e", Replicas: &replicas}, true},
+		{interfaces.UpdateDeploymentRequest{Endpoint: "e"}, false},
+		{interfaces.UpdateDeploymentRequest{Endpoint: "e", Replicas: &replicas, Image: "img:v2"}, false},
+		{interfaces.UpdateDeploymentRequest{Endpoint: "e", Replicas: &replicas, Env: &env}, false},
+	}
+	for _, tt := range tests {
+		if got := isReplicasOnly(&tt.req); got != tt.want {
+			t.Errorf("isReplicasOnly(%+v) = %v, want %v", tt.req, got, tt.want)
+		}
+	}
+}
+
+func TestRolloutGuard(t *testing.T) {
+	frozen := errors.New("frozen")
+	s := &Service{deployment: &DeploymentManager{}, env: &EnvManager{}}
+	s.SetRolloutGuard(func() error { return frozen })
+
+	ctx := context.Background()
+	if _, err := s.Deploy(ctx, &interfaces.DeployRequest{Endpoint: "e"}, &interfaces.EndpointMetadata{Name: "e"}); !errors.Is(err, frozen) {
+		t.Errorf("Deploy: expected guard error, got %v", err)
+	}
+	if _, err := s.UpdateDeployment(ctx, &interfaces.UpdateDeploymentRequest{Endpoint: "e", Image: "img:v2"}); !errors.Is(err, frozen) {
+		t.Errorf("UpdateDeployment: expected guard error, got %v", err)
+	}
+	if _, _, err := s.PatchEnv(ctx, "e", &EnvPatch{}, "ops"); !errors.Is(err, frozen) {
+		t.Errorf("PatchEnv: expected guard error, got %v", err)
+	}
+}
+
+func TestListEndpointsPage(t *testing.T) {
+	repo := &stubEndpointRepository{endpoints: []*mysql.Endpoint{
+		{Endpoint: "a", Replicas: 1},
+		{Endpoint: "b", Replicas: 2},
+		{Endpoint: "c", Replicas: 3},
+	}}
+	s := &Service{metadata: NewMetadataManager(repo, nil, nil, nil)}
+
+	endpoints, total, err := s.ListEndpointsPage(context.Background(), 2, 1)
+	if err != nil {
+		t.Fatalf("ListEndpointsPage: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("total = %d, want 3", total)
+	}
+	if len(endpoints) != 2 || endpoints[0].Name != "b" || endpoints[1].Name != "c" || endpoints[1].Replicas != 3 {
+		t.Errorf("unexpected page %+v", endpoints)
+	}
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/constants"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// UpsertEndpointGroupRequest creates or updates an endpoint group
+type UpsertEndpointGroupRequest struct {
+	DisplayName  string `json:"displayName,omitempty"`
+	Description  string `json:"description,omitempty"`
+	MaxReplicas  int    `json:"maxReplicas"`  // Total replicas across members (0 = unlimited)
+	MaxGPUCount  int    `json:"maxGpuCount"`  // Total GPUs across members (0 = unlimited)
+	WorkStealing bool   `json:"workStealing"` // Idle workers take tasks of members with the same image and spec
+}
+
+// EndpointGroupMemberStatus is the state of one member of a group
+type EndpointGroupMemberStatus struct {
+	Endpoint      string `json:"endpoint"`
+	Priority      int    `json:"priority"`
+	Replicas      int    `json:"replicas"`
+	ReadyReplicas int    `json:"readyReplicas"`
+	MinReplicas   int    `json:"minReplicas"`
+	MaxReplicas   int    `json:"maxReplicas"`
+	GPUCount      int    `json:"gpuCount"` // GPUs held by the desired replicas
+	PendingTasks  int64  `json:"pendingTasks"`
+	RunningTasks  int64  `json:"runningTasks"`
+	HealthStatus  string `json:"healthStatus,omitempty"`
+}
+
+// EndpointGroupStatus is the aggregate state of a group against its budget
+type EndpointGroupStatus struct {
+	Name          string                       `json:"name"`
+	MaxReplicas   int                          `json:"maxReplicas"`
+	MaxGPUCount   int                          `json:"maxGpuCount"`
+	WorkStealing  bool                         `json:"workStealing"`
+	Replicas      int                          `json:"replicas"`
+	ReadyReplicas int                          `json:"readyReplicas"`
+	GPUCount      int                          `json:"gpuCount"`
+	PendingTasks  int64                        `json:"pendingTasks"`
+	RunningTasks  int64                        `json:"runningTasks"`
+	Members       []*EndpointGroupMemberStatus `json:"members"`
+}
+
+// EndpointGroupUsage is the GPU usage of a group over a time range
+type EndpointGroupUsage struct {
+	Name           string                               `json:"name"`
+	From           time.Time                            `json:"from"`
+	To             time.Time                            `json:"to"`
+	TotalTasks     int                                  `json:"totalTasks"`
+	CompletedTasks int                                  `json:"completedTasks"`
+	FailedTasks    int                                  `json:"failedTasks"`
+	TotalGPUHours  float64                              `json:"totalGpuHours"`
+	ByEndpoint     map[string]*EndpointGroupMemberUsage `json:"byEndpoint"`
+}
+
+// EndpointGroupMemberUsage is the GPU usage of one member over the range
+type EndpointGroupMemberUsage struct {
+	TotalTasks    int     `json:"totalTasks"`
+	TotalGPUHours float64 `json:"totalGpuHours"`
+}
+
+// EndpointGroupService manages endpoint groups: endpoints sharing a replica / GPU budget
+// that the autoscaler allocates among members by priority and demand.
+type EndpointGroupService struct {
+	repo            *mysql.EndpointGroupRepository
+	endpointService *endpointsvc.Service
+	taskRepo        *mysql.TaskRepository
+	gpuUsageService *GPUUsageService
+}
+
+// NewEndpointGroupService creates a new endpoint group service
+func NewEndpointGroupService(repo *mysql.EndpointGroupRepository, endpointService *endpointsvc.Service, taskRepo *mysql.TaskRepository, gpuUsageService *GPUUsageService) *EndpointGroupService {
+	return &EndpointGroupService{
+		repo:            repo,
+		endpointService: endpointService,
+		taskRepo:        taskRepo,
+		gpuUsageService: gpuUsageService,
+	}
+}
+
+// ListGroups returns all groups
+func (s *EndpointGroupService) ListGroups(ctx context.Context) ([]*model.EndpointGroup, error) {
+	return s.repo.List(ctx)
+}
+
+// GetGroup returns a group, or an error if it does not exist
+func (s *EndpointGroupService) GetGroup(ctx context.Context, name string) (*model.EndpointGroup, error) {
+	group, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("endpoint group %s not found", name)
+	}
+	return group, nil
+}
+
+// UpsertGroup creates or updates a group
+func (s *EndpointGroupService) UpsertGroup(ctx context.Context, name string, req *UpsertEndpointGroupRequest) (*model.EndpointGroup, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("group name is required")
+	}
+	if req.MaxReplicas < 0 || req.MaxGPUCount < 0 {
+		return nil, fmt.Errorf("maxReplicas and maxGpuCount must be >= 0")
+	}
+
+	group := &model.EndpointGroup{
+		Name:         name,
+		DisplayName:  req.DisplayName,
+		Description:  req.Description,
+		MaxReplicas:  req.MaxReplicas,
+		MaxGPUCount:  req.MaxGPUCount,
+		WorkStealing: req.WorkStealing,
+	}
+	if group.DisplayName == "" {
+		group.DisplayName = name
+	}
+	if err := s.repo.Upsert(ctx, group); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, name)
+}
+
+// DeleteGroup removes a group; its members keep running without a shared budget
+func (s *EndpointGroupService) DeleteGroup(ctx context.Context, name string) error {
+	if _, err := s.GetGroup(ctx, name); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, name)
+}
+
+// AddMember assigns an endpoint to a group (moving it out of its previous group)
+func (s *EndpointGroupService) AddMember(ctx context.Context, name, endpoint string) error {
+	if _, err := s.GetGroup(ctx, name); err != nil {
+		return err
+	}
+	return s.setEndpointGroup(ctx, endpoint, name)
+}
+
+// RemoveMember detaches an endpoint from a group
+func (s *EndpointGroupService) RemoveMember(ctx context.Context, name, endpoint string) error {
+	meta, err := s.getEndpoint(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if meta.GroupName != name {
+		return fmt.Errorf("endpoint %s is not a member of endpoint group %s", endpoint, name)
+	}
+	return s.setEndpointGroup(ctx, endpoint, "")
+}
+
+func (s *EndpointGroupService) setEndpointGroup(ctx context.Context, endpoint, group string) error {
+	meta, err := s.getEndpoint(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if meta.GroupName == group {
+		return nil
+	}
+	previous := meta.GroupName
+	meta.GroupName = group
+	if err := s.endpointService.UpdateEndpoint(ctx, meta); err != nil {
+		return err
+	}
+	logger.InfoCtx(ctx, "Endpoint %s moved from group %q to %q", endpoint, previous, group)
+	return nil
+}
+
+func (s *EndpointGroupService) getEndpoint(ctx context.Context, endpoint string) (*interfaces.EndpointMetadata, error) {
+	meta, err := s.endpointService.GetEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("endpoint %s not found", endpoint)
+	}
+	return meta, nil
+}
+
+// GetGroupStatus aggregates replicas, GPUs and queue depth of the group members
+func (s *EndpointGroupService) GetGroupStatus(ctx context.Context, name string) (*EndpointGroupStatus, error) {
+	group, err := s.GetGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.listMembers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &EndpointGroupStatus{
+		Name:         group.Name,
+		MaxReplicas:  group.MaxReplicas,
+		MaxGPUCount:  group.MaxGPUCount,
+		WorkStealing: group.WorkStealing,
+		Members:      make([]*EndpointGroupMemberStatus, 0, len(members)),
+	}
+	for _, meta := range members {
+		member := &EndpointGroupMemberStatus{
+			Endpoint:      meta.Name,
+			Priority:      meta.Priority,
+			Replicas:      meta.Replicas,
+			ReadyReplicas: meta.ReadyReplicas,
+			MinReplicas:   meta.MinReplicas,
+			MaxReplicas:   meta.MaxReplicas,
+			GPUCount:      meta.Replicas * meta.GpuCount,
+			HealthStatus:  meta.HealthStatus,
+		}
+		if s.taskRepo != nil {
+			if pending, err := s.taskRepo.CountByEndpointAndStatus(ctx, meta.Name, constants.TaskStatusPending.String()); err == nil {
+				member.PendingTasks = pending
+			}
+			if running, err := s.taskRepo.CountInProgressByEndpoint(ctx, meta.Name); err == nil {
+				member.RunningTasks = running
+			}
+		}
+
+		status.Replicas += member.Replicas
+		status.ReadyReplicas += member.ReadyReplicas
+		status.GPUCount += member.GPUCount
+		status.PendingTasks += member.PendingTasks
+		status.RunningTasks += member.RunningTasks
+		status.Members = append(status.Members, member)
+	}
+	return status, nil
+}
+
+// GetGroupUsage sums the daily GPU usage of the current members over [from, to)
+func (s *EndpointGroupService) GetGroupUsage(ctx context.Context, name string, from, to time.Time) (*EndpointGroupUsage, error) {
+	if _, err := s.GetGroup(ctx, name); err != nil {
+		return nil, err
+	}
+	if s.gpuUsageService == nil {
+		return nil, fmt.Errorf("GPU usage statistics are not available")
+	}
+	members, err := s.listMembers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &EndpointGroupUsage{
+		Name:       name,
+		From:       from,
+		To:         to,
+		ByEndpoint: make(map[string]*EndpointGroupMemberUsage, len(members)),
+	}
+	loc := s.gpuUsageService.ReportingLocation()
+	for _, meta := range members {
+		stats, err := s.gpuUsageService.GetDailyStats(ctx, model.GPUUsageScopeEndpoint, meta.Name, from, to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GPU usage of %s: %w", meta.Name, err)
+		}
+		member := &EndpointGroupMemberUsage{}
+		for _, day := range stats {
+			member.TotalTasks += day.TotalTasks
+			member.TotalGPUHours += day.TotalGPUHours
+			usage.CompletedTasks += day.CompletedTasks
+			usage.FailedTasks += day.FailedTasks
+		}
+		usage.TotalTasks += member.TotalTasks
+		usage.TotalGPUHours += member.TotalGPUHours
+		usage.ByEndpoint[meta.Name] = member
+	}
+	return usage, nil
+}
+
+// listMembers returns the metadata of the group's endpoints, skipping deleted ones
+func (s *EndpointGroupService) listMembers(ctx context.Context, name string) ([]*interfaces.EndpointMetadata, error) {
+	names, err := s.repo.ListMembers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]*interfaces.EndpointMetadata, 0, len(names))
+	for _, endpoint := range names {
+		meta, err := s.endpointService.GetEndpoint(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if meta == nil || meta.Status == "deleted" {
+			continue
+		}
+		members = append(members, meta)
+	}
+	return members, nil
+}
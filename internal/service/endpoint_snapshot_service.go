@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"waverless/internal/model"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+// snapshotHistoryLimit is how many scaling decisions and failed tasks a snapshot holds
+const snapshotHistoryLimit = 50
+
+// EndpointSnapshot is the full state of an endpoint at one point in time, in one document to
+// attach to incident tickets. Sections that could not be read are left empty and their
+// error is recorded in Errors, so a snapshot is still taken while parts of the system fail.
+type EndpointSnapshot struct {
+	Endpoint   string    `json:"endpoint"`
+	CapturedAt time.Time `json:"capturedAt"`
+
+	Metadata   *interfaces.EndpointMetadata `json:"metadata"`
+	Autoscaler *mysqlModel.AutoscalerConfig `json:"autoscaler,omitempty"`
+	// Deployment is the live deployment as the provider runs it (nil when not deployed or
+	// the provider cannot describe it)
+	Deployment *interfaces.DeploymentView `json:"deployment,omitempty"`
+	// Workers includes workers terminated within the last hour
+	Workers []*SnapshotWorker `json:"workers"`
+	// ScalingEvents are the last autoscaler decisions, newest first
+	ScalingEvents []*mysqlModel.ScalingEvent `json:"scalingEvents"`
+	// FailedTasks are the last failed tasks, newest first (input and output are left out)
+	FailedTasks []*SnapshotTask `json:"failedTasks"`
+
+	Errors map[string]string `json:"errors,omitempty"` // Section -> error
+}
+
+// SnapshotWorker is a worker with its pod state and failure
+type SnapshotWorker struct {
+	WorkerID       string                 `json:"workerId"`
+	PodName        string                 `json:"podName,omitempty"`
+	Status         string                 `json:"status"`
+	Version        string                 `json:"version,omitempty"`
+	Concurrency    int                    `json:"concurrency"`
+	CurrentJobs    int                    `json:"currentJobs"`
+	LastHeartbeat  time.Time              `json:"lastHeartbeat"`
+	TasksCompleted int64                  `json:"tasksCompleted"`
+	TasksFailed    int64                  `json:"tasksFailed"`
+	Pod            map[string]interface{} `json:"pod,omitempty"` // Phase, status, reason, message, IP, node
+	DrainReason    string                 `json:"drainReason,omitempty"`
+	FailureType    string                 `json:"failureType,omitempty"`
+	FailureReason  string                 `json:"failureReason,omitempty"`
+	FailureAt      *time.Time             `json:"failureAt,omitempty"`
+	RegisteredAt   *time.Time             `json:"registeredAt,omitempty"`
+	TerminatedAt   *time.Time             `json:"terminatedAt,omitempty"`
+	ColdStartMs    *int64                 `json:"coldStartMs,omitempty"`
+	CustomMetric   *float64               `json:"customMetric,omitempty"`
+	JobsInProgress []string               `json:"jobsInProgress,omitempty"`
+}
+
+// SnapshotTask is a failed task without its payload
+type SnapshotTask struct {
+	TaskID      string     `json:"taskId"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error"`
+	WorkerID    string     `json:"workerId,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// EndpointSnapshotService captures endpoint snapshots
+type EndpointSnapshotService struct {
+	endpointService    *endpointsvc.Service
+	repo               *mysql.Repository
+	deploymentProvider interfaces.DeploymentProvider
+}
+
+// NewEndpointSnapshotService creates a new endpoint snapshot service
+func NewEndpointSnapshotService(endpointService *endpointsvc.Service, repo *mysql.Repository, deploymentProvider interfaces.DeploymentProvider) *EndpointSnapshotService {
+	return &EndpointSnapshotService{
+		endpointService:    endpointService,
+		repo:               repo,
+		deploymentProvider: deploymentProvider,
+	}
+}
+
+// Capture takes a snapshot of an endpoint. It fails only when the endpoint does not exist.
+func (s *EndpointSnapshotService) Capture(ctx context.Context, name string) (*EndpointSnapshot, error) {
+	meta, err := s.endpointService.GetEndpoint(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("endpoint %s not found", name)
+	}
+
+	snapshot := &EndpointSnapshot{
+		Endpoint:      name,
+		CapturedAt:    time.Now().UTC(),
+		Metadata:      meta,
+		Workers:       []*SnapshotWorker{},
+		ScalingEvents: []*mysqlModel.ScalingEvent{},
+		FailedTasks:   []*SnapshotTask{},
+	}
+	fail := func(section string, err error) {
+		if snapshot.Errors == nil {
+			snapshot.Errors = make(map[string]string)
+		}
+		snapshot.Errors[section] = err.Error()
+	}
+
+	if autoscaler, err := s.repo.AutoscalerConfig.Get(ctx, name); err != nil {
+		fail("autoscaler", err)
+	} else {
+		snapshot.Autoscaler = autoscaler
+	}
+
+	if viewer, ok := interfaces.ResolveProvider(ctx, s.deploymentProvider, name, "").(interfaces.DeploymentViewer); ok {
+		if view, err := viewer.LiveDeployment(ctx, name); err != nil {
+			fail("deployment", err)
+		} else {
+			snapshot.Deployment = view
+		}
+	}
+
+	if workers, err := s.repo.Worker.GetByEndpointForSync(ctx, name); err != nil {
+		fail("workers", err)
+	} else {
+		for _, w := range workers {
+			snapshot.Workers = append(snapshot.Workers, toSnapshotWorker(w))
+		}
+	}
+
+	if events, err := s.repo.ScalingEvent.ListByEndpoint(ctx, name, snapshotHistoryLimit); err != nil {
+		fail("scalingEvents", err)
+	} else {
+		snapshot.ScalingEvents = events
+	}
+
+	filters := map[string]interface{}{"endpoint": name, "status": string(model.TaskStatusFailed)}
+	if tasks, err := s.repo.Task.ListWithTaskIDExcludeInput(ctx, filters, "", snapshotHistoryLimit, 0); err != nil {
+		fail("failedTasks", err)
+	} else {
+		for _, t := range tasks {
+			snapshot.FailedTasks = append(snapshot.FailedTasks, &SnapshotTask{
+				TaskID:      t.TaskID,
+				Status:      t.Status,
+				Error:       t.Error,
+				WorkerID:    t.WorkerID,
+				CreatedAt:   t.CreatedAt,
+				StartedAt:   t.StartedAt,
+				CompletedAt: t.CompletedAt,
+			})
+		}
+	}
+
+	return snapshot, nil
+}
+
+// toSnapshotWorker converts a worker record
+func toSnapshotWorker(w *mysqlModel.Worker) *SnapshotWorker {
+	var jobsInProgress []string
+	if w.JobsInProgress != "" {
+		json.Unmarshal([]byte(w.JobsInProgress), &jobsInProgress)
+	}
+	return &SnapshotWorker{
+		WorkerID:       w.WorkerID,
+		PodName:        w.PodName,
+		Status:         w.Status,
+		Version:        w.Version,
+		Concurrency:    w.Concurrency,
+		CurrentJobs:    w.CurrentJobs,
+		LastHeartbeat:  w.LastHeartbeat,
+		TasksCompleted: w.TotalTasksCompleted,
+		TasksFailed:    w.TotalTasksFailed,
+		Pod:            w.RuntimeState,
+		DrainReason:    w.DrainReason,
+		FailureType:    w.FailureType,
+		FailureReason:  w.FailureReason,
+		FailureAt:      w.FailureOccurredAt,
+		RegisteredAt:   w.RegisteredAt,
+		TerminatedAt:   w.TerminatedAt,
+		ColdStartMs:    w.ColdStartDurationMs,
+		CustomMetric:   w.CustomMetric,
+		JobsInProgress: jobsInProgress,
+	}
+}
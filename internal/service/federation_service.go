@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"waverless/internal/model"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/constants"
+	"waverless/pkg/federation"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+// FederationMemberRequest is one backing endpoint of a federated endpoint
+type FederationMemberRequest struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+	Region   string `json:"region" binding:"required"`
+	Priority int    `json:"priority"`          // Higher = preferred (e.g. nearer to clients)
+	Weight   *int   `json:"weight,omitempty"`  // Percent of routed traffic kept, 0-100 (default 100)
+	Enabled  *bool  `json:"enabled,omitempty"` // Default true
+}
+
+// UpdateRegionWeightsRequest overrides the traffic weight of whole regions, e.g. to
+// migrate traffic away from a region step by step
+type UpdateRegionWeightsRequest struct {
+	Weights map[string]int `json:"weights" binding:"required"` // region -> 0-100
+}
+
+// SaveFederationRequest creates or updates a federated endpoint, replacing its members
+type SaveFederationRequest struct {
+	DisplayName string                     `json:"displayName,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Members     []*FederationMemberRequest `json:"members" binding:"required,min=1,dive"`
+}
+
+// FederationDetail is a federated endpoint with its members
+type FederationDetail struct {
+	*mysqlModel.FederatedEndpoint
+	Members []*mysqlModel.FederationMember `json:"members"`
+}
+
+// FederationStatus lists the members in routing order (first = current target)
+type FederationStatus struct {
+	Name    string                  `json:"name"`
+	Target  string                  `json:"target,omitempty"` // Endpoint tasks are routed to now, empty if none is available
+	Region  string                  `json:"region,omitempty"`
+	Members []*federation.Candidate `json:"members"`
+}
+
+// FederationUsage is the GPU usage of a federated endpoint rolled up per region
+type FederationUsage struct {
+	Name           string                            `json:"name"`
+	From           time.Time                         `json:"from"`
+	To             time.Time                         `json:"to"`
+	TotalTasks     int                               `json:"totalTasks"`
+	CompletedTasks int                               `json:"completedTasks"`
+	FailedTasks    int                               `json:"failedTasks"`
+	TotalGPUHours  float64                           `json:"totalGpuHours"`
+	ByRegion       map[string]*FederationRegionUsage `json:"byRegion"`
+}
+
+// FederationRegionUsage is the GPU usage of the members in one region
+type FederationRegionUsage struct {
+	Endpoints      []string `json:"endpoints"`
+	TotalTasks     int      `json:"totalTasks"`
+	CompletedTasks int      `json:"completedTasks"`
+	FailedTasks    int      `json:"failedTasks"`
+	TotalGPUHours  float64  `json:"totalGpuHours"`
+}
+
+// FederationRoute is the member a task of a federated endpoint was routed to
+type FederationRoute struct {
+	Endpoint string
+	Region   string
+}
+
+// FederationService manages federated endpoints: logical endpoints backed by endpoints in
+// several regions/clusters. Task submission routes to the healthiest, nearest member and
+// fails over to the next one when it becomes unavailable.
+type FederationService struct {
+	repo            *mysql.FederationRepository
+	endpointService *endpointsvc.Service
+	taskRepo        *mysql.TaskRepository
+	gpuUsageService *GPUUsageService
+	sourceRegions   *federation.SourceRegionResolver
+	rnd             func() float64
+}
+
+// NewFederationService creates a new federation service
+func NewFederationService(repo *mysql.FederationRepository, endpointService *endpointsvc.Service, taskRepo *mysql.TaskRepository, gpuUsageService *GPUUsageService) *FederationService {
+	return &FederationService{
+		repo:            repo,
+		endpointService: endpointService,
+		taskRepo:        taskRepo,
+		gpuUsageService: gpuUsageService,
+		rnd:             rand.Float64,
+	}
+}
+
+// SetSourceRegionResolver enables deriving the region hint from the client IP
+func (s *FederationService) SetSourceRegionResolver(resolver *federation.SourceRegionResolver) {
+	s.sourceRegions = resolver
+}
+
+// ListFederations returns all federated endpoints
+func (s *FederationService) ListFederations(ctx context.Context) ([]*mysqlModel.FederatedEndpoint, error) {
+	return s.repo.List(ctx)
+}
+
+// GetFederation returns a federated endpoint with its members
+func (s *FederationService) GetFederation(ctx context.Context, name string) (*FederationDetail, error) {
+	fed, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if fed == nil {
+		return nil, fmt.Errorf("federated endpoint %s not found", name)
+	}
+	members, err := s.repo.ListMembers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &FederationDetail{FederatedEndpoint: fed, Members: members}, nil
+}
+
+// SaveFederation creates or updates a federated endpoint and replaces its members
+func (s *FederationService) SaveFederation(ctx context.Context, name string, req *SaveFederationRequest) (*FederationDetail, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("federation name is required")
+	}
+	// Task submission resolves real endpoints first, a federation with the same name would be unreachable
+	if existing, err := s.endpointService.GetEndpointOnly(ctx, name); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("name %s is already used by an endpoint", name)
+	}
+
+	members := make([]*mysqlModel.FederationMember, 0, len(req.Members))
+	seen := make(map[string]bool, len(req.Members))
+	for _, m := range req.Members {
+		if seen[m.Endpoint] {
+			return nil, fmt.Errorf("endpoint %s is listed more than once", m.Endpoint)
+		}
+		seen[m.Endpoint] = true
+		if ep, err := s.endpointService.GetEndpointOnly(ctx, m.Endpoint); err != nil {
+			return nil, err
+		} else if ep == nil {
+			return nil, fmt.Errorf("endpoint %s not found", m.Endpoint)
+		}
+		enabled := true
+		if m.Enabled != nil {
+			enabled = *m.Enabled
+		}
+		weight := 100
+		if m.Weight != nil {
+			weight = *m.Weight
+		}
+		if weight < 0 || weight > 100 {
+			return nil, fmt.Errorf("weight of endpoint %s must be between 0 and 100", m.Endpoint)
+		}
+		members = append(members, &mysqlModel.FederationMember{
+			Federation: name,
+			Endpoint:   m.Endpoint,
+			Region:     m.Region,
+			Priority:   m.Priority,
+			Weight:     weight,
+			Enabled:    enabled,
+		})
+	}
+
+	fed := &mysqlModel.FederatedEndpoint{
+		Name:        name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+	}
+	if fed.DisplayName == "" {
+		fed.DisplayName = name
+	}
+	if err := s.repo.Save(ctx, fed, members); err != nil {
+		return nil, err
+	}
+	return s.GetFederation(ctx, name)
+}
+
+// DeleteFederation removes a federated endpoint; the backing endpoints are kept
+func (s *FederationService) DeleteFederation(ctx context.Context, name string) error {
+	if _, err := s.GetFederation(ctx, name); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, name)
+}
+
+// UpdateRegionWeights overrides the traffic weight of every member in the given regions
+func (s *FederationService) UpdateRegionWeights(ctx context.Context, name string, req *UpdateRegionWeightsRequest) (*FederationDetail, error) {
+	detail, err := s.GetFederation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	regions := make(map[string]bool, len(detail.Members))
+	for _, m := range detail.Members {
+		regions[m.Region] = true
+	}
+	for region, weight := range req.Weights {
+		if !regions[region] {
+			return nil, fmt.Errorf("federated endpoint %s has no member in region %s", name, region)
+		}
+		if weight < 0 || weight > 100 {
+			return nil, fmt.Errorf("weight of region %s must be between 0 and 100", region)
+		}
+	}
+	if err := s.repo.UpdateRegionWeights(ctx, name, req.Weights); err != nil {
+		return nil, err
+	}
+	logger.InfoCtx(ctx, "federated endpoint %s region weights updated: %v", name, req.Weights)
+	return s.GetFederation(ctx, name)
+}
+
+// Route picks the member a task submitted to a federated endpoint should go to, using
+// the client hints (or the region of clientIP when no region is hinted).
+// Returns nil when name is not a federated endpoint.
+func (s *FederationService) Route(ctx context.Context, name string, routing *model.RoutingHints, clientIP string) (*FederationRoute, error) {
+	fed, err := s.repo.Get(ctx, name)
+	if err != nil || fed == nil {
+		return nil, err
+	}
+	candidates, err := s.candidates(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	hints := s.hints(routing, clientIP)
+	target, err := federation.Pick(candidates, hints, s.rnd)
+	if err != nil {
+		return nil, fmt.Errorf("federated endpoint %s: %w", name, err)
+	}
+	logger.DebugCtx(ctx, "federated endpoint %s routed to %s (region %s, tier %s, hinted region %q)",
+		name, target.Endpoint, target.Region, target.TierName, hints.Region)
+	return &FederationRoute{Endpoint: target.Endpoint, Region: target.Region}, nil
+}
+
+// hints merges client-provided hints with the region derived from the client IP
+func (s *FederationService) hints(routing *model.RoutingHints, clientIP string) *federation.Hints {
+	hints := &federation.Hints{}
+	if routing != nil {
+		hints.Region = routing.Region
+		hints.LatencyMs = routing.LatencyMs
+	}
+	if hints.Region == "" && clientIP != "" {
+		hints.Region = s.sourceRegions.Resolve(clientIP)
+	}
+	return hints
+}
+
+// GetFederationStatus returns the members in routing order for a client in the given region
+// (empty = no hint) with their health and queue depth
+func (s *FederationService) GetFederationStatus(ctx context.Context, name, region string) (*FederationStatus, error) {
+	if _, err := s.GetFederation(ctx, name); err != nil {
+		return nil, err
+	}
+	candidates, err := s.candidates(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	hints := &federation.Hints{Region: region}
+	status := &FederationStatus{Name: name, Members: federation.Rank(candidates, hints)}
+	if len(status.Members) > 0 && status.Members[0].Tier != federation.TierUnavailable {
+		status.Target = status.Members[0].Endpoint
+		status.Region = status.Members[0].Region
+	}
+	return status, nil
+}
+
+// candidates loads the routing state of every member; deleted endpoints are unavailable
+func (s *FederationService) candidates(ctx context.Context, name string) ([]*federation.Candidate, error) {
+	members, err := s.repo.ListMembers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]*federation.Candidate, 0, len(members))
+	for _, m := range members {
+		c := &federation.Candidate{
+			Endpoint: m.Endpoint,
+			Region:   m.Region,
+			Priority: m.Priority,
+			Weight:   m.Weight,
+			Enabled:  m.Enabled,
+		}
+		meta, err := s.endpointService.GetEndpoint(ctx, m.Endpoint)
+		if err != nil {
+			logger.WarnCtx(ctx, "federated endpoint %s: failed to load member %s, skipping: %v", name, m.Endpoint, err)
+			c.Enabled = false
+		} else if meta == nil {
+			c.Enabled = false
+		} else {
+			c.HealthStatus = meta.HealthStatus
+			c.ReadyReplicas = meta.ReadyReplicas
+			c.MaxReplicas = meta.MaxReplicas
+		}
+		if c.Enabled && s.taskRepo != nil {
+			if pending, err := s.taskRepo.CountByEndpointAndStatus(ctx, m.Endpoint, constants.TaskStatusPending.String()); err == nil {
+				c.PendingTasks = pending
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+// GetFederationUsage sums the daily GPU usage of the members over [from, to), per region
+func (s *FederationService) GetFederationUsage(ctx context.Context, name string, from, to time.Time) (*FederationUsage, error) {
+	detail, err := s.GetFederation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if s.gpuUsageService == nil {
+		return nil, fmt.Errorf("GPU usage statistics are not available")
+	}
+
+	usage := &FederationUsage{
+		Name:     name,
+		From:     from,
+		To:       to,
+		ByRegion: make(map[string]*FederationRegionUsage),
+	}
+	loc := s.gpuUsageService.ReportingLocation()
+	for _, m := range detail.Members {
+		stats, err := s.gpuUsageService.GetDailyStats(ctx, mysqlModel.GPUUsageScopeEndpoint, m.Endpoint, from, to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GPU usage of %s: %w", m.Endpoint, err)
+		}
+		region := usage.ByRegion[m.Region]
+		if region == nil {
+			region = &FederationRegionUsage{}
+			usage.ByRegion[m.Region] = region
+		}
+		region.Endpoints = append(region.Endpoints, m.Endpoint)
+		for _, day := range stats {
+			region.TotalTasks += day.TotalTasks
+			region.CompletedTasks += day.CompletedTasks
+			region.FailedTasks += day.FailedTasks
+			region.TotalGPUHours += day.TotalGPUHours
+		}
+	}
+	for _, region := range usage.ByRegion {
+		usage.TotalTasks += region.TotalTasks
+		usage.CompletedTasks += region.CompletedTasks
+		usage.FailedTasks += region.FailedTasks
+		usage.TotalGPUHours += region.TotalGPUHours
+	}
+	return usage, nil
+}
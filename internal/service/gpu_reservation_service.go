@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// CreateGPUReservationRequest reserves capacity for an endpoint during a time window
+type CreateGPUReservationRequest struct {
+	Name        string    `json:"name" binding:"required"`
+	Endpoint    string    `json:"endpoint" binding:"required"`
+	SpecName    string    `json:"specName,omitempty"` // Defaults to the endpoint's spec
+	Replicas    int       `json:"replicas" binding:"required"`
+	StartTime   time.Time `json:"startTime" binding:"required"`
+	EndTime     time.Time `json:"endTime" binding:"required"`
+	Priority    *int      `json:"priority,omitempty"` // Defaults to the endpoint's priority
+	Description string    `json:"description,omitempty"`
+}
+
+// GPUReservationCalendarDay lists the reservations running on one day
+type GPUReservationCalendarDay struct {
+	Date         string                  `json:"date"` // YYYY-MM-DD in the calendar's time zone
+	ReservedGPUs int                     `json:"reservedGpus"`
+	Reservations []*model.GPUReservation `json:"reservations"`
+}
+
+// GPUReservationCalendar is a day-by-day view of reservations over a range
+type GPUReservationCalendar struct {
+	From        time.Time                    `json:"from"`
+	To          time.Time                    `json:"to"`
+	MaxGPUCount int                          `json:"maxGpuCount,omitempty"` // Cluster GPU limit (0 = unlimited)
+	Days        []*GPUReservationCalendarDay `json:"days"`
+}
+
+// GPUReservationService manages time-boxed GPU reservations. While a reservation runs the
+// autoscaler keeps its unclaimed GPUs free from lower-priority scale-ups; Reconcile converts it
+// into replicas at start time (raising the endpoint's min replicas for the window) and restores
+// the min replicas when the window ends.
+type GPUReservationService struct {
+	repo            *mysql.GPUReservationRepository
+	endpointService *endpointsvc.Service
+	deployProvider  interfaces.DeploymentProvider
+	maxGPUCount     int // Cluster GPU limit used to reject overbooking (0 = unlimited)
+}
+
+// NewGPUReservationService creates a new GPU reservation service
+func NewGPUReservationService(repo *mysql.GPUReservationRepository, endpointService *endpointsvc.Service, deployProvider interfaces.DeploymentProvider, maxGPUCount int) *GPUReservationService {
+	return &GPUReservationService{
+		repo:            repo,
+		endpointService: endpointService,
+		deployProvider:  deployProvider,
+		maxGPUCount:     maxGPUCount,
+	}
+}
+
+// CreateReservation validates and stores a reservation. Reservations overlapping in time may
+// not reserve more GPUs together than the cluster has.
+func (s *GPUReservationService) CreateReservation(ctx context.Context, req *CreateGPUReservationRequest, createdBy string) (*model.GPUReservation, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("reservation name is required")
+	}
+	if req.Replicas <= 0 {
+		return nil, fmt.Errorf("replicas must be > 0")
+	}
+	if !req.EndTime.After(req.StartTime) {
+		return nil, fmt.Errorf("endTime must be after startTime")
+	}
+	if !req.EndTime.After(time.Now()) {
+		return nil, fmt.Errorf("reservation window is already over")
+	}
+	if existing, err := s.repo.Get(ctx, req.Name); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("reservation %s already exists", req.Name)
+	}
+
+	meta, err := s.endpointService.GetEndpoint(ctx, req.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil || meta.Status == "deleted" {
+		return nil, fmt.Errorf("endpoint %s not found", req.Endpoint)
+	}
+	specName := req.SpecName
+	if specName == "" {
+		specName = meta.SpecName
+	}
+	gpusPerReplica, err := s.gpusPerReplica(ctx, specName)
+	if err != nil {
+		return nil, err
+	}
+	priority := meta.Priority
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+
+	reservation := &model.GPUReservation{
+		Name:        req.Name,
+		Endpoint:    req.Endpoint,
+		SpecName:    specName,
+		Replicas:    req.Replicas,
+		GPUCount:    req.Replicas * gpusPerReplica,
+		Priority:    priority,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Status:      model.GPUReservationScheduled,
+		Description: req.Description,
+		CreatedBy:   createdBy,
+	}
+	if err := s.checkOverbooking(ctx, reservation); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, reservation); err != nil {
+		return nil, err
+	}
+	logger.InfoCtx(ctx, "GPU reservation %s created: endpoint=%s, replicas=%d, gpus=%d, window=%s..%s",
+		reservation.Name, reservation.Endpoint, reservation.Replicas, reservation.GPUCount,
+		reservation.StartTime.Format(time.RFC3339), reservation.EndTime.Format(time.RFC3339))
+	return reservation, nil
+}
+
+// gpusPerReplica returns the GPUs one replica of a spec needs
+func (s *GPUReservationService) gpusPerReplica(ctx context.Context, specName string) (int, error) {
+	if s.deployProvider == nil {
+		return 0, fmt.Errorf("deployment provider not available")
+	}
+	spec, err := s.deployProvider.GetSpec(ctx, specName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get spec %s: %w", specName, err)
+	}
+	if spec.Category != "gpu" || spec.Resources.GPU == "" {
+		return 0, fmt.Errorf("spec %s is not a GPU spec", specName)
+	}
+	gpus, err := strconv.Atoi(spec.Resources.GPU)
+	if err != nil || gpus <= 0 {
+		return 0, fmt.Errorf("invalid GPU count %q of spec %s", spec.Resources.GPU, specName)
+	}
+	return gpus, nil
+}
+
+// checkOverbooking rejects a reservation that, together with the reservations running at the
+// same time, needs more GPUs than the cluster has. Overlaps are checked at every start time
+// within the window, where the reserved total peaks.
+func (s *GPUReservationService) checkOverbooking(ctx context.Context, reservation *model.GPUReservation) error {
+	if s.maxGPUCount <= 0 {
+		return nil
+	}
+	overlapping, err := s.repo.ListOverlapping(ctx, reservation.StartTime, reservation.EndTime, model.GPUReservationScheduled, model.GPUReservationActive)
+	if err != nil {
+		return err
+	}
+
+	checkpoints := []time.Time{reservation.StartTime}
+	for _, r := range overlapping {
+		if r.StartTime.After(reservation.StartTime) {
+			checkpoints = append(checkpoints, r.StartTime)
+		}
+	}
+	for _, t := range checkpoints {
+		total := reservation.GPUCount
+		for _, r := range overlapping {
+			if !t.Before(r.StartTime) && t.Before(r.EndTime) {
+				total += r.GPUCount
+			}
+		}
+		if total > s.maxGPUCount {
+			return fmt.Errorf("reservation needs %d GPUs at %s but only %d of %d are unreserved",
+				reservation.GPUCount, t.Format(time.RFC3339), s.maxGPUCount-(total-reservation.GPUCount), s.maxGPUCount)
+		}
+	}
+	return nil
+}
+
+// GetReservation returns a reservation, or an error if it does not exist
+func (s *GPUReservationService) GetReservation(ctx context.Context, name string) (*model.GPUReservation, error) {
+	reservation, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, fmt.Errorf("reservation %s not found", name)
+	}
+	return reservation, nil
+}
+
+// ListReservations returns the reservations overlapping [from, to), in any status
+func (s *GPUReservationService) ListReservations(ctx context.Context, from, to time.Time) ([]*model.GPUReservation, error) {
+	return s.repo.ListOverlapping(ctx, from, to)
+}
+
+// CancelReservation cancels a scheduled or running reservation; a running one gives the
+// endpoint its previous min replicas back (replicas are left to the autoscaler)
+func (s *GPUReservationService) CancelReservation(ctx context.Context, name string) (*model.GPUReservation, error) {
+	reservation, err := s.GetReservation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	switch reservation.Status {
+	case model.GPUReservationScheduled:
+	case model.GPUReservationActive:
+		if err := s.restoreMinReplicas(ctx, reservation); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("reservation %s is already %s", name, reservation.Status)
+	}
+
+	ok, err := s.repo.UpdateStatus(ctx, reservation.ID, reservation.Status, model.GPUReservationCancelled, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("reservation %s changed concurrently, retry", name)
+	}
+	logger.InfoCtx(ctx, "GPU reservation %s cancelled", name)
+	return s.repo.Get(ctx, name)
+}
+
+// GetCalendar returns the reservations (scheduled, active and completed) running on each day
+// of [from, to) in loc, with the GPUs reserved at the busiest moment of the day
+func (s *GPUReservationService) GetCalendar(ctx context.Context, from, to time.Time, loc *time.Location) (*GPUReservationCalendar, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("end_time must be after start_time")
+	}
+	reservations, err := s.repo.ListOverlapping(ctx, from, to, model.GPUReservationScheduled, model.GPUReservationActive, model.GPUReservationCompleted)
+	if err != nil {
+		return nil, err
+	}
+	return buildReservationCalendar(reservations, from, to, loc, s.maxGPUCount), nil
+}
+
+// buildReservationCalendar groups reservations by the days of [from, to) they run on
+func buildReservationCalendar(reservations []*model.GPUReservation, from, to time.Time, loc *time.Location, maxGPUCount int) *GPUReservationCalendar {
+	calendar := &GPUReservationCalendar{From: from, To: to, MaxGPUCount: maxGPUCount, Days: make([]*GPUReservationCalendarDay, 0)}
+
+	from = from.In(loc)
+	for dayStart := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc); dayStart.Before(to); dayStart = dayStart.AddDate(0, 0, 1) {
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		day := &GPUReservationCalendarDay{Date: dayStart.Format("2006-01-02"), Reservations: make([]*model.GPUReservation, 0)}
+		for _, r := range reservations {
+			if r.StartTime.Before(dayEnd) && r.EndTime.After(dayStart) {
+				day.Reservations = append(day.Reservations, r)
+			}
+		}
+
+		// Peak of the reserved GPUs: the total only grows at a start time
+		for _, candidate := range day.Reservations {
+			at := candidate.StartTime
+			if at.Before(dayStart) {
+				at = dayStart
+			}
+			total := 0
+			for _, r := range day.Reservations {
+				if !at.Before(r.StartTime) && at.Before(r.EndTime) {
+					total += r.GPUCount
+				}
+			}
+			day.ReservedGPUs = max(day.ReservedGPUs, total)
+		}
+		calendar.Days = append(calendar.Days, day)
+	}
+	return calendar
+}
+
+// Reconcile starts reservations whose window began and completes those whose window ended.
+// Starting raises the endpoint to the reserved replicas and holds them with the min replicas.
+func (s *GPUReservationService) Reconcile(ctx context.Context) error {
+	reservations, err := s.repo.ListByStatus(ctx, model.GPUReservationScheduled, model.GPUReservationActive)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, r := range reservations {
+		var err error
+		switch {
+		case !now.Before(r.EndTime):
+			err = s.complete(ctx, r)
+		case r.Status == model.GPUReservationScheduled && !now.Before(r.StartTime):
+			err = s.activate(ctx, r)
+		}
+		if err != nil {
+			logger.ErrorCtx(ctx, "GPU reservation %s: %v", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// activate converts a reservation into replicas of its endpoint
+func (s *GPUReservationService) activate(ctx context.Context, r *model.GPUReservation) error {
+	meta, err := s.endpointService.GetEndpoint(ctx, r.Endpoint)
+	if err != nil {
+		return err
+	}
+	if meta == nil || meta.Status == "deleted" {
+		_, err := s.repo.UpdateStatus(ctx, r.ID, r.Status, model.GPUReservationCancelled, nil)
+		logger.WarnCtx(ctx, "GPU reservation %s cancelled, endpoint %s no longer exists", r.Name, r.Endpoint)
+		return err
+	}
+
+	now := time.Now()
+	ok, err := s.repo.UpdateStatus(ctx, r.ID, model.GPUReservationScheduled, model.GPUReservationActive, map[string]interface{}{
+		"previous_min_replicas": meta.MinReplicas,
+		"activated_at":          now,
+	})
+	if err != nil || !ok {
+		return err
+	}
+
+	if meta.MinReplicas < r.Replicas {
+		meta.MinReplicas = r.Replicas
+		if meta.MaxReplicas > 0 && meta.MaxReplicas < r.Replicas {
+			meta.MaxReplicas = r.Replicas
+		}
+		if err := s.endpointService.UpdateEndpoint(ctx, meta); err != nil {
+			return fmt.Errorf("failed to raise min replicas of %s: %w", r.Endpoint, err)
+		}
+	}
+	if delta := r.Replicas - meta.Replicas; delta > 0 {
+		if err := s.endpointService.ScaleUp(ctx, r.Endpoint, delta); err != nil {
+			return fmt.Errorf("failed to scale %s to the reserved %d replicas: %w", r.Endpoint, r.Replicas, err)
+		}
+	}
+	logger.InfoCtx(ctx, "GPU reservation %s started: endpoint %s held at >= %d replicas until %s",
+		r.Name, r.Endpoint, r.Replicas, r.EndTime.Format(time.RFC3339))
+	return nil
+}
+
+// complete ends a reservation whose window is over
+func (s *GPUReservationService) complete(ctx context.Context, r *model.GPUReservation) error {
+	if r.Status == model.GPUReservationActive {
+		if err := s.restoreMinReplicas(ctx, r); err != nil {
+			return err
+		}
+	}
+	if _, err := s.repo.UpdateStatus(ctx, r.ID, r.Status, model.GPUReservationCompleted, nil); err != nil {
+		return err
+	}
+	logger.InfoCtx(ctx, "GPU reservation %s completed", r.Name)
+	return nil
+}
+
+// restoreMinReplicas gives the endpoint of a running reservation its previous min replicas,
+// unless they were changed during the window
+func (s *GPUReservationService) restoreMinReplicas(ctx context.Context, r *model.GPUReservation) error {
+	meta, err := s.endpointService.GetEndpoint(ctx, r.Endpoint)
+	if err != nil {
+		return err
+	}
+	if meta == nil || meta.MinReplicas != r.Replicas || r.PreviousMinReplicas >= r.Replicas {
+		return nil
+	}
+	meta.MinReplicas = r.PreviousMinReplicas
+	if err := s.endpointService.UpdateEndpoint(ctx, meta); err != nil {
+		return fmt.Errorf("failed to restore min replicas of %s: %w", r.Endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/gputier"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+// gpuTiersTTL is how long the tiers of an endpoint are cached for task submission, i.e. how
+// long a tier change made on another replica takes to apply
+const gpuTiersTTL = 10 * time.Second
+
+// GPUTierRequest is one tier of a heterogeneous endpoint
+type GPUTierRequest struct {
+	Endpoint      string   `json:"endpoint" binding:"required"` // Endpoint running the same image on another spec
+	MaxInputBytes int      `json:"maxInputBytes,omitempty"`     // Largest accepted input (JSON encoded), 0 = any size
+	Precisions    []string `json:"precisions,omitempty"`        // Accepted requested precisions (routing.precision), empty = any
+}
+
+// SaveGPUTiersRequest replaces the tiers of a heterogeneous endpoint; tiers are tried in order
+type SaveGPUTiersRequest struct {
+	Tiers []*GPUTierRequest `json:"tiers" binding:"required,min=1,dive"`
+}
+
+// GPUTierInfo is a tier with the spec it runs on
+type GPUTierInfo struct {
+	*mysqlModel.GPUTier
+	SpecName string `json:"spec_name"`
+}
+
+// GPUTierDetail is a heterogeneous endpoint with its tiers in routing order
+type GPUTierDetail struct {
+	Endpoint string         `json:"endpoint"`
+	SpecName string         `json:"spec_name"` // Spec of the tasks no tier accepts
+	Tiers    []*GPUTierInfo `json:"tiers"`
+}
+
+type cachedGPUTiers struct {
+	tiers    []gputier.Tier
+	loadedAt time.Time
+}
+
+// GPUTierService manages heterogeneous endpoints: an endpoint that hands tasks its tier
+// endpoints (the same image on cheaper specs) accept to them, so cheap requests never occupy
+// expensive GPUs. Each tier is a regular endpoint with its own deployment, queue and autoscaling.
+type GPUTierService struct {
+	repo            *mysql.GPUTierRepository
+	endpointService *endpointsvc.Service
+
+	mu    sync.Mutex
+	cache map[string]cachedGPUTiers // By endpoint
+}
+
+// NewGPUTierService creates a new GPU tier service
+func NewGPUTierService(repo *mysql.GPUTierRepository, endpointService *endpointsvc.Service) *GPUTierService {
+	return &GPUTierService{
+		repo:            repo,
+		endpointService: endpointService,
+		cache:           make(map[string]cachedGPUTiers),
+	}
+}
+
+// GetTiers returns the tiers of an endpoint
+func (s *GPUTierService) GetTiers(ctx context.Context, endpoint string) (*GPUTierDetail, error) {
+	ep, err := s.getEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	tiers, err := s.repo.ListByEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	detail := &GPUTierDetail{Endpoint: endpoint, SpecName: ep.SpecName, Tiers: make([]*GPUTierInfo, 0, len(tiers))}
+	for _, t := range tiers {
+		info := &GPUTierInfo{GPUTier: t}
+		if tierEp, err := s.endpointService.GetEndpointOnly(ctx, t.TierEndpoint); err == nil && tierEp != nil {
+			info.SpecName = tierEp.SpecName
+		}
+		detail.Tiers = append(detail.Tiers, info)
+	}
+	return detail, nil
+}
+
+// SaveTiers replaces the tiers of an endpoint. Tiers must run the endpoint's image on another
+// spec and have at least one rule; tiers cannot have tiers of their own.
+func (s *GPUTierService) SaveTiers(ctx context.Context, endpoint string, req *SaveGPUTiersRequest) (*GPUTierDetail, error) {
+	ep, err := s.getEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if parents, err := s.repo.ListParents(ctx, endpoint); err != nil {
+		return nil, err
+	} else if len(parents) > 0 {
+		return nil, fmt.Errorf("invalid tiers: endpoint %s is a GPU tier of %s", endpoint, strings.Join(parents, ", "))
+	}
+
+	tiers := make([]*mysqlModel.GPUTier, 0, len(req.Tiers))
+	seen := make(map[string]bool, len(req.Tiers))
+	for i, t := range req.Tiers {
+		if t.Endpoint == endpoint {
+			return nil, fmt.Errorf("invalid tiers: endpoint %s cannot be its own tier", endpoint)
+		}
+		if seen[t.Endpoint] {
+			return nil, fmt.Errorf("invalid tiers: endpoint %s is listed more than once", t.Endpoint)
+		}
+		seen[t.Endpoint] = true
+		if t.MaxInputBytes < 0 {
+			return nil, fmt.Errorf("invalid tiers: maxInputBytes of %s must be >= 0", t.Endpoint)
+		}
+		precisions := make([]string, 0, len(t.Precisions))
+		for _, p := range t.Precisions {
+			if p = gputier.NormalizePrecision(p); p != "" {
+				precisions = append(precisions, p)
+			}
+		}
+		// A tier without rules would take every task and leave the endpoint idle
+		if t.MaxInputBytes == 0 && len(precisions) == 0 {
+			return nil, fmt.Errorf("invalid tiers: tier %s needs maxInputBytes or precisions", t.Endpoint)
+		}
+
+		tierEp, err := s.getEndpoint(ctx, t.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if tierEp.Image != ep.Image {
+			return nil, fmt.Errorf("invalid tiers: tier %s runs image %s, endpoint %s runs %s", t.Endpoint, tierEp.Image, endpoint, ep.Image)
+		}
+		if tierEp.SpecName == ep.SpecName {
+			return nil, fmt.Errorf("invalid tiers: tier %s runs the same spec %s as endpoint %s", t.Endpoint, ep.SpecName, endpoint)
+		}
+		if own, err := s.repo.ListByEndpoint(ctx, t.Endpoint); err != nil {
+			return nil, err
+		} else if len(own) > 0 {
+			return nil, fmt.Errorf("invalid tiers: tier %s has GPU tiers of its own", t.Endpoint)
+		}
+
+		tiers = append(tiers, &mysqlModel.GPUTier{
+			Endpoint:      endpoint,
+			TierEndpoint:  t.Endpoint,
+			Position:      i,
+			MaxInputBytes: t.MaxInputBytes,
+			Precisions:    precisions,
+		})
+	}
+
+	if err := s.repo.Replace(ctx, endpoint, tiers); err != nil {
+		return nil, err
+	}
+	s.forget(endpoint)
+	return s.GetTiers(ctx, endpoint)
+}
+
+// DeleteTiers removes the tiers of an endpoint; the tier endpoints are kept
+func (s *GPUTierService) DeleteTiers(ctx context.Context, endpoint string) error {
+	if _, err := s.getEndpoint(ctx, endpoint); err != nil {
+		return err
+	}
+	if err := s.repo.Replace(ctx, endpoint, nil); err != nil {
+		return err
+	}
+	s.forget(endpoint)
+	return nil
+}
+
+// Route returns the tier endpoint a task submitted to endpoint goes to, nil when it stays on
+// the endpoint. Failed lookups keep the task on the endpoint: it can run any task, only at a
+// higher cost.
+func (s *GPUTierService) Route(ctx context.Context, endpoint *mysql.Endpoint, input map[string]interface{}, precision string) *mysql.Endpoint {
+	tiers := s.loadTiers(ctx, endpoint.Endpoint)
+	if len(tiers) == 0 {
+		return nil
+	}
+	task := gputier.Task{Precision: precision}
+	if gputier.NeedsInputSize(tiers) {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return nil
+		}
+		task.InputBytes = len(data)
+	}
+	tier, ok := gputier.Route(tiers, task)
+	if !ok {
+		return nil
+	}
+
+	tierEp, err := s.endpointService.GetEndpointOnly(ctx, tier.Endpoint)
+	if err != nil || tierEp == nil || tierEp.Status == "deleted" {
+		logger.WarnCtx(ctx, "GPU tier %s of endpoint %s is unavailable, keeping task on the endpoint, error: %v", tier.Endpoint, endpoint.Endpoint, err)
+		return nil
+	}
+	return tierEp
+}
+
+// loadTiers returns the cached routing tiers of an endpoint. A failed load caches no tiers so
+// submissions don't hammer the database.
+func (s *GPUTierService) loadTiers(ctx context.Context, endpoint string) []gputier.Tier {
+	s.mu.Lock()
+	cached, ok := s.cache[endpoint]
+	s.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < gpuTiersTTL {
+		return cached.tiers
+	}
+
+	rows, err := s.repo.ListByEndpoint(ctx, endpoint)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load GPU tiers, endpoint: %s, error: %v", endpoint, err)
+	}
+	tiers := make([]gputier.Tier, 0, len(rows))
+	for _, t := range rows {
+		tiers = append(tiers, gputier.Tier{Endpoint: t.TierEndpoint, MaxInputBytes: t.MaxInputBytes, Precisions: t.Precisions})
+	}
+	s.mu.Lock()
+	s.cache[endpoint] = cachedGPUTiers{tiers: tiers, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return tiers
+}
+
+func (s *GPUTierService) forget(endpoint string) {
+	s.mu.Lock()
+	delete(s.cache, endpoint)
+	s.mu.Unlock()
+}
+
+func (s *GPUTierService) getEndpoint(ctx context.Context, name string) (*mysql.Endpoint, error) {
+	ep, err := s.endpointService.GetEndpointOnly(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if ep == nil || ep.Status == "deleted" {
+		return nil, fmt.Errorf("endpoint %s not found", name)
+	}
+	return ep, nil
+}
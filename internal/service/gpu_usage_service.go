@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/gpuusage"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// GPUUsageService records task GPU usage and serves aggregated GPU usage statistics
+type GPUUsageService struct {
+	repo            *mysql.GPUUsageRepository
+	endpointRepo    *mysql.EndpointRepository
+	agg             *gpuusage.Aggregator
+	reporting       config.ReportingConfig
+	hourlyRetention time.Duration // Local-day reports cannot reach back further
+}
+
+// NewGPUUsageService creates a new GPU usage service.
+// reporting sets the timezones of query windows, by default and per project.
+func NewGPUUsageService(repo *mysql.Repository, reporting config.ReportingConfig) *GPUUsageService {
+	aggConfig := gpuusage.DefaultConfig()
+	agg := gpuusage.NewAggregator(repo.GPUUsage, repo.Endpoint, repo.Spec, aggConfig)
+	agg.SetWorkerRepository(repo.Worker)
+	return &GPUUsageService{
+		repo:            repo.GPUUsage,
+		endpointRepo:    repo.Endpoint,
+		agg:             agg,
+		reporting:       reporting,
+		hourlyRetention: aggConfig.HourlyRetention,
+	}
+}
+
+// ReportingLocation returns the default reporting timezone
+func (s *GPUUsageService) ReportingLocation() *time.Location {
+	return s.reporting.Location()
+}
+
+// ReportingLocationFor returns the reporting timezone of a scope: the timezone of the project
+// an endpoint belongs to for endpoint scopes, the default reporting timezone otherwise
+func (s *GPUUsageService) ReportingLocationFor(ctx context.Context, scopeType, scopeValue string) (*time.Location, error) {
+	if scopeType != model.GPUUsageScopeEndpoint || len(s.reporting.ProjectTimezones) == 0 || s.endpointRepo == nil {
+		return s.reporting.Location(), nil
+	}
+	ep, err := s.endpointRepo.Get(ctx, scopeValue)
+	if err != nil {
+		return nil, err
+	}
+	if ep == nil {
+		return s.reporting.Location(), nil
+	}
+	project, _ := ep.Labels[s.reporting.ProjectLabel].(string)
+	return s.reporting.ProjectLocation(project), nil
+}
+
+// RecordTaskUsage records GPU usage for a finished task, errors are logged only
+func (s *GPUUsageService) RecordTaskUsage(ctx context.Context, task *model.Task) {
+	if _, err := s.agg.RecordTask(ctx, task); err != nil {
+		logger.WarnCtx(ctx, "failed to record gpu usage for task %s: %v", task.TaskID, err)
+	}
+}
+
+// AggregatePending advances minute/hourly/daily aggregation up to the latest complete bucket
+func (s *GPUUsageService) AggregatePending(ctx context.Context) error {
+	return s.agg.AggregatePending(ctx)
+}
+
+// Reaggregate recomputes statistics for buckets in [from, to)
+func (s *GPUUsageService) Reaggregate(ctx context.Context, granularity string, from, to time.Time) (*gpuusage.ReaggregateResult, error) {
+	return s.agg.Reaggregate(ctx, granularity, from, to)
+}
+
+// Backfill creates missing GPU usage records for tasks in [from, to) and re-aggregates them
+func (s *GPUUsageService) Backfill(ctx context.Context, from, to time.Time, batchSize, maxTasks int) (*gpuusage.BackfillResult, error) {
+	return s.agg.Backfill(ctx, from, to, batchSize, maxTasks)
+}
+
+// CreateBackfillJob starts a resumable backfill job for tasks in [from, to)
+func (s *GPUUsageService) CreateBackfillJob(ctx context.Context, from, to time.Time, batchSize, rateLimit int, createdBy string) (*gpuusage.BackfillJobView, error) {
+	job, err := s.agg.CreateBackfillJob(ctx, from, to, batchSize, rateLimit, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	return gpuusage.NewBackfillJobView(job), nil
+}
+
+// GetBackfillJob returns a backfill job with its progress
+func (s *GPUUsageService) GetBackfillJob(ctx context.Context, id int64) (*gpuusage.BackfillJobView, error) {
+	job, err := s.repo.GetBackfillJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("backfill job %d not found", id)
+	}
+	return gpuusage.NewBackfillJobView(job), nil
+}
+
+// ListBackfillJobs returns the most recent backfill jobs with their progress, optionally
+// filtered by status
+func (s *GPUUsageService) ListBackfillJobs(ctx context.Context, status string, limit, offset int) ([]*gpuusage.BackfillJobView, error) {
+	jobs, err := s.repo.ListBackfillJobs(ctx, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*gpuusage.BackfillJobView, 0, len(jobs))
+	for _, job := range jobs {
+		views = append(views, gpuusage.NewBackfillJobView(job))
+	}
+	return views, nil
+}
+
+// CountBackfillJobs returns the number of backfill jobs, optionally filtered by status
+func (s *GPUUsageService) CountBackfillJobs(ctx context.Context, status string) (int64, error) {
+	return s.repo.CountBackfillJobs(ctx, status)
+}
+
+// CancelBackfillJob cancels a pending or running backfill job. Records already created are kept.
+func (s *GPUUsageService) CancelBackfillJob(ctx context.Context, id int64) (*gpuusage.BackfillJobView, error) {
+	cancelled, err := s.repo.CancelBackfillJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	view, err := s.GetBackfillJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("backfill job %d is %s and cannot be cancelled", id, view.Status)
+	}
+	logger.InfoCtx(ctx, "gpu usage backfill job %d cancelled", id)
+	return view, nil
+}
+
+// RunBackfillJob advances the active backfill job for at most budget
+func (s *GPUUsageService) RunBackfillJob(ctx context.Context, budget time.Duration) error {
+	job, err := s.agg.RunBackfillJob(ctx, budget)
+	if errors.Is(err, gpuusage.ErrBackfillInProgress) {
+		logger.DebugCtx(ctx, "gpu usage backfill job skipped: a backfill is running on this instance")
+		return nil
+	}
+	if job != nil && err == nil && job.Status == model.GPUUsageBackfillRunning {
+		view := gpuusage.NewBackfillJobView(job)
+		logger.InfoCtx(ctx, "gpu usage backfill job %d: phase=%s, records=%.1f%%, reaggregate=%.1f%%",
+			job.ID, job.Phase, view.RecordsPercent, view.ReaggregatePercent)
+	}
+	return err
+}
+
+// GetAggregationStatus returns aggregation watermarks and lag
+func (s *GPUUsageService) GetAggregationStatus(ctx context.Context) (*gpuusage.AggregationStatus, error) {
+	return s.agg.Status(ctx)
+}
+
+// GetMinuteStats returns minute statistics in [from, to), timestamps presented in loc
+func (s *GPUUsageService) GetMinuteStats(ctx context.Context, scopeType, scopeValue string, from, to time.Time, loc *time.Location) ([]*model.GPUUsageStatisticsMinute, error) {
+	stats, err := s.repo.GetMinuteStats(ctx, scopeType, scopeValue, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	gpuusage.LocalizeMinuteStats(stats, loc)
+	return stats, nil
+}
+
+// GetHourlyStats returns hourly statistics in [from, to), timestamps presented in loc
+func (s *GPUUsageService) GetHourlyStats(ctx context.Context, scopeType, scopeValue string, from, to time.Time, loc *time.Location) ([]*model.GPUUsageStatisticsHourly, error) {
+	stats, err := s.repo.GetHourlyStats(ctx, scopeType, scopeValue, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	gpuusage.LocalizeHourlyStats(stats, loc)
+	return stats, nil
+}
+
+// GetDailyStats returns daily statistics in [from, to) with day boundaries in loc.
+// Stored daily buckets are UTC days, so for other timezones the local days are
+// rebuilt from hourly statistics. Days whose hours were partly cleaned up already would
+// be short, so a window reaching back to them fails with ErrBeyondHourlyRetention.
+func (s *GPUUsageService) GetDailyStats(ctx context.Context, scopeType, scopeValue string, from, to time.Time, loc *time.Location) ([]*model.GPUUsageStatisticsDaily, error) {
+	if gpuusage.IsUTC(loc) {
+		return s.repo.GetDailyStats(ctx, scopeType, scopeValue, from.UTC(), to.UTC())
+	}
+
+	from = gpuusage.StartOfDayIn(from, loc)
+	if s.hourlyRetention > 0 {
+		if first := gpuusage.FirstCompleteLocalDay(time.Now(), s.hourlyRetention, loc); from.Before(first) {
+			return nil, fmt.Errorf("%w: daily statistics in %s are rebuilt from hourly statistics, start_time must be %s or later (or use timezone=UTC)",
+				gpuusage.ErrBeyondHourlyRetention, loc, first.Format("2006-01-02"))
+		}
+	}
+	hours, err := s.repo.GetHourlyStats(ctx, scopeType, scopeValue, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	stats := gpuusage.RollupHourlyStatsInLocation(hours, loc)
+	gpuusage.LocalizeDailyStats(stats, loc)
+	return stats, nil
+}
+
+// GetTopScopes ranks endpoints, specs or workers by usage over [from, to)
+func (s *GPUUsageService) GetTopScopes(ctx context.Context, granularity, scopeType string, from, to time.Time, orderBy string, ascending bool, limit int) ([]*mysql.GPUUsageScopeRanking, error) {
+	return s.repo.GetTopScopes(ctx, granularity, scopeType, from.UTC(), to.UTC(), orderBy, ascending, limit)
+}
+
+// CleanupOldRecords removes raw GPU usage records completed before the given time
+func (s *GPUUsageService) CleanupOldRecords(ctx context.Context, before time.Time) (int64, error) {
+	return s.repo.CleanupOldRecords(ctx, before)
+}
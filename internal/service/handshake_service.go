@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"waverless/internal/model"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+// HandshakeService negotiates the worker contract at startup and compares the env and volume
+// layout a worker sees with what its endpoint is configured with. Mismatches are returned to the
+// worker and recorded as endpoint warnings, so a broken image shows up before tasks fail.
+type HandshakeService struct {
+	warningRepo     *mysql.EndpointWarningRepository
+	workerRepo      *mysql.WorkerRepository
+	endpointService *endpointsvc.Service
+	deployProvider  interfaces.DeploymentProvider // nil = volume mounts are not checked
+}
+
+// NewHandshakeService creates a new handshake service
+func NewHandshakeService(warningRepo *mysql.EndpointWarningRepository, workerRepo *mysql.WorkerRepository, endpointService *endpointsvc.Service, deployProvider interfaces.DeploymentProvider) *HandshakeService {
+	return &HandshakeService{
+		warningRepo:     warningRepo,
+		workerRepo:      workerRepo,
+		endpointService: endpointService,
+		deployProvider:  deployProvider,
+	}
+}
+
+// Handshake checks a worker's startup report against its endpoint and records the outcome
+func (s *HandshakeService) Handshake(ctx context.Context, endpoint, workerID string, req *model.HandshakeRequest) (*model.HandshakeResponse, error) {
+	meta, err := s.endpointService.GetEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endpoint: %w", err)
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("endpoint %s not found", endpoint)
+	}
+
+	var volumes []string
+	if s.deployProvider != nil {
+		app, err := s.deployProvider.GetApp(ctx, endpoint)
+		if err != nil {
+			logger.WarnCtx(ctx, "handshake: failed to get app %s, volume mounts not checked: %v", endpoint, err)
+		} else if app != nil {
+			for _, vm := range app.VolumeMounts {
+				volumes = append(volumes, vm.MountPath)
+			}
+		}
+	}
+
+	resp := CheckHandshake(req, meta.Env, volumes)
+	s.record(ctx, endpoint, workerID, req.SDKVersion, resp.Warnings)
+
+	if len(resp.Warnings) > 0 {
+		logger.WarnCtx(ctx, "worker handshake mismatch, endpoint: %s, worker_id: %s, sdk: %s %s, contract: %d, warnings: %d",
+			endpoint, workerID, req.SDK, req.SDKVersion, req.ContractVersion, len(resp.Warnings))
+	} else {
+		logger.InfoCtx(ctx, "worker handshake ok, endpoint: %s, worker_id: %s, sdk: %s %s, contract: %d",
+			endpoint, workerID, req.SDK, req.SDKVersion, req.ContractVersion)
+	}
+	return resp, nil
+}
+
+// Warnings returns the recorded warnings of an endpoint
+func (s *HandshakeService) Warnings(ctx context.Context, endpoint string) ([]*mysqlModel.EndpointWarning, error) {
+	return s.warningRepo.List(ctx, endpoint)
+}
+
+// record stores the warnings of a handshake and resolves the handshake warnings that no live
+// worker of the endpoint reports anymore. During a rollout, a healthy new worker does not clear
+// a warning an old worker still triggers.
+func (s *HandshakeService) record(ctx context.Context, endpoint, workerID, sdkVersion string, warnings []model.HandshakeWarning) {
+	now := time.Now()
+	codes := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		codes = append(codes, w.Code)
+		if err := s.warningRepo.Record(ctx, &mysqlModel.EndpointWarning{
+			Endpoint:    endpoint,
+			Code:        w.Code,
+			Source:      mysqlModel.EndpointWarningSourceHandshake,
+			Message:     w.Message,
+			Details:     mysqlModel.JSONStringArray(w.Missing),
+			WorkerID:    workerID,
+			SDKVersion:  sdkVersion,
+			Occurrences: 1,
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+		}); err != nil {
+			logger.WarnCtx(ctx, "failed to record handshake warning %s for endpoint %s: %v", w.Code, endpoint, err)
+		}
+	}
+
+	if err := s.workerRepo.UpdateHandshakeWarnings(ctx, workerID, codes); err != nil {
+		logger.WarnCtx(ctx, "failed to store handshake warnings of worker %s: %v", workerID, err)
+	}
+	workers, err := s.workerRepo.GetByEndpoint(ctx, endpoint)
+	if err != nil {
+		// Other workers may still report what this one does not; keep the warnings
+		logger.WarnCtx(ctx, "failed to list workers of endpoint %s, handshake warnings not resolved: %v", endpoint, err)
+		return
+	}
+	keep := reportedHandshakeCodes(workers, workerID, codes)
+	if n, err := s.warningRepo.Resolve(ctx, endpoint, mysqlModel.EndpointWarningSourceHandshake, keep); err != nil {
+		logger.WarnCtx(ctx, "failed to resolve handshake warnings for endpoint %s: %v", endpoint, err)
+	} else if n > 0 {
+		logger.InfoCtx(ctx, "resolved %d handshake warning(s) for endpoint %s", n, endpoint)
+	}
+}
+
+// reportedHandshakeCodes returns the warning codes of a handshake together with the codes the
+// last handshakes of the endpoint's other live workers reported
+func reportedHandshakeCodes(workers []*mysqlModel.Worker, workerID string, codes []string) []string {
+	reported := append([]string{}, codes...)
+	for _, w := range workers {
+		if w.WorkerID == workerID || w.TerminatedAt != nil || w.HandshakeWarnings == nil {
+			continue
+		}
+		var workerCodes []string
+		if err := json.Unmarshal([]byte(*w.HandshakeWarnings), &workerCodes); err != nil {
+			continue
+		}
+		reported = append(reported, workerCodes...)
+	}
+	return uniqueSorted(reported)
+}
+
+// CheckHandshake negotiates the contract version and lists the contract env vars, endpoint env
+// vars and volume mounts the worker did not report
+func CheckHandshake(req *model.HandshakeRequest, endpointEnv map[string]string, volumes []string) *model.HandshakeResponse {
+	resp := &model.HandshakeResponse{
+		Compatible:         true,
+		ContractVersion:    model.WorkerContractVersion,
+		MinContractVersion: model.MinWorkerContractVersion,
+		MaxContractVersion: model.WorkerContractVersion,
+	}
+
+	switch v := req.ContractVersion; {
+	case v < model.MinWorkerContractVersion || v > model.WorkerContractVersion:
+		resp.Compatible = false
+		resp.Warnings = append(resp.Warnings, model.HandshakeWarning{
+			Code: model.HandshakeWarningContractUnsupported,
+			Message: fmt.Sprintf("worker contract version %d is not supported (supported: %d-%d), upgrade the worker SDK",
+				v, model.MinWorkerContractVersion, model.WorkerContractVersion),
+		})
+	case v < model.WorkerContractVersion:
+		resp.ContractVersion = v
+		resp.Warnings = append(resp.Warnings, model.HandshakeWarning{
+			Code:    model.HandshakeWarningContractOutdated,
+			Message: fmt.Sprintf("worker contract version %d is older than the current version %d", v, model.WorkerContractVersion),
+		})
+	}
+
+	sdk := strings.ToLower(strings.TrimSpace(req.SDK))
+	if sdk == "" {
+		sdk = model.WorkerSDKRunPod
+	}
+	contractEnv, known := model.WorkerContractEnv[sdk]
+	if !known {
+		resp.Warnings = append(resp.Warnings, model.HandshakeWarning{
+			Code:    model.HandshakeWarningUnknownSDK,
+			Message: fmt.Sprintf("unknown worker SDK %q, contract env vars were not checked", req.SDK),
+		})
+	}
+
+	expected := append([]string{}, contractEnv...)
+	for name := range endpointEnv {
+		expected = append(expected, name)
+	}
+	resp.ExpectedEnv = uniqueSorted(expected)
+	if missing := missingFrom(resp.ExpectedEnv, req.Env); len(missing) > 0 {
+		resp.Warnings = append(resp.Warnings, model.HandshakeWarning{
+			Code:    model.HandshakeWarningMissingEnv,
+			Message: fmt.Sprintf("%d expected env var(s) not visible to the worker: %s", len(missing), strings.Join(missing, ", ")),
+			Missing: missing,
+		})
+	}
+
+	resp.ExpectedVolumes = uniqueSorted(volumes)
+	if missing := missingFrom(resp.ExpectedVolumes, req.Volumes); len(missing) > 0 {
+		resp.Warnings = append(resp.Warnings, model.HandshakeWarning{
+			Code:    model.HandshakeWarningMissingVolume,
+			Message: fmt.Sprintf("%d volume mount(s) not visible to the worker: %s", len(missing), strings.Join(missing, ", ")),
+			Missing: missing,
+		})
+	}
+	return resp
+}
+
+// missingFrom returns the expected values that are not in reported
+func missingFrom(expected, reported []string) []string {
+	have := make(map[string]bool, len(reported))
+	for _, r := range reported {
+		have[strings.TrimRight(r, "/")] = true
+	}
+	var missing []string
+	for _, e := range expected {
+		if !have[strings.TrimRight(e, "/")] {
+			missing = append(missing, e)
+		}
+	}
+	return missing
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
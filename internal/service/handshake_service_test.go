@@ -0,0 +1,129 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"waverless/internal/model"
+	mysqlModel "waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var runpodEnv = model.WorkerContractEnv[model.WorkerSDKRunPod]
+
+func warningCodes(resp *model.HandshakeResponse) []string {
+	codes := []string{}
+	for _, w := range resp.Warnings {
+		codes = append(codes, w.Code)
+	}
+	return codes
+}
+
+func TestCheckHandshake_ContractVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    int
+		compatible bool
+		negotiated int
+		codes      []string
+	}{
+		{"current", model.WorkerContractVersion, true, model.WorkerContractVersion, []string{}},
+		{"too old", model.MinWorkerContractVersion - 1, false, model.WorkerContractVersion, []string{model.HandshakeWarningContractUnsupported}},
+		{"too new", model.WorkerContractVersion + 1, false, model.WorkerContractVersion, []string{model.HandshakeWarningContractUnsupported}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := CheckHandshake(&model.HandshakeRequest{ContractVersion: tt.version, Env: runpodEnv}, nil, nil)
+			assert.Equal(t, tt.compatible, resp.Compatible)
+			assert.Equal(t, tt.negotiated, resp.ContractVersion)
+			assert.Equal(t, model.MinWorkerContractVersion, resp.MinContractVersion)
+			assert.Equal(t, model.WorkerContractVersion, resp.MaxContractVersion)
+			assert.Equal(t, tt.codes, warningCodes(resp))
+		})
+	}
+}
+
+func TestCheckHandshake_Layout(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         model.HandshakeRequest
+		endpointEnv map[string]string
+		volumes     []string
+		codes       []string
+		missing     [][]string
+	}{
+		{
+			name:  "runpod sdk by default",
+			req:   model.HandshakeRequest{ContractVersion: model.WorkerContractVersion, Env: runpodEnv},
+			codes: []string{},
+		},
+		{
+			name:  "sdk name is case-insensitive",
+			req:   model.HandshakeRequest{SDK: " Waverless ", ContractVersion: model.WorkerContractVersion, Env: model.WorkerContractEnv[model.WorkerSDKWaverless]},
+			codes: []string{},
+		},
+		{
+			name:    "missing contract env",
+			req:     model.HandshakeRequest{ContractVersion: model.WorkerContractVersion, Env: runpodEnv[1:]},
+			codes:   []string{model.HandshakeWarningMissingEnv},
+			missing: [][]string{{runpodEnv[0]}},
+		},
+		{
+			name:        "missing endpoint env",
+			req:         model.HandshakeRequest{ContractVersion: model.WorkerContractVersion, Env: append([]string{"MODEL"}, runpodEnv...)},
+			endpointEnv: map[string]string{"MODEL": "flux", "HF_TOKEN": "secret"},
+			codes:       []string{model.HandshakeWarningMissingEnv},
+			missing:     [][]string{{"HF_TOKEN"}},
+		},
+		{
+			name:        "unknown sdk checks endpoint env only",
+			req:         model.HandshakeRequest{SDK: "custom", ContractVersion: model.WorkerContractVersion, Env: []string{"MODEL"}},
+			endpointEnv: map[string]string{"MODEL": "flux"},
+			codes:       []string{model.HandshakeWarningUnknownSDK},
+		},
+		{
+			name:    "missing volume, trailing slashes ignored",
+			req:     model.HandshakeRequest{ContractVersion: model.WorkerContractVersion, Env: runpodEnv, Volumes: []string{"/models/"}},
+			volumes: []string{"/models", "/cache", "/models"},
+			codes:   []string{model.HandshakeWarningMissingVolume},
+			missing: [][]string{{"/cache"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := CheckHandshake(&tt.req, tt.endpointEnv, tt.volumes)
+			assert.Equal(t, tt.codes, warningCodes(resp))
+			for i, missing := range tt.missing {
+				assert.Equal(t, missing, resp.Warnings[i].Missing)
+			}
+			assert.Equal(t, uniqueSorted(tt.volumes), resp.ExpectedVolumes)
+		})
+	}
+}
+
+func TestCheckHandshake_ExpectedEnv(t *testing.T) {
+	resp := CheckHandshake(&model.HandshakeRequest{ContractVersion: model.WorkerContractVersion},
+		map[string]string{"RUNPOD_POD_ID": "dup", "A_MODEL": "flux"}, nil)
+	assert.Equal(t, uniqueSorted(append([]string{"A_MODEL"}, runpodEnv...)), resp.ExpectedEnv)
+}
+
+func TestReportedHandshakeCodes(t *testing.T) {
+	codes := func(s string) *string { return &s }
+	terminated := time.Now()
+	workers := []*mysqlModel.Worker{
+		{WorkerID: "new", HandshakeWarnings: codes(`["MISSING_ENV"]`)}, // Stale row of the reporting worker
+		{WorkerID: "old-bad", HandshakeWarnings: codes(`["MISSING_VOLUME","CONTRACT_OUTDATED"]`)},
+		{WorkerID: "old-ok", HandshakeWarnings: codes(`[]`)},
+		{WorkerID: "never", HandshakeWarnings: nil},
+		{WorkerID: "gone", HandshakeWarnings: codes(`["UNKNOWN_SDK"]`), TerminatedAt: &terminated},
+		{WorkerID: "corrupt", HandshakeWarnings: codes(`not json`)},
+	}
+
+	// A healthy handshake keeps what a still-running worker reports
+	assert.Equal(t, []string{"CONTRACT_OUTDATED", "MISSING_VOLUME"}, reportedHandshakeCodes(workers, "new", nil))
+	assert.Equal(t, []string{"CONTRACT_OUTDATED", "MISSING_ENV", "MISSING_VOLUME"},
+		reportedHandshakeCodes(workers, "new", []string{"MISSING_ENV", "MISSING_VOLUME"}))
+	// Once no live worker reports anything, everything resolves
+	assert.Empty(t, reportedHandshakeCodes(workers[2:], "new", nil))
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/internal/model"
+	"waverless/pkg/config"
+	"waverless/pkg/constants"
+	"waverless/pkg/hedging"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+
+	"github.com/google/uuid"
+)
+
+// hedgeSampleLimit caps the completed tasks the p99 execution time is computed over
+const hedgeSampleLimit = 1000
+
+// HedgingService duplicates slow tasks of latency-critical endpoints: a task running longer
+// than the endpoint's recent p99 execution time gets a hedge, a copy queued for a second
+// worker. The first copy to report wins (see TaskService.resolveHedge).
+type HedgingService struct {
+	cfg          config.HedgingConfig
+	taskRepo     *mysql.TaskRepository
+	workerRepo   *mysql.WorkerRepository
+	endpointRepo *mysql.EndpointRepository
+	taskService  *TaskService
+}
+
+// NewHedgingService creates a new hedging service
+func NewHedgingService(cfg config.HedgingConfig, taskRepo *mysql.TaskRepository, workerRepo *mysql.WorkerRepository, endpointRepo *mysql.EndpointRepository, taskService *TaskService) *HedgingService {
+	return &HedgingService{
+		cfg:          cfg,
+		taskRepo:     taskRepo,
+		workerRepo:   workerRepo,
+		endpointRepo: endpointRepo,
+		taskService:  taskService,
+	}
+}
+
+// Run hedges the overdue tasks of every latency-critical endpoint
+func (s *HedgingService) Run(ctx context.Context) error {
+	for _, endpoint := range s.cfg.Endpoints {
+		if _, err := s.hedgeEndpoint(ctx, endpoint); err != nil {
+			logger.WarnCtx(ctx, "failed to hedge tasks, endpoint: %s, error: %v", endpoint, err)
+		}
+	}
+	return nil
+}
+
+// hedgeEndpoint hedges the longest running overdue tasks of an endpoint, at most one per idle
+// worker and none while tasks are queued or dispatch is paused
+func (s *HedgingService) hedgeEndpoint(ctx context.Context, endpoint string) (int, error) {
+	paused, err := s.endpointRepo.IsDispatchPaused(ctx, endpoint)
+	if err != nil || paused {
+		return 0, err
+	}
+	pending, err := s.taskRepo.CountByEndpointAndStatus(ctx, endpoint, string(model.TaskStatusPending))
+	if err != nil {
+		return 0, err
+	}
+	budget := hedging.Budget(pending, s.idleWorkers(ctx, endpoint))
+	if budget == 0 {
+		return 0, nil
+	}
+
+	samples, err := s.taskRepo.RecentExecutionTimes(ctx, endpoint, time.Now().Add(-s.cfg.Lookback), hedgeSampleLimit)
+	if err != nil {
+		return 0, err
+	}
+	threshold, ok := hedging.Threshold(samples, s.cfg.MinSamples, s.cfg.Multiplier, s.cfg.MinDelay)
+	if !ok {
+		return 0, nil
+	}
+	candidates, err := s.taskRepo.ListHedgeCandidates(ctx, endpoint, time.Now().Add(-threshold), budget)
+	if err != nil {
+		return 0, err
+	}
+
+	hedged := 0
+	for _, original := range candidates {
+		hedge, err := s.taskService.hedge(ctx, original)
+		if err != nil {
+			return hedged, fmt.Errorf("task %s: %w", original.TaskID, err)
+		}
+		if hedge != nil {
+			hedged++
+			logger.InfoCtx(ctx, "task hedged, task_id: %s, hedge_id: %s, endpoint: %s, threshold: %v",
+				original.TaskID, hedge.TaskID, endpoint, threshold)
+		}
+	}
+	return hedged, nil
+}
+
+// idleWorkers counts the online workers of an endpoint without jobs
+func (s *HedgingService) idleWorkers(ctx context.Context, endpoint string) int {
+	workers, err := s.workerRepo.GetByEndpoint(ctx, endpoint)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load workers for hedging, endpoint: %s, error: %v", endpoint, err)
+		return 0
+	}
+	idle := 0
+	for _, w := range workers {
+		if w.Status == constants.WorkerStatusOnline.String() && w.CurrentJobs == 0 {
+			idle++
+		}
+	}
+	return idle
+}
+
+// Stats returns the costs and savings of hedging on an endpoint for tasks created in [from, to)
+func (s *HedgingService) Stats(ctx context.Context, endpoint string, from, to time.Time) (*hedging.Stats, error) {
+	totals, err := s.taskRepo.GetHedgeTotals(ctx, endpoint, from, to)
+	if err != nil {
+		return nil, err
+	}
+	stats := &hedging.Stats{
+		Endpoint:         endpoint,
+		From:             from,
+		To:               to,
+		Hedged:           totals.Hedged,
+		HedgeWins:        totals.HedgeWins,
+		OriginalWins:     totals.OriginalWins,
+		Racing:           totals.Racing,
+		HedgeExecutionMs: totals.HedgeExecutionMs,
+		SavedMs:          totals.SavedMs,
+		SavedMeasured:    totals.SavedMeasured,
+	}
+	stats.Finish()
+	return stats, nil
+}
+
+// hedge queues a copy of an in-progress task. It returns nil when the task finished or was
+// hedged in the meantime.
+func (s *TaskService) hedge(ctx context.Context, original *mysql.Task) (*mysql.Task, error) {
+	ok, err := s.taskRepo.MarkHedged(ctx, original.TaskID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	now := time.Now()
+	hedge := &mysql.Task{
+		TaskID:           uuid.New().String(),
+		Endpoint:         original.Endpoint,
+		Input:            original.Input,
+		Status:           string(model.TaskStatusPending),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		TransformVersion: original.TransformVersion,
+		SubjectHash:      original.SubjectHash, // Hedges are erased with the subject's other tasks
+		HedgeOf:          original.TaskID,
+		ReplayOf:         original.ReplayOf,
+	}
+	if err := s.createPendingTask(ctx, hedge); err != nil {
+		// Leave the task to the next check
+		if resetErr := s.taskRepo.UpdateFields(ctx, original.TaskID, map[string]interface{}{"hedge_outcome": ""}); resetErr != nil {
+			logger.WarnCtx(ctx, "failed to reset hedge outcome, task_id: %s, error: %v", original.TaskID, resetErr)
+		}
+		return nil, err
+	}
+	return hedge, nil
+}
+
+// resolveHedge handles the result of a hedged task or of its hedge. The first result is the
+// task's result; a hedge that wins reports it for the original. A later result is dropped
+// and its copy cancelled. handled is false when req is to be applied to task as usual.
+func (s *TaskService) resolveHedge(ctx context.Context, task *mysql.Task, req *model.JobResultRequest) (handled bool, err error) {
+	if task.HedgeOf != "" {
+		if task.Status == string(model.TaskStatusCancelled) {
+			logger.InfoCtx(ctx, "result of cancelled hedge dropped, task_id: %s, hedge_of: %s", task.TaskID, task.HedgeOf)
+			return true, nil
+		}
+		won, err := s.taskRepo.ResolveHedge(ctx, task.HedgeOf, hedging.OutcomeHedge)
+		if err != nil {
+			return true, err
+		}
+		if !won {
+			logger.InfoCtx(ctx, "result of hedge dropped, original reported first, task_id: %s, hedge_of: %s", task.TaskID, task.HedgeOf)
+			s.finishHedge(ctx, task, string(model.TaskStatusCancelled))
+			return true, nil
+		}
+
+		original, err := s.taskRepo.Get(ctx, task.HedgeOf)
+		if err != nil {
+			return true, err
+		}
+		if original == nil {
+			return true, fmt.Errorf("task %s of hedge %s not found", task.HedgeOf, task.TaskID)
+		}
+		originalReq := *req
+		originalReq.TaskID = original.TaskID
+		if err := s.applyTaskResult(ctx, original, &originalReq); err != nil {
+			return true, err
+		}
+		status := string(model.TaskStatusCompleted)
+		if req.Error != "" {
+			status = string(model.TaskStatusFailed)
+		}
+		s.finishHedge(ctx, task, status)
+		logger.InfoCtx(ctx, "hedge reported first, task_id: %s, hedge_id: %s", original.TaskID, task.TaskID)
+		return true, nil
+	}
+
+	switch task.HedgeOutcome {
+	case hedging.OutcomeRacing:
+		won, err := s.taskRepo.ResolveHedge(ctx, task.TaskID, hedging.OutcomeOriginal)
+		if err != nil {
+			return true, err
+		}
+		if won {
+			s.cancelHedge(ctx, task.TaskID)
+			return false, nil
+		}
+		// The hedge reported in the meantime
+		s.dropLateResult(ctx, task.TaskID)
+		return true, nil
+	case hedging.OutcomeHedge:
+		s.dropLateResult(ctx, task.TaskID)
+		return true, nil
+	}
+	return false, nil
+}
+
+// dropLateResult drops the result of a task its hedge already reported for, recording how
+// much earlier the hedge was
+func (s *TaskService) dropLateResult(ctx context.Context, taskID string) {
+	original, err := s.taskRepo.Get(ctx, taskID)
+	if err == nil && original != nil && original.CompletedAt != nil {
+		if err := s.taskRepo.SetHedgeSaved(ctx, taskID, time.Since(*original.CompletedAt).Milliseconds()); err != nil {
+			logger.WarnCtx(ctx, "%v, task_id: %s", err, taskID)
+		}
+	}
+	logger.InfoCtx(ctx, "result dropped, hedge reported first, task_id: %s", taskID)
+}
+
+// cancelHedge cancels the hedge of a task if it is still queued or running; its worker is
+// told through the heartbeat like for any cancelled task
+func (s *TaskService) cancelHedge(ctx context.Context, taskID string) {
+	hedge, err := s.taskRepo.GetHedgeOf(ctx, taskID)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to cancel hedge, task_id: %s, error: %v", taskID, err)
+		return
+	}
+	if hedge == nil || (hedge.Status != string(model.TaskStatusPending) && hedge.Status != string(model.TaskStatusInProgress)) {
+		return
+	}
+	s.finishHedge(ctx, hedge, string(model.TaskStatusCancelled))
+}
+
+// finishHedge ends a hedge without storing its output, which is kept on the original only.
+// Its execution time is recorded as GPU usage: it is the cost of hedging.
+func (s *TaskService) finishHedge(ctx context.Context, hedge *mysql.Task, status string) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":       status,
+		"updated_at":   now,
+		"completed_at": now,
+	}
+	if err := s.taskRepo.UpdateFieldsWithStatus(ctx, hedge.TaskID, hedge.Status, updates); err != nil {
+		logger.WarnCtx(ctx, "failed to finish hedge, task_id: %s, error: %v", hedge.TaskID, err)
+		return
+	}
+
+	if s.statisticsService != nil && countsInStatistics(hedge) {
+		go s.statisticsService.UpdateStatisticsOnTaskStatusChange(context.Background(), hedge.Endpoint, hedge.Status, status)
+	}
+	hedge.Status = status
+	hedge.CompletedAt = &now
+	if s.gpuUsageService != nil {
+		go s.gpuUsageService.RecordTaskUsage(context.Background(), hedge)
+	}
+	if s.workerService != nil && hedge.WorkerID != "" && hedge.StartedAt != nil && status != string(model.TaskStatusCancelled) {
+		go s.workerService.RecordTaskCompletion(context.Background(), hedge.WorkerID, hedge.Endpoint, hedge.TaskID,
+			status == string(model.TaskStatusCompleted), now.Sub(*hedge.StartedAt).Milliseconds(), now)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"waverless/internal/model"
+	"waverless/pkg/store/mysql"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStatisticsCounter applies status changes to per-endpoint counters like the statistics table
+type fakeStatisticsCounter struct {
+	counts map[string]map[string]int
+}
+
+func (f *fakeStatisticsCounter) IncrementStatistics(ctx context.Context, endpoint string, fromStatus, toStatus string, count int) error {
+	if f.counts[endpoint] == nil {
+		f.counts[endpoint] = map[string]int{}
+	}
+	counts := f.counts[endpoint]
+	if fromStatus == "" {
+		counts["total"] += count
+	} else {
+		counts[fromStatus] -= count
+	}
+	counts[toStatus] += count
+	return nil
+}
+
+func TestHedgedTaskCountedOnce(t *testing.T) {
+	counter := &fakeStatisticsCounter{counts: map[string]map[string]int{}}
+	stats := &StatisticsService{counter: counter}
+	// record changes the status of a task the way the task and worker services report it
+	record := func(task *mysql.Task, to model.TaskStatus) {
+		if countsInStatistics(task) {
+			stats.UpdateStatisticsOnTaskStatusChange(context.Background(), task.Endpoint, task.Status, string(to))
+		}
+		task.Status = string(to)
+	}
+
+	original := &mysql.Task{TaskID: "task-a", Endpoint: "flux"}
+	hedge := &mysql.Task{TaskID: "task-b", Endpoint: "flux", HedgeOf: original.TaskID}
+
+	record(original, model.TaskStatusPending)    // Submitted
+	record(original, model.TaskStatusInProgress) // Pulled, then slow
+	record(hedge, model.TaskStatusPending)       // Hedge queued
+	record(hedge, model.TaskStatusInProgress)    // Hedge pulled
+	record(original, model.TaskStatusCompleted)  // The hedge's result is reported for the original
+	record(hedge, model.TaskStatusCompleted)     // The hedge is finished
+
+	assert.Equal(t, map[string]int{
+		"total":                            1,
+		string(model.TaskStatusPending):    0,
+		string(model.TaskStatusInProgress): 0,
+		string(model.TaskStatusCompleted):  1,
+	}, counter.counts["flux"])
+	assert.False(t, countsInStatistics(hedge))
+}
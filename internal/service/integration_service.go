@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/notification"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// integrationCacheTTL bounds how long a registry change takes to reach every replica
+	integrationCacheTTL = 30 * time.Second
+	// defaultIntegrationRetries is used when an integration does not set max_retries
+	defaultIntegrationRetries = 3
+	// maxIntegrationRetries caps max_retries so a dead receiver cannot hold a delivery for hours
+	maxIntegrationRetries = 10
+	// integrationRetryBaseDelay doubles after every failed attempt up to integrationRetryMaxDelay
+	integrationRetryBaseDelay = time.Second
+	integrationRetryMaxDelay  = time.Minute
+
+	// IntegrationReferencePrefix marks a task webhook that names a registered integration
+	// instead of a URL, e.g. "integration:billing"
+	IntegrationReferencePrefix = "integration:"
+)
+
+// integrationEvents are the event types integrations can subscribe to, by kind
+var integrationEvents = map[string][]string{
+	model.IntegrationKindWebhook: {notification.EventTaskCompleted, notification.EventTaskFailed, notification.EventEndpointHealth, notification.EventImageUpdate, notification.EventDiskPressure, notification.EventAnomaly, notification.EventEnvChanged, notification.EventJobSucceeded, notification.EventJobFailed, notification.EventHookRun, notification.EventQuota, notification.EventLoadTest},
+	model.IntegrationKindFeishu:  {notification.EventEndpointHealth, notification.EventImageUpdate, notification.EventDiskPressure, notification.EventAnomaly, notification.EventJobSucceeded, notification.EventJobFailed, notification.EventQuota, notification.EventLoadTest},
+}
+
+// UpsertIntegrationRequest creates or updates an integration
+type UpsertIntegrationRequest struct {
+	Kind        string   `json:"kind,omitempty"` // webhook (default), feishu
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events,omitempty"`    // Subscribed event types
+	Endpoints   []string `json:"endpoints,omitempty"` // Only events of these endpoints; empty = all
+	Secret      *string  `json:"secret,omitempty"`    // HMAC signing secret; omit to keep the current one
+	MaxRetries  *int     `json:"maxRetries,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"` // Default true
+	Description string   `json:"description,omitempty"`
+}
+
+// IntegrationInfo is an integration as returned by the API (the secret is never returned)
+type IntegrationInfo struct {
+	*model.Integration
+	HasSecret bool `json:"has_secret"`
+}
+
+// IntegrationDelivery is the outcome of a delivery to an integration
+type IntegrationDelivery struct {
+	Integration string `json:"integration"`
+	DeliveryID  string `json:"deliveryId"`
+	Success     bool   `json:"success"`
+	Attempts    int    `json:"attempts"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// IntegrationService manages the integrations registry and delivers events to it.
+// Integrations are cached in memory so publishing does not query MySQL.
+type IntegrationService struct {
+	repo   *mysql.IntegrationRepository
+	sender *notification.WebhookSender
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	mu           sync.RWMutex
+	integrations []*model.Integration
+	loadedAt     time.Time
+}
+
+// NewIntegrationService creates a new integration service
+func NewIntegrationService(repo *mysql.IntegrationRepository) *IntegrationService {
+	return &IntegrationService{
+		repo:   repo,
+		sender: notification.NewWebhookSender(30 * time.Second),
+		sleep:  sleepCtx,
+	}
+}
+
+// List returns all integrations
+func (s *IntegrationService) List(ctx context.Context) ([]*IntegrationInfo, error) {
+	integrations, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]*IntegrationInfo, 0, len(integrations))
+	for _, i := range integrations {
+		infos = append(infos, toIntegrationInfo(i))
+	}
+	return infos, nil
+}
+
+// Get returns an integration by name
+func (s *IntegrationService) Get(ctx context.Context, name string) (*IntegrationInfo, error) {
+	integration, err := s.get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return toIntegrationInfo(integration), nil
+}
+
+func (s *IntegrationService) get(ctx context.Context, name string) (*model.Integration, error) {
+	integration, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if integration == nil {
+		return nil, fmt.Errorf("integration %s not found", name)
+	}
+	return integration, nil
+}
+
+// Upsert creates or updates an integration; its delivery state is kept
+func (s *IntegrationService) Upsert(ctx context.Context, name string, req *UpsertIntegrationRequest) (*IntegrationInfo, error) {
+	if name == "" {
+		return nil, fmt.Errorf("integration name is required")
+	}
+	kind := req.Kind
+	if kind == "" {
+		kind = model.IntegrationKindWebhook
+	}
+	allowed, ok := integrationEvents[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported integration kind: %s", kind)
+	}
+	if u, err := url.Parse(req.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	for _, event := range req.Events {
+		if !containsString(allowed, event) {
+			return nil, fmt.Errorf("event %s is not supported by %s integrations (supported: %s)", event, kind, strings.Join(allowed, ", "))
+		}
+	}
+	maxRetries := defaultIntegrationRetries
+	if req.MaxRetries != nil {
+		if *req.MaxRetries < 0 || *req.MaxRetries > maxIntegrationRetries {
+			return nil, fmt.Errorf("maxRetries must be between 0 and %d", maxIntegrationRetries)
+		}
+		maxRetries = *req.MaxRetries
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	existing, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	secret := ""
+	if req.Secret != nil {
+		secret = *req.Secret
+	} else if existing != nil {
+		secret = existing.Secret
+	}
+
+	integration := &model.Integration{
+		Name:        name,
+		Kind:        kind,
+		URL:         req.URL,
+		Events:      model.JSONStringArray(req.Events),
+		Endpoints:   model.JSONStringArray(req.Endpoints),
+		Secret:      secret,
+		MaxRetries:  maxRetries,
+		Enabled:     enabled,
+		Description: req.Description,
+	}
+	if err := s.repo.Upsert(ctx, integration); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	logger.InfoCtx(ctx, "[AUDIT] integration upserted: name=%s, kind=%s, events=%v, enabled=%v", name, kind, req.Events, enabled)
+	return s.Get(ctx, name)
+}
+
+// Delete removes an integration
+func (s *IntegrationService) Delete(ctx context.Context, name string) error {
+	if _, err := s.get(ctx, name); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	logger.InfoCtx(ctx, "[AUDIT] integration deleted: name=%s", name)
+	return nil
+}
+
+// Test sends a test event to an integration once and records the outcome
+func (s *IntegrationService) Test(ctx context.Context, name string) (*IntegrationDelivery, error) {
+	integration, err := s.get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	event := &notification.Event{
+		ID:        uuid.New().String(),
+		Type:      notification.EventTest,
+		CreatedAt: time.Now(),
+		Data:      map[string]interface{}{"message": fmt.Sprintf("Test delivery to integration %s", name)},
+	}
+	statusCode, sendErr := s.send(ctx, integration, event, nil)
+	return s.record(ctx, integration, event.ID, 1, statusCode, sendErr), nil
+}
+
+// ValidateReference checks that a task webhook naming an integration can be delivered
+func (s *IntegrationService) ValidateReference(ctx context.Context, webhook string) error {
+	name, ok := IntegrationReference(webhook)
+	if !ok {
+		return nil
+	}
+	if s == nil {
+		return fmt.Errorf("integrations are not available, cannot use webhook %s", webhook)
+	}
+	_, err := s.get(ctx, name)
+	return err
+}
+
+// DeliverTo delivers a payload to a named integration regardless of its event
+// subscriptions, retrying with backoff. Used for task webhooks that reference an integration.
+func (s *IntegrationService) DeliverTo(ctx context.Context, name string, event *notification.Event, payload interface{}) {
+	integration, err := s.get(ctx, name)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to deliver %s to integration: %v", event.Type, err)
+		return
+	}
+	if !integration.Enabled {
+		logger.WarnCtx(ctx, "integration %s is disabled, dropped %s delivery %s", name, event.Type, event.ID)
+		return
+	}
+	s.deliver(ctx, integration, event, payload)
+}
+
+// Publish delivers an event to every enabled integration subscribed to it. Deliveries run
+// in the background; Publish itself never blocks on I/O beyond a periodic registry reload.
+func (s *IntegrationService) Publish(ctx context.Context, event *notification.Event) {
+	if s == nil || event == nil {
+		return
+	}
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	for _, integration := range s.subscribers(ctx, event) {
+		go s.deliver(context.Background(), integration, event, nil)
+	}
+}
+
+// subscribers returns the cached enabled integrations matching an event
+func (s *IntegrationService) subscribers(ctx context.Context, event *notification.Event) []*model.Integration {
+	var matched []*model.Integration
+	for _, integration := range s.cached(ctx) {
+		if !integration.Enabled || !containsString(integration.Events, event.Type) {
+			continue
+		}
+		if len(integration.Endpoints) > 0 && !containsString(integration.Endpoints, event.Endpoint) {
+			continue
+		}
+		matched = append(matched, integration)
+	}
+	return matched
+}
+
+// deliver sends an event (or payload in its place) with exponential backoff between
+// attempts and records the final outcome on the integration
+func (s *IntegrationService) deliver(ctx context.Context, integration *model.Integration, event *notification.Event, payload interface{}) *IntegrationDelivery {
+	attempts := integration.MaxRetries + 1
+	delay := integrationRetryBaseDelay
+
+	var statusCode int
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		statusCode, err = s.send(ctx, integration, event, payload)
+		if err == nil || attempt >= attempts {
+			break
+		}
+		logger.WarnCtx(ctx, "delivery %s to integration %s failed (attempt %d/%d), retrying in %s: %v",
+			event.ID, integration.Name, attempt, attempts, delay, err)
+		if s.sleep(ctx, delay) != nil {
+			break
+		}
+		delay *= 2
+		if delay > integrationRetryMaxDelay {
+			delay = integrationRetryMaxDelay
+		}
+	}
+	return s.record(ctx, integration, event.ID, attempt, statusCode, err)
+}
+
+// send performs a single delivery attempt
+func (s *IntegrationService) send(ctx context.Context, integration *model.Integration, event *notification.Event, payload interface{}) (int, error) {
+	if integration.Kind != model.IntegrationKindFeishu {
+		if payload != nil {
+			return s.sender.SendPayload(ctx, integration.URL, integration.Secret, event.Type, event.ID, payload)
+		}
+		return s.sender.Send(ctx, integration.URL, integration.Secret, event)
+	}
+
+	notifier := notification.NewFeishuNotifierWithURL(integration.URL)
+	var err error
+	switch data := event.Data.(type) {
+	case *notification.EndpointHealthNotification:
+		err = notifier.SendEndpointHealthNotification(ctx, data)
+	case *notification.ImageUpdateNotification:
+		err = notifier.SendImageUpdateNotification(ctx, data)
+	case *notification.DiskPressureNotification:
+		err = notifier.SendText(ctx, data.Text())
+	case *notification.AnomalyNotification:
+		err = notifier.SendText(ctx, data.Text())
+	case *notification.QuotaNotification:
+		err = notifier.SendText(ctx, data.Text())
+	case *notification.JobRunNotification:
+		err = notifier.SendText(ctx, data.Text())
+	case *notification.LoadTestNotification:
+		err = notifier.SendText(ctx, data.Text())
+	default:
+		err = notifier.SendText(ctx, fmt.Sprintf("[Waverless] %s event for %s", event.Type, integrationEventSubject(event)))
+	}
+	return 0, err
+}
+
+// record stores the outcome of a delivery and returns it
+func (s *IntegrationService) record(ctx context.Context, integration *model.Integration, deliveryID string, attempts, statusCode int, err error) *IntegrationDelivery {
+	delivery := &IntegrationDelivery{
+		Integration: integration.Name,
+		DeliveryID:  deliveryID,
+		Success:     err == nil,
+		Attempts:    attempts,
+		StatusCode:  statusCode,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+		if len(delivery.Error) > 1024 {
+			delivery.Error = delivery.Error[:1024]
+		}
+		logger.ErrorCtx(ctx, "delivery %s to integration %s failed after %d attempt(s): %v", deliveryID, integration.Name, attempts, err)
+	}
+	if recordErr := s.repo.RecordDelivery(ctx, integration.ID, time.Now(), attempts, statusCode, delivery.Error); recordErr != nil {
+		logger.WarnCtx(ctx, "failed to record delivery %s to integration %s: %v", deliveryID, integration.Name, recordErr)
+	}
+	return delivery
+}
+
+// cached returns the cached integrations, reloading them when the cache is stale
+func (s *IntegrationService) cached(ctx context.Context) []*model.Integration {
+	s.mu.RLock()
+	integrations, fresh := s.integrations, time.Since(s.loadedAt) < integrationCacheTTL
+	s.mu.RUnlock()
+	if fresh {
+		return integrations
+	}
+
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to reload integrations, keeping cached ones: %v", err)
+		return integrations
+	}
+	s.mu.Lock()
+	s.integrations, s.loadedAt = list, time.Now()
+	s.mu.Unlock()
+	return list
+}
+
+// invalidate forces a registry reload on the next publish
+func (s *IntegrationService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// IntegrationReference returns the integration named by a task webhook, if it names one
+func IntegrationReference(webhook string) (string, bool) {
+	if !strings.HasPrefix(webhook, IntegrationReferencePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(webhook, IntegrationReferencePrefix), true
+}
+
+func toIntegrationInfo(integration *model.Integration) *IntegrationInfo {
+	return &IntegrationInfo{Integration: integration, HasSecret: integration.Secret != ""}
+}
+
+func integrationEventSubject(event *notification.Event) string {
+	if event.Endpoint != "" {
+		return "endpoint " + event.Endpoint
+	}
+	return "delivery " + event.ID
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"waverless/pkg/cronexpr"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/notification"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+const (
+	// jobScheduleDefaultRetryDelay is the wait before retrying a failed run
+	jobScheduleDefaultRetryDelay = time.Minute
+	// jobScheduleMaxRetries bounds the retries of a run (run names end in -r<n>)
+	jobScheduleMaxRetries = 10
+	// jobScheduleDefaultHistory and jobScheduleMaxHistory bound the finished runs kept
+	jobScheduleDefaultHistory = 20
+	jobScheduleMaxHistory     = 100
+	// jobScheduleRunTimeFormat is the fire time in run names (UTC)
+	jobScheduleRunTimeFormat = "200601021504"
+)
+
+// jobScheduleNamePattern leaves room in the 63 characters of a job name for
+// "-<fire time>-r<n>"
+var jobScheduleNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,43}[a-z0-9])?$`)
+
+// SaveJobScheduleRequest creates or replaces a recurring job schedule
+type SaveJobScheduleRequest struct {
+	Cron              string                   `json:"cron" binding:"required"` // 5-field cron expression or @daily, @hourly, ...
+	Timezone          string                   `json:"timezone,omitempty"`      // IANA time zone of the cron expression (default UTC)
+	SpecName          string                   `json:"specName" binding:"required"`
+	Image             string                   `json:"image" binding:"required"`
+	Command           []string                 `json:"command,omitempty"`
+	Args              []string                 `json:"args,omitempty"`
+	Env               map[string]string        `json:"env,omitempty"`
+	GpuCount          int                      `json:"gpuCount,omitempty"`
+	VolumeMounts      []interfaces.VolumeMount `json:"volumeMounts,omitempty"`
+	ShmSize           string                   `json:"shmSize,omitempty"`
+	TimeoutSeconds    int                      `json:"timeoutSeconds,omitempty"`
+	ConcurrencyPolicy string                   `json:"concurrencyPolicy,omitempty"` // forbid (default), replace or allow
+	MaxRetries        int                      `json:"maxRetries,omitempty"`        // Retries of a failed run (0-10)
+	RetryDelaySeconds int                      `json:"retryDelaySeconds,omitempty"` // Default 60
+	HistoryLimit      int                      `json:"historyLimit,omitempty"`      // Finished runs kept (default 20, max 100)
+	Notify            []string                 `json:"notify,omitempty"`            // Integrations told about failed runs
+	NotifyOnSuccess   bool                     `json:"notifyOnSuccess,omitempty"`   // Tell them about successful runs too
+	Suspended         bool                     `json:"suspended,omitempty"`
+}
+
+// JobScheduleService starts batch jobs on cron schedules, replacing a crontab that submits
+// jobs: runs follow a concurrency policy, failed runs are retried, and the final outcome of
+// each run is published to integrations
+type JobScheduleService struct {
+	repo               *mysql.JobScheduleRepository
+	jobRepo            *mysql.BatchJobRepository
+	batchJobService    *BatchJobService
+	integrationService *IntegrationService
+}
+
+// NewJobScheduleService creates a new job schedule service
+func NewJobScheduleService(repo *mysql.JobScheduleRepository, jobRepo *mysql.BatchJobRepository, batchJobService *BatchJobService, integrationService *IntegrationService) *JobScheduleService {
+	return &JobScheduleService{
+		repo:               repo,
+		jobRepo:            jobRepo,
+		batchJobService:    batchJobService,
+		integrationService: integrationService,
+	}
+}
+
+// Save creates or replaces a schedule. The next run is computed from now; runs already
+// started are not affected.
+func (s *JobScheduleService) Save(ctx context.Context, name string, req *SaveJobScheduleRequest, requestedBy string) (*mysqlModel.JobSchedule, error) {
+	if !jobScheduleNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid job schedule name %q: must consist of lowercase alphanumeric characters or '-', start and end with an alphanumeric character, at most 45 characters", name)
+	}
+	cron, loc, err := parseJobSchedule(req.Cron, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	policy := req.ConcurrencyPolicy
+	if policy == "" {
+		policy = mysqlModel.JobConcurrencyForbid
+	}
+	if policy != mysqlModel.JobConcurrencyForbid && policy != mysqlModel.JobConcurrencyReplace && policy != mysqlModel.JobConcurrencyAllow {
+		return nil, fmt.Errorf("invalid job schedule: concurrencyPolicy must be forbid, replace or allow")
+	}
+	if req.MaxRetries < 0 || req.MaxRetries > jobScheduleMaxRetries {
+		return nil, fmt.Errorf("invalid job schedule: maxRetries must be between 0 and %d", jobScheduleMaxRetries)
+	}
+	if req.RetryDelaySeconds < 0 {
+		return nil, fmt.Errorf("invalid job schedule: retryDelaySeconds must be >= 0")
+	}
+	retryDelay := req.RetryDelaySeconds
+	if retryDelay == 0 {
+		retryDelay = int(jobScheduleDefaultRetryDelay.Seconds())
+	}
+	if req.HistoryLimit < 0 || req.HistoryLimit > jobScheduleMaxHistory {
+		return nil, fmt.Errorf("invalid job schedule: historyLimit must be between 0 and %d", jobScheduleMaxHistory)
+	}
+	historyLimit := req.HistoryLimit
+	if historyLimit == 0 {
+		historyLimit = jobScheduleDefaultHistory
+	}
+	for _, integration := range req.Notify {
+		if s.integrationService == nil {
+			return nil, fmt.Errorf("invalid job schedule: integrations are not available")
+		}
+		if _, err := s.integrationService.Get(ctx, integration); err != nil {
+			return nil, fmt.Errorf("invalid job schedule: %v", err)
+		}
+	}
+
+	// Validate the job template under the longest run name of the schedule
+	now := time.Now()
+	_, gpuCount, volumeMounts, err := s.batchJobService.validate(ctx, &SubmitBatchJobRequest{
+		Name:           jobScheduleRunName(name, now, jobScheduleMaxRetries+1),
+		SpecName:       req.SpecName,
+		Image:          req.Image,
+		GpuCount:       req.GpuCount,
+		VolumeMounts:   req.VolumeMounts,
+		TimeoutSeconds: req.TimeoutSeconds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	created := schedule == nil
+	if created {
+		schedule = &mysqlModel.JobSchedule{Name: name, CreatedBy: requestedBy}
+	}
+	schedule.Cron = req.Cron
+	schedule.Timezone = loc.String()
+	schedule.SpecName = req.SpecName
+	schedule.Image = req.Image
+	schedule.Command = req.Command
+	schedule.Args = req.Args
+	schedule.Env = mysqlModel.StringMapToJSONMap(req.Env)
+	schedule.VolumeMounts = mysqlModel.StringMapToJSONMap(volumeMounts)
+	schedule.GpuCount = gpuCount
+	schedule.ShmSize = req.ShmSize
+	schedule.TimeoutSeconds = req.TimeoutSeconds
+	schedule.ConcurrencyPolicy = policy
+	schedule.MaxRetries = req.MaxRetries
+	schedule.RetryDelaySeconds = retryDelay
+	schedule.HistoryLimit = historyLimit
+	schedule.Notify = req.Notify
+	schedule.NotifyOnSuccess = req.NotifyOnSuccess
+	schedule.Suspended = req.Suspended
+	schedule.NextRunAt = nextJobScheduleRun(schedule, cron, loc, now)
+
+	if created {
+		err = s.repo.Create(ctx, schedule)
+	} else {
+		err = s.repo.Save(ctx, schedule)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Get returns a schedule
+func (s *JobScheduleService) Get(ctx context.Context, name string) (*mysqlModel.JobSchedule, error) {
+	schedule, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, fmt.Errorf("job schedule %s not found", name)
+	}
+	return schedule, nil
+}
+
+// List returns all schedules
+func (s *JobScheduleService) List(ctx context.Context) ([]*mysqlModel.JobSchedule, error) {
+	return s.repo.List(ctx)
+}
+
+// Delete removes a schedule. Active runs keep running and the run history is kept.
+func (s *JobScheduleService) Delete(ctx context.Context, name string) error {
+	if _, err := s.Get(ctx, name); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, name)
+}
+
+// SetSuspended suspends or resumes a schedule. A resumed schedule fires at the next cron time
+// after now; fires missed while suspended are not caught up.
+func (s *JobScheduleService) SetSuspended(ctx context.Context, name string, suspended bool) (*mysqlModel.JobSchedule, error) {
+	schedule, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	cron, loc, err := parseJobSchedule(schedule.Cron, schedule.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Suspended = suspended
+	schedule.NextRunAt = nextJobScheduleRun(schedule, cron, loc, time.Now())
+	if err := s.repo.Update(ctx, name, map[string]interface{}{
+		"suspended":   schedule.Suspended,
+		"next_run_at": schedule.NextRunAt,
+	}); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Trigger starts a run now, outside the cron schedule. The concurrency policy applies.
+func (s *JobScheduleService) Trigger(ctx context.Context, name string) (*mysqlModel.BatchJob, error) {
+	schedule, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := s.jobRepo.ListBySchedule(ctx, name, jobScheduleMaxHistory)
+	if err != nil {
+		return nil, err
+	}
+	job, reason, err := s.fire(ctx, schedule, runs, time.Now().Truncate(time.Minute))
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("invalid trigger: %s", reason)
+	}
+	return job, nil
+}
+
+// Runs returns the most recent runs of a schedule, newest first
+func (s *JobScheduleService) Runs(ctx context.Context, name string, limit int) ([]*mysqlModel.BatchJob, error) {
+	if _, err := s.Get(ctx, name); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > jobScheduleMaxHistory {
+		limit = jobScheduleMaxHistory
+	}
+	return s.jobRepo.ListBySchedule(ctx, name, limit)
+}
+
+// Reconcile starts the due runs and retries of all schedules and trims their history
+func (s *JobScheduleService) Reconcile(ctx context.Context) error {
+	schedules, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, schedule := range schedules {
+		if schedule.Suspended {
+			continue
+		}
+		if err := s.reconcile(ctx, schedule, now); err != nil {
+			logger.WarnCtx(ctx, "failed to reconcile job schedule %s: %v", schedule.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *JobScheduleService) reconcile(ctx context.Context, schedule *mysqlModel.JobSchedule, now time.Time) error {
+	runs, err := s.jobRepo.ListBySchedule(ctx, schedule.Name, jobScheduleMaxHistory)
+	if err != nil {
+		return err
+	}
+
+	// Retry the latest run when it failed; a newer fire supersedes the retries of older ones
+	if len(runs) > 0 {
+		latest := runs[0]
+		if s.retryDue(schedule, latest, now) {
+			logger.InfoCtx(ctx, "retrying job %s of schedule %s (attempt %d/%d)", latest.Name, schedule.Name, latest.Attempt+1, schedule.MaxRetries+1)
+			if _, err := s.start(ctx, schedule, *latest.ScheduledAt, latest.Attempt+1); err != nil {
+				logger.WarnCtx(ctx, "failed to retry job %s of schedule %s: %v", latest.Name, schedule.Name, err)
+			}
+		}
+	}
+
+	if schedule.NextRunAt != nil && !now.Before(*schedule.NextRunAt) {
+		cron, loc, err := parseJobSchedule(schedule.Cron, schedule.Timezone)
+		if err != nil {
+			return err
+		}
+		// Fires missed while no controller ran coalesce into this one
+		fireAt := *schedule.NextRunAt
+		job, reason, err := s.fire(ctx, schedule, runs, fireAt)
+		if err != nil {
+			reason = err.Error()
+			logger.WarnCtx(ctx, "failed to start run of job schedule %s: %v", schedule.Name, err)
+		}
+		updates := map[string]interface{}{
+			"next_run_at":       nextJobScheduleRun(schedule, cron, loc, now),
+			"last_scheduled_at": fireAt,
+			"message":           truncateJobScheduleMessage(reason),
+		}
+		if job != nil {
+			updates["last_run_name"] = job.Name
+		}
+		if err := s.repo.Update(ctx, schedule.Name, updates); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.jobRepo.TrimSchedule(ctx, schedule.Name, schedule.HistoryLimit); err != nil {
+		logger.WarnCtx(ctx, "failed to trim run history of job schedule %s: %v", schedule.Name, err)
+	}
+	return nil
+}
+
+// fire starts a run of a schedule according to its concurrency policy. It returns the reason
+// when the policy skipped the run.
+func (s *JobScheduleService) fire(ctx context.Context, schedule *mysqlModel.JobSchedule, runs []*mysqlModel.BatchJob, fireAt time.Time) (*mysqlModel.BatchJob, string, error) {
+	var active []*mysqlModel.BatchJob
+	for _, run := range runs {
+		if isActiveBatchJob(run) {
+			active = append(active, run)
+		}
+	}
+	if len(active) > 0 {
+		switch schedule.ConcurrencyPolicy {
+		case mysqlModel.JobConcurrencyReplace:
+			for _, run := range active {
+				logger.InfoCtx(ctx, "job schedule %s replaces active run %s", schedule.Name, run.Name)
+				if _, err := s.batchJobService.Cancel(ctx, run.Name); err != nil {
+					return nil, "", fmt.Errorf("failed to cancel active run %s: %w", run.Name, err)
+				}
+			}
+		case mysqlModel.JobConcurrencyAllow:
+		default:
+			reason := fmt.Sprintf("skipped run of %s: run %s is still active", fireAt.UTC().Format(time.RFC3339), active[0].Name)
+			logger.InfoCtx(ctx, "job schedule %s %s", schedule.Name, reason)
+			return nil, reason, nil
+		}
+	}
+	job, err := s.start(ctx, schedule, fireAt, 1)
+	if err != nil {
+		return nil, "", err
+	}
+	return job, "", nil
+}
+
+// start submits a run of a schedule
+func (s *JobScheduleService) start(ctx context.Context, schedule *mysqlModel.JobSchedule, fireAt time.Time, attempt int) (*mysqlModel.BatchJob, error) {
+	return s.batchJobService.Submit(ctx, s.runRequest(schedule, fireAt, attempt), "schedule:"+schedule.Name)
+}
+
+// runRequest builds the job request of a run from the schedule's template
+func (s *JobScheduleService) runRequest(schedule *mysqlModel.JobSchedule, fireAt time.Time, attempt int) *SubmitBatchJobRequest {
+	volumeMounts := make([]interfaces.VolumeMount, 0, len(schedule.VolumeMounts))
+	for mountPath, pvc := range mysqlModel.JSONMapToStringMap(schedule.VolumeMounts) {
+		volumeMounts = append(volumeMounts, interfaces.VolumeMount{PVCName: pvc, MountPath: mountPath})
+	}
+	sort.Slice(volumeMounts, func(i, j int) bool { return volumeMounts[i].MountPath < volumeMounts[j].MountPath })
+	return &SubmitBatchJobRequest{
+		Name:           jobScheduleRunName(schedule.Name, fireAt, attempt),
+		SpecName:       schedule.SpecName,
+		Image:          schedule.Image,
+		Command:        schedule.Command,
+		Args:           schedule.Args,
+		Env:            mysqlModel.JSONMapToStringMap(schedule.Env),
+		GpuCount:       schedule.GpuCount,
+		VolumeMounts:   volumeMounts,
+		ShmSize:        schedule.ShmSize,
+		TimeoutSeconds: schedule.TimeoutSeconds,
+		ScheduleName:   schedule.Name,
+		Attempt:        attempt,
+		ScheduledAt:    &fireAt,
+	}
+}
+
+// retryDue reports whether a failed run gets another attempt now
+func (s *JobScheduleService) retryDue(schedule *mysqlModel.JobSchedule, run *mysqlModel.BatchJob, now time.Time) bool {
+	if run.Status != interfaces.JobPhaseFailed || run.Attempt > schedule.MaxRetries || run.ScheduledAt == nil || run.CompletedAt == nil {
+		return false
+	}
+	return !now.Before(run.CompletedAt.Add(time.Duration(schedule.RetryDelaySeconds) * time.Second))
+}
+
+// runFinished publishes the final outcome of a scheduled run; failed runs with retries left
+// are retried by Reconcile instead
+func (s *JobScheduleService) runFinished(ctx context.Context, job *mysqlModel.BatchJob) {
+	if job.Status == mysqlModel.BatchJobStatusCancelled {
+		return
+	}
+	schedule, err := s.repo.Get(ctx, job.ScheduleName)
+	if err != nil || schedule == nil {
+		return
+	}
+	failed := job.Status == interfaces.JobPhaseFailed
+	if failed && !schedule.Suspended && job.Attempt <= schedule.MaxRetries {
+		logger.InfoCtx(ctx, "job %s of schedule %s failed, retrying in %ds", job.Name, schedule.Name, schedule.RetryDelaySeconds)
+		return
+	}
+
+	eventType := notification.EventJobSucceeded
+	if failed {
+		eventType = notification.EventJobFailed
+	}
+	data := &notification.JobRunNotification{
+		Schedule:    schedule.Name,
+		Job:         job.Name,
+		Status:      job.Status,
+		Attempt:     job.Attempt,
+		ExitCode:    job.ExitCode,
+		Message:     job.Message,
+		GPUHours:    job.GPUHours,
+		CompletedAt: job.CompletedAt,
+	}
+	if job.ScheduledAt != nil {
+		data.ScheduledAt = *job.ScheduledAt
+	}
+	event := &notification.Event{Type: eventType, CreatedAt: time.Now(), Data: data}
+	s.integrationService.Publish(ctx, event)
+	if s.integrationService != nil && (failed || schedule.NotifyOnSuccess) {
+		for _, name := range schedule.Notify {
+			go s.integrationService.DeliverTo(context.Background(), name, event, nil)
+		}
+	}
+}
+
+// jobScheduleRunName names a run <schedule>-<fire time>, with -r<n> appended for retries
+func jobScheduleRunName(schedule string, fireAt time.Time, attempt int) string {
+	name := schedule + "-" + fireAt.UTC().Format(jobScheduleRunTimeFormat)
+	if attempt > 1 {
+		name += fmt.Sprintf("-r%d", attempt-1)
+	}
+	return name
+}
+
+// parseJobSchedule parses the cron expression and time zone of a schedule
+func parseJobSchedule(expr, timezone string) (*cronexpr.Schedule, *time.Location, error) {
+	cron, err := cronexpr.Parse(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid timezone %q: %v", timezone, err)
+	}
+	return cron, loc, nil
+}
+
+// nextJobScheduleRun returns the next fire time after now, nil when the schedule is suspended
+// or never fires
+func nextJobScheduleRun(schedule *mysqlModel.JobSchedule, cron *cronexpr.Schedule, loc *time.Location, now time.Time) *time.Time {
+	if schedule.Suspended {
+		return nil
+	}
+	next := cron.Next(now.In(loc))
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+func truncateJobScheduleMessage(message string) string {
+	if len(message) > 512 {
+		return message[:512]
+	}
+	return message
+}
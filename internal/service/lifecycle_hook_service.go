@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/hooks"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/notification"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+const (
+	// lifecycleHookRunListLimit is the default and maximum number of listed hook runs
+	lifecycleHookRunListLimit = 200
+	// maxLifecycleHooks bounds the hooks of an endpoint: they run inside the API request
+	maxLifecycleHooks = 10
+)
+
+// SaveLifecycleHooksRequest replaces the hooks of an endpoint; hooks of a phase run in order
+type SaveLifecycleHooksRequest struct {
+	Hooks []*hooks.Hook `json:"hooks" binding:"required,dive"`
+}
+
+// LifecycleHookService manages per-endpoint lifecycle hooks: HTTP calls or one-off jobs run
+// before a deploy, after a deploy or before a delete (e.g. warm an external cache, deregister
+// from a model catalog). Every run is recorded and published to integrations.
+type LifecycleHookService struct {
+	repo            *mysql.LifecycleHookRepository
+	endpointService *endpointsvc.Service
+	runner          *hooks.Runner
+	integrations    *IntegrationService
+}
+
+// NewLifecycleHookService creates a new lifecycle hook service. Job hooks need a provider
+// with job support.
+func NewLifecycleHookService(repo *mysql.LifecycleHookRepository, endpointService *endpointsvc.Service, deployProvider interfaces.DeploymentProvider) *LifecycleHookService {
+	jobs, _ := deployProvider.(interfaces.JobRunner)
+	return &LifecycleHookService{
+		repo:            repo,
+		endpointService: endpointService,
+		runner:          hooks.NewRunner(jobs),
+	}
+}
+
+// SetIntegrationService sets the integrations hook runs are published to (for dependency injection)
+func (s *LifecycleHookService) SetIntegrationService(integrations *IntegrationService) {
+	s.integrations = integrations
+}
+
+// GetHooks returns the hooks of an endpoint in execution order
+func (s *LifecycleHookService) GetHooks(ctx context.Context, endpoint string) ([]*hooks.Hook, error) {
+	if err := s.checkEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return s.loadHooks(ctx, endpoint)
+}
+
+// SaveHooks validates and replaces the hooks of an endpoint
+func (s *LifecycleHookService) SaveHooks(ctx context.Context, endpoint string, req *SaveLifecycleHooksRequest, updatedBy string) ([]*hooks.Hook, error) {
+	if err := s.checkEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	if len(req.Hooks) > maxLifecycleHooks {
+		return nil, fmt.Errorf("invalid hooks: at most %d hooks per endpoint", maxLifecycleHooks)
+	}
+
+	rows := make([]*mysqlModel.LifecycleHook, 0, len(req.Hooks))
+	seen := make(map[string]bool, len(req.Hooks))
+	for i, h := range req.Hooks {
+		if err := h.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[h.Name] {
+			return nil, fmt.Errorf("invalid hooks: hook %s is listed more than once", h.Name)
+		}
+		seen[h.Name] = true
+
+		action, err := toJSONMap(map[string]interface{}{"http": h.HTTP, "job": h.Job})
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, &mysqlModel.LifecycleHook{
+			Endpoint:       endpoint,
+			Name:           h.Name,
+			Phase:          h.Phase,
+			Position:       i,
+			Action:         action,
+			TimeoutSeconds: h.TimeoutSeconds,
+			FailurePolicy:  h.Policy(),
+			UpdatedBy:      updatedBy,
+		})
+	}
+
+	if err := s.repo.Replace(ctx, endpoint, rows); err != nil {
+		return nil, err
+	}
+	return s.loadHooks(ctx, endpoint)
+}
+
+// DeleteHooks removes the hooks of an endpoint; the run history is kept
+func (s *LifecycleHookService) DeleteHooks(ctx context.Context, endpoint string) error {
+	return s.repo.Replace(ctx, endpoint, nil)
+}
+
+// ListRuns returns the most recent hook runs of an endpoint, newest first
+func (s *LifecycleHookService) ListRuns(ctx context.Context, endpoint string, limit int) ([]*mysqlModel.LifecycleHookRun, error) {
+	if limit <= 0 || limit > lifecycleHookRunListLimit {
+		limit = lifecycleHookRunListLimit
+	}
+	return s.repo.ListRuns(ctx, endpoint, limit)
+}
+
+// RunHooks runs the hooks of an endpoint for a phase in order. A failed hook with the block
+// policy stops the remaining hooks and returns an error wrapping endpointsvc.ErrHookBlocked;
+// failures of warn hooks are only recorded.
+func (s *LifecycleHookService) RunHooks(ctx context.Context, endpoint, phase, operation string) error {
+	all, err := s.loadHooks(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to load lifecycle hooks of endpoint %s: %w", endpoint, err)
+	}
+
+	for _, h := range all {
+		if h.Phase != phase {
+			continue
+		}
+		logger.InfoCtx(ctx, "Running %s hook %s of endpoint %s (%s)", phase, h.Name, endpoint, h.Action())
+		startedAt := time.Now()
+		result := s.runner.Run(ctx, endpoint, operation, h)
+		blocked := !result.Succeeded() && h.Policy() == hooks.FailurePolicyBlock
+		s.record(ctx, endpoint, operation, h, result, blocked, startedAt)
+
+		if result.Succeeded() {
+			continue
+		}
+		if blocked {
+			logger.WarnCtx(ctx, "Blocking %s hook %s of endpoint %s %s: %s", phase, h.Name, endpoint, result.Status, result.Error)
+			return fmt.Errorf("%w: %s hook %s %s: %s", endpointsvc.ErrHookBlocked, phase, h.Name, result.Status, result.Error)
+		}
+		logger.WarnCtx(ctx, "%s hook %s of endpoint %s %s, continuing (failure policy warn): %s", phase, h.Name, endpoint, result.Status, result.Error)
+	}
+	return nil
+}
+
+// record stores a hook run and publishes it to integrations. Failures are logged and never
+// change the outcome of the operation.
+func (s *LifecycleHookService) record(ctx context.Context, endpoint, operation string, h *hooks.Hook, result *hooks.Result, blocked bool, startedAt time.Time) {
+	run := &mysqlModel.LifecycleHookRun{
+		Endpoint:      endpoint,
+		HookName:      h.Name,
+		Phase:         h.Phase,
+		Operation:     operation,
+		Action:        h.Action(),
+		FailurePolicy: h.Policy(),
+		Status:        result.Status,
+		Blocked:       blocked,
+		StatusCode:    result.StatusCode,
+		Output:        result.Output,
+		Error:         truncateBatchJobMessage(result.Error),
+		DurationMs:    result.Duration.Milliseconds(),
+		StartedAt:     startedAt,
+	}
+	if err := s.repo.CreateRun(ctx, run); err != nil {
+		logger.WarnCtx(ctx, "Failed to record %s hook %s of endpoint %s: %v", h.Phase, h.Name, endpoint, err)
+	}
+
+	s.integrations.Publish(ctx, &notification.Event{
+		Type:      notification.EventHookRun,
+		Endpoint:  endpoint,
+		CreatedAt: startedAt,
+		Data:      run,
+	})
+}
+
+// loadHooks returns the stored hooks of an endpoint in execution order
+func (s *LifecycleHookService) loadHooks(ctx context.Context, endpoint string) ([]*hooks.Hook, error) {
+	rows, err := s.repo.ListByEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*hooks.Hook, 0, len(rows))
+	for _, row := range rows {
+		h := &hooks.Hook{
+			Name:           row.Name,
+			Phase:          row.Phase,
+			TimeoutSeconds: row.TimeoutSeconds,
+			FailurePolicy:  row.FailurePolicy,
+		}
+		data, err := json.Marshal(row.Action)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode action of hook %s: %w", row.Name, err)
+		}
+		if err := json.Unmarshal(data, h); err != nil {
+			return nil, fmt.Errorf("failed to decode action of hook %s: %w", row.Name, err)
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// checkEndpoint returns an error if the endpoint does not exist
+func (s *LifecycleHookService) checkEndpoint(ctx context.Context, name string) error {
+	ep, err := s.endpointService.GetEndpointOnly(ctx, name)
+	if err != nil {
+		return err
+	}
+	if ep == nil || ep.Status == "deleted" {
+		return fmt.Errorf("endpoint %s not found", name)
+	}
+	return nil
+}
+
+// toJSONMap converts a value to a JSON column map
+func toJSONMap(v interface{}) (mysqlModel.JSONMap, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode hook action: %w", err)
+	}
+	var m mysqlModel.JSONMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to encode hook action: %w", err)
+	}
+	return m, nil
+}
+
+// compile-time assertion
+var _ endpointsvc.LifecycleHooks = (*LifecycleHookService)(nil)
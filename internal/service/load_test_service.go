@@ -0,0 +1,892 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"waverless/internal/model"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/config"
+	"waverless/pkg/cronexpr"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/loadtest"
+	"waverless/pkg/logger"
+	"waverless/pkg/notification"
+	"waverless/pkg/operation"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+const (
+	// loadTestDefaultTaskTimeout and loadTestMaxTaskTimeout bound how long a task may take
+	// before it counts as timed out and is cancelled
+	loadTestDefaultTaskTimeout = 10 * time.Minute
+	loadTestMaxTaskTimeout     = time.Hour
+	// loadTestDefaultHistory and loadTestMaxHistory bound the finished reports kept
+	loadTestDefaultHistory = 30
+	loadTestMaxHistory     = 365
+	// loadTestMaxPayloads bounds the payload corpus of a load test
+	loadTestMaxPayloads = 1000
+	// loadTestTick is how often the requests due by the profile are submitted
+	loadTestTick = 50 * time.Millisecond
+	// loadTestPollInterval is how often the outcome of submitted tasks is read
+	loadTestPollInterval = 5 * time.Second
+	// loadTestPollBatch bounds the tasks read per query
+	loadTestPollBatch = 500
+	// loadTestSampleInterval is how often the replicas and queue of the endpoint are sampled
+	loadTestSampleInterval = 10 * time.Second
+	// loadTestEventLimit bounds the scaling events kept in a report
+	loadTestEventLimit = 1000
+	// loadTestOrphanGrace leaves a just-created report time to be linked to its operation
+	loadTestOrphanGrace = time.Minute
+	// loadTestSubmitProgress is the progress reached once every request is submitted; the
+	// rest is waiting for the last tasks
+	loadTestSubmitProgress = 90
+)
+
+// loadTestNamePattern follows the rules of endpoint names
+var loadTestNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// SaveLoadTestRequest creates or replaces a load test
+type SaveLoadTestRequest struct {
+	Endpoint             string                   `json:"endpoint" binding:"required"`    // Staging endpoint the tasks are sent to
+	Cron                 string                   `json:"cron,omitempty"`                 // 5-field cron expression or @daily, ...; empty = on demand only
+	Timezone             string                   `json:"timezone,omitempty"`             // IANA time zone of the cron expression (default UTC)
+	Profile              []loadtest.Stage         `json:"profile" binding:"required"`     // RPS profile
+	Payloads             []map[string]interface{} `json:"payloads" binding:"required"`    // Task inputs, sent in turn
+	TaskTimeoutSeconds   int                      `json:"taskTimeoutSeconds,omitempty"`   // Tasks not finished by then time out (default 600)
+	MaxLatencyIncrease   float64                  `json:"maxLatencyIncrease,omitempty"`   // Relative latency increase that regresses (default 0.2)
+	MaxErrorRateIncrease float64                  `json:"maxErrorRateIncrease,omitempty"` // Absolute error rate increase that regresses (default 0.01)
+	HistoryLimit         int                      `json:"historyLimit,omitempty"`         // Finished reports kept (default 30, max 365)
+	Notify               []string                 `json:"notify,omitempty"`               // Integrations told about failed and regressed runs
+	Suspended            bool                     `json:"suspended,omitempty"`
+}
+
+// LoadTestRun is a started run of a load test
+type LoadTestRun struct {
+	Report    *mysqlModel.LoadTestReport `json:"report"`
+	Operation *mysqlModel.Operation      `json:"operation"`
+}
+
+// LoadTestComparison is a report compared with a baseline report of the same load test
+type LoadTestComparison struct {
+	Report     *mysqlModel.LoadTestReport `json:"report"`
+	Baseline   *mysqlModel.LoadTestReport `json:"baseline"`
+	Comparison *loadtest.Comparison       `json:"comparison"`
+}
+
+// LoadTestService runs load tests against staging endpoints: tasks built from a payload
+// corpus are submitted at the rate of an RPS profile, on a cron schedule or on demand. Each run
+// is an operation and stores a report with latency and error curves and the autoscaler
+// behavior, compared against the last run of another image to catch regressions.
+type LoadTestService struct {
+	repo               *mysql.LoadTestRepository
+	taskRepo           *mysql.TaskRepository
+	scalingEventRepo   *mysql.ScalingEventRepository
+	taskService        *TaskService
+	endpointService    *endpointsvc.Service
+	integrationService *IntegrationService
+	operations         *OperationService
+	config             config.LoadTestConfig
+}
+
+// NewLoadTestService creates a new load test service
+func NewLoadTestService(repo *mysql.LoadTestRepository, taskRepo *mysql.TaskRepository, scalingEventRepo *mysql.ScalingEventRepository, taskService *TaskService, endpointService *endpointsvc.Service, integrationService *IntegrationService, cfg config.LoadTestConfig) *LoadTestService {
+	return &LoadTestService{
+		repo:               repo,
+		taskRepo:           taskRepo,
+		scalingEventRepo:   scalingEventRepo,
+		taskService:        taskService,
+		endpointService:    endpointService,
+		integrationService: integrationService,
+		config:             cfg,
+	}
+}
+
+// SetOperationService sets the operations runs are started as; without it no run starts
+func (s *LoadTestService) SetOperationService(svc *OperationService) {
+	s.operations = svc
+}
+
+// Save creates or replaces a load test. The next scheduled run is computed from now; a run in
+// progress is not affected.
+func (s *LoadTestService) Save(ctx context.Context, name string, req *SaveLoadTestRequest, requestedBy string) (*mysqlModel.LoadTest, error) {
+	if !loadTestNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid load test name %q: must consist of lowercase alphanumeric characters or '-', start and end with an alphanumeric character, at most 63 characters", name)
+	}
+	if _, err := s.stagingEndpoint(ctx, req.Endpoint); err != nil {
+		return nil, err
+	}
+	if err := loadtest.Profile(req.Profile).Validate(s.config.MaxRPS, s.config.MaxDuration); err != nil {
+		return nil, fmt.Errorf("invalid load test: %v", err)
+	}
+	if len(req.Payloads) == 0 || len(req.Payloads) > loadTestMaxPayloads {
+		return nil, fmt.Errorf("invalid load test: payloads must hold between 1 and %d task inputs", loadTestMaxPayloads)
+	}
+	for i, payload := range req.Payloads {
+		if len(payload) == 0 {
+			return nil, fmt.Errorf("invalid load test: payload %d is empty", i+1)
+		}
+	}
+	if req.TaskTimeoutSeconds < 0 || time.Duration(req.TaskTimeoutSeconds)*time.Second > loadTestMaxTaskTimeout {
+		return nil, fmt.Errorf("invalid load test: taskTimeoutSeconds must be between 0 and %d", int(loadTestMaxTaskTimeout.Seconds()))
+	}
+	taskTimeout := req.TaskTimeoutSeconds
+	if taskTimeout == 0 {
+		taskTimeout = int(loadTestDefaultTaskTimeout.Seconds())
+	}
+	if req.MaxLatencyIncrease < 0 || req.MaxErrorRateIncrease < 0 {
+		return nil, fmt.Errorf("invalid load test: maxLatencyIncrease and maxErrorRateIncrease must be >= 0")
+	}
+	maxLatencyIncrease := req.MaxLatencyIncrease
+	if maxLatencyIncrease == 0 {
+		maxLatencyIncrease = loadtest.DefaultMaxLatencyIncrease
+	}
+	maxErrorRateIncrease := req.MaxErrorRateIncrease
+	if maxErrorRateIncrease == 0 {
+		maxErrorRateIncrease = loadtest.DefaultMaxErrorRateIncrease
+	}
+	if req.HistoryLimit < 0 || req.HistoryLimit > loadTestMaxHistory {
+		return nil, fmt.Errorf("invalid load test: historyLimit must be between 0 and %d", loadTestMaxHistory)
+	}
+	historyLimit := req.HistoryLimit
+	if historyLimit == 0 {
+		historyLimit = loadTestDefaultHistory
+	}
+	for _, integration := range req.Notify {
+		if s.integrationService == nil {
+			return nil, fmt.Errorf("invalid load test: integrations are not available")
+		}
+		if _, err := s.integrationService.Get(ctx, integration); err != nil {
+			return nil, fmt.Errorf("invalid load test: %v", err)
+		}
+	}
+	var cron *cronexpr.Schedule
+	loc := time.UTC
+	if req.Cron != "" {
+		var err error
+		if cron, loc, err = parseJobSchedule(req.Cron, req.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid load test: %v", err)
+		}
+	}
+
+	test, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	created := test == nil
+	if created {
+		test = &mysqlModel.LoadTest{Name: name, CreatedBy: requestedBy}
+	}
+	test.Endpoint = req.Endpoint
+	test.Cron = req.Cron
+	test.Timezone = loc.String()
+	test.Profile = make(mysqlModel.LoadTestStages, 0, len(req.Profile))
+	for _, stage := range req.Profile {
+		test.Profile = append(test.Profile, mysqlModel.LoadTestStage{DurationSeconds: stage.DurationSeconds, RPS: stage.RPS, Ramp: stage.Ramp})
+	}
+	test.Payloads = req.Payloads
+	test.TaskTimeoutSeconds = taskTimeout
+	test.MaxLatencyIncrease = maxLatencyIncrease
+	test.MaxErrorRateIncrease = maxErrorRateIncrease
+	test.HistoryLimit = historyLimit
+	test.Notify = req.Notify
+	test.Suspended = req.Suspended
+	test.NextRunAt = nextLoadTestRun(test, cron, loc, time.Now())
+
+	if created {
+		err = s.repo.Create(ctx, test)
+	} else {
+		err = s.repo.Save(ctx, test)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return test, nil
+}
+
+// Get returns a load test
+func (s *LoadTestService) Get(ctx context.Context, name string) (*mysqlModel.LoadTest, error) {
+	test, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if test == nil {
+		return nil, fmt.Errorf("load test %s not found", name)
+	}
+	return test, nil
+}
+
+// List returns all load tests
+func (s *LoadTestService) List(ctx context.Context) ([]*mysqlModel.LoadTest, error) {
+	return s.repo.List(ctx)
+}
+
+// Delete removes a load test. A run in progress keeps running and the reports are kept.
+func (s *LoadTestService) Delete(ctx context.Context, name string) error {
+	if _, err := s.Get(ctx, name); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, name)
+}
+
+// Trigger starts a run now, outside the cron schedule
+func (s *LoadTestService) Trigger(ctx context.Context, name, triggeredBy string) (*LoadTestRun, error) {
+	test, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.start(ctx, test, triggeredBy)
+}
+
+// Reports returns the most recent reports of a load test, newest first, without their curves
+func (s *LoadTestService) Reports(ctx context.Context, name string, limit, offset int) ([]*mysqlModel.LoadTestReport, error) {
+	if limit <= 0 || limit > loadTestMaxHistory {
+		limit = loadTestMaxHistory
+	}
+	return s.repo.ListReports(ctx, name, limit, offset)
+}
+
+// CountReports returns the number of reports of a load test
+func (s *LoadTestService) CountReports(ctx context.Context, name string) (int64, error) {
+	return s.repo.CountReports(ctx, name)
+}
+
+// GetReport returns a report of a load test with its curves
+func (s *LoadTestService) GetReport(ctx context.Context, name string, id int64) (*mysqlModel.LoadTestReport, error) {
+	report, err := s.repo.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil || report.LoadTest != name {
+		return nil, fmt.Errorf("load test report %d not found", id)
+	}
+	return report, nil
+}
+
+// Compare compares a report with a baseline report of the same load test: baselineID, else
+// the baseline picked when the run finished, else the last succeeded run of another image
+func (s *LoadTestService) Compare(ctx context.Context, name string, id, baselineID int64) (*LoadTestComparison, error) {
+	report, err := s.GetReport(ctx, name, id)
+	if err != nil {
+		return nil, err
+	}
+	if baselineID == 0 {
+		baselineID = report.BaselineID
+	}
+	var baseline *mysqlModel.LoadTestReport
+	if baselineID != 0 {
+		if baseline, err = s.GetReport(ctx, name, baselineID); err != nil {
+			return nil, err
+		}
+	} else {
+		if baseline, err = s.repo.FindBaseline(ctx, name, report.Image, report.ID); err != nil {
+			return nil, err
+		}
+		if baseline == nil {
+			return nil, fmt.Errorf("invalid comparison: no earlier succeeded report of load test %s ran an image other than %s", name, report.Image)
+		}
+	}
+
+	current, err := decodeLoadTestReport(report)
+	if err != nil {
+		return nil, err
+	}
+	base, err := decodeLoadTestReport(baseline)
+	if err != nil {
+		return nil, err
+	}
+	thresholds := loadtest.Thresholds{}
+	if test, err := s.repo.Get(ctx, name); err == nil && test != nil {
+		thresholds = loadTestThresholds(test)
+	}
+
+	report.Report, report.Comparison = nil, nil
+	baseline.Report, baseline.Comparison = nil, nil
+	return &LoadTestComparison{
+		Report:     report,
+		Baseline:   baseline,
+		Comparison: loadtest.Compare(base, current, thresholds),
+	}, nil
+}
+
+// Reconcile starts the due runs of all load tests, closes the reports of runs whose replica
+// stopped, and trims the report history
+func (s *LoadTestService) Reconcile(ctx context.Context) error {
+	s.closeOrphanedReports(ctx)
+
+	tests, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, test := range tests {
+		if !test.Suspended && test.NextRunAt != nil && !now.Before(*test.NextRunAt) {
+			if err := s.fire(ctx, test, now); err != nil {
+				logger.WarnCtx(ctx, "failed to reconcile load test %s: %v", test.Name, err)
+			}
+		}
+		if _, err := s.repo.TrimReports(ctx, test.Name, test.HistoryLimit); err != nil {
+			logger.WarnCtx(ctx, "failed to trim reports of load test %s: %v", test.Name, err)
+		}
+	}
+	return nil
+}
+
+// fire starts a scheduled run of a load test and moves it to its next cron time. Fires missed
+// while no controller ran coalesce into this one.
+func (s *LoadTestService) fire(ctx context.Context, test *mysqlModel.LoadTest, now time.Time) error {
+	cron, loc, err := parseJobSchedule(test.Cron, test.Timezone)
+	if err != nil {
+		return err
+	}
+	var message string
+	if _, err := s.start(ctx, test, "schedule:"+test.Name); err != nil {
+		message = fmt.Sprintf("skipped run of %s: %v", test.NextRunAt.UTC().Format(time.RFC3339), err)
+		logger.WarnCtx(ctx, "load test %s %s", test.Name, message)
+	}
+	return s.repo.Update(ctx, test.Name, map[string]interface{}{
+		"next_run_at": nextLoadTestRun(test, cron, loc, now),
+		"message":     truncateJobScheduleMessage(message),
+	})
+}
+
+// start stores the report of a new run and starts the run as an operation
+func (s *LoadTestService) start(ctx context.Context, test *mysqlModel.LoadTest, triggeredBy string) (*LoadTestRun, error) {
+	if s.operations == nil {
+		return nil, fmt.Errorf("long-running operations are not available")
+	}
+	meta, err := s.stagingEndpoint(ctx, test.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	profile := loadTestProfile(test)
+	if err := profile.Validate(s.config.MaxRPS, s.config.MaxDuration); err != nil {
+		return nil, fmt.Errorf("invalid load test: %v", err)
+	}
+	if len(test.Payloads) == 0 {
+		return nil, fmt.Errorf("invalid load test: no payloads")
+	}
+
+	now := time.Now()
+	report := &mysqlModel.LoadTestReport{
+		LoadTest:    test.Name,
+		Endpoint:    test.Endpoint,
+		Status:      mysqlModel.LoadTestReportRunning,
+		Image:       meta.Image,
+		TriggeredBy: triggeredBy,
+		StartedAt:   now,
+	}
+	if meta.Model != nil {
+		report.ModelVersion = meta.Model.Version
+	}
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	op, err := s.operations.StartLoadTest(ctx, test.Endpoint, triggeredBy, func(ctx context.Context, p *operation.Progress) error {
+		return s.run(ctx, test, report, profile, p)
+	})
+	if err != nil {
+		if _, updateErr := s.repo.UpdateReport(ctx, report.ID, map[string]interface{}{
+			"status":      mysqlModel.LoadTestReportFailed,
+			"error":       truncateReplayError(err),
+			"finished_at": time.Now(),
+		}); updateErr != nil {
+			logger.WarnCtx(ctx, "failed to close report %d of load test %s: %v", report.ID, test.Name, updateErr)
+		}
+		return nil, err
+	}
+	report.OperationID = op.ID
+	if err := s.repo.SetOperation(ctx, report.ID, op.ID); err != nil {
+		logger.WarnCtx(ctx, "failed to link report %d of load test %s to operation %s: %v", report.ID, test.Name, op.ID, err)
+	}
+	if err := s.repo.Update(ctx, test.Name, map[string]interface{}{
+		"last_run_at":    now,
+		"last_report_id": report.ID,
+	}); err != nil {
+		logger.WarnCtx(ctx, "failed to update load test %s: %v", test.Name, err)
+	}
+	logger.InfoCtx(ctx, "load test %s started against %s (image %s): report=%d, operation=%s, by=%s",
+		test.Name, test.Endpoint, report.Image, report.ID, op.ID, triggeredBy)
+	return &LoadTestRun{Report: report, Operation: op}, nil
+}
+
+// run is the operation of a run: it sends the load, builds the report and compares it with
+// the baseline. A cancelled run stores the report of what was sent so far.
+func (s *LoadTestService) run(ctx context.Context, test *mysqlModel.LoadTest, report *mysqlModel.LoadTestReport, profile loadtest.Profile, p *operation.Progress) error {
+	p.SetResult("loadTest", test.Name)
+	p.SetResult("reportId", report.ID)
+
+	runner := &loadTestRunner{
+		svc:         s,
+		test:        test,
+		profile:     profile,
+		progress:    p,
+		taskTimeout: time.Duration(test.TaskTimeoutSeconds) * time.Second,
+		pending:     make(map[string]*loadTestTask),
+	}
+	runErr := runner.drive(ctx)
+
+	// The run context is gone once cancelled; the report is stored regardless
+	bg := context.WithoutCancel(ctx)
+	if runErr != nil {
+		runner.abandon(bg)
+	}
+	finishedAt := time.Now()
+	result := loadtest.BuildReport(profile, runner.requests, runner.samples, s.scalingEvents(bg, test.Endpoint, runner.start, finishedAt))
+
+	status := mysqlModel.LoadTestReportSucceeded
+	switch {
+	case runErr != nil && ctx.Err() != nil:
+		status = mysqlModel.LoadTestReportCancelled
+	case runErr != nil:
+		status = mysqlModel.LoadTestReportFailed
+	}
+	if err := s.finish(bg, test, report, result, status, runErr, finishedAt); err != nil && runErr == nil {
+		runErr = err
+	}
+	p.SetResult("summary", result.Summary)
+	return runErr
+}
+
+// finish stores the report of a run, compares a succeeded run with its baseline and tells
+// integrations about the outcome
+func (s *LoadTestService) finish(ctx context.Context, test *mysqlModel.LoadTest, report *mysqlModel.LoadTestReport, result *loadtest.Report, status string, runErr error, finishedAt time.Time) error {
+	encoded, err := encodeLoadTestReport(result)
+	if err != nil {
+		return err
+	}
+	updates := map[string]interface{}{
+		"status":        status,
+		"finished_at":   finishedAt,
+		"requests":      result.Summary.Requests,
+		"error_rate":    result.Summary.ErrorRate,
+		"p50_ms":        result.Summary.LatencyMs.P50,
+		"p99_ms":        result.Summary.LatencyMs.P99,
+		"achieved_rps":  result.Summary.AchievedRPS,
+		"peak_replicas": result.Autoscaler.PeakReplicas,
+		"report":        encoded,
+	}
+	if runErr != nil {
+		updates["error"] = truncateReplayError(runErr)
+	}
+
+	var comparison *loadtest.Comparison
+	var baseline *mysqlModel.LoadTestReport
+	if status == mysqlModel.LoadTestReportSucceeded {
+		if baseline, err = s.repo.FindBaseline(ctx, test.Name, report.Image, report.ID); err != nil {
+			logger.WarnCtx(ctx, "failed to find baseline of load test %s: %v", test.Name, err)
+		}
+		if baseline != nil {
+			if base, err := decodeLoadTestReport(baseline); err != nil {
+				logger.WarnCtx(ctx, "failed to read baseline report %d of load test %s: %v", baseline.ID, test.Name, err)
+			} else {
+				comparison = loadtest.Compare(base, result, loadTestThresholds(test))
+				updates["baseline_id"] = baseline.ID
+				updates["regressed"] = comparison.Regressed
+				if updates["comparison"], err = encodeLoadTestReport(comparison); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if _, err := s.repo.UpdateReport(ctx, report.ID, updates); err != nil {
+		return err
+	}
+	logger.InfoCtx(ctx, "load test %s finished (%s): report=%d, requests=%d, errorRate=%.4f, p99=%.0fms, regressed=%v",
+		test.Name, status, report.ID, result.Summary.Requests, result.Summary.ErrorRate, result.Summary.LatencyMs.P99, comparison != nil && comparison.Regressed)
+	if status != mysqlModel.LoadTestReportCancelled {
+		s.notify(ctx, test, report, result, comparison, baseline, status, runErr, finishedAt)
+	}
+	return nil
+}
+
+// notify publishes the outcome of a run; the integrations of the load test receive failed and
+// regressed runs whatever their event filter says
+func (s *LoadTestService) notify(ctx context.Context, test *mysqlModel.LoadTest, report *mysqlModel.LoadTestReport, result *loadtest.Report, comparison *loadtest.Comparison, baseline *mysqlModel.LoadTestReport, status string, runErr error, finishedAt time.Time) {
+	if s.integrationService == nil {
+		return
+	}
+	data := &notification.LoadTestNotification{
+		LoadTest:   test.Name,
+		Endpoint:   test.Endpoint,
+		ReportID:   report.ID,
+		Status:     status,
+		Image:      report.Image,
+		Requests:   result.Summary.Requests,
+		ErrorRate:  result.Summary.ErrorRate,
+		P99Ms:      result.Summary.LatencyMs.P99,
+		FinishedAt: finishedAt,
+	}
+	if runErr != nil {
+		data.Error = runErr.Error()
+	}
+	if baseline != nil {
+		data.BaselineImage = baseline.Image
+	}
+	if comparison != nil {
+		data.Regressed = comparison.Regressed
+		data.Regressions = comparison.Regressions
+	}
+	event := &notification.Event{Type: notification.EventLoadTest, Endpoint: test.Endpoint, CreatedAt: finishedAt, Data: data}
+	s.integrationService.Publish(ctx, event)
+	if status == mysqlModel.LoadTestReportFailed || data.Regressed {
+		for _, name := range test.Notify {
+			go s.integrationService.DeliverTo(context.Background(), name, event, nil)
+		}
+	}
+}
+
+// closeOrphanedReports closes the reports of runs whose operation ended without storing them,
+// e.g. because the replica running it stopped
+func (s *LoadTestService) closeOrphanedReports(ctx context.Context) {
+	if s.operations == nil {
+		return
+	}
+	reports, err := s.repo.ListRunningReports(ctx)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to list running load test reports: %v", err)
+		return
+	}
+	for _, report := range reports {
+		status, reason := mysqlModel.LoadTestReportFailed, "the run stopped before its report was stored"
+		if report.OperationID == "" {
+			if time.Since(report.StartedAt) < loadTestOrphanGrace {
+				continue
+			}
+		} else {
+			op, err := s.operations.Get(ctx, report.OperationID)
+			if err != nil && !errors.Is(err, operation.ErrNotFound) {
+				logger.WarnCtx(ctx, "failed to get operation of load test report %d: %v", report.ID, err)
+				continue
+			}
+			if op != nil {
+				if op.Status == mysqlModel.OperationPending || op.Status == mysqlModel.OperationRunning {
+					continue
+				}
+				if op.Status == mysqlModel.OperationCancelled {
+					status = mysqlModel.LoadTestReportCancelled
+				}
+				if op.Error != "" {
+					reason = op.Error
+				}
+			}
+		}
+		if _, err := s.repo.UpdateReport(ctx, report.ID, map[string]interface{}{
+			"status":      status,
+			"error":       reason,
+			"finished_at": time.Now(),
+		}); err != nil {
+			logger.WarnCtx(ctx, "failed to close report %d of load test %s: %v", report.ID, report.LoadTest, err)
+			continue
+		}
+		logger.InfoCtx(ctx, "closed report %d of load test %s: %s", report.ID, report.LoadTest, reason)
+	}
+}
+
+// stagingEndpoint returns the endpoint of a load test; it must carry the staging labels
+func (s *LoadTestService) stagingEndpoint(ctx context.Context, name string) (*interfaces.EndpointMetadata, error) {
+	meta, err := s.endpointService.GetEndpoint(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("invalid load test: endpoint %s not found", name)
+	}
+	for key, value := range s.config.StagingLabels {
+		if meta.Labels[key] != value {
+			return nil, fmt.Errorf("invalid load test: endpoint %s is not a staging endpoint, it needs the labels %s", name, formatLabels(s.config.StagingLabels))
+		}
+	}
+	return meta, nil
+}
+
+// scalingEvents returns the scaling decisions of an endpoint during a run, oldest first
+func (s *LoadTestService) scalingEvents(ctx context.Context, endpoint string, start, end time.Time) []loadtest.ScalingEvent {
+	if s.scalingEventRepo == nil {
+		return nil
+	}
+	rows, err := s.scalingEventRepo.ListByEndpointAndTimeRange(ctx, endpoint, start, end, loadTestEventLimit)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to list scaling events of %s: %v", endpoint, err)
+		return nil
+	}
+	events := make([]loadtest.ScalingEvent, 0, len(rows))
+	for i := len(rows) - 1; i >= 0; i-- {
+		row := rows[i]
+		events = append(events, loadtest.ScalingEvent{
+			OffsetSeconds: row.Timestamp.Sub(start).Seconds(),
+			Action:        row.Action,
+			FromReplicas:  row.FromReplicas,
+			ToReplicas:    row.ToReplicas,
+			Reason:        row.Reason,
+		})
+	}
+	return events
+}
+
+// loadTestTask is a submitted task whose outcome is not known yet
+type loadTestTask struct {
+	index       int // In requests
+	submittedAt time.Time
+}
+
+// loadTestRunner sends the load of one run and collects its outcome
+type loadTestRunner struct {
+	svc         *LoadTestService
+	test        *mysqlModel.LoadTest
+	profile     loadtest.Profile
+	progress    *operation.Progress
+	taskTimeout time.Duration
+
+	start    time.Time
+	requests []loadtest.Request
+	pending  map[string]*loadTestTask
+	samples  []loadtest.ScalerSample
+	rejected int
+}
+
+// drive submits the requests of the profile as they come due, then waits for the last tasks.
+// It returns ctx.Err() when the run is cancelled.
+func (r *loadTestRunner) drive(ctx context.Context) error {
+	r.start = time.Now()
+	duration, total := r.profile.Duration(), r.profile.Total()
+	r.sample(ctx)
+
+	tick := time.NewTicker(loadTestTick)
+	defer tick.Stop()
+	poll := time.NewTicker(loadTestPollInterval)
+	defer poll.Stop()
+	sample := time.NewTicker(loadTestSampleInterval)
+	defer sample.Stop()
+
+	for len(r.requests) < total {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-tick.C:
+			elapsed := now.Sub(r.start)
+			due := r.profile.Due(elapsed)
+			if elapsed >= duration {
+				due = total
+			}
+			for len(r.requests) < due && ctx.Err() == nil {
+				r.submit(ctx, len(r.requests))
+			}
+		case <-poll.C:
+			r.collect(ctx)
+		case <-sample.C:
+			r.sample(ctx)
+			elapsed := time.Since(r.start)
+			last := r.samples[len(r.samples)-1]
+			r.progress.Update(loadTestSubmitProgress*int(elapsed)/int(duration), fmt.Sprintf(
+				"%d/%d requests sent (%.1f rps target), %d/%d replicas ready, %d tasks queued",
+				len(r.requests), total, r.profile.RateAt(elapsed), last.ReadyReplicas, last.DesiredReplicas, last.Pending))
+		}
+	}
+
+	r.progress.Update(loadTestSubmitProgress, fmt.Sprintf("%d requests sent, waiting for %d tasks", total, len(r.pending)))
+	for len(r.pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-poll.C:
+			r.collect(ctx)
+			r.progress.Update(loadTestSubmitProgress, fmt.Sprintf("%d requests sent, waiting for %d tasks", total, len(r.pending)))
+		case <-sample.C:
+			r.sample(ctx)
+		}
+	}
+	r.sample(ctx)
+	return nil
+}
+
+// submit submits request i with the next payload of the corpus
+func (r *loadTestRunner) submit(ctx context.Context, i int) {
+	now := time.Now()
+	req := loadtest.Request{Submitted: now.Sub(r.start)}
+	resp, err := r.svc.taskService.SubmitTask(ctx, &model.SubmitRequest{
+		Input:    maps.Clone(r.test.Payloads[i%len(r.test.Payloads)]),
+		Endpoint: r.test.Endpoint,
+	})
+	if err != nil {
+		req.Outcome = loadtest.OutcomeRejected
+		r.requests = append(r.requests, req)
+		if r.rejected++; r.rejected == 1 {
+			logger.WarnCtx(ctx, "load test %s: task submission to %s failed: %v", r.test.Name, r.test.Endpoint, err)
+		}
+		return
+	}
+	r.requests = append(r.requests, req)
+	r.pending[resp.ID] = &loadTestTask{index: i, submittedAt: now}
+}
+
+// collect records the outcome of finished tasks, and times out and cancels the tasks that
+// took longer than the task timeout
+func (r *loadTestRunner) collect(ctx context.Context) {
+	ids := make([]string, 0, len(r.pending))
+	for id := range r.pending {
+		ids = append(ids, id)
+	}
+	for from := 0; from < len(ids); from += loadTestPollBatch {
+		to := from + loadTestPollBatch
+		if to > len(ids) {
+			to = len(ids)
+		}
+		tasks, err := r.svc.taskRepo.GetTimings(ctx, ids[from:to])
+		if err != nil {
+			logger.WarnCtx(ctx, "load test %s: %v", r.test.Name, err)
+			return
+		}
+		for _, task := range tasks {
+			r.record(task)
+		}
+	}
+
+	now := time.Now()
+	for id, task := range r.pending {
+		if now.Sub(task.submittedAt) < r.taskTimeout {
+			continue
+		}
+		r.requests[task.index].Outcome = loadtest.OutcomeTimedOut
+		delete(r.pending, id)
+		if err := r.svc.taskService.CancelTask(ctx, id); err != nil {
+			logger.DebugCtx(ctx, "load test %s: failed to cancel timed out task %s: %v", r.test.Name, id, err)
+		}
+	}
+}
+
+// record stores the outcome of a task once it finished
+func (r *loadTestRunner) record(task *mysqlModel.Task) {
+	pending, ok := r.pending[task.TaskID]
+	if !ok {
+		return
+	}
+	var outcome string
+	switch model.TaskStatus(task.Status) {
+	case model.TaskStatusCompleted:
+		outcome = loadtest.OutcomeCompleted
+	case model.TaskStatusFailed, model.TaskStatusCancelled:
+		outcome = loadtest.OutcomeFailed
+	default:
+		return
+	}
+
+	req := &r.requests[pending.index]
+	finishedAt := time.Now()
+	if task.CompletedAt != nil {
+		finishedAt = *task.CompletedAt
+	}
+	req.Latency = finishedAt.Sub(task.CreatedAt)
+	if task.StartedAt != nil {
+		req.Started = true
+		req.QueueWait = task.StartedAt.Sub(task.CreatedAt)
+	}
+	req.Outcome = outcome
+	if outcome == loadtest.OutcomeCompleted && req.Latency > r.taskTimeout {
+		req.Outcome = loadtest.OutcomeTimedOut
+	}
+	delete(r.pending, task.TaskID)
+}
+
+// sample records the replicas and queue of the endpoint
+func (r *loadTestRunner) sample(ctx context.Context) {
+	sample := loadtest.ScalerSample{OffsetSeconds: time.Since(r.start).Seconds()}
+	if status, err := r.svc.endpointService.GetScalingStatus(ctx, r.test.Endpoint); err == nil {
+		sample.DesiredReplicas = status.DesiredReplicas
+		sample.ReadyReplicas = status.ReadyReplicas
+	}
+	if pending, err := r.svc.taskService.GetPendingTaskCount(ctx, r.test.Endpoint); err == nil {
+		sample.Pending = pending
+	}
+	r.samples = append(r.samples, sample)
+}
+
+// abandon cancels the tasks of a stopped run that have not finished; they count as timed out
+func (r *loadTestRunner) abandon(ctx context.Context) {
+	for id, task := range r.pending {
+		r.requests[task.index].Outcome = loadtest.OutcomeTimedOut
+		if err := r.svc.taskService.CancelTask(ctx, id); err != nil {
+			logger.DebugCtx(ctx, "load test %s: failed to cancel task %s: %v", r.test.Name, id, err)
+		}
+	}
+	r.pending = make(map[string]*loadTestTask)
+}
+
+// loadTestProfile returns the RPS profile of a load test
+func loadTestProfile(test *mysqlModel.LoadTest) loadtest.Profile {
+	profile := make(loadtest.Profile, 0, len(test.Profile))
+	for _, stage := range test.Profile {
+		profile = append(profile, loadtest.Stage{DurationSeconds: stage.DurationSeconds, RPS: stage.RPS, Ramp: stage.Ramp})
+	}
+	return profile
+}
+
+// loadTestThresholds returns the regression thresholds of a load test
+func loadTestThresholds(test *mysqlModel.LoadTest) loadtest.Thresholds {
+	return loadtest.Thresholds{
+		MaxLatencyIncrease:   test.MaxLatencyIncrease,
+		MaxErrorRateIncrease: test.MaxErrorRateIncrease,
+	}
+}
+
+// nextLoadTestRun returns the next fire time after now, nil when the load test is suspended,
+// has no cron expression or never fires
+func nextLoadTestRun(test *mysqlModel.LoadTest, cron *cronexpr.Schedule, loc *time.Location, now time.Time) *time.Time {
+	if test.Suspended || cron == nil {
+		return nil
+	}
+	next := cron.Next(now.In(loc))
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// encodeLoadTestReport converts a report or comparison to a JSON column
+func encodeLoadTestReport(v interface{}) (mysqlModel.JSONMap, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode load test report: %w", err)
+	}
+	var m mysqlModel.JSONMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to encode load test report: %w", err)
+	}
+	return m, nil
+}
+
+// decodeLoadTestReport reads the report stored with a run
+func decodeLoadTestReport(report *mysqlModel.LoadTestReport) (*loadtest.Report, error) {
+	if report.Report == nil {
+		return nil, fmt.Errorf("invalid comparison: load test report %d has no results (%s)", report.ID, report.Status)
+	}
+	data, err := json.Marshal(report.Report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode load test report %d: %w", report.ID, err)
+	}
+	var result loadtest.Report
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode load test report %d: %w", report.ID, err)
+	}
+	return &result, nil
+}
+
+// formatLabels renders labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/logship"
+	"waverless/pkg/redact"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// logRedactionCacheTTL bounds how long a rule change takes to reach every replica
+const logRedactionCacheTTL = 30 * time.Second
+
+// SaveLogRedactionRequest replaces an endpoint's log redaction rules with a new version.
+// Saving an empty rule list turns redaction off.
+type SaveLogRedactionRequest struct {
+	Rules   []redact.Rule `json:"rules"`
+	Comment string        `json:"comment,omitempty"` // Change note
+}
+
+// LogRedactionVersion is a stored rule version with its parsed rules
+type LogRedactionVersion struct {
+	Endpoint  string        `json:"endpoint"`
+	Version   int           `json:"version"`
+	Rules     []redact.Rule `json:"rules"`
+	Comment   string        `json:"comment,omitempty"`
+	ChangedBy string        `json:"changedBy,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+type cachedRedactor struct {
+	redactor *redact.Redactor // nil = endpoint has no rules
+	loadedAt time.Time
+}
+
+// LogRedactionService manages versioned per-endpoint log redaction rules and applies them to
+// worker logs on retrieval and before shipping. The version history is the audit trail of
+// rule changes. Redaction fails closed: when the rules cannot be loaded, logs are withheld.
+type LogRedactionService struct {
+	repo *mysql.EndpointLogRedactionRepository
+
+	mu    sync.RWMutex
+	cache map[string]*cachedRedactor
+}
+
+// NewLogRedactionService creates a new log redaction service
+func NewLogRedactionService(repo *mysql.EndpointLogRedactionRepository) *LogRedactionService {
+	return &LogRedactionService{repo: repo, cache: make(map[string]*cachedRedactor)}
+}
+
+// GetCurrent returns the newest rule version of an endpoint
+func (s *LogRedactionService) GetCurrent(ctx context.Context, endpoint string) (*LogRedactionVersion, error) {
+	r, err := s.repo.Latest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("log redaction rules for endpoint %s not found", endpoint)
+	}
+	return toLogRedactionVersion(r)
+}
+
+// ListVersions returns every rule version of an endpoint, newest first
+func (s *LogRedactionService) ListVersions(ctx context.Context, endpoint string) ([]*LogRedactionVersion, error) {
+	rows, err := s.repo.ListVersions(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*LogRedactionVersion, 0, len(rows))
+	for _, r := range rows {
+		v, err := toLogRedactionVersion(r)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// Save validates the rules and stores them as the endpoint's next version
+func (s *LogRedactionService) Save(ctx context.Context, endpoint string, req *SaveLogRedactionRequest, changedBy string) (*LogRedactionVersion, error) {
+	if err := redact.Validate(req.Rules); err != nil {
+		return nil, fmt.Errorf("invalid redaction rules: %w", err)
+	}
+	previous, err := s.repo.Latest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := rulesToMap(req.Rules)
+	if err != nil {
+		return nil, err
+	}
+	r := &model.EndpointLogRedaction{
+		Endpoint:  endpoint,
+		Rules:     doc,
+		Comment:   req.Comment,
+		ChangedBy: changedBy,
+	}
+	if err := s.repo.Create(ctx, r); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.cache, endpoint)
+	s.mu.Unlock()
+
+	saved, err := toLogRedactionVersion(r)
+	if err != nil {
+		return nil, err
+	}
+	var before []redact.Rule
+	if previous != nil {
+		if v, err := toLogRedactionVersion(previous); err == nil {
+			before = v.Rules
+		}
+	}
+	added, removed, changed := diffRedactionRules(before, saved.Rules)
+	logger.InfoCtx(ctx, "[AUDIT] log redaction rules changed: endpoint=%s, version=%d, changedBy=%q, added=%v, removed=%v, changed=%v, comment=%q",
+		endpoint, saved.Version, changedBy, added, removed, changed, req.Comment)
+	return saved, nil
+}
+
+// Redactor returns the compiled rules of an endpoint (nil when it has none), cached for
+// logRedactionCacheTTL
+func (s *LogRedactionService) Redactor(ctx context.Context, endpoint string) (*redact.Redactor, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[endpoint]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < logRedactionCacheTTL {
+		return cached.redactor, nil
+	}
+
+	r, err := s.repo.Latest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var redactor *redact.Redactor
+	if r != nil {
+		v, err := toLogRedactionVersion(r)
+		if err != nil {
+			return nil, err
+		}
+		if redactor, err = redact.Compile(v.Rules); err != nil {
+			return nil, fmt.Errorf("log redaction rules v%d of endpoint %s: %w", v.Version, endpoint, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[endpoint] = &cachedRedactor{redactor: redactor, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return redactor, nil
+}
+
+// RedactEntries masks the lines of shipped or queried log entries of an endpoint in place
+func (s *LogRedactionService) RedactEntries(ctx context.Context, endpoint string, entries []logship.Entry) error {
+	if s == nil || len(entries) == 0 {
+		return nil
+	}
+	redactor, err := s.Redactor(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if redactor.Empty() {
+		return nil
+	}
+	for i := range entries {
+		entries[i].Line = redactor.Redact(entries[i].Line)
+	}
+	return nil
+}
+
+// RedactText masks raw log output of an endpoint (e.g. live pod logs)
+func (s *LogRedactionService) RedactText(ctx context.Context, endpoint, text string) (string, error) {
+	if s == nil {
+		return text, nil
+	}
+	redactor, err := s.Redactor(ctx, endpoint)
+	if err != nil {
+		return "", err
+	}
+	if redactor.Empty() {
+		return text, nil
+	}
+	// Line by line, so no pattern spans two log lines
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = redactor.Redact(line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// diffRedactionRules returns the names of rules added, removed and modified between versions
+func diffRedactionRules(before, after []redact.Rule) (added, removed, changed []string) {
+	old := make(map[string]redact.Rule, len(before))
+	for _, r := range before {
+		old[r.Name] = r
+	}
+	for _, r := range after {
+		prev, ok := old[r.Name]
+		switch {
+		case !ok:
+			added = append(added, r.Name)
+		case prev != r:
+			changed = append(changed, r.Name)
+		}
+		delete(old, r.Name)
+	}
+	for _, r := range before {
+		if _, ok := old[r.Name]; ok {
+			removed = append(removed, r.Name)
+		}
+	}
+	return added, removed, changed
+}
+
+func toLogRedactionVersion(r *model.EndpointLogRedaction) (*LogRedactionVersion, error) {
+	v := &LogRedactionVersion{
+		Endpoint:  r.Endpoint,
+		Version:   r.Version,
+		Comment:   r.Comment,
+		ChangedBy: r.ChangedBy,
+		CreatedAt: r.CreatedAt,
+	}
+	var doc struct {
+		Rules []redact.Rule `json:"rules"`
+	}
+	data, err := json.Marshal(r.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode log redaction rules v%d: %w", r.Version, err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode log redaction rules v%d: %w", r.Version, err)
+	}
+	v.Rules = doc.Rules
+	if v.Rules == nil {
+		v.Rules = []redact.Rule{}
+	}
+	return v, nil
+}
+
+func rulesToMap(rules []redact.Rule) (model.JSONMap, error) {
+	if rules == nil {
+		rules = []redact.Rule{}
+	}
+	data, err := json.Marshal(map[string]interface{}{"rules": rules})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode log redaction rules: %w", err)
+	}
+	doc := make(model.JSONMap)
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to encode log redaction rules: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/logship"
+	"waverless/pkg/store/mysql"
+)
+
+// LogService ships worker logs to external storage and queries them back,
+// so logs remain available after the pod is deleted
+type LogService struct {
+	shipper    *logship.Shipper
+	taskRepo   *mysql.TaskRepository
+	workerRepo *mysql.WorkerRepository
+	redaction  *LogRedactionService
+}
+
+// NewLogService creates a new log service
+func NewLogService(shipper *logship.Shipper, taskRepo *mysql.TaskRepository, workerRepo *mysql.WorkerRepository) *LogService {
+	return &LogService{shipper: shipper, taskRepo: taskRepo, workerRepo: workerRepo}
+}
+
+// SetRedactionService masks log lines with the endpoint's redaction rules on query and
+// before shipping (for dependency injection)
+func (s *LogService) SetRedactionService(redaction *LogRedactionService) {
+	s.redaction = redaction
+	s.shipper.SetRedactor(redaction)
+}
+
+// TaskLogs is the log slice of a single task
+type TaskLogs struct {
+	TaskID   string     `json:"taskId"`
+	Endpoint string     `json:"endpoint"`
+	Worker   string     `json:"worker"`
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end,omitempty"`
+	// Source is "markers" when the slice is bounded by worker task markers, or
+	// "execution" when it falls back to the task's recorded execution window
+	Source string `json:"source"`
+	// Overlapping is set when lines of other tasks running concurrently on the
+	// same worker fall inside the slice
+	Overlapping bool            `json:"overlapping"`
+	Entries     []logship.Entry `json:"entries"`
+}
+
+// Backend returns the log store type (loki, elasticsearch)
+func (s *LogService) Backend() string {
+	return s.shipper.Sink().Name()
+}
+
+// ShipPending ships new log lines of all workers
+func (s *LogService) ShipPending(ctx context.Context) error {
+	return s.shipper.ShipPending(ctx)
+}
+
+// FlushWorker ships the remaining lines of a worker, called before its pod is deleted
+func (s *LogService) FlushWorker(ctx context.Context, endpoint, worker string) {
+	n, err := s.shipper.ShipTarget(ctx, logship.Target{Endpoint: endpoint, Worker: worker})
+	if err != nil {
+		logger.WarnCtx(ctx, "Failed to flush logs of worker %s (endpoint: %s): %v", worker, endpoint, err)
+		return
+	}
+	if n > 0 {
+		logger.InfoCtx(ctx, "Flushed %d log lines of terminating worker %s", n, worker)
+	}
+}
+
+// QueryLogs returns shipped log lines matching the query
+func (s *LogService) QueryLogs(ctx context.Context, q *logship.Query) ([]logship.Entry, error) {
+	entries, err := s.shipper.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	// Lines shipped before a rule was added are masked on the way out
+	if err := s.redaction.RedactEntries(ctx, q.Endpoint, entries); err != nil {
+		return nil, fmt.Errorf("failed to redact logs: %w", err)
+	}
+	return entries, nil
+}
+
+// GetTaskLogs returns the log lines of a task. Returns nil if the task is unknown
+// or never ran on a worker.
+func (s *LogService) GetTaskLogs(ctx context.Context, taskID string, limit int) (*TaskLogs, error) {
+	seg, entries, err := s.shipper.TaskLogs(ctx, taskID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if seg != nil {
+		if err := s.redaction.RedactEntries(ctx, seg.Endpoint, entries); err != nil {
+			return nil, fmt.Errorf("failed to redact task logs: %w", err)
+		}
+		return newTaskLogs(taskID, seg.Endpoint, seg.Worker, seg.Start, seg.End, "markers", entries), nil
+	}
+
+	// Worker emits no markers: fall back to the last execution window of the task
+	task, err := s.taskRepo.Get(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || task.StartedAt == nil || task.WorkerID == "" {
+		return nil, nil
+	}
+	workerID, start, end := task.WorkerID, *task.StartedAt, task.CompletedAt
+	if task.Extend != nil && len(*task.Extend) > 0 {
+		last := (*task.Extend)[len(*task.Extend)-1]
+		workerID, start, end = last.WorkerID, last.StartTime, last.EndTime
+	}
+
+	podName := workerID
+	if worker, err := s.workerRepo.Get(ctx, workerID); err == nil && worker.PodName != "" {
+		podName = worker.PodName
+	}
+
+	q := &logship.Query{Endpoint: task.Endpoint, Worker: podName, Start: start, Limit: limit, Forward: true}
+	if end != nil {
+		q.End = end.Add(time.Millisecond)
+	}
+	entries, err = s.shipper.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task logs: %w", err)
+	}
+	if err := s.redaction.RedactEntries(ctx, task.Endpoint, entries); err != nil {
+		return nil, fmt.Errorf("failed to redact task logs: %w", err)
+	}
+	return newTaskLogs(taskID, task.Endpoint, podName, start, end, "execution", entries), nil
+}
+
+func newTaskLogs(taskID, endpoint, worker string, start time.Time, end *time.Time, source string, entries []logship.Entry) *TaskLogs {
+	result := &TaskLogs{
+		TaskID:   taskID,
+		Endpoint: endpoint,
+		Worker:   worker,
+		Start:    start,
+		End:      end,
+		Source:   source,
+		Entries:  entries,
+	}
+	// Without markers lines carry no task ID, so overlap cannot be detected
+	if source == "markers" {
+		for _, e := range entries {
+			if e.TaskID != taskID {
+				result.Overlapping = true
+				break
+			}
+		}
+	}
+	if result.Entries == nil {
+		result.Entries = []logship.Entry{}
+	}
+	return result
+}
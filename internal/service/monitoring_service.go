@@ -10,7 +10,8 @@ import (
 
 // MonitoringService wraps monitoring.Aggregator for backward compatibility
 type MonitoringService struct {
-	agg *monitoring.Aggregator
+	agg       *monitoring.Aggregator
+	mysqlRepo *mysql.Repository
 }
 
 // NewMonitoringService creates a new monitoring service
@@ -18,6 +19,19 @@ func NewMonitoringService(monitoringRepo *mysql.MonitoringRepository) *Monitorin
 	return &MonitoringService{agg: monitoring.NewAggregator(monitoringRepo)}
 }
 
+// SetRepository sets the MySQL repository reporting runtime_state sync stats (for dependency injection)
+func (s *MonitoringService) SetRepository(repo *mysql.Repository) {
+	s.mysqlRepo = repo
+}
+
+// RuntimeStateSyncStats returns the runtime_state write reduction, nil without a repository
+func (s *MonitoringService) RuntimeStateSyncStats() *mysql.RuntimeStateSyncReport {
+	if s.mysqlRepo == nil {
+		return nil
+	}
+	return s.mysqlRepo.RuntimeStateSyncStats()
+}
+
 func (s *MonitoringService) AggregateMinuteStats(ctx context.Context) error {
 	return s.agg.AggregateMinuteStats(ctx)
 }
@@ -34,6 +48,10 @@ func (s *MonitoringService) GetRealtimeMetrics(ctx context.Context, endpoint str
 	return s.agg.GetRealtimeMetrics(ctx, endpoint)
 }
 
+func (s *MonitoringService) GetColdStartStats(ctx context.Context, endpoint, groupBy string, from, to time.Time) ([]*monitoring.ColdStartStats, error) {
+	return s.agg.GetColdStartStats(ctx, endpoint, groupBy, from, to)
+}
+
 func (s *MonitoringService) GetMinuteStats(ctx context.Context, endpoint string, from, to time.Time) ([]*monitoring.MinuteStatResponse, error) {
 	return s.agg.GetMinuteStats(ctx, endpoint, from, to)
 }
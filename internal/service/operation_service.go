@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/operation"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// Operation limits
+const (
+	DefaultOperationListLimit = 50
+	MaxOperationListLimit     = 500
+
+	// OperationRolloutTimeout bounds how long deploy and rollout operations wait for workers
+	OperationRolloutTimeout = 30 * time.Minute
+)
+
+// ErrBlueGreenInProgress is returned when an endpoint already has a blue/green update running
+var ErrBlueGreenInProgress = errors.New("blue/green update in progress")
+
+// ErrLoadTestInProgress is returned when an endpoint already has a load test running
+var ErrLoadTestInProgress = errors.New("load test in progress")
+
+// Progress of deploy and rollout operations: applying the change, then waiting for workers
+const (
+	progressApplied     = 30
+	progressWorkersSpan = 100 - progressApplied
+)
+
+// OperationService runs deploys, rollouts, blue/green updates and load tests as long-running
+// operations, pollable by ID on any replica. GPU usage backfills, replay jobs and batch jobs
+// keep their own state and are listed, polled and cancelled through it as well.
+type OperationService struct {
+	runner          *operation.Runner
+	endpointService *endpointsvc.Service
+	gpuUsageService *GPUUsageService
+	blueGreen       *BlueGreenService
+	sources         []operationSource
+}
+
+// NewOperationService creates a new operation service; owner identifies this replica
+func NewOperationService(repo *mysql.OperationRepository, owner string, endpointService *endpointsvc.Service) *OperationService {
+	return &OperationService{
+		runner:          operation.NewRunner(repo, owner),
+		endpointService: endpointService,
+	}
+}
+
+// SetGPUUsageService enables GPU usage backfill operations
+func (s *OperationService) SetGPUUsageService(svc *GPUUsageService) {
+	s.gpuUsageService = svc
+	s.sources = append(s.sources, gpuUsageBackfillSource{svc: svc})
+}
+
+// SetTaskReplayService exposes replay jobs as operations
+func (s *OperationService) SetTaskReplayService(svc *TaskReplayService) {
+	s.sources = append(s.sources, taskReplaySource{svc: svc})
+}
+
+// SetBatchJobService exposes batch jobs as operations
+func (s *OperationService) SetBatchJobService(svc *BatchJobService) {
+	s.sources = append(s.sources, batchJobSource{svc: svc})
+}
+
+// SetBlueGreenService enables blue/green update operations
+func (s *OperationService) SetBlueGreenService(svc *BlueGreenService) {
+	s.blueGreen = svc
+}
+
+// Get returns an operation
+func (s *OperationService) Get(ctx context.Context, id string) (*model.Operation, error) {
+	source, jobID := s.source(id)
+	if source == nil {
+		return s.runner.Get(ctx, id)
+	}
+	op, err := source.get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil {
+		return nil, fmt.Errorf("%w: %s", operation.ErrNotFound, id)
+	}
+	return op, nil
+}
+
+// List returns the most recent operations matching the filter, newest first, skipping the
+// first offset. When jobs are listed as well, every backend returns its first offset+limit
+// and the page is cut from the merged list, since the position of an operation depends on
+// the others.
+func (s *OperationService) List(ctx context.Context, filter mysql.OperationFilter, limit, offset int) ([]*model.Operation, error) {
+	if limit <= 0 {
+		limit = DefaultOperationListLimit
+	}
+	if limit > MaxOperationListLimit {
+		limit = MaxOperationListLimit
+	}
+	sources := s.matchingSources(filter)
+	if len(sources) == 0 {
+		return s.runner.List(ctx, filter, limit, offset)
+	}
+	ops, err := s.runner.List(ctx, filter, offset+limit, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		jobs, err := source.list(ctx, filter.Target, filter.Status, offset+limit, 0)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, jobs...)
+	}
+	sort.SliceStable(ops, func(i, j int) bool { return ops[i].CreatedAt.After(ops[j].CreatedAt) })
+	if offset >= len(ops) {
+		return []*model.Operation{}, nil
+	}
+	ops = ops[offset:]
+	if len(ops) > limit {
+		ops = ops[:limit]
+	}
+	return ops, nil
+}
+
+// Count returns the number of operations matching the filter, jobs included
+func (s *OperationService) Count(ctx context.Context, filter mysql.OperationFilter) (int64, error) {
+	total, err := s.runner.Count(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	for _, source := range s.matchingSources(filter) {
+		count, err := source.count(ctx, filter.Target, filter.Status)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// matchingSources returns the sources whose jobs the filter's type admits
+func (s *OperationService) matchingSources(filter mysql.OperationFilter) []operationSource {
+	var sources []operationSource
+	for _, source := range s.sources {
+		if filter.Type == "" || filter.Type == source.operationType() {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// Cancel cancels a pending or running operation
+func (s *OperationService) Cancel(ctx context.Context, id, cancelledBy string) (*model.Operation, error) {
+	source, jobID := s.source(id)
+	if source == nil {
+		return s.runner.Cancel(ctx, id)
+	}
+	op, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if op.Done() {
+		return op, fmt.Errorf("%w: %s is %s", operation.ErrFinished, id, op.Status)
+	}
+	return source.cancel(ctx, jobID, cancelledBy)
+}
+
+// source returns the source of the job an operation ID refers to and the job's own ID, nil
+// for the operations of the runner
+func (s *OperationService) source(id string) (operationSource, string) {
+	for _, source := range s.sources {
+		if jobID, ok := strings.CutPrefix(id, source.prefix()); ok {
+			return source, jobID
+		}
+	}
+	return nil, ""
+}
+
+// FailStale fails the operations of replicas that stopped sending heartbeats
+func (s *OperationService) FailStale(ctx context.Context) error {
+	_, err := s.runner.FailStale(ctx)
+	return err
+}
+
+// StartDeploy creates an endpoint in the background and waits for its workers
+func (s *OperationService) StartDeploy(ctx context.Context, req *interfaces.DeployRequest, metadata *interfaces.EndpointMetadata, createdBy string) (*model.Operation, error) {
+	if s.endpointService == nil {
+		return nil, fmt.Errorf("endpoint service not configured")
+	}
+	return s.runner.Start(ctx, model.OperationTypeDeploy, req.Endpoint, createdBy, func(ctx context.Context, p *operation.Progress) error {
+		p.Update(0, "deploying")
+		resp, err := s.endpointService.Deploy(ctx, req, metadata)
+		if err != nil {
+			return err
+		}
+		p.SetResult("endpoint", resp.Endpoint)
+		p.SetResult("message", resp.Message)
+		return s.waitForWorkers(ctx, req.Endpoint, p)
+	})
+}
+
+// StartRollout updates a deployment in the background and waits until every worker runs the
+// new spec. Cancelling stops the wait; the provider finishes the rollout on its own.
+func (s *OperationService) StartRollout(ctx context.Context, req *interfaces.UpdateDeploymentRequest, createdBy string) (*model.Operation, error) {
+	if s.endpointService == nil {
+		return nil, fmt.Errorf("endpoint service not configured")
+	}
+	return s.runner.Start(ctx, model.OperationTypeRollout, req.Endpoint, createdBy, func(ctx context.Context, p *operation.Progress) error {
+		p.Update(0, "updating deployment")
+		resp, err := s.endpointService.UpdateDeployment(ctx, req)
+		if err != nil {
+			return err
+		}
+		p.SetResult("endpoint", resp.Endpoint)
+		p.SetResult("message", resp.Message)
+		return s.waitForWorkers(ctx, req.Endpoint, p)
+	})
+}
+
+// StartBlueGreen runs a blue/green update in the background. Only one runs per endpoint at a
+// time; cancelling rolls it back unless the live deployment is already being updated.
+func (s *OperationService) StartBlueGreen(ctx context.Context, req *interfaces.UpdateDeploymentRequest, createdBy string) (*model.Operation, error) {
+	if s.blueGreen == nil {
+		return nil, endpointsvc.ErrBlueGreenUnsupported
+	}
+	if err := s.blueGreen.ValidateBlueGreen(ctx, req); err != nil {
+		return nil, err
+	}
+	active, err := s.active(ctx, model.OperationTypeBlueGreen, req.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, fmt.Errorf("%w: blue/green update %s of %s is in progress", ErrBlueGreenInProgress, active.ID, req.Endpoint)
+	}
+	return s.runner.Start(ctx, model.OperationTypeBlueGreen, req.Endpoint, createdBy, func(ctx context.Context, p *operation.Progress) error {
+		err := s.blueGreen.Run(ctx, req, createdBy, p.Update)
+		if IsBlueGreenRollback(err) {
+			p.SetResult("rolledBack", true)
+		}
+		return err
+	})
+}
+
+// StartLoadTest runs a load test of an endpoint in the background. Only one runs per endpoint
+// at a time, so runs do not skew each other's results.
+func (s *OperationService) StartLoadTest(ctx context.Context, endpoint, createdBy string, run operation.Func) (*model.Operation, error) {
+	active, err := s.active(ctx, model.OperationTypeLoadTest, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, fmt.Errorf("%w: load test %s of %s is in progress", ErrLoadTestInProgress, active.ID, endpoint)
+	}
+	return s.runner.Start(ctx, model.OperationTypeLoadTest, endpoint, createdBy, run)
+}
+
+// StartGPUUsageBackfill creates a resumable, throttled backfill job for tasks in [from, to).
+// The job is advanced in slices by any replica and ends with a consistency report.
+func (s *OperationService) StartGPUUsageBackfill(ctx context.Context, from, to time.Time, batchSize, rateLimit int, createdBy string) (*model.Operation, error) {
+	if s.gpuUsageService == nil {
+		return nil, fmt.Errorf("gpu usage service not configured")
+	}
+	view, err := s.gpuUsageService.CreateBackfillJob(ctx, from, to, batchSize, rateLimit, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	return backfillOperation(view), nil
+}
+
+// active returns a pending or running operation of a type on target, nil if there is none
+func (s *OperationService) active(ctx context.Context, opType, target string) (*model.Operation, error) {
+	for _, status := range []string{model.OperationPending, model.OperationRunning} {
+		ops, err := s.runner.List(ctx, mysql.OperationFilter{Type: opType, Target: target, Status: status}, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(ops) > 0 {
+			return ops[0], nil
+		}
+	}
+	return nil, nil
+}
+
+// waitForWorkers waits for the rollout of an endpoint, moving progress with its ready workers
+func (s *OperationService) waitForWorkers(ctx context.Context, endpoint string, p *operation.Progress) error {
+	p.Update(progressApplied, "waiting for workers")
+	ctx, cancel := context.WithTimeout(ctx, OperationRolloutTimeout)
+	defer cancel()
+	err := s.endpointService.WaitForRollout(ctx, endpoint, 0, func(status *interfaces.AppStatus) {
+		percent := progressApplied
+		if status.TotalReplicas > 0 {
+			ready := status.ReadyReplicas
+			if ready > status.TotalReplicas {
+				ready = status.TotalReplicas
+			}
+			percent += progressWorkersSpan * int(ready) / int(status.TotalReplicas)
+		}
+		p.Update(percent, fmt.Sprintf("%d/%d workers ready (%s)", status.ReadyReplicas, status.TotalReplicas, status.Status))
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("workers not rolled out within %s", OperationRolloutTimeout)
+	}
+	return err
+}
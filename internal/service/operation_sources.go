@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"waverless/pkg/gpuusage"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/store/mysql/model"
+)
+
+// Operation ID prefixes of the jobs exposed as operations
+const (
+	gpuUsageBackfillOperationPrefix = "gpu-usage-backfill-"
+	taskReplayOperationPrefix       = "replay-"
+	batchJobOperationPrefix         = "job-"
+)
+
+// operationSource exposes jobs that keep their own state as operations. Resumable GPU usage
+// backfills and replay jobs are advanced in slices by whichever replica holds their lock, and
+// batch jobs run in the provider, so unlike the operations of the runner they carry on when
+// the replica that started them stops.
+type operationSource interface {
+	// operationType is the operation type of the source's jobs
+	operationType() string
+	// prefix starts the operation IDs of the source's jobs
+	prefix() string
+	// get returns a job by the ID without the prefix, nil if there is none
+	get(ctx context.Context, id string) (*model.Operation, error)
+	// list returns the most recent jobs matching target and an operation status, skipping
+	// the first offset
+	list(ctx context.Context, target, status string, limit, offset int) ([]*model.Operation, error)
+	// count returns the number of jobs matching target and an operation status
+	count(ctx context.Context, target, status string) (int64, error)
+	// cancel stops a pending or running job
+	cancel(ctx context.Context, id, cancelledBy string) (*model.Operation, error)
+}
+
+// gpuUsageBackfillSource exposes resumable GPU usage backfill jobs
+type gpuUsageBackfillSource struct {
+	svc *GPUUsageService
+}
+
+func (gpuUsageBackfillSource) operationType() string { return model.OperationTypeGPUUsageBackfill }
+
+func (gpuUsageBackfillSource) prefix() string { return gpuUsageBackfillOperationPrefix }
+
+func (src gpuUsageBackfillSource) get(ctx context.Context, id string) (*model.Operation, error) {
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	job, err := src.svc.repo.GetBackfillJob(ctx, jobID)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	return backfillOperation(gpuusage.NewBackfillJobView(job)), nil
+}
+
+func (src gpuUsageBackfillSource) list(ctx context.Context, target, status string, limit, offset int) ([]*model.Operation, error) {
+	if target != "" {
+		// Backfills cover every endpoint
+		return nil, nil
+	}
+	views, err := src.svc.ListBackfillJobs(ctx, backfillStatus(status), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]*model.Operation, 0, len(views))
+	for _, view := range views {
+		ops = append(ops, backfillOperation(view))
+	}
+	return ops, nil
+}
+
+func (src gpuUsageBackfillSource) count(ctx context.Context, target, status string) (int64, error) {
+	if target != "" {
+		return 0, nil
+	}
+	return src.svc.CountBackfillJobs(ctx, backfillStatus(status))
+}
+
+// backfillStatus returns the backfill job status of an operation status
+func backfillStatus(status string) string {
+	if status == model.OperationSucceeded {
+		return model.GPUUsageBackfillCompleted
+	}
+	return status
+}
+
+func (src gpuUsageBackfillSource) cancel(ctx context.Context, id, cancelledBy string) (*model.Operation, error) {
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	view, err := src.svc.CancelBackfillJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return backfillOperation(view), nil
+}
+
+// backfillOperation presents a GPU usage backfill job as an operation; its progress splits
+// evenly between creating the records and re-aggregating the days
+func backfillOperation(view *gpuusage.BackfillJobView) *model.Operation {
+	job := view.GPUUsageBackfillJob
+	status := job.Status
+	if status == model.GPUUsageBackfillCompleted {
+		status = model.OperationSucceeded
+	}
+	return &model.Operation{
+		ID:       fmt.Sprintf("%s%d", gpuUsageBackfillOperationPrefix, job.ID),
+		Type:     model.OperationTypeGPUUsageBackfill,
+		Status:   status,
+		Progress: int((view.RecordsPercent + view.ReaggregatePercent) / 2),
+		Message: fmt.Sprintf("%s: %d/%d tasks processed, %d/%d days re-aggregated",
+			job.Phase, job.TasksProcessed, job.TotalTasks, job.DaysReaggregated, view.TotalDays),
+		Error:       job.LastError,
+		Result:      model.JSONMap{"backfill": view},
+		CreatedBy:   job.CreatedBy,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
+
+// taskReplaySource exposes replay jobs; their target is the endpoint replayed into
+type taskReplaySource struct {
+	svc *TaskReplayService
+}
+
+func (taskReplaySource) operationType() string { return model.OperationTypeTaskReplay }
+
+func (taskReplaySource) prefix() string { return taskReplayOperationPrefix }
+
+func (src taskReplaySource) get(ctx context.Context, id string) (*model.Operation, error) {
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	job, err := src.svc.repo.Get(ctx, jobID)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	counts, err := src.svc.taskRepo.CountReplaysByStatus(ctx, "", job.ID)
+	if err != nil {
+		return nil, err
+	}
+	return replayOperation(&ReplayJobView{TaskReplayJob: job, Tasks: counts}), nil
+}
+
+func (src taskReplaySource) list(ctx context.Context, target, status string, limit, offset int) ([]*model.Operation, error) {
+	jobs, err := src.svc.ListJobs(ctx, replayStatus(status), target, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]*model.Operation, 0, len(jobs))
+	for _, job := range jobs {
+		ops = append(ops, replayOperation(&ReplayJobView{TaskReplayJob: job}))
+	}
+	return ops, nil
+}
+
+func (src taskReplaySource) count(ctx context.Context, target, status string) (int64, error) {
+	return src.svc.CountJobs(ctx, replayStatus(status), target)
+}
+
+// replayStatus returns the replay job status of an operation status
+func replayStatus(status string) string {
+	if status == model.OperationSucceeded {
+		return model.TaskReplayCompleted
+	}
+	return status
+}
+
+func (src taskReplaySource) cancel(ctx context.Context, id, cancelledBy string) (*model.Operation, error) {
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	view, err := src.svc.CancelJob(ctx, jobID, cancelledBy)
+	if err != nil {
+		return nil, err
+	}
+	return replayOperation(view), nil
+}
+
+// replayOperation presents a replay job as an operation. The tasks of the window are not
+// counted up front, so the progress is only known once the job completes.
+func replayOperation(view *ReplayJobView) *model.Operation {
+	job := view.TaskReplayJob
+	op := &model.Operation{
+		ID:          fmt.Sprintf("%s%d", taskReplayOperationPrefix, job.ID),
+		Type:        model.OperationTypeTaskReplay,
+		Target:      job.TargetEndpoint,
+		Status:      job.Status,
+		Message:     fmt.Sprintf("%d submitted, %d skipped of at most %d", job.Submitted, job.Skipped, job.MaxTasks),
+		Error:       job.LastError,
+		Result:      model.JSONMap{"replay": view},
+		CreatedBy:   job.CreatedBy,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+	if job.Status == model.TaskReplayCompleted {
+		op.Status = model.OperationSucceeded
+		op.Progress = 100
+	}
+	return op
+}
+
+// batchJobSource exposes batch jobs; their target is the job name. Job statuses are the
+// operation statuses already.
+type batchJobSource struct {
+	svc *BatchJobService
+}
+
+func (batchJobSource) operationType() string { return model.OperationTypeBatchJob }
+
+func (batchJobSource) prefix() string { return batchJobOperationPrefix }
+
+func (src batchJobSource) get(ctx context.Context, name string) (*model.Operation, error) {
+	job, err := src.svc.repo.Get(ctx, name)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	src.svc.refresh(ctx, job)
+	return batchJobOperation(job), nil
+}
+
+func (src batchJobSource) list(ctx context.Context, target, status string, limit, offset int) ([]*model.Operation, error) {
+	if target != "" {
+		op, err := src.named(ctx, target, status)
+		if err != nil || op == nil || offset > 0 {
+			return nil, err
+		}
+		return []*model.Operation{op}, nil
+	}
+	// The repository is read directly: the service caps its lists below the depth of a merged page
+	jobs, err := src.svc.repo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]*model.Operation, 0, len(jobs))
+	for _, job := range jobs {
+		ops = append(ops, batchJobOperation(job))
+	}
+	return ops, nil
+}
+
+func (src batchJobSource) count(ctx context.Context, target, status string) (int64, error) {
+	if target != "" {
+		op, err := src.named(ctx, target, status)
+		if err != nil || op == nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+	return src.svc.Count(ctx, status)
+}
+
+// named returns the job a target names if it has the status, nil otherwise
+func (src batchJobSource) named(ctx context.Context, name, status string) (*model.Operation, error) {
+	op, err := src.get(ctx, name)
+	if err != nil || op == nil || (status != "" && op.Status != status) {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (src batchJobSource) cancel(ctx context.Context, name, cancelledBy string) (*model.Operation, error) {
+	job, err := src.svc.Cancel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return batchJobOperation(job), nil
+}
+
+// batchJobOperation presents a batch job as an operation
+func batchJobOperation(job *model.BatchJob) *model.Operation {
+	op := &model.Operation{
+		ID:          batchJobOperationPrefix + job.Name,
+		Type:        model.OperationTypeBatchJob,
+		Target:      job.Name,
+		Status:      job.Status,
+		Message:     job.Message,
+		Result:      model.JSONMap{"job": job},
+		CreatedBy:   job.CreatedBy,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+	switch job.Status {
+	case interfaces.JobPhaseSucceeded:
+		op.Progress = 100
+	case interfaces.JobPhaseFailed:
+		op.Error = job.Message
+	}
+	return op
+}
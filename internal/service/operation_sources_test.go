@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"waverless/pkg/gpuusage"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/operation"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRunnerStore holds the runner's operations; only listing and lookups are exercised
+type fakeRunnerStore struct {
+	ops []*model.Operation
+}
+
+func (f *fakeRunnerStore) Create(ctx context.Context, op *model.Operation) error { return nil }
+
+func (f *fakeRunnerStore) Get(ctx context.Context, id string) (*model.Operation, error) {
+	for _, op := range f.ops {
+		if op.ID == id {
+			return op, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeRunnerStore) List(ctx context.Context, filter mysql.OperationFilter, limit, offset int) ([]*model.Operation, error) {
+	ops := f.matching(filter)
+	if offset >= len(ops) {
+		return nil, nil
+	}
+	ops = ops[offset:]
+	if len(ops) > limit {
+		ops = ops[:limit]
+	}
+	return ops, nil
+}
+
+func (f *fakeRunnerStore) Count(ctx context.Context, filter mysql.OperationFilter) (int64, error) {
+	return int64(len(f.matching(filter))), nil
+}
+
+func (f *fakeRunnerStore) matching(filter mysql.OperationFilter) []*model.Operation {
+	var ops []*model.Operation
+	for _, op := range f.ops {
+		if filter.Type == "" || op.Type == filter.Type {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+func (f *fakeRunnerStore) SaveProgress(ctx context.Context, op *model.Operation) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeRunnerStore) Finish(ctx context.Context, op *model.Operation) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeRunnerStore) Cancel(ctx context.Context, id string) (bool, error) { return false, nil }
+
+func (f *fakeRunnerStore) FailStale(ctx context.Context, before time.Time, reason string) (int64, error) {
+	return 0, nil
+}
+
+// fakeOperationSource serves jobs by their ID without the prefix
+type fakeOperationSource struct {
+	opType, idPrefix string
+	jobs             map[string]*model.Operation
+	listed           []*model.Operation
+	cancelled        []string
+}
+
+func (f *fakeOperationSource) operationType() string { return f.opType }
+
+func (f *fakeOperationSource) prefix() string { return f.idPrefix }
+
+func (f *fakeOperationSource) get(ctx context.Context, id string) (*model.Operation, error) {
+	return f.jobs[id], nil
+}
+
+func (f *fakeOperationSource) list(ctx context.Context, target, status string, limit, offset int) ([]*model.Operation, error) {
+	if offset >= len(f.listed) {
+		return nil, nil
+	}
+	listed := f.listed[offset:]
+	if len(listed) > limit {
+		listed = listed[:limit]
+	}
+	return listed, nil
+}
+
+func (f *fakeOperationSource) count(ctx context.Context, target, status string) (int64, error) {
+	return int64(len(f.listed)), nil
+}
+
+func (f *fakeOperationSource) cancel(ctx context.Context, id, cancelledBy string) (*model.Operation, error) {
+	f.cancelled = append(f.cancelled, id+" by "+cancelledBy)
+	op := *f.jobs[id]
+	op.Status = model.OperationCancelled
+	return &op, nil
+}
+
+var operationsNow = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func testOperation(id, opType, status string, age time.Duration) *model.Operation {
+	return &model.Operation{ID: id, Type: opType, Status: status, CreatedAt: operationsNow.Add(-age)}
+}
+
+func newTestOperationService(store *fakeRunnerStore, sources ...operationSource) *OperationService {
+	return &OperationService{runner: operation.NewRunner(store, "replica-1"), sources: sources}
+}
+
+func operationIDs(ops []*model.Operation) []string {
+	ids := []string{}
+	for _, op := range ops {
+		ids = append(ids, op.ID)
+	}
+	return ids
+}
+
+func TestOperationService_ListMergesSources(t *testing.T) {
+	store := &fakeRunnerStore{ops: []*model.Operation{
+		testOperation("op-rollout", model.OperationTypeRollout, model.OperationRunning, time.Minute),
+		testOperation("op-deploy", model.OperationTypeDeploy, model.OperationSucceeded, time.Hour),
+	}}
+	replays := &fakeOperationSource{opType: model.OperationTypeTaskReplay, idPrefix: "replay-", listed: []*model.Operation{
+		testOperation("replay-2", model.OperationTypeTaskReplay, model.OperationRunning, 30*time.Second),
+		testOperation("replay-1", model.OperationTypeTaskReplay, model.OperationSucceeded, 2*time.Hour),
+	}}
+	jobs := &fakeOperationSource{opType: model.OperationTypeBatchJob, idPrefix: "job-", listed: []*model.Operation{
+		testOperation("job-eval", model.OperationTypeBatchJob, model.OperationPending, 10*time.Minute),
+	}}
+	svc := newTestOperationService(store, replays, jobs)
+
+	ctx := context.Background()
+
+	ops, err := svc.List(ctx, mysql.OperationFilter{}, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replay-2", "op-rollout", "job-eval", "op-deploy", "replay-1"}, operationIDs(ops))
+
+	// Limit and offset apply to the merged list
+	ops, err = svc.List(ctx, mysql.OperationFilter{}, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replay-2", "op-rollout"}, operationIDs(ops))
+	ops, err = svc.List(ctx, mysql.OperationFilter{}, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"job-eval", "op-deploy"}, operationIDs(ops))
+	ops, err = svc.List(ctx, mysql.OperationFilter{}, 2, 6)
+	require.NoError(t, err)
+	assert.Empty(t, ops)
+
+	total, err := svc.Count(ctx, mysql.OperationFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+
+	// A type filter skips the other sources
+	ops, err = svc.List(ctx, mysql.OperationFilter{Type: model.OperationTypeBatchJob}, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"job-eval"}, operationIDs(ops))
+	total, err = svc.Count(ctx, mysql.OperationFilter{Type: model.OperationTypeRollout})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestOperationService_GetAndCancelThroughSources(t *testing.T) {
+	store := &fakeRunnerStore{ops: []*model.Operation{
+		testOperation("op-rollout", model.OperationTypeRollout, model.OperationSucceeded, time.Minute),
+	}}
+	replays := &fakeOperationSource{opType: model.OperationTypeTaskReplay, idPrefix: "replay-", jobs: map[string]*model.Operation{
+		"7": testOperation("replay-7", model.OperationTypeTaskReplay, model.OperationRunning, time.Minute),
+		"8": testOperation("replay-8", model.OperationTypeTaskReplay, model.OperationSucceeded, time.Minute),
+	}}
+	svc := newTestOperationService(store, replays)
+	ctx := context.Background()
+
+	op, err := svc.Get(ctx, "replay-7")
+	require.NoError(t, err)
+	assert.Equal(t, model.OperationTypeTaskReplay, op.Type)
+
+	op, err = svc.Get(ctx, "op-rollout")
+	require.NoError(t, err)
+	assert.Equal(t, model.OperationTypeRollout, op.Type)
+
+	_, err = svc.Get(ctx, "replay-9")
+	assert.ErrorIs(t, err, operation.ErrNotFound)
+
+	op, err = svc.Cancel(ctx, "replay-7", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, model.OperationCancelled, op.Status)
+	assert.Equal(t, []string{"7 by alice"}, replays.cancelled)
+
+	_, err = svc.Cancel(ctx, "replay-8", "alice")
+	assert.ErrorIs(t, err, operation.ErrFinished)
+	_, err = svc.Cancel(ctx, "replay-9", "alice")
+	assert.ErrorIs(t, err, operation.ErrNotFound)
+	assert.Len(t, replays.cancelled, 1)
+}
+
+func TestJobOperations(t *testing.T) {
+	day := operationsNow.Truncate(24 * time.Hour)
+	backfill := backfillOperation(gpuusage.NewBackfillJobView(&model.GPUUsageBackfillJob{
+		ID: 3, Status: model.GPUUsageBackfillRunning, Phase: model.GPUUsageBackfillPhaseReaggregate,
+		FromTime: day.AddDate(0, 0, -4), ToTime: day, TotalTasks: 100, TasksProcessed: 100, DaysReaggregated: 2,
+	}))
+	assert.Equal(t, "gpu-usage-backfill-3", backfill.ID)
+	assert.Equal(t, model.OperationRunning, backfill.Status)
+	assert.Equal(t, 75, backfill.Progress) // Records done, half of the days re-aggregated
+	assert.Equal(t, "reaggregate: 100/100 tasks processed, 2/4 days re-aggregated", backfill.Message)
+
+	completed := backfillOperation(gpuusage.NewBackfillJobView(&model.GPUUsageBackfillJob{
+		ID: 4, Status: model.GPUUsageBackfillCompleted, Phase: model.GPUUsageBackfillPhaseReport, FromTime: day, ToTime: day.Add(time.Hour),
+	}))
+	assert.Equal(t, model.OperationSucceeded, completed.Status)
+	assert.Equal(t, 100, completed.Progress)
+
+	replay := replayOperation(&ReplayJobView{TaskReplayJob: &model.TaskReplayJob{
+		ID: 7, Status: model.TaskReplayCompleted, TargetEndpoint: "flux-staging", Submitted: 40, Skipped: 2, MaxTasks: 1000,
+	}})
+	assert.Equal(t, "replay-7", replay.ID)
+	assert.Equal(t, "flux-staging", replay.Target)
+	assert.Equal(t, model.OperationSucceeded, replay.Status)
+	assert.Equal(t, 100, replay.Progress)
+
+	failed := batchJobOperation(&model.BatchJob{Name: "eval", Status: interfaces.JobPhaseFailed, Message: "exit code 1"})
+	assert.Equal(t, "job-eval", failed.ID)
+	assert.Equal(t, "eval", failed.Target)
+	assert.Equal(t, model.OperationFailed, failed.Status)
+	assert.Equal(t, "exit code 1", failed.Error)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/logger"
+	"waverless/pkg/notification"
+	"waverless/pkg/quota"
+	"waverless/pkg/store/mysql"
+)
+
+// ProjectQuotaStatus is the quota usage and forecast of one project
+type ProjectQuotaStatus struct {
+	Project   string              `json:"project"`
+	Quota     config.ProjectQuota `json:"quota"`
+	Endpoints []string            `json:"endpoints"`
+	Level     string              `json:"level"` // Worst level of the resources
+	Resources []*quota.Headroom   `json:"resources"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+// QuotaService forecasts the headroom of the configured project quotas. Current usage is the
+// desired replicas (and their GPUs) of the project's endpoints; the trend is fitted on the daily
+// peak of the hourly worker statistics. Warnings are published as project.quota integration
+// events at most once per cooldown, project and resource.
+type QuotaService struct {
+	endpointRepo       *mysql.EndpointRepository
+	specRepo           *mysql.SpecRepository
+	monitoringRepo     *mysql.MonitoringRepository
+	integrationService *IntegrationService // nil = no alerts
+	config             config.QuotaConfig
+
+	mu        sync.Mutex
+	lastAlert map[string]time.Time // project/resource -> last alert
+}
+
+// NewQuotaService creates a new quota service
+func NewQuotaService(endpointRepo *mysql.EndpointRepository, specRepo *mysql.SpecRepository, monitoringRepo *mysql.MonitoringRepository, integrationService *IntegrationService, cfg config.QuotaConfig) *QuotaService {
+	return &QuotaService{
+		endpointRepo:       endpointRepo,
+		specRepo:           specRepo,
+		monitoringRepo:     monitoringRepo,
+		integrationService: integrationService,
+		config:             cfg,
+		lastAlert:          make(map[string]time.Time),
+	}
+}
+
+// Status returns the quota status of every configured project, sorted by name
+func (s *QuotaService) Status(ctx context.Context) ([]*ProjectQuotaStatus, error) {
+	byProject, gpuSpecs, err := s.projectEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	projects := make([]string, 0, len(s.config.Projects))
+	for project := range s.config.Projects {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	statuses := make([]*ProjectQuotaStatus, 0, len(projects))
+	for _, project := range projects {
+		status, err := s.evaluate(ctx, project, byProject[project], gpuSpecs, now)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Project returns the quota status of one project, nil if the project has no quota
+func (s *QuotaService) Project(ctx context.Context, project string) (*ProjectQuotaStatus, error) {
+	if _, ok := s.config.Projects[project]; !ok {
+		return nil, nil
+	}
+	byProject, gpuSpecs, err := s.projectEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.evaluate(ctx, project, byProject[project], gpuSpecs, time.Now())
+}
+
+// CheckAll evaluates every project and publishes the warnings outside their cooldown
+func (s *QuotaService) CheckAll(ctx context.Context) error {
+	statuses, err := s.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		for _, h := range status.Resources {
+			if h.Level == quota.LevelOK || !s.acquire(status.Project, h.Resource, status.UpdatedAt) {
+				continue
+			}
+			logger.WarnCtx(ctx, "project %s quota %s: %s", status.Project, h.Level, h.Reason)
+			s.integrationService.Publish(ctx, &notification.Event{
+				Type:      notification.EventQuota,
+				CreatedAt: status.UpdatedAt,
+				Data: &notification.QuotaNotification{
+					Project:          status.Project,
+					Resource:         h.Resource,
+					Level:            h.Level,
+					Limit:            h.Limit,
+					Used:             h.Used,
+					TrendPerDay:      h.TrendPerDay,
+					DaysToExhaustion: h.DaysToExhaustion,
+					Reason:           h.Reason,
+					DetectedAt:       status.UpdatedAt,
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// projectEndpoints groups the endpoints by project and returns the names of the GPU specs
+func (s *QuotaService) projectEndpoints(ctx context.Context) (map[string][]*mysql.Endpoint, map[string]bool, error) {
+	endpoints, err := s.endpointRepo.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	specs, err := s.specRepo.List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list specs: %w", err)
+	}
+
+	gpuSpecs := make(map[string]bool)
+	for _, spec := range specs {
+		if spec.Category == "gpu" {
+			gpuSpecs[spec.Name] = true
+		}
+	}
+	byProject := make(map[string][]*mysql.Endpoint)
+	for _, ep := range endpoints {
+		if project, _ := ep.Labels[s.config.ProjectLabel].(string); project != "" {
+			byProject[project] = append(byProject[project], ep)
+		}
+	}
+	return byProject, gpuSpecs, nil
+}
+
+// evaluate computes the headroom of each limited resource of a project
+func (s *QuotaService) evaluate(ctx context.Context, project string, endpoints []*mysql.Endpoint, gpuSpecs map[string]bool, now time.Time) (*ProjectQuotaStatus, error) {
+	limits := s.config.Projects[project]
+	status := &ProjectQuotaStatus{
+		Project:   project,
+		Quota:     limits,
+		Endpoints: make([]string, 0, len(endpoints)),
+		Level:     quota.LevelOK,
+		Resources: make([]*quota.Headroom, 0, 2),
+		UpdatedAt: now,
+	}
+
+	var usedReplicas, usedGPUs float64
+	for _, ep := range endpoints {
+		status.Endpoints = append(status.Endpoints, ep.Endpoint)
+		usedReplicas += float64(ep.Replicas)
+		usedGPUs += float64(ep.Replicas * gpusPerReplica(ep, gpuSpecs))
+	}
+	sort.Strings(status.Endpoints)
+
+	replicaPeaks, gpuPeaks, err := s.dailyPeaks(ctx, endpoints, gpuSpecs, now)
+	if err != nil {
+		return nil, fmt.Errorf("project %s: %w", project, err)
+	}
+
+	thresholds := quota.Thresholds{WarnPercent: s.config.WarnPercent, WarnDays: s.config.WarnDays}
+	if limits.GPUs > 0 {
+		status.Resources = append(status.Resources, quota.Forecast(quota.ResourceGPUs, float64(limits.GPUs), usedGPUs, gpuPeaks, thresholds))
+	}
+	if limits.Replicas > 0 {
+		status.Resources = append(status.Resources, quota.Forecast(quota.ResourceReplicas, float64(limits.Replicas), usedReplicas, replicaPeaks, thresholds))
+	}
+	for _, h := range status.Resources {
+		if levelRank(h.Level) > levelRank(status.Level) {
+			status.Level = h.Level
+		}
+	}
+	return status, nil
+}
+
+// dailyPeaks returns, per UTC day of the history, the peak over the hours of the workers (and
+// their GPUs) summed across the endpoints
+func (s *QuotaService) dailyPeaks(ctx context.Context, endpoints []*mysql.Endpoint, gpuSpecs map[string]bool, now time.Time) ([]quota.Point, []quota.Point, error) {
+	to := now.UTC().Truncate(time.Hour) // The current hour is not aggregated yet
+	from := to.Truncate(24*time.Hour).AddDate(0, 0, -s.config.HistoryDays)
+
+	replicasByHour := make(map[int64]float64) // Unix hour start -> workers
+	gpusByHour := make(map[int64]float64)
+	for _, ep := range endpoints {
+		stats, err := s.monitoringRepo.GetHourlyStats(ctx, ep.Endpoint, from, to)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get hourly stats of endpoint %s: %w", ep.Endpoint, err)
+		}
+		gpus := gpusPerReplica(ep, gpuSpecs)
+		for _, st := range stats {
+			workers := float64(st.ActiveWorkers + st.IdleWorkers)
+			replicasByHour[st.StatHour.Unix()] += workers
+			gpusByHour[st.StatHour.Unix()] += workers * float64(gpus)
+		}
+	}
+	return peaksByDay(replicasByHour), peaksByDay(gpusByHour), nil
+}
+
+// peaksByDay reduces hourly values to the maximum of each UTC day
+func peaksByDay(byHour map[int64]float64) []quota.Point {
+	byDay := make(map[int64]float64)
+	for hour, value := range byHour {
+		day := time.Unix(hour, 0).UTC().Truncate(24 * time.Hour).Unix()
+		if peak, ok := byDay[day]; !ok || value > peak {
+			byDay[day] = value
+		}
+	}
+	points := make([]quota.Point, 0, len(byDay))
+	for day, value := range byDay {
+		points = append(points, quota.Point{Day: time.Unix(day, 0).UTC(), Value: value})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Day.Before(points[j].Day) })
+	return points
+}
+
+// gpusPerReplica returns the GPUs of one replica of an endpoint, 0 for CPU specs
+func gpusPerReplica(ep *mysql.Endpoint, gpuSpecs map[string]bool) int {
+	if !gpuSpecs[ep.SpecName] {
+		return 0
+	}
+	return ep.GpuCount
+}
+
+func levelRank(level string) int {
+	switch level {
+	case quota.LevelExceeded:
+		return 2
+	case quota.LevelWarning:
+		return 1
+	}
+	return 0
+}
+
+// acquire reports whether a warning of the resource is out of its cooldown and starts a new one
+func (s *QuotaService) acquire(project, resource string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.Join([]string{project, resource}, "/")
+	if last, ok := s.lastAlert[key]; ok && now.Sub(last) < s.config.Cooldown {
+		return false
+	}
+	s.lastAlert[key] = now
+	return true
+}
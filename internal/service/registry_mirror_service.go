@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/image"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// Registry mirror sources
+const (
+	RegistryMirrorSourceConfig = "config" // Defined in the config file (read-only via API)
+	RegistryMirrorSourceAPI    = "api"    // Managed via the registry mirror API
+)
+
+// RegistryMirrorInfo is a mirror mapping with its origin
+type RegistryMirrorInfo struct {
+	Upstream    string     `json:"upstream"`
+	Mirror      string     `json:"mirror"`
+	Enabled     bool       `json:"enabled"`
+	Description string     `json:"description,omitempty"`
+	Source      string     `json:"source"`               // config, api
+	Overridden  bool       `json:"overridden,omitempty"` // Config mapping shadowed by an API mapping
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// UpsertRegistryMirrorRequest creates or updates a mirror mapping
+type UpsertRegistryMirrorRequest struct {
+	Upstream    string `json:"upstream" binding:"required"`
+	Mirror      string `json:"mirror" binding:"required"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// RegistryMirrorService manages upstream registry -> mirror mappings and rewrites
+// images at render time. Config file mappings act as defaults; API mappings for the
+// same upstream take precedence (a disabled API mapping turns mirroring off).
+type RegistryMirrorService struct {
+	repo          *mysql.RegistryMirrorRepository
+	configMirrors []image.RegistryMirror
+}
+
+// NewRegistryMirrorService creates a new registry mirror service
+func NewRegistryMirrorService(repo *mysql.RegistryMirrorRepository, configMirrors []config.RegistryMirrorConfig) *RegistryMirrorService {
+	s := &RegistryMirrorService{repo: repo}
+	for _, m := range configMirrors {
+		mirror := image.RegistryMirror{Upstream: image.NormalizeRegistry(m.Upstream), Mirror: m.Mirror}
+		if err := image.ValidateRegistryMirror(mirror); err != nil {
+			logger.WarnCtx(context.Background(), "Ignoring invalid registry mirror in config (%s -> %s): %v", m.Upstream, m.Mirror, err)
+			continue
+		}
+		s.configMirrors = append(s.configMirrors, mirror)
+	}
+	return s
+}
+
+// ListMirrors returns config and API mappings
+func (s *RegistryMirrorService) ListMirrors(ctx context.Context) ([]*RegistryMirrorInfo, error) {
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	apiUpstreams := make(map[string]bool, len(stored))
+	result := make([]*RegistryMirrorInfo, 0, len(stored)+len(s.configMirrors))
+	for _, m := range stored {
+		apiUpstreams[m.Upstream] = true
+		result = append(result, &RegistryMirrorInfo{
+			Upstream:    m.Upstream,
+			Mirror:      m.Mirror,
+			Enabled:     m.Enabled,
+			Description: m.Description,
+			Source:      RegistryMirrorSourceAPI,
+			UpdatedAt:   &m.UpdatedAt,
+		})
+	}
+	for _, m := range s.configMirrors {
+		result = append(result, &RegistryMirrorInfo{
+			Upstream:   m.Upstream,
+			Mirror:     m.Mirror,
+			Enabled:    true,
+			Source:     RegistryMirrorSourceConfig,
+			Overridden: apiUpstreams[m.Upstream],
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Upstream < result[j].Upstream
+	})
+	return result, nil
+}
+
+// UpsertMirror creates or updates an API-managed mapping
+func (s *RegistryMirrorService) UpsertMirror(ctx context.Context, req *UpsertRegistryMirrorRequest) (*model.RegistryMirror, error) {
+	mirror := image.RegistryMirror{
+		Upstream: image.NormalizeRegistry(req.Upstream),
+		Mirror:   strings.TrimSuffix(strings.TrimSpace(req.Mirror), "/"),
+	}
+	if err := image.ValidateRegistryMirror(mirror); err != nil {
+		return nil, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	record := &model.RegistryMirror{
+		Upstream:    mirror.Upstream,
+		Mirror:      mirror.Mirror,
+		Enabled:     enabled,
+		Description: req.Description,
+	}
+	if err := s.repo.Upsert(ctx, record); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, mirror.Upstream)
+}
+
+// DeleteMirror removes an API-managed mapping (config mappings cannot be deleted via API)
+func (s *RegistryMirrorService) DeleteMirror(ctx context.Context, upstream string) error {
+	upstream = image.NormalizeRegistry(upstream)
+	existing, err := s.repo.Get(ctx, upstream)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("registry mirror for %s not found", upstream)
+	}
+	return s.repo.Delete(ctx, upstream)
+}
+
+// EffectiveMirrors returns the mappings applied at render time
+func (s *RegistryMirrorService) EffectiveMirrors(ctx context.Context) ([]image.RegistryMirror, error) {
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	overridden := make(map[string]bool, len(stored))
+	var mirrors []image.RegistryMirror
+	for _, m := range stored {
+		overridden[m.Upstream] = true
+		if m.Enabled {
+			mirrors = append(mirrors, image.RegistryMirror{Upstream: m.Upstream, Mirror: m.Mirror})
+		}
+	}
+	for _, m := range s.configMirrors {
+		if !overridden[m.Upstream] {
+			mirrors = append(mirrors, m)
+		}
+	}
+	return mirrors, nil
+}
+
+// RewriteImage returns the image as it should be pulled inside the cluster.
+// On lookup errors the config mappings are used so deploys never fail because of mirrors.
+func (s *RegistryMirrorService) RewriteImage(ctx context.Context, imageRef string) string {
+	mirrors, err := s.EffectiveMirrors(ctx)
+	if err != nil {
+		logger.WarnCtx(ctx, "Failed to load registry mirrors, using config mappings only: %v", err)
+		mirrors = s.configMirrors
+	}
+
+	rewritten, ok := image.RewriteImage(imageRef, mirrors)
+	if ok {
+		logger.InfoCtx(ctx, "Rewrote image %s -> %s via registry mirror", imageRef, rewritten)
+	}
+	return rewritten
+}
+
+// ResolveImage previews how an image would be rewritten at render time
+func (s *RegistryMirrorService) ResolveImage(ctx context.Context, imageRef string) (string, bool, error) {
+	mirrors, err := s.EffectiveMirrors(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	rewritten, ok := image.RewriteImage(imageRef, mirrors)
+	return rewritten, ok, nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/sampling"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// samplingRuleCacheTTL bounds how long a rule change takes to reach every replica
+const samplingRuleCacheTTL = 30 * time.Second
+
+// UpsertSamplingRuleRequest creates or updates the sampling rule of an endpoint
+type UpsertSamplingRuleRequest struct {
+	SampleRate    float64  `json:"sampleRate"`              // 0-1, e.g. 0.01 = 1% of tasks
+	IncludeFailed bool     `json:"includeFailed,omitempty"` // Also sample failed tasks
+	ScrubFields   []string `json:"scrubFields,omitempty"`   // Input/output keys redacted before upload
+	Enabled       *bool    `json:"enabled,omitempty"`       // Default true
+}
+
+// SamplingService manages sampling rules and captures finished tasks that match them.
+// Rules are cached in memory so task completion does not query MySQL.
+type SamplingService struct {
+	repo    *mysql.SamplingRuleRepository
+	sampler *sampling.Sampler // nil = rules can be managed but nothing is captured
+	rnd     func() float64
+
+	encryptionService *TaskEncryptionService // Optional: seals payloads of encrypted endpoints
+
+	mu       sync.RWMutex
+	rules    map[string]*model.SamplingRule
+	loadedAt time.Time
+}
+
+// NewSamplingService creates a new sampling service; sampler may be nil when no datasets store is configured
+func NewSamplingService(repo *mysql.SamplingRuleRepository, sampler *sampling.Sampler) *SamplingService {
+	return &SamplingService{
+		repo:    repo,
+		sampler: sampler,
+		rnd:     rand.Float64,
+	}
+}
+
+// SetEncryptionService sets the task encryption service (for dependency injection)
+func (s *SamplingService) SetEncryptionService(encryptionService *TaskEncryptionService) {
+	s.encryptionService = encryptionService
+}
+
+// ListRules returns all sampling rules
+func (s *SamplingService) ListRules(ctx context.Context) ([]*model.SamplingRule, error) {
+	return s.repo.List(ctx)
+}
+
+// GetRule returns the sampling rule of an endpoint
+func (s *SamplingService) GetRule(ctx context.Context, endpoint string) (*model.SamplingRule, error) {
+	rule, err := s.repo.Get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("sampling rule for endpoint %s not found", endpoint)
+	}
+	return rule, nil
+}
+
+// UpsertRule creates or updates the sampling rule of an endpoint
+func (s *SamplingService) UpsertRule(ctx context.Context, endpoint string, req *UpsertSamplingRuleRequest) (*model.SamplingRule, error) {
+	if req.SampleRate < 0 || req.SampleRate > 1 {
+		return nil, fmt.Errorf("sampleRate must be between 0 and 1")
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	rule := &model.SamplingRule{
+		Endpoint:      endpoint,
+		SampleRate:    req.SampleRate,
+		IncludeFailed: req.IncludeFailed,
+		ScrubFields:   model.JSONStringArray(req.ScrubFields),
+		Enabled:       enabled,
+	}
+	if err := s.repo.Upsert(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return s.GetRule(ctx, endpoint)
+}
+
+// DeleteRule removes the sampling rule of an endpoint
+func (s *SamplingService) DeleteRule(ctx context.Context, endpoint string) error {
+	if _, err := s.GetRule(ctx, endpoint); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, endpoint); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Stats returns the capture/upload counters, nil when capturing is disabled
+func (s *SamplingService) Stats() *sampling.Stats {
+	if s.sampler == nil {
+		return nil
+	}
+	stats := s.sampler.Stats()
+	return &stats
+}
+
+// CaptureTask samples a finished task if its endpoint rule selects it. Never blocks on I/O
+// beyond a periodic rule reload.
+func (s *SamplingService) CaptureTask(ctx context.Context, task *mysql.Task) {
+	if s == nil || s.sampler == nil || task == nil {
+		return
+	}
+	rule := s.rule(ctx, task.Endpoint)
+	if rule == nil || !rule.Enabled || rule.SampleRate <= 0 {
+		return
+	}
+	if task.Status == "FAILED" && !rule.IncludeFailed {
+		return
+	}
+	if s.rnd() >= rule.SampleRate {
+		return
+	}
+
+	sample := &sampling.Sample{
+		TaskID:    task.TaskID,
+		Endpoint:  task.Endpoint,
+		WorkerID:  task.WorkerID,
+		Status:    task.Status,
+		Input:     task.Input,
+		Output:    task.Output,
+		Error:     task.Error,
+		Subject:   task.SubjectHash,
+		CreatedAt: task.CreatedAt,
+	}
+	if task.CompletedAt != nil {
+		sample.CompletedAt = *task.CompletedAt
+		if task.StartedAt != nil {
+			sample.ExecutionMs = task.CompletedAt.Sub(*task.StartedAt).Milliseconds()
+		}
+	}
+	// Payloads of encrypted endpoints leave the cluster sealed with the project's data key
+	if s.encryptionService != nil {
+		if err := s.sealSample(ctx, sample); err != nil {
+			logger.WarnCtx(ctx, "dropped sample of task %s (endpoint %s): %v", task.TaskID, task.Endpoint, err)
+			return
+		}
+	}
+	if !s.sampler.Capture(sample, sampling.NewFieldScrubber(rule.ScrubFields)) {
+		logger.WarnCtx(ctx, "sampling queue full, dropped sample of task %s (endpoint %s)", task.TaskID, task.Endpoint)
+	}
+}
+
+// sealSample encrypts the input/output of a sample when its endpoint is encrypted
+func (s *SamplingService) sealSample(ctx context.Context, sample *sampling.Sample) error {
+	input, _, err := s.encryptionService.Encrypt(ctx, sample.Endpoint, sample.TaskID, "sample_input", sample.Input)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt sample input: %w", err)
+	}
+	output, _, err := s.encryptionService.Encrypt(ctx, sample.Endpoint, sample.TaskID, "sample_output", sample.Output)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt sample output: %w", err)
+	}
+	sample.Input, sample.Output = input, output
+	return nil
+}
+
+// rule returns the cached rule of an endpoint, reloading all rules when the cache is stale
+func (s *SamplingService) rule(ctx context.Context, endpoint string) *model.SamplingRule {
+	s.mu.RLock()
+	rules, fresh := s.rules, time.Since(s.loadedAt) < samplingRuleCacheTTL
+	s.mu.RUnlock()
+	if fresh {
+		return rules[endpoint]
+	}
+
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to reload sampling rules, keeping cached ones: %v", err)
+		return rules[endpoint]
+	}
+	rules = make(map[string]*model.SamplingRule, len(list))
+	for _, r := range list {
+		rules[r.Endpoint] = r
+	}
+	s.mu.Lock()
+	s.rules, s.loadedAt = rules, time.Now()
+	s.mu.Unlock()
+	return rules[endpoint]
+}
+
+// invalidate forces a rule reload on the next capture
+func (s *SamplingService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
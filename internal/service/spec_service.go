@@ -32,6 +32,10 @@ func (s *SpecService) CreateSpec(ctx context.Context, req *interfaces.CreateSpec
 		return nil, fmt.Errorf("spec with name %s already exists", req.Name)
 	}
 
+	if err := validateGPUTypeFallbacks(req.Resources.GPUType, req.Resources.GPUTypeFallbacks); err != nil {
+		return nil, err
+	}
+
 	// Create spec model
 	spec := &model.Spec{
 		Name:             req.Name,
@@ -42,6 +46,7 @@ func (s *SpecService) CreateSpec(ctx context.Context, req *interfaces.CreateSpec
 		Memory:           req.Resources.Memory,
 		GPU:              req.Resources.GPU,
 		GPUType:          req.Resources.GPUType,
+		GPUTypeFallbacks: model.JSONStringArray(req.Resources.GPUTypeFallbacks),
 		EphemeralStorage: req.Resources.EphemeralStorage,
 		ShmSize:          req.Resources.ShmSize,
 		Platforms:        req.Platforms,
@@ -134,6 +139,13 @@ func (s *SpecService) UpdateSpec(ctx context.Context, name string, req *interfac
 		if req.Resources.ShmSize != "" {
 			spec.ShmSize = req.Resources.ShmSize
 		}
+		// An empty list removes the fallbacks, a missing one keeps them
+		if req.Resources.GPUTypeFallbacks != nil {
+			spec.GPUTypeFallbacks = model.JSONStringArray(req.Resources.GPUTypeFallbacks)
+		}
+		if err := validateGPUTypeFallbacks(spec.GPUType, spec.GPUTypeFallbacks); err != nil {
+			return nil, err
+		}
 	}
 	if req.Platforms != nil {
 		spec.Platforms = req.Platforms
@@ -166,9 +178,31 @@ func (s *SpecService) modelToSpecInfo(spec *model.Spec) *interfaces.SpecInfo {
 			Memory:           spec.Memory,
 			GPU:              spec.GPU,
 			GPUType:          spec.GPUType,
+			GPUTypeFallbacks: spec.GPUTypeFallbacks,
 			EphemeralStorage: spec.EphemeralStorage,
 			ShmSize:          spec.ShmSize,
 		},
 		Platforms: spec.Platforms,
 	}
 }
+
+// validateGPUTypeFallbacks checks that fallbacks follow a primary GPU type and name each GPU type once
+func validateGPUTypeFallbacks(gpuType string, fallbacks []string) error {
+	if len(fallbacks) == 0 {
+		return nil
+	}
+	if gpuType == "" {
+		return fmt.Errorf("gpuTypeFallbacks requires gpuType")
+	}
+	seen := map[string]bool{gpuType: true}
+	for _, fallback := range fallbacks {
+		if fallback == "" {
+			return fmt.Errorf("gpuTypeFallbacks contains an empty GPU type")
+		}
+		if seen[fallback] {
+			return fmt.Errorf("GPU type %s is listed twice in gpuType and gpuTypeFallbacks", fallback)
+		}
+		seen[fallback] = true
+	}
+	return nil
+}
@@ -9,18 +9,28 @@ import (
 	mysqlModel "waverless/pkg/store/mysql/model"
 )
 
+// statisticsCounter is the part of TaskStatisticsRepository that follows task status changes
+type statisticsCounter interface {
+	IncrementStatistics(ctx context.Context, endpoint string, fromStatus, toStatus string, count int) error
+}
+
 // StatisticsService provides task statistics operations
 type StatisticsService struct {
 	statsRepo  *mysql.TaskStatisticsRepository
+	counter    statisticsCounter
 	workerRepo *mysql.WorkerRepository
 }
 
 // NewStatisticsService creates a new statistics service
 func NewStatisticsService(statsRepo *mysql.TaskStatisticsRepository, workerRepo *mysql.WorkerRepository) *StatisticsService {
-	return &StatisticsService{
+	s := &StatisticsService{
 		statsRepo:  statsRepo,
 		workerRepo: workerRepo,
 	}
+	if statsRepo != nil {
+		s.counter = statsRepo
+	}
+	return s
 }
 
 // GetOverviewStatistics retrieves global task statistics for dashboard
@@ -91,14 +101,23 @@ func (s *StatisticsService) RefreshAllStatistics(ctx context.Context) error {
 // UpdateStatisticsOnTaskStatusChange updates statistics when a task status changes
 // This should be called asynchronously after task status updates
 func (s *StatisticsService) UpdateStatisticsOnTaskStatusChange(ctx context.Context, endpoint string, fromStatus, toStatus string) {
-	if err := s.statsRepo.IncrementStatistics(ctx, endpoint, fromStatus, toStatus, 1); err != nil {
+	if err := s.counter.IncrementStatistics(ctx, endpoint, fromStatus, toStatus, 1); err != nil {
 		logger.ErrorCtx(ctx, "failed to update statistics for status change (endpoint: %s, from: %s, to: %s): %v", endpoint, fromStatus, toStatus, err)
 	}
 }
 
+// countsInStatistics reports whether the status changes of a task update the endpoint
+// statistics. Replays are kept out, so validating a fix does not inflate regular traffic;
+// they are counted from replay_of instead (TaskRepository.CountReplaysByStatus). Hedges are
+// kept out too, so a hedged task is counted once; they are reported from hedge_of and
+// hedge_outcome (TaskRepository.GetHedgeTotals).
+func countsInStatistics(task *mysql.Task) bool {
+	return task.ReplayOf == "" && task.HedgeOf == ""
+}
+
 // UpdateStatisticsOnTaskStatusChangeBatch updates statistics for batch status changes
 func (s *StatisticsService) UpdateStatisticsOnTaskStatusChangeBatch(ctx context.Context, endpoint string, fromStatus, toStatus string, count int) {
-	if err := s.statsRepo.IncrementStatistics(ctx, endpoint, fromStatus, toStatus, count); err != nil {
+	if err := s.counter.IncrementStatistics(ctx, endpoint, fromStatus, toStatus, count); err != nil {
 		logger.ErrorCtx(ctx, "failed to update statistics for batch status change (endpoint: %s, from: %s, to: %s, count: %d): %v", endpoint, fromStatus, toStatus, count, err)
 	}
 }
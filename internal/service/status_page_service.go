@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/constants"
+	"waverless/pkg/logger"
+	"waverless/pkg/statuspage"
+	"waverless/pkg/store/mysql"
+)
+
+// statusPageWindow is the period the success rate of a status page covers
+const statusPageWindow = 24 * time.Hour
+
+// StatusPageEndpoint is the client-visible status of one endpoint. Queue saturation and
+// success rate are only set for endpoints exposed in full.
+type StatusPageEndpoint struct {
+	Name            string            `json:"name"`
+	Status          statuspage.Status `json:"status"`
+	QueueSaturation *float64          `json:"queueSaturation,omitempty"` // Pending tasks / max pending tasks
+	SuccessRate24h  *float64          `json:"successRate24h,omitempty"`  // Completed share of tasks finished in the last 24h, absent without tasks
+	Tasks24h        *int              `json:"tasks24h,omitempty"`        // Tasks finished in the last 24h
+}
+
+// StatusPage is the client-visible status of the exposed endpoints of a project
+type StatusPage struct {
+	Project   string                `json:"project"`
+	Status    statuspage.Status     `json:"status"` // Worst endpoint status
+	Endpoints []*StatusPageEndpoint `json:"endpoints"`
+	UpdatedAt time.Time             `json:"updatedAt"`
+}
+
+type cachedStatusPage struct {
+	page     *StatusPage
+	loadedAt time.Time
+}
+
+// StatusPageService builds the status pages of projects for external status pages. Only
+// endpoints that opt in through the exposure label are listed. Pages are served from memory
+// for the cache TTL, and only projects with exposed endpoints are cached, so unauthenticated
+// callers cannot grow the cache or reach the database more than once per TTL and project.
+type StatusPageService struct {
+	endpointRepo   *mysql.EndpointRepository
+	taskRepo       *mysql.TaskRepository
+	monitoringRepo *mysql.MonitoringRepository
+	config         config.StatusPageConfig
+
+	mu        sync.Mutex
+	exposed   map[string][]*mysql.Endpoint // project -> exposed endpoints
+	exposedAt time.Time
+	pages     map[string]*cachedStatusPage
+}
+
+// NewStatusPageService creates a new status page service
+func NewStatusPageService(endpointRepo *mysql.EndpointRepository, taskRepo *mysql.TaskRepository, monitoringRepo *mysql.MonitoringRepository, cfg config.StatusPageConfig) *StatusPageService {
+	return &StatusPageService{
+		endpointRepo:   endpointRepo,
+		taskRepo:       taskRepo,
+		monitoringRepo: monitoringRepo,
+		config:         cfg,
+		pages:          make(map[string]*cachedStatusPage),
+	}
+}
+
+// Project returns the status page of a project
+func (s *StatusPageService) Project(ctx context.Context, project string) (*StatusPage, error) {
+	now := time.Now()
+	s.mu.Lock()
+	cached, ok := s.pages[project]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.loadedAt) < s.config.CacheTTL {
+		return cached.page, nil
+	}
+
+	endpoints, err := s.exposedEndpoints(ctx, project, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("project %s not found", project)
+	}
+
+	page := &StatusPage{Project: project, Endpoints: make([]*StatusPageEndpoint, 0, len(endpoints)), UpdatedAt: now}
+	statuses := make([]statuspage.Status, 0, len(endpoints))
+	for _, ep := range endpoints {
+		status := s.endpointStatus(ctx, ep, now)
+		page.Endpoints = append(page.Endpoints, status)
+		statuses = append(statuses, status.Status)
+	}
+	page.Status = statuspage.Worst(statuses...)
+
+	s.mu.Lock()
+	s.pages[project] = &cachedStatusPage{page: page, loadedAt: now}
+	s.mu.Unlock()
+	return page, nil
+}
+
+// Endpoint returns the status of one exposed endpoint of a project
+func (s *StatusPageService) Endpoint(ctx context.Context, project, name string) (*StatusPageEndpoint, error) {
+	page, err := s.Project(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	for _, ep := range page.Endpoints {
+		if ep.Name == name {
+			return ep, nil
+		}
+	}
+	return nil, fmt.Errorf("endpoint %s not found", name)
+}
+
+// exposedEndpoints returns the exposed endpoints of a project by name, reloading the endpoint
+// labels at most once per cache TTL
+func (s *StatusPageService) exposedEndpoints(ctx context.Context, project string, now time.Time) ([]*mysql.Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exposed != nil && now.Sub(s.exposedAt) < s.config.CacheTTL {
+		return s.exposed[project], nil
+	}
+
+	endpoints, err := s.endpointRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exposed := make(map[string][]*mysql.Endpoint)
+	for _, ep := range endpoints {
+		p, _ := ep.Labels[s.config.ProjectLabel].(string)
+		if p == "" || s.exposure(ep) == statuspage.ExposureNone {
+			continue
+		}
+		exposed[p] = append(exposed[p], ep)
+	}
+	for _, eps := range exposed {
+		sort.Slice(eps, func(i, j int) bool { return eps[i].Endpoint < eps[j].Endpoint })
+	}
+	// Pages of projects that are gone must not be served from the cache
+	for p := range s.pages {
+		if _, ok := exposed[p]; !ok {
+			delete(s.pages, p)
+		}
+	}
+	s.exposed, s.exposedAt = exposed, now
+	return exposed[project], nil
+}
+
+func (s *StatusPageService) exposure(ep *mysql.Endpoint) statuspage.Exposure {
+	value, _ := ep.Labels[s.config.ExposureLabel].(string)
+	return statuspage.ParseExposure(value)
+}
+
+// endpointStatus builds the status of an exposed endpoint. Statistics that fail to load are
+// left out rather than failing the page.
+func (s *StatusPageService) endpointStatus(ctx context.Context, ep *mysql.Endpoint, now time.Time) *StatusPageEndpoint {
+	status := &StatusPageEndpoint{Name: ep.Endpoint, Status: statuspage.EndpointStatus(ep.HealthStatus, ep.DispatchPaused)}
+	if s.exposure(ep) != statuspage.ExposureFull {
+		return status
+	}
+
+	pending, err := s.taskRepo.CountByEndpointAndStatus(ctx, ep.Endpoint, constants.TaskStatusPending.String())
+	if err != nil {
+		logger.WarnCtx(ctx, "status page: failed to count pending tasks of %s: %v", ep.Endpoint, err)
+	} else {
+		saturation := statuspage.Saturation(pending, ep.MaxPendingTasks)
+		status.QueueSaturation = &saturation
+	}
+
+	completed, failed, err := s.finishedTasks(ctx, ep.Endpoint, now)
+	if err != nil {
+		logger.WarnCtx(ctx, "status page: failed to load statistics of %s: %v", ep.Endpoint, err)
+		return status
+	}
+	total := completed + failed
+	status.Tasks24h = &total
+	status.SuccessRate24h = statuspage.SuccessRate(completed, failed)
+	return status
+}
+
+// finishedTasks sums the completed and failed (including timed out) tasks of the window before
+// now: full hours from the hourly statistics, the current hour from the minute statistics
+func (s *StatusPageService) finishedTasks(ctx context.Context, endpoint string, now time.Time) (completed, failed int, err error) {
+	currentHour := now.Truncate(time.Hour)
+	hourly, err := s.monitoringRepo.GetHourlyStats(ctx, endpoint, currentHour.Add(-statusPageWindow), currentHour)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, st := range hourly {
+		completed += st.TasksCompleted
+		failed += st.TasksFailed + st.TasksTimeout
+	}
+
+	minutes, err := s.monitoringRepo.GetMinuteStats(ctx, endpoint, currentHour, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, st := range minutes {
+		completed += st.TasksCompleted
+		failed += st.TasksFailed + st.TasksTimeout
+	}
+	return completed, failed, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"waverless/pkg/envelope"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// encryptionProjectCacheTTL bounds how long a project label change takes to apply to new writes
+const encryptionProjectCacheTTL = 30 * time.Second
+
+// EndpointEncryption reports whether an endpoint's task payloads are encrypted at rest
+type EndpointEncryption struct {
+	Endpoint  string `json:"endpoint"`
+	Encrypted bool   `json:"encrypted"`
+	Project   string `json:"project,omitempty"`
+	KMS       string `json:"kms,omitempty"`
+}
+
+type cachedProject struct {
+	project  string // "" = endpoint is not encrypted
+	loadedAt time.Time
+}
+
+// TaskEncryptionService encrypts task input/output at rest for endpoints that belong to a
+// project (the value of the project label). Payloads are sealed with the project's data key
+// in the task repository, so API consumers and workers always see plaintext. The task row
+// records which payloads are sealed; payloads written before an endpoint joined a project
+// are not flagged and stay readable as they are.
+type TaskEncryptionService struct {
+	keyring      *envelope.Keyring
+	keyRepo      *mysql.EncryptionKeyRepository
+	endpointRepo *mysql.EndpointRepository
+	projectLabel string
+
+	mu       sync.RWMutex
+	projects map[string]*cachedProject
+}
+
+// NewTaskEncryptionService creates a new task encryption service
+func NewTaskEncryptionService(kms envelope.KMS, keyRepo *mysql.EncryptionKeyRepository, endpointRepo *mysql.EndpointRepository, projectLabel string) *TaskEncryptionService {
+	return &TaskEncryptionService{
+		keyring:      envelope.NewKeyring(kms, &encryptionKeyStore{repo: keyRepo}),
+		keyRepo:      keyRepo,
+		endpointRepo: endpointRepo,
+		projectLabel: projectLabel,
+		projects:     make(map[string]*cachedProject),
+	}
+}
+
+// Project returns the project of an endpoint, "" when its payloads are not encrypted
+func (s *TaskEncryptionService) Project(ctx context.Context, endpoint string) (string, error) {
+	s.mu.RLock()
+	cached, ok := s.projects[endpoint]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < encryptionProjectCacheTTL {
+		return cached.project, nil
+	}
+
+	ep, err := s.endpointRepo.Get(ctx, endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project of endpoint %s: %w", endpoint, err)
+	}
+	project := ""
+	if ep != nil {
+		if v, ok := ep.Labels[s.projectLabel].(string); ok {
+			project = v
+		}
+	}
+
+	s.mu.Lock()
+	s.projects[endpoint] = &cachedProject{project: project, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return project, nil
+}
+
+// Encrypt seals a payload of a task with its project's data key and reports whether it did
+// (implements mysql.PayloadCipher). Whether a payload is sealed is decided by the endpoint's
+// project only: a payload that merely looks like an envelope is sealed like any other.
+func (s *TaskEncryptionService) Encrypt(ctx context.Context, endpoint, taskID, field string, payload mysql.JSONMap) (mysql.JSONMap, bool, error) {
+	if payload == nil {
+		return nil, false, nil
+	}
+	project, err := s.Project(ctx, endpoint)
+	if err != nil || project == "" {
+		return payload, false, err
+	}
+	sealed, err := s.keyring.SealMap(ctx, project, payload, payloadAAD(taskID, field))
+	if err != nil {
+		return nil, false, err
+	}
+	return sealed, true, nil
+}
+
+// Decrypt opens a payload of a task that was stored sealed (implements mysql.PayloadCipher)
+func (s *TaskEncryptionService) Decrypt(ctx context.Context, taskID, field string, payload mysql.JSONMap) (mysql.JSONMap, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	if _, ok, err := envelope.SealedPayload(payload); err != nil || !ok {
+		return nil, fmt.Errorf("%s of task %s is flagged sealed but is not an envelope", field, taskID)
+	}
+	return s.keyring.OpenMap(ctx, payload, payloadAAD(taskID, field))
+}
+
+// EndpointStatus reports whether an endpoint's task payloads are encrypted
+func (s *TaskEncryptionService) EndpointStatus(ctx context.Context, endpoint string) (*EndpointEncryption, error) {
+	project, err := s.Project(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	status := &EndpointEncryption{Endpoint: endpoint, Encrypted: project != "", Project: project}
+	if status.Encrypted {
+		status.KMS = s.keyring.KMSName()
+	}
+	return status, nil
+}
+
+// ListKeys returns the data key versions of a project (never key material), newest first
+func (s *TaskEncryptionService) ListKeys(ctx context.Context, project string) ([]*envelope.DataKey, error) {
+	rows, err := s.keyRepo.ListVersions(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*envelope.DataKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, toDataKey(row))
+	}
+	return keys, nil
+}
+
+// RotateKey creates a new data key version of a project. New payloads are sealed with it;
+// existing payloads keep their version and remain readable.
+func (s *TaskEncryptionService) RotateKey(ctx context.Context, project, requestedBy string) (*envelope.DataKey, error) {
+	key, err := s.keyring.Rotate(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	logger.InfoCtx(ctx, "[AUDIT] encryption key rotated: project=%s, version=%d, kms=%s, requestedBy=%q",
+		project, key.Version, key.KMS, requestedBy)
+	return key, nil
+}
+
+// payloadAAD binds a sealed payload to its task and field, so it cannot be copied elsewhere
+func payloadAAD(taskID, field string) []byte {
+	return []byte("tasks/" + taskID + "/" + field)
+}
+
+// encryptionKeyStore stores keyring data keys in MySQL
+type encryptionKeyStore struct {
+	repo *mysql.EncryptionKeyRepository
+}
+
+func (s *encryptionKeyStore) LatestKey(ctx context.Context, project string) (*envelope.DataKey, error) {
+	row, err := s.repo.Latest(ctx, project)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return toDataKey(row), nil
+}
+
+func (s *encryptionKeyStore) GetKey(ctx context.Context, project string, version int) (*envelope.DataKey, error) {
+	row, err := s.repo.Get(ctx, project, version)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return toDataKey(row), nil
+}
+
+func (s *encryptionKeyStore) CreateKey(ctx context.Context, key *envelope.DataKey) error {
+	row := &model.EncryptionKey{Project: key.Project, WrappedKey: key.Wrapped, KMS: key.KMS}
+	if err := s.repo.Create(ctx, row); err != nil {
+		return err
+	}
+	key.Version = row.Version
+	key.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func toDataKey(row *model.EncryptionKey) *envelope.DataKey {
+	return &envelope.DataKey{
+		Project:   row.Project,
+		Version:   row.Version,
+		Wrapped:   row.WrappedKey,
+		KMS:       row.KMS,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"waverless/pkg/envelope"
+	"waverless/pkg/store/mysql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryKeyStore keeps data keys in memory
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys []*envelope.DataKey
+}
+
+func (s *memoryKeyStore) LatestKey(ctx context.Context, project string) (*envelope.DataKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var latest *envelope.DataKey
+	for _, key := range s.keys {
+		if key.Project == project {
+			latest = key
+		}
+	}
+	return latest, nil
+}
+
+func (s *memoryKeyStore) GetKey(ctx context.Context, project string, version int) (*envelope.DataKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range s.keys {
+		if key.Project == project && key.Version == version {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryKeyStore) CreateKey(ctx context.Context, key *envelope.DataKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key.Version = len(s.keys) + 1
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+// newTestEncryptionService encrypts the payloads of flux (project clinic); sdxl is not encrypted
+func newTestEncryptionService(t *testing.T) *TaskEncryptionService {
+	kms, err := envelope.NewLocalKMS(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	require.NoError(t, err)
+	now := time.Now()
+	return &TaskEncryptionService{
+		keyring: envelope.NewKeyring(kms, &memoryKeyStore{}),
+		projects: map[string]*cachedProject{
+			"flux": {project: "clinic", loadedAt: now},
+			"sdxl": {project: "", loadedAt: now},
+		},
+	}
+}
+
+func TestEncrypt_SealsEnvelopeShapedInput(t *testing.T) {
+	s := newTestEncryptionService(t)
+	ctx := context.Background()
+
+	// A caller copies the sealed input of another task into a new submission
+	other, sealed, err := s.Encrypt(ctx, "flux", "task-a", "input", mysql.JSONMap{"prompt": "x-ray"})
+	require.NoError(t, err)
+	require.True(t, sealed)
+	data, err := json.Marshal(other)
+	require.NoError(t, err)
+	var input mysql.JSONMap
+	require.NoError(t, json.Unmarshal(data, &input))
+
+	stored, sealed, err := s.Encrypt(ctx, "flux", "task-b", "input", input)
+	require.NoError(t, err)
+	assert.True(t, sealed)
+	assert.NotEqual(t, input, stored)
+
+	opened, err := s.Decrypt(ctx, "task-b", "input", stored)
+	require.NoError(t, err)
+	assert.Equal(t, input, opened)
+}
+
+func TestEncrypt_UnencryptedEndpointPassesThrough(t *testing.T) {
+	s := newTestEncryptionService(t)
+	input := mysql.JSONMap{envelope.PayloadKey: map[string]interface{}{"v": 1}}
+
+	stored, sealed, err := s.Encrypt(context.Background(), "sdxl", "task-a", "input", input)
+	require.NoError(t, err)
+	assert.False(t, sealed)
+	assert.Equal(t, input, stored)
+}
+
+func TestDecrypt_RejectsUnsealedPayload(t *testing.T) {
+	s := newTestEncryptionService(t)
+
+	_, err := s.Decrypt(context.Background(), "task-a", "input", mysql.JSONMap{"prompt": "x-ray"})
+	assert.Error(t, err)
+}
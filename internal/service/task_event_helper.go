@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"waverless/pkg/failure"
+	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
 	mysqlModel "waverless/pkg/store/mysql/model"
 )
@@ -40,6 +42,21 @@ func (s *TaskService) recordTaskEvent(
 			event.QueueWaitMs = &queueMs
 		}
 
+		// Classify worker-reported errors (e.g. CUDA OOM) so failures can be aggregated by cause
+		if eventType == mysqlModel.EventTaskFailed && errorMsg != "" {
+			classification := failure.Default().Classify(failure.Signal{Source: failure.SourceWorker, Message: errorMsg})
+			if classification.Type != interfaces.FailureTypeUnknown {
+				event.ErrorType = classification.Reason
+				if event.ErrorType == "" {
+					event.ErrorType = string(classification.Type)
+				}
+				event.Metadata = mysqlModel.JSONMap{
+					"failureType":    string(classification.Type),
+					"failureMatcher": classification.Matcher,
+				}
+			}
+		}
+
 		// Fill execution_duration_ms for completion events
 		if eventType == mysqlModel.EventTaskCompleted || eventType == mysqlModel.EventTaskFailed || eventType == mysqlModel.EventTaskTimeout {
 			if task.StartedAt != nil {
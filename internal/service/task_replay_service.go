@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/internal/model"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+
+	"github.com/google/uuid"
+)
+
+// Replay job limits
+const (
+	DefaultReplayRateLimit = 1 // Tasks per second
+	MaxReplayRateLimit     = 50
+	DefaultReplayMaxTasks  = 1000
+	MaxReplayMaxTasks      = 10000
+)
+
+// replayableStatuses are the task statuses a replay job may select
+var replayableStatuses = map[string]bool{
+	string(model.TaskStatusCompleted): true,
+	string(model.TaskStatusFailed):    true,
+	string(model.TaskStatusCancelled): true,
+}
+
+// ReplayTaskRequest replays a single task
+type ReplayTaskRequest struct {
+	Endpoint string `json:"endpoint,omitempty"` // Target endpoint (default: the task's own endpoint)
+}
+
+// ReplayedTask is a task submitted by a replay
+type ReplayedTask struct {
+	ID       string           `json:"id"`
+	ReplayOf string           `json:"replayOf"`
+	Endpoint string           `json:"endpoint"`
+	Status   model.TaskStatus `json:"status"`
+}
+
+// ReplayJobRequest replays the tasks of an endpoint submitted in [From, To) into a target endpoint
+type ReplayJobRequest struct {
+	SourceEndpoint string    `json:"sourceEndpoint" binding:"required"`
+	TargetEndpoint string    `json:"targetEndpoint" binding:"required"`
+	From           time.Time `json:"from" binding:"required"`
+	To             time.Time `json:"to" binding:"required"`
+	Statuses       []string  `json:"statuses,omitempty"`  // COMPLETED, FAILED and/or CANCELLED (default: all three)
+	RateLimit      int       `json:"rateLimit,omitempty"` // Tasks per second (default 1)
+	MaxTasks       int       `json:"maxTasks,omitempty"`  // Stop after this many replays (default 1000)
+}
+
+// ReplayJobView is a replay job with the status counts of the tasks it submitted
+type ReplayJobView struct {
+	*mysqlModel.TaskReplayJob
+	Tasks map[string]int64 `json:"tasks"`
+}
+
+// replayJobStore is the part of TaskReplayRepository the replay service uses
+type replayJobStore interface {
+	Create(ctx context.Context, job *mysqlModel.TaskReplayJob) error
+	Get(ctx context.Context, id int64) (*mysqlModel.TaskReplayJob, error)
+	List(ctx context.Context, status, targetEndpoint string, limit, offset int) ([]*mysqlModel.TaskReplayJob, error)
+	Count(ctx context.Context, status, targetEndpoint string) (int64, error)
+	ListActive(ctx context.Context) ([]*mysqlModel.TaskReplayJob, error)
+	SaveProgress(ctx context.Context, job *mysqlModel.TaskReplayJob) (bool, error)
+	Cancel(ctx context.Context, id int64) (bool, error)
+}
+
+// replayTaskStore is the part of TaskRepository the replay service uses
+type replayTaskStore interface {
+	Get(ctx context.Context, taskID string) (*mysql.Task, error)
+	ListReplaySources(ctx context.Context, endpoint string, from, to time.Time, statuses []string, afterID int64, limit int) ([]*mysql.Task, error)
+	CountReplaysByStatus(ctx context.Context, endpoint string, jobID int64) (map[string]int64, error)
+}
+
+// replayQueue resolves the endpoint replays go to and queues them (TaskService)
+type replayQueue interface {
+	replayTarget(ctx context.Context, name string) (*mysql.Endpoint, error)
+	createPendingTask(ctx context.Context, task *mysql.Task) error
+}
+
+// TaskReplayService resubmits the stored input of past tasks, one at a time or a time window
+// at a controlled rate, to validate fixes against real inputs. Replayed tasks keep the task
+// they replay in replay_of, so their statistics are kept apart from regular traffic.
+type TaskReplayService struct {
+	repo     replayJobStore
+	taskRepo replayTaskStore
+	queue    replayQueue
+}
+
+// NewTaskReplayService creates a new task replay service
+func NewTaskReplayService(repo *mysql.TaskReplayRepository, taskRepo *mysql.TaskRepository, taskService *TaskService) *TaskReplayService {
+	s := &TaskReplayService{}
+	// Only non-nil dependencies are assigned, so a missing one stays a nil interface
+	if repo != nil {
+		s.repo = repo
+	}
+	if taskRepo != nil {
+		s.taskRepo = taskRepo
+	}
+	if taskService != nil {
+		s.queue = taskService
+	}
+	return s
+}
+
+// ReplayTask submits the input of a past task again, to its own endpoint or req.Endpoint
+func (s *TaskReplayService) ReplayTask(ctx context.Context, taskID string, req *ReplayTaskRequest, requestedBy string) (*ReplayedTask, error) {
+	source, err := s.taskRepo.Get(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+	if len(source.Input) == 0 {
+		return nil, fmt.Errorf("invalid request: task %s has no input to replay (anonymized)", taskID)
+	}
+
+	targetName := source.Endpoint
+	if req != nil && req.Endpoint != "" {
+		targetName = req.Endpoint
+	}
+	target, err := s.target(ctx, targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	replay, err := s.submit(ctx, source, target, 0)
+	if err != nil {
+		return nil, err
+	}
+	logger.InfoCtx(ctx, "[AUDIT] task replayed: task_id=%s, replay_of=%s, endpoint=%s, requestedBy=%q",
+		replay.TaskID, source.TaskID, target.Endpoint, requestedBy)
+	return &ReplayedTask{ID: replay.TaskID, ReplayOf: source.TaskID, Endpoint: target.Endpoint, Status: model.TaskStatusPending}, nil
+}
+
+// CreateJob validates and stores a replay job. It is executed in slices by RunJobs.
+func (s *TaskReplayService) CreateJob(ctx context.Context, req *ReplayJobRequest, createdBy string) (*ReplayJobView, error) {
+	if !req.From.Before(req.To) {
+		return nil, fmt.Errorf("invalid request: from must be before to")
+	}
+	for _, status := range req.Statuses {
+		if !replayableStatuses[status] {
+			return nil, fmt.Errorf("invalid request: status %s cannot be replayed (use COMPLETED, FAILED or CANCELLED)", status)
+		}
+	}
+	rateLimit := req.RateLimit
+	if rateLimit <= 0 {
+		rateLimit = DefaultReplayRateLimit
+	}
+	if rateLimit > MaxReplayRateLimit {
+		return nil, fmt.Errorf("invalid request: rateLimit must not exceed %d", MaxReplayRateLimit)
+	}
+	maxTasks := req.MaxTasks
+	if maxTasks <= 0 {
+		maxTasks = DefaultReplayMaxTasks
+	}
+	if maxTasks > MaxReplayMaxTasks {
+		return nil, fmt.Errorf("invalid request: maxTasks must not exceed %d", MaxReplayMaxTasks)
+	}
+	if _, err := s.target(ctx, req.TargetEndpoint); err != nil {
+		return nil, err
+	}
+
+	statuses := req.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{string(model.TaskStatusCompleted), string(model.TaskStatusFailed), string(model.TaskStatusCancelled)}
+	}
+	now := time.Now()
+	job := &mysqlModel.TaskReplayJob{
+		Status:         mysqlModel.TaskReplayPending,
+		SourceEndpoint: req.SourceEndpoint,
+		TargetEndpoint: req.TargetEndpoint,
+		FromTime:       req.From.UTC(),
+		ToTime:         req.To.UTC(),
+		Statuses:       statuses,
+		RateLimit:      rateLimit,
+		MaxTasks:       maxTasks,
+		CreatedBy:      createdBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	logger.InfoCtx(ctx, "[AUDIT] task replay job %d created: source=%s, target=%s, from=%s, to=%s, statuses=%v, rate=%d/s, maxTasks=%d, createdBy=%q",
+		job.ID, job.SourceEndpoint, job.TargetEndpoint, job.FromTime.Format(time.RFC3339), job.ToTime.Format(time.RFC3339),
+		statuses, rateLimit, maxTasks, createdBy)
+	return &ReplayJobView{TaskReplayJob: job, Tasks: map[string]int64{}}, nil
+}
+
+// GetJob returns a replay job with the status counts of its tasks
+func (s *TaskReplayService) GetJob(ctx context.Context, id int64) (*ReplayJobView, error) {
+	job, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("replay job %d not found", id)
+	}
+	counts, err := s.taskRepo.CountReplaysByStatus(ctx, "", id)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayJobView{TaskReplayJob: job, Tasks: counts}, nil
+}
+
+// ListJobs returns the most recent replay jobs, optionally filtered by status and target endpoint
+func (s *TaskReplayService) ListJobs(ctx context.Context, status, targetEndpoint string, limit, offset int) ([]*mysqlModel.TaskReplayJob, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.repo.List(ctx, status, targetEndpoint, limit, offset)
+}
+
+// CountJobs returns the number of replay jobs, optionally filtered by status and target endpoint
+func (s *TaskReplayService) CountJobs(ctx context.Context, status, targetEndpoint string) (int64, error) {
+	return s.repo.Count(ctx, status, targetEndpoint)
+}
+
+// CancelJob stops a pending or running replay job. Tasks already submitted keep running.
+func (s *TaskReplayService) CancelJob(ctx context.Context, id int64, cancelledBy string) (*ReplayJobView, error) {
+	cancelled, err := s.repo.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	view, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("invalid request: replay job %d is %s and cannot be cancelled", id, view.Status)
+	}
+	logger.InfoCtx(ctx, "[AUDIT] task replay job %d cancelled: submitted=%d, cancelledBy=%q", id, view.Submitted, cancelledBy)
+	return view, nil
+}
+
+// EndpointStatistics counts the replayed tasks of an endpoint by status
+func (s *TaskReplayService) EndpointStatistics(ctx context.Context, endpoint string) (map[string]int64, error) {
+	return s.taskRepo.CountReplaysByStatus(ctx, endpoint, 0)
+}
+
+// RunJobs advances the active replay jobs for at most budget. Every job submits up to its rate
+// limit of tasks per second; progress is saved after every batch, so the next run (on any
+// replica) continues from the stored cursor.
+func (s *TaskReplayService) RunJobs(ctx context.Context, budget time.Duration) error {
+	jobs, err := s.repo.ListActive(ctx)
+	if err != nil || len(jobs) == 0 {
+		return err
+	}
+
+	deadline := time.Now().Add(budget)
+	for len(jobs) > 0 {
+		started := time.Now()
+		active := jobs[:0]
+		for _, job := range jobs {
+			done, err := s.advanceJob(ctx, job)
+			if err != nil {
+				return err
+			}
+			if !done {
+				active = append(active, job)
+			}
+		}
+		jobs = active
+
+		wait := time.Second - time.Since(started)
+		if len(jobs) == 0 || !time.Now().Add(wait).Before(deadline) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return nil
+}
+
+// advanceJob submits the next batch of a replay job, at most its rate limit. done is true
+// once the job completed or was cancelled.
+func (s *TaskReplayService) advanceJob(ctx context.Context, job *mysqlModel.TaskReplayJob) (done bool, err error) {
+	if job.Status == mysqlModel.TaskReplayPending {
+		now := time.Now()
+		job.Status = mysqlModel.TaskReplayRunning
+		job.StartedAt = &now
+	}
+
+	batch := job.RateLimit
+	if remaining := int64(job.MaxTasks) - job.Submitted; remaining < int64(batch) {
+		batch = int(remaining)
+	}
+	var sources []*mysql.Task
+	if batch > 0 {
+		sources, err = s.taskRepo.ListReplaySources(ctx, job.SourceEndpoint, job.FromTime, job.ToTime, job.Statuses, job.CursorTaskID, batch)
+	}
+	if err == nil {
+		err = s.replayBatch(ctx, job, sources)
+	}
+	if err != nil {
+		// The failed task is retried by the next batch from the saved cursor
+		job.LastError = truncateReplayError(err)
+		logger.WarnCtx(ctx, "task replay job %d: %v", job.ID, err)
+	} else {
+		job.LastError = ""
+		if len(sources) < batch || job.Submitted >= int64(job.MaxTasks) {
+			now := time.Now()
+			job.Status = mysqlModel.TaskReplayCompleted
+			job.CompletedAt = &now
+			logger.InfoCtx(ctx, "task replay job %d completed: submitted=%d, skipped=%d", job.ID, job.Submitted, job.Skipped)
+		}
+	}
+
+	saved, saveErr := s.repo.SaveProgress(ctx, job)
+	if saveErr != nil {
+		return false, saveErr
+	}
+	if !saved {
+		logger.InfoCtx(ctx, "task replay job %d was cancelled, stopping", job.ID)
+		return true, nil
+	}
+	return job.Status == mysqlModel.TaskReplayCompleted, nil
+}
+
+// replayBatch submits sources into the job's target endpoint, moving the cursor past every
+// task replayed or skipped
+func (s *TaskReplayService) replayBatch(ctx context.Context, job *mysqlModel.TaskReplayJob, sources []*mysql.Task) error {
+	if len(sources) == 0 {
+		return nil
+	}
+	target, err := s.target(ctx, job.TargetEndpoint)
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		if len(source.Input) == 0 {
+			job.Skipped++
+		} else if _, err := s.submit(ctx, source, target, job.ID); err != nil {
+			return fmt.Errorf("task %s: %w", source.TaskID, err)
+		} else {
+			job.Submitted++
+		}
+		job.CursorTaskID = source.ID
+	}
+	return nil
+}
+
+// target returns an endpoint replays can be submitted to
+func (s *TaskReplayService) target(ctx context.Context, name string) (*mysql.Endpoint, error) {
+	return s.queue.replayTarget(ctx, name)
+}
+
+// replayTarget returns an endpoint replays can be submitted to
+func (s *TaskService) replayTarget(ctx context.Context, name string) (*mysql.Endpoint, error) {
+	endpoint, err := s.endpointService.GetEndpointOnly(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endpoint: %w", err)
+	}
+	if endpoint == nil {
+		return nil, fmt.Errorf("endpoint %s not found", name)
+	}
+	if err := rejectIfPaused(endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// submit queues the stored input of source on target. The input was already transformed when
+// the source was submitted, so it is not transformed again; the output transform is kept only
+// on the same endpoint.
+func (s *TaskReplayService) submit(ctx context.Context, source *mysql.Task, target *mysql.Endpoint, jobID int64) (*mysql.Task, error) {
+	now := time.Now()
+	task := &mysql.Task{
+		TaskID:      uuid.New().String(),
+		Endpoint:    target.Endpoint,
+		Input:       source.Input,
+		Status:      string(model.TaskStatusPending),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		SubjectHash: source.SubjectHash, // Replays are erased with the subject's other tasks
+		ReplayOf:    source.TaskID,
+		ReplayJobID: jobID,
+	}
+	if target.Endpoint == source.Endpoint {
+		task.TransformVersion = source.TransformVersion
+	}
+	if err := s.queue.createPendingTask(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func truncateReplayError(err error) string {
+	msg := err.Error()
+	if len(msg) > 1024 {
+		msg = msg[:1024]
+	}
+	return msg
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReplayJobs serves ListActive and SaveProgress; other store methods are not used by RunJobs
+type fakeReplayJobs struct {
+	replayJobStore
+	active    []*mysqlModel.TaskReplayJob
+	saves     int
+	cancelled map[int64]bool
+	onSave    func(job *mysqlModel.TaskReplayJob)
+}
+
+func (f *fakeReplayJobs) ListActive(ctx context.Context) ([]*mysqlModel.TaskReplayJob, error) {
+	return f.active, nil
+}
+
+func (f *fakeReplayJobs) SaveProgress(ctx context.Context, job *mysqlModel.TaskReplayJob) (bool, error) {
+	f.saves++
+	if f.onSave != nil {
+		f.onSave(job)
+	}
+	return !f.cancelled[job.ID], nil
+}
+
+// fakeReplaySources lists the tasks of the source endpoint by id, like ListReplaySources
+type fakeReplaySources struct {
+	replayTaskStore
+	tasks  []*mysql.Task
+	limits []int
+	err    error
+}
+
+func (f *fakeReplaySources) ListReplaySources(ctx context.Context, endpoint string, from, to time.Time, statuses []string, afterID int64, limit int) ([]*mysql.Task, error) {
+	f.limits = append(f.limits, limit)
+	if f.err != nil {
+		return nil, f.err
+	}
+	var tasks []*mysql.Task
+	for _, task := range f.tasks {
+		if task.ID > afterID && len(tasks) < limit {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+type fakeReplayQueue struct {
+	queued []*mysql.Task
+	paused bool
+	failOn string
+}
+
+func (f *fakeReplayQueue) replayTarget(ctx context.Context, name string) (*mysql.Endpoint, error) {
+	if f.paused {
+		return nil, errors.New("invalid request: endpoint " + name + " is paused")
+	}
+	return &mysql.Endpoint{Endpoint: name}, nil
+}
+
+func (f *fakeReplayQueue) createPendingTask(ctx context.Context, task *mysql.Task) error {
+	if task.ReplayOf == f.failOn {
+		return errors.New("queue unavailable")
+	}
+	f.queued = append(f.queued, task)
+	return nil
+}
+
+// replaySources returns n stored tasks with ids 1..n
+func replaySources(n int) []*mysql.Task {
+	tasks := make([]*mysql.Task, 0, n)
+	for i := 1; i <= n; i++ {
+		tasks = append(tasks, &mysql.Task{
+			ID:       int64(i),
+			TaskID:   "task-" + string(rune('a'+i-1)),
+			Endpoint: "flux",
+			Input:    mysql.JSONMap{"prompt": i},
+		})
+	}
+	return tasks
+}
+
+func replayJob(rateLimit, maxTasks int) *mysqlModel.TaskReplayJob {
+	return &mysqlModel.TaskReplayJob{
+		ID:             7,
+		Status:         mysqlModel.TaskReplayPending,
+		SourceEndpoint: "flux",
+		TargetEndpoint: "flux-canary",
+		RateLimit:      rateLimit,
+		MaxTasks:       maxTasks,
+	}
+}
+
+func newTestReplayService(jobs *fakeReplayJobs, sources *fakeReplaySources, queue *fakeReplayQueue) *TaskReplayService {
+	return &TaskReplayService{repo: jobs, taskRepo: sources, queue: queue}
+}
+
+func replayOf(tasks []*mysql.Task) []string {
+	ids := []string{}
+	for _, task := range tasks {
+		ids = append(ids, task.ReplayOf)
+	}
+	return ids
+}
+
+func TestAdvanceReplayJob_CursorAndRateLimit(t *testing.T) {
+	jobs := &fakeReplayJobs{}
+	sources := &fakeReplaySources{tasks: replaySources(5)}
+	queue := &fakeReplayQueue{}
+	svc := newTestReplayService(jobs, sources, queue)
+	job := replayJob(2, 100)
+
+	done, err := svc.advanceJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, mysqlModel.TaskReplayRunning, job.Status)
+	assert.NotNil(t, job.StartedAt)
+	assert.Equal(t, int64(2), job.CursorTaskID)
+	assert.Equal(t, int64(2), job.Submitted)
+
+	done, err = svc.advanceJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, int64(4), job.CursorTaskID)
+
+	// A short batch means the window is exhausted
+	done, err = svc.advanceJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, mysqlModel.TaskReplayCompleted, job.Status)
+	assert.NotNil(t, job.CompletedAt)
+	assert.Equal(t, int64(5), job.Submitted)
+
+	assert.Equal(t, []int{2, 2, 2}, sources.limits)
+	assert.Equal(t, 3, jobs.saves)
+	assert.Equal(t, []string{"task-a", "task-b", "task-c", "task-d", "task-e"}, replayOf(queue.queued))
+	for _, task := range queue.queued {
+		assert.Equal(t, "flux-canary", task.Endpoint)
+		assert.Equal(t, int64(7), task.ReplayJobID)
+		assert.Equal(t, "PENDING", task.Status)
+	}
+}
+
+func TestAdvanceReplayJob_MaxTasks(t *testing.T) {
+	jobs := &fakeReplayJobs{}
+	sources := &fakeReplaySources{tasks: replaySources(10)}
+	queue := &fakeReplayQueue{}
+	svc := newTestReplayService(jobs, sources, queue)
+	job := replayJob(4, 6)
+
+	done, err := svc.advanceJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	// Only the 2 tasks left under MaxTasks are requested
+	done, err = svc.advanceJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, []int{4, 2}, sources.limits)
+	assert.Equal(t, int64(6), job.Submitted)
+	assert.Equal(t, int64(6), job.CursorTaskID)
+	assert.Equal(t, mysqlModel.TaskReplayCompleted, job.Status)
+}
+
+func TestAdvanceReplayJob_SkipsTasksWithoutInput(t *testing.T) {
+	tasks := replaySources(4)
+	tasks[1].Input = nil // Anonymized, or its input could not be decrypted
+	tasks[2].Input = mysql.JSONMap{}
+	jobs := &fakeReplayJobs{}
+	queue := &fakeReplayQueue{}
+	svc := newTestReplayService(jobs, &fakeReplaySources{tasks: tasks}, queue)
+	job := replayJob(10, 100)
+
+	done, err := svc.advanceJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, int64(2), job.Submitted)
+	assert.Equal(t, int64(2), job.Skipped)
+	assert.Equal(t, int64(4), job.CursorTaskID) // Skipped tasks move the cursor too
+	assert.Equal(t, []string{"task-a", "task-d"}, replayOf(queue.queued))
+}
+
+func TestAdvanceReplayJob_Errors(t *testing.T) {
+	t.Run("failed submission is retried from the cursor", func(t *testing.T) {
+		jobs := &fakeReplayJobs{}
+		sources := &fakeReplaySources{tasks: replaySources(3)}
+		queue := &fakeReplayQueue{failOn: "task-b"}
+		svc := newTestReplayService(jobs, sources, queue)
+		job := replayJob(3, 100)
+
+		done, err := svc.advanceJob(context.Background(), job)
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, mysqlModel.TaskReplayRunning, job.Status)
+		assert.Equal(t, int64(1), job.CursorTaskID)
+		assert.Equal(t, "task task-b: queue unavailable", job.LastError)
+
+		queue.failOn = ""
+		done, err = svc.advanceJob(context.Background(), job)
+		require.NoError(t, err)
+		assert.True(t, done)
+		assert.Empty(t, job.LastError)
+		assert.Equal(t, []string{"task-a", "task-b", "task-c"}, replayOf(queue.queued))
+	})
+
+	t.Run("paused target keeps the job running", func(t *testing.T) {
+		jobs := &fakeReplayJobs{}
+		svc := newTestReplayService(jobs, &fakeReplaySources{tasks: replaySources(2)}, &fakeReplayQueue{paused: true})
+		job := replayJob(5, 100)
+
+		done, err := svc.advanceJob(context.Background(), job)
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, int64(0), job.CursorTaskID)
+		assert.Contains(t, job.LastError, "is paused")
+	})
+
+	t.Run("failed listing is recorded", func(t *testing.T) {
+		jobs := &fakeReplayJobs{}
+		sources := &fakeReplaySources{err: errors.New("connection reset")}
+		svc := newTestReplayService(jobs, sources, &fakeReplayQueue{})
+		job := replayJob(5, 100)
+
+		done, err := svc.advanceJob(context.Background(), job)
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, "connection reset", job.LastError)
+		assert.Equal(t, 1, jobs.saves)
+	})
+}
+
+func TestAdvanceReplayJob_CancelledJob(t *testing.T) {
+	jobs := &fakeReplayJobs{cancelled: map[int64]bool{7: true}}
+	svc := newTestReplayService(jobs, &fakeReplaySources{tasks: replaySources(5)}, &fakeReplayQueue{})
+
+	// SaveProgress does not overwrite a cancelled job; the job stops
+	done, err := svc.advanceJob(context.Background(), replayJob(2, 100))
+	require.NoError(t, err)
+	assert.True(t, done)
+}
+
+func TestRunReplayJobs(t *testing.T) {
+	t.Run("runs until every job is done", func(t *testing.T) {
+		first, second := replayJob(5, 100), replayJob(5, 1)
+		second.ID = 8
+		jobs := &fakeReplayJobs{active: []*mysqlModel.TaskReplayJob{first, second}}
+		queue := &fakeReplayQueue{}
+		svc := newTestReplayService(jobs, &fakeReplaySources{tasks: replaySources(3)}, queue)
+
+		require.NoError(t, svc.RunJobs(context.Background(), time.Minute))
+		assert.Equal(t, mysqlModel.TaskReplayCompleted, first.Status)
+		assert.Equal(t, mysqlModel.TaskReplayCompleted, second.Status)
+		assert.Len(t, queue.queued, 4)
+	})
+
+	t.Run("stops at the budget", func(t *testing.T) {
+		job := replayJob(1, 100)
+		jobs := &fakeReplayJobs{active: []*mysqlModel.TaskReplayJob{job}}
+		svc := newTestReplayService(jobs, &fakeReplaySources{tasks: replaySources(3)}, &fakeReplayQueue{})
+
+		require.NoError(t, svc.RunJobs(context.Background(), 100*time.Millisecond))
+		assert.Equal(t, mysqlModel.TaskReplayRunning, job.Status)
+		assert.Equal(t, int64(1), job.CursorTaskID)
+	})
+
+	t.Run("job cancelled mid-run", func(t *testing.T) {
+		job := replayJob(1, 100)
+		jobs := &fakeReplayJobs{active: []*mysqlModel.TaskReplayJob{job}, cancelled: map[int64]bool{}}
+		jobs.onSave = func(job *mysqlModel.TaskReplayJob) {
+			if job.CursorTaskID == 2 {
+				jobs.cancelled[job.ID] = true
+			}
+		}
+		queue := &fakeReplayQueue{}
+		svc := newTestReplayService(jobs, &fakeReplaySources{tasks: replaySources(5)}, queue)
+
+		require.NoError(t, svc.RunJobs(context.Background(), time.Minute))
+		assert.Equal(t, []string{"task-a", "task-b"}, replayOf(queue.queued))
+	})
+
+	t.Run("context cancelled between passes", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		job := replayJob(1, 100)
+		jobs := &fakeReplayJobs{active: []*mysqlModel.TaskReplayJob{job}, onSave: func(*mysqlModel.TaskReplayJob) { cancel() }}
+		svc := newTestReplayService(jobs, &fakeReplaySources{tasks: replaySources(5)}, &fakeReplayQueue{})
+
+		err := svc.RunJobs(ctx, time.Minute)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int64(1), job.CursorTaskID)
+	})
+
+	t.Run("no active jobs", func(t *testing.T) {
+		svc := newTestReplayService(&fakeReplayJobs{}, &fakeReplaySources{}, &fakeReplayQueue{})
+		require.NoError(t, svc.RunJobs(context.Background(), time.Minute))
+	})
+}
+
+func TestCountsInStatistics(t *testing.T) {
+	assert.True(t, countsInStatistics(&mysql.Task{TaskID: "task-a"}))
+	assert.False(t, countsInStatistics(&mysql.Task{TaskID: "task-b", ReplayOf: "task-a"}))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -12,14 +13,20 @@ import (
 	"waverless/internal/model"
 	endpointsvc "waverless/internal/service/endpoint"
 	"waverless/pkg/config"
+	"waverless/pkg/hedging"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/longpoll"
+	"waverless/pkg/notification"
 	"waverless/pkg/store/mysql"
 	mysqlModel "waverless/pkg/store/mysql/model"
 
 	"github.com/google/uuid"
 )
 
+// ErrEndpointPaused is returned for submissions to an endpoint paused in reject mode
+var ErrEndpointPaused = errors.New("endpoint is paused")
+
 // TaskService Task service
 type TaskService struct {
 	taskRepo           *mysql.TaskRepository
@@ -28,6 +35,15 @@ type TaskService struct {
 	deploymentProvider interfaces.DeploymentProvider
 	statisticsService  *StatisticsService
 	workerService      *WorkerService
+	gpuUsageService    *GPUUsageService
+	federationService  *FederationService
+	samplingService    *SamplingService
+	transformService   *TransformService
+	integrationService *IntegrationService
+	taskSignals        *longpoll.Hub
+	wakeSignals        *longpoll.Hub
+	circuitBreaker     *CircuitBreakerService
+	gpuTierService     *GPUTierService
 }
 
 // NewTaskService creates a new Task service
@@ -45,11 +61,57 @@ func (s *TaskService) SetStatisticsService(statsService *StatisticsService) {
 	s.statisticsService = statsService
 }
 
+// SetGPUUsageService sets the GPU usage service (for dependency injection)
+func (s *TaskService) SetGPUUsageService(gpuUsageService *GPUUsageService) {
+	s.gpuUsageService = gpuUsageService
+}
+
 // SetWorkerService sets the worker service (for dependency injection)
 func (s *TaskService) SetWorkerService(workerService *WorkerService) {
 	s.workerService = workerService
 }
 
+// SetFederationService enables task submission to federated endpoints (for dependency injection)
+func (s *TaskService) SetFederationService(federationService *FederationService) {
+	s.federationService = federationService
+}
+
+// SetSamplingService enables input/output sampling of finished tasks (for dependency injection)
+func (s *TaskService) SetSamplingService(samplingService *SamplingService) {
+	s.samplingService = samplingService
+}
+
+// SetTransformService enables per-endpoint input/output transforms (for dependency injection)
+func (s *TaskService) SetTransformService(transformService *TransformService) {
+	s.transformService = transformService
+}
+
+// SetIntegrationService enables integration task webhooks and task events (for dependency injection)
+func (s *TaskService) SetIntegrationService(integrationService *IntegrationService) {
+	s.integrationService = integrationService
+}
+
+// SetTaskSignals wakes long-polling workers when tasks are queued (for dependency injection)
+func (s *TaskService) SetTaskSignals(hub *longpoll.Hub) {
+	s.taskSignals = hub
+}
+
+// SetWakeSignals asks the autoscaler to scale up endpoints at zero replicas as soon as they
+// get a task (for dependency injection)
+func (s *TaskService) SetWakeSignals(hub *longpoll.Hub) {
+	s.wakeSignals = hub
+}
+
+// SetCircuitBreaker fast-fails submissions to endpoints with an open circuit breaker (for dependency injection)
+func (s *TaskService) SetCircuitBreaker(circuitBreaker *CircuitBreakerService) {
+	s.circuitBreaker = circuitBreaker
+}
+
+// SetGPUTierService routes submissions of heterogeneous endpoints to their GPU tiers (for dependency injection)
+func (s *TaskService) SetGPUTierService(gpuTierService *GPUTierService) {
+	s.gpuTierService = gpuTierService
+}
+
 // SubmitTask submits a task
 func (s *TaskService) SubmitTask(ctx context.Context, req *model.SubmitRequest) (*model.SubmitResponse, error) {
 	taskID := uuid.New().String()
@@ -60,15 +122,56 @@ func (s *TaskService) SubmitTask(ctx context.Context, req *model.SubmitRequest)
 		endpoint = "default"
 	}
 
-	// Check if endpoint exists
-	if endpointMeta, err := s.endpointService.GetEndpointOnly(ctx, endpoint); err != nil || endpointMeta == nil {
-		return nil, fmt.Errorf("endpoint '%s' not found", endpoint)
+	// Check if endpoint exists, otherwise route a federated endpoint to one of its regions
+	var route *FederationRoute
+	tiered := false
+	endpointMeta, err := s.endpointService.GetEndpointOnly(ctx, endpoint)
+	if err != nil || endpointMeta == nil {
+		if s.federationService != nil && err == nil {
+			if route, err = s.federationService.Route(ctx, endpoint, req.Routing, req.ClientIP); err != nil {
+				return nil, err
+			}
+		}
+		if route == nil {
+			return nil, fmt.Errorf("endpoint '%s' not found", endpoint)
+		}
+		endpoint = route.Endpoint
+	} else {
+		// A heterogeneous endpoint hands the task to the GPU tier whose rules accept it
+		if s.gpuTierService != nil {
+			if tier := s.gpuTierService.Route(ctx, endpointMeta, req.Input, req.Routing.RequestedPrecision()); tier != nil {
+				endpointMeta, endpoint, tiered = tier, tier.Endpoint, true
+			}
+		}
+		if err := rejectIfPaused(endpointMeta); err != nil {
+			return nil, err
+		}
+		if s.circuitBreaker != nil {
+			if err := s.circuitBreaker.Admit(ctx, endpointMeta, taskID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// A webhook may name a registered integration ("integration:<name>") instead of a URL
+	if err := s.integrationService.ValidateReference(ctx, req.WebhookURL); err != nil {
+		return nil, err
+	}
+
+	// Apply the endpoint's input transform; the version is kept for the output transform
+	input := req.Input
+	transformVersion := 0
+	if s.transformService != nil {
+		var err error
+		if transformVersion, input, err = s.transformService.ApplyInput(ctx, endpoint, input); err != nil {
+			return nil, err
+		}
 	}
 
 	task := &model.Task{
 		ID:         taskID,
 		Endpoint:   endpoint,
-		Input:      req.Input,
+		Input:      input,
 		Status:     model.TaskStatusPending,
 		WebhookURL: req.WebhookURL,
 		CreatedAt:  time.Now(),
@@ -76,7 +179,47 @@ func (s *TaskService) SubmitTask(ctx context.Context, req *model.SubmitRequest)
 	}
 
 	mysqlTask := mysql.FromTaskDomain(task)
+	mysqlTask.TransformVersion = transformVersion
+	mysqlTask.SubjectHash = SubjectHash(req.Subject)
+
+	if err := s.createPendingTask(ctx, mysqlTask); err != nil {
+		return nil, err
+	}
+	logger.InfoCtx(ctx, "task submitted, task_id: %s, endpoint: %s", taskID, endpoint)
+
+	resp := &model.SubmitResponse{
+		ID:     taskID,
+		Status: model.TaskStatusPending,
+	}
+	if route != nil {
+		resp.Endpoint = route.Endpoint
+		resp.Region = route.Region
+	}
+	if tiered {
+		resp.Endpoint = endpoint
+	}
+	if endpointMeta != nil && endpointMeta.Replicas == 0 {
+		// Don't wait for the next autoscaler cycle
+		s.wakeSignals.Notify(ctx, endpoint)
+		resp.Waking = true
+	}
+	return resp, nil
+}
 
+// rejectIfPaused returns ErrEndpointPaused for an endpoint paused in reject mode
+func rejectIfPaused(endpoint *mysql.Endpoint) error {
+	if !endpoint.DispatchPaused || endpoint.PauseMode != mysqlModel.PauseModeReject {
+		return nil
+	}
+	if endpoint.PauseReason != "" {
+		return fmt.Errorf("%w: endpoint '%s' does not accept tasks: %s", ErrEndpointPaused, endpoint.Endpoint, endpoint.PauseReason)
+	}
+	return fmt.Errorf("%w: endpoint '%s' does not accept tasks", ErrEndpointPaused, endpoint.Endpoint)
+}
+
+// createPendingTask saves a new PENDING task with its events and statistics in a single
+// transaction and wakes workers waiting for its endpoint
+func (s *TaskService) createPendingTask(ctx context.Context, mysqlTask *mysql.Task) error {
 	// Execute all operations in a single transaction
 	err := s.taskRepo.ExecTx(ctx, func(txCtx context.Context) error {
 		// 1. Create task
@@ -94,23 +237,29 @@ func (s *TaskService) SubmitTask(ctx context.Context, req *model.SubmitRequest)
 		}
 
 		// 3. Update statistics
-		if s.statisticsService != nil {
-			s.statisticsService.UpdateStatisticsOnTaskStatusChange(txCtx, endpoint, "", "PENDING")
+		if s.statisticsService != nil && countsInStatistics(mysqlTask) {
+			s.statisticsService.UpdateStatisticsOnTaskStatusChange(txCtx, mysqlTask.Endpoint, "", "PENDING")
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	logger.InfoCtx(ctx, "task submitted, task_id: %s, endpoint: %s", taskID, endpoint)
+	s.taskSignals.Notify(ctx, mysqlTask.Endpoint)
+	return nil
+}
 
-	return &model.SubmitResponse{
-		ID:     taskID,
-		Status: model.TaskStatusPending,
-	}, nil
+// RunPodCompatEnabled reports whether an endpoint serves the RunPod client API paths.
+// Unknown endpoints are reported as enabled so SubmitTask can route or reject them.
+func (s *TaskService) RunPodCompatEnabled(ctx context.Context, endpoint string) (bool, error) {
+	endpointMeta, err := s.endpointService.GetEndpointOnly(ctx, endpoint)
+	if err != nil {
+		return false, fmt.Errorf("failed to get endpoint: %w", err)
+	}
+	return endpointMeta == nil || !endpointMeta.RunPodCompatOff, nil
 }
 
 // SubmitTaskSync submits a task synchronously (with timeout waiting)
@@ -225,10 +374,15 @@ func (s *TaskService) CancelTask(ctx context.Context, taskID string) error {
 	}
 
 	// Asynchronously update statistics (PENDING/IN_PROGRESS -> CANCELLED)
-	if s.statisticsService != nil {
+	if s.statisticsService != nil && countsInStatistics(mysqlTask) {
 		go s.statisticsService.UpdateStatisticsOnTaskStatusChange(context.Background(), endpoint, oldStatus, mysqlTask.Status)
 	}
 
+	// The hedge of a cancelled task is not needed either
+	if mysqlTask.HedgeOutcome == hedging.OutcomeRacing {
+		s.cancelHedge(ctx, taskID)
+	}
+
 	logger.InfoCtx(ctx, "task cancelled, task_id: %s", taskID)
 	return nil
 }
@@ -240,6 +394,17 @@ func (s *TaskService) UpdateTaskResult(ctx context.Context, req *model.JobResult
 		return err
 	}
 
+	// Of a hedged task and its hedge only the first result counts
+	if mysqlTask != nil && (mysqlTask.HedgeOf != "" || mysqlTask.HedgeOutcome != "") {
+		if handled, err := s.resolveHedge(ctx, mysqlTask, req); handled || err != nil {
+			return err
+		}
+	}
+	return s.applyTaskResult(ctx, mysqlTask, req)
+}
+
+// applyTaskResult stores the result of a task and notifies its webhook and integrations
+func (s *TaskService) applyTaskResult(ctx context.Context, mysqlTask *mysql.Task, req *model.JobResultRequest) error {
 	now := time.Now()
 	oldStatus := mysqlTask.Status // Save original status for statistics
 	endpoint := mysqlTask.Endpoint
@@ -250,6 +415,16 @@ func (s *TaskService) UpdateTaskResult(ctx context.Context, req *model.JobResult
 		"completed_at": now,
 	}
 
+	// Apply the output transform of the version the input was transformed with;
+	// a failing transform must not lose the result, so the raw output is kept
+	if req.Error == "" && s.transformService != nil && mysqlTask.TransformVersion > 0 {
+		if output, err := s.transformService.ApplyOutput(ctx, endpoint, mysqlTask.TransformVersion, req.Output); err != nil {
+			logger.WarnCtx(ctx, "output transform skipped, task_id: %s, error: %v", req.TaskID, err)
+		} else {
+			req.Output = output
+		}
+	}
+
 	var newStatus string
 	if req.Error != "" {
 		newStatus = "FAILED"
@@ -258,6 +433,7 @@ func (s *TaskService) UpdateTaskResult(ctx context.Context, req *model.JobResult
 
 		// Record TASK_FAILED event and update extend
 		mysqlTask.Status = newStatus
+		mysqlTask.Error = req.Error
 		s.recordTaskFailed(ctx, mysqlTask, mysqlTask.WorkerID, req.Error)
 		updates["extend"] = mysqlTask.Extend
 	} else {
@@ -273,13 +449,13 @@ func (s *TaskService) UpdateTaskResult(ctx context.Context, req *model.JobResult
 	}
 
 	// Update directly with WHERE + Updates
-	err = s.taskRepo.UpdateFields(ctx, req.TaskID, updates)
+	err := s.taskRepo.UpdateFields(ctx, req.TaskID, updates)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
 
 	// Asynchronously update statistics
-	if s.statisticsService != nil {
+	if s.statisticsService != nil && countsInStatistics(mysqlTask) {
 		go s.statisticsService.UpdateStatisticsOnTaskStatusChange(context.Background(), endpoint, oldStatus, newStatus)
 	}
 
@@ -298,6 +474,12 @@ func (s *TaskService) UpdateTaskResult(ctx context.Context, req *model.JobResult
 	// The mysqlTask object was fetched from DB before updates, so CompletedAt is still nil
 	// We must update it with the value from updates map for GPU usage recording to work
 	mysqlTask.CompletedAt = &now
+	if s.gpuUsageService != nil {
+		go s.gpuUsageService.RecordTaskUsage(context.Background(), mysqlTask)
+	}
+	if s.samplingService != nil {
+		go s.samplingService.CaptureTask(context.Background(), mysqlTask)
+	}
 
 	// 🔥 CRITICAL: Update endpoint's LastTaskTime (for autoscaler idle time calculation)
 	// If not updated, autoscaler will think endpoint is always idle, causing immediate scale-down after task completion
@@ -317,8 +499,8 @@ func (s *TaskService) UpdateTaskResult(ctx context.Context, req *model.JobResult
 		}
 	}
 
-	// If webhook is configured, call asynchronously
-	if mysqlTask.WebhookURL != "" {
+	// If webhook is configured, call asynchronously; integrations subscribed to task events are notified too
+	if mysqlTask.WebhookURL != "" || s.integrationService != nil {
 		task := mysql.ToTaskDomain(mysqlTask)
 		task.Status = model.TaskStatus(updates["status"].(string))
 		task.UpdatedAt = now
@@ -328,7 +510,24 @@ func (s *TaskService) UpdateTaskResult(ctx context.Context, req *model.JobResult
 		} else {
 			task.Output = req.Output
 		}
-		go s.callWebhook(context.Background(), task)
+
+		eventType := notification.EventTaskCompleted
+		if task.Status == model.TaskStatusFailed {
+			eventType = notification.EventTaskFailed
+		}
+		event := &notification.Event{
+			ID:        task.ID,
+			Type:      eventType,
+			Endpoint:  task.Endpoint,
+			CreatedAt: now,
+			Data:      s.toTaskResponse(task),
+		}
+		if name, ok := IntegrationReference(task.WebhookURL); ok && s.integrationService != nil {
+			go s.integrationService.DeliverTo(context.Background(), name, event, event.Data)
+		} else if task.WebhookURL != "" {
+			go s.callWebhook(context.Background(), task)
+		}
+		s.integrationService.Publish(ctx, event)
 	}
 
 	return nil
@@ -418,6 +617,23 @@ func (s *TaskService) CheckSubmitEligibility(ctx context.Context, endpoint strin
 // ListTasks retrieves a list of tasks with optional filtering
 // OPTIMIZATION: Excludes input field to avoid fetching potentially large data (e.g., base64 images)
 func (s *TaskService) ListTasks(ctx context.Context, status string, endpoint string, taskID string, workerID string, limit int, offset int) ([]*model.TaskResponse, int64, error) {
+	tasks, total, err := s.ListTaskRecords(ctx, status, endpoint, taskID, workerID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Convert to TaskResponse format (input will be nil/empty)
+	responses := make([]*model.TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		responses = append(responses, s.toTaskResponse(task))
+	}
+
+	return responses, total, nil
+}
+
+// ListTaskRecords retrieves tasks with optional filtering, newest first, and the total count of
+// matching tasks. The input field is not loaded.
+func (s *TaskService) ListTaskRecords(ctx context.Context, status string, endpoint string, taskID string, workerID string, limit int, offset int) ([]*model.Task, int64, error) {
 	// Build filters
 	filters := make(map[string]interface{})
 	if status != "" {
@@ -442,14 +658,12 @@ func (s *TaskService) ListTasks(ctx context.Context, status string, endpoint str
 		return nil, 0, err
 	}
 
-	// Convert to TaskResponse format (input will be nil/empty)
-	responses := make([]*model.TaskResponse, 0, len(mysqlTasks))
+	tasks := make([]*model.Task, 0, len(mysqlTasks))
 	for _, mysqlTask := range mysqlTasks {
-		task := mysql.ToTaskDomain(mysqlTask)
-		responses = append(responses, s.toTaskResponse(task))
+		tasks = append(tasks, mysql.ToTaskDomain(mysqlTask))
 	}
 
-	return responses, total, nil
+	return tasks, total, nil
 }
 
 // CountTasksByStatus counts tasks by status globally
@@ -600,13 +814,14 @@ func (s *TaskService) requeueOrphanedTask(ctx context.Context, task *mysql.Task,
 	}
 
 	// Asynchronously update statistics (non-critical, failure doesn't affect main flow)
-	if s.statisticsService != nil {
+	if s.statisticsService != nil && countsInStatistics(task) {
 		go s.statisticsService.UpdateStatisticsOnTaskStatusChange(
 			context.Background(), task.Endpoint, oldStatus, task.Status)
 	}
 
 	logger.InfoCtx(ctx, "✅ orphaned task re-queued successfully, task_id: %s, endpoint: %s, status: PENDING",
 		task.TaskID, task.Endpoint)
+	s.taskSignals.Notify(ctx, task.Endpoint)
 }
 
 // CleanupTimedOutTasks checks for tasks that have exceeded their execution timeout and fails them
@@ -706,7 +921,7 @@ func (s *TaskService) CleanupTimedOutTasks(ctx context.Context) error {
 			}
 
 			// Asynchronously update statistics (IN_PROGRESS -> FAILED)
-			if s.statisticsService != nil {
+			if s.statisticsService != nil && countsInStatistics(mysqlTask) {
 				go s.statisticsService.UpdateStatisticsOnTaskStatusChange(context.Background(), endpoint, oldStatus, mysqlTask.Status)
 			}
 
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+	"waverless/pkg/transform"
+)
+
+// transformCacheTTL bounds how long a new transform version takes to reach every replica
+const transformCacheTTL = 30 * time.Second
+
+// SaveTransformRequest creates a new version of an endpoint's transform
+type SaveTransformRequest struct {
+	transform.Spec
+	Comment string `json:"comment,omitempty"` // Change note
+}
+
+// RollbackTransformRequest restores an earlier version as the newest one
+type RollbackTransformRequest struct {
+	Version int    `json:"version"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// EndpointTransformVersion is a stored transform version with its parsed spec
+type EndpointTransformVersion struct {
+	Endpoint  string         `json:"endpoint"`
+	Version   int            `json:"version"`
+	Spec      transform.Spec `json:"spec"`
+	Comment   string         `json:"comment,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+type latestTransform struct {
+	version  *EndpointTransformVersion // nil = endpoint has no transform
+	loadedAt time.Time
+}
+
+// TransformService manages versioned per-endpoint transforms applied to task input at
+// submit time and to task output at completion. Versions are immutable, so a task always
+// gets the output transform matching the input transform it was submitted with.
+type TransformService struct {
+	repo *mysql.EndpointTransformRepository
+
+	mu       sync.RWMutex
+	latest   map[string]*latestTransform
+	versions map[string]*EndpointTransformVersion // endpoint/version -> spec, never stale
+}
+
+// NewTransformService creates a new transform service
+func NewTransformService(repo *mysql.EndpointTransformRepository) *TransformService {
+	return &TransformService{
+		repo:     repo,
+		latest:   make(map[string]*latestTransform),
+		versions: make(map[string]*EndpointTransformVersion),
+	}
+}
+
+// GetCurrent returns the newest transform version of an endpoint
+func (s *TransformService) GetCurrent(ctx context.Context, endpoint string) (*EndpointTransformVersion, error) {
+	t, err := s.repo.Latest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("transform for endpoint %s not found", endpoint)
+	}
+	return toTransformVersion(t)
+}
+
+// ListVersions returns every transform version of an endpoint, newest first
+func (s *TransformService) ListVersions(ctx context.Context, endpoint string) ([]*EndpointTransformVersion, error) {
+	rows, err := s.repo.ListVersions(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*EndpointTransformVersion, 0, len(rows))
+	for _, t := range rows {
+		v, err := toTransformVersion(t)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// Save validates the spec and stores it as the endpoint's next version.
+// Saving an empty spec disables transforms for new tasks.
+func (s *TransformService) Save(ctx context.Context, endpoint string, req *SaveTransformRequest) (*EndpointTransformVersion, error) {
+	if err := req.Spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid transform: %w", err)
+	}
+	return s.create(ctx, endpoint, &req.Spec, req.Comment)
+}
+
+// Rollback stores a copy of an earlier version as the endpoint's next version
+func (s *TransformService) Rollback(ctx context.Context, endpoint string, req *RollbackTransformRequest) (*EndpointTransformVersion, error) {
+	old, err := s.version(ctx, endpoint, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	if old == nil {
+		return nil, fmt.Errorf("transform version %d of endpoint %s not found", req.Version, endpoint)
+	}
+	comment := req.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("rollback to version %d", req.Version)
+	}
+	return s.create(ctx, endpoint, &old.Spec, comment)
+}
+
+func (s *TransformService) create(ctx context.Context, endpoint string, spec *transform.Spec, comment string) (*EndpointTransformVersion, error) {
+	doc, err := specToMap(spec)
+	if err != nil {
+		return nil, err
+	}
+	t := &model.EndpointTransform{
+		Endpoint: endpoint,
+		Spec:     doc,
+		Comment:  comment,
+	}
+	if err := s.repo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.latest, endpoint)
+	s.mu.Unlock()
+	return toTransformVersion(t)
+}
+
+// ApplyInput applies the endpoint's current input transform and returns the version to
+// record on the task (0 when the endpoint has no transform)
+func (s *TransformService) ApplyInput(ctx context.Context, endpoint string, input map[string]interface{}) (int, map[string]interface{}, error) {
+	current, err := s.current(ctx, endpoint)
+	if err != nil {
+		return 0, nil, err
+	}
+	if current == nil || (len(current.Spec.Input) == 0 && len(current.Spec.Output) == 0) {
+		return 0, input, nil
+	}
+	out, err := transform.Apply(current.Spec.Input, input)
+	if err != nil {
+		return 0, nil, fmt.Errorf("input transform v%d failed: %w", current.Version, err)
+	}
+	return current.Version, out, nil
+}
+
+// ApplyOutput applies the output transform of the version recorded on the task
+func (s *TransformService) ApplyOutput(ctx context.Context, endpoint string, version int, output map[string]interface{}) (map[string]interface{}, error) {
+	if version <= 0 {
+		return output, nil
+	}
+	v, err := s.version(ctx, endpoint, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("transform version %d of endpoint %s not found", version, endpoint)
+	}
+	out, err := transform.Apply(v.Spec.Output, output)
+	if err != nil {
+		return nil, fmt.Errorf("output transform v%d failed: %w", version, err)
+	}
+	return out, nil
+}
+
+// current returns the cached newest version, reloading it after transformCacheTTL
+func (s *TransformService) current(ctx context.Context, endpoint string) (*EndpointTransformVersion, error) {
+	s.mu.RLock()
+	cached, ok := s.latest[endpoint]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < transformCacheTTL {
+		return cached.version, nil
+	}
+
+	t, err := s.repo.Latest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var v *EndpointTransformVersion
+	if t != nil {
+		if v, err = toTransformVersion(t); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	s.latest[endpoint] = &latestTransform{version: v, loadedAt: time.Now()}
+	if v != nil {
+		s.versions[versionKey(endpoint, v.Version)] = v
+	}
+	s.mu.Unlock()
+	return v, nil
+}
+
+// version returns a specific version; versions are immutable so they are cached forever
+func (s *TransformService) version(ctx context.Context, endpoint string, version int) (*EndpointTransformVersion, error) {
+	key := versionKey(endpoint, version)
+	s.mu.RLock()
+	cached, ok := s.versions[key]
+	s.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	t, err := s.repo.Get(ctx, endpoint, version)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	v, err := toTransformVersion(t)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.versions[key] = v
+	s.mu.Unlock()
+	return v, nil
+}
+
+func versionKey(endpoint string, version int) string {
+	return fmt.Sprintf("%s/%d", endpoint, version)
+}
+
+func toTransformVersion(t *model.EndpointTransform) (*EndpointTransformVersion, error) {
+	v := &EndpointTransformVersion{
+		Endpoint:  t.Endpoint,
+		Version:   t.Version,
+		Comment:   t.Comment,
+		CreatedAt: t.CreatedAt,
+	}
+	data, err := json.Marshal(t.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transform v%d: %w", t.Version, err)
+	}
+	if err := json.Unmarshal(data, &v.Spec); err != nil {
+		return nil, fmt.Errorf("failed to decode transform v%d: %w", t.Version, err)
+	}
+	return v, nil
+}
+
+func specToMap(spec *transform.Spec) (model.JSONMap, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transform: %w", err)
+	}
+	doc := make(model.JSONMap)
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to encode transform: %w", err)
+	}
+	return doc, nil
+}
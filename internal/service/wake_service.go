@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/wake"
+)
+
+const (
+	// wakePollInterval is how often a waiting submission checks for a ready worker
+	wakePollInterval = 500 * time.Millisecond
+	// coldStartLookback is the window of cold starts the Retry-After estimate is based on
+	coldStartLookback = 7 * 24 * time.Hour
+)
+
+// WakeService lets submissions to an endpoint scaled to zero wait for its first worker and
+// estimates when to retry otherwise. The scale-up itself is triggered by TaskService on submit.
+type WakeService struct {
+	cfg            config.WakeConfig
+	workerRepo     *mysql.WorkerRepository
+	monitoringRepo *mysql.MonitoringRepository
+}
+
+// NewWakeService creates a new wake service
+func NewWakeService(cfg config.WakeConfig, workerRepo *mysql.WorkerRepository, monitoringRepo *mysql.MonitoringRepository) *WakeService {
+	return &WakeService{
+		cfg:            cfg,
+		workerRepo:     workerRepo,
+		monitoringRepo: monitoringRepo,
+	}
+}
+
+// Budget returns the longest a submission may wait for a worker
+func (s *WakeService) Budget() time.Duration {
+	return s.cfg.ColdStartBudget
+}
+
+// WaitReady waits up to wait for a worker of the endpoint to take tasks. It returns false
+// when none did in time.
+func (s *WakeService) WaitReady(ctx context.Context, endpoint string, wait time.Duration) (bool, error) {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(wakePollInterval)
+	defer ticker.Stop()
+
+	for {
+		ready, err := s.workerRepo.CountReady(ctx, endpoint)
+		if err != nil {
+			return false, err
+		}
+		if ready > 0 {
+			return true, nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-deadline.C:
+			return false, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RetryAfter estimates when an endpoint that is still cold after waited has a worker, from
+// the median cold start of its recent workers
+func (s *WakeService) RetryAfter(ctx context.Context, endpoint string, waited time.Duration) time.Duration {
+	now := time.Now()
+	samples, err := s.monitoringRepo.ListColdStartSamples(ctx, endpoint, now.Add(-coldStartLookback), now)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load cold starts, endpoint: %s, error: %v", endpoint, err)
+	}
+	durations := make([]time.Duration, 0, len(samples))
+	for _, sample := range samples {
+		if sample.PodCreatedAt != nil && sample.RegisteredAt != nil && sample.RegisteredAt.After(*sample.PodCreatedAt) {
+			durations = append(durations, sample.RegisteredAt.Sub(*sample.PodCreatedAt))
+		}
+	}
+	return wake.RetryAfter(wake.ColdStart(durations, s.cfg.DefaultColdStart), waited)
+}
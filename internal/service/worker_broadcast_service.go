@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"waverless/internal/model"
+	endpointsvc "waverless/internal/service/endpoint"
+	"waverless/pkg/constants"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+const (
+	// defaultBroadcastTTL is how long a broadcast is delivered when the request sets no TTL
+	defaultBroadcastTTL = time.Hour
+	// maxBroadcastTTL bounds how long a broadcast can stay pending
+	maxBroadcastTTL = 24 * time.Hour
+	// broadcastListLimit is how many broadcasts of an endpoint are listed
+	broadcastListLimit = 50
+	// maxBroadcastMessageLen bounds the failure message stored for a delivery
+	maxBroadcastMessageLen = 512
+)
+
+// WorkerConfigBroadcastType is the broadcast type carrying runtime worker settings: the payload
+// has "log_level" and/or "feature_flags" (flag name -> bool, unlisted flags keep their value)
+const WorkerConfigBroadcastType = "worker-config"
+
+// workerLogLevels log levels a worker can be switched to
+var workerLogLevels = map[string]bool{"debug": true, "info": true, "warning": true, "error": true, "critical": true}
+
+// CreateBroadcastRequest is a control message for every worker of an endpoint
+type CreateBroadcastRequest struct {
+	Type       string                 `json:"type" binding:"required"` // e.g. "weights-updated", "flush-cache"
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+	TTLSeconds int                    `json:"ttlSeconds,omitempty"` // How long unacknowledged workers keep receiving it (default 3600)
+	WorkerID   string                 `json:"workerId,omitempty"`   // Send to this worker (worker ID or pod name) only
+}
+
+// WorkerConfigRequest changes the log verbosity or feature flags of running workers
+type WorkerConfigRequest struct {
+	WorkerID     string          `json:"workerId,omitempty"`     // Worker ID or pod name; empty = every worker of the endpoint
+	LogLevel     string          `json:"logLevel,omitempty"`     // debug, info, warning, error or critical
+	FeatureFlags map[string]bool `json:"featureFlags,omitempty"` // Flags to set; unlisted flags keep their value
+	TTLSeconds   int             `json:"ttlSeconds,omitempty"`   // How long unacknowledged workers keep receiving it (default 3600)
+}
+
+// BroadcastSummary counts the deliveries of a broadcast by status
+type BroadcastSummary struct {
+	Workers     int `json:"workers"` // Workers the broadcast was addressed to
+	Acked       int `json:"acked"`
+	Failed      int `json:"failed"`
+	Pending     int `json:"pending"`
+	Unreachable int `json:"unreachable"` // Pending deliveries whose worker went offline (details only)
+}
+
+// BroadcastDelivery is the delivery of a broadcast to one worker with the worker's current status
+type BroadcastDelivery struct {
+	*mysqlModel.WorkerBroadcastDelivery
+	WorkerStatus string `json:"worker_status"` // Empty when the worker no longer exists
+}
+
+// BroadcastDetail is a broadcast with its delivery progress
+type BroadcastDetail struct {
+	*mysqlModel.WorkerBroadcast
+	Expired    bool                 `json:"expired"`  // Pending workers no longer receive it
+	Complete   bool                 `json:"complete"` // Every addressed worker acknowledged it
+	Summary    BroadcastSummary     `json:"summary"`
+	Skipped    []string             `json:"skipped,omitempty"`    // Workers without the broadcast capability (creation only)
+	Deliveries []*BroadcastDelivery `json:"deliveries,omitempty"` // Per-worker deliveries (details only)
+}
+
+// WorkerBroadcastService sends control messages (e.g. "model weights updated, flush your
+// cache") to all workers of an endpoint through the heartbeat and tracks their
+// acknowledgements, so caches can be invalidated without a redeploy
+type WorkerBroadcastService struct {
+	repo            *mysql.WorkerBroadcastRepository
+	workerRepo      *mysql.WorkerRepository
+	endpointService *endpointsvc.Service
+}
+
+// NewWorkerBroadcastService creates a new worker broadcast service
+func NewWorkerBroadcastService(repo *mysql.WorkerBroadcastRepository, workerRepo *mysql.WorkerRepository, endpointService *endpointsvc.Service) *WorkerBroadcastService {
+	return &WorkerBroadcastService{
+		repo:            repo,
+		workerRepo:      workerRepo,
+		endpointService: endpointService,
+	}
+}
+
+// Create sends a broadcast to the current workers of an endpoint (or the one worker named in the
+// request) that announced the broadcast capability. Workers registering later start with fresh
+// state and don't receive it.
+func (s *WorkerBroadcastService) Create(ctx context.Context, endpoint string, req *CreateBroadcastRequest, createdBy string) (*BroadcastDetail, error) {
+	if err := s.checkEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	broadcastType := strings.TrimSpace(req.Type)
+	if broadcastType == "" || len(broadcastType) > 100 {
+		return nil, fmt.Errorf("invalid broadcast: type must be 1-100 characters")
+	}
+	ttl := defaultBroadcastTTL
+	if req.TTLSeconds < 0 {
+		return nil, fmt.Errorf("invalid broadcast: ttlSeconds must be >= 0")
+	} else if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl > maxBroadcastTTL {
+		return nil, fmt.Errorf("invalid broadcast: ttlSeconds must be <= %d", int(maxBroadcastTTL.Seconds()))
+	}
+
+	workers, err := s.workerRepo.GetByEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	addressed, skipped, err := broadcastTargets(workers, req.WorkerID)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcast := &mysqlModel.WorkerBroadcast{
+		Endpoint:  endpoint,
+		Type:      broadcastType,
+		Payload:   mysqlModel.JSONMap(req.Payload),
+		CreatedBy: createdBy,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.repo.Create(ctx, broadcast, addressed); err != nil {
+		return nil, err
+	}
+	if len(skipped) > 0 {
+		logger.WarnCtx(ctx, "broadcast %d not sent to workers without the broadcast capability, endpoint: %s, workers: %v", broadcast.ID, endpoint, skipped)
+	}
+
+	return &BroadcastDetail{
+		WorkerBroadcast: broadcast,
+		Complete:        len(addressed) == 0,
+		Summary:         BroadcastSummary{Workers: len(addressed), Pending: len(addressed)},
+		Skipped:         skipped,
+	}, nil
+}
+
+// PushWorkerConfig switches the log level or feature flags of one worker or of every worker of
+// an endpoint at runtime, through a worker-config broadcast. Workers registering later start
+// with their deployed settings.
+func (s *WorkerBroadcastService) PushWorkerConfig(ctx context.Context, endpoint string, req *WorkerConfigRequest, requestedBy string) (*BroadcastDetail, error) {
+	payload, err := workerConfigPayload(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Create(ctx, endpoint, &CreateBroadcastRequest{
+		Type:       WorkerConfigBroadcastType,
+		Payload:    payload,
+		TTLSeconds: req.TTLSeconds,
+		WorkerID:   strings.TrimSpace(req.WorkerID),
+	}, requestedBy)
+}
+
+// List returns the recent broadcasts of an endpoint with their delivery counts, newest first
+func (s *WorkerBroadcastService) List(ctx context.Context, endpoint string) ([]*BroadcastDetail, error) {
+	if err := s.checkEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	broadcasts, err := s.repo.ListByEndpoint(ctx, endpoint, broadcastListLimit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(broadcasts))
+	for _, b := range broadcasts {
+		ids = append(ids, b.ID)
+	}
+	counts, err := s.repo.CountDeliveries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	details := make([]*BroadcastDetail, 0, len(broadcasts))
+	for _, b := range broadcasts {
+		c := counts[b.ID]
+		summary := BroadcastSummary{
+			Workers: c[mysqlModel.BroadcastDeliveryPending] + c[mysqlModel.BroadcastDeliveryAcked] + c[mysqlModel.BroadcastDeliveryFailed],
+			Acked:   c[mysqlModel.BroadcastDeliveryAcked],
+			Failed:  c[mysqlModel.BroadcastDeliveryFailed],
+			Pending: c[mysqlModel.BroadcastDeliveryPending],
+		}
+		details = append(details, &BroadcastDetail{
+			WorkerBroadcast: b,
+			Expired:         !now.Before(b.ExpiresAt),
+			Complete:        summary.Pending == 0,
+			Summary:         summary,
+		})
+	}
+	return details, nil
+}
+
+// Get returns a broadcast of an endpoint with the delivery to each addressed worker
+func (s *WorkerBroadcastService) Get(ctx context.Context, endpoint string, id int64) (*BroadcastDetail, error) {
+	broadcast, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if broadcast == nil || broadcast.Endpoint != endpoint {
+		return nil, fmt.Errorf("broadcast %d not found", id)
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	workers, err := s.workerRepo.GetByEndpointForSync(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	workerStatus := make(map[string]string, len(workers))
+	for _, w := range workers {
+		workerStatus[w.WorkerID] = w.Status
+	}
+
+	detail := &BroadcastDetail{
+		WorkerBroadcast: broadcast,
+		Expired:         !time.Now().Before(broadcast.ExpiresAt),
+		Deliveries:      make([]*BroadcastDelivery, 0, len(deliveries)),
+	}
+	for _, d := range deliveries {
+		status := workerStatus[d.WorkerID]
+		detail.Deliveries = append(detail.Deliveries, &BroadcastDelivery{WorkerBroadcastDelivery: d, WorkerStatus: status})
+		detail.Summary.Workers++
+		switch d.Status {
+		case mysqlModel.BroadcastDeliveryAcked:
+			detail.Summary.Acked++
+		case mysqlModel.BroadcastDeliveryFailed:
+			detail.Summary.Failed++
+		default:
+			detail.Summary.Pending++
+			if status == "" || status == constants.WorkerStatusOffline.String() {
+				detail.Summary.Unreachable++
+			}
+		}
+	}
+	detail.Complete = detail.Summary.Pending == 0
+	return detail, nil
+}
+
+// Acknowledge records the acknowledgements a worker sent with its heartbeat. Failures are
+// logged: a missed acknowledgement only means the broadcast is sent again.
+func (s *WorkerBroadcastService) Acknowledge(ctx context.Context, workerID string, acks []*model.BroadcastAck) {
+	now := time.Now()
+	for _, ack := range acks {
+		status, message := mysqlModel.BroadcastDeliveryAcked, ""
+		if ack.Error != "" {
+			status, message = mysqlModel.BroadcastDeliveryFailed, ack.Error
+			if len(message) > maxBroadcastMessageLen {
+				message = message[:maxBroadcastMessageLen]
+			}
+		}
+		updated, err := s.repo.Acknowledge(ctx, workerID, ack.ID, status, message, now)
+		if err != nil {
+			logger.WarnCtx(ctx, "failed to record broadcast acknowledgement, worker_id: %s, broadcast_id: %d, error: %v", workerID, ack.ID, err)
+			continue
+		}
+		if updated && status == mysqlModel.BroadcastDeliveryFailed {
+			logger.WarnCtx(ctx, "worker failed to apply broadcast, worker_id: %s, broadcast_id: %d, error: %s", workerID, ack.ID, message)
+		}
+	}
+}
+
+// Pending returns the broadcasts to send to a worker with its heartbeat response
+func (s *WorkerBroadcastService) Pending(ctx context.Context, workerID string) []*model.BroadcastMessage {
+	pending, err := s.repo.ListPending(ctx, workerID, time.Now())
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load pending broadcasts, worker_id: %s, error: %v", workerID, err)
+		return nil
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	messages := make([]*model.BroadcastMessage, 0, len(pending))
+	for _, p := range pending {
+		messages = append(messages, &model.BroadcastMessage{ID: p.ID, Type: p.Type, Payload: p.Payload})
+	}
+	return messages
+}
+
+// broadcastTargets splits the workers a broadcast is addressed to (all, or the one matching
+// workerID by worker ID or pod name) into those that announced the broadcast capability and
+// those skipped without it
+func broadcastTargets(workers []*mysqlModel.Worker, workerID string) (addressed, skipped []string, err error) {
+	for _, w := range workers {
+		if workerID != "" && w.WorkerID != workerID && w.PodName != workerID {
+			continue
+		}
+		if model.HasWorkerCapability(w.CapabilityList(), model.WorkerCapabilityBroadcast) {
+			addressed = append(addressed, w.WorkerID)
+		} else {
+			skipped = append(skipped, w.WorkerID)
+		}
+	}
+	sort.Strings(addressed)
+	sort.Strings(skipped)
+	if workerID != "" {
+		if len(addressed)+len(skipped) == 0 {
+			return nil, nil, fmt.Errorf("worker %s not found", workerID)
+		}
+		if len(addressed) == 0 {
+			return nil, nil, fmt.Errorf("invalid broadcast: worker %s does not announce the broadcast capability", workerID)
+		}
+	}
+	return addressed, skipped, nil
+}
+
+// workerConfigPayload validates a worker config request and builds the worker-config payload
+func workerConfigPayload(req *WorkerConfigRequest) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	if req.LogLevel != "" {
+		level := strings.ToLower(strings.TrimSpace(req.LogLevel))
+		if level == "warn" {
+			level = "warning"
+		}
+		if !workerLogLevels[level] {
+			return nil, fmt.Errorf("invalid worker config: logLevel must be debug, info, warning, error or critical")
+		}
+		payload["log_level"] = level
+	}
+	if len(req.FeatureFlags) > 0 {
+		for name := range req.FeatureFlags {
+			if strings.TrimSpace(name) == "" || len(name) > 100 {
+				return nil, fmt.Errorf("invalid worker config: feature flag names must be 1-100 characters")
+			}
+		}
+		payload["feature_flags"] = req.FeatureFlags
+	}
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("invalid worker config: logLevel or featureFlags is required")
+	}
+	return payload, nil
+}
+
+func (s *WorkerBroadcastService) checkEndpoint(ctx context.Context, name string) error {
+	ep, err := s.endpointService.GetEndpointOnly(ctx, name)
+	if err != nil {
+		return err
+	}
+	if ep == nil || ep.Status == "deleted" {
+		return fmt.Errorf("endpoint %s not found", name)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	mysqlModel "waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerConfigPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     WorkerConfigRequest
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "log level is normalized",
+			req:  WorkerConfigRequest{LogLevel: " DEBUG "},
+			want: map[string]interface{}{"log_level": "debug"},
+		},
+		{
+			name: "warn is an alias of warning",
+			req:  WorkerConfigRequest{LogLevel: "Warn"},
+			want: map[string]interface{}{"log_level": "warning"},
+		},
+		{
+			name:    "unknown log level",
+			req:     WorkerConfigRequest{LogLevel: "verbose"},
+			wantErr: "invalid worker config: logLevel must be",
+		},
+		{
+			name: "feature flags and log level",
+			req:  WorkerConfigRequest{LogLevel: "error", FeatureFlags: map[string]bool{"new_sampler": true, "fp8": false}},
+			want: map[string]interface{}{"log_level": "error", "feature_flags": map[string]bool{"new_sampler": true, "fp8": false}},
+		},
+		{
+			name: "flag name at the length limit",
+			req:  WorkerConfigRequest{FeatureFlags: map[string]bool{strings.Repeat("f", 100): true}},
+			want: map[string]interface{}{"feature_flags": map[string]bool{strings.Repeat("f", 100): true}},
+		},
+		{
+			name:    "flag name over the length limit",
+			req:     WorkerConfigRequest{FeatureFlags: map[string]bool{strings.Repeat("f", 101): true}},
+			wantErr: "feature flag names must be 1-100 characters",
+		},
+		{
+			name:    "blank flag name",
+			req:     WorkerConfigRequest{FeatureFlags: map[string]bool{"  ": true}},
+			wantErr: "feature flag names must be 1-100 characters",
+		},
+		{
+			name:    "empty payload",
+			req:     WorkerConfigRequest{WorkerID: "worker-1", TTLSeconds: 60},
+			wantErr: "invalid worker config: logLevel or featureFlags is required",
+		},
+		{
+			name:    "empty feature flags",
+			req:     WorkerConfigRequest{FeatureFlags: map[string]bool{}},
+			wantErr: "invalid worker config: logLevel or featureFlags is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := workerConfigPayload(&tt.req)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBroadcastTargets(t *testing.T) {
+	capabilities := func(s string) *string { return &s }
+	workers := []*mysqlModel.Worker{
+		{WorkerID: "w-b", PodName: "pod-b", Capabilities: capabilities(`["broadcast","streaming"]`)},
+		{WorkerID: "w-a", PodName: "pod-a", Capabilities: capabilities(`["broadcast"]`)},
+		{WorkerID: "w-legacy", PodName: "pod-legacy"},
+		{WorkerID: "w-stream", PodName: "pod-stream", Capabilities: capabilities(`["streaming"]`)},
+	}
+
+	tests := []struct {
+		name      string
+		workerID  string
+		addressed []string
+		skipped   []string
+		wantErr   string
+	}{
+		{name: "every worker", addressed: []string{"w-a", "w-b"}, skipped: []string{"w-legacy", "w-stream"}},
+		{name: "by worker id", workerID: "w-b", addressed: []string{"w-b"}},
+		{name: "by pod name", workerID: "pod-a", addressed: []string{"w-a"}},
+		{name: "unknown worker", workerID: "w-gone", wantErr: "worker w-gone not found"},
+		{name: "worker without the capability", workerID: "w-stream", wantErr: "invalid broadcast: worker w-stream does not announce the broadcast capability"},
+		{name: "worker that never declared capabilities", workerID: "pod-legacy", wantErr: "does not announce the broadcast capability"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addressed, skipped, err := broadcastTargets(workers, tt.workerID)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.addressed, addressed)
+			assert.Equal(t, tt.skipped, skipped)
+		})
+	}
+
+	// No workers at all is not an error for an endpoint-wide broadcast
+	addressed, skipped, err := broadcastTargets(nil, "")
+	require.NoError(t, err)
+	assert.Empty(t, addressed)
+	assert.Empty(t, skipped)
+}
@@ -19,6 +19,12 @@ func NewWorkerEventService(monitoringRepo *mysql.MonitoringRepository) *WorkerEv
 	return &WorkerEventService{monitoringRepo: monitoringRepo}
 }
 
+// onceEventID derives a stable event ID for events recorded at most once per worker,
+// so concurrent replicas handling the same transition insert the same row
+func onceEventID(workerID string, eventType model.WorkerEventType) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(string(eventType)+"/"+workerID)).String()
+}
+
 // RecordWorkerRegistered records when a worker becomes ready (first heartbeat, idempotent)
 func (s *WorkerEventService) RecordWorkerRegistered(ctx context.Context, workerID, endpoint, podName string, coldStartMs *int64) {
 	var count int64
@@ -27,14 +33,14 @@ func (s *WorkerEventService) RecordWorkerRegistered(ctx context.Context, workerI
 		return
 	}
 	event := &model.WorkerEvent{
-		EventID:             uuid.New().String(),
+		EventID:             onceEventID(workerID, model.EventWorkerRegistered),
 		WorkerID:            workerID,
 		Endpoint:            endpoint,
 		EventType:           string(model.EventWorkerRegistered),
 		EventTime:           time.Now(),
 		ColdStartDurationMs: coldStartMs,
 	}
-	s.monitoringRepo.SaveWorkerEvent(ctx, event)
+	s.monitoringRepo.SaveWorkerEventOnce(ctx, event)
 }
 
 // RecordWorkerTaskPulled records when a worker pulls a task (with idle duration)
@@ -72,12 +78,25 @@ func (s *WorkerEventService) RecordWorkerStarted(ctx context.Context, workerID,
 		return
 	}
 	event := &model.WorkerEvent{
-		EventID:   uuid.New().String(),
+		EventID:   onceEventID(workerID, model.EventWorkerStarted),
 		WorkerID:  workerID,
 		Endpoint:  endpoint,
 		EventType: string(model.EventWorkerStarted),
 		EventTime: time.Now(),
 	}
+	s.monitoringRepo.SaveWorkerEventOnce(ctx, event)
+}
+
+// RecordWorkerDebugAttached records an ephemeral debug container attach (audit trail)
+func (s *WorkerEventService) RecordWorkerDebugAttached(ctx context.Context, workerID, endpoint string, metadata map[string]interface{}) {
+	event := &model.WorkerEvent{
+		EventID:   uuid.New().String(),
+		WorkerID:  workerID,
+		Endpoint:  endpoint,
+		EventType: string(model.EventWorkerDebugAttached),
+		EventTime: time.Now(),
+		Metadata:  metadata,
+	}
 	s.monitoringRepo.SaveWorkerEvent(ctx, event)
 }
 
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+// DefaultWorkerIntegrityGrace is how old a worker row or pod must be before a mismatch is reported.
+// It covers the window between pod creation and the watcher writing the row, and watcher lag on delete.
+const DefaultWorkerIntegrityGrace = 5 * time.Minute
+
+// OrphanWorker is an active worker row whose pod no longer exists
+type OrphanWorker struct {
+	Endpoint      string    `json:"endpoint"`
+	WorkerID      string    `json:"workerId"`
+	PodName       string    `json:"podName"`
+	Status        string    `json:"status"`
+	LastHeartbeat time.Time `json:"lastHeartbeat"`
+	ClosedOut     bool      `json:"closedOut"` // Marked OFFLINE by this pass
+}
+
+// UnregisteredPod is a live pod without an active worker row
+type UnregisteredPod struct {
+	Endpoint  string `json:"endpoint"`
+	PodName   string `json:"podName"`
+	Phase     string `json:"phase"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// WorkerIntegrityReport summarizes one cross-check of the workers table against live pods
+type WorkerIntegrityReport struct {
+	CheckedAt        time.Time          `json:"checkedAt"`
+	DryRun           bool               `json:"dryRun"`
+	Endpoints        int                `json:"endpoints"` // Endpoints whose pods were listed
+	Workers          int                `json:"workers"`   // Active worker rows checked
+	Pods             int                `json:"pods"`      // Live pods checked
+	OrphanWorkers    []*OrphanWorker    `json:"orphanWorkers"`
+	UnregisteredPods []*UnregisteredPod `json:"unregisteredPods"`
+	Failed           []string           `json:"failed"` // Endpoints whose pods could not be listed (skipped)
+}
+
+// WorkerIntegrityStats are the integrity counters since startup, plus the discrepancies of the last pass
+type WorkerIntegrityStats struct {
+	Passes           int64      `json:"passes"`
+	LastPassAt       *time.Time `json:"lastPassAt,omitempty"`
+	OrphanWorkers    int        `json:"orphanWorkers"`    // Orphan rows found by the last pass
+	UnregisteredPods int        `json:"unregisteredPods"` // Pods without a row found by the last pass
+	FailedEndpoints  int        `json:"failedEndpoints"`  // Endpoints skipped by the last pass
+	ClosedOutTotal   int64      `json:"closedOutTotal"`   // Orphan rows marked OFFLINE since startup
+}
+
+// integrityWorkerStore is the part of the workers table the integrity check reads and closes out
+type integrityWorkerStore interface {
+	GetAll(ctx context.Context) ([]*mysqlModel.Worker, error)
+	MarkOfflineByPodName(ctx context.Context, podName string) error
+}
+
+// integrityEndpointStore lists the endpoints whose pods are checked
+type integrityEndpointStore interface {
+	List(ctx context.Context) ([]*mysqlModel.Endpoint, error)
+}
+
+// WorkerIntegrityService cross-checks the workers table against the pods the deployment provider
+// reports. Rows whose pod is gone (a missed delete event) are closed out the way the pod watcher
+// would have. Pods without a row are only reported: the row carries state (worker ID,
+// capabilities) that only the pod watcher and the worker's own heartbeat can fill in.
+type WorkerIntegrityService struct {
+	workerRepo         integrityWorkerStore
+	endpointRepo       integrityEndpointStore
+	provider           interfaces.DeploymentProvider
+	workerEventService *WorkerEventService
+	grace              time.Duration
+	heartbeatTimeout   time.Duration
+	now                func() time.Time
+
+	mu    sync.Mutex
+	stats WorkerIntegrityStats
+}
+
+// NewWorkerIntegrityService creates a worker integrity service
+func NewWorkerIntegrityService(workerRepo *mysql.WorkerRepository, endpointRepo *mysql.EndpointRepository, provider interfaces.DeploymentProvider, grace, heartbeatTimeout time.Duration) *WorkerIntegrityService {
+	if grace <= 0 {
+		grace = DefaultWorkerIntegrityGrace
+	}
+	s := &WorkerIntegrityService{
+		provider:         provider,
+		grace:            grace,
+		heartbeatTimeout: heartbeatTimeout,
+		now:              time.Now,
+	}
+	// Keep the stores nil rather than wrapping nil pointers, so run reports the missing setup
+	if workerRepo != nil {
+		s.workerRepo = workerRepo
+	}
+	if endpointRepo != nil {
+		s.endpointRepo = endpointRepo
+	}
+	return s
+}
+
+// SetWorkerEventService sets the worker event service (for dependency injection)
+func (s *WorkerIntegrityService) SetWorkerEventService(workerEventService *WorkerEventService) {
+	s.workerEventService = workerEventService
+}
+
+// Check reports discrepancies without changing anything
+func (s *WorkerIntegrityService) Check(ctx context.Context) (*WorkerIntegrityReport, error) {
+	return s.run(ctx, true)
+}
+
+// Reconcile reports discrepancies and closes out orphan worker rows
+func (s *WorkerIntegrityService) Reconcile(ctx context.Context) (*WorkerIntegrityReport, error) {
+	return s.run(ctx, false)
+}
+
+// Stats returns the integrity counters
+func (s *WorkerIntegrityService) Stats() WorkerIntegrityStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+func (s *WorkerIntegrityService) run(ctx context.Context, dryRun bool) (*WorkerIntegrityReport, error) {
+	if s.workerRepo == nil || s.endpointRepo == nil || s.provider == nil {
+		return nil, fmt.Errorf("worker integrity check not configured")
+	}
+
+	now := s.now()
+	report := &WorkerIntegrityReport{
+		CheckedAt:        now,
+		DryRun:           dryRun,
+		OrphanWorkers:    []*OrphanWorker{},
+		UnregisteredPods: []*UnregisteredPod{},
+		Failed:           []string{},
+	}
+
+	workers, err := s.workerRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	endpoints, err := s.endpointRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints: %w", err)
+	}
+
+	// Rows of endpoints missing from the endpoints table are checked too: deleting an
+	// endpoint while its watcher was down is one way orphans appear
+	rowsByEndpoint := make(map[string][]*mysqlModel.Worker)
+	for _, w := range workers {
+		rowsByEndpoint[w.Endpoint] = append(rowsByEndpoint[w.Endpoint], w)
+	}
+	names := make(map[string]struct{}, len(endpoints)+len(rowsByEndpoint))
+	for _, ep := range endpoints {
+		names[ep.Endpoint] = struct{}{}
+	}
+	for name := range rowsByEndpoint {
+		names[name] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, endpoint := range sorted {
+		pods, err := s.provider.GetPods(ctx, endpoint)
+		if err != nil {
+			logger.WarnCtx(ctx, "worker integrity: failed to list pods of endpoint %s: %v", endpoint, err)
+			report.Failed = append(report.Failed, endpoint)
+			continue
+		}
+		report.Endpoints++
+		report.Workers += len(rowsByEndpoint[endpoint])
+		report.Pods += len(pods)
+		s.checkEndpoint(ctx, endpoint, rowsByEndpoint[endpoint], pods, dryRun, report)
+	}
+
+	s.record(report)
+	return report, nil
+}
+
+// checkEndpoint compares one endpoint's active rows with its pods
+func (s *WorkerIntegrityService) checkEndpoint(ctx context.Context, endpoint string, rows []*mysqlModel.Worker, pods []*interfaces.PodInfo, dryRun bool, report *WorkerIntegrityReport) {
+	now := s.now()
+	live := make(map[string]*interfaces.PodInfo, len(pods))
+	for _, pod := range pods {
+		if pod != nil {
+			live[pod.Name] = pod
+		}
+	}
+
+	registered := make(map[string]struct{}, len(rows))
+	for _, w := range rows {
+		if w.PodName == "" {
+			continue
+		}
+		registered[w.PodName] = struct{}{}
+		if _, ok := live[w.PodName]; ok {
+			continue
+		}
+		// A row younger than the grace period may belong to a pod the provider does not list yet,
+		// and a fresh heartbeat means something is still serving under that pod name
+		if now.Sub(w.CreatedAt) < s.grace || (s.heartbeatTimeout > 0 && now.Sub(w.LastHeartbeat) < s.heartbeatTimeout) {
+			continue
+		}
+
+		orphan := &OrphanWorker{
+			Endpoint:      endpoint,
+			WorkerID:      w.WorkerID,
+			PodName:       w.PodName,
+			Status:        w.Status,
+			LastHeartbeat: w.LastHeartbeat,
+		}
+		report.OrphanWorkers = append(report.OrphanWorkers, orphan)
+		if dryRun {
+			continue
+		}
+
+		// Same close-out as the pod delete watcher; the orphaned task cleanup reclaims its tasks
+		if s.workerEventService != nil {
+			s.workerEventService.RecordWorkerOffline(ctx, w.WorkerID, endpoint, w.PodName)
+		}
+		if err := s.workerRepo.MarkOfflineByPodName(ctx, w.PodName); err != nil {
+			logger.ErrorCtx(ctx, "worker integrity: failed to close out worker %s (pod %s): %v", w.WorkerID, w.PodName, err)
+			continue
+		}
+		orphan.ClosedOut = true
+		logger.InfoCtx(ctx, "worker integrity: closed out worker %s of endpoint %s, pod %s no longer exists", w.WorkerID, endpoint, w.PodName)
+	}
+
+	for _, pod := range pods {
+		if pod == nil || pod.DeletionTimestamp != "" {
+			continue
+		}
+		if _, ok := registered[pod.Name]; ok {
+			continue
+		}
+		if created, err := time.Parse(time.RFC3339, pod.CreatedAt); err == nil && now.Sub(created) < s.grace {
+			continue
+		}
+		report.UnregisteredPods = append(report.UnregisteredPods, &UnregisteredPod{
+			Endpoint:  endpoint,
+			PodName:   pod.Name,
+			Phase:     pod.Phase,
+			Status:    pod.Status,
+			CreatedAt: pod.CreatedAt,
+		})
+	}
+}
+
+// record updates the counters from a finished pass
+func (s *WorkerIntegrityService) record(report *WorkerIntegrityReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkedAt := report.CheckedAt
+	s.stats.Passes++
+	s.stats.LastPassAt = &checkedAt
+	s.stats.OrphanWorkers = len(report.OrphanWorkers)
+	s.stats.UnregisteredPods = len(report.UnregisteredPods)
+	s.stats.FailedEndpoints = len(report.Failed)
+	for _, orphan := range report.OrphanWorkers {
+		if orphan.ClosedOut {
+			s.stats.ClosedOutTotal++
+		}
+	}
+}
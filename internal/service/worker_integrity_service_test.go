@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"waverless/pkg/interfaces"
+	mysqlModel "waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIntegrityWorkers struct {
+	workers []*mysqlModel.Worker
+	closed  []string
+}
+
+func (f *fakeIntegrityWorkers) GetAll(ctx context.Context) ([]*mysqlModel.Worker, error) {
+	return f.workers, nil
+}
+
+func (f *fakeIntegrityWorkers) MarkOfflineByPodName(ctx context.Context, podName string) error {
+	f.closed = append(f.closed, podName)
+	return nil
+}
+
+type fakeIntegrityEndpoints struct {
+	endpoints []*mysqlModel.Endpoint
+}
+
+func (f *fakeIntegrityEndpoints) List(ctx context.Context) ([]*mysqlModel.Endpoint, error) {
+	return f.endpoints, nil
+}
+
+// fakePodProvider serves GetPods only; other provider methods are not used by the check
+type fakePodProvider struct {
+	interfaces.DeploymentProvider
+	pods map[string][]*interfaces.PodInfo
+	errs map[string]error
+}
+
+func (f *fakePodProvider) GetPods(ctx context.Context, endpoint string) ([]*interfaces.PodInfo, error) {
+	if err := f.errs[endpoint]; err != nil {
+		return nil, err
+	}
+	return f.pods[endpoint], nil
+}
+
+var integrityNow = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func integrityRow(endpoint, pod string, age, heartbeatAge time.Duration) *mysqlModel.Worker {
+	return &mysqlModel.Worker{
+		WorkerID:      "w-" + pod,
+		Endpoint:      endpoint,
+		PodName:       pod,
+		Status:        "ONLINE",
+		CreatedAt:     integrityNow.Add(-age),
+		LastHeartbeat: integrityNow.Add(-heartbeatAge),
+	}
+}
+
+func integrityPod(name string, age time.Duration) *interfaces.PodInfo {
+	return &interfaces.PodInfo{Name: name, Phase: "Running", Status: "Running", CreatedAt: integrityNow.Add(-age).Format(time.RFC3339)}
+}
+
+func newTestIntegrityService(workers *fakeIntegrityWorkers, endpoints []string, provider *fakePodProvider) *WorkerIntegrityService {
+	eps := &fakeIntegrityEndpoints{}
+	for _, name := range endpoints {
+		eps.endpoints = append(eps.endpoints, &mysqlModel.Endpoint{Endpoint: name})
+	}
+	return &WorkerIntegrityService{
+		workerRepo:       workers,
+		endpointRepo:     eps,
+		provider:         provider,
+		grace:            5 * time.Minute,
+		heartbeatTimeout: time.Minute,
+		now:              func() time.Time { return integrityNow },
+	}
+}
+
+func orphanPods(report *WorkerIntegrityReport) []string {
+	pods := []string{}
+	for _, o := range report.OrphanWorkers {
+		pods = append(pods, o.PodName)
+	}
+	return pods
+}
+
+func unregisteredPods(report *WorkerIntegrityReport) []string {
+	pods := []string{}
+	for _, p := range report.UnregisteredPods {
+		pods = append(pods, p.PodName)
+	}
+	return pods
+}
+
+func TestWorkerIntegrity_OrphanRows(t *testing.T) {
+	workers := &fakeIntegrityWorkers{workers: []*mysqlModel.Worker{
+		integrityRow("flux", "pod-live", time.Hour, time.Hour),         // Pod exists
+		integrityRow("flux", "pod-gone", time.Hour, time.Hour),         // Orphan
+		integrityRow("flux", "pod-young", 2*time.Minute, time.Hour),    // Row within the grace window
+		integrityRow("flux", "pod-beating", time.Hour, 10*time.Second), // Fresh heartbeat
+		integrityRow("flux", "", time.Hour, time.Hour),                 // No pod name, cannot be matched
+		integrityRow("flux", "pod-at-grace", 5*time.Minute, time.Hour), // Grace window just over
+		integrityRow("flux", "pod-stale-beat", time.Hour, time.Minute), // Heartbeat timed out
+	}}
+	provider := &fakePodProvider{pods: map[string][]*interfaces.PodInfo{"flux": {integrityPod("pod-live", time.Hour)}}}
+	svc := newTestIntegrityService(workers, []string{"flux"}, provider)
+
+	report, err := svc.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod-gone", "pod-at-grace", "pod-stale-beat"}, orphanPods(report))
+	assert.Empty(t, unregisteredPods(report))
+	assert.Equal(t, 1, report.Endpoints)
+	assert.Equal(t, 7, report.Workers)
+	assert.Equal(t, 1, report.Pods)
+}
+
+func TestWorkerIntegrity_UnregisteredPods(t *testing.T) {
+	workers := &fakeIntegrityWorkers{workers: []*mysqlModel.Worker{integrityRow("flux", "pod-registered", time.Hour, 0)}}
+	deleting := integrityPod("pod-deleting", time.Hour)
+	deleting.DeletionTimestamp = integrityNow.Format(time.RFC3339)
+	unparsable := integrityPod("pod-no-created", time.Hour)
+	unparsable.CreatedAt = ""
+	provider := &fakePodProvider{pods: map[string][]*interfaces.PodInfo{"flux": {
+		integrityPod("pod-registered", time.Hour),
+		integrityPod("pod-unknown", time.Hour),  // Reported
+		integrityPod("pod-new", 30*time.Second), // Pod within the grace window
+		deleting,                                // Being deleted
+		unparsable,                              // Age unknown, reported
+		nil,
+	}}}
+	svc := newTestIntegrityService(workers, []string{"flux"}, provider)
+
+	report, err := svc.Check(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, orphanPods(report))
+	assert.Equal(t, []string{"pod-unknown", "pod-no-created"}, unregisteredPods(report))
+}
+
+func TestWorkerIntegrity_DryRunAndReconcile(t *testing.T) {
+	workers := &fakeIntegrityWorkers{workers: []*mysqlModel.Worker{integrityRow("flux", "pod-gone", time.Hour, time.Hour)}}
+	provider := &fakePodProvider{pods: map[string][]*interfaces.PodInfo{}}
+	svc := newTestIntegrityService(workers, []string{"flux"}, provider)
+
+	report, err := svc.Check(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	require.Len(t, report.OrphanWorkers, 1)
+	assert.False(t, report.OrphanWorkers[0].ClosedOut)
+	assert.Empty(t, workers.closed)
+
+	report, err = svc.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	require.Len(t, report.OrphanWorkers, 1)
+	assert.True(t, report.OrphanWorkers[0].ClosedOut)
+	assert.Equal(t, []string{"pod-gone"}, workers.closed)
+
+	stats := svc.Stats()
+	assert.Equal(t, int64(2), stats.Passes)
+	assert.Equal(t, 1, stats.OrphanWorkers)
+	assert.Equal(t, int64(1), stats.ClosedOutTotal)
+}
+
+func TestWorkerIntegrity_DeletedEndpointsAndFailures(t *testing.T) {
+	workers := &fakeIntegrityWorkers{workers: []*mysqlModel.Worker{
+		integrityRow("deleted-ep", "pod-a", time.Hour, time.Hour), // Endpoint no longer in the endpoints table
+		integrityRow("broken", "pod-b", time.Hour, time.Hour),     // Pods cannot be listed
+	}}
+	provider := &fakePodProvider{
+		pods: map[string][]*interfaces.PodInfo{"flux": {integrityPod("pod-unknown", time.Hour)}},
+		errs: map[string]error{"broken": errors.New("provider unavailable")},
+	}
+	svc := newTestIntegrityService(workers, []string{"flux", "broken"}, provider)
+
+	report, err := svc.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod-a"}, orphanPods(report))
+	assert.Equal(t, "deleted-ep", report.OrphanWorkers[0].Endpoint)
+	assert.Equal(t, []string{"pod-a"}, workers.closed)
+	assert.Equal(t, []string{"broken"}, report.Failed)
+	assert.Equal(t, []string{"pod-unknown"}, unregisteredPods(report))
+	assert.Equal(t, 2, report.Endpoints)
+	assert.Equal(t, 1, svc.Stats().FailedEndpoints)
+}
+
+func TestWorkerIntegrity_NotConfigured(t *testing.T) {
+	svc := NewWorkerIntegrityService(nil, nil, nil, 0, 0)
+	_, err := svc.Check(context.Background())
+	assert.EqualError(t, err, "worker integrity check not configured")
+}
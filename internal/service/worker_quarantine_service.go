@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"waverless/internal/model"
+	"waverless/pkg/deploy/k8s"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
+)
+
+const (
+	// workerBansTTL is how long the bans are cached, i.e. how long a new ban takes to reach the
+	// workers started after it was created
+	workerBansTTL = 15 * time.Second
+	// quarantineDeletionCost keeps a quarantined pod when its deployment scales down
+	quarantineDeletionCost = 1000
+)
+
+// CreateWorkerBanRequest bans a node or an image digest from receiving new tasks
+type CreateWorkerBanRequest struct {
+	Kind   string `json:"kind" binding:"required"`  // node or image
+	Value  string `json:"value" binding:"required"` // Node name or image digest (sha256:...)
+	Reason string `json:"reason,omitempty"`
+}
+
+// WorkerBanDetail is a ban with the workers it currently quarantines
+type WorkerBanDetail struct {
+	*mysqlModel.WorkerBan
+	Workers []string `json:"workers"`
+}
+
+// WorkerQuarantineService quarantines workers during incident triage: a quarantined worker
+// finishes its running tasks but pulls no new ones, and its pod is kept for inspection. Bans
+// quarantine every worker on a node or running an image digest, including workers started later.
+type WorkerQuarantineService struct {
+	workerRepo     *mysql.WorkerRepository
+	banRepo        *mysql.WorkerBanRepository
+	deployProvider interfaces.DeploymentProvider // nil = quarantined pods are not protected from scale-down
+
+	mu           sync.Mutex
+	bans         []*mysqlModel.WorkerBan
+	bansLoadedAt time.Time
+}
+
+// NewWorkerQuarantineService creates a new worker quarantine service
+func NewWorkerQuarantineService(workerRepo *mysql.WorkerRepository, banRepo *mysql.WorkerBanRepository, deployProvider interfaces.DeploymentProvider) *WorkerQuarantineService {
+	return &WorkerQuarantineService{
+		workerRepo:     workerRepo,
+		banRepo:        banRepo,
+		deployProvider: deployProvider,
+	}
+}
+
+// Quarantine stops a worker from receiving new tasks
+func (s *WorkerQuarantineService) Quarantine(ctx context.Context, workerID, reason, by string) (*model.WorkerQuarantine, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("invalid quarantine: reason is required")
+	}
+	worker, err := s.getWorker(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.workerRepo.Quarantine(ctx, worker.WorkerID, reason, by, nil); err != nil {
+		return nil, fmt.Errorf("failed to quarantine worker %s: %w", workerID, err)
+	}
+	s.setDeletionCost(ctx, worker, quarantineDeletionCost)
+	return &model.WorkerQuarantine{Reason: reason, By: by, At: time.Now()}, nil
+}
+
+// Release lifts the manual quarantine of a worker. Workers quarantined by a ban are released by
+// deleting the ban.
+func (s *WorkerQuarantineService) Release(ctx context.Context, workerID string) error {
+	worker, err := s.getWorker(ctx, workerID)
+	if err != nil {
+		return err
+	}
+	if worker.QuarantinedAt == nil {
+		return nil
+	}
+	if worker.QuarantineBanID != nil {
+		return fmt.Errorf("invalid release: worker %s is quarantined by ban %d, delete the ban instead", workerID, *worker.QuarantineBanID)
+	}
+	if err := s.workerRepo.Release(ctx, worker.WorkerID); err != nil {
+		return fmt.Errorf("failed to release worker %s: %w", workerID, err)
+	}
+	s.setDeletionCost(ctx, worker, 0)
+	return nil
+}
+
+// CreateBan bans a node or image digest and quarantines the workers it matches
+func (s *WorkerQuarantineService) CreateBan(ctx context.Context, req *CreateWorkerBanRequest, by string) (*WorkerBanDetail, error) {
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	value := strings.TrimSpace(req.Value)
+	switch kind {
+	case mysqlModel.WorkerBanNode:
+	case mysqlModel.WorkerBanImage:
+		if !strings.HasPrefix(value, "sha256:") {
+			return nil, fmt.Errorf("invalid ban: image digest must start with sha256:")
+		}
+	default:
+		return nil, fmt.Errorf("invalid ban: kind must be %s or %s", mysqlModel.WorkerBanNode, mysqlModel.WorkerBanImage)
+	}
+	if value == "" {
+		return nil, fmt.Errorf("invalid ban: value is required")
+	}
+
+	existing, err := s.banRepo.GetByValue(ctx, kind, value)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%s %s is already banned (ban %d)", kind, value, existing.ID)
+	}
+
+	ban := &mysqlModel.WorkerBan{Kind: kind, Value: value, Reason: strings.TrimSpace(req.Reason), CreatedBy: by}
+	if err := s.banRepo.Create(ctx, ban); err != nil {
+		return nil, err
+	}
+	s.invalidateBans()
+
+	workers, err := s.workerRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	detail := &WorkerBanDetail{WorkerBan: ban, Workers: []string{}}
+	for _, worker := range workers {
+		if worker.QuarantinedAt != nil || !banMatches(ban, worker) {
+			continue
+		}
+		if err := s.quarantineByBan(ctx, worker, ban); err != nil {
+			logger.WarnCtx(ctx, "failed to quarantine worker %s for ban %d: %v", worker.WorkerID, ban.ID, err)
+			continue
+		}
+		detail.Workers = append(detail.Workers, worker.WorkerID)
+	}
+	return detail, nil
+}
+
+// ListBans returns every ban with the workers it currently quarantines, newest first
+func (s *WorkerQuarantineService) ListBans(ctx context.Context) ([]*WorkerBanDetail, error) {
+	bans, err := s.banRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workers, err := s.workerRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	return banDetails(bans, workers), nil
+}
+
+// ListBansPage returns a page of the bans with the workers they currently quarantine, newest
+// first, and the total number of bans
+func (s *WorkerQuarantineService) ListBansPage(ctx context.Context, limit, offset int) ([]*WorkerBanDetail, int64, error) {
+	total, err := s.banRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	bans, err := s.banRepo.ListPage(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]int64, 0, len(bans))
+	for _, ban := range bans {
+		ids = append(ids, ban.ID)
+	}
+	workers, err := s.workerRepo.GetByQuarantineBans(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list workers: %w", err)
+	}
+	return banDetails(bans, workers), total, nil
+}
+
+// banDetails pairs bans with the workers among workers that they quarantine
+func banDetails(bans []*mysqlModel.WorkerBan, workers []*mysqlModel.Worker) []*WorkerBanDetail {
+	byBan := make(map[int64][]string)
+	for _, worker := range workers {
+		if worker.QuarantineBanID != nil {
+			byBan[*worker.QuarantineBanID] = append(byBan[*worker.QuarantineBanID], worker.WorkerID)
+		}
+	}
+
+	details := make([]*WorkerBanDetail, 0, len(bans))
+	for _, ban := range bans {
+		detail := &WorkerBanDetail{WorkerBan: ban, Workers: byBan[ban.ID]}
+		if detail.Workers == nil {
+			detail.Workers = []string{}
+		}
+		details = append(details, detail)
+	}
+	return details
+}
+
+// DeleteBan lifts a ban and releases the workers it quarantined
+func (s *WorkerQuarantineService) DeleteBan(ctx context.Context, id int64) (*WorkerBanDetail, error) {
+	ban, err := s.banRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ban == nil {
+		return nil, fmt.Errorf("worker ban %d not found", id)
+	}
+	workers, err := s.workerRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	if err := s.banRepo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	s.invalidateBans()
+	if _, err := s.workerRepo.ReleaseByBan(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to release workers of ban %d: %w", id, err)
+	}
+
+	detail := &WorkerBanDetail{WorkerBan: ban, Workers: []string{}}
+	for _, worker := range workers {
+		if worker.QuarantineBanID != nil && *worker.QuarantineBanID == id {
+			s.setDeletionCost(ctx, worker, 0)
+			detail.Workers = append(detail.Workers, worker.WorkerID)
+		}
+	}
+	return detail, nil
+}
+
+// Blocked reports whether a worker may not pull new tasks. A worker matching a ban is
+// quarantined by it on the way.
+func (s *WorkerQuarantineService) Blocked(ctx context.Context, worker *mysqlModel.Worker) bool {
+	if worker.QuarantinedAt != nil {
+		logger.DebugCtx(ctx, "worker %s is quarantined, not pulling new tasks: %s", worker.WorkerID, worker.QuarantineReason)
+		return true
+	}
+	for _, ban := range s.loadBans(ctx) {
+		if !banMatches(ban, worker) {
+			continue
+		}
+		if err := s.quarantineByBan(ctx, worker, ban); err != nil {
+			logger.WarnCtx(ctx, "failed to quarantine worker %s for ban %d: %v", worker.WorkerID, ban.ID, err)
+		}
+		return true
+	}
+	return false
+}
+
+// quarantineByBan quarantines a worker matched by a ban
+func (s *WorkerQuarantineService) quarantineByBan(ctx context.Context, worker *mysqlModel.Worker, ban *mysqlModel.WorkerBan) error {
+	reason := fmt.Sprintf("%s %s is banned", ban.Kind, ban.Value)
+	if ban.Reason != "" {
+		reason += ": " + ban.Reason
+	}
+	if err := s.workerRepo.Quarantine(ctx, worker.WorkerID, reason, ban.CreatedBy, &ban.ID); err != nil {
+		return err
+	}
+	logger.InfoCtx(ctx, "⛔ Worker %s quarantined by ban %d (%s)", worker.WorkerID, ban.ID, reason)
+	s.setDeletionCost(ctx, worker, quarantineDeletionCost)
+	return nil
+}
+
+// banMatches reports whether a ban applies to a worker
+func banMatches(ban *mysqlModel.WorkerBan, worker *mysqlModel.Worker) bool {
+	switch ban.Kind {
+	case mysqlModel.WorkerBanNode:
+		return worker.NodeName() == ban.Value
+	case mysqlModel.WorkerBanImage:
+		return worker.ImageDigest() == ban.Value
+	}
+	return false
+}
+
+// loadBans returns the cached bans, reloading them once they are older than workerBansTTL.
+// The stale bans are kept when reloading fails.
+func (s *WorkerQuarantineService) loadBans(ctx context.Context) []*mysqlModel.WorkerBan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.bansLoadedAt.IsZero() && time.Since(s.bansLoadedAt) < workerBansTTL {
+		return s.bans
+	}
+	bans, err := s.banRepo.List(ctx)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load worker bans: %v", err)
+		return s.bans
+	}
+	s.bans = bans
+	s.bansLoadedAt = time.Now()
+	return s.bans
+}
+
+func (s *WorkerQuarantineService) invalidateBans() {
+	s.mu.Lock()
+	s.bansLoadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// getWorker returns a worker by worker ID
+func (s *WorkerQuarantineService) getWorker(ctx context.Context, workerID string) (*mysqlModel.Worker, error) {
+	worker, err := s.workerRepo.Get(ctx, workerID)
+	if err != nil || worker == nil {
+		return nil, fmt.Errorf("worker %s not found", workerID)
+	}
+	return worker, nil
+}
+
+// setDeletionCost protects a quarantined pod from scale-down (cost 1000) or lifts the protection
+// (cost 0). Only K8s endpoints have a deletion cost.
+func (s *WorkerQuarantineService) setDeletionCost(ctx context.Context, worker *mysqlModel.Worker, cost int) {
+	k8sProvider, ok := interfaces.ResolveProvider(ctx, s.deployProvider, worker.Endpoint, "").(*k8s.K8sDeploymentProvider)
+	if !ok || k8sProvider == nil || worker.PodName == "" {
+		return
+	}
+	if err := k8sProvider.SetPodDeletionCost(ctx, worker.PodName, cost); err != nil {
+		logger.WarnCtx(ctx, "failed to set deletion cost of pod %s: %v", worker.PodName, err)
+	}
+}
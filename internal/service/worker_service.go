@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
+	"sync"
 	"time"
 
 	"waverless/internal/model"
@@ -11,6 +14,8 @@ import (
 	"waverless/pkg/constants"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/longpoll"
+	"waverless/pkg/scheduler"
 	"waverless/pkg/store/mysql"
 	mysqlModel "waverless/pkg/store/mysql/model"
 )
@@ -22,15 +27,59 @@ type WorkerService struct {
 	taskService        *TaskService
 	workerEventService *WorkerEventService
 	deployProvider     interfaces.DeploymentProvider
+	taskQueue          taskQueue                // taskRepo as used for assignment
+	endpointRepo       dispatchPauseChecker     // nil = dispatch is never paused
+	policies           *scheduler.Policies      // nil = plain FIFO
+	groupRepo          stealSiblingLister       // nil = no work stealing
+	broadcastService   *WorkerBroadcastService  // nil = no broadcasts in heartbeat responses
+	quarantineService  *WorkerQuarantineService // nil = no quarantine or bans
+
+	stealMu       sync.Mutex
+	stealSiblings map[string]stealSiblings // By endpoint
+
+	longPoll        *longpoll.Hub // nil = pulls never wait
+	longPollMaxWait time.Duration
+	longPollRecheck time.Duration
+}
+
+// taskQueue is the part of TaskRepository pulls assign tasks with
+type taskQueue interface {
+	CountPendingByEndpoints(ctx context.Context, endpoints []string) (map[string]int64, error)
+	SelectAndAssignTasks(ctx context.Context, endpoint string, limit int, workerID string) ([]*mysql.Task, error)
+	SelectAndAssignTasksWith(ctx context.Context, endpoint string, window int, workerID string, choose mysql.TaskChooser) ([]*mysql.Task, error)
+}
+
+// dispatchPauseChecker is the part of EndpointRepository that tells whether dispatch is paused
+type dispatchPauseChecker interface {
+	IsDispatchPaused(ctx context.Context, endpointName string) (bool, error)
+}
+
+// stealSiblingLister is the part of EndpointGroupRepository work stealing uses
+type stealSiblingLister interface {
+	ListStealSiblings(ctx context.Context, endpoint string) ([]string, error)
+}
+
+// stealSiblingsTTL is how long the steal siblings of an endpoint are cached, i.e. how long a
+// group or image change takes to apply to work stealing
+const stealSiblingsTTL = 15 * time.Second
+
+// stealSiblings are the endpoints an endpoint's idle workers may take tasks from
+type stealSiblings struct {
+	endpoints []string
+	loadedAt  time.Time
 }
 
 // NewWorkerService creates a new Worker service
 func NewWorkerService(workerRepo *mysql.WorkerRepository, taskRepo *mysql.TaskRepository, deployProvider interfaces.DeploymentProvider) *WorkerService {
-	return &WorkerService{
+	s := &WorkerService{
 		workerRepo:     workerRepo,
 		taskRepo:       taskRepo,
 		deployProvider: deployProvider,
 	}
+	if taskRepo != nil {
+		s.taskQueue = taskRepo
+	}
+	return s
 }
 
 // SetWorkerEventService sets the worker event service
@@ -38,13 +87,57 @@ func (s *WorkerService) SetWorkerEventService(svc *WorkerEventService) {
 	s.workerEventService = svc
 }
 
+// SetLongPoll lets pulls wait up to maxWait for a task, re-checking the queue every recheck
+// in case a signal was lost
+func (s *WorkerService) SetLongPoll(hub *longpoll.Hub, maxWait, recheck time.Duration) {
+	s.longPoll = hub
+	s.longPollMaxWait = maxWait
+	s.longPollRecheck = recheck
+}
+
+// SetEndpointRepository enables the per-endpoint dispatch pause (for dependency injection)
+func (s *WorkerService) SetEndpointRepository(repo *mysql.EndpointRepository) {
+	if repo != nil {
+		s.endpointRepo = repo
+	}
+}
+
+// SetSchedulerPolicies sets the task assignment policies of the endpoints (for dependency injection)
+func (s *WorkerService) SetSchedulerPolicies(policies *scheduler.Policies) {
+	s.policies = policies
+}
+
+// SchedulerPolicies returns the task assignment policies, nil when not set
+func (s *WorkerService) SchedulerPolicies() *scheduler.Policies {
+	return s.policies
+}
+
+// SetEndpointGroupRepository enables work stealing within endpoint groups (for dependency injection)
+func (s *WorkerService) SetEndpointGroupRepository(repo *mysql.EndpointGroupRepository) {
+	if repo != nil {
+		s.groupRepo = repo
+	}
+}
+
+// SetBroadcastService delivers endpoint broadcasts through the heartbeat (for dependency injection)
+func (s *WorkerService) SetBroadcastService(svc *WorkerBroadcastService) {
+	s.broadcastService = svc
+}
+
+// SetQuarantineService stops quarantined and banned workers from pulling tasks (for dependency injection)
+func (s *WorkerService) SetQuarantineService(svc *WorkerQuarantineService) {
+	s.quarantineService = svc
+}
+
 // SetTaskService sets the task service (for circular dependency resolution)
 func (s *WorkerService) SetTaskService(taskService *TaskService) {
 	s.taskService = taskService
 }
 
-// HandleHeartbeat handles heartbeat requests
-func (s *WorkerService) HandleHeartbeat(ctx context.Context, req *model.HeartbeatRequest, endpoint string) error {
+// HandleHeartbeat handles heartbeat requests.
+// Workers that announced the cancellation capability get their cancelled jobs back in the response,
+// workers that announced the broadcast capability their unacknowledged broadcasts.
+func (s *WorkerService) HandleHeartbeat(ctx context.Context, req *model.HeartbeatRequest, endpoint string) (*model.HeartbeatResponse, error) {
 	if endpoint == "" {
 		endpoint = "default"
 	}
@@ -62,7 +155,30 @@ func (s *WorkerService) HandleHeartbeat(ctx context.Context, req *model.Heartbea
 
 	// Update heartbeat in MySQL
 	if err := s.workerRepo.UpdateHeartbeat(ctx, req.WorkerID, endpoint, req.JobsInProgress, len(req.JobsInProgress), req.Version); err != nil {
-		return fmt.Errorf("failed to update heartbeat: %w", err)
+		return nil, fmt.Errorf("failed to update heartbeat: %w", err)
+	}
+
+	// Store announced capabilities (only when declared, so legacy pings don't clear them)
+	capabilities := existingWorker.CapabilityList()
+	if req.Capabilities != nil && (capabilities == nil || !slices.Equal(req.Capabilities, capabilities)) {
+		if err := s.workerRepo.UpdateCapabilities(ctx, req.WorkerID, req.Capabilities); err != nil {
+			logger.WarnCtx(ctx, "failed to update worker capabilities, worker_id: %s, error: %v", req.WorkerID, err)
+		} else {
+			logger.InfoCtx(ctx, "worker capabilities updated, worker_id: %s, capabilities: %v", req.WorkerID, req.Capabilities)
+		}
+		capabilities = req.Capabilities
+	}
+
+	// Store the custom autoscaling gauge (only when reported)
+	if req.CustomMetric != nil {
+		if err := s.workerRepo.UpdateCustomMetric(ctx, req.WorkerID, *req.CustomMetric); err != nil {
+			logger.WarnCtx(ctx, "failed to update worker custom metric, worker_id: %s, error: %v", req.WorkerID, err)
+		}
+	}
+
+	// A failed GPU health check drains the worker; the resource releaser replaces its pod
+	if req.GPUHealth != nil && !req.GPUHealth.Healthy {
+		s.recordGPUUnhealthy(ctx, req.WorkerID, existingWorker, req.GPUHealth.Reason)
 	}
 
 	// Record WORKER_REGISTERED event when worker transitions from STARTING to ONLINE
@@ -83,7 +199,68 @@ func (s *WorkerService) HandleHeartbeat(ctx context.Context, req *model.Heartbea
 	logger.DebugCtx(ctx, "heartbeat received, worker_id: %s, endpoint: %s, jobs_count: %d, version: %s",
 		req.WorkerID, endpoint, currentJobs, req.Version)
 
-	return nil
+	resp := &model.HeartbeatResponse{Status: "ok"}
+
+	// Only workers that can stop a running job are told about cancellations; tasks whose hedge
+	// already reported the result are cancelled on the worker too
+	if model.HasWorkerCapability(capabilities, model.WorkerCapabilityCancellation) && len(req.JobsInProgress) > 0 {
+		cancelled, err := s.taskRepo.FilterTaskIDsByStatus(ctx, req.JobsInProgress, string(model.TaskStatusCancelled))
+		if err == nil {
+			var losers []string
+			losers, err = s.taskRepo.FilterHedgeLosers(ctx, req.JobsInProgress, req.WorkerID)
+			cancelled = append(cancelled, losers...)
+		}
+		if err != nil {
+			logger.WarnCtx(ctx, "failed to check cancelled jobs, worker_id: %s, error: %v", req.WorkerID, err)
+		} else if len(cancelled) > 0 {
+			logger.InfoCtx(ctx, "sending cancellation to worker, worker_id: %s, task_ids: %v", req.WorkerID, cancelled)
+			resp.Cancel = cancelled
+		}
+	}
+
+	// Acknowledgements are recorded even if the worker no longer announces the capability
+	if s.broadcastService != nil {
+		if len(req.BroadcastAcks) > 0 {
+			s.broadcastService.Acknowledge(ctx, req.WorkerID, req.BroadcastAcks)
+		}
+		if model.HasWorkerCapability(capabilities, model.WorkerCapabilityBroadcast) {
+			resp.Broadcasts = s.broadcastService.Pending(ctx, req.WorkerID)
+		}
+	}
+
+	return resp, nil
+}
+
+// maxGPUHealthReasonLength keeps worker-reported GPU health reasons within the failure_reason column
+const maxGPUHealthReasonLength = 400
+
+// recordGPUUnhealthy drains a worker whose GPU health check failed and records a GPU_UNHEALTHY
+// failure, once per worker
+func (s *WorkerService) recordGPUUnhealthy(ctx context.Context, workerID string, worker *mysqlModel.Worker, reason string) {
+	if worker != nil && worker.FailureType == string(interfaces.FailureTypeGPUUnhealthy) {
+		return
+	}
+	if reason == "" {
+		reason = "unspecified"
+	}
+	if len(reason) > maxGPUHealthReasonLength {
+		reason = reason[:maxGPUHealthReasonLength]
+	}
+	message := "GPU health check failed: " + reason
+
+	podName, node := workerID, ""
+	if worker != nil {
+		podName, node = worker.PodName, worker.NodeName()
+	}
+	logger.WarnCtx(ctx, "worker GPU unhealthy, draining, worker_id: %s, node: %s, reason: %s", workerID, node, reason)
+
+	if err := s.workerRepo.MarkDraining(ctx, workerID, message); err != nil {
+		logger.WarnCtx(ctx, "failed to drain GPU unhealthy worker, worker_id: %s, error: %v", workerID, err)
+	}
+	details, _ := json.Marshal(map[string]string{"source": "worker_gpu_health_check", "reason": reason, "node": node})
+	if err := s.workerRepo.UpdateWorkerFailure(ctx, podName, string(interfaces.FailureTypeGPUUnhealthy), message, string(details), time.Now()); err != nil {
+		logger.WarnCtx(ctx, "failed to record GPU unhealthy worker, worker_id: %s, error: %v", workerID, err)
+	}
 }
 
 // PullJobs pulls tasks (by endpoint)
@@ -120,12 +297,24 @@ func (s *WorkerService) PullJobs(ctx context.Context, req *model.JobPullRequest,
 		}
 	}
 
+	// Quarantined workers and workers on a banned node or image keep their pod but get no new tasks
+	if s.quarantineService != nil && s.quarantineService.Blocked(ctx, worker) {
+		return &model.JobPullResponse{Jobs: []model.JobInfo{}}, nil
+	}
+
 	// Calculate available slots
 	batchSize := req.BatchSize
 	if batchSize <= 0 {
 		batchSize = 1
 	}
 
+	// Workers that declared capabilities without batch support get one job per pull
+	if capabilities := worker.CapabilityList(); batchSize > 1 && capabilities != nil &&
+		!model.HasWorkerCapability(capabilities, model.WorkerCapabilityBatch) {
+		logger.DebugCtx(ctx, "worker did not announce batch capability, limiting pull to 1, worker_id: %s", req.WorkerID)
+		batchSize = 1
+	}
+
 	// concurrency := worker.Concurrency
 	// if concurrency <= 0 {
 	// 	concurrency = config.GlobalConfig.Worker.DefaultConcurrency
@@ -145,22 +334,45 @@ func (s *WorkerService) PullJobs(ctx context.Context, req *model.JobPullRequest,
 	// 	batchSize = availableSlots
 	// }
 
-	// Calculate idle duration before pulling tasks (if worker was idle)
-	var idleDurationMs int64
-	if worker.LastTaskTime != nil && len(req.JobsInProgress) == 0 {
-		idleDurationMs = time.Since(*worker.LastTaskTime).Milliseconds()
+	// Long-polling pulls subscribe before the first attempt so a task queued in between is not missed
+	var signals <-chan struct{}
+	wait := s.longPollWait(req.WaitSeconds)
+	if wait > 0 {
+		ch, cancel := s.longPoll.Subscribe(endpoint)
+		defer cancel()
+		signals = ch
 	}
 
-	// Select and assign tasks atomically in one transaction
-	assignedTasks, err := s.taskRepo.SelectAndAssignTasks(ctx, endpoint, batchSize, req.WorkerID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to select and assign tasks: %w", err)
+	jobsInProgress := len(req.JobsInProgress)
+	if jobsInProgress == 0 {
+		jobsInProgress = req.JobsInProgressCount
+	}
+	puller := scheduler.Worker{ID: req.WorkerID, JobsInProgress: jobsInProgress}
+
+	// Select and assign tasks atomically in one transaction; a paused endpoint's queue is
+	// left alone and long-polling pulls wait for the resume like for a new task
+	var assignedTasks []*mysql.Task
+	if !s.dispatchPaused(ctx, endpoint) {
+		if assignedTasks, err = s.takeTasks(ctx, endpoint, batchSize, puller); err != nil {
+			return nil, fmt.Errorf("failed to select and assign tasks: %w", err)
+		}
+	}
+	if len(assignedTasks) == 0 && signals != nil {
+		if assignedTasks, err = s.waitForTasks(ctx, endpoint, batchSize, puller, wait, signals); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(assignedTasks) == 0 {
 		return &model.JobPullResponse{Jobs: []model.JobInfo{}}, nil
 	}
 
+	// Calculate idle duration up to the pull that found tasks (if worker was idle)
+	var idleDurationMs int64
+	if worker.LastTaskTime != nil && len(req.JobsInProgress) == 0 {
+		idleDurationMs = time.Since(*worker.LastTaskTime).Milliseconds()
+	}
+
 	// Record WORKER_TASK_PULLED event (once per pull, with first task ID)
 	if s.workerEventService != nil && idleDurationMs > 0 {
 		s.workerEventService.RecordWorkerTaskPulled(ctx, req.WorkerID, endpoint, worker.PodName, assignedTasks[0].TaskID, idleDurationMs)
@@ -181,16 +393,223 @@ func (s *WorkerService) PullJobs(ctx context.Context, req *model.JobPullRequest,
 		})
 	}
 
-	// Batch update statistics (once for all tasks, not per task)
+	// Batch update statistics (once per endpoint, not per task); stolen tasks count for the
+	// endpoint they were submitted to
 	if s.taskService != nil && s.taskService.statisticsService != nil && len(assignedTasks) > 0 {
-		go s.taskService.statisticsService.UpdateStatisticsOnTaskStatusChangeBatch(
-			context.Background(), endpoint, "PENDING", "IN_PROGRESS", len(assignedTasks))
+		for taskEndpoint, count := range countByStatisticsEndpoint(assignedTasks) {
+			go s.taskService.statisticsService.UpdateStatisticsOnTaskStatusChangeBatch(
+				context.Background(), taskEndpoint, "PENDING", "IN_PROGRESS", count)
+		}
 	}
 
 	logger.InfoCtx(ctx, "jobs pulled, worker_id: %s, endpoint: %s, count: %d, task_ids: %v", req.WorkerID, endpoint, len(jobs), getTaskIDs(assignedTasks))
 	return &model.JobPullResponse{Jobs: jobs}, nil
 }
 
+// countByStatisticsEndpoint counts the tasks that count in statistics by the endpoint they
+// were submitted to, which for stolen tasks is not the endpoint of the worker
+func countByStatisticsEndpoint(tasks []*mysql.Task) map[string]int {
+	byEndpoint := make(map[string]int)
+	for _, t := range tasks {
+		if countsInStatistics(t) {
+			byEndpoint[t.Endpoint]++
+		}
+	}
+	return byEndpoint
+}
+
+// longPollWait returns how long a pull may wait for a task, capped by the configured maximum
+func (s *WorkerService) longPollWait(waitSeconds int) time.Duration {
+	if s.longPoll == nil || waitSeconds <= 0 {
+		return 0
+	}
+	wait := time.Duration(waitSeconds) * time.Second
+	if s.longPollMaxWait > 0 && wait > s.longPollMaxWait {
+		wait = s.longPollMaxWait
+	}
+	return wait
+}
+
+// waitForTasks holds an empty pull until a task signal arrives for the endpoint, the queue
+// re-check finds a task, the wait ends or the worker disconnects
+func (s *WorkerService) waitForTasks(ctx context.Context, endpoint string, batchSize int, worker scheduler.Worker, wait time.Duration, signals <-chan struct{}) ([]*mysql.Task, error) {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	recheck := s.longPollRecheck
+	if recheck <= 0 {
+		recheck = 5 * time.Second
+	}
+	ticker := time.NewTicker(recheck)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-deadline.C:
+			return nil, nil
+		case <-signals:
+		case <-ticker.C:
+		}
+
+		if s.dispatchPaused(ctx, endpoint) {
+			continue
+		}
+		tasks, err := s.takeTasks(ctx, endpoint, batchSize, worker)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select and assign tasks: %w", err)
+		}
+		if len(tasks) > 0 {
+			return tasks, nil
+		}
+	}
+}
+
+// takeTasks assigns pending tasks of the worker's endpoint, or steals from a sibling endpoint
+// when there are none
+func (s *WorkerService) takeTasks(ctx context.Context, endpoint string, batchSize int, worker scheduler.Worker) ([]*mysql.Task, error) {
+	tasks, err := s.assignTasks(ctx, endpoint, batchSize, worker)
+	if err != nil || len(tasks) > 0 {
+		return tasks, err
+	}
+	return s.stealTasks(ctx, endpoint, batchSize, worker)
+}
+
+// stealTasks assigns tasks of the sibling with the longest queue to an idle worker. Siblings
+// are the members of the endpoint's work stealing group running the same image and spec;
+// paused siblings are skipped. Busy workers never steal so a sibling's own workers come first.
+func (s *WorkerService) stealTasks(ctx context.Context, endpoint string, batchSize int, worker scheduler.Worker) ([]*mysql.Task, error) {
+	if s.groupRepo == nil || worker.JobsInProgress > 0 {
+		return nil, nil
+	}
+	siblings := s.getStealSiblings(ctx, endpoint)
+	if len(siblings) == 0 {
+		return nil, nil
+	}
+	pending, err := s.taskQueue.CountPendingByEndpoints(ctx, siblings)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to count sibling queues for work stealing, endpoint: %s, error: %v", endpoint, err)
+		return nil, nil
+	}
+
+	victims := make([]string, 0, len(pending))
+	for sibling := range pending {
+		victims = append(victims, sibling)
+	}
+	sort.Slice(victims, func(i, j int) bool {
+		if pending[victims[i]] != pending[victims[j]] {
+			return pending[victims[i]] > pending[victims[j]]
+		}
+		return victims[i] < victims[j]
+	})
+	for _, victim := range victims {
+		if s.dispatchPaused(ctx, victim) {
+			continue
+		}
+		tasks, err := s.assignTasks(ctx, victim, batchSize, worker)
+		if err != nil {
+			return nil, err
+		}
+		if len(tasks) > 0 {
+			logger.InfoCtx(ctx, "tasks stolen, worker_id: %s, endpoint: %s, from: %s, count: %d, task_ids: %v",
+				worker.ID, endpoint, victim, len(tasks), getTaskIDs(tasks))
+			return tasks, nil
+		}
+	}
+	return nil, nil
+}
+
+// getStealSiblings returns the cached steal siblings of an endpoint. A failed load caches no
+// siblings so stealing stops until the next load instead of hammering the database.
+func (s *WorkerService) getStealSiblings(ctx context.Context, endpoint string) []string {
+	s.stealMu.Lock()
+	cached, ok := s.stealSiblings[endpoint]
+	s.stealMu.Unlock()
+	if ok && time.Since(cached.loadedAt) < stealSiblingsTTL {
+		return cached.endpoints
+	}
+
+	siblings, err := s.groupRepo.ListStealSiblings(ctx, endpoint)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load work stealing siblings, endpoint: %s, error: %v", endpoint, err)
+	}
+	s.stealMu.Lock()
+	if s.stealSiblings == nil {
+		s.stealSiblings = make(map[string]stealSiblings)
+	}
+	s.stealSiblings[endpoint] = stealSiblings{endpoints: siblings, loadedAt: time.Now()}
+	s.stealMu.Unlock()
+	return siblings
+}
+
+// assignTasks assigns pending tasks of an endpoint to a pulling worker as picked by the
+// endpoint's scheduler policy. With a shadow policy the window covers both policies; the
+// shadow picks are only recorded.
+func (s *WorkerService) assignTasks(ctx context.Context, endpoint string, batchSize int, worker scheduler.Worker) ([]*mysql.Task, error) {
+	if s.policies == nil {
+		return s.taskQueue.SelectAndAssignTasks(ctx, endpoint, batchSize, worker.ID)
+	}
+	policy, shadow := s.policies.For(endpoint), s.policies.ShadowFor(endpoint)
+	req := &scheduler.Request{
+		Endpoint:  endpoint,
+		Worker:    worker,
+		BatchSize: batchSize,
+		Now:       time.Now(),
+		LoadPeers: func() []scheduler.Worker { return s.schedulerPeers(ctx, endpoint) },
+	}
+	window := scheduler.Window(policy, req)
+	policyWindow := window
+	if shadow != nil {
+		window = max(window, scheduler.Window(shadow, req))
+	}
+
+	return s.taskQueue.SelectAndAssignTasksWith(ctx, endpoint, window, worker.ID, func(candidates []*mysql.Task) []int64 {
+		all := make([]scheduler.Task, 0, len(candidates))
+		for _, t := range candidates {
+			all = append(all, scheduler.Task{ID: t.ID, TaskID: t.TaskID, CreatedAt: t.CreatedAt, Tenant: t.SubjectHash})
+		}
+		req.Candidates = all[:min(policyWindow, len(all))]
+		picked := policy.Select(req)
+		if shadow != nil {
+			shadowReq := *req
+			shadowReq.Candidates = all
+			s.policies.RecordShadow(&shadowReq, picked, shadow.Select(&shadowReq))
+		}
+		return picked
+	})
+}
+
+// schedulerPeers returns the workers of an endpoint that take tasks, nil when they cannot be
+// loaded
+func (s *WorkerService) schedulerPeers(ctx context.Context, endpoint string) []scheduler.Worker {
+	workers, err := s.workerRepo.GetByEndpoint(ctx, endpoint)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load workers for scheduling, endpoint: %s, error: %v", endpoint, err)
+		return nil
+	}
+	peers := make([]scheduler.Worker, 0, len(workers))
+	for _, w := range workers {
+		if w.Status == constants.WorkerStatusOnline.String() || w.Status == constants.WorkerStatusBusy.String() {
+			peers = append(peers, scheduler.Worker{ID: w.WorkerID, JobsInProgress: w.CurrentJobs})
+		}
+	}
+	return peers
+}
+
+// dispatchPaused reports whether task dispatch of an endpoint is paused. A failed check
+// dispatches as usual so a database hiccup does not stall every endpoint.
+func (s *WorkerService) dispatchPaused(ctx context.Context, endpoint string) bool {
+	if s.endpointRepo == nil {
+		return false
+	}
+	paused, err := s.endpointRepo.IsDispatchPaused(ctx, endpoint)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to check dispatch pause, endpoint: %s, error: %v", endpoint, err)
+		return false
+	}
+	return paused
+}
+
 // ListWorkers lists all workers (optionally filtered by endpoint)
 func (s *WorkerService) ListWorkers(ctx context.Context, endpoint string) ([]*model.Worker, error) {
 	var mysqlWorkers []*mysqlModel.Worker
@@ -222,6 +641,20 @@ func (s *WorkerService) ListWorkersWithPodInfo(ctx context.Context, endpoint str
 	return s.workerRepo.GetAll(ctx)
 }
 
+// ListEndpointWorkers returns a page of the active workers of an endpoint, oldest first, and
+// their total
+func (s *WorkerService) ListEndpointWorkers(ctx context.Context, endpoint string, limit, offset int) ([]*mysqlModel.Worker, int64, error) {
+	total, err := s.workerRepo.CountByEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, 0, err
+	}
+	workers, err := s.workerRepo.ListByEndpoint(ctx, endpoint, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return workers, total, nil
+}
+
 // ListWorkersForSync returns workers for Portal sync (includes recently terminated OFFLINE workers)
 func (s *WorkerService) ListWorkersForSync(ctx context.Context, endpoint string) ([]*mysqlModel.Worker, error) {
 	return s.workerRepo.GetByEndpointForSync(ctx, endpoint)
@@ -258,6 +691,11 @@ func (s *WorkerService) UpdateWorkerStatus(ctx context.Context, workerID string,
 	return s.workerRepo.UpdateStatus(ctx, workerID, string(status))
 }
 
+// DrainWorker marks a worker DRAINING with the reason shown in its status
+func (s *WorkerService) DrainWorker(ctx context.Context, workerID, reason string) error {
+	return s.workerRepo.MarkDraining(ctx, workerID, reason)
+}
+
 // CleanupOfflineWorkers cleans up offline Workers and reclaims their tasks
 func (s *WorkerService) CleanupOfflineWorkers(ctx context.Context) error {
 	timeout := time.Duration(config.GlobalConfig.Worker.HeartbeatTimeout) * time.Second
@@ -326,6 +764,11 @@ func (s *WorkerService) toDomainWorker(mw *mysqlModel.Worker) *model.Worker {
 		lastTaskTime = *mw.LastTaskTime
 	}
 
+	var customMetricAt time.Time
+	if mw.CustomMetricAt != nil {
+		customMetricAt = *mw.CustomMetricAt
+	}
+
 	return &model.Worker{
 		ID:             mw.WorkerID,
 		Endpoint:       mw.Endpoint,
@@ -338,6 +781,24 @@ func (s *WorkerService) toDomainWorker(mw *mysqlModel.Worker) *model.Worker {
 		Version:        mw.Version,
 		RegisteredAt:   mw.CreatedAt,
 		PodName:        mw.PodName,
+		Capabilities:   mw.CapabilityList(),
+		CustomMetric:   mw.CustomMetric,
+		CustomMetricAt: customMetricAt,
+		DrainReason:    mw.DrainReason,
+		Quarantine:     WorkerQuarantineOf(mw),
+	}
+}
+
+// WorkerQuarantineOf returns the quarantine of a worker, nil if it is not quarantined
+func WorkerQuarantineOf(mw *mysqlModel.Worker) *model.WorkerQuarantine {
+	if mw.QuarantinedAt == nil {
+		return nil
+	}
+	return &model.WorkerQuarantine{
+		Reason: mw.QuarantineReason,
+		By:     mw.QuarantinedBy,
+		At:     *mw.QuarantinedAt,
+		BanID:  mw.QuarantineBanID,
 	}
 }
 
@@ -382,7 +843,7 @@ func (s *WorkerService) reclaimWorkerTasks(ctx context.Context, worker *model.Wo
 			continue
 		}
 
-		if s.taskService != nil && s.taskService.statisticsService != nil {
+		if s.taskService != nil && s.taskService.statisticsService != nil && countsInStatistics(mysqlTask) {
 			go s.taskService.statisticsService.UpdateStatisticsOnTaskStatusChange(
 				context.Background(), mysqlTask.Endpoint, "IN_PROGRESS", "PENDING")
 		}
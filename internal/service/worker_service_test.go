@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"waverless/pkg/scheduler"
+	"waverless/pkg/store/mysql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTaskQueue keeps the pending tasks of each endpoint in submission order
+type fakeTaskQueue struct {
+	pending  map[string][]*mysql.Task
+	assigned []string // Endpoints tasks were assigned from, in order
+}
+
+func (f *fakeTaskQueue) CountPendingByEndpoints(ctx context.Context, endpoints []string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	for _, endpoint := range endpoints {
+		if n := len(f.pending[endpoint]); n > 0 {
+			counts[endpoint] = int64(n)
+		}
+	}
+	return counts, nil
+}
+
+func (f *fakeTaskQueue) SelectAndAssignTasks(ctx context.Context, endpoint string, limit int, workerID string) ([]*mysql.Task, error) {
+	queue := f.pending[endpoint]
+	n := min(limit, len(queue))
+	if n == 0 {
+		return nil, nil
+	}
+	tasks := queue[:n]
+	f.pending[endpoint] = queue[n:]
+	for _, t := range tasks {
+		t.WorkerID = workerID
+	}
+	f.assigned = append(f.assigned, endpoint)
+	return tasks, nil
+}
+
+func (f *fakeTaskQueue) SelectAndAssignTasksWith(ctx context.Context, endpoint string, window int, workerID string, choose mysql.TaskChooser) ([]*mysql.Task, error) {
+	return f.SelectAndAssignTasks(ctx, endpoint, window, workerID)
+}
+
+// fakePausedEndpoints pauses dispatch of the listed endpoints
+type fakePausedEndpoints map[string]bool
+
+func (f fakePausedEndpoints) IsDispatchPaused(ctx context.Context, endpointName string) (bool, error) {
+	return f[endpointName], nil
+}
+
+// fakeStealSiblings returns the configured siblings and counts the loads
+type fakeStealSiblings struct {
+	siblings map[string][]string
+	err      error
+	loads    int
+}
+
+func (f *fakeStealSiblings) ListStealSiblings(ctx context.Context, endpoint string) ([]string, error) {
+	f.loads++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.siblings[endpoint], nil
+}
+
+func queuedTasks(endpoint string, n int) []*mysql.Task {
+	tasks := make([]*mysql.Task, n)
+	for i := range tasks {
+		tasks[i] = &mysql.Task{TaskID: endpoint + "-task", Endpoint: endpoint, Status: "PENDING"}
+	}
+	return tasks
+}
+
+// newStealingWorkerService returns a worker service of flux-eu, whose group also has flux-us and flux-asia
+func newStealingWorkerService(queue *fakeTaskQueue, paused fakePausedEndpoints) (*WorkerService, *fakeStealSiblings) {
+	siblings := &fakeStealSiblings{siblings: map[string][]string{"flux-eu": {"flux-asia", "flux-us"}}}
+	return &WorkerService{taskQueue: queue, endpointRepo: paused, groupRepo: siblings}, siblings
+}
+
+func TestTakeTasks_StealsFromBusiestSibling(t *testing.T) {
+	queue := &fakeTaskQueue{pending: map[string][]*mysql.Task{
+		"flux-us":   queuedTasks("flux-us", 2),
+		"flux-asia": queuedTasks("flux-asia", 5),
+	}}
+	s, _ := newStealingWorkerService(queue, nil)
+
+	tasks, err := s.takeTasks(context.Background(), "flux-eu", 2, scheduler.Worker{ID: "worker-1"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "flux-asia", tasks[0].Endpoint)
+	assert.Equal(t, "worker-1", tasks[0].WorkerID)
+	assert.Equal(t, []string{"flux-asia"}, queue.assigned)
+}
+
+func TestTakeTasks_SkipsPausedSibling(t *testing.T) {
+	queue := &fakeTaskQueue{pending: map[string][]*mysql.Task{
+		"flux-us":   queuedTasks("flux-us", 2),
+		"flux-asia": queuedTasks("flux-asia", 5),
+	}}
+	s, _ := newStealingWorkerService(queue, fakePausedEndpoints{"flux-asia": true})
+
+	tasks, err := s.takeTasks(context.Background(), "flux-eu", 1, scheduler.Worker{ID: "worker-1"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "flux-us", tasks[0].Endpoint)
+	assert.Len(t, queue.pending["flux-asia"], 5)
+}
+
+func TestTakeTasks_OwnQueueFirst(t *testing.T) {
+	queue := &fakeTaskQueue{pending: map[string][]*mysql.Task{
+		"flux-eu":   queuedTasks("flux-eu", 1),
+		"flux-asia": queuedTasks("flux-asia", 5),
+	}}
+	s, siblings := newStealingWorkerService(queue, nil)
+
+	tasks, err := s.takeTasks(context.Background(), "flux-eu", 4, scheduler.Worker{ID: "worker-1"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "flux-eu", tasks[0].Endpoint)
+	assert.Equal(t, []string{"flux-eu"}, queue.assigned)
+	assert.Zero(t, siblings.loads)
+}
+
+func TestTakeTasks_BusyWorkerDoesNotSteal(t *testing.T) {
+	queue := &fakeTaskQueue{pending: map[string][]*mysql.Task{
+		"flux-asia": queuedTasks("flux-asia", 5),
+	}}
+	s, _ := newStealingWorkerService(queue, nil)
+
+	tasks, err := s.takeTasks(context.Background(), "flux-eu", 1, scheduler.Worker{ID: "worker-1", JobsInProgress: 1})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+	assert.Len(t, queue.pending["flux-asia"], 5)
+}
+
+func TestGetStealSiblings_Cached(t *testing.T) {
+	s, siblings := newStealingWorkerService(&fakeTaskQueue{}, nil)
+
+	assert.Equal(t, []string{"flux-asia", "flux-us"}, s.getStealSiblings(context.Background(), "flux-eu"))
+	assert.Equal(t, []string{"flux-asia", "flux-us"}, s.getStealSiblings(context.Background(), "flux-eu"))
+	assert.Equal(t, 1, siblings.loads)
+
+	// A failed load caches no siblings instead of retrying on every pull
+	siblings.err = errors.New("connection refused")
+	assert.Empty(t, s.getStealSiblings(context.Background(), "flux-us"))
+	assert.Empty(t, s.getStealSiblings(context.Background(), "flux-us"))
+	assert.Equal(t, 2, siblings.loads)
+}
+
+func TestStolenTasksCountForTheirEndpoint(t *testing.T) {
+	queue := &fakeTaskQueue{pending: map[string][]*mysql.Task{
+		"flux-asia": queuedTasks("flux-asia", 3),
+	}}
+	s, _ := newStealingWorkerService(queue, nil)
+	tasks, err := s.takeTasks(context.Background(), "flux-eu", 3, scheduler.Worker{ID: "worker-1"})
+	require.NoError(t, err)
+
+	counter := &fakeStatisticsCounter{counts: map[string]map[string]int{}}
+	stats := &StatisticsService{counter: counter}
+	for endpoint, count := range countByStatisticsEndpoint(tasks) {
+		stats.UpdateStatisticsOnTaskStatusChangeBatch(context.Background(), endpoint, "PENDING", "IN_PROGRESS", count)
+	}
+
+	assert.Equal(t, map[string]int{"PENDING": -3, "IN_PROGRESS": 3}, counter.counts["flux-asia"])
+	assert.NotContains(t, counter.counts, "flux-eu")
+}
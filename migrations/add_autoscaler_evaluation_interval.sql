@@ -0,0 +1,7 @@
+-- Migration: Add per-endpoint autoscaler evaluation interval
+-- Date: 2026-10-15
+-- Interactive endpoints can be evaluated every few seconds (minimum 2) while batch endpoints
+-- keep a slower cadence than the global autoscaler interval. 0 = the global interval.
+
+ALTER TABLE `autoscaler_configs`
+  ADD COLUMN `evaluation_interval` int NOT NULL DEFAULT '0' COMMENT 'Seconds between autoscaler evaluations, 0 = global interval' AFTER `group_name`;
@@ -0,0 +1,8 @@
+-- Migration: Add per-endpoint autoscaler metric sources
+-- Date: 2026-10-16
+-- HPA-style scaling on GPU utilization, request latency or a custom Prometheus query, each with
+-- a target value. The autoscaler takes the max of the queue-based target and every source's
+-- target. NULL = queue-based scaling only.
+
+ALTER TABLE `autoscaler_configs`
+  ADD COLUMN `metric_sources` json DEFAULT NULL COMMENT 'Metric sources with target values (JSON array), NULL = none' AFTER `profile`;
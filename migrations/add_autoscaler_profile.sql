@@ -0,0 +1,7 @@
+-- Migration: Add endpoint resource profile
+-- Date: 2026-10-15
+-- Records the preset ("latency", "throughput", "economy") the autoscaler, queue and dispatch
+-- settings were last expanded from. The settings themselves stay in their own columns.
+
+ALTER TABLE `autoscaler_configs`
+  ADD COLUMN `profile` varchar(20) NOT NULL DEFAULT '' COMMENT 'Resource profile the settings were expanded from, empty = none' AFTER `queue_wait_slo`;
@@ -0,0 +1,8 @@
+-- Migration: Add per-endpoint queue wait SLO
+-- Date: 2026-10-15
+-- When set, the autoscaler starts scale-ups ahead of a growing queue if new workers, at the
+-- spec's learned startup time, would be ready only after tasks waited longer than the SLO.
+-- 0 = no SLO.
+
+ALTER TABLE `autoscaler_configs`
+  ADD COLUMN `queue_wait_slo` int NOT NULL DEFAULT '0' COMMENT 'Longest queue wait in seconds before scale-ups start ahead of the queue, 0 = none' AFTER `evaluation_interval`;
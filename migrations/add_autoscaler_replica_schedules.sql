@@ -0,0 +1,7 @@
+-- Migration: Add per-endpoint replica schedules
+-- Date: 2026-10-16
+-- Cron-based time windows that override the min/max replicas of an endpoint, e.g. to pre-warm
+-- GPU capacity before a known traffic peak and shrink at night. NULL = no schedules.
+
+ALTER TABLE `autoscaler_configs`
+  ADD COLUMN `replica_schedules` json DEFAULT NULL COMMENT 'Replica schedules (JSON array of cron, duration, min/max replicas), NULL = none' AFTER `metric_sources`;
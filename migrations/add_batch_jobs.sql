@@ -0,0 +1,35 @@
+-- Migration: Add batch jobs
+-- Date: 2026-10-15
+-- A batch job is a one-off run of an image on a spec (fine-tuning, evaluation) that runs to
+-- completion once outside the endpoint/queue model. The row keeps the job's final state, a log
+-- tail and its GPU hours after the provider removes the job.
+
+CREATE TABLE IF NOT EXISTS `batch_jobs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(63) NOT NULL,
+  `spec_name` varchar(100) NOT NULL,
+  `image` varchar(500) NOT NULL,
+  `command` json DEFAULT NULL,
+  `args` json DEFAULT NULL,
+  `env` json DEFAULT NULL,
+  `volume_mounts` json DEFAULT NULL COMMENT 'Mount path -> PVC name',
+  `gpu_count` int NOT NULL DEFAULT 0,
+  `gpu_type` varchar(100) NOT NULL DEFAULT '',
+  `shm_size` varchar(20) NOT NULL DEFAULT '',
+  `timeout_seconds` int NOT NULL DEFAULT 0 COMMENT '0 = no limit',
+  `status` varchar(20) NOT NULL DEFAULT 'pending' COMMENT 'pending, running, succeeded, failed, cancelled',
+  `message` varchar(1024) NOT NULL DEFAULT '',
+  `exit_code` int DEFAULT NULL,
+  `pod_name` varchar(255) NOT NULL DEFAULT '',
+  `gpu_hours` decimal(12,4) NOT NULL DEFAULT 0,
+  `logs` mediumtext COMMENT 'Log tail kept after the job finishes',
+  `created_by` varchar(255) NOT NULL DEFAULT '' COMMENT 'X-Requested-By of the submitter',
+  `started_at` datetime(3) DEFAULT NULL,
+  `completed_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_name` (`name`),
+  KEY `idx_status` (`status`),
+  KEY `idx_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='One-off GPU jobs';
@@ -0,0 +1,24 @@
+-- Migration: Add change requests (second-approver workflow for protected endpoints)
+-- Date: 2026-10-15
+-- Mutating operations on endpoints with a protected label (default environment=prod) are
+-- stored here and executed only after another identity approves them. Rows are kept as the
+-- audit trail of who requested, reviewed and applied each change.
+
+CREATE TABLE IF NOT EXISTS `change_requests` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `operation` varchar(32) NOT NULL COMMENT 'deploy, update_deployment, update_config, delete',
+  `payload` json DEFAULT NULL COMMENT 'Original request body',
+  `status` varchar(16) NOT NULL COMMENT 'pending, approved, executed, failed, rejected, expired',
+  `requested_by` varchar(255) NOT NULL,
+  `reviewed_by` varchar(255) NOT NULL DEFAULT '',
+  `review_comment` varchar(500) NOT NULL DEFAULT '',
+  `error` varchar(1024) NOT NULL DEFAULT '' COMMENT 'Execution error',
+  `expires_at` datetime(3) NOT NULL,
+  `reviewed_at` datetime(3) DEFAULT NULL,
+  `executed_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_endpoint_created` (`endpoint`, `created_at`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Endpoint changes held for approval';
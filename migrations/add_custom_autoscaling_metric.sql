@@ -0,0 +1,13 @@
+-- Migration: Add custom autoscaling metric (worker-reported gauge)
+-- Date: 2026-10-15
+-- Workers may report a numeric gauge in heartbeats (custom_metric query parameter or
+-- X-Worker-Custom-Metric header). When custom_metric_target > 0 the autoscaler sizes the
+-- endpoint so the average gauge per worker stays at the target, in addition to queue depth.
+
+ALTER TABLE `workers`
+  ADD COLUMN `custom_metric` double NULL COMMENT 'Last custom autoscaling gauge reported by the worker' AFTER `capabilities`,
+  ADD COLUMN `custom_metric_at` datetime(3) NULL COMMENT 'Time of the last custom metric report' AFTER `custom_metric`;
+
+ALTER TABLE `autoscaler_configs`
+  ADD COLUMN `custom_metric_name` varchar(100) NULL COMMENT 'Display name of the custom metric, e.g. batch_queue' AFTER `autoscaler_enabled`,
+  ADD COLUMN `custom_metric_target` double NOT NULL DEFAULT 0 COMMENT 'Custom metric setpoint per worker, 0 = disabled' AFTER `custom_metric_name`;
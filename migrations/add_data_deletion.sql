@@ -0,0 +1,34 @@
+-- Migration: Add data deletion by subject
+-- Date: 2026-10-15
+-- Callers may tag tasks with a data subject key; only its SHA-256 is stored on the task
+-- (tasks.subject_hash) and on its samples. Deletion requests purge or anonymize the tasks of
+-- a subject and rewrite the sample objects recorded in sample_subjects; every request keeps
+-- its report in data_deletion_requests.
+
+ALTER TABLE tasks
+ADD COLUMN subject_hash VARCHAR(64) NOT NULL DEFAULT '' COMMENT 'SHA-256 of the data subject key' AFTER transform_version,
+ADD INDEX idx_subject_hash (subject_hash);
+
+CREATE TABLE IF NOT EXISTS `sample_subjects` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `subject_hash` varchar(64) NOT NULL,
+  `task_id` varchar(255) NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `object_key` varchar(1000) NOT NULL COMMENT 'Sample object holding the task sample',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_subject_hash` (`subject_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Sample objects holding samples of data subjects';
+
+CREATE TABLE IF NOT EXISTS `data_deletion_requests` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `subject_hash` varchar(64) NOT NULL,
+  `mode` varchar(20) NOT NULL COMMENT 'purge or anonymize',
+  `status` varchar(20) NOT NULL COMMENT 'completed, partial or failed',
+  `report` json DEFAULT NULL COMMENT 'Deletion report',
+  `requested_by` varchar(255) DEFAULT NULL COMMENT 'Identity from the X-Requested-By header',
+  `created_at` datetime(3) NOT NULL,
+  `completed_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_subject_hash` (`subject_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Data deletion requests and reports';
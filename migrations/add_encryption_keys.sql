@@ -0,0 +1,17 @@
+-- Migration: Add per-project data keys for task payload encryption at rest
+-- Date: 2026-10-15
+-- Task input/output of endpoints labeled with a project are sealed with AES-256-GCM under the
+-- project's newest data key. Data keys are only stored wrapped by the KMS (Vault transit or
+-- the control plane master key); rotating a project adds a version, older versions stay so
+-- existing payloads remain readable.
+
+CREATE TABLE IF NOT EXISTS `encryption_keys` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `project` varchar(255) NOT NULL,
+  `version` int NOT NULL,
+  `wrapped_key` text NOT NULL COMMENT 'Data key encrypted by the KMS',
+  `kms` varchar(50) NOT NULL COMMENT 'KMS that wrapped the key: local, vault-transit',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_project_version` (`project`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Wrapped per-project data keys';
@@ -0,0 +1,12 @@
+-- Migration: Add endpoint circuit breaker
+-- Date: 2026-10-15
+-- An endpoint failing nearly every task trips its circuit breaker: submissions get 503 and
+-- the autoscaler stops scaling it up. After a delay the breaker turns half-open and admits
+-- one probe task; a success closes it, a failure reopens it with a doubled delay.
+
+ALTER TABLE endpoints
+ADD COLUMN circuit_state VARCHAR(16) NOT NULL DEFAULT '' COMMENT 'open or half_open (empty = closed)' AFTER paused_at,
+ADD COLUMN circuit_reason VARCHAR(512) NOT NULL DEFAULT '' COMMENT 'Failure rate that tripped the breaker' AFTER circuit_state,
+ADD COLUMN circuit_since DATETIME(3) NULL COMMENT 'Last circuit state change' AFTER circuit_reason,
+ADD COLUMN circuit_probe_task VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Task admitted as probe while half-open' AFTER circuit_since,
+ADD COLUMN circuit_failed_probes INT NOT NULL DEFAULT 0 COMMENT 'Failed probes since the breaker tripped' AFTER circuit_probe_task;
@@ -0,0 +1,13 @@
+-- Migration: Add per-endpoint task dispatch pause
+-- Date: 2026-10-15
+-- POST /api/v1/endpoints/:name/pause stops handing an endpoint's tasks to workers without
+-- touching its replicas (e.g. to investigate a bad model version with workers kept alive).
+-- In queue mode submissions keep queueing and are dispatched after resume; in reject mode
+-- they are rejected with 503. The autoscaler holds the replica count while paused.
+
+ALTER TABLE endpoints
+ADD COLUMN dispatch_paused TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'Workers get no tasks while set' AFTER labels,
+ADD COLUMN pause_mode VARCHAR(16) NOT NULL DEFAULT '' COMMENT 'queue or reject' AFTER dispatch_paused,
+ADD COLUMN pause_reason VARCHAR(512) NOT NULL DEFAULT '' COMMENT 'Why dispatch was paused' AFTER pause_mode,
+ADD COLUMN paused_by VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Identity from the X-Requested-By header' AFTER pause_reason,
+ADD COLUMN paused_at DATETIME(3) DEFAULT NULL AFTER paused_by;
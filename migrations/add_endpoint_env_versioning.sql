@@ -0,0 +1,23 @@
+-- Migration: Add versioned endpoint env changes
+-- Date: 2026-10-15
+-- Env vars of an endpoint can be set and unset one by one through the env API. Every change
+-- must name the env version it was based on (endpoints.env_version), so concurrent editors
+-- get a conflict instead of overwriting each other. Secret env vars are only listed by name
+-- (endpoints.secret_env); their values live in the provider's secret store. Every version is
+-- recorded in endpoint_env_changes without secret values.
+
+ALTER TABLE endpoints
+ADD COLUMN env_version INT NOT NULL DEFAULT 0 COMMENT 'Bumped by every env change' AFTER env,
+ADD COLUMN secret_env JSON DEFAULT NULL COMMENT 'Names of secret env vars' AFTER env_version;
+
+CREATE TABLE IF NOT EXISTS `endpoint_env_changes` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `version` int NOT NULL COMMENT 'Env version after the change',
+  `source` varchar(32) NOT NULL COMMENT 'env_api or deployment',
+  `changes` json DEFAULT NULL COMMENT 'Added, removed and changed env vars (no secret values)',
+  `changed_by` varchar(255) NOT NULL DEFAULT '' COMMENT 'Identity from the X-Requested-By header',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_endpoint_version` (`endpoint`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Endpoint env change history';
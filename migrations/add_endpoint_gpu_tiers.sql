@@ -0,0 +1,19 @@
+-- Migration: Add GPU tiers of heterogeneous endpoints
+-- Date: 2026-10-15
+-- A heterogeneous endpoint routes each submitted task to the first tier endpoint (same image,
+-- cheaper spec) whose rules accept it, by input size and requested precision; tasks no tier
+-- accepts stay on the endpoint itself.
+
+CREATE TABLE IF NOT EXISTS `endpoint_gpu_tiers` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `tier_endpoint` varchar(255) NOT NULL,
+  `position` int NOT NULL DEFAULT '0' COMMENT 'Tiers are tried in ascending position',
+  `max_input_bytes` int NOT NULL DEFAULT '0' COMMENT 'Largest accepted input, 0 = any size',
+  `precisions` json DEFAULT NULL COMMENT 'Accepted requested precisions, empty = any',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_endpoint_tier` (`endpoint`, `tier_endpoint`),
+  KEY `idx_tier_endpoint` (`tier_endpoint`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='GPU tiers of heterogeneous endpoints';
@@ -0,0 +1,7 @@
+-- Migration: Add work stealing to endpoint groups
+-- Date: 2026-10-15
+-- With work_stealing enabled, idle workers of a member whose own queue is empty take tasks
+-- from the member with the longest queue that runs the same image and spec.
+
+ALTER TABLE `endpoint_groups`
+  ADD COLUMN `work_stealing` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'Idle workers take tasks of members with the same image and spec' AFTER `max_gpu_count`;
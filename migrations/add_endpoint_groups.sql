@@ -0,0 +1,21 @@
+-- Migration: Add endpoint groups (shared replica / GPU budget across endpoints)
+-- Date: 2026-10-15
+-- Endpoints join a group through autoscaler_configs.group_name. The autoscaler allocates
+-- replicas within a group by priority and demand without exceeding the group budget.
+
+CREATE TABLE IF NOT EXISTS `endpoint_groups` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `display_name` varchar(255) NOT NULL DEFAULT '',
+  `description` varchar(512) NOT NULL DEFAULT '',
+  `max_replicas` int NOT NULL DEFAULT '0' COMMENT 'Total replicas across all members, 0 = unlimited',
+  `max_gpu_count` int NOT NULL DEFAULT '0' COMMENT 'Total GPUs across all members, 0 = unlimited',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Endpoint groups sharing a capacity pool';
+
+ALTER TABLE `autoscaler_configs`
+  ADD COLUMN `group_name` varchar(255) NOT NULL DEFAULT '' COMMENT 'Endpoint group sharing the capacity pool, empty = none' AFTER `custom_metric_target`,
+  ADD KEY `idx_group_name` (`group_name`);
@@ -0,0 +1,15 @@
+-- Migration: Add machine-readable health reason code to endpoints
+-- Date: 2026-10-15
+-- health_message is kept for display only; logic (e.g. scale-up blocking) branches on health_reason
+
+ALTER TABLE `endpoints`
+  ADD COLUMN `health_reason` varchar(64) NOT NULL DEFAULT '' COMMENT 'Health reason code: IMAGE_PULL_FAILED, CONTAINER_CRASH' AFTER `health_status`;
+
+-- Backfill codes for endpoints that are currently unhealthy, derived from their failed workers
+UPDATE `endpoints` e
+SET e.`health_reason` = CASE
+  WHEN EXISTS (SELECT 1 FROM `workers` w WHERE w.`endpoint` = e.`endpoint` AND w.`status` != 'OFFLINE' AND w.`failure_type` = 'IMAGE_PULL_FAILED') THEN 'IMAGE_PULL_FAILED'
+  WHEN EXISTS (SELECT 1 FROM `workers` w WHERE w.`endpoint` = e.`endpoint` AND w.`status` != 'OFFLINE' AND w.`failure_type` = 'CONTAINER_CRASH') THEN 'CONTAINER_CRASH'
+  ELSE ''
+END
+WHERE e.`health_status` IN ('UNHEALTHY', 'DEGRADED');
@@ -0,0 +1,39 @@
+-- Migration: Add per-endpoint lifecycle hooks
+-- Date: 2026-10-16
+-- Hooks are HTTP calls or one-off jobs run before a deploy, after a deploy or before a delete.
+-- Every execution is recorded in endpoint_lifecycle_hook_runs (the hook activity feed).
+
+CREATE TABLE IF NOT EXISTS `endpoint_lifecycle_hooks` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `name` varchar(64) NOT NULL,
+  `phase` varchar(32) NOT NULL COMMENT 'pre_deploy, post_deploy or pre_delete',
+  `position` int NOT NULL DEFAULT '0' COMMENT 'Hooks of a phase run in ascending position',
+  `action` json NOT NULL COMMENT '{"http": ...} or {"job": ...}',
+  `timeout_seconds` int NOT NULL DEFAULT '0' COMMENT '0 = action default',
+  `failure_policy` varchar(16) NOT NULL COMMENT 'block or warn',
+  `updated_by` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_endpoint_name` (`endpoint`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Endpoint lifecycle hooks';
+
+CREATE TABLE IF NOT EXISTS `endpoint_lifecycle_hook_runs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `hook_name` varchar(64) NOT NULL,
+  `phase` varchar(32) NOT NULL,
+  `operation` varchar(32) NOT NULL COMMENT 'deploy, update or delete',
+  `action` varchar(16) NOT NULL COMMENT 'http or job',
+  `failure_policy` varchar(16) NOT NULL,
+  `status` varchar(16) NOT NULL COMMENT 'succeeded, failed or timed_out',
+  `blocked` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'The failure stopped the operation or the remaining hooks',
+  `status_code` int NOT NULL DEFAULT '0' COMMENT 'HTTP status or job exit code',
+  `output` text COMMENT 'Response body or job log tail',
+  `error` varchar(1024) DEFAULT NULL,
+  `duration_ms` bigint NOT NULL DEFAULT '0',
+  `started_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_endpoint_started` (`endpoint`, `started_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Lifecycle hook executions';
@@ -0,0 +1,17 @@
+-- Migration: Add versioned per-endpoint log redaction rules
+-- Date: 2026-10-15
+-- Rules mask PII in worker logs returned by the log APIs and shipped to external sinks.
+-- Each change creates a new immutable version with its author, so the table is also the
+-- audit trail of rule changes.
+
+CREATE TABLE IF NOT EXISTS `endpoint_log_redactions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `version` int NOT NULL,
+  `rules` json NOT NULL COMMENT 'Redaction rules (builtin or regex patterns)',
+  `comment` varchar(500) DEFAULT NULL COMMENT 'Change note',
+  `changed_by` varchar(255) DEFAULT NULL COMMENT 'Identity from the X-Requested-By header',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_endpoint_version` (`endpoint`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Versioned endpoint log redaction rules';
@@ -0,0 +1,11 @@
+-- Migration: Link endpoints to the model registry version they were deployed from
+-- Date: 2026-10-16
+-- Endpoints created with a modelName/modelVersion record the registry entry their image and
+-- env were resolved from. Existing rows and endpoints deployed by image keep empty values.
+
+ALTER TABLE endpoints
+ADD COLUMN model_registry VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'Model registry the image was resolved from, empty = deployed by image' AFTER latest_image,
+ADD COLUMN model_name VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Registered model name' AFTER model_registry,
+ADD COLUMN model_version VARCHAR(64) NOT NULL DEFAULT '' COMMENT 'Model version' AFTER model_name,
+ADD COLUMN model_url VARCHAR(1000) NOT NULL DEFAULT '' COMMENT 'The model version in the registry' AFTER model_version,
+ADD INDEX idx_model_name (model_name);
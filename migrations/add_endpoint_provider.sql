@@ -0,0 +1,7 @@
+-- Migration: Pin endpoints to a deployment provider
+-- Date: 2026-10-16
+-- Endpoints are pinned to the provider they were first deployed on (k8s, novita, runpod).
+-- Existing rows keep an empty value, which means the default provider (providers.deployment).
+
+ALTER TABLE endpoints
+ADD COLUMN provider VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'Deployment provider, empty = default provider' AFTER endpoint;
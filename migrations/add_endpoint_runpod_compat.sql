@@ -0,0 +1,7 @@
+-- Migration: Add per-endpoint RunPod compatibility toggle
+-- Date: 2026-10-15
+-- Endpoints default to the RunPod compatibility layer (RUNPOD_* worker env vars and the RunPod
+-- /v2 client paths). The flag is stored inverted so existing rows keep the layer enabled.
+
+ALTER TABLE endpoints
+ADD COLUMN runpod_compat_disabled TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'Disable RunPod env vars and API paths for this endpoint' AFTER enable_ptrace;
@@ -0,0 +1,18 @@
+-- Migration: Add versioned endpoint transforms (control-plane input/output hooks)
+-- Date: 2026-10-15
+-- Each save creates a new immutable version; tasks record the version applied to their
+-- input so the matching output transform is used at completion even after an update.
+
+CREATE TABLE IF NOT EXISTS `endpoint_transforms` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `version` int NOT NULL,
+  `spec` json NOT NULL COMMENT 'Input/output transform operations',
+  `comment` varchar(500) DEFAULT NULL COMMENT 'Change note',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_endpoint_version` (`endpoint`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Versioned endpoint input/output transforms';
+
+ALTER TABLE `tasks`
+  ADD COLUMN `transform_version` int NOT NULL DEFAULT '0' COMMENT 'Endpoint transform version applied to the input (0 = none)';
@@ -0,0 +1,20 @@
+-- Migration: Add endpoint warnings (structured, deduplicated per endpoint and code)
+-- Date: 2026-10-15
+-- Written by the worker startup handshake (POST /v2/:endpoint/handshake/:worker_id) when the
+-- worker SDK contract or env/volume layout does not match the endpoint.
+
+CREATE TABLE IF NOT EXISTS `endpoint_warnings` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `code` varchar(64) NOT NULL COMMENT 'e.g. MISSING_ENV, CONTRACT_UNSUPPORTED',
+  `source` varchar(32) NOT NULL COMMENT 'handshake',
+  `message` varchar(1024) NOT NULL,
+  `details` json DEFAULT NULL COMMENT 'e.g. missing env var names',
+  `worker_id` varchar(255) NOT NULL DEFAULT '' COMMENT 'Last worker that reported it',
+  `sdk_version` varchar(64) NOT NULL DEFAULT '',
+  `occurrences` bigint NOT NULL DEFAULT '1',
+  `first_seen_at` datetime(3) NOT NULL,
+  `last_seen_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_endpoint_code` (`endpoint`, `code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Structured endpoint warnings';
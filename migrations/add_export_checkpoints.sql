@@ -0,0 +1,14 @@
+-- Migration: Add export checkpoints for shipping records to external analytics storage
+-- Date: 2026-10-15
+
+CREATE TABLE IF NOT EXISTS `export_checkpoints` (
+  `dataset` varchar(100) NOT NULL COMMENT 'Exported dataset: gpu_usage_records, tasks',
+  `last_id` bigint NOT NULL DEFAULT '0' COMMENT 'Last exported row ID',
+  `last_time` datetime(3) DEFAULT NULL COMMENT 'Last exported cursor time (time-ordered datasets)',
+  `rows_exported` bigint NOT NULL DEFAULT '0' COMMENT 'Total rows exported',
+  `last_exported_at` datetime(3) DEFAULT NULL,
+  `last_object` varchar(1024) NOT NULL DEFAULT '' COMMENT 'Last object key or table written',
+  `last_error` varchar(1024) NOT NULL DEFAULT '',
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`dataset`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Analytics export progress';
@@ -0,0 +1,29 @@
+-- Migration: Add federated endpoints (logical endpoints backed by several regions)
+-- Date: 2026-10-15
+-- Tasks submitted to a federated endpoint are routed to the preferred healthy member;
+-- usage statistics roll up per member region.
+
+CREATE TABLE IF NOT EXISTS `federated_endpoints` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `display_name` varchar(255) NOT NULL DEFAULT '',
+  `description` varchar(512) NOT NULL DEFAULT '',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Logical endpoints backed by several regions';
+
+CREATE TABLE IF NOT EXISTS `federation_members` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `federation` varchar(255) NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `region` varchar(100) NOT NULL,
+  `priority` int NOT NULL DEFAULT '0' COMMENT 'Higher = preferred (e.g. nearer to clients)',
+  `enabled` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_federation_endpoint` (`federation`, `endpoint`),
+  KEY `idx_endpoint` (`endpoint`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Backing endpoints of federated endpoints';
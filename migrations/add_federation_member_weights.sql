@@ -0,0 +1,7 @@
+-- Migration: Add per-region traffic weights to federated endpoint members
+-- Date: 2026-10-15
+-- A member keeps `weight` percent of the tasks routed to it and spills the rest to the
+-- next member in routing order; lowering a region's weight migrates traffic gradually.
+
+ALTER TABLE `federation_members`
+  ADD COLUMN `weight` int NOT NULL DEFAULT '100' COMMENT 'Percent of routed traffic kept, the rest spills to the next member' AFTER `priority`;
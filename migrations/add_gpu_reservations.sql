@@ -0,0 +1,29 @@
+-- Migration: Add time-boxed GPU reservations
+-- Date: 2026-10-15
+-- A reservation holds spec × replicas for an endpoint during [start_time, end_time). While it
+-- runs, the autoscaler keeps the unclaimed GPUs free from scale-ups of lower-priority endpoints;
+-- at start_time the reservation job raises the endpoint to the reserved replicas.
+
+CREATE TABLE IF NOT EXISTS `gpu_reservations` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `spec_name` varchar(255) NOT NULL,
+  `replicas` int NOT NULL,
+  `gpu_count` int NOT NULL DEFAULT '0' COMMENT 'GPUs of all reserved replicas',
+  `priority` int NOT NULL DEFAULT '50' COMMENT 'Scale-ups of lower-priority endpoints are blocked',
+  `start_time` datetime(3) NOT NULL,
+  `end_time` datetime(3) NOT NULL,
+  `status` varchar(16) NOT NULL COMMENT 'scheduled, active, completed, cancelled',
+  `previous_min_replicas` int NOT NULL DEFAULT '0' COMMENT 'Min replicas restored when the window ends',
+  `description` varchar(512) NOT NULL DEFAULT '',
+  `created_by` varchar(255) NOT NULL DEFAULT '',
+  `activated_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_name` (`name`),
+  KEY `idx_endpoint` (`endpoint`),
+  KEY `idx_window` (`start_time`, `end_time`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Time-boxed GPU capacity reservations';
@@ -0,0 +1,33 @@
+-- Migration: Add resumable GPU usage backfill jobs
+-- Date: 2026-10-15
+-- A backfill job creates the missing GPU usage records of a time range in throttled batches,
+-- rebuilds the statistics day by day and ends with a consistency report. The cursor columns
+-- are saved after every batch, so a restarted instance continues where the job stopped.
+
+CREATE TABLE IF NOT EXISTS `gpu_usage_backfill_jobs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `status` varchar(20) NOT NULL COMMENT 'pending, running, completed, failed, cancelled',
+  `phase` varchar(20) NOT NULL COMMENT 'records, reaggregate, report',
+  `from_time` datetime(3) NOT NULL,
+  `to_time` datetime(3) NOT NULL,
+  `batch_size` int NOT NULL,
+  `rate_limit` int NOT NULL COMMENT 'Tasks per second, 0 = unthrottled',
+  `total_tasks` bigint NOT NULL DEFAULT '0' COMMENT 'Tasks without records when the job was created',
+  `cursor_task_id` bigint NOT NULL DEFAULT '0' COMMENT 'Last processed task id (records phase)',
+  `cursor_day` datetime DEFAULT NULL COMMENT 'Next day to re-aggregate (reaggregate phase)',
+  `tasks_processed` bigint NOT NULL DEFAULT '0',
+  `records_created` bigint NOT NULL DEFAULT '0',
+  `records_skipped` bigint NOT NULL DEFAULT '0',
+  `error_count` bigint NOT NULL DEFAULT '0',
+  `errors` json DEFAULT NULL COMMENT 'First per-task errors',
+  `days_reaggregated` int NOT NULL DEFAULT '0',
+  `report` json DEFAULT NULL COMMENT 'Consistency report of a finished job',
+  `last_error` varchar(1024) NOT NULL DEFAULT '',
+  `created_by` varchar(255) NOT NULL DEFAULT '',
+  `started_at` datetime(3) DEFAULT NULL,
+  `completed_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Resumable GPU usage backfill jobs';
@@ -0,0 +1,104 @@
+-- Migration: Re-introduce GPU usage tracking tables with scheduled aggregation state
+-- Date: 2026-10-15
+
+-- 1. Task-level GPU usage records (one row per finished task)
+CREATE TABLE IF NOT EXISTS `gpu_usage_records` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `task_id` varchar(255) NOT NULL COMMENT 'Task ID',
+  `endpoint` varchar(255) NOT NULL COMMENT 'Endpoint name',
+  `worker_id` varchar(255) DEFAULT NULL COMMENT 'Worker that executed the task',
+  `spec_name` varchar(255) DEFAULT NULL COMMENT 'Spec name',
+  `gpu_count` int NOT NULL COMMENT 'GPUs allocated to the worker',
+  `gpu_type` varchar(100) DEFAULT NULL COMMENT 'GPU type (e.g. H200-80GB)',
+  `gpu_memory_gb` int DEFAULT NULL COMMENT 'GPU memory per card in GB',
+  `started_at` datetime(3) NOT NULL,
+  `completed_at` datetime(3) NOT NULL,
+  `duration_seconds` int NOT NULL,
+  `gpu_hours` decimal(12,4) NOT NULL,
+  `status` varchar(50) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_task_id` (`task_id`),
+  KEY `idx_endpoint_completed` (`endpoint`, `completed_at`),
+  KEY `idx_spec_name` (`spec_name`),
+  KEY `idx_completed_at` (`completed_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Task-level GPU usage records';
+
+-- 2. Minute-level aggregation
+CREATE TABLE IF NOT EXISTS `gpu_usage_statistics_minute` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `time_bucket` datetime NOT NULL,
+  `scope_type` varchar(50) NOT NULL COMMENT 'global, endpoint, spec',
+  `scope_value` varchar(255) NOT NULL COMMENT 'Scope value (global for global scope)',
+  `total_tasks` int DEFAULT '0',
+  `completed_tasks` int DEFAULT '0',
+  `failed_tasks` int DEFAULT '0',
+  `total_gpu_seconds` bigint DEFAULT '0',
+  `total_gpu_hours` decimal(12,4) DEFAULT '0',
+  `avg_gpu_count` decimal(10,2) DEFAULT '0',
+  `max_gpu_count` int DEFAULT '0',
+  `period_start` datetime(3) NOT NULL,
+  `period_end` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_time_scope` (`time_bucket`, `scope_type`, `scope_value`),
+  KEY `idx_time_bucket` (`time_bucket`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='GPU usage minute statistics';
+
+-- 3. Hourly aggregation (rolled up from minute statistics)
+CREATE TABLE IF NOT EXISTS `gpu_usage_statistics_hourly` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `time_bucket` datetime NOT NULL,
+  `scope_type` varchar(50) NOT NULL,
+  `scope_value` varchar(255) NOT NULL,
+  `total_tasks` int DEFAULT '0',
+  `completed_tasks` int DEFAULT '0',
+  `failed_tasks` int DEFAULT '0',
+  `total_gpu_seconds` bigint DEFAULT '0',
+  `total_gpu_hours` decimal(12,4) DEFAULT '0',
+  `avg_gpu_count` decimal(10,2) DEFAULT '0',
+  `max_gpu_count` int DEFAULT '0',
+  `peak_minute` datetime DEFAULT NULL,
+  `peak_gpu_hours` decimal(12,4) DEFAULT '0',
+  `period_start` datetime(3) NOT NULL,
+  `period_end` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_time_scope` (`time_bucket`, `scope_type`, `scope_value`),
+  KEY `idx_time_bucket` (`time_bucket`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='GPU usage hourly statistics';
+
+-- 4. Daily aggregation (rolled up from hourly statistics)
+CREATE TABLE IF NOT EXISTS `gpu_usage_statistics_daily` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `time_bucket` date NOT NULL,
+  `scope_type` varchar(50) NOT NULL,
+  `scope_value` varchar(255) NOT NULL,
+  `total_tasks` int DEFAULT '0',
+  `completed_tasks` int DEFAULT '0',
+  `failed_tasks` int DEFAULT '0',
+  `total_gpu_seconds` bigint DEFAULT '0',
+  `total_gpu_hours` decimal(12,4) DEFAULT '0',
+  `avg_gpu_count` decimal(10,2) DEFAULT '0',
+  `max_gpu_count` int DEFAULT '0',
+  `peak_hour` datetime DEFAULT NULL,
+  `peak_gpu_hours` decimal(12,4) DEFAULT '0',
+  `period_start` datetime(3) NOT NULL,
+  `period_end` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_time_scope` (`time_bucket`, `scope_type`, `scope_value`),
+  KEY `idx_time_bucket` (`time_bucket`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='GPU usage daily statistics';
+
+-- 5. Aggregation watermark per granularity (shared across replicas)
+CREATE TABLE IF NOT EXISTS `gpu_usage_aggregation_state` (
+  `granularity` varchar(20) NOT NULL COMMENT 'minute, hourly, daily',
+  `watermark` datetime NOT NULL COMMENT 'Buckets before this time are fully aggregated',
+  `last_run_at` datetime(3) DEFAULT NULL,
+  `last_duration_ms` bigint DEFAULT '0',
+  `last_buckets` int DEFAULT '0',
+  `last_error` varchar(1024) NOT NULL DEFAULT '',
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`granularity`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='GPU usage aggregation progress';
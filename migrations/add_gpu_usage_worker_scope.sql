@@ -0,0 +1,8 @@
+-- Migration: Support worker scope and top-N ranking queries on GPU usage statistics
+-- Date: 2026-10-15
+
+-- Worker scope rows share the existing tables (scope_type = 'worker', scope_value = worker ID).
+-- Ranking queries filter by scope_type over a time range, so index that access path.
+CREATE INDEX idx_scope_time ON gpu_usage_statistics_minute (scope_type, time_bucket);
+CREATE INDEX idx_scope_time ON gpu_usage_statistics_hourly (scope_type, time_bucket);
+CREATE INDEX idx_scope_time ON gpu_usage_statistics_daily (scope_type, time_bucket);
@@ -0,0 +1,21 @@
+-- Migration: Add provenance of images copied into the internal registry
+-- Date: 2026-10-15
+
+CREATE TABLE IF NOT EXISTS `image_copy_records` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL DEFAULT '' COMMENT 'Endpoint the copy was made for',
+  `source_image` varchar(512) NOT NULL COMMENT 'Upstream image as requested',
+  `source_digest` varchar(100) NOT NULL DEFAULT '' COMMENT 'Upstream manifest digest',
+  `target_image` varchar(512) NOT NULL DEFAULT '' COMMENT 'Internal reference pinned by digest',
+  `media_type` varchar(100) NOT NULL DEFAULT '',
+  `status` varchar(20) NOT NULL COMMENT 'succeeded, failed',
+  `blobs_copied` int NOT NULL DEFAULT '0',
+  `blobs_skipped` int NOT NULL DEFAULT '0' COMMENT 'Blobs already present in the internal registry',
+  `bytes_copied` bigint NOT NULL DEFAULT '0',
+  `error` varchar(1024) NOT NULL DEFAULT '',
+  `duration_ms` bigint NOT NULL DEFAULT '0',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_endpoint_created` (`endpoint`,`created_at`),
+  KEY `idx_target_image` (`target_image`(191))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Provenance of images copied into the internal registry';
@@ -0,0 +1,24 @@
+-- Migration: Add image validation history per endpoint
+-- Date: 2026-10-15
+
+CREATE TABLE IF NOT EXISTS `image_validation_records` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL DEFAULT '' COMMENT 'Endpoint name (empty for ad-hoc validations)',
+  `image` varchar(512) NOT NULL,
+  `source` varchar(20) NOT NULL COMMENT 'deploy, api',
+  `valid` tinyint(1) NOT NULL COMMENT 'Image reference format is valid',
+  `exists` tinyint(1) NOT NULL,
+  `accessible` tinyint(1) NOT NULL,
+  `rejected` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'Deploy rejected because of this result',
+  `digest` varchar(100) NOT NULL DEFAULT '',
+  `media_type` varchar(100) NOT NULL DEFAULT '',
+  `size_bytes` bigint NOT NULL DEFAULT '0' COMMENT 'Compressed size (config + layers)',
+  `platforms` json DEFAULT NULL COMMENT 'os/arch list',
+  `error` varchar(1024) NOT NULL DEFAULT '',
+  `warning` varchar(1024) NOT NULL DEFAULT '',
+  `duration_ms` bigint NOT NULL DEFAULT '0',
+  `cache_hit` tinyint(1) NOT NULL DEFAULT '0',
+  `checked_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_endpoint_checked` (`endpoint`,`checked_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Image validation outcomes per endpoint';
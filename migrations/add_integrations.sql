@@ -0,0 +1,30 @@
+-- Migration: Add integrations registry (external webhooks with delivery state)
+-- Date: 2026-10-15
+-- Callback URLs, event filters and signing secrets managed via /api/v1/integrations.
+-- Used for task completion callbacks (webhook: "integration:<name>" or event subscriptions),
+-- endpoint health and image update notifications.
+
+CREATE TABLE IF NOT EXISTS `integrations` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `kind` varchar(32) NOT NULL DEFAULT 'webhook' COMMENT 'webhook, feishu',
+  `url` varchar(1000) NOT NULL,
+  `events` json DEFAULT NULL COMMENT 'Subscribed event types',
+  `endpoints` json DEFAULT NULL COMMENT 'Endpoint filter, empty = all',
+  `secret` varchar(255) NOT NULL DEFAULT '' COMMENT 'HMAC-SHA256 signing secret',
+  `max_retries` int NOT NULL DEFAULT '3',
+  `enabled` tinyint(1) NOT NULL DEFAULT '1',
+  `description` varchar(500) NOT NULL DEFAULT '',
+  `consecutive_failures` int NOT NULL DEFAULT '0',
+  `total_deliveries` bigint NOT NULL DEFAULT '0',
+  `total_failures` bigint NOT NULL DEFAULT '0',
+  `last_delivery_at` datetime(3) DEFAULT NULL,
+  `last_status` varchar(16) NOT NULL DEFAULT '',
+  `last_status_code` int NOT NULL DEFAULT '0',
+  `last_error` varchar(1024) NOT NULL DEFAULT '',
+  `last_attempts` int NOT NULL DEFAULT '0',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='External webhooks and integrations';
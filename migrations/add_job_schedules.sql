@@ -0,0 +1,44 @@
+-- Migration: Add recurring job schedules
+-- Date: 2026-10-15
+-- A job schedule starts a batch job on a cron schedule with a concurrency policy, retries of
+-- failed runs and notifications. Its runs are batch jobs linked by schedule_name; retries of a
+-- run share its scheduled_at and count up attempt.
+
+CREATE TABLE IF NOT EXISTS `job_schedules` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(45) NOT NULL,
+  `cron` varchar(100) NOT NULL,
+  `timezone` varchar(64) NOT NULL DEFAULT 'UTC',
+  `spec_name` varchar(100) NOT NULL,
+  `image` varchar(500) NOT NULL,
+  `command` json DEFAULT NULL,
+  `args` json DEFAULT NULL,
+  `env` json DEFAULT NULL,
+  `volume_mounts` json DEFAULT NULL COMMENT 'Mount path -> PVC name',
+  `gpu_count` int NOT NULL DEFAULT 0,
+  `shm_size` varchar(20) NOT NULL DEFAULT '',
+  `timeout_seconds` int NOT NULL DEFAULT 0,
+  `concurrency_policy` varchar(10) NOT NULL DEFAULT 'forbid' COMMENT 'forbid, replace, allow',
+  `max_retries` int NOT NULL DEFAULT 0,
+  `retry_delay_seconds` int NOT NULL DEFAULT 0,
+  `history_limit` int NOT NULL DEFAULT 20 COMMENT 'Finished runs kept',
+  `notify` json DEFAULT NULL COMMENT 'Integrations told about failed runs',
+  `notify_on_success` tinyint(1) NOT NULL DEFAULT 0,
+  `suspended` tinyint(1) NOT NULL DEFAULT 0,
+  `next_run_at` datetime(3) DEFAULT NULL COMMENT 'NULL while suspended',
+  `last_scheduled_at` datetime(3) DEFAULT NULL,
+  `last_run_name` varchar(63) NOT NULL DEFAULT '',
+  `message` varchar(512) NOT NULL DEFAULT '' COMMENT 'Why the last fire started no run',
+  `created_by` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_name` (`name`),
+  KEY `idx_next_run_at` (`next_run_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Recurring batch job schedules';
+
+ALTER TABLE `batch_jobs`
+  ADD COLUMN `schedule_name` varchar(45) NOT NULL DEFAULT '' COMMENT 'Job schedule that started the run' AFTER `created_by`,
+  ADD COLUMN `attempt` int NOT NULL DEFAULT 0 COMMENT 'Attempt of a scheduled run, 1 = first' AFTER `schedule_name`,
+  ADD COLUMN `scheduled_at` datetime(3) DEFAULT NULL COMMENT 'Fire time, shared by retries' AFTER `attempt`,
+  ADD KEY `idx_schedule_created` (`schedule_name`, `created_at`);
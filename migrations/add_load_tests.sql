@@ -0,0 +1,61 @@
+-- Migration: Add load tests of staging endpoints
+-- Date: 2026-10-16
+-- A load test sends tasks built from a payload corpus to a staging endpoint at the rate of an
+-- RPS profile, on a cron schedule or on demand. Every run stores a report with latency/error
+-- curves and the autoscaler behavior, compared against the last run of another image.
+-- Runs are operations of type load_test.
+
+CREATE TABLE IF NOT EXISTS `load_tests` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(63) NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `cron` varchar(100) NOT NULL DEFAULT '' COMMENT 'Empty = on demand only',
+  `timezone` varchar(64) NOT NULL DEFAULT 'UTC',
+  `profile` json NOT NULL COMMENT 'Stages [{durationSeconds, rps, ramp}]',
+  `payloads` json NOT NULL COMMENT 'Task inputs, sent in turn',
+  `task_timeout_seconds` int NOT NULL DEFAULT 600,
+  `max_latency_increase` double NOT NULL DEFAULT 0.2 COMMENT 'Relative, per latency percentile',
+  `max_error_rate_increase` double NOT NULL DEFAULT 0.01 COMMENT 'Absolute',
+  `history_limit` int NOT NULL DEFAULT 30 COMMENT 'Finished reports kept',
+  `notify` json DEFAULT NULL COMMENT 'Integrations told about failed and regressed runs',
+  `suspended` tinyint(1) NOT NULL DEFAULT 0,
+  `next_run_at` datetime(3) DEFAULT NULL COMMENT 'NULL while suspended or without cron',
+  `last_run_at` datetime(3) DEFAULT NULL,
+  `last_report_id` bigint NOT NULL DEFAULT 0,
+  `message` varchar(512) NOT NULL DEFAULT '' COMMENT 'Why the last fire started no run',
+  `created_by` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_name` (`name`),
+  KEY `idx_endpoint` (`endpoint`),
+  KEY `idx_next_run_at` (`next_run_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Load tests of staging endpoints';
+
+CREATE TABLE IF NOT EXISTS `load_test_reports` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `load_test` varchar(63) NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `operation_id` varchar(64) NOT NULL DEFAULT '',
+  `status` varchar(20) NOT NULL COMMENT 'running, succeeded, failed, cancelled',
+  `image` varchar(500) NOT NULL DEFAULT '' COMMENT 'Endpoint image when the run started',
+  `model_version` varchar(100) NOT NULL DEFAULT '',
+  `triggered_by` varchar(255) NOT NULL DEFAULT '',
+  `requests` int NOT NULL DEFAULT 0,
+  `error_rate` double NOT NULL DEFAULT 0,
+  `p50_ms` double NOT NULL DEFAULT 0,
+  `p99_ms` double NOT NULL DEFAULT 0,
+  `achieved_rps` double NOT NULL DEFAULT 0,
+  `peak_replicas` int NOT NULL DEFAULT 0,
+  `baseline_id` bigint NOT NULL DEFAULT 0 COMMENT 'Report compared against, 0 = none',
+  `regressed` tinyint(1) NOT NULL DEFAULT 0,
+  `error` varchar(1024) NOT NULL DEFAULT '',
+  `report` json DEFAULT NULL COMMENT 'Summary, curves and autoscaler behavior',
+  `comparison` json DEFAULT NULL COMMENT 'Against the baseline',
+  `started_at` datetime(3) NOT NULL,
+  `finished_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_load_test_created` (`load_test`, `created_at`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Reports of load test runs';
@@ -0,0 +1,27 @@
+-- Migration: Add long-running operations
+-- Date: 2026-10-16
+-- Deploys, rollouts and backfills can run asynchronously (?async=true) and return an operation
+-- ID pollable at /api/v1/operations/{id}. The replica that accepted the request runs the
+-- operation and stores its progress here; updated_at is its heartbeat, so operations of a
+-- replica that died are failed by the others.
+
+CREATE TABLE IF NOT EXISTS `operations` (
+  `id` varchar(64) NOT NULL,
+  `type` varchar(50) NOT NULL COMMENT 'deploy, rollout, gpu_usage_backfill, blue_green or load_test',
+  `target` varchar(255) NOT NULL DEFAULT '' COMMENT 'Object the operation acts on, e.g. the endpoint',
+  `status` varchar(20) NOT NULL COMMENT 'pending, running, succeeded, failed or cancelled',
+  `progress` int NOT NULL DEFAULT '0' COMMENT 'Percent',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT 'Current step',
+  `error` varchar(1024) NOT NULL DEFAULT '',
+  `result` json DEFAULT NULL,
+  `owner` varchar(255) NOT NULL DEFAULT '' COMMENT 'Replica running the operation',
+  `created_by` varchar(255) NOT NULL DEFAULT '' COMMENT 'Identity from the X-Requested-By header',
+  `started_at` datetime(3) DEFAULT NULL,
+  `completed_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL COMMENT 'Heartbeat while pending or running',
+  PRIMARY KEY (`id`),
+  KEY `idx_type_created` (`type`, `created_at`),
+  KEY `idx_target` (`target`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Long-running API operations and their progress';
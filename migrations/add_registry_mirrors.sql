@@ -0,0 +1,14 @@
+-- Migration: Add registry mirror mappings (upstream registry -> in-cluster mirror)
+-- Date: 2026-10-15
+
+CREATE TABLE IF NOT EXISTS `registry_mirrors` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `upstream` varchar(255) NOT NULL COMMENT 'Normalized upstream registry host, e.g. docker.io',
+  `mirror` varchar(512) NOT NULL COMMENT 'Mirror registry with optional path prefix, e.g. harbor.internal/dockerhub',
+  `enabled` tinyint(1) NOT NULL DEFAULT '1',
+  `description` varchar(512) NOT NULL DEFAULT '',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_upstream` (`upstream`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Registry mirror / pull-through cache mappings';
@@ -0,0 +1,17 @@
+-- Migration: Add sampling rules (task input/output sampling for offline evaluation)
+-- Date: 2026-10-15
+-- A fraction of each endpoint's finished tasks is scrubbed of PII and copied to the
+-- datasets store configured under `sampling` (local directory or S3).
+
+CREATE TABLE IF NOT EXISTS `sampling_rules` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `sample_rate` decimal(7,6) NOT NULL DEFAULT '0' COMMENT 'Fraction of tasks sampled, 0-1',
+  `include_failed` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'Also sample failed tasks',
+  `scrub_fields` json DEFAULT NULL COMMENT 'Input/output keys redacted before upload',
+  `enabled` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_endpoint` (`endpoint`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Task input/output sampling rules';
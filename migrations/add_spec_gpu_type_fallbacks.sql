@@ -0,0 +1,8 @@
+-- Migration: Add GPU type fallbacks to resource specs
+-- Date: 2026-10-16
+-- Ordered GPU types (e.g. A100, L40S) a spec's workers may run on when its gpu_type has no
+-- capacity. The GPU type a worker actually runs on is kept in workers.runtime_state.gpuType and
+-- in gpu_usage_records.gpu_type. NULL = no fallbacks.
+
+ALTER TABLE `resource_specs`
+  ADD COLUMN `gpu_type_fallbacks` json DEFAULT NULL COMMENT 'Fallback GPU types in preference order (JSON array), NULL = none' AFTER `gpu_type`;
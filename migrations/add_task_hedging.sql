@@ -0,0 +1,12 @@
+-- Migration: Add hedged execution
+-- Date: 2026-10-15
+-- Tasks of latency-critical endpoints running longer than the endpoint's p99 execution time
+-- get a hedge: a copy queued for a second worker. The hedge keeps its original in
+-- tasks.hedge_of; the original records which copy reported first in hedge_outcome and, when
+-- the losing original still reported, how much earlier the hedge was in hedge_saved_ms.
+
+ALTER TABLE tasks
+ADD COLUMN hedge_of VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Task ID this task duplicates (empty = not a hedge)' AFTER replay_job_id,
+ADD COLUMN hedge_outcome VARCHAR(16) NOT NULL DEFAULT '' COMMENT 'racing, original or hedge (empty = not hedged)' AFTER hedge_of,
+ADD COLUMN hedge_saved_ms BIGINT NULL COMMENT 'How much earlier the winning hedge reported than the original' AFTER hedge_outcome,
+ADD INDEX idx_hedge_of (hedge_of);
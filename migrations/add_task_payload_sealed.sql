@@ -0,0 +1,16 @@
+-- Migration: Flag sealed task payloads
+-- Date: 2026-10-16
+-- Whether a task's input/output is stored sealed with its project's data key is recorded on the
+-- row instead of being inferred from the payload, so a caller cannot store an input shaped like
+-- an envelope unencrypted. Rows written before this migration are flagged from their stored
+-- payloads once; only the repository sets the flags afterwards.
+
+ALTER TABLE tasks
+ADD COLUMN input_sealed TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'Input is sealed with the project data key' AFTER input,
+ADD COLUMN output_sealed TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'Output is sealed with the project data key' AFTER output;
+
+UPDATE tasks SET input_sealed = 1
+WHERE JSON_LENGTH(input) = 1 AND JSON_CONTAINS_PATH(input, 'one', '$._encrypted');
+
+UPDATE tasks SET output_sealed = 1
+WHERE output IS NOT NULL AND JSON_LENGTH(output) = 1 AND JSON_CONTAINS_PATH(output, 'one', '$._encrypted');
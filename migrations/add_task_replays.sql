@@ -0,0 +1,36 @@
+-- Migration: Add task replay
+-- Date: 2026-10-15
+-- A past task can be replayed with the same input against its endpoint, and the tasks of a
+-- time window can be replayed at a fixed rate into e.g. a staging endpoint. Replayed tasks
+-- keep the task they replay in tasks.replay_of (and their replay job in replay_job_id), so
+-- their statistics are kept apart from regular traffic. Replay jobs run in slices and store
+-- their progress in task_replay_jobs.
+
+ALTER TABLE tasks
+ADD COLUMN replay_of VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Task ID replayed by this task (empty = not a replay)' AFTER subject_hash,
+ADD COLUMN replay_job_id BIGINT NOT NULL DEFAULT 0 COMMENT 'Replay job that submitted the task (0 = none)' AFTER replay_of,
+ADD INDEX idx_endpoint_replay (endpoint, replay_of),
+ADD INDEX idx_replay_job_id (replay_job_id);
+
+CREATE TABLE IF NOT EXISTS `task_replay_jobs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `status` varchar(20) NOT NULL COMMENT 'pending, running, completed or cancelled',
+  `source_endpoint` varchar(255) NOT NULL,
+  `target_endpoint` varchar(255) NOT NULL,
+  `from_time` datetime(3) NOT NULL,
+  `to_time` datetime(3) NOT NULL,
+  `statuses` json DEFAULT NULL COMMENT 'Task statuses replayed (empty = all finished)',
+  `rate_limit` int NOT NULL COMMENT 'Tasks per second',
+  `max_tasks` int NOT NULL,
+  `cursor_task_id` bigint NOT NULL DEFAULT '0' COMMENT 'Last source task ID processed',
+  `submitted` bigint NOT NULL DEFAULT '0',
+  `skipped` bigint NOT NULL DEFAULT '0' COMMENT 'Source tasks without input (anonymized)',
+  `last_error` varchar(1024) NOT NULL DEFAULT '',
+  `created_by` varchar(255) NOT NULL DEFAULT '' COMMENT 'Identity from the X-Requested-By header',
+  `started_at` datetime(3) DEFAULT NULL,
+  `completed_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Task replay jobs and their progress';
@@ -0,0 +1,31 @@
+-- Migration: Add worker broadcasts
+-- Date: 2026-10-15
+-- A broadcast is a control message (e.g. "model weights updated, flush your cache") sent to
+-- every worker of an endpoint through the heartbeat response; each addressed worker has a
+-- delivery row tracking its acknowledgement.
+
+CREATE TABLE IF NOT EXISTS `worker_broadcasts` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `endpoint` varchar(255) NOT NULL,
+  `type` varchar(100) NOT NULL,
+  `payload` json DEFAULT NULL,
+  `created_by` varchar(255) NOT NULL DEFAULT '' COMMENT 'X-Requested-By of the sender',
+  `expires_at` datetime(3) NOT NULL COMMENT 'Not delivered after this time',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_endpoint_created` (`endpoint`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Control messages to the workers of an endpoint';
+
+CREATE TABLE IF NOT EXISTS `worker_broadcast_deliveries` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `broadcast_id` bigint NOT NULL,
+  `worker_id` varchar(255) NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'pending' COMMENT 'pending, acked, failed',
+  `message` varchar(512) NOT NULL DEFAULT '' COMMENT 'Error reported by the worker',
+  `sent_at` datetime(3) DEFAULT NULL COMMENT 'First heartbeat that carried the broadcast',
+  `acked_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_broadcast_worker` (`broadcast_id`, `worker_id`),
+  KEY `idx_worker_status` (`worker_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Per-worker delivery of worker broadcasts';
@@ -0,0 +1,7 @@
+-- Migration: Add announced capabilities to workers
+-- Date: 2026-10-15
+-- NULL means the worker never declared capabilities (legacy behavior); otherwise a JSON array,
+-- e.g. ["batch","cancellation","streaming"]
+
+ALTER TABLE `workers`
+  ADD COLUMN `capabilities` text NULL COMMENT 'JSON array of worker capabilities: streaming, cancellation, checkpointing, batch' AFTER `version`;
@@ -0,0 +1,8 @@
+-- Migration: Track worker registration and first task completion for cold start metrics
+-- Date: 2026-10-15
+
+ALTER TABLE `workers`
+  ADD COLUMN `registered_at` datetime(3) DEFAULT NULL COMMENT 'First heartbeat received from the worker' AFTER `cold_start_duration_ms`,
+  ADD COLUMN `first_task_completed_at` datetime(3) DEFAULT NULL COMMENT 'First task finished by the worker' AFTER `registered_at`;
+
+CREATE INDEX idx_workers_pod_created_at ON workers(pod_created_at);
@@ -0,0 +1,7 @@
+-- Migration: Add drain reason to workers
+-- Date: 2026-10-15
+-- Records why a worker was marked DRAINING, e.g. "node maintenance on gpu-node-3: evicted
+-- while the node is drained", so node drains are distinguishable from scale-down.
+
+ALTER TABLE workers
+ADD COLUMN drain_reason VARCHAR(512) NOT NULL DEFAULT '' COMMENT 'Why the worker is draining' AFTER terminated_at;
@@ -0,0 +1,7 @@
+-- Migration: Add the warning codes of each worker's last handshake
+-- Date: 2026-10-16
+-- A handshake warning of an endpoint is resolved only once no live worker reports it anymore.
+-- NULL means the worker never handshaked; otherwise a JSON array, e.g. ["MISSING_ENV"]
+
+ALTER TABLE `workers`
+  ADD COLUMN `handshake_warnings` text NULL COMMENT 'JSON array of warning codes of the last handshake' AFTER `capabilities`;
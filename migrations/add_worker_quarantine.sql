@@ -0,0 +1,22 @@
+-- Migration: Add worker quarantine and node/image bans
+-- Date: 2026-10-15
+-- A quarantined worker receives no new tasks but its pod is kept for inspection. A ban keeps
+-- every worker on a node, or running an image digest, from receiving new tasks; the workers it
+-- matches are quarantined with the ban's ID and released when the ban is lifted.
+
+ALTER TABLE workers
+ADD COLUMN quarantined_at DATETIME(3) NULL COMMENT 'Set while the worker is quarantined' AFTER drain_reason,
+ADD COLUMN quarantine_reason VARCHAR(512) NOT NULL DEFAULT '' COMMENT 'Why the worker is quarantined' AFTER quarantined_at,
+ADD COLUMN quarantined_by VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'X-Requested-By of the operator' AFTER quarantine_reason,
+ADD COLUMN quarantine_ban_id BIGINT NULL COMMENT 'Ban that quarantined the worker, NULL if quarantined manually' AFTER quarantined_by;
+
+CREATE TABLE IF NOT EXISTS `worker_bans` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `kind` varchar(20) NOT NULL COMMENT 'node or image',
+  `value` varchar(255) NOT NULL COMMENT 'Node name or image digest (sha256:...)',
+  `reason` varchar(512) NOT NULL DEFAULT '',
+  `created_by` varchar(255) NOT NULL DEFAULT '' COMMENT 'X-Requested-By of the operator',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_kind_value` (`kind`, `value`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Nodes and image digests banned from receiving new tasks';
@@ -0,0 +1,227 @@
+// Package anomaly detects sudden changes in endpoint usage (failure rate spikes, GPU-hour
+// surges, runaway queue latency) by comparing a recent window with a robust baseline built
+// from the hourly statistics of the preceding days.
+//
+// Baselines use the median and the median absolute deviation (MAD) of the hourly buckets, so
+// a few past incidents do not inflate them. Detection is pure; loading the statistics and
+// raising alerts is up to the caller.
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Kind identifies an anomaly type; it doubles as the endpoint warning code
+type Kind string
+
+const (
+	KindFailureRate  Kind = "failure_rate_spike"    // Failed share of finished tasks far above its usual level
+	KindGPUHours     Kind = "gpu_hours_surge"       // GPU hours per hour a multiple of the usual level
+	KindQueueLatency Kind = "queue_latency_runaway" // Average queue wait a multiple of the usual level
+)
+
+// Sensitivity selects how far from the baseline a value must be to be reported
+type Sensitivity string
+
+const (
+	SensitivityLow    Sensitivity = "low"
+	SensitivityNormal Sensitivity = "normal"
+	SensitivityHigh   Sensitivity = "high"
+	SensitivityOff    Sensitivity = "off"
+)
+
+// MinBaselineSamples is the number of hourly buckets a baseline needs; endpoints with less
+// history are not checked
+const MinBaselineSamples = 6
+
+// madScale makes the MAD comparable to a standard deviation for normally distributed data
+const madScale = 1.4826
+
+// Thresholds are the detection limits of a sensitivity
+type Thresholds struct {
+	FailureRateMADs    float64 // Failure rate must exceed the baseline median by this many (scaled) MADs
+	MinFailureRate     float64 // ... and be at least this high
+	MinTasks           int     // Finished tasks in the window needed to judge the failure rate
+	GPUHoursFactor     float64 // GPU hours per hour must reach this multiple of the baseline median
+	MinGPUHours        float64 // ... and at least this many GPU hours per hour
+	QueueLatencyFactor float64 // Average queue wait must reach this multiple of the baseline median
+	MinQueueLatencyMs  float64 // ... and at least this long
+}
+
+var thresholds = map[Sensitivity]Thresholds{
+	SensitivityHigh:   {FailureRateMADs: 3, MinFailureRate: 0.05, MinTasks: 10, GPUHoursFactor: 2, MinGPUHours: 0.5, QueueLatencyFactor: 2, MinQueueLatencyMs: 5000},
+	SensitivityNormal: {FailureRateMADs: 4, MinFailureRate: 0.1, MinTasks: 20, GPUHoursFactor: 3, MinGPUHours: 1, QueueLatencyFactor: 3, MinQueueLatencyMs: 10000},
+	SensitivityLow:    {FailureRateMADs: 6, MinFailureRate: 0.25, MinTasks: 50, GPUHoursFactor: 5, MinGPUHours: 2, QueueLatencyFactor: 5, MinQueueLatencyMs: 30000},
+}
+
+// ParseSensitivity validates a sensitivity; empty means normal
+func ParseSensitivity(s string) (Sensitivity, error) {
+	if s == "" {
+		return SensitivityNormal, nil
+	}
+	sensitivity := Sensitivity(s)
+	if _, ok := thresholds[sensitivity]; !ok && sensitivity != SensitivityOff {
+		return "", fmt.Errorf("invalid anomaly sensitivity %q (expected low, normal, high or off)", s)
+	}
+	return sensitivity, nil
+}
+
+// ThresholdsFor returns the thresholds of a sensitivity, false for off or unknown values
+func ThresholdsFor(s Sensitivity) (Thresholds, bool) {
+	t, ok := thresholds[s]
+	return t, ok
+}
+
+// Sample is the usage of an endpoint in one bucket (the recent window or a baseline hour)
+type Sample struct {
+	Completed   int     // Tasks completed
+	Failed      int     // Tasks failed (including timeouts)
+	GPUHours    float64 // GPU hours per hour of the bucket
+	QueueWaitMs float64 // Average queue wait of the tasks in the bucket
+}
+
+// failureRate returns the failed share of finished tasks and whether any task finished
+func (s Sample) failureRate() (float64, bool) {
+	finished := s.Completed + s.Failed
+	if finished == 0 {
+		return 0, false
+	}
+	return float64(s.Failed) / float64(finished), true
+}
+
+// Finding is a detected anomaly with the numbers behind it
+type Finding struct {
+	Kind      Kind    `json:"kind"`
+	Current   float64 `json:"current"`   // Value in the recent window
+	Baseline  float64 `json:"baseline"`  // Median of the baseline buckets
+	Threshold float64 `json:"threshold"` // Value the window had to exceed
+	Samples   int     `json:"samples"`   // Baseline buckets used
+	Message   string  `json:"message"`
+}
+
+// Detect compares the recent window with the baseline buckets and returns the anomalies found
+func Detect(current Sample, baseline []Sample, t Thresholds) []Finding {
+	var findings []Finding
+	if f, ok := detectFailureRate(current, baseline, t); ok {
+		findings = append(findings, f)
+	}
+	if f, ok := detectGPUHours(current, baseline, t); ok {
+		findings = append(findings, f)
+	}
+	if f, ok := detectQueueLatency(current, baseline, t); ok {
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func detectFailureRate(current Sample, baseline []Sample, t Thresholds) (Finding, bool) {
+	rate, ok := current.failureRate()
+	if !ok || current.Completed+current.Failed < t.MinTasks {
+		return Finding{}, false
+	}
+	var rates []float64
+	for _, s := range baseline {
+		if r, ok := s.failureRate(); ok {
+			rates = append(rates, r)
+		}
+	}
+	if len(rates) < MinBaselineSamples {
+		return Finding{}, false
+	}
+
+	median, mad := medianMAD(rates)
+	// A perfectly stable baseline has no spread; assume at least 2 points of noise
+	threshold := math.Max(median+t.FailureRateMADs*math.Max(madScale*mad, 0.02), t.MinFailureRate)
+	if rate <= threshold {
+		return Finding{}, false
+	}
+	return Finding{
+		Kind:      KindFailureRate,
+		Current:   rate,
+		Baseline:  median,
+		Threshold: threshold,
+		Samples:   len(rates),
+		Message: fmt.Sprintf("failure rate %.1f%% (%d of %d tasks failed), usually %.1f%%",
+			rate*100, current.Failed, current.Completed+current.Failed, median*100),
+	}, true
+}
+
+func detectGPUHours(current Sample, baseline []Sample, t Thresholds) (Finding, bool) {
+	if current.GPUHours < t.MinGPUHours || len(baseline) < MinBaselineSamples {
+		return Finding{}, false
+	}
+	values := make([]float64, 0, len(baseline))
+	for _, s := range baseline {
+		values = append(values, s.GPUHours)
+	}
+	median, _ := medianMAD(values)
+	if median <= 0 {
+		return Finding{}, false
+	}
+
+	threshold := t.GPUHoursFactor * median
+	if current.GPUHours < threshold {
+		return Finding{}, false
+	}
+	return Finding{
+		Kind:      KindGPUHours,
+		Current:   current.GPUHours,
+		Baseline:  median,
+		Threshold: threshold,
+		Samples:   len(values),
+		Message: fmt.Sprintf("using %.2f GPU hours per hour, %.1fx the usual %.2f",
+			current.GPUHours, current.GPUHours/median, median),
+	}, true
+}
+
+func detectQueueLatency(current Sample, baseline []Sample, t Thresholds) (Finding, bool) {
+	if current.Completed+current.Failed == 0 || current.QueueWaitMs < t.MinQueueLatencyMs {
+		return Finding{}, false
+	}
+	var values []float64
+	for _, s := range baseline {
+		if s.Completed+s.Failed > 0 {
+			values = append(values, s.QueueWaitMs)
+		}
+	}
+	if len(values) < MinBaselineSamples {
+		return Finding{}, false
+	}
+
+	median, _ := medianMAD(values)
+	threshold := math.Max(t.QueueLatencyFactor*median, t.MinQueueLatencyMs)
+	if current.QueueWaitMs < threshold {
+		return Finding{}, false
+	}
+	return Finding{
+		Kind:      KindQueueLatency,
+		Current:   current.QueueWaitMs,
+		Baseline:  median,
+		Threshold: threshold,
+		Samples:   len(values),
+		Message: fmt.Sprintf("average queue wait %.1fs, usually %.1fs",
+			current.QueueWaitMs/1000, median/1000),
+	}, true
+}
+
+// medianMAD returns the median and the median absolute deviation of values (not empty)
+func medianMAD(values []float64) (float64, float64) {
+	m := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - m)
+	}
+	return m, median(deviations)
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
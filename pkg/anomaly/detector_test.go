@@ -0,0 +1,85 @@
+package anomaly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steadyBaseline returns a week of hourly samples around 2% failures, 4 GPU hours and 2s queue wait
+func steadyBaseline() []Sample {
+	var baseline []Sample
+	for i := 0; i < 168; i++ {
+		baseline = append(baseline, Sample{
+			Completed:   98 + i%3,
+			Failed:      2 + i%2,
+			GPUHours:    4 + float64(i%5)/10,
+			QueueWaitMs: 2000 + float64(i%7)*100,
+		})
+	}
+	// A past incident does not move a median-based baseline
+	baseline[10] = Sample{Completed: 10, Failed: 90, GPUHours: 40, QueueWaitMs: 600000}
+	return baseline
+}
+
+func TestDetect(t *testing.T) {
+	normal, ok := ThresholdsFor(SensitivityNormal)
+	require.True(t, ok)
+
+	tests := []struct {
+		name    string
+		current Sample
+		want    []Kind
+	}{
+		{"usual traffic", Sample{Completed: 95, Failed: 3, GPUHours: 4.5, QueueWaitMs: 2500}, nil},
+		{"failure rate spike", Sample{Completed: 60, Failed: 40, GPUHours: 4, QueueWaitMs: 2000}, []Kind{KindFailureRate}},
+		{"too few tasks to judge failures", Sample{Completed: 5, Failed: 5, GPUHours: 4, QueueWaitMs: 2000}, nil},
+		{"GPU hours 3x baseline", Sample{Completed: 100, Failed: 2, GPUHours: 13, QueueWaitMs: 2000}, []Kind{KindGPUHours}},
+		{"queue latency runaway", Sample{Completed: 100, Failed: 2, GPUHours: 4, QueueWaitMs: 45000}, []Kind{KindQueueLatency}},
+		{"everything at once", Sample{Completed: 50, Failed: 50, GPUHours: 20, QueueWaitMs: 60000}, []Kind{KindFailureRate, KindGPUHours, KindQueueLatency}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kinds []Kind
+			for _, f := range Detect(tt.current, steadyBaseline(), normal) {
+				kinds = append(kinds, f.Kind)
+				assert.Greater(t, f.Current, f.Threshold)
+				assert.NotEmpty(t, f.Message)
+			}
+			assert.Equal(t, tt.want, kinds)
+		})
+	}
+}
+
+func TestDetect_Sensitivity(t *testing.T) {
+	current := Sample{Completed: 100, Failed: 2, GPUHours: 10, QueueWaitMs: 2000}
+
+	high, _ := ThresholdsFor(SensitivityHigh)
+	low, _ := ThresholdsFor(SensitivityLow)
+	assert.Len(t, Detect(current, steadyBaseline(), high), 1, "2.4x baseline exceeds the high sensitivity factor")
+	assert.Empty(t, Detect(current, steadyBaseline(), low))
+
+	_, ok := ThresholdsFor(SensitivityOff)
+	assert.False(t, ok)
+}
+
+func TestDetect_ShortHistory(t *testing.T) {
+	normal, _ := ThresholdsFor(SensitivityNormal)
+	baseline := steadyBaseline()[:MinBaselineSamples-1]
+
+	assert.Empty(t, Detect(Sample{Completed: 50, Failed: 50, GPUHours: 20, QueueWaitMs: 60000}, baseline, normal))
+}
+
+func TestParseSensitivity(t *testing.T) {
+	s, err := ParseSensitivity("")
+	require.NoError(t, err)
+	assert.Equal(t, SensitivityNormal, s)
+
+	s, err = ParseSensitivity("off")
+	require.NoError(t, err)
+	assert.Equal(t, SensitivityOff, s)
+
+	_, err = ParseSensitivity("paranoid")
+	assert.Error(t, err)
+}
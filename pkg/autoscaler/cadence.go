@@ -0,0 +1,134 @@
+package autoscaler
+
+import (
+	"sync"
+	"time"
+)
+
+// MinEvaluationInterval 单个 endpoint 允许的最短评估间隔（秒）
+const MinEvaluationInterval = 2
+
+const (
+	// cadenceTick 快速通道检查到期 endpoint 的频率
+	cadenceTick = time.Second
+	// cadenceSlack 容忍控制循环的调度抖动，避免刚好差一点而错过一整个周期
+	cadenceSlack = 500 * time.Millisecond
+)
+
+// EffectiveInterval 返回 endpoint 的实际评估间隔：未单独配置时使用全局间隔
+func EffectiveInterval(ep *EndpointConfig, global time.Duration) time.Duration {
+	if ep.EvaluationInterval <= 0 {
+		return global
+	}
+	return time.Duration(max(ep.EvaluationInterval, MinEvaluationInterval)) * time.Second
+}
+
+// cadence 记录每个 endpoint 的评估间隔和上次评估时间。
+// 间隔短于全局间隔的 endpoint 由快速通道按时触发定向评估；
+// 间隔长于全局间隔的 endpoint 在全量评估中跳过，直到到期。
+type cadence struct {
+	mu        sync.Mutex
+	intervals map[string]time.Duration // 单独配置了间隔的 endpoint（取自最近一次指标收集）
+	evaluated map[string]time.Time
+}
+
+func newCadence() *cadence {
+	return &cadence{
+		intervals: make(map[string]time.Duration),
+		evaluated: make(map[string]time.Time),
+	}
+}
+
+// observe 根据最近收集的 endpoint 更新各自的评估间隔
+func (c *cadence) observe(endpoints []*EndpointConfig, global time.Duration) {
+	intervals := make(map[string]time.Duration)
+	for _, ep := range endpoints {
+		if interval := EffectiveInterval(ep, global); interval != global {
+			intervals[ep.Name] = interval
+		}
+	}
+	c.mu.Lock()
+	c.intervals = intervals
+	c.mu.Unlock()
+}
+
+// due 判断 endpoint 是否到了评估时间
+func (c *cadence) due(ep *EndpointConfig, global time.Duration, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last, ok := c.evaluated[ep.Name]
+	return !ok || now.Sub(last)+cadenceSlack >= EffectiveInterval(ep, global)
+}
+
+// markEvaluated 记录 endpoint 的评估时间
+func (c *cadence) markEvaluated(endpoints []*EndpointConfig, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ep := range endpoints {
+		c.evaluated[ep.Name] = now
+	}
+}
+
+// lastEvaluated 返回 endpoint 的上次评估时间
+func (c *cadence) lastEvaluated(name string) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evaluated[name]
+}
+
+// dueFast 返回间隔短于全局间隔且已到期的 endpoint
+func (c *cadence) dueFast(global time.Duration, now time.Time) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var due []string
+	for name, interval := range c.intervals {
+		if interval >= global {
+			continue
+		}
+		if last, ok := c.evaluated[name]; !ok || now.Sub(last)+cadenceSlack >= interval {
+			due = append(due, name)
+		}
+	}
+	return due
+}
+
+// providerLimiter 令牌桶，限制自动扩缩容对 provider 的调用频率，
+// 避免秒级评估的 endpoint 压垮 K8s / Novita API
+type providerLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// newProviderLimiter 创建限流器；qps <= 0 表示不限流（返回 nil）
+func newProviderLimiter(qps float64, burst int) *providerLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &providerLimiter{rate: qps, burst: float64(burst), tokens: float64(burst), now: time.Now}
+}
+
+// Allow 消耗一个令牌；nil 限流器总是放行
+func (l *providerLimiter) Allow() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
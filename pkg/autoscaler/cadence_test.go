@@ -0,0 +1,69 @@
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectiveInterval(t *testing.T) {
+	global := 30 * time.Second
+	assert.Equal(t, global, EffectiveInterval(&EndpointConfig{}, global))
+	assert.Equal(t, 5*time.Second, EffectiveInterval(&EndpointConfig{EvaluationInterval: 5}, global))
+	assert.Equal(t, 300*time.Second, EffectiveInterval(&EndpointConfig{EvaluationInterval: 300}, global))
+	// Below the minimum is raised to it
+	assert.Equal(t, MinEvaluationInterval*time.Second, EffectiveInterval(&EndpointConfig{EvaluationInterval: 1}, global))
+}
+
+func TestCadence(t *testing.T) {
+	global := 30 * time.Second
+	now := time.Now()
+	interactive := &EndpointConfig{Name: "chat", EvaluationInterval: 5}
+	batch := &EndpointConfig{Name: "batch", EvaluationInterval: 300}
+	standard := &EndpointConfig{Name: "standard"}
+
+	c := newCadence()
+	c.observe([]*EndpointConfig{interactive, batch, standard}, global)
+
+	// Never evaluated: everything is due
+	assert.True(t, c.due(batch, global, now))
+	assert.ElementsMatch(t, []string{"chat"}, c.dueFast(global, now))
+
+	c.markEvaluated([]*EndpointConfig{interactive, batch, standard}, now)
+	assert.Empty(t, c.dueFast(global, now.Add(2*time.Second)))
+	assert.Equal(t, []string{"chat"}, c.dueFast(global, now.Add(5*time.Second)))
+
+	// The batch endpoint is skipped by full runs until its interval passed
+	assert.True(t, c.due(standard, global, now.Add(global)))
+	assert.False(t, c.due(batch, global, now.Add(global)))
+	assert.True(t, c.due(batch, global, now.Add(300*time.Second)))
+	assert.Equal(t, now, c.lastEvaluated("batch"))
+}
+
+func TestProviderLimiter(t *testing.T) {
+	now := time.Now()
+	l := newProviderLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, l.Allow(), "burst call %d", i)
+	}
+	assert.False(t, l.Allow())
+
+	// Half a second refills one call at 2 per second
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, l.Allow())
+	assert.False(t, l.Allow())
+
+	// Refills cap at the burst
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		assert.True(t, l.Allow())
+	}
+	assert.False(t, l.Allow())
+
+	var unlimited *providerLimiter
+	assert.Nil(t, newProviderLimiter(0, 10))
+	assert.True(t, unlimited.Allow())
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"time"
 
+	"waverless/pkg/circuit"
 	"waverless/pkg/logger"
 )
 
@@ -88,8 +89,22 @@ func (e *DecisionEngine) shouldScaleUp(ctx context.Context, ep *EndpointConfig,
 		return nil // Already at max replicas
 	}
 
+	// A tripped circuit breaker holds scale-up: new workers would fail like the current ones.
+	// While half-open the endpoint may get one replica to run the probe.
+	switch ep.CircuitState {
+	case circuit.StateOpen:
+		logger.DebugCtx(ctx, "endpoint %s: skip scale up, circuit breaker is open", ep.Name)
+		return nil
+	case circuit.StateHalfOpen:
+		if currentReplicas > 0 {
+			logger.DebugCtx(ctx, "endpoint %s: skip scale up, circuit breaker is half-open", ep.Name)
+			return nil
+		}
+	}
+
 	// 2. Check cooldown time (check first to avoid frequent scaling)
-	if !ep.LastScaleTime.IsZero() {
+	// An endpoint scaled to zero has no capacity to protect: tasks waiting on it wake it at once
+	if !ep.LastScaleTime.IsZero() && currentReplicas > 0 {
 		cooldown := time.Duration(ep.ScaleUpCooldown) * time.Second
 		elapsed := time.Since(ep.LastScaleTime)
 		if elapsed < cooldown {
@@ -104,6 +119,29 @@ func (e *DecisionEngine) shouldScaleUp(ctx context.Context, ep *EndpointConfig,
 	// Consider both running and queued tasks
 	totalTasks := ep.PendingTasks + ep.RunningTasks
 	targetReplicas := int(math.Ceil(float64(totalTasks)))
+	reason := fmt.Sprintf("queue length %d exceeds threshold %d", ep.PendingTasks, ep.ScaleUpThreshold)
+
+	// Workers that batch internally may hold a backlog the queue doesn't show: also size by the custom metric
+	if metricReplicas := customMetricReplicas(ep); metricReplicas > targetReplicas {
+		logger.InfoCtx(ctx, "endpoint %s: custom metric %s average %.2f over %d workers (target %.2f) requires %d replicas",
+			ep.Name, customMetricLabel(ep), ep.CustomMetricValue, ep.CustomMetricCount, ep.CustomMetricTarget, metricReplicas)
+		targetReplicas = metricReplicas
+		reason = fmt.Sprintf("custom metric %s average %.2f exceeds target %.2f", customMetricLabel(ep), ep.CustomMetricValue, ep.CustomMetricTarget)
+	}
+
+	// HPA-style metric sources (GPU utilization, latency, Prometheus query): the target is the max of all of them
+	if sourceReplicas, sourceReason := metricSourcesReplicas(ep); sourceReplicas > targetReplicas {
+		logger.InfoCtx(ctx, "endpoint %s: %s requires %d replicas", ep.Name, sourceReason, sourceReplicas)
+		targetReplicas = sourceReplicas
+		reason = sourceReason + " requires more replicas"
+	}
+
+	// Start workers ahead of a growing queue when they would otherwise be ready after the SLO is breached
+	if leadReplicas, leadReason := leadTimeReplicas(ep, time.Now()); leadReplicas > targetReplicas {
+		logger.InfoCtx(ctx, "endpoint %s: %s, requires %d replicas", ep.Name, leadReason, leadReplicas)
+		targetReplicas = leadReplicas
+		reason = leadReason
+	}
 
 	// 🔍 DEBUG: Log detailed scale-up decision calculation
 	logger.InfoCtx(ctx, "endpoint %s: scale-up calculation - pending=%d, running=%d, totalTasks=%d, currentReplicas(desired)=%d, actualReplicas(ready)=%d, targetReplicas(calculated)=%d",
@@ -137,6 +175,10 @@ func (e *DecisionEngine) shouldScaleUp(ctx context.Context, ep *EndpointConfig,
 			ep.Name, currentReplicas, ep.MinReplicas, targetReplicas)
 	}
 
+	if ep.CircuitState == circuit.StateHalfOpen {
+		targetReplicas = min(targetReplicas, 1)
+	}
+
 	scaleAmount := targetReplicas - currentReplicas
 	logger.InfoCtx(ctx, "endpoint %s: final scale decision - targetReplicas=%d, scaleAmount=%d",
 		ep.Name, targetReplicas, scaleAmount)
@@ -175,7 +217,7 @@ func (e *DecisionEngine) shouldScaleUp(ctx context.Context, ep *EndpointConfig,
 		Priority:         effectivePriority,
 		BasePriority:     ep.Priority,
 		QueueLength:      ep.PendingTasks,
-		Reason:           fmt.Sprintf("queue length %d exceeds threshold %d", ep.PendingTasks, ep.ScaleUpThreshold),
+		Reason:           reason,
 		Approved:         !blocked,
 		Blocked:          blocked,
 		RequiredResource: *requiredResources,
@@ -204,6 +246,11 @@ func (e *DecisionEngine) fairAllocation(ctx context.Context, decisions []*ScaleD
 	remainingDecisions := make([]*ScaleDecision, 0)
 
 	for _, decision := range decisions {
+		// Scale-ups driven by the custom metric alone get no minimal guarantee, they compete by priority
+		if decision.ScaleAmount > 0 && decision.QueueLength == 0 {
+			remainingDecisions = append(remainingDecisions, decision)
+			continue
+		}
 		if decision.ScaleAmount > 0 && decision.QueueLength > 0 {
 			// Calculate resources needed for 1 replica
 			singleReplica, err := e.resourceCalculator.CalculateEndpointResource(ctx, &EndpointConfig{Name: decision.Endpoint}, 1)
@@ -335,9 +382,26 @@ func (e *DecisionEngine) shouldScaleDown(ctx context.Context, ep *EndpointConfig
 		minRequiredReplicas += 1
 	}
 
+	// Keep enough replicas to hold the custom metric at its target
+	metricReplicas := customMetricReplicas(ep)
+	if metricReplicas > minRequiredReplicas {
+		minRequiredReplicas = metricReplicas
+	}
+	// ... and every metric source at its target
+	sourceReplicas, sourceReason := metricSourcesReplicas(ep)
+	if sourceReplicas > minRequiredReplicas {
+		minRequiredReplicas = sourceReplicas
+	}
+
 	// If current replicas <= required replicas, don't scale down
 	if currentReplicas <= minRequiredReplicas {
-		if ep.RunningTasks > 0 {
+		if metricReplicas >= currentReplicas {
+			logger.DebugCtx(ctx, "endpoint %s: skip scale down, custom metric %s average %.2f (target %.2f) needs %d replicas (current=%d)",
+				ep.Name, customMetricLabel(ep), ep.CustomMetricValue, ep.CustomMetricTarget, metricReplicas, currentReplicas)
+		} else if sourceReplicas >= currentReplicas {
+			logger.DebugCtx(ctx, "endpoint %s: skip scale down, %s needs %d replicas (current=%d)",
+				ep.Name, sourceReason, sourceReplicas, currentReplicas)
+		} else if ep.RunningTasks > 0 {
 			logger.DebugCtx(ctx, "endpoint %s: skip scale down, need at least %d replicas for %d running tasks (current=%d)",
 				ep.Name, minRequiredReplicas, ep.RunningTasks, currentReplicas)
 		}
@@ -432,6 +496,25 @@ func (e *DecisionEngine) shouldScaleDown(ctx context.Context, ep *EndpointConfig
 	}
 }
 
+// customMetricReplicas returns the replicas needed to bring the per-worker custom metric
+// average down to its target, or 0 if the endpoint doesn't scale on a custom metric
+// or no worker reported it recently
+func customMetricReplicas(ep *EndpointConfig) int {
+	if ep.CustomMetricTarget <= 0 || ep.CustomMetricCount == 0 {
+		return 0
+	}
+	total := ep.CustomMetricValue * float64(ep.CustomMetricCount)
+	return int(math.Ceil(total / ep.CustomMetricTarget))
+}
+
+// customMetricLabel names the custom metric in logs and decision reasons
+func customMetricLabel(ep *EndpointConfig) string {
+	if ep.CustomMetricName != "" {
+		return ep.CustomMetricName
+	}
+	return "custom_metric"
+}
+
 // considerPreemption considers preemptive scheduling
 func (e *DecisionEngine) considerPreemption(ctx context.Context, blockedDecisions []*ScaleDecision, allEndpoints []*EndpointConfig, availableResources *Resources) []*ScaleDecision {
 	preemptionDecisions := make([]*ScaleDecision, 0)
@@ -0,0 +1,94 @@
+package autoscaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"waverless/internal/model"
+	"waverless/pkg/circuit"
+)
+
+func TestCustomMetricReplicas(t *testing.T) {
+	tests := []struct {
+		name string
+		ep   EndpointConfig
+		want int
+	}{
+		{"disabled", EndpointConfig{CustomMetricValue: 40, CustomMetricCount: 2}, 0},
+		{"no reports", EndpointConfig{CustomMetricTarget: 10}, 0},
+		{"at target", EndpointConfig{CustomMetricTarget: 10, CustomMetricValue: 10, CustomMetricCount: 3}, 3},
+		{"above target", EndpointConfig{CustomMetricTarget: 10, CustomMetricValue: 25, CustomMetricCount: 2}, 5},
+		{"below target", EndpointConfig{CustomMetricTarget: 10, CustomMetricValue: 4, CustomMetricCount: 4}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, customMetricReplicas(&tt.ep))
+		})
+	}
+}
+
+func TestAverageCustomMetric(t *testing.T) {
+	now := time.Now()
+	value := func(v float64) *float64 { return &v }
+	workers := []*model.Worker{
+		{ID: "online", Status: model.WorkerStatusOnline, CustomMetric: value(12), CustomMetricAt: now},
+		{ID: "busy", Status: model.WorkerStatusBusy, CustomMetric: value(8), CustomMetricAt: now.Add(-time.Minute)},
+		{ID: "stale", Status: model.WorkerStatusOnline, CustomMetric: value(100), CustomMetricAt: now.Add(-10 * time.Minute)},
+		{ID: "draining", Status: model.WorkerStatusDraining, CustomMetric: value(100), CustomMetricAt: now},
+		{ID: "silent", Status: model.WorkerStatusOnline},
+	}
+
+	avg, count := averageCustomMetric(workers, now)
+	assert.Equal(t, 10.0, avg)
+	assert.Equal(t, 2, count)
+
+	avg, count = averageCustomMetric(nil, now)
+	assert.Zero(t, avg)
+	assert.Zero(t, count)
+}
+
+func TestShouldScaleDown_HoldsCustomMetricReplicas(t *testing.T) {
+	engine := NewDecisionEngine(&Config{}, nil)
+	ep := &EndpointConfig{
+		Name:               "batcher",
+		MaxReplicas:        10,
+		Replicas:           4,
+		ActualReplicas:     4,
+		ScaleDownIdleTime:  60,
+		CustomMetricName:   "batch_queue",
+		CustomMetricTarget: 10,
+		CustomMetricValue:  9,
+		CustomMetricCount:  4,
+		LastTaskTime:       time.Now().Add(-time.Hour),
+	}
+
+	// The queue is empty but the workers' internal backlog still needs 4 replicas
+	assert.Nil(t, engine.shouldScaleDown(context.Background(), ep))
+
+	// Backlog drained: scale down to what the metric requires
+	ep.CustomMetricValue = 5
+	decision := engine.shouldScaleDown(context.Background(), ep)
+	if assert.NotNil(t, decision) {
+		assert.Equal(t, 2, decision.DesiredReplicas)
+	}
+}
+
+func TestShouldScaleUp_HeldByCircuitBreaker(t *testing.T) {
+	engine := NewDecisionEngine(&Config{}, nil)
+	ep := &EndpointConfig{
+		Name:             "crashing",
+		MaxReplicas:      10,
+		Replicas:         2,
+		PendingTasks:     50,
+		ScaleUpThreshold: 1,
+		CircuitState:     circuit.StateOpen,
+	}
+	assert.Nil(t, engine.shouldScaleUp(context.Background(), ep, &Resources{}))
+
+	// Half-open: the probe runs on the replicas there are
+	ep.CircuitState = circuit.StateHalfOpen
+	assert.Nil(t, engine.shouldScaleUp(context.Background(), ep, &Resources{}))
+}
@@ -18,10 +18,17 @@ type Executor struct {
 	deploymentProvider interfaces.DeploymentProvider
 	endpointService    *endpointsvc.Service
 	scalingEventRepo   *mysql.ScalingEventRepository
-	workerLister       interfaces.WorkerLister    // For smart scale-down
-	taskRepo           *mysql.TaskRepository      // For checking running tasks in database
-	k8sProvider        *k8s.K8sDeploymentProvider // For pod draining & deletion
-	endpointRepo       *mysql.EndpointRepository  // For checking endpoint health status
+	workerLister       interfaces.WorkerLister   // For smart scale-down
+	taskRepo           *mysql.TaskRepository     // For checking running tasks in database
+	endpointRepo       *mysql.EndpointRepository // For checking endpoint health status
+	limiter            *providerLimiter          // Caps provider calls (nil = unlimited)
+}
+
+// k8sProviderFor returns the K8s provider serving endpoint for pod draining & deletion,
+// nil if the endpoint runs on another provider
+func (e *Executor) k8sProviderFor(ctx context.Context, endpoint string) *k8s.K8sDeploymentProvider {
+	k8sProvider, _ := interfaces.ResolveProvider(ctx, e.deploymentProvider, endpoint, "").(*k8s.K8sDeploymentProvider)
+	return k8sProvider
 }
 
 // NewExecutor creates executor
@@ -33,16 +40,12 @@ func NewExecutor(
 	taskRepo *mysql.TaskRepository,
 	endpointRepo *mysql.EndpointRepository,
 ) *Executor {
-	// Try to get K8sDeploymentProvider for pod-level operations
-	k8sProvider, _ := deploymentProvider.(*k8s.K8sDeploymentProvider)
-
 	return &Executor{
 		deploymentProvider: deploymentProvider,
 		endpointService:    endpointService,
 		scalingEventRepo:   scalingEventRepo,
 		workerLister:       workerLister,
 		taskRepo:           taskRepo,
-		k8sProvider:        k8sProvider,
 		endpointRepo:       endpointRepo,
 	}
 }
@@ -70,6 +73,11 @@ func (e *Executor) ExecuteDecisions(ctx context.Context, decisions []*ScaleDecis
 			continue
 		}
 
+		if decision.ScaleAmount != 0 && !e.limiter.Allow() {
+			logger.WarnCtx(ctx, "provider rate limit reached, scaling of %s deferred to its next evaluation", decision.Endpoint)
+			continue
+		}
+
 		if decision.ScaleAmount > 0 {
 			// Scale up
 			if err := e.scaleUp(ctx, decision); err != nil {
@@ -124,16 +132,8 @@ func (e *Executor) scaleUp(ctx context.Context, decision *ScaleDecision) error {
 	logger.InfoCtx(ctx, "scaling up %s from %d to %d replicas (reason: %s)",
 		decision.Endpoint, decision.CurrentReplicas, decision.DesiredReplicas, decision.Reason)
 
-	// Update K8s Deployment
-	req := &interfaces.UpdateDeploymentRequest{
-		Endpoint: decision.Endpoint,
-		Replicas: &decision.DesiredReplicas,
-	}
-	if _, err := e.deploymentProvider.UpdateDeployment(ctx, req); err != nil {
-		return fmt.Errorf("failed to update deployment: %w", err)
-	}
-
-	// Update metadata
+	// Record the desired count before touching the deployment, so a failed or lost
+	// update is converged by the replica reconciler instead of being forgotten
 	meta, err := e.endpointService.GetEndpoint(ctx, decision.Endpoint)
 	if err != nil {
 		logger.WarnCtx(ctx, "failed to get endpoint metadata: %v", err)
@@ -153,6 +153,15 @@ func (e *Executor) scaleUp(ctx context.Context, decision *ScaleDecision) error {
 		}
 	}
 
+	// Update K8s Deployment
+	req := &interfaces.UpdateDeploymentRequest{
+		Endpoint: decision.Endpoint,
+		Replicas: &decision.DesiredReplicas,
+	}
+	if _, err := e.deploymentProvider.UpdateDeployment(ctx, req); err != nil {
+		return fmt.Errorf("failed to update deployment: %w", err)
+	}
+
 	// Record event
 	action := "scale_up"
 	if len(decision.PreemptedFrom) > 0 {
@@ -188,13 +197,13 @@ func (e *Executor) scaleDown(ctx context.Context, decision *ScaleDecision) error
 		return fmt.Errorf("failed to get workers: %w", err)
 	}
 
-	// Step 2: Find idle workers
+	// Step 2: Find idle workers (quarantined workers are kept for inspection)
 	// Priority: select the worker with the longest idle time (earliest LastTaskTime)
 	var idleWorker *model.Worker
 	var oldestIdleTime time.Time
 
 	for _, w := range endpointWorkers {
-		if w.CurrentJobs == 0 {
+		if w.CurrentJobs == 0 && w.Quarantine == nil {
 			// If this is the first idle worker, or has been idle longer
 			if idleWorker == nil {
 				idleWorker = w
@@ -256,16 +265,16 @@ func (e *Executor) scaleDown(ctx context.Context, decision *ScaleDecision) error
 		targetPodName, decision.Endpoint, idleDurationMsg)
 
 	// Step 5: Mark Pod as draining (prevent pulling new tasks) + set deletion priority
-	if e.k8sProvider != nil {
+	if k8sProvider := e.k8sProviderFor(ctx, decision.Endpoint); k8sProvider != nil {
 		// 5.1: Mark draining label (business logic: prevent worker from pulling new tasks)
-		if err := e.k8sProvider.MarkPodDraining(ctx, targetPodName); err != nil {
+		if err := k8sProvider.MarkPodDraining(ctx, targetPodName); err != nil {
 			logger.WarnCtx(ctx, "failed to mark pod draining: %v, continue anyway", err)
 		} else {
 			logger.InfoCtx(ctx, "marked pod as draining: %s", targetPodName)
 		}
 
 		// 5.2: Set Pod Deletion Cost (K8s logic: make Deployment controller prioritize deleting this Pod)
-		if err := e.k8sProvider.SetPodDeletionCost(ctx, targetPodName, -1000); err != nil {
+		if err := k8sProvider.SetPodDeletionCost(ctx, targetPodName, -1000); err != nil {
 			logger.WarnCtx(ctx, "failed to set pod deletion cost: %v, continue anyway", err)
 		} else {
 			logger.InfoCtx(ctx, "set pod deletion cost to -1000: %s", targetPodName)
@@ -349,8 +358,8 @@ func (e *Executor) executeScaleDownConfirmed(ctx context.Context, decision *Scal
 
 	// Step 1: Set Pod Deletion Cost, make K8s prioritize deleting this Pod
 	// Use -1000 to ensure this Pod has lowest priority (deleted first)
-	if e.k8sProvider != nil {
-		if err := e.k8sProvider.SetPodDeletionCost(ctx, podName, -1000); err != nil {
+	if k8sProvider := e.k8sProviderFor(ctx, decision.Endpoint); k8sProvider != nil {
+		if err := k8sProvider.SetPodDeletionCost(ctx, podName, -1000); err != nil {
 			logger.WarnCtx(ctx, "failed to set pod deletion cost for %s: %v", podName, err)
 			// Not a fatal error, continue execution
 		} else {
@@ -414,8 +423,8 @@ func (e *Executor) revertScaleDown(ctx context.Context, decision *ScaleDecision,
 	// 🔥 CRITICAL: Must restore Pod Deletion Cost to 0
 	// Reason: If not restored, this Pod will keep deletion cost = -1000
 	// Next scale-down, if another worker is selected, K8s might mistakenly delete this Pod with tasks!
-	if e.k8sProvider != nil {
-		if err := e.k8sProvider.SetPodDeletionCost(ctx, podName, 0); err != nil {
+	if k8sProvider := e.k8sProviderFor(ctx, decision.Endpoint); k8sProvider != nil {
+		if err := k8sProvider.SetPodDeletionCost(ctx, podName, 0); err != nil {
 			logger.ErrorCtx(ctx, "CRITICAL: failed to reset pod deletion cost for %s: %v", podName, err)
 			// This is a serious error, but we cannot block the process
 		} else {
@@ -463,7 +472,7 @@ func generateEventID() string {
 func (e *Executor) isOrphanedEndpoint(ctx context.Context, decision *ScaleDecision) bool {
 	// Only apply this logic for K8s provider
 	// Novita and other providers have different behaviors
-	if e.k8sProvider == nil {
+	if e.k8sProviderFor(ctx, decision.Endpoint) == nil {
 		logger.DebugCtx(ctx, "endpoint %s: skipping orphan check (not K8s provider)", decision.Endpoint)
 		return false
 	}
@@ -0,0 +1,136 @@
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"waverless/pkg/logger"
+)
+
+// GroupBudget 端点组共享的容量池（0 表示不限制）
+type GroupBudget struct {
+	Name        string `json:"name"`
+	MaxReplicas int    `json:"maxReplicas"`
+	MaxGPUCount int    `json:"maxGpuCount"`
+}
+
+// groupUsage 组内已占用的副本数和 GPU 数
+type groupUsage struct {
+	replicas int
+	gpus     int
+}
+
+// ApplyGroupBudgets caps scale-ups of group members so a group never exceeds its shared budget.
+// Scale-downs are applied first (they free budget), then scale-ups are granted by effective
+// priority and queue length; a partial grant shrinks the decision, none blocks it.
+// endpoints must include every member of the affected groups (not only the evaluated targets)
+// so the current usage of the group is complete.
+func (e *DecisionEngine) ApplyGroupBudgets(ctx context.Context, decisions []*ScaleDecision, endpoints []*EndpointConfig, budgets map[string]*GroupBudget) []*ScaleDecision {
+	if len(budgets) == 0 {
+		return decisions
+	}
+
+	gpusPerReplica := make(map[string]int)
+	for _, ep := range endpoints {
+		if _, ok := budgets[ep.GroupName]; !ok || e.resourceCalculator == nil {
+			continue
+		}
+		perReplica, err := e.resourceCalculator.CalculateEndpointResource(ctx, ep, 1)
+		if err != nil {
+			logger.WarnCtx(ctx, "group %s: failed to calculate resources of member %s, counting replicas only: %v", ep.GroupName, ep.Name, err)
+			continue
+		}
+		gpusPerReplica[ep.Name] = perReplica.GPUCount
+	}
+
+	return applyGroupBudgets(ctx, decisions, endpoints, budgets, gpusPerReplica)
+}
+
+// applyGroupBudgets enforces group budgets given the GPUs each member replica needs
+func applyGroupBudgets(ctx context.Context, decisions []*ScaleDecision, endpoints []*EndpointConfig, budgets map[string]*GroupBudget, gpusPerReplica map[string]int) []*ScaleDecision {
+	groupOf := make(map[string]string)
+	usage := make(map[string]*groupUsage)
+	for _, ep := range endpoints {
+		if _, ok := budgets[ep.GroupName]; !ok {
+			continue
+		}
+		groupOf[ep.Name] = ep.GroupName
+		u := usage[ep.GroupName]
+		if u == nil {
+			u = &groupUsage{}
+			usage[ep.GroupName] = u
+		}
+		u.replicas += ep.Replicas
+		u.gpus += ep.Replicas * gpusPerReplica[ep.Name]
+	}
+
+	scaleUps := make([]*ScaleDecision, 0)
+	for _, d := range decisions {
+		group, ok := groupOf[d.Endpoint]
+		if !ok || !d.Approved {
+			continue
+		}
+		if d.ScaleAmount < 0 {
+			usage[group].replicas += d.ScaleAmount
+			usage[group].gpus += d.ScaleAmount * gpusPerReplica[d.Endpoint]
+		} else if d.ScaleAmount > 0 {
+			scaleUps = append(scaleUps, d)
+		}
+	}
+
+	sort.SliceStable(scaleUps, func(i, j int) bool {
+		if scaleUps[i].Priority != scaleUps[j].Priority {
+			return scaleUps[i].Priority > scaleUps[j].Priority
+		}
+		return scaleUps[i].QueueLength > scaleUps[j].QueueLength
+	})
+
+	for _, d := range scaleUps {
+		group := groupOf[d.Endpoint]
+		budget := budgets[group]
+		u := usage[group]
+
+		granted := d.ScaleAmount
+		if budget.MaxReplicas > 0 {
+			granted = min(granted, budget.MaxReplicas-u.replicas)
+		}
+		if perReplica := gpusPerReplica[d.Endpoint]; budget.MaxGPUCount > 0 && perReplica > 0 {
+			granted = min(granted, (budget.MaxGPUCount-u.gpus)/perReplica)
+		}
+		granted = max(granted, 0)
+
+		if granted == 0 {
+			d.Approved = false
+			d.Blocked = true
+			d.BlockedReason = fmt.Sprintf("endpoint group %s budget exhausted (replicas %d/%d, GPUs %d/%d)",
+				group, u.replicas, budget.MaxReplicas, u.gpus, budget.MaxGPUCount)
+			logger.InfoCtx(ctx, "endpoint %s: scale up blocked, %s", d.Endpoint, d.BlockedReason)
+			continue
+		}
+
+		if granted < d.ScaleAmount {
+			logger.InfoCtx(ctx, "endpoint %s: scale up capped by endpoint group %s budget (%d → %d replicas)",
+				d.Endpoint, group, d.ScaleAmount, granted)
+			scaleResources(&d.RequiredResource, granted, d.ScaleAmount)
+			d.ScaleAmount = granted
+			d.DesiredReplicas = d.CurrentReplicas + granted
+			d.Reason = fmt.Sprintf("%s (capped by endpoint group %s budget)", d.Reason, group)
+		}
+
+		u.replicas += granted
+		u.gpus += granted * gpusPerReplica[d.Endpoint]
+	}
+
+	return decisions
+}
+
+// scaleResources rescales resources computed for `from` replicas to `to` replicas
+func scaleResources(r *Resources, to, from int) {
+	if from <= 0 {
+		return
+	}
+	r.GPUCount = r.GPUCount * to / from
+	r.CPUCores = r.CPUCores * float64(to) / float64(from)
+	r.MemoryGB = r.MemoryGB * float64(to) / float64(from)
+}
@@ -0,0 +1,58 @@
+package autoscaler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyGroupBudgets(t *testing.T) {
+	ctx := context.Background()
+	budgets := map[string]*GroupBudget{"small-models": {Name: "small-models", MaxReplicas: 4, MaxGPUCount: 4}}
+	endpoints := []*EndpointConfig{
+		{Name: "a", GroupName: "small-models", Replicas: 1},
+		{Name: "b", GroupName: "small-models", Replicas: 1},
+		{Name: "c", GroupName: "small-models", Replicas: 1},
+		{Name: "solo", Replicas: 2},
+	}
+	gpus := map[string]int{"a": 1, "b": 1, "c": 1, "solo": 1}
+
+	t.Run("grants by priority and caps the rest", func(t *testing.T) {
+		low := &ScaleDecision{Endpoint: "a", CurrentReplicas: 1, DesiredReplicas: 3, ScaleAmount: 2, Priority: 10, Approved: true, Reason: "queue", RequiredResource: Resources{GPUCount: 2}}
+		high := &ScaleDecision{Endpoint: "b", CurrentReplicas: 1, DesiredReplicas: 2, ScaleAmount: 1, Priority: 50, Approved: true}
+		solo := &ScaleDecision{Endpoint: "solo", CurrentReplicas: 2, DesiredReplicas: 6, ScaleAmount: 4, Approved: true}
+
+		applyGroupBudgets(ctx, []*ScaleDecision{low, high, solo}, endpoints, budgets, gpus)
+
+		assert.True(t, high.Approved)
+		assert.Equal(t, 1, high.ScaleAmount)
+		assert.True(t, low.Blocked)
+		assert.False(t, low.Approved)
+		assert.Contains(t, low.BlockedReason, "small-models")
+		assert.Equal(t, 4, solo.ScaleAmount, "endpoints outside groups are untouched")
+	})
+
+	t.Run("scale-down frees budget for a partial grant", func(t *testing.T) {
+		down := &ScaleDecision{Endpoint: "c", CurrentReplicas: 1, DesiredReplicas: 0, ScaleAmount: -1, Approved: true}
+		up := &ScaleDecision{Endpoint: "a", CurrentReplicas: 1, DesiredReplicas: 4, ScaleAmount: 3, Approved: true, Reason: "queue", RequiredResource: Resources{GPUCount: 3, CPUCores: 6}}
+
+		applyGroupBudgets(ctx, []*ScaleDecision{up, down}, endpoints, budgets, gpus)
+
+		assert.True(t, up.Approved)
+		assert.Equal(t, 2, up.ScaleAmount)
+		assert.Equal(t, 3, up.DesiredReplicas)
+		assert.Equal(t, Resources{GPUCount: 2, CPUCores: 4}, up.RequiredResource)
+		assert.Contains(t, up.Reason, "capped by endpoint group small-models")
+	})
+
+	t.Run("GPU budget limits multi-GPU members", func(t *testing.T) {
+		gpuBudget := map[string]*GroupBudget{"small-models": {Name: "small-models", MaxGPUCount: 5}}
+		up := &ScaleDecision{Endpoint: "a", CurrentReplicas: 1, DesiredReplicas: 3, ScaleAmount: 2, Approved: true}
+
+		applyGroupBudgets(ctx, []*ScaleDecision{up}, endpoints, gpuBudget, map[string]int{"a": 2, "b": 1, "c": 1})
+
+		// used: a=2, b=1, c=1 → 4 of 5 GPUs, not enough for another 2-GPU replica
+		assert.True(t, up.Blocked)
+	})
+}
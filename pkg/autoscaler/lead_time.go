@@ -0,0 +1,30 @@
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// leadTimeReplicas sizes an endpoint for the queue it will have once new workers are ready.
+// When the queue grows and the startup estimate exceeds the headroom left before the oldest
+// task breaches the queue wait SLO, scaling for the current queue is already too late: the
+// target covers the tasks expected to arrive during startup as well. Returns 0 when no lead
+// is needed or the endpoint has no SLO, growth or startup estimate.
+func leadTimeReplicas(ep *EndpointConfig, now time.Time) (int, string) {
+	if ep.QueueWaitSLO <= 0 || ep.QueueGrowthRate <= 0 || ep.StartupEstimate <= 0 {
+		return 0, ""
+	}
+	headroom := time.Duration(ep.QueueWaitSLO) * time.Second
+	if ep.PendingTasks > 0 && !ep.FirstPendingTime.IsZero() {
+		headroom -= now.Sub(ep.FirstPendingTime)
+	}
+	if ep.StartupEstimate < headroom {
+		return 0, ""
+	}
+	arriving := ep.QueueGrowthRate * ep.StartupEstimate.Seconds()
+	replicas := int(math.Ceil(float64(ep.PendingTasks+ep.RunningTasks) + arriving))
+	reason := fmt.Sprintf("queue growing %.2f tasks/s, startup %.0fs exceeds queue wait SLO headroom %.0fs",
+		ep.QueueGrowthRate, ep.StartupEstimate.Seconds(), max(headroom, 0).Seconds())
+	return replicas, reason
+}
@@ -0,0 +1,44 @@
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeadTimeReplicas(t *testing.T) {
+	now := time.Now()
+	ep := &EndpointConfig{
+		Name:             "chat",
+		PendingTasks:     4,
+		RunningTasks:     2,
+		FirstPendingTime: now.Add(-30 * time.Second),
+		QueueWaitSLO:     60,
+		QueueGrowthRate:  0.5,
+		StartupEstimate:  90 * time.Second,
+	}
+
+	// 30s of headroom left, workers take 90s: size for the 45 tasks arriving meanwhile
+	replicas, reason := leadTimeReplicas(ep, now)
+	assert.Equal(t, 51, replicas)
+	assert.Contains(t, reason, "queue wait SLO")
+
+	// Workers are ready before the SLO is breached
+	ep.StartupEstimate = 20 * time.Second
+	replicas, _ = leadTimeReplicas(ep, now)
+	assert.Zero(t, replicas)
+
+	// Shrinking queue, no SLO or no estimate: no lead
+	ep.StartupEstimate = 90 * time.Second
+	for _, mutate := range []func(*EndpointConfig){
+		func(e *EndpointConfig) { e.QueueGrowthRate = -1 },
+		func(e *EndpointConfig) { e.QueueWaitSLO = 0 },
+		func(e *EndpointConfig) { e.StartupEstimate = 0 },
+	} {
+		copied := *ep
+		mutate(&copied)
+		replicas, _ = leadTimeReplicas(&copied, now)
+		assert.Zero(t, replicas)
+	}
+}
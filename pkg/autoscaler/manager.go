@@ -14,7 +14,9 @@ import (
 	"waverless/pkg/deploy/k8s"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/maintenance"
 	"waverless/pkg/store/mysql"
+	mysqlModel "waverless/pkg/store/mysql/model"
 )
 
 // Manager 自动扩缩容管理器
@@ -38,10 +40,15 @@ type Manager struct {
 	scalingEventRepo   *mysql.ScalingEventRepository
 	lastRunTime        time.Time
 	specManager        *k8s.SpecManager
-	redisClient        *redis.Client           // Redis用于全局配置存储
-	configKey          string                  // 全局配置key
-	distributedLock    DistributedLock         // 分布式锁，防止多副本冲突
-	workerLister       interfaces.WorkerLister // For worker queries
+	redisClient        *redis.Client                   // Redis用于全局配置存储
+	configKey          string                          // 全局配置key
+	distributedLock    DistributedLock                 // 分布式锁，防止多副本冲突
+	workerLister       interfaces.WorkerLister         // For worker queries
+	groupRepo          *mysql.EndpointGroupRepository  // 端点组容量池（可选）
+	reservationRepo    *mysql.GPUReservationRepository // GPU 预留（可选）
+	brake              *maintenance.Brake              // 紧急制动（可选）
+	cadence            *cadence                        // 每个 endpoint 的评估节奏
+	startupModel       *StartupModel                   // 启动耗时模型（可选）
 
 	// 缓存集群资源状态，避免每次 API 调用都重新计算
 	cachedClusterMu        sync.RWMutex
@@ -88,6 +95,7 @@ func NewManager(
 		redisClient:        redisClient,
 		configKey:          "autoscaler:global-config",
 		distributedLock:    distributedLock,
+		cadence:            newCadence(),
 	}
 
 	// 从Redis加载全局配置（如果存在）
@@ -150,6 +158,9 @@ func (m *Manager) Stop() error {
 func (m *Manager) controlLoop(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(m.config.Interval) * time.Second)
 	defer ticker.Stop()
+	// 快速通道：评估间隔短于全局间隔的 endpoint 到期后触发定向评估
+	cadenceTicker := time.NewTicker(cadenceTick)
+	defer cadenceTicker.Stop()
 
 	triggerCh := m.triggerCh
 
@@ -165,6 +176,13 @@ func (m *Manager) controlLoop(ctx context.Context) {
 			if err := m.runOnce(ctx); err != nil {
 				logger.ErrorCtx(ctx, "autoscaler run failed: %v", err)
 			}
+		case <-cadenceTicker.C:
+			if !m.IsEnabled() {
+				continue
+			}
+			for _, endpoint := range m.cadence.dueFast(m.globalInterval(), time.Now()) {
+				m.enqueueTarget(endpoint)
+			}
 		case <-triggerCh:
 			if !m.IsEnabled() {
 				continue
@@ -240,7 +258,14 @@ func (m *Manager) collectTargets() []string {
 		targets = append(targets, k)
 		delete(m.pendingTargets, k)
 	}
-	return targets
+	// pendingTargets 已包含队列中的 endpoint，清空队列以免写满
+	for {
+		select {
+		case <-m.targetQueue:
+		default:
+			return targets
+		}
+	}
 }
 
 func (m *Manager) triggerAutoscaler() {
@@ -258,6 +283,10 @@ func (m *Manager) runOnce(ctx context.Context) error {
 	// 🔍 DEBUG: 记录每次 runOnce 调用
 	logger.InfoCtx(ctx, "autoscaler runOnce called at %s", time.Now().Format("2006-01-02 15:04:05.000"))
 
+	if m.braked(ctx) {
+		return nil
+	}
+
 	// 🔒 关键改进：使用分布式锁防止多副本冲突
 	// 尝试获取分布式锁
 	acquired, err := m.distributedLock.TryLock(ctx)
@@ -301,22 +330,32 @@ func (m *Manager) runOnce(ctx context.Context) error {
 		return nil
 	}
 
-	// Filter endpoints based on autoscaler override settings
+	// Filter endpoints based on autoscaler override settings and evaluation cadence
+	now := time.Now()
+	globalInterval := m.globalInterval()
+	m.cadence.observe(endpoints, globalInterval)
 	enabledEndpoints := make([]*EndpointConfig, 0, len(endpoints))
 	for _, ep := range endpoints {
-		if m.shouldProcessEndpoint(ep) {
-			enabledEndpoints = append(enabledEndpoints, ep)
-		} else {
+		if !m.shouldProcessEndpoint(ep) {
 			logger.DebugCtx(ctx, "skipping endpoint %s: autoscaler disabled for this endpoint", ep.Name)
+			continue
 		}
+		if !m.cadence.due(ep, globalInterval, now) {
+			logger.DebugCtx(ctx, "skipping endpoint %s: next evaluation not due (interval %v)", ep.Name, EffectiveInterval(ep, globalInterval))
+			continue
+		}
+		enabledEndpoints = append(enabledEndpoints, ep)
 	}
 
 	if len(enabledEndpoints) == 0 {
 		logger.DebugCtx(ctx, "no enabled endpoints to scale")
 		return nil
 	}
+	m.cadence.markEvaluated(enabledEndpoints, now)
 
 	// Use filtered endpoints for resource calculation and decision making
+	// (group budgets still count replicas of members with autoscaling disabled)
+	allEndpoints := endpoints
 	endpoints = enabledEndpoints
 
 	// Step 2: 计算集群资源使用情况
@@ -344,6 +383,8 @@ func (m *Manager) runOnce(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to make decisions: %w", err)
 	}
+	decisions = m.decisionEngine.ApplyGroupBudgets(ctx, decisions, allEndpoints, m.loadGroupBudgets(ctx))
+	decisions = m.decisionEngine.ApplyReservations(ctx, decisions, allEndpoints, clusterResources, m.loadReservations(ctx))
 
 	if len(decisions) == 0 {
 		logger.DebugCtx(ctx, "no scaling decisions to execute")
@@ -392,6 +433,10 @@ func (m *Manager) runForTargets(ctx context.Context, targets []string) error {
 	}
 	m.mu.Unlock()
 
+	if m.braked(ctx) {
+		return nil
+	}
+
 	logger.DebugCtx(ctx, "autoscaler running targeted evaluation for %d endpoints", len(targets))
 
 	acquired, err := m.distributedLock.TryLock(ctx)
@@ -415,6 +460,7 @@ func (m *Manager) runForTargets(ctx context.Context, targets []string) error {
 	if len(allEndpoints) == 0 {
 		return nil
 	}
+	m.cadence.observe(allEndpoints, m.globalInterval())
 
 	targetSet := make(map[string]struct{}, len(targets))
 	for _, name := range targets {
@@ -436,6 +482,7 @@ func (m *Manager) runForTargets(ctx context.Context, targets []string) error {
 		logger.DebugCtx(ctx, "no matching endpoints for targeted run")
 		return nil
 	}
+	m.cadence.markEvaluated(filtered, time.Now())
 
 	maxResources := &Resources{
 		GPUCount: m.config.MaxGPUCount,
@@ -451,6 +498,8 @@ func (m *Manager) runForTargets(ctx context.Context, targets []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to make decisions: %w", err)
 	}
+	decisions = m.decisionEngine.ApplyGroupBudgets(ctx, decisions, allEndpoints, m.loadGroupBudgets(ctx))
+	decisions = m.decisionEngine.ApplyReservations(ctx, decisions, allEndpoints, clusterResources, m.loadReservations(ctx))
 	if len(decisions) == 0 {
 		logger.DebugCtx(ctx, "no targeted decisions to execute")
 		return nil
@@ -463,6 +512,115 @@ func (m *Manager) runForTargets(ctx context.Context, targets []string) error {
 	return nil
 }
 
+// SetEndpointGroupRepository 注入端点组仓库，启用组级容量池限制
+func (m *Manager) SetEndpointGroupRepository(repo *mysql.EndpointGroupRepository) {
+	m.groupRepo = repo
+}
+
+// loadGroupBudgets 加载端点组容量池；加载失败时不做组级限制，避免阻塞扩缩容
+func (m *Manager) loadGroupBudgets(ctx context.Context) map[string]*GroupBudget {
+	if m.groupRepo == nil {
+		return nil
+	}
+	groups, err := m.groupRepo.List(ctx)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load endpoint groups, group budgets not enforced this cycle: %v", err)
+		return nil
+	}
+	budgets := make(map[string]*GroupBudget, len(groups))
+	for _, g := range groups {
+		budgets[g.Name] = &GroupBudget{Name: g.Name, MaxReplicas: g.MaxReplicas, MaxGPUCount: g.MaxGPUCount}
+	}
+	return budgets
+}
+
+// SetGPUReservationRepository 注入 GPU 预留仓库，启用预留窗口内的容量保护
+func (m *Manager) SetGPUReservationRepository(repo *mysql.GPUReservationRepository) {
+	m.reservationRepo = repo
+}
+
+// SetProviderRateLimit 限制扩缩容对 provider 的调用频率（qps <= 0 不限流）
+func (m *Manager) SetProviderRateLimit(qps float64, burst int) {
+	m.executor.limiter = newProviderLimiter(qps, burst)
+}
+
+// SetStartupModel 注入启动耗时模型，配置了排队 SLO 的 endpoint 据此提前扩容
+func (m *Manager) SetStartupModel(model *StartupModel) {
+	m.startupModel = model
+	m.metricsCollector.SetStartupModel(model)
+}
+
+// SetMetricReader 注册某类指标源（GPU 利用率、延迟、Prometheus 查询）的读取器，
+// 未注册读取器的指标源不参与扩缩容
+func (m *Manager) SetMetricReader(sourceType string, reader MetricReader) {
+	m.metricsCollector.SetMetricReader(sourceType, reader)
+}
+
+// GetStartupTimes 返回各 spec/image 的启动耗时估计
+func (m *Manager) GetStartupTimes(ctx context.Context) []*StartupTime {
+	m.startupModel.RefreshIfStale(ctx)
+	return m.startupModel.List()
+}
+
+// globalInterval 返回全局评估间隔
+func (m *Manager) globalInterval() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Duration(m.config.Interval) * time.Second
+}
+
+// SetEmergencyBrake 注入紧急制动：制动期间跳过所有扩缩容
+func (m *Manager) SetEmergencyBrake(brake *maintenance.Brake) {
+	m.brake = brake
+}
+
+// braked 判断紧急制动是否生效
+func (m *Manager) braked(ctx context.Context) bool {
+	if m.brake == nil {
+		return false
+	}
+	engaged, reason := m.brake.Engaged()
+	if engaged {
+		logger.DebugCtx(ctx, "emergency brake engaged, skipping autoscaling: %s", reason)
+	}
+	return engaged
+}
+
+// loadReservations 加载当前窗口内的预留；加载失败时不做预留保护，避免阻塞扩缩容
+func (m *Manager) loadReservations(ctx context.Context) []*Reservation {
+	if m.reservationRepo == nil {
+		return nil
+	}
+	now := time.Now()
+	rows, err := m.reservationRepo.ListOverlapping(ctx, now, now.Add(time.Millisecond), mysqlModel.GPUReservationScheduled, mysqlModel.GPUReservationActive)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to load GPU reservations, reservations not enforced this cycle: %v", err)
+		return nil
+	}
+	reservations := make([]*Reservation, 0, len(rows))
+	for _, r := range rows {
+		reservations = append(reservations, &Reservation{
+			Name:      r.Name,
+			Endpoint:  r.Endpoint,
+			Replicas:  r.Replicas,
+			GPUCount:  r.GPUCount,
+			Priority:  r.Priority,
+			StartTime: r.StartTime,
+			EndTime:   r.EndTime,
+		})
+	}
+	return reservations
+}
+
+// Wake 立即评估一个缩到零的 endpoint（收到新任务时触发），不等下一个周期
+func (m *Manager) Wake(endpoint string) {
+	if !m.IsRunning() {
+		return
+	}
+	logger.Infof("waking endpoint %s scaled to zero", endpoint)
+	m.enqueueTarget(endpoint)
+}
+
 // TriggerScale 手动触发扩缩容
 func (m *Manager) TriggerScale(ctx context.Context, endpoint string) error {
 	logger.InfoCtx(ctx, "manually triggering scale for endpoint: %s", endpoint)
@@ -525,6 +683,11 @@ func (m *Manager) GetStatus(ctx context.Context) (*AutoScalerStatus, error) {
 		Running:     running,
 		LastRunTime: lastRunTime,
 	}
+	if m.brake != nil {
+		if brake := m.brake.Current(); brake.Engaged {
+			status.EmergencyBrake = &brake
+		}
+	}
 
 	// 收集 endpoint 状态
 	endpoints, err := m.metricsCollector.CollectEndpointMetrics(ctx)
@@ -552,6 +715,7 @@ func (m *Manager) GetStatus(ctx context.Context) (*AutoScalerStatus, error) {
 
 		endpointStatuses = append(endpointStatuses, EndpointStatus{
 			Name:             ep.Name,
+			GroupName:        ep.GroupName,
 			Enabled:          enabled,
 			CurrentReplicas:  ep.ActualReplicas,
 			DesiredReplicas:  ep.Replicas,
@@ -567,6 +731,12 @@ func (m *Manager) GetStatus(ctx context.Context) (*AutoScalerStatus, error) {
 			IdleTime:         idleTime,
 			WaitingTime:      waitingTime,
 			ResourceUsage:    *resourceUsage,
+			EvalInterval:     int(EffectiveInterval(ep, m.globalInterval()).Seconds()),
+			LastEvaluated:    m.cadence.lastEvaluated(ep.Name),
+			StartupEstimate:  ep.StartupEstimate.Seconds(),
+			QueueGrowthRate:  ep.QueueGrowthRate,
+			MetricValues:     ep.MetricValues,
+			ActiveSchedule:   ep.ActiveSchedule,
 		})
 	}
 	status.Endpoints = endpointStatuses
@@ -672,6 +842,11 @@ func (m *Manager) shouldProcessEndpoint(endpoint *EndpointConfig) bool {
 	globalEnabled := m.enabled
 	m.mu.RUnlock()
 
+	// A paused queue keeps its workers up and idle until dispatch resumes
+	if endpoint.DispatchPaused {
+		return false
+	}
+
 	// 如果endpoint有明确的覆盖配置，使用覆盖配置
 	if endpoint.AutoscalerEnabled != nil && *endpoint.AutoscalerEnabled != "" {
 		switch *endpoint.AutoscalerEnabled {
@@ -828,8 +1003,8 @@ func (m *Manager) checkAndScaleDownIdleWorkers(ctx context.Context, endpoints []
 		now := time.Now()
 
 		for _, w := range workers {
-			// Skip workers with current jobs
-			if w.CurrentJobs > 0 {
+			// Skip workers with current jobs and quarantined workers (kept for inspection)
+			if w.CurrentJobs > 0 || w.Quarantine != nil {
 				continue
 			}
 
@@ -0,0 +1,290 @@
+package autoscaler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+)
+
+// MetricSource is an alias to interfaces.MetricSource
+type MetricSource = interfaces.MetricSource
+
+// MetricValue is an alias to interfaces.MetricValue
+type MetricValue = interfaces.MetricValue
+
+const (
+	// metricTolerance is how far a ratio-based metric may stray from its target before it asks
+	// for a different replica count, so that noise doesn't flap the endpoint (as in the HPA)
+	metricTolerance = 0.1
+
+	// DefaultGPUUtilizationQuery averages the DCGM exporter's GPU utilization over the
+	// endpoint's pods. {{endpoint}} and {{namespace}} are filled in per endpoint.
+	DefaultGPUUtilizationQuery = `avg(DCGM_FI_DEV_GPU_UTIL{namespace="{{namespace}}",pod=~"{{endpoint}}-[a-z0-9]+-[a-z0-9]+"})`
+)
+
+// MetricReader reads the current value of a metric source for an endpoint
+type MetricReader interface {
+	Read(ctx context.Context, ep *EndpointConfig, source MetricSource) (float64, error)
+}
+
+// ValidateMetricSources checks the metric sources of an endpoint
+func ValidateMetricSources(sources []MetricSource) error {
+	seen := make(map[string]bool)
+	for i, source := range sources {
+		switch source.Type {
+		case interfaces.MetricSourceGPUUtilization, interfaces.MetricSourceLatency:
+			if seen[source.Type] {
+				return fmt.Errorf("invalid metric source %d: %s is configured twice", i, source.Type)
+			}
+			seen[source.Type] = true
+			if source.Query != "" || source.TargetType != "" {
+				return fmt.Errorf("invalid metric source %d: query and targetType only apply to %s sources", i, interfaces.MetricSourcePrometheus)
+			}
+		case interfaces.MetricSourcePrometheus:
+			if strings.TrimSpace(source.Query) == "" {
+				return fmt.Errorf("invalid metric source %d: query is required", i)
+			}
+			switch source.TargetType {
+			case "", interfaces.MetricTargetAverageValue, interfaces.MetricTargetValue:
+			default:
+				return fmt.Errorf("invalid metric source %d: targetType must be %s or %s", i, interfaces.MetricTargetAverageValue, interfaces.MetricTargetValue)
+			}
+		default:
+			return fmt.Errorf("invalid metric source %d: type must be %s, %s or %s", i,
+				interfaces.MetricSourceGPUUtilization, interfaces.MetricSourceLatency, interfaces.MetricSourcePrometheus)
+		}
+		if source.Target <= 0 {
+			return fmt.Errorf("invalid metric source %d: target must be greater than 0", i)
+		}
+		if source.Type == interfaces.MetricSourceGPUUtilization && source.Target > 100 {
+			return fmt.Errorf("invalid metric source %d: GPU utilization target must be at most 100", i)
+		}
+	}
+	return nil
+}
+
+// metricSourceReplicas returns the replicas a metric reading asks for, or 0 when it has no
+// opinion. Utilization, latency and "value" Prometheus targets describe the whole endpoint and
+// scale the ready replicas by value/target; they need ready replicas to scale from, so waking an
+// endpoint from zero is left to the queue. "averageValue" targets are per replica:
+// replicas = value/target.
+func metricSourceReplicas(source MetricSource, value float64, readyReplicas int) int {
+	if source.Target <= 0 {
+		return 0
+	}
+	if source.Type == interfaces.MetricSourcePrometheus && source.TargetType != interfaces.MetricTargetValue {
+		if readyReplicas > 0 && math.Abs(value/(source.Target*float64(readyReplicas))-1) <= metricTolerance {
+			return readyReplicas
+		}
+		return int(math.Ceil(value / source.Target))
+	}
+	if readyReplicas == 0 {
+		return 0
+	}
+	ratio := value / source.Target
+	if math.Abs(ratio-1) <= metricTolerance {
+		return readyReplicas
+	}
+	return int(math.Ceil(float64(readyReplicas) * ratio))
+}
+
+// metricSourcesReplicas returns the highest replica count asked for by the endpoint's metric
+// readings and a reason naming the source, or 0 when no source has an opinion
+func metricSourcesReplicas(ep *EndpointConfig) (int, string) {
+	replicas, reason := 0, ""
+	for _, v := range ep.MetricValues {
+		if v.Error != "" || v.Replicas <= replicas {
+			continue
+		}
+		replicas = v.Replicas
+		reason = fmt.Sprintf("metric %s at %.2f (target %.2f)", v.Type, v.Value, v.Target)
+	}
+	return replicas, reason
+}
+
+// readMetricSources reads every metric source of an endpoint. A source that can't be read
+// records the error and asks for no replicas.
+func (c *MetricsCollector) readMetricSources(ctx context.Context, ep *EndpointConfig) []MetricValue {
+	values := make([]MetricValue, 0, len(ep.MetricSources))
+	for _, source := range ep.MetricSources {
+		v := MetricValue{Type: source.Type, Target: source.Target}
+		reader := c.metricReaders[source.Type]
+		if reader == nil {
+			v.Error = fmt.Sprintf("no reader configured for %s metrics", source.Type)
+			values = append(values, v)
+			continue
+		}
+		value, err := reader.Read(ctx, ep, source)
+		if err != nil {
+			logger.WarnCtx(ctx, "endpoint %s: failed to read %s metric: %v", ep.Name, source.Type, err)
+			v.Error = err.Error()
+			values = append(values, v)
+			continue
+		}
+		v.Value = value
+		v.Replicas = metricSourceReplicas(source, value, ep.ActualReplicas)
+		values = append(values, v)
+	}
+	return values
+}
+
+// LatencyReader reads the average request latency (queue wait + execution, ms) of an endpoint
+// from the per-minute monitoring stats, weighted by completed tasks
+type LatencyReader struct {
+	repo   *mysql.MonitoringRepository
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewLatencyReader creates a latency reader averaging over window
+func NewLatencyReader(repo *mysql.MonitoringRepository, window time.Duration) *LatencyReader {
+	return &LatencyReader{repo: repo, window: window, now: time.Now}
+}
+
+// Read implements MetricReader
+func (r *LatencyReader) Read(ctx context.Context, ep *EndpointConfig, _ MetricSource) (float64, error) {
+	now := r.now()
+	stats, err := r.repo.GetMinuteStats(ctx, ep.Name, now.Add(-r.window), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get minute stats: %w", err)
+	}
+	var sum float64
+	var completed int
+	for _, stat := range stats {
+		sum += (stat.AvgQueueWaitMs + stat.AvgExecutionMs) * float64(stat.TasksCompleted)
+		completed += stat.TasksCompleted
+	}
+	if completed == 0 {
+		return 0, fmt.Errorf("no tasks completed in the last %s", r.window)
+	}
+	return sum / float64(completed), nil
+}
+
+// PrometheusReader reads GPU utilization and custom query metrics from Prometheus
+type PrometheusReader struct {
+	client    *PrometheusClient
+	namespace string
+	gpuQuery  string
+}
+
+// NewPrometheusReader creates a Prometheus metric reader. gpuQuery is the GPU utilization query
+// template (empty = DefaultGPUUtilizationQuery).
+func NewPrometheusReader(client *PrometheusClient, namespace, gpuQuery string) *PrometheusReader {
+	if gpuQuery == "" {
+		gpuQuery = DefaultGPUUtilizationQuery
+	}
+	return &PrometheusReader{client: client, namespace: namespace, gpuQuery: gpuQuery}
+}
+
+// Read implements MetricReader
+func (r *PrometheusReader) Read(ctx context.Context, ep *EndpointConfig, source MetricSource) (float64, error) {
+	query := source.Query
+	if source.Type == interfaces.MetricSourceGPUUtilization {
+		query = r.gpuQuery
+	}
+	query = strings.NewReplacer("{{endpoint}}", ep.Name, "{{namespace}}", r.namespace).Replace(query)
+	return r.client.Query(ctx, query)
+}
+
+// PrometheusClient runs instant queries against the Prometheus HTTP API
+type PrometheusClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewPrometheusClient creates a Prometheus client
+func NewPrometheusClient(baseURL string, timeout time.Duration) *PrometheusClient {
+	return &PrometheusClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// Query runs an instant query and returns its value. The samples of a vector result are
+// summed; a query that returns nothing is an error.
+func (c *PrometheusClient) Query(ctx context.Context, query string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/query?"+url.Values{"query": {query}}.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("prometheus query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to decode prometheus response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if body.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s", body.Error)
+	}
+
+	switch body.Data.ResultType {
+	case "scalar":
+		var sample []interface{}
+		if err := json.Unmarshal(body.Data.Result, &sample); err != nil {
+			return 0, fmt.Errorf("failed to decode scalar result: %w", err)
+		}
+		return parsePrometheusSample(sample)
+	case "vector":
+		var series []struct {
+			Value []interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &series); err != nil {
+			return 0, fmt.Errorf("failed to decode vector result: %w", err)
+		}
+		if len(series) == 0 {
+			return 0, fmt.Errorf("prometheus query returned no samples")
+		}
+		var sum float64
+		for _, s := range series {
+			v, err := parsePrometheusSample(s.Value)
+			if err != nil {
+				return 0, err
+			}
+			sum += v
+		}
+		return sum, nil
+	}
+	return 0, fmt.Errorf("unsupported prometheus result type %q", body.Data.ResultType)
+}
+
+// parsePrometheusSample parses a [timestamp, "value"] sample
+func parsePrometheusSample(sample []interface{}) (float64, error) {
+	if len(sample) != 2 {
+		return 0, fmt.Errorf("malformed prometheus sample: %v", sample)
+	}
+	s, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("malformed prometheus sample value: %v", sample[1])
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed prometheus sample value %q: %w", s, err)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("prometheus sample value is %s", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,184 @@
+package autoscaler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/interfaces"
+)
+
+func TestValidateMetricSources(t *testing.T) {
+	assert.NoError(t, ValidateMetricSources(nil))
+	assert.NoError(t, ValidateMetricSources([]MetricSource{
+		{Type: interfaces.MetricSourceGPUUtilization, Target: 70},
+		{Type: interfaces.MetricSourceLatency, Target: 2000},
+		{Type: interfaces.MetricSourcePrometheus, Target: 5, Query: "sum(rate(requests_total[1m]))"},
+		{Type: interfaces.MetricSourcePrometheus, Target: 100, Query: "max(kv_cache_usage)", TargetType: interfaces.MetricTargetValue},
+	}))
+
+	for _, sources := range [][]MetricSource{
+		{{Type: "cpu", Target: 50}},
+		{{Type: interfaces.MetricSourceLatency, Target: 0}},
+		{{Type: interfaces.MetricSourceGPUUtilization, Target: 120}},
+		{{Type: interfaces.MetricSourceGPUUtilization, Target: 70}, {Type: interfaces.MetricSourceGPUUtilization, Target: 80}},
+		{{Type: interfaces.MetricSourceLatency, Target: 2000, Query: "up"}},
+		{{Type: interfaces.MetricSourcePrometheus, Target: 5}},
+		{{Type: interfaces.MetricSourcePrometheus, Target: 5, Query: "up", TargetType: "utilization"}},
+	} {
+		err := ValidateMetricSources(sources)
+		if assert.Error(t, err, "%+v", sources) {
+			assert.Contains(t, err.Error(), "invalid metric source")
+		}
+	}
+}
+
+func TestMetricSourceReplicas(t *testing.T) {
+	gpu := MetricSource{Type: interfaces.MetricSourceGPUUtilization, Target: 60}
+	perReplica := MetricSource{Type: interfaces.MetricSourcePrometheus, Target: 10, Query: "q"}
+	total := MetricSource{Type: interfaces.MetricSourcePrometheus, Target: 0.8, Query: "q", TargetType: interfaces.MetricTargetValue}
+
+	tests := []struct {
+		name   string
+		source MetricSource
+		value  float64
+		ready  int
+		want   int
+	}{
+		{"utilization above target", gpu, 90, 4, 6},
+		{"utilization below target", gpu, 30, 4, 2},
+		{"utilization within tolerance", gpu, 64, 4, 4},
+		{"utilization without ready replicas", gpu, 90, 0, 0},
+		{"average value", perReplica, 45, 2, 5},
+		{"average value within tolerance", perReplica, 41, 4, 4},
+		{"average value wakes from zero", perReplica, 15, 0, 2},
+		{"total value", total, 1.2, 3, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, metricSourceReplicas(tt.source, tt.value, tt.ready))
+		})
+	}
+}
+
+func TestMetricSourcesReplicas(t *testing.T) {
+	ep := &EndpointConfig{MetricValues: []MetricValue{
+		{Type: interfaces.MetricSourceGPUUtilization, Value: 90, Target: 60, Replicas: 6},
+		{Type: interfaces.MetricSourceLatency, Value: 4000, Target: 1000, Replicas: 16},
+		{Type: interfaces.MetricSourcePrometheus, Error: "prometheus query returned no samples"},
+	}}
+	replicas, reason := metricSourcesReplicas(ep)
+	assert.Equal(t, 16, replicas)
+	assert.Contains(t, reason, "latency")
+
+	replicas, _ = metricSourcesReplicas(&EndpointConfig{})
+	assert.Zero(t, replicas)
+}
+
+type fakeMetricReader struct {
+	value float64
+	err   error
+}
+
+func (r fakeMetricReader) Read(context.Context, *EndpointConfig, MetricSource) (float64, error) {
+	return r.value, r.err
+}
+
+func TestReadMetricSources(t *testing.T) {
+	c := NewMetricsCollector(nil, nil, nil, nil)
+	c.SetMetricReader(interfaces.MetricSourceGPUUtilization, fakeMetricReader{value: 90})
+	c.SetMetricReader(interfaces.MetricSourceLatency, fakeMetricReader{err: errors.New("no tasks completed")})
+
+	ep := &EndpointConfig{Name: "sdxl", ActualReplicas: 2, MetricSources: []MetricSource{
+		{Type: interfaces.MetricSourceGPUUtilization, Target: 60},
+		{Type: interfaces.MetricSourceLatency, Target: 1000},
+		{Type: interfaces.MetricSourcePrometheus, Target: 5, Query: "up"},
+	}}
+	values := c.readMetricSources(context.Background(), ep)
+	require.Len(t, values, 3)
+	assert.Equal(t, MetricValue{Type: interfaces.MetricSourceGPUUtilization, Value: 90, Target: 60, Replicas: 3}, values[0])
+	assert.Equal(t, "no tasks completed", values[1].Error)
+	assert.Contains(t, values[2].Error, "no reader configured")
+}
+
+func TestShouldScaleDown_HoldsMetricSourceReplicas(t *testing.T) {
+	engine := NewDecisionEngine(&Config{}, nil)
+	ep := &EndpointConfig{
+		Name:              "sdxl",
+		MaxReplicas:       10,
+		Replicas:          4,
+		ActualReplicas:    4,
+		ScaleDownIdleTime: 60,
+		LastTaskTime:      time.Now().Add(-time.Hour),
+		MetricValues: []MetricValue{
+			{Type: interfaces.MetricSourceGPUUtilization, Value: 62, Target: 60, Replicas: 4},
+		},
+	}
+
+	// The queue is empty but the GPUs are at their target utilization
+	assert.Nil(t, engine.shouldScaleDown(context.Background(), ep))
+
+	// Utilization dropped: scale down to what it requires
+	ep.MetricValues[0] = MetricValue{Type: interfaces.MetricSourceGPUUtilization, Value: 30, Target: 60, Replicas: 2}
+	decision := engine.shouldScaleDown(context.Background(), ep)
+	if assert.NotNil(t, decision) {
+		assert.Equal(t, 2, decision.DesiredReplicas)
+	}
+}
+
+func TestPrometheusClientQuery(t *testing.T) {
+	responses := map[string]string{
+		"vector":  `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"pod":"a"},"value":[1700000000,"1.5"]},{"metric":{"pod":"b"},"value":[1700000000,"2.5"]}]}}`,
+		"scalar":  `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"42"]}}`,
+		"empty":   `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		"nan":     `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"NaN"]}}`,
+		"invalid": `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		w.Write([]byte(responses[r.URL.Query().Get("query")]))
+	}))
+	defer server.Close()
+
+	client := NewPrometheusClient(server.URL+"/", time.Second)
+	ctx := context.Background()
+
+	v, err := client.Query(ctx, "vector")
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, v)
+
+	v, err = client.Query(ctx, "scalar")
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, v)
+
+	for _, q := range []string{"empty", "nan", "invalid"} {
+		_, err = client.Query(ctx, q)
+		assert.Error(t, err, q)
+	}
+}
+
+func TestPrometheusReaderExpandsQuery(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("query")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1700000000,"1"]}}`))
+	}))
+	defer server.Close()
+
+	reader := NewPrometheusReader(NewPrometheusClient(server.URL, time.Second), "wavespeed", "")
+	ep := &EndpointConfig{Name: "sdxl"}
+
+	_, err := reader.Read(context.Background(), ep, MetricSource{Type: interfaces.MetricSourceGPUUtilization, Target: 60})
+	require.NoError(t, err)
+	assert.Equal(t, `avg(DCGM_FI_DEV_GPU_UTIL{namespace="wavespeed",pod=~"sdxl-[a-z0-9]+-[a-z0-9]+"})`, got)
+
+	_, err = reader.Read(context.Background(), ep, MetricSource{Type: interfaces.MetricSourcePrometheus, Target: 5, Query: `sum(rate(http_requests_total{app="{{endpoint}}"}[1m]))`})
+	require.NoError(t, err)
+	assert.Equal(t, `sum(rate(http_requests_total{app="sdxl"}[1m]))`, got)
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"waverless/internal/model"
 	endpointsvc "waverless/internal/service/endpoint"
 	"waverless/pkg/constants"
 	"waverless/pkg/interfaces"
@@ -22,6 +23,12 @@ type MetricsCollector struct {
 
 	replicaMu        sync.RWMutex
 	replicaSnapshots map[string]replicaSnapshot
+
+	startupModel *StartupModel
+	growthMu     sync.Mutex
+	queueGrowth  map[string]*queueGrowth
+
+	metricReaders map[string]MetricReader // 指标源类型 -> 读取器
 }
 
 type replicaSnapshot struct {
@@ -45,9 +52,21 @@ func NewMetricsCollector(
 		workerLister:       workerLister,
 		taskRepo:           taskRepo,
 		replicaSnapshots:   make(map[string]replicaSnapshot),
+		queueGrowth:        make(map[string]*queueGrowth),
+		metricReaders:      make(map[string]MetricReader),
 	}
 }
 
+// SetStartupModel 设置启动耗时模型（用于依赖注入）
+func (c *MetricsCollector) SetStartupModel(model *StartupModel) {
+	c.startupModel = model
+}
+
+// SetMetricReader 注册某类指标源的读取器（用于依赖注入）
+func (c *MetricsCollector) SetMetricReader(sourceType string, reader MetricReader) {
+	c.metricReaders[sourceType] = reader
+}
+
 // UpdateReplicaSnapshot 更新副本快照，供控制循环快速读取最新状态
 func (c *MetricsCollector) UpdateReplicaSnapshot(event interfaces.ReplicaEvent) bool {
 	c.replicaMu.Lock()
@@ -89,6 +108,7 @@ func (c *MetricsCollector) CollectEndpointMetrics(ctx context.Context) ([]*Endpo
 	if err != nil {
 		return nil, fmt.Errorf("failed to list endpoints: %w", err)
 	}
+	c.startupModel.RefreshIfStale(ctx)
 
 	configs := make([]*EndpointConfig, 0, len(endpoints))
 	for _, ep := range endpoints {
@@ -109,6 +129,7 @@ func (c *MetricsCollector) collectSingleEndpoint(ctx context.Context, ep *interf
 		Name:        ep.Name,
 		DisplayName: ep.DisplayName,
 		SpecName:    ep.SpecName, // Copy SpecName to avoid re-querying metadata
+		Image:       ep.Image,
 		MinReplicas: ep.MinReplicas,
 		MaxReplicas: ep.MaxReplicas,
 		Replicas:    ep.Replicas,
@@ -123,15 +144,29 @@ func (c *MetricsCollector) collectSingleEndpoint(ctx context.Context, ep *interf
 		HighLoadThreshold: getOrDefault(ep.HighLoadThreshold, 10),
 		PriorityBoost:     getOrDefault(ep.PriorityBoost, 20),
 		AutoscalerEnabled: ep.AutoscalerEnabled,
+		DispatchPaused:    ep.DispatchPaused,
+		CircuitState:      ep.CircuitState,
 		LastScaleTime:     ep.LastScaleTime,
 		LastTaskTime:      ep.LastTaskTime,
 		FirstPendingTime:  ep.FirstPendingTime,
 
+		// 自定义指标扩缩容配置
+		CustomMetricName:   ep.CustomMetricName,
+		CustomMetricTarget: ep.CustomMetricTarget,
+		GroupName:          ep.GroupName,
+		EvaluationInterval: ep.EvaluationInterval,
+		QueueWaitSLO:       ep.QueueWaitSLO,
+		MetricSources:      ep.MetricSources,
+		ReplicaSchedules:   ep.ReplicaSchedules,
+
 		// 直接使用数据库中的副本状态，不再调用 K8s API
 		ActualReplicas:    ep.ReadyReplicas,
 		AvailableReplicas: ep.AvailableReplicas,
 	}
 
+	// 定时副本计划生效期间，使用计划的最小/最大副本数
+	applyReplicaSchedule(ctx, config, time.Now())
+
 	// WARNING: Check for invalid autoscaling configuration
 	if config.MaxReplicas == 0 && ep.MaxReplicas == 0 {
 		logger.WarnCtx(ctx, "endpoint %s: maxReplicas is 0, autoscaling will NOT work! Please configure maxReplicas > 0", ep.Name)
@@ -160,6 +195,22 @@ func (c *MetricsCollector) collectSingleEndpoint(ctx context.Context, ep *interf
 	}
 	config.RunningTasks = runningCount
 
+	// 队列增长速度与启动耗时估计（仅在配置了排队 SLO 时）
+	if config.QueueWaitSLO > 0 {
+		config.QueueGrowthRate = c.observeQueue(ep.Name, pendingCount, time.Now())
+		config.StartupEstimate = c.startupModel.Estimate(config.SpecName, config.Image)
+	}
+
+	// 汇总 worker 上报的自定义指标（仅在配置了目标值时）
+	if config.CustomMetricTarget > 0 {
+		config.CustomMetricValue, config.CustomMetricCount = c.getCustomMetric(ctx, ep.Name)
+	}
+
+	// 读取 HPA 风格的指标源（GPU 利用率、延迟、Prometheus 查询）
+	if len(config.MetricSources) > 0 {
+		config.MetricValues = c.readMetricSources(ctx, config)
+	}
+
 	// 更新 FirstPendingTime
 	if pendingCount > 0 && config.FirstPendingTime.IsZero() {
 		config.FirstPendingTime = time.Now()
@@ -170,6 +221,50 @@ func (c *MetricsCollector) collectSingleEndpoint(ctx context.Context, ep *interf
 	return config, nil
 }
 
+const (
+	// queueGrowthAlpha 队列增长速度的指数平滑系数
+	queueGrowthAlpha = 0.5
+	// queueGrowthMinGap 两次观测间隔过短时不更新，避免快速通道的连续评估放大噪声
+	queueGrowthMinGap = time.Second
+	// queueGrowthMaxGap 两次观测间隔过长时重新开始计算
+	queueGrowthMaxGap = 10 * time.Minute
+)
+
+// queueGrowth 单个 endpoint 的排队任务数观测
+type queueGrowth struct {
+	pending int64
+	at      time.Time
+	rate    float64 // 任务数/秒，指数平滑
+}
+
+// observe 记录一次排队任务数并返回平滑后的增长速度
+func (g *queueGrowth) observe(pending int64, now time.Time) float64 {
+	if g.at.IsZero() || now.Sub(g.at) > queueGrowthMaxGap {
+		g.pending, g.at, g.rate = pending, now, 0
+		return 0
+	}
+	dt := now.Sub(g.at)
+	if dt < queueGrowthMinGap {
+		return g.rate
+	}
+	instant := float64(pending-g.pending) / dt.Seconds()
+	g.rate = queueGrowthAlpha*instant + (1-queueGrowthAlpha)*g.rate
+	g.pending, g.at = pending, now
+	return g.rate
+}
+
+// observeQueue 更新 endpoint 的队列增长速度
+func (c *MetricsCollector) observeQueue(endpoint string, pending int64, now time.Time) float64 {
+	c.growthMu.Lock()
+	defer c.growthMu.Unlock()
+	g, ok := c.queueGrowth[endpoint]
+	if !ok {
+		g = &queueGrowth{}
+		c.queueGrowth[endpoint] = g
+	}
+	return g.observe(pending, now)
+}
+
 // getReplicaStats 获取 K8s 中实际运行的副本数和正在排空的副本数
 func (c *MetricsCollector) getReplicaStats(ctx context.Context, endpoint string) (ready int, available int, draining int, conditions []interfaces.ReplicaCondition, err error) {
 	app, err := c.deploymentProvider.GetApp(ctx, endpoint)
@@ -195,6 +290,38 @@ func (c *MetricsCollector) getReplicaStats(ctx context.Context, endpoint string)
 	return ready, available, draining, conditions, nil
 }
 
+// customMetricMaxAge 自定义指标上报的有效期，超过则视为过期不参与计算
+const customMetricMaxAge = 2 * time.Minute
+
+// getCustomMetric 计算活跃 worker 上报的自定义指标平均值，返回平均值和参与计算的 worker 数
+func (c *MetricsCollector) getCustomMetric(ctx context.Context, endpoint string) (float64, int) {
+	workers, err := c.workerLister.ListWorkers(ctx, endpoint)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to list workers for custom metric of %s: %v", endpoint, err)
+		return 0, 0
+	}
+	return averageCustomMetric(workers, time.Now())
+}
+
+// averageCustomMetric 仅统计在线且近期上报过指标的 worker（排空中的 worker 即将退出，不计入）
+func averageCustomMetric(workers []*model.Worker, now time.Time) (float64, int) {
+	sum, count := 0.0, 0
+	for _, worker := range workers {
+		if worker.CustomMetric == nil || now.Sub(worker.CustomMetricAt) > customMetricMaxAge {
+			continue
+		}
+		if worker.Status != model.WorkerStatusOnline && worker.Status != model.WorkerStatusBusy {
+			continue
+		}
+		sum += *worker.CustomMetric
+		count++
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return sum / float64(count), count
+}
+
 // getRunningTaskCount 获取正在执行的任务数
 func (c *MetricsCollector) getRunningTaskCount(ctx context.Context, endpoint string) (int64, error) {
 	// OPTIMIZATION: Use in-progress index instead of scanning all tasks
@@ -0,0 +1,137 @@
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/cronexpr"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// ReplicaSchedule is an alias to interfaces.ReplicaSchedule
+type ReplicaSchedule = interfaces.ReplicaSchedule
+
+// MaxReplicaScheduleDuration caps the window of a replica schedule
+const MaxReplicaScheduleDuration = 7 * 24 * time.Hour
+
+// ReplicaScheduleStatus is a replica schedule with its current state
+type ReplicaScheduleStatus struct {
+	ReplicaSchedule
+	Active      bool       `json:"active"`                // The schedule's min/max replicas apply now
+	ActiveSince *time.Time `json:"activeSince,omitempty"` // Start of the current window
+	NextStart   *time.Time `json:"nextStart,omitempty"`   // Start of the next window (nil = never)
+}
+
+// ValidateReplicaSchedules checks the replica schedules of an endpoint
+func ValidateReplicaSchedules(schedules []ReplicaSchedule) error {
+	seen := make(map[string]bool)
+	for i, schedule := range schedules {
+		if schedule.Name == "" {
+			return fmt.Errorf("invalid replica schedule %d: name is required", i)
+		}
+		if seen[schedule.Name] {
+			return fmt.Errorf("invalid replica schedule %s: name is used twice", schedule.Name)
+		}
+		seen[schedule.Name] = true
+		if _, _, err := parseReplicaSchedule(schedule); err != nil {
+			return fmt.Errorf("invalid replica schedule %s: %w", schedule.Name, err)
+		}
+		duration := time.Duration(schedule.DurationMinutes) * time.Minute
+		if duration <= 0 || duration > MaxReplicaScheduleDuration {
+			return fmt.Errorf("invalid replica schedule %s: durationMinutes must be between 1 and %d",
+				schedule.Name, int(MaxReplicaScheduleDuration.Minutes()))
+		}
+		if schedule.MinReplicas < 0 || schedule.MaxReplicas < 1 || schedule.MinReplicas > schedule.MaxReplicas {
+			return fmt.Errorf("invalid replica schedule %s: need 0 <= minReplicas <= maxReplicas and maxReplicas >= 1", schedule.Name)
+		}
+	}
+	return nil
+}
+
+// parseReplicaSchedule parses the cron expression and time zone of a schedule
+func parseReplicaSchedule(schedule ReplicaSchedule) (*cronexpr.Schedule, *time.Location, error) {
+	cron, err := cronexpr.Parse(schedule.Cron)
+	if err != nil {
+		return nil, nil, err
+	}
+	timezone := schedule.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid timezone %q: %v", timezone, err)
+	}
+	return cron, loc, nil
+}
+
+// replicaScheduleState returns the start of the schedule's window containing now (zero when
+// none does) and the start of its next window (zero when it never fires again)
+func replicaScheduleState(schedule ReplicaSchedule, now time.Time) (time.Time, time.Time, error) {
+	cron, loc, err := parseReplicaSchedule(schedule)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	now = now.In(loc)
+	duration := time.Duration(schedule.DurationMinutes) * time.Minute
+
+	// Latest window start in (now-duration, now]
+	var since time.Time
+	for start := cron.Next(now.Add(-duration)); !start.IsZero() && !start.After(now); start = cron.Next(start) {
+		since = start
+	}
+	return since, cron.Next(now), nil
+}
+
+// activeReplicaSchedule returns the schedule whose window started last among those containing
+// now, nil when there is none. Schedules that can't be parsed are skipped.
+func activeReplicaSchedule(ctx context.Context, endpoint string, schedules []ReplicaSchedule, now time.Time) *ReplicaSchedule {
+	var active *ReplicaSchedule
+	var activeSince time.Time
+	for i := range schedules {
+		since, _, err := replicaScheduleState(schedules[i], now)
+		if err != nil {
+			logger.WarnCtx(ctx, "endpoint %s: skipping replica schedule %s: %v", endpoint, schedules[i].Name, err)
+			continue
+		}
+		if !since.IsZero() && (active == nil || since.After(activeSince)) {
+			active, activeSince = &schedules[i], since
+		}
+	}
+	return active
+}
+
+// applyReplicaSchedule replaces the min/max replicas of an endpoint with those of its active
+// replica schedule, if any
+func applyReplicaSchedule(ctx context.Context, ep *EndpointConfig, now time.Time) {
+	schedule := activeReplicaSchedule(ctx, ep.Name, ep.ReplicaSchedules, now)
+	if schedule == nil {
+		return
+	}
+	ep.MinReplicas = schedule.MinReplicas
+	ep.MaxReplicas = schedule.MaxReplicas
+	ep.ActiveSchedule = schedule.Name
+}
+
+// DescribeReplicaSchedules returns the state of an endpoint's replica schedules at now
+func DescribeReplicaSchedules(ctx context.Context, endpoint string, schedules []ReplicaSchedule, now time.Time) []ReplicaScheduleStatus {
+	active := activeReplicaSchedule(ctx, endpoint, schedules, now)
+	result := make([]ReplicaScheduleStatus, 0, len(schedules))
+	for _, schedule := range schedules {
+		status := ReplicaScheduleStatus{ReplicaSchedule: schedule}
+		since, next, err := replicaScheduleState(schedule, now)
+		if err == nil {
+			status.Active = active != nil && active.Name == schedule.Name
+			if status.Active {
+				status.ActiveSince = &since
+			}
+			if !next.IsZero() {
+				status.NextStart = &next
+			}
+		}
+		result = append(result, status)
+	}
+	return result
+}
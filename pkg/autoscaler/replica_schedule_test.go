@@ -0,0 +1,95 @@
+package autoscaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReplicaSchedules(t *testing.T) {
+	assert.NoError(t, ValidateReplicaSchedules(nil))
+	assert.NoError(t, ValidateReplicaSchedules([]ReplicaSchedule{
+		{Name: "peak", Cron: "30 7 * * 1-5", DurationMinutes: 600, Timezone: "Asia/Shanghai", MinReplicas: 4, MaxReplicas: 20},
+		{Name: "night", Cron: "0 22 * * *", DurationMinutes: 540, MinReplicas: 0, MaxReplicas: 2},
+	}))
+
+	for _, schedules := range [][]ReplicaSchedule{
+		{{Cron: "0 8 * * *", DurationMinutes: 60, MaxReplicas: 1}},
+		{{Name: "a", Cron: "0 8 * * *", DurationMinutes: 60, MaxReplicas: 1}, {Name: "a", Cron: "0 9 * * *", DurationMinutes: 60, MaxReplicas: 1}},
+		{{Name: "a", Cron: "0 25 * * *", DurationMinutes: 60, MaxReplicas: 1}},
+		{{Name: "a", Cron: "0 8 * * *", DurationMinutes: 60, Timezone: "Mars/Olympus", MaxReplicas: 1}},
+		{{Name: "a", Cron: "0 8 * * *", DurationMinutes: 0, MaxReplicas: 1}},
+		{{Name: "a", Cron: "0 8 * * *", DurationMinutes: 8 * 24 * 60, MaxReplicas: 1}},
+		{{Name: "a", Cron: "0 8 * * *", DurationMinutes: 60, MinReplicas: 3, MaxReplicas: 2}},
+		{{Name: "a", Cron: "0 8 * * *", DurationMinutes: 60}},
+	} {
+		err := ValidateReplicaSchedules(schedules)
+		if assert.Error(t, err, "%+v", schedules) {
+			assert.Contains(t, err.Error(), "invalid replica schedule")
+		}
+	}
+}
+
+func TestApplyReplicaSchedule(t *testing.T) {
+	ctx := context.Background()
+	schedules := []ReplicaSchedule{
+		{Name: "day", Cron: "0 8 * * *", DurationMinutes: 14 * 60, MinReplicas: 2, MaxReplicas: 10},
+		{Name: "peak", Cron: "0 12 * * *", DurationMinutes: 60, MinReplicas: 6, MaxReplicas: 20},
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 16, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		schedule string
+		min, max int
+	}{
+		{"before any window", at(7, 59), "", 0, 4},
+		{"window start", at(8, 0), "day", 2, 10},
+		{"overlap: latest start wins", at(12, 30), "peak", 6, 20},
+		{"after the inner window", at(13, 0), "day", 2, 10},
+		{"after every window", at(22, 0), "", 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := &EndpointConfig{Name: "flux", MinReplicas: 0, MaxReplicas: 4, ReplicaSchedules: schedules}
+			applyReplicaSchedule(ctx, ep, tt.now)
+			assert.Equal(t, tt.schedule, ep.ActiveSchedule)
+			assert.Equal(t, tt.min, ep.MinReplicas)
+			assert.Equal(t, tt.max, ep.MaxReplicas)
+		})
+	}
+}
+
+func TestApplyReplicaSchedule_Timezone(t *testing.T) {
+	// 08:00 in Shanghai is 00:00 UTC
+	ep := &EndpointConfig{MaxReplicas: 4, ReplicaSchedules: []ReplicaSchedule{
+		{Name: "cn-day", Cron: "0 8 * * *", DurationMinutes: 60, Timezone: "Asia/Shanghai", MinReplicas: 3, MaxReplicas: 8},
+	}}
+	applyReplicaSchedule(context.Background(), ep, time.Date(2026, 10, 16, 0, 30, 0, 0, time.UTC))
+	assert.Equal(t, "cn-day", ep.ActiveSchedule)
+	assert.Equal(t, 3, ep.MinReplicas)
+}
+
+func TestDescribeReplicaSchedules(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+	statuses := DescribeReplicaSchedules(context.Background(), "flux", []ReplicaSchedule{
+		{Name: "day", Cron: "0 8 * * *", DurationMinutes: 14 * 60, MinReplicas: 2, MaxReplicas: 10},
+		{Name: "peak", Cron: "0 12 * * *", DurationMinutes: 60, MinReplicas: 6, MaxReplicas: 20},
+	}, now)
+	require.Len(t, statuses, 2)
+
+	assert.False(t, statuses[0].Active)
+	assert.Nil(t, statuses[0].ActiveSince)
+	require.NotNil(t, statuses[0].NextStart)
+	assert.Equal(t, time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC), *statuses[0].NextStart)
+
+	assert.True(t, statuses[1].Active)
+	require.NotNil(t, statuses[1].ActiveSince)
+	assert.Equal(t, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), *statuses[1].ActiveSince)
+}
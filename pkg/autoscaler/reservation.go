@@ -0,0 +1,155 @@
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"waverless/pkg/logger"
+)
+
+// Reservation 时间窗口内为某个 endpoint 预留的 GPU 容量
+type Reservation struct {
+	Name      string    `json:"name"`
+	Endpoint  string    `json:"endpoint"`
+	Replicas  int       `json:"replicas"`
+	GPUCount  int       `json:"gpuCount"` // 所有预留副本的 GPU 总数
+	Priority  int       `json:"priority"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+// activeAt reports whether the reservation window covers t
+func (r *Reservation) activeAt(t time.Time) bool {
+	return !t.Before(r.StartTime) && t.Before(r.EndTime)
+}
+
+// ApplyReservations keeps the reserved GPUs of running reservations free: scale-ups of endpoints
+// with a lower priority than a reservation may only use the GPUs left after the part of the
+// reservation its endpoint has not claimed yet. The reserving endpoint itself and endpoints with
+// an equal or higher priority are not restricted. Without a cluster GPU limit nothing is blocked.
+func (e *DecisionEngine) ApplyReservations(ctx context.Context, decisions []*ScaleDecision, endpoints []*EndpointConfig, cluster *ClusterResources, reservations []*Reservation) []*ScaleDecision {
+	if len(reservations) == 0 || cluster == nil || cluster.Total.GPUCount <= 0 {
+		return decisions
+	}
+
+	changed := make(map[string]bool)
+	for _, d := range decisions {
+		if d.Approved && d.ScaleAmount != 0 {
+			changed[d.Endpoint] = true
+		}
+	}
+
+	gpusPerReplica := make(map[string]int)
+	for _, ep := range endpoints {
+		if !changed[ep.Name] || e.resourceCalculator == nil {
+			continue
+		}
+		perReplica, err := e.resourceCalculator.CalculateEndpointResource(ctx, ep, 1)
+		if err != nil {
+			logger.WarnCtx(ctx, "reservations: failed to calculate resources of %s, its scale-ups are not checked: %v", ep.Name, err)
+			continue
+		}
+		gpusPerReplica[ep.Name] = perReplica.GPUCount
+	}
+
+	return applyReservations(ctx, decisions, endpoints, cluster, reservations, gpusPerReplica, time.Now())
+}
+
+// applyReservations enforces running reservations given the GPUs each endpoint replica needs
+func applyReservations(ctx context.Context, decisions []*ScaleDecision, endpoints []*EndpointConfig, cluster *ClusterResources, reservations []*Reservation, gpusPerReplica map[string]int, now time.Time) []*ScaleDecision {
+	replicas := make(map[string]int, len(endpoints))
+	for _, ep := range endpoints {
+		replicas[ep.Name] = ep.Replicas
+	}
+
+	// GPUs of each running reservation its endpoint has not claimed yet
+	outstanding := make(map[*Reservation]int)
+	for _, r := range reservations {
+		if !r.activeAt(now) || r.Replicas <= 0 {
+			continue
+		}
+		unclaimed := max(r.Replicas-replicas[r.Endpoint], 0)
+		outstanding[r] = unclaimed * r.GPUCount / r.Replicas
+	}
+	if len(outstanding) == 0 {
+		return decisions
+	}
+
+	// GPUs free after this cycle's scale-downs
+	headroom := cluster.Available.GPUCount
+	scaleUps := make([]*ScaleDecision, 0)
+	for _, d := range decisions {
+		if !d.Approved {
+			continue
+		}
+		if d.ScaleAmount < 0 {
+			headroom -= d.ScaleAmount * gpusPerReplica[d.Endpoint]
+		} else if d.ScaleAmount > 0 {
+			scaleUps = append(scaleUps, d)
+		}
+	}
+
+	sort.SliceStable(scaleUps, func(i, j int) bool {
+		if scaleUps[i].Priority != scaleUps[j].Priority {
+			return scaleUps[i].Priority > scaleUps[j].Priority
+		}
+		return scaleUps[i].QueueLength > scaleUps[j].QueueLength
+	})
+
+	for _, d := range scaleUps {
+		perReplica := gpusPerReplica[d.Endpoint]
+		if perReplica <= 0 {
+			continue
+		}
+
+		held := 0
+		var blocking *Reservation
+		for r, gpus := range outstanding {
+			if r.Endpoint == d.Endpoint || r.Priority <= d.Priority || gpus == 0 {
+				continue
+			}
+			held += gpus
+			if blocking == nil || r.StartTime.Before(blocking.StartTime) {
+				blocking = r
+			}
+		}
+
+		granted := d.ScaleAmount
+		if held > 0 {
+			granted = max(min(granted, (headroom-held)/perReplica), 0)
+		}
+
+		if granted == 0 {
+			d.Approved = false
+			d.Blocked = true
+			d.BlockedReason = fmt.Sprintf("capacity reserved by %s for endpoint %s until %s (%d GPUs held)",
+				blocking.Name, blocking.Endpoint, blocking.EndTime.Format(time.RFC3339), held)
+			logger.InfoCtx(ctx, "endpoint %s: scale up blocked, %s", d.Endpoint, d.BlockedReason)
+			continue
+		}
+
+		if granted < d.ScaleAmount {
+			logger.InfoCtx(ctx, "endpoint %s: scale up capped by reservation %s (%d → %d replicas)",
+				d.Endpoint, blocking.Name, d.ScaleAmount, granted)
+			scaleResources(&d.RequiredResource, granted, d.ScaleAmount)
+			d.ScaleAmount = granted
+			d.DesiredReplicas = d.CurrentReplicas + granted
+			d.Reason = fmt.Sprintf("%s (capped by reservation %s)", d.Reason, blocking.Name)
+		}
+
+		headroom -= granted * perReplica
+		// Scale-ups of the reserving endpoint claim its reservation
+		claimed := granted * perReplica
+		for r, gpus := range outstanding {
+			if r.Endpoint == d.Endpoint && claimed > 0 {
+				take := min(gpus, claimed)
+				outstanding[r] = gpus - take
+				claimed -= take
+			}
+		}
+	}
+
+	return decisions
+}
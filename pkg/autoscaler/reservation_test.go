@@ -0,0 +1,88 @@
+package autoscaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyReservations(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 11, 11, 9, 30, 0, 0, time.UTC)
+	launch := &Reservation{
+		Name:      "flux-launch",
+		Endpoint:  "flux",
+		Replicas:  4,
+		GPUCount:  8,
+		Priority:  80,
+		StartTime: now.Add(-30 * time.Minute),
+		EndTime:   now.Add(6 * time.Hour),
+	}
+	endpoints := []*EndpointConfig{
+		{Name: "flux", Replicas: 1},
+		{Name: "sd", Replicas: 2},
+		{Name: "batch", Replicas: 2},
+		{Name: "vip", Replicas: 1},
+	}
+	gpus := map[string]int{"flux": 2, "sd": 1, "batch": 1, "vip": 1}
+	cluster := func() *ClusterResources {
+		return &ClusterResources{Total: Resources{GPUCount: 16}, Available: Resources{GPUCount: 6}}
+	}
+
+	t.Run("blocks lower-priority scale-ups from the unclaimed GPUs", func(t *testing.T) {
+		// flux holds 1 of 4 reserved replicas → 6 GPUs outstanding, exactly the free GPUs
+		up := &ScaleDecision{Endpoint: "sd", CurrentReplicas: 2, DesiredReplicas: 4, ScaleAmount: 2, Priority: 50, Approved: true}
+
+		applyReservations(ctx, []*ScaleDecision{up}, endpoints, cluster(), []*Reservation{launch}, gpus, now)
+
+		assert.False(t, up.Approved)
+		assert.True(t, up.Blocked)
+		assert.Contains(t, up.BlockedReason, "flux-launch")
+		assert.Contains(t, up.BlockedReason, "6 GPUs held")
+	})
+
+	t.Run("scale-down frees GPUs for a partial grant", func(t *testing.T) {
+		down := &ScaleDecision{Endpoint: "batch", CurrentReplicas: 2, DesiredReplicas: 0, ScaleAmount: -2, Approved: true}
+		up := &ScaleDecision{Endpoint: "sd", CurrentReplicas: 2, DesiredReplicas: 5, ScaleAmount: 3, Priority: 50, Approved: true, Reason: "queue", RequiredResource: Resources{GPUCount: 3}}
+
+		applyReservations(ctx, []*ScaleDecision{up, down}, endpoints, cluster(), []*Reservation{launch}, gpus, now)
+
+		assert.True(t, up.Approved)
+		assert.Equal(t, 2, up.ScaleAmount)
+		assert.Equal(t, 4, up.DesiredReplicas)
+		assert.Equal(t, Resources{GPUCount: 2}, up.RequiredResource)
+		assert.Contains(t, up.Reason, "capped by reservation flux-launch")
+	})
+
+	t.Run("reserving endpoint claims its reservation first", func(t *testing.T) {
+		flux := &ScaleDecision{Endpoint: "flux", CurrentReplicas: 1, DesiredReplicas: 4, ScaleAmount: 3, Priority: 40, Approved: true}
+		sd := &ScaleDecision{Endpoint: "sd", CurrentReplicas: 2, DesiredReplicas: 3, ScaleAmount: 1, Priority: 50, Approved: true}
+
+		applyReservations(ctx, []*ScaleDecision{sd, flux}, endpoints, cluster(), []*Reservation{launch}, gpus, now)
+
+		assert.True(t, flux.Approved)
+		assert.Equal(t, 3, flux.ScaleAmount)
+		// sd is handled first (higher priority) and finds every free GPU reserved
+		assert.True(t, sd.Blocked)
+	})
+
+	t.Run("higher-priority endpoints are not restricted", func(t *testing.T) {
+		up := &ScaleDecision{Endpoint: "vip", CurrentReplicas: 1, DesiredReplicas: 3, ScaleAmount: 2, Priority: 90, Approved: true}
+
+		applyReservations(ctx, []*ScaleDecision{up}, endpoints, cluster(), []*Reservation{launch}, gpus, now)
+
+		assert.True(t, up.Approved)
+		assert.Equal(t, 2, up.ScaleAmount)
+	})
+
+	t.Run("reservations outside their window hold nothing", func(t *testing.T) {
+		up := &ScaleDecision{Endpoint: "sd", CurrentReplicas: 2, DesiredReplicas: 4, ScaleAmount: 2, Priority: 50, Approved: true}
+
+		applyReservations(ctx, []*ScaleDecision{up}, endpoints, cluster(), []*Reservation{launch}, gpus, now.Add(-time.Hour))
+
+		assert.True(t, up.Approved)
+		assert.Equal(t, 2, up.ScaleAmount)
+	})
+}
@@ -0,0 +1,165 @@
+package autoscaler
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+)
+
+const (
+	// startupLookback 启动耗时样本的时间窗口
+	startupLookback = 7 * 24 * time.Hour
+	// startupRefreshInterval 启动耗时模型的刷新间隔
+	startupRefreshInterval = 10 * time.Minute
+	// startupMinSamples 一个 spec/image 至少需要的样本数，不足时退回 spec 级别的估计
+	startupMinSamples = 3
+	// startupPercentile 使用偏保守的分位数：提前量不足比多扩一点代价更大
+	startupPercentile = 0.9
+)
+
+// StartupTime 一个 spec（及镜像）从扩容到 worker 就绪的典型耗时
+type StartupTime struct {
+	SpecName string        `json:"specName"`
+	Image    string        `json:"image,omitempty"` // 为空表示 spec 下所有镜像
+	Samples  int           `json:"samples"`
+	Estimate time.Duration `json:"estimate"`
+}
+
+// StartupModel 从冷启动指标（pod 创建 -> worker 首次心跳）学习每个 spec/image 的启动耗时，
+// 供扩容提前量计算使用
+type StartupModel struct {
+	repo *mysql.MonitoringRepository
+	now  func() time.Time
+
+	mu          sync.RWMutex
+	bySpecImage map[string]*StartupTime // key: spec + "\x00" + image
+	bySpec      map[string]*StartupTime
+	refreshedAt time.Time
+}
+
+// NewStartupModel 创建启动耗时模型
+func NewStartupModel(repo *mysql.MonitoringRepository) *StartupModel {
+	return &StartupModel{
+		repo:        repo,
+		now:         time.Now,
+		bySpecImage: make(map[string]*StartupTime),
+		bySpec:      make(map[string]*StartupTime),
+	}
+}
+
+// RefreshIfStale 超过刷新间隔时重新加载样本；失败时保留上次的估计
+func (m *StartupModel) RefreshIfStale(ctx context.Context) {
+	if m == nil || m.repo == nil {
+		return
+	}
+	m.mu.RLock()
+	fresh := !m.refreshedAt.IsZero() && m.now().Sub(m.refreshedAt) < startupRefreshInterval
+	m.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	now := m.now()
+	samples, err := m.repo.ListColdStartSamples(ctx, "", now.Add(-startupLookback), now)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to refresh startup time model: %v", err)
+		// 避免每次评估都重试
+		m.mu.Lock()
+		m.refreshedAt = now
+		m.mu.Unlock()
+		return
+	}
+	bySpecImage, bySpec := buildStartupTimes(samples)
+
+	m.mu.Lock()
+	m.bySpecImage = bySpecImage
+	m.bySpec = bySpec
+	m.refreshedAt = now
+	m.mu.Unlock()
+}
+
+// Estimate 返回 spec/image 的启动耗时估计；样本不足时退回 spec 级别，仍不足返回 0
+func (m *StartupModel) Estimate(specName, image string) time.Duration {
+	if m == nil {
+		return 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if st, ok := m.bySpecImage[startupKey(specName, image)]; ok && st.Samples >= startupMinSamples {
+		return st.Estimate
+	}
+	if st, ok := m.bySpec[specName]; ok && st.Samples >= startupMinSamples {
+		return st.Estimate
+	}
+	return 0
+}
+
+// List 返回所有 spec 及 spec/image 的估计，按 spec、镜像排序
+func (m *StartupModel) List() []*StartupTime {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]*StartupTime, 0, len(m.bySpec)+len(m.bySpecImage))
+	for _, st := range m.bySpec {
+		list = append(list, st)
+	}
+	for _, st := range m.bySpecImage {
+		list = append(list, st)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SpecName != list[j].SpecName {
+			return list[i].SpecName < list[j].SpecName
+		}
+		return list[i].Image < list[j].Image
+	})
+	return list
+}
+
+func startupKey(specName, image string) string {
+	return specName + "\x00" + image
+}
+
+// buildStartupTimes 按 spec/image 与 spec 汇总启动耗时（pod 创建 -> worker 注册）
+func buildStartupTimes(samples []*mysql.ColdStartSample) (bySpecImage, bySpec map[string]*StartupTime) {
+	specImageDurations := make(map[string][]time.Duration)
+	specDurations := make(map[string][]time.Duration)
+	images := make(map[string][2]string)
+	for _, s := range samples {
+		if s.SpecName == "" || s.PodCreatedAt == nil || s.RegisteredAt == nil || s.RegisteredAt.Before(*s.PodCreatedAt) {
+			continue
+		}
+		d := s.RegisteredAt.Sub(*s.PodCreatedAt)
+		key := startupKey(s.SpecName, s.Image)
+		specImageDurations[key] = append(specImageDurations[key], d)
+		specDurations[s.SpecName] = append(specDurations[s.SpecName], d)
+		images[key] = [2]string{s.SpecName, s.Image}
+	}
+
+	bySpecImage = make(map[string]*StartupTime, len(specImageDurations))
+	for key, durations := range specImageDurations {
+		bySpecImage[key] = &StartupTime{SpecName: images[key][0], Image: images[key][1], Samples: len(durations), Estimate: durationPercentile(durations, startupPercentile)}
+	}
+	bySpec = make(map[string]*StartupTime, len(specDurations))
+	for spec, durations := range specDurations {
+		bySpec[spec] = &StartupTime{SpecName: spec, Samples: len(durations), Estimate: durationPercentile(durations, startupPercentile)}
+	}
+	return bySpecImage, bySpec
+}
+
+// durationPercentile 返回最近秩分位数
+func durationPercentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
@@ -0,0 +1,61 @@
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"waverless/pkg/store/mysql"
+)
+
+func coldStart(spec, image string, startup time.Duration) *mysql.ColdStartSample {
+	created := time.Now().Add(-time.Hour)
+	registered := created.Add(startup)
+	return &mysql.ColdStartSample{SpecName: spec, Image: image, PodCreatedAt: &created, RegisteredAt: &registered}
+}
+
+func TestStartupModel_Estimate(t *testing.T) {
+	samples := []*mysql.ColdStartSample{
+		coldStart("a100", "llm:v1", 100*time.Second),
+		coldStart("a100", "llm:v1", 120*time.Second),
+		coldStart("a100", "llm:v1", 300*time.Second),
+		coldStart("a100", "llm:v2", 60*time.Second),
+		{SpecName: "a100", Image: "llm:v1"}, // Never registered
+	}
+	m := NewStartupModel(nil)
+	m.bySpecImage, m.bySpec = buildStartupTimes(samples)
+
+	// p90 of the image's samples
+	assert.Equal(t, 300*time.Second, m.Estimate("a100", "llm:v1"))
+	// Too few samples for the image: fall back to the spec
+	assert.Equal(t, 300*time.Second, m.Estimate("a100", "llm:v2"))
+	assert.Equal(t, 300*time.Second, m.Estimate("a100", "unknown"))
+	// No data: no estimate
+	assert.Zero(t, m.Estimate("h100", "llm:v1"))
+
+	list := m.List()
+	if assert.Len(t, list, 3) {
+		assert.Equal(t, "", list[0].Image)
+		assert.Equal(t, 4, list[0].Samples)
+		assert.Equal(t, "llm:v1", list[1].Image)
+	}
+
+	var nilModel *StartupModel
+	assert.Zero(t, nilModel.Estimate("a100", "llm:v1"))
+}
+
+func TestQueueGrowth(t *testing.T) {
+	now := time.Now()
+	g := &queueGrowth{}
+	assert.Zero(t, g.observe(10, now))
+
+	// +20 tasks in 10s: 2 tasks/s, smoothed from 0
+	assert.InDelta(t, 1.0, g.observe(30, now.Add(10*time.Second)), 0.001)
+	// Observations closer than a second don't move the rate
+	assert.InDelta(t, 1.0, g.observe(100, now.Add(10500*time.Millisecond)), 0.001)
+	assert.InDelta(t, 1.5, g.observe(50, now.Add(20*time.Second)), 0.001)
+
+	// After a long gap the rate starts over
+	assert.Zero(t, g.observe(500, now.Add(time.Hour)))
+}
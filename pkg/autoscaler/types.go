@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"waverless/pkg/interfaces"
+	"waverless/pkg/maintenance"
 )
 
 // Config 自动扩缩容配置
@@ -96,22 +97,29 @@ type ClusterResources struct {
 
 // EndpointStatus Endpoint 状态（用于监控和展示）
 type EndpointStatus struct {
-	Name             string    `json:"name"`
-	Enabled          bool      `json:"enabled"`
-	CurrentReplicas  int       `json:"currentReplicas"`
-	DesiredReplicas  int       `json:"desiredReplicas"`
-	MinReplicas      int       `json:"minReplicas"`
-	MaxReplicas      int       `json:"maxReplicas"`
-	DrainingReplicas int       `json:"drainingReplicas"`
-	PendingTasks     int64     `json:"pendingTasks"`
-	RunningTasks     int64     `json:"runningTasks"`
-	Priority         int       `json:"priority"`
-	EffectivePrio    int       `json:"effectivePrio"`
-	LastScaleTime    time.Time `json:"lastScaleTime"`
-	LastTaskTime     time.Time `json:"lastTaskTime"`
-	IdleTime         float64   `json:"idleTime"` // 秒
-	WaitingTime      float64   `json:"waitingTime"`
-	ResourceUsage    Resources `json:"resourceUsage"`
+	Name             string        `json:"name"`
+	GroupName        string        `json:"groupName,omitempty"`
+	Enabled          bool          `json:"enabled"`
+	CurrentReplicas  int           `json:"currentReplicas"`
+	DesiredReplicas  int           `json:"desiredReplicas"`
+	MinReplicas      int           `json:"minReplicas"`
+	MaxReplicas      int           `json:"maxReplicas"`
+	DrainingReplicas int           `json:"drainingReplicas"`
+	PendingTasks     int64         `json:"pendingTasks"`
+	RunningTasks     int64         `json:"runningTasks"`
+	Priority         int           `json:"priority"`
+	EffectivePrio    int           `json:"effectivePrio"`
+	LastScaleTime    time.Time     `json:"lastScaleTime"`
+	LastTaskTime     time.Time     `json:"lastTaskTime"`
+	IdleTime         float64       `json:"idleTime"` // 秒
+	WaitingTime      float64       `json:"waitingTime"`
+	ResourceUsage    Resources     `json:"resourceUsage"`
+	EvalInterval     int           `json:"evaluationInterval"` // 实际评估间隔（秒）
+	LastEvaluated    time.Time     `json:"lastEvaluated"`
+	StartupEstimate  float64       `json:"startupEstimate,omitempty"` // 启动耗时估计（秒），配置了排队 SLO 时
+	QueueGrowthRate  float64       `json:"queueGrowthRate,omitempty"` // 排队任务增长速度（任务数/秒）
+	MetricValues     []MetricValue `json:"metricValues,omitempty"`    // 各指标源的最新读数及所需副本数
+	ActiveSchedule   string        `json:"activeSchedule,omitempty"`  // 当前生效的定时副本计划（其 min/max 覆盖 endpoint 配置）
 }
 
 // ClusterResourcesStatus 集群资源状态（轻量版）
@@ -134,4 +142,7 @@ type AutoScalerStatus struct {
 	BlockedEndpoints  []string               `json:"blockedEndpoints"`
 	StarvingEndpoints []string               `json:"starvingEndpoints"`
 	Metrics           map[string]interface{} `json:"metrics"`
+
+	// 紧急制动生效时扩缩容暂停
+	EmergencyBrake *maintenance.BrakeState `json:"emergencyBrake,omitempty"`
 }
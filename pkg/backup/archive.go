@@ -0,0 +1,148 @@
+// Package backup defines the disaster-recovery archive of control-plane state: endpoint
+// metadata, autoscaler configs, specs, registry mirrors and (encrypted) registry credentials.
+//
+// The archive is a single versioned JSON document. Credentials are sealed with AES-256-GCM
+// under a key derived from an operator-supplied passphrase, so an archive can be stored
+// next to ordinary backups without exposing registry passwords.
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+const (
+	// ArchiveVersion is bumped whenever a section is added or a field changes meaning
+	ArchiveVersion = 1
+
+	kdfName          = "pbkdf2-sha256"
+	kdfIterations    = 600000
+	kdfSaltSize      = 16
+	minPassphraseLen = 12
+)
+
+var (
+	ErrPassphraseRequired = errors.New("passphrase is required to seal or open credentials")
+	ErrWrongPassphrase    = errors.New("failed to decrypt credentials: wrong passphrase or corrupted archive")
+)
+
+// Archive is a point-in-time copy of the control-plane state
+type Archive struct {
+	Version           int                       `json:"version"`
+	ExportedAt        time.Time                 `json:"exportedAt"`
+	Endpoints         []*model.Endpoint         `json:"endpoints"`
+	AutoscalerConfigs []*model.AutoscalerConfig `json:"autoscalerConfigs"`
+	Specs             []*model.Spec             `json:"specs"`
+	RegistryMirrors   []*model.RegistryMirror   `json:"registryMirrors"`
+	Credentials       *SealedCredentials        `json:"credentials,omitempty"` // Omitted when exported without a passphrase
+}
+
+// RegistryCredential is the image pull secret of one endpoint
+type RegistryCredential struct {
+	Endpoint         string `json:"endpoint"`
+	DockerConfigJSON []byte `json:"dockerConfigJson"`
+}
+
+// SealedCredentials holds registry credentials encrypted with a passphrase-derived key
+type SealedCredentials struct {
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Validate checks that the archive can be imported by this version
+func (a *Archive) Validate() error {
+	if a.Version == 0 {
+		return fmt.Errorf("archive version is missing")
+	}
+	if a.Version > ArchiveVersion {
+		return fmt.Errorf("archive version %d is newer than supported version %d", a.Version, ArchiveVersion)
+	}
+	return nil
+}
+
+// SealCredentials encrypts registry credentials with a key derived from passphrase
+func SealCredentials(creds []RegistryCredential, passphrase string) (*SealedCredentials, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	if len(passphrase) < minPassphraseLen {
+		return nil, fmt.Errorf("passphrase must be at least %d characters", minPassphraseLen)
+	}
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	salt := make([]byte, kdfSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := newGCM(passphrase, salt, kdfIterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return &SealedCredentials{
+		KDF:        kdfName,
+		Iterations: kdfIterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, []byte(kdfName)),
+	}, nil
+}
+
+// OpenCredentials decrypts credentials sealed by SealCredentials
+func OpenCredentials(sealed *SealedCredentials, passphrase string) ([]RegistryCredential, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	if sealed.KDF != kdfName || sealed.Iterations <= 0 {
+		return nil, fmt.Errorf("unsupported credential key derivation %q", sealed.KDF)
+	}
+	gcm, err := newGCM(passphrase, sealed.Salt, sealed.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed.Nonce) != gcm.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plaintext, err := gcm.Open(nil, sealed.Nonce, sealed.Ciphertext, []byte(kdfName))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	var creds []RegistryCredential
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// newGCM derives an AES-256 key from the passphrase and returns its GCM cipher
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
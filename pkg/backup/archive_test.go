@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+func TestSealAndOpenCredentials(t *testing.T) {
+	creds := []RegistryCredential{{Endpoint: "flux", DockerConfigJSON: []byte(`{"auths":{"ghcr.io":{"password":"hunter2"}}}`)}}
+
+	sealed, err := SealCredentials(creds, "correct horse battery")
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed.Ciphertext), "hunter2")
+
+	// Survives the JSON roundtrip of the archive
+	data, err := json.Marshal(sealed)
+	require.NoError(t, err)
+	var decoded SealedCredentials
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	opened, err := OpenCredentials(&decoded, "correct horse battery")
+	require.NoError(t, err)
+	assert.Equal(t, creds, opened)
+
+	_, err = OpenCredentials(&decoded, "wrong horse battery")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+	_, err = OpenCredentials(&decoded, "")
+	assert.ErrorIs(t, err, ErrPassphraseRequired)
+
+	_, err = SealCredentials(creds, "short")
+	assert.Error(t, err)
+}
+
+func TestArchiveValidate(t *testing.T) {
+	assert.NoError(t, (&Archive{Version: ArchiveVersion}).Validate())
+	assert.Error(t, (&Archive{}).Validate())
+	assert.Error(t, (&Archive{Version: ArchiveVersion + 1}).Validate())
+}
+
+func TestCompare(t *testing.T) {
+	current := &model.Endpoint{ID: 7, Endpoint: "flux", Image: "flux:1", Replicas: 2, HealthStatus: "DEGRADED"}
+	desired := &model.Endpoint{ID: 1, Endpoint: "flux", Image: "flux:2", Replicas: 2, HealthStatus: "HEALTHY"}
+
+	change, err := Compare(KindEndpoint, "flux", true, current, desired)
+	require.NoError(t, err)
+	assert.Equal(t, ActionUpdate, change.Action)
+	// id and health are volatile and not reported
+	assert.Equal(t, []string{"image"}, change.Fields)
+
+	desired.Image = "flux:1"
+	change, err = Compare(KindEndpoint, "flux", true, current, desired)
+	require.NoError(t, err)
+	assert.Equal(t, ActionUnchanged, change.Action)
+
+	change, err = Compare(KindEndpoint, "flux", false, nil, desired)
+	require.NoError(t, err)
+	assert.Equal(t, ActionCreate, change.Action)
+
+	plan := &Plan{}
+	plan.Add(Change{Action: ActionCreate})
+	plan.Add(Change{Action: ActionUpdate})
+	plan.Add(Change{Action: ActionUnchanged})
+	assert.Equal(t, 1, plan.Created)
+	assert.Equal(t, 1, plan.Updated)
+	assert.Equal(t, 1, plan.Unchanged)
+}
@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Archive sections, used as Change.Kind
+const (
+	KindSpec             = "spec"
+	KindRegistryMirror   = "registryMirror"
+	KindEndpoint         = "endpoint"
+	KindAutoscalerConfig = "autoscalerConfig"
+	KindCredential       = "credential"
+)
+
+// ChangeAction is what an import does to one item
+type ChangeAction string
+
+const (
+	ActionCreate    ChangeAction = "create"
+	ActionUpdate    ChangeAction = "update"
+	ActionUnchanged ChangeAction = "unchanged"
+)
+
+// Change describes the effect of importing one archived item
+type Change struct {
+	Kind   string       `json:"kind"`
+	Name   string       `json:"name"`
+	Action ChangeAction `json:"action"`
+	Fields []string     `json:"fields,omitempty"` // Changed fields (update only)
+}
+
+// Plan is the result of an import; in dry-run mode nothing was written
+type Plan struct {
+	DryRun    bool     `json:"dryRun"`
+	Created   int      `json:"created"`
+	Updated   int      `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+	Changes   []Change `json:"changes"`
+}
+
+// Add records a change and updates the counters
+func (p *Plan) Add(change Change) {
+	switch change.Action {
+	case ActionCreate:
+		p.Created++
+	case ActionUpdate:
+		p.Updated++
+	default:
+		p.Unchanged++
+	}
+	p.Changes = append(p.Changes, change)
+}
+
+// volatileFields are maintained by the running system (ids, timestamps, observed state)
+// and are neither compared nor restored
+var volatileFields = map[string]map[string]bool{
+	KindSpec:           {"id": true, "created_at": true, "updated_at": true},
+	KindRegistryMirror: {"id": true, "created_at": true, "updated_at": true},
+	KindEndpoint: {
+		"id": true, "created_at": true, "updated_at": true,
+		"runtime_state": true, "health_status": true, "health_reason": true, "health_message": true,
+		"last_health_check_at": true, "image_last_checked": true, "latest_image": true,
+	},
+	KindAutoscalerConfig: {
+		"id": true, "created_at": true, "updated_at": true,
+		"last_task_time": true, "last_scale_time": true, "first_pending_time": true,
+	},
+}
+
+// Compare builds the change for one item. current is nil-able only through exists=false,
+// since typed nil pointers cannot be detected generically.
+func Compare(kind, name string, exists bool, current, desired interface{}) (Change, error) {
+	change := Change{Kind: kind, Name: name}
+	if !exists {
+		change.Action = ActionCreate
+		return change, nil
+	}
+	fields, err := diffFields(current, desired, volatileFields[kind])
+	if err != nil {
+		return change, fmt.Errorf("failed to compare %s %s: %w", kind, name, err)
+	}
+	if len(fields) == 0 {
+		change.Action = ActionUnchanged
+		return change, nil
+	}
+	change.Action = ActionUpdate
+	change.Fields = fields
+	return change, nil
+}
+
+// diffFields returns the sorted JSON field names whose values differ
+func diffFields(current, desired interface{}, ignored map[string]bool) ([]string, error) {
+	a, err := toFieldMap(current)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toFieldMap(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for key, value := range b {
+		if ignored[key] {
+			continue
+		}
+		if !reflect.DeepEqual(a[key], value) {
+			fields = append(fields, key)
+		}
+	}
+	for key := range a {
+		if _, ok := b[key]; !ok && !ignored[key] {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func toFieldMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
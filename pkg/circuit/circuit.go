@@ -0,0 +1,68 @@
+// Package circuit implements the endpoint circuit breaker. An endpoint failing nearly every
+// task (e.g. the model crashes on every request) trips its breaker: submissions fail fast and
+// the autoscaler stops adding workers that would fail the same way. After a delay the breaker
+// turns half-open and admits a probe; a task succeeding closes it again, a failure reopens it
+// with a longer delay.
+package circuit
+
+import (
+	"fmt"
+	"time"
+)
+
+// States of an endpoint's circuit breaker
+const (
+	StateClosed   = ""          // Tasks flow normally
+	StateOpen     = "open"      // Submissions fail fast, scale-up is held
+	StateHalfOpen = "half_open" // One probe task is admitted, scaling up to one replica for it
+)
+
+// Policy decides when a breaker trips
+type Policy struct {
+	MinTasks    int64   // Finished tasks in the window before the failure rate counts
+	FailureRate float64 // Failed share of finished tasks that trips the breaker
+}
+
+// Trip reports whether failed out of finished tasks trips the breaker, with the reason shown
+// to clients and operators
+func (p Policy) Trip(failed, finished int64) (bool, string) {
+	if finished == 0 || finished < p.MinTasks {
+		return false, ""
+	}
+	rate := float64(failed) / float64(finished)
+	if rate < p.FailureRate {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%d of the last %d tasks failed (%.0f%%)", failed, finished, rate*100)
+}
+
+// ProbeDelay returns how long a breaker stays open before a probe: base, doubled for every
+// failed probe, at most maxDelay
+func ProbeDelay(failedProbes int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 0; i < failedProbes && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// Verdict is what the tasks started since a breaker turned half-open say
+type Verdict int
+
+const (
+	VerdictPending Verdict = iota // No started task finished yet
+	VerdictClose                  // A task succeeded
+	VerdictReopen                 // Tasks failed and none succeeded
+)
+
+// Judge decides a half-open breaker from the outcomes of tasks started since it turned half-open
+func Judge(completed, failed int64) Verdict {
+	switch {
+	case completed > 0:
+		return VerdictClose
+	case failed > 0:
+		return VerdictReopen
+	default:
+		return VerdictPending
+	}
+}
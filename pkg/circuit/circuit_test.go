@@ -0,0 +1,36 @@
+package circuit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyTrip(t *testing.T) {
+	p := Policy{MinTasks: 10, FailureRate: 0.9}
+
+	trip, _ := p.Trip(9, 9)
+	assert.False(t, trip, "too few tasks")
+	trip, _ = p.Trip(8, 10)
+	assert.False(t, trip)
+	trip, _ = p.Trip(0, 0)
+	assert.False(t, trip)
+
+	trip, reason := p.Trip(19, 20)
+	assert.True(t, trip)
+	assert.Equal(t, "19 of the last 20 tasks failed (95%)", reason)
+}
+
+func TestProbeDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, ProbeDelay(0, time.Minute, 15*time.Minute))
+	assert.Equal(t, 4*time.Minute, ProbeDelay(2, time.Minute, 15*time.Minute))
+	assert.Equal(t, 15*time.Minute, ProbeDelay(10, time.Minute, 15*time.Minute))
+	assert.Equal(t, 15*time.Minute, ProbeDelay(1000, time.Minute, 15*time.Minute))
+}
+
+func TestJudge(t *testing.T) {
+	assert.Equal(t, VerdictPending, Judge(0, 0))
+	assert.Equal(t, VerdictReopen, Judge(0, 3))
+	assert.Equal(t, VerdictClose, Judge(1, 3))
+}
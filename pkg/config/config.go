@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
@@ -25,8 +26,688 @@ type Config struct {
 	Notification     NotificationConfig     `yaml:"notification"`        // Notification configuration
 	Providers        *ProvidersConfig       `yaml:"providers,omitempty"` // Providers configuration (optional)
 	Novita           NovitaConfig           `yaml:"novita"`              // Novita serverless configuration
+	RunPod           RunPodConfig           `yaml:"runpod"`              // RunPod serverless configuration
 	ImageValidation  ImageValidationConfig  `yaml:"imageValidation"`     // Image validation configuration
+	ImageCopy        ImageCopyConfig        `yaml:"imageCopy"`           // Copy external images into the internal registry on deploy
 	ResourceReleaser ResourceReleaserConfig `yaml:"resourceReleaser"`    // Resource releaser configuration
+	Reporting        ReportingConfig        `yaml:"reporting"`           // Usage statistics reporting configuration
+	Export           ExportConfig           `yaml:"export"`              // Analytics export configuration
+	LogShipping      LogShippingConfig      `yaml:"logShipping"`         // Worker log shipping configuration
+	Coordination     CoordinationConfig     `yaml:"coordination"`        // Multi-replica coordination (roles, leader lease)
+	Security         SecurityConfig         `yaml:"security"`            // Org-level worker privilege policy
+	DataPlane        DataPlaneConfig        `yaml:"dataPlane"`           // Signed tokens for direct worker invocation
+	Maintenance      MaintenanceConfig      `yaml:"maintenance"`         // Read-only mode for maintenance windows
+	Enrichment       EnrichmentConfig       `yaml:"enrichment"`          // Live runtime status on endpoint lists
+	Federation       FederationConfig       `yaml:"federation"`          // Region hints for federated endpoints
+	Sampling         SamplingConfig         `yaml:"sampling"`            // Task input/output sampling for offline evaluation
+	Approval         ApprovalConfig         `yaml:"approval"`            // Second-approver gate for protected endpoints
+	Anomaly          AnomalyConfig          `yaml:"anomaly"`             // Usage anomaly detection and alerting
+	Encryption       EncryptionConfig       `yaml:"encryption"`          // Task payload encryption at rest
+	StatusPage       StatusPageConfig       `yaml:"statusPage"`          // Client-visible endpoint status for external status pages
+	Scheduler        SchedulerConfig        `yaml:"scheduler"`           // Task-to-worker assignment policies
+	Hedging          HedgingConfig          `yaml:"hedging"`             // Duplicate execution of slow tasks of latency-critical endpoints
+	Wake             WakeConfig             `yaml:"wake"`                // Wake-on-request for endpoints scaled to zero
+	CircuitBreaker   CircuitBreakerConfig   `yaml:"circuitBreaker"`      // Fast-fail endpoints that fail nearly every task
+	Discovery        DiscoveryConfig        `yaml:"discovery"`           // Register endpoints in Consul or external-dns
+	TaskJournal      TaskJournalConfig      `yaml:"taskJournal"`         // Task event journal on a Redis Stream
+	Quota            QuotaConfig            `yaml:"quota"`               // Per-project GPU/replica quotas with headroom forecasting
+	ModelRegistry    ModelRegistryConfig    `yaml:"modelRegistry"`       // Deploy endpoints by model name and version
+	LoadTest         LoadTestConfig         `yaml:"loadTest"`            // Scheduled load tests of staging endpoints
+}
+
+// LoadTestConfig enables load tests: tasks sent to a staging endpoint at the rate of an RPS
+// profile, with reports of latency, errors and autoscaler behavior compared across images
+type LoadTestConfig struct {
+	// Enabled turns on load tests and their schedules (default: false)
+	// Environment variable: LOAD_TEST_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// StagingLabels are the endpoint labels that mark an endpoint as safe to load test
+	// (default: {environment: staging}); endpoints without all of them are refused
+	StagingLabels map[string]string `yaml:"stagingLabels,omitempty"`
+
+	// MaxRPS caps the rate of every profile stage (default: 50)
+	MaxRPS float64 `yaml:"maxRps"`
+
+	// MaxDuration caps the duration of a profile (default: 2h)
+	MaxDuration time.Duration `yaml:"maxDuration"`
+}
+
+// Model registry kinds
+const (
+	ModelRegistryMLflow = "mlflow" // MLflow model registry; image and env are read from version tags
+	ModelRegistryHTTP   = "http"   // Custom registry serving model versions as JSON
+)
+
+// ModelRegistryConfig lets endpoints be created with a modelName and modelVersion instead of an
+// image: the image and env are resolved from the registry entry, and the endpoint keeps a link
+// to it. Endpoints deployed this way can be moved to the latest approved version of their model.
+type ModelRegistryConfig struct {
+	// Kind is mlflow or http (empty = disabled)
+	// Environment variable: MODEL_REGISTRY_KIND
+	Kind string `yaml:"kind"`
+
+	// Address is the MLflow tracking server, or the base URL of the custom registry
+	// Environment variable: MODEL_REGISTRY_ADDRESS
+	Address string `yaml:"address"`
+
+	// Token is sent as a bearer token (optional)
+	// Environment variable: MODEL_REGISTRY_TOKEN
+	Token string `yaml:"token"`
+
+	// ApprovedStage is the MLflow stage of versions approved for deployment (default: Production)
+	ApprovedStage string `yaml:"approvedStage"`
+
+	// ApprovedAlias is the MLflow alias of the approved version, e.g. champion; takes precedence
+	// over ApprovedStage
+	ApprovedAlias string `yaml:"approvedAlias"`
+}
+
+// QuotaConfig declares soft GPU and replica quotas per project. Endpoints belong to the project
+// named by their ProjectLabel label. Every interval the current usage and the trend of the daily
+// peak usage over HistoryDays are compared with the quota; projects close to their quota, or
+// forecast to reach it within WarnDays, are published as project.quota integration events.
+type QuotaConfig struct {
+	// Enabled turns on forecasting, warnings and the status API (default: false)
+	// Environment variable: QUOTA_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Interval between forecasting runs (default: 1h)
+	Interval time.Duration `yaml:"interval"`
+
+	// ProjectLabel is the endpoint label holding the project name (default: project)
+	ProjectLabel string `yaml:"projectLabel"`
+
+	// Projects maps a project name to its quota, e.g. {"search": {gpus: 32, replicas: 40}}
+	Projects map[string]ProjectQuota `yaml:"projects,omitempty"`
+
+	// WarnPercent warns once usage reaches this percentage of a quota (default: 80)
+	WarnPercent float64 `yaml:"warnPercent"`
+
+	// WarnDays warns once the forecast days to exhaustion drop to this value (default: 14)
+	WarnDays float64 `yaml:"warnDays"`
+
+	// HistoryDays is the usage history the trend is fitted on (default: 14)
+	HistoryDays int `yaml:"historyDays"`
+
+	// Cooldown is the minimum time between warnings per project and resource (default: 24h)
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+// ProjectQuota is the quota of one project; 0 means unlimited
+type ProjectQuota struct {
+	GPUs     int `yaml:"gpus" json:"gpus"`         // Total GPUs of the project's running replicas
+	Replicas int `yaml:"replicas" json:"replicas"` // Total replicas across the project's endpoints
+}
+
+// TaskJournalConfig relays every task state transition from the task_events table to a Redis
+// Stream, so analytics and billing pipelines consume events through consumer groups instead
+// of polling the tasks table. Delivery is at-least-once; entries carry event_id for de-duplication.
+type TaskJournalConfig struct {
+	// Enabled indicates whether the relay runs (default: false)
+	// Environment variable: TASK_JOURNAL_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Stream is the Redis Stream key (default: waverless:task-events)
+	// Environment variable: TASK_JOURNAL_STREAM
+	Stream string `yaml:"stream"`
+
+	// MaxLen caps the stream length approximately; older entries are trimmed (default: 1000000, -1 = unbounded)
+	MaxLen int64 `yaml:"maxLen"`
+
+	// Groups are consumer groups created on the stream, starting at its first entry
+	Groups []string `yaml:"groups"`
+
+	// Interval between relay runs (default: 5s)
+	Interval time.Duration `yaml:"interval"`
+
+	// BatchSize is the number of events appended per round trip (default: 500)
+	BatchSize int `yaml:"batchSize"`
+}
+
+// Service discovery modes
+const (
+	DiscoveryModeConsul      = "consul"       // Register a Consul service per endpoint
+	DiscoveryModeExternalDNS = "external-dns" // Create an annotated ExternalName Service per endpoint
+)
+
+// DiscoveryConfig registers each endpoint's data plane address (server.base_url, tasks are
+// submitted to <base_url>/v1/<endpoint>) when it is deployed and deregisters it when it is
+// deleted, so downstream services find endpoints by name.
+type DiscoveryConfig struct {
+	// Mode is consul or external-dns (empty = disabled)
+	// Environment variable: DISCOVERY_MODE
+	Mode string `yaml:"mode"`
+
+	// Address overrides server.base_url as the registered data plane address
+	Address string `yaml:"address"`
+
+	Consul      ConsulDiscoveryConfig      `yaml:"consul"`
+	ExternalDNS ExternalDNSDiscoveryConfig `yaml:"externalDNS"`
+}
+
+// ConsulDiscoveryConfig registers endpoints as services of the local Consul agent
+type ConsulDiscoveryConfig struct {
+	// Address of the Consul HTTP API (default: http://127.0.0.1:8500)
+	// Environment variable: CONSUL_HTTP_ADDR
+	Address string `yaml:"address"`
+
+	// Token is the ACL token (optional)
+	// Environment variable: CONSUL_HTTP_TOKEN
+	Token string `yaml:"token"`
+
+	// Tags added to every endpoint service ("waverless" is always set)
+	Tags []string `yaml:"tags"`
+}
+
+// ExternalDNSDiscoveryConfig creates a Service <endpoint>-dns of type ExternalName per endpoint.
+// external-dns publishes it as a CNAME <endpoint>.<domain> to the data plane host.
+type ExternalDNSDiscoveryConfig struct {
+	// Domain endpoint hostnames are created in, e.g. endpoints.example.com (required)
+	Domain string `yaml:"domain"`
+
+	// TTL of the DNS records in seconds (0 = external-dns default)
+	TTL int `yaml:"ttl"`
+}
+
+// CircuitBreakerConfig trips an endpoint's circuit breaker when nearly all its recent tasks
+// fail: submissions get 503 and scale-up is held. After ProbeAfter the breaker admits one
+// probe task (scaling to one replica if needed); a success closes it, a failure reopens it and
+// doubles the delay up to MaxProbeAfter.
+type CircuitBreakerConfig struct {
+	// Environment variable: CIRCUIT_BREAKER_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Window of finished tasks the failure rate is computed over (default: 5m)
+	Window time.Duration `yaml:"window"`
+
+	// MinTasks is the number of finished tasks within Window before an endpoint can trip (default: 20)
+	MinTasks int64 `yaml:"minTasks"`
+
+	// FailureRate is the failed share of finished tasks that trips the breaker (default: 0.9)
+	FailureRate float64 `yaml:"failureRate"`
+
+	// ProbeAfter is how long a tripped breaker stays open before a probe (default: 1m)
+	ProbeAfter time.Duration `yaml:"probeAfter"`
+
+	// MaxProbeAfter caps the delay doubled by every failed probe (default: 15m)
+	MaxProbeAfter time.Duration `yaml:"maxProbeAfter"`
+
+	// Interval between checks (default: 15s)
+	Interval time.Duration `yaml:"interval"`
+}
+
+// WakeConfig bounds wake-on-request: a task submitted to an endpoint scaled to zero triggers an
+// immediate scale-up, and the submission may wait for the first worker (?wait_ready=). A
+// submission still cold when it returns gets 202 Accepted with a Retry-After estimate.
+type WakeConfig struct {
+	// ColdStartBudget caps how long a submission waits for a worker (default: 60s)
+	ColdStartBudget time.Duration `yaml:"coldStartBudget"`
+
+	// DefaultColdStart is the cold start assumed for endpoints without one in the last
+	// 7 days, used for Retry-After (default: 30s)
+	DefaultColdStart time.Duration `yaml:"defaultColdStart"`
+}
+
+// SchedulerConfig selects the policy that picks the queued tasks a pulling worker gets.
+// Policies: fifo (oldest first, idle workers preferred) and fair-share (turns across the
+// subject keys of tasks). A shadow policy runs on the same pulls without assigning anything;
+// GET /api/v1/scheduler compares its picks with the endpoint's policy.
+type SchedulerConfig struct {
+	// Policy of endpoints without their own (default: fifo)
+	Policy string `yaml:"policy"`
+
+	// Endpoints overrides the policy per endpoint, e.g. {"llm": "fair-share"}
+	Endpoints map[string]string `yaml:"endpoints,omitempty"`
+
+	// Shadow sets a shadow policy per endpoint, e.g. {"flux-dev": "fair-share"}
+	Shadow map[string]string `yaml:"shadow,omitempty"`
+}
+
+// HedgingConfig enables hedged execution for latency-critical endpoints: a task running longer
+// than the endpoint's recent p99 execution time is duplicated on an idle worker, the first
+// result wins and the other copy is cancelled. Hedges are only dispatched while nothing is
+// queued, at most one per idle worker.
+type HedgingConfig struct {
+	// Environment variable: HEDGING_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Endpoints are the latency-critical endpoints whose tasks are hedged
+	Endpoints []string `yaml:"endpoints,omitempty"`
+
+	// Multiplier scales the p99 execution time into the hedge threshold (default: 1.0)
+	Multiplier float64 `yaml:"multiplier"`
+
+	// MinDelay is the smallest hedge threshold (default: 1s)
+	MinDelay time.Duration `yaml:"minDelay"`
+
+	// MinSamples is the number of completed tasks within Lookback needed before an endpoint
+	// is hedged (default: 20)
+	MinSamples int `yaml:"minSamples"`
+
+	// Lookback is the window of completed tasks the p99 is computed over (default: 1h)
+	Lookback time.Duration `yaml:"lookback"`
+
+	// Interval between checks for overdue tasks (default: 5s)
+	Interval time.Duration `yaml:"interval"`
+}
+
+// StatusPageConfig exposes endpoint status per project at GET /status/v1/projects/:project
+// for external status pages: health, queue saturation and the success rate of the last 24h.
+// Endpoints are only listed when their exposure label is full or health.
+type StatusPageConfig struct {
+	// Enabled turns on the status API (default: false)
+	// Environment variable: STATUS_PAGE_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Token is required as a bearer token or ?token= when set; empty = unauthenticated
+	// Environment variable: STATUS_PAGE_TOKEN
+	Token string `yaml:"token"`
+
+	// ProjectLabel is the endpoint label naming its project (default: project)
+	ProjectLabel string `yaml:"projectLabel"`
+
+	// ExposureLabel is the endpoint label selecting what is shown: full or health; other values hide the endpoint (default: status-page)
+	ExposureLabel string `yaml:"exposureLabel"`
+
+	// CacheTTL is how long a project's status is served from memory (default: 30s)
+	CacheTTL time.Duration `yaml:"cacheTTL"`
+}
+
+// EncryptionConfig encrypts task input/output at rest (MySQL and sampled datasets) for
+// endpoints labeled with a project. Payloads are sealed with AES-256-GCM under a per-project
+// data key; data keys are stored wrapped by Vault transit when configured, otherwise by the
+// local master key. API consumers and workers always see plaintext.
+type EncryptionConfig struct {
+	// Enabled turns on encryption of new payloads (default: false)
+	// Environment variable: ENCRYPTION_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// ProjectLabel is the endpoint label naming its project; unlabeled endpoints are not encrypted (default: project)
+	ProjectLabel string `yaml:"projectLabel"`
+
+	// MasterKey is the base64-encoded 32-byte local key-encryption key, used when Vault is not configured
+	// Environment variable: ENCRYPTION_MASTER_KEY
+	MasterKey string `yaml:"masterKey"`
+
+	// Vault wraps data keys with Vault transit (one transit key per project)
+	Vault VaultTransitConfig `yaml:"vault"`
+}
+
+// VaultTransitConfig configures HashiCorp Vault's transit secrets engine
+type VaultTransitConfig struct {
+	// Address of Vault, e.g. https://vault.example.com:8200; empty = use the local master key
+	Address string `yaml:"address"`
+
+	// Token authenticates against Vault
+	// Environment variable: VAULT_TOKEN
+	Token string `yaml:"token"`
+
+	// Mount is the transit engine mount path (default: transit)
+	Mount string `yaml:"mount"`
+
+	// KeyPrefix is prepended to the project name to form the transit key name (default: waverless-)
+	KeyPrefix string `yaml:"keyPrefix"`
+}
+
+// AnomalyConfig controls usage anomaly detection. Every interval the last Window of each
+// endpoint's statistics is compared with its hourly statistics of the preceding BaselineDays.
+// Anomalies (failure rate spike, GPU-hour surge, queue latency runaway) are recorded as endpoint
+// warnings and published as endpoint.anomaly integration events.
+type AnomalyConfig struct {
+	// Enabled turns on detection (default: false)
+	// Environment variable: ANOMALY_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Interval between detection runs (default: 5m)
+	Interval time.Duration `yaml:"interval"`
+
+	// Window is the recent period compared with the baseline (default: 15m)
+	Window time.Duration `yaml:"window"`
+
+	// BaselineDays is the history the baseline is built from (default: 7)
+	BaselineDays int `yaml:"baselineDays"`
+
+	// Sensitivity is the default sensitivity: low, normal, high or off (default: normal)
+	Sensitivity string `yaml:"sensitivity"`
+
+	// Endpoints overrides the sensitivity per endpoint, e.g. {"flux-dev": "off", "llm": "high"}
+	Endpoints map[string]string `yaml:"endpoints,omitempty"`
+
+	// Cooldown is the minimum time between alerts of the same kind per endpoint (default: 1h)
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+// SensitivityFor returns the sensitivity configured for an endpoint
+func (c AnomalyConfig) SensitivityFor(endpoint string) string {
+	if s, ok := c.Endpoints[endpoint]; ok {
+		return s
+	}
+	return c.Sensitivity
+}
+
+// ApprovalConfig requires a second person for mutating operations on protected endpoints.
+// Deploys, deployment/config updates and deletes of an endpoint carrying a protected label
+// are stored as pending change requests and only run once another identity approves them
+// via POST /api/v1/change-requests/:id/approve. Requesters and approvers are identified by
+// their own token (X-Approval-Token header), never by a free-form name.
+type ApprovalConfig struct {
+	// Enabled turns on the approval gate (default: false); requires Approvers
+	// Environment variable: APPROVAL_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Labels that make an endpoint protected, any one matching is enough (default: environment=prod)
+	Labels map[string]string `yaml:"labels"`
+
+	// Approvers may request and approve changes (never their own)
+	Approvers []ApprovalIdentity `yaml:"approvers,omitempty"`
+
+	// Requesters may request changes but not approve them
+	Requesters []ApprovalIdentity `yaml:"requesters,omitempty"`
+
+	// TTL is how long a change request stays approvable (default: 24h)
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// ApprovalIdentity is a person taking part in change requests
+type ApprovalIdentity struct {
+	Name  string `yaml:"name"`  // Recorded as requester/reviewer in the audit trail
+	Token string `yaml:"token"` // Secret sent in the X-Approval-Token header
+}
+
+// SamplingConfig controls where sampled task input/output pairs are stored.
+// Which tasks are sampled is set per endpoint via /api/v1/endpoints/:name/sampling.
+type SamplingConfig struct {
+	// Enabled turns on capturing (default: false); rules can be managed either way
+	// Environment variable: SAMPLING_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Store is the datasets store: local, s3
+	Store string `yaml:"store"`
+
+	// BatchSize is the number of samples per uploaded object (default: 500)
+	BatchSize int `yaml:"batchSize"`
+
+	// FlushInterval is the max time a sample stays buffered (default: 1m)
+	FlushInterval time.Duration `yaml:"flushInterval"`
+
+	// QueueSize is the number of buffered samples before new ones are dropped (default: 10000)
+	QueueSize int `yaml:"queueSize"`
+
+	// Scrubbers are the built-in PII patterns redacted in every sample:
+	// email, phone, credit_card, ipv4 (default: email, phone, credit_card)
+	Scrubbers []string `yaml:"scrubbers"`
+
+	// ScrubFields are input/output keys redacted in every sample, on top of per-rule fields
+	ScrubFields []string `yaml:"scrubFields,omitempty"`
+
+	Local ExportLocalConfig `yaml:"local"`
+	S3    ExportS3Config    `yaml:"s3"`
+}
+
+// FederationConfig controls how tasks of federated endpoints pick a region.
+// Clients can pass a region or per-region latencies with the task; without a region,
+// the client IP is matched against SourceRegions.
+type FederationConfig struct {
+	// SourceRegions maps client networks to regions (first match wins)
+	SourceRegions []SourceRegion `yaml:"sourceRegions,omitempty"`
+}
+
+// SourceRegion maps a client network to the region nearest to it
+type SourceRegion struct {
+	CIDR   string `yaml:"cidr"`   // e.g. 10.8.0.0/16
+	Region string `yaml:"region"` // Member region name, e.g. us-east
+}
+
+// EnrichmentConfig bounds the live runtime status lookups done by GET /api/v1/endpoints.
+// Lookups run in parallel under a per-provider concurrency limit; endpoints whose lookup
+// misses the budget keep the runtime state persisted in MySQL.
+type EnrichmentConfig struct {
+	// Disabled serves the persisted runtime state only
+	Disabled bool `yaml:"disabled"`
+
+	// ProviderConcurrency limits in-flight status calls per deployment provider
+	// (default: k8s 64, novita 8, others 16)
+	ProviderConcurrency map[string]int `yaml:"providerConcurrency"`
+
+	// CallTimeout bounds a single provider call (default: 100ms)
+	CallTimeout time.Duration `yaml:"callTimeout"`
+
+	// Budget bounds the whole enrichment of one list request (default: 250ms)
+	Budget time.Duration `yaml:"budget"`
+}
+
+// MaintenanceConfig controls the global read-only switch. While read-only, mutating API
+// calls are rejected with 503 and reads, metrics and task status stay available.
+// The switch can also be flipped at runtime via PUT /api/v1/admin/read-only; a config
+// value of true cannot be turned off through the API.
+type MaintenanceConfig struct {
+	// ReadOnly forces read-only mode from startup
+	// Environment variable: MAINTENANCE_READ_ONLY
+	ReadOnly bool `yaml:"readOnly"`
+
+	// Reason is returned to rejected callers
+	// Environment variable: MAINTENANCE_REASON
+	Reason string `yaml:"reason"`
+
+	// RefreshInterval is how often replicas re-read the switch from Redis (default: 2s)
+	RefreshInterval time.Duration `yaml:"refreshInterval"`
+}
+
+// DataPlaneConfig controls signed invoke tokens for the proxy/direct-HTTP mode.
+// When a secret is set, every K8s endpoint gets a derived key (Secret <endpoint>-invoke-key,
+// env WAVERLESS_INVOKE_KEY) and workers reject direct requests without a valid token.
+type DataPlaneConfig struct {
+	// TokenSecret is the master secret endpoint keys are derived from (min 32 chars, empty = disabled)
+	// Environment variable: DATA_PLANE_TOKEN_SECRET
+	TokenSecret string `yaml:"tokenSecret"`
+
+	// TokenTTL is the lifetime of issued tokens (default: 5m)
+	TokenTTL time.Duration `yaml:"tokenTTL"`
+}
+
+// Worker privileges gated by the security policy
+const (
+	PrivilegePrivileged = "privileged" // Privileged containers (from a spec security profile)
+	PrivilegePtrace     = "ptrace"     // SYS_PTRACE capability (enablePtrace)
+)
+
+// SecurityConfig is the org-level policy for elevated worker privileges.
+// Privileges are denied unless allowed globally or granted to an endpoint by an exception.
+type SecurityConfig struct {
+	// AllowPrivileged allows every endpoint to use specs with privileged profiles (default: false)
+	AllowPrivileged bool `yaml:"allowPrivileged"`
+
+	// AllowPtrace allows every endpoint to enable SYS_PTRACE (default: false)
+	AllowPtrace bool `yaml:"allowPtrace"`
+
+	// Exceptions grant privileges to specific endpoints
+	Exceptions []SecurityException `yaml:"exceptions,omitempty"`
+}
+
+// SecurityException grants privileges to matching endpoints
+type SecurityException struct {
+	Endpoint   string    `yaml:"endpoint"`            // Endpoint name; a trailing "*" matches a prefix
+	Privileges []string  `yaml:"privileges"`          // privileged, ptrace
+	Reason     string    `yaml:"reason"`              // Why the exception was granted (audit trail)
+	ExpiresAt  time.Time `yaml:"expiresAt,omitempty"` // Exception is ignored after this time (zero = never expires)
+}
+
+// Server roles for running several control-plane replicas
+const (
+	RoleAll        = "all"        // Serve the API and campaign for the controller lease (default)
+	RoleAPI        = "api"        // Stateless handler tier only: no watch callbacks, controllers or background jobs
+	RoleController = "controller" // Campaign for the controller lease; intended to run outside the API Service
+)
+
+// CoordinationConfig controls how control-plane replicas share informer callbacks and
+// controller loops. Every replica serves the HTTP API; only the replica holding the
+// leader lease acts on watch events and runs the releaser/monitors. Without Redis the
+// lease is in-process and the single replica always leads.
+type CoordinationConfig struct {
+	// Role of this replica: all, api, controller (default: all)
+	// Environment variable: SERVER_ROLE
+	Role string `yaml:"role"`
+
+	// Identity used for the leader lease (default: hostname, i.e. the pod name)
+	// Environment variable: POD_NAME
+	Identity string `yaml:"identity"`
+
+	// LeaseTTL is how long the leader lease survives without renewal (default: 15s)
+	LeaseTTL time.Duration `yaml:"leaseTTL"`
+
+	// RenewInterval is how often the leader renews and followers retry (default: LeaseTTL/3)
+	RenewInterval time.Duration `yaml:"renewInterval"`
+}
+
+// RunsControllers reports whether this replica campaigns for the controller lease
+func (c CoordinationConfig) RunsControllers() bool {
+	return c.Role != RoleAPI
+}
+
+// LogShippingConfig contains configuration for forwarding worker stdout/stderr to Loki or Elasticsearch.
+type LogShippingConfig struct {
+	// Enabled indicates whether worker logs are shipped (default: false)
+	// Environment variable: LOG_SHIPPING_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Backend is the log store: loki, elasticsearch
+	Backend string `yaml:"backend"`
+
+	// Interval between log polls (default: 15s)
+	Interval time.Duration `yaml:"interval"`
+
+	// MaxBytesPerPoll bounds a single read per worker (default: 1MiB)
+	MaxBytesPerPoll int64 `yaml:"maxBytesPerPoll"`
+
+	Loki          LokiConfig          `yaml:"loki"`
+	Elasticsearch ElasticsearchConfig `yaml:"elasticsearch"`
+}
+
+// LokiConfig Grafana Loki backend
+type LokiConfig struct {
+	URL      string `yaml:"url"`      // e.g. http://loki:3100
+	TenantID string `yaml:"tenantId"` // X-Scope-OrgID (optional)
+	Username string `yaml:"username"` // Basic auth (optional)
+	Password string `yaml:"password"` // Environment variable: LOG_SHIPPING_LOKI_PASSWORD
+}
+
+// ElasticsearchConfig Elasticsearch / OpenSearch backend
+type ElasticsearchConfig struct {
+	URL      string `yaml:"url"`      // e.g. http://elasticsearch:9200
+	Index    string `yaml:"index"`    // default: waverless-logs
+	Username string `yaml:"username"` // Basic auth (optional)
+	Password string `yaml:"password"` // Environment variable: LOG_SHIPPING_ES_PASSWORD
+	APIKey   string `yaml:"apiKey"`   // Takes precedence over basic auth. Environment variable: LOG_SHIPPING_ES_API_KEY
+}
+
+// ExportConfig contains configuration for exporting usage and task records to external analytics storage.
+type ExportConfig struct {
+	// Enabled indicates whether the periodic exporter runs (default: false)
+	// Environment variable: EXPORT_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Sink is the export destination: local, s3, bigquery
+	// Environment variable: EXPORT_SINK
+	Sink string `yaml:"sink"`
+
+	// Interval between export runs (default: 10m)
+	Interval time.Duration `yaml:"interval"`
+
+	// BatchSize is the number of rows per exported object / insert request (default: 5000)
+	BatchSize int `yaml:"batchSize"`
+
+	// Datasets to export (default: gpu_usage_records, tasks)
+	Datasets []string `yaml:"datasets"`
+
+	Local    ExportLocalConfig    `yaml:"local"`
+	S3       ExportS3Config       `yaml:"s3"`
+	BigQuery ExportBigQueryConfig `yaml:"bigquery"`
+}
+
+// ExportLocalConfig local filesystem sink (e.g. a mounted bucket)
+type ExportLocalConfig struct {
+	Dir string `yaml:"dir"` // Output directory
+}
+
+// ExportS3Config S3 (or S3-compatible) sink
+type ExportS3Config struct {
+	Bucket          string `yaml:"bucket"`
+	Region          string `yaml:"region"`
+	Prefix          string `yaml:"prefix"`            // Object key prefix
+	Endpoint        string `yaml:"endpoint"`          // Custom endpoint for S3-compatible storage (path-style)
+	AccessKeyID     string `yaml:"access_key_id"`     // Optional, use default credential chain if empty
+	SecretAccessKey string `yaml:"secret_access_key"` // Optional
+}
+
+// ExportBigQueryConfig BigQuery sink (streaming inserts)
+type ExportBigQueryConfig struct {
+	ProjectID   string `yaml:"project_id"`
+	Dataset     string `yaml:"dataset"`
+	TablePrefix string `yaml:"table_prefix"` // Table name = prefix + dataset name
+	// AccessToken is an OAuth2 access token; if empty the GCE metadata server is used
+	// Environment variable: EXPORT_BIGQUERY_ACCESS_TOKEN
+	AccessToken string `yaml:"access_token"`
+}
+
+// ReportingConfig contains configuration for usage statistics reporting.
+// Statistics are always stored in UTC; the timezone only affects how query APIs
+// interpret date boundaries and group daily buckets.
+type ReportingConfig struct {
+	// Timezone is the IANA timezone used for reporting windows (default: UTC).
+	// Can be overridden per request with the timezone query parameter.
+	// Environment variable: REPORTING_TIMEZONE
+	Timezone string `yaml:"timezone"`
+
+	// ProjectLabel is the endpoint label naming its project (default: project)
+	ProjectLabel string `yaml:"projectLabel"`
+
+	// ProjectTimezones maps a project name to the IANA timezone of its reports, e.g.
+	// {"search": "America/New_York"}; endpoint queries of other projects use Timezone
+	ProjectTimezones map[string]string `yaml:"projectTimezones,omitempty"`
+}
+
+// Location returns the reporting timezone location, UTC if unset or invalid
+func (c ReportingConfig) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := LoadReportingLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ProjectLocation returns the reporting timezone of a project, Location if it has none
+func (c ReportingConfig) ProjectLocation(project string) *time.Location {
+	tz, ok := c.ProjectTimezones[project]
+	if !ok || project == "" {
+		return c.Location()
+	}
+	loc, err := LoadReportingLocation(tz)
+	if err != nil {
+		return c.Location()
+	}
+	return loc
+}
+
+// LoadReportingLocation loads an IANA timezone for reports. Statistics are bucketed by UTC
+// hour, so zones whose UTC offset is not a whole number of hours (e.g. Asia/Kolkata) are
+// rejected: their local days would not start on an hour bucket.
+func LoadReportingLocation(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	// January and July cover standard and daylight saving time on both hemispheres
+	year := time.Now().Year()
+	for _, month := range []time.Month{time.January, time.July} {
+		if _, offset := time.Date(year, month, 1, 0, 0, 0, 0, loc).Zone(); offset%3600 != 0 {
+			return nil, fmt.Errorf("UTC offset of %s is not a whole number of hours", name)
+		}
+	}
+	return loc, nil
 }
 
 // ImageValidationConfig contains configuration for image validation.
@@ -49,6 +730,38 @@ type ImageValidationConfig struct {
 	SkipOnTimeout bool `yaml:"skipOnTimeout"`
 }
 
+// ImageCopyConfig contains configuration for copying external images into a trusted
+// internal registry on deploy. The copied image is deployed by digest, insulating
+// production from upstream deletions.
+type ImageCopyConfig struct {
+	// Enabled copies images on deploy by default; requests can opt out with copyImage=false (default: false)
+	// Environment variable: IMAGE_COPY_ENABLED
+	Enabled bool `yaml:"enabled"`
+
+	// Registry is the internal registry host, e.g. harbor.internal
+	Registry string `yaml:"registry"`
+
+	// Prefix is the repository prefix in the internal registry (e.g. a Harbor project)
+	Prefix string `yaml:"prefix"`
+
+	// Username / Password are the push credentials for the internal registry
+	// Environment variables: IMAGE_COPY_USERNAME, IMAGE_COPY_PASSWORD
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// Insecure uses plain HTTP for the internal registry
+	Insecure bool `yaml:"insecure"`
+
+	// Timeout bounds each registry request, including blob uploads (default: 10m)
+	Timeout time.Duration `yaml:"timeout"`
+
+	// FailOnError rejects the deploy when the copy fails; otherwise the original image is deployed (default: false)
+	FailOnError bool `yaml:"failOnError"`
+
+	// TrustedRegistries are never copied (the internal registry is always trusted)
+	TrustedRegistries []string `yaml:"trustedRegistries"`
+}
+
 // ResourceReleaserConfig contains configuration for the ResourceReleaser.
 // Validates: Requirements 8.1, 8.2, 8.5
 type ResourceReleaserConfig struct {
@@ -66,6 +779,16 @@ type ResourceReleaserConfig struct {
 	// Default: 3
 	// Environment variable: RESOURCE_RELEASER_MAX_RETRIES
 	MaxRetries int `yaml:"maxRetries"`
+
+	// HealthRecomputeDebounce coalesces event-triggered endpoint health recomputes.
+	// Default: 2 seconds
+	HealthRecomputeDebounce time.Duration `yaml:"healthRecomputeDebounce"`
+
+	// AvoidUnhealthyGPUNodes keeps new workers off the node of a worker replaced for a failed
+	// GPU health check.
+	// Default: false
+	// Environment variable: RESOURCE_RELEASER_AVOID_UNHEALTHY_GPU_NODES
+	AvoidUnhealthyGPUNodes bool `yaml:"avoidUnhealthyGPUNodes"`
 }
 
 // DefaultImageValidationConfig returns the default configuration for image validation.
@@ -81,9 +804,10 @@ func DefaultImageValidationConfig() ImageValidationConfig {
 // DefaultResourceReleaserConfig returns the default configuration for ResourceReleaser.
 func DefaultResourceReleaserConfig() ResourceReleaserConfig {
 	return ResourceReleaserConfig{
-		ImagePullTimeout: 5 * time.Minute,
-		CheckInterval:    30 * time.Second,
-		MaxRetries:       3,
+		ImagePullTimeout:        5 * time.Minute,
+		CheckInterval:           30 * time.Second,
+		MaxRetries:              3,
+		HealthRecomputeDebounce: 2 * time.Second,
 	}
 }
 
@@ -133,6 +857,10 @@ type WorkerConfig struct {
 	HeartbeatInterval  int `yaml:"heartbeat_interval"`  // Heartbeat interval (seconds)
 	HeartbeatTimeout   int `yaml:"heartbeat_timeout"`   // Heartbeat timeout (seconds)
 	DefaultConcurrency int `yaml:"default_concurrency"` // default concurrency
+	LongPollMaxWait    int `yaml:"long_poll_max_wait"`  // Longest a pull may wait for a task (seconds, default 30)
+	LongPollRecheck    int `yaml:"long_poll_recheck"`   // Queue re-check interval while waiting, covers lost signals (seconds, default 5)
+	IntegrityInterval  int `yaml:"integrity_interval"`  // Workers table vs live pods check interval (seconds, default 300, negative disables)
+	IntegrityGrace     int `yaml:"integrity_grace"`     // Age before a row without a pod or a pod without a row is reported (seconds, default 300)
 }
 
 // LoggerConfig logger configuration
@@ -159,6 +887,54 @@ type K8sConfig struct {
 	ConfigDir string            `yaml:"config_dir"`           // Configuration directory (specs.yaml and templates)
 	GlobalEnv map[string]string `yaml:"global_env,omitempty"` // Global environment variables for all deployments
 	AWS       *AWSConfig        `yaml:"aws,omitempty"`        // AWS configuration (for aws-eks platform)
+
+	// RegistryMirrors maps upstream registries to in-cluster mirrors (e.g. docker.io -> harbor.internal/dockerhub).
+	// Applied to images when rendering deployments; mappings managed via API take precedence.
+	RegistryMirrors []RegistryMirrorConfig `yaml:"registry_mirrors,omitempty"`
+
+	// Ephemeral debug containers (POST /endpoints/:name/workers/:pod_name/debug)
+	DebugImage  string        `yaml:"debug_image,omitempty"`   // Image with profiling tools (default: nicolaka/netshoot:latest)
+	DebugMaxTTL time.Duration `yaml:"debug_max_ttl,omitempty"` // Maximum debug container lifetime (default: 1h)
+
+	// RunPod compatibility layer (per endpoint, runpodCompat): base URL of the Waverless /v2 API injected
+	// into RUNPOD_WEBHOOK_* and RUNPOD_ENDPOINT_BASE_URL (default: http://waverless-svc/v2)
+	RunPodAPIBase string `yaml:"runpod_api_base,omitempty"`
+
+	// Informer scoping: only matching deployments/pods are cached (GET /api/v1/k8s/informers shows cache size)
+	InformerLabelSelector    string        `yaml:"informer_label_selector,omitempty"`     // Default: managed-by=waverless; "*" caches the whole namespace
+	InformerPodFieldSelector string        `yaml:"informer_pod_field_selector,omitempty"` // Optional pod field selector, e.g. status.phase!=Succeeded
+	InformerResync           time.Duration `yaml:"informer_resync,omitempty"`             // Full resync period (default: 5m)
+
+	// Reaction to pods evicted for ephemeral storage or node disk pressure
+	DiskPressure DiskPressureConfig `yaml:"disk_pressure,omitempty"`
+
+	// Limits of the per-endpoint spec overrides of deploy requests
+	SpecOverrides SpecOverrideLimitsConfig `yaml:"spec_overrides,omitempty"`
+}
+
+// SpecOverrideLimitsConfig bounds what a deploy request may add to or change in its spec.
+// Extra CPU and memory are refused unless their limit is set; shmSize and ephemeralStorage
+// overrides are unbounded unless theirs is.
+type SpecOverrideLimitsConfig struct {
+	MaxExtraCPU         string `yaml:"max_extra_cpu,omitempty"`         // Most CPU a request may add to its spec (e.g. "8")
+	MaxExtraMemory      string `yaml:"max_extra_memory,omitempty"`      // Most memory a request may add to its spec (e.g. "64Gi")
+	MaxShmSize          string `yaml:"max_shm_size,omitempty"`          // Largest shmSize a request may set (e.g. "32Gi")
+	MaxEphemeralStorage string `yaml:"max_ephemeral_storage,omitempty"` // Largest ephemeralStorage a request or disk pressure bump may set (e.g. "500Gi")
+}
+
+// DiskPressureConfig controls how disk pressure failures of worker pods are handled.
+// Failures always raise an endpoint.disk_pressure integration event.
+type DiskPressureConfig struct {
+	AutoBump            bool          `yaml:"auto_bump"`                       // Raise the ephemeral-storage limit of endpoints whose pods exceeded it
+	BumpFactor          float64       `yaml:"bump_factor,omitempty"`           // Limit multiplier per bump (default: 1.5)
+	MaxEphemeralStorage string        `yaml:"max_ephemeral_storage,omitempty"` // Bumps never exceed this size (default: 200Gi)
+	Cooldown            time.Duration `yaml:"cooldown,omitempty"`              // Minimum time between alerts or bumps per endpoint (default: 30m)
+}
+
+// RegistryMirrorConfig maps an upstream registry to a mirror / pull-through cache
+type RegistryMirrorConfig struct {
+	Upstream string `yaml:"upstream"` // Upstream registry host, e.g. docker.io
+	Mirror   string `yaml:"mirror"`   // Mirror registry (optionally with path prefix), e.g. harbor.internal/dockerhub
 }
 
 // AWSConfig AWS configuration
@@ -170,9 +946,10 @@ type AWSConfig struct {
 
 // ProvidersConfig providers configuration
 type ProvidersConfig struct {
-	Deployment string `yaml:"deployment"` // Deployment provider: k8s, docker, custom
-	Queue      string `yaml:"queue"`      // Queue provider: redis, mysql, postgres
-	Metadata   string `yaml:"metadata"`   // Metadata storage: redis, mysql, postgres
+	Deployment string   `yaml:"deployment"` // Deployment provider: k8s, docker, custom
+	Additional []string `yaml:"additional"` // Further deployment providers endpoints can pin with "provider" (e.g. novita, runpod)
+	Queue      string   `yaml:"queue"`      // Queue provider: redis, mysql, postgres
+	Metadata   string   `yaml:"metadata"`   // Metadata storage: redis, mysql, postgres
 }
 
 // AutoScalerConfig autoscaler configuration
@@ -183,6 +960,26 @@ type AutoScalerConfig struct {
 	MaxCPUCores    int  `yaml:"max_cpu_cores"`   // Total cluster CPU cores
 	MaxMemoryGB    int  `yaml:"max_memory_gb"`   // Total cluster memory (GB)
 	StarvationTime int  `yaml:"starvation_time"` // Starvation time threshold (seconds)
+
+	// Replica reconciliation: converge provider replicas to the desired count in endpoint metadata
+	ReconcileInterval int `yaml:"reconcile_interval"` // Reconcile interval (seconds, default: 30, negative disables)
+	ReconcileSettle   int `yaml:"reconcile_settle"`   // Seconds a drift must persist before it is corrected (default: 60)
+
+	// Rate protection for provider scale calls, which endpoints with short evaluation intervals make often
+	ProviderQPS   float64 `yaml:"provider_qps"`   // Scale calls per second (default: 5, negative disables)
+	ProviderBurst int     `yaml:"provider_burst"` // Calls allowed at once after a quiet period (default: 10)
+
+	Metrics AutoScalerMetricsConfig `yaml:"metrics"` // Sources of the per-endpoint metric-based scaling
+}
+
+// AutoScalerMetricsConfig configures where endpoints scaled on metrics (GPU utilization, latency,
+// Prometheus queries) read them. Latency comes from the monitoring stats; GPU utilization and
+// custom queries need Prometheus.
+type AutoScalerMetricsConfig struct {
+	PrometheusURL       string        `yaml:"prometheus_url"`        // Prometheus base URL (empty disables GPU utilization and query sources)
+	GPUUtilizationQuery string        `yaml:"gpu_utilization_query"` // GPU utilization query with {{endpoint}} / {{namespace}} placeholders (default: DCGM exporter average)
+	QueryTimeout        time.Duration `yaml:"query_timeout"`         // Timeout of a Prometheus query (default: 5s)
+	LatencyWindow       time.Duration `yaml:"latency_window"`        // Window the request latency is averaged over (default: 5m)
 }
 
 // DockerConfig Docker registry authentication configuration
@@ -210,6 +1007,27 @@ type NovitaConfig struct {
 	BaseURL      string `yaml:"base_url"`      // API base URL, default: https://api.novita.ai
 	ConfigDir    string `yaml:"config_dir"`    // Configuration directory (specs.yaml and templates)
 	PollInterval int    `yaml:"poll_interval"` // Poll interval for status updates (seconds, default: 10)
+
+	RateLimit ProviderRateLimitConfig `yaml:"rate_limit"` // Client-side throttling of API calls per API key
+}
+
+// RunPodConfig RunPod serverless configuration
+type RunPodConfig struct {
+	Enabled      bool   `yaml:"enabled"`       // Whether to enable the RunPod provider
+	APIKey       string `yaml:"api_key"`       // RunPod API key (Bearer token)
+	BaseURL      string `yaml:"base_url"`      // REST API base URL, default: https://rest.runpod.io/v1
+	ConfigDir    string `yaml:"config_dir"`    // Configuration directory (specs.yaml)
+	PollInterval int    `yaml:"poll_interval"` // Poll interval for status updates (seconds, default: 10)
+
+	RateLimit ProviderRateLimitConfig `yaml:"rate_limit"` // Client-side throttling of API calls per API key
+}
+
+// ProviderRateLimitConfig smooths calls to a provider API below its per-credential limit. Replicas
+// using the same credential share the limit through Redis.
+type ProviderRateLimitConfig struct {
+	QPS     float64 `yaml:"qps"`      // Calls per second (default: 5, negative disables)
+	Burst   int     `yaml:"burst"`    // Calls allowed at once after a quiet period (default: 10)
+	MaxWait int     `yaml:"max_wait"` // Longest a call waits for its slot before failing as throttled (seconds, default: 30)
 }
 
 // Init initializes configuration
@@ -273,6 +1091,21 @@ func applyEnvOverrides(cfg *Config) {
 		}
 	}
 
+	// Image copy configuration
+	if v := os.Getenv("IMAGE_COPY_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.ImageCopy.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid IMAGE_COPY_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+	if v := os.Getenv("IMAGE_COPY_USERNAME"); v != "" {
+		cfg.ImageCopy.Username = v
+	}
+	if v := os.Getenv("IMAGE_COPY_PASSWORD"); v != "" {
+		cfg.ImageCopy.Password = v
+	}
+
 	// Resource Releaser configuration
 	if v := os.Getenv("RESOURCE_RELEASER_IMAGE_PULL_TIMEOUT"); v != "" {
 		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
@@ -297,6 +1130,204 @@ func applyEnvOverrides(cfg *Config) {
 			log.Printf("[WARN] Invalid RESOURCE_RELEASER_MAX_RETRIES value '%s', using config file value: %v", v, err)
 		}
 	}
+
+	if v := os.Getenv("RESOURCE_RELEASER_AVOID_UNHEALTHY_GPU_NODES"); v != "" {
+		if avoid, err := strconv.ParseBool(v); err == nil {
+			cfg.ResourceReleaser.AvoidUnhealthyGPUNodes = avoid
+		} else {
+			log.Printf("[WARN] Invalid RESOURCE_RELEASER_AVOID_UNHEALTHY_GPU_NODES value '%s', using config file value: %v", v, err)
+		}
+	}
+
+	// Reporting configuration
+	if v := os.Getenv("REPORTING_TIMEZONE"); v != "" {
+		cfg.Reporting.Timezone = v
+	}
+
+	// Export configuration
+	if v := os.Getenv("EXPORT_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Export.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid EXPORT_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+	if v := os.Getenv("EXPORT_SINK"); v != "" {
+		cfg.Export.Sink = v
+	}
+	if v := os.Getenv("EXPORT_BIGQUERY_ACCESS_TOKEN"); v != "" {
+		cfg.Export.BigQuery.AccessToken = v
+	}
+
+	// Log shipping configuration
+	if v := os.Getenv("LOG_SHIPPING_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.LogShipping.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid LOG_SHIPPING_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+	if v := os.Getenv("LOG_SHIPPING_LOKI_PASSWORD"); v != "" {
+		cfg.LogShipping.Loki.Password = v
+	}
+	if v := os.Getenv("LOG_SHIPPING_ES_PASSWORD"); v != "" {
+		cfg.LogShipping.Elasticsearch.Password = v
+	}
+	if v := os.Getenv("LOG_SHIPPING_ES_API_KEY"); v != "" {
+		cfg.LogShipping.Elasticsearch.APIKey = v
+	}
+
+	// Coordination configuration
+	if v := os.Getenv("SERVER_ROLE"); v != "" {
+		cfg.Coordination.Role = v
+	}
+	if v := os.Getenv("POD_NAME"); v != "" {
+		cfg.Coordination.Identity = v
+	}
+
+	// Data plane configuration
+	if v := os.Getenv("DATA_PLANE_TOKEN_SECRET"); v != "" {
+		cfg.DataPlane.TokenSecret = v
+	}
+
+	// Discovery configuration
+	if v := os.Getenv("DISCOVERY_MODE"); v != "" {
+		cfg.Discovery.Mode = v
+	}
+	if v := os.Getenv("CONSUL_HTTP_ADDR"); v != "" {
+		cfg.Discovery.Consul.Address = v
+	}
+	if v := os.Getenv("CONSUL_HTTP_TOKEN"); v != "" {
+		cfg.Discovery.Consul.Token = v
+	}
+
+	// Model registry configuration
+	if v := os.Getenv("MODEL_REGISTRY_KIND"); v != "" {
+		cfg.ModelRegistry.Kind = v
+	}
+	if v := os.Getenv("MODEL_REGISTRY_ADDRESS"); v != "" {
+		cfg.ModelRegistry.Address = v
+	}
+	if v := os.Getenv("MODEL_REGISTRY_TOKEN"); v != "" {
+		cfg.ModelRegistry.Token = v
+	}
+
+	// Load test configuration
+	if v := os.Getenv("LOAD_TEST_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.LoadTest.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid LOAD_TEST_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+
+	// Task journal configuration
+	if v := os.Getenv("TASK_JOURNAL_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.TaskJournal.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid TASK_JOURNAL_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+	if v := os.Getenv("TASK_JOURNAL_STREAM"); v != "" {
+		cfg.TaskJournal.Stream = v
+	}
+
+	// Quota configuration
+	if v := os.Getenv("QUOTA_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Quota.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid QUOTA_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+
+	// Sampling configuration
+	if v := os.Getenv("SAMPLING_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Sampling.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid SAMPLING_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+
+	// Approval configuration
+	if v := os.Getenv("APPROVAL_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Approval.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid APPROVAL_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+
+	// Anomaly detection configuration
+	if v := os.Getenv("ANOMALY_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Anomaly.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid ANOMALY_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+
+	// Encryption configuration
+	if v := os.Getenv("ENCRYPTION_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Encryption.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid ENCRYPTION_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+	if v := os.Getenv("ENCRYPTION_MASTER_KEY"); v != "" {
+		cfg.Encryption.MasterKey = v
+	}
+	if v := os.Getenv("VAULT_TOKEN"); v != "" {
+		cfg.Encryption.Vault.Token = v
+	}
+
+	// Status page configuration
+	if v := os.Getenv("STATUS_PAGE_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.StatusPage.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid STATUS_PAGE_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+	if v := os.Getenv("STATUS_PAGE_TOKEN"); v != "" {
+		cfg.StatusPage.Token = v
+	}
+
+	// Hedging configuration
+	if v := os.Getenv("HEDGING_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Hedging.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid HEDGING_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+
+	// Circuit breaker configuration
+	if v := os.Getenv("CIRCUIT_BREAKER_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.CircuitBreaker.Enabled = enabled
+		} else {
+			log.Printf("[WARN] Invalid CIRCUIT_BREAKER_ENABLED value '%s', using config file value: %v", v, err)
+		}
+	}
+
+	// Maintenance configuration
+	if v := os.Getenv("MAINTENANCE_READ_ONLY"); v != "" {
+		if readOnly, err := strconv.ParseBool(v); err == nil {
+			cfg.Maintenance.ReadOnly = readOnly
+		} else {
+			log.Printf("[WARN] Invalid MAINTENANCE_READ_ONLY value '%s', using config file value: %v", v, err)
+		}
+	}
+	if v := os.Getenv("MAINTENANCE_REASON"); v != "" {
+		cfg.Maintenance.Reason = v
+	}
+	if v := os.Getenv("AUTOSCALER_PROMETHEUS_URL"); v != "" {
+		cfg.AutoScaler.Metrics.PrometheusURL = v
+	}
 }
 
 // validateAndApplyDefaults validates configuration values and applies defaults for invalid values.
@@ -335,6 +1366,15 @@ func validateAndApplyDefaults(cfg *Config) {
 		}
 	}
 
+	// Validate ImageCopy configuration
+	if cfg.ImageCopy.Timeout <= 0 {
+		cfg.ImageCopy.Timeout = 10 * time.Minute
+	}
+	if cfg.ImageCopy.Enabled && cfg.ImageCopy.Registry == "" {
+		log.Printf("[WARN] imageCopy.enabled is set but imageCopy.registry is empty, disabling image copy")
+		cfg.ImageCopy.Enabled = false
+	}
+
 	// Validate ResourceReleaser configuration
 	if cfg.ResourceReleaser.ImagePullTimeout <= 0 {
 		log.Printf("[WARN] Invalid resourceReleaser.imagePullTimeout value '%v', using default '%v'",
@@ -353,4 +1393,292 @@ func validateAndApplyDefaults(cfg *Config) {
 			cfg.ResourceReleaser.MaxRetries, releaserDefaults.MaxRetries)
 		cfg.ResourceReleaser.MaxRetries = releaserDefaults.MaxRetries
 	}
+
+	if cfg.ResourceReleaser.HealthRecomputeDebounce <= 0 {
+		cfg.ResourceReleaser.HealthRecomputeDebounce = releaserDefaults.HealthRecomputeDebounce
+	}
+
+	// Validate Reporting configuration
+	if cfg.Reporting.Timezone == "" {
+		cfg.Reporting.Timezone = "UTC"
+	} else if _, err := LoadReportingLocation(cfg.Reporting.Timezone); err != nil {
+		log.Printf("[WARN] Invalid reporting.timezone value '%s', using default 'UTC': %v", cfg.Reporting.Timezone, err)
+		cfg.Reporting.Timezone = "UTC"
+	}
+	if cfg.Reporting.ProjectLabel == "" {
+		cfg.Reporting.ProjectLabel = "project"
+	}
+	for project, tz := range cfg.Reporting.ProjectTimezones {
+		if _, err := LoadReportingLocation(tz); err != nil {
+			log.Printf("[WARN] Invalid reporting.projectTimezones value '%s' of project %s, using reporting.timezone: %v", tz, project, err)
+			delete(cfg.Reporting.ProjectTimezones, project)
+		}
+	}
+
+	// Validate Export configuration
+	if cfg.Export.Interval <= 0 {
+		cfg.Export.Interval = 10 * time.Minute
+	}
+	if cfg.Export.BatchSize <= 0 {
+		cfg.Export.BatchSize = 5000
+	}
+	if cfg.Export.Sink == "" {
+		cfg.Export.Sink = "local"
+	}
+
+	// Validate LogShipping configuration
+	if cfg.LogShipping.Interval <= 0 {
+		cfg.LogShipping.Interval = 15 * time.Second
+	}
+	if cfg.LogShipping.MaxBytesPerPoll <= 0 {
+		cfg.LogShipping.MaxBytesPerPoll = 1 << 20
+	}
+	if cfg.LogShipping.Backend == "" {
+		cfg.LogShipping.Backend = "loki"
+	}
+
+	// Validate Coordination configuration
+	switch cfg.Coordination.Role {
+	case RoleAll, RoleAPI, RoleController:
+	case "":
+		cfg.Coordination.Role = RoleAll
+	default:
+		log.Printf("[WARN] Invalid coordination.role value '%s', using default '%s'", cfg.Coordination.Role, RoleAll)
+		cfg.Coordination.Role = RoleAll
+	}
+	if cfg.Coordination.Identity == "" {
+		if hostname, err := os.Hostname(); err == nil {
+			cfg.Coordination.Identity = hostname
+		}
+	}
+	if cfg.Coordination.LeaseTTL <= 0 {
+		cfg.Coordination.LeaseTTL = 15 * time.Second
+	}
+	if cfg.Coordination.RenewInterval <= 0 || cfg.Coordination.RenewInterval >= cfg.Coordination.LeaseTTL {
+		cfg.Coordination.RenewInterval = cfg.Coordination.LeaseTTL / 3
+	}
+
+	// Validate DataPlane configuration
+	if cfg.DataPlane.TokenTTL <= 0 {
+		cfg.DataPlane.TokenTTL = 5 * time.Minute
+	}
+
+	// Validate Maintenance configuration
+	if cfg.Maintenance.RefreshInterval <= 0 {
+		cfg.Maintenance.RefreshInterval = 2 * time.Second
+	}
+
+	// Validate replica reconciliation
+	if cfg.AutoScaler.ReconcileInterval == 0 {
+		cfg.AutoScaler.ReconcileInterval = 30
+	}
+	if cfg.AutoScaler.ReconcileSettle <= 0 {
+		cfg.AutoScaler.ReconcileSettle = 60
+	}
+	if cfg.AutoScaler.ProviderQPS == 0 {
+		cfg.AutoScaler.ProviderQPS = 5
+	}
+	if cfg.AutoScaler.ProviderBurst <= 0 {
+		cfg.AutoScaler.ProviderBurst = 10
+	}
+	if cfg.AutoScaler.Metrics.QueryTimeout <= 0 {
+		cfg.AutoScaler.Metrics.QueryTimeout = 5 * time.Second
+	}
+	if cfg.AutoScaler.Metrics.LatencyWindow <= 0 {
+		cfg.AutoScaler.Metrics.LatencyWindow = 5 * time.Minute
+	}
+
+	// Validate provider API rate limits
+	if cfg.Novita.RateLimit.QPS == 0 {
+		cfg.Novita.RateLimit.QPS = 5
+	}
+	if cfg.Novita.RateLimit.Burst <= 0 {
+		cfg.Novita.RateLimit.Burst = 10
+	}
+	if cfg.Novita.RateLimit.MaxWait <= 0 {
+		cfg.Novita.RateLimit.MaxWait = 30
+	}
+	if cfg.RunPod.RateLimit.QPS == 0 {
+		cfg.RunPod.RateLimit.QPS = 5
+	}
+	if cfg.RunPod.RateLimit.Burst <= 0 {
+		cfg.RunPod.RateLimit.Burst = 10
+	}
+	if cfg.RunPod.RateLimit.MaxWait <= 0 {
+		cfg.RunPod.RateLimit.MaxWait = 30
+	}
+
+	// Validate Sampling configuration
+	if cfg.Sampling.Scrubbers == nil {
+		cfg.Sampling.Scrubbers = []string{"email", "phone", "credit_card"}
+	}
+
+	// Validate Approval configuration
+	if len(cfg.Approval.Labels) == 0 {
+		cfg.Approval.Labels = map[string]string{"environment": "prod"}
+	}
+	if cfg.Approval.TTL <= 0 {
+		cfg.Approval.TTL = 24 * time.Hour
+	}
+
+	// Validate worker long-poll configuration
+	if cfg.Worker.LongPollMaxWait <= 0 {
+		cfg.Worker.LongPollMaxWait = 30
+	}
+	if cfg.Worker.LongPollRecheck <= 0 {
+		cfg.Worker.LongPollRecheck = 5
+	}
+	if cfg.Worker.IntegrityInterval == 0 {
+		cfg.Worker.IntegrityInterval = 300
+	}
+	if cfg.Worker.IntegrityGrace <= 0 {
+		cfg.Worker.IntegrityGrace = 300
+	}
+
+	// Validate Anomaly configuration
+	if cfg.Anomaly.Interval <= 0 {
+		cfg.Anomaly.Interval = 5 * time.Minute
+	}
+	if cfg.Anomaly.Window <= 0 {
+		cfg.Anomaly.Window = 15 * time.Minute
+	}
+	if cfg.Anomaly.BaselineDays <= 0 {
+		cfg.Anomaly.BaselineDays = 7
+	}
+	if cfg.Anomaly.Sensitivity == "" {
+		cfg.Anomaly.Sensitivity = "normal"
+	}
+	if cfg.Anomaly.Cooldown <= 0 {
+		cfg.Anomaly.Cooldown = time.Hour
+	}
+
+	// Validate Encryption configuration
+	if cfg.Encryption.ProjectLabel == "" {
+		cfg.Encryption.ProjectLabel = "project"
+	}
+	if cfg.Encryption.Vault.Mount == "" {
+		cfg.Encryption.Vault.Mount = "transit"
+	}
+	if cfg.Encryption.Vault.KeyPrefix == "" {
+		cfg.Encryption.Vault.KeyPrefix = "waverless-"
+	}
+
+	// Validate StatusPage configuration
+	if cfg.StatusPage.ProjectLabel == "" {
+		cfg.StatusPage.ProjectLabel = "project"
+	}
+	if cfg.StatusPage.ExposureLabel == "" {
+		cfg.StatusPage.ExposureLabel = "status-page"
+	}
+	if cfg.StatusPage.CacheTTL <= 0 {
+		cfg.StatusPage.CacheTTL = 30 * time.Second
+	}
+
+	// Validate Hedging configuration
+	if cfg.Hedging.Multiplier <= 0 {
+		cfg.Hedging.Multiplier = 1.0
+	}
+	if cfg.Hedging.MinDelay <= 0 {
+		cfg.Hedging.MinDelay = time.Second
+	}
+	if cfg.Hedging.MinSamples <= 0 {
+		cfg.Hedging.MinSamples = 20
+	}
+	if cfg.Hedging.Lookback <= 0 {
+		cfg.Hedging.Lookback = time.Hour
+	}
+	if cfg.Hedging.Interval <= 0 {
+		cfg.Hedging.Interval = 5 * time.Second
+	}
+
+	// Validate Wake configuration
+	if cfg.Wake.ColdStartBudget <= 0 {
+		cfg.Wake.ColdStartBudget = 60 * time.Second
+	}
+	if cfg.Wake.DefaultColdStart <= 0 {
+		cfg.Wake.DefaultColdStart = 30 * time.Second
+	}
+
+	// Validate CircuitBreaker configuration
+	if cfg.CircuitBreaker.Window <= 0 {
+		cfg.CircuitBreaker.Window = 5 * time.Minute
+	}
+	if cfg.CircuitBreaker.MinTasks <= 0 {
+		cfg.CircuitBreaker.MinTasks = 20
+	}
+	if cfg.CircuitBreaker.FailureRate <= 0 || cfg.CircuitBreaker.FailureRate > 1 {
+		cfg.CircuitBreaker.FailureRate = 0.9
+	}
+	if cfg.CircuitBreaker.ProbeAfter <= 0 {
+		cfg.CircuitBreaker.ProbeAfter = time.Minute
+	}
+	if cfg.CircuitBreaker.MaxProbeAfter < cfg.CircuitBreaker.ProbeAfter {
+		cfg.CircuitBreaker.MaxProbeAfter = max(15*time.Minute, cfg.CircuitBreaker.ProbeAfter)
+	}
+	if cfg.CircuitBreaker.Interval <= 0 {
+		cfg.CircuitBreaker.Interval = 15 * time.Second
+	}
+
+	// Validate Discovery configuration
+	if cfg.Discovery.Address == "" {
+		cfg.Discovery.Address = cfg.Server.BaseURL
+	}
+	if cfg.Discovery.Consul.Address == "" {
+		cfg.Discovery.Consul.Address = "http://127.0.0.1:8500"
+	}
+
+	// Validate TaskJournal configuration
+	if cfg.TaskJournal.Stream == "" {
+		cfg.TaskJournal.Stream = "waverless:task-events"
+	}
+	if cfg.TaskJournal.MaxLen < 0 {
+		cfg.TaskJournal.MaxLen = 0
+	} else if cfg.TaskJournal.MaxLen == 0 {
+		cfg.TaskJournal.MaxLen = 1000000
+	}
+	if cfg.TaskJournal.Interval <= 0 {
+		cfg.TaskJournal.Interval = 5 * time.Second
+	}
+	if cfg.TaskJournal.BatchSize <= 0 {
+		cfg.TaskJournal.BatchSize = 500
+	}
+
+	// Validate Quota configuration
+	if cfg.Quota.Interval <= 0 {
+		cfg.Quota.Interval = time.Hour
+	}
+	if cfg.Quota.ProjectLabel == "" {
+		cfg.Quota.ProjectLabel = "project"
+	}
+	if cfg.Quota.WarnPercent <= 0 {
+		cfg.Quota.WarnPercent = 80
+	}
+	if cfg.Quota.WarnDays <= 0 {
+		cfg.Quota.WarnDays = 14
+	}
+	if cfg.Quota.HistoryDays <= 0 {
+		cfg.Quota.HistoryDays = 14
+	}
+	if cfg.Quota.Cooldown <= 0 {
+		cfg.Quota.Cooldown = 24 * time.Hour
+	}
+
+	// Validate LoadTest configuration
+	if len(cfg.LoadTest.StagingLabels) == 0 {
+		cfg.LoadTest.StagingLabels = map[string]string{"environment": "staging"}
+	}
+	if cfg.LoadTest.MaxRPS <= 0 {
+		cfg.LoadTest.MaxRPS = 50
+	}
+	if cfg.LoadTest.MaxDuration <= 0 {
+		cfg.LoadTest.MaxDuration = 2 * time.Hour
+	}
+
+	// Validate Enrichment configuration
+	if cfg.Enrichment.CallTimeout <= 0 {
+		cfg.Enrichment.CallTimeout = 100 * time.Millisecond
+	}
+	if cfg.Enrichment.Budget <= 0 {
+		cfg.Enrichment.Budget = 250 * time.Millisecond
+	}
 }
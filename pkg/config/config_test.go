@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadReportingLocation(t *testing.T) {
+	for _, tz := range []string{"UTC", "Asia/Shanghai", "America/New_York", "Europe/Berlin"} {
+		loc, err := LoadReportingLocation(tz)
+		require.NoError(t, err, tz)
+		assert.Equal(t, tz, loc.String())
+	}
+	// Local days would start between two hour buckets
+	for _, tz := range []string{"Asia/Kolkata", "Asia/Kathmandu", "Australia/Adelaide", "Australia/Lord_Howe"} {
+		_, err := LoadReportingLocation(tz)
+		assert.Error(t, err, tz)
+	}
+	_, err := LoadReportingLocation("Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
+
+func TestReportingConfigProjectLocation(t *testing.T) {
+	cfg := ReportingConfig{
+		Timezone:         "Asia/Shanghai",
+		ProjectLabel:     "project",
+		ProjectTimezones: map[string]string{"search": "America/New_York", "ads": "Asia/Kolkata"},
+	}
+
+	assert.Equal(t, "America/New_York", cfg.ProjectLocation("search").String())
+	assert.Equal(t, "Asia/Shanghai", cfg.ProjectLocation("vision").String())
+	assert.Equal(t, "Asia/Shanghai", cfg.ProjectLocation("").String())
+	assert.Equal(t, "Asia/Shanghai", cfg.ProjectLocation("ads").String())
+	assert.Equal(t, time.UTC, ReportingConfig{}.ProjectLocation("search"))
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces secrets in exported configuration
+const RedactedValue = "REDACTED"
+
+// secretKeyParts mark a config key as holding a secret
+var secretKeyParts = []string{"password", "secret", "token", "api_key", "access_key", "private_key"}
+
+// isSecretKey reports whether a yaml key holds a credential
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "auth" {
+		return true
+	}
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveYAML renders the configuration in effect (file, environment overrides and
+// defaults) as a config file. Credentials are replaced by RedactedValue; their dotted
+// paths are returned so they can be filled in where the file is deployed.
+func (c *Config) EffectiveYAML() ([]byte, []string, error) {
+	var redacted []string
+	node, err := configNode(reflect.ValueOf(c), "", &redacted)
+	if err != nil {
+		return nil, nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	sort.Strings(redacted)
+	return buf.Bytes(), redacted, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// configNode converts a config value to a yaml node the way yaml.v3 would marshal it, except
+// that durations are written as "30s" instead of nanoseconds and secrets are redacted
+func configNode(v reflect.Value, path string, redacted *[]string) (*yaml.Node, error) {
+	if v.Type() == durationType {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: time.Duration(v.Int()).String()}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+		}
+		return configNode(v.Elem(), path, redacted)
+
+	case reflect.Struct:
+		if _, ok := v.Interface().(time.Time); ok {
+			break
+		}
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			fv := v.Field(i)
+			if strings.Contains(opts, "omitempty") && fv.IsZero() {
+				continue
+			}
+
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			if fv.Kind() == reflect.String && fv.Len() > 0 && isSecretKey(name) {
+				*redacted = append(*redacted, fieldPath)
+				fv = reflect.ValueOf(RedactedValue)
+			}
+			valueNode, err := configNode(fv, fieldPath, redacted)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, valueNode)
+		}
+		return node, nil
+
+	case reflect.Map:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, k := range keys {
+			key := fmt.Sprint(k)
+			mv := v.MapIndex(k)
+			// Env maps (global_env) hold credentials like HF_TOKEN as well
+			if mv.Kind() == reflect.String && mv.Len() > 0 && isSecretKey(key) {
+				*redacted = append(*redacted, path+"."+key)
+				mv = reflect.ValueOf(RedactedValue)
+			}
+			valueNode, err := configNode(mv, path+"."+key, redacted)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, valueNode)
+		}
+		return node, nil
+
+	case reflect.Slice, reflect.Array:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := 0; i < v.Len(); i++ {
+			item, err := configNode(v.Index(i), fmt.Sprintf("%s[%d]", path, i), redacted)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, item)
+		}
+		return node, nil
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(v.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return node, nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestEffectiveYAML(t *testing.T) {
+	cfg := &Config{
+		Redis: RedisConfig{Addr: "redis:6379"},
+		MySQL: MySQLConfig{Host: "mysql", User: "waverless", Password: "s3cret"},
+		K8s: K8sConfig{
+			Namespace: "wavespeed",
+			GlobalEnv: map[string]string{"HF_TOKEN": "hf_abc", "HF_HOME": "/models"},
+		},
+		CircuitBreaker: CircuitBreakerConfig{Window: 5 * time.Minute, ProbeAfter: 90 * time.Second},
+	}
+
+	data, redacted, err := cfg.EffectiveYAML()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k8s.global_env.HF_TOKEN", "mysql.password"}, redacted,
+		"empty credentials (redis.password) are not reported")
+	assert.Contains(t, string(data), "window: 5m0s")
+	assert.NotContains(t, string(data), "s3cret")
+	assert.NotContains(t, string(data), "hf_abc")
+
+	var loaded Config
+	require.NoError(t, yaml.Unmarshal(data, &loaded), "the export loads as a config file")
+	assert.Equal(t, 90*time.Second, loaded.CircuitBreaker.ProbeAfter)
+	assert.Equal(t, "mysql", loaded.MySQL.Host)
+	assert.Equal(t, RedactedValue, loaded.MySQL.Password)
+	assert.Equal(t, "/models", loaded.K8s.GlobalEnv["HF_HOME"])
+	assert.Nil(t, loaded.Providers, "unset optional sections stay unset")
+}
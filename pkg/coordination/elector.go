@@ -0,0 +1,139 @@
+package coordination
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"waverless/pkg/logger"
+)
+
+// LeaderCallbacks are invoked on leadership transitions
+type LeaderCallbacks struct {
+	// OnStartedLeading runs when the lease is acquired; ctx is cancelled when leadership is lost
+	OnStartedLeading func(ctx context.Context)
+	// OnStoppedLeading runs after leadership is lost or given up
+	OnStoppedLeading func()
+}
+
+// ElectorConfig configures a LeaderElector
+type ElectorConfig struct {
+	Identity      string        // Unique identity of this replica (e.g. pod name)
+	LeaseTTL      time.Duration // How long the lease survives without renewal
+	RenewInterval time.Duration // How often the holder renews (and followers retry); must be < LeaseTTL
+}
+
+// LeaderElector elects a single leader among replicas sharing a lease.
+// The leader renews the lease periodically; if a renewal fails it steps down
+// immediately so that at most one replica acts on watch events at a time.
+type LeaderElector struct {
+	lease  Lease
+	config ElectorConfig
+
+	leading atomic.Bool
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+}
+
+// NewLeaderElector creates a leader elector over the lease
+func NewLeaderElector(lease Lease, config ElectorConfig) *LeaderElector {
+	if config.LeaseTTL <= 0 {
+		config.LeaseTTL = 15 * time.Second
+	}
+	if config.RenewInterval <= 0 || config.RenewInterval >= config.LeaseTTL {
+		config.RenewInterval = config.LeaseTTL / 3
+	}
+	return &LeaderElector{lease: lease, config: config}
+}
+
+// Identity returns the identity this elector campaigns with
+func (e *LeaderElector) Identity() string {
+	return e.config.Identity
+}
+
+// IsLeader reports whether this replica currently holds the lease
+func (e *LeaderElector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// Run campaigns for the lease until ctx is done, invoking callbacks on transitions.
+// The lease is released on exit so another replica can take over without waiting for the TTL.
+func (e *LeaderElector) Run(ctx context.Context, callbacks LeaderCallbacks) {
+	ticker := time.NewTicker(e.config.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		e.tick(ctx, callbacks)
+
+		select {
+		case <-ctx.Done():
+			if e.IsLeader() {
+				e.stepDown(callbacks)
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := e.lease.Release(releaseCtx, e.config.Identity); err != nil {
+					logger.WarnCtx(releaseCtx, "failed to release leader lease: %v", err)
+				}
+				cancel()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick acquires the lease as a follower or renews it as the leader
+func (e *LeaderElector) tick(ctx context.Context, callbacks LeaderCallbacks) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	if e.IsLeader() {
+		renewed, err := e.lease.Renew(ctx, e.config.Identity, e.config.LeaseTTL)
+		if err != nil || !renewed {
+			if err != nil {
+				logger.WarnCtx(ctx, "leader lease renewal failed, stepping down: identity=%s, error=%v", e.config.Identity, err)
+			} else {
+				logger.WarnCtx(ctx, "leader lease lost to another replica, stepping down: identity=%s", e.config.Identity)
+			}
+			e.stepDown(callbacks)
+		}
+		return
+	}
+
+	acquired, err := e.lease.TryAcquire(ctx, e.config.Identity, e.config.LeaseTTL)
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to acquire leader lease: %v", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	e.mu.Lock()
+	e.cancel = cancel
+	e.mu.Unlock()
+	e.leading.Store(true)
+
+	logger.InfoCtx(ctx, "acquired leader lease, starting controllers: identity=%s", e.config.Identity)
+	if callbacks.OnStartedLeading != nil {
+		go callbacks.OnStartedLeading(leaderCtx)
+	}
+}
+
+// stepDown cancels the leader context and notifies the callbacks
+func (e *LeaderElector) stepDown(callbacks LeaderCallbacks) {
+	e.leading.Store(false)
+	e.mu.Lock()
+	if e.cancel != nil {
+		e.cancel()
+		e.cancel = nil
+	}
+	e.mu.Unlock()
+
+	logger.Info("leader lease released, controllers stopped")
+	if callbacks.OnStoppedLeading != nil {
+		callbacks.OnStoppedLeading()
+	}
+}
@@ -0,0 +1,103 @@
+package coordination
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLease(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	lease := NewMemoryLease()
+	lease.now = func() time.Time { return now }
+
+	ok, err := lease.TryAcquire(ctx, "a", 10*time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, _ = lease.TryAcquire(ctx, "b", 10*time.Second)
+	assert.False(t, ok, "held lease cannot be taken")
+
+	ok, _ = lease.Renew(ctx, "b", 10*time.Second)
+	assert.False(t, ok, "only the holder can renew")
+
+	// Expired lease can be taken over, and the old holder can no longer renew
+	now = now.Add(11 * time.Second)
+	ok, _ = lease.TryAcquire(ctx, "b", 10*time.Second)
+	assert.True(t, ok)
+	ok, _ = lease.Renew(ctx, "a", 10*time.Second)
+	assert.False(t, ok)
+
+	// Release by a non-holder is a no-op
+	require.NoError(t, lease.Release(ctx, "a"))
+	holder, _ := lease.Holder(ctx)
+	assert.Equal(t, "b", holder)
+
+	require.NoError(t, lease.Release(ctx, "b"))
+	holder, _ = lease.Holder(ctx)
+	assert.Empty(t, holder)
+}
+
+func TestLeaderElector_SingleLeaderAndFailover(t *testing.T) {
+	lease := NewMemoryLease()
+	cfg := ElectorConfig{LeaseTTL: 200 * time.Millisecond, RenewInterval: 10 * time.Millisecond}
+
+	var startedA, stoppedA, startedB atomic.Int32
+	cfgA := cfg
+	cfgA.Identity = "replica-a"
+	a := NewLeaderElector(lease, cfgA)
+	cfgB := cfg
+	cfgB.Identity = "replica-b"
+	b := NewLeaderElector(lease, cfgB)
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	doneA := make(chan struct{})
+	go func() {
+		a.Run(ctxA, LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) { startedA.Add(1) },
+			OnStoppedLeading: func() { stoppedA.Add(1) },
+		})
+		close(doneA)
+	}()
+	require.Eventually(t, a.IsLeader, time.Second, 5*time.Millisecond)
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+	var leaderCtxB context.Context
+	leaderCtxSet := make(chan struct{})
+	go b.Run(ctxB, LeaderCallbacks{
+		OnStartedLeading: func(ctx context.Context) {
+			startedB.Add(1)
+			leaderCtxB = ctx
+			close(leaderCtxSet)
+		},
+	})
+
+	// B stays a follower while A renews
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, b.IsLeader())
+	assert.True(t, a.IsLeader())
+
+	// A shuts down and releases; B takes over without waiting for the TTL
+	cancelA()
+	<-doneA
+	assert.False(t, a.IsLeader())
+	assert.Equal(t, int32(1), startedA.Load())
+	assert.Equal(t, int32(1), stoppedA.Load())
+
+	require.Eventually(t, b.IsLeader, 100*time.Millisecond, 5*time.Millisecond)
+	<-leaderCtxSet
+	assert.Equal(t, int32(1), startedB.Load())
+
+	// Losing the lease cancels the leader context
+	lease.mu.Lock()
+	lease.holder = "someone-else"
+	lease.mu.Unlock()
+	require.Eventually(t, func() bool { return !b.IsLeader() }, time.Second, 5*time.Millisecond)
+	assert.Error(t, leaderCtxB.Err())
+}
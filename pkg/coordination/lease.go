@@ -0,0 +1,158 @@
+// Package coordination provides the primitives that let several control-plane
+// replicas run side by side: a leader lease that elects the single replica
+// running informer callbacks and controller loops, while every replica serves
+// the stateless HTTP handler tier.
+package coordination
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Lease is a named, time-bounded claim held by one replica at a time
+type Lease interface {
+	// TryAcquire claims the lease for holder if it is free (or already held by holder)
+	TryAcquire(ctx context.Context, holder string, ttl time.Duration) (bool, error)
+	// Renew extends the lease if holder still owns it; returns false if it was lost
+	Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error)
+	// Release gives up the lease if holder owns it
+	Release(ctx context.Context, holder string) error
+	// Holder returns the current holder, empty if the lease is free
+	Holder(ctx context.Context) (string, error)
+}
+
+const leaseKeyPrefix = "coordination:lease:"
+
+// Only touch the key if it still carries our holder identity
+const (
+	renewScript = `
+		if redis.call("get", KEYS[1]) == ARGV[1] then
+			return redis.call("pexpire", KEYS[1], ARGV[2])
+		else
+			return 0
+		end
+	`
+	releaseScript = `
+		if redis.call("get", KEYS[1]) == ARGV[1] then
+			return redis.call("del", KEYS[1])
+		else
+			return 0
+		end
+	`
+)
+
+// RedisLease stores the lease holder in a Redis key with a TTL
+type RedisLease struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisLease creates a Redis-backed lease with the given name
+func NewRedisLease(client *redis.Client, name string) *RedisLease {
+	return &RedisLease{client: client, key: leaseKeyPrefix + name}
+}
+
+// TryAcquire claims the lease with SET NX, or reports success if holder already owns it
+func (l *RedisLease) TryAcquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	acquired, err := l.client.SetNX(ctx, l.key, holder, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease %s: %w", l.key, err)
+	}
+	if acquired {
+		return true, nil
+	}
+	// Re-acquire after a restart that kept the identity (e.g. same pod name)
+	return l.Renew(ctx, holder, ttl)
+}
+
+// Renew extends the lease TTL if holder still owns it
+func (l *RedisLease) Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	result, err := l.client.Eval(ctx, renewScript, []string{l.key}, holder, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew lease %s: %w", l.key, err)
+	}
+	return result == 1, nil
+}
+
+// Release deletes the lease if holder owns it
+func (l *RedisLease) Release(ctx context.Context, holder string) error {
+	if err := l.client.Eval(ctx, releaseScript, []string{l.key}, holder).Err(); err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", l.key, err)
+	}
+	return nil
+}
+
+// Holder returns the current lease holder
+func (l *RedisLease) Holder(ctx context.Context) (string, error) {
+	holder, err := l.client.Get(ctx, l.key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get lease holder %s: %w", l.key, err)
+	}
+	return holder, nil
+}
+
+// MemoryLease is an in-process lease, used when running a single replica without Redis
+type MemoryLease struct {
+	mu        sync.Mutex
+	holder    string
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+// NewMemoryLease creates an in-process lease
+func NewMemoryLease() *MemoryLease {
+	return &MemoryLease{now: time.Now}
+}
+
+// TryAcquire claims the lease if it is free, expired or already held by holder
+func (l *MemoryLease) TryAcquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if l.holder != "" && l.holder != holder && now.Before(l.expiresAt) {
+		return false, nil
+	}
+	l.holder = holder
+	l.expiresAt = now.Add(ttl)
+	return true, nil
+}
+
+// Renew extends the lease if holder still owns it and it has not expired
+func (l *MemoryLease) Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if l.holder != holder || !now.Before(l.expiresAt) {
+		return false, nil
+	}
+	l.expiresAt = now.Add(ttl)
+	return true, nil
+}
+
+// Release frees the lease if holder owns it
+func (l *MemoryLease) Release(ctx context.Context, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == holder {
+		l.holder = ""
+		l.expiresAt = time.Time{}
+	}
+	return nil
+}
+
+// Holder returns the current holder, empty if free or expired
+func (l *MemoryLease) Holder(ctx context.Context) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == "" || !l.now().Before(l.expiresAt) {
+		return "", nil
+	}
+	return l.holder, nil
+}
@@ -0,0 +1,197 @@
+// Package cronexpr parses standard 5-field cron expressions ("minute hour day-of-month month
+// day-of-week") and computes their next fire times. Fields accept "*", values, ranges, lists and
+// steps ("*/15", "1-5", "0,30", "10-50/20"); months and weekdays also accept three-letter names.
+// As in cron, when both day fields are restricted a day matches if either does. The macros
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are supported.
+package cronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit bounds the search for the next fire time; expressions such as "0 0 30 2 *" never fire
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var weekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	domField     = field{name: "day-of-month", min: 1, max: 31}
+	monthField   = field{name: "month", min: 1, max: 12, names: monthNames}
+	weekdayField = field{name: "day-of-week", min: 0, max: 7, names: weekdayNames} // 7 = Sunday
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	minutes  uint64
+	hours    uint64
+	doms     uint64
+	months   uint64
+	weekdays uint64
+	domAny   bool
+	dowAny   bool
+}
+
+// Parse parses a cron expression
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hours, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.doms, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.months, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.weekdays, err = weekdayField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+	s.domAny = fields[2] == "*" || fields[2] == "?"
+	s.dowAny = fields[4] == "*" || fields[4] == "?"
+	return s, nil
+}
+
+// Next returns the first fire time strictly after t, in t's location. It returns the zero time
+// when the expression never fires.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			// Not Truncate: it works on absolute time and breaks in zones with half-hour offsets
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.doms&(1<<uint(t.Day())) != 0
+	dow := s.weekdays&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// parse returns the bit set of the values a field matches
+func (f field) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid cron %s %q: bad step", f.name, part)
+			}
+			rangeExpr, step = part[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid cron %s %q: range start after end", f.name, part)
+			}
+		default:
+			v, err := f.value(rangeExpr)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// "5/10" means from 5 to the end in steps of 10
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid cron %s %q: must be between %d and %d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,55 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	from := time.Date(2026, 10, 15, 10, 7, 30, 0, time.UTC) // Thursday
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 * 1", time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}, // Day of month or Monday
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"10-50/20 * * * *", time.Date(2026, 10, 15, 10, 10, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			s, err := Parse(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, s.Next(from))
+		})
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	s, err := Parse("0 2 * * *")
+	require.NoError(t, err)
+
+	next := s.Next(time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC).In(loc))
+	assert.Equal(t, time.Date(2026, 10, 16, 2, 0, 0, 0, loc), next)
+	assert.Equal(t, time.Date(2026, 10, 15, 18, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
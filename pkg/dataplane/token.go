@@ -0,0 +1,97 @@
+// Package dataplane signs requests that the control plane or proxy sends directly to
+// worker pods (direct-HTTP mode), so workers can reject callers that only learned a pod IP.
+//
+// Each endpoint gets its own key derived from a master secret; the key is handed to the
+// endpoint's workers (via a K8s Secret) and never leaves the control plane otherwise.
+// Tokens bind the endpoint, HTTP method, path and expiry:
+//
+//	v1.<expiry unix seconds>.<base64url(HMAC-SHA256(endpointKey, "v1\n"+endpoint+"\n"+METHOD+"\n"+path+"\n"+expiry))>
+package dataplane
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TokenHeader is the request header carrying the invoke token
+	TokenHeader = "X-Waverless-Invoke-Token"
+	// KeyEnvVar is the worker environment variable holding the hex-encoded endpoint key
+	KeyEnvVar = "WAVERLESS_INVOKE_KEY"
+
+	tokenVersion = "v1"
+)
+
+var (
+	ErrMalformedToken = errors.New("malformed invoke token")
+	ErrExpiredToken   = errors.New("invoke token expired")
+	ErrInvalidToken   = errors.New("invoke token signature mismatch")
+)
+
+// Signer derives endpoint keys and signs invoke tokens
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewSigner creates a signer from the master secret
+func NewSigner(secret string) (*Signer, error) {
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("data plane token secret must be at least 32 characters")
+	}
+	return &Signer{secret: []byte(secret), now: time.Now}, nil
+}
+
+// EndpointKey derives the key an endpoint's workers use to verify tokens
+func (s *Signer) EndpointKey(endpoint string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("waverless-endpoint-key\n" + endpoint))
+	return mac.Sum(nil)
+}
+
+// EndpointKeyHex returns EndpointKey hex-encoded, as stored in the worker environment
+func (s *Signer) EndpointKeyHex(endpoint string) string {
+	return hex.EncodeToString(s.EndpointKey(endpoint))
+}
+
+// Sign issues a token for one method+path on an endpoint, valid for ttl
+func (s *Signer) Sign(endpoint, method, path string, ttl time.Duration) (string, time.Time) {
+	expiresAt := s.now().Add(ttl).Truncate(time.Second)
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	sig := signature(s.EndpointKey(endpoint), endpoint, method, path, expiry)
+	return tokenVersion + "." + expiry + "." + sig, expiresAt
+}
+
+// Verify checks a token against the endpoint key; workers written in Go can use it directly
+func Verify(key []byte, endpoint, method, path, token string, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenVersion {
+		return ErrMalformedToken
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	if now.Unix() > expiry {
+		return ErrExpiredToken
+	}
+	expected := signature(key, endpoint, method, path, parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// signature computes the base64url HMAC over the signed fields
+func signature(key []byte, endpoint, method, path, expiry string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(tokenVersion + "\n" + endpoint + "\n" + strings.ToUpper(method) + "\n" + path + "\n" + expiry))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
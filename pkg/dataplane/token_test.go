@@ -0,0 +1,53 @@
+package dataplane
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestNewSigner_RejectsShortSecret(t *testing.T) {
+	_, err := NewSigner("short")
+	assert.Error(t, err)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	signer, err := NewSigner(testSecret)
+	require.NoError(t, err)
+	now := time.Unix(1_800_000_000, 0)
+	signer.now = func() time.Time { return now }
+
+	token, expiresAt := signer.Sign("flux", "post", "/run", time.Minute)
+	assert.Equal(t, now.Add(time.Minute), expiresAt)
+	key := signer.EndpointKey("flux")
+
+	assert.NoError(t, Verify(key, "flux", "POST", "/run", token, now))
+
+	// Bound to endpoint key, endpoint, method and path
+	assert.ErrorIs(t, Verify(signer.EndpointKey("other"), "flux", "POST", "/run", token, now), ErrInvalidToken)
+	assert.ErrorIs(t, Verify(key, "other", "POST", "/run", token, now), ErrInvalidToken)
+	assert.ErrorIs(t, Verify(key, "flux", "GET", "/run", token, now), ErrInvalidToken)
+	assert.ErrorIs(t, Verify(key, "flux", "POST", "/health", token, now), ErrInvalidToken)
+
+	// Expiry
+	assert.ErrorIs(t, Verify(key, "flux", "POST", "/run", token, now.Add(2*time.Minute)), ErrExpiredToken)
+
+	// Tampered expiry invalidates the signature
+	parts := strings.Split(token, ".")
+	tampered := parts[0] + ".9999999999." + parts[2]
+	assert.ErrorIs(t, Verify(key, "flux", "POST", "/run", tampered, now), ErrInvalidToken)
+
+	assert.ErrorIs(t, Verify(key, "flux", "POST", "/run", "garbage", now), ErrMalformedToken)
+}
+
+func TestEndpointKey_DistinctPerEndpoint(t *testing.T) {
+	signer, err := NewSigner(testSecret)
+	require.NoError(t, err)
+	assert.NotEqual(t, signer.EndpointKeyHex("a"), signer.EndpointKeyHex("b"))
+	assert.Len(t, signer.EndpointKeyHex("a"), 64)
+}
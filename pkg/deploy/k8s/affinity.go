@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Anti-affinity modes between replicas of the same endpoint
+const (
+	AntiAffinityPreferred = "preferred" // Spread when possible (default)
+	AntiAffinityRequired  = "required"  // Never co-locate; replicas stay Pending when no domain is left
+	AntiAffinityNone      = "none"      // No constraint
+)
+
+// Failure domains replicas are spread across
+const (
+	topologyKeyHostname = "kubernetes.io/hostname"
+	topologyKeyZone     = "topology.kubernetes.io/zone"
+)
+
+// Weights of the preferred terms: spreading across nodes matters more than across zones
+const (
+	hostnameAntiAffinityWeight = 100
+	zoneAntiAffinityWeight     = 50
+)
+
+// AntiAffinityPolicy controls how replicas of an endpoint are spread across failure domains.
+// Empty fields default to preferred.
+type AntiAffinityPolicy struct {
+	Hostname string `yaml:"hostname,omitempty" json:"hostname,omitempty"` // preferred, required or none
+	Zone     string `yaml:"zone,omitempty" json:"zone,omitempty"`         // preferred, required or none
+}
+
+// buildReplicaAntiAffinity builds the podAntiAffinity between replicas of an endpoint;
+// nil when the policy disables both domains
+func buildReplicaAntiAffinity(endpoint string, policy *AntiAffinityPolicy) (*corev1.PodAntiAffinity, error) {
+	if policy == nil {
+		policy = &AntiAffinityPolicy{}
+	}
+
+	antiAffinity := &corev1.PodAntiAffinity{}
+	domains := []struct {
+		mode        string
+		topologyKey string
+		weight      int32
+	}{
+		{policy.Hostname, topologyKeyHostname, hostnameAntiAffinityWeight},
+		{policy.Zone, topologyKeyZone, zoneAntiAffinityWeight},
+	}
+	for _, d := range domains {
+		mode, err := normalizeAntiAffinityMode(d.mode)
+		if err != nil {
+			return nil, err
+		}
+		term := corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": endpoint}},
+			TopologyKey:   d.topologyKey,
+		}
+		switch mode {
+		case AntiAffinityRequired:
+			antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+		case AntiAffinityPreferred:
+			antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+				corev1.WeightedPodAffinityTerm{Weight: d.weight, PodAffinityTerm: term})
+		}
+	}
+
+	if len(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) == 0 && len(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) == 0 {
+		return nil, nil
+	}
+	return antiAffinity, nil
+}
+
+// normalizeAntiAffinityMode validates a mode, empty means preferred
+func normalizeAntiAffinityMode(mode string) (string, error) {
+	switch m := strings.ToLower(strings.TrimSpace(mode)); m {
+	case "":
+		return AntiAffinityPreferred, nil
+	case AntiAffinityPreferred, AntiAffinityRequired, AntiAffinityNone:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid anti-affinity mode %q (expected preferred, required or none)", mode)
+	}
+}
+
+// applyReplicaAntiAffinity replaces the podAntiAffinity of a pod spec, keeping other affinity rules
+func applyReplicaAntiAffinity(podSpec *corev1.PodSpec, antiAffinity *corev1.PodAntiAffinity) {
+	if podSpec.Affinity == nil {
+		if antiAffinity == nil {
+			return
+		}
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	podSpec.Affinity.PodAntiAffinity = antiAffinity
+	if podSpec.Affinity.NodeAffinity == nil && podSpec.Affinity.PodAffinity == nil && podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity = nil
+	}
+}
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestBuildReplicaAntiAffinity_DefaultPreferred(t *testing.T) {
+	antiAffinity, err := buildReplicaAntiAffinity("flux", nil)
+	require.NoError(t, err)
+	require.NotNil(t, antiAffinity)
+
+	assert.Empty(t, antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	require.Len(t, antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 2)
+	hostname := antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0]
+	assert.Equal(t, topologyKeyHostname, hostname.PodAffinityTerm.TopologyKey)
+	assert.Equal(t, map[string]string{"app": "flux"}, hostname.PodAffinityTerm.LabelSelector.MatchLabels)
+	assert.Greater(t, hostname.Weight, antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[1].Weight)
+	assert.Equal(t, topologyKeyZone, antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[1].PodAffinityTerm.TopologyKey)
+}
+
+func TestBuildReplicaAntiAffinity_Policy(t *testing.T) {
+	antiAffinity, err := buildReplicaAntiAffinity("flux", &AntiAffinityPolicy{Hostname: "Required", Zone: "none"})
+	require.NoError(t, err)
+	require.Len(t, antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, 1)
+	assert.Equal(t, topologyKeyHostname, antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey)
+	assert.Empty(t, antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+
+	antiAffinity, err = buildReplicaAntiAffinity("flux", &AntiAffinityPolicy{Hostname: "none", Zone: "none"})
+	require.NoError(t, err)
+	assert.Nil(t, antiAffinity)
+
+	_, err = buildReplicaAntiAffinity("flux", &AntiAffinityPolicy{Zone: "sometimes"})
+	assert.Error(t, err)
+}
+
+func TestApplyReplicaAntiAffinity_KeepsNodeAffinity(t *testing.T) {
+	nodeAffinity := &corev1.NodeAffinity{}
+	podSpec := &corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: nodeAffinity}}
+
+	antiAffinity, err := buildReplicaAntiAffinity("flux", nil)
+	require.NoError(t, err)
+	applyReplicaAntiAffinity(podSpec, antiAffinity)
+	assert.Same(t, nodeAffinity, podSpec.Affinity.NodeAffinity)
+	assert.Equal(t, antiAffinity, podSpec.Affinity.PodAntiAffinity)
+
+	// Disabling keeps the node affinity
+	applyReplicaAntiAffinity(podSpec, nil)
+	require.NotNil(t, podSpec.Affinity)
+	assert.Nil(t, podSpec.Affinity.PodAntiAffinity)
+
+	// Nothing left: the affinity is dropped
+	empty := &corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: antiAffinity}}
+	applyReplicaAntiAffinity(empty, nil)
+	assert.Nil(t, empty.Affinity)
+}
+
+func TestDeploymentTemplate_RendersAffinity(t *testing.T) {
+	antiAffinity, err := buildReplicaAntiAffinity("flux", &AntiAffinityPolicy{Hostname: AntiAffinityRequired})
+	require.NoError(t, err)
+	affinityJSON, err := json.Marshal(&corev1.Affinity{PodAntiAffinity: antiAffinity})
+	require.NoError(t, err)
+
+	renderer := NewTemplateRenderer("../../../config/templates")
+	content, err := renderer.Render("deployment.yaml", &RenderContext{
+		Endpoint:      "flux",
+		Namespace:     "wavespeed",
+		Image:         "flux:latest",
+		Replicas:      2,
+		ContainerName: "flux-worker",
+		ContainerPort: 8000,
+		CpuLimit:      "4",
+		MemoryRequest: "8Gi",
+		AffinityJSON:  string(affinityJSON),
+	})
+	require.NoError(t, err)
+
+	var deployment appsv1.Deployment
+	require.NoError(t, yaml.Unmarshal([]byte(content), &deployment))
+	require.NotNil(t, deployment.Spec.Template.Spec.Affinity)
+	assert.Equal(t, antiAffinity, deployment.Spec.Template.Spec.Affinity.PodAntiAffinity)
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"waverless/pkg/backup"
+)
+
+const registrySecretPrefix = "registry-"
+
+// ListRegistryCredentials returns the image pull secrets created for endpoints
+func (m *Manager) ListRegistryCredentials(ctx context.Context) ([]backup.RegistryCredential, error) {
+	list, err := m.client.CoreV1().Secrets(m.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	var creds []backup.RegistryCredential
+	for i := range list.Items {
+		secret := &list.Items[i]
+		if secret.Type != corev1.SecretTypeDockerConfigJson || !strings.HasPrefix(secret.Name, registrySecretPrefix) {
+			continue
+		}
+		creds = append(creds, backup.RegistryCredential{
+			Endpoint:         strings.TrimPrefix(secret.Name, registrySecretPrefix),
+			DockerConfigJSON: secret.Data[corev1.DockerConfigJsonKey],
+		})
+	}
+	sort.Slice(creds, func(i, j int) bool { return creds[i].Endpoint < creds[j].Endpoint })
+	return creds, nil
+}
+
+// RestoreRegistryCredential creates or replaces an endpoint's image pull secret
+func (m *Manager) RestoreRegistryCredential(ctx context.Context, cred backup.RegistryCredential) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      registrySecretPrefix + cred.Endpoint,
+			Namespace: m.namespace,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: cred.DockerConfigJSON,
+		},
+	}
+
+	secrets := m.client.CoreV1().Secrets(m.namespace)
+	existing, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	}
+
+	secret.ResourceVersion = existing.ResourceVersion
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/interfaces"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// SlotLabel marks the pods of a blue/green candidate deployment
+	SlotLabel = "waverless.io/slot"
+	// slotGreen is the slot of candidate pods (live pods carry no slot)
+	slotGreen = "green"
+)
+
+// candidateName is the name of the candidate deployment of an endpoint
+func candidateName(endpoint string) string {
+	return endpoint + "-" + slotGreen
+}
+
+// candidateDeployment turns desired, the live deployment with an update applied, into the
+// candidate deployment of endpoint. The candidate itself is not managed-by waverless so it is
+// never listed or reconciled as an endpoint; its pods keep the endpoint's labels so they
+// register as the endpoint's workers, plus the slot label its selector matches on.
+func candidateDeployment(desired *appsv1.Deployment, endpoint string) *appsv1.Deployment {
+	candidate := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        candidateName(endpoint),
+			Namespace:   desired.Namespace,
+			Labels:      map[string]string{"app": endpoint, SlotLabel: slotGreen},
+			Annotations: desired.Annotations,
+		},
+		Spec: *desired.Spec.DeepCopy(),
+	}
+
+	candidate.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": endpoint, SlotLabel: slotGreen},
+	}
+	if candidate.Spec.Template.Labels == nil {
+		candidate.Spec.Template.Labels = make(map[string]string)
+	}
+	candidate.Spec.Template.Labels["app"] = endpoint
+	candidate.Spec.Template.Labels[SlotLabel] = slotGreen
+	return candidate
+}
+
+// DeployCandidate creates the candidate deployment of an endpoint, replacing a leftover one
+func (m *Manager) DeployCandidate(ctx context.Context, req *interfaces.UpdateDeploymentRequest) error {
+	deployments := m.client.AppsV1().Deployments(m.namespace)
+	live, err := deployments.Get(ctx, req.Endpoint, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	desired := live.DeepCopy()
+	if err := m.applyDeploymentUpdate(ctx, desired, req.Endpoint, req.SpecName, req.Image, req.Replicas, req.VolumeMounts, req.ShmSize, req.EphemeralStorage, req.EnablePtrace, req.Env, req.RunPodCompat); err != nil {
+		return err
+	}
+	if desired.Spec.Replicas == nil || *desired.Spec.Replicas == 0 {
+		return fmt.Errorf("endpoint %s has no replicas to run a blue/green update with", req.Endpoint)
+	}
+
+	candidate := candidateDeployment(desired, req.Endpoint)
+	_, err = deployments.Create(ctx, candidate, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		existing, getErr := deployments.Get(ctx, candidate.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("failed to get candidate deployment: %w", getErr)
+		}
+		candidate.ResourceVersion = existing.ResourceVersion
+		_, err = deployments.Update(ctx, candidate, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create candidate deployment: %w", err)
+	}
+	return nil
+}
+
+// GetCandidateStatus returns the status of an endpoint's candidate deployment. The candidate
+// is not in the informer cache (it is not managed-by waverless), so it is read live.
+func (m *Manager) GetCandidateStatus(ctx context.Context, endpoint string) (*AppInfo, error) {
+	deployment, err := m.client.AppsV1().Deployments(m.namespace).Get(ctx, candidateName(endpoint), metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate deployment: %w", err)
+	}
+	info := deploymentToAppInfo(deployment)
+	m.setInitStatus(info, deployment)
+	return info, nil
+}
+
+// ListCandidateWorkers returns the names of the pods of an endpoint's candidate deployment
+func (m *Manager) ListCandidateWorkers(ctx context.Context, endpoint string) ([]string, error) {
+	selector := labels.SelectorFromSet(labels.Set{"app": endpoint, SlotLabel: slotGreen})
+	pods, err := m.podLister.Pods(m.namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list candidate pods from cache: %w", err)
+	}
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil {
+			names = append(names, pod.Name)
+		}
+	}
+	return names, nil
+}
+
+// DeleteCandidate deletes an endpoint's candidate deployment and its pods
+func (m *Manager) DeleteCandidate(ctx context.Context, endpoint string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := m.client.AppsV1().Deployments(m.namespace).Delete(ctx, candidateName(endpoint), metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete candidate deployment: %w", err)
+	}
+	return nil
+}
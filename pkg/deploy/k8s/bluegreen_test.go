@@ -0,0 +1,37 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestCandidateDeployment(t *testing.T) {
+	desired := testDeployment("img:2", 3)
+	desired.Labels = map[string]string{"app": "flux", "managed-by": "waverless"}
+	desired.Annotations = map[string]string{"waverless.io/spec-override": "{}"}
+	desired.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "flux"}}
+	desired.Spec.Template.Labels = map[string]string{"app": "flux", "managed-by": "waverless", "waverless.io/spec": "a10"}
+
+	candidate := candidateDeployment(desired, "flux")
+	assert.Equal(t, "flux-green", candidate.Name)
+	assert.NotContains(t, candidate.Labels, "managed-by", "the candidate must not be listed as an endpoint")
+	assert.Equal(t, desired.Annotations, candidate.Annotations)
+	assert.Equal(t, int32(3), *candidate.Spec.Replicas)
+	assert.Equal(t, "img:2", candidate.Spec.Template.Spec.Containers[0].Image)
+
+	podLabels := labels.Set(candidate.Spec.Template.Labels)
+	assert.Equal(t, "waverless", podLabels["managed-by"], "candidate pods register as the endpoint's workers")
+	assert.Equal(t, "a10", podLabels["waverless.io/spec"])
+
+	candidateSelector, err := metav1.LabelSelectorAsSelector(candidate.Spec.Selector)
+	assert.NoError(t, err)
+	assert.True(t, candidateSelector.Matches(podLabels))
+	assert.False(t, candidateSelector.Matches(labels.Set(desired.Spec.Template.Labels)), "live pods must not be adopted by the candidate")
+
+	// The live deployment is left untouched
+	assert.Equal(t, map[string]string{"app": "flux"}, desired.Spec.Selector.MatchLabels)
+	assert.NotContains(t, desired.Spec.Template.Labels, SlotLabel)
+}
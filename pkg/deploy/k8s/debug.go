@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Debug container defaults
+const (
+	DebugContainerPrefix   = "debugger-"
+	DefaultDebugImage      = "nicolaka/netshoot:latest"
+	DefaultDebugTTL        = 15 * time.Minute
+	DefaultDebugMaxTTL     = time.Hour
+	debugContainerMinTTL   = time.Minute
+	debugContainerShellCmd = "/bin/sh"
+)
+
+// DebugContainerRequest asks for an ephemeral debug container in a worker pod
+type DebugContainerRequest struct {
+	Endpoint string
+	PodName  string
+	Image    string        // Empty = configured debug image
+	TTL      time.Duration // Zero = DefaultDebugTTL; capped at the configured maximum
+}
+
+// DebugContainerInfo describes an attached debug container
+type DebugContainerInfo struct {
+	PodName       string    `json:"podName"`
+	ContainerName string    `json:"containerName"`
+	TargetName    string    `json:"targetContainer"`
+	Image         string    `json:"image"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// SetDebugContainerConfig sets the default image and maximum lifetime of debug containers
+func (m *Manager) SetDebugContainerConfig(image string, maxTTL time.Duration) {
+	m.debugImage = image
+	m.debugMaxTTL = maxTTL
+}
+
+// AttachDebugContainer injects an ephemeral container into a worker pod that shares the
+// worker's process namespace and carries SYS_PTRACE, so profilers can attach without the
+// long-lived worker container running with that capability. The container runs `sleep TTL`
+// as its entrypoint: once the TTL elapses it exits and can no longer be exec'd into.
+func (m *Manager) AttachDebugContainer(ctx context.Context, req *DebugContainerRequest) (*DebugContainerInfo, error) {
+	pods := m.client.CoreV1().Pods(m.namespace)
+	pod, err := pods.Get(ctx, req.PodName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", req.PodName, err)
+	}
+	if pod.Labels["app"] != req.Endpoint {
+		return nil, fmt.Errorf("pod %s does not belong to endpoint %s", req.PodName, req.Endpoint)
+	}
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return nil, fmt.Errorf("pod %s is not running", req.PodName)
+	}
+
+	image := req.Image
+	if image == "" {
+		image = m.debugImage
+	}
+	if image == "" {
+		image = DefaultDebugImage
+	}
+	ttl := clampDebugTTL(req.TTL, m.debugMaxTTL)
+
+	now := time.Now()
+	name := DebugContainerPrefix + strconv.FormatInt(now.Unix(), 36)
+	target := req.Endpoint + "-worker"
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:    name,
+			Image:   image,
+			Command: []string{"sleep", strconv.Itoa(int(ttl.Seconds()))},
+			Stdin:   true,
+			TTY:     true,
+			SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_PTRACE"}},
+			},
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+		TargetContainerName: target,
+	})
+
+	if _, err := pods.UpdateEphemeralContainers(ctx, pod.Name, pod, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to add debug container to pod %s: %w", req.PodName, err)
+	}
+
+	return &DebugContainerInfo{
+		PodName:       pod.Name,
+		ContainerName: name,
+		TargetName:    target,
+		Image:         image,
+		ExpiresAt:     now.Add(ttl),
+	}, nil
+}
+
+// IsDebugContainerName reports whether name refers to a container created by AttachDebugContainer
+func IsDebugContainerName(name string) bool {
+	return strings.HasPrefix(name, DebugContainerPrefix)
+}
+
+// DebugShellCommand is the shell started when exec'ing into a debug container
+func DebugShellCommand() []string {
+	return []string{debugContainerShellCmd}
+}
+
+// clampDebugTTL applies the default and bounds the lifetime to [1m, maxTTL]
+func clampDebugTTL(ttl, maxTTL time.Duration) time.Duration {
+	if maxTTL <= 0 {
+		maxTTL = DefaultDebugMaxTTL
+	}
+	if ttl <= 0 {
+		ttl = DefaultDebugTTL
+	}
+	if ttl < debugContainerMinTTL {
+		ttl = debugContainerMinTTL
+	}
+	if ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return ttl
+}
@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClampDebugTTL(t *testing.T) {
+	assert.Equal(t, DefaultDebugTTL, clampDebugTTL(0, 0))
+	assert.Equal(t, time.Minute, clampDebugTTL(5*time.Second, 0))
+	assert.Equal(t, DefaultDebugMaxTTL, clampDebugTTL(3*time.Hour, 0))
+	assert.Equal(t, 10*time.Minute, clampDebugTTL(30*time.Minute, 10*time.Minute))
+}
+
+func TestAttachDebugContainer(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "flux-abc", Namespace: "wavespeed", Labels: map[string]string{"app": "flux"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	m := &Manager{client: fake.NewSimpleClientset(pod), namespace: "wavespeed", debugImage: "tools:1"}
+	ctx := context.Background()
+
+	info, err := m.AttachDebugContainer(ctx, &DebugContainerRequest{Endpoint: "flux", PodName: "flux-abc", TTL: 2 * time.Minute})
+	require.NoError(t, err)
+	assert.True(t, IsDebugContainerName(info.ContainerName))
+	assert.Equal(t, "flux-worker", info.TargetName)
+	assert.Equal(t, "tools:1", info.Image)
+
+	updated, err := m.client.CoreV1().Pods("wavespeed").Get(ctx, "flux-abc", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, updated.Spec.EphemeralContainers, 1)
+	ec := updated.Spec.EphemeralContainers[0]
+	assert.Equal(t, []string{"sleep", "120"}, ec.Command)
+	assert.Equal(t, "flux-worker", ec.TargetContainerName)
+	assert.Equal(t, []corev1.Capability{"SYS_PTRACE"}, ec.SecurityContext.Capabilities.Add)
+
+	// Pods of other endpoints are rejected
+	_, err = m.AttachDebugContainer(ctx, &DebugContainerRequest{Endpoint: "other", PodName: "flux-abc"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"waverless/pkg/interfaces"
+)
+
+// ViewDeployment describes the live Deployment of an endpoint (nil when it does not exist)
+// and the Deployment req would render, without applying anything
+func (m *Manager) ViewDeployment(ctx context.Context, req *DeployAppRequest) (current, proposed *interfaces.DeploymentView, err error) {
+	if err := validateK8sName(req.Endpoint); err != nil {
+		return nil, nil, fmt.Errorf("invalid endpoint name: %w", err)
+	}
+	req.Endpoint = strings.ToLower(strings.TrimSpace(req.Endpoint))
+
+	spec, err := m.specManager.GetSpec(req.SpecName)
+	if err != nil {
+		return nil, nil, err
+	}
+	renderCtx, err := m.buildRenderContext(req, spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := m.addEnvSecretRefs(ctx, req.Endpoint, renderCtx); err != nil {
+		return nil, nil, err
+	}
+	yamlContent, err := m.renderer.Render("deployment.yaml", renderCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	deployments, err := parseDeployments(yamlContent)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(deployments) == 0 {
+		return nil, nil, fmt.Errorf("template rendered no Deployment")
+	}
+	proposed = deploymentView(deployments[0])
+
+	current, err = m.LiveDeployment(ctx, req.Endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, proposed, nil
+}
+
+// LiveDeployment returns the view of the live Deployment of an endpoint, nil when it does not exist
+func (m *Manager) LiveDeployment(ctx context.Context, endpoint string) (*interfaces.DeploymentView, error) {
+	live, err := m.client.AppsV1().Deployments(m.namespace).Get(ctx, endpoint, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+	return deploymentView(live), nil
+}
+
+// parseDeployments parses a rendered multi-document manifest, which may only contain Deployments
+func parseDeployments(yamlContent string) ([]*appsv1.Deployment, error) {
+	var deployments []*appsv1.Deployment
+	for _, doc := range strings.Split(yamlContent, "---") {
+		doc = strings.TrimSpace(doc)
+		if doc == "" {
+			continue
+		}
+		var deployment appsv1.Deployment
+		if err := yaml.Unmarshal([]byte(doc), &deployment); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		if deployment.Kind != "Deployment" {
+			return nil, fmt.Errorf("unsupported resource kind: %s", deployment.Kind)
+		}
+		deployments = append(deployments, &deployment)
+	}
+	return deployments, nil
+}
+
+// deploymentView flattens the worker container of a Deployment into a DeploymentView
+func deploymentView(d *appsv1.Deployment) *interfaces.DeploymentView {
+	container := firstContainer(&d.Spec.Template.Spec)
+	view := &interfaces.DeploymentView{
+		Image:       container.Image,
+		Resources:   make(map[string]string),
+		Env:         make(map[string]string, len(container.Env)),
+		Volumes:     make(map[string]string, len(container.VolumeMounts)),
+		Tolerations: make(map[string]string, len(d.Spec.Template.Spec.Tolerations)),
+	}
+
+	for name, q := range container.Resources.Requests {
+		view.Resources["requests."+string(name)] = q.String()
+	}
+	for name, q := range container.Resources.Limits {
+		view.Resources["limits."+string(name)] = q.String()
+	}
+
+	for _, e := range container.Env {
+		view.Env[e.Name] = envVarValue(e)
+	}
+
+	sources := make(map[string]string, len(d.Spec.Template.Spec.Volumes))
+	for _, v := range d.Spec.Template.Spec.Volumes {
+		sources[v.Name] = volumeSource(v)
+	}
+	for _, vm := range container.VolumeMounts {
+		source, ok := sources[vm.Name]
+		if !ok {
+			source = "volume:" + vm.Name
+		}
+		if vm.ReadOnly {
+			source += " (ro)"
+		}
+		view.Volumes[vm.MountPath] = source
+	}
+
+	for _, t := range d.Spec.Template.Spec.Tolerations {
+		view.Tolerations[tolerationKey(t)] = tolerationValue(t)
+	}
+	return view
+}
+
+// tolerationKey identifies a toleration by key and effect; empty parts match everything
+func tolerationKey(t corev1.Toleration) string {
+	key, effect := t.Key, string(t.Effect)
+	if key == "" {
+		key = "*"
+	}
+	if effect == "" {
+		effect = "*"
+	}
+	return key + ":" + effect
+}
+
+// tolerationValue describes what a toleration matches and for how long
+func tolerationValue(t corev1.Toleration) string {
+	value := "Exists"
+	if t.Operator != corev1.TolerationOpExists {
+		value = "Equal " + t.Value
+	}
+	if t.TolerationSeconds != nil {
+		value += fmt.Sprintf(" for %ds", *t.TolerationSeconds)
+	}
+	return value
+}
+
+// envVarValue returns the literal value of an env var, or where it is read from
+func envVarValue(e corev1.EnvVar) string {
+	from := e.ValueFrom
+	switch {
+	case from == nil:
+		return e.Value
+	case from.SecretKeyRef != nil:
+		return fmt.Sprintf("secret:%s/%s", from.SecretKeyRef.Name, from.SecretKeyRef.Key)
+	case from.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configMap:%s/%s", from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key)
+	case from.FieldRef != nil:
+		return "field:" + from.FieldRef.FieldPath
+	case from.ResourceFieldRef != nil:
+		return "resource:" + from.ResourceFieldRef.Resource
+	default:
+		return "valueFrom"
+	}
+}
+
+// volumeSource describes where a volume's data comes from
+func volumeSource(v corev1.Volume) string {
+	switch {
+	case v.PersistentVolumeClaim != nil:
+		return "pvc:" + v.PersistentVolumeClaim.ClaimName
+	case v.EmptyDir != nil:
+		source := "emptyDir"
+		if v.EmptyDir.Medium != "" {
+			source += ":" + string(v.EmptyDir.Medium)
+		}
+		if v.EmptyDir.SizeLimit != nil {
+			source += " " + v.EmptyDir.SizeLimit.String()
+		}
+		return source
+	case v.ConfigMap != nil:
+		return "configMap:" + v.ConfigMap.Name
+	case v.Secret != nil:
+		return "secret:" + v.Secret.SecretName
+	case v.HostPath != nil:
+		return "hostPath:" + v.HostPath.Path
+	default:
+		return "volume:" + v.Name
+	}
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeploymentView(t *testing.T) {
+	shm := resource.MustParse("1Gi")
+	d := testDeployment("flux:1", 1,
+		corev1.EnvVar{Name: "A", Value: "1"},
+		corev1.EnvVar{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "invoke-flux"}, Key: "token"}}},
+	)
+	d.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{Name: "models", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "models"}}},
+		{Name: "dshm", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &shm}}},
+	}
+	container := &d.Spec.Template.Spec.Containers[0]
+	container.VolumeMounts = []corev1.VolumeMount{
+		{Name: "models", MountPath: "/models", ReadOnly: true},
+		{Name: "dshm", MountPath: "/dev/shm"},
+	}
+	container.Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		Limits:   corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+	}
+	evictAfter := int64(300)
+	d.Spec.Template.Spec.Tolerations = []corev1.Toleration{
+		{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoExecute, TolerationSeconds: &evictAfter},
+		{Operator: corev1.TolerationOpExists},
+	}
+
+	view := deploymentView(d)
+	assert.Equal(t, "flux:1", view.Image)
+	assert.Equal(t, map[string]string{"requests.cpu": "4", "limits.nvidia.com/gpu": "1"}, view.Resources)
+	assert.Equal(t, map[string]string{"A": "1", "TOKEN": "secret:invoke-flux/token"}, view.Env)
+	assert.Equal(t, map[string]string{"/models": "pvc:models (ro)", "/dev/shm": "emptyDir:Memory 1Gi"}, view.Volumes)
+	assert.Equal(t, map[string]string{
+		"nvidia.com/gpu:NoSchedule": "Exists",
+		"spot:NoExecute":            "Equal true for 300s",
+		"*:*":                       "Exists",
+	}, view.Tolerations)
+}
+
+func TestLiveDeployment(t *testing.T) {
+	d := testDeployment("flux:1", 1)
+	d.Namespace = "wavespeed"
+	m := &Manager{client: fake.NewSimpleClientset(d), namespace: "wavespeed"}
+
+	view, err := m.LiveDeployment(context.Background(), "flux")
+	require.NoError(t, err)
+	require.NotNil(t, view)
+	assert.Equal(t, "flux:1", view.Image)
+
+	view, err = m.LiveDeployment(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Nil(t, view, "an endpoint that is not deployed has no live view")
+}
@@ -0,0 +1,278 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"waverless/pkg/interfaces"
+)
+
+// dryRunAll asks the API server to run validation and admission (including ResourceQuota)
+// without persisting the object
+var dryRunAll = []string{metav1.DryRunAll}
+
+// DryRunDeployApp runs every step of DeployApp without persisting anything: name and spec
+// validation, security policy, template rendering and a server-side dry run of the Deployment.
+// Secrets and isolation objects are only checked for existence.
+func (m *Manager) DryRunDeployApp(ctx context.Context, req *DeployAppRequest) (*interfaces.DryRunResult, error) {
+	if err := validateK8sName(req.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid endpoint name: %w", err)
+	}
+	req.Endpoint = strings.ToLower(strings.TrimSpace(req.Endpoint))
+
+	spec, err := m.specManager.GetSpec(req.SpecName)
+	if err != nil {
+		return nil, err
+	}
+	renderCtx, err := m.buildRenderContext(req, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &interfaces.DryRunResult{}
+	if req.Replicas == 0 {
+		result.Warnings = append(result.Warnings, "replicas not set, 1 replica will be deployed")
+	}
+	if renderCtx.IsGpu && req.GpuCount == 0 {
+		result.Warnings = append(result.Warnings, "gpuCount not set, 1 GPU per replica will be requested")
+	}
+	result.Warnings = append(result.Warnings, m.missingPVCWarnings(ctx, req.VolumeMounts)...)
+
+	secrets := m.client.CoreV1().Secrets(m.namespace)
+	if req.RegistryCredential != nil {
+		renderCtx.ImagePullSecret = fmt.Sprintf("registry-%s", req.Endpoint)
+		_, err := secrets.Get(ctx, renderCtx.ImagePullSecret, metav1.GetOptions{})
+		if err := addExistenceChange(result, "Secret", renderCtx.ImagePullSecret, err); err != nil {
+			return nil, err
+		}
+	}
+	if renderCtx.InvokeKeySecret != "" {
+		_, err := secrets.Get(ctx, renderCtx.InvokeKeySecret, metav1.GetOptions{})
+		if err := addExistenceChange(result, "Secret", renderCtx.InvokeKeySecret, err); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range req.Storage {
+		pvcName := storagePVCName(req.Endpoint, r)
+		_, err := m.client.CoreV1().PersistentVolumeClaims(m.namespace).Get(ctx, pvcName, metav1.GetOptions{})
+		if err := addExistenceChange(result, "PersistentVolumeClaim", pvcName, err); err != nil {
+			return nil, err
+		}
+	}
+
+	isolation, err := m.buildIsolationObjects(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if sa := isolation.serviceAccount; sa != nil {
+		_, err := m.client.CoreV1().ServiceAccounts(m.namespace).Get(ctx, sa.Name, metav1.GetOptions{})
+		if err := addExistenceChange(result, "ServiceAccount", sa.Name, err); err != nil {
+			return nil, err
+		}
+	}
+	if policy := isolation.networkPolicy; policy != nil {
+		_, err := m.client.NetworkingV1().NetworkPolicies(m.namespace).Get(ctx, policy.Name, metav1.GetOptions{})
+		if err := addExistenceChange(result, "NetworkPolicy", policy.Name, err); err != nil {
+			return nil, err
+		}
+	}
+
+	yamlContent, err := m.renderer.Render("deployment.yaml", renderCtx)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := parseDeployments(yamlContent)
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments {
+		change, err := m.dryRunApplyDeployment(ctx, deployment)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	result.Manifest, err = appendObjectsYAML(yamlContent, isolation.objects())
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DryRunUpdateDeployment applies an update to a copy of the live Deployment and submits it
+// as a server-side dry run, reporting which fields would change and their values before and
+// after the update
+func (m *Manager) DryRunUpdateDeployment(ctx context.Context, endpoint string, specName string, image string, replicas *int, volumeMounts *[]interfaces.VolumeMount, shmSize *string, ephemeralStorage *string, enablePtrace *bool, env *map[string]string, runPodCompat *bool) (*interfaces.DryRunResult, error) {
+	deployments := m.client.AppsV1().Deployments(m.namespace)
+	current, err := deployments.Get(ctx, endpoint, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	desired := current.DeepCopy()
+	if err := m.applyDeploymentUpdate(ctx, desired, endpoint, specName, image, replicas, volumeMounts, shmSize, ephemeralStorage, enablePtrace, env, runPodCompat); err != nil {
+		return nil, err
+	}
+	updated, err := deployments.Update(ctx, desired, metav1.UpdateOptions{DryRun: dryRunAll})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update deployment: %w", err)
+	}
+
+	result := &interfaces.DryRunResult{}
+	if replicas != nil && *replicas == 0 {
+		result.Warnings = append(result.Warnings, "replicas is 0, the endpoint will stop processing tasks")
+	}
+	if volumeMounts != nil {
+		result.Warnings = append(result.Warnings, m.missingPVCWarnings(ctx, *volumeMounts)...)
+	}
+	result.Changes = append(result.Changes, deploymentChange(current, updated))
+	result.Current, result.Proposed = deploymentView(current), deploymentView(updated)
+
+	manifest := updated.DeepCopy()
+	manifest.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	manifest.ManagedFields = nil
+	manifest.Status = appsv1.DeploymentStatus{}
+	doc, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Deployment: %w", err)
+	}
+	result.Manifest = string(doc)
+	return result, nil
+}
+
+// dryRunApplyDeployment is applyDeployment as a server-side dry run
+func (m *Manager) dryRunApplyDeployment(ctx context.Context, deployment *appsv1.Deployment) (interfaces.ResourceChange, error) {
+	deployments := m.client.AppsV1().Deployments(m.namespace)
+	existing, err := deployments.Get(ctx, deployment.Name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return interfaces.ResourceChange{}, fmt.Errorf("failed to get Deployment: %w", err)
+		}
+		if _, err := deployments.Create(ctx, deployment, metav1.CreateOptions{DryRun: dryRunAll}); err != nil {
+			return interfaces.ResourceChange{}, err
+		}
+		return interfaces.ResourceChange{Kind: "Deployment", Name: deployment.Name, Action: interfaces.ChangeActionCreate}, nil
+	}
+
+	deployment.ResourceVersion = existing.ResourceVersion
+	updated, err := deployments.Update(ctx, deployment, metav1.UpdateOptions{DryRun: dryRunAll})
+	if err != nil {
+		return interfaces.ResourceChange{}, err
+	}
+	return deploymentChange(existing, updated), nil
+}
+
+// missingPVCWarnings warns about mounted PVCs that do not exist (pods would stay Pending)
+func (m *Manager) missingPVCWarnings(ctx context.Context, mounts []interfaces.VolumeMount) []string {
+	var warnings []string
+	for _, vm := range mounts {
+		_, err := m.client.CoreV1().PersistentVolumeClaims(m.namespace).Get(ctx, vm.PVCName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			warnings = append(warnings, fmt.Sprintf("PVC %s not found in namespace %s, pods will stay Pending until it exists", vm.PVCName, m.namespace))
+		}
+	}
+	return warnings
+}
+
+// addExistenceChange records create or update for an object that is applied unconditionally
+func addExistenceChange(r *interfaces.DryRunResult, kind, name string, getErr error) error {
+	action := interfaces.ChangeActionUpdate
+	if getErr != nil {
+		if !errors.IsNotFound(getErr) {
+			return fmt.Errorf("failed to get %s %s: %w", kind, name, getErr)
+		}
+		action = interfaces.ChangeActionCreate
+	}
+	r.Changes = append(r.Changes, interfaces.ResourceChange{Kind: kind, Name: name, Action: action})
+	return nil
+}
+
+// deploymentChange reports how desired differs from current
+func deploymentChange(current, desired *appsv1.Deployment) interfaces.ResourceChange {
+	change := interfaces.ResourceChange{Kind: "Deployment", Name: current.Name, Action: interfaces.ChangeActionUnchanged}
+	if fields := deploymentChangedFields(current, desired); len(fields) > 0 {
+		change.Action = interfaces.ChangeActionUpdate
+		change.Fields = fields
+	}
+	return change
+}
+
+// deploymentChangedFields lists the worker-relevant fields that differ between two versions
+// of a Deployment. Env is compared by name since it is rendered from a map.
+func deploymentChangedFields(current, desired *appsv1.Deployment) []string {
+	var fields []string
+	compare := func(name string, a, b interface{}) {
+		if !apiequality.Semantic.DeepEqual(a, b) {
+			fields = append(fields, name)
+		}
+	}
+
+	compare("replicas", current.Spec.Replicas, desired.Spec.Replicas)
+	cur, des := current.Spec.Template, desired.Spec.Template
+	curContainer, desContainer := firstContainer(&cur.Spec), firstContainer(&des.Spec)
+	compare("image", curContainer.Image, desContainer.Image)
+	compare("resources", curContainer.Resources, desContainer.Resources)
+	compare("env", envByName(curContainer.Env), envByName(desContainer.Env))
+	compare("volumes", cur.Spec.Volumes, des.Spec.Volumes)
+	compare("volumeMounts", curContainer.VolumeMounts, desContainer.VolumeMounts)
+	compare("securityContext", curContainer.SecurityContext, desContainer.SecurityContext)
+	compare("nodeSelector", cur.Spec.NodeSelector, des.Spec.NodeSelector)
+	compare("tolerations", cur.Spec.Tolerations, des.Spec.Tolerations)
+	compare("affinity", cur.Spec.Affinity, des.Spec.Affinity)
+	compare("serviceAccountName", cur.Spec.ServiceAccountName, des.Spec.ServiceAccountName)
+	compare("labels", cur.Labels, des.Labels)
+	compare("annotations", cur.Annotations, des.Annotations)
+
+	if len(fields) == 0 && !apiequality.Semantic.DeepEqual(sortedEnvTemplate(cur), sortedEnvTemplate(des)) {
+		fields = append(fields, "template")
+	}
+	return fields
+}
+
+// sortedEnvTemplate returns a copy of a pod template with container env sorted by name
+func sortedEnvTemplate(template corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
+	sorted := template.DeepCopy()
+	for i := range sorted.Spec.Containers {
+		env := sorted.Spec.Containers[i].Env
+		sort.SliceStable(env, func(a, b int) bool { return env[a].Name < env[b].Name })
+	}
+	return sorted
+}
+
+func firstContainer(spec *corev1.PodSpec) corev1.Container {
+	if len(spec.Containers) == 0 {
+		return corev1.Container{}
+	}
+	return spec.Containers[0]
+}
+
+func envByName(env []corev1.EnvVar) map[string]corev1.EnvVar {
+	byName := make(map[string]corev1.EnvVar, len(env))
+	for _, e := range env {
+		byName[e.Name] = e
+	}
+	return byName
+}
+
+// appendObjectsYAML appends objects to a rendered manifest as extra YAML documents
+func appendObjectsYAML(yamlContent string, objs []interface{}) (string, error) {
+	for _, obj := range objs {
+		doc, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal %T: %v", obj, err)
+		}
+		yamlContent = strings.TrimRight(yamlContent, "\n") + "\n---\n" + string(doc)
+	}
+	return yamlContent, nil
+}
@@ -0,0 +1,37 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"waverless/pkg/interfaces"
+)
+
+func testDeployment(image string, replicas int32, env ...corev1.EnvVar) *appsv1.Deployment {
+	d := &appsv1.Deployment{}
+	d.Name = "flux"
+	d.Spec.Replicas = &replicas
+	d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "worker", Image: image, Env: env}}
+	return d
+}
+
+func TestDeploymentChange(t *testing.T) {
+	a := corev1.EnvVar{Name: "A", Value: "1"}
+	b := corev1.EnvVar{Name: "B", Value: "2"}
+
+	change := deploymentChange(testDeployment("img:1", 1, a, b), testDeployment("img:1", 1, b, a))
+	assert.Equal(t, interfaces.ChangeActionUnchanged, change.Action, "env order must not count as a change")
+	assert.Empty(t, change.Fields)
+
+	change = deploymentChange(testDeployment("img:1", 1, a), testDeployment("img:2", 3, a, b))
+	assert.Equal(t, interfaces.ChangeActionUpdate, change.Action)
+	assert.Equal(t, []string{"replicas", "image", "env"}, change.Fields)
+
+	desired := testDeployment("img:1", 1)
+	desired.Spec.Template.Spec.TerminationGracePeriodSeconds = new(int64)
+	change = deploymentChange(testDeployment("img:1", 1), desired)
+	assert.Equal(t, []string{"template"}, change.Fields)
+}
@@ -0,0 +1,197 @@
+package k8s
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// envSecretChecksumAnnotation on the pod template rolls the workers when a secret env value
+// changes, since pods only read secretKeyRef values at start
+const envSecretChecksumAnnotation = "waverless.io/env-secret-checksum"
+
+// envSecretName returns the Secret holding an endpoint's secret env vars
+func envSecretName(endpoint string) string {
+	return endpoint + "-env"
+}
+
+// SyncSecretEnv updates the endpoint's env Secret and points the worker container at its keys.
+// A missing Deployment is not an error: the keys are injected when the endpoint is deployed.
+func (m *Manager) SyncSecretEnv(ctx context.Context, endpoint string, set map[string]string, unset []string) error {
+	data, err := m.applyEnvSecret(ctx, endpoint, set, unset)
+	if err != nil {
+		return fmt.Errorf("failed to apply env secret: %w", err)
+	}
+
+	deployments := m.client.AppsV1().Deployments(m.namespace)
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := deployments.Get(ctx, endpoint, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if len(deployment.Spec.Template.Spec.Containers) == 0 {
+			return nil
+		}
+		container := &deployment.Spec.Template.Spec.Containers[0]
+		container.Env = secretEnvVars(container.Env, envSecretName(endpoint), sortedKeys(data))
+
+		if len(data) == 0 {
+			delete(deployment.Spec.Template.Annotations, envSecretChecksumAnnotation)
+		} else {
+			if deployment.Spec.Template.Annotations == nil {
+				deployment.Spec.Template.Annotations = make(map[string]string)
+			}
+			deployment.Spec.Template.Annotations[envSecretChecksumAnnotation] = envSecretChecksum(data)
+		}
+
+		_, err = deployments.Update(ctx, deployment, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to update deployment env: %w", err)
+	}
+	return nil
+}
+
+// applyEnvSecret merges set and unset into the env Secret and returns its resulting data.
+// The Secret is deleted once it holds no keys.
+func (m *Manager) applyEnvSecret(ctx context.Context, endpoint string, set map[string]string, unset []string) (map[string][]byte, error) {
+	secrets := m.client.CoreV1().Secrets(m.namespace)
+	name := envSecretName(endpoint)
+
+	existing, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		existing = nil
+	case err != nil:
+		return nil, err
+	}
+	data := make(map[string][]byte)
+	if existing != nil {
+		for k, v := range existing.Data {
+			data[k] = v
+		}
+	}
+	for k, v := range set {
+		data[k] = []byte(v)
+	}
+	for _, k := range unset {
+		delete(data, k)
+	}
+
+	if len(data) == 0 {
+		if existing != nil {
+			if err := secrets.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		return data, nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.namespace,
+			Labels:    map[string]string{"app": endpoint, "managed-by": "waverless"},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	if existing == nil {
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+		return data, err
+	}
+	secret.ResourceVersion = existing.ResourceVersion
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return data, err
+}
+
+// envSecretKeys returns the keys of the endpoint's env Secret, nil when it has none
+func (m *Manager) envSecretKeys(ctx context.Context, endpoint string) ([]string, error) {
+	secret, err := m.client.CoreV1().Secrets(m.namespace).Get(ctx, envSecretName(endpoint), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return sortedKeys(secret.Data), nil
+}
+
+// addEnvSecretRefs makes a rendered Deployment read the keys of the endpoint's env Secret.
+// Plain env vars of the same names are dropped, as SyncSecretEnv does.
+func (m *Manager) addEnvSecretRefs(ctx context.Context, endpoint string, renderCtx *RenderContext) error {
+	keys, err := m.envSecretKeys(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to read env secret: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	renderCtx.EnvSecret = envSecretName(endpoint)
+	renderCtx.EnvSecretKeys = keys
+	for _, k := range keys {
+		delete(renderCtx.Env, k)
+	}
+	return nil
+}
+
+// secretEnvVars replaces the references to secretName in env with one per key. Plain vars
+// named like a key are dropped so the secret value is the one workers see.
+func secretEnvVars(env []corev1.EnvVar, secretName string, keys []string) []corev1.EnvVar {
+	isKey := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		isKey[k] = true
+	}
+
+	out := make([]corev1.EnvVar, 0, len(env)+len(keys))
+	for _, envVar := range env {
+		if envVar.ValueFrom != nil && envVar.ValueFrom.SecretKeyRef != nil && envVar.ValueFrom.SecretKeyRef.Name == secretName {
+			continue
+		}
+		if envVar.ValueFrom == nil && isKey[envVar.Name] {
+			continue
+		}
+		out = append(out, envVar)
+	}
+	for _, k := range keys {
+		out = append(out, corev1.EnvVar{
+			Name: k,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  k,
+				},
+			},
+		})
+	}
+	return out
+}
+
+// envSecretChecksum fingerprints secret data without revealing it
+func envSecretChecksum(data map[string][]byte) string {
+	h := sha256.New()
+	for _, k := range sortedKeys(data) {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(data[k])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func sortedKeys(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
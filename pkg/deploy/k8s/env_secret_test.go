@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func envTestDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "flux", Namespace: "wavespeed"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name: "worker",
+					Env: []corev1.EnvVar{
+						{Name: "WAVERLESS_ENDPOINT_ID", Value: "flux"},
+						{Name: "HF_TOKEN", Value: "plain"},
+						{Name: "MODEL", Value: "dev"},
+					},
+				}}},
+			},
+		},
+	}
+}
+
+func containerEnv(env []corev1.EnvVar) map[string]string {
+	out := make(map[string]string)
+	for _, e := range env {
+		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+			out[e.Name] = "secret:" + e.ValueFrom.SecretKeyRef.Name + "/" + e.ValueFrom.SecretKeyRef.Key
+			continue
+		}
+		out[e.Name] = e.Value
+	}
+	return out
+}
+
+func TestSyncSecretEnv(t *testing.T) {
+	ctx := context.Background()
+	m := &Manager{client: fake.NewSimpleClientset(envTestDeployment()), namespace: "wavespeed"}
+
+	// A plain var turned secret is replaced by a reference
+	require.NoError(t, m.SyncSecretEnv(ctx, "flux", map[string]string{"HF_TOKEN": "hf_123"}, nil))
+
+	secret, err := m.client.CoreV1().Secrets("wavespeed").Get(ctx, "flux-env", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "hf_123", string(secret.Data["HF_TOKEN"]))
+
+	deployment, err := m.client.AppsV1().Deployments("wavespeed").Get(ctx, "flux", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"WAVERLESS_ENDPOINT_ID": "flux",
+		"HF_TOKEN":              "secret:flux-env/HF_TOKEN",
+		"MODEL":                 "dev",
+	}, containerEnv(deployment.Spec.Template.Spec.Containers[0].Env))
+	checksum := deployment.Spec.Template.Annotations[envSecretChecksumAnnotation]
+	assert.NotEmpty(t, checksum)
+	assert.NotContains(t, checksum, "hf_123")
+
+	// Changing a value rolls the pods
+	require.NoError(t, m.SyncSecretEnv(ctx, "flux", map[string]string{"HF_TOKEN": "hf_456"}, nil))
+	deployment, err = m.client.AppsV1().Deployments("wavespeed").Get(ctx, "flux", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, checksum, deployment.Spec.Template.Annotations[envSecretChecksumAnnotation])
+	assert.Len(t, deployment.Spec.Template.Spec.Containers[0].Env, 3)
+
+	// Unsetting the last key removes the reference and the Secret
+	require.NoError(t, m.SyncSecretEnv(ctx, "flux", nil, []string{"HF_TOKEN"}))
+	deployment, err = m.client.AppsV1().Deployments("wavespeed").Get(ctx, "flux", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"WAVERLESS_ENDPOINT_ID": "flux", "MODEL": "dev"},
+		containerEnv(deployment.Spec.Template.Spec.Containers[0].Env))
+	assert.NotContains(t, deployment.Spec.Template.Annotations, envSecretChecksumAnnotation)
+	_, err = m.client.CoreV1().Secrets("wavespeed").Get(ctx, "flux-env", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestSyncSecretEnv_NotDeployed(t *testing.T) {
+	ctx := context.Background()
+	m := &Manager{client: fake.NewSimpleClientset(), namespace: "wavespeed"}
+
+	require.NoError(t, m.SyncSecretEnv(ctx, "flux", map[string]string{"B": "2", "A": "1"}, nil))
+	keys, err := m.envSecretKeys(ctx, "flux")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A", "B"}, keys)
+}
@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// parseEphemeralStorage parses an ephemeral storage size. Plain numbers are GB, matching
+// the ephemeralStorage of specs (e.g. "30" is 30Gi); other values are K8s quantities.
+func parseEphemeralStorage(value string) (resource.Quantity, error) {
+	value = strings.TrimSpace(value)
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		value += "Gi"
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid ephemeral storage %q: %w", value, err)
+	}
+	if quantity.Sign() <= 0 {
+		return resource.Quantity{}, fmt.Errorf("invalid ephemeral storage %q: must be positive", value)
+	}
+	return quantity, nil
+}
+
+// resolveEphemeralStorage returns the ephemeral storage of an endpoint as a K8s quantity
+// Priority: request > spec; empty when neither sets it
+func resolveEphemeralStorage(override string, spec *ResourceSpec) (string, error) {
+	value := override
+	if value == "" && spec != nil {
+		value = spec.Resources.EphemeralStorage
+	}
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	quantity, err := parseEphemeralStorage(value)
+	if err != nil {
+		return "", err
+	}
+	return quantity.String(), nil
+}
+
+// applyEphemeralStorage sets the ephemeral-storage request and limit of a container, or
+// removes them when value is empty
+func applyEphemeralStorage(container *corev1.Container, value string) error {
+	if value == "" {
+		delete(container.Resources.Requests, corev1.ResourceEphemeralStorage)
+		delete(container.Resources.Limits, corev1.ResourceEphemeralStorage)
+		return nil
+	}
+	quantity, err := parseEphemeralStorage(value)
+	if err != nil {
+		return err
+	}
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	container.Resources.Requests[corev1.ResourceEphemeralStorage] = quantity
+	container.Resources.Limits[corev1.ResourceEphemeralStorage] = quantity
+	return nil
+}
+
+// BumpEphemeralStorage scales an ephemeral storage size by factor, rounded up to whole Gi
+// and capped at max (no cap when empty). ok is false when the size cannot grow any further.
+func BumpEphemeralStorage(current string, factor float64, max string) (string, bool, error) {
+	quantity, err := parseEphemeralStorage(current)
+	if err != nil {
+		return "", false, err
+	}
+	if factor <= 1 {
+		return "", false, fmt.Errorf("bump factor must be greater than 1, got %v", factor)
+	}
+
+	const gi = 1 << 30
+	bumped := int64(math.Ceil(float64(quantity.Value())*factor/gi)) * gi
+	if max != "" {
+		maxQuantity, err := parseEphemeralStorage(max)
+		if err != nil {
+			return "", false, err
+		}
+		if bumped > maxQuantity.Value() {
+			bumped = maxQuantity.Value()
+		}
+	}
+	if bumped <= quantity.Value() {
+		return "", false, nil
+	}
+	return resource.NewQuantity(bumped, resource.BinarySI).String(), true, nil
+}
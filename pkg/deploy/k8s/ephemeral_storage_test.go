@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+func TestResolveEphemeralStorage(t *testing.T) {
+	spec := &ResourceSpec{Resources: SpecResources{EphemeralStorage: "30"}}
+
+	value, err := resolveEphemeralStorage("", spec)
+	require.NoError(t, err)
+	assert.Equal(t, "30Gi", value)
+
+	value, err = resolveEphemeralStorage("100Gi", spec)
+	require.NoError(t, err)
+	assert.Equal(t, "100Gi", value)
+
+	value, err = resolveEphemeralStorage("", &ResourceSpec{})
+	require.NoError(t, err)
+	assert.Empty(t, value)
+
+	_, err = resolveEphemeralStorage("lots", spec)
+	assert.Error(t, err)
+	_, err = resolveEphemeralStorage("-5", spec)
+	assert.Error(t, err)
+}
+
+func TestApplyEphemeralStorage(t *testing.T) {
+	container := &corev1.Container{}
+	require.NoError(t, applyEphemeralStorage(container, "50"))
+	assert.Equal(t, resource.MustParse("50Gi"), container.Resources.Requests[corev1.ResourceEphemeralStorage])
+	assert.Equal(t, resource.MustParse("50Gi"), container.Resources.Limits[corev1.ResourceEphemeralStorage])
+
+	require.NoError(t, applyEphemeralStorage(container, ""))
+	assert.NotContains(t, container.Resources.Limits, corev1.ResourceEphemeralStorage)
+	assert.NotContains(t, container.Resources.Requests, corev1.ResourceEphemeralStorage)
+}
+
+func TestBumpEphemeralStorage(t *testing.T) {
+	bumped, ok, err := BumpEphemeralStorage("30Gi", 1.5, "200Gi")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "45Gi", bumped)
+
+	// Rounded up to whole Gi
+	bumped, _, err = BumpEphemeralStorage("25Gi", 1.5, "")
+	require.NoError(t, err)
+	assert.Equal(t, "38Gi", bumped)
+
+	// Capped at the maximum, then no further growth
+	bumped, ok, err = BumpEphemeralStorage("180Gi", 1.5, "200Gi")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "200Gi", bumped)
+
+	_, ok, err = BumpEphemeralStorage("200Gi", 1.5, "200Gi")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = BumpEphemeralStorage("30Gi", 1, "")
+	assert.Error(t, err)
+}
+
+func TestDeploymentTemplate_RendersEphemeralStorage(t *testing.T) {
+	renderer := NewTemplateRenderer("../../../config/templates")
+	content, err := renderer.Render("deployment.yaml", &RenderContext{
+		Endpoint:         "flux",
+		Namespace:        "wavespeed",
+		Image:            "flux:latest",
+		Replicas:         1,
+		ContainerName:    "flux-worker",
+		ContainerPort:    8000,
+		MemoryRequest:    "8Gi",
+		EphemeralStorage: "30Gi",
+	})
+	require.NoError(t, err)
+
+	var deployment appsv1.Deployment
+	require.NoError(t, yaml.Unmarshal([]byte(content), &deployment))
+	resources := deployment.Spec.Template.Spec.Containers[0].Resources
+	assert.Equal(t, resource.MustParse("30Gi"), resources.Requests[corev1.ResourceEphemeralStorage])
+	assert.Equal(t, resource.MustParse("30Gi"), resources.Limits[corev1.ResourceEphemeralStorage])
+}
@@ -0,0 +1,32 @@
+package k8s
+
+import (
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"waverless/pkg/interfaces"
+)
+
+// providerError classifies Kubernetes API errors into provider error kinds.
+// Errors that are already classified, or not API errors, are returned unchanged.
+func providerError(err error) error {
+	if err == nil || interfaces.ProviderErrorKind(err) != nil {
+		return err
+	}
+
+	switch {
+	case apierrors.IsNotFound(err):
+		return interfaces.NewProviderError(interfaces.ErrNotFound, err)
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return interfaces.NewProviderError(interfaces.ErrConflict, err)
+	case apierrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota"):
+		// ResourceQuota admission rejects with 403 Forbidden
+		return interfaces.NewProviderError(interfaces.ErrQuotaExceeded, err)
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return interfaces.NewProviderError(interfaces.ErrUnauthorized, err)
+	case apierrors.IsTooManyRequests(err):
+		return interfaces.NewProviderError(interfaces.ErrThrottled, err)
+	}
+	return err
+}
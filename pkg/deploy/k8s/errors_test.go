@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"waverless/pkg/interfaces"
+)
+
+func TestProviderError_ClassifiesAPIErrors(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"not found", apierrors.NewNotFound(deployments, "flux"), interfaces.ErrNotFound},
+		{"conflict", apierrors.NewConflict(deployments, "flux", errors.New("object modified")), interfaces.ErrConflict},
+		{"already exists", apierrors.NewAlreadyExists(deployments, "flux"), interfaces.ErrConflict},
+		{"quota", apierrors.NewForbidden(deployments, "flux", errors.New("exceeded quota: gpu, requested: nvidia.com/gpu=8")), interfaces.ErrQuotaExceeded},
+		{"forbidden", apierrors.NewForbidden(deployments, "flux", errors.New("rbac")), interfaces.ErrUnauthorized},
+		{"throttled", apierrors.NewTooManyRequests("slow down", 1), interfaces.ErrThrottled},
+		{"privilege denied", &PrivilegeDeniedError{Endpoint: "flux", Privilege: "ptrace"}, interfaces.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Manager errors wrap API errors with context
+			err := providerError(fmt.Errorf("failed to get deployment: %w", tt.err))
+			assert.Equal(t, tt.kind, interfaces.ProviderErrorKind(err))
+			assert.ErrorIs(t, err, tt.err)
+			assert.Contains(t, err.Error(), "failed to get deployment")
+		})
+	}
+
+	assert.Nil(t, providerError(nil))
+	plain := errors.New("template render failed")
+	assert.Equal(t, plain, providerError(plain))
+}
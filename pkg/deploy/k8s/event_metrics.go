@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/metrics"
+)
+
+// Self-metrics on how far behind the cluster waverless is: events are stamped when the
+// informer delivers them, and the stamp follows the key through the queue to every
+// callback the reconcile triggers.
+var (
+	eventQueueLatency = metrics.Default.NewHistogramVec(
+		"waverless_k8s_event_queue_latency_seconds",
+		"Time from a K8s event to the start of its reconcile.",
+		metrics.LatencyBuckets, "kind")
+	callbackLatency = metrics.Default.NewHistogramVec(
+		"waverless_k8s_event_callback_latency_seconds",
+		"Time from a K8s event to the completion of a callback it triggered.",
+		metrics.LatencyBuckets, "callback")
+	callbackErrors = metrics.Default.NewCounterVec(
+		"waverless_k8s_callback_errors_total",
+		"Callbacks that panicked.",
+		"callback")
+	reconcileErrors = metrics.Default.NewCounterVec(
+		"waverless_k8s_reconcile_errors_total",
+		"Failed reconciles of K8s events, by whether the key was retried or dropped.",
+		"kind", "result")
+	informerResyncDuration = metrics.Default.NewHistogramVec(
+		"waverless_k8s_informer_resync_duration_seconds",
+		"Time from the first resync event of an informer to the reconcile of the last object it covered.",
+		metrics.LatencyBuckets, "kind")
+)
+
+// Callback names used as metric labels
+const (
+	callbackReplica          = "replica"
+	callbackDeploymentStatus = "deployment_status"
+	callbackDeploymentSpec   = "deployment_spec"
+	callbackPodDelete        = "pod_delete"
+	callbackPodTerminating   = "pod_terminating"
+	callbackPodStatus        = "pod_status"
+	callbackSpotInterruption = "spot_interruption"
+	callbackNodeMaintenance  = "node_maintenance"
+)
+
+// eventTimes tracks when the events behind the keys of one queue happened
+type eventTimes struct {
+	mu         sync.Mutex
+	queued     map[string]time.Time // Oldest event not picked up by a worker yet
+	processing map[string]time.Time // Event of the key being reconciled
+
+	// Keys of the current resync round, reconciled or not
+	resyncKeys  map[string]struct{}
+	resyncStart time.Time
+}
+
+func newEventTimes() *eventTimes {
+	return &eventTimes{
+		queued:     make(map[string]time.Time),
+		processing: make(map[string]time.Time),
+		resyncKeys: make(map[string]struct{}),
+	}
+}
+
+// mark stamps an event for key. Events collapsed into one reconcile keep the oldest stamp.
+func (t *eventTimes) mark(key string, now time.Time, resync bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.queued[key]; !ok {
+		t.queued[key] = now
+	}
+	if resync {
+		if len(t.resyncKeys) == 0 {
+			t.resyncStart = now
+		}
+		t.resyncKeys[key] = struct{}{}
+	}
+}
+
+// start moves the stamp of key to processing and returns it
+func (t *eventTimes) start(key string, now time.Time) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	eventAt, ok := t.queued[key]
+	if !ok {
+		eventAt = now
+	}
+	delete(t.queued, key)
+	t.processing[key] = eventAt
+	return eventAt
+}
+
+// current returns the stamp of the key being reconciled, now when it has none
+func (t *eventTimes) current(key string) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if eventAt, ok := t.processing[key]; ok {
+		return eventAt
+	}
+	return time.Now()
+}
+
+// finish ends the reconcile of key. A retried key keeps its stamp; otherwise the key leaves
+// the resync round, and the round's duration is returned when it was the last one.
+func (t *eventTimes) finish(key string, retry bool, now time.Time) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	eventAt := t.processing[key]
+	delete(t.processing, key)
+	if retry {
+		if queuedAt, ok := t.queued[key]; !ok || eventAt.Before(queuedAt) {
+			t.queued[key] = eventAt
+		}
+		return 0, false
+	}
+	if _, ok := t.resyncKeys[key]; !ok {
+		return 0, false
+	}
+	delete(t.resyncKeys, key)
+	if len(t.resyncKeys) > 0 {
+		return 0, false
+	}
+	return now.Sub(t.resyncStart), true
+}
+
+// runCallback runs a callback asynchronously so it can't block the queue workers, recovering
+// panics and recording the time from the event to its completion
+func runCallback(callback string, eventAt time.Time, fn func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				callbackErrors.Inc(callback)
+				logger.ErrorCtx(context.Background(), "%s callback panicked: %v", callback, r)
+			}
+			callbackLatency.Observe(time.Since(eventAt).Seconds(), callback)
+		}()
+		fn()
+	}()
+}
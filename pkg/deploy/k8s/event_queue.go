@@ -0,0 +1,333 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// Informer event handlers only enqueue namespace/name keys. Workers reconcile each key
+// against the informer cache and the object they last delivered, so a burst of events
+// for one object (e.g. every pod being updated during a node upgrade) collapses into a
+// single reconcile, and a failed reconcile is retried with backoff instead of lost.
+const (
+	deploymentQueueWorkers = 2
+	podQueueWorkers        = 4
+	maxReconcileRetries    = 5
+)
+
+// eventQueues holds the work queues, the last object delivered per key and when the
+// events behind the queued keys happened
+type eventQueues struct {
+	deployments workqueue.TypedRateLimitingInterface[string]
+	pods        workqueue.TypedRateLimitingInterface[string]
+
+	deploymentTimes *eventTimes
+	podTimes        *eventTimes
+
+	mu              sync.Mutex
+	lastDeployments map[string]*appsv1.Deployment
+	lastPods        map[string]*corev1.Pod // Managed worker pods only
+}
+
+func newEventQueues() *eventQueues {
+	return &eventQueues{
+		deployments: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "waverless-deployments"},
+		),
+		pods: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "waverless-pods"},
+		),
+		deploymentTimes: newEventTimes(),
+		podTimes:        newEventTimes(),
+		lastDeployments: make(map[string]*appsv1.Deployment),
+		lastPods:        make(map[string]*corev1.Pod),
+	}
+}
+
+// shutDown stops the queues; workers exit once their current item is done
+func (q *eventQueues) shutDown() {
+	q.deployments.ShutDown()
+	q.pods.ShutDown()
+}
+
+// deploymentEventHandler enqueues deployment events
+func (m *Manager) deploymentEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { m.enqueueDeployment(obj, false, false) },
+		UpdateFunc: func(oldObj, newObj interface{}) { m.enqueueDeployment(newObj, false, isResync(oldObj, newObj)) },
+		DeleteFunc: func(obj interface{}) { m.enqueueDeployment(obj, true, false) },
+	}
+}
+
+// podEventHandler enqueues pod events
+func (m *Manager) podEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { m.enqueuePod(obj, false, false) },
+		UpdateFunc: func(oldObj, newObj interface{}) { m.enqueuePod(newObj, false, isResync(oldObj, newObj)) },
+		DeleteFunc: func(obj interface{}) { m.enqueuePod(obj, true, false) },
+	}
+}
+
+// isResync reports whether an update is a periodic resync: the informer re-delivers the
+// cached object, so its resource version is unchanged
+func isResync(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+	return oldMeta.GetResourceVersion() == newMeta.GetResourceVersion()
+}
+
+func (m *Manager) enqueueDeployment(obj interface{}, deleted, resync bool) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		logger.WarnCtx(context.Background(), "failed to get deployment key: %v", err)
+		return
+	}
+	if deleted {
+		// Keep the final state so the delete is delivered even if no earlier event was processed
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		if dep, ok := obj.(*appsv1.Deployment); ok && dep != nil {
+			m.queues.mu.Lock()
+			if _, seen := m.queues.lastDeployments[key]; !seen {
+				m.queues.lastDeployments[key] = dep
+			}
+			m.queues.mu.Unlock()
+		}
+	}
+	m.queues.deploymentTimes.mark(key, time.Now(), resync)
+	m.queues.deployments.Add(key)
+}
+
+func (m *Manager) enqueuePod(obj interface{}, deleted, resync bool) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		logger.WarnCtx(context.Background(), "failed to get pod key: %v", err)
+		return
+	}
+	if deleted {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || !m.isManagedWorkerPod(pod) {
+			return
+		}
+		m.queues.mu.Lock()
+		if _, seen := m.queues.lastPods[key]; !seen {
+			m.queues.lastPods[key] = pod
+		}
+		m.queues.mu.Unlock()
+	}
+	m.queues.podTimes.mark(key, time.Now(), resync)
+	m.queues.pods.Add(key)
+}
+
+// startEventWorkers runs the queue workers until the queues are shut down
+func (m *Manager) startEventWorkers() {
+	for i := 0; i < deploymentQueueWorkers; i++ {
+		go m.runQueueWorker("deployment", m.queues.deployments, m.queues.deploymentTimes, m.reconcileDeployment)
+	}
+	for i := 0; i < podQueueWorkers; i++ {
+		go m.runQueueWorker("pod", m.queues.pods, m.queues.podTimes, m.reconcilePod)
+	}
+}
+
+func (m *Manager) runQueueWorker(kind string, queue workqueue.TypedRateLimitingInterface[string], times *eventTimes, reconcile func(key string) error) {
+	for m.processNextItem(kind, queue, times, reconcile) {
+	}
+}
+
+// processNextItem reconciles one key; the queue guarantees a key is never processed
+// by two workers at once
+func (m *Manager) processNextItem(kind string, queue workqueue.TypedRateLimitingInterface[string], times *eventTimes, reconcile func(key string) error) bool {
+	key, shutdown := queue.Get()
+	if shutdown {
+		return false
+	}
+	defer queue.Done(key)
+
+	now := time.Now()
+	eventQueueLatency.Observe(now.Sub(times.start(key, now)).Seconds(), kind)
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("reconcile panicked: %v", r)
+			}
+		}()
+		return reconcile(key)
+	}()
+
+	retry := false
+	switch {
+	case err == nil:
+		queue.Forget(key)
+	case queue.NumRequeues(key) < maxReconcileRetries:
+		logger.WarnCtx(context.Background(), "failed to reconcile %s %s, retrying: %v", kind, key, err)
+		reconcileErrors.Inc(kind, "retry")
+		retry = true
+	default:
+		logger.ErrorCtx(context.Background(), "dropping %s %s after %d retries: %v", kind, key, maxReconcileRetries, err)
+		reconcileErrors.Inc(kind, "dropped")
+		queue.Forget(key)
+	}
+	// Before the requeue, so that a worker picking the key up right away finds its stamp
+	if resyncDuration, done := times.finish(key, retry, time.Now()); done {
+		informerResyncDuration.Observe(resyncDuration.Seconds(), kind)
+	}
+	if retry {
+		queue.AddRateLimited(key)
+	}
+	return true
+}
+
+// reconcileDeployment delivers replica events, status and spec changes for one deployment
+func (m *Manager) reconcileDeployment(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil // Malformed keys never succeed
+	}
+	eventAt := m.queues.deploymentTimes.current(key)
+
+	deployment, err := m.deploymentLister.Deployments(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		m.queues.mu.Lock()
+		last := m.queues.lastDeployments[key]
+		delete(m.queues.lastDeployments, key)
+		m.queues.mu.Unlock()
+		if last != nil {
+			m.emitReplicaChange(interfaces.ReplicaEvent{
+				Name:       last.Name,
+				Conditions: deletedCondition("Deleted"),
+			}, eventAt)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	m.queues.mu.Lock()
+	last := m.queues.lastDeployments[key]
+	m.queues.lastDeployments[key] = deployment
+	m.queues.mu.Unlock()
+
+	m.emitReplicaChange(buildReplicaEvent(deployment), eventAt)
+
+	endpoint := deployment.Labels["app"]
+	if endpoint == "" || deployment.Labels["managed-by"] != "waverless" || endpoint == "waverless" {
+		return nil
+	}
+
+	if last == nil || deploymentStatusChanged(last, deployment) {
+		m.syncDeploymentStatus(deployment, eventAt)
+	}
+
+	// Detect spec changes that trigger pod recreation (image, resources, env, etc.)
+	if last != nil && m.hasSpecChanged(last, deployment) {
+		logger.InfoCtx(context.Background(), "🔄 Deployment %s (endpoint: %s) spec changed, triggering optimized rolling update",
+			deployment.Name, endpoint)
+		m.notifyDeploymentSpecChange(endpoint, eventAt)
+	}
+	return nil
+}
+
+// reconcilePod delivers spot interruption, terminating, node maintenance, status and delete
+// notifications for one worker pod
+func (m *Manager) reconcilePod(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+
+	eventAt := m.queues.podTimes.current(key)
+
+	pod, err := m.podLister.Pods(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		m.queues.mu.Lock()
+		last := m.queues.lastPods[key]
+		delete(m.queues.lastPods, key)
+		m.queues.mu.Unlock()
+		if last != nil {
+			endpoint := GetPodEndpoint(last)
+			logger.InfoCtx(context.Background(), "🗑️ Pod %s (endpoint: %s) deleted", last.Name, endpoint)
+			m.notifyPodDelete(last.Name, endpoint, eventAt)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only handle pods managed by waverless (worker pods)
+	endpoint := GetPodEndpoint(pod)
+	if endpoint == "" || !m.isManagedWorkerPod(pod) {
+		return nil
+	}
+
+	m.queues.mu.Lock()
+	last := m.queues.lastPods[key]
+	m.queues.lastPods[key] = pod
+	m.queues.mu.Unlock()
+
+	// 1. Check for Spot interruption FIRST (before pod termination)
+	if detected, reason := m.detectSpotInterruption(pod); detected {
+		logger.WarnCtx(context.Background(), "🚨 Spot interruption detected for pod %s (endpoint: %s): %s",
+			pod.Name, endpoint, reason)
+		m.notifySpotInterruption(pod.Name, endpoint, reason, eventAt)
+	}
+
+	// 2. Detect when a pod is marked for deletion
+	if pod.DeletionTimestamp != nil && (last == nil || last.DeletionTimestamp == nil) {
+		logger.InfoCtx(context.Background(), "🔔 Pod %s (endpoint: %s) marked for deletion, notifying callbacks",
+			pod.Name, endpoint)
+		m.notifyPodTerminating(pod.Name, endpoint, eventAt)
+	}
+
+	// 2b. Detect node maintenance (drain eviction, NoExecute taint, node shutdown). The
+	// DisruptionTarget condition may land in the same or a later update than the deletion.
+	if nm := detectNodeMaintenance(pod); nm != nil && detectNodeMaintenance(last) == nil {
+		logger.WarnCtx(context.Background(), "🔧 Pod %s (endpoint: %s) removed by node maintenance: %s",
+			pod.Name, endpoint, nm.Reason())
+		m.notifyNodeMaintenance(pod.Name, endpoint, nm, eventAt)
+	}
+
+	// 3. Notify pod status change (for worker runtime state sync)
+	m.notifyPodStatusChange(pod.Name, endpoint, m.podToPodInfo(pod), eventAt)
+	return nil
+}
+
+// deploymentStatusChanged reports whether ready/available/desired replicas differ
+func deploymentStatusChanged(oldDep, newDep *appsv1.Deployment) bool {
+	return oldDep.Status.ReadyReplicas != newDep.Status.ReadyReplicas ||
+		oldDep.Status.AvailableReplicas != newDep.Status.AvailableReplicas ||
+		desiredReplicas(oldDep) != desiredReplicas(newDep)
+}
+
+func desiredReplicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
@@ -0,0 +1,194 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"waverless/pkg/interfaces"
+)
+
+func newQueueTestManager() (*Manager, cache.Indexer, cache.Indexer) {
+	deployments := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	pods := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	m := &Manager{
+		namespace:                       "wavespeed",
+		deploymentLister:                appslisters.NewDeploymentLister(deployments),
+		podLister:                       corelisters.NewPodLister(pods),
+		queues:                          newEventQueues(),
+		podTerminatingCallbacks:         make(map[int64]PodTerminatingCallback),
+		podDeleteCallbacks:              make(map[int64]PodDeleteCallback),
+		podStatusChangeCallbacks:        make(map[int64]PodStatusChangeCallback),
+		deploymentSpecChangeCallbacks:   make(map[int64]DeploymentSpecChangeCallback),
+		deploymentStatusChangeCallbacks: make(map[int64]DeploymentStatusChangeCallback),
+	}
+	return m, deployments, pods
+}
+
+func workerPod(name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: name, Namespace: "wavespeed",
+		Labels: map[string]string{"app": "flux", "managed-by": "waverless"},
+	}}
+}
+
+func receive(t *testing.T, ch <-chan string) string {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+		return ""
+	}
+}
+
+func assertNone(t *testing.T, ch <-chan string) {
+	t.Helper()
+	select {
+	case v := <-ch:
+		t.Fatalf("unexpected callback: %s", v)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestReconcilePod(t *testing.T) {
+	m, _, pods := newQueueTestManager()
+	status := make(chan string, 10)
+	terminating := make(chan string, 10)
+	deleted := make(chan string, 10)
+	m.podStatusChangeCallbacks[1] = func(podName, _ string, _ *interfaces.PodInfo) { status <- podName }
+	m.podTerminatingCallbacks[1] = func(podName, _ string) { terminating <- podName }
+	m.podDeleteCallbacks[1] = func(podName, _ string) { deleted <- podName }
+
+	pod := workerPod("flux-abc")
+	require.NoError(t, pods.Add(pod))
+	require.NoError(t, m.reconcilePod("wavespeed/flux-abc"))
+	assert.Equal(t, "flux-abc", receive(t, status))
+	assertNone(t, terminating)
+
+	// Marked for deletion: terminating is delivered once
+	marked := pod.DeepCopy()
+	now := metav1.Now()
+	marked.DeletionTimestamp = &now
+	require.NoError(t, pods.Update(marked))
+	require.NoError(t, m.reconcilePod("wavespeed/flux-abc"))
+	assert.Equal(t, "flux-abc", receive(t, terminating))
+	receive(t, status)
+	require.NoError(t, m.reconcilePod("wavespeed/flux-abc"))
+	assertNone(t, terminating)
+	receive(t, status)
+
+	// Gone from the cache
+	require.NoError(t, pods.Delete(marked))
+	require.NoError(t, m.reconcilePod("wavespeed/flux-abc"))
+	assert.Equal(t, "flux-abc", receive(t, deleted))
+
+	// Unmanaged pods are ignored
+	require.NoError(t, pods.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "wavespeed"}}))
+	require.NoError(t, m.reconcilePod("wavespeed/other"))
+	assertNone(t, status)
+}
+
+func TestEnqueuePod_DeleteBeforeFirstReconcile(t *testing.T) {
+	m, _, _ := newQueueTestManager()
+	deleted := make(chan string, 1)
+	m.podDeleteCallbacks[1] = func(podName, _ string) { deleted <- podName }
+
+	// Created and deleted before a worker got to it: the final state is kept for the delete
+	m.enqueuePod(cache.DeletedFinalStateUnknown{Key: "wavespeed/flux-xyz", Obj: workerPod("flux-xyz")}, true, false)
+	assert.Equal(t, 1, m.queues.pods.Len())
+	require.NoError(t, m.reconcilePod("wavespeed/flux-xyz"))
+	assert.Equal(t, "flux-xyz", receive(t, deleted))
+}
+
+func TestReconcileDeployment(t *testing.T) {
+	m, deployments, _ := newQueueTestManager()
+	statusChanges := make(chan string, 10)
+	specChanges := make(chan string, 10)
+	m.deploymentStatusChangeCallbacks[1] = func(endpoint string, _ *appsv1.Deployment) { statusChanges <- endpoint }
+	m.deploymentSpecChangeCallbacks[1] = func(endpoint string) { specChanges <- endpoint }
+
+	replicas := int32(2)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "flux", Namespace: "wavespeed", Labels: map[string]string{"app": "flux", "managed-by": "waverless"}},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "flux-worker", Image: "flux:1"}}}},
+		},
+	}
+	require.NoError(t, deployments.Add(dep))
+	require.NoError(t, m.reconcileDeployment("wavespeed/flux"))
+	assert.Equal(t, "flux", receive(t, statusChanges))
+
+	// Resync without changes delivers nothing
+	require.NoError(t, m.reconcileDeployment("wavespeed/flux"))
+	assertNone(t, statusChanges)
+
+	updated := dep.DeepCopy()
+	updated.Status.ReadyReplicas = 1
+	updated.Spec.Template.Spec.Containers[0].Image = "flux:2"
+	require.NoError(t, deployments.Update(updated))
+	require.NoError(t, m.reconcileDeployment("wavespeed/flux"))
+	assert.Equal(t, "flux", receive(t, statusChanges))
+	assert.Equal(t, "flux", receive(t, specChanges))
+}
+
+func TestEventTimes(t *testing.T) {
+	times := newEventTimes()
+	t0 := time.Now()
+
+	// Collapsed events keep the oldest stamp
+	times.mark("wavespeed/flux-abc", t0, false)
+	times.mark("wavespeed/flux-abc", t0.Add(time.Second), false)
+	assert.Equal(t, t0, times.start("wavespeed/flux-abc", t0.Add(2*time.Second)))
+	assert.Equal(t, t0, times.current("wavespeed/flux-abc"))
+
+	// A retried key keeps its stamp
+	_, done := times.finish("wavespeed/flux-abc", true, t0.Add(3*time.Second))
+	assert.False(t, done)
+	assert.Equal(t, t0, times.start("wavespeed/flux-abc", t0.Add(4*time.Second)))
+	_, done = times.finish("wavespeed/flux-abc", false, t0.Add(5*time.Second))
+	assert.False(t, done)
+
+	// A resync round ends with the reconcile of its last key
+	times.mark("wavespeed/a", t0, true)
+	times.mark("wavespeed/b", t0.Add(time.Second), true)
+	times.start("wavespeed/a", t0.Add(2*time.Second))
+	_, done = times.finish("wavespeed/a", false, t0.Add(3*time.Second))
+	assert.False(t, done)
+	times.start("wavespeed/b", t0.Add(4*time.Second))
+	duration, done := times.finish("wavespeed/b", false, t0.Add(6*time.Second))
+	assert.True(t, done)
+	assert.Equal(t, 6*time.Second, duration)
+}
+
+func TestProcessNextItem_RecordsLatency(t *testing.T) {
+	m, _, pods := newQueueTestManager()
+	status := make(chan string, 1)
+	m.podStatusChangeCallbacks[1] = func(podName, _ string, _ *interfaces.PodInfo) { status <- podName }
+
+	queued := eventQueueLatency.Count("pod")
+	callbacks := callbackLatency.Count(callbackPodStatus)
+	require.NoError(t, pods.Add(workerPod("flux-abc")))
+	m.enqueuePod(workerPod("flux-abc"), false, false)
+	assert.True(t, m.processNextItem("pod", m.queues.pods, m.queues.podTimes, m.reconcilePod))
+	assert.Equal(t, "flux-abc", receive(t, status))
+	assert.Equal(t, queued+1, eventQueueLatency.Count("pod"))
+	assert.Eventually(t, func() bool { return callbackLatency.Count(callbackPodStatus) == callbacks+1 }, time.Second, 10*time.Millisecond)
+
+	// Panicking callbacks are counted
+	panics := callbackErrors.Value(callbackPodStatus)
+	m.podStatusChangeCallbacks[1] = func(string, string, *interfaces.PodInfo) { panic("boom") }
+	m.enqueuePod(workerPod("flux-abc"), false, false)
+	assert.True(t, m.processNextItem("pod", m.queues.pods, m.queues.podTimes, m.reconcilePod))
+	assert.Eventually(t, func() bool { return callbackErrors.Value(callbackPodStatus) == panics+1 }, time.Second, 10*time.Millisecond)
+}
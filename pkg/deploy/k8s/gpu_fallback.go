@@ -0,0 +1,263 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"waverless/pkg/logger"
+)
+
+// gpuTypesAnnotation records the GPU type chain of a deployment, so that the GPU type each
+// pod actually landed on can be resolved from the labels of its node
+const gpuTypesAnnotation = "waverless.io/gpu-types"
+
+// nodeLabelsTTL bounds how long node labels are cached for GPU type resolution
+const nodeLabelsTTL = 10 * time.Minute
+
+// gpuTypeOption is one GPU type of a chain with the node selector of its nodes
+type gpuTypeOption struct {
+	GPUType      string            `json:"gpuType"`
+	NodeSelector map[string]string `json:"nodeSelector"`
+}
+
+// gpuTypeChain returns the GPU types a spec may run on in preference order: its gpuType with
+// the platform node selector, then each fallback with the platform node selector overlaid by
+// gpuTypeNodeSelectors. Nil when the spec has no fallbacks.
+func gpuTypeChain(spec *ResourceSpec, platform PlatformConfig) ([]gpuTypeOption, error) {
+	if len(spec.Resources.GpuTypeFallbacks) == 0 {
+		return nil, nil
+	}
+	if spec.Resources.GpuType == "" {
+		return nil, fmt.Errorf("gpuTypeFallbacks requires gpuType")
+	}
+
+	chain := []gpuTypeOption{{GPUType: spec.Resources.GpuType, NodeSelector: platform.NodeSelector}}
+	for _, gpuType := range spec.Resources.GpuTypeFallbacks {
+		overlay, ok := platform.GPUTypeNodeSelectors[gpuType]
+		if !ok || len(overlay) == 0 {
+			return nil, fmt.Errorf("fallback GPU type %s has no node selector in gpuTypeNodeSelectors", gpuType)
+		}
+		selector := make(map[string]string, len(platform.NodeSelector)+len(overlay))
+		for k, v := range platform.NodeSelector {
+			selector[k] = v
+		}
+		for k, v := range overlay {
+			selector[k] = v
+		}
+		chain = append(chain, gpuTypeOption{GPUType: gpuType, NodeSelector: selector})
+	}
+
+	for i := range chain {
+		for j := 0; j < i; j++ {
+			if chain[i].GPUType == chain[j].GPUType {
+				return nil, fmt.Errorf("GPU type %s is listed twice", chain[i].GPUType)
+			}
+			if selectorsEqual(chain[i].NodeSelector, chain[j].NodeSelector) {
+				return nil, fmt.Errorf("GPU types %s and %s have the same node selector", chain[j].GPUType, chain[i].GPUType)
+			}
+		}
+	}
+	return chain, nil
+}
+
+// buildGPUTypeScheduling splits the node selectors of a GPU type chain into the keys shared by
+// every GPU type, kept as the pod's nodeSelector, and a node affinity on the other keys that
+// requires one of the GPU types and prefers them in chain order
+func buildGPUTypeScheduling(chain []gpuTypeOption) (map[string]string, *corev1.NodeAffinity) {
+	common := make(map[string]string)
+	for k, v := range chain[0].NodeSelector {
+		shared := true
+		for _, option := range chain[1:] {
+			if value, ok := option.NodeSelector[k]; !ok || value != v {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			common[k] = v
+		}
+	}
+
+	affinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{},
+	}
+	for i, option := range chain {
+		term := corev1.NodeSelectorTerm{}
+		keys := make([]string, 0, len(option.NodeSelector))
+		for k := range option.NodeSelector {
+			if _, ok := common[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			term.MatchExpressions = append(term.MatchExpressions, corev1.NodeSelectorRequirement{
+				Key:      k,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{option.NodeSelector[k]},
+			})
+		}
+		affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = append(
+			affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, term)
+		// Weights 100 down to 1 in chain order
+		weight := int32(100 - i*99/len(chain))
+		affinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.PreferredSchedulingTerm{Weight: weight, Preference: term})
+	}
+	return common, affinity
+}
+
+// applyGPUTypeScheduling replaces the nodeSelector and node affinity of a pod spec with those of
+// a GPU type chain, or with the plain nodeSelector when there is no chain
+func applyGPUTypeScheduling(podSpec *corev1.PodSpec, nodeSelector map[string]string, chain []gpuTypeOption) {
+	var nodeAffinity *corev1.NodeAffinity
+	if len(chain) > 0 {
+		nodeSelector, nodeAffinity = buildGPUTypeScheduling(chain)
+	}
+	if nodeSelector == nil {
+		nodeSelector = make(map[string]string)
+	}
+	podSpec.NodeSelector = nodeSelector
+
+	if podSpec.Affinity == nil {
+		if nodeAffinity == nil {
+			return
+		}
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	podSpec.Affinity.NodeAffinity = nodeAffinity
+	if podSpec.Affinity.NodeAffinity == nil && podSpec.Affinity.PodAffinity == nil && podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity = nil
+	}
+}
+
+// gpuTypeChainJSON returns the annotation value of a chain ("" when there is none)
+func gpuTypeChainJSON(chain []gpuTypeOption) (string, error) {
+	if len(chain) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(chain)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal GPU type chain: %w", err)
+	}
+	return string(data), nil
+}
+
+// gpuTypeChainOf returns the GPU type chain recorded on a deployment, nil when there is none
+func gpuTypeChainOf(deployment *appsv1.Deployment) []gpuTypeOption {
+	value := deployment.Annotations[gpuTypesAnnotation]
+	if value == "" {
+		return nil
+	}
+	var chain []gpuTypeOption
+	if err := json.Unmarshal([]byte(value), &chain); err != nil {
+		return nil
+	}
+	return chain
+}
+
+// gpuTypeOfNode returns the first GPU type of the chain whose node selector matches the node
+// labels, empty when none does
+func gpuTypeOfNode(chain []gpuTypeOption, nodeLabels map[string]string) string {
+	for _, option := range chain {
+		matches := true
+		for k, v := range option.NodeSelector {
+			if nodeLabels[k] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return option.GPUType
+		}
+	}
+	return ""
+}
+
+func selectorsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if value, ok := b[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeLabelsCache caches node labels for GPU type resolution. Failed lookups are cached as
+// well, so that a missing nodes permission doesn't cost an API call per pod event.
+type nodeLabelsCache struct {
+	mu      sync.Mutex
+	entries map[string]nodeLabelsEntry
+}
+
+type nodeLabelsEntry struct {
+	labels    map[string]string
+	fetchedAt time.Time
+}
+
+// podGPUType returns the GPU type the pod's node provides, empty when the pod is not scheduled
+// yet or its deployment has no GPU type chain (the spec's gpuType applies then)
+func (m *Manager) podGPUType(pod *corev1.Pod) string {
+	if pod.Spec.NodeName == "" || m.deploymentLister == nil {
+		return ""
+	}
+	endpoint := pod.Labels["app"]
+	if endpoint == "" {
+		return ""
+	}
+	deployment, err := m.deploymentLister.Deployments(m.namespace).Get(endpoint)
+	if err != nil {
+		return ""
+	}
+	chain := gpuTypeChainOf(deployment)
+	if len(chain) == 0 {
+		return ""
+	}
+	return gpuTypeOfNode(chain, m.nodeLabels(pod.Spec.NodeName))
+}
+
+// nodeLabels returns the labels of a node, nil when it can't be read
+func (m *Manager) nodeLabels(nodeName string) map[string]string {
+	now := time.Now()
+	m.nodeLabelsCache.mu.Lock()
+	if m.nodeLabelsCache.entries == nil {
+		m.nodeLabelsCache.entries = make(map[string]nodeLabelsEntry)
+	}
+	if entry, ok := m.nodeLabelsCache.entries[nodeName]; ok && now.Sub(entry.fetchedAt) < nodeLabelsTTL {
+		m.nodeLabelsCache.mu.Unlock()
+		return entry.labels
+	}
+	m.nodeLabelsCache.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var labels map[string]string
+	node, err := m.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		logger.WarnCtx(ctx, "failed to get node %s to resolve the GPU type of its pods: %v", nodeName, err)
+	} else {
+		labels = node.Labels
+	}
+
+	m.nodeLabelsCache.mu.Lock()
+	defer m.nodeLabelsCache.mu.Unlock()
+	// Drop expired entries, nodes come and go with autoscaling
+	for name, entry := range m.nodeLabelsCache.entries {
+		if now.Sub(entry.fetchedAt) >= nodeLabelsTTL {
+			delete(m.nodeLabelsCache.entries, name)
+		}
+	}
+	m.nodeLabelsCache.entries[nodeName] = nodeLabelsEntry{labels: labels, fetchedAt: now}
+	return labels
+}
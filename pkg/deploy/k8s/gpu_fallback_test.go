@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func fallbackSpec() (*ResourceSpec, PlatformConfig) {
+	spec := &ResourceSpec{
+		Name:     "gpu-80g",
+		Category: "gpu",
+		Resources: SpecResources{
+			GPU:              "1",
+			GpuType:          "NVIDIA-H100",
+			GpuTypeFallbacks: []string{"NVIDIA-A100", "NVIDIA-L40S"},
+		},
+	}
+	platform := PlatformConfig{
+		NodeSelector: map[string]string{"karpenter.sh/nodepool": "h100", "workload": "inference"},
+		GPUTypeNodeSelectors: map[string]map[string]string{
+			"NVIDIA-A100": {"karpenter.sh/nodepool": "a100"},
+			"NVIDIA-L40S": {"karpenter.sh/nodepool": "l40s"},
+		},
+	}
+	return spec, platform
+}
+
+func TestGPUTypeChain(t *testing.T) {
+	spec, platform := fallbackSpec()
+	chain, err := gpuTypeChain(spec, platform)
+	require.NoError(t, err)
+	require.Len(t, chain, 3)
+	assert.Equal(t, "NVIDIA-H100", chain[0].GPUType)
+	assert.Equal(t, map[string]string{"karpenter.sh/nodepool": "a100", "workload": "inference"}, chain[1].NodeSelector)
+
+	// No fallbacks, no chain
+	chain, err = gpuTypeChain(&ResourceSpec{Resources: SpecResources{GpuType: "NVIDIA-H100"}}, platform)
+	require.NoError(t, err)
+	assert.Nil(t, chain)
+
+	spec.Resources.GpuTypeFallbacks = []string{"NVIDIA-A10"}
+	_, err = gpuTypeChain(spec, platform)
+	assert.ErrorContains(t, err, "no node selector")
+
+	spec.Resources.GpuTypeFallbacks = []string{"NVIDIA-A100"}
+	platform.GPUTypeNodeSelectors["NVIDIA-A100"] = map[string]string{"karpenter.sh/nodepool": "h100"}
+	_, err = gpuTypeChain(spec, platform)
+	assert.ErrorContains(t, err, "same node selector")
+}
+
+func TestBuildGPUTypeScheduling(t *testing.T) {
+	spec, platform := fallbackSpec()
+	chain, err := gpuTypeChain(spec, platform)
+	require.NoError(t, err)
+
+	nodeSelector, affinity := buildGPUTypeScheduling(chain)
+	// Keys shared by every GPU type stay in the nodeSelector
+	assert.Equal(t, map[string]string{"workload": "inference"}, nodeSelector)
+
+	terms := affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 3)
+	for i, pool := range []string{"h100", "a100", "l40s"} {
+		assert.Equal(t, []corev1.NodeSelectorRequirement{
+			{Key: "karpenter.sh/nodepool", Operator: corev1.NodeSelectorOpIn, Values: []string{pool}},
+		}, terms[i].MatchExpressions)
+	}
+
+	preferred := affinity.PreferredDuringSchedulingIgnoredDuringExecution
+	require.Len(t, preferred, 3)
+	assert.Equal(t, int32(100), preferred[0].Weight)
+	assert.Greater(t, preferred[0].Weight, preferred[1].Weight)
+	assert.Greater(t, preferred[1].Weight, preferred[2].Weight)
+	assert.GreaterOrEqual(t, preferred[2].Weight, int32(1))
+}
+
+func TestApplyGPUTypeScheduling(t *testing.T) {
+	spec, platform := fallbackSpec()
+	chain, err := gpuTypeChain(spec, platform)
+	require.NoError(t, err)
+
+	podSpec := &corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}}
+	applyGPUTypeScheduling(podSpec, platform.NodeSelector, chain)
+	assert.Equal(t, map[string]string{"workload": "inference"}, podSpec.NodeSelector)
+	require.NotNil(t, podSpec.Affinity.NodeAffinity)
+	assert.NotNil(t, podSpec.Affinity.PodAntiAffinity)
+
+	// Moving to a spec without fallbacks restores the plain nodeSelector
+	applyGPUTypeScheduling(podSpec, platform.NodeSelector, nil)
+	assert.Equal(t, platform.NodeSelector, podSpec.NodeSelector)
+	assert.Nil(t, podSpec.Affinity.NodeAffinity)
+	assert.NotNil(t, podSpec.Affinity.PodAntiAffinity)
+
+	podSpec = &corev1.PodSpec{}
+	applyGPUTypeScheduling(podSpec, nil, nil)
+	assert.Nil(t, podSpec.Affinity)
+	assert.NotNil(t, podSpec.NodeSelector)
+}
+
+func TestGPUTypeOfNode(t *testing.T) {
+	spec, platform := fallbackSpec()
+	chain, err := gpuTypeChain(spec, platform)
+	require.NoError(t, err)
+
+	assert.Equal(t, "NVIDIA-A100", gpuTypeOfNode(chain, map[string]string{
+		"karpenter.sh/nodepool": "a100", "workload": "inference", "kubernetes.io/hostname": "node-1",
+	}))
+	assert.Equal(t, "", gpuTypeOfNode(chain, map[string]string{"karpenter.sh/nodepool": "a100"}))
+	assert.Equal(t, "", gpuTypeOfNode(chain, nil))
+}
+
+func TestDeploymentTemplate_RecordsGPUTypes(t *testing.T) {
+	spec, platform := fallbackSpec()
+	chain, err := gpuTypeChain(spec, platform)
+	require.NoError(t, err)
+	chainJSON, err := gpuTypeChainJSON(chain)
+	require.NoError(t, err)
+
+	renderer := NewTemplateRenderer("../../../config/templates")
+	content, err := renderer.Render("deployment.yaml", &RenderContext{
+		Endpoint:      "flux",
+		Namespace:     "wavespeed",
+		Image:         "flux:latest",
+		Replicas:      1,
+		ContainerName: "flux-worker",
+		ContainerPort: 8000,
+		MemoryRequest: "96Gi",
+		GPUTypesJSON:  chainJSON,
+	})
+	require.NoError(t, err)
+
+	var deployment appsv1.Deployment
+	require.NoError(t, yaml.Unmarshal([]byte(content), &deployment))
+	assert.Equal(t, chain, gpuTypeChainOf(&deployment))
+
+	assert.Nil(t, gpuTypeChainOf(&appsv1.Deployment{}))
+}
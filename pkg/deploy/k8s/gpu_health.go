@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// GPUUnhealthyTaint keeps new pods off a node where a worker failed its GPU health check.
+	// Remove it (kubectl taint nodes <node> waverless.io/gpu-unhealthy-) once the GPU is fixed.
+	GPUUnhealthyTaint = "waverless.io/gpu-unhealthy"
+	// gpuUnhealthyReasonAnnotation records why the node was tainted
+	gpuUnhealthyReasonAnnotation = "waverless.io/gpu-unhealthy-reason"
+	// maxNodeAnnotationReason bounds the reason stored on the node
+	maxNodeAnnotationReason = 512
+)
+
+// taintGPUUnhealthy adds the GPU unhealthy NoSchedule taint and its reason to node, and
+// reports whether the node changed
+func taintGPUUnhealthy(node *corev1.Node, reason string, now time.Time) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == GPUUnhealthyTaint {
+			return false
+		}
+	}
+	added := metav1.NewTime(now)
+	node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+		Key:       GPUUnhealthyTaint,
+		Value:     "true",
+		Effect:    corev1.TaintEffectNoSchedule,
+		TimeAdded: &added,
+	})
+	if len(reason) > maxNodeAnnotationReason {
+		reason = reason[:maxNodeAnnotationReason]
+	}
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[gpuUnhealthyReasonAnnotation] = reason
+	return true
+}
+
+// AvoidNode taints a node so that no new pods are scheduled onto it; running pods are kept
+func (m *Manager) AvoidNode(ctx context.Context, nodeName, reason string) error {
+	nodes := m.client.CoreV1().Nodes()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := nodes.Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+		}
+		if !taintGPUUnhealthy(node, reason, time.Now()) {
+			return nil
+		}
+		if _, err := nodes.Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to taint node %s: %w", nodeName, err)
+		}
+		return nil
+	})
+}
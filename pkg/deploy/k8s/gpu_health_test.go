@@ -0,0 +1,24 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTaintGPUUnhealthy(t *testing.T) {
+	node := &corev1.Node{}
+	node.Spec.Taints = []corev1.Taint{{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}}
+
+	assert.True(t, taintGPUUnhealthy(node, "GPU health check failed: ECC errors", time.Now()))
+	assert.Len(t, node.Spec.Taints, 2, "existing taints are kept")
+	assert.Equal(t, GPUUnhealthyTaint, node.Spec.Taints[1].Key)
+	assert.Equal(t, corev1.TaintEffectNoSchedule, node.Spec.Taints[1].Effect)
+	assert.Equal(t, "GPU health check failed: ECC errors", node.Annotations[gpuUnhealthyReasonAnnotation])
+
+	assert.False(t, taintGPUUnhealthy(node, "another worker", time.Now()), "an avoided node is tainted once")
+	assert.Len(t, node.Spec.Taints, 2)
+	assert.Equal(t, "GPU health check failed: ECC errors", node.Annotations[gpuUnhealthyReasonAnnotation])
+}
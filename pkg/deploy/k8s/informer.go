@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Informer defaults
+const (
+	DefaultInformerLabelSelector = "managed-by=waverless"
+	DefaultInformerResync        = 5 * time.Minute
+	// InformerSelectorAll disables label scoping (cache every object in the namespace)
+	InformerSelectorAll = "*"
+)
+
+// InformerOptions scopes the Deployment/Pod informers. In shared namespaces an unscoped
+// pod informer caches every pod; the default selector only caches waverless workloads.
+type InformerOptions struct {
+	LabelSelector    string        // Applied to deployments and pods (default: managed-by=waverless, "*" = everything)
+	PodFieldSelector string        // Extra pod field selector, e.g. status.phase!=Succeeded (default: none)
+	Resync           time.Duration // Full resync period (default: 5m)
+}
+
+// withDefaults fills unset options
+func (o InformerOptions) withDefaults() InformerOptions {
+	if o.LabelSelector == "" {
+		o.LabelSelector = DefaultInformerLabelSelector
+	}
+	if o.Resync <= 0 {
+		o.Resync = DefaultInformerResync
+	}
+	return o
+}
+
+// labelSelector returns the selector sent to the API server ("" = everything)
+func (o InformerOptions) labelSelector() string {
+	if o.LabelSelector == InformerSelectorAll {
+		return ""
+	}
+	return o.LabelSelector
+}
+
+// newScopedInformerFactory creates the informer factory with the label selector applied to
+// every informer; the pod informer is registered up front so it also gets the field selector
+func newScopedInformerFactory(client kubernetes.Interface, namespace string, opts InformerOptions) informers.SharedInformerFactory {
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		client,
+		opts.Resync,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(list *metav1.ListOptions) {
+			list.LabelSelector = opts.labelSelector()
+		}),
+	)
+	factory.InformerFor(&corev1.Pod{}, func(c kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+		return coreinformers.NewFilteredPodInformer(c, namespace, resync,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, opts.tweakPodList)
+	})
+	return factory
+}
+
+// tweakPodList scopes the pod list/watch
+func (o InformerOptions) tweakPodList(list *metav1.ListOptions) {
+	list.LabelSelector = o.labelSelector()
+	if o.PodFieldSelector != "" {
+		list.FieldSelector = o.PodFieldSelector
+	}
+}
+
+// InformerStats describes one informer cache
+type InformerStats struct {
+	Resource       string `json:"resource"`
+	Synced         bool   `json:"synced"`
+	Objects        int    `json:"objects"`
+	EstimatedBytes int64  `json:"estimatedBytes"` // Protobuf-encoded size; heap usage is a small multiple of this
+	QueueDepth     int    `json:"queueDepth"`     // Keys waiting for reconcile
+	Tracked        int    `json:"tracked"`        // Objects remembered for change detection
+}
+
+// InformerCacheStats describes the informer caches of the manager
+type InformerCacheStats struct {
+	Namespace        string          `json:"namespace"`
+	LabelSelector    string          `json:"labelSelector"`
+	PodFieldSelector string          `json:"podFieldSelector,omitempty"`
+	Resync           string          `json:"resync"`
+	Informers        []InformerStats `json:"informers"`
+}
+
+// InformerStats reports object counts and estimated memory of the informer caches.
+// It walks every cached object, so it is meant for diagnostics rather than hot paths.
+func (m *Manager) InformerStats() *InformerCacheStats {
+	stats := &InformerCacheStats{
+		Namespace:        m.namespace,
+		LabelSelector:    m.informerOpts.LabelSelector,
+		PodFieldSelector: m.informerOpts.PodFieldSelector,
+		Resync:           m.informerOpts.Resync.String(),
+	}
+	if m.informerFactory == nil {
+		return stats
+	}
+
+	deployments := informerStats("deployments", m.informerFactory.Apps().V1().Deployments().Informer())
+	pods := informerStats("pods", m.informerFactory.Core().V1().Pods().Informer())
+	if m.queues != nil {
+		m.queues.mu.Lock()
+		deployments.Tracked = len(m.queues.lastDeployments)
+		pods.Tracked = len(m.queues.lastPods)
+		m.queues.mu.Unlock()
+		deployments.QueueDepth = m.queues.deployments.Len()
+		pods.QueueDepth = m.queues.pods.Len()
+	}
+	stats.Informers = []InformerStats{deployments, pods}
+	return stats
+}
+
+// sizer is implemented by generated Kubernetes API types
+type sizer interface {
+	Size() int
+}
+
+func informerStats(resource string, informer cache.SharedIndexInformer) InformerStats {
+	stats := InformerStats{Resource: resource, Synced: informer.HasSynced()}
+	for _, obj := range informer.GetStore().List() {
+		stats.Objects++
+		if s, ok := obj.(sizer); ok {
+			stats.EstimatedBytes += int64(s.Size())
+		}
+	}
+	return stats
+}
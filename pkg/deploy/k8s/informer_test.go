@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestInformerOptions_Defaults(t *testing.T) {
+	opts := InformerOptions{}.withDefaults()
+	assert.Equal(t, DefaultInformerLabelSelector, opts.labelSelector())
+	assert.Equal(t, DefaultInformerResync, opts.Resync)
+
+	assert.Equal(t, "", InformerOptions{LabelSelector: InformerSelectorAll}.withDefaults().labelSelector())
+}
+
+func TestScopedInformerFactory_OnlyCachesManagedPods(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		workerPod("flux-abc"),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "someone-else", Namespace: "wavespeed", Labels: map[string]string{"app": "db"}}},
+	)
+	factory := newScopedInformerFactory(client, "wavespeed", InformerOptions{}.withDefaults())
+	podInformer := factory.Core().V1().Pods()
+	deploymentInformer := factory.Apps().V1().Deployments()
+	// Informers are registered lazily, so request them before starting the factory
+	podSynced := podInformer.Informer().HasSynced
+	deploymentSynced := deploymentInformer.Informer().HasSynced
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	require.True(t, cache.WaitForCacheSync(stopCh, podSynced, deploymentSynced))
+
+	pods, err := podInformer.Lister().Pods("wavespeed").List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "flux-abc", pods[0].Name)
+
+	m := &Manager{namespace: "wavespeed", informerFactory: factory, informerOpts: InformerOptions{}.withDefaults(), queues: newEventQueues()}
+	stats := m.InformerStats()
+	require.Len(t, stats.Informers, 2)
+	assert.Equal(t, "pods", stats.Informers[1].Resource)
+	assert.Equal(t, 1, stats.Informers[1].Objects)
+	assert.Positive(t, stats.Informers[1].EstimatedBytes)
+	assert.Equal(t, DefaultInformerLabelSelector, stats.LabelSelector)
+}
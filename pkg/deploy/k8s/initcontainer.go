@@ -0,0 +1,150 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"waverless/pkg/interfaces"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Init container naming
+const (
+	InitContainerPrefix        = "init-"
+	initDescriptionsAnnotation = "waverless.io/init-descriptions" // JSON map of container name -> description
+)
+
+// initFailureReasons are waiting reasons of an init container that will not resolve on their own
+var initFailureReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+}
+
+// applyInitContainers validates the init containers of a request and adds their containers and
+// volumes to the render context. claims are the PVCs mounted by the worker.
+func (m *Manager) applyInitContainers(ctx *RenderContext, initContainers []interfaces.InitContainer, claims []interfaces.VolumeMount) error {
+	if len(initContainers) == 0 {
+		return nil
+	}
+
+	volumes := newPodVolumes(ctx, claims)
+	names := make(map[string]bool, len(initContainers))
+	descriptions := make(map[string]string)
+	for _, ic := range initContainers {
+		if err := validateContainer("init container", InitContainerPrefix, ic.Sidecar); err != nil {
+			return err
+		}
+		if names[ic.Name] {
+			return fmt.Errorf("duplicate init container name %q", ic.Name)
+		}
+		names[ic.Name] = true
+
+		info, err := m.containerInfo("init container", InitContainerPrefix, ic.Sidecar, volumes)
+		if err != nil {
+			return err
+		}
+		ctx.InitContainers = append(ctx.InitContainers, info)
+		if ic.Description != "" {
+			descriptions[info.Name] = ic.Description
+		}
+	}
+
+	if len(descriptions) > 0 {
+		descriptionsJSON, err := json.Marshal(descriptions)
+		if err != nil {
+			return fmt.Errorf("failed to marshal init container descriptions: %w", err)
+		}
+		// Rendered in a single-quoted YAML scalar
+		ctx.InitDescriptionsJSON = strings.ReplaceAll(string(descriptionsJSON), "'", "''")
+	}
+	return nil
+}
+
+// setInitStatus fills the init container progress of a pending deployment from the pod cache
+func (m *Manager) setInitStatus(info *AppInfo, deployment *appsv1.Deployment) {
+	if info.Status != "Pending" || len(deployment.Spec.Template.Spec.InitContainers) == 0 || m.podLister == nil {
+		return
+	}
+	pods, err := m.podLister.Pods(deployment.Namespace).List(labels.SelectorFromSet(labels.Set{"app": deployment.Name}))
+	if err != nil {
+		return
+	}
+	info.InitStatus = InitStatus(deployment, pods)
+}
+
+// InitStatus summarizes the init container progress of a deployment's pods, e.g.
+// "Initializing: pulling model (2/3 pods)" or "Init failed: pulling model (CrashLoopBackOff, 1/3 pods)".
+// It is empty once no pod is initializing.
+func InitStatus(deployment *appsv1.Deployment, pods []*corev1.Pod) string {
+	descriptions := make(map[string]string)
+	if raw := deployment.Annotations[initDescriptionsAnnotation]; raw != "" {
+		_ = json.Unmarshal([]byte(raw), &descriptions)
+	}
+	order := make(map[string]int, len(deployment.Spec.Template.Spec.InitContainers))
+	for i, c := range deployment.Spec.Template.Spec.InitContainers {
+		order[c.Name] = i
+	}
+	describe := func(name string) string {
+		if d := descriptions[name]; d != "" {
+			return d
+		}
+		return name
+	}
+
+	total, initializing, failing := 0, 0, 0
+	current, failed, failReason := "", "", ""
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		total++
+		name, reason, ok := podInitStage(pod)
+		if !ok {
+			continue
+		}
+		if reason != "" {
+			failing++
+			if failed == "" {
+				failed, failReason = name, reason
+			}
+			continue
+		}
+		initializing++
+		if current == "" || order[name] < order[current] {
+			current = name
+		}
+	}
+
+	switch {
+	case failing > 0:
+		return fmt.Sprintf("Init failed: %s (%s, %d/%d pods)", describe(failed), failReason, failing, total)
+	case initializing > 0:
+		return fmt.Sprintf("Initializing: %s (%d/%d pods)", describe(current), initializing, total)
+	}
+	return ""
+}
+
+// podInitStage returns the init container a pod is at and, when it is failing, why.
+// ok is false once every init container has completed (or none has started yet).
+func podInitStage(pod *corev1.Pod) (name, failReason string, ok bool) {
+	for _, ics := range pod.Status.InitContainerStatuses {
+		switch {
+		case ics.State.Terminated != nil && ics.State.Terminated.ExitCode == 0:
+			continue
+		case ics.State.Terminated != nil:
+			return ics.Name, fmt.Sprintf("exit code %d", ics.State.Terminated.ExitCode), true
+		case ics.State.Waiting != nil && initFailureReasons[ics.State.Waiting.Reason]:
+			return ics.Name, ics.State.Waiting.Reason, true
+		default:
+			return ics.Name, "", true
+		}
+	}
+	return "", "", false
+}
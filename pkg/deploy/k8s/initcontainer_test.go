@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"waverless/pkg/interfaces"
+)
+
+func TestDeploymentTemplate_RendersInitContainers(t *testing.T) {
+	m := &Manager{}
+	ctx := sidecarRenderContext()
+	require.NoError(t, m.applyInitContainers(ctx, []interfaces.InitContainer{{
+		Sidecar: interfaces.Sidecar{
+			Name:    "model",
+			Image:   "curlimages/curl:8.8.0",
+			Command: []string{"sh", "-c", "curl -fsSL -o /models/model.bin \"$MODEL_URL\""},
+			Env:     map[string]string{"MODEL_URL": "https://example.com/model.bin"},
+			Mounts:  []interfaces.SidecarMount{{Volume: "models", MountPath: "/models"}},
+		},
+		Description: "pulling model 'flux'",
+	}}, nil))
+	// A sidecar naming the same scratch volume shares it
+	require.NoError(t, m.applySidecars(ctx, []interfaces.Sidecar{{
+		Name:   "exporter",
+		Image:  "prom/node-exporter:v1.8.0",
+		Mounts: []interfaces.SidecarMount{{Volume: "models", MountPath: "/data"}},
+	}}, nil))
+
+	renderer := NewTemplateRenderer("../../../config/templates")
+	content, err := renderer.Render("deployment.yaml", ctx)
+	require.NoError(t, err)
+
+	var deployment appsv1.Deployment
+	require.NoError(t, yaml.Unmarshal([]byte(content), &deployment))
+	podSpec := deployment.Spec.Template.Spec
+
+	require.Len(t, podSpec.InitContainers, 1)
+	initContainer := podSpec.InitContainers[0]
+	assert.Equal(t, "init-model", initContainer.Name)
+	assert.Equal(t, []string{"sh", "-c", "curl -fsSL -o /models/model.bin \"$MODEL_URL\""}, initContainer.Command)
+	assert.Equal(t, []corev1.EnvVar{{Name: "MODEL_URL", Value: "https://example.com/model.bin"}}, initContainer.Env)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "scratch-models", MountPath: "/models"}}, initContainer.VolumeMounts)
+
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "scratch-models", MountPath: "/models"})
+	scratch := 0
+	for _, v := range podSpec.Volumes {
+		if v.Name == "scratch-models" {
+			scratch++
+		}
+	}
+	assert.Equal(t, 1, scratch)
+	assert.JSONEq(t, `{"init-model": "pulling model 'flux'"}`, deployment.Annotations[initDescriptionsAnnotation])
+}
+
+func TestApplyInitContainers_Validation(t *testing.T) {
+	m := &Manager{}
+	assert.Error(t, m.applyInitContainers(sidecarRenderContext(), []interfaces.InitContainer{
+		{Sidecar: interfaces.Sidecar{Name: "model", Image: "busybox"}},
+		{Sidecar: interfaces.Sidecar{Name: "model", Image: "busybox"}},
+	}, nil))
+	assert.Error(t, m.applyInitContainers(sidecarRenderContext(), []interfaces.InitContainer{
+		{Sidecar: interfaces.Sidecar{Name: "model"}},
+	}, nil))
+}
+
+func initPod(name string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: statuses},
+	}
+}
+
+func TestInitStatus(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "flux",
+			Annotations: map[string]string{initDescriptionsAnnotation: `{"init-model":"pulling model"}`},
+		},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init-model"}, {Name: "init-warmup"}},
+		}}},
+	}
+	done := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	waiting := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}
+	crashing := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+
+	pods := []*corev1.Pod{
+		initPod("a", corev1.ContainerStatus{Name: "init-model", State: running}, corev1.ContainerStatus{Name: "init-warmup", State: waiting}),
+		initPod("b", corev1.ContainerStatus{Name: "init-model", State: done}, corev1.ContainerStatus{Name: "init-warmup", State: running}),
+		initPod("c", corev1.ContainerStatus{Name: "init-model", State: done}, corev1.ContainerStatus{Name: "init-warmup", State: done}),
+	}
+	assert.Equal(t, "Initializing: pulling model (2/3 pods)", InitStatus(deployment, pods))
+
+	assert.Equal(t, "Initializing: init-warmup (1/2 pods)", InitStatus(deployment, pods[1:]))
+
+	pods[0].Status.InitContainerStatuses[0].State = crashing
+	assert.Equal(t, "Init failed: pulling model (CrashLoopBackOff, 1/3 pods)", InitStatus(deployment, pods))
+
+	assert.Empty(t, InitStatus(deployment, pods[2:]))
+}
@@ -0,0 +1,222 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"waverless/pkg/interfaces"
+)
+
+// controlPlaneSelector matches the waverless pods workers must reach to pull jobs and send heartbeats
+var controlPlaneSelector = map[string]string{"app": "waverless"}
+
+// hostLookupTimeout bounds DNS resolution of allowlisted hosts while rendering
+const hostLookupTimeout = 5 * time.Second
+
+// egressPolicyName returns the NetworkPolicy name for an endpoint
+func egressPolicyName(endpoint string) string {
+	return endpoint + "-egress"
+}
+
+// workerServiceAccountName returns the restricted ServiceAccount name for an endpoint
+func workerServiceAccountName(endpoint string) string {
+	return endpoint + "-worker"
+}
+
+// isolationObjects holds the optional objects rendered next to an endpoint's Deployment
+type isolationObjects struct {
+	serviceAccount *corev1.ServiceAccount
+	networkPolicy  *networkingv1.NetworkPolicy
+}
+
+// objects returns the rendered objects in apply order
+func (o isolationObjects) objects() []interface{} {
+	var objs []interface{}
+	if o.serviceAccount != nil {
+		objs = append(objs, o.serviceAccount)
+	}
+	if o.networkPolicy != nil {
+		objs = append(objs, o.networkPolicy)
+	}
+	return objs
+}
+
+// buildIsolationObjects renders the ServiceAccount and NetworkPolicy requested for an endpoint
+func (m *Manager) buildIsolationObjects(ctx context.Context, req *DeployAppRequest) (isolationObjects, error) {
+	var result isolationObjects
+	if req.RestrictedServiceAccount {
+		result.serviceAccount = buildWorkerServiceAccount(m.namespace, req.Endpoint)
+	}
+	if req.EgressPolicy != nil {
+		lookupCtx, cancel := context.WithTimeout(ctx, hostLookupTimeout)
+		defer cancel()
+		policy, err := buildEgressNetworkPolicy(lookupCtx, m.namespace, req.Endpoint, req.EgressPolicy, net.DefaultResolver.LookupIPAddr)
+		if err != nil {
+			return result, fmt.Errorf("invalid egress policy: %w", err)
+		}
+		result.networkPolicy = policy
+	}
+	return result, nil
+}
+
+// buildWorkerServiceAccount builds a ServiceAccount with no role bindings and no mounted token
+func buildWorkerServiceAccount(namespace, endpoint string) *corev1.ServiceAccount {
+	automount := false
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workerServiceAccountName(endpoint),
+			Namespace: namespace,
+			Labels:    map[string]string{"app": endpoint, "managed-by": "waverless"},
+		},
+		AutomountServiceAccountToken: &automount,
+	}
+}
+
+// buildEgressNetworkPolicy builds a NetworkPolicy that denies all egress from the endpoint's pods
+// except DNS, the waverless control plane and the allowlisted CIDRs and hosts.
+// Hosts are resolved once here; the policy must be re-applied if their addresses change.
+func buildEgressNetworkPolicy(ctx context.Context, namespace, endpoint string, policy *interfaces.EgressPolicy,
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)) (*networkingv1.NetworkPolicy, error) {
+	cidrs, err := allowedEgressCIDRs(ctx, policy, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	dnsPort := intstr.FromInt(53)
+	rules := []networkingv1.NetworkPolicyEgressRule{
+		{
+			// DNS (kube-dns / CoreDNS in any namespace)
+			To: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}},
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &dnsPort},
+				{Protocol: &tcp, Port: &dnsPort},
+			},
+		},
+		{
+			// Waverless control plane (job pull, heartbeat, results)
+			To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: controlPlaneSelector}}},
+		},
+	}
+	if len(cidrs) > 0 {
+		peers := make([]networkingv1.NetworkPolicyPeer, len(cidrs))
+		for i, cidr := range cidrs {
+			peers[i] = networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}}
+		}
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{To: peers})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      egressPolicyName(endpoint),
+			Namespace: namespace,
+			Labels:    map[string]string{"app": endpoint, "managed-by": "waverless"},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": endpoint}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      rules,
+		},
+	}, nil
+}
+
+// allowedEgressCIDRs validates allowlisted CIDRs and resolves hosts into single-address CIDRs
+func allowedEgressCIDRs(ctx context.Context, policy *interfaces.EgressPolicy,
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)) ([]string, error) {
+	seen := make(map[string]struct{})
+	add := func(cidr string) { seen[cidr] = struct{}{} }
+
+	for _, raw := range policy.AllowedCIDRs {
+		value := strings.TrimSpace(raw)
+		if value == "" {
+			continue
+		}
+		if ip := net.ParseIP(value); ip != nil {
+			add(singleAddressCIDR(ip))
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", raw)
+		}
+		add(network.String())
+	}
+
+	for _, raw := range policy.AllowedHosts {
+		host := strings.ToLower(strings.TrimSpace(raw))
+		if host == "" {
+			continue
+		}
+		addrs, err := lookup(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve host %q: %w", host, err)
+		}
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("host %q resolved to no addresses", host)
+		}
+		for _, addr := range addrs {
+			add(singleAddressCIDR(addr.IP))
+		}
+	}
+
+	cidrs := make([]string, 0, len(seen))
+	for cidr := range seen {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+	return cidrs, nil
+}
+
+// singleAddressCIDR returns a /32 (IPv4) or /128 (IPv6) CIDR for ip
+func singleAddressCIDR(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		return v4.String() + "/32"
+	}
+	return ip.String() + "/128"
+}
+
+// applyServiceAccount creates or updates a ServiceAccount
+func (m *Manager) applyServiceAccount(ctx context.Context, sa *corev1.ServiceAccount) error {
+	serviceAccounts := m.client.CoreV1().ServiceAccounts(m.namespace)
+	existing, err := serviceAccounts.Get(ctx, sa.Name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		_, err = serviceAccounts.Create(ctx, sa, metav1.CreateOptions{})
+		return err
+	}
+
+	sa.ResourceVersion = existing.ResourceVersion
+	_, err = serviceAccounts.Update(ctx, sa, metav1.UpdateOptions{})
+	return err
+}
+
+// applyNetworkPolicy creates or updates a NetworkPolicy
+func (m *Manager) applyNetworkPolicy(ctx context.Context, policy *networkingv1.NetworkPolicy) error {
+	policies := m.client.NetworkingV1().NetworkPolicies(m.namespace)
+	existing, err := policies.Get(ctx, policy.Name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		_, err = policies.Create(ctx, policy, metav1.CreateOptions{})
+		return err
+	}
+
+	policy.ResourceVersion = existing.ResourceVersion
+	_, err = policies.Update(ctx, policy, metav1.UpdateOptions{})
+	return err
+}
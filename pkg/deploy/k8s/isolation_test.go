@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"waverless/pkg/interfaces"
+)
+
+func fakeLookup(hosts map[string][]string) func(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		ips, ok := hosts[host]
+		if !ok {
+			return nil, fmt.Errorf("no such host")
+		}
+		addrs := make([]net.IPAddr, len(ips))
+		for i, ip := range ips {
+			addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+		}
+		return addrs, nil
+	}
+}
+
+func TestBuildEgressNetworkPolicy(t *testing.T) {
+	lookup := fakeLookup(map[string][]string{
+		"huggingface.co": {"52.1.2.3", "2600:1f18::1"},
+	})
+	policy, err := buildEgressNetworkPolicy(context.Background(), "wavespeed", "flux", &interfaces.EgressPolicy{
+		AllowedCIDRs: []string{"10.0.0.0/8", "52.1.2.3", " 192.168.1.7/24 "},
+		AllowedHosts: []string{"HuggingFace.co"},
+	}, lookup)
+	require.NoError(t, err)
+
+	assert.Equal(t, "flux-egress", policy.Name)
+	assert.Equal(t, map[string]string{"app": "flux"}, policy.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+
+	// DNS, control plane, allowlist
+	require.Len(t, policy.Spec.Egress, 3)
+	assert.Len(t, policy.Spec.Egress[0].Ports, 2)
+	assert.Equal(t, controlPlaneSelector, policy.Spec.Egress[1].To[0].PodSelector.MatchLabels)
+
+	var cidrs []string
+	for _, peer := range policy.Spec.Egress[2].To {
+		cidrs = append(cidrs, peer.IPBlock.CIDR)
+	}
+	// Normalized, deduplicated and sorted
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.0/24", "2600:1f18::1/128", "52.1.2.3/32"}, cidrs)
+}
+
+func TestBuildEgressNetworkPolicy_DenyAll(t *testing.T) {
+	policy, err := buildEgressNetworkPolicy(context.Background(), "wavespeed", "flux", &interfaces.EgressPolicy{}, fakeLookup(nil))
+	require.NoError(t, err)
+	// Only DNS and control plane remain reachable
+	assert.Len(t, policy.Spec.Egress, 2)
+}
+
+func TestBuildEgressNetworkPolicy_Invalid(t *testing.T) {
+	_, err := buildEgressNetworkPolicy(context.Background(), "wavespeed", "flux", &interfaces.EgressPolicy{
+		AllowedCIDRs: []string{"10.0.0.0/33"},
+	}, fakeLookup(nil))
+	assert.Error(t, err)
+
+	_, err = buildEgressNetworkPolicy(context.Background(), "wavespeed", "flux", &interfaces.EgressPolicy{
+		AllowedHosts: []string{"unknown.example"},
+	}, fakeLookup(nil))
+	assert.Error(t, err)
+}
+
+func TestBuildWorkerServiceAccount(t *testing.T) {
+	sa := buildWorkerServiceAccount("wavespeed", "flux")
+	assert.Equal(t, "flux-worker", sa.Name)
+	require.NotNil(t, sa.AutomountServiceAccountToken)
+	assert.False(t, *sa.AutomountServiceAccountToken)
+}
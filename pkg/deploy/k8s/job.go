@@ -0,0 +1,339 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"waverless/pkg/interfaces"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// JobLabel carries the job name on the Job and its pod
+	JobLabel = "waverless.io/job"
+	// jobManagedBy differs from "waverless" so job pods are never taken for endpoint workers
+	jobManagedBy = "waverless-job"
+	// jobContainerName is the name of the job container
+	jobContainerName = "job"
+	// jobTTLAfterFinished keeps finished Jobs (and their logs) for a day before Kubernetes removes them
+	jobTTLAfterFinished = 24 * time.Hour
+)
+
+// RunJob creates a Kubernetes Job that runs the image once on the spec's nodes
+func (m *Manager) RunJob(ctx context.Context, req *interfaces.JobRequest) error {
+	if err := validateK8sName(req.Name); err != nil {
+		return err
+	}
+	spec, err := m.GetSpec(req.SpecName)
+	if err != nil {
+		return fmt.Errorf("failed to get spec %s: %w", req.SpecName, err)
+	}
+	job, err := m.buildJob(ctx, req, spec)
+	if err != nil {
+		return err
+	}
+	if _, err := m.client.BatchV1().Jobs(m.namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return fmt.Errorf("job %s already exists", req.Name)
+		}
+		return fmt.Errorf("failed to create job %s: %w", req.Name, err)
+	}
+	return nil
+}
+
+// buildJob renders the Job of a request: the spec's resources and scheduling, no restarts
+func (m *Manager) buildJob(ctx context.Context, req *interfaces.JobRequest, spec *ResourceSpec) (*batchv1.Job, error) {
+	platformConfig := spec.GetPlatformConfig(m.platform.GetName())
+
+	gpuCount := 0
+	if spec.Category == "gpu" {
+		var maxGpu int
+		fmt.Sscanf(spec.Resources.GPU, "%d", &maxGpu)
+		gpuCount = 1
+		if req.GpuCount > 0 {
+			if req.GpuCount > maxGpu {
+				return nil, fmt.Errorf("requested gpuCount %d exceeds spec max %d", req.GpuCount, maxGpu)
+			}
+			gpuCount = req.GpuCount
+		}
+	}
+
+	// Resources: spec defines per-GPU resources, requests equal limits
+	resources := corev1.ResourceList{}
+	if spec.Resources.Memory != "" {
+		quantity, err := resource.ParseQuantity(multiplyResource(spec.Resources.Memory, gpuCount))
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory for spec %s: %w", spec.Name, err)
+		}
+		resources[corev1.ResourceMemory] = quantity
+	}
+	if spec.Resources.CPU != "" {
+		quantity, err := resource.ParseQuantity(multiplyResource(spec.Resources.CPU, gpuCount))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu for spec %s: %w", spec.Name, err)
+		}
+		resources[corev1.ResourceCPU] = quantity
+	}
+	if gpuCount > 0 {
+		resources["nvidia.com/gpu"] = *resource.NewQuantity(int64(gpuCount), resource.DecimalSI)
+	}
+	ephemeralStorage, err := resolveEphemeralStorage("", spec)
+	if err != nil {
+		return nil, err
+	}
+	if ephemeralStorage != "" {
+		resources[corev1.ResourceEphemeralStorage] = resource.MustParse(ephemeralStorage)
+	}
+
+	if err := m.securityPolicy.Authorize(req.Name, requestedPrivileges(platformConfig.Security, false)...); err != nil {
+		return nil, err
+	}
+	securityContext, err := buildContainerSecurityContext(platformConfig.Security, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid security profile for spec %s: %w", spec.Name, err)
+	}
+
+	// Environment: globalEnv (without the RunPod worker contract) overridden by the request
+	envMap := make(map[string]string, len(m.globalEnv)+len(req.Env))
+	for k, v := range m.globalEnv {
+		if strings.HasPrefix(k, runPodEnvPrefix) {
+			continue
+		}
+		envMap[k] = strings.ReplaceAll(v, "{{.Endpoint}}", req.Name)
+	}
+	for k, v := range req.Env {
+		envMap[k] = v
+	}
+	names := make([]string, 0, len(envMap))
+	for k := range envMap {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	env := make([]corev1.EnvVar, 0, len(names)+1)
+	env = append(env, corev1.EnvVar{Name: "WAVERLESS_JOB_NAME", Value: req.Name})
+	for _, k := range names {
+		env = append(env, corev1.EnvVar{Name: k, Value: envMap[k]})
+	}
+
+	container := corev1.Container{
+		Name:            jobContainerName,
+		Image:           m.rewriteImage(ctx, req.Image),
+		Command:         req.Command,
+		Args:            req.Args,
+		Env:             env,
+		Resources:       corev1.ResourceRequirements{Requests: resources, Limits: resources.DeepCopy()},
+		SecurityContext: securityContext,
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy:    corev1.RestartPolicyNever,
+		NodeSelector:     platformConfig.NodeSelector,
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "dockerhub-secret"}},
+	}
+	for _, t := range platformConfig.Tolerations {
+		podSpec.Tolerations = append(podSpec.Tolerations, corev1.Toleration{
+			Key:      t.Key,
+			Operator: corev1.TolerationOperator(t.Operator),
+			Value:    t.Value,
+			Effect:   corev1.TaintEffect(t.Effect),
+		})
+	}
+
+	// Shared memory: request > spec
+	shmSize := req.ShmSize
+	if shmSize == "" {
+		shmSize = spec.Resources.ShmSize
+	}
+	if shmSize != "" {
+		quantity, err := resource.ParseQuantity(shmSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shmSize %q: %w", shmSize, err)
+		}
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         "dshm",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &quantity}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "dshm", MountPath: "/dev/shm"})
+	}
+	for i, vm := range req.VolumeMounts {
+		name := fmt.Sprintf("pvc-%d", i)
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: vm.PVCName}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: name, MountPath: vm.MountPath})
+	}
+	podSpec.Containers = []corev1.Container{container}
+
+	podLabels := make(map[string]string, len(platformConfig.Labels)+3)
+	for k, v := range platformConfig.Labels {
+		podLabels[k] = v
+	}
+	podLabels["managed-by"] = jobManagedBy
+	podLabels[JobLabel] = req.Name
+	podLabels["waverless.io/spec"] = req.SpecName
+
+	backoffLimit := int32(0)
+	ttl := int32(jobTTLAfterFinished.Seconds())
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      req.Name,
+			Namespace: m.namespace,
+			Labels:    map[string]string{"managed-by": jobManagedBy, JobLabel: req.Name, "waverless.io/spec": req.SpecName},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels, Annotations: platformConfig.Annotations},
+				Spec:       podSpec,
+			},
+		},
+	}
+	if req.TimeoutSeconds > 0 {
+		deadline := int64(req.TimeoutSeconds)
+		job.Spec.ActiveDeadlineSeconds = &deadline
+	}
+	return job, nil
+}
+
+// GetJobStatus returns the phase of a job from the Job and its pod, nil when the Job is gone
+func (m *Manager) GetJobStatus(ctx context.Context, name string) (*interfaces.JobStatus, error) {
+	job, err := m.client.BatchV1().Jobs(m.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job %s: %w", name, err)
+	}
+	pod, err := m.jobPod(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return jobStatus(job, pod), nil
+}
+
+// jobStatus combines the Job conditions with the state of its container
+func jobStatus(job *batchv1.Job, pod *corev1.Pod) *interfaces.JobStatus {
+	status := &interfaces.JobStatus{Phase: interfaces.JobPhasePending}
+	if job.Status.StartTime != nil {
+		t := job.Status.StartTime.Time
+		status.StartedAt = &t
+	}
+
+	if pod != nil {
+		status.PodName = pod.Name
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != jobContainerName {
+				continue
+			}
+			switch {
+			case cs.State.Running != nil:
+				status.Phase = interfaces.JobPhaseRunning
+				t := cs.State.Running.StartedAt.Time
+				status.StartedAt = &t
+			case cs.State.Terminated != nil:
+				exitCode := cs.State.Terminated.ExitCode
+				status.ExitCode = &exitCode
+				started, finished := cs.State.Terminated.StartedAt.Time, cs.State.Terminated.FinishedAt.Time
+				if !started.IsZero() {
+					status.StartedAt = &started
+				}
+				if !finished.IsZero() {
+					status.CompletedAt = &finished
+				}
+				if exitCode != 0 {
+					status.Message = fmt.Sprintf("exit code %d: %s", exitCode, cs.State.Terminated.Reason)
+				}
+			case cs.State.Waiting != nil:
+				status.Message = strings.TrimSpace(cs.State.Waiting.Reason + " " + cs.State.Waiting.Message)
+			}
+		}
+		if status.Phase == interfaces.JobPhasePending && status.Message == "" {
+			for _, cond := range pod.Status.Conditions {
+				if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+					status.Message = strings.TrimSpace(cond.Reason + " " + cond.Message)
+				}
+			}
+		}
+	}
+
+	// Job conditions are final
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			status.Phase = interfaces.JobPhaseSucceeded
+		case batchv1.JobFailed:
+			status.Phase = interfaces.JobPhaseFailed
+			if status.Message == "" || cond.Reason == "DeadlineExceeded" {
+				status.Message = strings.TrimSpace(cond.Reason + " " + cond.Message)
+			}
+		default:
+			continue
+		}
+		if status.CompletedAt == nil {
+			t := cond.LastTransitionTime.Time
+			status.CompletedAt = &t
+		}
+	}
+	return status
+}
+
+// DeleteJob deletes a Job and its pod; a missing Job is not an error
+func (m *Manager) DeleteJob(ctx context.Context, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := m.client.BatchV1().Jobs(m.namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete job %s: %w", name, err)
+	}
+	return nil
+}
+
+// StreamJobLogs streams the logs of a job's container
+func (m *Manager) StreamJobLogs(ctx context.Context, name string, follow bool, tailLines int) (io.ReadCloser, error) {
+	pod, err := m.jobPod(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if pod == nil {
+		return nil, fmt.Errorf("job %s has no pod", name)
+	}
+	opts := &corev1.PodLogOptions{Container: jobContainerName, Follow: follow}
+	if tailLines > 0 {
+		lines := int64(tailLines)
+		opts.TailLines = &lines
+	}
+	stream, err := m.client.CoreV1().Pods(m.namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job logs: %w", err)
+	}
+	return stream, nil
+}
+
+// jobPod returns the newest pod of a job, nil when none exists yet
+func (m *Manager) jobPod(ctx context.Context, name string) (*corev1.Pod, error) {
+	selector := labels.SelectorFromSet(labels.Set{JobLabel: name, "managed-by": jobManagedBy}).String()
+	pods, err := m.client.CoreV1().Pods(m.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of job %s: %w", name, err)
+	}
+	var newest *corev1.Pod
+	for i := range pods.Items {
+		if newest == nil || pods.Items[i].CreationTimestamp.After(newest.CreationTimestamp.Time) {
+			newest = &pods.Items[i]
+		}
+	}
+	return newest, nil
+}
@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"waverless/pkg/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testJobSpec() *ResourceSpec {
+	return &ResourceSpec{
+		Name:     "h100-1x",
+		Category: "gpu",
+		Resources: SpecResources{
+			CPU:     "8",
+			Memory:  "64Gi",
+			GPU:     "8",
+			ShmSize: "16Gi",
+		},
+		Platforms: map[string]PlatformConfig{
+			"generic": {
+				NodeSelector: map[string]string{"gpu": "h100"},
+				Tolerations:  []Toleration{{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoSchedule"}},
+			},
+		},
+	}
+}
+
+func TestBuildJob(t *testing.T) {
+	m := &Manager{namespace: "wavespeed", platform: &GenericPlatform{}, globalEnv: map[string]string{"HF_HOME": "/cache", "RUNPOD_WEBHOOK_GET_JOB": "x"}}
+	req := &interfaces.JobRequest{
+		Name:           "finetune-1",
+		SpecName:       "h100-1x",
+		Image:          "trainer:1",
+		Command:        []string{"python", "train.py"},
+		Env:            map[string]string{"EPOCHS": "3"},
+		GpuCount:       2,
+		VolumeMounts:   []interfaces.VolumeMount{{PVCName: "datasets", MountPath: "/data"}},
+		TimeoutSeconds: 3600,
+	}
+
+	job, err := m.buildJob(context.Background(), req, testJobSpec())
+	require.NoError(t, err)
+
+	assert.Equal(t, "finetune-1", job.Name)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, int64(3600), *job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, jobManagedBy, job.Spec.Template.Labels["managed-by"])
+	assert.Equal(t, "finetune-1", job.Spec.Template.Labels[JobLabel])
+
+	pod := job.Spec.Template.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, pod.RestartPolicy)
+	assert.Equal(t, map[string]string{"gpu": "h100"}, pod.NodeSelector)
+	require.Len(t, pod.Tolerations, 1)
+	require.Len(t, pod.Containers, 1)
+
+	c := pod.Containers[0]
+	assert.Equal(t, []string{"python", "train.py"}, c.Command)
+	assert.Equal(t, "2", c.Resources.Limits.Name("nvidia.com/gpu", "").String())
+	assert.Equal(t, "128Gi", c.Resources.Limits.Memory().String())
+	assert.Equal(t, "16", c.Resources.Requests.Cpu().String())
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "WAVERLESS_JOB_NAME", Value: "finetune-1"},
+		{Name: "EPOCHS", Value: "3"},
+		{Name: "HF_HOME", Value: "/cache"},
+	}, c.Env)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "dshm", MountPath: "/dev/shm"}, {Name: "pvc-0", MountPath: "/data"}}, c.VolumeMounts)
+}
+
+func TestBuildJobRejectsTooManyGPUs(t *testing.T) {
+	m := &Manager{namespace: "wavespeed", platform: &GenericPlatform{}}
+	_, err := m.buildJob(context.Background(), &interfaces.JobRequest{Name: "eval", Image: "eval:1", GpuCount: 16}, testJobSpec())
+	assert.ErrorContains(t, err, "exceeds spec max")
+}
+
+func TestJobStatus(t *testing.T) {
+	started := metav1.NewTime(time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC))
+	finished := metav1.NewTime(started.Add(30 * time.Minute))
+	job := &batchv1.Job{}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "eval-abc"}}
+
+	// Unscheduled pod
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes available"}}
+	status := jobStatus(job, pod)
+	assert.Equal(t, interfaces.JobPhasePending, status.Phase)
+	assert.Equal(t, "Unschedulable 0/3 nodes available", status.Message)
+
+	// Running container
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: jobContainerName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: started}}}}
+	status = jobStatus(job, pod)
+	assert.Equal(t, interfaces.JobPhaseRunning, status.Phase)
+	assert.Equal(t, "eval-abc", status.PodName)
+	assert.Equal(t, started.Time, *status.StartedAt)
+
+	// Failed container and job
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", StartedAt: started, FinishedAt: finished}}
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+	status = jobStatus(job, pod)
+	assert.Equal(t, interfaces.JobPhaseFailed, status.Phase)
+	assert.Equal(t, int32(137), *status.ExitCode)
+	assert.Equal(t, "exit code 137: OOMKilled", status.Message)
+	assert.Equal(t, finished.Time, *status.CompletedAt)
+
+	// Deadline exceeded wins over the container message
+	job.Status.Conditions[0].Reason = "DeadlineExceeded"
+	job.Status.Conditions[0].Message = "Job was active longer than specified deadline"
+	status = jobStatus(job, pod)
+	assert.Equal(t, "DeadlineExceeded Job was active longer than specified deadline", status.Message)
+}
+
+func TestGetJobStatusAndDelete(t *testing.T) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "eval", Namespace: "wavespeed"},
+		Status:     batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "eval-abc", Namespace: "wavespeed", Labels: map[string]string{JobLabel: "eval", "managed-by": jobManagedBy}}}
+	m := &Manager{client: fake.NewSimpleClientset(job, pod), namespace: "wavespeed"}
+	ctx := context.Background()
+
+	status, err := m.GetJobStatus(ctx, "eval")
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.JobPhaseSucceeded, status.Phase)
+	assert.Equal(t, "eval-abc", status.PodName)
+
+	require.NoError(t, m.DeleteJob(ctx, "eval"))
+	require.NoError(t, m.DeleteJob(ctx, "eval"))
+	status, err = m.GetJobStatus(ctx, "eval")
+	require.NoError(t, err)
+	assert.Nil(t, status)
+}
@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"waverless/pkg/logship"
+)
+
+// defaultLogReadLimitBytes bounds a single log read per pod
+const defaultLogReadLimitBytes = 1 << 20
+
+// PodLogSource reads worker container logs of waverless-managed pods for log shipping
+type PodLogSource struct {
+	manager    *Manager
+	limitBytes int64
+}
+
+// NewPodLogSource creates a log source backed by the K8s pod logs API
+func NewPodLogSource(provider *K8sDeploymentProvider, limitBytes int64) *PodLogSource {
+	if limitBytes <= 0 {
+		limitBytes = defaultLogReadLimitBytes
+	}
+	return &PodLogSource{manager: provider.manager, limitBytes: limitBytes}
+}
+
+// ListTargets returns worker pods whose containers have started (from Informer cache)
+func (s *PodLogSource) ListTargets(ctx context.Context) ([]logship.Target, error) {
+	selector := labels.SelectorFromSet(labels.Set{"managed-by": "waverless"})
+	pods, err := s.manager.podLister.Pods(s.manager.namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	targets := make([]logship.Target, 0, len(pods))
+	for _, pod := range pods {
+		endpoint := pod.Labels["app"]
+		if endpoint == "" || pod.Status.Phase == corev1.PodPending {
+			continue
+		}
+		targets = append(targets, logship.Target{Endpoint: endpoint, Worker: pod.Name})
+	}
+	return targets, nil
+}
+
+// ReadSince reads the worker container log written at or after since (second precision)
+func (s *PodLogSource) ReadSince(ctx context.Context, target logship.Target, since time.Time) ([]logship.Entry, error) {
+	opts := &corev1.PodLogOptions{
+		Container:  fmt.Sprintf("%s-worker", target.Endpoint),
+		Timestamps: true,
+		LimitBytes: &s.limitBytes,
+	}
+	if !since.IsZero() {
+		sinceTime := metav1.NewTime(since)
+		opts.SinceTime = &sinceTime
+	}
+
+	stream, err := s.manager.client.CoreV1().Pods(s.manager.namespace).GetLogs(target.Worker, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod logs: %w", err)
+	}
+	defer stream.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(stream, s.limitBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pod logs: %w", err)
+	}
+	return logship.ParseTimestampedLines(string(raw), target), nil
+}
+
+var _ logship.Source = (*PodLogSource)(nil)
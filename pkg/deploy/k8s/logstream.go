@@ -0,0 +1,187 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// logStreamDiscoveryInterval is how often a followed endpoint is checked for new or restarted pods
+var logStreamDiscoveryInterval = 5 * time.Second
+
+// maxLogLineBytes bounds a single log line; longer lines are sent in pieces
+const maxLogLineBytes = 64 * 1024
+
+// StreamAppLogs follows the worker container logs of an endpoint's pods. Pods that start
+// while the stream is open are picked up, and a restarted container is resumed from where
+// its previous stream ended. With podName only that pod is followed, until its log ends.
+// container selects a container other than the worker, e.g. a sidecar.
+func (m *Manager) StreamAppLogs(ctx context.Context, name string, tailLines int64, podName, container string) (<-chan interfaces.LogLine, error) {
+	if podName != "" {
+		pod, err := m.podLister.Pods(m.namespace).Get(podName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+		}
+		if pod.Labels["app"] != name {
+			return nil, interfaces.NewProviderError(interfaces.ErrNotFound, fmt.Errorf("pod %s does not belong to endpoint %s", podName, name))
+		}
+	} else if _, err := m.deploymentLister.Deployments(m.namespace).Get(name); err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	if container == "" {
+		container = fmt.Sprintf("%s-worker", name)
+	}
+
+	lines := make(chan interfaces.LogLine, 256)
+	s := &logStream{
+		m:         m,
+		endpoint:  name,
+		container: container,
+		tailLines: tailLines,
+		lines:     lines,
+		active:    make(map[string]bool),
+		endedAt:   make(map[string]time.Time),
+	}
+
+	if podName != "" {
+		s.wg.Add(1)
+		go func() {
+			s.follow(ctx, podName, nil)
+			s.wg.Wait()
+			close(lines)
+		}()
+		return lines, nil
+	}
+
+	go func() {
+		ticker := time.NewTicker(logStreamDiscoveryInterval)
+		defer ticker.Stop()
+		for {
+			s.discover(ctx)
+			select {
+			case <-ctx.Done():
+				s.wg.Wait()
+				close(lines)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return lines, nil
+}
+
+// logStream multiplexes the followed logs of an endpoint's pods onto one channel
+type logStream struct {
+	m         *Manager
+	endpoint  string
+	container string
+	tailLines int64
+	lines     chan<- interfaces.LogLine
+	wg        sync.WaitGroup
+
+	mu      sync.Mutex
+	active  map[string]bool      // pods with an open log stream
+	endedAt map[string]time.Time // when the last stream of a pod ended, to resume without repeating lines
+}
+
+// discover starts streams for running pods of the endpoint that have none
+func (s *logStream) discover(ctx context.Context) {
+	selector := labels.SelectorFromSet(labels.Set{"app": s.endpoint})
+	pods, err := s.m.podLister.Pods(s.m.namespace).List(selector)
+	if err != nil {
+		logger.Warnf("failed to list pods of endpoint %s for log streaming: %v", s.endpoint, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		current[pod.Name] = true
+		if s.active[pod.Name] || !containerRunning(pod, s.container) {
+			continue
+		}
+		var since *time.Time
+		if t, ok := s.endedAt[pod.Name]; ok {
+			since = &t
+		}
+		s.active[pod.Name] = true
+		s.wg.Add(1)
+		go s.follow(ctx, pod.Name, since)
+	}
+	for pod := range s.endedAt {
+		if !current[pod] {
+			delete(s.endedAt, pod)
+		}
+	}
+}
+
+// containerRunning reports whether a container (or init container) of a pod is running
+func containerRunning(pod *corev1.Pod, containerName string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return status.State.Running != nil
+		}
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name == containerName {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+// follow streams the log of one pod until it ends or ctx is done. A pod seen before resumes
+// at since; otherwise the stream starts with the last tailLines lines.
+func (s *logStream) follow(ctx context.Context, podName string, since *time.Time) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, podName)
+		s.endedAt[podName] = time.Now()
+		s.mu.Unlock()
+	}()
+
+	opts := &corev1.PodLogOptions{
+		Container: s.container,
+		Follow:    true,
+	}
+	if since != nil {
+		opts.SinceTime = &metav1.Time{Time: *since}
+	} else if s.tailLines > 0 {
+		opts.TailLines = &s.tailLines
+	}
+
+	stream, err := s.m.client.CoreV1().Pods(s.m.namespace).GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		if ctx.Err() == nil && !errors.IsNotFound(err) {
+			logger.Warnf("failed to stream logs of pod %s: %v", podName, err)
+		}
+		return
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReaderSize(stream, maxLogLineBytes)
+	for {
+		line, _, err := reader.ReadLine()
+		if err != nil {
+			return
+		}
+		select {
+		case s.lines <- interfaces.LogLine{Pod: podName, Line: string(line)}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"waverless/pkg/interfaces"
+)
+
+func runningWorkerPod(name string) *corev1.Pod {
+	pod := workerPod(name)
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "flux-worker",
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}}
+	return pod
+}
+
+func newLogStreamTestManager(t *testing.T, pods ...*corev1.Pod) *Manager {
+	t.Helper()
+	m, deployments, podIndexer := newQueueTestManager()
+	m.client = fake.NewSimpleClientset()
+	require.NoError(t, deployments.Add(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "flux", Namespace: "wavespeed"}}))
+	for _, pod := range pods {
+		require.NoError(t, podIndexer.Add(pod))
+	}
+	return m
+}
+
+func receiveLine(t *testing.T, ch <-chan interfaces.LogLine) interfaces.LogLine {
+	t.Helper()
+	select {
+	case line, ok := <-ch:
+		require.True(t, ok, "stream closed early")
+		return line
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a log line")
+	}
+	return interfaces.LogLine{}
+}
+
+func TestStreamAppLogs_MultiplexesRunningPods(t *testing.T) {
+	pending := workerPod("flux-c")
+	m := newLogStreamTestManager(t, runningWorkerPod("flux-a"), runningWorkerPod("flux-b"), pending)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines, err := m.StreamAppLogs(ctx, "flux", 100, "", "")
+	require.NoError(t, err)
+
+	pods := []string{receiveLine(t, lines).Pod, receiveLine(t, lines).Pod}
+	sort.Strings(pods)
+	assert.Equal(t, []string{"flux-a", "flux-b"}, pods, "pods without a running worker container are skipped")
+
+	cancel()
+	for range lines {
+	}
+}
+
+func TestStreamAppLogs_SinglePod(t *testing.T) {
+	m := newLogStreamTestManager(t, runningWorkerPod("flux-a"))
+
+	lines, err := m.StreamAppLogs(context.Background(), "flux", 0, "flux-a", "")
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.LogLine{Pod: "flux-a", Line: "fake logs"}, receiveLine(t, lines))
+
+	select {
+	case _, ok := <-lines:
+		assert.False(t, ok, "the stream closes when the pod log ends")
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed")
+	}
+}
+
+func TestStreamAppLogs_ForeignPod(t *testing.T) {
+	other := workerPod("sdxl-a")
+	other.Labels["app"] = "sdxl"
+	m := newLogStreamTestManager(t, other)
+
+	_, err := m.StreamAppLogs(context.Background(), "flux", 0, "sdxl-a", "")
+	assert.True(t, errors.Is(err, interfaces.ErrNotFound))
+
+	_, err = m.StreamAppLogs(context.Background(), "missing", 0, "", "")
+	assert.Error(t, err)
+}
@@ -31,9 +31,12 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/yaml"
 
+	"waverless/pkg/config"
 	"waverless/pkg/constants"
+	"waverless/pkg/dataplane"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
 )
@@ -71,20 +74,32 @@ func validateK8sName(name string) error {
 
 // Manager K8s application manager
 type Manager struct {
-	client        kubernetes.Interface
-	dynamicClient dynamic.Interface
-	config        *rest.Config
-	namespace     string
-	platform      Platform
-	specManager   *SpecManager
-	renderer      *TemplateRenderer
-	globalEnv     map[string]string
+	client         kubernetes.Interface
+	dynamicClient  dynamic.Interface
+	config         *rest.Config
+	namespace      string
+	platform       Platform
+	specManager    *SpecManager
+	renderer       *TemplateRenderer
+	globalEnv      map[string]string
+	imageRewriter  ImageRewriter
+	securityPolicy *SecurityPolicy
+	debugImage     string
+	debugMaxTTL    time.Duration
+	invokeSigner   *dataplane.Signer
+	runPodAPIBase  string
+	runPodAPIKey   string
+
+	specOverrideLimits SpecOverrideLimits
+	nodeLabelsCache    nodeLabelsCache
 
 	informerFactory  informers.SharedInformerFactory
+	informerOpts     InformerOptions
 	deploymentLister appslisters.DeploymentLister
 	podLister        corelisters.PodLister
 	informerStopCh   chan struct{}
 	stopOnce         sync.Once
+	queues           *eventQueues
 
 	callbacksMu                     sync.RWMutex
 	replicaCallbacks                map[int64]interfaces.ReplicaCallback
@@ -92,11 +107,40 @@ type Manager struct {
 	podDeleteCallbacks              map[int64]PodDeleteCallback
 	podStatusChangeCallbacks        map[int64]PodStatusChangeCallback
 	spotInterruptionCallbacks       map[int64]SpotInterruptionCallback
+	nodeMaintenanceCallbacks        map[int64]NodeMaintenanceCallback
 	deploymentSpecChangeCallbacks   map[int64]DeploymentSpecChangeCallback
 	deploymentStatusChangeCallbacks map[int64]DeploymentStatusChangeCallback
 	nextCallbackID                  int64
 }
 
+// ImageRewriter rewrites image references at render time (e.g. registry mirrors)
+type ImageRewriter interface {
+	RewriteImage(ctx context.Context, image string) string
+}
+
+// SetImageRewriter sets the image rewriter applied when rendering and updating deployments
+func (m *Manager) SetImageRewriter(rewriter ImageRewriter) {
+	m.imageRewriter = rewriter
+}
+
+// SetSecurityPolicy sets the policy gating privileged profiles and ptrace
+func (m *Manager) SetSecurityPolicy(policy *SecurityPolicy) {
+	m.securityPolicy = policy
+}
+
+// SetInvokeSigner enables per-endpoint invoke keys for direct worker requests
+func (m *Manager) SetInvokeSigner(signer *dataplane.Signer) {
+	m.invokeSigner = signer
+}
+
+// rewriteImage applies the configured image rewriter, if any
+func (m *Manager) rewriteImage(ctx context.Context, image string) string {
+	if m.imageRewriter == nil || image == "" {
+		return image
+	}
+	return m.imageRewriter.RewriteImage(ctx, image)
+}
+
 // PodTerminatingCallback is called when a pod is marked for deletion (DeletionTimestamp set)
 // This allows the system to drain workers before pods are actually terminated
 type PodTerminatingCallback func(podName, endpoint string)
@@ -120,7 +164,7 @@ type DeploymentSpecChangeCallback func(endpoint string)
 type DeploymentStatusChangeCallback func(endpoint string, deployment *appsv1.Deployment)
 
 // NewManager creates a K8s manager
-func NewManager(namespace, platformName, configDir string, globalEnv map[string]string) (*Manager, error) {
+func NewManager(namespace, platformName, configDir string, globalEnv map[string]string, informerOpts InformerOptions) (*Manager, error) {
 	// Create K8s client
 	var config *rest.Config
 	var err error
@@ -130,7 +174,7 @@ func NewManager(namespace, platformName, configDir string, globalEnv map[string]
 		// Running in cluster, use InClusterConfig
 		config, err = rest.InClusterConfig()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get in-cluster config: %v", err)
+			return nil, fmt.Errorf("failed to get in-cluster config: %w", err)
 		}
 	} else {
 		// Running outside cluster, use kubeconfig
@@ -139,26 +183,26 @@ func NewManager(namespace, platformName, configDir string, globalEnv map[string]
 		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
 		config, err = kubeConfig.ClientConfig()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get kubeconfig: %v", err)
+			return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
 		}
 	}
 
 	client, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes client: %v", err)
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
 	// Create dynamic client for CRDs (e.g., Karpenter NodeClaim)
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
 	// Create spec manager
 	specPath := fmt.Sprintf("%s/specs.yaml", configDir)
 	specManager, err := NewSpecManager(specPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create spec manager: %v", err)
+		return nil, fmt.Errorf("failed to create spec manager: %w", err)
 	}
 
 	// Create platform instance
@@ -169,14 +213,11 @@ func NewManager(namespace, platformName, configDir string, globalEnv map[string]
 	templateDir := fmt.Sprintf("%s/templates", configDir)
 	renderer := NewTemplateRenderer(templateDir)
 
-	// Setup shared informers for Deployments/Pods in the configured namespace
-	// Resync period: check for updates every 5 minutes
+	// Setup shared informers for Deployments/Pods in the configured namespace,
+	// scoped by label (and optionally pod fields) so shared namespaces stay cheap to cache
+	informerOpts = informerOpts.withDefaults()
 	stopCh := make(chan struct{})
-	informerFactory := informers.NewSharedInformerFactoryWithOptions(
-		client,
-		5*time.Minute, // Resync period
-		informers.WithNamespace(namespace),
-	)
+	informerFactory := newScopedInformerFactory(client, namespace, informerOpts)
 	deploymentInformer := informerFactory.Apps().V1().Deployments()
 	podInformer := informerFactory.Core().V1().Pods()
 
@@ -190,6 +231,7 @@ func NewManager(namespace, platformName, configDir string, globalEnv map[string]
 		renderer:                      renderer,
 		globalEnv:                     globalEnv,
 		informerFactory:               informerFactory,
+		informerOpts:                  informerOpts,
 		deploymentLister:              deploymentInformer.Lister(),
 		podLister:                     podInformer.Lister(),
 		informerStopCh:                stopCh,
@@ -198,31 +240,16 @@ func NewManager(namespace, platformName, configDir string, globalEnv map[string]
 		podDeleteCallbacks:            make(map[int64]PodDeleteCallback),
 		podStatusChangeCallbacks:      make(map[int64]PodStatusChangeCallback),
 		spotInterruptionCallbacks:     make(map[int64]SpotInterruptionCallback),
+		nodeMaintenanceCallbacks:      make(map[int64]NodeMaintenanceCallback),
 		deploymentSpecChangeCallbacks: make(map[int64]DeploymentSpecChangeCallback),
+		queues:                        newEventQueues(),
 	}
 
-	// Add event handlers to force informers to start watching
-	// Without handlers, informers may not actually watch in some environments (ASK/Virtual Kubelet)
-	deploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    manager.handleDeploymentEvent,
-		UpdateFunc: manager.handleDeploymentUpdate,
-		DeleteFunc: manager.handleDeploymentDelete,
-	})
-
-	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			logger.DebugCtx(context.Background(), "pod added (informer cache)")
-			manager.handlePodAdd(obj)
-		},
-		UpdateFunc: func(oldObj, newObj interface{}) {
-			logger.DebugCtx(context.Background(), "pod updated (informer cache)")
-			manager.handlePodUpdate(oldObj, newObj)
-		},
-		DeleteFunc: func(obj interface{}) {
-			logger.DebugCtx(context.Background(), "pod deleted (informer cache)")
-			manager.handlePodDelete(obj)
-		},
-	})
+	// Event handlers only enqueue keys; queue workers reconcile them against the cache.
+	// Handlers are also required to make informers watch at all in some environments (ASK/Virtual Kubelet)
+	deploymentInformer.Informer().AddEventHandler(manager.deploymentEventHandler())
+	podInformer.Informer().AddEventHandler(manager.podEventHandler())
+	manager.startEventWorkers()
 
 	// Start informers asynchronously (non-blocking mode)
 	// This is critical for ASK/Virtual Kubelet environments where initial sync may be slow
@@ -294,23 +321,40 @@ func NewManager(namespace, platformName, configDir string, globalEnv map[string]
 // DeployAppRequest deployment request (simplified version)
 type DeployAppRequest struct {
 	// Core variables (user input)
-	Endpoint        string                   `json:"endpoint" binding:"required"` // Endpoint name
-	SpecName        string                   `json:"specName" binding:"required"` // Spec name
-	Image           string                   `json:"image" binding:"required"`    // Image
-	ImagePrefix     string                   `json:"imagePrefix,omitempty"`       // Image prefix for matching updates (e.g., "wavespeed/model-deploy:wan_i2v-default-")
-	Replicas        int                      `json:"replicas,omitempty"`          // Replica count (default 1)
-	GpuCount        int                      `json:"gpuCount,omitempty"`          // GPU count (1-N, resources = per-gpu-config * gpuCount)
-	TaskTimeout     int                      `json:"taskTimeout,omitempty"`       // Task execution timeout in seconds (0 = use global default)
-	MaxPendingTasks int                      `json:"maxPendingTasks,omitempty"`   // Maximum allowed pending tasks before warning clients (default 1)
-	VolumeMounts    []interfaces.VolumeMount `json:"volumeMounts,omitempty"`      // PVC volume mounts
-	ShmSize         string                   `json:"shmSize,omitempty"`           // Shared memory size (e.g., "1Gi", "512Mi")
-	EnablePtrace    bool                     `json:"enablePtrace,omitempty"`      // Enable SYS_PTRACE capability for debugging (only for fixed resource pools)
-	ValidateImage   *bool                    `json:"validateImage,omitempty"`     // Whether to validate image before deployment (default: true)
-	Env             map[string]string        `json:"env,omitempty"`               // Custom environment variables
+	Endpoint         string                      `json:"endpoint" binding:"required"` // Endpoint name
+	Provider         string                      `json:"provider,omitempty"`          // Deployment provider (k8s, novita, runpod; default: providers.deployment), fixed once created
+	SpecName         string                      `json:"specName" binding:"required"` // Spec name
+	Image            string                      `json:"image,omitempty"`             // Image (required unless modelName is set)
+	ModelName        string                      `json:"modelName,omitempty"`         // Model registry model to resolve the image and env from
+	ModelVersion     string                      `json:"modelVersion,omitempty"`      // Model version (empty or "latest" = latest approved version)
+	ImagePrefix      string                      `json:"imagePrefix,omitempty"`       // Image prefix for matching updates (e.g., "wavespeed/model-deploy:wan_i2v-default-")
+	Replicas         int                         `json:"replicas,omitempty"`          // Replica count (default 1)
+	GpuCount         int                         `json:"gpuCount,omitempty"`          // GPU count (1-N, resources = per-gpu-config * gpuCount)
+	TaskTimeout      int                         `json:"taskTimeout,omitempty"`       // Task execution timeout in seconds (0 = use global default)
+	MaxPendingTasks  int                         `json:"maxPendingTasks,omitempty"`   // Maximum allowed pending tasks before warning clients (default 1)
+	VolumeMounts     []interfaces.VolumeMount    `json:"volumeMounts,omitempty"`      // PVC volume mounts
+	Storage          []interfaces.StorageRequest `json:"storage,omitempty"`           // PVCs to provision and mount (<endpoint>-<name>)
+	InitContainers   []interfaces.InitContainer  `json:"initContainers,omitempty"`    // Containers run before the worker starts (init-<name>)
+	Sidecars         []interfaces.Sidecar        `json:"sidecars,omitempty"`          // Containers run next to the worker (sidecar-<name>)
+	ShmSize          string                      `json:"shmSize,omitempty"`           // Shared memory size (e.g., "1Gi", "512Mi")
+	EphemeralStorage string                      `json:"ephemeralStorage,omitempty"`  // Ephemeral storage request and limit, overrides the spec (e.g., "100Gi", plain numbers are GB)
+	EnablePtrace     bool                        `json:"enablePtrace,omitempty"`      // Enable SYS_PTRACE capability for debugging (only for fixed resource pools)
+	ValidateImage    *bool                       `json:"validateImage,omitempty"`     // Whether to validate image before deployment (default: true)
+	CopyImage        *bool                       `json:"copyImage,omitempty"`         // Whether to copy the image into the internal registry (default: use config)
+	Env              map[string]string           `json:"env,omitempty"`               // Custom environment variables
+	Labels           map[string]string           `json:"labels,omitempty"`            // Endpoint labels (e.g. environment=prod)
+	RunPodCompat     *bool                       `json:"runpodCompat,omitempty"`      // Inject RunPod worker env vars and serve the RunPod API paths (default: true)
 
 	// Registry credential for private images
 	RegistryCredential *RegistryCredential `json:"registryCredential,omitempty"`
 
+	// Isolation for untrusted model containers
+	EgressPolicy             *interfaces.EgressPolicy `json:"egressPolicy,omitempty"`             // Render a NetworkPolicy denying egress except the allowlist
+	RestrictedServiceAccount bool                     `json:"restrictedServiceAccount,omitempty"` // Run workers under a dedicated service account without API token
+
+	// Resources added to the spec, bounded by k8s.spec_overrides (e.g. {"extraMemory": "16Gi"})
+	SpecOverride *interfaces.SpecOverride `json:"specOverride,omitempty"`
+
 	// Auto-scaling configuration (optional)
 	MinReplicas       int   `json:"minReplicas,omitempty"`       // Minimum replica count (default 0)
 	MaxReplicas       int   `json:"maxReplicas,omitempty"`       // Maximum replica count (default 10)
@@ -322,6 +366,10 @@ type DeployAppRequest struct {
 	EnableDynamicPrio *bool `json:"enableDynamicPrio,omitempty"` // Enable dynamic priority (default true)
 	HighLoadThreshold int   `json:"highLoadThreshold,omitempty"` // High load threshold for priority boost (default 10)
 	PriorityBoost     int   `json:"priorityBoost,omitempty"`     // Priority boost amount when high load (default 20)
+
+	// Resource profile ("latency", "throughput", "economy") expanded into the auto-scaling, queue and
+	// dispatch settings; fields set explicitly in the request override it
+	Profile string `json:"profile,omitempty"`
 }
 
 // RegistryCredential for private container registries
@@ -363,6 +411,41 @@ func (m *Manager) DeployApp(ctx context.Context, req *DeployAppRequest) error {
 	}
 	renderCtx.ImagePullSecret = imagePullSecretName
 
+	// Provisioned volumes must exist before pods mount them
+	if len(req.Storage) > 0 {
+		if err := m.provisionStorage(ctx, req.Endpoint, req.Storage); err != nil {
+			return err
+		}
+	}
+
+	// Invoke key secret must exist before pods reference it
+	if renderCtx.InvokeKeySecret != "" {
+		if err := m.applyInvokeKeySecret(ctx, req.Endpoint); err != nil {
+			return fmt.Errorf("failed to create invoke key secret: %w", err)
+		}
+	}
+
+	// Secret env vars are kept in their own Secret and survive redeploys
+	if err := m.addEnvSecretRefs(ctx, req.Endpoint, renderCtx); err != nil {
+		return err
+	}
+
+	// Isolation objects are applied before the Deployment so pods never start unrestricted
+	isolation, err := m.buildIsolationObjects(ctx, req)
+	if err != nil {
+		return err
+	}
+	if isolation.serviceAccount != nil {
+		if err := m.applyServiceAccount(ctx, isolation.serviceAccount); err != nil {
+			return fmt.Errorf("failed to apply worker service account: %w", err)
+		}
+	}
+	if isolation.networkPolicy != nil {
+		if err := m.applyNetworkPolicy(ctx, isolation.networkPolicy); err != nil {
+			return fmt.Errorf("failed to apply egress network policy: %w", err)
+		}
+	}
+
 	// Render Deployment template
 	yamlContent, err := m.renderer.Render("deployment.yaml", renderCtx)
 	if err != nil {
@@ -393,7 +476,7 @@ func (m *Manager) buildRenderContext(req *DeployAppRequest, spec *ResourceSpec)
 	ctx := &RenderContext{
 		Endpoint:      req.Endpoint,
 		Namespace:     m.namespace,
-		Image:         req.Image,
+		Image:         m.rewriteImage(context.Background(), req.Image),
 		Replicas:      req.Replicas,
 		ContainerName: fmt.Sprintf("%s-worker", req.Endpoint), // Or directly use endpoint name
 		ContainerPort: 8000,                                   // Default container port
@@ -414,6 +497,40 @@ func (m *Manager) buildRenderContext(req *DeployAppRequest, spec *ResourceSpec)
 		TaskTimeout: req.TaskTimeout,
 	}
 
+	// Spread replicas across nodes and zones (spec antiAffinity policy, default preferred)
+	antiAffinity, err := buildReplicaAntiAffinity(req.Endpoint, platformConfig.AntiAffinity)
+	if err != nil {
+		return nil, fmt.Errorf("invalid anti-affinity for spec %s: %w", spec.Name, err)
+	}
+
+	// GPU type fallbacks: pods may land on any GPU type of the chain, preferring earlier ones
+	chain, err := gpuTypeChain(spec, platformConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GPU type fallbacks for spec %s: %w", spec.Name, err)
+	}
+	var nodeAffinity *corev1.NodeAffinity
+	if len(chain) > 0 {
+		ctx.NodeSelector, nodeAffinity = buildGPUTypeScheduling(chain)
+		if ctx.GPUTypesJSON, err = gpuTypeChainJSON(chain); err != nil {
+			return nil, err
+		}
+	}
+
+	if antiAffinity != nil || nodeAffinity != nil {
+		affinityJSON, err := json.Marshal(&corev1.Affinity{PodAntiAffinity: antiAffinity, NodeAffinity: nodeAffinity})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal affinity: %w", err)
+		}
+		ctx.AffinityJSON = string(affinityJSON)
+	}
+
+	if req.RestrictedServiceAccount {
+		ctx.ServiceAccountName = workerServiceAccountName(req.Endpoint)
+	}
+	if m.invokeSigner != nil {
+		ctx.InvokeKeySecret = invokeKeySecretName(req.Endpoint)
+	}
+
 	// Inject spec name as label for tracking
 	if ctx.Labels == nil {
 		ctx.Labels = make(map[string]string)
@@ -475,6 +592,18 @@ func (m *Manager) buildRenderContext(req *DeployAppRequest, spec *ResourceSpec)
 		ctx.MemoryRequest = multiplyResource(spec.Resources.Memory, ctx.GpuCount)
 	}
 
+	// Per-endpoint spec override, added to the (scaled) spec resources
+	if err := m.specOverrideLimits.Validate(req.SpecOverride, req.ShmSize, req.EphemeralStorage); err != nil {
+		return nil, err
+	}
+	ctx.CpuLimit, ctx.MemoryRequest, err = applySpecOverride(ctx.CpuLimit, ctx.MemoryRequest, req.SpecOverride)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.SpecOverrideJSON, err = specOverrideJSON(req.SpecOverride); err != nil {
+		return nil, err
+	}
+
 	// Calculate termination grace period
 	// Formula: taskTimeout + 30s buffer for cleanup
 	// Default: 330s (300s task timeout + 30s buffer)
@@ -484,11 +613,17 @@ func (m *Manager) buildRenderContext(req *DeployAppRequest, spec *ResourceSpec)
 	}
 	ctx.TerminationGracePeriodSeconds = int64(taskTimeout + 30)
 
-	// Process volume mounts
-	if len(req.VolumeMounts) > 0 {
-		ctx.Volumes = make([]VolumeInfo, len(req.VolumeMounts))
-		ctx.VolumeMounts = make([]VolumeMountInfo, len(req.VolumeMounts))
-		for i, vm := range req.VolumeMounts {
+	// Process volume mounts (existing PVCs, then the PVCs provisioned from storage requests)
+	storage, err := normalizeStorageRequests(req.Endpoint, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	req.Storage = storage
+	volumeMounts := append(append([]interfaces.VolumeMount{}, req.VolumeMounts...), storageVolumeMounts(req.Endpoint, storage)...)
+	if len(volumeMounts) > 0 {
+		ctx.Volumes = make([]VolumeInfo, len(volumeMounts))
+		ctx.VolumeMounts = make([]VolumeMountInfo, len(volumeMounts))
+		for i, vm := range volumeMounts {
 			// Use pvc name as volume name (replace special chars to make it k8s-safe)
 			volumeName := fmt.Sprintf("pvc-%d", i)
 			ctx.Volumes[i] = VolumeInfo{
@@ -501,6 +636,12 @@ func (m *Manager) buildRenderContext(req *DeployAppRequest, spec *ResourceSpec)
 			}
 		}
 	}
+	if err := m.applyInitContainers(ctx, req.InitContainers, volumeMounts); err != nil {
+		return nil, err
+	}
+	if err := m.applySidecars(ctx, req.Sidecars, volumeMounts); err != nil {
+		return nil, err
+	}
 
 	// Shared memory size
 	// Priority: request.ShmSize > spec.ShmSize
@@ -510,16 +651,67 @@ func (m *Manager) buildRenderContext(req *DeployAppRequest, spec *ResourceSpec)
 		ctx.ShmSize = spec.Resources.ShmSize
 	}
 
+	// Ephemeral storage (model downloads, caches)
+	// Priority: request.EphemeralStorage > spec.EphemeralStorage
+	ctx.EphemeralStorage, err = resolveEphemeralStorage(req.EphemeralStorage, spec)
+	if err != nil {
+		return nil, err
+	}
+
 	// Enable ptrace capability (only for fixed resource pools)
 	ctx.EnablePtrace = req.EnablePtrace
 
-	// Environment variables: merge globalEnv with request env (request takes precedence)
+	// Security profile from the spec; privileged and ptrace need a policy exception
+	if err := m.securityPolicy.Authorize(req.Endpoint, requestedPrivileges(platformConfig.Security, req.EnablePtrace)...); err != nil {
+		return nil, err
+	}
+	securityContext, err := buildContainerSecurityContext(platformConfig.Security, req.EnablePtrace)
+	if err != nil {
+		return nil, fmt.Errorf("invalid security profile for spec %s: %w", spec.Name, err)
+	}
+
+	// Topology hints of multi-GPU specs (host network, IB devices, hugepages, NCCL env)
+	topology, err := resolveTopologyHints(platformConfig.Topology, ctx.CpuLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology hints for spec %s: %w", spec.Name, err)
+	}
+	ctx.HostNetwork = topology.hostNetwork
+	ctx.TopologyResources = topology.renderResources()
+	ctx.HugepagesMedium = string(topology.hugepagesMedium)
+	if topology.ipcLock {
+		if securityContext == nil {
+			securityContext = &corev1.SecurityContext{}
+		}
+		addCapability(securityContext, capabilityIPCLock)
+	}
+	if securityContext != nil {
+		scJSON, err := json.Marshal(securityContext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal security context: %w", err)
+		}
+		ctx.SecurityContextJSON = string(scJSON)
+	}
+
+	// Environment variables: merge globalEnv, the RunPod compatibility env and request env
+	// (request takes precedence). RUNPOD_* globalEnv entries only apply with the layer enabled.
+	ctx.RunPodCompat = runPodCompatEnabled(req.RunPodCompat)
 	ctx.Env = make(map[string]string)
 	for k, v := range m.globalEnv {
+		if strings.HasPrefix(k, runPodEnvPrefix) {
+			continue
+		}
 		// Replace placeholders in globalEnv values
 		v = strings.ReplaceAll(v, "{{.Endpoint}}", req.Endpoint)
 		ctx.Env[k] = v
 	}
+	if ctx.RunPodCompat {
+		for k, v := range m.runPodEnv(req.Endpoint, ctx.GpuCount) {
+			ctx.Env[k] = v
+		}
+	}
+	for k, v := range topology.env {
+		ctx.Env[k] = v
+	}
 	for k, v := range req.Env {
 		ctx.Env[k] = v
 	}
@@ -541,14 +733,14 @@ func (m *Manager) applyYAML(ctx context.Context, yamlContent string) error {
 		// Parse YAML to determine resource type
 		var meta metav1.TypeMeta
 		if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
-			return fmt.Errorf("failed to parse YAML: %v", err)
+			return fmt.Errorf("failed to parse YAML: %w", err)
 		}
 
 		switch meta.Kind {
 		case "Deployment":
 			var deployment appsv1.Deployment
 			if err := yaml.Unmarshal([]byte(doc), &deployment); err != nil {
-				return fmt.Errorf("failed to parse Deployment: %v", err)
+				return fmt.Errorf("failed to parse Deployment: %w", err)
 			}
 			if err := m.applyDeployment(ctx, &deployment); err != nil {
 				return err
@@ -609,13 +801,47 @@ func (m *Manager) createRegistrySecret(ctx context.Context, name string, cred *R
 	return err
 }
 
+// invokeKeySecretName returns the Secret holding an endpoint's invoke key
+func invokeKeySecretName(endpoint string) string {
+	return endpoint + "-invoke-key"
+}
+
+// applyInvokeKeySecret creates or updates the Secret holding the endpoint's derived invoke key
+func (m *Manager) applyInvokeKeySecret(ctx context.Context, endpoint string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      invokeKeySecretName(endpoint),
+			Namespace: m.namespace,
+			Labels:    map[string]string{"app": endpoint, "managed-by": "waverless"},
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			"invoke-key": m.invokeSigner.EndpointKeyHex(endpoint),
+		},
+	}
+
+	secrets := m.client.CoreV1().Secrets(m.namespace)
+	existing, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	}
+
+	secret.ResourceVersion = existing.ResourceVersion
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
 // applyDeployment applies Deployment
 func (m *Manager) applyDeployment(ctx context.Context, deployment *appsv1.Deployment) error {
 	deployments := m.client.AppsV1().Deployments(m.namespace)
 	existing, err := deployments.Get(ctx, deployment.Name, metav1.GetOptions{})
 	if err != nil {
 		if !errors.IsNotFound(err) {
-			return fmt.Errorf("failed to get Deployment: %v", err)
+			return fmt.Errorf("failed to get Deployment: %w", err)
 		}
 		// Create new
 		_, err = deployments.Create(ctx, deployment, metav1.CreateOptions{})
@@ -643,7 +869,17 @@ func (m *Manager) PreviewYAML(req *DeployAppRequest) (string, error) {
 	}
 
 	// Render template
-	return m.renderer.Render("deployment.yaml", renderCtx)
+	yamlContent, err := m.renderer.Render("deployment.yaml", renderCtx)
+	if err != nil {
+		return "", err
+	}
+
+	// Append isolation objects so the preview shows everything DeployApp would apply
+	isolation, err := m.buildIsolationObjects(context.Background(), req)
+	if err != nil {
+		return "", err
+	}
+	return appendObjectsYAML(yamlContent, isolation.objects())
 }
 
 // AppInfo application information
@@ -660,8 +896,12 @@ type AppInfo struct {
 	Image             string                   `json:"image"`
 	Labels            map[string]string        `json:"labels"`
 	CreatedAt         string                   `json:"createdAt"`
-	ShmSize           string                   `json:"shmSize,omitempty"`      // Shared memory size from deployment volumes
-	VolumeMounts      []interfaces.VolumeMount `json:"volumeMounts,omitempty"` // PVC volume mounts from deployment
+	ShmSize           string                   `json:"shmSize,omitempty"`          // Shared memory size from deployment volumes
+	EphemeralStorage  string                   `json:"ephemeralStorage,omitempty"` // Ephemeral storage limit of the worker container
+	SpecOverride      *interfaces.SpecOverride `json:"specOverride,omitempty"`     // Resources added to the spec (waverless.io/spec-override)
+	VolumeMounts      []interfaces.VolumeMount `json:"volumeMounts,omitempty"`     // PVC volume mounts from deployment
+	InitStatus        string                   `json:"initStatus,omitempty"`       // Init container progress of pending pods
+	RolloutComplete   bool                     `json:"rolloutComplete,omitempty"`  // Every replica runs the latest spec and is available
 }
 
 // GetApp gets application details
@@ -669,9 +909,11 @@ func (m *Manager) GetApp(ctx context.Context, name string) (*AppInfo, error) {
 	// Try cache (Deployment)
 	if m.deploymentLister != nil {
 		if deployment, err := m.deploymentLister.Deployments(m.namespace).Get(name); err == nil {
-			return deploymentToAppInfo(deployment), nil
+			info := deploymentToAppInfo(deployment)
+			m.setInitStatus(info, deployment)
+			return info, nil
 		} else if !errors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to get deployment from cache: %v", err)
+			return nil, fmt.Errorf("failed to get deployment from cache: %w", err)
 		}
 	}
 
@@ -680,15 +922,17 @@ func (m *Manager) GetApp(ctx context.Context, name string) (*AppInfo, error) {
 		if pod, err := m.podLister.Pods(m.namespace).Get(name); err == nil {
 			return podToAppInfo(pod), nil
 		} else if !errors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to get pod from cache: %v", err)
+			return nil, fmt.Errorf("failed to get pod from cache: %w", err)
 		}
 	}
 
 	// As a last resort, query API server directly (handles newly created resources before cache sync)
 	if deploymentLive, err := m.client.AppsV1().Deployments(m.namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
-		return deploymentToAppInfo(deploymentLive), nil
+		info := deploymentToAppInfo(deploymentLive)
+		m.setInitStatus(info, deploymentLive)
+		return info, nil
 	} else if !errors.IsNotFound(err) {
-		return nil, fmt.Errorf("failed to get deployment: %v", err)
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
 	}
 
 	podLive, podErr := m.client.CoreV1().Pods(m.namespace).Get(ctx, name, metav1.GetOptions{})
@@ -696,7 +940,7 @@ func (m *Manager) GetApp(ctx context.Context, name string) (*AppInfo, error) {
 		return podToAppInfo(podLive), nil
 	}
 
-	return nil, fmt.Errorf("failed to get pod: %v", podErr)
+	return nil, fmt.Errorf("failed to get pod: %w", podErr)
 }
 
 // ListApps lists all applications (only those managed by Waverless)
@@ -714,7 +958,9 @@ func (m *Manager) ListApps(ctx context.Context) ([]*AppInfo, error) {
 		if m.informerFactory.Apps().V1().Deployments().Informer().HasSynced() {
 			if deployments, err := m.deploymentLister.Deployments(m.namespace).List(selector); err == nil {
 				for _, deployment := range deployments {
-					result = append(result, deploymentToAppInfo(deployment))
+					info := deploymentToAppInfo(deployment)
+					m.setInitStatus(info, deployment)
+					result = append(result, info)
 				}
 				useCache = true
 				logger.DebugCtx(ctx, "listed deployments from informer cache")
@@ -808,6 +1054,9 @@ func (m *Manager) Close() {
 	m.stopOnce.Do(func() {
 		close(m.informerStopCh)
 		m.informerStopCh = nil
+		if m.queues != nil {
+			m.queues.shutDown()
+		}
 	})
 }
 
@@ -1005,7 +1254,7 @@ func (m *Manager) UnregisterDeploymentStatusChangeCallback(id int64) {
 }
 
 // notifyDeploymentStatusChange notifies all registered callbacks that a deployment status has changed
-func (m *Manager) notifyDeploymentStatusChange(endpoint string, deployment *appsv1.Deployment) {
+func (m *Manager) notifyDeploymentStatusChange(endpoint string, deployment *appsv1.Deployment, eventAt time.Time) {
 	m.callbacksMu.RLock()
 	callbacks := make([]DeploymentStatusChangeCallback, 0, len(m.deploymentStatusChangeCallbacks))
 	for _, cb := range m.deploymentStatusChangeCallbacks {
@@ -1015,19 +1264,12 @@ func (m *Manager) notifyDeploymentStatusChange(endpoint string, deployment *apps
 
 	for _, cb := range callbacks {
 		cb := cb
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-					logger.ErrorCtx(context.Background(), "Deployment status change callback panicked: %v", r)
-				}
-			}()
-			cb(endpoint, deployment)
-		}()
+		runCallback(callbackDeploymentStatus, eventAt, func() { cb(endpoint, deployment) })
 	}
 }
 
 // notifyDeploymentSpecChange notifies all registered callbacks that a deployment spec has changed
-func (m *Manager) notifyDeploymentSpecChange(endpoint string) {
+func (m *Manager) notifyDeploymentSpecChange(endpoint string, eventAt time.Time) {
 	m.callbacksMu.RLock()
 	callbacks := make([]DeploymentSpecChangeCallback, 0, len(m.deploymentSpecChangeCallbacks))
 	for _, cb := range m.deploymentSpecChangeCallbacks {
@@ -1038,31 +1280,52 @@ func (m *Manager) notifyDeploymentSpecChange(endpoint string) {
 	// Fan out asynchronously to avoid blocking informer thread
 	for _, cb := range callbacks {
 		cb := cb // capture for goroutine
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-					logger.ErrorCtx(context.Background(), "Deployment spec change callback panicked: %v", r)
-				}
-			}()
-			cb(endpoint)
-		}()
+		runCallback(callbackDeploymentSpec, eventAt, func() { cb(endpoint) })
 	}
 }
 
-// handlePodAdd handles pod add events and creates worker record
-func (m *Manager) handlePodAdd(obj interface{}) {
-	pod, ok := obj.(*corev1.Pod)
-	if !ok || pod == nil {
-		return
+// ReplayWatchState re-delivers the current informer cache to the registered callbacks:
+// deployment status for every managed deployment, status for every worker pod and
+// terminating notifications for pods already marked for deletion.
+// It lets a replica that just became leader catch up on events it ignored as a follower.
+func (m *Manager) ReplayWatchState(ctx context.Context) error {
+	if m.informerFactory == nil || m.podLister == nil || m.deploymentLister == nil {
+		return fmt.Errorf("informers not initialized")
+	}
+	deploymentsSynced := m.informerFactory.Apps().V1().Deployments().Informer().HasSynced
+	podsSynced := m.informerFactory.Core().V1().Pods().Informer().HasSynced
+	if !cache.WaitForCacheSync(ctx.Done(), deploymentsSynced, podsSynced) {
+		return fmt.Errorf("informer cache not synced")
 	}
+	// Latency of replayed callbacks is measured from the replay
+	replayedAt := time.Now()
 
-	endpoint := GetPodEndpoint(pod)
-	if endpoint == "" || !m.isManagedWorkerPod(pod) {
-		return
+	selector := labels.SelectorFromSet(labels.Set{"managed-by": "waverless"})
+	deployments, err := m.deploymentLister.Deployments(m.namespace).List(selector)
+	if err != nil {
+		return fmt.Errorf("failed to list deployments from cache: %w", err)
+	}
+	for _, deployment := range deployments {
+		m.syncDeploymentStatus(deployment, replayedAt)
 	}
 
-	podInfo := m.podToPodInfo(pod)
-	m.notifyPodStatusChange(pod.Name, endpoint, podInfo)
+	pods, err := m.podLister.Pods(m.namespace).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list pods from cache: %w", err)
+	}
+	for _, pod := range pods {
+		endpoint := GetPodEndpoint(pod)
+		if endpoint == "" || !m.isManagedWorkerPod(pod) {
+			continue
+		}
+		if pod.DeletionTimestamp != nil {
+			m.notifyPodTerminating(pod.Name, endpoint, replayedAt)
+		}
+		m.notifyPodStatusChange(pod.Name, endpoint, m.podToPodInfo(pod), replayedAt)
+	}
+
+	logger.InfoCtx(ctx, "replayed watch state: %d deployments, %d pods", len(deployments), len(pods))
+	return nil
 }
 
 // isManagedWorkerPod checks if pod is a managed worker pod (not the waverless service itself)
@@ -1075,24 +1338,8 @@ func (m *Manager) isManagedWorkerPod(pod *corev1.Pod) bool {
 	return endpoint != "" && managedBy == constants.ManagedByWaverless && endpoint != "waverless"
 }
 
-// handlePodDelete handles pod delete events
-func (m *Manager) handlePodDelete(obj interface{}) {
-	pod, ok := obj.(*corev1.Pod)
-	if !ok || pod == nil {
-		return
-	}
-
-	endpoint := GetPodEndpoint(pod)
-	if endpoint == "" || !m.isManagedWorkerPod(pod) {
-		return
-	}
-
-	logger.InfoCtx(context.Background(), "🗑️ Pod %s (endpoint: %s) deleted", pod.Name, endpoint)
-	m.notifyPodDelete(pod.Name, endpoint)
-}
-
 // notifyPodDelete notifies all registered callbacks about pod deletion
-func (m *Manager) notifyPodDelete(podName, endpoint string) {
+func (m *Manager) notifyPodDelete(podName, endpoint string, eventAt time.Time) {
 	m.callbacksMu.RLock()
 	callbacks := make([]PodDeleteCallback, 0, len(m.podDeleteCallbacks))
 	for _, cb := range m.podDeleteCallbacks {
@@ -1102,56 +1349,12 @@ func (m *Manager) notifyPodDelete(podName, endpoint string) {
 
 	for _, cb := range callbacks {
 		cb := cb
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-					logger.ErrorCtx(context.Background(), "Pod delete callback panicked: %v", r)
-				}
-			}()
-			cb(podName, endpoint)
-		}()
-	}
-}
-
-// handlePodUpdate handles pod update events and detects when pods are marked for deletion
-func (m *Manager) handlePodUpdate(oldObj, newObj interface{}) {
-	oldPod, oldOk := oldObj.(*corev1.Pod)
-	newPod, newOk := newObj.(*corev1.Pod)
-
-	if !oldOk || !newOk || oldPod == nil || newPod == nil {
-		return
-	}
-
-	// Extract pod info
-	podName := newPod.Name
-	endpoint := GetPodEndpoint(newPod)
-
-	// Only handle pods managed by waverless (worker pods)
-	if endpoint == "" || !m.isManagedWorkerPod(newPod) {
-		return
+		runCallback(callbackPodDelete, eventAt, func() { cb(podName, endpoint) })
 	}
-
-	// 1. Check for Spot interruption FIRST (before pod termination)
-	if detected, reason := m.detectSpotInterruption(newPod); detected {
-		logger.WarnCtx(context.Background(), "🚨 Spot interruption detected for pod %s (endpoint: %s): %s",
-			podName, endpoint, reason)
-		m.notifySpotInterruption(podName, endpoint, reason)
-	}
-
-	// 2. Detect when a pod is marked for deletion
-	if oldPod.DeletionTimestamp == nil && newPod.DeletionTimestamp != nil {
-		logger.InfoCtx(context.Background(), "🔔 Pod %s (endpoint: %s) marked for deletion, notifying callbacks",
-			podName, endpoint)
-		m.notifyPodTerminating(podName, endpoint)
-	}
-
-	// 3. Notify pod status change (for worker runtime state sync)
-	podInfo := m.podToPodInfo(newPod)
-	m.notifyPodStatusChange(podName, endpoint, podInfo)
 }
 
 // notifyPodTerminating notifies all registered callbacks that a pod is terminating
-func (m *Manager) notifyPodTerminating(podName, endpoint string) {
+func (m *Manager) notifyPodTerminating(podName, endpoint string, eventAt time.Time) {
 	m.callbacksMu.RLock()
 	callbacks := make([]PodTerminatingCallback, 0, len(m.podTerminatingCallbacks))
 	for _, cb := range m.podTerminatingCallbacks {
@@ -1162,19 +1365,12 @@ func (m *Manager) notifyPodTerminating(podName, endpoint string) {
 	// Fan out asynchronously to avoid blocking informer thread
 	for _, cb := range callbacks {
 		cb := cb // capture for goroutine
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-					logger.ErrorCtx(context.Background(), "Pod terminating callback panicked: %v", r)
-				}
-			}()
-			cb(podName, endpoint)
-		}()
+		runCallback(callbackPodTerminating, eventAt, func() { cb(podName, endpoint) })
 	}
 }
 
 // notifyPodStatusChange notifies all registered callbacks about pod status change
-func (m *Manager) notifyPodStatusChange(podName, endpoint string, info *interfaces.PodInfo) {
+func (m *Manager) notifyPodStatusChange(podName, endpoint string, info *interfaces.PodInfo, eventAt time.Time) {
 	m.callbacksMu.RLock()
 	callbacks := make([]PodStatusChangeCallback, 0, len(m.podStatusChangeCallbacks))
 	for _, cb := range m.podStatusChangeCallbacks {
@@ -1184,25 +1380,23 @@ func (m *Manager) notifyPodStatusChange(podName, endpoint string, info *interfac
 
 	for _, cb := range callbacks {
 		cb := cb
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-					logger.ErrorCtx(context.Background(), "Pod status change callback panicked: %v", r)
-				}
-			}()
-			cb(podName, endpoint, info)
-		}()
+		runCallback(callbackPodStatus, eventAt, func() { cb(podName, endpoint, info) })
 	}
 }
 
 // podToPodInfo converts a K8s Pod to PodInfo
 func (m *Manager) podToPodInfo(pod *corev1.Pod) *interfaces.PodInfo {
 	info := &interfaces.PodInfo{
-		Name:      pod.Name,
-		Phase:     string(pod.Status.Phase),
-		IP:        pod.Status.PodIP,
-		NodeName:  pod.Spec.NodeName,
-		CreatedAt: pod.CreationTimestamp.Format(time.RFC3339),
+		Name:        pod.Name,
+		Phase:       string(pod.Status.Phase),
+		IP:          pod.Status.PodIP,
+		NodeName:    pod.Spec.NodeName,
+		GPUType:     m.podGPUType(pod),
+		ImageDigest: workerImageDigest(pod),
+		CreatedAt:   pod.CreationTimestamp.Format(time.RFC3339),
+
+		PodReason:  pod.Status.Reason,
+		PodMessage: pod.Status.Message,
 	}
 	if pod.DeletionTimestamp != nil {
 		info.DeletionTimestamp = pod.DeletionTimestamp.Format(time.RFC3339)
@@ -1221,6 +1415,13 @@ func (m *Manager) podToPodInfo(pod *corev1.Pod) *interfaces.PodInfo {
 			info.Status = "Terminated"
 			info.Reason = cs.State.Terminated.Reason
 			info.Message = cs.State.Terminated.Message
+			info.ExitCode = cs.State.Terminated.ExitCode
+		}
+		// Keep why the previous instance died, CrashLoopBackOff hides it otherwise
+		if last := cs.LastTerminationState.Terminated; last != nil {
+			info.LastTerminationReason = last.Reason
+			info.LastTerminationMessage = last.Message
+			info.LastExitCode = last.ExitCode
 		}
 	}
 	// Check conditions for Ready
@@ -1230,6 +1431,11 @@ func (m *Manager) podToPodInfo(pod *corev1.Pod) *interfaces.PodInfo {
 			info.Message = "All containers are ready"
 		}
 	}
+	// Node maintenance explains the termination better than any container state
+	if nm := detectNodeMaintenance(pod); nm != nil {
+		info.Reason = PodReasonNodeMaintenance
+		info.Message = nm.Reason()
+	}
 	return info
 }
 
@@ -1242,7 +1448,7 @@ func (m *Manager) detectSpotInterruption(pod *corev1.Pod) (bool, string) {
 }
 
 // notifySpotInterruption notifies all registered callbacks about spot interruption
-func (m *Manager) notifySpotInterruption(podName, endpoint, reason string) {
+func (m *Manager) notifySpotInterruption(podName, endpoint, reason string, eventAt time.Time) {
 	m.callbacksMu.RLock()
 	callbacks := make([]SpotInterruptionCallback, 0, len(m.spotInterruptionCallbacks))
 	for _, cb := range m.spotInterruptionCallbacks {
@@ -1253,66 +1459,11 @@ func (m *Manager) notifySpotInterruption(podName, endpoint, reason string) {
 	// Fan out asynchronously
 	for _, cb := range callbacks {
 		cb := cb
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-					logger.ErrorCtx(context.Background(), "spot interruption callback panic: %v", r)
-				}
-			}()
-			cb(podName, endpoint, reason)
-		}()
-	}
-}
-
-func (m *Manager) handleDeploymentEvent(obj interface{}) {
-	deployment, ok := obj.(*appsv1.Deployment)
-	if !ok || deployment == nil {
-		return
-	}
-	m.emitReplicaChange(buildReplicaEvent(deployment))
-	m.syncDeploymentStatus(deployment)
-}
-
-func (m *Manager) handleDeploymentUpdate(oldObj, newObj interface{}) {
-	oldDep, oldOk := oldObj.(*appsv1.Deployment)
-	newDep, newOk := newObj.(*appsv1.Deployment)
-
-	if !oldOk || !newOk || oldDep == nil || newDep == nil {
-		return
-	}
-
-	// Always emit replica change event
-	m.emitReplicaChange(buildReplicaEvent(newDep))
-
-	// Extract endpoint name from deployment labels
-	endpoint := ""
-	managedBy := ""
-	if newDep.Labels != nil {
-		endpoint = newDep.Labels["app"]
-		managedBy = newDep.Labels["managed-by"]
-	}
-
-	// Only handle deployments managed by waverless (worker deployments)
-	if endpoint == "" || managedBy != "waverless" || endpoint == "waverless" {
-		return
-	}
-
-	// Notify status change if replicas changed
-	if oldDep.Status.ReadyReplicas != newDep.Status.ReadyReplicas ||
-		oldDep.Status.AvailableReplicas != newDep.Status.AvailableReplicas ||
-		*oldDep.Spec.Replicas != *newDep.Spec.Replicas {
-		m.syncDeploymentStatus(newDep)
-	}
-
-	// Detect spec changes that trigger pod recreation (image, resources, env, etc.)
-	if m.hasSpecChanged(oldDep, newDep) {
-		logger.InfoCtx(context.Background(), "🔄 Deployment %s (endpoint: %s) spec changed, triggering optimized rolling update",
-			newDep.Name, endpoint)
-		m.notifyDeploymentSpecChange(endpoint)
+		runCallback(callbackSpotInterruption, eventAt, func() { cb(podName, endpoint, reason) })
 	}
 }
 
-func (m *Manager) syncDeploymentStatus(deployment *appsv1.Deployment) {
+func (m *Manager) syncDeploymentStatus(deployment *appsv1.Deployment, eventAt time.Time) {
 	endpoint := ""
 	managedBy := ""
 	if deployment.Labels != nil {
@@ -1322,32 +1473,7 @@ func (m *Manager) syncDeploymentStatus(deployment *appsv1.Deployment) {
 	if endpoint == "" || managedBy != "waverless" || endpoint == "waverless" {
 		return
 	}
-	m.notifyDeploymentStatusChange(endpoint, deployment)
-}
-
-func (m *Manager) handleDeploymentDelete(obj interface{}) {
-	switch v := obj.(type) {
-	case *appsv1.Deployment:
-		if v != nil {
-			m.emitReplicaChange(interfaces.ReplicaEvent{
-				Name:              v.Name,
-				DesiredReplicas:   0,
-				ReadyReplicas:     0,
-				AvailableReplicas: 0,
-				Conditions:        deletedCondition("Deleted"),
-			})
-		}
-	case cache.DeletedFinalStateUnknown:
-		if dep, ok := v.Obj.(*appsv1.Deployment); ok && dep != nil {
-			m.emitReplicaChange(interfaces.ReplicaEvent{
-				Name:              dep.Name,
-				DesiredReplicas:   0,
-				ReadyReplicas:     0,
-				AvailableReplicas: 0,
-				Conditions:        deletedCondition("Deleted"),
-			})
-		}
-	}
+	m.notifyDeploymentStatusChange(endpoint, deployment, eventAt)
 }
 
 // hasSpecChanged checks if deployment spec has changed in ways that trigger pod recreation
@@ -1433,7 +1559,7 @@ func deletedCondition(reason string) []interfaces.ReplicaCondition {
 	}
 }
 
-func (m *Manager) emitReplicaChange(event interfaces.ReplicaEvent) {
+func (m *Manager) emitReplicaChange(event interfaces.ReplicaEvent, eventAt time.Time) {
 	m.callbacksMu.RLock()
 	if len(m.replicaCallbacks) == 0 {
 		m.callbacksMu.RUnlock()
@@ -1446,8 +1572,9 @@ func (m *Manager) emitReplicaChange(event interfaces.ReplicaEvent) {
 	m.callbacksMu.RUnlock()
 
 	for _, cb := range callbacks {
+		cb := cb
 		// fan out asynchronously to avoid blocking informer thread
-		go cb(event)
+		runCallback(callbackReplica, eventAt, func() { cb(event) })
 	}
 }
 
@@ -1501,12 +1628,22 @@ func deploymentToAppInfo(deployment *appsv1.Deployment) *AppInfo {
 		AvailableReplicas: deployment.Status.AvailableReplicas,
 		Labels:            deployment.Labels,
 		CreatedAt:         deployment.CreationTimestamp.Format(time.RFC3339),
+		SpecOverride:      specOverrideOf(deployment),
 	}
 
 	if len(deployment.Spec.Template.Spec.Containers) > 0 {
 		info.Image = deployment.Spec.Template.Spec.Containers[0].Image
+		if limit, ok := deployment.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceEphemeralStorage]; ok {
+			info.EphemeralStorage = limit.String()
+		}
 	}
 
+	// Same check as kubectl rollout status
+	info.RolloutComplete = deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == *deployment.Spec.Replicas &&
+		deployment.Status.Replicas == deployment.Status.UpdatedReplicas &&
+		deployment.Status.AvailableReplicas == deployment.Status.UpdatedReplicas
+
 	if *deployment.Spec.Replicas == 0 {
 		info.Status = "Stopped"
 	} else if deployment.Status.AvailableReplicas == *deployment.Spec.Replicas {
@@ -1577,7 +1714,7 @@ func (m *Manager) DeleteApp(ctx context.Context, name string) error {
 	// Delete Deployment (which will automatically delete managed Pods)
 	err := m.client.AppsV1().Deployments(m.namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("failed to delete deployment: %v", err)
+		return fmt.Errorf("failed to delete deployment: %w", err)
 	}
 
 	// Try to delete Service (if exists)
@@ -1594,17 +1731,53 @@ func (m *Manager) DeleteApp(ctx context.Context, name string) error {
 		fmt.Printf("Warning: failed to delete registry secret %s: %v\n", secretName, err)
 	}
 
+	// Try to delete invoke key secret (if exists)
+	invokeSecretName := invokeKeySecretName(name)
+	err = m.client.CoreV1().Secrets(m.namespace).Delete(ctx, invokeSecretName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		fmt.Printf("Warning: failed to delete invoke key secret %s: %v\n", invokeSecretName, err)
+	}
+
+	// Try to delete env secret (if exists)
+	envSecret := envSecretName(name)
+	err = m.client.CoreV1().Secrets(m.namespace).Delete(ctx, envSecret, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		fmt.Printf("Warning: failed to delete env secret %s: %v\n", envSecret, err)
+	}
+
+	// Try to delete isolation objects (if exist)
+	policyName := egressPolicyName(name)
+	err = m.client.NetworkingV1().NetworkPolicies(m.namespace).Delete(ctx, policyName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		fmt.Printf("Warning: failed to delete network policy %s: %v\n", policyName, err)
+	}
+	serviceAccountName := workerServiceAccountName(name)
+	err = m.client.CoreV1().ServiceAccounts(m.namespace).Delete(ctx, serviceAccountName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		fmt.Printf("Warning: failed to delete service account %s: %v\n", serviceAccountName, err)
+	}
+
+	// Delete provisioned PVCs with the delete policy (retained ones are kept)
+	if err := m.releaseStorage(ctx, name); err != nil {
+		fmt.Printf("Warning: failed to release storage of %s: %v\n", name, err)
+	}
+
 	return nil
 }
 
 // GetAppLogs gets application logs
 func (m *Manager) GetAppLogs(ctx context.Context, name string, tailLines int64, specificPodName ...string) (string, error) {
 	var podName string
+	if len(specificPodName) > 0 {
+		podName = specificPodName[0]
+	}
+	return m.GetContainerLogs(ctx, name, tailLines, podName, "")
+}
 
+// GetContainerLogs gets the logs of a container of an endpoint pod (container empty = worker)
+func (m *Manager) GetContainerLogs(ctx context.Context, name string, tailLines int64, podName, container string) (string, error) {
 	// If specific pod name is provided, use it directly
-	if len(specificPodName) > 0 && specificPodName[0] != "" {
-		podName = specificPodName[0]
-	} else {
+	if podName == "" {
 		// Check if it's a Deployment first (use Informer cache)
 		deployment, err := m.deploymentLister.Deployments(m.namespace).Get(name)
 		if err == nil {
@@ -1612,22 +1785,25 @@ func (m *Manager) GetAppLogs(ctx context.Context, name string, tailLines int64,
 			selector := labels.SelectorFromSet(labels.Set{"app": deployment.Name})
 			pods, err := m.podLister.Pods(m.namespace).List(selector)
 			if err != nil {
-				return "", fmt.Errorf("failed to list pods for deployment: %v", err)
+				return "", fmt.Errorf("failed to list pods for deployment: %w", err)
 			}
 			if len(pods) == 0 {
 				return "", fmt.Errorf("no pods found for deployment %s", name)
 			}
 			podName = pods[0].Name
 		} else if !errors.IsNotFound(err) {
-			return "", fmt.Errorf("failed to get deployment: %v", err)
+			return "", fmt.Errorf("failed to get deployment: %w", err)
 		} else {
 			// Not a Deployment, use name directly as Pod name
 			podName = name
 		}
 	}
 
-	// Get Pod logs - specify container name as {endpoint}-worker
-	containerName := fmt.Sprintf("%s-worker", name)
+	// Get Pod logs - the worker container is named {endpoint}-worker
+	containerName := container
+	if containerName == "" {
+		containerName = fmt.Sprintf("%s-worker", name)
+	}
 	logReq := m.client.CoreV1().Pods(m.namespace).GetLogs(podName, &corev1.PodLogOptions{
 		Container: containerName,
 		TailLines: &tailLines,
@@ -1635,14 +1811,14 @@ func (m *Manager) GetAppLogs(ctx context.Context, name string, tailLines int64,
 
 	logs, err := logReq.Stream(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to get pod logs: %v", err)
+		return "", fmt.Errorf("failed to get pod logs: %w", err)
 	}
 	defer logs.Close()
 
 	buf := make([]byte, 1024*1024) // 1MB buffer
 	n, err := io.ReadFull(logs, buf)
 	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
-		return "", fmt.Errorf("failed to read pod logs: %v", err)
+		return "", fmt.Errorf("failed to read pod logs: %w", err)
 	}
 
 	return string(buf[:n]), nil
@@ -1710,6 +1886,11 @@ func (m *Manager) GetPodCountsBySpec(ctx context.Context) (map[string]PodCounts,
 
 // getSpecFromPod 从 Pod 获取对应的 spec 名称
 func (m *Manager) getSpecFromPod(pod *corev1.Pod) string {
+	// Pods carry their spec as a label; the nodepool may have moved into the GPU type node affinity
+	if specName := pod.Labels["waverless.io/spec"]; specName != "" {
+		return specName
+	}
+
 	if pod.Spec.NodeSelector == nil {
 		return ""
 	}
@@ -1777,21 +1958,65 @@ func (m *Manager) GetInstanceTypesFromNodePool(ctx context.Context, nodePoolName
 }
 
 // UpdateDeployment updates deployment
-func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specName string, image string, replicas *int, volumeMounts *[]interfaces.VolumeMount, shmSize *string, enablePtrace *bool, env *map[string]string) error {
+func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specName string, image string, replicas *int, volumeMounts *[]interfaces.VolumeMount, shmSize *string, ephemeralStorage *string, enablePtrace *bool, env *map[string]string, runPodCompat *bool) error {
 	deployments := m.client.AppsV1().Deployments(m.namespace)
 
 	// Get existing deployment
 	deployment, err := deployments.Get(ctx, endpoint, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to get deployment: %v", err)
+		return fmt.Errorf("failed to get deployment: %w", err)
 	}
 
-	// Update spec if provided
+	if err := m.applyDeploymentUpdate(ctx, deployment, endpoint, specName, image, replicas, volumeMounts, shmSize, ephemeralStorage, enablePtrace, env, runPodCompat); err != nil {
+		return err
+	}
+
+	// Update deployment
+	_, err = deployments.Update(ctx, deployment, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update deployment: %w", err)
+	}
+
+	return nil
+}
+
+// applyDeploymentUpdate applies the fields set in an update request to deployment in place
+func (m *Manager) applyDeploymentUpdate(ctx context.Context, deployment *appsv1.Deployment, endpoint string, specName string, image string, replicas *int, volumeMounts *[]interfaces.VolumeMount, shmSize *string, ephemeralStorage *string, enablePtrace *bool, env *map[string]string, runPodCompat *bool) error {
+	var err error
+
+	// Privileges requested by this update must be granted before anything changes
+	var privileges []string
+	if enablePtrace != nil && *enablePtrace {
+		privileges = append(privileges, config.PrivilegePtrace)
+	}
+	var newSpec *ResourceSpec
 	if specName != "" {
-		spec, err := m.specManager.GetSpec(specName)
+		newSpec, err = m.specManager.GetSpec(specName)
 		if err != nil {
 			return fmt.Errorf("failed to get spec %s: %v", specName, err)
 		}
+		privileges = append(privileges, requestedPrivileges(newSpec.GetPlatformConfig(m.platform.GetName()).Security, false)...)
+	}
+	if err := m.securityPolicy.Authorize(endpoint, privileges...); err != nil {
+		return err
+	}
+
+	// shmSize and ephemeralStorage set by this update must be within the spec override limits
+	var newShmSize, newEphemeralStorage string
+	if shmSize != nil {
+		newShmSize = *shmSize
+	}
+	if ephemeralStorage != nil {
+		newEphemeralStorage = *ephemeralStorage
+	}
+	if err := m.specOverrideLimits.Validate(nil, newShmSize, newEphemeralStorage); err != nil {
+		return err
+	}
+
+	// Update spec if provided
+	if specName != "" {
+		spec := newSpec
+		previousSpecName := deployment.Spec.Template.Labels["waverless.io/spec"]
 
 		if len(deployment.Spec.Template.Spec.Containers) > 0 {
 			// Build ResourceRequirements from SpecResources
@@ -1800,16 +2025,22 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 				Limits:   corev1.ResourceList{},
 			}
 
+			// The deployment's spec override is kept on the new spec
+			cpu, memory, err := applySpecOverride(spec.Resources.CPU, spec.Resources.Memory, specOverrideOf(deployment))
+			if err != nil {
+				return err
+			}
+
 			// Memory is always set
-			if spec.Resources.Memory != "" {
-				memoryQuantity := resource.MustParse(spec.Resources.Memory)
+			if memory != "" {
+				memoryQuantity := resource.MustParse(memory)
 				resources.Requests[corev1.ResourceMemory] = memoryQuantity
 				resources.Limits[corev1.ResourceMemory] = memoryQuantity
 			}
 
 			// CPU is optional (empty means unlimited)
-			if spec.Resources.CPU != "" {
-				cpuQuantity := resource.MustParse(spec.Resources.CPU)
+			if cpu != "" {
+				cpuQuantity := resource.MustParse(cpu)
 				resources.Requests[corev1.ResourceCPU] = cpuQuantity
 				resources.Limits[corev1.ResourceCPU] = cpuQuantity
 			}
@@ -1824,6 +2055,15 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 			// Update container resources
 			deployment.Spec.Template.Spec.Containers[0].Resources = resources
 
+			// Ephemeral storage of the new spec (an override in this update is applied below)
+			specStorage, err := resolveEphemeralStorage("", spec)
+			if err != nil {
+				return err
+			}
+			if err := applyEphemeralStorage(&deployment.Spec.Template.Spec.Containers[0], specStorage); err != nil {
+				return err
+			}
+
 			// Update spec label
 			if deployment.Spec.Template.Labels == nil {
 				deployment.Spec.Template.Labels = make(map[string]string)
@@ -1833,6 +2073,37 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 			// Apply platform-specific configuration (Tolerations, NodeSelector, Labels, Annotations)
 			platformConfig := spec.GetPlatformConfig(m.platform.GetName())
 
+			// 0. Replace the container security profile (capabilities such as SYS_PTRACE are kept)
+			container := &deployment.Spec.Template.Spec.Containers[0]
+			profile := platformConfig.Security
+			if profile == nil {
+				profile = &SecurityProfile{}
+			}
+			if container.SecurityContext == nil {
+				container.SecurityContext = &corev1.SecurityContext{}
+			}
+			if err := applySecurityProfile(container.SecurityContext, profile); err != nil {
+				return fmt.Errorf("invalid security profile for spec %s: %w", specName, err)
+			}
+			if isEmptySecurityContext(container.SecurityContext) {
+				container.SecurityContext = nil
+			}
+
+			// 0b. Replace the replica anti-affinity (node and pod affinity rules are kept)
+			antiAffinity, err := buildReplicaAntiAffinity(endpoint, platformConfig.AntiAffinity)
+			if err != nil {
+				return fmt.Errorf("invalid anti-affinity for spec %s: %w", specName, err)
+			}
+			applyReplicaAntiAffinity(&deployment.Spec.Template.Spec, antiAffinity)
+
+			// 0c. Replace the topology hints (NCCL env of the previous spec is replaced as well)
+			topology, err := resolveTopologyHints(platformConfig.Topology, spec.Resources.CPU)
+			if err != nil {
+				return fmt.Errorf("invalid topology hints for spec %s: %w", specName, err)
+			}
+			applyTopologySettings(&deployment.Spec.Template.Spec, topology)
+			applyTopologyEnv(container, topology.env, m.topologyEnvOf(previousSpecName))
+
 			// 1. Update Tolerations (replace entirely to remove old tolerations)
 			// Convert from spec.Toleration to corev1.Toleration
 			tolerations := make([]corev1.Toleration, len(platformConfig.Tolerations))
@@ -1846,13 +2117,24 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 			}
 			deployment.Spec.Template.Spec.Tolerations = tolerations
 
-			// 2. Update NodeSelector (replace entirely)
-			// Ensure NodeSelector is initialized (not nil) to avoid nil map assignment
-			nodeSelector := platformConfig.NodeSelector
-			if nodeSelector == nil {
-				nodeSelector = make(map[string]string)
+			// 2. Update NodeSelector and the GPU type fallbacks (replace entirely)
+			chain, err := gpuTypeChain(spec, platformConfig)
+			if err != nil {
+				return fmt.Errorf("invalid GPU type fallbacks for spec %s: %w", specName, err)
+			}
+			applyGPUTypeScheduling(&deployment.Spec.Template.Spec, platformConfig.NodeSelector, chain)
+			chainJSON, err := gpuTypeChainJSON(chain)
+			if err != nil {
+				return err
+			}
+			if chainJSON != "" {
+				if deployment.Annotations == nil {
+					deployment.Annotations = make(map[string]string)
+				}
+				deployment.Annotations[gpuTypesAnnotation] = chainJSON
+			} else {
+				delete(deployment.Annotations, gpuTypesAnnotation)
 			}
-			deployment.Spec.Template.Spec.NodeSelector = nodeSelector
 
 			// 3. Update Pod Labels (smart merge: remove old platform labels, keep system labels, apply new platform labels)
 			if deployment.Spec.Template.Labels == nil {
@@ -1933,7 +2215,7 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 	// Update image if provided
 	if image != "" {
 		if len(deployment.Spec.Template.Spec.Containers) > 0 {
-			deployment.Spec.Template.Spec.Containers[0].Image = image
+			deployment.Spec.Template.Spec.Containers[0].Image = m.rewriteImage(ctx, image)
 		}
 	}
 
@@ -1949,9 +2231,9 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 		var volumes []corev1.Volume
 		var mounts []corev1.VolumeMount
 
-		// Keep non-PVC volumes (e.g., dshm for shared memory)
+		// Keep non-PVC volumes (e.g., dshm for shared memory) and the PVCs mounted by sidecars
 		for _, vol := range deployment.Spec.Template.Spec.Volumes {
-			if vol.PersistentVolumeClaim == nil {
+			if vol.PersistentVolumeClaim == nil || isSidecarVolume(vol.Name) {
 				volumes = append(volumes, vol)
 			}
 		}
@@ -2005,7 +2287,10 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 
 		if *shmSize != "" {
 			// Add or update dshm volume
-			shmSizeQuantity := resource.MustParse(*shmSize)
+			shmSizeQuantity, err := resource.ParseQuantity(*shmSize)
+			if err != nil {
+				return fmt.Errorf("invalid shmSize %q: %w", *shmSize, err)
+			}
 			dshmVolume := corev1.Volume{
 				Name: "dshm",
 				VolumeSource: corev1.VolumeSource{
@@ -2106,10 +2391,7 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 				if len(container.SecurityContext.Capabilities.Add) == 0 && len(container.SecurityContext.Capabilities.Drop) == 0 {
 					container.SecurityContext.Capabilities = nil
 				}
-				if container.SecurityContext.Capabilities == nil &&
-					container.SecurityContext.RunAsUser == nil &&
-					container.SecurityContext.RunAsGroup == nil &&
-					container.SecurityContext.Privileged == nil {
+				if isEmptySecurityContext(container.SecurityContext) {
 					container.SecurityContext = nil
 				}
 			}
@@ -2142,17 +2424,55 @@ func (m *Manager) UpdateDeployment(ctx context.Context, endpoint string, specNam
 
 		// Update container env vars
 		container.Env = newEnvVars
+
+		// Topology env of the current spec (custom env takes precedence)
+		applyTopologyEnv(container, m.topologyEnvOf(deployment.Spec.Template.Labels["waverless.io/spec"]), nil)
 	}
 
-	// Update deployment
-	_, err = deployments.Update(ctx, deployment, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update deployment: %v", err)
+	// Update ephemeral storage if provided (empty reverts to the spec)
+	if ephemeralStorage != nil && len(deployment.Spec.Template.Spec.Containers) > 0 {
+		value := *ephemeralStorage
+		if value == "" {
+			if currentSpec := deployment.Spec.Template.Labels["waverless.io/spec"]; currentSpec != "" {
+				spec, err := m.specManager.GetSpec(currentSpec)
+				if err != nil {
+					return fmt.Errorf("failed to get spec %s: %v", currentSpec, err)
+				}
+				if value, err = resolveEphemeralStorage("", spec); err != nil {
+					return err
+				}
+			}
+		}
+		if err := applyEphemeralStorage(&deployment.Spec.Template.Spec.Containers[0], value); err != nil {
+			return err
+		}
+	}
+
+	// Toggle the RunPod compatibility layer if provided
+	if runPodCompat != nil && len(deployment.Spec.Template.Spec.Containers) > 0 {
+		m.applyRunPodCompat(&deployment.Spec.Template.Spec.Containers[0], endpoint, *runPodCompat)
 	}
 
 	return nil
 }
 
+// topologyEnvOf returns the topology env of a spec on this platform, nil when the spec is
+// unknown or has no topology hints
+func (m *Manager) topologyEnvOf(specName string) map[string]string {
+	if specName == "" {
+		return nil
+	}
+	spec, err := m.specManager.GetSpec(specName)
+	if err != nil {
+		return nil
+	}
+	hints := spec.GetPlatformConfig(m.platform.GetName()).Topology
+	if hints == nil {
+		return nil
+	}
+	return hints.NCCLEnv
+}
+
 // ScaleDeployment updates the desired replica count for an endpoint.
 func (m *Manager) ScaleDeployment(ctx context.Context, endpoint string, replicas int) error {
 	if replicas < 0 {
@@ -2160,16 +2480,21 @@ func (m *Manager) ScaleDeployment(ctx context.Context, endpoint string, replicas
 	}
 
 	deployments := m.client.AppsV1().Deployments(m.namespace)
-	deployment, err := deployments.Get(ctx, endpoint, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get deployment: %v", err)
-	}
+	// Re-read and retry on 409s: the deployment controller and other writers update it concurrently
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := deployments.Get(ctx, endpoint, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
 
-	r := int32(replicas)
-	deployment.Spec.Replicas = &r
+		r := int32(replicas)
+		deployment.Spec.Replicas = &r
 
-	if _, err := deployments.Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
-		return fmt.Errorf("failed to scale deployment: %v", err)
+		_, err = deployments.Update(ctx, deployment, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale deployment: %w", err)
 	}
 	return nil
 }
@@ -2189,7 +2514,7 @@ func (m *Manager) IsPodDraining(ctx context.Context, podName string) (bool, erro
 			// Pod not found, not draining
 			return false, nil
 		}
-		return false, fmt.Errorf("failed to get pod from cache: %v", err)
+		return false, fmt.Errorf("failed to get pod from cache: %w", err)
 	}
 
 	// Check if pod has draining label
@@ -2218,7 +2543,7 @@ func (m *Manager) IsPodTerminating(ctx context.Context, podName string) (bool, e
 			// Pod not found, consider it as terminating (already deleted)
 			return true, nil
 		}
-		return false, fmt.Errorf("failed to get pod from cache: %v", err)
+		return false, fmt.Errorf("failed to get pod from cache: %w", err)
 	}
 
 	// Check if pod has DeletionTimestamp set (K8s marks pod for deletion)
@@ -2418,7 +2743,7 @@ func (m *Manager) GetDefaultEnvFromConfigMap(ctx context.Context) (map[string]st
 		if strings.Contains(err.Error(), "not found") {
 			return make(map[string]string), nil
 		}
-		return nil, fmt.Errorf("failed to get wavespeed-config ConfigMap: %v", err)
+		return nil, fmt.Errorf("failed to get wavespeed-config ConfigMap: %w", err)
 	}
 
 	if cm.Data == nil {
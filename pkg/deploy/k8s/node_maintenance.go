@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodReasonNodeMaintenance is the pod status reason shown instead of the generic
+// PodTerminating while a worker is taken off a node by cluster operations
+const PodReasonNodeMaintenance = "NodeMaintenance"
+
+// podDisruptionTarget is the pod condition the API server, kubelet and scheduler set before
+// they remove a pod (corev1.DisruptionTarget, Kubernetes 1.26+)
+const podDisruptionTarget corev1.PodConditionType = "DisruptionTarget"
+
+// Reasons of the DisruptionTarget condition that mean node maintenance
+var nodeMaintenanceCauses = map[string]string{
+	"EvictionByEvictionAPI":  "evicted while the node is drained",
+	"DeletionByTaintManager": "node tainted NoExecute",
+	"TerminationByKubelet":   "node shutting down",
+	"PreemptionByScheduler":  "preempted by a higher-priority pod",
+}
+
+// NodeMaintenance describes a worker pod being removed from its node by cluster operations
+// (kubectl drain, node upgrades, graceful node shutdown)
+type NodeMaintenance struct {
+	Node      string        `json:"node"`
+	Cause     string        `json:"cause"`              // DisruptionTarget reason, e.g. EvictionByEvictionAPI
+	Message   string        `json:"message,omitempty"`  // Condition message from the evicting component
+	Grace     time.Duration `json:"grace"`              // Grace period the pod was deleted with
+	FullGrace time.Duration `json:"fullGrace"`          // Grace period of the pod spec (task timeout + buffer)
+	Deadline  *time.Time    `json:"deadline,omitempty"` // When the pod is killed
+}
+
+// Shortened reports whether the evicting component cut the grace period below the pod's own,
+// so running tasks may not get their full task timeout
+func (n *NodeMaintenance) Shortened() bool {
+	return n.FullGrace > 0 && n.Grace < n.FullGrace
+}
+
+// Reason is the user-facing drain reason of the worker
+func (n *NodeMaintenance) Reason() string {
+	cause := nodeMaintenanceCauses[n.Cause]
+	reason := fmt.Sprintf("node maintenance: %s", cause)
+	if n.Node != "" {
+		reason = fmt.Sprintf("node maintenance on %s: %s", n.Node, cause)
+	}
+	if n.Grace > 0 {
+		reason += fmt.Sprintf(", grace %s", n.Grace)
+		if n.Shortened() {
+			reason += fmt.Sprintf(" (shortened from %s, running tasks may be cut)", n.FullGrace)
+		}
+	}
+	return reason
+}
+
+// detectNodeMaintenance returns the node maintenance a terminating pod is affected by, or nil
+func detectNodeMaintenance(pod *corev1.Pod) *NodeMaintenance {
+	if pod == nil || pod.DeletionTimestamp == nil {
+		return nil
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != podDisruptionTarget || cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		if _, ok := nodeMaintenanceCauses[cond.Reason]; !ok {
+			return nil
+		}
+		nm := &NodeMaintenance{Node: pod.Spec.NodeName, Cause: cond.Reason, Message: cond.Message}
+		if pod.DeletionGracePeriodSeconds != nil {
+			nm.Grace = time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second
+		}
+		if pod.Spec.TerminationGracePeriodSeconds != nil {
+			nm.FullGrace = time.Duration(*pod.Spec.TerminationGracePeriodSeconds) * time.Second
+		}
+		deadline := pod.DeletionTimestamp.Time
+		nm.Deadline = &deadline
+		return nm
+	}
+	return nil
+}
+
+// NodeMaintenanceCallback is called once when a worker pod starts terminating because of
+// node maintenance
+type NodeMaintenanceCallback func(podName, endpoint string, maintenance *NodeMaintenance)
+
+// RegisterNodeMaintenanceCallback adds a new node maintenance listener and returns its id.
+func (m *Manager) RegisterNodeMaintenanceCallback(cb NodeMaintenanceCallback) int64 {
+	if cb == nil {
+		return 0
+	}
+	id := atomic.AddInt64(&m.nextCallbackID, 1)
+	m.callbacksMu.Lock()
+	if m.nodeMaintenanceCallbacks == nil {
+		m.nodeMaintenanceCallbacks = make(map[int64]NodeMaintenanceCallback)
+	}
+	m.nodeMaintenanceCallbacks[id] = cb
+	m.callbacksMu.Unlock()
+	return id
+}
+
+// UnregisterNodeMaintenanceCallback removes a previously registered node maintenance listener.
+func (m *Manager) UnregisterNodeMaintenanceCallback(id int64) {
+	if id == 0 {
+		return
+	}
+	m.callbacksMu.Lock()
+	if m.nodeMaintenanceCallbacks != nil {
+		delete(m.nodeMaintenanceCallbacks, id)
+	}
+	m.callbacksMu.Unlock()
+}
+
+// notifyNodeMaintenance notifies all registered callbacks about a pod removed by node maintenance
+func (m *Manager) notifyNodeMaintenance(podName, endpoint string, maintenance *NodeMaintenance, eventAt time.Time) {
+	m.callbacksMu.RLock()
+	callbacks := make([]NodeMaintenanceCallback, 0, len(m.nodeMaintenanceCallbacks))
+	for _, cb := range m.nodeMaintenanceCallbacks {
+		callbacks = append(callbacks, cb)
+	}
+	m.callbacksMu.RUnlock()
+
+	for _, cb := range callbacks {
+		cb := cb
+		runCallback(callbackNodeMaintenance, eventAt, func() { cb(podName, endpoint, maintenance) })
+	}
+}
@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func drainedPod(reason string, grace, fullGrace int64) *corev1.Pod {
+	now := metav1.NewTime(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:                       "worker-1",
+			DeletionTimestamp:          &now,
+			DeletionGracePeriodSeconds: &grace,
+		},
+		Spec: corev1.PodSpec{NodeName: "gpu-node-3", TerminationGracePeriodSeconds: &fullGrace},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			{Type: podDisruptionTarget, Status: corev1.ConditionTrue, Reason: reason, Message: "Eviction API: evicting"},
+		}},
+	}
+}
+
+func TestDetectNodeMaintenance_Eviction(t *testing.T) {
+	nm := detectNodeMaintenance(drainedPod("EvictionByEvictionAPI", 3630, 3630))
+	assert.NotNil(t, nm)
+	assert.Equal(t, "gpu-node-3", nm.Node)
+	assert.Equal(t, "EvictionByEvictionAPI", nm.Cause)
+	assert.Equal(t, time.Hour+30*time.Second, nm.Grace)
+	assert.False(t, nm.Shortened())
+	assert.Equal(t, "node maintenance on gpu-node-3: evicted while the node is drained, grace 1h0m30s", nm.Reason())
+}
+
+func TestDetectNodeMaintenance_ShortenedGrace(t *testing.T) {
+	nm := detectNodeMaintenance(drainedPod("EvictionByEvictionAPI", 60, 3630))
+	assert.NotNil(t, nm)
+	assert.True(t, nm.Shortened())
+	assert.Contains(t, nm.Reason(), "grace 1m0s (shortened from 1h0m30s, running tasks may be cut)")
+}
+
+func TestDetectNodeMaintenance_NotMaintenance(t *testing.T) {
+	// Not being deleted yet
+	pod := drainedPod("EvictionByEvictionAPI", 30, 30)
+	pod.DeletionTimestamp = nil
+	assert.Nil(t, detectNodeMaintenance(pod))
+
+	// Deleted without a disruption condition (scale-down, manual delete)
+	pod = drainedPod("EvictionByEvictionAPI", 30, 30)
+	pod.Status.Conditions = pod.Status.Conditions[:1]
+	assert.Nil(t, detectNodeMaintenance(pod))
+
+	// Disruption that is not node maintenance
+	assert.Nil(t, detectNodeMaintenance(drainedPod("DeletionByPodGC", 30, 30)))
+
+	assert.Nil(t, detectNodeMaintenance(nil))
+}
+
+func TestGetPodStatus_NodeMaintenance(t *testing.T) {
+	status, reason, message := (&Manager{}).getPodStatus(drainedPod("TerminationByKubelet", 30, 30))
+	assert.Equal(t, "Terminating", status)
+	assert.Equal(t, PodReasonNodeMaintenance, reason)
+	assert.Equal(t, "node maintenance on gpu-node-3: node shutting down, grace 30s", message)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -61,7 +62,7 @@ func (m *Manager) DescribePod(ctx context.Context, endpoint string, podName stri
 
 	// Verify Pod belongs to specified endpoint (using "app" label)
 	if pod.Labels["app"] != endpoint {
-		return nil, fmt.Errorf("pod %s does not belong to endpoint %s", podName, endpoint)
+		return nil, interfaces.ProviderErrorf(interfaces.ErrNotFound, "pod %s does not belong to endpoint %s", podName, endpoint)
 	}
 
 	// Convert to PodInfo
@@ -112,6 +113,8 @@ func (m *Manager) convertPodToInfo(pod *corev1.Pod) *interfaces.PodInfo {
 		Message:      message,
 		IP:           pod.Status.PodIP,
 		NodeName:     pod.Spec.NodeName,
+		GPUType:      m.podGPUType(pod),
+		ImageDigest:  workerImageDigest(pod),
 		CreatedAt:    pod.CreationTimestamp.Format(time.RFC3339),
 		RestartCount: restartCount,
 		Labels:       pod.Labels,
@@ -128,10 +131,29 @@ func (m *Manager) convertPodToInfo(pod *corev1.Pod) *interfaces.PodInfo {
 	return podInfo
 }
 
+// workerImageDigest returns the digest (sha256:...) of the image the worker container runs,
+// empty until the image is pulled. Sidecars are skipped.
+func workerImageDigest(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if IsSidecarContainerName(cs.Name) {
+			continue
+		}
+		// ImageID is e.g. "docker-pullable://repo@sha256:..." or "sha256:..."
+		if i := strings.Index(cs.ImageID, "sha256:"); i >= 0 {
+			return cs.ImageID[i:]
+		}
+		return ""
+	}
+	return ""
+}
+
 // getPodStatus gets detailed Pod status
 func (m *Manager) getPodStatus(pod *corev1.Pod) (status, reason, message string) {
 	// If being deleted
 	if pod.DeletionTimestamp != nil {
+		if nm := detectNodeMaintenance(pod); nm != nil {
+			return "Terminating", PodReasonNodeMaintenance, nm.Reason()
+		}
 		return "Terminating", "PodTerminating", fmt.Sprintf("Pod is terminating (grace period: %ds)", *pod.DeletionGracePeriodSeconds)
 	}
 
@@ -429,7 +451,7 @@ func (m *Manager) GetPodYAML(ctx context.Context, endpoint string, podName strin
 
 	// Verify Pod belongs to specified endpoint (using "app" label)
 	if pod.Labels["app"] != endpoint {
-		return "", fmt.Errorf("pod %s does not belong to endpoint %s", podName, endpoint)
+		return "", interfaces.ProviderErrorf(interfaces.ErrNotFound, "pod %s does not belong to endpoint %s", podName, endpoint)
 	}
 
 	// Convert Pod to YAML
@@ -0,0 +1,23 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestWorkerImageDigest(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+		{Name: "sidecar-exporter", ImageID: "docker-pullable://prom/node-exporter@sha256:aaa"},
+		{Name: "flux", ImageID: "docker-pullable://registry.example.com/flux@sha256:bbb"},
+	}}}
+	assert.Equal(t, "sha256:bbb", workerImageDigest(pod))
+
+	pod.Status.ContainerStatuses[1].ImageID = "sha256:ccc"
+	assert.Equal(t, "sha256:ccc", workerImageDigest(pod))
+
+	// Not pulled yet
+	pod.Status.ContainerStatuses[1].ImageID = ""
+	assert.Empty(t, workerImageDigest(pod))
+}
@@ -3,13 +3,16 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"io"
 
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	"waverless/pkg/backup"
 	"waverless/pkg/config"
+	"waverless/pkg/dataplane"
 	"waverless/pkg/interfaces"
 )
 
@@ -26,13 +29,6 @@ func NewK8sDeploymentProvider(cfg *config.Config) (interfaces.DeploymentProvider
 
 	// Build globalEnv with defaults, then merge config overrides
 	globalEnv := map[string]string{
-		// RunPod compatible environment variables (for runpod-python SDK)
-		"RUNPOD_ENDPOINT_ID":         "{{.Endpoint}}",
-		"RUNPOD_PING_INTERVAL":       "10000",
-		"RUNPOD_WEBHOOK_GET_JOB":     "http://waverless-svc/v2/{{.Endpoint}}/job-take/$ID?",
-		"RUNPOD_WEBHOOK_PING":        "http://waverless-svc/v2/{{.Endpoint}}/ping/$RUNPOD_POD_ID",
-		"RUNPOD_WEBHOOK_POST_OUTPUT": "http://waverless-svc/v2/{{.Endpoint}}/job-done/$RUNPOD_POD_ID/$ID?",
-		"RUNPOD_WEBHOOK_POST_STREAM": "http://waverless-svc/v2/{{.Endpoint}}/job-stream/$RUNPOD_POD_ID/$ID?",
 		// Waverless native environment variables (for wavespeed-python SDK)
 		"WAVERLESS_ENDPOINT_ID":         "{{.Endpoint}}",
 		"WAVERLESS_PING_INTERVAL":       "10000",
@@ -50,14 +46,32 @@ func NewK8sDeploymentProvider(cfg *config.Config) (interfaces.DeploymentProvider
 	// Inject server api_key
 	if cfg.Server.APIKey != "" {
 		globalEnv["WAVERLESS_API_KEY"] = cfg.Server.APIKey
-		globalEnv["RUNPOD_AI_API_KEY"] = cfg.Server.APIKey
-		globalEnv["RUNPOD_API_KEY"] = cfg.Server.APIKey
 	}
 
-	manager, err := NewManager(cfg.K8s.Namespace, cfg.K8s.Platform, cfg.K8s.ConfigDir, globalEnv)
+	manager, err := NewManager(cfg.K8s.Namespace, cfg.K8s.Platform, cfg.K8s.ConfigDir, globalEnv, InformerOptions{
+		LabelSelector:    cfg.K8s.InformerLabelSelector,
+		PodFieldSelector: cfg.K8s.InformerPodFieldSelector,
+		Resync:           cfg.K8s.InformerResync,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create k8s manager: %w", err)
 	}
+	manager.SetSecurityPolicy(NewSecurityPolicy(cfg.Security))
+	manager.SetDebugContainerConfig(cfg.K8s.DebugImage, cfg.K8s.DebugMaxTTL)
+	manager.SetRunPodCompat(cfg.K8s.RunPodAPIBase, cfg.Server.APIKey)
+	manager.SetSpecOverrideLimits(SpecOverrideLimits{
+		MaxExtraCPU:         cfg.K8s.SpecOverrides.MaxExtraCPU,
+		MaxExtraMemory:      cfg.K8s.SpecOverrides.MaxExtraMemory,
+		MaxShmSize:          cfg.K8s.SpecOverrides.MaxShmSize,
+		MaxEphemeralStorage: cfg.K8s.SpecOverrides.MaxEphemeralStorage,
+	})
+	if cfg.DataPlane.TokenSecret != "" {
+		signer, err := dataplane.NewSigner(cfg.DataPlane.TokenSecret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data plane config: %w", err)
+		}
+		manager.SetInvokeSigner(signer)
+	}
 
 	return &K8sDeploymentProvider{
 		manager: manager,
@@ -66,17 +80,63 @@ func NewK8sDeploymentProvider(cfg *config.Config) (interfaces.DeploymentProvider
 
 // Deploy deploys an application
 func (p *K8sDeploymentProvider) Deploy(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
-	// Convert to DeployAppRequest
+	if err := p.manager.DeployApp(ctx, toDeployAppRequest(req)); err != nil {
+		return nil, providerError(err)
+	}
+
+	return &interfaces.DeployResponse{
+		Endpoint:  req.Endpoint,
+		Message:   "Application deployed successfully",
+		CreatedAt: "", // TODO: Get creation time
+	}, nil
+}
+
+// DryRunDeploy validates and renders a deploy and submits it to the API server as a dry run
+func (p *K8sDeploymentProvider) DryRunDeploy(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DryRunResult, error) {
+	result, err := p.manager.DryRunDeployApp(ctx, toDeployAppRequest(req))
+	if err != nil {
+		return nil, providerError(err)
+	}
+	return result, nil
+}
+
+// ViewDeployment describes the live and the proposed Deployment of an endpoint
+func (p *K8sDeploymentProvider) ViewDeployment(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeploymentView, *interfaces.DeploymentView, error) {
+	current, proposed, err := p.manager.ViewDeployment(ctx, toDeployAppRequest(req))
+	if err != nil {
+		return nil, nil, providerError(err)
+	}
+	return current, proposed, nil
+}
+
+// LiveDeployment returns the live state of an endpoint's deployment
+func (p *K8sDeploymentProvider) LiveDeployment(ctx context.Context, endpoint string) (*interfaces.DeploymentView, error) {
+	view, err := p.manager.LiveDeployment(ctx, endpoint)
+	return view, providerError(err)
+}
+
+// toDeployAppRequest converts a provider deploy request to a DeployAppRequest
+func toDeployAppRequest(req *interfaces.DeployRequest) *DeployAppRequest {
 	k8sReq := &DeployAppRequest{
-		Endpoint:     req.Endpoint,
-		SpecName:     req.SpecName,
-		Image:        req.Image,
-		Replicas:     req.Replicas,
-		GpuCount:     req.GpuCount,
-		TaskTimeout:  req.TaskTimeout,
-		Env:          req.Env,
-		VolumeMounts: req.VolumeMounts,
-		ShmSize:      req.ShmSize,
+		Endpoint:         req.Endpoint,
+		SpecName:         req.SpecName,
+		Image:            req.Image,
+		Replicas:         req.Replicas,
+		GpuCount:         req.GpuCount,
+		TaskTimeout:      req.TaskTimeout,
+		Env:              req.Env,
+		VolumeMounts:     req.VolumeMounts,
+		Storage:          req.Storage,
+		InitContainers:   req.InitContainers,
+		Sidecars:         req.Sidecars,
+		ShmSize:          req.ShmSize,
+		EphemeralStorage: req.EphemeralStorage,
+		EnablePtrace:     req.EnablePtrace,
+		RunPodCompat:     req.RunPodCompat,
+
+		EgressPolicy:             req.EgressPolicy,
+		RestrictedServiceAccount: req.RestrictedServiceAccount,
+		SpecOverride:             req.SpecOverride,
 	}
 	if req.RegistryCredential != nil {
 		k8sReq.RegistryCredential = &RegistryCredential{
@@ -85,23 +145,14 @@ func (p *K8sDeploymentProvider) Deploy(ctx context.Context, req *interfaces.Depl
 			Password: req.RegistryCredential.Password,
 		}
 	}
-
-	if err := p.manager.DeployApp(ctx, k8sReq); err != nil {
-		return nil, err
-	}
-
-	return &interfaces.DeployResponse{
-		Endpoint:  req.Endpoint,
-		Message:   "Application deployed successfully",
-		CreatedAt: "", // TODO: Get creation time
-	}, nil
+	return k8sReq
 }
 
 // GetApp gets application details
 func (p *K8sDeploymentProvider) GetApp(ctx context.Context, endpoint string) (*interfaces.AppInfo, error) {
 	app, err := p.manager.GetApp(ctx, endpoint)
 	if err != nil {
-		return nil, err
+		return nil, providerError(err)
 	}
 
 	// Convert to interfaces.AppInfo
@@ -117,7 +168,10 @@ func (p *K8sDeploymentProvider) GetApp(ctx context.Context, endpoint string) (*i
 		Labels:            app.Labels,
 		CreatedAt:         app.CreatedAt,
 		ShmSize:           app.ShmSize,
+		EphemeralStorage:  app.EphemeralStorage,
+		SpecOverride:      app.SpecOverride,
 		VolumeMounts:      app.VolumeMounts,
+		InitStatus:        app.InitStatus,
 	}, nil
 }
 
@@ -125,7 +179,7 @@ func (p *K8sDeploymentProvider) GetApp(ctx context.Context, endpoint string) (*i
 func (p *K8sDeploymentProvider) ListApps(ctx context.Context) ([]*interfaces.AppInfo, error) {
 	apps, err := p.manager.ListApps(ctx)
 	if err != nil {
-		return nil, err
+		return nil, providerError(err)
 	}
 
 	// Convert to interfaces.AppInfo list
@@ -143,7 +197,10 @@ func (p *K8sDeploymentProvider) ListApps(ctx context.Context) ([]*interfaces.App
 			Labels:            app.Labels,
 			CreatedAt:         app.CreatedAt,
 			ShmSize:           app.ShmSize,
+			EphemeralStorage:  app.EphemeralStorage,
+			SpecOverride:      app.SpecOverride,
 			VolumeMounts:      app.VolumeMounts,
+			InitStatus:        app.InitStatus,
 		})
 	}
 
@@ -152,24 +209,37 @@ func (p *K8sDeploymentProvider) ListApps(ctx context.Context) ([]*interfaces.App
 
 // DeleteApp deletes an application
 func (p *K8sDeploymentProvider) DeleteApp(ctx context.Context, endpoint string) error {
-	return p.manager.DeleteApp(ctx, endpoint)
+	return providerError(p.manager.DeleteApp(ctx, endpoint))
 }
 
 // GetAppLogs gets application logs
 func (p *K8sDeploymentProvider) GetAppLogs(ctx context.Context, endpoint string, lines int, podName ...string) (string, error) {
-	return p.manager.GetAppLogs(ctx, endpoint, int64(lines), podName...)
+	logs, err := p.manager.GetAppLogs(ctx, endpoint, int64(lines), podName...)
+	return logs, providerError(err)
+}
+
+// GetContainerLogs gets the logs of a container of an endpoint pod, e.g. a sidecar
+func (p *K8sDeploymentProvider) GetContainerLogs(ctx context.Context, endpoint string, lines int, podName, container string) (string, error) {
+	logs, err := p.manager.GetContainerLogs(ctx, endpoint, int64(lines), podName, container)
+	return logs, providerError(err)
+}
+
+// StreamAppLogs follows the logs of an endpoint's pods
+func (p *K8sDeploymentProvider) StreamAppLogs(ctx context.Context, endpoint string, tailLines int, podName, container string) (<-chan interfaces.LogLine, error) {
+	lines, err := p.manager.StreamAppLogs(ctx, endpoint, int64(tailLines), podName, container)
+	return lines, providerError(err)
 }
 
 // ScaleApp scales an application
 func (p *K8sDeploymentProvider) ScaleApp(ctx context.Context, endpoint string, replicas int) error {
-	return p.manager.ScaleDeployment(ctx, endpoint, replicas)
+	return providerError(p.manager.ScaleDeployment(ctx, endpoint, replicas))
 }
 
 // GetAppStatus gets application status
 func (p *K8sDeploymentProvider) GetAppStatus(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
 	app, err := p.manager.GetApp(ctx, endpoint)
 	if err != nil {
-		return nil, err
+		return nil, providerError(err)
 	}
 
 	return &interfaces.AppStatus{
@@ -178,7 +248,8 @@ func (p *K8sDeploymentProvider) GetAppStatus(ctx context.Context, endpoint strin
 		ReadyReplicas:     app.ReadyReplicas,
 		AvailableReplicas: app.AvailableReplicas,
 		TotalReplicas:     app.Replicas,
-		Message:           "",
+		RolloutComplete:   app.RolloutComplete,
+		Message:           app.InitStatus,
 	}, nil
 }
 
@@ -203,6 +274,7 @@ func (p *K8sDeploymentProvider) ListSpecs(ctx context.Context) ([]*interfaces.Sp
 			Resources: interfaces.ResourceRequirements{
 				GPU:              spec.Resources.GPU,
 				GPUType:          spec.Resources.GpuType,
+				GPUTypeFallbacks: spec.Resources.GpuTypeFallbacks,
 				CPU:              spec.Resources.CPU,
 				Memory:           spec.Resources.Memory,
 				EphemeralStorage: spec.Resources.EphemeralStorage,
@@ -231,6 +303,7 @@ func (p *K8sDeploymentProvider) ListSpecsWithCapacity(ctx context.Context) ([]*i
 				Resources: interfaces.ResourceRequirements{
 					GPU:              spec.Resources.GPU,
 					GPUType:          spec.Resources.GpuType,
+					GPUTypeFallbacks: spec.Resources.GpuTypeFallbacks,
 					CPU:              spec.Resources.CPU,
 					Memory:           spec.Resources.Memory,
 					EphemeralStorage: spec.Resources.EphemeralStorage,
@@ -263,6 +336,7 @@ func (p *K8sDeploymentProvider) GetSpec(ctx context.Context, specName string) (*
 		Resources: interfaces.ResourceRequirements{
 			GPU:              spec.Resources.GPU,
 			GPUType:          spec.Resources.GpuType,
+			GPUTypeFallbacks: spec.Resources.GpuTypeFallbacks,
 			CPU:              spec.Resources.CPU,
 			Memory:           spec.Resources.Memory,
 			EphemeralStorage: spec.Resources.EphemeralStorage,
@@ -275,15 +349,24 @@ func (p *K8sDeploymentProvider) GetSpec(ctx context.Context, specName string) (*
 func (p *K8sDeploymentProvider) PreviewDeploymentYAML(ctx context.Context, req *interfaces.DeployRequest) (string, error) {
 	// Convert to DeployAppRequest
 	k8sReq := &DeployAppRequest{
-		Endpoint:     req.Endpoint,
-		SpecName:     req.SpecName,
-		Image:        req.Image,
-		Replicas:     req.Replicas,
-		GpuCount:     req.GpuCount,
-		TaskTimeout:  req.TaskTimeout,
-		Env:          req.Env,
-		VolumeMounts: req.VolumeMounts,
-		ShmSize:      req.ShmSize,
+		Endpoint:         req.Endpoint,
+		SpecName:         req.SpecName,
+		Image:            req.Image,
+		Replicas:         req.Replicas,
+		GpuCount:         req.GpuCount,
+		TaskTimeout:      req.TaskTimeout,
+		Env:              req.Env,
+		VolumeMounts:     req.VolumeMounts,
+		Storage:          req.Storage,
+		InitContainers:   req.InitContainers,
+		Sidecars:         req.Sidecars,
+		ShmSize:          req.ShmSize,
+		EphemeralStorage: req.EphemeralStorage,
+		EnablePtrace:     req.EnablePtrace,
+		RunPodCompat:     req.RunPodCompat,
+
+		EgressPolicy:             req.EgressPolicy,
+		RestrictedServiceAccount: req.RestrictedServiceAccount,
 	}
 
 	return p.manager.PreviewYAML(k8sReq)
@@ -291,8 +374,8 @@ func (p *K8sDeploymentProvider) PreviewDeploymentYAML(ctx context.Context, req *
 
 // UpdateDeployment updates deployment
 func (p *K8sDeploymentProvider) UpdateDeployment(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error) {
-	if err := p.manager.UpdateDeployment(ctx, req.Endpoint, req.SpecName, req.Image, req.Replicas, req.VolumeMounts, req.ShmSize, req.EnablePtrace, req.Env); err != nil {
-		return nil, err
+	if err := p.manager.UpdateDeployment(ctx, req.Endpoint, req.SpecName, req.Image, req.Replicas, req.VolumeMounts, req.ShmSize, req.EphemeralStorage, req.EnablePtrace, req.Env, req.RunPodCompat); err != nil {
+		return nil, providerError(err)
 	}
 
 	return &interfaces.DeployResponse{
@@ -302,6 +385,15 @@ func (p *K8sDeploymentProvider) UpdateDeployment(ctx context.Context, req *inter
 	}, nil
 }
 
+// DryRunUpdateDeployment applies an update to a copy of the live deployment and submits it as a dry run
+func (p *K8sDeploymentProvider) DryRunUpdateDeployment(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DryRunResult, error) {
+	result, err := p.manager.DryRunUpdateDeployment(ctx, req.Endpoint, req.SpecName, req.Image, req.Replicas, req.VolumeMounts, req.ShmSize, req.EphemeralStorage, req.EnablePtrace, req.Env, req.RunPodCompat)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	return result, nil
+}
+
 // GetSpecManager gets spec manager (for autoscaling)
 func (p *K8sDeploymentProvider) GetSpecManager() *SpecManager {
 	return p.manager.specManager
@@ -444,6 +536,14 @@ func (p *K8sDeploymentProvider) WatchPodStatusChange(ctx context.Context, callba
 	return nil
 }
 
+// ReplayWatchState re-delivers the current informer cache to registered watch callbacks
+func (p *K8sDeploymentProvider) ReplayWatchState(ctx context.Context) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	return p.manager.ReplayWatchState(ctx)
+}
+
 // WatchPodDelete registers a callback to observe pod deletions
 func (p *K8sDeploymentProvider) WatchPodDelete(ctx context.Context, callback PodDeleteCallback) error {
 	if p.manager == nil {
@@ -489,6 +589,25 @@ func (p *K8sDeploymentProvider) WatchSpotInterruption(ctx context.Context, callb
 	return nil
 }
 
+// WatchNodeMaintenance registers a callback to observe worker pods removed by node maintenance
+func (p *K8sDeploymentProvider) WatchNodeMaintenance(ctx context.Context, callback NodeMaintenanceCallback) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	if callback == nil {
+		return fmt.Errorf("node maintenance callback is nil")
+	}
+
+	id := p.manager.RegisterNodeMaintenanceCallback(callback)
+
+	go func() {
+		<-ctx.Done()
+		p.manager.UnregisterNodeMaintenanceCallback(id)
+	}()
+
+	return nil
+}
+
 // WatchDeploymentSpecChange registers a callback to observe when deployment spec changes.
 // This is used to optimize pod replacement during rolling updates by prioritizing idle workers.
 func (p *K8sDeploymentProvider) WatchDeploymentSpecChange(ctx context.Context, callback DeploymentSpecChangeCallback) error {
@@ -550,6 +669,11 @@ func (p *K8sDeploymentProvider) Close() {
 	}
 }
 
+// SetImageRewriter sets the image rewriter (registry mirrors) applied at render time
+func (p *K8sDeploymentProvider) SetImageRewriter(rewriter ImageRewriter) {
+	p.manager.SetImageRewriter(rewriter)
+}
+
 // GetManager returns the underlying K8s manager.
 // This is used by the worker status monitor to access pod watching capabilities.
 func (p *K8sDeploymentProvider) GetManager() *Manager {
@@ -564,6 +688,38 @@ func (p *K8sDeploymentProvider) GetRestConfig() *rest.Config {
 	return p.manager.GetRestConfig()
 }
 
+// AttachDebugContainer injects a time-limited ephemeral debug container into a worker pod
+func (p *K8sDeploymentProvider) AttachDebugContainer(ctx context.Context, req *DebugContainerRequest) (*DebugContainerInfo, error) {
+	if p.manager == nil {
+		return nil, fmt.Errorf("k8s manager not initialized")
+	}
+	return p.manager.AttachDebugContainer(ctx, req)
+}
+
+// ListRegistryCredentials returns endpoint image pull secrets for disaster-recovery export
+func (p *K8sDeploymentProvider) ListRegistryCredentials(ctx context.Context) ([]backup.RegistryCredential, error) {
+	if p.manager == nil {
+		return nil, fmt.Errorf("k8s manager not initialized")
+	}
+	return p.manager.ListRegistryCredentials(ctx)
+}
+
+// RestoreRegistryCredential recreates an endpoint image pull secret from an archive
+func (p *K8sDeploymentProvider) RestoreRegistryCredential(ctx context.Context, cred backup.RegistryCredential) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	return p.manager.RestoreRegistryCredential(ctx, cred)
+}
+
+// InformerStats reports informer cache sizes for diagnostics
+func (p *K8sDeploymentProvider) InformerStats() *InformerCacheStats {
+	if p.manager == nil {
+		return nil
+	}
+	return p.manager.InformerStats()
+}
+
 // GetNamespace returns the namespace this provider operates in
 func (p *K8sDeploymentProvider) GetNamespace() string {
 	if p.manager == nil {
@@ -585,7 +741,8 @@ func (p *K8sDeploymentProvider) GetPods(ctx context.Context, endpoint string) ([
 	if p.manager == nil {
 		return nil, fmt.Errorf("k8s manager not initialized")
 	}
-	return p.manager.GetPods(ctx, endpoint)
+	pods, err := p.manager.GetPods(ctx, endpoint)
+	return pods, providerError(err)
 }
 
 // DescribePod gets detailed Pod info (similar to kubectl describe)
@@ -593,7 +750,8 @@ func (p *K8sDeploymentProvider) DescribePod(ctx context.Context, endpoint string
 	if p.manager == nil {
 		return nil, fmt.Errorf("k8s manager not initialized")
 	}
-	return p.manager.DescribePod(ctx, endpoint, podName)
+	detail, err := p.manager.DescribePod(ctx, endpoint, podName)
+	return detail, providerError(err)
 }
 
 // GetPodYAML gets Pod YAML (similar to kubectl get pod -o yaml)
@@ -601,7 +759,8 @@ func (p *K8sDeploymentProvider) GetPodYAML(ctx context.Context, endpoint string,
 	if p.manager == nil {
 		return "", fmt.Errorf("k8s manager not initialized")
 	}
-	return p.manager.GetPodYAML(ctx, endpoint, podName)
+	yaml, err := p.manager.GetPodYAML(ctx, endpoint, podName)
+	return yaml, providerError(err)
 }
 
 // ListPVCs lists all PersistentVolumeClaims in the namespace
@@ -612,6 +771,121 @@ func (p *K8sDeploymentProvider) ListPVCs(ctx context.Context) ([]*interfaces.PVC
 	return p.manager.ListPVCs(ctx)
 }
 
+// GetEndpointStorage returns the PVCs provisioned for an endpoint
+func (p *K8sDeploymentProvider) GetEndpointStorage(ctx context.Context, endpoint string) ([]*interfaces.StorageStatus, error) {
+	if p.manager == nil {
+		return nil, fmt.Errorf("k8s manager not initialized")
+	}
+	storage, err := p.manager.GetEndpointStorage(ctx, endpoint)
+	return storage, providerError(err)
+}
+
+// ListSharedStorage returns the shared storage and the endpoints using each
+func (p *K8sDeploymentProvider) ListSharedStorage(ctx context.Context) ([]*interfaces.StorageStatus, error) {
+	if p.manager == nil {
+		return nil, fmt.Errorf("k8s manager not initialized")
+	}
+	storage, err := p.manager.ListSharedStorage(ctx)
+	return storage, providerError(err)
+}
+
+// SyncSecretEnv keeps the secret env vars of an endpoint in its env Secret
+func (p *K8sDeploymentProvider) SyncSecretEnv(ctx context.Context, endpoint string, set map[string]string, unset []string) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	return providerError(p.manager.SyncSecretEnv(ctx, endpoint, set, unset))
+}
+
+// RunJob starts a one-off Kubernetes Job
+func (p *K8sDeploymentProvider) RunJob(ctx context.Context, req *interfaces.JobRequest) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	return providerError(p.manager.RunJob(ctx, req))
+}
+
+// GetJobStatus returns the state of a Job, nil when it does not exist
+func (p *K8sDeploymentProvider) GetJobStatus(ctx context.Context, name string) (*interfaces.JobStatus, error) {
+	if p.manager == nil {
+		return nil, fmt.Errorf("k8s manager not initialized")
+	}
+	status, err := p.manager.GetJobStatus(ctx, name)
+	return status, providerError(err)
+}
+
+// DeleteJob deletes a Job and its pod
+func (p *K8sDeploymentProvider) DeleteJob(ctx context.Context, name string) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	return providerError(p.manager.DeleteJob(ctx, name))
+}
+
+// StreamJobLogs streams the logs of a Job's container
+func (p *K8sDeploymentProvider) StreamJobLogs(ctx context.Context, name string, follow bool, tailLines int) (io.ReadCloser, error) {
+	if p.manager == nil {
+		return nil, fmt.Errorf("k8s manager not initialized")
+	}
+	stream, err := p.manager.StreamJobLogs(ctx, name, follow, tailLines)
+	return stream, providerError(err)
+}
+
+// DeployCandidate creates the candidate deployment of a blue/green update
+func (p *K8sDeploymentProvider) DeployCandidate(ctx context.Context, req *interfaces.UpdateDeploymentRequest) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	return providerError(p.manager.DeployCandidate(ctx, req))
+}
+
+// GetCandidateStatus returns the status of an endpoint's candidate deployment
+func (p *K8sDeploymentProvider) GetCandidateStatus(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+	if p.manager == nil {
+		return nil, fmt.Errorf("k8s manager not initialized")
+	}
+	app, err := p.manager.GetCandidateStatus(ctx, endpoint)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	return &interfaces.AppStatus{
+		Endpoint:          endpoint,
+		Status:            app.Status,
+		ReadyReplicas:     app.ReadyReplicas,
+		AvailableReplicas: app.AvailableReplicas,
+		TotalReplicas:     app.Replicas,
+		RolloutComplete:   app.RolloutComplete,
+		Message:           app.InitStatus,
+	}, nil
+}
+
+// ListCandidateWorkers returns the worker IDs of an endpoint's candidate deployment
+func (p *K8sDeploymentProvider) ListCandidateWorkers(ctx context.Context, endpoint string) ([]string, error) {
+	if p.manager == nil {
+		return nil, fmt.Errorf("k8s manager not initialized")
+	}
+	workers, err := p.manager.ListCandidateWorkers(ctx, endpoint)
+	return workers, providerError(err)
+}
+
+// DeleteCandidate deletes an endpoint's candidate deployment
+func (p *K8sDeploymentProvider) DeleteCandidate(ctx context.Context, endpoint string) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	return providerError(p.manager.DeleteCandidate(ctx, endpoint))
+}
+
+// AvoidNode taints a node so that new workers are not scheduled onto it.
+// This implements the NodeAvoider interface for resource release.
+// It is called by ResourceReleaser when a worker on the node fails its GPU health check.
+func (p *K8sDeploymentProvider) AvoidNode(ctx context.Context, node, reason string) error {
+	if p.manager == nil {
+		return fmt.Errorf("k8s manager not initialized")
+	}
+	return providerError(p.manager.AvoidNode(ctx, node, reason))
+}
+
 // TerminateWorker terminates a specific worker (pod) due to failure.
 // This implements the WorkerTerminator interface for resource release.
 // It is called by ResourceReleaser when a worker exceeds the image pull timeout.
@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RunPod compatibility layer: workers of endpoints with runpodCompat enabled (the default) get
+// every variable runpod-python reads at startup, pointed at the Waverless /v2 API, which serves
+// the RunPod worker and client paths. Images written for RunPod run unmodified.
+
+// DefaultRunPodAPIBase is the in-cluster Waverless /v2 base URL RunPod workers are pointed at
+const DefaultRunPodAPIBase = "http://waverless-svc/v2"
+
+// runPodEnvPrefix marks env vars owned by the compatibility layer
+const runPodEnvPrefix = "RUNPOD_"
+
+// runPodFieldEnv are the RunPod variables taken from the pod itself
+var runPodFieldEnv = []string{"RUNPOD_POD_ID", "RUNPOD_POD_HOSTNAME"}
+
+// SetRunPodCompat sets the API base URL and key injected into workers with the RunPod
+// compatibility layer. An empty base uses DefaultRunPodAPIBase.
+func (m *Manager) SetRunPodCompat(apiBase, apiKey string) {
+	m.runPodAPIBase = strings.TrimRight(apiBase, "/")
+	m.runPodAPIKey = apiKey
+}
+
+// runPodCompatEnabled reports whether a request enables the compatibility layer (default: true)
+func runPodCompatEnabled(flag *bool) bool {
+	return flag == nil || *flag
+}
+
+// runPodEnv returns the RunPod variables of an endpoint: the full set runpod-python expects,
+// overridden by RUNPOD_* entries of the configured global env
+func (m *Manager) runPodEnv(endpoint string, gpuCount int) map[string]string {
+	base := m.runPodAPIBase
+	if base == "" {
+		base = DefaultRunPodAPIBase
+	}
+	endpointBase := base + "/" + endpoint
+
+	env := map[string]string{
+		"RUNPOD_ENDPOINT_ID":         endpoint,
+		"RUNPOD_ENDPOINT_BASE_URL":   base,
+		"RUNPOD_PING_INTERVAL":       "10000",
+		"RUNPOD_WEBHOOK_GET_JOB":     endpointBase + "/job-take/$ID?",
+		"RUNPOD_WEBHOOK_PING":        endpointBase + "/ping/$RUNPOD_POD_ID",
+		"RUNPOD_WEBHOOK_POST_OUTPUT": endpointBase + "/job-done/$RUNPOD_POD_ID/$ID?",
+		"RUNPOD_WEBHOOK_POST_STREAM": endpointBase + "/job-stream/$RUNPOD_POD_ID/$ID?",
+	}
+	if gpuCount > 0 {
+		env["RUNPOD_GPU_COUNT"] = strconv.Itoa(gpuCount)
+	}
+	if m.runPodAPIKey != "" {
+		env["RUNPOD_AI_API_KEY"] = m.runPodAPIKey
+		env["RUNPOD_API_KEY"] = m.runPodAPIKey
+	}
+	for k, v := range m.globalEnv {
+		if strings.HasPrefix(k, runPodEnvPrefix) {
+			env[k] = strings.ReplaceAll(v, "{{.Endpoint}}", endpoint)
+		}
+	}
+	return env
+}
+
+// isRunPodManagedEnv reports whether an env var is set by the compatibility layer
+func (m *Manager) isRunPodManagedEnv(name string, managed map[string]string) bool {
+	if _, ok := managed[name]; ok {
+		return true
+	}
+	for _, field := range runPodFieldEnv {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRunPodCompat adds or removes the RunPod variables of a live worker container.
+// Endpoint env vars that happen to use the RUNPOD_ prefix are left alone.
+func (m *Manager) applyRunPodCompat(container *corev1.Container, endpoint string, enabled bool) {
+	managed := m.runPodEnv(endpoint, containerGPUCount(container))
+	env := make([]corev1.EnvVar, 0, len(container.Env)+len(managed)+len(runPodFieldEnv))
+	for _, e := range container.Env {
+		if !m.isRunPodManagedEnv(e.Name, managed) {
+			env = append(env, e)
+		}
+	}
+
+	if enabled {
+		for _, name := range runPodFieldEnv {
+			env = append(env, corev1.EnvVar{
+				Name:      name,
+				ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+			})
+		}
+		names := make([]string, 0, len(managed))
+		for name := range managed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			env = append(env, corev1.EnvVar{Name: name, Value: managed[name]})
+		}
+	}
+	container.Env = env
+}
+
+// containerGPUCount returns the GPUs requested by a container, 0 for CPU workers
+func containerGPUCount(container *corev1.Container) int {
+	if q, ok := container.Resources.Limits["nvidia.com/gpu"]; ok {
+		return int(q.Value())
+	}
+	return 0
+}
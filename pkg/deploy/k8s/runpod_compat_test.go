@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func envNames(env []corev1.EnvVar) []string {
+	names := make([]string, 0, len(env))
+	for _, e := range env {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestRunPodEnv(t *testing.T) {
+	m := &Manager{globalEnv: map[string]string{
+		"RUNPOD_PING_INTERVAL": "5000",
+		"RUNPOD_DEBUG_LEVEL":   "{{.Endpoint}}-debug",
+		"WAVERLESS_PING":       "ignored",
+	}}
+	m.SetRunPodCompat("http://api.internal/v2/", "secret")
+
+	env := m.runPodEnv("flux", 2)
+	assert.Equal(t, "flux", env["RUNPOD_ENDPOINT_ID"])
+	assert.Equal(t, "http://api.internal/v2", env["RUNPOD_ENDPOINT_BASE_URL"])
+	assert.Equal(t, "http://api.internal/v2/flux/job-take/$ID?", env["RUNPOD_WEBHOOK_GET_JOB"])
+	assert.Equal(t, "http://api.internal/v2/flux/ping/$RUNPOD_POD_ID", env["RUNPOD_WEBHOOK_PING"])
+	assert.Equal(t, "2", env["RUNPOD_GPU_COUNT"])
+	assert.Equal(t, "secret", env["RUNPOD_AI_API_KEY"])
+	assert.Equal(t, "secret", env["RUNPOD_API_KEY"])
+
+	// RUNPOD_* global env overrides the defaults, other prefixes are not part of the layer
+	assert.Equal(t, "5000", env["RUNPOD_PING_INTERVAL"])
+	assert.Equal(t, "flux-debug", env["RUNPOD_DEBUG_LEVEL"])
+	assert.NotContains(t, env, "WAVERLESS_PING")
+}
+
+func TestRunPodEnv_Defaults(t *testing.T) {
+	m := &Manager{}
+	env := m.runPodEnv("flux", 0)
+	assert.Equal(t, DefaultRunPodAPIBase+"/flux/job-done/$RUNPOD_POD_ID/$ID?", env["RUNPOD_WEBHOOK_POST_OUTPUT"])
+	assert.NotContains(t, env, "RUNPOD_GPU_COUNT")
+	assert.NotContains(t, env, "RUNPOD_API_KEY")
+}
+
+func TestApplyRunPodCompat(t *testing.T) {
+	m := &Manager{}
+	container := &corev1.Container{
+		Env: []corev1.EnvVar{
+			{Name: "MODEL", Value: "flux"},
+			{Name: "WAVERLESS_ENDPOINT_ID", Value: "flux"},
+		},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("4")},
+		},
+	}
+
+	m.applyRunPodCompat(container, "flux", true)
+	names := envNames(container.Env)
+	assert.Contains(t, names, "RUNPOD_POD_ID")
+	assert.Contains(t, names, "RUNPOD_ENDPOINT_ID")
+	assert.Contains(t, names, "RUNPOD_WEBHOOK_GET_JOB")
+	assert.Contains(t, names, "RUNPOD_GPU_COUNT")
+
+	// Enabling twice does not duplicate variables
+	before := len(container.Env)
+	m.applyRunPodCompat(container, "flux", true)
+	require.Len(t, container.Env, before)
+
+	// Disabling removes the layer and keeps everything else
+	container.Env = append(container.Env, corev1.EnvVar{Name: "RUNPOD_CUSTOM", Value: "kept"})
+	m.applyRunPodCompat(container, "flux", false)
+	assert.Equal(t, []string{"MODEL", "WAVERLESS_ENDPOINT_ID", "RUNPOD_CUSTOM"}, envNames(container.Env))
+}
+
+func TestRunPodCompatEnabled(t *testing.T) {
+	enabled, disabled := true, false
+	assert.True(t, runPodCompatEnabled(nil))
+	assert.True(t, runPodCompatEnabled(&enabled))
+	assert.False(t, runPodCompatEnabled(&disabled))
+}
@@ -0,0 +1,215 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
+)
+
+// SecurityProfile container hardening applied to worker pods of a spec (per platform)
+type SecurityProfile struct {
+	RunAsNonRoot           bool   `yaml:"runAsNonRoot,omitempty" json:"runAsNonRoot,omitempty"`
+	RunAsUser              *int64 `yaml:"runAsUser,omitempty" json:"runAsUser,omitempty"`
+	RunAsGroup             *int64 `yaml:"runAsGroup,omitempty" json:"runAsGroup,omitempty"`
+	ReadOnlyRootFilesystem bool   `yaml:"readOnlyRootFilesystem,omitempty" json:"readOnlyRootFilesystem,omitempty"` // Image must only write to mounted volumes
+	SeccompProfile         string `yaml:"seccompProfile,omitempty" json:"seccompProfile,omitempty"`                 // RuntimeDefault, Unconfined or Localhost/<path>
+	AppArmorProfile        string `yaml:"appArmorProfile,omitempty" json:"appArmorProfile,omitempty"`               // RuntimeDefault, Unconfined or Localhost/<name>
+	Privileged             bool   `yaml:"privileged,omitempty" json:"privileged,omitempty"`                         // Requires a security policy exception
+}
+
+// PrivilegeDeniedError is returned when a deploy requests a privilege the security policy does not grant
+type PrivilegeDeniedError struct {
+	Endpoint  string
+	Privilege string
+}
+
+func (e *PrivilegeDeniedError) Error() string {
+	return fmt.Sprintf("endpoint %s requests %s, which requires a security policy exception", e.Endpoint, e.Privilege)
+}
+
+// Is classifies a denied privilege as unauthorized
+func (e *PrivilegeDeniedError) Is(target error) bool {
+	return target == interfaces.ErrUnauthorized
+}
+
+// SecurityPolicy decides which endpoints may run with elevated privileges
+type SecurityPolicy struct {
+	config config.SecurityConfig
+	now    func() time.Time
+}
+
+// NewSecurityPolicy creates a security policy from config
+func NewSecurityPolicy(cfg config.SecurityConfig) *SecurityPolicy {
+	return &SecurityPolicy{config: cfg, now: time.Now}
+}
+
+// Authorize returns a PrivilegeDeniedError for the first privilege not granted to endpoint.
+// A nil policy allows everything.
+func (p *SecurityPolicy) Authorize(endpoint string, privileges ...string) error {
+	if p == nil {
+		return nil
+	}
+	for _, privilege := range privileges {
+		if !p.allows(endpoint, privilege) {
+			return &PrivilegeDeniedError{Endpoint: endpoint, Privilege: privilege}
+		}
+	}
+	return nil
+}
+
+// allows checks the global switches, then unexpired exceptions matching endpoint
+func (p *SecurityPolicy) allows(endpoint, privilege string) bool {
+	switch privilege {
+	case config.PrivilegePrivileged:
+		if p.config.AllowPrivileged {
+			return true
+		}
+	case config.PrivilegePtrace:
+		if p.config.AllowPtrace {
+			return true
+		}
+	}
+
+	now := p.now()
+	for _, exception := range p.config.Exceptions {
+		if !exception.ExpiresAt.IsZero() && now.After(exception.ExpiresAt) {
+			continue
+		}
+		if !matchesEndpoint(exception.Endpoint, endpoint) {
+			continue
+		}
+		for _, granted := range exception.Privileges {
+			if strings.EqualFold(granted, privilege) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesEndpoint matches an exact endpoint name or a "prefix*" pattern
+func matchesEndpoint(pattern, endpoint string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(endpoint, prefix)
+	}
+	return pattern == endpoint
+}
+
+// requestedPrivileges lists the privileges a deploy needs
+func requestedPrivileges(profile *SecurityProfile, enablePtrace bool) []string {
+	var privileges []string
+	if profile != nil && profile.Privileged {
+		privileges = append(privileges, config.PrivilegePrivileged)
+	}
+	if enablePtrace {
+		privileges = append(privileges, config.PrivilegePtrace)
+	}
+	return privileges
+}
+
+// buildContainerSecurityContext builds the worker container securityContext from the
+// spec profile and the ptrace flag; nil when neither requires one
+func buildContainerSecurityContext(profile *SecurityProfile, enablePtrace bool) (*corev1.SecurityContext, error) {
+	sc := &corev1.SecurityContext{}
+	if profile != nil {
+		if err := applySecurityProfile(sc, profile); err != nil {
+			return nil, err
+		}
+	}
+	if enablePtrace {
+		sc.Capabilities = &corev1.Capabilities{Add: []corev1.Capability{"SYS_PTRACE"}}
+	}
+	if isEmptySecurityContext(sc) {
+		return nil, nil
+	}
+	return sc, nil
+}
+
+// applySecurityProfile sets the profile fields on sc, leaving capabilities untouched
+func applySecurityProfile(sc *corev1.SecurityContext, profile *SecurityProfile) error {
+	seccomp, err := parseSeccompProfile(profile.SeccompProfile)
+	if err != nil {
+		return err
+	}
+	appArmor, err := parseAppArmorProfile(profile.AppArmorProfile)
+	if err != nil {
+		return err
+	}
+
+	sc.RunAsNonRoot = optionalTrue(profile.RunAsNonRoot)
+	sc.RunAsUser = profile.RunAsUser
+	sc.RunAsGroup = profile.RunAsGroup
+	sc.ReadOnlyRootFilesystem = optionalTrue(profile.ReadOnlyRootFilesystem)
+	sc.Privileged = optionalTrue(profile.Privileged)
+	sc.SeccompProfile = seccomp
+	sc.AppArmorProfile = appArmor
+	if profile.RunAsNonRoot && !profile.Privileged {
+		allow := false
+		sc.AllowPrivilegeEscalation = &allow
+	} else {
+		sc.AllowPrivilegeEscalation = nil
+	}
+	return nil
+}
+
+// isEmptySecurityContext reports whether sc sets nothing
+func isEmptySecurityContext(sc *corev1.SecurityContext) bool {
+	return sc.Capabilities == nil && sc.Privileged == nil && sc.SELinuxOptions == nil &&
+		sc.WindowsOptions == nil && sc.RunAsUser == nil && sc.RunAsGroup == nil &&
+		sc.RunAsNonRoot == nil && sc.ReadOnlyRootFilesystem == nil &&
+		sc.AllowPrivilegeEscalation == nil && sc.ProcMount == nil &&
+		sc.SeccompProfile == nil && sc.AppArmorProfile == nil
+}
+
+// optionalTrue returns a pointer to true when set, nil otherwise (so unset fields stay out of the YAML)
+func optionalTrue(set bool) *bool {
+	if !set {
+		return nil
+	}
+	return &set
+}
+
+// parseSeccompProfile parses RuntimeDefault, Unconfined or Localhost/<path>
+func parseSeccompProfile(value string) (*corev1.SeccompProfile, error) {
+	if value == "" {
+		return nil, nil
+	}
+	kind, path, _ := strings.Cut(value, "/")
+	switch strings.ToLower(kind) {
+	case "runtimedefault":
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}, nil
+	case "unconfined":
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}, nil
+	case "localhost":
+		if path == "" {
+			return nil, fmt.Errorf("seccomp profile %q: Localhost requires a profile path", value)
+		}
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &path}, nil
+	}
+	return nil, fmt.Errorf("unsupported seccomp profile %q", value)
+}
+
+// parseAppArmorProfile parses RuntimeDefault, Unconfined or Localhost/<name>
+func parseAppArmorProfile(value string) (*corev1.AppArmorProfile, error) {
+	if value == "" {
+		return nil, nil
+	}
+	kind, name, _ := strings.Cut(value, "/")
+	switch strings.ToLower(kind) {
+	case "runtimedefault":
+		return &corev1.AppArmorProfile{Type: corev1.AppArmorProfileTypeRuntimeDefault}, nil
+	case "unconfined":
+		return &corev1.AppArmorProfile{Type: corev1.AppArmorProfileTypeUnconfined}, nil
+	case "localhost":
+		if name == "" {
+			return nil, fmt.Errorf("apparmor profile %q: Localhost requires a profile name", value)
+		}
+		return &corev1.AppArmorProfile{Type: corev1.AppArmorProfileTypeLocalhost, LocalhostProfile: &name}, nil
+	}
+	return nil, fmt.Errorf("unsupported apparmor profile %q", value)
+}
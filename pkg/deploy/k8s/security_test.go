@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"waverless/pkg/config"
+)
+
+func TestSecurityPolicy_Authorize(t *testing.T) {
+	now := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	policy := NewSecurityPolicy(config.SecurityConfig{
+		Exceptions: []config.SecurityException{
+			{Endpoint: "profiling-*", Privileges: []string{"ptrace"}, Reason: "perf"},
+			{Endpoint: "driver-test", Privileges: []string{"privileged", "ptrace"}, ExpiresAt: now.Add(-time.Hour)},
+			{Endpoint: "gpu-burn", Privileges: []string{"Privileged"}, ExpiresAt: now.Add(time.Hour)},
+		},
+	})
+	policy.now = func() time.Time { return now }
+
+	assert.NoError(t, policy.Authorize("any-endpoint"))
+	assert.NoError(t, policy.Authorize("profiling-flux", config.PrivilegePtrace))
+	assert.NoError(t, policy.Authorize("gpu-burn", config.PrivilegePrivileged))
+
+	err := policy.Authorize("flux", config.PrivilegePtrace)
+	var denied *PrivilegeDeniedError
+	require.True(t, errors.As(err, &denied))
+	assert.Equal(t, "flux", denied.Endpoint)
+	assert.Equal(t, config.PrivilegePtrace, denied.Privilege)
+
+	// Exception for a different privilege does not apply
+	assert.Error(t, policy.Authorize("profiling-flux", config.PrivilegePrivileged))
+	// Expired exception
+	assert.Error(t, policy.Authorize("driver-test", config.PrivilegePtrace))
+
+	// Global switch
+	open := NewSecurityPolicy(config.SecurityConfig{AllowPtrace: true})
+	assert.NoError(t, open.Authorize("flux", config.PrivilegePtrace))
+	assert.Error(t, open.Authorize("flux", config.PrivilegePrivileged))
+
+	// Nil policy allows everything
+	var none *SecurityPolicy
+	assert.NoError(t, none.Authorize("flux", config.PrivilegePrivileged))
+}
+
+func TestBuildContainerSecurityContext(t *testing.T) {
+	sc, err := buildContainerSecurityContext(nil, false)
+	require.NoError(t, err)
+	assert.Nil(t, sc)
+
+	sc, err = buildContainerSecurityContext(nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.Capability{"SYS_PTRACE"}, sc.Capabilities.Add)
+
+	sc, err = buildContainerSecurityContext(&SecurityProfile{
+		RunAsNonRoot:           true,
+		ReadOnlyRootFilesystem: true,
+		SeccompProfile:         "RuntimeDefault",
+		AppArmorProfile:        "Localhost/worker",
+	}, false)
+	require.NoError(t, err)
+	assert.True(t, *sc.RunAsNonRoot)
+	assert.True(t, *sc.ReadOnlyRootFilesystem)
+	assert.False(t, *sc.AllowPrivilegeEscalation)
+	assert.Nil(t, sc.Privileged)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, sc.SeccompProfile.Type)
+	assert.Equal(t, corev1.AppArmorProfileTypeLocalhost, sc.AppArmorProfile.Type)
+	assert.Equal(t, "worker", *sc.AppArmorProfile.LocalhostProfile)
+
+	_, err = buildContainerSecurityContext(&SecurityProfile{SeccompProfile: "Localhost"}, false)
+	assert.Error(t, err)
+	_, err = buildContainerSecurityContext(&SecurityProfile{AppArmorProfile: "strict"}, false)
+	assert.Error(t, err)
+}
+
+func TestApplySecurityProfile_KeepsCapabilities(t *testing.T) {
+	sc := &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_PTRACE"}}}
+	require.NoError(t, applySecurityProfile(sc, &SecurityProfile{RunAsNonRoot: true}))
+	assert.True(t, *sc.RunAsNonRoot)
+
+	// Switching to a spec without a profile clears it but keeps SYS_PTRACE
+	require.NoError(t, applySecurityProfile(sc, &SecurityProfile{}))
+	assert.Nil(t, sc.RunAsNonRoot)
+	assert.Nil(t, sc.AllowPrivilegeEscalation)
+	assert.False(t, isEmptySecurityContext(sc))
+	assert.Equal(t, []corev1.Capability{"SYS_PTRACE"}, sc.Capabilities.Add)
+}
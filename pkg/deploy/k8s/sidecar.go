@@ -0,0 +1,203 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"waverless/pkg/interfaces"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Sidecar container and volume naming
+const (
+	SidecarContainerPrefix   = "sidecar-"
+	sidecarClaimVolumePrefix = "sidecar-claim-" // PVC of the worker, mounted by sidecars
+	scratchVolumePrefix      = "scratch-"       // emptyDir shared by sidecars, init containers and the worker
+	sidecarShellCmd          = "/bin/sh"
+)
+
+// SidecarInfo sidecar or init container info for template rendering
+type SidecarInfo struct {
+	Name         string            `json:"name"` // Container name (sidecar-<name> or init-<name>)
+	Image        string            `json:"image"`
+	CommandJSON  string            `json:"commandJSON,omitempty"` // Inline JSON array
+	ArgsJSON     string            `json:"argsJSON,omitempty"`    // Inline JSON array
+	EnvJSON      string            `json:"envJSON,omitempty"`     // Inline JSON array of EnvVar
+	VolumeMounts []VolumeMountInfo `json:"volumeMounts,omitempty"`
+}
+
+// IsSidecarContainerName reports whether a container name belongs to a sidecar
+func IsSidecarContainerName(name string) bool {
+	return strings.HasPrefix(name, SidecarContainerPrefix)
+}
+
+// SidecarShellCommand is the shell started when exec'ing into a sidecar
+func SidecarShellCommand() []string {
+	return []string{sidecarShellCmd}
+}
+
+// isSidecarVolume reports whether a pod volume only exists for sidecars
+func isSidecarVolume(name string) bool {
+	return strings.HasPrefix(name, sidecarClaimVolumePrefix)
+}
+
+// applySidecars validates the sidecars of a request and adds their containers and volumes to
+// the render context. claims are the PVCs mounted by the worker.
+func (m *Manager) applySidecars(ctx *RenderContext, sidecars []interfaces.Sidecar, claims []interfaces.VolumeMount) error {
+	if len(sidecars) == 0 {
+		return nil
+	}
+
+	volumes := newPodVolumes(ctx, claims)
+	names := make(map[string]bool, len(sidecars))
+	for _, sidecar := range sidecars {
+		if err := validateContainer("sidecar", SidecarContainerPrefix, sidecar); err != nil {
+			return err
+		}
+		if names[sidecar.Name] {
+			return fmt.Errorf("duplicate sidecar name %q", sidecar.Name)
+		}
+		names[sidecar.Name] = true
+
+		info, err := m.containerInfo("sidecar", SidecarContainerPrefix, sidecar, volumes)
+		if err != nil {
+			return err
+		}
+		ctx.Sidecars = append(ctx.Sidecars, info)
+	}
+	return nil
+}
+
+// containerInfo renders an extra container (sidecar or init container) and resolves its mounts
+func (m *Manager) containerInfo(kind, prefix string, c interfaces.Sidecar, volumes *podVolumes) (SidecarInfo, error) {
+	info := SidecarInfo{
+		Name:  prefix + c.Name,
+		Image: m.rewriteImage(context.Background(), c.Image),
+	}
+	if len(c.Command) > 0 {
+		commandJSON, _ := json.Marshal(c.Command)
+		info.CommandJSON = string(commandJSON)
+	}
+	if len(c.Args) > 0 {
+		argsJSON, _ := json.Marshal(c.Args)
+		info.ArgsJSON = string(argsJSON)
+	}
+	if len(c.Env) > 0 {
+		env := make([]corev1.EnvVar, 0, len(c.Env))
+		for k, v := range c.Env {
+			env = append(env, corev1.EnvVar{Name: k, Value: v})
+		}
+		sort.Slice(env, func(i, j int) bool { return env[i].Name < env[j].Name })
+		envJSON, err := json.Marshal(env)
+		if err != nil {
+			return info, fmt.Errorf("failed to marshal env of %s %s: %w", kind, c.Name, err)
+		}
+		info.EnvJSON = string(envJSON)
+	}
+	for _, mount := range c.Mounts {
+		vm, err := volumes.mount(mount)
+		if err != nil {
+			return info, fmt.Errorf("%s %s: %w", kind, c.Name, err)
+		}
+		info.VolumeMounts = append(info.VolumeMounts, vm)
+	}
+	return info, nil
+}
+
+// podVolumes resolves the mounts of sidecars and init containers against the volumes already
+// in the render context, so containers naming the same volume share it
+type podVolumes struct {
+	ctx          *RenderContext
+	workerPaths  map[string]bool   // Mount paths used by the worker
+	claimNames   map[string]bool   // PVCs of the worker
+	claimVolumes map[string]string // PVC name -> sidecar claim volume name
+	scratch      map[string]bool   // Scratch volume names
+}
+
+func newPodVolumes(ctx *RenderContext, claims []interfaces.VolumeMount) *podVolumes {
+	v := &podVolumes{
+		ctx:          ctx,
+		workerPaths:  make(map[string]bool, len(ctx.VolumeMounts)),
+		claimNames:   make(map[string]bool, len(claims)),
+		claimVolumes: make(map[string]string),
+		scratch:      make(map[string]bool, len(ctx.ScratchVolumes)),
+	}
+	for _, vm := range ctx.VolumeMounts {
+		v.workerPaths[vm.MountPath] = true
+	}
+	for _, vm := range claims {
+		v.claimNames[vm.PVCName] = true
+	}
+	for _, vol := range ctx.Volumes {
+		if isSidecarVolume(vol.Name) {
+			v.claimVolumes[vol.PVCName] = vol.Name
+		}
+	}
+	for _, name := range ctx.ScratchVolumes {
+		v.scratch[name] = true
+	}
+	return v
+}
+
+// mount returns the volume mount of a container, adding the volume to the pod on first use
+func (v *podVolumes) mount(mount interfaces.SidecarMount) (VolumeMountInfo, error) {
+	var volumeName string
+	if v.claimNames[mount.Volume] {
+		volumeName = v.claimVolumes[mount.Volume]
+		if volumeName == "" {
+			volumeName = fmt.Sprintf("%s%d", sidecarClaimVolumePrefix, len(v.claimVolumes))
+			v.claimVolumes[mount.Volume] = volumeName
+			v.ctx.Volumes = append(v.ctx.Volumes, VolumeInfo{Name: volumeName, PVCName: mount.Volume})
+		}
+	} else {
+		volumeName = scratchVolumePrefix + mount.Volume
+		if !v.scratch[volumeName] {
+			if v.workerPaths[mount.MountPath] {
+				return VolumeMountInfo{}, fmt.Errorf("mount path %s of scratch volume %s is used by a worker volume", mount.MountPath, mount.Volume)
+			}
+			v.scratch[volumeName] = true
+			v.ctx.ScratchVolumes = append(v.ctx.ScratchVolumes, volumeName)
+			v.ctx.VolumeMounts = append(v.ctx.VolumeMounts, VolumeMountInfo{Name: volumeName, MountPath: mount.MountPath})
+			v.workerPaths[mount.MountPath] = true
+		}
+	}
+	return VolumeMountInfo{
+		Name:      volumeName,
+		MountPath: mount.MountPath,
+		ReadOnly:  mount.ReadOnly,
+	}, nil
+}
+
+// validateContainer checks the fields of a sidecar or init container
+func validateContainer(kind, prefix string, c interfaces.Sidecar) error {
+	if !dns1123LabelRegex.MatchString(c.Name) || len(prefix+c.Name) > 63 {
+		return fmt.Errorf("invalid %s name %q: must be a lowercase DNS label of at most %d characters", kind, c.Name, 63-len(prefix))
+	}
+	if strings.TrimSpace(c.Image) == "" {
+		return fmt.Errorf("%s %s: image is required", kind, c.Name)
+	}
+	for key := range c.Env {
+		if key == "" || strings.ContainsAny(key, "= ") {
+			return fmt.Errorf("%s %s: invalid env var name %q", kind, c.Name, key)
+		}
+	}
+	paths := make(map[string]bool, len(c.Mounts))
+	for _, mount := range c.Mounts {
+		if !dns1123LabelRegex.MatchString(mount.Volume) || len(scratchVolumePrefix+mount.Volume) > 63 {
+			return fmt.Errorf("%s %s: invalid volume name %q", kind, c.Name, mount.Volume)
+		}
+		if !path.IsAbs(mount.MountPath) {
+			return fmt.Errorf("%s %s: mount path %q must be absolute", kind, c.Name, mount.MountPath)
+		}
+		if paths[mount.MountPath] {
+			return fmt.Errorf("%s %s: mount path %s is used twice", kind, c.Name, mount.MountPath)
+		}
+		paths[mount.MountPath] = true
+	}
+	return nil
+}
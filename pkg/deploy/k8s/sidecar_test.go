@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"waverless/pkg/interfaces"
+)
+
+func sidecarRenderContext() *RenderContext {
+	return &RenderContext{
+		Endpoint:      "flux",
+		Namespace:     "wavespeed",
+		Image:         "flux:latest",
+		Replicas:      1,
+		ContainerName: "flux-worker",
+		ContainerPort: 8000,
+		ProxyPort:     8001,
+		MemoryRequest: "8Gi",
+		Volumes:       []VolumeInfo{{Name: "pvc-0", PVCName: "model-cache"}},
+		VolumeMounts:  []VolumeMountInfo{{Name: "pvc-0", MountPath: "/cache"}},
+	}
+}
+
+func TestDeploymentTemplate_RendersSidecars(t *testing.T) {
+	m := &Manager{}
+	ctx := sidecarRenderContext()
+	claims := []interfaces.VolumeMount{{PVCName: "model-cache", MountPath: "/cache"}}
+	require.NoError(t, m.applySidecars(ctx, []interfaces.Sidecar{
+		{
+			Name:    "downloader",
+			Image:   "busybox:1.36",
+			Command: []string{"sh", "-c"},
+			Args:    []string{"wget -O /models/model.bin \"$MODEL_URL\" && sleep infinity"},
+			Env:     map[string]string{"MODEL_URL": "https://example.com/model.bin?a=1&b=2"},
+			Mounts: []interfaces.SidecarMount{
+				{Volume: "models", MountPath: "/models"},
+				{Volume: "model-cache", MountPath: "/cache", ReadOnly: true},
+			},
+		},
+		{
+			Name:   "exporter",
+			Image:  "prom/node-exporter:v1.8.0",
+			Mounts: []interfaces.SidecarMount{{Volume: "models", MountPath: "/data"}},
+		},
+	}, claims))
+
+	renderer := NewTemplateRenderer("../../../config/templates")
+	content, err := renderer.Render("deployment.yaml", ctx)
+	require.NoError(t, err)
+
+	var deployment appsv1.Deployment
+	require.NoError(t, yaml.Unmarshal([]byte(content), &deployment))
+	podSpec := deployment.Spec.Template.Spec
+	require.Len(t, podSpec.Containers, 4)
+	assert.Equal(t, "flux-worker", podSpec.Containers[0].Name, "the worker stays the first container")
+
+	downloader := podSpec.Containers[2]
+	assert.Equal(t, "sidecar-downloader", downloader.Name)
+	assert.Equal(t, "busybox:1.36", downloader.Image)
+	assert.Equal(t, []string{"sh", "-c"}, downloader.Command)
+	assert.Equal(t, []string{"wget -O /models/model.bin \"$MODEL_URL\" && sleep infinity"}, downloader.Args)
+	assert.Equal(t, []corev1.EnvVar{{Name: "MODEL_URL", Value: "https://example.com/model.bin?a=1&b=2"}}, downloader.Env)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "scratch-models", MountPath: "/models"},
+		{Name: "sidecar-claim-0", MountPath: "/cache", ReadOnly: true},
+	}, downloader.VolumeMounts)
+
+	exporter := podSpec.Containers[3]
+	assert.Equal(t, "sidecar-exporter", exporter.Name)
+	assert.Empty(t, exporter.Command)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "scratch-models", MountPath: "/data"}}, exporter.VolumeMounts)
+
+	// The worker mounts the scratch volume at the path of its first sidecar mount
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "scratch-models", MountPath: "/models"})
+
+	volumes := make(map[string]corev1.VolumeSource)
+	for _, v := range podSpec.Volumes {
+		volumes[v.Name] = v.VolumeSource
+	}
+	require.Contains(t, volumes, "scratch-models")
+	assert.NotNil(t, volumes["scratch-models"].EmptyDir)
+	require.Contains(t, volumes, "sidecar-claim-0")
+	assert.Equal(t, "model-cache", volumes["sidecar-claim-0"].PersistentVolumeClaim.ClaimName)
+}
+
+func TestApplySidecars_Validation(t *testing.T) {
+	m := &Manager{}
+	tests := []struct {
+		name     string
+		sidecars []interfaces.Sidecar
+	}{
+		{"invalid name", []interfaces.Sidecar{{Name: "Downloader", Image: "busybox"}}},
+		{"missing image", []interfaces.Sidecar{{Name: "downloader"}}},
+		{"duplicate name", []interfaces.Sidecar{{Name: "a", Image: "busybox"}, {Name: "a", Image: "busybox"}}},
+		{"relative mount path", []interfaces.Sidecar{{Name: "a", Image: "busybox", Mounts: []interfaces.SidecarMount{{Volume: "models", MountPath: "models"}}}}},
+		{"invalid volume", []interfaces.Sidecar{{Name: "a", Image: "busybox", Mounts: []interfaces.SidecarMount{{Volume: "My_Models", MountPath: "/models"}}}}},
+		{"scratch on worker path", []interfaces.Sidecar{{Name: "a", Image: "busybox", Mounts: []interfaces.SidecarMount{{Volume: "models", MountPath: "/cache"}}}}},
+		{"invalid env", []interfaces.Sidecar{{Name: "a", Image: "busybox", Env: map[string]string{"A=B": "c"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, m.applySidecars(sidecarRenderContext(), tt.sidecars, nil))
+		})
+	}
+}
+
+func TestContainerNames(t *testing.T) {
+	assert.True(t, IsSidecarContainerName("sidecar-downloader"))
+	assert.False(t, IsSidecarContainerName("flux-worker"))
+	assert.True(t, isSidecarVolume("sidecar-claim-0"))
+	assert.False(t, isSidecarVolume("pvc-0"))
+}
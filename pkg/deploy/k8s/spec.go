@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -26,16 +27,21 @@ type SpecResources struct {
 	Memory            string `yaml:"memory" json:"memory"`
 	GPU               string `yaml:"gpu,omitempty" json:"gpu,omitempty"`
 	GpuType           string `yaml:"gpuType,omitempty" json:"gpuType,omitempty"`
+	GpuTypeFallbacks  []string `yaml:"gpuTypeFallbacks,omitempty" json:"gpuTypeFallbacks,omitempty"` // GPU types used in order when gpuType has no capacity
 	EphemeralStorage  string `yaml:"ephemeralStorage" json:"ephemeralStorage"`
 	ShmSize           string `yaml:"shmSize,omitempty" json:"shmSize,omitempty"` // Shared memory size
 }
 
 // PlatformConfig 平台特定配置
 type PlatformConfig struct {
-	NodeSelector map[string]string `yaml:"nodeSelector" json:"nodeSelector"`
-	Tolerations  []Toleration      `yaml:"tolerations" json:"tolerations"`
-	Labels       map[string]string `yaml:"labels" json:"labels"`
-	Annotations  map[string]string `yaml:"annotations" json:"annotations"`
+	NodeSelector map[string]string   `yaml:"nodeSelector" json:"nodeSelector"`
+	Tolerations  []Toleration        `yaml:"tolerations" json:"tolerations"`
+	Labels       map[string]string   `yaml:"labels" json:"labels"`
+	Annotations  map[string]string   `yaml:"annotations" json:"annotations"`
+	Security     *SecurityProfile    `yaml:"security,omitempty" json:"security,omitempty"`         // Container hardening (runAsNonRoot, seccomp, ...)
+	AntiAffinity *AntiAffinityPolicy `yaml:"antiAffinity,omitempty" json:"antiAffinity,omitempty"` // Replica spreading across nodes/zones (default: preferred)
+	Topology     *TopologyHints      `yaml:"topology,omitempty" json:"topology,omitempty"`         // Multi-GPU topology hints (host network, IB, hugepages, NCCL env)
+	GPUTypeNodeSelectors map[string]map[string]string `yaml:"gpuTypeNodeSelectors,omitempty" json:"gpuTypeNodeSelectors,omitempty"` // Fallback GPU type -> node selector overlaid on nodeSelector
 }
 
 // Toleration 容忍度
@@ -126,7 +132,7 @@ func (m *SpecManager) GetSpec(name string) (*ResourceSpec, error) {
 	// Fallback to YAML-based specs
 	spec, exists := m.specs[name]
 	if !exists {
-		return nil, fmt.Errorf("spec not found: %s", name)
+		return nil, interfaces.ProviderErrorf(interfaces.ErrNotFound, "spec not found: %s", name)
 	}
 	return spec, nil
 }
@@ -304,6 +310,59 @@ func (m *SpecManager) convertSpecInfoToResourceSpec(specInfo *interfaces.SpecInf
 					}
 				}
 
+				// Convert security profile
+				if securityData, ok := platformMap["security"].(map[string]interface{}); ok {
+					if securityJSON, err := json.Marshal(securityData); err == nil {
+						var profile SecurityProfile
+						if err := json.Unmarshal(securityJSON, &profile); err == nil {
+							platform.Security = &profile
+						} else {
+							logger.WarnCtx(ctx, "[SPEC-CONVERT] invalid security profile for platform %s: %v", platformName, err)
+						}
+					}
+				}
+
+				// Convert anti-affinity policy
+				if antiAffinityData, ok := platformMap["antiAffinity"].(map[string]interface{}); ok {
+					if antiAffinityJSON, err := json.Marshal(antiAffinityData); err == nil {
+						var policy AntiAffinityPolicy
+						if err := json.Unmarshal(antiAffinityJSON, &policy); err == nil {
+							platform.AntiAffinity = &policy
+						} else {
+							logger.WarnCtx(ctx, "[SPEC-CONVERT] invalid anti-affinity policy for platform %s: %v", platformName, err)
+						}
+					}
+				}
+
+				// Convert fallback GPU type node selectors
+				if selectorsData, ok := platformMap["gpuTypeNodeSelectors"].(map[string]interface{}); ok {
+					platform.GPUTypeNodeSelectors = make(map[string]map[string]string)
+					for gpuType, selectorData := range selectorsData {
+						selector, ok := selectorData.(map[string]interface{})
+						if !ok {
+							continue
+						}
+						platform.GPUTypeNodeSelectors[gpuType] = make(map[string]string)
+						for k, v := range selector {
+							if str, ok := v.(string); ok {
+								platform.GPUTypeNodeSelectors[gpuType][k] = str
+							}
+						}
+					}
+				}
+
+				// Convert topology hints
+				if topologyData, ok := platformMap["topology"].(map[string]interface{}); ok {
+					if topologyJSON, err := json.Marshal(topologyData); err == nil {
+						var hints TopologyHints
+						if err := json.Unmarshal(topologyJSON, &hints); err == nil {
+							platform.Topology = &hints
+						} else {
+							logger.WarnCtx(ctx, "[SPEC-CONVERT] invalid topology hints for platform %s: %v", platformName, err)
+						}
+					}
+				}
+
 				platforms[platformName] = platform
 			}
 		}
@@ -318,6 +377,7 @@ func (m *SpecManager) convertSpecInfoToResourceSpec(specInfo *interfaces.SpecInf
 			Memory:           specInfo.Resources.Memory,
 			GPU:              specInfo.Resources.GPU,
 			GpuType:          specInfo.Resources.GPUType,
+			GpuTypeFallbacks: specInfo.Resources.GPUTypeFallbacks,
 			EphemeralStorage: specInfo.Resources.EphemeralStorage,
 			ShmSize:          specInfo.Resources.ShmSize,
 		},
@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"waverless/pkg/interfaces"
+)
+
+// specOverrideAnnotation records the spec override of a deployment, so that it is re-applied
+// when the endpoint moves to another spec and shows up as drift in the endpoint status
+const specOverrideAnnotation = "waverless.io/spec-override"
+
+// SpecOverrideLimits bounds the spec overrides of deploy requests. Extra CPU and memory are
+// refused while their limit is empty; shmSize and ephemeralStorage are unbounded while theirs is.
+type SpecOverrideLimits struct {
+	MaxExtraCPU         string
+	MaxExtraMemory      string
+	MaxShmSize          string
+	MaxEphemeralStorage string
+}
+
+// SetSpecOverrideLimits sets the limits of the spec overrides of deploy and update requests
+func (m *Manager) SetSpecOverrideLimits(limits SpecOverrideLimits) {
+	m.specOverrideLimits = limits
+}
+
+// Validate checks a spec override and the shmSize and ephemeralStorage overrides of a request
+// against the limits (empty values are not overridden)
+func (l SpecOverrideLimits) Validate(override *interfaces.SpecOverride, shmSize, ephemeralStorage string) error {
+	if override != nil {
+		if err := checkOverride("extraCpu", override.ExtraCPU, l.MaxExtraCPU, true, resource.ParseQuantity); err != nil {
+			return err
+		}
+		if err := checkOverride("extraMemory", override.ExtraMemory, l.MaxExtraMemory, true, resource.ParseQuantity); err != nil {
+			return err
+		}
+	}
+	if err := checkOverride("shmSize", shmSize, l.MaxShmSize, false, resource.ParseQuantity); err != nil {
+		return err
+	}
+	return checkOverride("ephemeralStorage", ephemeralStorage, l.MaxEphemeralStorage, false, parseEphemeralStorage)
+}
+
+// checkOverride checks that an overridden value is positive and within max. An empty max
+// refuses the override when limitRequired, and leaves it unbounded otherwise.
+func checkOverride(field, value, max string, limitRequired bool, parse func(string) (resource.Quantity, error)) error {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	quantity, err := parse(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("invalid spec override %s %q: %w", field, value, err)
+	}
+	if quantity.Sign() <= 0 {
+		return fmt.Errorf("invalid spec override %s %q: must be positive", field, value)
+	}
+	if max == "" {
+		if limitRequired {
+			return fmt.Errorf("invalid spec override %s: not allowed by k8s.spec_overrides", field)
+		}
+		return nil
+	}
+	maxQuantity, err := parse(max)
+	if err != nil {
+		return fmt.Errorf("invalid spec override limit for %s %q: %w", field, max, err)
+	}
+	if quantity.Cmp(maxQuantity) > 0 {
+		return fmt.Errorf("invalid spec override %s %s: exceeds the limit of %s", field, value, maxQuantity.String())
+	}
+	return nil
+}
+
+// applySpecOverride adds the extra CPU and memory of an override to the spec's resources
+func applySpecOverride(cpu, memory string, override *interfaces.SpecOverride) (string, string, error) {
+	if override.IsZero() {
+		return cpu, memory, nil
+	}
+	var err error
+	if override.ExtraCPU != "" {
+		if cpu == "" {
+			return "", "", fmt.Errorf("invalid spec override extraCpu: the spec sets no CPU limit")
+		}
+		if cpu, err = addQuantity(cpu, override.ExtraCPU); err != nil {
+			return "", "", fmt.Errorf("invalid spec override extraCpu: %w", err)
+		}
+	}
+	if override.ExtraMemory != "" {
+		if memory, err = addQuantity(memory, override.ExtraMemory); err != nil {
+			return "", "", fmt.Errorf("invalid spec override extraMemory: %w", err)
+		}
+	}
+	return cpu, memory, nil
+}
+
+// addQuantity adds two K8s quantities
+func addQuantity(base, extra string) (string, error) {
+	quantity, err := resource.ParseQuantity(base)
+	if err != nil {
+		return "", err
+	}
+	extraQuantity, err := resource.ParseQuantity(extra)
+	if err != nil {
+		return "", err
+	}
+	quantity.Add(extraQuantity)
+	return quantity.String(), nil
+}
+
+// specOverrideJSON returns the annotation value of an override ("" when it changes nothing)
+func specOverrideJSON(override *interfaces.SpecOverride) (string, error) {
+	if override.IsZero() {
+		return "", nil
+	}
+	data, err := json.Marshal(override)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal spec override: %w", err)
+	}
+	return string(data), nil
+}
+
+// specOverrideOf returns the spec override recorded on a deployment, nil when there is none
+func specOverrideOf(deployment *appsv1.Deployment) *interfaces.SpecOverride {
+	value := deployment.Annotations[specOverrideAnnotation]
+	if value == "" {
+		return nil
+	}
+	var override interfaces.SpecOverride
+	if err := json.Unmarshal([]byte(value), &override); err != nil || override.IsZero() {
+		return nil
+	}
+	return &override
+}
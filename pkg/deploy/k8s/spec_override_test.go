@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/yaml"
+
+	"waverless/pkg/interfaces"
+)
+
+func TestSpecOverrideLimits_Validate(t *testing.T) {
+	limits := SpecOverrideLimits{MaxExtraCPU: "8", MaxExtraMemory: "64Gi", MaxShmSize: "32Gi"}
+
+	assert.NoError(t, limits.Validate(nil, "", ""))
+	assert.NoError(t, limits.Validate(&interfaces.SpecOverride{ExtraCPU: "4", ExtraMemory: "16Gi"}, "16Gi", "1000"))
+	assert.NoError(t, limits.Validate(&interfaces.SpecOverride{ExtraMemory: "64Gi"}, "", ""))
+
+	assert.ErrorContains(t, limits.Validate(&interfaces.SpecOverride{ExtraMemory: "65Gi"}, "", ""), "exceeds the limit of 64Gi")
+	assert.ErrorContains(t, limits.Validate(&interfaces.SpecOverride{ExtraCPU: "500m0"}, "", ""), "invalid spec override extraCpu")
+	assert.ErrorContains(t, limits.Validate(&interfaces.SpecOverride{ExtraCPU: "-1"}, "", ""), "must be positive")
+	assert.ErrorContains(t, limits.Validate(nil, "64Gi", ""), "shmSize")
+
+	// Extra resources need a limit; shm and ephemeral storage don't
+	assert.ErrorContains(t, SpecOverrideLimits{}.Validate(&interfaces.SpecOverride{ExtraMemory: "1Gi"}, "", ""), "not allowed")
+	assert.NoError(t, SpecOverrideLimits{}.Validate(nil, "64Gi", "2000"))
+	assert.ErrorContains(t, SpecOverrideLimits{MaxEphemeralStorage: "500"}.Validate(nil, "", "600Gi"), "ephemeralStorage")
+}
+
+func TestApplySpecOverride(t *testing.T) {
+	cpu, memory, err := applySpecOverride("16", "96Gi", &interfaces.SpecOverride{ExtraCPU: "4", ExtraMemory: "16Gi"})
+	require.NoError(t, err)
+	assert.Equal(t, "20", cpu)
+	assert.Equal(t, "112Gi", memory)
+
+	cpu, memory, err = applySpecOverride("16", "96Gi", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "16", cpu)
+	assert.Equal(t, "96Gi", memory)
+
+	_, _, err = applySpecOverride("", "96Gi", &interfaces.SpecOverride{ExtraCPU: "4"})
+	assert.ErrorContains(t, err, "no CPU limit")
+}
+
+func TestDeploymentTemplate_RecordsSpecOverride(t *testing.T) {
+	override := &interfaces.SpecOverride{ExtraMemory: "16Gi"}
+	overrideJSON, err := specOverrideJSON(override)
+	require.NoError(t, err)
+
+	renderer := NewTemplateRenderer("../../../config/templates")
+	content, err := renderer.Render("deployment.yaml", &RenderContext{
+		Endpoint:         "flux",
+		Namespace:        "wavespeed",
+		Image:            "flux:latest",
+		Replicas:         1,
+		ContainerName:    "flux-worker",
+		ContainerPort:    8000,
+		MemoryRequest:    "112Gi",
+		SpecOverrideJSON: overrideJSON,
+	})
+	require.NoError(t, err)
+
+	var deployment appsv1.Deployment
+	require.NoError(t, yaml.Unmarshal([]byte(content), &deployment))
+	assert.Equal(t, override, specOverrideOf(&deployment))
+	assert.Equal(t, override, deploymentToAppInfo(&deployment).SpecOverride)
+
+	assert.Nil(t, specOverrideOf(&appsv1.Deployment{}))
+}
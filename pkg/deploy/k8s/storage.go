@@ -0,0 +1,370 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// Labels and annotations of the PVCs provisioned for endpoints
+const (
+	storageVolumeLabel              = "waverless.io/volume"          // Volume name from the storage request
+	storageDeletionPolicyAnnotation = "waverless.io/deletion-policy" // retain or delete
+	storageMountPathAnnotation      = "waverless.io/mount-path"
+	storageSharedLabel              = "waverless.io/shared"    // Set on shared storage
+	storageConsumerLabelPrefix      = "consumer.waverless.io/" // One label per endpoint using shared storage
+)
+
+// storagePVCName returns the name of the PVC provisioned for a storage request
+func storagePVCName(endpoint string, req interfaces.StorageRequest) string {
+	if req.Shared {
+		return "shared-" + req.Name
+	}
+	return endpoint + "-" + req.Name
+}
+
+// storageConsumerLabel returns the label marking an endpoint as a consumer of shared storage
+func storageConsumerLabel(endpoint string) string {
+	return storageConsumerLabelPrefix + endpoint
+}
+
+// normalizeStorageRequest validates a storage request and fills in its defaults
+func normalizeStorageRequest(endpoint string, req interfaces.StorageRequest) (interfaces.StorageRequest, error) {
+	req.Name = strings.ToLower(strings.TrimSpace(req.Name))
+	if err := validateK8sName(req.Name); err != nil {
+		return req, fmt.Errorf("invalid storage name: %w", err)
+	}
+	if err := validateK8sName(storagePVCName(endpoint, req)); err != nil {
+		return req, fmt.Errorf("invalid storage %s: %w", req.Name, err)
+	}
+	if !path.IsAbs(req.MountPath) {
+		return req, fmt.Errorf("invalid storage %s: mountPath must be an absolute path", req.Name)
+	}
+	size, err := resource.ParseQuantity(strings.TrimSpace(req.Size))
+	if err != nil || size.Sign() <= 0 {
+		return req, fmt.Errorf("invalid storage %s: size %q must be a positive quantity such as 100Gi", req.Name, req.Size)
+	}
+	req.Size = size.String()
+
+	switch corev1.PersistentVolumeAccessMode(req.AccessMode) {
+	case "":
+		req.AccessMode = string(corev1.ReadWriteOnce)
+		if req.Shared {
+			req.AccessMode = string(corev1.ReadOnlyMany)
+		}
+	case corev1.ReadWriteOnce, corev1.ReadWriteMany, corev1.ReadOnlyMany, corev1.ReadWriteOncePod:
+	default:
+		return req, fmt.Errorf("invalid storage %s: unsupported access mode %q", req.Name, req.AccessMode)
+	}
+
+	switch strings.ToLower(req.DeletionPolicy) {
+	case "", interfaces.StorageDeletionRetain:
+		req.DeletionPolicy = interfaces.StorageDeletionRetain
+	case interfaces.StorageDeletionDelete:
+		req.DeletionPolicy = interfaces.StorageDeletionDelete
+	default:
+		return req, fmt.Errorf("invalid storage %s: deletionPolicy must be retain or delete", req.Name)
+	}
+	return req, nil
+}
+
+// normalizeStorageRequests validates the storage requests of an endpoint
+func normalizeStorageRequests(endpoint string, reqs []interfaces.StorageRequest) ([]interfaces.StorageRequest, error) {
+	seen := make(map[string]bool, len(reqs))
+	result := make([]interfaces.StorageRequest, 0, len(reqs))
+	for _, r := range reqs {
+		normalized, err := normalizeStorageRequest(endpoint, r)
+		if err != nil {
+			return nil, err
+		}
+		if seen[normalized.Name] {
+			return nil, fmt.Errorf("duplicate storage name %s", normalized.Name)
+		}
+		seen[normalized.Name] = true
+		result = append(result, normalized)
+	}
+	return result, nil
+}
+
+// storageVolumeMounts returns the volume mounts of the PVCs provisioned for the storage requests
+func storageVolumeMounts(endpoint string, reqs []interfaces.StorageRequest) []interfaces.VolumeMount {
+	mounts := make([]interfaces.VolumeMount, 0, len(reqs))
+	for _, r := range reqs {
+		mounts = append(mounts, interfaces.VolumeMount{PVCName: storagePVCName(endpoint, r), MountPath: r.MountPath})
+	}
+	return mounts
+}
+
+// buildStoragePVC builds the PVC of a normalized storage request
+func buildStoragePVC(namespace, endpoint string, req interfaces.StorageRequest) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      storagePVCName(endpoint, req),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":              endpoint,
+				"managed-by":       "waverless",
+				storageVolumeLabel: req.Name,
+			},
+			Annotations: map[string]string{
+				storageDeletionPolicyAnnotation: req.DeletionPolicy,
+				storageMountPathAnnotation:      req.MountPath,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.PersistentVolumeAccessMode(req.AccessMode)},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(req.Size)},
+			},
+		},
+	}
+	if req.StorageClass != "" {
+		storageClass := req.StorageClass
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	// Shared storage belongs to no endpoint; consumers are tracked in labels and the mount
+	// path may differ per endpoint
+	if req.Shared {
+		delete(pvc.Labels, "app")
+		pvc.Labels[storageSharedLabel] = "true"
+		pvc.Labels[storageConsumerLabel(endpoint)] = "true"
+		delete(pvc.Annotations, storageMountPathAnnotation)
+	}
+	return pvc
+}
+
+// provisionStorage creates the PVCs of an endpoint's storage requests. Existing PVCs of the
+// endpoint are reused: their deletion policy and mount path are updated and they are grown when
+// a larger size is requested (shrinking is not supported by K8s and is ignored). Existing shared
+// storage gains the endpoint as a consumer.
+func (m *Manager) provisionStorage(ctx context.Context, endpoint string, reqs []interfaces.StorageRequest) error {
+	pvcs := m.client.CoreV1().PersistentVolumeClaims(m.namespace)
+	for _, r := range reqs {
+		desired := buildStoragePVC(m.namespace, endpoint, r)
+		// Consumers of shared storage are added concurrently by other deploys
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			existing, err := pvcs.Get(ctx, desired.Name, metav1.GetOptions{})
+			if err != nil {
+				if !errors.IsNotFound(err) {
+					return fmt.Errorf("failed to get PVC %s: %w", desired.Name, err)
+				}
+				if _, err := pvcs.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+					return err
+				}
+				logger.InfoCtx(ctx, "created PVC %s (%s) for endpoint %s", desired.Name, r.Size, endpoint)
+				return nil
+			}
+
+			if err := updateStoragePVC(ctx, existing, endpoint, r, desired); err != nil {
+				return err
+			}
+			_, err = pvcs.Update(ctx, existing, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to provision PVC %s: %w", desired.Name, err)
+		}
+	}
+	return nil
+}
+
+// updateStoragePVC applies a storage request to the existing PVC of an earlier deploy
+func updateStoragePVC(ctx context.Context, existing *corev1.PersistentVolumeClaim, endpoint string, r interfaces.StorageRequest, desired *corev1.PersistentVolumeClaim) error {
+	if existing.Labels[storageVolumeLabel] != r.Name || existing.Labels["managed-by"] != "waverless" {
+		return fmt.Errorf("PVC %s already exists and was not provisioned by waverless", existing.Name)
+	}
+	if r.Shared {
+		if existing.Labels[storageSharedLabel] != "true" {
+			return fmt.Errorf("PVC %s already exists and is not shared", existing.Name)
+		}
+		existing.Labels[storageConsumerLabel(endpoint)] = "true"
+	} else if existing.Labels["app"] != endpoint {
+		return fmt.Errorf("PVC %s already exists and was not provisioned for endpoint %s", existing.Name, endpoint)
+	}
+
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	existing.Annotations[storageDeletionPolicyAnnotation] = r.DeletionPolicy
+	if !r.Shared {
+		existing.Annotations[storageMountPathAnnotation] = r.MountPath
+	}
+	requested := desired.Spec.Resources.Requests[corev1.ResourceStorage]
+	if current := existing.Spec.Resources.Requests[corev1.ResourceStorage]; requested.Cmp(current) > 0 {
+		existing.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+		logger.InfoCtx(ctx, "growing PVC %s from %s to %s", existing.Name, current.String(), requested.String())
+	}
+	return nil
+}
+
+// listStoragePVCs lists the PVCs provisioned for an endpoint and the shared storage it uses,
+// sorted by name
+func (m *Manager) listStoragePVCs(ctx context.Context, endpoint string) ([]corev1.PersistentVolumeClaim, error) {
+	selectors := []string{
+		labels.SelectorFromSet(labels.Set{"app": endpoint, "managed-by": "waverless"}).String() + "," + storageVolumeLabel,
+		labels.SelectorFromSet(labels.Set{storageConsumerLabel(endpoint): "true", "managed-by": "waverless"}).String(),
+	}
+	var result []corev1.PersistentVolumeClaim
+	for _, selector := range selectors {
+		list, err := m.client.CoreV1().PersistentVolumeClaims(m.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list PVCs of endpoint %s: %w", endpoint, err)
+		}
+		result = append(result, list.Items...)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// ListSharedStorage returns the shared storage and the endpoints using each
+func (m *Manager) ListSharedStorage(ctx context.Context) ([]*interfaces.StorageStatus, error) {
+	selector := labels.SelectorFromSet(labels.Set{storageSharedLabel: "true", "managed-by": "waverless"}).String()
+	list, err := m.client.CoreV1().PersistentVolumeClaims(m.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared PVCs: %w", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+	result := make([]*interfaces.StorageStatus, 0, len(list.Items))
+	for i := range list.Items {
+		result = append(result, storageStatus(&list.Items[i]))
+	}
+	return result, nil
+}
+
+// GetEndpointStorage returns the PVCs provisioned for an endpoint
+func (m *Manager) GetEndpointStorage(ctx context.Context, endpoint string) ([]*interfaces.StorageStatus, error) {
+	pvcs, err := m.listStoragePVCs(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*interfaces.StorageStatus, 0, len(pvcs))
+	for i := range pvcs {
+		result = append(result, storageStatus(&pvcs[i]))
+	}
+	return result, nil
+}
+
+// releaseStorage deletes the PVCs of a deleted endpoint whose deletion policy is delete.
+// Shared storage loses the endpoint as a consumer and is only deleted with its last consumer.
+func (m *Manager) releaseStorage(ctx context.Context, endpoint string) error {
+	pvcs, err := m.listStoragePVCs(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		if pvc.Labels[storageSharedLabel] == "true" {
+			if err := m.releaseSharedStorage(ctx, pvc.Name, endpoint); err != nil {
+				return err
+			}
+			continue
+		}
+		if pvc.Annotations[storageDeletionPolicyAnnotation] != interfaces.StorageDeletionDelete {
+			logger.InfoCtx(ctx, "retaining PVC %s of deleted endpoint %s", pvc.Name, endpoint)
+			continue
+		}
+		if err := m.deleteStoragePVC(ctx, pvc.Name); err != nil {
+			return err
+		}
+		logger.InfoCtx(ctx, "deleted PVC %s of deleted endpoint %s", pvc.Name, endpoint)
+	}
+	return nil
+}
+
+// releaseSharedStorage removes an endpoint from the consumers of shared storage
+func (m *Manager) releaseSharedStorage(ctx context.Context, name, endpoint string) error {
+	pvcs := m.client.CoreV1().PersistentVolumeClaims(m.namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pvc, err := pvcs.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get PVC %s: %w", name, err)
+		}
+		delete(pvc.Labels, storageConsumerLabel(endpoint))
+
+		consumers := storageConsumers(pvc)
+		if len(consumers) == 0 && pvc.Annotations[storageDeletionPolicyAnnotation] == interfaces.StorageDeletionDelete {
+			// Preconditions make the delete fail if a consumer was added since the read
+			err := pvcs.Delete(ctx, name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: &pvc.ResourceVersion}})
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			logger.InfoCtx(ctx, "deleted shared PVC %s, endpoint %s was its last consumer", name, endpoint)
+			return nil
+		}
+
+		if _, err := pvcs.Update(ctx, pvc, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		logger.InfoCtx(ctx, "endpoint %s released shared PVC %s, %d consumer(s) left", endpoint, name, len(consumers))
+		return nil
+	})
+}
+
+// deleteStoragePVC deletes a PVC, ignoring PVCs that are already gone
+func (m *Manager) deleteStoragePVC(ctx context.Context, name string) error {
+	err := m.client.CoreV1().PersistentVolumeClaims(m.namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete PVC %s: %w", name, err)
+	}
+	return nil
+}
+
+// storageConsumers returns the endpoints using shared storage, sorted
+func storageConsumers(pvc *corev1.PersistentVolumeClaim) []string {
+	var consumers []string
+	for key := range pvc.Labels {
+		if strings.HasPrefix(key, storageConsumerLabelPrefix) {
+			consumers = append(consumers, strings.TrimPrefix(key, storageConsumerLabelPrefix))
+		}
+	}
+	sort.Strings(consumers)
+	return consumers
+}
+
+// storageStatus converts a provisioned PVC to its status
+func storageStatus(pvc *corev1.PersistentVolumeClaim) *interfaces.StorageStatus {
+	status := &interfaces.StorageStatus{
+		Name:           pvc.Labels[storageVolumeLabel],
+		PVCName:        pvc.Name,
+		MountPath:      pvc.Annotations[storageMountPathAnnotation],
+		DeletionPolicy: pvc.Annotations[storageDeletionPolicyAnnotation],
+		Phase:          string(pvc.Status.Phase),
+	}
+	if status.DeletionPolicy == "" {
+		status.DeletionPolicy = interfaces.StorageDeletionRetain
+	}
+	if status.Phase == "" {
+		status.Phase = string(corev1.ClaimPending)
+	}
+	if size, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		status.Size = size.String()
+	}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		status.Capacity = capacity.String()
+	}
+	if pvc.Spec.StorageClassName != nil {
+		status.StorageClass = *pvc.Spec.StorageClassName
+	}
+	if len(pvc.Spec.AccessModes) > 0 {
+		status.AccessMode = string(pvc.Spec.AccessModes[0])
+	}
+	if pvc.Labels[storageSharedLabel] == "true" {
+		status.Shared = true
+		status.Consumers = storageConsumers(pvc)
+	}
+	return status
+}
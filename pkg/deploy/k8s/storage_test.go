@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"waverless/pkg/interfaces"
+)
+
+func TestNormalizeStorageRequests(t *testing.T) {
+	reqs, err := normalizeStorageRequests("flux", []interfaces.StorageRequest{
+		{Name: " Models ", MountPath: "/models", Size: "100Gi"},
+	})
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "models", reqs[0].Name)
+	assert.Equal(t, string(corev1.ReadWriteOnce), reqs[0].AccessMode)
+	assert.Equal(t, interfaces.StorageDeletionRetain, reqs[0].DeletionPolicy)
+	assert.Equal(t, []interfaces.VolumeMount{{PVCName: "flux-models", MountPath: "/models"}}, storageVolumeMounts("flux", reqs))
+
+	invalid := []interfaces.StorageRequest{
+		{Name: "models", MountPath: "models", Size: "100Gi"},
+		{Name: "models", MountPath: "/models", Size: "big"},
+		{Name: "models", MountPath: "/models", Size: "100Gi", AccessMode: "ReadWriteSometimes"},
+		{Name: "models", MountPath: "/models", Size: "100Gi", DeletionPolicy: "archive"},
+		{Name: "Models_1", MountPath: "/models", Size: "100Gi"},
+	}
+	for _, r := range invalid {
+		_, err := normalizeStorageRequests("flux", []interfaces.StorageRequest{r})
+		assert.Error(t, err, "%+v", r)
+	}
+
+	_, err = normalizeStorageRequests("flux", []interfaces.StorageRequest{
+		{Name: "models", MountPath: "/a", Size: "1Gi"},
+		{Name: "models", MountPath: "/b", Size: "1Gi"},
+	})
+	assert.Error(t, err)
+}
+
+func TestProvisionStorage(t *testing.T) {
+	ctx := context.Background()
+	m := &Manager{client: fake.NewSimpleClientset(), namespace: "wavespeed"}
+
+	reqs, err := normalizeStorageRequests("flux", []interfaces.StorageRequest{
+		{Name: "models", MountPath: "/models", Size: "100Gi", StorageClass: "fast", DeletionPolicy: "delete"},
+		{Name: "cache", MountPath: "/cache", Size: "10Gi", AccessMode: "ReadWriteMany"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.provisionStorage(ctx, "flux", reqs))
+
+	storage, err := m.GetEndpointStorage(ctx, "flux")
+	require.NoError(t, err)
+	require.Len(t, storage, 2)
+	assert.Equal(t, &interfaces.StorageStatus{
+		Name: "cache", PVCName: "flux-cache", MountPath: "/cache", Size: "10Gi",
+		AccessMode: "ReadWriteMany", DeletionPolicy: "retain", Phase: "Pending",
+	}, storage[0])
+	assert.Equal(t, "fast", storage[1].StorageClass)
+	assert.Equal(t, "delete", storage[1].DeletionPolicy)
+
+	// Redeploy grows the PVC, a smaller size is ignored
+	reqs[0].Size = "200Gi"
+	reqs[1].Size = "5Gi"
+	require.NoError(t, m.provisionStorage(ctx, "flux", reqs))
+	models, err := m.client.CoreV1().PersistentVolumeClaims("wavespeed").Get(ctx, "flux-models", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, resource.MustParse("200Gi"), models.Spec.Resources.Requests[corev1.ResourceStorage])
+	cache, err := m.client.CoreV1().PersistentVolumeClaims("wavespeed").Get(ctx, "flux-cache", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, resource.MustParse("10Gi"), cache.Spec.Resources.Requests[corev1.ResourceStorage])
+
+	// Deleting the endpoint only deletes PVCs with the delete policy
+	require.NoError(t, m.releaseStorage(ctx, "flux"))
+	storage, err = m.GetEndpointStorage(ctx, "flux")
+	require.NoError(t, err)
+	require.Len(t, storage, 1)
+	assert.Equal(t, "flux-cache", storage[0].PVCName)
+}
+
+func TestProvisionStorage_ForeignPVC(t *testing.T) {
+	foreign := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "flux-models", Namespace: "wavespeed"}}
+	m := &Manager{client: fake.NewSimpleClientset(foreign), namespace: "wavespeed"}
+
+	reqs, err := normalizeStorageRequests("flux", []interfaces.StorageRequest{{Name: "models", MountPath: "/models", Size: "100Gi"}})
+	require.NoError(t, err)
+	assert.Error(t, m.provisionStorage(context.Background(), "flux", reqs))
+}
+
+func TestProvisionStorage_Shared(t *testing.T) {
+	ctx := context.Background()
+	m := &Manager{client: fake.NewSimpleClientset(), namespace: "wavespeed"}
+
+	for _, endpoint := range []string{"flux-dev", "flux-schnell"} {
+		reqs, err := normalizeStorageRequests(endpoint, []interfaces.StorageRequest{
+			{Name: "weights", MountPath: "/models/" + endpoint, Size: "500Gi", Shared: true, DeletionPolicy: "delete"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, string(corev1.ReadOnlyMany), reqs[0].AccessMode)
+		assert.Equal(t, []interfaces.VolumeMount{{PVCName: "shared-weights", MountPath: "/models/" + endpoint}}, storageVolumeMounts(endpoint, reqs))
+		require.NoError(t, m.provisionStorage(ctx, endpoint, reqs))
+	}
+
+	shared, err := m.ListSharedStorage(ctx)
+	require.NoError(t, err)
+	require.Len(t, shared, 1)
+	assert.Equal(t, "shared-weights", shared[0].PVCName)
+	assert.True(t, shared[0].Shared)
+	assert.Equal(t, []string{"flux-dev", "flux-schnell"}, shared[0].Consumers)
+
+	storage, err := m.GetEndpointStorage(ctx, "flux-schnell")
+	require.NoError(t, err)
+	require.Len(t, storage, 1)
+	assert.Equal(t, "shared-weights", storage[0].PVCName)
+
+	// The volume survives until its last consumer is deleted
+	require.NoError(t, m.releaseStorage(ctx, "flux-dev"))
+	shared, err = m.ListSharedStorage(ctx)
+	require.NoError(t, err)
+	require.Len(t, shared, 1)
+	assert.Equal(t, []string{"flux-schnell"}, shared[0].Consumers)
+
+	require.NoError(t, m.releaseStorage(ctx, "flux-schnell"))
+	shared, err = m.ListSharedStorage(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, shared)
+}
+
+func TestProvisionStorage_SharedNameTakenByUnsharedPVC(t *testing.T) {
+	ctx := context.Background()
+	m := &Manager{client: fake.NewSimpleClientset(), namespace: "wavespeed"}
+
+	reqs, err := normalizeStorageRequests("shared", []interfaces.StorageRequest{{Name: "weights", MountPath: "/models", Size: "1Gi"}})
+	require.NoError(t, err)
+	require.NoError(t, m.provisionStorage(ctx, "shared", reqs))
+
+	reqs, err = normalizeStorageRequests("flux", []interfaces.StorageRequest{{Name: "weights", MountPath: "/models", Size: "1Gi", Shared: true}})
+	require.NoError(t, err)
+	assert.Error(t, m.provisionStorage(ctx, "flux", reqs))
+}
@@ -41,21 +41,54 @@ type RenderContext struct {
 	Tolerations  []Toleration      `json:"tolerations"`
 	Labels       map[string]string `json:"labels"`
 	Annotations  map[string]string `json:"annotations"`
+	AffinityJSON string            `json:"affinityJSON,omitempty"` // Pod affinity (replica anti-affinity) as inline JSON
+
+	// Topology hints (from Spec)
+	HostNetwork       bool              `json:"hostNetwork,omitempty"`       // Node network namespace (dnsPolicy ClusterFirstWithHostNet)
+	TopologyResources map[string]string `json:"topologyResources,omitempty"` // IB devices and hugepages, requested and limited
+	HugepagesMedium   string            `json:"hugepagesMedium,omitempty"`   // emptyDir medium of /dev/hugepages (e.g., "HugePages-2Mi")
 
 	// 存储配置
-	Volumes      []VolumeInfo      `json:"volumes,omitempty"`
-	VolumeMounts []VolumeMountInfo `json:"volumeMounts,omitempty"`
-	ShmSize      string            `json:"shmSize,omitempty"` // Shared memory size (e.g., "1Gi", "512Mi")
+	Volumes          []VolumeInfo      `json:"volumes,omitempty"`
+	VolumeMounts     []VolumeMountInfo `json:"volumeMounts,omitempty"`
+	ShmSize          string            `json:"shmSize,omitempty"`          // Shared memory size (e.g., "1Gi", "512Mi")
+	EphemeralStorage string            `json:"ephemeralStorage,omitempty"` // Ephemeral storage request and limit (e.g., "30Gi")
+	ScratchVolumes   []string          `json:"scratchVolumes,omitempty"`   // emptyDir volumes shared by sidecars and the worker
+
+	// Containers run next to the worker
+	Sidecars []SidecarInfo `json:"sidecars,omitempty"`
+
+	// Containers run to completion before the worker starts
+	InitContainers       []SidecarInfo `json:"initContainers,omitempty"`
+	InitDescriptionsJSON string        `json:"initDescriptionsJSON,omitempty"` // Container name -> description, for the endpoint status
+
+	// Spec override (extra CPU/memory) as inline JSON, recorded on the deployment
+	SpecOverrideJSON string `json:"specOverrideJSON,omitempty"`
+
+	// GPU type chain (gpuType and fallbacks with their node selectors) as inline JSON, recorded on the deployment
+	GPUTypesJSON string `json:"gpuTypesJSON,omitempty"`
 
 	// 安全配置
-	EnablePtrace bool `json:"enablePtrace,omitempty"` // Enable SYS_PTRACE capability for debugging
+	EnablePtrace        bool   `json:"enablePtrace,omitempty"`        // Enable SYS_PTRACE capability for debugging
+	SecurityContextJSON string `json:"securityContextJSON,omitempty"` // Container securityContext (spec profile + ptrace) as inline JSON
 
 	// 环境变量配置
-	Env map[string]string `json:"env,omitempty"` // Custom environment variables
+	Env          map[string]string `json:"env,omitempty"`          // Custom environment variables
+	RunPodCompat bool              `json:"runpodCompat,omitempty"` // Inject the RunPod pod env vars (RUNPOD_POD_ID, RUNPOD_POD_HOSTNAME)
 
 	// Image pull secret for private registries
 	ImagePullSecret string `json:"imagePullSecret,omitempty"` // Additional image pull secret name
 
+	// Dedicated service account without API token (restricted workers)
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Secret holding the endpoint invoke key (WAVERLESS_INVOKE_KEY), set when data plane tokens are enabled
+	InvokeKeySecret string `json:"invokeKeySecret,omitempty"`
+
+	// Secret holding secret-typed endpoint env vars, referenced once per key
+	EnvSecret     string   `json:"envSecret,omitempty"`
+	EnvSecretKeys []string `json:"envSecretKeys,omitempty"`
+
 	// 平台配置追踪（用于记录到 Deployment annotations）
 	PlatformLabelsJSON      string `json:"platformLabelsJSON,omitempty"`      // 平台labels的JSON记录
 	PlatformAnnotationsJSON string `json:"platformAnnotationsJSON,omitempty"` // 平台annotations的JSON记录
@@ -75,6 +108,7 @@ type VolumeInfo struct {
 type VolumeMountInfo struct {
 	Name      string `json:"name"`
 	MountPath string `json:"mountPath"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
 }
 
 // Render 渲染模板
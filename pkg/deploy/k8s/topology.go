@@ -0,0 +1,231 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	hugepagesVolumeName = "hugepages"
+	hugepagesMountPath  = "/dev/hugepages"
+	defaultHugepageSize = "2Mi"
+
+	// RDMA needs to pin (lock) memory registered with the IB devices
+	capabilityIPCLock corev1.Capability = "IPC_LOCK"
+)
+
+// TopologyHints are topology-aware pod settings for multi-GPU specs (e.g. 8-GPU nodes with
+// InfiniBand). All fields are optional; a spec without hints renders as before.
+type TopologyHints struct {
+	HostNetwork  bool              `yaml:"hostNetwork,omitempty" json:"hostNetwork,omitempty"`   // Use the node network namespace (IB/RoCE NICs not exposed through the CNI)
+	RDMAResource string            `yaml:"rdmaResource,omitempty" json:"rdmaResource,omitempty"` // Device plugin resource of the IB devices, e.g. rdma/ib
+	RDMADevices  int               `yaml:"rdmaDevices,omitempty" json:"rdmaDevices,omitempty"`   // IB devices per pod (default 1 when rdmaResource is set)
+	NCCLEnv      map[string]string `yaml:"ncclEnv,omitempty" json:"ncclEnv,omitempty"`           // NCCL settings, e.g. NCCL_IB_HCA; endpoint env takes precedence
+	Hugepages    string            `yaml:"hugepages,omitempty" json:"hugepages,omitempty"`       // Hugepages per pod, e.g. 16Gi
+	HugepageSize string            `yaml:"hugepageSize,omitempty" json:"hugepageSize,omitempty"` // 2Mi (default) or 1Gi
+	NUMAAligned  bool              `yaml:"numaAligned,omitempty" json:"numaAligned,omitempty"`   // Require whole CPUs so the static CPU manager can pin them to the GPUs' NUMA node
+}
+
+// topologySettings are the pod settings resolved from topology hints
+type topologySettings struct {
+	hostNetwork     bool
+	resources       corev1.ResourceList // IB devices and hugepages, used as both request and limit
+	hugepagesMedium corev1.StorageMedium
+	ipcLock         bool
+	env             map[string]string
+}
+
+// resolveTopologyHints validates topology hints and resolves the pod settings; cpu is the CPU
+// of the worker container, which must be whole cores when the hints ask for NUMA alignment
+func resolveTopologyHints(hints *TopologyHints, cpu string) (*topologySettings, error) {
+	settings := &topologySettings{resources: corev1.ResourceList{}}
+	if hints == nil {
+		return settings, nil
+	}
+	settings.hostNetwork = hints.HostNetwork
+
+	if name := strings.TrimSpace(hints.RDMAResource); name != "" {
+		if !strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid rdmaResource %q: expected a device plugin resource such as rdma/ib", name)
+		}
+		devices := hints.RDMADevices
+		if devices < 0 {
+			return nil, fmt.Errorf("invalid rdmaDevices %d", devices)
+		}
+		if devices == 0 {
+			devices = 1
+		}
+		settings.resources[corev1.ResourceName(name)] = resource.MustParse(strconv.Itoa(devices))
+		settings.ipcLock = true
+	} else if hints.RDMADevices != 0 {
+		return nil, fmt.Errorf("rdmaDevices requires rdmaResource")
+	}
+
+	if strings.TrimSpace(hints.Hugepages) != "" {
+		amount, err := resource.ParseQuantity(strings.TrimSpace(hints.Hugepages))
+		if err != nil || amount.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid hugepages %q", hints.Hugepages)
+		}
+		size := strings.TrimSpace(hints.HugepageSize)
+		if size == "" {
+			size = defaultHugepageSize
+		}
+		if size != "2Mi" && size != "1Gi" {
+			return nil, fmt.Errorf("invalid hugepageSize %q (expected 2Mi or 1Gi)", hints.HugepageSize)
+		}
+		settings.resources[corev1.ResourceName(corev1.ResourceHugePagesPrefix+size)] = amount
+		settings.hugepagesMedium = corev1.StorageMedium(string(corev1.StorageMediumHugePagesPrefix) + size)
+	}
+
+	if hints.NUMAAligned {
+		quantity, err := resource.ParseQuantity(cpu)
+		if err != nil || quantity.Sign() <= 0 || quantity.MilliValue()%1000 != 0 {
+			return nil, fmt.Errorf("numaAligned requires a whole number of CPUs, got %q", cpu)
+		}
+	}
+
+	if len(hints.NCCLEnv) > 0 {
+		settings.env = make(map[string]string, len(hints.NCCLEnv))
+		for k, v := range hints.NCCLEnv {
+			settings.env[k] = v
+		}
+	}
+	return settings, nil
+}
+
+// renderResources returns the resources as strings for template rendering
+func (s *topologySettings) renderResources() map[string]string {
+	if len(s.resources) == 0 {
+		return nil
+	}
+	rendered := make(map[string]string, len(s.resources))
+	for name, quantity := range s.resources {
+		rendered[string(name)] = quantity.String()
+	}
+	return rendered
+}
+
+// applyTopologySettings replaces the topology settings of a pod spec: host network, IB and
+// hugepages resources of the worker container, the hugepages volume and IPC_LOCK. Environment
+// variables are handled by the caller.
+func applyTopologySettings(podSpec *corev1.PodSpec, settings *topologySettings) {
+	podSpec.HostNetwork = settings.hostNetwork
+	if settings.hostNetwork {
+		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	} else if podSpec.DNSPolicy == corev1.DNSClusterFirstWithHostNet {
+		podSpec.DNSPolicy = corev1.DNSClusterFirst
+	}
+	if len(podSpec.Containers) == 0 {
+		return
+	}
+	container := &podSpec.Containers[0]
+
+	for name, quantity := range settings.resources {
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{}
+		}
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{}
+		}
+		container.Resources.Requests[name] = quantity
+		container.Resources.Limits[name] = quantity
+	}
+
+	// Hugepages volume
+	var volumes []corev1.Volume
+	for _, vol := range podSpec.Volumes {
+		if vol.Name != hugepagesVolumeName {
+			volumes = append(volumes, vol)
+		}
+	}
+	var mounts []corev1.VolumeMount
+	for _, mount := range container.VolumeMounts {
+		if mount.Name != hugepagesVolumeName {
+			mounts = append(mounts, mount)
+		}
+	}
+	if settings.hugepagesMedium != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name:         hugepagesVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: settings.hugepagesMedium}},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: hugepagesVolumeName, MountPath: hugepagesMountPath})
+	}
+	podSpec.Volumes = volumes
+	container.VolumeMounts = mounts
+
+	// IPC_LOCK for RDMA memory registration
+	if settings.ipcLock {
+		if container.SecurityContext == nil {
+			container.SecurityContext = &corev1.SecurityContext{}
+		}
+		addCapability(container.SecurityContext, capabilityIPCLock)
+	} else if container.SecurityContext != nil {
+		removeCapability(container.SecurityContext, capabilityIPCLock)
+		if isEmptySecurityContext(container.SecurityContext) {
+			container.SecurityContext = nil
+		}
+	}
+}
+
+// addCapability adds a capability to a securityContext unless already present
+func addCapability(sc *corev1.SecurityContext, capability corev1.Capability) {
+	if sc.Capabilities == nil {
+		sc.Capabilities = &corev1.Capabilities{}
+	}
+	for _, c := range sc.Capabilities.Add {
+		if c == capability {
+			return
+		}
+	}
+	sc.Capabilities.Add = append(sc.Capabilities.Add, capability)
+}
+
+// removeCapability removes an added capability, dropping empty capabilities
+func removeCapability(sc *corev1.SecurityContext, capability corev1.Capability) {
+	if sc.Capabilities == nil {
+		return
+	}
+	var kept []corev1.Capability
+	for _, c := range sc.Capabilities.Add {
+		if c != capability {
+			kept = append(kept, c)
+		}
+	}
+	sc.Capabilities.Add = kept
+	if len(sc.Capabilities.Add) == 0 && len(sc.Capabilities.Drop) == 0 {
+		sc.Capabilities = nil
+	}
+}
+
+// applyTopologyEnv sets the topology env of a container. Variables set by the hints of the
+// previous spec are removed first; variables the container already has otherwise (the
+// endpoint's own env) take precedence.
+func applyTopologyEnv(container *corev1.Container, env, previous map[string]string) {
+	var kept []corev1.EnvVar
+	existing := make(map[string]bool)
+	for _, e := range container.Env {
+		if _, ok := previous[e.Name]; ok && e.ValueFrom == nil {
+			continue
+		}
+		kept = append(kept, e)
+		existing[e.Name] = true
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		if !existing[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		kept = append(kept, corev1.EnvVar{Name: name, Value: env[name]})
+	}
+	container.Env = kept
+}
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+func TestResolveTopologyHints(t *testing.T) {
+	settings, err := resolveTopologyHints(nil, "")
+	require.NoError(t, err)
+	assert.False(t, settings.hostNetwork)
+	assert.Empty(t, settings.resources)
+
+	settings, err = resolveTopologyHints(&TopologyHints{
+		HostNetwork:  true,
+		RDMAResource: "rdma/ib",
+		RDMADevices:  8,
+		Hugepages:    "16Gi",
+		NCCLEnv:      map[string]string{"NCCL_IB_HCA": "mlx5"},
+		NUMAAligned:  true,
+	}, "96")
+	require.NoError(t, err)
+	assert.True(t, settings.hostNetwork)
+	assert.True(t, settings.ipcLock)
+	assert.Equal(t, resource.MustParse("8"), settings.resources["rdma/ib"])
+	assert.Equal(t, resource.MustParse("16Gi"), settings.resources["hugepages-2Mi"])
+	assert.Equal(t, corev1.StorageMedium("HugePages-2Mi"), settings.hugepagesMedium)
+	assert.Equal(t, map[string]string{"NCCL_IB_HCA": "mlx5"}, settings.env)
+
+	invalid := []struct {
+		hints TopologyHints
+		cpu   string
+	}{
+		{TopologyHints{RDMAResource: "ib"}, ""},
+		{TopologyHints{RDMADevices: 2}, ""},
+		{TopologyHints{Hugepages: "lots"}, ""},
+		{TopologyHints{Hugepages: "1Gi", HugepageSize: "4Mi"}, ""},
+		{TopologyHints{NUMAAligned: true}, "1500m"},
+		{TopologyHints{NUMAAligned: true}, ""},
+	}
+	for _, c := range invalid {
+		_, err := resolveTopologyHints(&c.hints, c.cpu)
+		assert.Error(t, err, "%+v", c.hints)
+	}
+}
+
+func TestApplyTopologySettings(t *testing.T) {
+	podSpec := &corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name: "flux-worker",
+			SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_PTRACE"}},
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: "dshm", MountPath: "/dev/shm"}},
+		}},
+		Volumes: []corev1.Volume{{Name: "dshm"}},
+	}
+
+	settings, err := resolveTopologyHints(&TopologyHints{HostNetwork: true, RDMAResource: "rdma/ib", Hugepages: "2Gi", HugepageSize: "1Gi"}, "")
+	require.NoError(t, err)
+	applyTopologySettings(podSpec, settings)
+	container := podSpec.Containers[0]
+	assert.True(t, podSpec.HostNetwork)
+	assert.Equal(t, corev1.DNSClusterFirstWithHostNet, podSpec.DNSPolicy)
+	assert.Equal(t, resource.MustParse("1"), container.Resources.Limits["rdma/ib"])
+	assert.Equal(t, resource.MustParse("2Gi"), container.Resources.Requests["hugepages-1Gi"])
+	assert.Equal(t, []corev1.Capability{"SYS_PTRACE", "IPC_LOCK"}, container.SecurityContext.Capabilities.Add)
+	require.Len(t, podSpec.Volumes, 2)
+	assert.Equal(t, corev1.StorageMedium("HugePages-1Gi"), podSpec.Volumes[1].EmptyDir.Medium)
+	assert.Equal(t, corev1.VolumeMount{Name: "hugepages", MountPath: "/dev/hugepages"}, container.VolumeMounts[1])
+
+	// A spec without hints removes them again, other settings are kept
+	settings, err = resolveTopologyHints(nil, "")
+	require.NoError(t, err)
+	applyTopologySettings(podSpec, settings)
+	container = podSpec.Containers[0]
+	assert.False(t, podSpec.HostNetwork)
+	assert.Equal(t, corev1.DNSClusterFirst, podSpec.DNSPolicy)
+	assert.Equal(t, []corev1.Capability{"SYS_PTRACE"}, container.SecurityContext.Capabilities.Add)
+	assert.Equal(t, []corev1.Volume{{Name: "dshm"}}, podSpec.Volumes)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "dshm", MountPath: "/dev/shm"}}, container.VolumeMounts)
+}
+
+func TestApplyTopologyEnv(t *testing.T) {
+	container := &corev1.Container{Env: []corev1.EnvVar{
+		{Name: "NCCL_IB_HCA", Value: "mlx5_0"},
+		{Name: "NCCL_DEBUG", Value: "INFO"},
+		{Name: "MODEL", Value: "flux"},
+	}}
+
+	// NCCL_IB_HCA came from the previous spec; NCCL_DEBUG is the endpoint's own and is kept
+	applyTopologyEnv(container,
+		map[string]string{"NCCL_DEBUG": "WARN", "NCCL_SOCKET_IFNAME": "ib0"},
+		map[string]string{"NCCL_IB_HCA": "mlx5_0"})
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "NCCL_DEBUG", Value: "INFO"},
+		{Name: "MODEL", Value: "flux"},
+		{Name: "NCCL_SOCKET_IFNAME", Value: "ib0"},
+	}, container.Env)
+}
+
+func TestDeploymentTemplate_RendersTopologyHints(t *testing.T) {
+	settings, err := resolveTopologyHints(&TopologyHints{HostNetwork: true, RDMAResource: "rdma/ib", RDMADevices: 8, Hugepages: "16Gi"}, "")
+	require.NoError(t, err)
+
+	renderer := NewTemplateRenderer("../../../config/templates")
+	content, err := renderer.Render("deployment.yaml", &RenderContext{
+		Endpoint:          "llama-405b",
+		Namespace:         "wavespeed",
+		Image:             "vllm:latest",
+		Replicas:          1,
+		ContainerName:     "llama-405b-worker",
+		ContainerPort:     8000,
+		CpuLimit:          "96",
+		MemoryRequest:     "1600Gi",
+		IsGpu:             true,
+		GpuCount:          8,
+		HostNetwork:       settings.hostNetwork,
+		TopologyResources: settings.renderResources(),
+		HugepagesMedium:   string(settings.hugepagesMedium),
+	})
+	require.NoError(t, err)
+
+	var deployment appsv1.Deployment
+	require.NoError(t, yaml.Unmarshal([]byte(content), &deployment))
+	podSpec := deployment.Spec.Template.Spec
+	assert.True(t, podSpec.HostNetwork)
+	assert.Equal(t, corev1.DNSClusterFirstWithHostNet, podSpec.DNSPolicy)
+	resources := podSpec.Containers[0].Resources
+	assert.Equal(t, resource.MustParse("8"), resources.Requests["rdma/ib"])
+	assert.Equal(t, resource.MustParse("16Gi"), resources.Limits["hugepages-2Mi"])
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "hugepages", MountPath: "/dev/hugepages"})
+	require.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, corev1.StorageMedium("HugePages-2Mi"), podSpec.Volumes[0].EmptyDir.Medium)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"waverless/pkg/failure"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
 	"waverless/pkg/status"
@@ -25,6 +26,7 @@ type K8sWorkerStatusMonitor struct {
 	manager    *Manager
 	workerRepo *mysql.WorkerRepository
 	sanitizer  *status.StatusSanitizer
+	classifier *failure.Classifier
 }
 
 // NewK8sWorkerStatusMonitor creates a new K8s worker status monitor.
@@ -40,6 +42,7 @@ func NewK8sWorkerStatusMonitor(manager *Manager, workerRepo *mysql.WorkerReposit
 		manager:    manager,
 		workerRepo: workerRepo,
 		sanitizer:  status.NewStatusSanitizer(),
+		classifier: failure.Default(),
 	}
 }
 
@@ -72,22 +75,25 @@ func (m *K8sWorkerStatusMonitor) DetectFailure(info *interfaces.PodInfo) *interf
 		return nil
 	}
 
-	// Check if the reason indicates a failure
-	failureType := m.ClassifyK8sFailure(info.Reason, info.Message)
-	if failureType == interfaces.FailureTypeUnknown {
-		// Also check the status field for failure indicators
-		failureType = m.ClassifyK8sFailure(info.Status, info.Message)
-	}
+	// Classify across container state, previous termination and pod-level signals
+	classification := m.getClassifier().Classify(podFailureSignals(info)...)
+	failureType := classification.Type
 
 	// Only return failure info for actual failures
-	if !isFailureState(info.Reason, info.Status) {
+	if !isFailureState(info.Reason, info.Status) && !isFailureState(info.PodReason, "") {
 		return nil
 	}
 
+	// Specific causes have their own user messages, otherwise use the provider reason
+	sanitizeReason := info.Reason
+	if classification.Matcher != "" && classification.Reason != info.Reason && classification.Reason != info.Status {
+		sanitizeReason = classification.Reason
+	}
+
 	// Sanitize the message to remove sensitive information
 	sanitizedMsg := ""
 	if m.sanitizer != nil {
-		sanitized := m.sanitizer.Sanitize(failureType, info.Reason, info.Message)
+		sanitized := m.sanitizer.Sanitize(failureType, sanitizeReason, info.Message)
 		if sanitized != nil {
 			sanitizedMsg = sanitized.UserMessage
 			if sanitized.Suggestion != "" {
@@ -101,10 +107,40 @@ func (m *K8sWorkerStatusMonitor) DetectFailure(info *interfaces.PodInfo) *interf
 		Reason:       info.Reason,
 		Message:      info.Message,
 		SanitizedMsg: sanitizedMsg,
+		Cause:        classification.Reason,
+		Details:      classification.Details,
 		OccurredAt:   time.Now().UTC(), // Use local time - GORM will convert to UTC for storage
 	}
 }
 
+// podFailureSignals converts pod info into classification signals, most specific first
+func podFailureSignals(info *interfaces.PodInfo) []failure.Signal {
+	signals := []failure.Signal{
+		{Source: failure.SourceContainer, Reason: info.Reason, Message: info.Message, ExitCode: info.ExitCode},
+		{Source: failure.SourceContainer, Reason: info.Status, Message: info.Message},
+	}
+	if info.LastTerminationReason != "" || info.LastExitCode != 0 {
+		signals = append(signals, failure.Signal{
+			Source:   failure.SourceContainer,
+			Reason:   info.LastTerminationReason,
+			Message:  info.LastTerminationMessage,
+			ExitCode: info.LastExitCode,
+		})
+	}
+	if info.PodReason != "" {
+		signals = append(signals, failure.Signal{Source: failure.SourcePod, Reason: info.PodReason, Message: info.PodMessage})
+	}
+	return signals
+}
+
+// getClassifier returns the failure classifier, falling back to the shared default
+func (m *K8sWorkerStatusMonitor) getClassifier() *failure.Classifier {
+	if m.classifier == nil {
+		return failure.Default()
+	}
+	return m.classifier
+}
+
 // isFailureState checks if the given reason or status indicates a failure state.
 // This is used to filter out normal states like "Running", "Ready", "ContainerCreating".
 func isFailureState(reason, status string) bool {
@@ -125,6 +161,7 @@ func isFailureState(reason, status string) bool {
 		"FailedMount":          true,
 		"FailedAttachVolume":   true,
 		"CreateContainerError": true,
+		"Evicted":              true,
 	}
 
 	if failureReasons[reason] {
@@ -146,7 +183,7 @@ func isFailureState(reason, status string) bool {
 
 // ClassifyK8sFailure converts K8s reason to generic FailureType.
 // This method maps Kubernetes-specific error reasons to the generic failure types
-// defined in pkg/interfaces/image_validation.go.
+// defined in pkg/interfaces/image_validation.go using the failure classifier.
 //
 // Parameters:
 //   - reason: The K8s reason string (e.g., "ImagePullBackOff", "CrashLoopBackOff")
@@ -157,73 +194,7 @@ func isFailureState(reason, status string) bool {
 //
 // Validates: Requirements 3.2, 6.2
 func (m *K8sWorkerStatusMonitor) ClassifyK8sFailure(reason, message string) interfaces.FailureType {
-	// Normalize reason for comparison
-	reasonLower := strings.ToLower(reason)
-
-	// Image pull failures
-	switch reason {
-	case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ImageInspectError":
-		return interfaces.FailureTypeImagePull
-	}
-
-	// Check for image-related keywords in reason
-	if strings.Contains(reasonLower, "image") &&
-		(strings.Contains(reasonLower, "pull") ||
-			strings.Contains(reasonLower, "error") ||
-			strings.Contains(reasonLower, "invalid")) {
-		return interfaces.FailureTypeImagePull
-	}
-
-	// Container crash failures
-	switch reason {
-	case "CrashLoopBackOff", "Error", "OOMKilled", "ContainerCannotRun", "CreateContainerError":
-		return interfaces.FailureTypeContainerCrash
-	}
-
-	// Check for crash-related keywords
-	if strings.Contains(reasonLower, "crash") ||
-		strings.Contains(reasonLower, "oom") ||
-		strings.Contains(reasonLower, "killed") {
-		return interfaces.FailureTypeContainerCrash
-	}
-
-	// Resource limit failures
-	switch reason {
-	case "OutOfMemory", "OutOfCpu", "Unschedulable", "FailedScheduling":
-		return interfaces.FailureTypeResourceLimit
-	}
-
-	// Check for resource-related keywords
-	if strings.Contains(reasonLower, "memory") ||
-		strings.Contains(reasonLower, "cpu") ||
-		strings.Contains(reasonLower, "resource") ||
-		strings.Contains(reasonLower, "unschedulable") {
-		return interfaces.FailureTypeResourceLimit
-	}
-
-	// Check message for additional context
-	messageLower := strings.ToLower(message)
-
-	// Image-related messages
-	if strings.Contains(messageLower, "image") &&
-		(strings.Contains(messageLower, "pull") ||
-			strings.Contains(messageLower, "not found") ||
-			strings.Contains(messageLower, "unauthorized")) {
-		return interfaces.FailureTypeImagePull
-	}
-
-	// Resource-related messages
-	if strings.Contains(messageLower, "insufficient") ||
-		strings.Contains(messageLower, "no nodes available") {
-		return interfaces.FailureTypeResourceLimit
-	}
-
-	// Timeout-related
-	if strings.Contains(reasonLower, "timeout") || strings.Contains(messageLower, "timeout") {
-		return interfaces.FailureTypeTimeout
-	}
-
-	return interfaces.FailureTypeUnknown
+	return m.getClassifier().Classify(failure.Signal{Source: failure.SourceContainer, Reason: reason, Message: message}).Type
 }
 
 // UpdateWorkerFailure updates the worker record with failure information.
@@ -255,6 +226,12 @@ func (m *K8sWorkerStatusMonitor) UpdateWorkerFailure(ctx context.Context, podNam
 		"sanitizedMsg": info.SanitizedMsg,
 		"occurredAt":   info.OccurredAt.Format(time.RFC3339),
 	}
+	if info.Cause != "" {
+		details["cause"] = info.Cause
+	}
+	if len(info.Details) > 0 {
+		details["details"] = info.Details
+	}
 	detailsJSON, err := json.Marshal(details)
 	if err != nil {
 		logger.WarnCtx(ctx, "Failed to marshal failure details: %v", err)
@@ -271,6 +248,12 @@ func (m *K8sWorkerStatusMonitor) GetManager() *Manager {
 	return m.manager
 }
 
+// GetClassifier returns the failure classifier.
+// Register additional matchers on it to recognize custom failure causes.
+func (m *K8sWorkerStatusMonitor) GetClassifier() *failure.Classifier {
+	return m.getClassifier()
+}
+
 // GetSanitizer returns the status sanitizer.
 // This is useful for sanitizing error messages externally.
 func (m *K8sWorkerStatusMonitor) GetSanitizer() *status.StatusSanitizer {
@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/ratelimit"
 )
 
 // Client is the Novita API client
@@ -18,6 +20,7 @@ type Client struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+	limiter    *ratelimit.Limiter // nil = unthrottled
 }
 
 // NewClient creates a new Novita API client
@@ -36,6 +39,11 @@ func NewClient(cfg *config.NovitaConfig) *Client {
 	}
 }
 
+// SetRateLimiter throttles the client's API calls (for dependency injection)
+func (c *Client) SetRateLimiter(limiter *ratelimit.Limiter) {
+	c.limiter = limiter
+}
+
 // CreateEndpoint creates a new endpoint
 func (c *Client) CreateEndpoint(ctx context.Context, req *CreateEndpointRequest) (*CreateEndpointResponse, error) {
 	url := c.baseURL + "/gpu-instance/openapi/v1/endpoint/create"
@@ -183,6 +191,11 @@ func (c *Client) doRequest(ctx context.Context, method, url string, body interfa
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
+	// Wait for a call slot of the API key (WatchReplicas polling alone can exceed the limit)
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
@@ -206,11 +219,31 @@ func (c *Client) doRequest(ctx context.Context, method, url string, body interfa
 	// Check for HTTP errors
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		var errResp ErrorResponse
+		message := string(respData)
 		if err := json.Unmarshal(respData, &errResp); err == nil && errResp.Message != "" {
-			return nil, fmt.Errorf("novita API error (status %d): %s", resp.StatusCode, errResp.Message)
+			message = errResp.Message
 		}
-		return nil, fmt.Errorf("novita API error (status %d): %s", resp.StatusCode, string(respData))
+		return nil, interfaces.NewProviderError(errorKindForStatus(resp.StatusCode),
+			fmt.Errorf("novita API error (status %d): %s", resp.StatusCode, message))
 	}
 
 	return respData, nil
 }
+
+// errorKindForStatus classifies Novita API status codes (nil = unclassified)
+func errorKindForStatus(status int) error {
+	switch status {
+	case http.StatusNotFound:
+		return interfaces.ErrNotFound
+	case http.StatusConflict:
+		return interfaces.ErrConflict
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return interfaces.ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return interfaces.ErrThrottled
+	case http.StatusPaymentRequired:
+		// Insufficient balance / quota on the Novita account
+		return interfaces.ErrQuotaExceeded
+	}
+	return nil
+}
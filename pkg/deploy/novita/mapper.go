@@ -367,14 +367,17 @@ func mapNovitaStatusToAppStatus(endpointName string, data *EndpointConfig) *inte
 	}
 
 	totalReplicas := runningWorkers + pendingWorkers
+	status := mapNovitaStatusToWaverless(data.State.State)
 
 	return &interfaces.AppStatus{
 		Endpoint:          endpointName,
-		Status:            mapNovitaStatusToWaverless(data.State.State),
+		Status:            status,
 		ReadyReplicas:     int32(healthyWorkers),
 		AvailableReplicas: int32(runningWorkers),
 		TotalReplicas:     int32(totalReplicas),
-		Message:           data.State.Message,
+		// Novita replaces workers itself and reports Updating meanwhile
+		RolloutComplete: status == StatusRunning || status == StatusStopped,
+		Message:         data.State.Message,
 	}
 }
 
@@ -465,8 +468,11 @@ func mapUpdateRequestToNovita(endpointID string, req *interfaces.UpdateDeploymen
 			})
 		}
 	}
-	healthCheck := &HealthCheck{
-		Path: data.Healthy.Path,
+	var healthCheck *HealthCheck
+	if data.Healthy != nil {
+		healthCheck = &HealthCheck{
+			Path: data.Healthy.Path,
+		}
 	}
 	// Build flattened update request
 	return &UpdateEndpointRequest{
@@ -12,6 +12,7 @@ import (
 	"waverless/pkg/config"
 	"waverless/pkg/interfaces"
 	"waverless/pkg/logger"
+	"waverless/pkg/ratelimit"
 )
 
 // clientInterface defines the interface for Novita API client (for testing)
@@ -55,10 +56,10 @@ type workerState struct {
 }
 
 // WorkerStatusChangeCallback is called when a worker's status changes
-type WorkerStatusChangeCallback func(workerID, endpoint string, info *interfaces.PodInfo)
+type WorkerStatusChangeCallback = func(workerID, endpoint string, info *interfaces.PodInfo)
 
 // WorkerDeleteCallback is called when a worker is deleted
-type WorkerDeleteCallback func(workerID, endpoint string)
+type WorkerDeleteCallback = func(workerID, endpoint string)
 
 // NovitaDeploymentProvider implements interfaces.DeploymentProvider for Novita Serverless
 type NovitaDeploymentProvider struct {
@@ -83,6 +84,12 @@ type NovitaDeploymentProvider struct {
 	workerDeleteCallbacks     map[uint64]WorkerDeleteCallback
 	workerDeleteCallbacksLock sync.RWMutex
 	workerStates              sync.Map // workerID -> *workerState
+
+	// Registry auth lifecycle (see registry_auth.go)
+	registryAuthMu sync.Mutex // Serializes create/rotate/delete of registry auths
+	recentAuths    sync.Map   // authID -> time.Time created, protected from garbage collection
+
+	rateLimiter *ratelimit.Limiter // Throttles API calls of the API key (nil = unthrottled)
 }
 
 // NewNovitaDeploymentProvider creates a new Novita deployment provider
@@ -140,6 +147,11 @@ func NewNovitaDeploymentProvider(cfg *config.Config) (interfaces.DeploymentProvi
 func (p *NovitaDeploymentProvider) Deploy(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
 	logger.Infof("Deploying endpoint %s to Novita", req.Endpoint)
 
+	// Isolation relies on K8s NetworkPolicy/ServiceAccount; refuse rather than deploy unrestricted
+	if req.EgressPolicy != nil || req.RestrictedServiceAccount {
+		return nil, fmt.Errorf("egress policy and restricted service account are not supported by the Novita provider")
+	}
+
 	// Merge globalEnv with request env (request takes precedence)
 	mergedEnv := make(map[string]string)
 	for k, v := range p.globalEnv {
@@ -242,6 +254,12 @@ func (p *NovitaDeploymentProvider) DeleteApp(ctx context.Context, endpoint strin
 		return err
 	}
 
+	// Remember the registry auth of the endpoint so it can be released afterwards
+	var authID string
+	if current, err := p.client.GetEndpoint(ctx, endpointID); err == nil {
+		authID = current.Endpoint.Image.AuthID
+	}
+
 	// Delete endpoint
 	if err := p.client.DeleteEndpoint(ctx, endpointID); err != nil {
 		return fmt.Errorf("failed to delete endpoint from Novita: %w", err)
@@ -250,6 +268,10 @@ func (p *NovitaDeploymentProvider) DeleteApp(ctx context.Context, endpoint strin
 	// Remove from cache
 	p.endpointCache.Delete(endpoint)
 
+	if authID != "" {
+		p.releaseRegistryAuth(ctx, authID)
+	}
+
 	logger.Infof("%s %s (ID: %s)", MessageDeleteSuccess, endpoint, endpointID)
 	return nil
 }
@@ -996,43 +1018,6 @@ func (p *NovitaDeploymentProvider) GetDefaultEnv(ctx context.Context) (map[strin
 	return defaultEnv, nil
 }
 
-// ensureRegistryAuth ensures a registry auth exists in Novita
-// Returns the auth ID (existing or newly created)
-func (p *NovitaDeploymentProvider) ensureRegistryAuth(ctx context.Context, cred *interfaces.RegistryCredential) (string, error) {
-	if cred == nil {
-		return "", fmt.Errorf("registry credential is nil")
-	}
-
-	// List existing registry auths
-	listResp, err := p.client.ListRegistryAuths(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to list registry auths: %w", err)
-	}
-	// Check if auth already exists by matching registry name
-	for _, auth := range listResp.Data {
-		if auth.Name == cred.Registry {
-			logger.Infof("Found existing registry auth for %s (ID: %s)", cred.Registry, auth.ID)
-			return auth.ID, nil
-		}
-	}
-
-	// Auth doesn't exist, create new one
-	logger.Infof("Creating new registry auth for %s", cred.Registry)
-	createReq := &CreateRegistryAuthRequest{
-		Name:     cred.Registry,
-		Username: cred.Username,
-		Password: cred.Password,
-	}
-
-	createResp, err := p.client.CreateRegistryAuth(ctx, createReq)
-	if err != nil {
-		return "", fmt.Errorf("failed to create registry auth: %w", err)
-	}
-
-	logger.Infof("Created registry auth for %s (ID: %s)", cred.Registry, createResp.ID)
-	return createResp.ID, nil
-}
-
 // getEndpointID retrieves the Novita endpoint ID for a given endpoint name
 // It first checks the cache, then queries the API if not found
 func (p *NovitaDeploymentProvider) getEndpointID(ctx context.Context, endpoint string) (string, error) {
@@ -1057,7 +1042,7 @@ func (p *NovitaDeploymentProvider) getEndpointID(ctx context.Context, endpoint s
 		}
 	}
 
-	return "", fmt.Errorf("endpoint %s not found in Novita", endpoint)
+	return "", interfaces.ProviderErrorf(interfaces.ErrNotFound, "endpoint %s not found in Novita", endpoint)
 }
 
 // SetSpecRepository sets the spec repository for database access
@@ -1065,6 +1050,23 @@ func (p *NovitaDeploymentProvider) SetSpecRepository(repo SpecRepositoryInterfac
 	p.specsConfig.SetSpecRepository(repo)
 }
 
+// SetRateLimiter throttles the provider's API calls (for dependency injection)
+func (p *NovitaDeploymentProvider) SetRateLimiter(limiter *ratelimit.Limiter) {
+	p.rateLimiter = limiter
+	if c, ok := p.client.(*Client); ok {
+		c.SetRateLimiter(limiter)
+	}
+}
+
+// RateLimitStats returns the queue and wait metrics of the API rate limiter (nil = unthrottled)
+func (p *NovitaDeploymentProvider) RateLimitStats() *ratelimit.Stats {
+	if p.rateLimiter == nil {
+		return nil
+	}
+	stats := p.rateLimiter.Stats()
+	return &stats
+}
+
 // IsPodTerminating checks if a worker is terminating (Novita doesn't have this concept)
 func (p *NovitaDeploymentProvider) IsPodTerminating(ctx context.Context, podName string) (bool, error) {
 	return false, nil
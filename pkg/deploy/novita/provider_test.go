@@ -21,6 +21,9 @@ type mockClient struct {
 	deleteError    error
 	updateError    error
 	listError      error
+
+	auths      []RegistryAuthItem
+	nextAuthID int
 }
 
 func newMockClient() *mockClient {
@@ -128,6 +131,7 @@ func (m *mockClient) ListEndpoints(ctx context.Context) (*ListEndpointsResponse,
 			Name:    ep.Endpoint.Name,
 			AppName: ep.Endpoint.AppName,
 			State:   ep.Endpoint.State,
+			Image:   ep.Endpoint.Image,
 		})
 	}
 
@@ -226,15 +230,33 @@ func (m *mockClient) DeleteEndpoint(ctx context.Context, endpointID string) erro
 }
 
 func (m *mockClient) CreateRegistryAuth(ctx context.Context, req *CreateRegistryAuthRequest) (*CreateRegistryAuthResponse, error) {
-	return &CreateRegistryAuthResponse{ID: "auth-123"}, nil
+	m.endpointsMutex.Lock()
+	defer m.endpointsMutex.Unlock()
+
+	m.nextAuthID++
+	id := fmt.Sprintf("auth-%d", m.nextAuthID)
+	m.auths = append(m.auths, RegistryAuthItem{ID: id, Name: req.Name, Username: req.Username, Password: req.Password})
+	return &CreateRegistryAuthResponse{ID: id}, nil
 }
 
 func (m *mockClient) ListRegistryAuths(ctx context.Context) (*ListRegistryAuthsResponse, error) {
-	return &ListRegistryAuthsResponse{Data: []RegistryAuthItem{}}, nil
+	m.endpointsMutex.RLock()
+	defer m.endpointsMutex.RUnlock()
+
+	return &ListRegistryAuthsResponse{Data: append([]RegistryAuthItem{}, m.auths...)}, nil
 }
 
 func (m *mockClient) DeleteRegistryAuth(ctx context.Context, authID string) error {
-	return nil
+	m.endpointsMutex.Lock()
+	defer m.endpointsMutex.Unlock()
+
+	for i, auth := range m.auths {
+		if auth.ID == authID {
+			m.auths = append(m.auths[:i], m.auths[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("registry auth %s not found", authID)
 }
 
 func (m *mockClient) DrainWorker(ctx context.Context, req *DrainWorkerRequest) error {
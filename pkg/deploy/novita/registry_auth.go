@@ -0,0 +1,313 @@
+// Package novita provides Novita deployment provider implementation.
+// This file implements the lifecycle of Novita container registry auths: deduplication by
+// registry and username, password rotation and deletion of auths no endpoint references.
+package novita
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// registryAuthGracePeriod protects newly created auths from garbage collection while the
+// endpoint that will use them is still being created
+const registryAuthGracePeriod = 10 * time.Minute
+
+// RegistryAuth is a Novita registry auth and the endpoints whose image uses it
+type RegistryAuth struct {
+	ID        string   `json:"id"`
+	Registry  string   `json:"registry"`
+	Username  string   `json:"username"`
+	Endpoints []string `json:"endpoints"`           // Endpoint names using this auth
+	Duplicate bool     `json:"duplicate,omitempty"` // Another auth exists for the same registry and username
+}
+
+// RegistryAuthRotation is the result of rotating a registry credential
+type RegistryAuthRotation struct {
+	AuthID    string   `json:"authId"`              // New auth ID
+	Replaced  []string `json:"replaced,omitempty"`  // Old auth IDs deleted
+	Endpoints []string `json:"endpoints,omitempty"` // Endpoints moved to the new auth
+}
+
+// ListRegistryAuths lists the registry auths of the Novita account with the endpoints using each
+func (p *NovitaDeploymentProvider) ListRegistryAuths(ctx context.Context) ([]*RegistryAuth, error) {
+	listResp, err := p.client.ListRegistryAuths(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registry auths: %w", err)
+	}
+	refs, err := p.registryAuthReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(listResp.Data))
+	auths := make([]*RegistryAuth, 0, len(listResp.Data))
+	for _, item := range listResp.Data {
+		key := registryAuthKey(item.Name, item.Username)
+		endpoints := refs[item.ID]
+		if endpoints == nil {
+			endpoints = []string{}
+		}
+		auths = append(auths, &RegistryAuth{
+			ID:        item.ID,
+			Registry:  item.Name,
+			Username:  item.Username,
+			Endpoints: endpoints,
+			Duplicate: seen[key],
+		})
+		seen[key] = true
+	}
+	return auths, nil
+}
+
+// RotateRegistryAuth replaces the auths of a registry and username with a new one holding the
+// given password, moves every endpoint using them to it and deletes the old auths
+func (p *NovitaDeploymentProvider) RotateRegistryAuth(ctx context.Context, cred *interfaces.RegistryCredential) (*RegistryAuthRotation, error) {
+	if cred == nil || cred.Registry == "" || cred.Username == "" || cred.Password == "" {
+		return nil, fmt.Errorf("registry, username and password are required")
+	}
+
+	p.registryAuthMu.Lock()
+	defer p.registryAuthMu.Unlock()
+
+	listResp, err := p.client.ListRegistryAuths(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registry auths: %w", err)
+	}
+	return p.rotateRegistryAuth(ctx, cred, listResp.Data)
+}
+
+// GarbageCollectRegistryAuths deletes the registry auths no endpoint references and returns their IDs.
+// Auths created within the last 10 minutes are kept.
+func (p *NovitaDeploymentProvider) GarbageCollectRegistryAuths(ctx context.Context) ([]string, error) {
+	p.registryAuthMu.Lock()
+	defer p.registryAuthMu.Unlock()
+
+	listResp, err := p.client.ListRegistryAuths(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registry auths: %w", err)
+	}
+	refs, err := p.registryAuthReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []string{}
+	for _, item := range listResp.Data {
+		if len(refs[item.ID]) > 0 || p.isRecentAuth(item.ID) {
+			continue
+		}
+		if err := p.client.DeleteRegistryAuth(ctx, item.ID); err != nil {
+			return deleted, fmt.Errorf("failed to delete registry auth %s: %w", item.ID, err)
+		}
+		logger.Infof("Deleted unused registry auth for %s/%s (ID: %s)", item.Name, item.Username, item.ID)
+		deleted = append(deleted, item.ID)
+	}
+	return deleted, nil
+}
+
+// ensureRegistryAuth ensures a registry auth exists in Novita
+// Returns the auth ID (existing, rotated or newly created)
+func (p *NovitaDeploymentProvider) ensureRegistryAuth(ctx context.Context, cred *interfaces.RegistryCredential) (string, error) {
+	if cred == nil {
+		return "", fmt.Errorf("registry credential is nil")
+	}
+
+	p.registryAuthMu.Lock()
+	defer p.registryAuthMu.Unlock()
+
+	// List existing registry auths
+	listResp, err := p.client.ListRegistryAuths(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list registry auths: %w", err)
+	}
+
+	// Reuse the auth of the same registry and username unless its password changed
+	if matches := matchRegistryAuths(listResp.Data, cred.Registry, cred.Username); len(matches) > 0 {
+		auth := matches[0]
+		if auth.Password == "" || isMaskedPassword(auth.Password) || auth.Password == cred.Password {
+			logger.Infof("Found existing registry auth for %s/%s (ID: %s)", cred.Registry, cred.Username, auth.ID)
+			return auth.ID, nil
+		}
+
+		logger.Infof("Password of registry auth %s/%s changed, rotating", cred.Registry, cred.Username)
+		rotation, err := p.rotateRegistryAuth(ctx, cred, listResp.Data)
+		if rotation == nil {
+			return "", err
+		}
+		if err != nil {
+			logger.Warnf("Registry auth rotation for %s/%s incomplete: %v", cred.Registry, cred.Username, err)
+		}
+		return rotation.AuthID, nil
+	}
+
+	// Auth doesn't exist, create new one
+	return p.createRegistryAuth(ctx, cred)
+}
+
+// releaseRegistryAuth deletes a registry auth once no endpoint references it (best effort)
+func (p *NovitaDeploymentProvider) releaseRegistryAuth(ctx context.Context, authID string) {
+	p.registryAuthMu.Lock()
+	defer p.registryAuthMu.Unlock()
+
+	refs, err := p.registryAuthReferences(ctx)
+	if err != nil {
+		logger.Warnf("Failed to check references of registry auth %s: %v", authID, err)
+		return
+	}
+	if len(refs[authID]) > 0 {
+		return
+	}
+	if err := p.client.DeleteRegistryAuth(ctx, authID); err != nil {
+		logger.Warnf("Failed to delete unused registry auth %s: %v", authID, err)
+		return
+	}
+	p.recentAuths.Delete(authID)
+	logger.Infof("Deleted registry auth %s, no endpoint references it", authID)
+}
+
+// rotateRegistryAuth creates the new auth, moves the endpoints of the old ones and deletes them.
+// Old auths still referenced by an endpoint that could not be moved are kept.
+// Must be called with registryAuthMu held.
+func (p *NovitaDeploymentProvider) rotateRegistryAuth(ctx context.Context, cred *interfaces.RegistryCredential, auths []RegistryAuthItem) (*RegistryAuthRotation, error) {
+	old := make(map[string]bool)
+	for _, auth := range matchRegistryAuths(auths, cred.Registry, cred.Username) {
+		old[auth.ID] = true
+	}
+
+	newID, err := p.createRegistryAuth(ctx, cred)
+	if err != nil {
+		return nil, err
+	}
+	rotation := &RegistryAuthRotation{AuthID: newID}
+
+	endpoints, err := p.client.ListEndpoints(ctx)
+	if err != nil {
+		return rotation, fmt.Errorf("failed to list endpoints: %w", err)
+	}
+
+	var failed []string
+	keep := make(map[string]bool)
+	for _, item := range endpoints.Endpoints {
+		if !old[item.Image.AuthID] {
+			continue
+		}
+		if err := p.setEndpointRegistryAuth(ctx, item.ID, newID); err != nil {
+			logger.Warnf("Failed to move endpoint %s to registry auth %s: %v", item.Name, newID, err)
+			failed = append(failed, item.Name)
+			keep[item.Image.AuthID] = true
+			continue
+		}
+		rotation.Endpoints = append(rotation.Endpoints, item.Name)
+	}
+
+	for id := range old {
+		if keep[id] {
+			continue
+		}
+		if err := p.client.DeleteRegistryAuth(ctx, id); err != nil {
+			logger.Warnf("Failed to delete rotated registry auth %s: %v", id, err)
+			continue
+		}
+		rotation.Replaced = append(rotation.Replaced, id)
+	}
+	sort.Strings(rotation.Replaced)
+	sort.Strings(rotation.Endpoints)
+
+	logger.Infof("Rotated registry auth for %s/%s (ID: %s), moved %d endpoint(s), deleted %d auth(s)",
+		cred.Registry, cred.Username, newID, len(rotation.Endpoints), len(rotation.Replaced))
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return rotation, fmt.Errorf("failed to move %d endpoint(s) to the new registry auth: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return rotation, nil
+}
+
+// createRegistryAuth creates a registry auth and protects it from garbage collection for a while
+func (p *NovitaDeploymentProvider) createRegistryAuth(ctx context.Context, cred *interfaces.RegistryCredential) (string, error) {
+	logger.Infof("Creating new registry auth for %s/%s", cred.Registry, cred.Username)
+	createResp, err := p.client.CreateRegistryAuth(ctx, &CreateRegistryAuthRequest{
+		Name:     cred.Registry,
+		Username: cred.Username,
+		Password: cred.Password,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create registry auth: %w", err)
+	}
+
+	p.recentAuths.Store(createResp.ID, time.Now())
+	logger.Infof("Created registry auth for %s/%s (ID: %s)", cred.Registry, cred.Username, createResp.ID)
+	return createResp.ID, nil
+}
+
+// setEndpointRegistryAuth points the image of an endpoint at another registry auth
+func (p *NovitaDeploymentProvider) setEndpointRegistryAuth(ctx context.Context, endpointID, authID string) error {
+	current, err := p.client.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to get endpoint: %w", err)
+	}
+	updateReq := mapUpdateRequestToNovita(endpointID, &interfaces.UpdateDeploymentRequest{Endpoint: current.Endpoint.Name}, current)
+	if updateReq == nil {
+		return fmt.Errorf("failed to create update request")
+	}
+	updateReq.Image.AuthID = authID
+	return p.client.UpdateEndpoint(ctx, updateReq)
+}
+
+// registryAuthReferences maps registry auth IDs to the names of the endpoints using them
+func (p *NovitaDeploymentProvider) registryAuthReferences(ctx context.Context) (map[string][]string, error) {
+	resp, err := p.client.ListEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints: %w", err)
+	}
+	refs := make(map[string][]string)
+	for _, item := range resp.Endpoints {
+		if item.Image.AuthID != "" {
+			refs[item.Image.AuthID] = append(refs[item.Image.AuthID], item.Name)
+		}
+	}
+	for _, names := range refs {
+		sort.Strings(names)
+	}
+	return refs, nil
+}
+
+// isRecentAuth reports whether an auth was created by this provider within the grace period
+func (p *NovitaDeploymentProvider) isRecentAuth(authID string) bool {
+	v, ok := p.recentAuths.Load(authID)
+	if !ok {
+		return false
+	}
+	if time.Since(v.(time.Time)) > registryAuthGracePeriod {
+		p.recentAuths.Delete(authID)
+		return false
+	}
+	return true
+}
+
+// matchRegistryAuths returns the auths of a registry and username
+func matchRegistryAuths(auths []RegistryAuthItem, registry, username string) []RegistryAuthItem {
+	key := registryAuthKey(registry, username)
+	var matches []RegistryAuthItem
+	for _, auth := range auths {
+		if registryAuthKey(auth.Name, auth.Username) == key {
+			matches = append(matches, auth)
+		}
+	}
+	return matches
+}
+
+// registryAuthKey is the deduplication key of a registry auth
+func registryAuthKey(registry, username string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(registry), "/")) + "|" + strings.TrimSpace(username)
+}
+
+// isMaskedPassword reports whether the API returned a masked password that cannot be compared
+func isMaskedPassword(password string) bool {
+	return strings.Contains(password, "*")
+}
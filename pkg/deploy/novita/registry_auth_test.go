@@ -0,0 +1,139 @@
+package novita
+
+import (
+	"context"
+	"testing"
+
+	"waverless/pkg/interfaces"
+)
+
+func deployWithCredential(t *testing.T, provider *NovitaDeploymentProvider, endpoint string, cred *interfaces.RegistryCredential) {
+	t.Helper()
+	_, err := provider.Deploy(context.Background(), &interfaces.DeployRequest{
+		Endpoint:           endpoint,
+		SpecName:           SpecNameNovitaH100Single,
+		Image:              "registry.example.com/team/" + endpoint + ":latest",
+		Replicas:           1,
+		RegistryCredential: cred,
+	})
+	if err != nil {
+		t.Fatalf("Failed to deploy %s: %v", endpoint, err)
+	}
+}
+
+func endpointAuthID(t *testing.T, mockCli *mockClient, endpoint string) string {
+	t.Helper()
+	ep, err := mockCli.GetEndpoint(context.Background(), "ep-"+endpoint)
+	if err != nil {
+		t.Fatalf("Failed to get endpoint %s: %v", endpoint, err)
+	}
+	return ep.Endpoint.Image.AuthID
+}
+
+func TestEnsureRegistryAuthDedupe(t *testing.T) {
+	mockCli := newMockClient()
+	provider := createTestProvider(mockCli)
+
+	alice := &interfaces.RegistryCredential{Registry: "registry.example.com", Username: "alice", Password: "pw"}
+	bob := &interfaces.RegistryCredential{Registry: "registry.example.com", Username: "bob", Password: "pw"}
+
+	deployWithCredential(t, provider, "a", alice)
+	deployWithCredential(t, provider, "b", alice)
+	deployWithCredential(t, provider, "c", bob)
+
+	if len(mockCli.auths) != 2 {
+		t.Fatalf("Expected one auth per registry and username, got %d", len(mockCli.auths))
+	}
+	if endpointAuthID(t, mockCli, "a") != endpointAuthID(t, mockCli, "b") {
+		t.Error("Expected endpoints with the same credential to share an auth")
+	}
+	if endpointAuthID(t, mockCli, "a") == endpointAuthID(t, mockCli, "c") {
+		t.Error("Expected a different auth for a different username")
+	}
+
+	auths, err := provider.ListRegistryAuths(context.Background())
+	if err != nil {
+		t.Fatalf("ListRegistryAuths failed: %v", err)
+	}
+	for _, auth := range auths {
+		if auth.Username == "alice" && len(auth.Endpoints) != 2 {
+			t.Errorf("Expected alice's auth to be used by 2 endpoints, got %v", auth.Endpoints)
+		}
+	}
+}
+
+func TestEnsureRegistryAuthRotatesChangedPassword(t *testing.T) {
+	mockCli := newMockClient()
+	provider := createTestProvider(mockCli)
+
+	deployWithCredential(t, provider, "a", &interfaces.RegistryCredential{Registry: "registry.example.com", Username: "alice", Password: "old"})
+	oldID := endpointAuthID(t, mockCli, "a")
+
+	deployWithCredential(t, provider, "b", &interfaces.RegistryCredential{Registry: "registry.example.com", Username: "alice", Password: "new"})
+	newID := endpointAuthID(t, mockCli, "b")
+
+	if newID == oldID {
+		t.Fatal("Expected a new auth after the password changed")
+	}
+	if got := endpointAuthID(t, mockCli, "a"); got != newID {
+		t.Errorf("Expected existing endpoint to move to the rotated auth %s, got %s", newID, got)
+	}
+	if len(mockCli.auths) != 1 || mockCli.auths[0].ID != newID {
+		t.Errorf("Expected only the rotated auth to remain, got %+v", mockCli.auths)
+	}
+}
+
+func TestRotateRegistryAuthValidation(t *testing.T) {
+	provider := createTestProvider(newMockClient())
+	if _, err := provider.RotateRegistryAuth(context.Background(), &interfaces.RegistryCredential{Registry: "registry.example.com"}); err == nil {
+		t.Error("Expected error for credential without username and password")
+	}
+}
+
+func TestRegistryAuthReleasedOnDelete(t *testing.T) {
+	mockCli := newMockClient()
+	provider := createTestProvider(mockCli)
+	ctx := context.Background()
+
+	cred := &interfaces.RegistryCredential{Registry: "registry.example.com", Username: "alice", Password: "pw"}
+	deployWithCredential(t, provider, "a", cred)
+	deployWithCredential(t, provider, "b", cred)
+
+	if err := provider.DeleteApp(ctx, "a"); err != nil {
+		t.Fatalf("DeleteApp failed: %v", err)
+	}
+	if len(mockCli.auths) != 1 {
+		t.Fatalf("Expected auth to be kept while endpoint b uses it, got %d auths", len(mockCli.auths))
+	}
+
+	if err := provider.DeleteApp(ctx, "b"); err != nil {
+		t.Fatalf("DeleteApp failed: %v", err)
+	}
+	if len(mockCli.auths) != 0 {
+		t.Errorf("Expected auth to be deleted with its last endpoint, got %+v", mockCli.auths)
+	}
+}
+
+func TestGarbageCollectRegistryAuths(t *testing.T) {
+	mockCli := newMockClient()
+	provider := createTestProvider(mockCli)
+	ctx := context.Background()
+
+	deployWithCredential(t, provider, "a", &interfaces.RegistryCredential{Registry: "registry.example.com", Username: "alice", Password: "pw"})
+	mockCli.auths = append(mockCli.auths, RegistryAuthItem{ID: "orphan", Name: "docker.io", Username: "old"})
+	if _, err := provider.createRegistryAuth(ctx, &interfaces.RegistryCredential{Registry: "ghcr.io", Username: "new", Password: "pw"}); err != nil {
+		t.Fatalf("createRegistryAuth failed: %v", err)
+	}
+
+	deleted, err := provider.GarbageCollectRegistryAuths(ctx)
+	if err != nil {
+		t.Fatalf("GarbageCollectRegistryAuths failed: %v", err)
+	}
+	// Referenced and recently created auths are kept
+	if len(deleted) != 1 || deleted[0] != "orphan" {
+		t.Errorf("Expected only the orphaned auth to be deleted, got %v", deleted)
+	}
+	if len(mockCli.auths) != 2 {
+		t.Errorf("Expected 2 auths to remain, got %d", len(mockCli.auths))
+	}
+}
@@ -0,0 +1,202 @@
+package runpod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/ratelimit"
+)
+
+// DefaultBaseURL is the RunPod REST API
+const DefaultBaseURL = "https://rest.runpod.io/v1"
+
+// Client is the RunPod REST API client
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	limiter    *ratelimit.Limiter // nil = unthrottled
+}
+
+// NewClient creates a new RunPod API client
+func NewClient(cfg *config.RunPodConfig) *Client {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		apiKey:  cfg.APIKey,
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// SetRateLimiter throttles the client's API calls (for dependency injection)
+func (c *Client) SetRateLimiter(limiter *ratelimit.Limiter) {
+	c.limiter = limiter
+}
+
+// CreateTemplate creates a serverless template
+func (c *Client) CreateTemplate(ctx context.Context, req *TemplateRequest) (*Template, error) {
+	var tmpl Template
+	if err := c.doRequest(ctx, http.MethodPost, "/templates", req, &tmpl); err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// UpdateTemplate updates a template; running workers pick the change up when they are replaced
+func (c *Client) UpdateTemplate(ctx context.Context, templateID string, req *TemplateRequest) error {
+	return c.doRequest(ctx, http.MethodPatch, "/templates/"+url.PathEscape(templateID), req, nil)
+}
+
+// DeleteTemplate deletes a template
+func (c *Client) DeleteTemplate(ctx context.Context, templateID string) error {
+	return c.doRequest(ctx, http.MethodDelete, "/templates/"+url.PathEscape(templateID), nil, nil)
+}
+
+// CreateEndpoint creates a serverless endpoint
+func (c *Client) CreateEndpoint(ctx context.Context, req *EndpointRequest) (*Endpoint, error) {
+	var ep Endpoint
+	if err := c.doRequest(ctx, http.MethodPost, "/endpoints", req, &ep); err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+// GetEndpoint gets an endpoint with its template and workers
+func (c *Client) GetEndpoint(ctx context.Context, endpointID string) (*Endpoint, error) {
+	var ep Endpoint
+	path := "/endpoints/" + url.PathEscape(endpointID) + "?includeTemplate=true&includeWorkers=true"
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &ep); err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+// ListEndpoints lists all endpoints with their templates and workers
+func (c *Client) ListEndpoints(ctx context.Context) ([]Endpoint, error) {
+	var eps []Endpoint
+	if err := c.doRequest(ctx, http.MethodGet, "/endpoints?includeTemplate=true&includeWorkers=true", nil, &eps); err != nil {
+		return nil, err
+	}
+	return eps, nil
+}
+
+// UpdateEndpoint updates the set fields of an endpoint
+func (c *Client) UpdateEndpoint(ctx context.Context, endpointID string, req *EndpointRequest) error {
+	return c.doRequest(ctx, http.MethodPatch, "/endpoints/"+url.PathEscape(endpointID), req, nil)
+}
+
+// DeleteEndpoint deletes an endpoint and its workers
+func (c *Client) DeleteEndpoint(ctx context.Context, endpointID string) error {
+	return c.doRequest(ctx, http.MethodDelete, "/endpoints/"+url.PathEscape(endpointID), nil, nil)
+}
+
+// CreateRegistryAuth creates a container registry auth
+func (c *Client) CreateRegistryAuth(ctx context.Context, req *RegistryAuthRequest) (*RegistryAuth, error) {
+	var auth RegistryAuth
+	if err := c.doRequest(ctx, http.MethodPost, "/containerregistryauth", req, &auth); err != nil {
+		return nil, err
+	}
+	return &auth, nil
+}
+
+// ListRegistryAuths lists the container registry auths of the account
+func (c *Client) ListRegistryAuths(ctx context.Context) ([]RegistryAuth, error) {
+	var auths []RegistryAuth
+	if err := c.doRequest(ctx, http.MethodGet, "/containerregistryauth", nil, &auths); err != nil {
+		return nil, err
+	}
+	return auths, nil
+}
+
+// DeleteRegistryAuth deletes a container registry auth
+func (c *Client) DeleteRegistryAuth(ctx context.Context, authID string) error {
+	return c.doRequest(ctx, http.MethodDelete, "/containerregistryauth/"+url.PathEscape(authID), nil, nil)
+}
+
+// doRequest performs an authenticated API call and decodes the response into out (if not nil)
+func (c *Client) doRequest(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonData)
+	}
+	logger.Debugf("RunPod API Request: %s %s", method, path)
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Wait for a call slot of the API key (WatchReplicas polling shares it with deploys and scaling)
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp ErrorResponse
+		message := string(respData)
+		if err := json.Unmarshal(respData, &errResp); err == nil && errResp.Error != "" {
+			message = errResp.Error
+		}
+		return interfaces.NewProviderError(errorKindForStatus(resp.StatusCode),
+			fmt.Errorf("runpod API error (status %d): %s", resp.StatusCode, message))
+	}
+
+	if out != nil && len(respData) > 0 {
+		if err := json.Unmarshal(respData, out); err != nil {
+			return fmt.Errorf("failed to parse RunPod response of %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// errorKindForStatus classifies RunPod API status codes (nil = unclassified)
+func errorKindForStatus(status int) error {
+	switch status {
+	case http.StatusNotFound:
+		return interfaces.ErrNotFound
+	case http.StatusConflict:
+		return interfaces.ErrConflict
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return interfaces.ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return interfaces.ErrThrottled
+	case http.StatusPaymentRequired:
+		// Insufficient balance on the RunPod account
+		return interfaces.ErrQuotaExceeded
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package runpod
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"waverless/pkg/interfaces"
+)
+
+const (
+	// Default values
+	DefaultContainerDiskGB = 50
+	DefaultIdleTimeout     = 5    // Seconds; workersMin == workersMax, so only surplus workers idle out
+	DefaultRequestTimeout  = 3600 // Seconds, used when the endpoint has no task timeout
+	DefaultScalerType      = "QUEUE_DELAY"
+	DefaultScalerValue     = 4
+	ComputeTypeGPU         = "GPU"
+
+	// templatePrefix names the template (and registry auth) of an endpoint: waverless-<endpoint>
+	templatePrefix = "waverless-"
+
+	// Label keys
+	LabelKeyProvider   = "provider"
+	LabelKeyEndpointID = "endpoint-id"
+	LabelKeyTemplateID = "template-id"
+
+	// Label values
+	LabelValueRunPod = "runpod"
+
+	// App/Endpoint types
+	TypeServerlessEndpoint = "ServerlessEndpoint"
+
+	// Status strings
+	StatusRunning     = "Running"
+	StatusStopped     = "Stopped"
+	StatusPending     = "Pending"
+	StatusTerminating = "Terminating"
+	StatusUnknown     = "Unknown"
+
+	// RunPod worker desired statuses
+	RunPodStatusRunning    = "RUNNING"
+	RunPodStatusExited     = "EXITED"
+	RunPodStatusTerminated = "TERMINATED"
+
+	// Environment variable keys and values
+	EnvKeyProviderType = "PROVIDER_TYPE"
+	EnvValueRunPod     = "runpod"
+
+	// Messages
+	MessageDeploySuccess    = "Endpoint deployed successfully"
+	MessageUpdateSuccess    = "Endpoint updated successfully"
+	MessageDeleteSuccess    = "Successfully deleted endpoint"
+	MessageNoStatusInfo     = "No status information available"
+	MessageNotSupported     = "not supported by RunPod provider"
+	MessageLogsNotSupported = "GetAppLogs is not supported by RunPod provider - please use the RunPod console for logs"
+)
+
+// templateName returns the name of the template created for an endpoint
+func templateName(endpoint string) string {
+	return templatePrefix + endpoint
+}
+
+// extractRunPodConfig extracts PlatformConfig from spec.Platforms
+func extractRunPodConfig(spec *interfaces.SpecInfo) (PlatformConfig, error) {
+	platformData, ok := spec.Platforms[PlatformRunPod]
+	if !ok {
+		return PlatformConfig{}, fmt.Errorf("runpod config not found for spec %s", spec.Name)
+	}
+
+	var cfg PlatformConfig
+	switch data := platformData.(type) {
+	case PlatformConfig:
+		cfg = data
+	case map[string]interface{}:
+		// Specs read from the database
+		cfg.GpuTypeIDs = stringList(data["gpuTypeIds"])
+		cfg.DataCenterIDs = stringList(data["dataCenterIds"])
+	default:
+		return PlatformConfig{}, fmt.Errorf("invalid runpod config type %T for spec %s", platformData, spec.Name)
+	}
+
+	if len(cfg.GpuTypeIDs) == 0 {
+		return PlatformConfig{}, fmt.Errorf("runpod config of spec %s has no gpuTypeIds", spec.Name)
+	}
+	return cfg, nil
+}
+
+// stringList converts a decoded JSON list to strings
+func stringList(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// gpuCountOf returns the GPUs per worker: the request's count, else the spec's
+func gpuCountOf(req *interfaces.DeployRequest, spec *interfaces.SpecInfo) (int, error) {
+	if req.GpuCount > 0 {
+		return req.GpuCount, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(spec.Resources.GPU))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("spec %s has no GPU count; the RunPod provider supports GPU specs only", spec.Name)
+	}
+	return n, nil
+}
+
+// diskGB converts a storage size ("100Gi", "100G", plain numbers are GB) to whole GB
+func diskGB(size string) (int, error) {
+	size = strings.TrimSpace(size)
+	if size == "" {
+		return DefaultContainerDiskGB, nil
+	}
+	if n, err := strconv.Atoi(size); err == nil {
+		return n, nil
+	}
+	q, err := resource.ParseQuantity(size)
+	if err != nil {
+		return 0, fmt.Errorf("invalid storage size %q: %w", size, err)
+	}
+	gb := (q.Value() + 1e9 - 1) / 1e9
+	return int(gb), nil
+}
+
+// buildTemplateRequest maps a deploy request to the template of its workers
+func buildTemplateRequest(req *interfaces.DeployRequest, spec *interfaces.SpecInfo, env map[string]string) (*TemplateRequest, error) {
+	storage := spec.Resources.EphemeralStorage
+	if req.EphemeralStorage != "" {
+		storage = req.EphemeralStorage
+	}
+	disk, err := diskGB(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateRequest{
+		Name:              templateName(req.Endpoint),
+		ImageName:         req.Image,
+		Env:               env,
+		ContainerDiskInGb: disk,
+		IsServerless:      true,
+	}, nil
+}
+
+// buildEndpointRequest maps a deploy request to a RunPod endpoint running the template
+func buildEndpointRequest(req *interfaces.DeployRequest, spec *interfaces.SpecInfo, templateID string) (*EndpointRequest, error) {
+	cfg, err := extractRunPodConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+	gpuCount, err := gpuCountOf(req, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := req.TaskTimeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	replicas := req.Replicas
+
+	// Waverless owns scaling: RunPod keeps exactly the desired number of workers
+	return &EndpointRequest{
+		Name:               req.Endpoint,
+		TemplateID:         templateID,
+		ComputeType:        ComputeTypeGPU,
+		GpuTypeIDs:         cfg.GpuTypeIDs,
+		GpuCount:           gpuCount,
+		DataCenterIDs:      cfg.DataCenterIDs,
+		WorkersMin:         &replicas,
+		WorkersMax:         &replicas,
+		IdleTimeout:        DefaultIdleTimeout,
+		ExecutionTimeoutMs: timeout * 1000,
+		ScalerType:         DefaultScalerType,
+		ScalerValue:        DefaultScalerValue,
+	}, nil
+}
+
+// isActiveWorker reports whether a worker is still part of the endpoint
+func isActiveWorker(w *Worker) bool {
+	return w.DesiredStatus != RunPodStatusExited && w.DesiredStatus != RunPodStatusTerminated
+}
+
+// countWorkers counts the active and running workers of an endpoint
+func countWorkers(ep *Endpoint) (active, running int) {
+	for i := range ep.Workers {
+		if !isActiveWorker(&ep.Workers[i]) {
+			continue
+		}
+		active++
+		if ep.Workers[i].DesiredStatus == RunPodStatusRunning {
+			running++
+		}
+	}
+	return active, running
+}
+
+// endpointStatus derives a Waverless status; RunPod endpoints have no state of their own
+func endpointStatus(ep *Endpoint) string {
+	_, running := countWorkers(ep)
+	switch {
+	case ep.WorkersMax == 0:
+		return StatusStopped
+	case running >= ep.WorkersMin && running > 0:
+		return StatusRunning
+	default:
+		return StatusPending
+	}
+}
+
+// mapWorkerStatus converts a RunPod worker status to a Waverless pod status
+func mapWorkerStatus(desiredStatus string) string {
+	switch desiredStatus {
+	case RunPodStatusRunning:
+		return StatusRunning
+	case RunPodStatusExited, RunPodStatusTerminated:
+		return StatusTerminating
+	case "":
+		return StatusUnknown
+	default:
+		return StatusPending
+	}
+}
+
+// mapEndpointToAppInfo converts a RunPod endpoint to Waverless AppInfo
+func mapEndpointToAppInfo(ep *Endpoint) *interfaces.AppInfo {
+	if ep == nil {
+		return nil
+	}
+	_, running := countWorkers(ep)
+
+	labels := map[string]string{
+		LabelKeyProvider:   LabelValueRunPod,
+		LabelKeyEndpointID: ep.ID,
+		LabelKeyTemplateID: ep.TemplateID,
+	}
+	var image string
+	if ep.Template != nil {
+		image = ep.Template.ImageName
+	}
+
+	return &interfaces.AppInfo{
+		Name:              ep.Name,
+		Type:              TypeServerlessEndpoint,
+		Status:            endpointStatus(ep),
+		Replicas:          int32(ep.WorkersMax),
+		ReadyReplicas:     int32(running),
+		AvailableReplicas: int32(running),
+		Image:             image,
+		Labels:            labels,
+		CreatedAt:         ep.CreatedAt,
+	}
+}
+
+// mapEndpointToAppStatus converts a RunPod endpoint to Waverless AppStatus
+func mapEndpointToAppStatus(endpointName string, ep *Endpoint) *interfaces.AppStatus {
+	if ep == nil {
+		return &interfaces.AppStatus{
+			Endpoint: endpointName,
+			Status:   StatusUnknown,
+			Message:  MessageNoStatusInfo,
+		}
+	}
+	active, running := countWorkers(ep)
+	status := endpointStatus(ep)
+
+	return &interfaces.AppStatus{
+		Endpoint:          endpointName,
+		Status:            status,
+		ReadyReplicas:     int32(running),
+		AvailableReplicas: int32(running),
+		TotalReplicas:     int32(active),
+		// RunPod rolls workers to a new template itself without reporting progress
+		RolloutComplete: status == StatusRunning || status == StatusStopped,
+	}
+}
+
+// workerToPodInfo converts a RunPod worker to interfaces.PodInfo; the worker ID is the pod name
+// (workers see it as RUNPOD_POD_ID)
+func workerToPodInfo(w *Worker, state *workerState) *interfaces.PodInfo {
+	info := &interfaces.PodInfo{
+		Name:      w.ID,
+		Phase:     w.DesiredStatus,
+		Status:    mapWorkerStatus(w.DesiredStatus),
+		StartedAt: w.LastStartedAt,
+	}
+	if !isActiveWorker(w) {
+		info.DeletionTimestamp = w.LastStatusChange
+	}
+	if state != nil && state.CreatedAt != nil {
+		info.CreatedAt = state.CreatedAt.Format(time.RFC3339)
+	}
+	return info
+}
@@ -0,0 +1,763 @@
+package runpod
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+	"waverless/pkg/ratelimit"
+)
+
+// clientInterface defines the interface for RunPod API client (for testing)
+type clientInterface interface {
+	CreateTemplate(ctx context.Context, req *TemplateRequest) (*Template, error)
+	UpdateTemplate(ctx context.Context, templateID string, req *TemplateRequest) error
+	DeleteTemplate(ctx context.Context, templateID string) error
+	CreateEndpoint(ctx context.Context, req *EndpointRequest) (*Endpoint, error)
+	GetEndpoint(ctx context.Context, endpointID string) (*Endpoint, error)
+	ListEndpoints(ctx context.Context) ([]Endpoint, error)
+	UpdateEndpoint(ctx context.Context, endpointID string, req *EndpointRequest) error
+	DeleteEndpoint(ctx context.Context, endpointID string) error
+	// Registry Auth methods
+	CreateRegistryAuth(ctx context.Context, req *RegistryAuthRequest) (*RegistryAuth, error)
+	ListRegistryAuths(ctx context.Context) ([]RegistryAuth, error)
+	DeleteRegistryAuth(ctx context.Context, authID string) error
+}
+
+// endpointState stores the last known state of an endpoint
+type endpointState struct {
+	DesiredReplicas   int
+	ReadyReplicas     int
+	AvailableReplicas int
+	Status            string
+}
+
+// workerState stores the last known state of a worker
+type workerState struct {
+	ID        string
+	Endpoint  string
+	Status    string
+	CreatedAt *time.Time // First time the worker was seen (RunPod does not report creation time)
+}
+
+// WorkerStatusChangeCallback is called when a worker's status changes
+type WorkerStatusChangeCallback = func(workerID, endpoint string, info *interfaces.PodInfo)
+
+// WorkerDeleteCallback is called when a worker is gone
+type WorkerDeleteCallback = func(workerID, endpoint string)
+
+// RunPodDeploymentProvider implements interfaces.DeploymentProvider for RunPod Serverless.
+// Each endpoint is a RunPod template (image, env, disk) plus a RunPod endpoint (GPUs, workers)
+// pinned to the desired replicas; workers pull tasks from Waverless, not from RunPod's queue.
+type RunPodDeploymentProvider struct {
+	client        clientInterface
+	config        *config.RunPodConfig
+	specsConfig   *SpecsConfig
+	globalEnv     map[string]string
+	endpointCache sync.Map // Cache endpoint ID mappings: name -> endpointID
+
+	// WatchReplicas / WatchPodStatusChange / WatchPodDelete support
+	callbacksLock         sync.RWMutex
+	nextCallbackID        uint64
+	replicaCallbacks      map[uint64]interfaces.ReplicaCallback
+	workerStatusCallbacks map[uint64]WorkerStatusChangeCallback
+	workerDeleteCallbacks map[uint64]WorkerDeleteCallback
+	endpointStates        sync.Map // endpoint name -> *endpointState
+	workerStates          sync.Map // workerID -> *workerState
+	watcherRunning        atomic.Bool
+	watcherStopCh         chan struct{}
+	pollInterval          time.Duration
+
+	rateLimiter *ratelimit.Limiter // Throttles API calls of the API key (nil = unthrottled)
+}
+
+// NewRunPodDeploymentProvider creates a new RunPod deployment provider
+func NewRunPodDeploymentProvider(cfg *config.Config) (interfaces.DeploymentProvider, error) {
+	if !cfg.RunPod.Enabled {
+		return nil, fmt.Errorf("runpod provider is not enabled in config")
+	}
+	if cfg.RunPod.APIKey == "" {
+		return nil, fmt.Errorf("runpod API key is required")
+	}
+
+	specsConfig, err := NewSpecsConfig(cfg.RunPod.ConfigDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize specs config: %w", err)
+	}
+
+	pollInterval := 10 * time.Second
+	if cfg.RunPod.PollInterval > 0 {
+		pollInterval = time.Duration(cfg.RunPod.PollInterval) * time.Second
+	}
+
+	return newProvider(NewClient(&cfg.RunPod), &cfg.RunPod, specsConfig, pollInterval, defaultGlobalEnv(cfg)), nil
+}
+
+func newProvider(client clientInterface, cfg *config.RunPodConfig, specsConfig *SpecsConfig, pollInterval time.Duration, globalEnv map[string]string) *RunPodDeploymentProvider {
+	return &RunPodDeploymentProvider{
+		client:                client,
+		config:                cfg,
+		specsConfig:           specsConfig,
+		globalEnv:             globalEnv,
+		replicaCallbacks:      make(map[uint64]interfaces.ReplicaCallback),
+		workerStatusCallbacks: make(map[uint64]WorkerStatusChangeCallback),
+		workerDeleteCallbacks: make(map[uint64]WorkerDeleteCallback),
+		watcherStopCh:         make(chan struct{}),
+		pollInterval:          pollInterval,
+	}
+}
+
+// defaultGlobalEnv points workers at the Waverless worker API. RunPod sets RUNPOD_POD_ID to the
+// worker ID in every worker, which is also the pod name the provider reports.
+func defaultGlobalEnv(cfg *config.Config) map[string]string {
+	return map[string]string{
+		"WAVERLESS_ENDPOINT_ID":         "{{.Endpoint}}",
+		"WAVERLESS_PING_INTERVAL":       "10000",
+		"WAVERLESS_WEBHOOK_GET_JOB":     cfg.Server.BaseURL + "/v2/{{.Endpoint}}/job-take/$ID?",
+		"WAVERLESS_WEBHOOK_PING":        cfg.Server.BaseURL + "/v2/{{.Endpoint}}/ping/$RUNPOD_POD_ID",
+		"WAVERLESS_WEBHOOK_POST_OUTPUT": cfg.Server.BaseURL + "/v2/{{.Endpoint}}/job-done/$RUNPOD_POD_ID/$ID?",
+		"WAVERLESS_WEBHOOK_POST_STREAM": cfg.Server.BaseURL + "/v2/{{.Endpoint}}/job-stream/$RUNPOD_POD_ID/$ID?",
+		"WAVERLESS_API_KEY":             cfg.Server.APIKey,
+		EnvKeyProviderType:              EnvValueRunPod,
+	}
+}
+
+// workerEnv merges the global env of an endpoint with its own (request takes precedence)
+func (p *RunPodDeploymentProvider) workerEnv(endpoint string, env map[string]string) map[string]string {
+	merged := make(map[string]string, len(p.globalEnv)+len(env))
+	for k, v := range p.globalEnv {
+		merged[k] = strings.ReplaceAll(v, "{{.Endpoint}}", endpoint)
+	}
+	for k, v := range env {
+		merged[k] = v
+	}
+	return merged
+}
+
+// checkSupported rejects deploy options that only the K8s provider implements
+func checkSupported(req *interfaces.DeployRequest) error {
+	if req.EgressPolicy != nil || req.RestrictedServiceAccount {
+		return fmt.Errorf("egress policy and restricted service account are not supported by the RunPod provider")
+	}
+	if len(req.Storage) > 0 || len(req.VolumeMounts) > 0 {
+		return fmt.Errorf("volume mounts and provisioned storage are not supported by the RunPod provider")
+	}
+	return nil
+}
+
+// Deploy deploys an application to RunPod serverless: a template, then an endpoint running it
+func (p *RunPodDeploymentProvider) Deploy(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
+	logger.Infof("Deploying endpoint %s to RunPod", req.Endpoint)
+
+	if err := checkSupported(req); err != nil {
+		return nil, err
+	}
+	specInfo, err := p.specsConfig.GetSpec(req.SpecName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spec for %s: %w", req.SpecName, err)
+	}
+
+	tmplReq, err := buildTemplateRequest(req, specInfo, p.workerEnv(req.Endpoint, req.Env))
+	if err != nil {
+		return nil, fmt.Errorf("failed to map deploy request to RunPod: %w", err)
+	}
+	// Validate the endpoint mapping before creating anything
+	if _, err := buildEndpointRequest(req, specInfo, ""); err != nil {
+		return nil, fmt.Errorf("failed to map deploy request to RunPod: %w", err)
+	}
+
+	if req.RegistryCredential != nil {
+		authID, err := p.ensureRegistryAuth(ctx, req.Endpoint, req.RegistryCredential)
+		if err != nil {
+			return nil, fmt.Errorf("failed to ensure registry auth: %w", err)
+		}
+		tmplReq.ContainerRegistryAuthID = authID
+	}
+
+	tmpl, err := p.client.CreateTemplate(ctx, tmplReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RunPod template: %w", err)
+	}
+
+	epReq, _ := buildEndpointRequest(req, specInfo, tmpl.ID)
+	ep, err := p.client.CreateEndpoint(ctx, epReq)
+	if err != nil {
+		// Do not leave an unused template behind
+		if delErr := p.client.DeleteTemplate(ctx, tmpl.ID); delErr != nil {
+			logger.Warnf("Failed to delete RunPod template %s after failed deploy: %v", tmpl.ID, delErr)
+		}
+		return nil, fmt.Errorf("failed to create RunPod endpoint: %w", err)
+	}
+
+	p.endpointCache.Store(req.Endpoint, ep.ID)
+	logger.Infof("Successfully deployed endpoint %s to RunPod (ID: %s, template: %s)", req.Endpoint, ep.ID, tmpl.ID)
+
+	return &interfaces.DeployResponse{
+		Endpoint:  req.Endpoint,
+		Message:   fmt.Sprintf("%s (ID: %s)", MessageDeploySuccess, ep.ID),
+		CreatedAt: ep.CreatedAt,
+	}, nil
+}
+
+// ensureRegistryAuth returns the registry auth of an endpoint, creating it if needed
+func (p *RunPodDeploymentProvider) ensureRegistryAuth(ctx context.Context, endpoint string, cred *interfaces.RegistryCredential) (string, error) {
+	name := templateName(endpoint)
+	auths, err := p.client.ListRegistryAuths(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, auth := range auths {
+		if auth.Name == name {
+			// Passwords are never returned, so an existing auth can't be compared; replace it
+			if err := p.client.DeleteRegistryAuth(ctx, auth.ID); err != nil {
+				return "", fmt.Errorf("failed to replace registry auth %s: %w", auth.ID, err)
+			}
+		}
+	}
+
+	auth, err := p.client.CreateRegistryAuth(ctx, &RegistryAuthRequest{
+		Name:     name,
+		Username: cred.Username,
+		Password: cred.Password,
+	})
+	if err != nil {
+		return "", err
+	}
+	return auth.ID, nil
+}
+
+// GetApp retrieves application details
+func (p *RunPodDeploymentProvider) GetApp(ctx context.Context, endpoint string) (*interfaces.AppInfo, error) {
+	ep, err := p.getEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return mapEndpointToAppInfo(ep), nil
+}
+
+// ListApps lists all applications
+func (p *RunPodDeploymentProvider) ListApps(ctx context.Context) ([]*interfaces.AppInfo, error) {
+	eps, err := p.client.ListEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints from RunPod: %w", err)
+	}
+
+	apps := make([]*interfaces.AppInfo, 0, len(eps))
+	for i := range eps {
+		p.endpointCache.Store(eps[i].Name, eps[i].ID)
+		apps = append(apps, mapEndpointToAppInfo(&eps[i]))
+	}
+	return apps, nil
+}
+
+// DeleteApp deletes the endpoint, then its template and registry auth
+func (p *RunPodDeploymentProvider) DeleteApp(ctx context.Context, endpoint string) error {
+	logger.Infof("Deleting endpoint %s from RunPod", endpoint)
+
+	ep, err := p.getEndpoint(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	// A template can't be deleted while an endpoint uses it, and workers must stop first
+	if ep.WorkersMax > 0 {
+		zero := 0
+		if err := p.client.UpdateEndpoint(ctx, ep.ID, &EndpointRequest{WorkersMin: &zero, WorkersMax: &zero}); err != nil {
+			return fmt.Errorf("failed to scale RunPod endpoint to zero before delete: %w", err)
+		}
+	}
+	if err := p.client.DeleteEndpoint(ctx, ep.ID); err != nil {
+		return fmt.Errorf("failed to delete endpoint from RunPod: %w", err)
+	}
+	p.endpointCache.Delete(endpoint)
+
+	if ep.TemplateID != "" {
+		if err := p.client.DeleteTemplate(ctx, ep.TemplateID); err != nil {
+			logger.Warnf("Failed to delete RunPod template %s of endpoint %s: %v", ep.TemplateID, endpoint, err)
+		}
+	}
+	if ep.Template != nil && ep.Template.ContainerRegistryAuthID != "" {
+		if err := p.client.DeleteRegistryAuth(ctx, ep.Template.ContainerRegistryAuthID); err != nil {
+			logger.Warnf("Failed to delete RunPod registry auth %s of endpoint %s: %v", ep.Template.ContainerRegistryAuthID, endpoint, err)
+		}
+	}
+
+	logger.Infof("%s %s (ID: %s)", MessageDeleteSuccess, endpoint, ep.ID)
+	return nil
+}
+
+// ScaleApp scales application replicas. RunPod has no drain call: graceful scale-down drains
+// workers through Waverless before the count is lowered.
+func (p *RunPodDeploymentProvider) ScaleApp(ctx context.Context, endpoint string, replicas int) error {
+	logger.Infof("Scaling endpoint %s to %d replicas", endpoint, replicas)
+
+	endpointID, err := p.getEndpointID(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if err := p.client.UpdateEndpoint(ctx, endpointID, &EndpointRequest{WorkersMin: &replicas, WorkersMax: &replicas}); err != nil {
+		return fmt.Errorf("failed to scale endpoint: %w", err)
+	}
+
+	logger.Infof("Successfully scaled endpoint %s to %d replicas", endpoint, replicas)
+	return nil
+}
+
+// GetAppStatus retrieves application status
+func (p *RunPodDeploymentProvider) GetAppStatus(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+	ep, err := p.getEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return mapEndpointToAppStatus(endpoint, ep), nil
+}
+
+// GetAppLogs retrieves application logs (not supported by RunPod)
+func (p *RunPodDeploymentProvider) GetAppLogs(ctx context.Context, endpoint string, lines int, podName ...string) (string, error) {
+	return "", errors.New(MessageLogsNotSupported)
+}
+
+// UpdateDeployment updates the template (image, env, disk) and the endpoint (spec, replicas,
+// timeout). Workers pick template changes up as RunPod replaces them.
+func (p *RunPodDeploymentProvider) UpdateDeployment(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error) {
+	logger.Infof("Updating deployment for endpoint %s", req.Endpoint)
+
+	if req.VolumeMounts != nil && len(*req.VolumeMounts) > 0 {
+		return nil, fmt.Errorf("volume mounts are not supported by the RunPod provider")
+	}
+
+	ep, err := p.getEndpoint(ctx, req.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var tmplReq TemplateRequest
+	tmplChanged := false
+	if req.Image != "" {
+		tmplReq.ImageName = req.Image
+		tmplChanged = true
+	}
+	if req.Env != nil {
+		tmplReq.Env = p.workerEnv(req.Endpoint, *req.Env)
+		tmplChanged = true
+	}
+	if req.EphemeralStorage != nil && *req.EphemeralStorage != "" {
+		disk, err := diskGB(*req.EphemeralStorage)
+		if err != nil {
+			return nil, err
+		}
+		tmplReq.ContainerDiskInGb = disk
+		tmplChanged = true
+	}
+	if tmplChanged {
+		if ep.TemplateID == "" {
+			return nil, fmt.Errorf("RunPod endpoint %s has no template", req.Endpoint)
+		}
+		if err := p.client.UpdateTemplate(ctx, ep.TemplateID, &tmplReq); err != nil {
+			return nil, fmt.Errorf("failed to update RunPod template: %w", err)
+		}
+	}
+
+	var epReq EndpointRequest
+	epChanged := false
+	if req.SpecName != "" {
+		specInfo, err := p.specsConfig.GetSpec(req.SpecName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get spec for %s: %w", req.SpecName, err)
+		}
+		cfg, err := extractRunPodConfig(specInfo)
+		if err != nil {
+			return nil, err
+		}
+		gpuCount, err := gpuCountOf(&interfaces.DeployRequest{}, specInfo)
+		if err != nil {
+			return nil, err
+		}
+		epReq.GpuTypeIDs = cfg.GpuTypeIDs
+		epReq.DataCenterIDs = cfg.DataCenterIDs
+		epReq.GpuCount = gpuCount
+		epChanged = true
+	}
+	if req.Replicas != nil {
+		epReq.WorkersMin = req.Replicas
+		epReq.WorkersMax = req.Replicas
+		epChanged = true
+	}
+	if req.TaskTimeout != nil && *req.TaskTimeout > 0 {
+		epReq.ExecutionTimeoutMs = *req.TaskTimeout * 1000
+		epChanged = true
+	}
+	if epChanged {
+		if err := p.client.UpdateEndpoint(ctx, ep.ID, &epReq); err != nil {
+			return nil, fmt.Errorf("failed to update endpoint: %w", err)
+		}
+	}
+
+	logger.Infof("Successfully updated endpoint %s", req.Endpoint)
+	return &interfaces.DeployResponse{
+		Endpoint: req.Endpoint,
+		Message:  MessageUpdateSuccess,
+	}, nil
+}
+
+// ListSpecs lists available specifications
+func (p *RunPodDeploymentProvider) ListSpecs(ctx context.Context) ([]*interfaces.SpecInfo, error) {
+	return p.specsConfig.ListSpecs(), nil
+}
+
+// GetSpec retrieves specification details
+func (p *RunPodDeploymentProvider) GetSpec(ctx context.Context, specName string) (*interfaces.SpecInfo, error) {
+	return p.specsConfig.GetSpec(specName)
+}
+
+// PreviewDeploymentYAML previews deployment configuration (returns the template and endpoint requests as JSON)
+func (p *RunPodDeploymentProvider) PreviewDeploymentYAML(ctx context.Context, req *interfaces.DeployRequest) (string, error) {
+	if err := checkSupported(req); err != nil {
+		return "", err
+	}
+	specInfo, err := p.specsConfig.GetSpec(req.SpecName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get spec for %s: %w", req.SpecName, err)
+	}
+	tmplReq, err := buildTemplateRequest(req, specInfo, p.workerEnv(req.Endpoint, req.Env))
+	if err != nil {
+		return "", fmt.Errorf("failed to map deploy request to RunPod: %w", err)
+	}
+	epReq, err := buildEndpointRequest(req, specInfo, "<template id>")
+	if err != nil {
+		return "", fmt.Errorf("failed to map deploy request to RunPod: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(map[string]interface{}{
+		"template": tmplReq,
+		"endpoint": epReq,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal RunPod config: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+// WatchReplicas watches replica count changes using polling mechanism
+func (p *RunPodDeploymentProvider) WatchReplicas(ctx context.Context, callback interfaces.ReplicaCallback) error {
+	if callback == nil {
+		return fmt.Errorf("replica callback is nil")
+	}
+	p.registerCallback(ctx, "replica watch", func(id uint64) {
+		p.replicaCallbacks[id] = callback
+	}, func(id uint64) {
+		delete(p.replicaCallbacks, id)
+	})
+	return nil
+}
+
+// WatchPodStatusChange registers a callback to observe worker status changes
+// This is the RunPod equivalent of K8s WatchPodStatusChange
+func (p *RunPodDeploymentProvider) WatchPodStatusChange(ctx context.Context, callback WorkerStatusChangeCallback) error {
+	if callback == nil {
+		return fmt.Errorf("worker status change callback is nil")
+	}
+	p.registerCallback(ctx, "worker status change", func(id uint64) {
+		p.workerStatusCallbacks[id] = callback
+	}, func(id uint64) {
+		delete(p.workerStatusCallbacks, id)
+	})
+	return nil
+}
+
+// WatchPodDelete registers a callback to observe worker deletions
+// This is the RunPod equivalent of K8s WatchPodDelete
+func (p *RunPodDeploymentProvider) WatchPodDelete(ctx context.Context, callback WorkerDeleteCallback) error {
+	if callback == nil {
+		return fmt.Errorf("worker delete callback is nil")
+	}
+	p.registerCallback(ctx, "worker delete", func(id uint64) {
+		p.workerDeleteCallbacks[id] = callback
+	}, func(id uint64) {
+		delete(p.workerDeleteCallbacks, id)
+	})
+	return nil
+}
+
+// registerCallback adds a callback under the callbacks lock, starts the shared poller and
+// removes the callback when ctx is done
+func (p *RunPodDeploymentProvider) registerCallback(ctx context.Context, kind string, add, remove func(id uint64)) {
+	p.callbacksLock.Lock()
+	id := atomic.AddUint64(&p.nextCallbackID, 1)
+	add(id)
+	p.callbacksLock.Unlock()
+	logger.Infof("Registered %s callback (ID: %d) for RunPod", kind, id)
+
+	if p.watcherRunning.CompareAndSwap(false, true) {
+		logger.Infof("Starting RunPod watcher (poll interval: %v)", p.pollInterval)
+		go p.runWatcher(ctx)
+	}
+
+	go func() {
+		<-ctx.Done()
+		p.callbacksLock.Lock()
+		remove(id)
+		p.callbacksLock.Unlock()
+		logger.Infof("Unregistered %s callback (ID: %d)", kind, id)
+	}()
+}
+
+// runWatcher runs the polling loop to monitor endpoint and worker changes
+func (p *RunPodDeploymentProvider) runWatcher(ctx context.Context) {
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Infof("RunPod watcher stopped (context done)")
+			p.watcherRunning.Store(false)
+			return
+		case <-p.watcherStopCh:
+			logger.Infof("RunPod watcher stopped (stop signal)")
+			p.watcherRunning.Store(false)
+			return
+		case <-ticker.C:
+			p.pollEndpointStates(ctx)
+		}
+	}
+}
+
+// pollEndpointStates lists all endpoints once and reports endpoint and worker changes
+func (p *RunPodDeploymentProvider) pollEndpointStates(ctx context.Context) {
+	eps, err := p.client.ListEndpoints(ctx)
+	if err != nil {
+		logger.Errorf("Failed to list RunPod endpoints for polling: %v", err)
+		return
+	}
+
+	currentWorkerIDs := make(map[string]bool)
+	for i := range eps {
+		ep := &eps[i]
+		p.endpointCache.Store(ep.Name, ep.ID)
+
+		_, running := countWorkers(ep)
+		state := &endpointState{
+			DesiredReplicas:   ep.WorkersMax,
+			ReadyReplicas:     running,
+			AvailableReplicas: running,
+			Status:            endpointStatus(ep),
+		}
+		previous, exists := p.endpointStates.Load(ep.Name)
+		p.endpointStates.Store(ep.Name, state)
+		if !exists || *previous.(*endpointState) != *state {
+			p.triggerReplicaCallbacks(interfaces.ReplicaEvent{
+				Name:              ep.Name,
+				DesiredReplicas:   state.DesiredReplicas,
+				ReadyReplicas:     state.ReadyReplicas,
+				AvailableReplicas: state.AvailableReplicas,
+			})
+		}
+
+		for j := range ep.Workers {
+			w := &ep.Workers[j]
+			if !isActiveWorker(w) {
+				continue
+			}
+			currentWorkerIDs[w.ID] = true
+			p.processWorkerState(ep.Name, w)
+		}
+	}
+
+	// Workers no longer listed (or exited) are gone
+	p.workerStates.Range(func(key, value interface{}) bool {
+		workerID := key.(string)
+		if !currentWorkerIDs[workerID] {
+			state := value.(*workerState)
+			logger.Infof("Worker deleted: %s (endpoint: %s)", workerID, state.Endpoint)
+			p.workerStates.Delete(workerID)
+			p.notifyWorkerDelete(workerID, state.Endpoint)
+		}
+		return true
+	})
+}
+
+// processWorkerState records a worker and reports new workers and status changes
+func (p *RunPodDeploymentProvider) processWorkerState(endpoint string, w *Worker) {
+	previous, exists := p.workerStates.Load(w.ID)
+	if exists && previous.(*workerState).Status == w.DesiredStatus {
+		return
+	}
+
+	state := &workerState{ID: w.ID, Endpoint: endpoint, Status: w.DesiredStatus}
+	if exists {
+		state.CreatedAt = previous.(*workerState).CreatedAt
+		logger.Infof("Worker state changed: %s (endpoint: %s, state: %s -> %s)",
+			w.ID, endpoint, previous.(*workerState).Status, w.DesiredStatus)
+	} else {
+		now := time.Now()
+		state.CreatedAt = &now
+		logger.Infof("New worker detected: %s (endpoint: %s, state: %s)", w.ID, endpoint, w.DesiredStatus)
+	}
+	p.workerStates.Store(w.ID, state)
+	p.notifyWorkerStatusChange(w.ID, endpoint, workerToPodInfo(w, state))
+}
+
+// triggerReplicaCallbacks triggers all registered callbacks with the event
+func (p *RunPodDeploymentProvider) triggerReplicaCallbacks(event interfaces.ReplicaEvent) {
+	p.callbacksLock.RLock()
+	defer p.callbacksLock.RUnlock()
+	for _, cb := range p.replicaCallbacks {
+		cb := cb
+		safeGo("replica", func() { cb(event) })
+	}
+}
+
+// notifyWorkerStatusChange notifies all registered callbacks about worker status change
+func (p *RunPodDeploymentProvider) notifyWorkerStatusChange(workerID, endpoint string, info *interfaces.PodInfo) {
+	p.callbacksLock.RLock()
+	defer p.callbacksLock.RUnlock()
+	for _, cb := range p.workerStatusCallbacks {
+		cb := cb
+		safeGo("worker status change", func() { cb(workerID, endpoint, info) })
+	}
+}
+
+// notifyWorkerDelete notifies all registered callbacks about worker deletion
+func (p *RunPodDeploymentProvider) notifyWorkerDelete(workerID, endpoint string) {
+	p.callbacksLock.RLock()
+	defer p.callbacksLock.RUnlock()
+	for _, cb := range p.workerDeleteCallbacks {
+		cb := cb
+		safeGo("worker delete", func() { cb(workerID, endpoint) })
+	}
+}
+
+// safeGo runs a callback in a goroutine so a slow or panicking callback can't stall polling
+func safeGo(kind string, fn func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Errorf("Panic in %s callback: %v", kind, r)
+			}
+		}()
+		fn()
+	}()
+}
+
+// StopReplicaWatcher stops the watcher
+func (p *RunPodDeploymentProvider) StopReplicaWatcher() {
+	if p.watcherRunning.Load() {
+		close(p.watcherStopCh)
+	}
+}
+
+// GetPods lists the active workers of an endpoint; the worker ID is the pod name
+func (p *RunPodDeploymentProvider) GetPods(ctx context.Context, endpoint string) ([]*interfaces.PodInfo, error) {
+	ep, err := p.getEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]*interfaces.PodInfo, 0, len(ep.Workers))
+	for i := range ep.Workers {
+		w := &ep.Workers[i]
+		if !isActiveWorker(w) {
+			continue
+		}
+		var state *workerState
+		if v, ok := p.workerStates.Load(w.ID); ok {
+			state = v.(*workerState)
+		}
+		pods = append(pods, workerToPodInfo(w, state))
+	}
+	return pods, nil
+}
+
+// DescribePod retrieves detailed Pod information (not supported by RunPod)
+func (p *RunPodDeploymentProvider) DescribePod(ctx context.Context, endpoint string, podName string) (*interfaces.PodDetail, error) {
+	return nil, fmt.Errorf("DescribePod %s", MessageNotSupported)
+}
+
+// GetPodYAML retrieves Pod YAML (not supported by RunPod)
+func (p *RunPodDeploymentProvider) GetPodYAML(ctx context.Context, endpoint string, podName string) (string, error) {
+	return "", fmt.Errorf("GetPodYAML %s", MessageNotSupported)
+}
+
+// ListPVCs lists all PersistentVolumeClaims (not supported by RunPod)
+func (p *RunPodDeploymentProvider) ListPVCs(ctx context.Context) ([]*interfaces.PVCInfo, error) {
+	return nil, fmt.Errorf("ListPVCs %s", MessageNotSupported)
+}
+
+// GetDefaultEnv retrieves default environment variables
+func (p *RunPodDeploymentProvider) GetDefaultEnv(ctx context.Context) (map[string]string, error) {
+	return map[string]string{
+		EnvKeyProviderType: EnvValueRunPod,
+	}, nil
+}
+
+// IsPodTerminating checks if a worker is terminating (RunPod removes exited workers at once)
+func (p *RunPodDeploymentProvider) IsPodTerminating(ctx context.Context, podName string) (bool, error) {
+	return false, nil
+}
+
+// getEndpoint fetches an endpoint by Waverless name
+func (p *RunPodDeploymentProvider) getEndpoint(ctx context.Context, endpoint string) (*Endpoint, error) {
+	endpointID, err := p.getEndpointID(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := p.client.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			p.endpointCache.Delete(endpoint)
+		}
+		return nil, fmt.Errorf("failed to get endpoint from RunPod: %w", err)
+	}
+	return ep, nil
+}
+
+// getEndpointID retrieves the RunPod endpoint ID for a given endpoint name
+// It first checks the cache, then queries the API if not found
+func (p *RunPodDeploymentProvider) getEndpointID(ctx context.Context, endpoint string) (string, error) {
+	if id, ok := p.endpointCache.Load(endpoint); ok {
+		return id.(string), nil
+	}
+
+	eps, err := p.client.ListEndpoints(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list endpoints: %w", err)
+	}
+	for _, ep := range eps {
+		p.endpointCache.Store(ep.Name, ep.ID)
+		if ep.Name == endpoint {
+			return ep.ID, nil
+		}
+	}
+	return "", interfaces.ProviderErrorf(interfaces.ErrNotFound, "endpoint %s not found in RunPod", endpoint)
+}
+
+// SetSpecRepository sets the spec repository for database access
+func (p *RunPodDeploymentProvider) SetSpecRepository(repo SpecRepositoryInterface) {
+	p.specsConfig.SetSpecRepository(repo)
+}
+
+// SetRateLimiter throttles the provider's API calls (for dependency injection)
+func (p *RunPodDeploymentProvider) SetRateLimiter(limiter *ratelimit.Limiter) {
+	p.rateLimiter = limiter
+	if c, ok := p.client.(*Client); ok {
+		c.SetRateLimiter(limiter)
+	}
+}
+
+// RateLimitStats returns the queue and wait metrics of the API rate limiter (nil = unthrottled)
+func (p *RunPodDeploymentProvider) RateLimitStats() *ratelimit.Stats {
+	if p.rateLimiter == nil {
+		return nil
+	}
+	stats := p.rateLimiter.Stats()
+	return &stats
+}
@@ -0,0 +1,510 @@
+package runpod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/config"
+	"waverless/pkg/interfaces"
+)
+
+// mockClient is an in-memory RunPod API for testing
+type mockClient struct {
+	mu        sync.Mutex
+	templates map[string]*Template
+	endpoints map[string]*Endpoint
+	auths     []RegistryAuth
+	nextID    int
+
+	createEndpointError error
+	endpointUpdates     []EndpointRequest
+	templateUpdates     []TemplateRequest
+	deletedTemplates    []string
+	deletedAuths        []string
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{
+		templates: make(map[string]*Template),
+		endpoints: make(map[string]*Endpoint),
+	}
+}
+
+func (m *mockClient) id(prefix string) string {
+	m.nextID++
+	return fmt.Sprintf("%s-%d", prefix, m.nextID)
+}
+
+func (m *mockClient) CreateTemplate(ctx context.Context, req *TemplateRequest) (*Template, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tmpl := &Template{
+		ID:                      m.id("tpl"),
+		Name:                    req.Name,
+		ImageName:               req.ImageName,
+		Env:                     req.Env,
+		ContainerDiskInGb:       req.ContainerDiskInGb,
+		ContainerRegistryAuthID: req.ContainerRegistryAuthID,
+		IsServerless:            req.IsServerless,
+	}
+	m.templates[tmpl.ID] = tmpl
+	return tmpl, nil
+}
+
+func (m *mockClient) UpdateTemplate(ctx context.Context, templateID string, req *TemplateRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tmpl, ok := m.templates[templateID]
+	if !ok {
+		return interfaces.ProviderErrorf(interfaces.ErrNotFound, "template %s not found", templateID)
+	}
+	m.templateUpdates = append(m.templateUpdates, *req)
+	if req.ImageName != "" {
+		tmpl.ImageName = req.ImageName
+	}
+	if req.Env != nil {
+		tmpl.Env = req.Env
+	}
+	if req.ContainerDiskInGb > 0 {
+		tmpl.ContainerDiskInGb = req.ContainerDiskInGb
+	}
+	return nil
+}
+
+func (m *mockClient) DeleteTemplate(ctx context.Context, templateID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.templates, templateID)
+	m.deletedTemplates = append(m.deletedTemplates, templateID)
+	return nil
+}
+
+func (m *mockClient) CreateEndpoint(ctx context.Context, req *EndpointRequest) (*Endpoint, error) {
+	if m.createEndpointError != nil {
+		return nil, m.createEndpointError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ep := &Endpoint{
+		ID:                 m.id("ep"),
+		Name:               req.Name,
+		TemplateID:         req.TemplateID,
+		Template:           m.templates[req.TemplateID],
+		ComputeType:        req.ComputeType,
+		GpuTypeIDs:         req.GpuTypeIDs,
+		GpuCount:           req.GpuCount,
+		DataCenterIDs:      req.DataCenterIDs,
+		WorkersMin:         *req.WorkersMin,
+		WorkersMax:         *req.WorkersMax,
+		ExecutionTimeoutMs: req.ExecutionTimeoutMs,
+		CreatedAt:          "2025-01-01T00:00:00Z",
+	}
+	m.endpoints[ep.ID] = ep
+	return ep, nil
+}
+
+func (m *mockClient) GetEndpoint(ctx context.Context, endpointID string) (*Endpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ep, ok := m.endpoints[endpointID]
+	if !ok {
+		return nil, interfaces.ProviderErrorf(interfaces.ErrNotFound, "endpoint %s not found", endpointID)
+	}
+	cp := *ep
+	return &cp, nil
+}
+
+func (m *mockClient) ListEndpoints(ctx context.Context) ([]Endpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	eps := make([]Endpoint, 0, len(m.endpoints))
+	for _, ep := range m.endpoints {
+		eps = append(eps, *ep)
+	}
+	return eps, nil
+}
+
+func (m *mockClient) UpdateEndpoint(ctx context.Context, endpointID string, req *EndpointRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ep, ok := m.endpoints[endpointID]
+	if !ok {
+		return interfaces.ProviderErrorf(interfaces.ErrNotFound, "endpoint %s not found", endpointID)
+	}
+	m.endpointUpdates = append(m.endpointUpdates, *req)
+	if req.WorkersMin != nil {
+		ep.WorkersMin = *req.WorkersMin
+	}
+	if req.WorkersMax != nil {
+		ep.WorkersMax = *req.WorkersMax
+	}
+	if len(req.GpuTypeIDs) > 0 {
+		ep.GpuTypeIDs = req.GpuTypeIDs
+	}
+	if req.ExecutionTimeoutMs > 0 {
+		ep.ExecutionTimeoutMs = req.ExecutionTimeoutMs
+	}
+	return nil
+}
+
+func (m *mockClient) DeleteEndpoint(ctx context.Context, endpointID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.endpoints, endpointID)
+	return nil
+}
+
+func (m *mockClient) CreateRegistryAuth(ctx context.Context, req *RegistryAuthRequest) (*RegistryAuth, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	auth := RegistryAuth{ID: m.id("auth"), Name: req.Name}
+	m.auths = append(m.auths, auth)
+	return &auth, nil
+}
+
+func (m *mockClient) ListRegistryAuths(ctx context.Context) ([]RegistryAuth, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RegistryAuth(nil), m.auths...), nil
+}
+
+func (m *mockClient) DeleteRegistryAuth(ctx context.Context, authID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, auth := range m.auths {
+		if auth.ID == authID {
+			m.auths = append(m.auths[:i], m.auths[i+1:]...)
+			break
+		}
+	}
+	m.deletedAuths = append(m.deletedAuths, authID)
+	return nil
+}
+
+// setWorkers replaces the workers of an endpoint
+func (m *mockClient) setWorkers(endpointID string, workers ...Worker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.endpoints[endpointID].Workers = workers
+}
+
+func createTestProvider(t *testing.T, cli *mockClient) *RunPodDeploymentProvider {
+	t.Helper()
+	dir := t.TempDir()
+	specs := `specs:
+  - name: runpod-h100-single
+    displayName: "RunPod H100 1x GPU"
+    category: gpu
+    resources:
+      gpu: "1"
+      gpuType: "NVIDIA-H100"
+      memory: "80Gi"
+      ephemeralStorage: "100Gi"
+    platforms:
+      runpod:
+        gpuTypeIds: ["NVIDIA H100 80GB HBM3", "NVIDIA H100 PCIe"]
+        dataCenterIds: ["US-KS-2"]
+  - name: runpod-a100-dual
+    displayName: "RunPod A100 2x GPU"
+    category: gpu
+    resources:
+      gpu: "2"
+      gpuType: "NVIDIA-A100"
+      memory: "160Gi"
+      ephemeralStorage: "200"
+    platforms:
+      runpod:
+        gpuTypeIds: ["NVIDIA A100 80GB PCIe"]
+  - name: k8s-only
+    category: cpu
+    resources:
+      cpu: "4"
+      memory: "8Gi"
+    platforms:
+      generic: {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "specs.yaml"), []byte(specs), 0644))
+	specsConfig, err := NewSpecsConfig(dir)
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	cfg.Server.BaseURL = "https://waverless.example.com"
+	return newProvider(cli, &config.RunPodConfig{}, specsConfig, 10*time.Millisecond, defaultGlobalEnv(cfg))
+}
+
+func deployTestEndpoint(t *testing.T, p *RunPodDeploymentProvider, replicas int) {
+	t.Helper()
+	_, err := p.Deploy(context.Background(), &interfaces.DeployRequest{
+		Endpoint:    "demo",
+		SpecName:    "runpod-h100-single",
+		Image:       "registry.example.com/demo:v1",
+		Replicas:    replicas,
+		TaskTimeout: 600,
+		Env:         map[string]string{"MODEL": "llama"},
+	})
+	require.NoError(t, err)
+}
+
+func TestNewRunPodDeploymentProvider(t *testing.T) {
+	_, err := NewRunPodDeploymentProvider(&config.Config{})
+	assert.Error(t, err)
+
+	_, err = NewRunPodDeploymentProvider(&config.Config{RunPod: config.RunPodConfig{Enabled: true}})
+	assert.Error(t, err, "API key is required")
+
+	p, err := NewRunPodDeploymentProvider(&config.Config{RunPod: config.RunPodConfig{Enabled: true, APIKey: "key", ConfigDir: t.TempDir()}})
+	require.NoError(t, err)
+	assert.NotNil(t, p)
+}
+
+func TestDeploy(t *testing.T) {
+	cli := newMockClient()
+	p := createTestProvider(t, cli)
+	deployTestEndpoint(t, p, 2)
+
+	require.Len(t, cli.endpoints, 1)
+	var ep *Endpoint
+	for _, e := range cli.endpoints {
+		ep = e
+	}
+	assert.Equal(t, "demo", ep.Name)
+	assert.Equal(t, 2, ep.WorkersMin)
+	assert.Equal(t, 2, ep.WorkersMax)
+	assert.Equal(t, []string{"NVIDIA H100 80GB HBM3", "NVIDIA H100 PCIe"}, ep.GpuTypeIDs)
+	assert.Equal(t, []string{"US-KS-2"}, ep.DataCenterIDs)
+	assert.Equal(t, 1, ep.GpuCount)
+	assert.Equal(t, 600000, ep.ExecutionTimeoutMs)
+
+	tmpl := cli.templates[ep.TemplateID]
+	require.NotNil(t, tmpl)
+	assert.Equal(t, "waverless-demo", tmpl.Name)
+	assert.Equal(t, "registry.example.com/demo:v1", tmpl.ImageName)
+	assert.Equal(t, 108, tmpl.ContainerDiskInGb) // 100Gi rounded up to whole GB
+	assert.True(t, tmpl.IsServerless)
+	assert.Equal(t, "llama", tmpl.Env["MODEL"])
+	assert.Equal(t, "demo", tmpl.Env["WAVERLESS_ENDPOINT_ID"])
+	assert.Equal(t, "https://waverless.example.com/v2/demo/ping/$RUNPOD_POD_ID", tmpl.Env["WAVERLESS_WEBHOOK_PING"])
+	assert.Equal(t, EnvValueRunPod, tmpl.Env[EnvKeyProviderType])
+}
+
+func TestDeployRejectsUnsupported(t *testing.T) {
+	p := createTestProvider(t, newMockClient())
+	ctx := context.Background()
+
+	_, err := p.Deploy(ctx, &interfaces.DeployRequest{Endpoint: "demo", SpecName: "runpod-h100-single", RestrictedServiceAccount: true})
+	assert.Error(t, err)
+
+	_, err = p.Deploy(ctx, &interfaces.DeployRequest{Endpoint: "demo", SpecName: "k8s-only"})
+	assert.Error(t, err, "spec without runpod platform")
+}
+
+func TestDeployCleansUpTemplateOnFailure(t *testing.T) {
+	cli := newMockClient()
+	cli.createEndpointError = fmt.Errorf("out of capacity")
+	p := createTestProvider(t, cli)
+
+	_, err := p.Deploy(context.Background(), &interfaces.DeployRequest{Endpoint: "demo", SpecName: "runpod-h100-single", Image: "img", Replicas: 1})
+	require.Error(t, err)
+	assert.Empty(t, cli.templates)
+	assert.Len(t, cli.deletedTemplates, 1)
+}
+
+func TestDeployWithRegistryCredential(t *testing.T) {
+	cli := newMockClient()
+	cli.auths = []RegistryAuth{{ID: "stale", Name: "waverless-demo"}}
+	p := createTestProvider(t, cli)
+
+	_, err := p.Deploy(context.Background(), &interfaces.DeployRequest{
+		Endpoint:           "demo",
+		SpecName:           "runpod-h100-single",
+		Image:              "private/img",
+		Replicas:           1,
+		RegistryCredential: &interfaces.RegistryCredential{Username: "u", Password: "p"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, cli.auths, 1)
+	assert.NotEqual(t, "stale", cli.auths[0].ID)
+	for _, tmpl := range cli.templates {
+		assert.Equal(t, cli.auths[0].ID, tmpl.ContainerRegistryAuthID)
+	}
+
+	// Deleting the endpoint removes its template and registry auth
+	require.NoError(t, p.DeleteApp(context.Background(), "demo"))
+	assert.Empty(t, cli.endpoints)
+	assert.Empty(t, cli.templates)
+	assert.Empty(t, cli.auths)
+}
+
+func TestScaleApp(t *testing.T) {
+	cli := newMockClient()
+	p := createTestProvider(t, cli)
+	deployTestEndpoint(t, p, 1)
+
+	require.NoError(t, p.ScaleApp(context.Background(), "demo", 4))
+	app, err := p.GetApp(context.Background(), "demo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), app.Replicas)
+
+	assert.Error(t, p.ScaleApp(context.Background(), "missing", 1))
+}
+
+func TestUpdateDeployment(t *testing.T) {
+	cli := newMockClient()
+	p := createTestProvider(t, cli)
+	deployTestEndpoint(t, p, 1)
+
+	image := "registry.example.com/demo:v2"
+	replicas := 3
+	timeout := 120
+	env := map[string]string{"MODEL": "mistral"}
+	_, err := p.UpdateDeployment(context.Background(), &interfaces.UpdateDeploymentRequest{
+		Endpoint:    "demo",
+		Image:       image,
+		SpecName:    "runpod-a100-dual",
+		Replicas:    &replicas,
+		TaskTimeout: &timeout,
+		Env:         &env,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, cli.templateUpdates, 1)
+	assert.Equal(t, image, cli.templateUpdates[0].ImageName)
+	assert.Equal(t, "mistral", cli.templateUpdates[0].Env["MODEL"])
+	assert.Equal(t, "demo", cli.templateUpdates[0].Env["WAVERLESS_ENDPOINT_ID"], "global env is kept")
+
+	require.Len(t, cli.endpointUpdates, 1)
+	upd := cli.endpointUpdates[0]
+	assert.Equal(t, []string{"NVIDIA A100 80GB PCIe"}, upd.GpuTypeIDs)
+	assert.Equal(t, 2, upd.GpuCount)
+	assert.Equal(t, 3, *upd.WorkersMax)
+	assert.Equal(t, 120000, upd.ExecutionTimeoutMs)
+}
+
+func TestUpdateDeploymentReplicasOnly(t *testing.T) {
+	cli := newMockClient()
+	p := createTestProvider(t, cli)
+	deployTestEndpoint(t, p, 1)
+
+	replicas := 0
+	_, err := p.UpdateDeployment(context.Background(), &interfaces.UpdateDeploymentRequest{Endpoint: "demo", Replicas: &replicas})
+	require.NoError(t, err)
+	assert.Empty(t, cli.templateUpdates, "template untouched")
+	require.Len(t, cli.endpointUpdates, 1)
+	assert.Equal(t, 0, *cli.endpointUpdates[0].WorkersMin)
+}
+
+func TestGetPodsAndStatus(t *testing.T) {
+	cli := newMockClient()
+	p := createTestProvider(t, cli)
+	deployTestEndpoint(t, p, 2)
+	epID, err := p.getEndpointID(context.Background(), "demo")
+	require.NoError(t, err)
+
+	cli.setWorkers(epID,
+		Worker{ID: "w1", DesiredStatus: RunPodStatusRunning},
+		Worker{ID: "w2", DesiredStatus: "INITIALIZING"},
+		Worker{ID: "w3", DesiredStatus: RunPodStatusExited},
+	)
+
+	pods, err := p.GetPods(context.Background(), "demo")
+	require.NoError(t, err)
+	require.Len(t, pods, 2)
+	assert.Equal(t, "w1", pods[0].Name)
+	assert.Equal(t, StatusRunning, pods[0].Status)
+	assert.Equal(t, StatusPending, pods[1].Status)
+
+	status, err := p.GetAppStatus(context.Background(), "demo")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, status.Status)
+	assert.Equal(t, int32(1), status.ReadyReplicas)
+	assert.Equal(t, int32(2), status.TotalReplicas)
+}
+
+func TestWatchers(t *testing.T) {
+	cli := newMockClient()
+	p := createTestProvider(t, cli)
+	deployTestEndpoint(t, p, 1)
+	epID, err := p.getEndpointID(context.Background(), "demo")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replicaEvents := make(chan interfaces.ReplicaEvent, 16)
+	statusEvents := make(chan string, 16)
+	deleteEvents := make(chan string, 16)
+	require.NoError(t, p.WatchReplicas(ctx, func(e interfaces.ReplicaEvent) { replicaEvents <- e }))
+	require.NoError(t, p.WatchPodStatusChange(ctx, func(workerID, endpoint string, info *interfaces.PodInfo) {
+		statusEvents <- workerID + "/" + endpoint + "/" + info.Status
+	}))
+	require.NoError(t, p.WatchPodDelete(ctx, func(workerID, endpoint string) { deleteEvents <- workerID + "/" + endpoint }))
+
+	cli.setWorkers(epID, Worker{ID: "w1", DesiredStatus: RunPodStatusRunning})
+	assert.Equal(t, "w1/demo/"+StatusRunning, receive(t, statusEvents))
+	e := receiveReplica(t, replicaEvents)
+	assert.Equal(t, "demo", e.Name)
+
+	cli.setWorkers(epID, Worker{ID: "w1", DesiredStatus: RunPodStatusExited})
+	assert.Equal(t, "w1/demo", receive(t, deleteEvents))
+}
+
+func receive(t *testing.T, ch chan string) string {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for callback")
+		return ""
+	}
+}
+
+func receiveReplica(t *testing.T, ch chan interfaces.ReplicaEvent) interfaces.ReplicaEvent {
+	t.Helper()
+	for {
+		select {
+		case e := <-ch:
+			if e.ReadyReplicas == 1 {
+				return e
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for replica event")
+			return interfaces.ReplicaEvent{}
+		}
+	}
+}
+
+func TestExtractRunPodConfig(t *testing.T) {
+	spec := &interfaces.SpecInfo{Name: "db", Platforms: map[string]interface{}{
+		"runpod": map[string]interface{}{
+			"gpuTypeIds":    []interface{}{"NVIDIA L40S"},
+			"dataCenterIds": []interface{}{"EU-RO-1"},
+		},
+	}}
+	cfg, err := extractRunPodConfig(spec)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"NVIDIA L40S"}, cfg.GpuTypeIDs)
+	assert.Equal(t, []string{"EU-RO-1"}, cfg.DataCenterIDs)
+
+	_, err = extractRunPodConfig(&interfaces.SpecInfo{Name: "empty", Platforms: map[string]interface{}{"runpod": map[string]interface{}{}}})
+	assert.Error(t, err)
+}
+
+func TestDiskGB(t *testing.T) {
+	for in, want := range map[string]int{"": DefaultContainerDiskGB, "80": 80, "100G": 100, "10Gi": 11} {
+		got, err := diskGB(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := diskGB("lots")
+	assert.Error(t, err)
+}
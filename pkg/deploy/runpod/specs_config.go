@@ -0,0 +1,165 @@
+package runpod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+const (
+	PlatformRunPod = "runpod"
+)
+
+// SpecRepositoryInterface defines the interface for spec repository
+type SpecRepositoryInterface interface {
+	GetSpec(ctx context.Context, name string) (*interfaces.SpecInfo, error)
+	ListSpecs(ctx context.Context) ([]*interfaces.SpecInfo, error)
+}
+
+// PlatformConfig is the runpod entry of a spec's platforms
+type PlatformConfig struct {
+	GpuTypeIDs    []string `yaml:"gpuTypeIds" json:"gpuTypeIds"`                           // RunPod GPU type IDs in preference order, e.g. "NVIDIA H100 80GB HBM3"
+	DataCenterIDs []string `yaml:"dataCenterIds,omitempty" json:"dataCenterIds,omitempty"` // Allowed data centers (empty = any)
+}
+
+// ResourceSpec defines resource specification
+type ResourceSpec struct {
+	Name        string                    `yaml:"name"`
+	DisplayName string                    `yaml:"displayName"`
+	Category    string                    `yaml:"category"` // cpu, gpu
+	Resources   SpecResources             `yaml:"resources"`
+	Platforms   map[string]PlatformConfig `yaml:"platforms"`
+}
+
+// SpecResources defines spec resources
+type SpecResources struct {
+	CPU              string `yaml:"cpu,omitempty"`
+	Memory           string `yaml:"memory"`
+	GPU              string `yaml:"gpu,omitempty"`
+	GpuType          string `yaml:"gpuType,omitempty"`
+	EphemeralStorage string `yaml:"ephemeralStorage"`
+}
+
+// SpecsFileConfig represents the structure of specs.yaml file
+type SpecsFileConfig struct {
+	Specs []*ResourceSpec `yaml:"specs"`
+}
+
+// SpecsConfig manages the specs usable on RunPod (database first, then specs.yaml)
+type SpecsConfig struct {
+	specs     map[string]*ResourceSpec
+	configDir string
+	specRepo  SpecRepositoryInterface
+}
+
+// NewSpecsConfig creates a new specs configuration manager
+func NewSpecsConfig(configDir string) (*SpecsConfig, error) {
+	if configDir == "" {
+		configDir = "config"
+	}
+
+	sc := &SpecsConfig{
+		specs:     make(map[string]*ResourceSpec),
+		configDir: configDir,
+	}
+	if err := sc.loadSpecs(); err != nil {
+		logger.Warnf("Failed to load RunPod specs from file: %v", err)
+	}
+	return sc, nil
+}
+
+// SetSpecRepository sets the spec repository for database access
+func (sc *SpecsConfig) SetSpecRepository(repo SpecRepositoryInterface) {
+	sc.specRepo = repo
+}
+
+// loadSpecs loads the specs with a runpod platform entry from specs.yaml
+func (sc *SpecsConfig) loadSpecs() error {
+	specsFile := filepath.Join(sc.configDir, "specs.yaml")
+
+	data, err := os.ReadFile(specsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read specs file: %w", err)
+	}
+
+	var config SpecsFileConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse specs file: %w", err)
+	}
+
+	sc.specs = make(map[string]*ResourceSpec)
+	for _, s := range config.Specs {
+		if _, ok := s.Platforms[PlatformRunPod]; ok {
+			sc.specs[s.Name] = s
+		}
+	}
+
+	if len(sc.specs) == 0 {
+		logger.Warnf("No RunPod-compatible specs found in %s", specsFile)
+	} else {
+		logger.Infof("Loaded %d RunPod-compatible specs from %s", len(sc.specs), specsFile)
+	}
+	return nil
+}
+
+// GetSpec returns a specific spec info by name (database first, then YAML fallback)
+func (sc *SpecsConfig) GetSpec(specName string) (*interfaces.SpecInfo, error) {
+	if sc.specRepo != nil {
+		dbSpec, err := sc.specRepo.GetSpec(context.Background(), specName)
+		if err == nil && dbSpec != nil {
+			return dbSpec, nil
+		}
+		logger.Warnf("Failed to get spec from database, falling back to YAML: %v", err)
+	}
+
+	resourceSpec, ok := sc.specs[specName]
+	if !ok {
+		return nil, fmt.Errorf("spec %s not found", specName)
+	}
+	return convertToSpecInfo(resourceSpec), nil
+}
+
+// ListSpecs returns all available spec infos (database first, then YAML fallback)
+func (sc *SpecsConfig) ListSpecs() []*interfaces.SpecInfo {
+	if sc.specRepo != nil {
+		dbSpecs, err := sc.specRepo.ListSpecs(context.Background())
+		if err == nil && len(dbSpecs) > 0 {
+			return dbSpecs
+		}
+		logger.Warnf("Failed to list specs from database, falling back to YAML: %v", err)
+	}
+
+	specs := make([]*interfaces.SpecInfo, 0, len(sc.specs))
+	for _, spec := range sc.specs {
+		specs = append(specs, convertToSpecInfo(spec))
+	}
+	return specs
+}
+
+// convertToSpecInfo converts ResourceSpec to interfaces.SpecInfo
+func convertToSpecInfo(spec *ResourceSpec) *interfaces.SpecInfo {
+	platforms := make(map[string]interface{}, len(spec.Platforms))
+	for name, cfg := range spec.Platforms {
+		platforms[name] = cfg
+	}
+
+	return &interfaces.SpecInfo{
+		Name:        spec.Name,
+		DisplayName: spec.DisplayName,
+		Category:    spec.Category,
+		Resources: interfaces.ResourceRequirements{
+			GPU:              spec.Resources.GPU,
+			GPUType:          spec.Resources.GpuType,
+			CPU:              spec.Resources.CPU,
+			Memory:           spec.Resources.Memory,
+			EphemeralStorage: spec.Resources.EphemeralStorage,
+		},
+		Platforms: platforms,
+	}
+}
@@ -0,0 +1,101 @@
+package runpod
+
+// RunPod REST API types based on https://rest.runpod.io/v1/docs
+
+// ========================================
+// Template Types
+// ========================================
+
+// TemplateRequest creates or updates a serverless template (image, env and disk of the workers)
+type TemplateRequest struct {
+	Name                    string            `json:"name,omitempty"`
+	ImageName               string            `json:"imageName,omitempty"`
+	Env                     map[string]string `json:"env,omitempty"`
+	ContainerDiskInGb       int               `json:"containerDiskInGb,omitempty"`
+	ContainerRegistryAuthID string            `json:"containerRegistryAuthId,omitempty"`
+	DockerStartCmd          []string          `json:"dockerStartCmd,omitempty"`
+	IsServerless            bool              `json:"isServerless,omitempty"` // Create only
+}
+
+// Template is a template returned by the API
+type Template struct {
+	ID                      string            `json:"id"`
+	Name                    string            `json:"name"`
+	ImageName               string            `json:"imageName"`
+	Env                     map[string]string `json:"env"`
+	ContainerDiskInGb       int               `json:"containerDiskInGb"`
+	ContainerRegistryAuthID string            `json:"containerRegistryAuthId"`
+	IsServerless            bool              `json:"isServerless"`
+}
+
+// ========================================
+// Endpoint Types
+// ========================================
+
+// EndpointRequest creates or updates a serverless endpoint. Pointers distinguish 0 from unset on update.
+type EndpointRequest struct {
+	Name               string   `json:"name,omitempty"`
+	TemplateID         string   `json:"templateId,omitempty"`
+	ComputeType        string   `json:"computeType,omitempty"` // GPU or CPU
+	GpuTypeIDs         []string `json:"gpuTypeIds,omitempty"`  // Preference order
+	GpuCount           int      `json:"gpuCount,omitempty"`    // GPUs per worker
+	DataCenterIDs      []string `json:"dataCenterIds,omitempty"`
+	WorkersMin         *int     `json:"workersMin,omitempty"`
+	WorkersMax         *int     `json:"workersMax,omitempty"`
+	IdleTimeout        int      `json:"idleTimeout,omitempty"`        // Seconds a worker above workersMin stays idle
+	ExecutionTimeoutMs int      `json:"executionTimeoutMs,omitempty"` // Per-request timeout (ms)
+	ScalerType         string   `json:"scalerType,omitempty"`         // QUEUE_DELAY or REQUEST_COUNT
+	ScalerValue        int      `json:"scalerValue,omitempty"`
+	Flashboot          *bool    `json:"flashboot,omitempty"`
+}
+
+// Endpoint is an endpoint returned by the API (workers and template included on request)
+type Endpoint struct {
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	TemplateID         string    `json:"templateId"`
+	Template           *Template `json:"template,omitempty"`
+	ComputeType        string    `json:"computeType"`
+	GpuTypeIDs         []string  `json:"gpuTypeIds"`
+	GpuCount           int       `json:"gpuCount"`
+	DataCenterIDs      []string  `json:"dataCenterIds"`
+	WorkersMin         int       `json:"workersMin"`
+	WorkersMax         int       `json:"workersMax"`
+	IdleTimeout        int       `json:"idleTimeout"`
+	ExecutionTimeoutMs int       `json:"executionTimeoutMs"`
+	ScalerType         string    `json:"scalerType"`
+	ScalerValue        int       `json:"scalerValue"`
+	Workers            []Worker  `json:"workers"`
+	CreatedAt          string    `json:"createdAt"`
+}
+
+// Worker is a worker (pod) of an endpoint
+type Worker struct {
+	ID               string `json:"id"`
+	DesiredStatus    string `json:"desiredStatus"` // RUNNING, EXITED or TERMINATED
+	LastStartedAt    string `json:"lastStartedAt"`
+	LastStatusChange string `json:"lastStatusChange"`
+	ImageName        string `json:"imageName"`
+}
+
+// ========================================
+// Container Registry Auth Types
+// ========================================
+
+// RegistryAuthRequest creates a container registry auth
+type RegistryAuthRequest struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RegistryAuth is a container registry auth returned by the API (the password is never returned)
+type RegistryAuth struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ErrorResponse is an error body of the API
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,103 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// consulServicePrefix prefixes the Consul service ID of an endpoint
+	consulServicePrefix = "waverless-"
+	// consulTag is set on every endpoint service, to list them all
+	consulTag = "waverless"
+)
+
+// ConsulRegistrar registers each endpoint as a service of the local Consul agent. The service
+// is named after the endpoint, so it resolves as <endpoint>.service.consul.
+type ConsulRegistrar struct {
+	baseURL string
+	token   string
+	tags    []string
+	address *Address
+	client  *http.Client
+}
+
+// NewConsulRegistrar creates a Consul registrar for the agent at consulAddr
+func NewConsulRegistrar(consulAddr, token string, tags []string, address *Address) *ConsulRegistrar {
+	return &ConsulRegistrar{
+		baseURL: strings.TrimSuffix(consulAddr, "/"),
+		token:   token,
+		tags:    append([]string{consulTag}, tags...),
+		address: address,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (r *ConsulRegistrar) Name() string { return "consul" }
+
+// consulService is the agent service registration payload
+type consulService struct {
+	ID      string            `json:"ID"`
+	Name    string            `json:"Name"`
+	Tags    []string          `json:"Tags"`
+	Address string            `json:"Address"`
+	Port    int               `json:"Port"`
+	Meta    map[string]string `json:"Meta"`
+}
+
+// Register registers (or re-registers) the endpoint service
+func (r *ConsulRegistrar) Register(ctx context.Context, endpoint string) error {
+	body, err := json.Marshal(&consulService{
+		ID:      consulServicePrefix + endpoint,
+		Name:    endpoint,
+		Tags:    r.tags,
+		Address: r.address.Host,
+		Port:    r.address.Port,
+		Meta: map[string]string{
+			"endpoint": endpoint,
+			"url":      r.address.EndpointURL(endpoint),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode consul service: %w", err)
+	}
+	return r.put(ctx, "/v1/agent/service/register", body)
+}
+
+// Deregister removes the endpoint service; Consul treats unknown services as deregistered
+func (r *ConsulRegistrar) Deregister(ctx context.Context, endpoint string) error {
+	err := r.put(ctx, "/v1/agent/service/deregister/"+consulServicePrefix+endpoint, nil)
+	if err != nil && strings.Contains(err.Error(), "status 404") {
+		return nil
+	}
+	return err
+}
+
+func (r *ConsulRegistrar) put(ctx context.Context, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create consul request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("X-Consul-Token", r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("consul request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("consul %s: status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
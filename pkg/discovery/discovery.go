@@ -0,0 +1,65 @@
+// Package discovery registers the data plane address of endpoints in a service discovery
+// system (Consul, or DNS through external-dns), so downstream services find endpoints by name.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Registrar registers endpoints in a service discovery system. Both calls are idempotent.
+type Registrar interface {
+	// Name returns the discovery mode, e.g. consul
+	Name() string
+	// Register creates or updates the registration of an endpoint
+	Register(ctx context.Context, endpoint string) error
+	// Deregister removes the registration of an endpoint; a missing registration is no error
+	Deregister(ctx context.Context, endpoint string) error
+}
+
+// Address is the data plane address endpoints are registered with. Tasks of an endpoint are
+// submitted to <URL>/v1/<endpoint>.
+type Address struct {
+	URL  string // Base URL without trailing slash
+	Host string
+	Port int
+}
+
+// ParseAddress parses the data plane base URL; the port defaults to 80 (http) or 443 (https)
+func ParseAddress(raw string) (*Address, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid data plane address %q: must be an http(s) URL", raw)
+	}
+
+	port := 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if p := u.Port(); p != "" {
+		port, err = strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data plane address %q: bad port", raw)
+		}
+	}
+
+	return &Address{
+		URL:  strings.TrimSuffix(u.Scheme+"://"+u.Host+u.Path, "/"),
+		Host: u.Hostname(),
+		Port: port,
+	}, nil
+}
+
+// EndpointURL returns the URL tasks of an endpoint are submitted to
+func (a *Address) EndpointURL(endpoint string) string {
+	return a.URL + "/v1/" + endpoint
+}
+
+// isIP reports whether the address host is an IP rather than a DNS name
+func (a *Address) isIP() bool {
+	return net.ParseIP(a.Host) != nil
+}
@@ -0,0 +1,123 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseAddress(t *testing.T) {
+	addr, err := ParseAddress("https://api.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, &Address{URL: "https://api.example.com", Host: "api.example.com", Port: 443}, addr)
+	assert.Equal(t, "https://api.example.com/v1/flux", addr.EndpointURL("flux"))
+
+	addr, err = ParseAddress("http://10.0.0.5:8080/waverless")
+	require.NoError(t, err)
+	assert.Equal(t, &Address{URL: "http://10.0.0.5:8080/waverless", Host: "10.0.0.5", Port: 8080}, addr)
+	assert.True(t, addr.isIP())
+
+	for _, raw := range []string{"", "api.example.com", "ftp://api.example.com", "http://api.example.com:port"} {
+		_, err := ParseAddress(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestConsulRegistrar(t *testing.T) {
+	var registered consulService
+	var paths []string
+	var token string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		token = r.Header.Get("X-Consul-Token")
+		if r.URL.Path == "/v1/agent/service/register" {
+			_ = json.NewDecoder(r.Body).Decode(&registered)
+		}
+		if r.URL.Path == "/v1/agent/service/deregister/waverless-gone" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	addr, err := ParseAddress("https://api.example.com")
+	require.NoError(t, err)
+	r := NewConsulRegistrar(srv.URL+"/", "secret", []string{"prod"}, addr)
+
+	require.NoError(t, r.Register(context.Background(), "flux"))
+	assert.Equal(t, "waverless-flux", registered.ID)
+	assert.Equal(t, "flux", registered.Name)
+	assert.Equal(t, []string{"waverless", "prod"}, registered.Tags)
+	assert.Equal(t, "api.example.com", registered.Address)
+	assert.Equal(t, 443, registered.Port)
+	assert.Equal(t, "https://api.example.com/v1/flux", registered.Meta["url"])
+	assert.Equal(t, "secret", token)
+
+	require.NoError(t, r.Deregister(context.Background(), "flux"))
+	require.NoError(t, r.Deregister(context.Background(), "gone"), "unknown services are deregistered")
+	assert.Equal(t, []string{
+		"PUT /v1/agent/service/register",
+		"PUT /v1/agent/service/deregister/waverless-flux",
+		"PUT /v1/agent/service/deregister/waverless-gone",
+	}, paths)
+}
+
+func TestConsulRegistrarError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	addr, _ := ParseAddress("https://api.example.com")
+	err := NewConsulRegistrar(srv.URL, "", nil, addr).Register(context.Background(), "flux")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Permission denied")
+}
+
+func TestExternalDNSRegistrar(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	addr, _ := ParseAddress("https://api.example.com")
+
+	r, err := NewExternalDNSRegistrar(client, "wavespeed", "endpoints.example.com.", 60, addr)
+	require.NoError(t, err)
+	assert.Equal(t, "flux.endpoints.example.com", r.Hostname("flux"))
+
+	require.NoError(t, r.Register(ctx, "flux"))
+	svc, err := client.CoreV1().Services("wavespeed").Get(ctx, "flux-dns", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.ServiceTypeExternalName, svc.Spec.Type)
+	assert.Equal(t, "api.example.com", svc.Spec.ExternalName)
+	assert.Equal(t, "flux.endpoints.example.com", svc.Annotations[externalDNSHostnameAnnotation])
+	assert.Equal(t, "60", svc.Annotations[externalDNSTTLAnnotation])
+	assert.Equal(t, "waverless", svc.Labels["managed-by"])
+
+	// Registering again updates the existing Service
+	r.address = &Address{URL: "https://api2.example.com", Host: "api2.example.com", Port: 443}
+	require.NoError(t, r.Register(ctx, "flux"))
+	svc, err = client.CoreV1().Services("wavespeed").Get(ctx, "flux-dns", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "api2.example.com", svc.Spec.ExternalName)
+
+	require.NoError(t, r.Deregister(ctx, "flux"))
+	require.NoError(t, r.Deregister(ctx, "flux"), "deregistering twice is no error")
+	_, err = client.CoreV1().Services("wavespeed").Get(ctx, "flux-dns", metav1.GetOptions{})
+	assert.Error(t, err)
+}
+
+func TestNewExternalDNSRegistrarValidation(t *testing.T) {
+	host, _ := ParseAddress("https://api.example.com")
+	_, err := NewExternalDNSRegistrar(fake.NewSimpleClientset(), "ns", "", 0, host)
+	assert.Error(t, err, "domain is required")
+
+	ip, _ := ParseAddress("http://10.0.0.5:8080")
+	_, err = NewExternalDNSRegistrar(fake.NewSimpleClientset(), "ns", "example.com", 0, ip)
+	assert.Error(t, err, "ExternalName services need a DNS name")
+}
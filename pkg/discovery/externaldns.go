@@ -0,0 +1,117 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// externalDNSHostnameAnnotation is the hostname external-dns creates a record for
+	externalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+	// externalDNSTTLAnnotation is the record TTL in seconds
+	externalDNSTTLAnnotation = "external-dns.alpha.kubernetes.io/ttl"
+
+	// externalDNSServiceSuffix keeps the Service apart from Services named after the endpoint
+	externalDNSServiceSuffix = "-dns"
+)
+
+// ExternalDNSRegistrar creates a Service <endpoint>-dns of type ExternalName pointing to the
+// data plane host and annotated for external-dns, which publishes <endpoint>.<domain> as a
+// CNAME of the data plane host.
+type ExternalDNSRegistrar struct {
+	client    kubernetes.Interface
+	namespace string
+	domain    string
+	ttl       int
+	address   *Address
+}
+
+// NewExternalDNSRegistrar creates an external-dns registrar. The data plane host must be a DNS
+// name, ExternalName Services cannot point to IPs.
+func NewExternalDNSRegistrar(client kubernetes.Interface, namespace, domain string, ttl int, address *Address) (*ExternalDNSRegistrar, error) {
+	domain = strings.Trim(strings.TrimSpace(domain), ".")
+	if domain == "" {
+		return nil, fmt.Errorf("invalid discovery config: externalDNS.domain is required")
+	}
+	if address.isIP() {
+		return nil, fmt.Errorf("invalid discovery config: external-dns needs a DNS name as data plane address, got %s", address.Host)
+	}
+	return &ExternalDNSRegistrar{
+		client:    client,
+		namespace: namespace,
+		domain:    domain,
+		ttl:       ttl,
+		address:   address,
+	}, nil
+}
+
+func (r *ExternalDNSRegistrar) Name() string { return "external-dns" }
+
+// Hostname returns the DNS name of an endpoint
+func (r *ExternalDNSRegistrar) Hostname(endpoint string) string {
+	return endpoint + "." + r.domain
+}
+
+// Register creates or updates the endpoint's ExternalName Service
+func (r *ExternalDNSRegistrar) Register(ctx context.Context, endpoint string) error {
+	desired := r.service(endpoint)
+	services := r.client.CoreV1().Services(r.namespace)
+
+	existing, err := services.Get(ctx, desired.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := services.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create discovery service %s: %w", desired.Name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get discovery service %s: %w", desired.Name, err)
+	}
+
+	existing.Labels = desired.Labels
+	existing.Annotations = desired.Annotations
+	existing.Spec.Type = desired.Spec.Type
+	existing.Spec.ExternalName = desired.Spec.ExternalName
+	if _, err := services.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update discovery service %s: %w", desired.Name, err)
+	}
+	return nil
+}
+
+// Deregister deletes the endpoint's Service; external-dns then removes the record
+func (r *ExternalDNSRegistrar) Deregister(ctx context.Context, endpoint string) error {
+	name := endpoint + externalDNSServiceSuffix
+	err := r.client.CoreV1().Services(r.namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete discovery service %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *ExternalDNSRegistrar) service(endpoint string) *corev1.Service {
+	annotations := map[string]string{
+		externalDNSHostnameAnnotation: r.Hostname(endpoint),
+	}
+	if r.ttl > 0 {
+		annotations[externalDNSTTLAnnotation] = strconv.Itoa(r.ttl)
+	}
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        endpoint + externalDNSServiceSuffix,
+			Namespace:   r.namespace,
+			Labels:      map[string]string{"app": endpoint, "managed-by": "waverless"},
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:         corev1.ServiceTypeExternalName,
+			ExternalName: r.address.Host,
+		},
+	}
+}
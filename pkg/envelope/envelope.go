@@ -0,0 +1,259 @@
+// Package envelope implements envelope encryption of task payloads at rest. Every project has
+// versioned data keys; a payload is sealed with AES-256-GCM under the project's newest data key,
+// and data keys are only stored wrapped by a KMS (Vault transit or a local master key).
+// Rotating a project adds a data key version; payloads sealed with older versions stay readable.
+package envelope
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PayloadKey marks a JSON payload that holds a sealed document instead of plaintext
+const PayloadKey = "_encrypted"
+
+// activeKeyTTL bounds how long a replica keeps sealing with a data key after another replica
+// rotated the project
+const activeKeyTTL = 5 * time.Minute
+
+// DataKey is a stored, wrapped data key version of a project
+type DataKey struct {
+	Project   string    `json:"project"`
+	Version   int       `json:"version"`
+	Wrapped   string    `json:"-"`
+	KMS       string    `json:"kms"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// KeyStore persists wrapped data keys
+type KeyStore interface {
+	// LatestKey returns the newest data key of a project, nil if it has none
+	LatestKey(ctx context.Context, project string) (*DataKey, error)
+	// GetKey returns a data key version, nil if it does not exist
+	GetKey(ctx context.Context, project string, version int) (*DataKey, error)
+	// CreateKey stores key as the project's next version and sets key.Version
+	CreateKey(ctx context.Context, key *DataKey) error
+}
+
+// Sealed is an encrypted document
+type Sealed struct {
+	V       int    `json:"v"` // Format version
+	Project string `json:"p"`
+	Key     int    `json:"k"` // Data key version
+	Nonce   string `json:"n"`
+	Data    string `json:"d"`
+}
+
+type activeKey struct {
+	version  int
+	loadedAt time.Time
+}
+
+// Keyring seals and opens documents with per-project data keys, caching unwrapped keys
+type Keyring struct {
+	kms   KMS
+	store KeyStore
+
+	mu     sync.Mutex
+	keys   map[string][]byte // project/version -> data key
+	active map[string]activeKey
+}
+
+// NewKeyring creates a keyring
+func NewKeyring(kms KMS, store KeyStore) *Keyring {
+	return &Keyring{kms: kms, store: store, keys: make(map[string][]byte), active: make(map[string]activeKey)}
+}
+
+// KMSName returns the type of the KMS wrapping the data keys
+func (k *Keyring) KMSName() string {
+	return k.kms.Name()
+}
+
+// Seal encrypts plaintext under the project's newest data key, creating the first one on demand.
+// aad binds the ciphertext to its location (e.g. the task and field) so it cannot be moved.
+func (k *Keyring) Seal(ctx context.Context, project string, plaintext, aad []byte) (*Sealed, error) {
+	version, key, err := k.activeKey(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	nonce, data, err := seal(key, plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealed{
+		V:       1,
+		Project: project,
+		Key:     version,
+		Nonce:   base64.StdEncoding.EncodeToString(nonce),
+		Data:    base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+// Open decrypts a sealed document
+func (k *Keyring) Open(ctx context.Context, s *Sealed, aad []byte) ([]byte, error) {
+	if s.V != 1 {
+		return nil, fmt.Errorf("unsupported sealed format version %d", s.V)
+	}
+	key, err := k.dataKey(ctx, s.Project, s.Key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(s.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(s.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	return open(key, nonce, data, aad)
+}
+
+// Rotate creates a new data key version for a project; new payloads are sealed with it
+func (k *Keyring) Rotate(ctx context.Context, project string) (*DataKey, error) {
+	key, plain, err := k.createKey(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	k.mu.Lock()
+	k.keys[keyID(project, key.Version)] = plain
+	k.active[project] = activeKey{version: key.Version, loadedAt: time.Now()}
+	k.mu.Unlock()
+	return key, nil
+}
+
+// activeKey returns the newest data key of a project
+func (k *Keyring) activeKey(ctx context.Context, project string) (int, []byte, error) {
+	k.mu.Lock()
+	active, ok := k.active[project]
+	k.mu.Unlock()
+	if ok && time.Since(active.loadedAt) < activeKeyTTL {
+		key, err := k.dataKey(ctx, project, active.version)
+		return active.version, key, err
+	}
+
+	latest, err := k.store.LatestKey(ctx, project)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load data key of project %s: %w", project, err)
+	}
+	if latest == nil {
+		created, err := k.Rotate(ctx, project)
+		if err != nil {
+			// Another replica may have created the first key concurrently
+			if latest, _ = k.store.LatestKey(ctx, project); latest == nil {
+				return 0, nil, err
+			}
+		} else {
+			latest = created
+		}
+	}
+
+	k.mu.Lock()
+	k.active[project] = activeKey{version: latest.Version, loadedAt: time.Now()}
+	k.mu.Unlock()
+	key, err := k.dataKey(ctx, project, latest.Version)
+	return latest.Version, key, err
+}
+
+// dataKey returns an unwrapped data key version, unwrapping it through the KMS once
+func (k *Keyring) dataKey(ctx context.Context, project string, version int) ([]byte, error) {
+	id := keyID(project, version)
+	k.mu.Lock()
+	key, ok := k.keys[id]
+	k.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	stored, err := k.store.GetKey(ctx, project, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data key %s: %w", id, err)
+	}
+	if stored == nil {
+		return nil, fmt.Errorf("data key %s not found", id)
+	}
+	if key, err = k.kms.Unwrap(ctx, project, stored.Wrapped); err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %s: %w", id, err)
+	}
+	k.mu.Lock()
+	k.keys[id] = key
+	k.mu.Unlock()
+	return key, nil
+}
+
+func (k *Keyring) createKey(ctx context.Context, project string) (*DataKey, []byte, error) {
+	plain := make([]byte, 32)
+	if _, err := rand.Read(plain); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	wrapped, err := k.kms.Wrap(ctx, project, plain)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to wrap data key of project %s: %w", project, err)
+	}
+	key := &DataKey{Project: project, Wrapped: wrapped, KMS: k.kms.Name()}
+	if err := k.store.CreateKey(ctx, key); err != nil {
+		return nil, nil, err
+	}
+	return key, plain, nil
+}
+
+func keyID(project string, version int) string {
+	return fmt.Sprintf("%s/%d", project, version)
+}
+
+// SealMap encrypts a JSON document into {"_encrypted": Sealed}
+func (k *Keyring) SealMap(ctx context.Context, project string, doc map[string]interface{}, aad []byte) (map[string]interface{}, error) {
+	plaintext, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	s, err := k.Seal(ctx, project, plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{PayloadKey: s}, nil
+}
+
+// OpenMap decrypts a document sealed by SealMap; plaintext documents are returned as they are
+func (k *Keyring) OpenMap(ctx context.Context, doc map[string]interface{}, aad []byte) (map[string]interface{}, error) {
+	s, ok, err := SealedPayload(doc)
+	if err != nil || !ok {
+		return doc, err
+	}
+	plaintext, err := k.Open(ctx, s, aad)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(plaintext, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return out, nil
+}
+
+// SealedPayload extracts the sealed document of a payload, ok=false for plaintext payloads
+func SealedPayload(doc map[string]interface{}) (*Sealed, bool, error) {
+	raw, ok := doc[PayloadKey]
+	if !ok || len(doc) != 1 {
+		return nil, false, nil
+	}
+	var s Sealed
+	switch v := raw.(type) {
+	case *Sealed:
+		return v, true, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid sealed payload: %w", err)
+		}
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, false, fmt.Errorf("invalid sealed payload: %w", err)
+		}
+	}
+	return &s, true, nil
+}
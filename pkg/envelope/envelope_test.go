@@ -0,0 +1,180 @@
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys map[string][]*DataKey
+}
+
+func newMemoryKeyStore() *memoryKeyStore {
+	return &memoryKeyStore{keys: make(map[string][]*DataKey)}
+}
+
+func (s *memoryKeyStore) LatestKey(ctx context.Context, project string) (*DataKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := s.keys[project]
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return keys[len(keys)-1], nil
+}
+
+func (s *memoryKeyStore) GetKey(ctx context.Context, project string, version int) (*DataKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.keys[project] {
+		if k.Version == version {
+			return k, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryKeyStore) CreateKey(ctx context.Context, key *DataKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key.Version = len(s.keys[key.Project]) + 1
+	s.keys[key.Project] = append(s.keys[key.Project], key)
+	return nil
+}
+
+func testKMS(t *testing.T) *LocalKMS {
+	kms, err := NewLocalKMS(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	require.NoError(t, err)
+	return kms
+}
+
+func TestKeyring_SealOpenMap(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryKeyStore()
+	k := NewKeyring(testKMS(t), store)
+
+	doc := map[string]interface{}{"prompt": "patient record 42", "steps": float64(20)}
+	sealed, err := k.SealMap(ctx, "acme", doc, []byte("task-1/input"))
+	require.NoError(t, err)
+
+	// Stored form carries no plaintext
+	data, err := json.Marshal(sealed)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "patient")
+
+	// Round trip through JSON as MySQL would
+	var stored map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &stored))
+	opened, err := k.OpenMap(ctx, stored, []byte("task-1/input"))
+	require.NoError(t, err)
+	assert.Equal(t, doc, opened)
+
+	// Ciphertext is bound to its location
+	_, err = k.OpenMap(ctx, stored, []byte("task-2/input"))
+	assert.Error(t, err)
+
+	// First use created exactly one data key
+	assert.Len(t, store.keys["acme"], 1)
+}
+
+func TestKeyring_PlaintextPassthrough(t *testing.T) {
+	k := NewKeyring(testKMS(t), newMemoryKeyStore())
+	doc := map[string]interface{}{"prompt": "hello"}
+	opened, err := k.OpenMap(context.Background(), doc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, doc, opened)
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryKeyStore()
+	k := NewKeyring(testKMS(t), store)
+
+	old, err := k.Seal(ctx, "acme", []byte("v1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, old.Key)
+
+	key, err := k.Rotate(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, 2, key.Version)
+
+	cur, err := k.Seal(ctx, "acme", []byte("v2"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cur.Key)
+
+	// A fresh keyring (another replica) opens both versions from the store
+	other := NewKeyring(testKMS(t), store)
+	plain, err := other.Open(ctx, old, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(plain))
+	plain, err = other.Open(ctx, cur, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(plain))
+}
+
+func TestLocalKMS_ProjectIsolation(t *testing.T) {
+	ctx := context.Background()
+	kms := testKMS(t)
+	wrapped, err := kms.Wrap(ctx, "acme", []byte("data-key"))
+	require.NoError(t, err)
+
+	plain, err := kms.Unwrap(ctx, "acme", wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "data-key", string(plain))
+
+	_, err = kms.Unwrap(ctx, "globex", wrapped)
+	assert.Error(t, err)
+}
+
+func TestNewLocalKMS_InvalidKey(t *testing.T) {
+	_, err := NewLocalKMS("not base64!")
+	assert.Error(t, err)
+	_, err = NewLocalKMS(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+}
+
+func TestVaultTransit(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if strings.Contains(r.URL.Path, "/encrypt/") {
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"ciphertext": "vault:v1:" + body["plaintext"]}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v1:")}})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	kms := NewVaultTransit(srv.URL, "s.token", "", "waverless-")
+	wrapped, err := kms.Wrap(ctx, "acme", []byte("data-key"))
+	require.NoError(t, err)
+	plain, err := kms.Unwrap(ctx, "acme", wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "data-key", string(plain))
+	assert.Equal(t, []string{"/v1/transit/encrypt/waverless-acme", "/v1/transit/decrypt/waverless-acme"}, paths)
+}
+
+func TestVaultTransit_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := NewVaultTransit(srv.URL, "bad", "transit", "").Wrap(context.Background(), "acme", []byte("k"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
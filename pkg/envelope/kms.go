@@ -0,0 +1,199 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// KMS wraps and unwraps data keys with a per-project key-encryption key
+type KMS interface {
+	// Name returns the KMS type recorded with every data key
+	Name() string
+	// Wrap encrypts a data key of a project
+	Wrap(ctx context.Context, project string, dataKey []byte) (string, error)
+	// Unwrap decrypts a data key wrapped by Wrap
+	Unwrap(ctx context.Context, project, wrapped string) ([]byte, error)
+}
+
+// LocalKMS derives a key-encryption key per project from a master key held by the control
+// plane. Use it where no external KMS is available; the master key must be kept outside MySQL.
+type LocalKMS struct {
+	master []byte
+}
+
+// NewLocalKMS creates a local KMS from a base64-encoded 32-byte master key
+func NewLocalKMS(masterKey string) (*LocalKMS, error) {
+	master, err := base64.StdEncoding.DecodeString(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	if len(master) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(master))
+	}
+	return &LocalKMS{master: master}, nil
+}
+
+// Name returns the KMS type
+func (k *LocalKMS) Name() string {
+	return "local"
+}
+
+// Wrap encrypts a data key with the project's derived key
+func (k *LocalKMS) Wrap(ctx context.Context, project string, dataKey []byte) (string, error) {
+	nonce, sealed, err := seal(k.projectKey(project), dataKey, []byte(project))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(append(nonce, sealed...)), nil
+}
+
+// Unwrap decrypts a data key wrapped by Wrap
+func (k *LocalKMS) Unwrap(ctx context.Context, project, wrapped string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped key: %w", err)
+	}
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("invalid wrapped key: too short")
+	}
+	return open(k.projectKey(project), data[:nonceSize], data[nonceSize:], []byte(project))
+}
+
+// projectKey derives the key-encryption key of a project
+func (k *LocalKMS) projectKey(project string) []byte {
+	mac := hmac.New(sha256.New, k.master)
+	mac.Write([]byte("waverless-kek:" + project))
+	return mac.Sum(nil)
+}
+
+// VaultTransit wraps data keys with HashiCorp Vault's transit secrets engine, one transit key
+// per project (<keyPrefix><project>). Key material never leaves Vault.
+type VaultTransit struct {
+	address   string
+	token     string
+	mount     string
+	keyPrefix string
+	client    *http.Client
+}
+
+// NewVaultTransit creates a Vault transit KMS
+func NewVaultTransit(address, token, mount, keyPrefix string) *VaultTransit {
+	if mount == "" {
+		mount = "transit"
+	}
+	return &VaultTransit{
+		address:   strings.TrimRight(address, "/"),
+		token:     token,
+		mount:     strings.Trim(mount, "/"),
+		keyPrefix: keyPrefix,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the KMS type
+func (v *VaultTransit) Name() string {
+	return "vault-transit"
+}
+
+// Wrap encrypts a data key with the project's transit key
+func (v *VaultTransit) Wrap(ctx context.Context, project string, dataKey []byte) (string, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	if err := v.call(ctx, "encrypt", project, body, &resp); err != nil {
+		return "", err
+	}
+	return resp.Data.Ciphertext, nil
+}
+
+// Unwrap decrypts a data key with the project's transit key
+func (v *VaultTransit) Unwrap(ctx context.Context, project, wrapped string) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := v.call(ctx, "decrypt", project, map[string]string{"ciphertext": wrapped}, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+func (v *VaultTransit) call(ctx context.Context, op, project string, body interface{}, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/v1/%s/%s/%s%s", v.address, v.mount, op, v.keyPrefix, project)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault transit %s failed: %w", op, err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault transit %s failed: status %d: %s", op, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("vault transit %s: invalid response: %w", op, err)
+	}
+	return nil
+}
+
+const nonceSize = 12
+
+// seal encrypts plaintext with AES-256-GCM under key
+func seal(key, plaintext, aad []byte) (nonce, sealed []byte, err error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return nonce, gcm.Seal(nil, nonce, plaintext, aad), nil
+}
+
+// open decrypts data sealed by seal
+func open(key, nonce, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, sealed, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,180 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	bigQueryAPIBase     = "https://bigquery.googleapis.com/bigquery/v2"
+	gceMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	bigQueryInsertChunk = 500
+)
+
+// BigQuerySink streams rows into BigQuery tables via tabledata.insertAll.
+// Each row carries an insertId derived from its cursor so retried batches are de-duplicated by BigQuery.
+// Target tables must exist with columns matching the dataset header.
+type BigQuerySink struct {
+	projectID   string
+	dataset     string
+	tablePrefix string
+	staticToken string
+	client      *http.Client
+
+	mu          sync.Mutex
+	cachedToken string
+	tokenExpiry time.Time
+}
+
+// NewBigQuerySink creates a new BigQuery sink. If accessToken is empty the GCE metadata server is used.
+func NewBigQuerySink(projectID, dataset, tablePrefix, accessToken string) (*BigQuerySink, error) {
+	if projectID == "" || dataset == "" {
+		return nil, fmt.Errorf("bigquery export project_id and dataset are required")
+	}
+	return &BigQuerySink{
+		projectID:   projectID,
+		dataset:     dataset,
+		tablePrefix: tablePrefix,
+		staticToken: accessToken,
+		client:      &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+func (s *BigQuerySink) Name() string { return "bigquery" }
+
+type bigQueryInsertRow struct {
+	InsertID string            `json:"insertId"`
+	JSON     map[string]string `json:"json"`
+}
+
+type bigQueryInsertResponse struct {
+	InsertErrors []struct {
+		Index  int `json:"index"`
+		Errors []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"insertErrors"`
+}
+
+func (s *BigQuerySink) Write(ctx context.Context, batch *Batch) (string, error) {
+	table := s.tablePrefix + batch.Dataset
+	url := fmt.Sprintf("%s/projects/%s/datasets/%s/tables/%s/insertAll", bigQueryAPIBase, s.projectID, s.dataset, table)
+
+	for start := 0; start < len(batch.Rows); start += bigQueryInsertChunk {
+		end := start + bigQueryInsertChunk
+		if end > len(batch.Rows) {
+			end = len(batch.Rows)
+		}
+
+		rows := make([]bigQueryInsertRow, 0, end-start)
+		for _, row := range batch.Rows[start:end] {
+			values := make(map[string]string, len(batch.Header))
+			for i, col := range batch.Header {
+				if i < len(row) && row[i] != "" {
+					values[col] = row[i]
+				}
+			}
+			// First column is always the row's unique key
+			rows = append(rows, bigQueryInsertRow{InsertID: batch.Dataset + "-" + row[0], JSON: values})
+		}
+
+		if err := s.insert(ctx, url, rows); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("bigquery://%s.%s.%s", s.projectID, s.dataset, table), nil
+}
+
+func (s *BigQuerySink) insert(ctx context.Context, url string, rows []bigQueryInsertRow) error {
+	body, err := json.Marshal(map[string]interface{}{"rows": rows})
+	if err != nil {
+		return err
+	}
+
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bigquery insert failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("bigquery insert failed with status %d: %s", resp.StatusCode, truncate(string(respBody), 512))
+	}
+
+	var result bigQueryInsertResponse
+	if err := json.Unmarshal(respBody, &result); err == nil && len(result.InsertErrors) > 0 {
+		first := result.InsertErrors[0]
+		msg := ""
+		if len(first.Errors) > 0 {
+			msg = first.Errors[0].Reason + ": " + first.Errors[0].Message
+		}
+		return fmt.Errorf("bigquery rejected %d rows (first at index %d: %s)", len(result.InsertErrors), first.Index, msg)
+	}
+	return nil
+}
+
+// token returns the static token or a cached token from the GCE metadata server
+func (s *BigQuerySink) token(ctx context.Context) (string, error) {
+	if s.staticToken != "" {
+		return s.staticToken, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cachedToken != "" && time.Now().Before(s.tokenExpiry) {
+		return s.cachedToken, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gceMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get token from metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned status %d", resp.StatusCode)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("failed to decode metadata token: %w", err)
+	}
+	s.cachedToken = tok.AccessToken
+	// Refresh a minute early
+	s.tokenExpiry = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - time.Minute)
+	return s.cachedToken, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
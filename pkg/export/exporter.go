@@ -0,0 +1,245 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// Exported datasets
+const (
+	DatasetGPUUsageRecords = "gpu_usage_records"
+	DatasetTasks           = "tasks"
+)
+
+// settleDelay keeps the exporter away from rows that may still be committed out of order
+const settleDelay = 2 * time.Minute
+
+// maxBatchesPerRun bounds a single run so a large backlog is drained over several runs
+const maxBatchesPerRun = 20
+
+var gpuUsageRecordHeader = []string{
+	"id", "task_id", "endpoint", "worker_id", "spec_name", "gpu_count", "gpu_type", "gpu_memory_gb",
+	"started_at", "completed_at", "duration_seconds", "gpu_hours", "status", "created_at",
+}
+
+var taskHeader = []string{
+	"id", "task_id", "endpoint", "status", "worker_id", "error",
+	"created_at", "started_at", "completed_at", "queue_wait_ms", "execution_ms",
+}
+
+// Exporter ships raw rows to a sink in checkpointed batches.
+// The checkpoint is only advanced after the sink accepted a batch, so delivery is at-least-once;
+// batches are keyed by cursor range so a retried batch overwrites (S3/local) or is de-duplicated (BigQuery).
+type Exporter struct {
+	repo      *mysql.ExportRepository
+	sink      Sink
+	datasets  []string
+	batchSize int
+}
+
+// NewExporter creates a new exporter; datasets defaults to all supported datasets
+func NewExporter(repo *mysql.ExportRepository, sink Sink, datasets []string, batchSize int) *Exporter {
+	if len(datasets) == 0 {
+		datasets = []string{DatasetGPUUsageRecords, DatasetTasks}
+	}
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+	return &Exporter{repo: repo, sink: sink, datasets: datasets, batchSize: batchSize}
+}
+
+// ExportPending exports new rows of every dataset since its checkpoint
+func (e *Exporter) ExportPending(ctx context.Context) error {
+	var firstErr error
+	for _, dataset := range e.datasets {
+		rows, err := e.exportDataset(ctx, dataset)
+		if err != nil {
+			logger.ErrorCtx(ctx, "export of %s to %s failed: %v", dataset, e.sink.Name(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if rows > 0 {
+			logger.InfoCtx(ctx, "exported %d %s rows to %s", rows, dataset, e.sink.Name())
+		}
+	}
+	return firstErr
+}
+
+// Checkpoints returns export progress of all datasets
+func (e *Exporter) Checkpoints(ctx context.Context) ([]*model.ExportCheckpoint, error) {
+	return e.repo.ListCheckpoints(ctx)
+}
+
+func (e *Exporter) exportDataset(ctx context.Context, dataset string) (int, error) {
+	checkpoint, err := e.repo.GetCheckpoint(ctx, dataset)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint == nil {
+		checkpoint = &model.ExportCheckpoint{Dataset: dataset}
+	}
+
+	before := time.Now().Add(-settleDelay)
+	exported := 0
+	for i := 0; i < maxBatchesPerRun; i++ {
+		if err := ctx.Err(); err != nil {
+			return exported, err
+		}
+
+		batch, err := e.nextBatch(ctx, dataset, checkpoint, before)
+		if err != nil {
+			return exported, err
+		}
+		if batch == nil {
+			break
+		}
+
+		object, err := e.sink.Write(ctx, batch.Batch)
+		if err != nil {
+			checkpoint.LastError = truncate(err.Error(), 1024)
+			if saveErr := e.repo.SaveCheckpoint(ctx, checkpoint); saveErr != nil {
+				logger.WarnCtx(ctx, "failed to save export checkpoint for %s: %v", dataset, saveErr)
+			}
+			return exported, err
+		}
+
+		now := time.Now()
+		checkpoint.LastID = batch.lastID
+		checkpoint.LastTime = batch.lastTime
+		checkpoint.RowsExported += int64(len(batch.Rows))
+		checkpoint.LastExportedAt = &now
+		checkpoint.LastObject = truncate(object, 1024)
+		checkpoint.LastError = ""
+		if err := e.repo.SaveCheckpoint(ctx, checkpoint); err != nil {
+			return exported, fmt.Errorf("failed to save export checkpoint: %w", err)
+		}
+		exported += len(batch.Rows)
+
+		if len(batch.Rows) < e.batchSize {
+			break
+		}
+	}
+	return exported, nil
+}
+
+// cursorBatch is a batch plus the cursor to store once it is written
+type cursorBatch struct {
+	*Batch
+	lastID   int64
+	lastTime *time.Time
+}
+
+func (e *Exporter) nextBatch(ctx context.Context, dataset string, checkpoint *model.ExportCheckpoint, before time.Time) (*cursorBatch, error) {
+	switch dataset {
+	case DatasetGPUUsageRecords:
+		records, err := e.repo.ListGPUUsageRecordsAfter(ctx, checkpoint.LastID, before, e.batchSize)
+		if err != nil || len(records) == 0 {
+			return nil, err
+		}
+		rows := make([][]string, 0, len(records))
+		for _, r := range records {
+			rows = append(rows, gpuUsageRecordRow(r))
+		}
+		first, last := records[0], records[len(records)-1]
+		return &cursorBatch{
+			Batch: &Batch{
+				Dataset:     dataset,
+				Header:      gpuUsageRecordHeader,
+				Rows:        rows,
+				FirstCursor: strconv.FormatInt(first.ID, 10),
+				LastCursor:  strconv.FormatInt(last.ID, 10),
+				Time:        first.CreatedAt,
+			},
+			lastID: last.ID,
+		}, nil
+
+	case DatasetTasks:
+		var afterTime time.Time
+		if checkpoint.LastTime != nil {
+			afterTime = *checkpoint.LastTime
+		}
+		tasks, err := e.repo.ListFinishedTasksAfter(ctx, afterTime, checkpoint.LastID, before, e.batchSize)
+		if err != nil || len(tasks) == 0 {
+			return nil, err
+		}
+		rows := make([][]string, 0, len(tasks))
+		for _, t := range tasks {
+			rows = append(rows, taskRow(t))
+		}
+		first, last := tasks[0], tasks[len(tasks)-1]
+		lastTime := *last.CompletedAt
+		return &cursorBatch{
+			Batch: &Batch{
+				Dataset:     dataset,
+				Header:      taskHeader,
+				Rows:        rows,
+				FirstCursor: strconv.FormatInt(first.ID, 10),
+				LastCursor:  strconv.FormatInt(last.ID, 10),
+				Time:        *first.CompletedAt,
+			},
+			lastID:   last.ID,
+			lastTime: &lastTime,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported export dataset: %s", dataset)
+	}
+}
+
+func gpuUsageRecordRow(r *model.GPUUsageRecord) []string {
+	return []string{
+		strconv.FormatInt(r.ID, 10),
+		r.TaskID,
+		r.Endpoint,
+		r.WorkerID,
+		r.SpecName,
+		strconv.Itoa(r.GPUCount),
+		r.GPUType,
+		strconv.Itoa(r.GPUMemoryGB),
+		formatTime(&r.StartedAt),
+		formatTime(&r.CompletedAt),
+		strconv.Itoa(r.DurationSeconds),
+		strconv.FormatFloat(r.GPUHours, 'f', 4, 64),
+		r.Status,
+		formatTime(&r.CreatedAt),
+	}
+}
+
+func taskRow(t *model.Task) []string {
+	var queueWaitMs, executionMs string
+	if t.StartedAt != nil {
+		queueWaitMs = strconv.FormatInt(t.StartedAt.Sub(t.CreatedAt).Milliseconds(), 10)
+		if t.CompletedAt != nil {
+			executionMs = strconv.FormatInt(t.CompletedAt.Sub(*t.StartedAt).Milliseconds(), 10)
+		}
+	}
+	return []string{
+		strconv.FormatInt(t.ID, 10),
+		t.TaskID,
+		t.Endpoint,
+		t.Status,
+		t.WorkerID,
+		truncate(t.Error, 4096),
+		formatTime(&t.CreatedAt),
+		formatTime(t.StartedAt),
+		formatTime(t.CompletedAt),
+		queueWaitMs,
+		executionMs,
+	}
+}
+
+// formatTime formats a timestamp as UTC RFC3339 with milliseconds, empty for nil
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// Batch is a set of rows of one dataset written to a sink in a single call.
+// FirstCursor/LastCursor identify the batch so re-exports after a failed checkpoint
+// write overwrite the same object instead of creating duplicates.
+type Batch struct {
+	Dataset     string
+	Header      []string
+	Rows        [][]string
+	FirstCursor string
+	LastCursor  string
+	Time        time.Time // Cursor time of the first row, used for partitioning
+}
+
+// ObjectKey returns the partitioned object key for a batch (without any sink prefix)
+func (b *Batch) ObjectKey() string {
+	return path.Join(
+		b.Dataset,
+		"dt="+b.Time.UTC().Format("2006-01-02"),
+		fmt.Sprintf("%s-%s-%s.csv.gz", b.Dataset, b.FirstCursor, b.LastCursor),
+	)
+}
+
+// Sink writes exported batches to external storage
+type Sink interface {
+	// Name returns the sink type
+	Name() string
+	// Write stores a batch and returns the object key / table it was written to
+	Write(ctx context.Context, batch *Batch) (string, error)
+}
+
+// ObjectStore stores arbitrary objects (e.g. sampled datasets) next to exported batches
+type ObjectStore interface {
+	// Name returns the store type
+	Name() string
+	// PutObject stores data under key and returns its location
+	PutObject(ctx context.Context, key string, data []byte, contentType string) (string, error)
+}
+
+// ObjectRewriter is an ObjectStore whose objects can be read back and deleted, used to erase
+// individual records from already uploaded objects (e.g. data deletion requests)
+type ObjectRewriter interface {
+	ObjectStore
+	// GetObject returns the data stored under key as it was uploaded
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	// DeleteObject removes the object stored under key; missing objects are not an error
+	DeleteObject(ctx context.Context, key string) error
+}
+
+// encodeCSVGzip encodes header and rows as gzip-compressed CSV
+func encodeCSVGzip(header []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := csv.NewWriter(gz)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LocalSink writes gzip CSV files to a local directory (e.g. a mounted bucket)
+type LocalSink struct {
+	dir string
+}
+
+// NewLocalSink creates a new local filesystem sink
+func NewLocalSink(dir string) (*LocalSink, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("local export dir is required")
+	}
+	return &LocalSink{dir: dir}, nil
+}
+
+func (s *LocalSink) Name() string { return "local" }
+
+func (s *LocalSink) Write(ctx context.Context, batch *Batch) (string, error) {
+	data, err := encodeCSVGzip(batch.Header, batch.Rows)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode batch: %w", err)
+	}
+	return s.PutObject(ctx, batch.ObjectKey(), data, "text/csv")
+}
+
+// PutObject writes data to a file under the sink directory
+func (s *LocalSink) PutObject(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	target := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create export dir: %w", err)
+	}
+
+	// Write to a temp file then rename so readers never see partial files
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return "", fmt.Errorf("failed to rename export file: %w", err)
+	}
+	return target, nil
+}
+
+// GetObject reads a file under the sink directory
+func (s *LocalSink) GetObject(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export file: %w", err)
+	}
+	return data, nil
+}
+
+// DeleteObject removes a file under the sink directory
+func (s *LocalSink) DeleteObject(ctx context.Context, key string) error {
+	if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key))); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete export file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBatchObjectKey tests that object keys are partitioned by day and identified by cursor range.
+func TestBatchObjectKey(t *testing.T) {
+	batch := &Batch{
+		Dataset:     DatasetTasks,
+		FirstCursor: "10",
+		LastCursor:  "20",
+		Time:        time.Date(2026, 3, 4, 23, 0, 0, 0, time.FixedZone("UTC-2", -2*3600)),
+	}
+	assert.Equal(t, "tasks/dt=2026-03-05/tasks-10-20.csv.gz", batch.ObjectKey())
+}
+
+// TestLocalSinkWrite tests that the local sink writes a readable gzip CSV file and overwrites on retry.
+func TestLocalSinkWrite(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewLocalSink(dir)
+	require.NoError(t, err)
+
+	batch := &Batch{
+		Dataset:     DatasetGPUUsageRecords,
+		Header:      []string{"id", "task_id"},
+		Rows:        [][]string{{"1", "task-a"}, {"2", "task-b"}},
+		FirstCursor: "1",
+		LastCursor:  "2",
+		Time:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	path, err := sink.Write(context.Background(), batch)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "gpu_usage_records", "dt=2026-01-01", "gpu_usage_records-1-2.csv.gz"), path)
+
+	// Retrying the same batch overwrites the same file
+	_, err = sink.Write(context.Background(), batch)
+	require.NoError(t, err)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	records, err := csv.NewReader(gz).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"id", "task_id"}, {"1", "task-a"}, {"2", "task-b"}}, records)
+
+	_, err = NewLocalSink("")
+	assert.Error(t, err)
+}
+
+// TestLocalSinkGetDeleteObject tests reading back and deleting objects of the local sink.
+func TestLocalSinkGetDeleteObject(t *testing.T) {
+	ctx := context.Background()
+	sink, err := NewLocalSink(t.TempDir())
+	require.NoError(t, err)
+
+	var store ObjectRewriter = sink
+	_, err = store.PutObject(ctx, "samples/flux/a.jsonl.gz", []byte("data"), "application/x-ndjson")
+	require.NoError(t, err)
+
+	data, err := store.GetObject(ctx, "samples/flux/a.jsonl.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	require.NoError(t, store.DeleteObject(ctx, "samples/flux/a.jsonl.gz"))
+	_, err = store.GetObject(ctx, "samples/flux/a.jsonl.gz")
+	assert.Error(t, err)
+	assert.NoError(t, store.DeleteObject(ctx, "samples/flux/a.jsonl.gz"), "deleting a missing object is not an error")
+}
+
+// TestTaskRow tests derived task timing columns.
+func TestTaskRow(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	started := created.Add(1500 * time.Millisecond)
+	completed := started.Add(3 * time.Second)
+
+	row := taskRow(&model.Task{ID: 7, TaskID: "t-1", CreatedAt: created, StartedAt: &started, CompletedAt: &completed})
+	require.Len(t, row, len(taskHeader))
+	assert.Equal(t, "7", row[0])
+	assert.Equal(t, "2026-01-01T00:00:01.500Z", row[7])
+	assert.Equal(t, "1500", row[9])
+	assert.Equal(t, "3000", row[10])
+}
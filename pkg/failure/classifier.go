@@ -0,0 +1,143 @@
+// Package failure classifies worker failures from provider signals (pod status,
+// container states, events, worker reports) into structured failure types.
+//
+// Classification runs an ordered list of matchers; the first matcher that
+// recognizes any of the signals wins. Specific matchers (CUDA OOM, seccomp
+// denial, ...) come before generic provider reason matching, and callers can
+// register their own matchers ahead of the built-in ones.
+package failure
+
+import (
+	"sync"
+
+	"waverless/pkg/interfaces"
+)
+
+// Source identifies where a signal was observed
+type Source string
+
+const (
+	SourcePod       Source = "pod"       // Pod-level status (phase, eviction)
+	SourceContainer Source = "container" // Container state / last termination state
+	SourceEvent     Source = "event"     // Provider event (e.g. K8s Warning event)
+	SourceWorker    Source = "worker"    // Failure reported by the worker itself
+)
+
+// Signal is a single observation that may indicate a failure
+type Signal struct {
+	Source   Source
+	Reason   string // Provider reason, e.g. "CrashLoopBackOff", "Evicted"
+	Message  string
+	ExitCode int32 // Container exit code, 0 if unknown
+}
+
+// Classification is the structured result of classifying failure signals
+type Classification struct {
+	// Type is the coarse failure category used for health and resource release decisions
+	Type interfaces.FailureType `json:"type"`
+	// Reason is the specific cause, e.g. "OOMKilled", "CUDAOutOfMemory", "DiskPressure"
+	Reason string `json:"reason"`
+	// Details holds matcher-specific context (exit code, evicted resource, ...)
+	Details map[string]string `json:"details,omitempty"`
+	// Matcher is the name of the matcher that produced the classification
+	Matcher string `json:"matcher,omitempty"`
+}
+
+// Matcher recognizes a failure from a single signal
+type Matcher interface {
+	// Name identifies the matcher in classifications and logs
+	Name() string
+	// Match returns a classification, or nil if the signal is not recognized
+	Match(s Signal) *Classification
+}
+
+// matcherFunc adapts a function to the Matcher interface
+type matcherFunc struct {
+	name string
+	fn   func(s Signal) *Classification
+}
+
+func (m *matcherFunc) Name() string                   { return m.name }
+func (m *matcherFunc) Match(s Signal) *Classification { return m.fn(s) }
+
+// NewMatcher creates a matcher from a function
+func NewMatcher(name string, fn func(s Signal) *Classification) Matcher {
+	return &matcherFunc{name: name, fn: fn}
+}
+
+// Classifier runs matchers in order over a set of signals
+type Classifier struct {
+	mu       sync.RWMutex
+	matchers []Matcher
+}
+
+// NewClassifier creates a classifier with the given matchers, in priority order
+func NewClassifier(matchers ...Matcher) *Classifier {
+	return &Classifier{matchers: matchers}
+}
+
+// NewDefaultClassifier creates a classifier with the built-in matchers
+func NewDefaultClassifier() *Classifier {
+	return NewClassifier(DefaultMatchers()...)
+}
+
+var (
+	defaultClassifier     *Classifier
+	defaultClassifierOnce sync.Once
+)
+
+// Default returns the shared classifier with the built-in matchers
+func Default() *Classifier {
+	defaultClassifierOnce.Do(func() {
+		defaultClassifier = NewDefaultClassifier()
+	})
+	return defaultClassifier
+}
+
+// Register adds a matcher ahead of the existing ones so it takes precedence
+func (c *Classifier) Register(m Matcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.matchers = append([]Matcher{m}, c.matchers...)
+}
+
+// Matchers returns the matcher names in priority order
+func (c *Classifier) Matchers() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, len(c.matchers))
+	for i, m := range c.matchers {
+		names[i] = m.Name()
+	}
+	return names
+}
+
+// Classify returns the classification of the highest-priority matcher that
+// recognizes any signal. Matcher order takes precedence over signal order.
+// Returns an UNKNOWN classification if no matcher applies.
+func (c *Classifier) Classify(signals ...Signal) *Classification {
+	c.mu.RLock()
+	matchers := c.matchers
+	c.mu.RUnlock()
+
+	for _, m := range matchers {
+		for _, s := range signals {
+			if result := m.Match(s); result != nil {
+				if result.Reason == "" {
+					result.Reason = s.Reason
+				}
+				result.Matcher = m.Name()
+				return result
+			}
+		}
+	}
+
+	unknown := &Classification{Type: interfaces.FailureTypeUnknown}
+	for _, s := range signals {
+		if s.Reason != "" {
+			unknown.Reason = s.Reason
+			break
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,131 @@
+package failure
+
+import (
+	"testing"
+
+	"waverless/pkg/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClassifier_BuiltinMatchers tests the built-in failure causes
+func TestClassifier_BuiltinMatchers(t *testing.T) {
+	tests := []struct {
+		name       string
+		signals    []Signal
+		wantType   interfaces.FailureType
+		wantReason string
+	}{
+		{
+			name:       "OOMKilled behind CrashLoopBackOff",
+			signals:    []Signal{{Source: SourceContainer, Reason: "CrashLoopBackOff"}, {Source: SourceContainer, Reason: "OOMKilled", ExitCode: 137}},
+			wantType:   interfaces.FailureTypeContainerCrash,
+			wantReason: ReasonOOMKilled,
+		},
+		{
+			name:       "CUDA OOM in termination message",
+			signals:    []Signal{{Source: SourceContainer, Reason: "Error", Message: "RuntimeError: CUDA out of memory. Tried to allocate 2.00 GiB", ExitCode: 1}},
+			wantType:   interfaces.FailureTypeContainerCrash,
+			wantReason: ReasonCUDAOutOfMemory,
+		},
+		{
+			name:       "CUDA OOM reported by worker",
+			signals:    []Signal{{Source: SourceWorker, Message: "torch.OutOfMemoryError: CUDA error: out of memory"}},
+			wantType:   interfaces.FailureTypeContainerCrash,
+			wantReason: ReasonCUDAOutOfMemory,
+		},
+		{
+			name:       "disk pressure eviction",
+			signals:    []Signal{{Source: SourcePod, Reason: "Evicted", Message: "The node was low on resource: ephemeral-storage. Threshold quantity: 10Gi"}},
+			wantType:   interfaces.FailureTypeResourceLimit,
+			wantReason: ReasonDiskPressure,
+		},
+		{
+			name:       "no space left on device",
+			signals:    []Signal{{Source: SourceContainer, Reason: "Error", Message: "write /tmp/model.bin: no space left on device"}},
+			wantType:   interfaces.FailureTypeResourceLimit,
+			wantReason: ReasonDiskPressure,
+		},
+		{
+			name:       "memory eviction",
+			signals:    []Signal{{Source: SourcePod, Reason: "Evicted", Message: "The node was low on resource: memory. Container worker was using 30Gi"}},
+			wantType:   interfaces.FailureTypeResourceLimit,
+			wantReason: ReasonEvicted,
+		},
+		{
+			name:       "seccomp message",
+			signals:    []Signal{{Source: SourceContainer, Reason: "CreateContainerError", Message: "failed to create containerd task: seccomp filter: operation not permitted"}},
+			wantType:   interfaces.FailureTypeContainerCrash,
+			wantReason: ReasonSeccompDenied,
+		},
+		{
+			name:       "SIGSYS exit code",
+			signals:    []Signal{{Source: SourceContainer, Reason: "Error", ExitCode: 159}},
+			wantType:   interfaces.FailureTypeContainerCrash,
+			wantReason: ReasonSeccompDenied,
+		},
+		{
+			name:       "provider reason",
+			signals:    []Signal{{Source: SourceContainer, Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+			wantType:   interfaces.FailureTypeImagePull,
+			wantReason: "ImagePullBackOff",
+		},
+		{
+			name:       "unknown",
+			signals:    []Signal{{Source: SourceContainer, Reason: "Running"}},
+			wantType:   interfaces.FailureTypeUnknown,
+			wantReason: "Running",
+		},
+	}
+
+	classifier := NewDefaultClassifier()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := classifier.Classify(tt.signals...)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantType, result.Type)
+			assert.Equal(t, tt.wantReason, result.Reason)
+		})
+	}
+}
+
+// TestClassifier_Details tests matcher-specific details
+func TestClassifier_Details(t *testing.T) {
+	classifier := NewDefaultClassifier()
+
+	result := classifier.Classify(Signal{Source: SourcePod, Reason: "Evicted", Message: "The node was low on resource: memory. Threshold quantity: 100Mi"})
+	assert.Equal(t, "eviction", result.Matcher)
+	assert.Equal(t, "memory", result.Details["resource"])
+	assert.Equal(t, "pod", result.Details["source"])
+
+	result = classifier.Classify(Signal{Source: SourcePod, Reason: "Evicted", Message: "Container worker exceeded its local ephemeral storage limit \"30Gi\". "})
+	assert.Equal(t, ReasonDiskPressure, result.Reason)
+	assert.Equal(t, DiskScopeLimit, result.Details["scope"])
+
+	result = classifier.Classify(Signal{Source: SourcePod, Reason: "Evicted", Message: "The node was low on resource: ephemeral-storage. Threshold quantity: 10Gi"})
+	assert.Equal(t, DiskScopeNode, result.Details["scope"])
+
+	result = classifier.Classify(Signal{Source: SourceContainer, Reason: "Error", ExitCode: 159})
+	assert.Equal(t, "SIGSYS", result.Details["signal"])
+	assert.Equal(t, "159", result.Details["exitCode"])
+}
+
+// TestClassifier_Register verifies custom matchers take precedence over built-in ones
+func TestClassifier_Register(t *testing.T) {
+	classifier := NewDefaultClassifier()
+	classifier.Register(NewMatcher("nccl", func(s Signal) *Classification {
+		if s.Reason == "Error" && s.ExitCode == 134 {
+			return &Classification{Type: interfaces.FailureTypeContainerCrash, Reason: "NCCLAbort"}
+		}
+		return nil
+	}))
+
+	result := classifier.Classify(Signal{Source: SourceContainer, Reason: "Error", ExitCode: 134})
+	assert.Equal(t, "NCCLAbort", result.Reason)
+	assert.Equal(t, "nccl", result.Matcher)
+	assert.Equal(t, "nccl", classifier.Matchers()[0])
+
+	// Default classifier is unaffected
+	assert.Equal(t, "Error", Default().Classify(Signal{Reason: "Error", ExitCode: 134}).Reason)
+}
@@ -0,0 +1,231 @@
+package failure
+
+import (
+	"strconv"
+	"strings"
+
+	"waverless/pkg/interfaces"
+)
+
+// Specific failure reasons produced by the built-in matchers
+const (
+	ReasonOOMKilled       = "OOMKilled"
+	ReasonCUDAOutOfMemory = "CUDAOutOfMemory"
+	ReasonDiskPressure    = "DiskPressure"
+	ReasonEvicted         = "Evicted"
+	ReasonSeccompDenied   = "SeccompDenied"
+)
+
+// Scopes of a disk pressure failure, reported in the "scope" detail
+const (
+	DiskScopeLimit = "limit" // The pod exceeded its own ephemeral-storage limit
+	DiskScopeNode  = "node"  // The node ran out of disk
+)
+
+// exitCodeSIGSYS is the exit code of a process killed by a seccomp filter (128 + SIGSYS)
+const exitCodeSIGSYS = 159
+
+// DefaultMatchers returns the built-in matchers in priority order
+func DefaultMatchers() []Matcher {
+	return []Matcher{
+		SeccompMatcher(),
+		CUDAOutOfMemoryMatcher(),
+		OOMKilledMatcher(),
+		DiskPressureMatcher(),
+		EvictionMatcher(),
+		ProviderReasonMatcher(),
+	}
+}
+
+// SeccompMatcher detects syscalls blocked by a seccomp profile
+func SeccompMatcher() Matcher {
+	return NewMatcher("seccomp", func(s Signal) *Classification {
+		if containsAny(strings.ToLower(s.Message), "seccomp") {
+			return &Classification{Type: interfaces.FailureTypeContainerCrash, Reason: ReasonSeccompDenied, Details: signalDetails(s)}
+		}
+		if s.ExitCode == exitCodeSIGSYS {
+			details := signalDetails(s)
+			details["signal"] = "SIGSYS"
+			return &Classification{Type: interfaces.FailureTypeContainerCrash, Reason: ReasonSeccompDenied, Details: details}
+		}
+		return nil
+	})
+}
+
+// CUDAOutOfMemoryMatcher detects GPU memory exhaustion reported by CUDA runtimes
+func CUDAOutOfMemoryMatcher() Matcher {
+	return NewMatcher("cuda-oom", func(s Signal) *Classification {
+		if containsAny(strings.ToLower(s.Message),
+			"cuda out of memory",
+			"cuda error: out of memory",
+			"cudaerrormemoryallocation",
+			"cublas_status_alloc_failed",
+		) {
+			return &Classification{Type: interfaces.FailureTypeContainerCrash, Reason: ReasonCUDAOutOfMemory, Details: signalDetails(s)}
+		}
+		return nil
+	})
+}
+
+// OOMKilledMatcher detects containers killed by the kernel OOM killer
+func OOMKilledMatcher() Matcher {
+	return NewMatcher("oom-killed", func(s Signal) *Classification {
+		if strings.EqualFold(s.Reason, "OOMKilled") {
+			return &Classification{Type: interfaces.FailureTypeContainerCrash, Reason: ReasonOOMKilled, Details: signalDetails(s)}
+		}
+		return nil
+	})
+}
+
+// DiskPressureMatcher detects node disk exhaustion (eviction, image GC failure, full volumes)
+func DiskPressureMatcher() Matcher {
+	return NewMatcher("disk-pressure", func(s Signal) *Classification {
+		reason := strings.ToLower(s.Reason)
+		message := strings.ToLower(s.Message)
+
+		// "Container worker exceeded its local ephemeral storage limit" or
+		// "Pod ephemeral local storage usage exceeds the total limit of containers 30Gi"
+		limitExceeded := reason == "evicted" && containsAny(message, "ephemeral storage limit", "ephemeral local storage usage exceeds")
+
+		matched := reason == "diskpressure" ||
+			reason == "freediskspacefailed" ||
+			limitExceeded ||
+			(reason == "evicted" && containsAny(message, "ephemeral-storage", "disk")) ||
+			(reason == "evictionthresholdmet" && containsAny(message, "ephemeral-storage", "nodefs", "imagefs")) ||
+			containsAny(message, "no space left on device", "disk-pressure")
+		if !matched {
+			return nil
+		}
+		details := signalDetails(s)
+		switch {
+		case limitExceeded:
+			details["scope"] = DiskScopeLimit
+		case reason == "evicted" || reason == "diskpressure" || reason == "evictionthresholdmet":
+			details["scope"] = DiskScopeNode
+		}
+		return &Classification{Type: interfaces.FailureTypeResourceLimit, Reason: ReasonDiskPressure, Details: details}
+	})
+}
+
+// EvictionMatcher detects pods evicted by the kubelet for reasons other than disk
+func EvictionMatcher() Matcher {
+	return NewMatcher("eviction", func(s Signal) *Classification {
+		if !strings.EqualFold(s.Reason, "Evicted") {
+			return nil
+		}
+		details := signalDetails(s)
+		// "The node was low on resource: memory. ..."
+		if i := strings.Index(s.Message, "low on resource: "); i >= 0 {
+			resource := s.Message[i+len("low on resource: "):]
+			if end := strings.IndexAny(resource, ". "); end > 0 {
+				resource = resource[:end]
+			}
+			details["resource"] = resource
+		}
+		return &Classification{Type: interfaces.FailureTypeResourceLimit, Reason: ReasonEvicted, Details: details}
+	})
+}
+
+// ProviderReasonMatcher maps provider reasons and messages (K8s waiting/terminated
+// reasons, Warning events) to failure types by reason name and keywords
+func ProviderReasonMatcher() Matcher {
+	return NewMatcher("provider-reason", func(s Signal) *Classification {
+		failureType := classifyProviderReason(s.Reason, s.Message)
+		if failureType == interfaces.FailureTypeUnknown {
+			return nil
+		}
+		return &Classification{Type: failureType, Details: signalDetails(s)}
+	})
+}
+
+// classifyProviderReason converts a provider reason to a generic FailureType
+func classifyProviderReason(reason, message string) interfaces.FailureType {
+	// Normalize reason for comparison
+	reasonLower := strings.ToLower(reason)
+
+	// Image pull failures
+	switch reason {
+	case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ImageInspectError":
+		return interfaces.FailureTypeImagePull
+	}
+
+	// Check for image-related keywords in reason
+	if strings.Contains(reasonLower, "image") &&
+		(strings.Contains(reasonLower, "pull") ||
+			strings.Contains(reasonLower, "error") ||
+			strings.Contains(reasonLower, "invalid")) {
+		return interfaces.FailureTypeImagePull
+	}
+
+	// Container crash failures
+	switch reason {
+	case "CrashLoopBackOff", "Error", "OOMKilled", "ContainerCannotRun", "CreateContainerError":
+		return interfaces.FailureTypeContainerCrash
+	}
+
+	// Check for crash-related keywords
+	if strings.Contains(reasonLower, "crash") ||
+		strings.Contains(reasonLower, "oom") ||
+		strings.Contains(reasonLower, "killed") {
+		return interfaces.FailureTypeContainerCrash
+	}
+
+	// Resource limit failures
+	switch reason {
+	case "OutOfMemory", "OutOfCpu", "Unschedulable", "FailedScheduling":
+		return interfaces.FailureTypeResourceLimit
+	}
+
+	// Check for resource-related keywords
+	if strings.Contains(reasonLower, "memory") ||
+		strings.Contains(reasonLower, "cpu") ||
+		strings.Contains(reasonLower, "resource") ||
+		strings.Contains(reasonLower, "unschedulable") {
+		return interfaces.FailureTypeResourceLimit
+	}
+
+	// Check message for additional context
+	messageLower := strings.ToLower(message)
+
+	// Image-related messages
+	if strings.Contains(messageLower, "image") &&
+		(strings.Contains(messageLower, "pull") ||
+			strings.Contains(messageLower, "not found") ||
+			strings.Contains(messageLower, "unauthorized")) {
+		return interfaces.FailureTypeImagePull
+	}
+
+	// Resource-related messages
+	if strings.Contains(messageLower, "insufficient") ||
+		strings.Contains(messageLower, "no nodes available") {
+		return interfaces.FailureTypeResourceLimit
+	}
+
+	// Timeout-related
+	if strings.Contains(reasonLower, "timeout") || strings.Contains(messageLower, "timeout") {
+		return interfaces.FailureTypeTimeout
+	}
+
+	return interfaces.FailureTypeUnknown
+}
+
+// signalDetails returns the common details of a matched signal
+func signalDetails(s Signal) map[string]string {
+	details := map[string]string{"source": string(s.Source)}
+	if s.Reason != "" {
+		details["providerReason"] = s.Reason
+	}
+	if s.ExitCode != 0 {
+		details["exitCode"] = strconv.Itoa(int(s.ExitCode))
+	}
+	return details
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Package federation routes tasks of a federated endpoint (a logical endpoint backed by
+// deployments in several regions/clusters) to the most suitable backing endpoint.
+package federation
+
+import (
+	"errors"
+	"sort"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+// ErrNoAvailableRegion is returned when no member of a federation can take tasks
+var ErrNoAvailableRegion = errors.New("no available region")
+
+// Tier is the routing preference class of a member; lower tiers are tried first
+type Tier int
+
+const (
+	TierReady       Tier = iota // Healthy with ready replicas
+	TierDegraded                // Ready replicas, but some workers failed
+	TierCold                    // No ready replicas yet, can scale up (cold start)
+	TierUnavailable             // Unhealthy, disabled or cannot run replicas
+)
+
+// String returns the tier name used in status responses
+func (t Tier) String() string {
+	switch t {
+	case TierReady:
+		return "ready"
+	case TierDegraded:
+		return "degraded"
+	case TierCold:
+		return "cold"
+	default:
+		return "unavailable"
+	}
+}
+
+// Hints are client-provided routing preferences
+type Hints struct {
+	Region    string         // Preferred region (explicit or derived from the source IP)
+	LatencyMs map[string]int // Client-measured latency per region
+}
+
+// Candidate is the routing state of one member
+type Candidate struct {
+	Endpoint      string `json:"endpoint"`
+	Region        string `json:"region"`
+	Priority      int    `json:"priority"` // Higher = preferred (e.g. nearer to clients)
+	Weight        int    `json:"weight"`   // Percent of routed traffic the member keeps, the rest spills to the next one
+	Enabled       bool   `json:"enabled"`
+	HealthStatus  string `json:"healthStatus,omitempty"`
+	ReadyReplicas int    `json:"readyReplicas"`
+	MaxReplicas   int    `json:"maxReplicas"`
+	PendingTasks  int64  `json:"pendingTasks"`
+	Tier          Tier   `json:"-"`
+	TierName      string `json:"tier"`
+}
+
+// Classify sets the tier of the candidate from its health and replicas
+func (c *Candidate) Classify() {
+	switch {
+	case !c.Enabled || c.HealthStatus == string(model.HealthStatusUnhealthy) || (c.MaxReplicas <= 0 && c.ReadyReplicas <= 0):
+		c.Tier = TierUnavailable
+	case c.ReadyReplicas <= 0:
+		c.Tier = TierCold
+	case c.HealthStatus == string(model.HealthStatusDegraded):
+		c.Tier = TierDegraded
+	default:
+		c.Tier = TierReady
+	}
+	c.TierName = c.Tier.String()
+}
+
+// load is the queue depth per ready replica (pending tasks when cold)
+func (c *Candidate) load() float64 {
+	if c.ReadyReplicas <= 0 {
+		return float64(c.PendingTasks)
+	}
+	return float64(c.PendingTasks) / float64(c.ReadyReplicas)
+}
+
+// nearness ranks a candidate against the hints: hinted region, then regions with a
+// measured latency, then the rest
+func (c *Candidate) nearness(hints *Hints) int {
+	if hints == nil {
+		return 0
+	}
+	if hints.Region != "" && c.Region == hints.Region {
+		return 0
+	}
+	if _, ok := hints.LatencyMs[c.Region]; ok {
+		return 1
+	}
+	return 2
+}
+
+// Rank classifies the candidates and orders them by routing preference: tier first
+// (healthiest), then nearness to the client hints, measured latency and priority,
+// then queue depth per ready replica. The first element is the routing target; the
+// rest are failover targets in order. Unavailable members are sorted last.
+func Rank(candidates []*Candidate, hints *Hints) []*Candidate {
+	for _, c := range candidates {
+		c.Classify()
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Tier != b.Tier {
+			return a.Tier < b.Tier
+		}
+		if na, nb := a.nearness(hints), b.nearness(hints); na != nb {
+			return na < nb
+		} else if na == 1 && hints.LatencyMs[a.Region] != hints.LatencyMs[b.Region] {
+			return hints.LatencyMs[a.Region] < hints.LatencyMs[b.Region]
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.load() < b.load()
+	})
+	return candidates
+}
+
+// Pick returns the member a task is routed to, or ErrNoAvailableRegion.
+// Available members are walked in rank order and each keeps Weight percent of the
+// traffic reaching it (rnd returns values in [0, 1)); the rest spills to the next
+// one, so lowering a region's weight migrates its traffic gradually. If every member
+// spilled, the first one with a non-zero weight takes the task.
+func Pick(candidates []*Candidate, hints *Hints, rnd func() float64) (*Candidate, error) {
+	ranked := Rank(candidates, hints)
+	var fallback *Candidate
+	for _, c := range ranked {
+		if c.Tier == TierUnavailable {
+			break
+		}
+		if c.Weight >= 100 || rnd()*100 < float64(c.Weight) {
+			return c, nil
+		}
+		if fallback == nil || (fallback.Weight <= 0 && c.Weight > 0) {
+			fallback = c
+		}
+	}
+	if fallback == nil {
+		return nil, ErrNoAvailableRegion
+	}
+	return fallback, nil
+}
@@ -0,0 +1,132 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/config"
+)
+
+// always returns a fixed random value
+func always(v float64) func() float64 { return func() float64 { return v } }
+
+func TestPick_PrefersHealthyThenPriority(t *testing.T) {
+	candidates := []*Candidate{
+		{Endpoint: "llm-eu", Region: "eu", Priority: 100, Weight: 100, Enabled: true, HealthStatus: "DEGRADED", ReadyReplicas: 2, MaxReplicas: 4},
+		{Endpoint: "llm-us", Region: "us", Priority: 50, Weight: 100, Enabled: true, HealthStatus: "HEALTHY", ReadyReplicas: 1, MaxReplicas: 4},
+		{Endpoint: "llm-ap", Region: "ap", Priority: 80, Weight: 100, Enabled: true, HealthStatus: "HEALTHY", ReadyReplicas: 1, MaxReplicas: 4},
+	}
+
+	got, err := Pick(candidates, nil, always(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "llm-ap", got.Endpoint)
+	assert.Equal(t, []string{"llm-ap", "llm-us", "llm-eu"}, endpoints(candidates))
+}
+
+func TestPick_FailsOverToColdRegion(t *testing.T) {
+	candidates := []*Candidate{
+		{Endpoint: "llm-eu", Region: "eu", Priority: 100, Weight: 100, Enabled: true, HealthStatus: "UNHEALTHY", ReadyReplicas: 2, MaxReplicas: 4},
+		{Endpoint: "llm-us", Region: "us", Priority: 50, Weight: 100, Enabled: true, HealthStatus: "HEALTHY", MaxReplicas: 4},
+		{Endpoint: "llm-ap", Region: "ap", Priority: 90, Weight: 100, Enabled: false, HealthStatus: "HEALTHY", ReadyReplicas: 3, MaxReplicas: 4},
+	}
+
+	got, err := Pick(candidates, nil, always(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "llm-us", got.Endpoint)
+	assert.Equal(t, TierCold, got.Tier)
+}
+
+func TestPick_BreaksTiesByLoad(t *testing.T) {
+	candidates := []*Candidate{
+		{Endpoint: "busy", Weight: 100, Enabled: true, ReadyReplicas: 2, MaxReplicas: 2, PendingTasks: 10},
+		{Endpoint: "idle", Weight: 100, Enabled: true, ReadyReplicas: 4, MaxReplicas: 4, PendingTasks: 10},
+	}
+
+	got, err := Pick(candidates, nil, always(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "idle", got.Endpoint)
+}
+
+func TestPick_NoAvailableRegion(t *testing.T) {
+	_, err := Pick(nil, nil, always(0))
+	assert.ErrorIs(t, err, ErrNoAvailableRegion)
+
+	_, err = Pick([]*Candidate{{Endpoint: "down", Weight: 100, Enabled: true, HealthStatus: "UNHEALTHY", ReadyReplicas: 1, MaxReplicas: 1}}, nil, always(0))
+	assert.ErrorIs(t, err, ErrNoAvailableRegion)
+}
+
+func TestPick_Hints(t *testing.T) {
+	newCandidates := func() []*Candidate {
+		return []*Candidate{
+			{Endpoint: "llm-us", Region: "us", Priority: 100, Weight: 100, Enabled: true, ReadyReplicas: 1, MaxReplicas: 2},
+			{Endpoint: "llm-eu", Region: "eu", Priority: 50, Weight: 100, Enabled: true, ReadyReplicas: 1, MaxReplicas: 2},
+			{Endpoint: "llm-ap", Region: "ap", Priority: 10, Weight: 100, Enabled: true, ReadyReplicas: 1, MaxReplicas: 2},
+		}
+	}
+
+	got, err := Pick(newCandidates(), &Hints{Region: "eu"}, always(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "llm-eu", got.Endpoint, "hinted region wins over priority")
+
+	got, err = Pick(newCandidates(), &Hints{LatencyMs: map[string]int{"eu": 80, "ap": 20}}, always(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "llm-ap", got.Endpoint, "lowest measured latency wins")
+
+	candidates := newCandidates()
+	candidates[1].HealthStatus = "UNHEALTHY"
+	got, err = Pick(candidates, &Hints{Region: "eu"}, always(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "llm-us", got.Endpoint, "unhealthy hinted region fails over")
+}
+
+func TestPick_WeightSpillsTraffic(t *testing.T) {
+	newCandidates := func(weight int) []*Candidate {
+		return []*Candidate{
+			{Endpoint: "old", Region: "us-west", Priority: 100, Weight: weight, Enabled: true, ReadyReplicas: 1, MaxReplicas: 2},
+			{Endpoint: "new", Region: "us-east", Priority: 50, Weight: 100, Enabled: true, ReadyReplicas: 1, MaxReplicas: 2},
+		}
+	}
+
+	got, _ := Pick(newCandidates(30), nil, always(0.2))
+	assert.Equal(t, "old", got.Endpoint, "below the weight the region keeps the task")
+
+	got, _ = Pick(newCandidates(30), nil, always(0.6))
+	assert.Equal(t, "new", got.Endpoint, "above the weight the task spills to the next region")
+
+	got, _ = Pick(newCandidates(0), nil, always(0))
+	assert.Equal(t, "new", got.Endpoint, "weight 0 drains the region")
+
+	candidates := newCandidates(0)
+	candidates[1].HealthStatus = "UNHEALTHY"
+	got, err := Pick(candidates, nil, always(0))
+	require.NoError(t, err)
+	assert.Equal(t, "old", got.Endpoint, "a drained region still serves as last resort")
+}
+
+func TestSourceRegionResolver(t *testing.T) {
+	r, err := NewSourceRegionResolver([]config.SourceRegion{
+		{CIDR: "10.8.0.0/16", Region: "us-east"},
+		{CIDR: "10.0.0.0/8", Region: "eu-west"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "us-east", r.Resolve("10.8.1.2"))
+	assert.Equal(t, "eu-west", r.Resolve("10.9.1.2"))
+	assert.Empty(t, r.Resolve("192.168.1.1"))
+	assert.Empty(t, r.Resolve("not-an-ip"))
+
+	var disabled *SourceRegionResolver
+	assert.Empty(t, disabled.Resolve("10.8.1.2"))
+
+	_, err = NewSourceRegionResolver([]config.SourceRegion{{CIDR: "10.8.0.0", Region: "us-east"}})
+	assert.Error(t, err)
+}
+
+func endpoints(candidates []*Candidate) []string {
+	names := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		names = append(names, c.Endpoint)
+	}
+	return names
+}
@@ -0,0 +1,45 @@
+package federation
+
+import (
+	"fmt"
+	"net"
+
+	"waverless/pkg/config"
+)
+
+// SourceRegionResolver derives a region hint from the client IP
+type SourceRegionResolver struct {
+	networks []*net.IPNet
+	regions  []string
+}
+
+// NewSourceRegionResolver parses the configured client networks; the first match wins
+func NewSourceRegionResolver(sources []config.SourceRegion) (*SourceRegionResolver, error) {
+	r := &SourceRegionResolver{}
+	for _, src := range sources {
+		_, network, err := net.ParseCIDR(src.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source region cidr %q: %w", src.CIDR, err)
+		}
+		r.networks = append(r.networks, network)
+		r.regions = append(r.regions, src.Region)
+	}
+	return r, nil
+}
+
+// Resolve returns the region of the client IP, empty if no network matches
+func (r *SourceRegionResolver) Resolve(clientIP string) string {
+	if r == nil {
+		return ""
+	}
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return ""
+	}
+	for i, network := range r.networks {
+		if network.Contains(ip) {
+			return r.regions[i]
+		}
+	}
+	return ""
+}
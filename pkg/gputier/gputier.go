@@ -0,0 +1,59 @@
+// Package gputier routes the tasks of a heterogeneous endpoint to the GPU tier that fits
+// them. A heterogeneous endpoint runs on its own spec (typically the expensive GPU) and lists
+// tier endpoints running the same image on other specs, each with routing rules. A task goes
+// to the first tier whose rules accept it, and to the endpoint itself when none does, so
+// cheap requests never occupy expensive GPUs.
+package gputier
+
+import (
+	"slices"
+	"strings"
+)
+
+// Tier is an endpoint serving the tasks its rules accept. Empty rules accept everything; at
+// least one rule is required when a tier is saved.
+type Tier struct {
+	Endpoint      string
+	MaxInputBytes int      // Largest accepted input (JSON encoded), 0 = any size
+	Precisions    []string // Accepted requested precisions, empty = any (normalized)
+}
+
+// Task is what the rules look at
+type Task struct {
+	InputBytes int
+	Precision  string // Requested precision, empty when the client did not ask for one
+}
+
+// NormalizePrecision lowercases and trims a precision ("FP16 " -> "fp16")
+func NormalizePrecision(precision string) string {
+	return strings.ToLower(strings.TrimSpace(precision))
+}
+
+// Accepts reports whether the tier's rules accept the task. A tier restricted to precisions
+// only takes tasks that ask for one of them.
+func (t Tier) Accepts(task Task) bool {
+	if t.MaxInputBytes > 0 && task.InputBytes > t.MaxInputBytes {
+		return false
+	}
+	if len(t.Precisions) > 0 && !slices.Contains(t.Precisions, NormalizePrecision(task.Precision)) {
+		return false
+	}
+	return true
+}
+
+// NeedsInputSize reports whether routing needs the task's input size, which costs encoding
+// the input
+func NeedsInputSize(tiers []Tier) bool {
+	return slices.ContainsFunc(tiers, func(t Tier) bool { return t.MaxInputBytes > 0 })
+}
+
+// Route returns the first tier in order that accepts the task; ok is false when the task
+// stays on the heterogeneous endpoint itself
+func Route(tiers []Tier, task Task) (tier Tier, ok bool) {
+	for _, t := range tiers {
+		if t.Accepts(task) {
+			return t, true
+		}
+	}
+	return Tier{}, false
+}
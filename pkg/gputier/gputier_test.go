@@ -0,0 +1,40 @@
+package gputier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute(t *testing.T) {
+	tiers := []Tier{
+		{Endpoint: "llm-t4", MaxInputBytes: 1024, Precisions: []string{"int8"}},
+		{Endpoint: "llm-a10", MaxInputBytes: 64 * 1024},
+	}
+
+	cases := []struct {
+		name string
+		task Task
+		want string
+	}{
+		{"small int8 task takes the cheapest tier", Task{InputBytes: 512, Precision: " INT8"}, "llm-t4"},
+		{"small task without precision skips precision-restricted tier", Task{InputBytes: 512}, "llm-a10"},
+		{"medium task", Task{InputBytes: 4096, Precision: "int8"}, "llm-a10"},
+		{"large task stays on the endpoint", Task{InputBytes: 1 << 20, Precision: "fp16"}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tier, ok := Route(tiers, tc.task)
+			assert.Equal(t, tc.want != "", ok)
+			assert.Equal(t, tc.want, tier.Endpoint)
+		})
+	}
+
+	_, ok := Route(nil, Task{InputBytes: 1})
+	assert.False(t, ok)
+}
+
+func TestNeedsInputSize(t *testing.T) {
+	assert.False(t, NeedsInputSize([]Tier{{Endpoint: "a", Precisions: []string{"fp8"}}}))
+	assert.True(t, NeedsInputSize([]Tier{{Endpoint: "a", Precisions: []string{"fp8"}}, {Endpoint: "b", MaxInputBytes: 10}}))
+}
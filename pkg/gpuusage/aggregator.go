@@ -0,0 +1,526 @@
+package gpuusage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+)
+
+// ErrBackfillInProgress is returned when a backfill is requested while another one is running
+var ErrBackfillInProgress = errors.New("gpu usage backfill already in progress")
+
+type endpointGetter interface {
+	Get(ctx context.Context, name string) (*model.Endpoint, error)
+}
+
+type specGetter interface {
+	Get(ctx context.Context, name string) (*model.Spec, error)
+}
+
+type workerGetter interface {
+	Get(ctx context.Context, workerID string) (*model.Worker, error)
+}
+
+// Config controls aggregation scheduling behaviour
+type Config struct {
+	// SettleDelay is how long after a minute ends before it is aggregated,
+	// giving asynchronously recorded usage time to land.
+	SettleDelay time.Duration
+	// InitialLookback is how far back the first run starts when no watermark exists yet.
+	InitialLookback time.Duration
+	// MaxCatchUpWindow bounds how much time a single run advances a watermark,
+	// so a long outage is caught up over several runs instead of one huge one.
+	MaxCatchUpWindow time.Duration
+	// LagWarnThreshold logs a warning when the minute watermark falls further behind than this.
+	LagWarnThreshold time.Duration
+	// MinuteRetention, HourlyRetention control cleanup of rolled-up statistics.
+	MinuteRetention time.Duration
+	HourlyRetention time.Duration
+}
+
+// DefaultConfig returns the default aggregation configuration
+func DefaultConfig() Config {
+	return Config{
+		SettleDelay:      time.Minute,
+		InitialLookback:  time.Hour,
+		MaxCatchUpWindow: 6 * time.Hour,
+		LagWarnThreshold: 10 * time.Minute,
+		MinuteRetention:  7 * 24 * time.Hour,
+		HourlyRetention:  90 * 24 * time.Hour,
+	}
+}
+
+// Aggregator records task GPU usage and rolls it up into minute/hourly/daily statistics.
+// Progress is tracked by a per-granularity watermark stored in MySQL, so any replica
+// that wins the aggregation lock continues from where the previous one stopped.
+type Aggregator struct {
+	repo         *mysql.GPUUsageRepository
+	endpointRepo endpointGetter
+	specRepo     specGetter
+	workerRepo   workerGetter
+	config       Config
+
+	backfillRunning atomic.Bool
+}
+
+// NewAggregator creates a new GPU usage aggregator
+func NewAggregator(repo *mysql.GPUUsageRepository, endpointRepo endpointGetter, specRepo specGetter, config Config) *Aggregator {
+	return &Aggregator{
+		repo:         repo,
+		endpointRepo: endpointRepo,
+		specRepo:     specRepo,
+		config:       config,
+	}
+}
+
+// SetWorkerRepository records the GPU type each worker actually ran on (GPU type fallbacks)
+// instead of the spec's gpuType
+func (a *Aggregator) SetWorkerRepository(workerRepo workerGetter) {
+	a.workerRepo = workerRepo
+}
+
+// specCache caches endpoint/spec/worker GPU type lookups during a backfill run
+type specCache struct {
+	endpoints map[string]*model.Endpoint
+	specs     map[string]*model.Spec
+	gpuTypes  map[string]string
+}
+
+func newSpecCache() *specCache {
+	return &specCache{
+		endpoints: make(map[string]*model.Endpoint),
+		specs:     make(map[string]*model.Spec),
+		gpuTypes:  make(map[string]string),
+	}
+}
+
+// RecordTask creates the GPU usage record for a finished task.
+// Returns false without error when the task is not eligible (not finished, CPU-only spec, endpoint gone).
+func (a *Aggregator) RecordTask(ctx context.Context, task *model.Task) (bool, error) {
+	return a.recordTask(ctx, task, nil)
+}
+
+func (a *Aggregator) recordTask(ctx context.Context, task *model.Task, cache *specCache) (bool, error) {
+	if task == nil || task.StartedAt == nil || task.CompletedAt == nil {
+		return false, nil
+	}
+
+	endpoint, err := a.lookupEndpoint(ctx, task.Endpoint, cache)
+	if err != nil {
+		return false, err
+	}
+	if endpoint == nil {
+		return false, nil
+	}
+
+	spec, err := a.lookupSpec(ctx, endpoint.SpecName, cache)
+	if err != nil {
+		return false, err
+	}
+	if spec == nil {
+		return false, nil
+	}
+
+	gpuCount := ComputeGPUCount(spec.GPU, endpoint.GpuCount)
+	if gpuCount == 0 {
+		return false, nil
+	}
+
+	duration := task.CompletedAt.Sub(*task.StartedAt)
+	if duration < 0 {
+		duration = 0
+	}
+
+	gpuType := spec.GPUType
+	if workerGPUType := a.lookupWorkerGPUType(ctx, task.WorkerID, cache); workerGPUType != "" {
+		gpuType = workerGPUType
+	}
+
+	record := &model.GPUUsageRecord{
+		TaskID:          task.TaskID,
+		Endpoint:        task.Endpoint,
+		WorkerID:        task.WorkerID,
+		SpecName:        endpoint.SpecName,
+		GPUCount:        gpuCount,
+		GPUType:         gpuType,
+		GPUMemoryGB:     ParseGPUMemoryGB(gpuType),
+		StartedAt:       *task.StartedAt,
+		CompletedAt:     *task.CompletedAt,
+		DurationSeconds: int(duration.Seconds()),
+		GPUHours:        float64(gpuCount) * duration.Hours(),
+		Status:          task.Status,
+	}
+	return a.repo.CreateRecord(ctx, record)
+}
+
+func (a *Aggregator) lookupEndpoint(ctx context.Context, name string, cache *specCache) (*model.Endpoint, error) {
+	if cache != nil {
+		if ep, ok := cache.endpoints[name]; ok {
+			return ep, nil
+		}
+	}
+	ep, err := a.endpointRepo.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endpoint %s: %w", name, err)
+	}
+	if cache != nil {
+		cache.endpoints[name] = ep
+	}
+	return ep, nil
+}
+
+// lookupWorkerGPUType returns the GPU type the worker ran on, empty when it is unknown
+// (no GPU type fallbacks, or the worker record is gone)
+func (a *Aggregator) lookupWorkerGPUType(ctx context.Context, workerID string, cache *specCache) string {
+	if a.workerRepo == nil || workerID == "" {
+		return ""
+	}
+	if cache != nil {
+		if gpuType, ok := cache.gpuTypes[workerID]; ok {
+			return gpuType
+		}
+	}
+	var gpuType string
+	if worker, err := a.workerRepo.Get(ctx, workerID); err == nil && worker != nil {
+		gpuType = worker.GPUType()
+	}
+	if cache != nil {
+		cache.gpuTypes[workerID] = gpuType
+	}
+	return gpuType
+}
+
+func (a *Aggregator) lookupSpec(ctx context.Context, name string, cache *specCache) (*model.Spec, error) {
+	if cache != nil {
+		if spec, ok := cache.specs[name]; ok {
+			return spec, nil
+		}
+	}
+	spec, err := a.specRepo.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spec %s: %w", name, err)
+	}
+	if cache != nil {
+		cache.specs[name] = spec
+	}
+	return spec, nil
+}
+
+// AggregatePending advances the minute, hourly and daily watermarks up to the latest complete bucket.
+// Intended to be called periodically by a single leader.
+func (a *Aggregator) AggregatePending(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	minuteTarget := now.Truncate(time.Minute).Add(-a.config.SettleDelay)
+	minuteWatermark, err := a.advance(ctx, model.GPUUsageGranularityMinute, minuteTarget, time.Minute,
+		a.repo.GetDistinctMinuteBuckets, a.aggregateMinute)
+	if err != nil {
+		return err
+	}
+
+	if lag := minuteTarget.Sub(minuteWatermark); lag > a.config.LagWarnThreshold {
+		logger.WarnCtx(ctx, "gpu usage minute aggregation is lagging by %v (watermark: %s)", lag, minuteWatermark.Format(time.RFC3339))
+	}
+
+	// Hours are only rolled up once all of their minutes are aggregated
+	hourWatermark, err := a.advance(ctx, model.GPUUsageGranularityHourly, truncateHour(minuteWatermark), time.Hour,
+		a.repo.GetDistinctHourBuckets, a.aggregateHour)
+	if err != nil {
+		return err
+	}
+
+	dayTarget := truncateDay(hourWatermark)
+	prevDay, _ := a.repo.GetAggregationState(ctx, model.GPUUsageGranularityDaily)
+	dayWatermark, err := a.advance(ctx, model.GPUUsageGranularityDaily, dayTarget, 24*time.Hour,
+		a.repo.GetDistinctDayBuckets, a.aggregateDay)
+	if err != nil {
+		return err
+	}
+
+	// Retention cleanup once per day, after the daily rollup moved forward
+	if prevDay != nil && dayWatermark.After(prevDay.Watermark) {
+		a.cleanup(ctx, now)
+	}
+	return nil
+}
+
+// advance moves the watermark of a granularity towards target, aggregating every bucket that has data.
+// Returns the (possibly unchanged) watermark.
+func (a *Aggregator) advance(
+	ctx context.Context,
+	granularity string,
+	target time.Time,
+	bucketSize time.Duration,
+	listBuckets func(ctx context.Context, from, to time.Time) ([]time.Time, error),
+	aggregate func(ctx context.Context, bucket time.Time) error,
+) (time.Time, error) {
+	state, err := a.repo.GetAggregationState(ctx, granularity)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if state == nil {
+		state = &model.GPUUsageAggregationState{
+			Granularity: granularity,
+			Watermark:   target.Add(-a.config.InitialLookback).Truncate(bucketSize),
+		}
+	}
+
+	watermark := state.Watermark.UTC()
+	if !watermark.Before(target) {
+		return watermark, nil
+	}
+
+	to := target
+	if a.config.MaxCatchUpWindow > 0 && to.Sub(watermark) > a.config.MaxCatchUpWindow {
+		to = watermark.Add(a.config.MaxCatchUpWindow).Truncate(bucketSize)
+		if !to.After(watermark) {
+			to = watermark.Add(bucketSize)
+		}
+	}
+
+	start := time.Now()
+	buckets, err := listBuckets(ctx, watermark, to)
+	if err == nil {
+		for _, bucket := range buckets {
+			if err = aggregate(ctx, bucket); err != nil {
+				break
+			}
+		}
+	}
+
+	runAt := time.Now()
+	state.LastRunAt = &runAt
+	state.LastDurationMs = time.Since(start).Milliseconds()
+	state.LastBuckets = len(buckets)
+	if err != nil {
+		state.LastError = truncateError(err)
+	} else {
+		state.LastError = ""
+		state.Watermark = to
+	}
+	if saveErr := a.repo.SaveAggregationState(ctx, state); saveErr != nil {
+		logger.ErrorCtx(ctx, "failed to save gpu usage %s aggregation state: %v", granularity, saveErr)
+	}
+	if err != nil {
+		return watermark, fmt.Errorf("gpu usage %s aggregation failed: %w", granularity, err)
+	}
+
+	logger.DebugCtx(ctx, "gpu usage %s aggregation advanced to %s (%d buckets)", granularity, to.Format(time.RFC3339), len(buckets))
+	return to, nil
+}
+
+func (a *Aggregator) aggregateMinute(ctx context.Context, bucket time.Time) error {
+	records, err := a.repo.ListRecords(ctx, bucket, bucket.Add(time.Minute))
+	if err != nil {
+		return err
+	}
+	return a.repo.UpsertMinuteStats(ctx, AggregateRecords(bucket, records))
+}
+
+func (a *Aggregator) aggregateHour(ctx context.Context, hour time.Time) error {
+	minutes, err := a.repo.ListMinuteStatsInRange(ctx, hour, hour.Add(time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to list minute stats: %w", err)
+	}
+	return a.repo.UpsertHourlyStats(ctx, RollupMinuteStats(hour, minutes))
+}
+
+func (a *Aggregator) aggregateDay(ctx context.Context, day time.Time) error {
+	hours, err := a.repo.ListHourlyStatsInRange(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("failed to list hourly stats: %w", err)
+	}
+	return a.repo.UpsertDailyStats(ctx, RollupHourlyStats(day, hours))
+}
+
+func (a *Aggregator) cleanup(ctx context.Context, now time.Time) {
+	if a.config.MinuteRetention > 0 {
+		if rows, err := a.repo.CleanupOldMinuteStats(ctx, now.Add(-a.config.MinuteRetention)); err != nil {
+			logger.WarnCtx(ctx, "failed to cleanup gpu usage minute stats: %v", err)
+		} else if rows > 0 {
+			logger.InfoCtx(ctx, "cleaned up %d old gpu usage minute stats", rows)
+		}
+	}
+	if a.config.HourlyRetention > 0 {
+		if rows, err := a.repo.CleanupOldHourlyStats(ctx, now.Add(-a.config.HourlyRetention)); err != nil {
+			logger.WarnCtx(ctx, "failed to cleanup gpu usage hourly stats: %v", err)
+		} else if rows > 0 {
+			logger.InfoCtx(ctx, "cleaned up %d old gpu usage hourly stats", rows)
+		}
+	}
+}
+
+// Reaggregate recomputes statistics for every bucket with data in [from, to).
+// granularity selects the level to start from; lower levels cascade upwards
+// ("minute" also rebuilds the hours and days it touches, "hourly" also rebuilds days).
+func (a *Aggregator) Reaggregate(ctx context.Context, granularity string, from, to time.Time) (*ReaggregateResult, error) {
+	from, to = from.UTC(), to.UTC()
+	result := &ReaggregateResult{}
+
+	var hours []time.Time
+	switch granularity {
+	case "", model.GPUUsageGranularityMinute:
+		minutes, err := a.repo.GetDistinctMinuteBuckets(ctx, from, to)
+		if err != nil {
+			return result, err
+		}
+		for _, bucket := range minutes {
+			if err := a.aggregateMinute(ctx, bucket); err != nil {
+				return result, err
+			}
+		}
+		result.MinuteBuckets = len(minutes)
+		hours = uniqueTruncated(minutes, truncateHour)
+	case model.GPUUsageGranularityHourly:
+		var err error
+		if hours, err = a.repo.GetDistinctHourBuckets(ctx, truncateHour(from), to); err != nil {
+			return result, err
+		}
+	case model.GPUUsageGranularityDaily:
+	default:
+		return result, fmt.Errorf("invalid granularity: %s", granularity)
+	}
+
+	var days []time.Time
+	if granularity == model.GPUUsageGranularityDaily {
+		var err error
+		if days, err = a.repo.GetDistinctDayBuckets(ctx, truncateDay(from), to); err != nil {
+			return result, err
+		}
+	} else {
+		for _, hour := range hours {
+			if err := a.aggregateHour(ctx, hour); err != nil {
+				return result, err
+			}
+		}
+		result.HourBuckets = len(hours)
+		days = uniqueTruncated(hours, truncateDay)
+	}
+
+	for _, day := range days {
+		if err := a.aggregateDay(ctx, day); err != nil {
+			return result, err
+		}
+	}
+	result.DayBuckets = len(days)
+	return result, nil
+}
+
+// Backfill creates missing GPU usage records for tasks finished in [from, to)
+// and re-aggregates every affected bucket. Only one backfill runs at a time per instance.
+func (a *Aggregator) Backfill(ctx context.Context, from, to time.Time, batchSize, maxTasks int) (*BackfillResult, error) {
+	if !a.backfillRunning.CompareAndSwap(false, true) {
+		return nil, ErrBackfillInProgress
+	}
+	defer a.backfillRunning.Store(false)
+
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	start := time.Now()
+	result := &BackfillResult{From: from, To: to, StartTime: start}
+	cache := newSpecCache()
+
+	var afterID int64
+	for maxTasks <= 0 || result.TotalTasksProcessed < maxTasks {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		limit := batchSize
+		if maxTasks > 0 && maxTasks-result.TotalTasksProcessed < limit {
+			limit = maxTasks - result.TotalTasksProcessed
+		}
+
+		tasks, err := a.repo.GetTasksWithoutGPURecords(ctx, from, to, afterID, limit)
+		if err != nil {
+			return result, err
+		}
+
+		for _, task := range tasks {
+			afterID = task.ID
+			result.TotalTasksProcessed++
+			created, err := a.recordTask(ctx, task, cache)
+			switch {
+			case err != nil:
+				result.addError(fmt.Sprintf("task %s: %v", task.TaskID, err))
+			case created:
+				result.RecordsCreated++
+			default:
+				result.RecordsSkipped++
+			}
+		}
+
+		if len(tasks) < limit {
+			break
+		}
+	}
+
+	agg, err := a.Reaggregate(ctx, model.GPUUsageGranularityMinute, from, to)
+	result.ReaggregateResult = *agg
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(start).String()
+	if err != nil {
+		return result, fmt.Errorf("backfill re-aggregation failed: %w", err)
+	}
+
+	logger.InfoCtx(ctx, "gpu usage backfill finished: from=%s, to=%s, processed=%d, created=%d, skipped=%d, errors=%d, duration=%s",
+		from.Format(time.RFC3339), to.Format(time.RFC3339), result.TotalTasksProcessed, result.RecordsCreated,
+		result.RecordsSkipped, len(result.Errors), result.Duration)
+	return result, nil
+}
+
+// Status reports the aggregation watermark and lag of every granularity
+func (a *Aggregator) Status(ctx context.Context) (*AggregationStatus, error) {
+	states, err := a.repo.ListAggregationStates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aggregation states: %w", err)
+	}
+
+	now := time.Now().UTC()
+	targets := map[string]time.Time{
+		model.GPUUsageGranularityMinute: now.Truncate(time.Minute).Add(-a.config.SettleDelay),
+		model.GPUUsageGranularityHourly: truncateHour(now),
+		model.GPUUsageGranularityDaily:  truncateDay(now),
+	}
+
+	status := &AggregationStatus{
+		BackfillRunning: a.backfillRunning.Load(),
+		Granularities:   make([]GranularityStatus, 0, len(states)),
+	}
+	for _, s := range states {
+		gs := GranularityStatus{
+			Granularity:    s.Granularity,
+			Watermark:      s.Watermark,
+			LastRunAt:      s.LastRunAt,
+			LastDurationMs: s.LastDurationMs,
+			LastBuckets:    s.LastBuckets,
+			LastError:      s.LastError,
+		}
+		if target, ok := targets[s.Granularity]; ok && target.After(s.Watermark) {
+			gs.LagSeconds = int64(target.Sub(s.Watermark.UTC()).Seconds())
+		}
+		if s.Granularity == model.GPUUsageGranularityMinute {
+			if pending, err := a.repo.CountRecordsSince(ctx, s.Watermark); err == nil {
+				status.PendingRecords = pending
+			}
+		}
+		status.Granularities = append(status.Granularities, gs)
+	}
+	return status, nil
+}
+
+func truncateError(err error) string {
+	msg := err.Error()
+	if len(msg) > 1024 {
+		msg = msg[:1024]
+	}
+	return msg
+}
@@ -0,0 +1,340 @@
+package gpuusage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
+)
+
+// Backfill job defaults
+const (
+	DefaultBackfillJobBatchSize = 500
+	DefaultBackfillJobRateLimit = 200 // Tasks per second
+	MaxBackfillJobBatchSize     = 5000
+)
+
+// ErrBackfillJobActive is returned when a backfill job is created while another one is pending or running
+var ErrBackfillJobActive = errors.New("a gpu usage backfill job is already pending or running")
+
+// BackfillReport is the consistency report written when a backfill job finishes. Records are
+// compared with the finished tasks of the range and with the daily statistics of the whole UTC
+// days the range touches.
+type BackfillReport struct {
+	FinishedTasks       int64     `json:"finished_tasks"`        // Finished tasks with a run time in the range
+	Records             int64     `json:"records"`               // GPU usage records in the range
+	RecordGPUHours      float64   `json:"record_gpu_hours"`      // GPU hours of those records
+	TasksWithoutRecords int64     `json:"tasks_without_records"` // CPU-only specs, deleted endpoints and failed tasks
+	DaysFrom            time.Time `json:"days_from"`
+	DaysTo              time.Time `json:"days_to"`
+	DayRecordGPUHours   float64   `json:"day_record_gpu_hours"` // GPU hours of the records in [days_from, days_to)
+	DailyStatsGPUHours  float64   `json:"daily_stats_gpu_hours"`
+	GPUHoursDelta       float64   `json:"gpu_hours_delta"` // daily_stats_gpu_hours - day_record_gpu_hours
+	Errors              int64     `json:"errors"`
+	Consistent          bool      `json:"consistent"`
+	GeneratedAt         time.Time `json:"generated_at"`
+}
+
+// BackfillJobView is a backfill job with its progress
+type BackfillJobView struct {
+	*model.GPUUsageBackfillJob
+	TotalDays          int     `json:"total_days"`
+	RecordsPercent     float64 `json:"records_percent"`
+	ReaggregatePercent float64 `json:"reaggregate_percent"`
+}
+
+// NewBackfillJobView computes the progress of a backfill job
+func NewBackfillJobView(job *model.GPUUsageBackfillJob) *BackfillJobView {
+	view := &BackfillJobView{GPUUsageBackfillJob: job, TotalDays: backfillDays(job.FromTime, job.ToTime)}
+	switch {
+	case job.Phase != model.GPUUsageBackfillPhaseRecords:
+		view.RecordsPercent = 100
+	case job.TotalTasks > 0:
+		// Tasks finishing after creation can push processed above the initial total
+		view.RecordsPercent = math.Min(99, float64(job.TasksProcessed)*100/float64(job.TotalTasks))
+	}
+	switch {
+	case job.Phase == model.GPUUsageBackfillPhaseReport:
+		view.ReaggregatePercent = 100
+	case job.Phase == model.GPUUsageBackfillPhaseReaggregate && view.TotalDays > 0:
+		view.ReaggregatePercent = float64(job.DaysReaggregated) * 100 / float64(view.TotalDays)
+	}
+	if job.Status == model.GPUUsageBackfillCompleted {
+		view.RecordsPercent, view.ReaggregatePercent = 100, 100
+	}
+	return view
+}
+
+// CreateBackfillJob validates and stores a new backfill job for tasks finished in [from, to).
+// The job is executed in slices by RunBackfillJob.
+func (a *Aggregator) CreateBackfillJob(ctx context.Context, from, to time.Time, batchSize, rateLimit int, createdBy string) (*model.GPUUsageBackfillJob, error) {
+	from, to = from.UTC(), to.UTC()
+	if !from.Before(to) {
+		return nil, fmt.Errorf("start_time must be before end_time")
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBackfillJobBatchSize
+	}
+	if batchSize > MaxBackfillJobBatchSize {
+		return nil, fmt.Errorf("batch_size must not exceed %d", MaxBackfillJobBatchSize)
+	}
+	if rateLimit < 0 {
+		return nil, fmt.Errorf("rate_limit must not be negative")
+	}
+
+	active, err := a.repo.GetActiveBackfillJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, fmt.Errorf("%w (job %d)", ErrBackfillJobActive, active.ID)
+	}
+
+	total, err := a.repo.CountTasksWithoutGPURecords(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &model.GPUUsageBackfillJob{
+		Status:     model.GPUUsageBackfillPending,
+		Phase:      model.GPUUsageBackfillPhaseRecords,
+		FromTime:   from,
+		ToTime:     to,
+		BatchSize:  batchSize,
+		RateLimit:  rateLimit,
+		TotalTasks: total,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := a.repo.CreateBackfillJob(ctx, job); err != nil {
+		return nil, err
+	}
+	logger.InfoCtx(ctx, "gpu usage backfill job %d created: from=%s, to=%s, tasks=%d, batchSize=%d, rateLimit=%d/s, createdBy=%s",
+		job.ID, from.Format(time.RFC3339), to.Format(time.RFC3339), total, batchSize, rateLimit, createdBy)
+	return job, nil
+}
+
+// RunBackfillJob advances the active backfill job for at most budget. Progress is saved after
+// every batch, so the next run (on any replica) continues from the stored cursor. Returns the
+// job as of the end of the slice, or nil when no job is active.
+func (a *Aggregator) RunBackfillJob(ctx context.Context, budget time.Duration) (*model.GPUUsageBackfillJob, error) {
+	if !a.backfillRunning.CompareAndSwap(false, true) {
+		return nil, ErrBackfillInProgress
+	}
+	defer a.backfillRunning.Store(false)
+
+	job, err := a.repo.GetActiveBackfillJob(ctx)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	if job.Status == model.GPUUsageBackfillPending {
+		now := time.Now()
+		job.Status = model.GPUUsageBackfillRunning
+		job.StartedAt = &now
+	}
+
+	deadline := time.Now().Add(budget)
+	cache := newSpecCache()
+	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return job, err
+		}
+
+		var processed int
+		var stepErr error
+		switch job.Phase {
+		case model.GPUUsageBackfillPhaseRecords:
+			processed, stepErr = a.backfillRecordsBatch(ctx, job, cache)
+		case model.GPUUsageBackfillPhaseReaggregate:
+			stepErr = a.backfillReaggregateDay(ctx, job)
+		default:
+			stepErr = a.backfillReport(ctx, job)
+		}
+		if stepErr != nil {
+			// Transient failures are retried by the next run from the saved cursor
+			job.LastError = truncateError(stepErr)
+		}
+
+		saved, err := a.repo.SaveBackfillJobProgress(ctx, job)
+		if err != nil {
+			return job, err
+		}
+		if !saved {
+			logger.InfoCtx(ctx, "gpu usage backfill job %d was cancelled, stopping", job.ID)
+			return job, nil
+		}
+		if stepErr != nil {
+			return job, fmt.Errorf("gpu usage backfill job %d: %w", job.ID, stepErr)
+		}
+		if job.Status == model.GPUUsageBackfillCompleted {
+			return job, nil
+		}
+
+		if !sleepUntil(ctx, throttleDelay(processed, job.RateLimit), deadline) {
+			break
+		}
+	}
+	return job, nil
+}
+
+// backfillRecordsBatch creates the records of the next batch of tasks and moves to the
+// reaggregate phase once no task is left
+func (a *Aggregator) backfillRecordsBatch(ctx context.Context, job *model.GPUUsageBackfillJob, cache *specCache) (int, error) {
+	tasks, err := a.repo.GetTasksWithoutGPURecords(ctx, job.FromTime, job.ToTime, job.CursorTaskID, job.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, task := range tasks {
+		job.CursorTaskID = task.ID
+		job.TasksProcessed++
+		created, err := a.recordTask(ctx, task, cache)
+		switch {
+		case err != nil:
+			job.ErrorCount++
+			if len(job.Errors) < maxBackfillErrors {
+				job.Errors = append(job.Errors, fmt.Sprintf("task %s: %v", task.TaskID, err))
+			}
+		case created:
+			job.RecordsCreated++
+		default:
+			job.RecordsSkipped++
+		}
+	}
+
+	if len(tasks) < job.BatchSize {
+		day := truncateDay(job.FromTime)
+		job.Phase = model.GPUUsageBackfillPhaseReaggregate
+		job.CursorDay = &day
+		logger.InfoCtx(ctx, "gpu usage backfill job %d: records done (processed=%d, created=%d, skipped=%d, errors=%d), re-aggregating",
+			job.ID, job.TasksProcessed, job.RecordsCreated, job.RecordsSkipped, job.ErrorCount)
+	}
+	return len(tasks), nil
+}
+
+// backfillReaggregateDay rebuilds the statistics of the next day of the range
+func (a *Aggregator) backfillReaggregateDay(ctx context.Context, job *model.GPUUsageBackfillJob) error {
+	if job.CursorDay == nil || !job.CursorDay.Before(job.ToTime) {
+		job.Phase = model.GPUUsageBackfillPhaseReport
+		return nil
+	}
+
+	day := job.CursorDay.UTC()
+	from, to := day, day.AddDate(0, 0, 1)
+	if from.Before(job.FromTime) {
+		from = job.FromTime
+	}
+	if to.After(job.ToTime) {
+		to = job.ToTime
+	}
+	if _, err := a.Reaggregate(ctx, model.GPUUsageGranularityMinute, from, to); err != nil {
+		return fmt.Errorf("failed to re-aggregate %s: %w", day.Format("2006-01-02"), err)
+	}
+
+	next := day.AddDate(0, 0, 1)
+	job.CursorDay = &next
+	job.DaysReaggregated++
+	return nil
+}
+
+// backfillReport builds the consistency report and completes the job
+func (a *Aggregator) backfillReport(ctx context.Context, job *model.GPUUsageBackfillJob) error {
+	report := &BackfillReport{Errors: job.ErrorCount, GeneratedAt: time.Now()}
+
+	var err error
+	if report.FinishedTasks, err = a.repo.CountFinishedTasks(ctx, job.FromTime, job.ToTime); err != nil {
+		return err
+	}
+	if report.Records, report.RecordGPUHours, err = a.repo.SumRecords(ctx, job.FromTime, job.ToTime); err != nil {
+		return err
+	}
+	if report.TasksWithoutRecords, err = a.repo.CountTasksWithoutGPURecords(ctx, job.FromTime, job.ToTime); err != nil {
+		return err
+	}
+
+	report.DaysFrom, report.DaysTo = backfillDayRange(job.FromTime, job.ToTime)
+	if _, report.DayRecordGPUHours, err = a.repo.SumRecords(ctx, report.DaysFrom, report.DaysTo); err != nil {
+		return err
+	}
+	daily, err := a.repo.GetDailyStats(ctx, model.GPUUsageScopeGlobal, "", report.DaysFrom, report.DaysTo)
+	if err != nil {
+		return fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	for _, st := range daily {
+		report.DailyStatsGPUHours += st.TotalGPUHours
+	}
+	report.GPUHoursDelta = report.DailyStatsGPUHours - report.DayRecordGPUHours
+	report.Consistent = report.Errors == 0 && gpuHoursMatch(report.DailyStatsGPUHours, report.DayRecordGPUHours, report.Records)
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode backfill report: %w", err)
+	}
+	if err := json.Unmarshal(data, &job.Report); err != nil {
+		return fmt.Errorf("failed to encode backfill report: %w", err)
+	}
+
+	now := time.Now()
+	job.Status = model.GPUUsageBackfillCompleted
+	job.CompletedAt = &now
+	job.LastError = ""
+	logger.InfoCtx(ctx, "gpu usage backfill job %d completed: processed=%d, created=%d, records=%d/%d tasks, days=%d, consistent=%v (delta %.4f GPU hours)",
+		job.ID, job.TasksProcessed, job.RecordsCreated, report.Records, report.FinishedTasks, job.DaysReaggregated,
+		report.Consistent, report.GPUHoursDelta)
+	return nil
+}
+
+// throttleDelay is the pause after a batch of tasks that keeps the job at rateLimit tasks per second
+func throttleDelay(tasks, rateLimit int) time.Duration {
+	if tasks <= 0 || rateLimit <= 0 {
+		return 0
+	}
+	return time.Duration(tasks) * time.Second / time.Duration(rateLimit)
+}
+
+// sleepUntil waits for d, returning false if the wait would pass the deadline or ctx is done
+func sleepUntil(ctx context.Context, d time.Duration, deadline time.Time) bool {
+	if !time.Now().Add(d).Before(deadline) {
+		return false
+	}
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// backfillDayRange returns the whole UTC days covering [from, to)
+func backfillDayRange(from, to time.Time) (time.Time, time.Time) {
+	dayTo := truncateDay(to)
+	if dayTo.Before(to) {
+		dayTo = dayTo.AddDate(0, 0, 1)
+	}
+	return truncateDay(from), dayTo
+}
+
+// backfillDays returns the number of UTC days [from, to) touches
+func backfillDays(from, to time.Time) int {
+	dayFrom, dayTo := backfillDayRange(from, to)
+	return int(dayTo.Sub(dayFrom) / (24 * time.Hour))
+}
+
+// gpuHoursMatch reports whether the daily statistics agree with the records, allowing for the
+// decimal(12,4) rounding of every stored bucket
+func gpuHoursMatch(statsHours, recordHours float64, records int64) bool {
+	tolerance := 0.01 + 0.0001*float64(records)
+	return math.Abs(statsHours-recordHours) <= tolerance
+}
@@ -0,0 +1,79 @@
+package gpuusage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestThrottleDelay tests the pause that keeps a backfill job at its rate limit.
+func TestThrottleDelay(t *testing.T) {
+	assert.Equal(t, 2500*time.Millisecond, throttleDelay(500, 200))
+	assert.Equal(t, time.Duration(0), throttleDelay(500, 0), "0 = unthrottled")
+	assert.Equal(t, time.Duration(0), throttleDelay(0, 200))
+}
+
+// TestSleepUntil tests that throttling never runs past the slice deadline.
+func TestSleepUntil(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, sleepUntil(ctx, 0, time.Now().Add(time.Second)))
+	assert.True(t, sleepUntil(ctx, time.Millisecond, time.Now().Add(time.Second)))
+	assert.False(t, sleepUntil(ctx, time.Minute, time.Now().Add(time.Second)))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, sleepUntil(cancelled, 100*time.Millisecond, time.Now().Add(time.Second)))
+}
+
+// TestBackfillDayRange tests the whole UTC days used by the consistency report.
+func TestBackfillDayRange(t *testing.T) {
+	from := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	dayFrom, dayTo := backfillDayRange(from, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), dayFrom)
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), dayTo)
+
+	_, dayTo = backfillDayRange(from, time.Date(2026, 3, 3, 0, 1, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), dayTo)
+	assert.Equal(t, 3, backfillDays(from, time.Date(2026, 3, 3, 0, 1, 0, 0, time.UTC)))
+}
+
+// TestNewBackfillJobView tests progress reporting across backfill phases.
+func TestNewBackfillJobView(t *testing.T) {
+	job := &model.GPUUsageBackfillJob{
+		Status:         model.GPUUsageBackfillRunning,
+		Phase:          model.GPUUsageBackfillPhaseRecords,
+		FromTime:       time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		ToTime:         time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC),
+		TotalTasks:     1000,
+		TasksProcessed: 250,
+	}
+	view := NewBackfillJobView(job)
+	assert.Equal(t, 10, view.TotalDays)
+	assert.Equal(t, 25.0, view.RecordsPercent)
+	assert.Equal(t, 0.0, view.ReaggregatePercent)
+
+	job.TasksProcessed = 1200 // Tasks finished after the job was created
+	assert.Equal(t, 99.0, NewBackfillJobView(job).RecordsPercent)
+
+	job.Phase = model.GPUUsageBackfillPhaseReaggregate
+	job.DaysReaggregated = 4
+	view = NewBackfillJobView(job)
+	assert.Equal(t, 100.0, view.RecordsPercent)
+	assert.Equal(t, 40.0, view.ReaggregatePercent)
+
+	job.Phase = model.GPUUsageBackfillPhaseReport
+	job.Status = model.GPUUsageBackfillCompleted
+	view = NewBackfillJobView(job)
+	assert.Equal(t, 100.0, view.ReaggregatePercent)
+}
+
+// TestGPUHoursMatch tests the rounding tolerance of the consistency check.
+func TestGPUHoursMatch(t *testing.T) {
+	assert.True(t, gpuHoursMatch(120.0042, 120, 10))
+	assert.True(t, gpuHoursMatch(1000.3, 1000, 5000))
+	assert.False(t, gpuHoursMatch(118, 120, 10))
+}
@@ -0,0 +1,246 @@
+package gpuusage
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+// gpuMemoryPattern extracts memory size from GPU type names like "H200-80GB" or "A100 40GB"
+var gpuMemoryPattern = regexp.MustCompile(`(?i)(\d+)\s*GB`)
+
+// ParseGPUMemoryGB extracts GPU memory (GB) from a GPU type string, returns 0 if not present
+func ParseGPUMemoryGB(gpuType string) int {
+	match := gpuMemoryPattern.FindStringSubmatch(gpuType)
+	if len(match) < 2 {
+		return 0
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// ComputeGPUCount returns the number of GPUs allocated to a single worker.
+// specGPU is the per-unit GPU count from the spec (e.g. "1"), endpointGPUCount is the
+// endpoint multiplier (resources = per-gpu-config * gpuCount). Returns 0 for CPU-only specs.
+func ComputeGPUCount(specGPU string, endpointGPUCount int) int {
+	perUnit, err := strconv.Atoi(strings.TrimSpace(specGPU))
+	if err != nil || perUnit <= 0 {
+		return 0
+	}
+	if endpointGPUCount <= 0 {
+		endpointGPUCount = 1
+	}
+	return perUnit * endpointGPUCount
+}
+
+// scopeKey identifies one aggregation row inside a bucket
+type scopeKey struct {
+	scopeType  string
+	scopeValue string
+}
+
+// recordScopes returns the scopes a record contributes to
+func recordScopes(r *model.GPUUsageRecord) []scopeKey {
+	scopes := []scopeKey{
+		{model.GPUUsageScopeGlobal, model.GPUUsageScopeGlobal},
+		{model.GPUUsageScopeEndpoint, r.Endpoint},
+	}
+	if r.SpecName != "" {
+		scopes = append(scopes, scopeKey{model.GPUUsageScopeSpec, r.SpecName})
+	}
+	if r.WorkerID != "" {
+		scopes = append(scopes, scopeKey{model.GPUUsageScopeWorker, r.WorkerID})
+	}
+	if r.GPUType != "" {
+		scopes = append(scopes, scopeKey{model.GPUUsageScopeGPUType, r.GPUType})
+	}
+	return scopes
+}
+
+// sortedScopeKeys returns map keys in a deterministic order
+func sortedScopeKeys[T any](m map[scopeKey]T) []scopeKey {
+	keys := make([]scopeKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].scopeType != keys[j].scopeType {
+			return keys[i].scopeType < keys[j].scopeType
+		}
+		return keys[i].scopeValue < keys[j].scopeValue
+	})
+	return keys
+}
+
+// AggregateRecords builds minute statistics for a bucket from the raw records completed within it
+func AggregateRecords(bucket time.Time, records []*model.GPUUsageRecord) []*model.GPUUsageStatisticsMinute {
+	now := time.Now()
+	acc := make(map[scopeKey]*model.GPUUsageStatisticsMinute)
+	gpuSum := make(map[scopeKey]int)
+
+	for _, r := range records {
+		for _, key := range recordScopes(r) {
+			stat, ok := acc[key]
+			if !ok {
+				stat = &model.GPUUsageStatisticsMinute{
+					TimeBucket:  bucket,
+					ScopeType:   key.scopeType,
+					ScopeValue:  key.scopeValue,
+					PeriodStart: bucket,
+					PeriodEnd:   bucket.Add(time.Minute),
+					UpdatedAt:   now,
+				}
+				acc[key] = stat
+			}
+			stat.TotalTasks++
+			switch r.Status {
+			case "COMPLETED":
+				stat.CompletedTasks++
+			case "FAILED":
+				stat.FailedTasks++
+			}
+			stat.TotalGPUSeconds += int64(r.DurationSeconds) * int64(r.GPUCount)
+			stat.TotalGPUHours += r.GPUHours
+			gpuSum[key] += r.GPUCount
+			if r.GPUCount > stat.MaxGPUCount {
+				stat.MaxGPUCount = r.GPUCount
+			}
+		}
+	}
+
+	result := make([]*model.GPUUsageStatisticsMinute, 0, len(acc))
+	for _, key := range sortedScopeKeys(acc) {
+		stat := acc[key]
+		stat.AvgGPUCount = float64(gpuSum[key]) / float64(stat.TotalTasks)
+		result = append(result, stat)
+	}
+	return result
+}
+
+// RollupMinuteStats rolls minute statistics up into hourly statistics for the given hour
+func RollupMinuteStats(hour time.Time, minutes []*model.GPUUsageStatisticsMinute) []*model.GPUUsageStatisticsHourly {
+	now := time.Now()
+	acc := make(map[scopeKey]*model.GPUUsageStatisticsHourly)
+	weightedGPU := make(map[scopeKey]float64)
+
+	for _, m := range minutes {
+		key := scopeKey{m.ScopeType, m.ScopeValue}
+		stat, ok := acc[key]
+		if !ok {
+			stat = &model.GPUUsageStatisticsHourly{
+				TimeBucket:  hour,
+				ScopeType:   m.ScopeType,
+				ScopeValue:  m.ScopeValue,
+				PeriodStart: hour,
+				PeriodEnd:   hour.Add(time.Hour),
+				UpdatedAt:   now,
+			}
+			acc[key] = stat
+		}
+		stat.TotalTasks += m.TotalTasks
+		stat.CompletedTasks += m.CompletedTasks
+		stat.FailedTasks += m.FailedTasks
+		stat.TotalGPUSeconds += m.TotalGPUSeconds
+		stat.TotalGPUHours += m.TotalGPUHours
+		weightedGPU[key] += m.AvgGPUCount * float64(m.TotalTasks)
+		if m.MaxGPUCount > stat.MaxGPUCount {
+			stat.MaxGPUCount = m.MaxGPUCount
+		}
+		if stat.PeakMinute == nil || m.TotalGPUHours > stat.PeakGPUHours {
+			peak := m.TimeBucket
+			stat.PeakMinute = &peak
+			stat.PeakGPUHours = m.TotalGPUHours
+		}
+	}
+
+	result := make([]*model.GPUUsageStatisticsHourly, 0, len(acc))
+	for _, key := range sortedScopeKeys(acc) {
+		stat := acc[key]
+		if stat.TotalTasks > 0 {
+			stat.AvgGPUCount = weightedGPU[key] / float64(stat.TotalTasks)
+		}
+		result = append(result, stat)
+	}
+	return result
+}
+
+// RollupHourlyStats rolls hourly statistics up into daily statistics for the given day
+func RollupHourlyStats(day time.Time, hours []*model.GPUUsageStatisticsHourly) []*model.GPUUsageStatisticsDaily {
+	now := time.Now()
+	acc := make(map[scopeKey]*model.GPUUsageStatisticsDaily)
+	weightedGPU := make(map[scopeKey]float64)
+
+	for _, h := range hours {
+		key := scopeKey{h.ScopeType, h.ScopeValue}
+		stat, ok := acc[key]
+		if !ok {
+			stat = &model.GPUUsageStatisticsDaily{
+				TimeBucket:  day,
+				ScopeType:   h.ScopeType,
+				ScopeValue:  h.ScopeValue,
+				PeriodStart: day,
+				PeriodEnd:   day.AddDate(0, 0, 1),
+				UpdatedAt:   now,
+			}
+			acc[key] = stat
+		}
+		stat.TotalTasks += h.TotalTasks
+		stat.CompletedTasks += h.CompletedTasks
+		stat.FailedTasks += h.FailedTasks
+		stat.TotalGPUSeconds += h.TotalGPUSeconds
+		stat.TotalGPUHours += h.TotalGPUHours
+		weightedGPU[key] += h.AvgGPUCount * float64(h.TotalTasks)
+		if h.MaxGPUCount > stat.MaxGPUCount {
+			stat.MaxGPUCount = h.MaxGPUCount
+		}
+		if stat.PeakHour == nil || h.TotalGPUHours > stat.PeakGPUHours {
+			peak := h.TimeBucket
+			stat.PeakHour = &peak
+			stat.PeakGPUHours = h.TotalGPUHours
+		}
+	}
+
+	result := make([]*model.GPUUsageStatisticsDaily, 0, len(acc))
+	for _, key := range sortedScopeKeys(acc) {
+		stat := acc[key]
+		if stat.TotalTasks > 0 {
+			stat.AvgGPUCount = weightedGPU[key] / float64(stat.TotalTasks)
+		}
+		result = append(result, stat)
+	}
+	return result
+}
+
+// uniqueTruncated truncates each time with fn and returns the sorted distinct results
+func uniqueTruncated(times []time.Time, fn func(time.Time) time.Time) []time.Time {
+	seen := make(map[int64]bool)
+	result := make([]time.Time, 0, len(times))
+	for _, t := range times {
+		b := fn(t)
+		if seen[b.Unix()] {
+			continue
+		}
+		seen[b.Unix()] = true
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+	return result
+}
+
+// truncateHour truncates a time to the start of its UTC hour
+func truncateHour(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
+
+// truncateDay truncates a time to the start of its UTC day
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,171 @@
+package gpuusage
+
+import (
+	"testing"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseGPUMemoryGB tests GPU memory extraction from GPU type names.
+func TestParseGPUMemoryGB(t *testing.T) {
+	assert.Equal(t, 80, ParseGPUMemoryGB("H200-80GB"))
+	assert.Equal(t, 40, ParseGPUMemoryGB("A100 40gb"))
+	assert.Equal(t, 0, ParseGPUMemoryGB("RTX4090"))
+	assert.Equal(t, 0, ParseGPUMemoryGB(""))
+}
+
+// TestComputeGPUCount tests per-worker GPU count computation.
+func TestComputeGPUCount(t *testing.T) {
+	assert.Equal(t, 1, ComputeGPUCount("1", 0))
+	assert.Equal(t, 4, ComputeGPUCount("2", 2))
+	assert.Equal(t, 0, ComputeGPUCount("", 4))
+	assert.Equal(t, 0, ComputeGPUCount("0", 1))
+}
+
+// TestAggregateRecords tests minute aggregation across global, endpoint, spec and worker scopes.
+func TestAggregateRecords(t *testing.T) {
+	bucket := time.Date(2026, 1, 1, 10, 5, 0, 0, time.UTC)
+	records := []*model.GPUUsageRecord{
+		{Endpoint: "ep-a", SpecName: "h200", WorkerID: "w1", GPUCount: 1, DurationSeconds: 60, GPUHours: 1.0 / 60, Status: "COMPLETED"},
+		{Endpoint: "ep-a", SpecName: "h200", WorkerID: "w1", GPUCount: 2, DurationSeconds: 30, GPUHours: 1.0 / 60, Status: "FAILED"},
+		{Endpoint: "ep-b", GPUType: "NVIDIA-A100", GPUCount: 4, DurationSeconds: 90, GPUHours: 0.1, Status: "COMPLETED"},
+	}
+
+	stats := AggregateRecords(bucket, records)
+	byScope := make(map[string]*model.GPUUsageStatisticsMinute)
+	for _, s := range stats {
+		byScope[s.ScopeType+"/"+s.ScopeValue] = s
+	}
+	require.Len(t, byScope, 6)
+
+	global := byScope["global/global"]
+	require.NotNil(t, global)
+	assert.Equal(t, 3, global.TotalTasks)
+	assert.Equal(t, 2, global.CompletedTasks)
+	assert.Equal(t, 1, global.FailedTasks)
+	assert.Equal(t, int64(60+60+360), global.TotalGPUSeconds)
+	assert.Equal(t, 4, global.MaxGPUCount)
+	assert.InDelta(t, 7.0/3, global.AvgGPUCount, 1e-9)
+	assert.Equal(t, bucket, global.TimeBucket)
+	assert.Equal(t, bucket.Add(time.Minute), global.PeriodEnd)
+
+	epA := byScope["endpoint/ep-a"]
+	require.NotNil(t, epA)
+	assert.Equal(t, 2, epA.TotalTasks)
+	assert.InDelta(t, 1.5, epA.AvgGPUCount, 1e-9)
+
+	spec := byScope["spec/h200"]
+	require.NotNil(t, spec)
+	assert.Equal(t, 2, spec.TotalTasks)
+
+	worker := byScope["worker/w1"]
+	require.NotNil(t, worker)
+	assert.Equal(t, 2, worker.TotalTasks)
+	assert.Equal(t, 1, worker.FailedTasks)
+
+	gpuType := byScope["gpu_type/NVIDIA-A100"]
+	require.NotNil(t, gpuType)
+	assert.Equal(t, 1, gpuType.TotalTasks)
+	assert.Equal(t, int64(360), gpuType.TotalGPUSeconds)
+
+	assert.Empty(t, AggregateRecords(bucket, nil))
+}
+
+// TestRollupMinuteStats tests hourly rollup with task-weighted average and peak minute.
+func TestRollupMinuteStats(t *testing.T) {
+	hour := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	m1 := hour.Add(5 * time.Minute)
+	m2 := hour.Add(30 * time.Minute)
+	minutes := []*model.GPUUsageStatisticsMinute{
+		{TimeBucket: m1, ScopeType: "global", ScopeValue: "global", TotalTasks: 1, CompletedTasks: 1, TotalGPUHours: 0.5, AvgGPUCount: 1, MaxGPUCount: 1},
+		{TimeBucket: m2, ScopeType: "global", ScopeValue: "global", TotalTasks: 3, CompletedTasks: 2, FailedTasks: 1, TotalGPUHours: 1.5, AvgGPUCount: 3, MaxGPUCount: 4},
+	}
+
+	hourly := RollupMinuteStats(hour, minutes)
+	require.Len(t, hourly, 1)
+	h := hourly[0]
+	assert.Equal(t, 4, h.TotalTasks)
+	assert.Equal(t, 3, h.CompletedTasks)
+	assert.Equal(t, 1, h.FailedTasks)
+	assert.InDelta(t, 2.0, h.TotalGPUHours, 1e-9)
+	assert.InDelta(t, 2.5, h.AvgGPUCount, 1e-9)
+	assert.Equal(t, 4, h.MaxGPUCount)
+	require.NotNil(t, h.PeakMinute)
+	assert.Equal(t, m2, *h.PeakMinute)
+	assert.InDelta(t, 1.5, h.PeakGPUHours, 1e-9)
+	assert.Equal(t, hour.Add(time.Hour), h.PeriodEnd)
+}
+
+// TestRollupHourlyStats tests daily rollup and peak hour selection.
+func TestRollupHourlyStats(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h1 := day.Add(3 * time.Hour)
+	h2 := day.Add(15 * time.Hour)
+	hours := []*model.GPUUsageStatisticsHourly{
+		{TimeBucket: h1, ScopeType: "endpoint", ScopeValue: "ep-a", TotalTasks: 10, TotalGPUHours: 5, AvgGPUCount: 1, MaxGPUCount: 1},
+		{TimeBucket: h2, ScopeType: "endpoint", ScopeValue: "ep-a", TotalTasks: 10, TotalGPUHours: 2, AvgGPUCount: 2, MaxGPUCount: 2},
+	}
+
+	daily := RollupHourlyStats(day, hours)
+	require.Len(t, daily, 1)
+	d := daily[0]
+	assert.Equal(t, 20, d.TotalTasks)
+	assert.InDelta(t, 7.0, d.TotalGPUHours, 1e-9)
+	assert.InDelta(t, 1.5, d.AvgGPUCount, 1e-9)
+	require.NotNil(t, d.PeakHour)
+	assert.Equal(t, h1, *d.PeakHour)
+	assert.Equal(t, day.AddDate(0, 0, 1), d.PeriodEnd)
+}
+
+// TestUniqueTruncated tests bucket de-duplication used when cascading rollups.
+func TestUniqueTruncated(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	minutes := []time.Time{base.Add(65 * time.Minute), base.Add(time.Minute), base.Add(2 * time.Minute)}
+
+	hours := uniqueTruncated(minutes, truncateHour)
+	assert.Equal(t, []time.Time{base, base.Add(time.Hour)}, hours)
+
+	days := uniqueTruncated(hours, truncateDay)
+	assert.Equal(t, []time.Time{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}, days)
+}
+
+// TestRollupHourlyStatsInLocation tests that local-day reports regroup UTC hours at local midnight.
+func TestRollupHourlyStatsInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	// 2026-01-01 15:00 UTC is 23:00 local, 16:00 UTC is 00:00 local on Jan 2
+	h1 := time.Date(2026, 1, 1, 15, 0, 0, 0, time.UTC)
+	h2 := time.Date(2026, 1, 1, 16, 0, 0, 0, time.UTC)
+	hours := []*model.GPUUsageStatisticsHourly{
+		{TimeBucket: h1, ScopeType: "global", ScopeValue: "global", TotalTasks: 1, TotalGPUHours: 1},
+		{TimeBucket: h2, ScopeType: "global", ScopeValue: "global", TotalTasks: 2, TotalGPUHours: 2},
+	}
+
+	daily := RollupHourlyStatsInLocation(hours, loc)
+	require.Len(t, daily, 2)
+	assert.True(t, daily[0].TimeBucket.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, loc)))
+	assert.Equal(t, 1, daily[0].TotalTasks)
+	assert.True(t, daily[1].TimeBucket.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, loc)))
+	assert.Equal(t, 2, daily[1].TotalTasks)
+	assert.True(t, daily[1].PeriodEnd.Equal(time.Date(2026, 1, 3, 0, 0, 0, 0, loc)))
+
+	assert.True(t, IsUTC(time.UTC))
+	assert.False(t, IsUTC(loc))
+}
+
+// TestFirstCompleteLocalDay tests that local-day reports start on the first day whose hours are all kept.
+func TestFirstCompleteLocalDay(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	retention := 90 * 24 * time.Hour
+
+	// Hours before 2026-01-01 06:00 UTC (14:00 local) are gone, so Jan 1 is short
+	now := time.Date(2026, 4, 1, 6, 0, 0, 0, time.UTC)
+	assert.True(t, FirstCompleteLocalDay(now, retention, loc).Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, loc)))
+
+	// Cleaned up exactly at local midnight, so Jan 1 is complete
+	now = time.Date(2026, 3, 31, 16, 0, 0, 0, time.UTC)
+	assert.True(t, FirstCompleteLocalDay(now, retention, loc).Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, loc)))
+}
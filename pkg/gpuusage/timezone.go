@@ -0,0 +1,93 @@
+package gpuusage
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+// ErrBeyondHourlyRetention is returned for local-day reports reaching back to days whose hourly
+// statistics were partly cleaned up already
+var ErrBeyondHourlyRetention = errors.New("hourly statistics of the requested days are no longer kept")
+
+// IsUTC reports whether loc is equivalent to UTC for reporting purposes
+func IsUTC(loc *time.Location) bool {
+	return loc == nil || loc == time.UTC || loc.String() == "UTC"
+}
+
+// StartOfDayIn returns the start of the local day containing t in loc
+func StartOfDayIn(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// FirstCompleteLocalDay returns the start of the first local day in loc whose hourly statistics
+// are all kept when the ones older than retention are cleaned up
+func FirstCompleteLocalDay(now time.Time, retention time.Duration, loc *time.Location) time.Time {
+	horizon := now.Add(-retention)
+	day := StartOfDayIn(horizon, loc)
+	if day.Before(horizon) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// RollupHourlyStatsInLocation rolls UTC hourly statistics up into daily statistics whose
+// boundaries are local days in loc. Each hour is attributed to the local day its start falls in,
+// which is exact for whole-hour UTC offsets only; config.LoadReportingLocation rejects the others.
+func RollupHourlyStatsInLocation(hours []*model.GPUUsageStatisticsHourly, loc *time.Location) []*model.GPUUsageStatisticsDaily {
+	byDay := make(map[time.Time][]*model.GPUUsageStatisticsHourly)
+	for _, h := range hours {
+		day := StartOfDayIn(h.TimeBucket, loc)
+		byDay[day] = append(byDay[day], h)
+	}
+
+	days := make([]time.Time, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	var result []*model.GPUUsageStatisticsDaily
+	for _, day := range days {
+		result = append(result, RollupHourlyStats(day, byDay[day])...)
+	}
+	return result
+}
+
+// LocalizeMinuteStats converts bucket timestamps to loc for presentation
+func LocalizeMinuteStats(stats []*model.GPUUsageStatisticsMinute, loc *time.Location) {
+	for _, s := range stats {
+		s.TimeBucket = s.TimeBucket.In(loc)
+		s.PeriodStart = s.PeriodStart.In(loc)
+		s.PeriodEnd = s.PeriodEnd.In(loc)
+	}
+}
+
+// LocalizeHourlyStats converts bucket timestamps to loc for presentation
+func LocalizeHourlyStats(stats []*model.GPUUsageStatisticsHourly, loc *time.Location) {
+	for _, s := range stats {
+		s.TimeBucket = s.TimeBucket.In(loc)
+		s.PeriodStart = s.PeriodStart.In(loc)
+		s.PeriodEnd = s.PeriodEnd.In(loc)
+		if s.PeakMinute != nil {
+			peak := s.PeakMinute.In(loc)
+			s.PeakMinute = &peak
+		}
+	}
+}
+
+// LocalizeDailyStats converts bucket timestamps to loc for presentation
+func LocalizeDailyStats(stats []*model.GPUUsageStatisticsDaily, loc *time.Location) {
+	for _, s := range stats {
+		s.TimeBucket = s.TimeBucket.In(loc)
+		s.PeriodStart = s.PeriodStart.In(loc)
+		s.PeriodEnd = s.PeriodEnd.In(loc)
+		if s.PeakHour != nil {
+			peak := s.PeakHour.In(loc)
+			s.PeakHour = &peak
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package gpuusage
+
+import "time"
+
+// maxBackfillErrors caps the number of per-task errors kept in a backfill result
+const maxBackfillErrors = 100
+
+// ReaggregateResult reports how many buckets were recomputed per granularity
+type ReaggregateResult struct {
+	MinuteBuckets int `json:"minuteBuckets"`
+	HourBuckets   int `json:"hourBuckets"`
+	DayBuckets    int `json:"dayBuckets"`
+}
+
+// BackfillResult summarizes a backfill run
+type BackfillResult struct {
+	ReaggregateResult
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+	TotalTasksProcessed int       `json:"totalTasksProcessed"`
+	RecordsCreated      int       `json:"recordsCreated"`
+	RecordsSkipped      int       `json:"recordsSkipped"`
+	Errors              []string  `json:"errors"`
+	StartTime           time.Time `json:"startTime"`
+	EndTime             time.Time `json:"endTime"`
+	Duration            string    `json:"duration"`
+}
+
+func (r *BackfillResult) addError(msg string) {
+	if len(r.Errors) < maxBackfillErrors {
+		r.Errors = append(r.Errors, msg)
+	}
+}
+
+// GranularityStatus is the aggregation progress of one granularity
+type GranularityStatus struct {
+	Granularity    string     `json:"granularity"`
+	Watermark      time.Time  `json:"watermark"`
+	LagSeconds     int64      `json:"lag_seconds"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	LastBuckets    int        `json:"last_buckets"`
+	LastError      string     `json:"last_error,omitempty"`
+}
+
+// AggregationStatus is the overall aggregation progress, used for lag monitoring
+type AggregationStatus struct {
+	Granularities   []GranularityStatus `json:"granularities"`
+	PendingRecords  int64               `json:"pending_records"` // Records completed after the minute watermark
+	BackfillRunning bool                `json:"backfill_running"`
+}
@@ -0,0 +1,80 @@
+// Package hedging decides when a task of a latency-critical endpoint gets a duplicate (a
+// hedge) on a second worker. A task running longer than the endpoint's recent p99 execution
+// time is hedged; the first of the two copies to report a result wins and the other one is
+// cancelled.
+package hedging
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Outcomes of a hedged task, stored on the original task
+const (
+	OutcomeRacing   = "racing"   // Both copies may still report
+	OutcomeOriginal = "original" // The original reported first; the hedge was cancelled
+	OutcomeHedge    = "hedge"    // The hedge reported first; its result is the task's result
+)
+
+// Quantile returns the q-quantile (nearest rank) of samples, 0 without samples. samples is
+// sorted in place.
+func Quantile(samples []time.Duration, q float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	rank := int(math.Ceil(q*float64(len(samples)))) - 1
+	rank = max(0, min(rank, len(samples)-1))
+	return samples[rank]
+}
+
+// Threshold returns how long a task may run before it is hedged: the p99 of the recent
+// execution times scaled by multiplier, at least minDelay. ok is false with fewer than
+// minSamples samples, as a p99 of a handful of tasks says nothing.
+func Threshold(samples []time.Duration, minSamples int, multiplier float64, minDelay time.Duration) (threshold time.Duration, ok bool) {
+	if len(samples) == 0 || len(samples) < minSamples {
+		return 0, false
+	}
+	threshold = time.Duration(float64(Quantile(samples, 0.99)) * multiplier)
+	return max(threshold, minDelay), true
+}
+
+// Budget returns how many tasks may be hedged now: none while tasks are queued, as a hedge
+// would wait behind them and delay them in turn, otherwise one per idle worker
+func Budget(pending int64, idleWorkers int) int {
+	if pending > 0 {
+		return 0
+	}
+	return max(idleWorkers, 0)
+}
+
+// Stats are the costs and savings of hedging on an endpoint over a time range
+type Stats struct {
+	Endpoint     string    `json:"endpoint"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Hedged       int64     `json:"hedged"`       // Tasks that got a hedge
+	HedgeWins    int64     `json:"hedgeWins"`    // The hedge reported first
+	OriginalWins int64     `json:"originalWins"` // The original reported first anyway
+	Racing       int64     `json:"racing"`       // Both copies still running
+	HedgeWinRate float64   `json:"hedgeWinRate"` // HedgeWins / decided races
+
+	// Cost: execution time of the hedge copies, i.e. the extra GPU time spent
+	HedgeExecutionMs int64 `json:"hedgeExecutionMs"`
+	// Savings: how much earlier hedge wins returned than their original, known when the
+	// original still reported its (dropped) result
+	SavedMs       int64   `json:"savedMs"`
+	SavedMeasured int64   `json:"savedMeasured"` // Hedge wins with a measured saving
+	AvgSavedMs    float64 `json:"avgSavedMs"`
+}
+
+// Finish computes the derived rates
+func (s *Stats) Finish() {
+	if decided := s.HedgeWins + s.OriginalWins; decided > 0 {
+		s.HedgeWinRate = float64(s.HedgeWins) / float64(decided)
+	}
+	if s.SavedMeasured > 0 {
+		s.AvgSavedMs = float64(s.SavedMs) / float64(s.SavedMeasured)
+	}
+}
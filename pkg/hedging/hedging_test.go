@@ -0,0 +1,59 @@
+package hedging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func samples(ms ...int) []time.Duration {
+	out := make([]time.Duration, 0, len(ms))
+	for _, v := range ms {
+		out = append(out, time.Duration(v)*time.Millisecond)
+	}
+	return out
+}
+
+func TestQuantile(t *testing.T) {
+	assert.Zero(t, Quantile(nil, 0.99))
+	assert.Equal(t, 300*time.Millisecond, Quantile(samples(300, 100, 200), 0.99))
+	assert.Equal(t, 200*time.Millisecond, Quantile(samples(300, 100, 200), 0.5))
+
+	hundred := make([]int, 0, 100)
+	for i := 100; i >= 1; i-- {
+		hundred = append(hundred, i)
+	}
+	assert.Equal(t, 99*time.Millisecond, Quantile(samples(hundred...), 0.99))
+}
+
+func TestThreshold(t *testing.T) {
+	_, ok := Threshold(samples(100, 200), 3, 1, 0)
+	assert.False(t, ok)
+	_, ok = Threshold(nil, 0, 1, 0)
+	assert.False(t, ok)
+
+	threshold, ok := Threshold(samples(100, 200, 400), 3, 1.5, 0)
+	assert.True(t, ok)
+	assert.Equal(t, 600*time.Millisecond, threshold)
+
+	threshold, _ = Threshold(samples(100, 200, 400), 3, 1, time.Second)
+	assert.Equal(t, time.Second, threshold)
+}
+
+func TestBudget(t *testing.T) {
+	assert.Zero(t, Budget(1, 4))
+	assert.Equal(t, 4, Budget(0, 4))
+	assert.Zero(t, Budget(0, -1))
+}
+
+func TestStatsFinish(t *testing.T) {
+	s := &Stats{HedgeWins: 3, OriginalWins: 1, Racing: 2, SavedMs: 900, SavedMeasured: 2}
+	s.Finish()
+	assert.Equal(t, 0.75, s.HedgeWinRate)
+	assert.Equal(t, 450.0, s.AvgSavedMs)
+
+	empty := &Stats{}
+	empty.Finish()
+	assert.Zero(t, empty.HedgeWinRate)
+}
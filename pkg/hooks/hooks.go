@@ -0,0 +1,357 @@
+// Package hooks runs per-endpoint lifecycle hooks: an HTTP call or a one-off job executed
+// before a deploy, after a deploy or before a delete.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// Lifecycle points at which hooks run
+const (
+	PhasePreDeploy  = "pre_deploy"  // Before a deploy or deployment update; a blocking failure aborts it
+	PhasePostDeploy = "post_deploy" // After a successful deploy or deployment update
+	PhasePreDelete  = "pre_delete"  // Before an endpoint is deleted; a blocking failure aborts it
+)
+
+// Operations that trigger hooks
+const (
+	OperationDeploy = "deploy"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// Failure policies
+const (
+	FailurePolicyBlock = "block" // A failure stops the remaining hooks and, before the operation, the operation itself
+	FailurePolicyWarn  = "warn"  // A failure is recorded and the next hook runs
+)
+
+// Run outcomes
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusTimedOut  = "timed_out"
+)
+
+// Action types
+const (
+	ActionHTTP = "http"
+	ActionJob  = "job"
+)
+
+const (
+	// DefaultHTTPTimeout bounds an HTTP hook without a timeout
+	DefaultHTTPTimeout = 30 * time.Second
+	// DefaultJobTimeout bounds a job hook without a timeout
+	DefaultJobTimeout = 10 * time.Minute
+	// MaxTimeout bounds every hook: hooks run inside the API request of the operation
+	MaxTimeout = 30 * time.Minute
+	// maxOutputBytes bounds the kept response body or job log tail
+	maxOutputBytes = 4096
+	// jobLogTailLines is how many log lines of a job hook are kept
+	jobLogTailLines = 50
+)
+
+// namePattern hook names appear in job names and run history
+var namePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,30}[a-z0-9])?$`)
+
+// Hook is one lifecycle hook of an endpoint. Exactly one of HTTP and Job is set.
+type Hook struct {
+	Name           string      `json:"name"`                     // Lowercase letters, digits and '-', unique per endpoint
+	Phase          string      `json:"phase"`                    // pre_deploy, post_deploy or pre_delete
+	HTTP           *HTTPAction `json:"http,omitempty"`           // Call a URL
+	Job            *JobAction  `json:"job,omitempty"`            // Run an image to completion (providers with job support)
+	TimeoutSeconds int         `json:"timeoutSeconds,omitempty"` // Default 30 (HTTP) or 600 (job)
+	FailurePolicy  string      `json:"failurePolicy,omitempty"`  // block (default) or warn
+}
+
+// HTTPAction calls a URL; any 2xx response succeeds
+type HTTPAction struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`  // Default POST
+	Headers map[string]string `json:"headers,omitempty"` // Extra request headers
+	Body    string            `json:"body,omitempty"`    // Default: {"endpoint", "phase", "hook", "operation"} as JSON
+}
+
+// JobAction runs an image to completion on a spec; exit code 0 succeeds
+type JobAction struct {
+	Image    string            `json:"image"`
+	SpecName string            `json:"specName"`
+	Command  []string          `json:"command,omitempty"`
+	Args     []string          `json:"args,omitempty"`
+	Env      map[string]string `json:"env,omitempty"` // WAVERLESS_ENDPOINT, WAVERLESS_HOOK_PHASE and WAVERLESS_HOOK_OPERATION are always set
+}
+
+// Action returns the action type of the hook
+func (h *Hook) Action() string {
+	if h.Job != nil {
+		return ActionJob
+	}
+	return ActionHTTP
+}
+
+// Policy returns the failure policy, block when unset
+func (h *Hook) Policy() string {
+	if h.FailurePolicy == "" {
+		return FailurePolicyBlock
+	}
+	return h.FailurePolicy
+}
+
+// Timeout returns how long the hook may run
+func (h *Hook) Timeout() time.Duration {
+	if h.TimeoutSeconds > 0 {
+		return time.Duration(h.TimeoutSeconds) * time.Second
+	}
+	if h.Job != nil {
+		return DefaultJobTimeout
+	}
+	return DefaultHTTPTimeout
+}
+
+// Validate checks a hook; errors start with "invalid"
+func (h *Hook) Validate() error {
+	if !namePattern.MatchString(h.Name) {
+		return fmt.Errorf("invalid hook name %q: use lowercase letters, digits and '-', at most 32 characters", h.Name)
+	}
+	switch h.Phase {
+	case PhasePreDeploy, PhasePostDeploy, PhasePreDelete:
+	default:
+		return fmt.Errorf("invalid phase %q of hook %s: must be %s, %s or %s", h.Phase, h.Name, PhasePreDeploy, PhasePostDeploy, PhasePreDelete)
+	}
+	switch h.FailurePolicy {
+	case "", FailurePolicyBlock, FailurePolicyWarn:
+	default:
+		return fmt.Errorf("invalid failure policy %q of hook %s: must be %s or %s", h.FailurePolicy, h.Name, FailurePolicyBlock, FailurePolicyWarn)
+	}
+	if h.TimeoutSeconds < 0 || time.Duration(h.TimeoutSeconds)*time.Second > MaxTimeout {
+		return fmt.Errorf("invalid timeout of hook %s: must be between 0 and %d seconds", h.Name, int(MaxTimeout.Seconds()))
+	}
+
+	if (h.HTTP == nil) == (h.Job == nil) {
+		return fmt.Errorf("invalid hook %s: set exactly one of http and job", h.Name)
+	}
+	if h.HTTP != nil {
+		u, err := url.Parse(h.HTTP.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid url of hook %s: must be an http(s) URL", h.Name)
+		}
+		switch strings.ToUpper(h.HTTP.Method) {
+		case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return fmt.Errorf("invalid method %q of hook %s", h.HTTP.Method, h.Name)
+		}
+	}
+	if h.Job != nil && (h.Job.Image == "" || h.Job.SpecName == "") {
+		return fmt.Errorf("invalid job of hook %s: image and specName are required", h.Name)
+	}
+	return nil
+}
+
+// Result is the outcome of one hook run
+type Result struct {
+	Status     string        `json:"status"`               // succeeded, failed or timed_out
+	StatusCode int           `json:"statusCode,omitempty"` // HTTP status, or the job's exit code
+	Output     string        `json:"output,omitempty"`     // Response body or job log tail (truncated)
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Succeeded reports whether the hook succeeded
+func (r *Result) Succeeded() bool {
+	return r.Status == StatusSucceeded
+}
+
+// Runner executes hooks
+type Runner struct {
+	client       *http.Client
+	jobs         interfaces.JobRunner // nil = job hooks fail
+	pollInterval time.Duration
+}
+
+// NewRunner creates a hook runner; jobs may be nil when the provider can't run jobs
+func NewRunner(jobs interfaces.JobRunner) *Runner {
+	return &Runner{
+		client:       &http.Client{},
+		jobs:         jobs,
+		pollInterval: 2 * time.Second,
+	}
+}
+
+// Run executes a hook of an endpoint and waits for its outcome
+func (r *Runner) Run(ctx context.Context, endpoint, operation string, h *Hook) *Result {
+	start := time.Now()
+	runCtx, cancel := context.WithTimeout(ctx, h.Timeout())
+	defer cancel()
+
+	var result *Result
+	if h.Job != nil {
+		result = r.runJob(runCtx, endpoint, operation, h)
+	} else {
+		result = r.runHTTP(runCtx, endpoint, operation, h)
+	}
+	if !result.Succeeded() && errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		result.Status = StatusTimedOut
+		result.Error = fmt.Sprintf("hook did not finish within %v", h.Timeout())
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// runHTTP calls the hook's URL
+func (r *Runner) runHTTP(ctx context.Context, endpoint, operation string, h *Hook) *Result {
+	method := strings.ToUpper(h.HTTP.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	body := h.HTTP.Body
+	if body == "" && method != http.MethodGet {
+		payload, _ := json.Marshal(map[string]string{
+			"endpoint":  endpoint,
+			"phase":     h.Phase,
+			"hook":      h.Name,
+			"operation": operation,
+		})
+		body = string(payload)
+	}
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, h.HTTP.URL, reader)
+	if err != nil {
+		return failed(fmt.Errorf("failed to create request: %w", err))
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Waverless-Endpoint", endpoint)
+	req.Header.Set("X-Waverless-Hook-Phase", h.Phase)
+	for k, v := range h.HTTP.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return failed(err)
+	}
+	defer resp.Body.Close()
+	output, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutputBytes))
+
+	result := &Result{Status: StatusSucceeded, StatusCode: resp.StatusCode, Output: string(output)}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.Status = StatusFailed
+		result.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return result
+}
+
+// runJob starts the hook's job, waits for it to finish and removes it
+func (r *Runner) runJob(ctx context.Context, endpoint, operation string, h *Hook) *Result {
+	if r.jobs == nil {
+		return failed(fmt.Errorf("job hooks are not supported by the deployment provider"))
+	}
+
+	env := make(map[string]string, len(h.Job.Env)+3)
+	for k, v := range h.Job.Env {
+		env[k] = v
+	}
+	env["WAVERLESS_ENDPOINT"] = endpoint
+	env["WAVERLESS_HOOK_PHASE"] = h.Phase
+	env["WAVERLESS_HOOK_OPERATION"] = operation
+
+	name := JobName(endpoint, h.Name, time.Now())
+	err := r.jobs.RunJob(ctx, &interfaces.JobRequest{
+		Name:           name,
+		SpecName:       h.Job.SpecName,
+		Image:          h.Job.Image,
+		Command:        h.Job.Command,
+		Args:           h.Job.Args,
+		Env:            env,
+		TimeoutSeconds: int(h.Timeout().Seconds()),
+	})
+	if err != nil {
+		return failed(fmt.Errorf("failed to start job %s: %w", name, err))
+	}
+	// The job is removed whatever the outcome; ctx may already be done
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := r.jobs.DeleteJob(cleanupCtx, name); err != nil {
+			logger.WarnCtx(ctx, "Failed to delete hook job %s: %v", name, err)
+		}
+	}()
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := r.jobs.GetJobStatus(ctx, name)
+		if err != nil && ctx.Err() == nil {
+			logger.WarnCtx(ctx, "Failed to get status of hook job %s: %v", name, err)
+		}
+		if status != nil && (status.Phase == interfaces.JobPhaseSucceeded || status.Phase == interfaces.JobPhaseFailed) {
+			result := &Result{Status: StatusSucceeded, Output: r.logTail(name)}
+			if status.ExitCode != nil {
+				result.StatusCode = int(*status.ExitCode)
+			}
+			if status.Phase == interfaces.JobPhaseFailed {
+				result.Status = StatusFailed
+				result.Error = status.Message
+				if result.Error == "" {
+					result.Error = "job failed"
+				}
+			}
+			return result
+		}
+
+		select {
+		case <-ctx.Done():
+			result := failed(fmt.Errorf("job %s did not finish: %w", name, ctx.Err()))
+			result.Output = r.logTail(name)
+			return result
+		case <-ticker.C:
+		}
+	}
+}
+
+// logTail returns the end of a job's logs, empty when they can't be read
+func (r *Runner) logTail(name string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := r.jobs.StreamJobLogs(ctx, name, false, jobLogTailLines)
+	if err != nil {
+		return ""
+	}
+	defer stream.Close()
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, io.LimitReader(stream, maxOutputBytes))
+	return buf.String()
+}
+
+// JobName returns the name of a hook job: a DNS-1123 label unique per run
+func JobName(endpoint, hook string, now time.Time) string {
+	suffix := fmt.Sprintf("-%s-%x", hook, now.UnixNano()&0xffffffff)
+	prefix := "hook-" + strings.ToLower(endpoint)
+	if max := 63 - len(suffix); len(prefix) > max {
+		prefix = prefix[:max]
+	}
+	return strings.TrimRight(prefix, "-.") + suffix
+}
+
+func failed(err error) *Result {
+	return &Result{Status: StatusFailed, Error: err.Error()}
+}
@@ -0,0 +1,152 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/interfaces"
+)
+
+func TestValidate(t *testing.T) {
+	valid := &Hook{Name: "warm-cache", Phase: PhasePostDeploy, HTTP: &HTTPAction{URL: "https://cache.example.com/warm"}}
+	require.NoError(t, valid.Validate())
+	assert.Equal(t, FailurePolicyBlock, valid.Policy())
+	assert.Equal(t, DefaultHTTPTimeout, valid.Timeout())
+	assert.Equal(t, ActionHTTP, valid.Action())
+
+	job := &Hook{Name: "deregister", Phase: PhasePreDelete, Job: &JobAction{Image: "tools:v1", SpecName: "cpu-small"}, FailurePolicy: FailurePolicyWarn}
+	require.NoError(t, job.Validate())
+	assert.Equal(t, DefaultJobTimeout, job.Timeout())
+	assert.Equal(t, ActionJob, job.Action())
+
+	invalid := []*Hook{
+		{Name: "Bad_Name", Phase: PhasePreDeploy, HTTP: &HTTPAction{URL: "https://x"}},
+		{Name: "a", Phase: "post_scale", HTTP: &HTTPAction{URL: "https://x"}},
+		{Name: "a", Phase: PhasePreDeploy},
+		{Name: "a", Phase: PhasePreDeploy, HTTP: &HTTPAction{URL: "https://x"}, Job: &JobAction{Image: "i", SpecName: "s"}},
+		{Name: "a", Phase: PhasePreDeploy, HTTP: &HTTPAction{URL: "ftp://x"}},
+		{Name: "a", Phase: PhasePreDeploy, HTTP: &HTTPAction{URL: "https://x", Method: "TRACE"}},
+		{Name: "a", Phase: PhasePreDeploy, HTTP: &HTTPAction{URL: "https://x"}, FailurePolicy: "ignore"},
+		{Name: "a", Phase: PhasePreDeploy, HTTP: &HTTPAction{URL: "https://x"}, TimeoutSeconds: 7200},
+		{Name: "a", Phase: PhasePreDeploy, Job: &JobAction{Image: "i"}},
+	}
+	for _, h := range invalid {
+		err := h.Validate()
+		if assert.Error(t, err, "%+v", h) {
+			assert.True(t, strings.HasPrefix(err.Error(), "invalid"), err.Error())
+		}
+	}
+}
+
+func TestRunHTTP(t *testing.T) {
+	var got map[string]string
+	var phaseHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		phaseHeader = r.Header.Get("X-Waverless-Hook-Phase")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte("warmed"))
+	}))
+	defer srv.Close()
+
+	h := &Hook{Name: "warm", Phase: PhasePostDeploy, HTTP: &HTTPAction{URL: srv.URL}}
+	result := NewRunner(nil).Run(context.Background(), "demo", OperationDeploy, h)
+	require.True(t, result.Succeeded(), result.Error)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, "warmed", result.Output)
+	assert.Equal(t, PhasePostDeploy, phaseHeader)
+	assert.Equal(t, map[string]string{"endpoint": "demo", "phase": PhasePostDeploy, "hook": "warm", "operation": OperationDeploy}, got)
+}
+
+func TestRunHTTPFailureAndTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(2 * time.Second)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	runner := NewRunner(nil)
+	result := runner.Run(context.Background(), "demo", OperationDeploy, &Hook{Name: "a", Phase: PhasePreDeploy, HTTP: &HTTPAction{URL: srv.URL}})
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+
+	result = runner.Run(context.Background(), "demo", OperationDeploy, &Hook{Name: "a", Phase: PhasePreDeploy, HTTP: &HTTPAction{URL: srv.URL + "/slow"}, TimeoutSeconds: 1})
+	assert.Equal(t, StatusTimedOut, result.Status)
+}
+
+// fakeJobs is an in-memory JobRunner whose jobs finish with the given phase
+type fakeJobs struct {
+	mu      sync.Mutex
+	phase   string
+	started []*interfaces.JobRequest
+	deleted []string
+}
+
+func (f *fakeJobs) RunJob(ctx context.Context, req *interfaces.JobRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.started = append(f.started, req)
+	return nil
+}
+
+func (f *fakeJobs) GetJobStatus(ctx context.Context, name string) (*interfaces.JobStatus, error) {
+	code := int32(0)
+	if f.phase == interfaces.JobPhaseFailed {
+		code = 3
+	}
+	return &interfaces.JobStatus{Phase: f.phase, ExitCode: &code}, nil
+}
+
+func (f *fakeJobs) DeleteJob(ctx context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+func (f *fakeJobs) StreamJobLogs(ctx context.Context, name string, follow bool, tailLines int) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("catalog updated")), nil
+}
+
+func TestRunJob(t *testing.T) {
+	jobs := &fakeJobs{phase: interfaces.JobPhaseSucceeded}
+	h := &Hook{Name: "deregister", Phase: PhasePreDelete, Job: &JobAction{Image: "tools:v1", SpecName: "cpu", Env: map[string]string{"CATALOG": "prod"}}}
+
+	result := NewRunner(jobs).Run(context.Background(), "demo", OperationDelete, h)
+	require.True(t, result.Succeeded(), result.Error)
+	assert.Equal(t, "catalog updated", result.Output)
+	require.Len(t, jobs.started, 1)
+	assert.Equal(t, "prod", jobs.started[0].Env["CATALOG"])
+	assert.Equal(t, "demo", jobs.started[0].Env["WAVERLESS_ENDPOINT"])
+	assert.Equal(t, PhasePreDelete, jobs.started[0].Env["WAVERLESS_HOOK_PHASE"])
+	assert.Equal(t, []string{jobs.started[0].Name}, jobs.deleted, "job is removed after it finished")
+
+	jobs.phase = interfaces.JobPhaseFailed
+	result = NewRunner(jobs).Run(context.Background(), "demo", OperationDelete, h)
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Equal(t, 3, result.StatusCode)
+
+	result = NewRunner(nil).Run(context.Background(), "demo", OperationDelete, h)
+	assert.Equal(t, StatusFailed, result.Status, "no job support")
+}
+
+func TestJobName(t *testing.T) {
+	now := time.Unix(1700000000, 123)
+	name := JobName("demo", "warm", now)
+	assert.True(t, strings.HasPrefix(name, "hook-demo-warm-"), name)
+
+	long := JobName(strings.Repeat("a", 80), strings.Repeat("b", 32), now)
+	assert.LessOrEqual(t, len(long), 63)
+	assert.True(t, strings.HasPrefix(long, "hook-a"))
+}
@@ -0,0 +1,177 @@
+// Package iac renders endpoints as infrastructure-as-code resources, so endpoint definitions can be
+// managed by Terraform: a resource representation with stable IDs a Terraform provider can read,
+// and exports of existing endpoints as Terraform configuration (HCL or JSON) with import blocks.
+package iac
+
+import (
+	"sort"
+	"strings"
+
+	"waverless/pkg/interfaces"
+)
+
+// ResourceType is the Terraform resource type of an endpoint
+const ResourceType = "waverless_endpoint"
+
+// VolumeMount is a PVC mounted into the workers (nested block volume_mount)
+type VolumeMount struct {
+	PVCName   string `json:"pvc_name"`
+	MountPath string `json:"mount_path"`
+}
+
+// EndpointResource is the configuration of an endpoint as a Terraform resource. Runtime state
+// (status, replicas ready, health) is not part of it, so reading it back never shows a diff
+// that configuration cannot resolve.
+type EndpointResource struct {
+	// ID is the stable resource ID: the endpoint name, which is unique and cannot change
+	ID string `json:"id"`
+
+	Name            string            `json:"name"`
+	Provider        string            `json:"provider,omitempty"`
+	Description     string            `json:"description,omitempty"`
+	SpecName        string            `json:"spec_name"`
+	Image           string            `json:"image"`
+	Replicas        int               `json:"replicas"`
+	GpuCount        int               `json:"gpu_count,omitempty"`
+	TaskTimeout     int               `json:"task_timeout,omitempty"`
+	MaxPendingTasks int               `json:"max_pending_tasks,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+	SecretEnv       []string          `json:"secret_env,omitempty"` // Names only, secret values are never exported
+	Labels          map[string]string `json:"labels,omitempty"`
+
+	MinReplicas       int `json:"min_replicas"`
+	MaxReplicas       int `json:"max_replicas,omitempty"`
+	ScaleUpThreshold  int `json:"scale_up_threshold,omitempty"`
+	ScaleDownIdleTime int `json:"scale_down_idle_time,omitempty"`
+	ScaleUpCooldown   int `json:"scale_up_cooldown,omitempty"`
+	ScaleDownCooldown int `json:"scale_down_cooldown,omitempty"`
+	Priority          int `json:"priority,omitempty"`
+
+	EnablePtrace     bool          `json:"enable_ptrace,omitempty"`
+	RunPodCompat     bool          `json:"runpod_compat"`
+	ShmSize          string        `json:"shm_size,omitempty"`
+	EphemeralStorage string        `json:"ephemeral_storage,omitempty"`
+	VolumeMounts     []VolumeMount `json:"volume_mount,omitempty"`
+}
+
+// FromMetadata converts endpoint metadata to its resource representation
+func FromMetadata(meta *interfaces.EndpointMetadata) *EndpointResource {
+	r := &EndpointResource{
+		ID:                meta.Name,
+		Name:              meta.Name,
+		Provider:          meta.Provider,
+		Description:       meta.Description,
+		SpecName:          meta.SpecName,
+		Image:             meta.Image,
+		Replicas:          meta.Replicas,
+		GpuCount:          meta.GpuCount,
+		TaskTimeout:       meta.TaskTimeout,
+		MaxPendingTasks:   meta.MaxPendingTasks,
+		Env:               meta.Env,
+		Labels:            meta.Labels,
+		MinReplicas:       meta.MinReplicas,
+		MaxReplicas:       meta.MaxReplicas,
+		ScaleUpThreshold:  meta.ScaleUpThreshold,
+		ScaleDownIdleTime: meta.ScaleDownIdleTime,
+		ScaleUpCooldown:   meta.ScaleUpCooldown,
+		ScaleDownCooldown: meta.ScaleDownCooldown,
+		Priority:          meta.Priority,
+		EnablePtrace:      meta.EnablePtrace,
+		RunPodCompat:      meta.RunPodCompat,
+		ShmSize:           meta.ShmSize,
+		EphemeralStorage:  meta.EphemeralStorage,
+	}
+	if len(meta.SecretEnv) > 0 {
+		r.SecretEnv = append([]string(nil), meta.SecretEnv...)
+		sort.Strings(r.SecretEnv)
+	}
+	for _, vm := range meta.VolumeMounts {
+		r.VolumeMounts = append(r.VolumeMounts, VolumeMount{PVCName: vm.PVCName, MountPath: vm.MountPath})
+	}
+	return r
+}
+
+// Address returns the Terraform address of the resource, e.g. waverless_endpoint.flux_dev
+func (r *EndpointResource) Address() string {
+	return ResourceType + "." + Label(r.Name)
+}
+
+// Label returns the Terraform resource name for an endpoint. Endpoint names are DNS labels;
+// Terraform names cannot start with a digit, so those get an "ep_" prefix.
+func Label(name string) string {
+	label := strings.ReplaceAll(name, "-", "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "ep_" + label
+	}
+	return label
+}
+
+// attribute is one argument of a resource block, in schema order
+type attribute struct {
+	name  string
+	value interface{} // string, int, bool, map[string]string or []string
+}
+
+// attributes returns the configurable arguments of the resource. Optional arguments at their
+// zero value are left out so the exported configuration only carries what was set.
+func (r *EndpointResource) attributes() []attribute {
+	attrs := []attribute{
+		{"name", r.Name},
+		{"provider", r.Provider},
+		{"description", r.Description},
+		{"spec_name", r.SpecName},
+		{"image", r.Image},
+		{"replicas", r.Replicas},
+		{"gpu_count", r.GpuCount},
+		{"task_timeout", r.TaskTimeout},
+		{"max_pending_tasks", r.MaxPendingTasks},
+		{"min_replicas", r.MinReplicas},
+		{"max_replicas", r.MaxReplicas},
+		{"scale_up_threshold", r.ScaleUpThreshold},
+		{"scale_down_idle_time", r.ScaleDownIdleTime},
+		{"scale_up_cooldown", r.ScaleUpCooldown},
+		{"scale_down_cooldown", r.ScaleDownCooldown},
+		{"priority", r.Priority},
+		{"enable_ptrace", r.EnablePtrace},
+		{"runpod_compat", r.RunPodCompat},
+		{"shm_size", r.ShmSize},
+		{"ephemeral_storage", r.EphemeralStorage},
+		{"env", r.Env},
+		{"labels", r.Labels},
+	}
+
+	required := map[string]bool{"name": true, "spec_name": true, "image": true, "replicas": true, "runpod_compat": true}
+	set := attrs[:0]
+	for _, a := range attrs {
+		if required[a.name] || !isZero(a.value) {
+			set = append(set, a)
+		}
+	}
+	return set
+}
+
+func isZero(v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case bool:
+		return !v
+	case map[string]string:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return v == nil
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,202 @@
+package iac
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatHCL  = "hcl"
+	FormatJSON = "json"
+)
+
+// secretEnvComment explains why secret env vars are missing from an export
+func secretEnvComment(r *EndpointResource) string {
+	return fmt.Sprintf("Secret env vars %s are not exported, set them through PATCH /api/v1/endpoints/%s/env",
+		strings.Join(r.SecretEnv, ", "), r.Name)
+}
+
+// RenderHCL renders resources as a Terraform configuration with one import block per
+// resource, so `terraform plan` adopts the existing endpoints instead of creating them
+func RenderHCL(resources []*EndpointResource) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by waverless. Apply with the waverless Terraform provider.\n")
+	for _, r := range resources {
+		buf.WriteString("\n")
+		fmt.Fprintf(&buf, "resource %q %q {\n", ResourceType, Label(r.Name))
+		if len(r.SecretEnv) > 0 {
+			fmt.Fprintf(&buf, "  # %s\n", secretEnvComment(r))
+		}
+		writeHCLAttributes(&buf, r.attributes())
+		for _, vm := range r.VolumeMounts {
+			buf.WriteString("\n  volume_mount {\n")
+			fmt.Fprintf(&buf, "    pvc_name   = %s\n", hclString(vm.PVCName))
+			fmt.Fprintf(&buf, "    mount_path = %s\n", hclString(vm.MountPath))
+			buf.WriteString("  }\n")
+		}
+		buf.WriteString("}\n\n")
+
+		buf.WriteString("import {\n")
+		fmt.Fprintf(&buf, "  to = %s\n", r.Address())
+		fmt.Fprintf(&buf, "  id = %s\n", hclString(r.ID))
+		buf.WriteString("}\n")
+	}
+	return buf.Bytes()
+}
+
+// writeHCLAttributes writes arguments the way terraform fmt does: the equals signs of
+// consecutive single-line arguments are aligned, maps start a new group
+func writeHCLAttributes(buf *bytes.Buffer, attrs []attribute) {
+	for start := 0; start < len(attrs); {
+		if m, ok := attrs[start].value.(map[string]string); ok {
+			if start > 0 {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(buf, "  %s = {\n", attrs[start].name)
+			keys := sortedKeys(m)
+			width := 0
+			for _, k := range keys {
+				if l := len(hclKey(k)); l > width {
+					width = l
+				}
+			}
+			for _, k := range keys {
+				fmt.Fprintf(buf, "    %-*s = %s\n", width, hclKey(k), hclString(m[k]))
+			}
+			buf.WriteString("  }\n")
+			start++
+			continue
+		}
+
+		end, width := start, 0
+		for ; end < len(attrs); end++ {
+			if _, isMap := attrs[end].value.(map[string]string); isMap {
+				break
+			}
+			if l := len(attrs[end].name); l > width {
+				width = l
+			}
+		}
+		for _, a := range attrs[start:end] {
+			fmt.Fprintf(buf, "  %-*s = %s\n", width, a.name, hclValue(a.value))
+		}
+		start = end
+	}
+}
+
+func hclValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return hclString(v)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = hclString(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+	return "null"
+}
+
+// hclKey returns a map key, quoted unless it is a plain identifier
+func hclKey(k string) string {
+	for i, c := range k {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && (c == '-' || (c >= '0' && c <= '9')))) {
+			return hclString(k)
+		}
+	}
+	if k == "" {
+		return `""`
+	}
+	return k
+}
+
+// hclString quotes s as an HCL string literal; template sequences are escaped so values are
+// taken literally
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '"':
+			b.WriteString(`\"`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case (c == '$' || c == '%') && i+1 < len(s) && s[i+1] == '{':
+			b.WriteByte(c)
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// RenderJSON renders resources in Terraform's JSON configuration syntax (*.tf.json), with
+// the same import blocks as RenderHCL
+func RenderJSON(resources []*EndpointResource) ([]byte, error) {
+	blocks := make(map[string]interface{}, len(resources))
+	imports := make([]map[string]string, 0, len(resources))
+	for _, r := range resources {
+		block := make(map[string]interface{})
+		if len(r.SecretEnv) > 0 {
+			block["//"] = secretEnvComment(r)
+		}
+		for _, a := range r.attributes() {
+			block[a.name] = jsonValue(a.value)
+		}
+		if len(r.VolumeMounts) > 0 {
+			mounts := make([]VolumeMount, len(r.VolumeMounts))
+			for i, vm := range r.VolumeMounts {
+				mounts[i] = VolumeMount{PVCName: escapeTemplate(vm.PVCName), MountPath: escapeTemplate(vm.MountPath)}
+			}
+			block["volume_mount"] = mounts
+		}
+		blocks[Label(r.Name)] = block
+		imports = append(imports, map[string]string{"to": r.Address(), "id": r.ID})
+	}
+
+	config := map[string]interface{}{
+		"resource": map[string]interface{}{ResourceType: blocks},
+		"import":   imports,
+	}
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// jsonValue escapes the strings of an argument; Terraform evaluates JSON strings as templates too
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return escapeTemplate(v)
+	case map[string]string:
+		escaped := make(map[string]string, len(v))
+		for k, val := range v {
+			escaped[k] = escapeTemplate(val)
+		}
+		return escaped
+	}
+	return v
+}
+
+var templateEscaper = strings.NewReplacer("${", "$${", "%{", "%%{")
+
+// escapeTemplate escapes template sequences so Terraform takes s literally
+func escapeTemplate(s string) string {
+	return templateEscaper.Replace(s)
+}
@@ -0,0 +1,98 @@
+package iac
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/interfaces"
+)
+
+func testResource() *EndpointResource {
+	return FromMetadata(&interfaces.EndpointMetadata{
+		Name:         "flux-dev",
+		SpecName:     "h100-single",
+		Image:        "wavespeed/flux:${TAG}",
+		Replicas:     1,
+		MaxReplicas:  4,
+		Env:          map[string]string{"HF_HOME": "/models", "model.path": "a\"b"},
+		SecretEnv:    []string{"HF_TOKEN", "API_KEY"},
+		RunPodCompat: true,
+		VolumeMounts: []interfaces.VolumeMount{{PVCName: "models", MountPath: "/models"}},
+		Status:       "Running",
+		WorkerCount:  3,
+	})
+}
+
+func TestLabel(t *testing.T) {
+	assert.Equal(t, "flux_dev", Label("flux-dev"))
+	assert.Equal(t, "ep_7b_chat", Label("7b-chat"))
+	assert.Equal(t, "waverless_endpoint.ep_7b_chat", (&EndpointResource{Name: "7b-chat"}).Address())
+}
+
+func TestFromMetadata(t *testing.T) {
+	r := testResource()
+	assert.Equal(t, "flux-dev", r.ID)
+	assert.Equal(t, []string{"API_KEY", "HF_TOKEN"}, r.SecretEnv, "secret names are sorted")
+	assert.Equal(t, []VolumeMount{{PVCName: "models", MountPath: "/models"}}, r.VolumeMounts)
+
+	names := []string{}
+	for _, a := range r.attributes() {
+		names = append(names, a.name)
+	}
+	assert.Equal(t, []string{"name", "spec_name", "image", "replicas", "max_replicas", "runpod_compat", "env"}, names,
+		"unset optional arguments are left out")
+}
+
+func TestRenderHCL(t *testing.T) {
+	want := `# Generated by waverless. Apply with the waverless Terraform provider.
+
+resource "waverless_endpoint" "flux_dev" {
+  # Secret env vars API_KEY, HF_TOKEN are not exported, set them through PATCH /api/v1/endpoints/flux-dev/env
+  name          = "flux-dev"
+  spec_name     = "h100-single"
+  image         = "wavespeed/flux:$${TAG}"
+  replicas      = 1
+  max_replicas  = 4
+  runpod_compat = true
+
+  env = {
+    HF_HOME      = "/models"
+    "model.path" = "a\"b"
+  }
+
+  volume_mount {
+    pvc_name   = "models"
+    mount_path = "/models"
+  }
+}
+
+import {
+  to = waverless_endpoint.flux_dev
+  id = "flux-dev"
+}
+`
+	assert.Equal(t, want, string(RenderHCL([]*EndpointResource{testResource()})))
+}
+
+func TestRenderJSON(t *testing.T) {
+	body, err := RenderJSON([]*EndpointResource{testResource()})
+	require.NoError(t, err)
+
+	var config struct {
+		Resource map[string]map[string]map[string]interface{} `json:"resource"`
+		Import   []map[string]string                          `json:"import"`
+	}
+	require.NoError(t, json.Unmarshal(body, &config))
+
+	block := config.Resource[ResourceType]["flux_dev"]
+	require.NotNil(t, block)
+	assert.Equal(t, "wavespeed/flux:$${TAG}", block["image"], "JSON strings are templates as well")
+	assert.Equal(t, float64(4), block["max_replicas"])
+	assert.Contains(t, block["//"], "API_KEY, HF_TOKEN")
+	assert.NotContains(t, block, "gpu_count")
+	assert.Len(t, block["volume_mount"], 1)
+	assert.Equal(t, []map[string]string{{"to": "waverless_endpoint.flux_dev", "id": "flux-dev"}}, config.Import)
+}
@@ -0,0 +1,367 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"waverless/pkg/interfaces"
+)
+
+// CopyTarget is the internal registry images are copied into.
+type CopyTarget struct {
+	Registry   string                         // Registry host, e.g. harbor.internal
+	Prefix     string                         // Repository prefix (e.g. Harbor project), e.g. waverless/upstream
+	Credential *interfaces.RegistryCredential // Push credential
+	Insecure   bool                           // Use plain HTTP
+}
+
+// CopyResult describes a completed registry-to-registry copy.
+type CopyResult struct {
+	SourceImage  string `json:"sourceImage"`
+	SourceDigest string `json:"sourceDigest"`
+	TargetImage  string `json:"targetImage"` // Internal reference pinned by digest
+	MediaType    string `json:"mediaType"`
+	BlobsCopied  int    `json:"blobsCopied"`
+	BlobsSkipped int    `json:"blobsSkipped"` // Already present in the target registry
+	BytesCopied  int64  `json:"bytesCopied"`
+	DurationMs   int64  `json:"durationMs"`
+}
+
+// ImageCopier copies images between registries over the Registry HTTP API V2,
+// without a local docker daemon. Blobs are streamed from source to target.
+type ImageCopier struct {
+	httpClient *http.Client
+}
+
+// NewImageCopier creates an ImageCopier. timeout bounds each HTTP request (blob uploads included).
+func NewImageCopier(timeout time.Duration) *ImageCopier {
+	if timeout <= 0 {
+		timeout = 10 * time.Minute
+	}
+	return &ImageCopier{httpClient: &http.Client{Timeout: timeout}}
+}
+
+// TargetRepository returns the repository an image is copied to in the target registry.
+// The normalized source registry is kept in the path so images from different
+// registries never collide, e.g. docker.io/library/nginx -> <prefix>/docker.io/library/nginx.
+func TargetRepository(image, prefix string) (string, error) {
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+	repo := NormalizeRegistry(ref.Registry) + "/" + ref.Repository
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		repo = prefix + "/" + repo
+	}
+	return repo, nil
+}
+
+// Copy copies image (by digest) from its registry into target and returns the
+// internal reference pinned to the same digest. Index manifests are copied with
+// all referenced platform manifests so the digest is preserved.
+func (c *ImageCopier) Copy(ctx context.Context, image string, srcCred *interfaces.RegistryCredential, target CopyTarget) (*CopyResult, error) {
+	start := time.Now()
+
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source image: %w", err)
+	}
+	targetRepo, err := TargetRepository(image, target.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &registryRepository{client: c.httpClient, baseURL: registryBaseURL(ref.Registry, false), name: ref.Repository, actions: "pull", cred: srcCred}
+	dst := &registryRepository{client: c.httpClient, baseURL: registryBaseURL(target.Registry, target.Insecure), name: targetRepo, actions: "pull,push", cred: target.Credential}
+
+	reference := ref.Tag
+	if ref.Digest != "" {
+		reference = ref.Digest
+	}
+
+	body, mediaType, digest, err := src.getManifest(ctx, reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch source manifest: %w", err)
+	}
+	if ref.Digest != "" && digest != ref.Digest {
+		return nil, fmt.Errorf("source manifest digest mismatch: expected %s, got %s", ref.Digest, digest)
+	}
+
+	result := &CopyResult{
+		SourceImage:  image,
+		SourceDigest: digest,
+		MediaType:    mediaType,
+	}
+
+	var doc manifestDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse source manifest: %w", err)
+	}
+
+	if doc.isIndex() {
+		for _, child := range doc.Manifests {
+			childBody, childType, _, err := src.getManifest(ctx, child.Digest)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch manifest %s: %w", child.Digest, err)
+			}
+			var childDoc manifestDocument
+			if err := json.Unmarshal(childBody, &childDoc); err != nil {
+				return nil, fmt.Errorf("failed to parse manifest %s: %w", child.Digest, err)
+			}
+			if err := c.copyBlobs(ctx, src, dst, &childDoc, result); err != nil {
+				return nil, err
+			}
+			if err := dst.putManifest(ctx, child.Digest, childType, childBody); err != nil {
+				return nil, fmt.Errorf("failed to push manifest %s: %w", child.Digest, err)
+			}
+		}
+	} else if err := c.copyBlobs(ctx, src, dst, &doc, result); err != nil {
+		return nil, err
+	}
+
+	// Push under the source tag for discoverability; deploys always use the digest
+	pushRef := digest
+	if ref.Digest == "" {
+		pushRef = ref.Tag
+	}
+	if err := dst.putManifest(ctx, pushRef, mediaType, body); err != nil {
+		return nil, fmt.Errorf("failed to push manifest: %w", err)
+	}
+
+	result.TargetImage = target.Registry + "/" + targetRepo + "@" + digest
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// copyBlobs copies the config and layers of an image manifest, skipping blobs the target already has.
+func (c *ImageCopier) copyBlobs(ctx context.Context, src, dst *registryRepository, doc *manifestDocument, result *CopyResult) error {
+	if doc.Config.Digest == "" {
+		return fmt.Errorf("unsupported manifest schema (no config descriptor)")
+	}
+
+	blobs := append([]manifestDescriptor{doc.Config}, doc.Layers...)
+	for _, blob := range blobs {
+		exists, err := dst.blobExists(ctx, blob.Digest)
+		if err != nil {
+			return fmt.Errorf("failed to check blob %s: %w", blob.Digest, err)
+		}
+		if exists {
+			result.BlobsSkipped++
+			continue
+		}
+		if err := c.copyBlob(ctx, src, dst, blob); err != nil {
+			return fmt.Errorf("failed to copy blob %s: %w", blob.Digest, err)
+		}
+		result.BlobsCopied++
+		result.BytesCopied += blob.Size
+	}
+	return nil
+}
+
+// copyBlob streams a single blob from src into a monolithic upload on dst.
+func (c *ImageCopier) copyBlob(ctx context.Context, src, dst *registryRepository, blob manifestDescriptor) error {
+	location, err := dst.startUpload(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := src.do(ctx, http.MethodGet, src.url("/blobs/"+blob.Digest), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("source blob request returned status %d", resp.StatusCode)
+	}
+
+	uploadURL, err := url.Parse(location)
+	if err != nil {
+		return fmt.Errorf("invalid upload location: %w", err)
+	}
+	q := uploadURL.Query()
+	q.Set("digest", blob.Digest)
+	uploadURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL.String(), resp.Body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = blob.Size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	dst.authorize(req)
+
+	putResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer putResp.Body.Close()
+	if putResp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("blob upload returned status %d", putResp.StatusCode)
+	}
+	return nil
+}
+
+// registryRepository is an authenticated session against one repository of a registry.
+// Bearer tokens (or basic credentials) are obtained on the first 401 and reused.
+type registryRepository struct {
+	client        *http.Client
+	baseURL       string // scheme://host
+	name          string // Repository path
+	actions       string // Token scope actions, e.g. "pull" or "pull,push"
+	cred          *interfaces.RegistryCredential
+	authorization string
+}
+
+func (r *registryRepository) url(path string) string {
+	return r.baseURL + "/v2/" + r.name + path
+}
+
+func (r *registryRepository) authorize(req *http.Request) {
+	if r.authorization != "" {
+		req.Header.Set("Authorization", r.authorization)
+	}
+}
+
+// do sends a request, authenticating and retrying once on 401. body must be replayable.
+func (r *registryRepository) do(ctx context.Context, method, target string, body []byte, header http.Header) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, target, reader)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		r.authorize(req)
+		return r.client.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	if err := r.authenticate(ctx, challenge); err != nil {
+		return nil, err
+	}
+	return send()
+}
+
+// authenticate resolves an Authorization header from a WWW-Authenticate challenge.
+func (r *registryRepository) authenticate(ctx context.Context, challenge string) error {
+	if strings.HasPrefix(strings.ToLower(challenge), "basic") {
+		if r.cred == nil || r.cred.Username == "" {
+			return fmt.Errorf("registry requires credentials")
+		}
+		r.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(r.cred.Username+":"+r.cred.Password))
+		return nil
+	}
+
+	auth, err := parseWWWAuthenticate(challenge)
+	if err != nil {
+		return fmt.Errorf("failed to parse authentication challenge: %w", err)
+	}
+	token, err := fetchRegistryToken(ctx, r.client, auth, fmt.Sprintf("repository:%s:%s", r.name, r.actions), r.cred)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	r.authorization = "Bearer " + token
+	return nil
+}
+
+// getManifest fetches a manifest by tag or digest, returning raw body, media type and digest.
+func (r *registryRepository) getManifest(ctx context.Context, reference string) ([]byte, string, string, error) {
+	resp, err := r.do(ctx, http.MethodGet, r.url("/manifests/"+reference), nil, http.Header{"Accept": {manifestAcceptTypes}})
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("manifest request returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestBytes))
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	mediaType := strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	var doc struct {
+		MediaType string `json:"mediaType"`
+	}
+	if json.Unmarshal(body, &doc) == nil && doc.MediaType != "" {
+		mediaType = doc.MediaType
+	}
+
+	// The digest is computed locally so it always matches the pushed bytes
+	sum := sha256.Sum256(body)
+	return body, mediaType, "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// putManifest uploads a manifest under a tag or digest.
+func (r *registryRepository) putManifest(ctx context.Context, reference, mediaType string, body []byte) error {
+	resp, err := r.do(ctx, http.MethodPut, r.url("/manifests/"+reference), body, http.Header{"Content-Type": {mediaType}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("manifest upload returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// blobExists reports whether the repository already has a blob.
+func (r *registryRepository) blobExists(ctx context.Context, digest string) (bool, error) {
+	resp, err := r.do(ctx, http.MethodHead, r.url("/blobs/"+digest), nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("blob check returned status %d", resp.StatusCode)
+	}
+}
+
+// startUpload opens a blob upload session and returns its absolute location.
+func (r *registryRepository) startUpload(ctx context.Context) (string, error) {
+	resp, err := r.do(ctx, http.MethodPost, r.url("/blobs/uploads/"), nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("upload request returned status %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || location.String() == "" {
+		return "", fmt.Errorf("upload response has no valid location")
+	}
+	base, err := url.Parse(r.baseURL)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(location).String(), nil
+}
@@ -0,0 +1,130 @@
+package image
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry is a minimal in-memory Registry API V2 server.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	manifests map[string][]byte // repo/manifests/ref -> body
+	blobs     map[string][]byte // digest -> content
+	uploads   int
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case strings.Contains(path, "/blobs/uploads/") && r.Method == http.MethodPost:
+		w.Header().Set("Location", "/upload/1")
+		w.WriteHeader(http.StatusAccepted)
+	case strings.HasPrefix(r.URL.Path, "/upload/") && r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.blobs[r.URL.Query().Get("digest")] = body
+		f.uploads++
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/blobs/"):
+		digest := path[strings.LastIndex(path, "/")+1:]
+		blob, ok := f.blobs[digest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write(blob)
+		}
+	case strings.Contains(path, "/manifests/"):
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			f.manifests[path] = body
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		body, ok := f.manifests[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+		w.Write(body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func sha(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// TestImageCopier_Copy verifies manifests and blobs are copied and the target is pinned by digest.
+func TestImageCopier_Copy(t *testing.T) {
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	layer := []byte("layer-content")
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json",` +
+		`"config":{"digest":"` + sha(config) + `","size":` + strconv.Itoa(len(config)) + `},` +
+		`"layers":[{"digest":"` + sha(layer) + `","size":` + strconv.Itoa(len(layer)) + `}]}`)
+
+	source := newFakeRegistry()
+	source.blobs[sha(config)] = config
+	source.blobs[sha(layer)] = layer
+	source.manifests["team/model/manifests/v1"] = manifest
+	srcServer := httptest.NewServer(source)
+	defer srcServer.Close()
+
+	target := newFakeRegistry()
+	dstServer := httptest.NewServer(target)
+	defer dstServer.Close()
+
+	srcHost := strings.TrimPrefix(srcServer.URL, "http://")
+	dstHost := strings.TrimPrefix(dstServer.URL, "http://")
+
+	copier := NewImageCopier(0)
+	result, err := copier.Copy(context.Background(), srcHost+"/team/model:v1", nil, CopyTarget{Registry: dstHost, Prefix: "mirror"})
+	require.NoError(t, err)
+
+	digest := sha(manifest)
+	assert.Equal(t, digest, result.SourceDigest)
+	assert.Equal(t, dstHost+"/mirror/"+srcHost+"/team/model@"+digest, result.TargetImage)
+	assert.Equal(t, 2, result.BlobsCopied)
+	assert.Equal(t, int64(len(config)+len(layer)), result.BytesCopied)
+	assert.Equal(t, layer, target.blobs[sha(layer)])
+	assert.Equal(t, manifest, target.manifests["mirror/"+srcHost+"/team/model/manifests/v1"])
+
+	// Second copy skips blobs already present in the target
+	result, err = copier.Copy(context.Background(), srcHost+"/team/model:v1", nil, CopyTarget{Registry: dstHost, Prefix: "mirror"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.BlobsCopied)
+	assert.Equal(t, 2, result.BlobsSkipped)
+	assert.Equal(t, 2, target.uploads)
+}
+
+// TestTargetRepository tests internal repository naming.
+func TestTargetRepository(t *testing.T) {
+	repo, err := TargetRepository("nginx:1.25", "waverless")
+	require.NoError(t, err)
+	assert.Equal(t, "waverless/docker.io/library/nginx", repo)
+
+	repo, err = TargetRepository("ghcr.io/org/app@sha256:abcdefabcdefabcdefabcdefabcdefabcdef", "")
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/org/app", repo)
+}
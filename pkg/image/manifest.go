@@ -0,0 +1,202 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"waverless/pkg/interfaces"
+)
+
+// DefaultPlatform is the platform workers run on; index manifests are resolved against it.
+const DefaultPlatform = "linux/amd64"
+
+// maxManifestBytes bounds manifest/config reads (manifests are small JSON documents).
+const maxManifestBytes = 4 << 20
+
+// manifestAcceptTypes are the manifest media types accepted from registries.
+var manifestAcceptTypes = strings.Join([]string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}, ", ")
+
+// manifestPlatform is the platform of an index entry or image config.
+type manifestPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+func (p manifestPlatform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// manifestDescriptor references a blob or a child manifest.
+type manifestDescriptor struct {
+	MediaType string            `json:"mediaType"`
+	Digest    string            `json:"digest"`
+	Size      int64             `json:"size"`
+	Platform  *manifestPlatform `json:"platform,omitempty"`
+}
+
+// manifestDocument covers both image manifests and manifest lists / OCI indexes.
+type manifestDocument struct {
+	MediaType string               `json:"mediaType"`
+	Config    manifestDescriptor   `json:"config"`
+	Layers    []manifestDescriptor `json:"layers"`
+	Manifests []manifestDescriptor `json:"manifests"`
+}
+
+func (d *manifestDocument) isIndex() bool {
+	return len(d.Manifests) > 0
+}
+
+// compressedSize returns config + layer sizes of an image manifest.
+func (d *manifestDocument) compressedSize() int64 {
+	size := d.Config.Size
+	for _, l := range d.Layers {
+		size += l.Size
+	}
+	return size
+}
+
+// selectPlatform picks the index entry matching DefaultPlatform, or the first entry.
+func (d *manifestDocument) selectPlatform() *manifestDescriptor {
+	for i := range d.Manifests {
+		p := d.Manifests[i].Platform
+		if p != nil && p.OS+"/"+p.Architecture == DefaultPlatform {
+			return &d.Manifests[i]
+		}
+	}
+	if len(d.Manifests) > 0 {
+		return &d.Manifests[0]
+	}
+	return nil
+}
+
+// enrichManifestDetails fills digest, media type, size and platforms into an
+// already successful validation result. It is best effort: any failure leaves
+// the details empty and never changes the validation outcome.
+func (v *ImageValidator) enrichManifestDetails(ctx context.Context, manifestURL, token string, result *interfaces.ImageValidationResult) {
+	doc, digest, mediaType, err := v.fetchManifest(ctx, manifestURL, token)
+	if err != nil {
+		return
+	}
+	result.Digest = digest
+	result.MediaType = mediaType
+
+	baseURL := manifestURL[:strings.LastIndex(manifestURL, "/manifests/")]
+
+	if doc.isIndex() {
+		for _, m := range doc.Manifests {
+			if m.Platform != nil && m.Platform.OS != "unknown" {
+				result.Platforms = append(result.Platforms, m.Platform.String())
+			}
+		}
+		selected := doc.selectPlatform()
+		if selected == nil {
+			return
+		}
+		child, _, _, err := v.fetchManifest(ctx, baseURL+"/manifests/"+selected.Digest, token)
+		if err != nil {
+			return
+		}
+		result.SizeBytes = child.compressedSize()
+		return
+	}
+
+	result.SizeBytes = doc.compressedSize()
+	if doc.Config.Digest != "" {
+		if platform, err := v.fetchConfigPlatform(ctx, baseURL+"/blobs/"+doc.Config.Digest, token); err == nil && platform.OS != "" {
+			result.Platforms = []string{platform.String()}
+		}
+	}
+}
+
+// fetchManifest GETs and parses a manifest, returning its digest and media type.
+func (v *ImageValidator) fetchManifest(ctx context.Context, manifestURL, token string) (*manifestDocument, string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", manifestURL, nil)
+	if err != nil {
+		return nil, "", "", err
+	}
+	req.Header.Set("Accept", manifestAcceptTypes)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("manifest request returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestBytes))
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	var doc manifestDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, "", "", fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	mediaType := doc.MediaType
+	if mediaType == "" {
+		mediaType = strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	}
+	return &doc, resp.Header.Get("Docker-Content-Digest"), mediaType, nil
+}
+
+// fetchConfigPlatform reads os/architecture from an image config blob.
+func (v *ImageValidator) fetchConfigPlatform(ctx context.Context, blobURL, token string) (*manifestPlatform, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", blobURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("config blob request returned status %d", resp.StatusCode)
+	}
+
+	var platform manifestPlatform
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestBytes)).Decode(&platform); err != nil {
+		return nil, fmt.Errorf("failed to parse image config: %w", err)
+	}
+	return &platform, nil
+}
+
+// SupportsPlatform reports whether the platforms list includes the given os/arch.
+// An empty list (unknown platforms) is treated as supported.
+func SupportsPlatform(platforms []string, platform string) bool {
+	if len(platforms) == 0 {
+		return true
+	}
+	for _, p := range platforms {
+		if p == platform || strings.HasPrefix(p, platform+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package image
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckImageExists_ManifestDetails verifies digest, size and platform are extracted from an image manifest.
+func TestCheckImageExists_ManifestDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/manifests/"):
+			w.Header().Set("Docker-Content-Digest", "sha256:aaaa")
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.Write([]byte(`{
+				"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+				"config": {"digest": "sha256:cfg", "size": 100},
+				"layers": [{"digest": "sha256:l1", "size": 1000}, {"digest": "sha256:l2", "size": 2000}]
+			}`))
+		case strings.HasSuffix(r.URL.Path, "/blobs/sha256:cfg"):
+			w.Write([]byte(`{"architecture": "amd64", "os": "linux"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	validator := NewImageValidator(nil)
+	image := strings.TrimPrefix(server.URL, "http://") + "/myimage:latest"
+
+	result, err := validator.CheckImageExists(context.Background(), image, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Exists)
+	assert.False(t, result.CacheHit)
+	assert.Equal(t, "sha256:aaaa", result.Digest)
+	assert.Equal(t, int64(3100), result.SizeBytes)
+	assert.Equal(t, []string{"linux/amd64"}, result.Platforms)
+
+	// Second call is served from cache
+	cached, err := validator.CheckImageExists(context.Background(), image, nil)
+	require.NoError(t, err)
+	assert.True(t, cached.CacheHit)
+	assert.Equal(t, "sha256:aaaa", cached.Digest)
+}
+
+// TestCheckImageExists_IndexManifest verifies platforms are listed and size is taken from the linux/amd64 entry.
+func TestCheckImageExists_IndexManifest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/manifests/latest"):
+			w.Header().Set("Docker-Content-Digest", "sha256:index")
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.Write([]byte(`{
+				"mediaType": "application/vnd.oci.image.index.v1+json",
+				"manifests": [
+					{"digest": "sha256:arm", "size": 10, "platform": {"architecture": "arm64", "os": "linux"}},
+					{"digest": "sha256:amd", "size": 10, "platform": {"architecture": "amd64", "os": "linux"}},
+					{"digest": "sha256:att", "size": 10, "platform": {"architecture": "unknown", "os": "unknown"}}
+				]
+			}`))
+		case strings.HasSuffix(r.URL.Path, "/manifests/sha256:amd"):
+			w.Write([]byte(`{"config": {"size": 5}, "layers": [{"size": 500}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	validator := NewImageValidator(nil)
+	image := strings.TrimPrefix(server.URL, "http://") + "/myimage:latest"
+
+	result, err := validator.CheckImageExists(context.Background(), image, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:index", result.Digest)
+	assert.Equal(t, "application/vnd.oci.image.index.v1+json", result.MediaType)
+	assert.Equal(t, []string{"linux/arm64", "linux/amd64"}, result.Platforms)
+	assert.Equal(t, int64(505), result.SizeBytes)
+}
+
+// TestSupportsPlatform tests platform matching.
+func TestSupportsPlatform(t *testing.T) {
+	assert.True(t, SupportsPlatform(nil, DefaultPlatform))
+	assert.True(t, SupportsPlatform([]string{"linux/amd64"}, DefaultPlatform))
+	assert.True(t, SupportsPlatform([]string{"linux/amd64/v3"}, DefaultPlatform))
+	assert.False(t, SupportsPlatform([]string{"linux/arm64"}, DefaultPlatform))
+}
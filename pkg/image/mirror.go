@@ -0,0 +1,103 @@
+package image
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dockerHubRegistry is the canonical name used for Docker Hub in mirror mappings.
+const dockerHubRegistry = "docker.io"
+
+// RegistryMirror maps an upstream registry to an in-cluster mirror / pull-through cache.
+// Mirror may include a path prefix, e.g. "harbor.internal/dockerhub-proxy".
+type RegistryMirror struct {
+	Upstream string `json:"upstream" yaml:"upstream"` // e.g. docker.io, ghcr.io, nvcr.io
+	Mirror   string `json:"mirror" yaml:"mirror"`     // e.g. harbor.internal/dockerhub-proxy
+}
+
+// NormalizeRegistry canonicalizes a registry host for mirror lookup.
+// All Docker Hub aliases map to "docker.io".
+func NormalizeRegistry(registry string) string {
+	registry = strings.ToLower(strings.TrimSpace(registry))
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	registry = strings.TrimSuffix(registry, "/")
+	switch registry {
+	case "", "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHubRegistry
+	}
+	return registry
+}
+
+// ValidateRegistryMirror checks that a mapping has a valid upstream host and mirror reference.
+func ValidateRegistryMirror(m RegistryMirror) error {
+	upstream := strings.TrimSpace(m.Upstream)
+	if upstream == "" {
+		return fmt.Errorf("upstream registry is required")
+	}
+	if strings.Contains(NormalizeRegistry(upstream), "/") {
+		return fmt.Errorf("upstream must be a registry host without path: %s", upstream)
+	}
+	mirror := strings.TrimSuffix(strings.TrimSpace(m.Mirror), "/")
+	if mirror == "" {
+		return fmt.Errorf("mirror is required")
+	}
+	if strings.Contains(mirror, "://") {
+		return fmt.Errorf("mirror must not include a scheme: %s", mirror)
+	}
+	host := strings.SplitN(mirror, "/", 2)[0]
+	if err := validateRegistry(host); err != nil {
+		return err
+	}
+	if NormalizeRegistry(host) == NormalizeRegistry(upstream) {
+		return fmt.Errorf("mirror cannot point to the upstream registry itself")
+	}
+	return nil
+}
+
+// splitImageRegistry splits an image reference into its (normalized) registry and
+// the remainder (repository with tag/digest). Docker Hub official images get the
+// implicit "library/" namespace so the remainder is valid under any mirror.
+func splitImageRegistry(image string) (registry, remainder string) {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && isRegistry(parts[0]) {
+		registry = NormalizeRegistry(parts[0])
+		remainder = parts[1]
+	} else {
+		registry = dockerHubRegistry
+		remainder = image
+	}
+	if registry == dockerHubRegistry && !strings.Contains(remainder, "/") {
+		remainder = "library/" + remainder
+	}
+	return registry, remainder
+}
+
+// ImageRegistry returns the normalized registry host of an image reference.
+func ImageRegistry(image string) string {
+	registry, _ := splitImageRegistry(strings.TrimSpace(image))
+	return registry
+}
+
+// RewriteImage returns the image reference served through the configured mirror for
+// its registry. The second return value reports whether a mirror was applied.
+// Images already pointing to a mirror (or without a mapping) are returned unchanged.
+func RewriteImage(image string, mirrors []RegistryMirror) (string, bool) {
+	image = strings.TrimSpace(image)
+	if image == "" || len(mirrors) == 0 {
+		return image, false
+	}
+
+	registry, remainder := splitImageRegistry(image)
+	for _, m := range mirrors {
+		if NormalizeRegistry(m.Upstream) != registry {
+			continue
+		}
+		mirror := strings.TrimSuffix(strings.TrimSpace(m.Mirror), "/")
+		if mirror == "" {
+			continue
+		}
+		return mirror + "/" + remainder, true
+	}
+	return image, false
+}
@@ -0,0 +1,60 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRewriteImage tests registry mirror rewriting for common image reference forms.
+func TestRewriteImage(t *testing.T) {
+	mirrors := []RegistryMirror{
+		{Upstream: "docker.io", Mirror: "harbor.internal/dockerhub-proxy/"},
+		{Upstream: "ghcr.io", Mirror: "harbor.internal/ghcr-proxy"},
+	}
+
+	tests := []struct {
+		image    string
+		expected string
+		applied  bool
+	}{
+		{"nginx", "harbor.internal/dockerhub-proxy/library/nginx", true},
+		{"nginx:1.25", "harbor.internal/dockerhub-proxy/library/nginx:1.25", true},
+		{"user/repo:tag", "harbor.internal/dockerhub-proxy/user/repo:tag", true},
+		{"docker.io/user/repo:tag", "harbor.internal/dockerhub-proxy/user/repo:tag", true},
+		{"index.docker.io/library/redis", "harbor.internal/dockerhub-proxy/library/redis", true},
+		{"ghcr.io/x/y@sha256:abcd", "harbor.internal/ghcr-proxy/x/y@sha256:abcd", true},
+		{"nvcr.io/nvidia/pytorch:24.01", "nvcr.io/nvidia/pytorch:24.01", false},
+		{"localhost:5000/app", "localhost:5000/app", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, ok := RewriteImage(tt.image, mirrors)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.applied, ok)
+		})
+	}
+
+	got, ok := RewriteImage("nginx", nil)
+	assert.Equal(t, "nginx", got)
+	assert.False(t, ok)
+}
+
+// TestNormalizeRegistry tests Docker Hub alias handling.
+func TestNormalizeRegistry(t *testing.T) {
+	assert.Equal(t, "docker.io", NormalizeRegistry("index.docker.io"))
+	assert.Equal(t, "docker.io", NormalizeRegistry("https://registry-1.docker.io/"))
+	assert.Equal(t, "docker.io", NormalizeRegistry(""))
+	assert.Equal(t, "ghcr.io", NormalizeRegistry(" GHCR.io "))
+}
+
+// TestValidateRegistryMirror tests mapping validation.
+func TestValidateRegistryMirror(t *testing.T) {
+	assert.NoError(t, ValidateRegistryMirror(RegistryMirror{Upstream: "docker.io", Mirror: "harbor.internal/proxy"}))
+	assert.Error(t, ValidateRegistryMirror(RegistryMirror{Upstream: "", Mirror: "harbor.internal"}))
+	assert.Error(t, ValidateRegistryMirror(RegistryMirror{Upstream: "docker.io/library", Mirror: "harbor.internal"}))
+	assert.Error(t, ValidateRegistryMirror(RegistryMirror{Upstream: "docker.io", Mirror: ""}))
+	assert.Error(t, ValidateRegistryMirror(RegistryMirror{Upstream: "docker.io", Mirror: "https://harbor.internal"}))
+	assert.Error(t, ValidateRegistryMirror(RegistryMirror{Upstream: "docker.io", Mirror: "index.docker.io/proxy"}))
+}
@@ -402,9 +402,14 @@ func (v *ImageValidator) CheckImageExists(ctx context.Context, image string, cre
 		}, nil
 	}
 
+	start := time.Now()
+
 	// Check cache first
 	if cached := v.cache.Get(image); cached != nil {
-		return cached, nil
+		hit := *cached
+		hit.CacheHit = true
+		hit.DurationMs = time.Since(start).Milliseconds()
+		return &hit, nil
 	}
 
 	// Parse image reference
@@ -423,6 +428,7 @@ func (v *ImageValidator) CheckImageExists(ctx context.Context, image string, cre
 
 	// Check manifest with optional authentication
 	result := v.checkManifest(ctx, manifestURL, ref, cred)
+	result.DurationMs = time.Since(start).Milliseconds()
 
 	// Cache successful results
 	if result.Valid && result.Exists && result.Accessible {
@@ -517,20 +523,18 @@ func buildManifestURL(ref *imageReference) string {
 		reference = ref.Digest
 	}
 
-	// Determine scheme - use HTTP for localhost/IP addresses (typically test servers)
+	return fmt.Sprintf("%s/v2/%s/manifests/%s", registryBaseURL(ref.Registry, false), ref.Repository, reference)
+}
+
+// registryBaseURL returns the scheme and host of a registry.
+// HTTP is used for localhost/IP test servers or when insecure is set.
+func registryBaseURL(host string, insecure bool) string {
 	scheme := "https"
-	host := ref.Registry
-	if strings.Contains(host, ":") {
-		// Remove port for localhost check
-		hostWithoutPort := strings.Split(host, ":")[0]
-		if hostWithoutPort == "localhost" || hostWithoutPort == "127.0.0.1" {
-			scheme = "http"
-		}
-	} else if host == "localhost" || host == "127.0.0.1" {
+	hostWithoutPort := strings.Split(host, ":")[0]
+	if insecure || hostWithoutPort == "localhost" || hostWithoutPort == "127.0.0.1" {
 		scheme = "http"
 	}
-
-	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, ref.Registry, ref.Repository, reference)
+	return scheme + "://" + host
 }
 
 // checkManifest checks if the manifest exists, handling authentication
@@ -595,12 +599,14 @@ func (v *ImageValidator) checkManifest(ctx context.Context, manifestURL string,
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return &interfaces.ImageValidationResult{
+		result := &interfaces.ImageValidationResult{
 			Valid:      true,
 			Exists:     true,
 			Accessible: true,
 			CheckedAt:  time.Now(),
 		}
+		v.enrichManifestDetails(ctx, manifestURL, "", result)
+		return result
 
 	case http.StatusUnauthorized:
 		// Need authentication
@@ -704,12 +710,14 @@ func (v *ImageValidator) checkManifestWithAuth(ctx context.Context, manifestURL,
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return &interfaces.ImageValidationResult{
+		result := &interfaces.ImageValidationResult{
 			Valid:      true,
 			Exists:     true,
 			Accessible: true,
 			CheckedAt:  time.Now(),
 		}
+		v.enrichManifestDetails(ctx, manifestURL, token, result)
+		return result
 
 	case http.StatusUnauthorized:
 		return &interfaces.ImageValidationResult{
@@ -800,6 +808,16 @@ type tokenResponse struct {
 
 // getAuthToken gets an authentication token from the token service
 func (v *ImageValidator) getAuthToken(ctx context.Context, auth *authInfo, ref *imageReference, cred *interfaces.RegistryCredential) (string, error) {
+	// Build scope if not provided
+	scope := auth.Scope
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", ref.Repository)
+	}
+	return fetchRegistryToken(ctx, v.httpClient, auth, scope, cred)
+}
+
+// fetchRegistryToken requests a bearer token for the given scope from the realm in auth
+func fetchRegistryToken(ctx context.Context, client *http.Client, auth *authInfo, scope string, cred *interfaces.RegistryCredential) (string, error) {
 	// Build token request URL
 	tokenURL, err := url.Parse(auth.Realm)
 	if err != nil {
@@ -811,12 +829,6 @@ func (v *ImageValidator) getAuthToken(ctx context.Context, auth *authInfo, ref *
 	if auth.Service != "" {
 		q.Set("service", auth.Service)
 	}
-
-	// Build scope if not provided
-	scope := auth.Scope
-	if scope == "" {
-		scope = fmt.Sprintf("repository:%s:pull", ref.Repository)
-	}
 	q.Set("scope", scope)
 
 	tokenURL.RawQuery = q.Encode()
@@ -834,7 +846,7 @@ func (v *ImageValidator) getAuthToken(ctx context.Context, auth *authInfo, ref *
 	}
 
 	// Make request
-	resp, err := v.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("token request failed: %w", err)
 	}
@@ -8,6 +8,7 @@ type EndpointConfig struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"displayName,omitempty"`
 	SpecName    string `json:"specName,omitempty"` // Spec name (for resource calculation, avoid repeated queries)
+	Image       string `json:"image,omitempty"`    // Image (for the startup time estimate)
 
 	// Replica configuration
 	MinReplicas int `json:"minReplicas"` // Minimum replica count (default 0)
@@ -30,12 +31,43 @@ type EndpointConfig struct {
 	HighLoadThreshold int `json:"highLoadThreshold"` // High load threshold (queued task count), temporarily boost priority when exceeded, default 10
 	PriorityBoost     int `json:"priorityBoost"`     // Priority boost amount during high load, default 20
 
+	// Custom metric scaling: workers report a gauge (e.g. internal batch queue length) in heartbeats
+	// and replicas are sized so the per-worker average stays at the target. 0 = disabled
+	CustomMetricName   string  `json:"customMetricName,omitempty"`   // Display name of the reported gauge
+	CustomMetricTarget float64 `json:"customMetricTarget,omitempty"` // Setpoint per worker
+
+	// Endpoint group whose shared budget caps this endpoint's scale-ups (empty = none)
+	GroupName string `json:"groupName,omitempty"`
+
+	// Seconds between evaluations of this endpoint (0 = the global autoscaler interval)
+	EvaluationInterval int `json:"evaluationInterval,omitempty"`
+
+	// Longest a task should wait in the queue (seconds, 0 = none): scale-ups start ahead of a
+	// growing queue when new workers would otherwise be ready too late
+	QueueWaitSLO int `json:"queueWaitSLO,omitempty"`
+
+	// Metric sources the endpoint is also sized by (GPU utilization, request latency, Prometheus
+	// query); the target is the max of the queue-based replicas and every source's replicas
+	MetricSources []MetricSource `json:"metricSources,omitempty"`
+
+	// Time windows with their own min/max replicas (pre-warming before known peaks, shrinking at night)
+	ReplicaSchedules []ReplicaSchedule `json:"replicaSchedules,omitempty"`
+
+	// Resource profile ("latency", "throughput", "economy") the settings were last expanded from
+	Profile string `json:"profile,omitempty"`
+
 	// Autoscaler switch override configuration
 	// nil/"" = follow global setting (default)
 	// "disabled" = force disable autoscaling for this endpoint
 	// "enabled" = force enable autoscaling for this endpoint
 	AutoscalerEnabled *string `json:"autoscalerEnabled,omitempty"`
 
+	// Task dispatch of the endpoint is paused: replicas are held as they are
+	DispatchPaused bool `json:"dispatchPaused,omitempty"`
+
+	// Circuit breaker state (circuit.State*): no scale-up while open, at most one replica while half-open
+	CircuitState string `json:"circuitState,omitempty"`
+
 	// Runtime state (not persisted)
 	ActualReplicas    int                `json:"actualReplicas,omitempty"`    // K8s actual running replica count
 	AvailableReplicas int                `json:"availableReplicas,omitempty"` // Available replica count
@@ -43,9 +75,56 @@ type EndpointConfig struct {
 	DrainingReplicas  int                `json:"drainingReplicas,omitempty"`  // Draining replica count
 	PendingTasks      int64              `json:"pendingTasks,omitempty"`      // Current queued task count
 	RunningTasks      int64              `json:"runningTasks,omitempty"`      // Current running task count
+	CustomMetricValue float64            `json:"customMetricValue,omitempty"` // Average custom metric across reporting workers
+	CustomMetricCount int                `json:"customMetricCount,omitempty"` // Workers with a fresh custom metric report
+	MetricValues      []MetricValue      `json:"metricValues,omitempty"`      // Latest reading of each metric source
+	ActiveSchedule    string             `json:"activeSchedule,omitempty"`    // Replica schedule whose min/max replicas apply now
 	LastScaleTime     time.Time          `json:"lastScaleTime,omitempty"`     // Last scaling time
 	LastTaskTime      time.Time          `json:"lastTaskTime,omitempty"`      // Last task processing time
 	FirstPendingTime  time.Time          `json:"firstPendingTime,omitempty"`  // First task queue time (for starvation detection)
+	QueueGrowthRate   float64            `json:"queueGrowthRate,omitempty"`   // Smoothed change of the queued task count (tasks/s)
+	StartupEstimate   time.Duration      `json:"startupEstimate,omitempty"`   // Typical time from scale-up to worker ready for the spec/image
+}
+
+// Metric source types
+const (
+	MetricSourceGPUUtilization = "gpu_utilization" // Average GPU utilization of the endpoint's workers (%)
+	MetricSourceLatency        = "latency"         // Average request latency, queue wait + execution (ms)
+	MetricSourcePrometheus     = "prometheus"      // Result of a custom Prometheus query
+)
+
+// Target types of a Prometheus metric source
+const (
+	MetricTargetAverageValue = "averageValue" // Target per replica: replicas = value / target
+	MetricTargetValue        = "value"        // Target for the whole endpoint: replicas = current * value / target
+)
+
+// MetricSource is a metric an endpoint is sized by, HPA style
+type MetricSource struct {
+	Type       string  `json:"type"`                 // MetricSource* constant
+	Target     float64 `json:"target"`               // Target value (%, ms or query result)
+	Query      string  `json:"query,omitempty"`      // PromQL query (prometheus only)
+	TargetType string  `json:"targetType,omitempty"` // MetricTarget* constant (prometheus only, default averageValue)
+}
+
+// MetricValue is the latest reading of a metric source and the replicas it asks for
+type MetricValue struct {
+	Type     string  `json:"type"`
+	Value    float64 `json:"value"`
+	Target   float64 `json:"target"`
+	Replicas int     `json:"replicas"`        // 0 = no opinion (no reading or no ready replicas)
+	Error    string  `json:"error,omitempty"` // Why the source couldn't be read
+}
+
+// ReplicaSchedule overrides the min/max replicas of an endpoint for a time window starting at
+// every fire time of a cron expression. When windows overlap, the one that started last applies.
+type ReplicaSchedule struct {
+	Name            string `json:"name"`               // Unique per endpoint
+	Cron            string `json:"cron"`               // 5-field cron expression of the window starts (e.g. "30 7 * * 1-5")
+	DurationMinutes int    `json:"durationMinutes"`    // Window length
+	Timezone        string `json:"timezone,omitempty"` // IANA time zone of the cron expression (default UTC)
+	MinReplicas     int    `json:"minReplicas"`
+	MaxReplicas     int    `json:"maxReplicas"`
 }
 
 // EffectivePriority calculates effective priority (including dynamic adjustments)
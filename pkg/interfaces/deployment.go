@@ -67,6 +67,89 @@ type DeploymentProvider interface {
 	IsPodTerminating(ctx context.Context, podName string) (bool, error)
 }
 
+// ProviderRouter is implemented by deployment providers that dispatch each endpoint to one of
+// several providers (optional capability)
+type ProviderRouter interface {
+	// Route returns the name and provider serving endpoint. requested pins a new endpoint to a
+	// provider; empty means the endpoint's pinned provider, or the default for new endpoints.
+	Route(ctx context.Context, endpoint, requested string) (string, DeploymentProvider, error)
+
+	// Providers returns all providers, the default first
+	Providers() []DeploymentProvider
+}
+
+// ResolveProvider returns the provider serving endpoint: p itself, or the provider a router
+// dispatches the endpoint to. Optional capabilities must be checked on the resolved provider.
+// If routing fails, p is returned.
+func ResolveProvider(ctx context.Context, p DeploymentProvider, endpoint, requested string) DeploymentProvider {
+	router, ok := p.(ProviderRouter)
+	if !ok {
+		return p
+	}
+	_, resolved, err := router.Route(ctx, endpoint, requested)
+	if err != nil {
+		return p
+	}
+	return resolved
+}
+
+// DeploymentDryRunner is implemented by providers that can validate a deploy or update
+// against the target platform without changing anything (optional capability)
+type DeploymentDryRunner interface {
+	// DryRunDeploy runs Deploy up to, but not including, persisting any resource
+	DryRunDeploy(ctx context.Context, req *DeployRequest) (*DryRunResult, error)
+
+	// DryRunUpdateDeployment runs UpdateDeployment up to, but not including, persisting any resource
+	DryRunUpdateDeployment(ctx context.Context, req *UpdateDeploymentRequest) (*DryRunResult, error)
+}
+
+// Resource change actions reported by a dry run
+const (
+	ChangeActionCreate    = "create"
+	ChangeActionUpdate    = "update"
+	ChangeActionUnchanged = "unchanged"
+)
+
+// ResourceChange is the effect a deploy or update would have on one resource
+type ResourceChange struct {
+	Kind   string   `json:"kind"`             // e.g. Deployment, Secret, NetworkPolicy, Endpoint
+	Name   string   `json:"name"`             // Resource name
+	Action string   `json:"action"`           // create, update, unchanged
+	Fields []string `json:"fields,omitempty"` // Changed fields (update only)
+}
+
+// DryRunResult is what a provider would apply for a deploy or update
+type DryRunResult struct {
+	Changes  []ResourceChange `json:"changes"`
+	Warnings []string         `json:"warnings,omitempty"`
+	Manifest string           `json:"manifest,omitempty"` // Rendered resources, when the provider renders any
+
+	// Current and Proposed describe the deployment before and after an update, when the
+	// provider can describe it (see DeploymentViewer)
+	Current  *DeploymentView `json:"current,omitempty"`
+	Proposed *DeploymentView `json:"proposed,omitempty"`
+}
+
+// DeploymentViewer is implemented by providers that can describe the runtime state of a
+// deployment, live and as a deploy request would render it (optional capability)
+type DeploymentViewer interface {
+	// ViewDeployment returns the live state (nil when not deployed) and the state req would produce
+	ViewDeployment(ctx context.Context, req *DeployRequest) (current, proposed *DeploymentView, err error)
+
+	// LiveDeployment returns the live state of an endpoint's deployment, nil when not deployed
+	LiveDeployment(ctx context.Context, endpoint string) (*DeploymentView, error)
+}
+
+// DeploymentView is the reviewable part of a deployment's runtime state
+type DeploymentView struct {
+	Image     string            `json:"image"`
+	Resources map[string]string `json:"resources,omitempty"` // e.g. "limits.nvidia.com/gpu" -> "1"
+	Env       map[string]string `json:"env,omitempty"`       // Name -> value (or a valueFrom reference)
+	Volumes   map[string]string `json:"volumes,omitempty"`   // Mount path -> source, e.g. "pvc:models"
+
+	Tolerations map[string]string `json:"tolerations,omitempty"` // "key:effect" -> operator and value, e.g. "nvidia.com/gpu:NoSchedule" -> "Exists"
+}
+
 // ReplicaEvent represents Deployment replica change event
 type ReplicaEvent struct {
 	Name              string             `json:"name"`
@@ -94,19 +177,165 @@ type VolumeMount struct {
 
 // DeployRequest deployment request
 type DeployRequest struct {
-	Endpoint           string              `json:"endpoint"`                // Application name/endpoint
-	SpecName           string              `json:"specName"`                // Spec name
-	Image              string              `json:"image"`                   // Docker image
-	Replicas           int                 `json:"replicas"`                // Replica count
-	GpuCount           int                 `json:"gpuCount"`                // GPU count (1-N, resources = per-gpu-config * gpuCount)
-	TaskTimeout        int                 `json:"taskTimeout"`             // Task execution timeout in seconds (0 = use global default)
-	Env                map[string]string   `json:"env"`                     // Environment variables
-	Labels             map[string]string   `json:"labels"`                  // Labels
-	VolumeMounts       []VolumeMount       `json:"volumeMounts,omitempty"`  // PVC volume mounts
-	ShmSize            string              `json:"shmSize,omitempty"`       // Shared memory size (e.g., "1Gi", "512Mi")
-	EnablePtrace       bool                `json:"enablePtrace,omitempty"`  // Enable SYS_PTRACE capability for debugging (only for fixed resource pools)
-	ValidateImage      *bool               `json:"validateImage,omitempty"` // Whether to validate image before deployment (default: use config)
+	Endpoint           string              `json:"endpoint"`                   // Application name/endpoint
+	Provider           string              `json:"provider,omitempty"`         // Deployment provider to pin a new endpoint to (empty = default)
+	SpecName           string              `json:"specName"`                   // Spec name
+	Image              string              `json:"image"`                      // Docker image
+	Replicas           int                 `json:"replicas"`                   // Replica count
+	GpuCount           int                 `json:"gpuCount"`                   // GPU count (1-N, resources = per-gpu-config * gpuCount)
+	TaskTimeout        int                 `json:"taskTimeout"`                // Task execution timeout in seconds (0 = use global default)
+	Env                map[string]string   `json:"env"`                        // Environment variables
+	Labels             map[string]string   `json:"labels"`                     // Labels
+	VolumeMounts       []VolumeMount       `json:"volumeMounts,omitempty"`     // PVC volume mounts
+	ShmSize            string              `json:"shmSize,omitempty"`          // Shared memory size (e.g., "1Gi", "512Mi")
+	EphemeralStorage   string              `json:"ephemeralStorage,omitempty"` // Ephemeral storage limit overriding the spec (K8s only, e.g., "100Gi", plain numbers are GB)
+	EnablePtrace       bool                `json:"enablePtrace,omitempty"`     // Enable SYS_PTRACE capability for debugging (only for fixed resource pools)
+	ValidateImage      *bool               `json:"validateImage,omitempty"`    // Whether to validate image before deployment (default: use config)
+	CopyImage          *bool               `json:"copyImage,omitempty"`        // Whether to copy the image into the internal registry (default: use config)
+	RunPodCompat       *bool               `json:"runpodCompat,omitempty"`     // Inject RunPod worker env vars and serve the RunPod API paths (default: true)
 	RegistryCredential *RegistryCredential `json:"registryCredential,omitempty"`
+
+	// Volumes provisioned by waverless and mounted into the workers (K8s only)
+	Storage []StorageRequest `json:"storage,omitempty"`
+
+	// Containers run to completion before the worker starts, e.g. model downloads (K8s only)
+	InitContainers []InitContainer `json:"initContainers,omitempty"`
+
+	// Containers run next to the worker in each pod (K8s only)
+	Sidecars []Sidecar `json:"sidecars,omitempty"`
+
+	// Isolation for untrusted model containers (K8s only)
+	EgressPolicy             *EgressPolicy `json:"egressPolicy,omitempty"`             // Deny all egress except the allowlist (nil = unrestricted)
+	RestrictedServiceAccount bool          `json:"restrictedServiceAccount,omitempty"` // Run workers under a dedicated service account without API access
+
+	// Resources added to the spec for this endpoint, within the admin's override limits (K8s only)
+	SpecOverride *SpecOverride `json:"specOverride,omitempty"`
+}
+
+// SpecOverride adds resources on top of the chosen spec for one endpoint, so that an endpoint
+// needing a little more than a spec offers doesn't need a spec of its own. The amounts are
+// added after the spec is scaled by gpuCount and are bounded by k8s.spec_overrides.
+type SpecOverride struct {
+	ExtraCPU    string `json:"extraCpu,omitempty"`    // CPU added to the spec (e.g., "4")
+	ExtraMemory string `json:"extraMemory,omitempty"` // Memory added to the spec (e.g., "16Gi")
+}
+
+// IsZero reports whether the override changes nothing
+func (o *SpecOverride) IsZero() bool {
+	return o == nil || (o.ExtraCPU == "" && o.ExtraMemory == "")
+}
+
+// Deletion policies of provisioned storage, applied when the endpoint (for shared storage:
+// the last endpoint using it) is deleted
+const (
+	StorageDeletionRetain = "retain" // Keep the PVC (default)
+	StorageDeletionDelete = "delete" // Delete the PVC with the endpoint
+)
+
+// StorageRequest asks for a PVC to be created for an endpoint and mounted into its workers.
+// The PVC is named <endpoint>-<name>; an existing PVC of the endpoint is reused and grown.
+// Shared storage is named shared-<name> and mounted by every endpoint requesting that name.
+type StorageRequest struct {
+	Name           string `json:"name"`                     // Volume name, unique per endpoint
+	MountPath      string `json:"mountPath"`                // Mount path in the worker container
+	Size           string `json:"size"`                     // Requested size (e.g., "100Gi")
+	StorageClass   string `json:"storageClass,omitempty"`   // Storage class (empty = cluster default)
+	AccessMode     string `json:"accessMode,omitempty"`     // ReadWriteOnce (default), ReadWriteMany, ReadOnlyMany or ReadWriteOncePod
+	DeletionPolicy string `json:"deletionPolicy,omitempty"` // retain (default) or delete
+	Shared         bool   `json:"shared,omitempty"`         // Share between endpoints (access mode defaults to ReadOnlyMany)
+}
+
+// Sidecar is a container run next to the worker in each pod, e.g. a model downloader or a
+// metrics exporter. The container is named sidecar-<name>.
+type Sidecar struct {
+	Name    string            `json:"name"`              // Unique per endpoint
+	Image   string            `json:"image"`             // Container image
+	Command []string          `json:"command,omitempty"` // Entrypoint (default: the image's)
+	Args    []string          `json:"args,omitempty"`    // Arguments (default: the image's)
+	Env     map[string]string `json:"env,omitempty"`     // Environment variables
+	Mounts  []SidecarMount    `json:"mounts,omitempty"`  // Volumes mounted into the sidecar
+}
+
+// InitContainer is a container run to completion before the worker starts, e.g. to download
+// a model into a scratch volume the worker mounts. Init containers run in order; the container
+// is named init-<name>. Mounts resolve like sidecar mounts.
+type InitContainer struct {
+	Sidecar
+	Description string `json:"description,omitempty"` // Shown in the endpoint status while it runs, e.g. "pulling model"
+}
+
+// SidecarMount mounts a volume into a sidecar. A volume named like a PVC of the worker
+// (volumeMounts or provisioned storage) mounts that PVC; any other name is a scratch
+// directory (emptyDir) shared with the other sidecars mounting it and with the worker,
+// which mounts it at the path of its first sidecar mount.
+type SidecarMount struct {
+	Volume    string `json:"volume"`             // PVC name or scratch volume name
+	MountPath string `json:"mountPath"`          // Absolute path in the sidecar
+	ReadOnly  bool   `json:"readOnly,omitempty"` // Mount read-only
+}
+
+// StorageStatus is the state of a PVC provisioned for an endpoint
+type StorageStatus struct {
+	Name           string   `json:"name"`
+	PVCName        string   `json:"pvcName"`
+	MountPath      string   `json:"mountPath,omitempty"`
+	Size           string   `json:"size"`               // Requested size
+	Capacity       string   `json:"capacity,omitempty"` // Provisioned capacity once bound
+	StorageClass   string   `json:"storageClass,omitempty"`
+	AccessMode     string   `json:"accessMode"`
+	DeletionPolicy string   `json:"deletionPolicy"`
+	Phase          string   `json:"phase"` // Pending, Bound, Lost
+	Shared         bool     `json:"shared,omitempty"`
+	Consumers      []string `json:"consumers,omitempty"` // Endpoints using shared storage
+}
+
+// StorageProvisioner is implemented by providers that provision volumes from the storage
+// requests of endpoints (optional capability)
+type StorageProvisioner interface {
+	// GetEndpointStorage returns the volumes provisioned for an endpoint
+	GetEndpointStorage(ctx context.Context, endpoint string) ([]*StorageStatus, error)
+
+	// ListSharedStorage returns the shared volumes and the endpoints using each
+	ListSharedStorage(ctx context.Context) ([]*StorageStatus, error)
+}
+
+// SecretEnvSyncer is implemented by providers that can keep secret-typed env vars of an
+// endpoint in a secret store and inject them into its workers by reference (optional capability)
+type SecretEnvSyncer interface {
+	// SyncSecretEnv stores the set values, removes the unset keys and makes the workers read
+	// exactly the remaining secret keys. Plain env vars of the same names are replaced.
+	SyncSecretEnv(ctx context.Context, endpoint string, set map[string]string, unset []string) error
+}
+
+// LogLine is a line of a followed pod log
+type LogLine struct {
+	Pod  string `json:"pod"`
+	Line string `json:"line"`
+}
+
+// AppLogStreamer is implemented by providers that can follow the logs of endpoint pods
+// (optional capability)
+type AppLogStreamer interface {
+	// StreamAppLogs follows the logs of all pods of an endpoint, or of podName only when set,
+	// starting with the last tailLines lines of each. Lines of all pods are multiplexed onto
+	// the returned channel, which is closed when ctx is done (or the single pod's log ends).
+	// container selects a container other than the worker (empty = worker).
+	StreamAppLogs(ctx context.Context, endpoint string, tailLines int, podName, container string) (<-chan LogLine, error)
+}
+
+// ContainerLogReader is implemented by providers whose pods run containers next to the
+// worker, such as sidecars (optional capability)
+type ContainerLogReader interface {
+	// GetContainerLogs returns the last lines of a container of an endpoint pod (podName
+	// empty = any pod of the endpoint)
+	GetContainerLogs(ctx context.Context, endpoint string, lines int, podName, container string) (string, error)
+}
+
+// EgressPolicy allowlists the destinations workers may reach.
+// DNS and the waverless control plane are always reachable so workers can pull jobs.
+type EgressPolicy struct {
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"` // CIDR blocks or single IPs, e.g. "10.0.0.0/8", "52.1.2.3"
+	AllowedHosts []string `json:"allowedHosts,omitempty"` // DNS names, resolved to IPs when the policy is rendered
 }
 
 // RegistryCredential for private container registries
@@ -125,15 +354,46 @@ type DeployResponse struct {
 
 // UpdateDeploymentRequest update deployment request (image, specification, replica count)
 type UpdateDeploymentRequest struct {
-	Endpoint     string             `json:"endpoint"`               // Application name (required)
-	SpecName     string             `json:"specName,omitempty"`     // New spec name (optional)
-	Image        string             `json:"image,omitempty"`        // New docker image (optional)
-	Replicas     *int               `json:"replicas,omitempty"`     // New replica count (optional, use pointer to distinguish 0 from unset)
-	VolumeMounts *[]VolumeMount     `json:"volumeMounts,omitempty"` // New volume mounts (optional, use pointer to distinguish empty from unset)
-	ShmSize      *string            `json:"shmSize,omitempty"`      // New shared memory size (optional, use pointer to distinguish empty from unset)
-	EnablePtrace *bool              `json:"enablePtrace,omitempty"` // Enable SYS_PTRACE capability (optional, use pointer to distinguish false from unset)
-	Env          *map[string]string `json:"env,omitempty"`          // New environment variables (optional, use pointer to distinguish empty from unset)
-	TaskTimeout  *int               `json:"taskTimeout,omitempty"`  // New task timeout (optional)
+	Endpoint         string             `json:"endpoint"`                   // Application name (required)
+	SpecName         string             `json:"specName,omitempty"`         // New spec name (optional)
+	Image            string             `json:"image,omitempty"`            // New docker image (optional)
+	Replicas         *int               `json:"replicas,omitempty"`         // New replica count (optional, use pointer to distinguish 0 from unset)
+	VolumeMounts     *[]VolumeMount     `json:"volumeMounts,omitempty"`     // New volume mounts (optional, use pointer to distinguish empty from unset)
+	ShmSize          *string            `json:"shmSize,omitempty"`          // New shared memory size (optional, use pointer to distinguish empty from unset)
+	EphemeralStorage *string            `json:"ephemeralStorage,omitempty"` // New ephemeral storage limit (optional, empty reverts to the spec)
+	EnablePtrace     *bool              `json:"enablePtrace,omitempty"`     // Enable SYS_PTRACE capability (optional, use pointer to distinguish false from unset)
+	Env              *map[string]string `json:"env,omitempty"`              // New environment variables (optional, use pointer to distinguish empty from unset)
+	TaskTimeout      *int               `json:"taskTimeout,omitempty"`      // New task timeout (optional)
+	CopyImage        *bool              `json:"copyImage,omitempty"`        // Copy the new image into the internal registry (optional, default: use config)
+	RunPodCompat     *bool              `json:"runpodCompat,omitempty"`     // Enable or disable the RunPod compatibility layer (optional)
+	BlueGreen        *BlueGreenOptions  `json:"blueGreen,omitempty"`        // Roll out as a blue/green update instead of in place (optional)
+	Model            *ModelLineage      `json:"model,omitempty"`            // Registry model version the new image was resolved from (set by POST /endpoints/:name/model)
+}
+
+// BlueGreenOptions tune a blue/green update. Zero values take the defaults.
+type BlueGreenOptions struct {
+	ReadyTimeout         int     `json:"readyTimeout,omitempty"`         // Seconds for every new worker to register and become ready (default 900)
+	BakeWindow           int     `json:"bakeWindow,omitempty"`           // Seconds the new workers take all tasks before the old ones are replaced (default 300)
+	MaxFailureRate       float64 `json:"maxFailureRate,omitempty"`       // Task failure rate of the new workers that rolls back (default 0.2)
+	MinTasks             int     `json:"minTasks,omitempty"`             // Tasks the new workers must finish before the failure rate counts (default 10)
+	MaxImagePullFailures int     `json:"maxImagePullFailures,omitempty"` // IMAGE_PULL_FAILED workers tolerated before rolling back (default 0)
+}
+
+// CandidateDeployer is implemented by providers that can run an update as a second
+// deployment next to the live one, for blue/green updates (optional capability)
+type CandidateDeployer interface {
+	// DeployCandidate creates (or replaces) the candidate deployment of req.Endpoint: the live
+	// deployment with req applied. Its workers belong to the endpoint.
+	DeployCandidate(ctx context.Context, req *UpdateDeploymentRequest) error
+
+	// GetCandidateStatus returns the status of the candidate deployment
+	GetCandidateStatus(ctx context.Context, endpoint string) (*AppStatus, error)
+
+	// ListCandidateWorkers returns the pod names (worker IDs) of the candidate deployment
+	ListCandidateWorkers(ctx context.Context, endpoint string) ([]string, error)
+
+	// DeleteCandidate deletes the candidate deployment and its workers, if any
+	DeleteCandidate(ctx context.Context, endpoint string) error
 }
 
 // UpdateEndpointConfigRequest update Endpoint configuration request (metadata + autoscaling configuration)
@@ -146,6 +406,8 @@ type UpdateEndpointConfigRequest struct {
 	MaxPendingTasks *int    `json:"maxPendingTasks,omitempty"` // Maximum allowed pending tasks before warning clients
 	ImagePrefix     *string `json:"imagePrefix,omitempty"`     // Image prefix for matching updates
 
+	Labels *map[string]string `json:"labels,omitempty"` // Replaces all labels (e.g. environment=prod)
+
 	// Autoscaling configuration
 	MinReplicas       *int    `json:"minReplicas,omitempty"`       // Minimum replicas (0 = scale-to-zero)
 	MaxReplicas       *int    `json:"maxReplicas,omitempty"`       // Maximum replicas
@@ -158,6 +420,20 @@ type UpdateEndpointConfigRequest struct {
 	HighLoadThreshold *int    `json:"highLoadThreshold,omitempty"` // High load threshold for priority boost
 	PriorityBoost     *int    `json:"priorityBoost,omitempty"`     // Priority boost amount (0 = no boost)
 	AutoscalerEnabled *string `json:"autoscalerEnabled,omitempty"` // Autoscaler override: "" = default, "disabled" = off, "enabled" = on
+
+	// Custom metric scaling
+	CustomMetricName   *string  `json:"customMetricName,omitempty"`   // Display name of the worker-reported gauge
+	CustomMetricTarget *float64 `json:"customMetricTarget,omitempty"` // Setpoint per worker (0 = disabled)
+
+	// Autoscaler evaluation cadence (seconds, 0 = global interval)
+	EvaluationInterval *int `json:"evaluationInterval,omitempty"`
+	// Queue-wait SLO (seconds, 0 = none)
+	QueueWaitSLO *int `json:"queueWaitSLO,omitempty"`
+	// Metric sources the endpoint is also scaled on (empty list = queue-based scaling only)
+	MetricSources *[]MetricSource `json:"metricSources,omitempty"`
+
+	// Resource profile expanded before the other fields, which override it ("" = clear the label only)
+	Profile *string `json:"profile,omitempty"`
 }
 
 // AppInfo application information
@@ -172,8 +448,11 @@ type AppInfo struct {
 	Image             string            `json:"image"`
 	Labels            map[string]string `json:"labels"`
 	CreatedAt         string            `json:"createdAt"`
-	ShmSize           string            `json:"shmSize,omitempty"`      // Shared memory size from deployment volumes
-	VolumeMounts      []VolumeMount     `json:"volumeMounts,omitempty"` // PVC volume mounts from deployment
+	ShmSize           string            `json:"shmSize,omitempty"`          // Shared memory size from deployment volumes
+	EphemeralStorage  string            `json:"ephemeralStorage,omitempty"` // Ephemeral storage limit of the worker container
+	SpecOverride      *SpecOverride     `json:"specOverride,omitempty"`     // Resources added to the spec, from the deployment annotation
+	VolumeMounts      []VolumeMount     `json:"volumeMounts,omitempty"`     // PVC volume mounts from deployment
+	InitStatus        string            `json:"initStatus,omitempty"`       // Progress of init containers, e.g. "Initializing: pulling model (2/3 pods)"
 }
 
 // AppStatus application status
@@ -183,6 +462,7 @@ type AppStatus struct {
 	ReadyReplicas     int32  `json:"readyReplicas"`
 	AvailableReplicas int32  `json:"availableReplicas"`
 	TotalReplicas     int32  `json:"totalReplicas"`
+	RolloutComplete   bool   `json:"rolloutComplete"` // Every replica runs the latest spec and is available
 	Message           string `json:"message,omitempty"`
 }
 
@@ -198,12 +478,13 @@ type SpecInfo struct {
 
 // ResourceRequirements resource requirements
 type ResourceRequirements struct {
-	GPU              string `json:"gpu"`
-	GPUType          string `json:"gpuType"`
-	CPU              string `json:"cpu"`
-	Memory           string `json:"memory"`
-	EphemeralStorage string `json:"ephemeralStorage,omitempty"`
-	ShmSize          string `json:"shmSize,omitempty"` // Shared memory size (e.g., "1Gi", "512Mi")
+	GPU              string   `json:"gpu"`
+	GPUType          string   `json:"gpuType"`
+	GPUTypeFallbacks []string `json:"gpuTypeFallbacks,omitempty"` // GPU types used in order when gpuType has no capacity
+	CPU              string   `json:"cpu"`
+	Memory           string   `json:"memory"`
+	EphemeralStorage string   `json:"ephemeralStorage,omitempty"`
+	ShmSize          string   `json:"shmSize,omitempty"` // Shared memory size (e.g., "1Gi", "512Mi")
 }
 
 // CreateSpecRequest create spec request
@@ -235,12 +516,22 @@ type PodInfo struct {
 	Message           string            `json:"message,omitempty"` // Detailed status message
 	IP                string            `json:"ip,omitempty"`
 	NodeName          string            `json:"nodeName,omitempty"`
+	GPUType           string            `json:"gpuType,omitempty"`     // GPU type of the node, set when the spec has GPU type fallbacks
+	ImageDigest       string            `json:"imageDigest,omitempty"` // Digest of the image the worker container runs (sha256:...)
 	CreatedAt         string            `json:"createdAt"`
 	StartedAt         string            `json:"startedAt,omitempty"`
 	DeletionTimestamp string            `json:"deletionTimestamp,omitempty"` // Set when pod is terminating
 	Labels            map[string]string `json:"labels,omitempty"`
 	RestartCount      int32             `json:"restartCount"`
 	WorkerID          string            `json:"workerID,omitempty"` // Matched worker ID from Redis
+
+	// Failure signals beyond the current container state
+	ExitCode               int32  `json:"exitCode,omitempty"`               // Exit code of the terminated container
+	LastTerminationReason  string `json:"lastTerminationReason,omitempty"`  // Why the previous container instance exited (e.g. OOMKilled behind CrashLoopBackOff)
+	LastTerminationMessage string `json:"lastTerminationMessage,omitempty"` // Termination message of the previous container instance
+	LastExitCode           int32  `json:"lastExitCode,omitempty"`           // Exit code of the previous container instance
+	PodReason              string `json:"podReason,omitempty"`              // Pod-level reason (e.g. Evicted)
+	PodMessage             string `json:"podMessage,omitempty"`             // Pod-level message
 }
 
 // PodDetail Pod detailed information (similar to kubectl describe)
@@ -0,0 +1,55 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Provider error kinds. Providers classify failures with these so callers can react
+// (and handlers can answer 404/409/429/403) without parsing error strings.
+// Test with errors.Is(err, interfaces.ErrNotFound).
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrThrottled     = errors.New("throttled")
+)
+
+// ProviderError is a classified provider failure. Error() is the underlying message;
+// errors.Is matches both the kind and anything in the underlying chain.
+type ProviderError struct {
+	Kind error // One of the provider error kinds above
+	Err  error // Underlying error
+}
+
+func (e *ProviderError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ProviderError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// NewProviderError classifies err as kind. A nil err stays nil and a nil kind leaves err unchanged.
+func NewProviderError(kind, err error) error {
+	if err == nil || kind == nil {
+		return err
+	}
+	return &ProviderError{Kind: kind, Err: err}
+}
+
+// ProviderErrorf formats a new error classified as kind
+func ProviderErrorf(kind error, format string, args ...interface{}) error {
+	return NewProviderError(kind, fmt.Errorf(format, args...))
+}
+
+// ProviderErrorKind returns the kind of a classified error, or nil
+func ProviderErrorKind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrConflict, ErrQuotaExceeded, ErrUnauthorized, ErrThrottled} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
@@ -26,6 +26,10 @@ const (
 	// This includes image pull timeout, startup timeout
 	FailureTypeTimeout FailureType = "TIMEOUT"
 
+	// FailureTypeGPUUnhealthy indicates the worker's own GPU health check failed
+	// This includes uncorrectable ECC errors and an unresponsive nvidia-smi
+	FailureTypeGPUUnhealthy FailureType = "GPU_UNHEALTHY"
+
 	// FailureTypeUnknown indicates an unknown failure type
 	// Used when the failure reason cannot be classified
 	FailureTypeUnknown FailureType = "UNKNOWN"
@@ -51,6 +55,24 @@ type ImageValidationResult struct {
 
 	// CheckedAt is the timestamp when the validation was performed
 	CheckedAt time.Time `json:"checkedAt"`
+
+	// Digest is the manifest digest reported by the registry (sha256:...)
+	Digest string `json:"digest,omitempty"`
+
+	// MediaType is the manifest media type (image manifest or index)
+	MediaType string `json:"mediaType,omitempty"`
+
+	// SizeBytes is the compressed image size (config + layers) for the selected platform
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+
+	// Platforms lists the os/arch combinations the image provides
+	Platforms []string `json:"platforms,omitempty"`
+
+	// DurationMs is how long the validation took
+	DurationMs int64 `json:"durationMs"`
+
+	// CacheHit indicates the result was served from the validation cache
+	CacheHit bool `json:"cacheHit"`
 }
 
 // WorkerFailureInfo represents failure information for a worker
@@ -68,6 +90,12 @@ type WorkerFailureInfo struct {
 	// SanitizedMsg is the user-friendly message without sensitive information
 	SanitizedMsg string `json:"sanitizedMsg"`
 
+	// Cause is the specific failure cause from classification (e.g., "CUDAOutOfMemory", "DiskPressure")
+	Cause string `json:"cause,omitempty"`
+
+	// Details holds classification context (exit code, evicted resource, ...)
+	Details map[string]string `json:"details,omitempty"`
+
 	// OccurredAt is the timestamp when the failure occurred
 	OccurredAt time.Time `json:"occurredAt"`
 }
@@ -113,3 +141,11 @@ type WorkerTerminator interface {
 	// Returns error if termination fails
 	TerminateWorker(ctx context.Context, endpoint, workerID string, reason string) error
 }
+
+// NodeAvoider interface for keeping new workers off a node (optional capability)
+// Providers that schedule workers onto nodes they can mark should implement this interface
+type NodeAvoider interface {
+	// AvoidNode stops new workers from being scheduled onto a node; running workers are kept
+	// reason: why the node is avoided (recorded on the node)
+	AvoidNode(ctx context.Context, node, reason string) error
+}
@@ -0,0 +1,57 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Batch job phases
+const (
+	JobPhasePending   = "pending"   // Waiting for capacity or pulling the image
+	JobPhaseRunning   = "running"   // Container started
+	JobPhaseSucceeded = "succeeded" // Container exited with code 0
+	JobPhaseFailed    = "failed"    // Container exited with an error, or the job hit its deadline
+)
+
+// JobRequest is a one-off run of an image on a spec: the container runs to completion once
+// and is not restarted (e.g. a fine-tuning or evaluation run)
+type JobRequest struct {
+	Name           string            `json:"name"`                     // Job name, unique among jobs
+	SpecName       string            `json:"specName"`                 // Spec name
+	Image          string            `json:"image"`                    // Docker image
+	Command        []string          `json:"command,omitempty"`        // Entrypoint override (empty = image entrypoint)
+	Args           []string          `json:"args,omitempty"`           // Arguments (empty = image cmd)
+	Env            map[string]string `json:"env,omitempty"`            // Environment variables
+	GpuCount       int               `json:"gpuCount,omitempty"`       // GPU count (1-N, resources = per-gpu-config * gpuCount)
+	VolumeMounts   []VolumeMount     `json:"volumeMounts,omitempty"`   // PVC volume mounts (datasets, checkpoints)
+	ShmSize        string            `json:"shmSize,omitempty"`        // Shared memory size (empty = spec default)
+	TimeoutSeconds int               `json:"timeoutSeconds,omitempty"` // Longest the job may run, 0 = no limit
+}
+
+// JobStatus is the runtime state of a job
+type JobStatus struct {
+	Phase       string     `json:"phase"` // pending, running, succeeded, failed
+	PodName     string     `json:"podName,omitempty"`
+	Message     string     `json:"message,omitempty"`  // Why the job is pending or failed
+	ExitCode    *int32     `json:"exitCode,omitempty"` // Set once the container exited
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// JobRunner is implemented by providers that can run one-off jobs next to long-lived
+// endpoints (optional capability)
+type JobRunner interface {
+	// RunJob starts a job
+	RunJob(ctx context.Context, req *JobRequest) error
+
+	// GetJobStatus returns the state of a job, nil when it does not exist
+	GetJobStatus(ctx context.Context, name string) (*JobStatus, error)
+
+	// DeleteJob stops a job and removes it with its pod
+	DeleteJob(ctx context.Context, name string) error
+
+	// StreamJobLogs returns the logs of a job's container; with follow the stream stays open
+	// until the container exits or ctx is done. tailLines <= 0 returns all lines.
+	StreamJobLogs(ctx context.Context, name string, follow bool, tailLines int) (io.ReadCloser, error)
+}
@@ -59,18 +59,20 @@ type EndpointMetadata struct {
 	// Basic information
 	Name        string `json:"name"`                // Endpoint name
 	Namespace   string `json:"namespace,omitempty"` // K8s namespace
+	Provider    string `json:"provider,omitempty"`  // Deployment provider the endpoint is pinned to (empty = default)
 	DisplayName string `json:"displayName"`         // Display name
 	Description string `json:"description"`         // Description
 
 	// Deployment information
-	SpecName         string     `json:"specName"`         // Spec name
-	Image            string     `json:"image"`            // Docker image
-	ImagePrefix      string     `json:"imagePrefix"`      // Image prefix for matching updates (e.g., "wavespeed/model-deploy:wan_i2v-default-")
-	ImageDigest      string     `json:"imageDigest"`      // Current image digest from DockerHub
-	ImageLastChecked *time.Time `json:"imageLastChecked"` // Last time image was checked for updates
-	LatestImage      string     `json:"latestImage"`      // Latest available image if update is available
-	Replicas         int        `json:"replicas"`         // Replica count
-	GpuCount         int        `json:"gpuCount"`         // GPU count per replica (resources = per-gpu-config * gpuCount)
+	SpecName         string        `json:"specName"`         // Spec name
+	Image            string        `json:"image"`            // Docker image
+	ImagePrefix      string        `json:"imagePrefix"`      // Image prefix for matching updates (e.g., "wavespeed/model-deploy:wan_i2v-default-")
+	ImageDigest      string        `json:"imageDigest"`      // Current image digest from DockerHub
+	ImageLastChecked *time.Time    `json:"imageLastChecked"` // Last time image was checked for updates
+	LatestImage      string        `json:"latestImage"`      // Latest available image if update is available
+	Model            *ModelLineage `json:"model,omitempty"`  // Model registry version the image was resolved from (nil = deployed by image)
+	Replicas         int           `json:"replicas"`         // Replica count
+	GpuCount         int           `json:"gpuCount"`         // GPU count per replica (resources = per-gpu-config * gpuCount)
 
 	// Auto-scaling configuration
 	MinReplicas       int     `json:"minReplicas"`                 // Minimum replica count (default 0)
@@ -85,6 +87,30 @@ type EndpointMetadata struct {
 	PriorityBoost     int     `json:"priorityBoost"`               // Priority boost amount when high load (default 20)
 	AutoscalerEnabled *string `json:"autoscalerEnabled,omitempty"` // Autoscaler override: nil/"" = follow global, "disabled" = force off, "enabled" = force on
 
+	// Custom metric scaling (worker-reported gauge, in addition to queue depth)
+	CustomMetricName   string  `json:"customMetricName,omitempty"`   // Display name of the gauge, e.g. "batch_queue"
+	CustomMetricTarget float64 `json:"customMetricTarget,omitempty"` // Setpoint per worker (0 = disabled)
+
+	// Endpoint group: members share the group's replica / GPU budget
+	GroupName string `json:"groupName,omitempty"`
+
+	// Autoscaler evaluation cadence in seconds: short for interactive endpoints, long for batch (0 = global interval)
+	EvaluationInterval int `json:"evaluationInterval,omitempty"`
+
+	// Longest a task should wait in the queue in seconds; the autoscaler scales up ahead of a growing queue to keep it (0 = none)
+	QueueWaitSLO int `json:"queueWaitSLO,omitempty"`
+
+	// Metrics the endpoint is also scaled on (GPU utilization, latency, Prometheus query), combined with
+	// the queue-based target via max()
+	MetricSources []MetricSource `json:"metricSources,omitempty"`
+
+	// Cron-based windows overriding minReplicas/maxReplicas, e.g. to pre-warm GPUs before a daily peak
+	ReplicaSchedules []ReplicaSchedule `json:"replicaSchedules,omitempty"`
+
+	// Resource profile the autoscaler, queue and dispatch settings were last expanded from (empty = none);
+	// knobs edited afterwards override it individually
+	Profile string `json:"profile,omitempty"`
+
 	// Auto-scaling runtime state
 	LastScaleTime    time.Time `json:"lastScaleTime,omitempty"`    // Last scaling time
 	LastTaskTime     time.Time `json:"lastTaskTime,omitempty"`     // Last task processing time
@@ -99,8 +125,25 @@ type EndpointMetadata struct {
 	Labels          map[string]string `json:"labels"`          // Labels
 	TaskTimeout     int               `json:"taskTimeout"`     // Task execution timeout in seconds (0 = use global default)
 	EnablePtrace    bool              `json:"enablePtrace"`    // Enable SYS_PTRACE capability for debugging (only for fixed resource pools)
+	RunPodCompat    bool              `json:"runpodCompat"`    // RunPod worker env vars and API paths are provided
 	MaxPendingTasks int               `json:"maxPendingTasks"` // Maximum allowed pending tasks before warning clients (default 1)
 
+	// Env versioning (read-only, changed through PATCH /endpoints/:name/env)
+	EnvVersion int      `json:"envVersion"`          // Version the next env change must be based on
+	SecretEnv  []string `json:"secretEnv,omitempty"` // Names of secret env vars; values are never returned
+
+	// Task dispatch pause (read-only, changed through POST /endpoints/:name/pause and /resume)
+	DispatchPaused bool       `json:"dispatchPaused"`        // Workers get no tasks; replicas are kept
+	PauseMode      string     `json:"pauseMode,omitempty"`   // queue (submissions are queued) or reject
+	PauseReason    string     `json:"pauseReason,omitempty"` // Why dispatch was paused
+	PausedBy       string     `json:"pausedBy,omitempty"`    // Identity from the X-Requested-By header
+	PausedAt       *time.Time `json:"pausedAt,omitempty"`
+
+	// Circuit breaker (read-only, tripped by failure storms, closed by a probe or POST /endpoints/:name/circuit/close)
+	CircuitState  string     `json:"circuitState,omitempty"`  // open or half_open; empty when closed
+	CircuitReason string     `json:"circuitReason,omitempty"` // Failure rate that tripped the breaker
+	CircuitSince  *time.Time `json:"circuitSince,omitempty"`  // Last state change
+
 	// Status information
 	Status            string `json:"status"`            // Running, Stopped, Failed
 	ReadyReplicas     int    `json:"readyReplicas"`     // Ready replicas
@@ -108,7 +151,8 @@ type EndpointMetadata struct {
 
 	// Health status (for image validation and status transparency feature)
 	HealthStatus      string     `json:"healthStatus"`                // HEALTHY, DEGRADED, UNHEALTHY
-	HealthMessage     string     `json:"healthMessage,omitempty"`     // User-friendly health message
+	HealthReason      string     `json:"healthReason,omitempty"`      // Reason code (IMAGE_PULL_FAILED, CONTAINER_CRASH), for logic and localization
+	HealthMessage     string     `json:"healthMessage,omitempty"`     // User-friendly health message (display only)
 	LastHealthCheckAt *time.Time `json:"lastHealthCheckAt,omitempty"` // Last health check timestamp
 
 	// Worker information
@@ -121,14 +165,26 @@ type EndpointMetadata struct {
 	FailedTasks    int64 `json:"failedTasks"`    // Failed tasks
 
 	// Storage configuration (backfilled from K8s deployment)
-	ShmSize      string        `json:"shmSize,omitempty"`      // Shared memory size from deployment
-	VolumeMounts []VolumeMount `json:"volumeMounts,omitempty"` // PVC volume mounts from deployment
+	ShmSize          string           `json:"shmSize,omitempty"`          // Shared memory size from deployment
+	EphemeralStorage string           `json:"ephemeralStorage,omitempty"` // Ephemeral storage limit from deployment
+	SpecOverride     *SpecOverride    `json:"specOverride,omitempty"`     // Resources added to the spec (drift from specName), from deployment
+	VolumeMounts     []VolumeMount    `json:"volumeMounts,omitempty"`     // PVC volume mounts from deployment
+	Storage          []*StorageStatus `json:"storage,omitempty"`          // PVCs provisioned for the endpoint (endpoint details only)
 
 	// Timestamps
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// ModelLineage links an endpoint to the model registry version its image and env were
+// resolved from
+type ModelLineage struct {
+	Registry string `json:"registry"`      // Registry kind, e.g. mlflow
+	Name     string `json:"name"`          // Registered model name
+	Version  string `json:"version"`       // Model version
+	URL      string `json:"url,omitempty"` // The version in the registry
+}
+
 // WorkerMetadata Worker metadata
 type WorkerMetadata struct {
 	ID             string    `json:"id"`
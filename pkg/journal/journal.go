@@ -0,0 +1,197 @@
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CheckpointDataset is the export checkpoint holding the relay cursor
+const CheckpointDataset = "task_journal"
+
+// settleDelay keeps the relay away from events whose transaction may still commit out of id order
+const settleDelay = 5 * time.Second
+
+// maxBatchesPerRun bounds a single run so a large backlog is drained over several runs
+const maxBatchesPerRun = 20
+
+// Stream appends task events to a Redis Stream. Consumers read it with XREADGROUP and
+// acknowledge with XACK; every entry carries the event_id, so a consumer that sees an
+// entry twice (after a relay retry) drops it by event_id.
+type Stream struct {
+	client *redis.Client
+	name   string
+	maxLen int64
+	groups []string
+}
+
+// NewStream creates a stream writer; maxLen caps the stream length approximately (0 = unbounded)
+func NewStream(client *redis.Client, name string, maxLen int64, groups []string) *Stream {
+	return &Stream{client: client, name: name, maxLen: maxLen, groups: groups}
+}
+
+// Name returns the stream key
+func (s *Stream) Name() string { return s.name }
+
+// EnsureGroups creates the configured consumer groups (and the stream) if missing. New groups
+// start at the beginning of the stream so they also receive the retained history.
+func (s *Stream) EnsureGroups(ctx context.Context) error {
+	for _, group := range s.groups {
+		err := s.client.XGroupCreateMkStream(ctx, s.name, group, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("failed to create consumer group %s on %s: %w", group, s.name, err)
+		}
+	}
+	return nil
+}
+
+// Append adds the events in order in one round trip and returns the ID of the last entry
+func (s *Stream) Append(ctx context.Context, events []*model.TaskEvent) (string, error) {
+	if len(events) == 0 {
+		return "", nil
+	}
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.StringCmd, 0, len(events))
+	for _, event := range events {
+		cmds = append(cmds, pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: s.name,
+			MaxLen: s.maxLen,
+			Approx: s.maxLen > 0,
+			Values: entryValues(event),
+		}))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to append task events to %s: %w", s.name, err)
+	}
+	return cmds[len(cmds)-1].Val(), nil
+}
+
+// entryValues flattens a task event into stream entry fields; empty fields are left out
+func entryValues(event *model.TaskEvent) map[string]interface{} {
+	values := map[string]interface{}{
+		"event_id":    event.EventID,
+		"task_id":     event.TaskID,
+		"endpoint":    event.Endpoint,
+		"event_type":  event.EventType,
+		"event_time":  event.EventTime.UTC().Format(time.RFC3339Nano),
+		"retry_count": strconv.Itoa(event.RetryCount),
+	}
+	optional := map[string]string{
+		"worker_id":       event.WorkerID,
+		"worker_pod_name": event.WorkerPodName,
+		"from_status":     event.FromStatus,
+		"to_status":       event.ToStatus,
+		"error_message":   event.ErrorMessage,
+		"error_type":      event.ErrorType,
+	}
+	for key, value := range optional {
+		if value != "" {
+			values[key] = value
+		}
+	}
+	durations := map[string]*int{
+		"queue_wait_ms":         event.QueueWaitMs,
+		"execution_duration_ms": event.ExecutionDurationMs,
+		"total_duration_ms":     event.TotalDurationMs,
+	}
+	for key, value := range durations {
+		if value != nil {
+			values[key] = strconv.Itoa(*value)
+		}
+	}
+	if len(event.Metadata) > 0 {
+		if metadata, err := json.Marshal(event.Metadata); err == nil {
+			values["metadata"] = string(metadata)
+		}
+	}
+	return values
+}
+
+// Relay tails the task_events table into the stream. The cursor is only advanced after a batch
+// was appended, so delivery is at-least-once: a batch is appended again if the relay fails
+// between appending and saving the cursor.
+type Relay struct {
+	repo      *mysql.ExportRepository
+	stream    *Stream
+	batchSize int
+}
+
+// NewRelay creates a new task event relay
+func NewRelay(repo *mysql.ExportRepository, stream *Stream, batchSize int) *Relay {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &Relay{repo: repo, stream: stream, batchSize: batchSize}
+}
+
+// RelayPending appends the task events recorded since the cursor to the stream
+func (r *Relay) RelayPending(ctx context.Context) error {
+	if err := r.stream.EnsureGroups(ctx); err != nil {
+		return err
+	}
+	checkpoint, err := r.repo.GetCheckpoint(ctx, CheckpointDataset)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		checkpoint = &model.ExportCheckpoint{Dataset: CheckpointDataset}
+	}
+
+	before := time.Now().Add(-settleDelay)
+	relayed := 0
+	for i := 0; i < maxBatchesPerRun; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		events, err := r.repo.ListTaskEventsAfter(ctx, checkpoint.LastID, before, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		entryID, err := r.stream.Append(ctx, events)
+		if err != nil {
+			checkpoint.LastError = err.Error()
+			if len(checkpoint.LastError) > 1024 {
+				checkpoint.LastError = checkpoint.LastError[:1024]
+			}
+			if saveErr := r.repo.SaveCheckpoint(ctx, checkpoint); saveErr != nil {
+				logger.WarnCtx(ctx, "failed to save task journal checkpoint: %v", saveErr)
+			}
+			return err
+		}
+
+		last := events[len(events)-1]
+		now := time.Now()
+		checkpoint.LastID = last.ID
+		checkpoint.LastTime = &last.EventTime
+		checkpoint.RowsExported += int64(len(events))
+		checkpoint.LastExportedAt = &now
+		checkpoint.LastObject = entryID
+		checkpoint.LastError = ""
+		if err := r.repo.SaveCheckpoint(ctx, checkpoint); err != nil {
+			return fmt.Errorf("failed to save task journal checkpoint: %w", err)
+		}
+		relayed += len(events)
+
+		if len(events) < r.batchSize {
+			break
+		}
+	}
+	if relayed > 0 {
+		logger.InfoCtx(ctx, "relayed %d task events to stream %s", relayed, r.stream.Name())
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package journal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+func newTestStream(t *testing.T, maxLen int64, groups ...string) (*Stream, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewStream(client, "waverless:task-events", maxLen, groups), client
+}
+
+func TestStream_AppendAndConsume(t *testing.T) {
+	ctx := context.Background()
+	stream, client := newTestStream(t, 0, "billing", "analytics")
+	require.NoError(t, stream.EnsureGroups(ctx))
+	require.NoError(t, stream.EnsureGroups(ctx), "existing groups are kept")
+
+	execMs := 1200
+	eventTime := time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC)
+	lastID, err := stream.Append(ctx, []*model.TaskEvent{
+		{EventID: "evt-1", TaskID: "task-1", Endpoint: "flux", EventType: "TASK_ASSIGNED", EventTime: eventTime, WorkerID: "w-1", FromStatus: "PENDING", ToStatus: "IN_PROGRESS"},
+		{EventID: "evt-2", TaskID: "task-1", Endpoint: "flux", EventType: "TASK_COMPLETED", EventTime: eventTime, ExecutionDurationMs: &execMs, Metadata: model.JSONMap{"gpu": "A100"}},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, lastID)
+
+	for _, group := range []string{"billing", "analytics"} {
+		streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: "c1",
+			Streams:  []string{stream.Name(), ">"},
+			Count:    10,
+		}).Result()
+		require.NoError(t, err)
+		require.Len(t, streams, 1)
+		messages := streams[0].Messages
+		require.Len(t, messages, 2, "every group receives every event")
+		assert.Equal(t, lastID, messages[1].ID)
+
+		assert.Equal(t, "evt-1", messages[0].Values["event_id"])
+		assert.Equal(t, "IN_PROGRESS", messages[0].Values["to_status"])
+		assert.Equal(t, "2026-10-16T08:30:00Z", messages[0].Values["event_time"])
+		assert.NotContains(t, messages[0].Values, "error_message")
+		assert.Equal(t, "1200", messages[1].Values["execution_duration_ms"])
+		assert.Equal(t, `{"gpu":"A100"}`, messages[1].Values["metadata"])
+	}
+}
+
+func TestStream_MaxLen(t *testing.T) {
+	ctx := context.Background()
+	stream, client := newTestStream(t, 3)
+
+	events := make([]*model.TaskEvent, 0, 10)
+	for i := 0; i < 10; i++ {
+		events = append(events, &model.TaskEvent{EventID: "evt", TaskID: "task", Endpoint: "flux", EventType: "TASK_QUEUED"})
+	}
+	_, err := stream.Append(ctx, events)
+	require.NoError(t, err)
+
+	length, err := client.XLen(ctx, stream.Name()).Result()
+	require.NoError(t, err)
+	assert.Less(t, length, int64(10))
+}
+
+func TestStream_AppendEmpty(t *testing.T) {
+	stream, _ := newTestStream(t, 0)
+	id, err := stream.Append(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, id)
+}
@@ -0,0 +1,174 @@
+// Package knobs describes the tunable per-endpoint settings (type, default, valid range) and
+// validates requests against them, so that the API, the UIs and the CLI share one definition.
+package knobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"waverless/pkg/autoscaler"
+	"waverless/pkg/profile"
+)
+
+// Knob types
+const (
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeBool   = "bool"
+	TypeString = "string"
+	TypeEnum   = "enum"
+)
+
+// Knob groups
+const (
+	GroupAutoscaling = "autoscaling"
+	GroupQueue       = "queue"
+	GroupDispatch    = "dispatch"
+)
+
+// Knob is a tunable per-endpoint setting. Name is the JSON field in the create and update
+// endpoint requests.
+type Knob struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Group       string   `json:"group"`
+	Default     any      `json:"default"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Zero        string   `json:"zero,omitempty"`   // Meaning of 0 when it lies outside [Min, Max]
+	Values      []string `json:"values,omitempty"` // Allowed values of an enum
+	Unit        string   `json:"unit,omitempty"`
+	Description string   `json:"description"`
+}
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+// List returns the knobs in display order
+func List() []Knob {
+	profiles := []string{""}
+	for _, p := range profile.List() {
+		profiles = append(profiles, p.Name)
+	}
+
+	return []Knob{
+		{Name: "profile", Type: TypeEnum, Group: GroupAutoscaling, Default: "", Values: profiles,
+			Description: "Resource profile expanded into the autoscaler, queue and dispatch settings; other settings in the same request override it"},
+		{Name: "minReplicas", Type: TypeInt, Group: GroupAutoscaling, Default: 0, Min: bound(0),
+			Description: "Replicas kept when idle (0 = scale to zero)"},
+		{Name: "maxReplicas", Type: TypeInt, Group: GroupAutoscaling, Default: 10, Min: bound(0),
+			Description: "Most replicas the autoscaler starts"},
+		{Name: "scaleUpThreshold", Type: TypeInt, Group: GroupAutoscaling, Default: 1, Min: bound(1), Unit: "tasks",
+			Description: "Queued tasks that trigger a scale-up"},
+		{Name: "scaleDownIdleTime", Type: TypeInt, Group: GroupAutoscaling, Default: 300, Min: bound(0), Unit: "seconds",
+			Description: "Idle time before a scale-down"},
+		{Name: "scaleUpCooldown", Type: TypeInt, Group: GroupAutoscaling, Default: 30, Min: bound(0), Unit: "seconds",
+			Description: "Time between scale-ups (0 = no cooldown)"},
+		{Name: "scaleDownCooldown", Type: TypeInt, Group: GroupAutoscaling, Default: 60, Min: bound(0), Unit: "seconds",
+			Description: "Time between scale-downs (0 = no cooldown)"},
+		{Name: "evaluationInterval", Type: TypeInt, Group: GroupAutoscaling, Default: 0, Min: bound(autoscaler.MinEvaluationInterval), Zero: "global interval", Unit: "seconds",
+			Description: "Time between autoscaler evaluations of the endpoint"},
+		{Name: "queueWaitSLO", Type: TypeInt, Group: GroupAutoscaling, Default: 0, Min: bound(0), Unit: "seconds",
+			Description: "Longest a task should wait in the queue; scale-ups start early to keep it (0 = none)"},
+		{Name: "autoscalerEnabled", Type: TypeEnum, Group: GroupAutoscaling, Default: "", Values: []string{"", "enabled", "disabled"},
+			Description: "Autoscaler override (\"\" = follow the global setting)"},
+		{Name: "customMetricName", Type: TypeString, Group: GroupAutoscaling, Default: "",
+			Description: "Display name of the gauge workers report in heartbeats"},
+		{Name: "customMetricTarget", Type: TypeFloat, Group: GroupAutoscaling, Default: 0.0, Min: bound(0),
+			Description: "Setpoint of the custom metric per worker (0 = disabled)"},
+		{Name: "priority", Type: TypeInt, Group: GroupDispatch, Default: 50, Min: bound(0), Max: bound(100),
+			Description: "Priority for GPU allocation between endpoints (0 = best-effort)"},
+		{Name: "enableDynamicPrio", Type: TypeBool, Group: GroupDispatch, Default: true,
+			Description: "Boost the priority while the queue is long"},
+		{Name: "highLoadThreshold", Type: TypeInt, Group: GroupDispatch, Default: 10, Min: bound(0), Unit: "tasks",
+			Description: "Queued tasks above which the priority is boosted"},
+		{Name: "priorityBoost", Type: TypeInt, Group: GroupDispatch, Default: 20, Min: bound(0), Max: bound(100),
+			Description: "Priority added under high load (0 = no boost)"},
+		{Name: "maxPendingTasks", Type: TypeInt, Group: GroupQueue, Default: 1, Min: bound(1), Zero: "default of 1", Unit: "tasks",
+			Description: "Pending tasks above which clients are told not to submit"},
+		{Name: "taskTimeout", Type: TypeInt, Group: GroupQueue, Default: 3600, Min: bound(1), Zero: "global default", Unit: "seconds",
+			Description: "Task execution timeout"},
+	}
+}
+
+// Validate checks the knob fields set in req (any struct or map that marshals to JSON with the
+// knob names) against their types and ranges. Fields that are not knobs are ignored.
+func Validate(req any) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for _, k := range List() {
+		v, ok := fields[k.Name]
+		if !ok || v == nil {
+			continue
+		}
+		if err := k.check(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k Knob) check(v any) error {
+	switch k.Type {
+	case TypeBool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", k.Name)
+		}
+	case TypeString:
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s must be a string", k.Name)
+		}
+	case TypeEnum:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", k.Name)
+		}
+		for _, allowed := range k.Values {
+			if s == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %q", k.Name, k.Values)
+	case TypeInt, TypeFloat:
+		n, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("%s must be a number", k.Name)
+		}
+		if k.Type == TypeInt && n != float64(int64(n)) {
+			return fmt.Errorf("%s must be an integer", k.Name)
+		}
+		if n == 0 && k.Zero != "" {
+			return nil
+		}
+		if (k.Min != nil && n < *k.Min) || (k.Max != nil && n > *k.Max) {
+			return fmt.Errorf("%s %s", k.Name, k.rangeText())
+		}
+	}
+	return nil
+}
+
+func (k Knob) rangeText() string {
+	var b strings.Builder
+	b.WriteString("must be ")
+	if k.Zero != "" {
+		fmt.Fprintf(&b, "0 (%s) or ", k.Zero)
+	}
+	switch {
+	case k.Min != nil && k.Max != nil:
+		fmt.Fprintf(&b, "between %g and %g", *k.Min, *k.Max)
+	case k.Min != nil:
+		fmt.Fprintf(&b, "at least %g", *k.Min)
+	default:
+		fmt.Fprintf(&b, "at most %g", *k.Max)
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package knobs
+
+import (
+	"testing"
+
+	"waverless/pkg/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestListUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, k := range List() {
+		assert.False(t, seen[k.Name], k.Name)
+		seen[k.Name] = true
+		assert.NotEmpty(t, k.Description, k.Name)
+		assert.NoError(t, k.check(toJSONValue(k.Default)), "default of %s", k.Name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(&interfaces.UpdateEndpointConfigRequest{}))
+	assert.NoError(t, Validate(&interfaces.UpdateEndpointConfigRequest{
+		MinReplicas:        intPtr(0),
+		Priority:           intPtr(100),
+		EvaluationInterval: intPtr(0),
+		MaxPendingTasks:    intPtr(0),
+	}))
+
+	cases := []struct {
+		req  *interfaces.UpdateEndpointConfigRequest
+		want string
+	}{
+		{&interfaces.UpdateEndpointConfigRequest{Priority: intPtr(101)}, "priority must be between 0 and 100"},
+		{&interfaces.UpdateEndpointConfigRequest{EvaluationInterval: intPtr(1)}, "evaluationInterval must be 0 (global interval) or at least 2"},
+		{&interfaces.UpdateEndpointConfigRequest{ScaleUpThreshold: intPtr(0)}, "scaleUpThreshold must be at least 1"},
+		{&interfaces.UpdateEndpointConfigRequest{QueueWaitSLO: intPtr(-1)}, "queueWaitSLO must be at least 0"},
+	}
+	for _, tc := range cases {
+		err := Validate(tc.req)
+		require.Error(t, err)
+		assert.Equal(t, tc.want, err.Error())
+	}
+
+	profile := "fastest"
+	assert.ErrorContains(t, Validate(&interfaces.UpdateEndpointConfigRequest{Profile: &profile}), "profile must be one of")
+	assert.ErrorContains(t, Validate(map[string]any{"minReplicas": 1.5}), "minReplicas must be an integer")
+	assert.ErrorContains(t, Validate(map[string]any{"enableDynamicPrio": "yes"}), "enableDynamicPrio must be a boolean")
+}
+
+// toJSONValue converts a Go default to the value it decodes to from JSON
+func toJSONValue(v any) any {
+	if n, ok := v.(int); ok {
+		return float64(n)
+	}
+	return v
+}
@@ -0,0 +1,103 @@
+package loadtest
+
+import "fmt"
+
+// Default regression thresholds
+const (
+	DefaultMaxLatencyIncrease   = 0.2  // p50, p90 or p99 latency 20% above the baseline
+	DefaultMaxErrorRateIncrease = 0.01 // Error rate 1 percentage point above the baseline
+)
+
+// Compared metrics
+const (
+	MetricLatencyP50     = "latency_p50_ms"
+	MetricLatencyP90     = "latency_p90_ms"
+	MetricLatencyP99     = "latency_p99_ms"
+	MetricErrorRate      = "error_rate"
+	MetricAchievedRPS    = "achieved_rps"
+	MetricQueueWaitP90   = "queue_wait_p90_ms"
+	MetricPeakReplicas   = "peak_replicas"
+	MetricReplicaMinutes = "replica_minutes"
+	MetricFirstScaleUp   = "first_scale_up_seconds"
+)
+
+// Thresholds decide when a metric of a report regressed against its baseline
+type Thresholds struct {
+	MaxLatencyIncrease   float64 // Relative increase of a latency percentile, e.g. 0.2
+	MaxErrorRateIncrease float64 // Absolute increase of the error rate, e.g. 0.01
+}
+
+// Delta is a metric of a report next to its baseline
+type Delta struct {
+	Metric    string  `json:"metric"`
+	Baseline  float64 `json:"baseline"`
+	Current   float64 `json:"current"`
+	Change    float64 `json:"change"` // Relative change, absolute for the error rate
+	Regressed bool    `json:"regressed,omitempty"`
+}
+
+// Comparison is a report compared with the report of a baseline run
+type Comparison struct {
+	Metrics     []Delta  `json:"metrics"`
+	Regressed   bool     `json:"regressed"`
+	Regressions []string `json:"regressions,omitempty"` // What regressed, readable
+}
+
+// Compare compares a report with its baseline. Latency percentiles and the error rate regress
+// beyond the thresholds; throughput and autoscaler metrics are reported without a verdict,
+// since the same load may be served well by a different number of replicas.
+func Compare(baseline, current *Report, thresholds Thresholds) *Comparison {
+	if thresholds.MaxLatencyIncrease <= 0 {
+		thresholds.MaxLatencyIncrease = DefaultMaxLatencyIncrease
+	}
+	if thresholds.MaxErrorRateIncrease <= 0 {
+		thresholds.MaxErrorRateIncrease = DefaultMaxErrorRateIncrease
+	}
+	c := &Comparison{}
+
+	latency := func(metric string, base, cur float64) {
+		d := relative(metric, base, cur)
+		d.Regressed = base > 0 && d.Change > thresholds.MaxLatencyIncrease
+		c.add(d, fmt.Sprintf("%s rose %.0f%% (%.0fms -> %.0fms), limit %.0f%%",
+			metric, d.Change*100, base, cur, thresholds.MaxLatencyIncrease*100))
+	}
+	latency(MetricLatencyP50, baseline.Summary.LatencyMs.P50, current.Summary.LatencyMs.P50)
+	latency(MetricLatencyP90, baseline.Summary.LatencyMs.P90, current.Summary.LatencyMs.P90)
+	latency(MetricLatencyP99, baseline.Summary.LatencyMs.P99, current.Summary.LatencyMs.P99)
+
+	errRate := Delta{
+		Metric:   MetricErrorRate,
+		Baseline: baseline.Summary.ErrorRate,
+		Current:  current.Summary.ErrorRate,
+		Change:   current.Summary.ErrorRate - baseline.Summary.ErrorRate,
+	}
+	errRate.Regressed = errRate.Change > thresholds.MaxErrorRateIncrease
+	c.add(errRate, fmt.Sprintf("%s rose from %.2f%% to %.2f%%, limit +%.2f points",
+		MetricErrorRate, errRate.Baseline*100, errRate.Current*100, thresholds.MaxErrorRateIncrease*100))
+
+	c.add(relative(MetricAchievedRPS, baseline.Summary.AchievedRPS, current.Summary.AchievedRPS), "")
+	c.add(relative(MetricQueueWaitP90, baseline.Summary.QueueWaitMs.P90, current.Summary.QueueWaitMs.P90), "")
+	c.add(relative(MetricPeakReplicas, float64(baseline.Autoscaler.PeakReplicas), float64(current.Autoscaler.PeakReplicas)), "")
+	c.add(relative(MetricReplicaMinutes, baseline.Autoscaler.ReplicaMinutes, current.Autoscaler.ReplicaMinutes), "")
+	if baseline.Autoscaler.FirstScaleUpSeconds != nil && current.Autoscaler.FirstScaleUpSeconds != nil {
+		c.add(relative(MetricFirstScaleUp, *baseline.Autoscaler.FirstScaleUpSeconds, *current.Autoscaler.FirstScaleUpSeconds), "")
+	}
+	return c
+}
+
+func (c *Comparison) add(d Delta, regression string) {
+	c.Metrics = append(c.Metrics, d)
+	if d.Regressed {
+		c.Regressed = true
+		c.Regressions = append(c.Regressions, regression)
+	}
+}
+
+// relative returns the relative change of a metric; 0 when the baseline is 0
+func relative(metric string, base, cur float64) Delta {
+	d := Delta{Metric: metric, Baseline: base, Current: cur}
+	if base > 0 {
+		d.Change = (cur - base) / base
+	}
+	return d
+}
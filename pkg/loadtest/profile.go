@@ -0,0 +1,126 @@
+// Package loadtest plans the request rate of a load test from an RPS profile, and turns the
+// outcomes of the tasks it submitted and the replicas observed meanwhile into a report with
+// latency, error and autoscaler curves. Reports of the same test are compared to catch
+// regressions between image versions.
+package loadtest
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// MaxStages bounds the stages of a profile
+const MaxStages = 50
+
+// Stage is a step of an RPS profile
+type Stage struct {
+	DurationSeconds int     `json:"durationSeconds"`
+	RPS             float64 `json:"rps"`            // Requests per second at the end of the stage
+	Ramp            bool    `json:"ramp,omitempty"` // Ramp linearly from the rate of the previous stage (0 before the first) instead of holding RPS
+}
+
+// Profile is the request rate of a load test over time, as consecutive stages
+type Profile []Stage
+
+// Validate checks the stages against the rate and duration limits of the control plane
+func (p Profile) Validate(maxRPS float64, maxDuration time.Duration) error {
+	if len(p) == 0 {
+		return fmt.Errorf("profile needs at least one stage")
+	}
+	if len(p) > MaxStages {
+		return fmt.Errorf("profile has %d stages, at most %d are allowed", len(p), MaxStages)
+	}
+	var peak float64
+	for i, stage := range p {
+		if stage.DurationSeconds <= 0 {
+			return fmt.Errorf("stage %d: durationSeconds must be > 0", i+1)
+		}
+		if stage.RPS < 0 || math.IsNaN(stage.RPS) || math.IsInf(stage.RPS, 0) {
+			return fmt.Errorf("stage %d: rps must be >= 0", i+1)
+		}
+		if maxRPS > 0 && stage.RPS > maxRPS {
+			return fmt.Errorf("stage %d: rps %g exceeds the limit of %g", i+1, stage.RPS, maxRPS)
+		}
+		peak = math.Max(peak, stage.RPS)
+	}
+	if peak == 0 {
+		return fmt.Errorf("profile sends no requests: every stage has rps 0")
+	}
+	if maxDuration > 0 && p.Duration() > maxDuration {
+		return fmt.Errorf("profile lasts %s, at most %s is allowed", p.Duration(), maxDuration)
+	}
+	return nil
+}
+
+// Duration returns the total duration of the profile
+func (p Profile) Duration() time.Duration {
+	var total int
+	for _, stage := range p {
+		total += stage.DurationSeconds
+	}
+	return time.Duration(total) * time.Second
+}
+
+// RateAt returns the target requests per second at t since the start; 0 after the end
+func (p Profile) RateAt(t time.Duration) float64 {
+	if t < 0 {
+		return 0
+	}
+	var start time.Duration
+	var prev float64
+	for _, stage := range p {
+		d := time.Duration(stage.DurationSeconds) * time.Second
+		if t < start+d {
+			from := stage.RPS
+			if stage.Ramp {
+				from = prev
+			}
+			return from + (stage.RPS-from)*float64(t-start)/float64(d)
+		}
+		start += d
+		prev = stage.RPS
+	}
+	return 0
+}
+
+// Expected returns the number of requests the profile sends in [0, t), fractional
+func (p Profile) Expected(t time.Duration) float64 {
+	if t <= 0 {
+		return 0
+	}
+	var total float64
+	var start time.Duration
+	var prev float64
+	for _, stage := range p {
+		d := time.Duration(stage.DurationSeconds) * time.Second
+		from := stage.RPS
+		if stage.Ramp {
+			from = prev
+		}
+		elapsed := d
+		if t < start+d {
+			elapsed = t - start
+		}
+		// Integral of the linear rate from the start of the stage over elapsed
+		secs, length := elapsed.Seconds(), d.Seconds()
+		total += from*secs + (stage.RPS-from)*secs*secs/(2*length)
+		if t < start+d {
+			return total
+		}
+		start += d
+		prev = stage.RPS
+	}
+	return total
+}
+
+// Due returns how many requests must have been sent by t since the start
+func (p Profile) Due(t time.Duration) int {
+	// The epsilon keeps float error from holding back a request that is due exactly at t
+	return int(math.Floor(p.Expected(t) + 1e-9))
+}
+
+// Total returns the number of requests of the whole profile
+func (p Profile) Total() int {
+	return p.Due(p.Duration())
+}
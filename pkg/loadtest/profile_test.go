@@ -0,0 +1,51 @@
+package loadtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile_HoldAndRamp(t *testing.T) {
+	// 10s at 2 rps, ramp to 10 rps over 20s, hold 10 rps for 10s
+	p := Profile{
+		{DurationSeconds: 10, RPS: 2},
+		{DurationSeconds: 20, RPS: 10, Ramp: true},
+		{DurationSeconds: 10, RPS: 10},
+	}
+
+	assert.Equal(t, 40*time.Second, p.Duration())
+	assert.InDelta(t, 2, p.RateAt(5*time.Second), 1e-9)
+	assert.InDelta(t, 6, p.RateAt(20*time.Second), 1e-9)
+	assert.InDelta(t, 10, p.RateAt(35*time.Second), 1e-9)
+	assert.Zero(t, p.RateAt(40*time.Second))
+
+	assert.InDelta(t, 20, p.Expected(10*time.Second), 1e-9)
+	assert.InDelta(t, 20+(2+6)/2.0*10, p.Expected(20*time.Second), 1e-9)
+	assert.InDelta(t, 20+120+100, p.Expected(time.Minute), 1e-9)
+	assert.Equal(t, 240, p.Total())
+
+	assert.Equal(t, 0, p.Due(0))
+	assert.Equal(t, 1, p.Due(500*time.Millisecond))
+	assert.Equal(t, 20, p.Due(10*time.Second))
+}
+
+func TestProfile_RampFromZero(t *testing.T) {
+	p := Profile{{DurationSeconds: 10, RPS: 4, Ramp: true}}
+	assert.Zero(t, p.RateAt(0))
+	assert.InDelta(t, 5, p.Expected(5*time.Second), 1e-9)
+	assert.Equal(t, 20, p.Total())
+}
+
+func TestProfile_Validate(t *testing.T) {
+	ok := Profile{{DurationSeconds: 60, RPS: 5}}
+	assert.NoError(t, ok.Validate(10, time.Hour))
+
+	assert.ErrorContains(t, Profile{}.Validate(10, time.Hour), "at least one stage")
+	assert.ErrorContains(t, Profile{{DurationSeconds: 0, RPS: 1}}.Validate(10, time.Hour), "durationSeconds")
+	assert.ErrorContains(t, Profile{{DurationSeconds: 10, RPS: -1}}.Validate(10, time.Hour), "rps must be >= 0")
+	assert.ErrorContains(t, Profile{{DurationSeconds: 10, RPS: 20}}.Validate(10, time.Hour), "exceeds the limit")
+	assert.ErrorContains(t, Profile{{DurationSeconds: 10, RPS: 0}}.Validate(10, time.Hour), "sends no requests")
+	assert.ErrorContains(t, Profile{{DurationSeconds: 7200, RPS: 1}}.Validate(10, time.Hour), "at most 1h0m0s")
+}
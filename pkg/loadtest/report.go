@@ -0,0 +1,284 @@
+package loadtest
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Request outcomes
+const (
+	OutcomeCompleted = "completed"
+	OutcomeFailed    = "failed"    // The task failed or was cancelled
+	OutcomeTimedOut  = "timed_out" // The task did not finish within the task timeout
+	OutcomeRejected  = "rejected"  // The task could not be submitted
+)
+
+// Bucket widths of the curves: at least minBucket, wide enough for at most maxBuckets buckets
+const (
+	minBucket  = 10 * time.Second
+	maxBuckets = 180
+)
+
+// Request is the outcome of one request of a run
+type Request struct {
+	Submitted time.Duration // Since the start of the run
+	Outcome   string
+	Latency   time.Duration // Submission to completion, for finished tasks
+	QueueWait time.Duration // Submission to start, for tasks that started
+	Started   bool
+}
+
+// ScalerSample is the replica count and queue of the endpoint at a point of the run
+type ScalerSample struct {
+	OffsetSeconds   float64 `json:"offsetSeconds"`
+	DesiredReplicas int     `json:"desiredReplicas"`
+	ReadyReplicas   int     `json:"readyReplicas"`
+	Pending         int64   `json:"pending"` // Tasks waiting in the queue
+}
+
+// ScalingEvent is a decision of the autoscaler during the run
+type ScalingEvent struct {
+	OffsetSeconds float64 `json:"offsetSeconds"`
+	Action        string  `json:"action"`
+	FromReplicas  int     `json:"fromReplicas"`
+	ToReplicas    int     `json:"toReplicas"`
+	Reason        string  `json:"reason,omitempty"`
+}
+
+// Percentiles of a distribution, in milliseconds
+type Percentiles struct {
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+}
+
+// Summary is the outcome of a whole run
+type Summary struct {
+	DurationSeconds float64     `json:"durationSeconds"` // Of the profile
+	Requests        int         `json:"requests"`
+	Completed       int         `json:"completed"`
+	Failed          int         `json:"failed"`
+	TimedOut        int         `json:"timedOut"`
+	Rejected        int         `json:"rejected"`
+	ErrorRate       float64     `json:"errorRate"`   // (failed + timed out + rejected) / requests
+	AchievedRPS     float64     `json:"achievedRps"` // Completed tasks per second of the profile
+	LatencyMs       Percentiles `json:"latencyMs"`   // Submission to completion of completed tasks
+	QueueWaitMs     Percentiles `json:"queueWaitMs"` // Submission to start of started tasks
+}
+
+// Bucket is one point of the curves of a run, covering the requests submitted in it
+type Bucket struct {
+	StartSeconds    float64     `json:"startSeconds"`
+	TargetRPS       float64     `json:"targetRps"`    // Average rate of the profile
+	SubmittedRPS    float64     `json:"submittedRps"` // Rate actually submitted
+	Completed       int         `json:"completed"`
+	Errors          int         `json:"errors"` // Failed, timed out and rejected
+	ErrorRate       float64     `json:"errorRate"`
+	LatencyMs       Percentiles `json:"latencyMs"`
+	DesiredReplicas int         `json:"desiredReplicas"` // At the end of the bucket
+	ReadyReplicas   int         `json:"readyReplicas"`   // At the end of the bucket
+	Pending         int64       `json:"pending"`         // At the end of the bucket
+}
+
+// Autoscaler is how the endpoint scaled during a run
+type Autoscaler struct {
+	StartReplicas       int            `json:"startReplicas"` // Ready replicas
+	PeakReplicas        int            `json:"peakReplicas"`
+	EndReplicas         int            `json:"endReplicas"`
+	PeakDesiredReplicas int            `json:"peakDesiredReplicas"`
+	PeakPending         int64          `json:"peakPending"`
+	ReplicaMinutes      float64        `json:"replicaMinutes"` // Ready replicas integrated over the run, a proxy of its cost
+	ScaleUps            int            `json:"scaleUps"`
+	ScaleDowns          int            `json:"scaleDowns"`
+	FirstScaleUpSeconds *float64       `json:"firstScaleUpSeconds,omitempty"` // When the first scale-up was decided; nil without one
+	Samples             []ScalerSample `json:"samples"`
+	Events              []ScalingEvent `json:"events,omitempty"`
+}
+
+// Report is the result of a run: a summary, curves over time and the autoscaler behavior
+type Report struct {
+	BucketSeconds float64    `json:"bucketSeconds"`
+	Summary       Summary    `json:"summary"`
+	Curve         []Bucket   `json:"curve"`
+	Autoscaler    Autoscaler `json:"autoscaler"`
+}
+
+// BucketWidth returns the width of the curve buckets of a profile lasting d
+func BucketWidth(d time.Duration) time.Duration {
+	width := minBucket
+	if d/maxBuckets > width {
+		width = (d/maxBuckets + time.Second - 1).Truncate(time.Second)
+	}
+	return width
+}
+
+// BuildReport builds the report of a run of profile. samples and events are ordered by time.
+func BuildReport(profile Profile, requests []Request, samples []ScalerSample, events []ScalingEvent) *Report {
+	duration := profile.Duration()
+	width := BucketWidth(duration)
+	report := &Report{
+		BucketSeconds: width.Seconds(),
+		Summary:       summarize(requests, duration),
+		Autoscaler:    autoscalerBehavior(samples, events),
+	}
+
+	n := int((duration + width - 1) / width)
+	groups := make([][]Request, n)
+	for _, req := range requests {
+		i := int(req.Submitted / width)
+		if i >= n {
+			i = n - 1
+		}
+		if i >= 0 {
+			groups[i] = append(groups[i], req)
+		}
+	}
+	report.Curve = make([]Bucket, n)
+	for i := range groups {
+		start := time.Duration(i) * width
+		end := start + width
+		if end > duration {
+			end = duration
+		}
+		secs := (end - start).Seconds()
+		s := summarize(groups[i], end-start)
+		b := Bucket{
+			StartSeconds: start.Seconds(),
+			TargetRPS:    (profile.Expected(end) - profile.Expected(start)) / secs,
+			SubmittedRPS: float64(s.Requests-s.Rejected) / secs,
+			Completed:    s.Completed,
+			Errors:       s.Failed + s.TimedOut + s.Rejected,
+			ErrorRate:    s.ErrorRate,
+			LatencyMs:    s.LatencyMs,
+		}
+		if sample, ok := sampleAt(samples, end.Seconds()); ok {
+			b.DesiredReplicas, b.ReadyReplicas, b.Pending = sample.DesiredReplicas, sample.ReadyReplicas, sample.Pending
+		}
+		report.Curve[i] = b
+	}
+	return report
+}
+
+// summarize computes the outcome of requests sent over duration
+func summarize(requests []Request, duration time.Duration) Summary {
+	s := Summary{DurationSeconds: duration.Seconds(), Requests: len(requests)}
+	var latencies, waits []float64
+	for _, req := range requests {
+		switch req.Outcome {
+		case OutcomeCompleted:
+			s.Completed++
+			latencies = append(latencies, ms(req.Latency))
+		case OutcomeFailed:
+			s.Failed++
+		case OutcomeTimedOut:
+			s.TimedOut++
+		case OutcomeRejected:
+			s.Rejected++
+		}
+		if req.Started {
+			waits = append(waits, ms(req.QueueWait))
+		}
+	}
+	if s.Requests > 0 {
+		s.ErrorRate = float64(s.Failed+s.TimedOut+s.Rejected) / float64(s.Requests)
+	}
+	if duration > 0 {
+		s.AchievedRPS = float64(s.Completed) / duration.Seconds()
+	}
+	s.LatencyMs = percentiles(latencies)
+	s.QueueWaitMs = percentiles(waits)
+	return s
+}
+
+// autoscalerBehavior summarizes the replica samples and scaling events of a run
+func autoscalerBehavior(samples []ScalerSample, events []ScalingEvent) Autoscaler {
+	a := Autoscaler{Samples: samples, Events: events}
+	if len(samples) > 0 {
+		a.StartReplicas = samples[0].ReadyReplicas
+		a.EndReplicas = samples[len(samples)-1].ReadyReplicas
+	}
+	for i, sample := range samples {
+		if sample.ReadyReplicas > a.PeakReplicas {
+			a.PeakReplicas = sample.ReadyReplicas
+		}
+		if sample.DesiredReplicas > a.PeakDesiredReplicas {
+			a.PeakDesiredReplicas = sample.DesiredReplicas
+		}
+		if sample.Pending > a.PeakPending {
+			a.PeakPending = sample.Pending
+		}
+		if i > 0 {
+			prev := samples[i-1]
+			a.ReplicaMinutes += float64(prev.ReadyReplicas) * (sample.OffsetSeconds - prev.OffsetSeconds) / 60
+		}
+	}
+	for _, event := range events {
+		switch {
+		case event.ToReplicas > event.FromReplicas:
+			a.ScaleUps++
+			if a.FirstScaleUpSeconds == nil {
+				offset := event.OffsetSeconds
+				a.FirstScaleUpSeconds = &offset
+			}
+		case event.ToReplicas < event.FromReplicas:
+			a.ScaleDowns++
+		}
+	}
+	// Without scaling events (e.g. another autoscaler), the first rise of the desired replicas
+	if a.FirstScaleUpSeconds == nil {
+		for _, sample := range samples {
+			if sample.DesiredReplicas > samples[0].DesiredReplicas {
+				offset := sample.OffsetSeconds
+				a.FirstScaleUpSeconds = &offset
+				break
+			}
+		}
+	}
+	return a
+}
+
+// sampleAt returns the last sample taken at or before offset, or the first one
+func sampleAt(samples []ScalerSample, offset float64) (ScalerSample, bool) {
+	if len(samples) == 0 {
+		return ScalerSample{}, false
+	}
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].OffsetSeconds > offset })
+	if i == 0 {
+		return samples[0], true
+	}
+	return samples[i-1], true
+}
+
+// percentiles returns the nearest-rank percentiles of values
+func percentiles(values []float64) Percentiles {
+	if len(values) == 0 {
+		return Percentiles{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return sorted[i]
+	}
+	return Percentiles{
+		P50:  rank(0.50),
+		P90:  rank(0.90),
+		P99:  rank(0.99),
+		Max:  sorted[len(sorted)-1],
+		Mean: sum / float64(len(sorted)),
+	}
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
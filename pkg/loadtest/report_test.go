@@ -0,0 +1,132 @@
+package loadtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func completed(submitted, latency time.Duration) Request {
+	return Request{Submitted: submitted, Outcome: OutcomeCompleted, Latency: latency, QueueWait: latency / 2, Started: true}
+}
+
+func TestBuildReport(t *testing.T) {
+	p := Profile{{DurationSeconds: 20, RPS: 1}}
+	var requests []Request
+	for i := 0; i < 10; i++ {
+		requests = append(requests, completed(time.Duration(i)*time.Second, time.Duration(i+1)*100*time.Millisecond))
+	}
+	requests = append(requests,
+		Request{Submitted: 12 * time.Second, Outcome: OutcomeFailed, Latency: time.Second, Started: true},
+		Request{Submitted: 14 * time.Second, Outcome: OutcomeTimedOut},
+		Request{Submitted: 16 * time.Second, Outcome: OutcomeRejected},
+		completed(18*time.Second, 2*time.Second),
+	)
+	samples := []ScalerSample{
+		{OffsetSeconds: 0, DesiredReplicas: 1, ReadyReplicas: 1},
+		{OffsetSeconds: 10, DesiredReplicas: 3, ReadyReplicas: 1, Pending: 8},
+		{OffsetSeconds: 20, DesiredReplicas: 3, ReadyReplicas: 3, Pending: 2},
+	}
+	events := []ScalingEvent{{OffsetSeconds: 6, Action: "scale_up", FromReplicas: 1, ToReplicas: 3}}
+
+	r := BuildReport(p, requests, samples, events)
+
+	assert.Equal(t, 10.0, r.BucketSeconds)
+	s := r.Summary
+	assert.Equal(t, 14, s.Requests)
+	assert.Equal(t, 11, s.Completed)
+	assert.Equal(t, 1, s.Failed)
+	assert.Equal(t, 1, s.TimedOut)
+	assert.Equal(t, 1, s.Rejected)
+	assert.InDelta(t, 3.0/14, s.ErrorRate, 1e-9)
+	assert.InDelta(t, 11.0/20, s.AchievedRPS, 1e-9)
+	assert.Equal(t, 600.0, s.LatencyMs.P50)
+	assert.Equal(t, 1000.0, s.LatencyMs.P90)
+	assert.Equal(t, 2000.0, s.LatencyMs.P99)
+	assert.Equal(t, 2000.0, s.LatencyMs.Max)
+
+	require.Len(t, r.Curve, 2)
+	first, second := r.Curve[0], r.Curve[1]
+	assert.Equal(t, 0.0, first.StartSeconds)
+	assert.InDelta(t, 1, first.TargetRPS, 1e-9)
+	assert.InDelta(t, 1, first.SubmittedRPS, 1e-9)
+	assert.Equal(t, 10, first.Completed)
+	assert.Zero(t, first.Errors)
+	assert.Equal(t, 3, first.DesiredReplicas)
+	assert.Equal(t, 1, first.ReadyReplicas)
+	assert.Equal(t, int64(8), first.Pending)
+	assert.Equal(t, 3, second.Errors)
+	assert.InDelta(t, 0.75, second.ErrorRate, 1e-9)
+	assert.InDelta(t, 0.3, second.SubmittedRPS, 1e-9)
+	assert.Equal(t, 3, second.ReadyReplicas)
+
+	a := r.Autoscaler
+	assert.Equal(t, 1, a.StartReplicas)
+	assert.Equal(t, 3, a.PeakReplicas)
+	assert.Equal(t, 3, a.EndReplicas)
+	assert.Equal(t, int64(8), a.PeakPending)
+	assert.InDelta(t, 20.0/60, a.ReplicaMinutes, 1e-9)
+	assert.Equal(t, 1, a.ScaleUps)
+	require.NotNil(t, a.FirstScaleUpSeconds)
+	assert.Equal(t, 6.0, *a.FirstScaleUpSeconds)
+}
+
+func TestBuildReport_FirstScaleUpFromSamples(t *testing.T) {
+	samples := []ScalerSample{
+		{OffsetSeconds: 0, DesiredReplicas: 2},
+		{OffsetSeconds: 10, DesiredReplicas: 2},
+		{OffsetSeconds: 20, DesiredReplicas: 4},
+	}
+	r := BuildReport(Profile{{DurationSeconds: 30, RPS: 1}}, nil, samples, nil)
+	require.NotNil(t, r.Autoscaler.FirstScaleUpSeconds)
+	assert.Equal(t, 20.0, *r.Autoscaler.FirstScaleUpSeconds)
+	assert.Zero(t, r.Summary.Requests)
+	assert.Len(t, r.Curve, 3)
+}
+
+func TestBucketWidth(t *testing.T) {
+	assert.Equal(t, 10*time.Second, BucketWidth(5*time.Minute))
+	assert.Equal(t, 20*time.Second, BucketWidth(time.Hour))
+	assert.Equal(t, 41*time.Second, BucketWidth(2*time.Hour+time.Second))
+}
+
+func TestCompare(t *testing.T) {
+	first := 30.0
+	baseline := &Report{
+		Summary:    Summary{ErrorRate: 0.01, AchievedRPS: 10, LatencyMs: Percentiles{P50: 100, P90: 200, P99: 400}},
+		Autoscaler: Autoscaler{PeakReplicas: 4, FirstScaleUpSeconds: &first},
+	}
+
+	// Within the thresholds
+	current := &Report{
+		Summary:    Summary{ErrorRate: 0.015, AchievedRPS: 10, LatencyMs: Percentiles{P50: 110, P90: 230, P99: 470}},
+		Autoscaler: Autoscaler{PeakReplicas: 6, FirstScaleUpSeconds: &first},
+	}
+	c := Compare(baseline, current, Thresholds{})
+	assert.False(t, c.Regressed)
+	assert.Empty(t, c.Regressions)
+	assert.Len(t, c.Metrics, 9)
+
+	// p99 up 50% and error rate up 4 points
+	current.Summary.LatencyMs.P99 = 600
+	current.Summary.ErrorRate = 0.05
+	c = Compare(baseline, current, Thresholds{})
+	assert.True(t, c.Regressed)
+	require.Len(t, c.Regressions, 2)
+	assert.Contains(t, c.Regressions[0], MetricLatencyP99)
+	assert.Contains(t, c.Regressions[1], MetricErrorRate)
+
+	// Looser thresholds
+	c = Compare(baseline, current, Thresholds{MaxLatencyIncrease: 0.6, MaxErrorRateIncrease: 0.05})
+	assert.False(t, c.Regressed)
+
+	// Peak replicas are reported without a verdict
+	for _, d := range c.Metrics {
+		if d.Metric == MetricPeakReplicas {
+			assert.InDelta(t, 0.5, d.Change, 1e-9)
+			assert.False(t, d.Regressed)
+		}
+	}
+}
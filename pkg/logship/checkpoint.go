@@ -0,0 +1,74 @@
+package logship
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// checkpointTTL keeps checkpoints of deleted workers from accumulating
+const checkpointTTL = 24 * time.Hour
+
+// CheckpointStore remembers the timestamp of the last shipped line per worker
+type CheckpointStore interface {
+	Get(ctx context.Context, key string) (time.Time, bool, error)
+	Set(ctx context.Context, key string, ts time.Time) error
+}
+
+// RedisCheckpointStore stores checkpoints in Redis so they survive restarts and
+// leadership changes between replicas
+type RedisCheckpointStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCheckpointStore creates a Redis-backed checkpoint store
+func NewRedisCheckpointStore(client *redis.Client) *RedisCheckpointStore {
+	return &RedisCheckpointStore{client: client, prefix: "logship:checkpoint:"}
+}
+
+func (s *RedisCheckpointStore) Get(ctx context.Context, key string) (time.Time, bool, error) {
+	val, err := s.client.Get(ctx, s.prefix+key).Result()
+	if err == redis.Nil {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	ts, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return time.Time{}, false, nil
+	}
+	return ts, true, nil
+}
+
+func (s *RedisCheckpointStore) Set(ctx context.Context, key string, ts time.Time) error {
+	return s.client.Set(ctx, s.prefix+key, ts.UTC().Format(time.RFC3339Nano), checkpointTTL).Err()
+}
+
+// MemoryCheckpointStore keeps checkpoints in process memory (single instance / no Redis)
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]time.Time
+}
+
+// NewMemoryCheckpointStore creates an in-memory checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]time.Time)}
+}
+
+func (s *MemoryCheckpointStore) Get(ctx context.Context, key string) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ts, ok := s.checkpoints[key]
+	return ts, ok, nil
+}
+
+func (s *MemoryCheckpointStore) Set(ctx context.Context, key string, ts time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[key] = ts
+	return nil
+}
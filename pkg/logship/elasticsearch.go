@@ -0,0 +1,227 @@
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// elasticsearchMapping keeps endpoint/worker/task_id as keywords so term filters are exact
+const elasticsearchMapping = `{
+  "mappings": {
+    "properties": {
+      "@timestamp": {"type": "date_nanos"},
+      "endpoint":   {"type": "keyword"},
+      "worker":     {"type": "keyword"},
+      "task_id":    {"type": "keyword"},
+      "message":    {"type": "text"}
+    }
+  }
+}`
+
+// ElasticsearchSink indexes logs into Elasticsearch / OpenSearch with the bulk API
+type ElasticsearchSink struct {
+	url        string
+	index      string
+	username   string
+	password   string
+	apiKey     string
+	httpClient *http.Client
+
+	indexMu    sync.Mutex
+	indexReady bool
+}
+
+// NewElasticsearchSink creates a new Elasticsearch sink
+func NewElasticsearchSink(baseURL, index, username, password, apiKey string) (*ElasticsearchSink, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("elasticsearch url is required")
+	}
+	if index == "" {
+		index = "waverless-logs"
+	}
+	return &ElasticsearchSink{
+		url:        strings.TrimSuffix(baseURL, "/"),
+		index:      index,
+		username:   username,
+		password:   password,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *ElasticsearchSink) Name() string { return "elasticsearch" }
+
+type esDocument struct {
+	Timestamp string `json:"@timestamp"`
+	Endpoint  string `json:"endpoint"`
+	Worker    string `json:"worker"`
+	TaskID    string `json:"task_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// ensureIndex creates the index with keyword mappings once; an existing index is left untouched
+func (s *ElasticsearchSink) ensureIndex(ctx context.Context) error {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if s.indexReady {
+		return nil
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, "/"+s.index, "application/json", []byte(elasticsearchMapping))
+	if err != nil {
+		return fmt.Errorf("failed to create index %s: %w", s.index, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(msg), "resource_already_exists_exception") {
+			return fmt.Errorf("failed to create index %s: status %d: %s", s.index, resp.StatusCode, strings.TrimSpace(string(msg)))
+		}
+	}
+	s.indexReady = true
+	return nil
+}
+
+func (s *ElasticsearchSink) Push(ctx context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := s.ensureIndex(ctx); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	action := []byte(`{"index":{}}` + "\n")
+	for _, e := range entries {
+		doc, err := json.Marshal(esDocument{
+			Timestamp: e.Timestamp.UTC().Format(time.RFC3339Nano),
+			Endpoint:  e.Endpoint,
+			Worker:    e.Worker,
+			TaskID:    e.TaskID,
+			Message:   e.Line,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode log document: %w", err)
+		}
+		buf.Write(action)
+		buf.Write(doc)
+		buf.WriteByte('\n')
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, "/"+s.index+"/_bulk", "application/x-ndjson", buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("elasticsearch bulk failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("elasticsearch bulk returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse bulk response: %w", err)
+	}
+	if result.Errors {
+		return fmt.Errorf("elasticsearch bulk request had item errors")
+	}
+	return nil
+}
+
+// buildSearchQuery builds the search request body for a query
+func buildSearchQuery(q *Query) map[string]interface{} {
+	filters := []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"endpoint": q.Endpoint}},
+		map[string]interface{}{"range": map[string]interface{}{"@timestamp": map[string]interface{}{
+			"gte": q.Start.UTC().Format(time.RFC3339Nano),
+			"lte": q.End.UTC().Format(time.RFC3339Nano),
+		}}},
+	}
+	if q.Worker != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"worker": q.Worker}})
+	}
+	if q.TaskID != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"task_id": q.TaskID}})
+	}
+
+	boolQuery := map[string]interface{}{"filter": filters}
+	if q.Contains != "" {
+		boolQuery["must"] = []interface{}{
+			map[string]interface{}{"match_phrase": map[string]interface{}{"message": q.Contains}},
+		}
+	}
+
+	order := "desc"
+	if q.Forward {
+		order = "asc"
+	}
+	return map[string]interface{}{
+		"size":  q.Limit,
+		"query": map[string]interface{}{"bool": boolQuery},
+		"sort":  []interface{}{map[string]interface{}{"@timestamp": map[string]interface{}{"order": order}}},
+	}
+}
+
+func (s *ElasticsearchSink) Query(ctx context.Context, q *Query) ([]Entry, error) {
+	body, err := json.Marshal(buildSearchQuery(q))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, "/"+s.index+"/_search", "application/json", body)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch search failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("elasticsearch search returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source esDocument `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		ts, _ := time.Parse(time.RFC3339Nano, hit.Source.Timestamp)
+		entries = append(entries, Entry{
+			Timestamp: ts,
+			Endpoint:  hit.Source.Endpoint,
+			Worker:    hit.Source.Worker,
+			TaskID:    hit.Source.TaskID,
+			Line:      hit.Source.Message,
+		})
+	}
+	return entries, nil
+}
+
+func (s *ElasticsearchSink) do(ctx context.Context, method, path, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+s.apiKey)
+	} else if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	return s.httpClient.Do(req)
+}
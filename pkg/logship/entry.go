@@ -0,0 +1,97 @@
+// Package logship ships worker stdout/stderr to external log storage (Loki, Elasticsearch)
+// so logs survive pod deletion, and queries them back through a provider-agnostic API.
+package logship
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Entry is a single worker log line
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Endpoint  string    `json:"endpoint"`
+	Worker    string    `json:"worker"` // Pod name / worker ID
+	TaskID    string    `json:"taskId,omitempty"`
+	Line      string    `json:"line"`
+}
+
+// Query selects shipped log lines
+type Query struct {
+	Endpoint string
+	Worker   string // Optional
+	TaskID   string // Optional
+	Contains string // Optional substring filter
+	Start    time.Time
+	End      time.Time
+	Limit    int
+	Forward  bool // Oldest first (default: newest first)
+}
+
+// Target identifies a worker whose logs are collected
+type Target struct {
+	Endpoint string
+	Worker   string
+}
+
+// Key returns the checkpoint key of a target
+func (t Target) Key() string {
+	return t.Endpoint + "/" + t.Worker
+}
+
+// Source reads worker logs from a deployment provider
+type Source interface {
+	// ListTargets returns the workers whose logs can currently be read
+	ListTargets(ctx context.Context) ([]Target, error)
+	// ReadSince returns log entries of a worker written at or after since, oldest first
+	ReadSince(ctx context.Context, target Target, since time.Time) ([]Entry, error)
+}
+
+// Sink stores log entries and answers queries
+type Sink interface {
+	// Name returns the backend type
+	Name() string
+	// Push stores entries
+	Push(ctx context.Context, entries []Entry) error
+	// Query returns entries matching q
+	Query(ctx context.Context, q *Query) ([]Entry, error)
+}
+
+// Redactor masks sensitive data in the log lines of an endpoint before they are shipped
+type Redactor interface {
+	// RedactEntries rewrites the lines of entries in place
+	RedactEntries(ctx context.Context, endpoint string, entries []Entry) error
+}
+
+// ParseTimestampedLines parses log output where every line is prefixed with an
+// RFC3339Nano timestamp (e.g. kubectl logs --timestamps). A trailing line without
+// newline is treated as truncated and dropped; it is read again on the next poll.
+func ParseTimestampedLines(raw string, target Target) []Entry {
+	if raw == "" {
+		return nil
+	}
+	lines := strings.Split(raw, "\n")
+	// The last element is either empty (output ended with newline) or a truncated line
+	lines = lines[:len(lines)-1]
+
+	entries := make([]Entry, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		ts, rest, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{
+			Timestamp: t,
+			Endpoint:  target.Endpoint,
+			Worker:    target.Worker,
+			Line:      rest,
+		})
+	}
+	return entries
+}
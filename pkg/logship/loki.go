@@ -0,0 +1,203 @@
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lokiSourceLabel marks streams shipped by waverless
+const lokiSourceLabel = "waverless"
+
+// LokiSink pushes logs to Grafana Loki. endpoint and worker are stream labels;
+// task_id is sent as structured metadata to keep label cardinality low.
+type LokiSink struct {
+	url        string
+	tenantID   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewLokiSink creates a new Loki sink
+func NewLokiSink(baseURL, tenantID, username, password string) (*LokiSink, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("loki url is required")
+	}
+	return &LokiSink{
+		url:        strings.TrimSuffix(baseURL, "/"),
+		tenantID:   tenantID,
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *LokiSink) Name() string { return "loki" }
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][]interface{}   `json:"values"`
+}
+
+func (s *LokiSink) Push(ctx context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	streams := make(map[string]*lokiStream)
+	var order []string
+	for _, e := range entries {
+		key := e.Endpoint + "/" + e.Worker
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: map[string]string{
+				"source":   lokiSourceLabel,
+				"endpoint": e.Endpoint,
+				"worker":   e.Worker,
+			}}
+			streams[key] = stream
+			order = append(order, key)
+		}
+		value := []interface{}{strconv.FormatInt(e.Timestamp.UnixNano(), 10), e.Line}
+		if e.TaskID != "" {
+			value = append(value, map[string]string{"task_id": e.TaskID})
+		}
+		stream.Values = append(stream.Values, value)
+	}
+
+	payload := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, key := range order {
+		payload.Streams = append(payload.Streams, streams[key])
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode loki push: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+"/loki/api/v1/push", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.authorize(req)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("loki push failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("loki push returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// buildLogQL builds the LogQL selector for a query
+func buildLogQL(q *Query) string {
+	selector := fmt.Sprintf("{source=%q,endpoint=%q", lokiSourceLabel, q.Endpoint)
+	if q.Worker != "" {
+		selector += fmt.Sprintf(",worker=%q", q.Worker)
+	}
+	selector += "}"
+	if q.TaskID != "" {
+		selector += fmt.Sprintf(" | task_id=%q", q.TaskID)
+	}
+	if q.Contains != "" {
+		selector += fmt.Sprintf(" |= %q", q.Contains)
+	}
+	return selector
+}
+
+func (s *LokiSink) Query(ctx context.Context, q *Query) ([]Entry, error) {
+	params := url.Values{}
+	params.Set("query", buildLogQL(q))
+	params.Set("start", strconv.FormatInt(q.Start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(q.End.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(q.Limit))
+	direction := "backward"
+	if q.Forward {
+		direction = "forward"
+	}
+	params.Set("direction", direction)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"/loki/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.authorize(req)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("loki query failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("loki query returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Data struct {
+			Result []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][]string        `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse loki response: %w", err)
+	}
+
+	var entries []Entry
+	for _, stream := range result.Data.Result {
+		for _, v := range stream.Values {
+			if len(v) < 2 {
+				continue
+			}
+			ns, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			entries = append(entries, Entry{
+				Timestamp: time.Unix(0, ns).UTC(),
+				Endpoint:  stream.Stream["endpoint"],
+				Worker:    stream.Stream["worker"],
+				TaskID:    stream.Stream["task_id"],
+				Line:      v[1],
+			})
+		}
+	}
+	sortEntries(entries, q.Forward)
+	return entries, nil
+}
+
+func (s *LokiSink) authorize(req *http.Request) {
+	if s.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.tenantID)
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+}
+
+// sortEntries orders entries by time across streams
+func sortEntries(entries []Entry, forward bool) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if forward {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		}
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+}
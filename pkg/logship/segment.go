@@ -0,0 +1,233 @@
+package logship
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Task markers are single JSON lines printed by workers around task execution:
+//
+//	{"waverless_task":"start","task_id":"<id>"}
+//	{"waverless_task":"end","task_id":"<id>"}
+const (
+	markerField = "waverless_task"
+	MarkerStart = "start"
+	MarkerEnd   = "end"
+)
+
+// segmentTTL bounds how long task segments stay queryable
+const segmentTTL = 7 * 24 * time.Hour
+
+// TaskSegment is the time range of a worker's log belonging to one task
+type TaskSegment struct {
+	TaskID   string     `json:"taskId"`
+	Endpoint string     `json:"endpoint"`
+	Worker   string     `json:"worker"`
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end,omitempty"` // nil while the task is running
+}
+
+// ParseTaskMarker extracts a task marker from a log line
+func ParseTaskMarker(line string) (event, taskID string, ok bool) {
+	if !strings.Contains(line, markerField) {
+		return "", "", false
+	}
+	// Tolerate prefixes added by logging frameworks
+	i := strings.IndexByte(line, '{')
+	if i < 0 {
+		return "", "", false
+	}
+	var m struct {
+		Event  string `json:"waverless_task"`
+		TaskID string `json:"task_id"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(line[i:])), &m); err != nil {
+		return "", "", false
+	}
+	if m.TaskID == "" || (m.Event != MarkerStart && m.Event != MarkerEnd) {
+		return "", "", false
+	}
+	return m.Event, m.TaskID, true
+}
+
+// tagTaskSegments sets TaskID on entries between start/end markers and returns the
+// segments opened or closed in this batch plus the tasks still running afterwards.
+// Lines written while several tasks overlap on one worker cannot be attributed and
+// stay untagged.
+func tagTaskSegments(target Target, entries []Entry, active []*TaskSegment) ([]*TaskSegment, []*TaskSegment) {
+	var changed []*TaskSegment
+	for i := range entries {
+		event, taskID, ok := ParseTaskMarker(entries[i].Line)
+		if ok && event == MarkerStart {
+			seg := &TaskSegment{TaskID: taskID, Endpoint: target.Endpoint, Worker: target.Worker, Start: entries[i].Timestamp}
+			active = append(removeSegment(active, taskID), seg)
+			changed = append(changed, seg)
+		}
+
+		if ok {
+			entries[i].TaskID = taskID
+		} else if len(active) == 1 {
+			entries[i].TaskID = active[0].TaskID
+		}
+
+		if ok && event == MarkerEnd {
+			seg := findSegment(active, taskID)
+			if seg == nil {
+				// Start marker was never seen (e.g. rotated away), keep only the end
+				seg = &TaskSegment{TaskID: taskID, Endpoint: target.Endpoint, Worker: target.Worker, Start: entries[i].Timestamp}
+			}
+			end := entries[i].Timestamp
+			seg.End = &end
+			active = removeSegment(active, taskID)
+			changed = append(changed, seg)
+		}
+	}
+	return changed, active
+}
+
+func findSegment(segments []*TaskSegment, taskID string) *TaskSegment {
+	for _, s := range segments {
+		if s.TaskID == taskID {
+			return s
+		}
+	}
+	return nil
+}
+
+func removeSegment(segments []*TaskSegment, taskID string) []*TaskSegment {
+	out := segments[:0]
+	for _, s := range segments {
+		if s.TaskID != taskID {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// SegmentIndex stores task segments and the tasks currently running on each worker
+type SegmentIndex interface {
+	Get(ctx context.Context, taskID string) (*TaskSegment, error)
+	Save(ctx context.Context, seg *TaskSegment) error
+	Active(ctx context.Context, key string) ([]*TaskSegment, error)
+	SetActive(ctx context.Context, key string, segments []*TaskSegment) error
+}
+
+// RedisSegmentIndex stores task segments in Redis
+type RedisSegmentIndex struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisSegmentIndex creates a Redis-backed segment index
+func NewRedisSegmentIndex(client *redis.Client) *RedisSegmentIndex {
+	return &RedisSegmentIndex{client: client, prefix: "logship:"}
+}
+
+func (s *RedisSegmentIndex) Get(ctx context.Context, taskID string) (*TaskSegment, error) {
+	data, err := s.client.Get(ctx, s.prefix+"segment:"+taskID).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var seg TaskSegment
+	if err := json.Unmarshal(data, &seg); err != nil {
+		return nil, nil
+	}
+	return &seg, nil
+}
+
+func (s *RedisSegmentIndex) Save(ctx context.Context, seg *TaskSegment) error {
+	data, err := json.Marshal(seg)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.prefix+"segment:"+seg.TaskID, data, segmentTTL).Err()
+}
+
+func (s *RedisSegmentIndex) Active(ctx context.Context, key string) ([]*TaskSegment, error) {
+	data, err := s.client.Get(ctx, s.prefix+"active:"+key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var segments []*TaskSegment
+	if err := json.Unmarshal(data, &segments); err != nil {
+		return nil, nil
+	}
+	return segments, nil
+}
+
+func (s *RedisSegmentIndex) SetActive(ctx context.Context, key string, segments []*TaskSegment) error {
+	if len(segments) == 0 {
+		return s.client.Del(ctx, s.prefix+"active:"+key).Err()
+	}
+	data, err := json.Marshal(segments)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.prefix+"active:"+key, data, checkpointTTL).Err()
+}
+
+// MemorySegmentIndex keeps task segments in process memory (single instance / no Redis)
+type MemorySegmentIndex struct {
+	mu       sync.Mutex
+	segments map[string]TaskSegment
+	active   map[string][]TaskSegment
+}
+
+// NewMemorySegmentIndex creates an in-memory segment index
+func NewMemorySegmentIndex() *MemorySegmentIndex {
+	return &MemorySegmentIndex{segments: make(map[string]TaskSegment), active: make(map[string][]TaskSegment)}
+}
+
+func (s *MemorySegmentIndex) Get(ctx context.Context, taskID string) (*TaskSegment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg, ok := s.segments[taskID]
+	if !ok {
+		return nil, nil
+	}
+	return &seg, nil
+}
+
+func (s *MemorySegmentIndex) Save(ctx context.Context, seg *TaskSegment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.segments[seg.TaskID] = *seg
+	return nil
+}
+
+func (s *MemorySegmentIndex) Active(ctx context.Context, key string) ([]*TaskSegment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*TaskSegment
+	for _, seg := range s.active[key] {
+		seg := seg
+		out = append(out, &seg)
+	}
+	return out, nil
+}
+
+func (s *MemorySegmentIndex) SetActive(ctx context.Context, key string, segments []*TaskSegment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(segments) == 0 {
+		delete(s.active, key)
+		return nil
+	}
+	copied := make([]TaskSegment, len(segments))
+	for i, seg := range segments {
+		copied[i] = *seg
+	}
+	s.active[key] = copied
+	return nil
+}
@@ -0,0 +1,89 @@
+package logship
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseTaskMarker tests task marker detection
+func TestParseTaskMarker(t *testing.T) {
+	event, taskID, ok := ParseTaskMarker(`{"waverless_task":"start","task_id":"t1"}`)
+	assert.True(t, ok)
+	assert.Equal(t, MarkerStart, event)
+	assert.Equal(t, "t1", taskID)
+
+	event, taskID, ok = ParseTaskMarker(`INFO worker: {"waverless_task": "end", "task_id": "t2"}`)
+	assert.True(t, ok)
+	assert.Equal(t, MarkerEnd, event)
+	assert.Equal(t, "t2", taskID)
+
+	for _, line := range []string{
+		"plain line",
+		`{"waverless_task":"pause","task_id":"t1"}`,
+		`{"waverless_task":"start"}`,
+		`waverless_task start t1`,
+	} {
+		_, _, ok := ParseTaskMarker(line)
+		assert.False(t, ok, line)
+	}
+}
+
+// TestShipper_TaskSegments verifies lines are tagged across polls and the segment is indexed
+func TestShipper_TaskSegments(t *testing.T) {
+	base := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+	target := Target{Endpoint: "ep", Worker: "ep-abc"}
+	source := &fakeSource{
+		targets: []Target{target},
+		entries: map[string][]Entry{target.Key(): {
+			{Timestamp: at(100), Line: "idle"},
+			{Timestamp: at(200), Line: `{"waverless_task":"start","task_id":"t1"}`},
+			{Timestamp: at(300), Line: "loading model"},
+		}},
+		since: map[string]time.Time{},
+	}
+	sink := &fakeSink{}
+	shipper := NewShipper(source, sink, nil)
+
+	require.NoError(t, shipper.ShipPending(context.Background()))
+	seg, _, err := shipper.TaskLogs(context.Background(), "t1", 0)
+	require.NoError(t, err)
+	require.NotNil(t, seg)
+	assert.Nil(t, seg.End)
+
+	// Task continues in the next poll, then a second task overlaps
+	source.entries[target.Key()] = append(source.entries[target.Key()],
+		Entry{Timestamp: at(400), Line: "step 1"},
+		Entry{Timestamp: at(500), Line: `{"waverless_task":"start","task_id":"t2"}`},
+		Entry{Timestamp: at(600), Line: "ambiguous"},
+		Entry{Timestamp: at(700), Line: `{"waverless_task":"end","task_id":"t1"}`},
+		Entry{Timestamp: at(800), Line: "t2 only"},
+	)
+	require.NoError(t, shipper.ShipPending(context.Background()))
+
+	tags := map[string]string{}
+	for _, e := range sink.pushed {
+		tags[e.Line] = e.TaskID
+	}
+	assert.Equal(t, "", tags["idle"])
+	assert.Equal(t, "t1", tags["loading model"])
+	assert.Equal(t, "t1", tags["step 1"])
+	assert.Equal(t, "", tags["ambiguous"])
+	assert.Equal(t, "t1", tags[`{"waverless_task":"end","task_id":"t1"}`])
+	assert.Equal(t, "t2", tags["t2 only"])
+
+	seg, _, err = shipper.TaskLogs(context.Background(), "t1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, at(200), seg.Start)
+	require.NotNil(t, seg.End)
+	assert.Equal(t, at(700), *seg.End)
+	assert.Equal(t, "ep-abc", seg.Worker)
+
+	seg, _, err = shipper.TaskLogs(context.Background(), "unknown", 0)
+	require.NoError(t, err)
+	assert.Nil(t, seg)
+}
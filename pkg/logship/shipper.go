@@ -0,0 +1,193 @@
+package logship
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"waverless/pkg/logger"
+)
+
+// maxConcurrentTargets bounds parallel log reads per run
+const maxConcurrentTargets = 8
+
+// Shipper polls worker logs from a Source and forwards new lines to a Sink.
+// Each worker's checkpoint only advances after the sink accepted its lines, so
+// delivery is at-least-once. Task markers in the stream are indexed so the log
+// slice of a single task can be queried back.
+type Shipper struct {
+	source      Source
+	sink        Sink
+	checkpoints CheckpointStore
+	segments    SegmentIndex
+	redactor    Redactor
+}
+
+// NewShipper creates a new log shipper
+func NewShipper(source Source, sink Sink, checkpoints CheckpointStore) *Shipper {
+	if checkpoints == nil {
+		checkpoints = NewMemoryCheckpointStore()
+	}
+	return &Shipper{source: source, sink: sink, checkpoints: checkpoints, segments: NewMemorySegmentIndex()}
+}
+
+// SetSegmentIndex sets the task segment index (default: in-memory)
+func (s *Shipper) SetSegmentIndex(segments SegmentIndex) {
+	s.segments = segments
+}
+
+// SetRedactor sets the redaction applied to lines before they reach the sink
+func (s *Shipper) SetRedactor(redactor Redactor) {
+	s.redactor = redactor
+}
+
+// Sink returns the configured sink
+func (s *Shipper) Sink() Sink {
+	return s.sink
+}
+
+// ShipPending ships new lines of every current worker
+func (s *Shipper) ShipPending(ctx context.Context) error {
+	targets, err := s.source.ListTargets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list log targets: %w", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		shipped  int
+		firstErr error
+	)
+	sem := make(chan struct{}, maxConcurrentTargets)
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t Target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			n, err := s.ShipTarget(ctx, t)
+			mu.Lock()
+			defer mu.Unlock()
+			shipped += n
+			if err != nil {
+				logger.WarnCtx(ctx, "failed to ship logs of %s to %s: %v", t.Key(), s.sink.Name(), err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}(target)
+	}
+	wg.Wait()
+
+	if shipped > 0 {
+		logger.DebugCtx(ctx, "shipped %d log lines of %d workers to %s", shipped, len(targets), s.sink.Name())
+	}
+	return firstErr
+}
+
+// ShipTarget ships new lines of a single worker, e.g. right before its pod is deleted
+func (s *Shipper) ShipTarget(ctx context.Context, target Target) (int, error) {
+	since, ok, err := s.checkpoints.Get(ctx, target.Key())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	entries, err := s.source.ReadSince(ctx, target, since)
+	if err != nil {
+		return 0, err
+	}
+
+	// Sources have second precision; drop lines already shipped in the checkpoint second
+	fresh := entries[:0]
+	for _, e := range entries {
+		if !ok || e.Timestamp.After(since) {
+			fresh = append(fresh, e)
+		}
+	}
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+
+	active, err := s.segments.Active(ctx, target.Key())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read active task segments: %w", err)
+	}
+	changed, active := tagTaskSegments(target, fresh, active)
+
+	// Redact after the task markers were read; on failure nothing leaves and the batch is retried
+	if s.redactor != nil {
+		if err := s.redactor.RedactEntries(ctx, target.Endpoint, fresh); err != nil {
+			return 0, fmt.Errorf("failed to redact logs: %w", err)
+		}
+	}
+
+	if err := s.sink.Push(ctx, fresh); err != nil {
+		return 0, err
+	}
+
+	// Index only after the push so a retried batch re-derives the same segments
+	for _, seg := range changed {
+		if err := s.segments.Save(ctx, seg); err != nil {
+			logger.WarnCtx(ctx, "failed to save log segment of task %s: %v", seg.TaskID, err)
+		}
+	}
+	if err := s.segments.SetActive(ctx, target.Key(), active); err != nil {
+		logger.WarnCtx(ctx, "failed to save active task segments of %s: %v", target.Key(), err)
+	}
+	if err := s.checkpoints.Set(ctx, target.Key(), fresh[len(fresh)-1].Timestamp); err != nil {
+		return len(fresh), fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return len(fresh), nil
+}
+
+// Query returns shipped log lines. Defaults: last hour, 500 lines, newest first.
+func (s *Shipper) Query(ctx context.Context, q *Query) ([]Entry, error) {
+	if q.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+	if q.End.IsZero() {
+		q.End = time.Now()
+	}
+	if q.Start.IsZero() {
+		q.Start = q.End.Add(-time.Hour)
+	}
+	if !q.Start.Before(q.End) {
+		return nil, fmt.Errorf("start must be before end")
+	}
+	if q.Limit <= 0 || q.Limit > 5000 {
+		q.Limit = 500
+	}
+	return s.sink.Query(ctx, q)
+}
+
+// TaskLogs returns the log lines written between a task's start and end markers,
+// oldest first. Returns nil segment if no markers were seen for the task.
+func (s *Shipper) TaskLogs(ctx context.Context, taskID string, limit int) (*TaskSegment, []Entry, error) {
+	seg, err := s.segments.Get(ctx, taskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read task segment: %w", err)
+	}
+	if seg == nil {
+		return nil, nil, nil
+	}
+
+	end := time.Now()
+	if seg.End != nil {
+		end = *seg.End
+	}
+	entries, err := s.Query(ctx, &Query{
+		Endpoint: seg.Endpoint,
+		Worker:   seg.Worker,
+		Start:    seg.Start,
+		End:      end.Add(time.Millisecond), // Range end is exclusive in the backends
+		Limit:    limit,
+		Forward:  true,
+	})
+	if err != nil {
+		return seg, nil, err
+	}
+	return seg, entries, nil
+}
@@ -0,0 +1,144 @@
+package logship
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	targets []Target
+	entries map[string][]Entry
+	since   map[string]time.Time
+}
+
+func (s *fakeSource) ListTargets(ctx context.Context) ([]Target, error) { return s.targets, nil }
+
+func (s *fakeSource) ReadSince(ctx context.Context, target Target, since time.Time) ([]Entry, error) {
+	s.since[target.Key()] = since
+	// Emulate second precision of the K8s logs API
+	cutoff := since.Truncate(time.Second)
+	var out []Entry
+	for _, e := range s.entries[target.Key()] {
+		if !e.Timestamp.Before(cutoff) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+type fakeSink struct {
+	pushed []Entry
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Push(ctx context.Context, entries []Entry) error {
+	s.pushed = append(s.pushed, entries...)
+	return nil
+}
+
+func (s *fakeSink) Query(ctx context.Context, q *Query) ([]Entry, error) { return nil, nil }
+
+// TestShipper_ShipPending verifies lines are shipped once and the checkpoint advances per worker.
+func TestShipper_ShipPending(t *testing.T) {
+	base := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	target := Target{Endpoint: "ep", Worker: "ep-abc"}
+	source := &fakeSource{
+		targets: []Target{target},
+		entries: map[string][]Entry{target.Key(): {
+			{Timestamp: base.Add(100 * time.Millisecond), Line: "a"},
+			{Timestamp: base.Add(200 * time.Millisecond), Line: "b"},
+		}},
+		since: map[string]time.Time{},
+	}
+	sink := &fakeSink{}
+	shipper := NewShipper(source, sink, nil)
+
+	require.NoError(t, shipper.ShipPending(context.Background()))
+	assert.Len(t, sink.pushed, 2)
+	assert.True(t, source.since[target.Key()].IsZero())
+
+	// New line in the same second: only the new line is shipped
+	source.entries[target.Key()] = append(source.entries[target.Key()], Entry{Timestamp: base.Add(300 * time.Millisecond), Line: "c"})
+	require.NoError(t, shipper.ShipPending(context.Background()))
+	require.Len(t, sink.pushed, 3)
+	assert.Equal(t, "c", sink.pushed[2].Line)
+	assert.Equal(t, base.Add(200*time.Millisecond), source.since[target.Key()])
+
+	// Nothing new
+	require.NoError(t, shipper.ShipPending(context.Background()))
+	assert.Len(t, sink.pushed, 3)
+}
+
+// TestParseTimestampedLines tests parsing of timestamp-prefixed log output.
+func TestParseTimestampedLines(t *testing.T) {
+	raw := "2026-10-15T12:00:00.123456789Z hello world\n" +
+		"not-a-timestamp line\n" +
+		"2026-10-15T12:00:01Z second\n" +
+		"2026-10-15T12:00:02Z trunc"
+	entries := ParseTimestampedLines(raw, Target{Endpoint: "ep", Worker: "w"})
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, "hello world", entries[0].Line)
+	assert.Equal(t, 123456789, entries[0].Timestamp.Nanosecond())
+	assert.Equal(t, "ep", entries[0].Endpoint)
+	assert.Equal(t, "w", entries[0].Worker)
+	assert.Equal(t, "second", entries[1].Line)
+
+	assert.Nil(t, ParseTimestampedLines("", Target{}))
+}
+
+// TestShipper_QueryDefaults tests query defaults and validation.
+func TestShipper_QueryDefaults(t *testing.T) {
+	shipper := NewShipper(&fakeSource{}, &fakeSink{}, nil)
+
+	_, err := shipper.Query(context.Background(), &Query{})
+	assert.Error(t, err)
+
+	q := &Query{Endpoint: "ep"}
+	_, err = shipper.Query(context.Background(), q)
+	require.NoError(t, err)
+	assert.Equal(t, 500, q.Limit)
+	assert.Equal(t, time.Hour, q.End.Sub(q.Start))
+}
+
+type fakeRedactor struct{ fail bool }
+
+func (r *fakeRedactor) RedactEntries(ctx context.Context, endpoint string, entries []Entry) error {
+	if r.fail {
+		return assert.AnError
+	}
+	for i := range entries {
+		entries[i].Line = endpoint + ":[REDACTED]"
+	}
+	return nil
+}
+
+// TestShipper_Redactor verifies lines are redacted before the push and held back when redaction fails.
+func TestShipper_Redactor(t *testing.T) {
+	base := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	target := Target{Endpoint: "ep", Worker: "ep-abc"}
+	source := &fakeSource{
+		targets: []Target{target},
+		entries: map[string][]Entry{target.Key(): {{Timestamp: base, Line: "prompt from a@b.io"}}},
+		since:   map[string]time.Time{},
+	}
+	sink := &fakeSink{}
+	redactor := &fakeRedactor{fail: true}
+	shipper := NewShipper(source, sink, nil)
+	shipper.SetRedactor(redactor)
+
+	_, err := shipper.ShipTarget(context.Background(), target)
+	assert.Error(t, err)
+	assert.Empty(t, sink.pushed)
+
+	redactor.fail = false
+	n, err := shipper.ShipTarget(context.Background(), target)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "ep:[REDACTED]", sink.pushed[0].Line)
+}
@@ -0,0 +1,112 @@
+package logship
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLokiSink_PushAndQuery verifies stream labels, structured metadata and query parsing.
+func TestLokiSink_PushAndQuery(t *testing.T) {
+	var pushed map[string]interface{}
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tenant-a", r.Header.Get("X-Scope-OrgID"))
+		switch r.URL.Path {
+		case "/loki/api/v1/push":
+			body, _ := io.ReadAll(r.Body)
+			require.NoError(t, json.Unmarshal(body, &pushed))
+			w.WriteHeader(http.StatusNoContent)
+		case "/loki/api/v1/query_range":
+			query = r.URL.Query().Get("query")
+			w.Write([]byte(`{"data":{"resultType":"streams","result":[
+				{"stream":{"endpoint":"ep","worker":"w1"},"values":[["1000000000","old"]]},
+				{"stream":{"endpoint":"ep","worker":"w2","task_id":"t1"},"values":[["2000000000","new"]]}
+			]}}`))
+		}
+	}))
+	defer server.Close()
+
+	sink, err := NewLokiSink(server.URL, "tenant-a", "", "")
+	require.NoError(t, err)
+
+	err = sink.Push(context.Background(), []Entry{
+		{Timestamp: time.Unix(1, 0), Endpoint: "ep", Worker: "w1", Line: "a"},
+		{Timestamp: time.Unix(2, 0), Endpoint: "ep", Worker: "w1", TaskID: "t1", Line: "b"},
+	})
+	require.NoError(t, err)
+	streams := pushed["streams"].([]interface{})
+	require.Len(t, streams, 1)
+	stream := streams[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"source": "waverless", "endpoint": "ep", "worker": "w1"}, stream["stream"])
+	values := stream["values"].([]interface{})
+	assert.Len(t, values[0], 2)
+	assert.Equal(t, map[string]interface{}{"task_id": "t1"}, values[1].([]interface{})[2])
+
+	entries, err := sink.Query(context.Background(), &Query{Endpoint: "ep", TaskID: "t1", Contains: "err", Start: time.Unix(0, 0), End: time.Unix(10, 0), Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, `{source="waverless",endpoint="ep"} | task_id="t1" |= "err"`, query)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "new", entries[0].Line) // newest first
+	assert.Equal(t, "t1", entries[0].TaskID)
+}
+
+// TestElasticsearchSink_Push verifies index creation and bulk encoding.
+func TestElasticsearchSink_Push(t *testing.T) {
+	var bulk string
+	indexCreates := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/logs":
+			indexCreates++
+			if indexCreates > 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":{"type":"resource_already_exists_exception"}}`))
+				return
+			}
+			w.Write([]byte(`{"acknowledged":true}`))
+		case r.URL.Path == "/logs/_bulk":
+			assert.Equal(t, "ApiKey secret", r.Header.Get("Authorization"))
+			body, _ := io.ReadAll(r.Body)
+			bulk = string(body)
+			w.Write([]byte(`{"errors":false}`))
+		}
+	}))
+	defer server.Close()
+
+	sink, err := NewElasticsearchSink(server.URL, "logs", "", "", "secret")
+	require.NoError(t, err)
+
+	entries := []Entry{{Timestamp: time.Unix(1, 0), Endpoint: "ep", Worker: "w1", TaskID: "t1", Line: "hello"}}
+	require.NoError(t, sink.Push(context.Background(), entries))
+	require.NoError(t, sink.Push(context.Background(), entries))
+	assert.Equal(t, 1, indexCreates)
+
+	lines := strings.Split(strings.TrimSpace(bulk), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"index":{}}`, lines[0])
+	assert.Contains(t, lines[1], `"task_id":"t1"`)
+	assert.Contains(t, lines[1], `"message":"hello"`)
+}
+
+// TestBuildSearchQuery tests Elasticsearch query construction.
+func TestBuildSearchQuery(t *testing.T) {
+	q := buildSearchQuery(&Query{Endpoint: "ep", Worker: "w1", Contains: "oom", Start: time.Unix(0, 0), End: time.Unix(60, 0), Limit: 100, Forward: true})
+
+	data, err := json.Marshal(q)
+	require.NoError(t, err)
+	s := string(data)
+	assert.Contains(t, s, `{"term":{"endpoint":"ep"}}`)
+	assert.Contains(t, s, `{"term":{"worker":"w1"}}`)
+	assert.Contains(t, s, `"match_phrase":{"message":"oom"}`)
+	assert.Contains(t, s, `"order":"asc"`)
+	assert.NotContains(t, s, "task_id")
+}
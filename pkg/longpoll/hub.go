@@ -0,0 +1,152 @@
+// Package longpoll lets worker pulls wait on the server until a task arrives instead of
+// returning empty. Task submissions publish the endpoint name on a Redis channel; every
+// replica keeps one subscription and wakes its local waiters for that endpoint, so a task
+// submitted through any replica reaches workers long-polling on any other.
+//
+// Signals are best effort (Redis pub/sub does not buffer); waiters re-check the queue
+// periodically, so a lost signal only costs latency.
+//
+// A hub on another channel carries other per-endpoint signals, e.g. wake-up requests for
+// endpoints scaled to zero.
+package longpoll
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"waverless/pkg/logger"
+)
+
+const defaultChannel = "waverless:tasks:available"
+
+// Hub fans task-available signals out to the waiters of an endpoint
+type Hub struct {
+	client  *redis.Client // nil = signals stay on this replica
+	channel string
+
+	mu        sync.Mutex
+	waiters   map[string]map[chan struct{}]struct{} // endpoint -> waiter channels
+	listeners []func(endpoint string)
+}
+
+// NewHub creates a hub; with a nil client it only wakes waiters of this replica
+func NewHub(client *redis.Client) *Hub {
+	return NewChannelHub(client, defaultChannel)
+}
+
+// NewChannelHub creates a hub publishing and subscribing on the given Redis channel
+func NewChannelHub(client *redis.Client, channel string) *Hub {
+	return &Hub{client: client, channel: channel, waiters: make(map[string]map[chan struct{}]struct{})}
+}
+
+// OnSignal registers fn to be called with the endpoint of every signal this replica receives.
+// fn runs on the subscription goroutine and must not block.
+func (h *Hub) OnSignal(fn func(endpoint string)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners = append(h.listeners, fn)
+}
+
+// Run subscribes to the Redis channel and wakes local waiters until ctx is done
+func (h *Hub) Run(ctx context.Context) {
+	if h.client == nil {
+		return
+	}
+	for ctx.Err() == nil {
+		h.subscribe(ctx)
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second): // Redis unavailable, retry
+		}
+	}
+}
+
+func (h *Hub) subscribe(ctx context.Context) {
+	sub := h.client.Subscribe(ctx, h.channel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		if ctx.Err() == nil {
+			logger.WarnCtx(ctx, "long-poll: failed to subscribe to %s: %v", h.channel, err)
+		}
+		return
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			h.wake(msg.Payload)
+		}
+	}
+}
+
+// Notify signals that tasks are available for an endpoint
+func (h *Hub) Notify(ctx context.Context, endpoint string) {
+	if h == nil {
+		return
+	}
+	if h.client == nil {
+		h.wake(endpoint)
+		return
+	}
+	if err := h.client.Publish(ctx, h.channel, endpoint).Err(); err != nil {
+		// Waiters still find the task on their next re-check
+		logger.WarnCtx(ctx, "long-poll: failed to publish signal on %s for endpoint %s: %v", h.channel, endpoint, err)
+		h.wake(endpoint)
+	}
+}
+
+// Subscribe registers a waiter for an endpoint. The returned channel receives at most one
+// pending signal; call cancel when done waiting. Subscribe before checking the queue so a
+// task submitted in between is not missed.
+func (h *Hub) Subscribe(endpoint string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	h.mu.Lock()
+	if h.waiters[endpoint] == nil {
+		h.waiters[endpoint] = make(map[chan struct{}]struct{})
+	}
+	h.waiters[endpoint][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.waiters[endpoint], ch)
+		if len(h.waiters[endpoint]) == 0 {
+			delete(h.waiters, endpoint)
+		}
+	}
+}
+
+// Waiting returns the number of waiters of an endpoint on this replica
+func (h *Hub) Waiting(endpoint string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.waiters[endpoint])
+}
+
+// wake signals every waiter of an endpoint; they race for the task through the usual
+// transactional assignment, so waking too many only costs an empty pull
+func (h *Hub) wake(endpoint string) {
+	h.mu.Lock()
+	for ch := range h.waiters[endpoint] {
+		select {
+		case ch <- struct{}{}:
+		default: // Already signalled
+		}
+	}
+	listeners := h.listeners
+	h.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(endpoint)
+	}
+}
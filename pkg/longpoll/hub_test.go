@@ -0,0 +1,74 @@
+package longpoll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_NotifyWakesEndpointWaiters(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(nil)
+
+	a, cancelA := hub.Subscribe("flux")
+	defer cancelA()
+	b, cancelB := hub.Subscribe("flux")
+	defer cancelB()
+	other, cancelOther := hub.Subscribe("sd")
+	defer cancelOther()
+	assert.Equal(t, 2, hub.Waiting("flux"))
+
+	hub.Notify(ctx, "flux")
+	hub.Notify(ctx, "flux") // Coalesced into the pending signal
+
+	for _, ch := range []<-chan struct{}{a, b} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("waiter was not woken")
+		}
+		select {
+		case <-ch:
+			t.Fatal("signals were not coalesced")
+		default:
+		}
+	}
+	select {
+	case <-other:
+		t.Fatal("waiter of another endpoint was woken")
+	default:
+	}
+}
+
+func TestHub_SignalBeforeWaitIsKept(t *testing.T) {
+	hub := NewHub(nil)
+	ch, cancel := hub.Subscribe("flux")
+
+	// A task queued between Subscribe and the wait is still seen
+	hub.Notify(context.Background(), "flux")
+	select {
+	case <-ch:
+	default:
+		t.Fatal("signal was lost")
+	}
+
+	cancel()
+	assert.Equal(t, 0, hub.Waiting("flux"))
+}
+
+func TestHub_NilNotify(t *testing.T) {
+	var hub *Hub
+	assert.NotPanics(t, func() { hub.Notify(context.Background(), "flux") })
+}
+
+func TestHub_OnSignal(t *testing.T) {
+	hub := NewChannelHub(nil, "waverless:endpoints:wake")
+	var got []string
+	hub.OnSignal(func(endpoint string) { got = append(got, endpoint) })
+
+	hub.Notify(context.Background(), "flux")
+	hub.Notify(context.Background(), "sd")
+	assert.Equal(t, []string{"flux", "sd"}, got)
+}
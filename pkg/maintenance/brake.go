@@ -0,0 +1,255 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"waverless/pkg/config"
+	"waverless/pkg/logger"
+)
+
+const brakeKey = "maintenance:emergency-brake"
+
+var (
+	// ErrBrakeEngaged is returned for rollouts while the emergency brake is engaged
+	ErrBrakeEngaged = errors.New("emergency brake is engaged")
+	// ErrBrakeNotEngaged is returned when releasing a brake that is not engaged
+	ErrBrakeNotEngaged = errors.New("emergency brake is not engaged")
+)
+
+// BrakeState is the current emergency brake status. The release fields describe the last
+// release and are kept until the brake is engaged again.
+type BrakeState struct {
+	Engaged    bool       `json:"engaged"`
+	Reason     string     `json:"reason,omitempty"`
+	EngagedBy  string     `json:"engagedBy,omitempty"`
+	Since      *time.Time `json:"since,omitempty"`
+	DrainToMin bool       `json:"drainToMin,omitempty"` // Endpoints were scaled down to minReplicas when engaged
+	ReleasedBy string     `json:"releasedBy,omitempty"`
+	ReleasedAt *time.Time `json:"releasedAt,omitempty"`
+}
+
+// BrakeStore persists the emergency brake state
+type BrakeStore interface {
+	Load(ctx context.Context) (*BrakeState, error) // nil, nil if never set
+	Save(ctx context.Context, state *BrakeState) error
+}
+
+// RedisBrakeStore shares the brake between replicas
+type RedisBrakeStore struct {
+	client *redis.Client
+}
+
+// NewRedisBrakeStore creates a Redis-backed brake store
+func NewRedisBrakeStore(client *redis.Client) *RedisBrakeStore {
+	return &RedisBrakeStore{client: client}
+}
+
+// Load reads the brake from Redis
+func (s *RedisBrakeStore) Load(ctx context.Context) (*BrakeState, error) {
+	data, err := s.client.Get(ctx, brakeKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load emergency brake: %w", err)
+	}
+	var state BrakeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode emergency brake: %w", err)
+	}
+	return &state, nil
+}
+
+// Save writes the brake to Redis (no expiry: the brake must be released explicitly)
+func (s *RedisBrakeStore) Save(ctx context.Context, state *BrakeState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, brakeKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save emergency brake: %w", err)
+	}
+	return nil
+}
+
+// MemoryBrakeStore keeps the brake in-process (single replica, or tests)
+type MemoryBrakeStore struct {
+	mu    sync.Mutex
+	state *BrakeState
+}
+
+// NewMemoryBrakeStore creates an in-process brake store
+func NewMemoryBrakeStore() *MemoryBrakeStore {
+	return &MemoryBrakeStore{}
+}
+
+// Load returns a copy of the stored brake
+func (s *MemoryBrakeStore) Load(ctx context.Context) (*BrakeState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return nil, nil
+	}
+	state := *s.state
+	return &state, nil
+}
+
+// Save stores a copy of the brake
+func (s *MemoryBrakeStore) Save(ctx context.Context, state *BrakeState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *state
+	s.state = &copied
+	return nil
+}
+
+// Brake is the cluster-wide emergency brake: while engaged, autoscaling and rollouts are
+// frozen on every replica until an operator releases it
+type Brake struct {
+	store    BrakeStore
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	current BrakeState
+}
+
+// NewBrake creates the brake; call Refresh/Run to pick up the shared state
+func NewBrake(cfg config.MaintenanceConfig, store BrakeStore) *Brake {
+	b := &Brake{store: store, interval: cfg.RefreshInterval, now: time.Now}
+	if b.interval <= 0 {
+		b.interval = 2 * time.Second
+	}
+	return b
+}
+
+// Current returns the brake state from memory
+func (b *Brake) Current() BrakeState {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.current
+}
+
+// Engaged reports whether the brake is engaged, and why
+func (b *Brake) Engaged() (bool, string) {
+	state := b.Current()
+	return state.Engaged, state.Reason
+}
+
+// CheckRollout returns ErrBrakeEngaged while the brake is engaged. A nil brake allows rollouts.
+func (b *Brake) CheckRollout() error {
+	if b == nil {
+		return nil
+	}
+	state := b.Current()
+	if !state.Engaged {
+		return nil
+	}
+	return fmt.Errorf("%w by %s: %s; rollouts are frozen until it is released", ErrBrakeEngaged, state.EngagedBy, state.Reason)
+}
+
+// Engage freezes autoscaling and rollouts on all replicas. Engaging again updates the reason
+// and keeps the original start time.
+func (b *Brake) Engage(ctx context.Context, reason, engagedBy string, drainToMin bool) (BrakeState, error) {
+	if reason == "" {
+		return b.Current(), fmt.Errorf("invalid request: reason is required")
+	}
+	if engagedBy == "" {
+		return b.Current(), fmt.Errorf("invalid request: requester identity is required")
+	}
+	since := b.now()
+	prev := b.Current()
+	if prev.Engaged && prev.Since != nil {
+		since = *prev.Since
+		drainToMin = drainToMin || prev.DrainToMin
+	}
+	state := &BrakeState{Engaged: true, Reason: reason, EngagedBy: engagedBy, Since: &since, DrainToMin: drainToMin}
+	if err := b.store.Save(ctx, state); err != nil {
+		return b.Current(), err
+	}
+	b.apply(state)
+	return b.Current(), nil
+}
+
+// Release lifts the brake on all replicas
+func (b *Brake) Release(ctx context.Context, releasedBy string) (BrakeState, error) {
+	if releasedBy == "" {
+		return b.Current(), fmt.Errorf("invalid request: requester identity is required")
+	}
+	// Release against the shared state, which another replica may have changed
+	if err := b.Refresh(ctx); err != nil {
+		return b.Current(), err
+	}
+	prev := b.Current()
+	if !prev.Engaged {
+		return prev, ErrBrakeNotEngaged
+	}
+	releasedAt := b.now()
+	state := &BrakeState{
+		Reason:     prev.Reason,
+		EngagedBy:  prev.EngagedBy,
+		Since:      prev.Since,
+		ReleasedBy: releasedBy,
+		ReleasedAt: &releasedAt,
+	}
+	if err := b.store.Save(ctx, state); err != nil {
+		return b.Current(), err
+	}
+	b.apply(state)
+	return b.Current(), nil
+}
+
+// Refresh reloads the shared state from the store
+func (b *Brake) Refresh(ctx context.Context) error {
+	state, err := b.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	b.apply(state)
+	return nil
+}
+
+// Run refreshes the shared state until ctx is done. On store errors the last known state
+// is kept, so an outage neither engages nor releases the brake.
+func (b *Brake) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.Refresh(ctx); err != nil {
+				logger.WarnCtx(ctx, "Failed to refresh emergency brake: %v", err)
+			}
+		}
+	}
+}
+
+// apply sets the state in memory
+func (b *Brake) apply(state *BrakeState) {
+	current := BrakeState{}
+	if state != nil {
+		current = *state
+	}
+
+	b.mu.Lock()
+	prev := b.current
+	b.current = current
+	b.mu.Unlock()
+
+	if prev.Engaged != current.Engaged {
+		if current.Engaged {
+			logger.WarnCtx(context.Background(), "Emergency brake engaged by %s: %s", current.EngagedBy, current.Reason)
+		} else {
+			logger.InfoCtx(context.Background(), "Emergency brake released")
+		}
+	}
+}
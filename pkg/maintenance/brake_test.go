@@ -0,0 +1,72 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/config"
+)
+
+func TestBrake_EngageAndRelease(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryBrakeStore()
+	a := NewBrake(config.MaintenanceConfig{}, store)
+	b := NewBrake(config.MaintenanceConfig{}, store)
+
+	assert.NoError(t, a.CheckRollout())
+
+	state, err := a.Engage(ctx, "bad release in progress", "ops", true)
+	require.NoError(t, err)
+	assert.True(t, state.Engaged)
+	assert.True(t, state.DrainToMin)
+	require.NotNil(t, state.Since)
+	assert.ErrorIs(t, a.CheckRollout(), ErrBrakeEngaged)
+
+	// Other replicas pick it up on refresh
+	require.NoError(t, b.Refresh(ctx))
+	engaged, reason := b.Engaged()
+	assert.True(t, engaged)
+	assert.Equal(t, "bad release in progress", reason)
+
+	// Engaging again keeps the original start time
+	updated, err := a.Engage(ctx, "bad release, rolling back", "ops", false)
+	require.NoError(t, err)
+	assert.Equal(t, *state.Since, *updated.Since)
+	assert.True(t, updated.DrainToMin)
+
+	// Released from another replica
+	released, err := b.Release(ctx, "oncall")
+	require.NoError(t, err)
+	assert.False(t, released.Engaged)
+	assert.Equal(t, "oncall", released.ReleasedBy)
+	assert.Equal(t, "bad release, rolling back", released.Reason)
+
+	require.NoError(t, a.Refresh(ctx))
+	assert.NoError(t, a.CheckRollout())
+
+	_, err = a.Release(ctx, "oncall")
+	assert.ErrorIs(t, err, ErrBrakeNotEngaged)
+}
+
+func TestBrake_EngageRequiresReasonAndIdentity(t *testing.T) {
+	ctx := context.Background()
+	brake := NewBrake(config.MaintenanceConfig{}, NewMemoryBrakeStore())
+
+	_, err := brake.Engage(ctx, "", "ops", false)
+	assert.Error(t, err)
+	_, err = brake.Engage(ctx, "incident", "", false)
+	assert.Error(t, err)
+	_, err = brake.Release(ctx, "")
+	assert.Error(t, err)
+
+	engaged, _ := brake.Engaged()
+	assert.False(t, engaged)
+}
+
+func TestBrake_NilAllowsRollouts(t *testing.T) {
+	var brake *Brake
+	assert.NoError(t, brake.CheckRollout())
+}
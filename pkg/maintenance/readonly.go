@@ -0,0 +1,226 @@
+// Package maintenance implements the global read-only switch used during maintenance
+// windows (e.g. database migrations). The switch is stored in Redis so toggling it on
+// one replica reaches every replica within the refresh interval; request handling only
+// reads an in-memory copy. The emergency brake (brake.go) is shared the same way.
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"waverless/pkg/config"
+	"waverless/pkg/logger"
+)
+
+// State sources
+const (
+	SourceConfig = "config"
+	SourceAPI    = "api"
+)
+
+const stateKey = "maintenance:read-only"
+
+// ErrForcedByConfig is returned when the API tries to leave a read-only mode set in config
+var ErrForcedByConfig = errors.New("read-only mode is set in config (maintenance.readOnly) and cannot be disabled via the API")
+
+// State is the current read-only status
+type State struct {
+	ReadOnly  bool       `json:"readOnly"`
+	Reason    string     `json:"reason,omitempty"`
+	Source    string     `json:"source,omitempty"` // config, api
+	UpdatedBy string     `json:"updatedBy,omitempty"`
+	Since     *time.Time `json:"since,omitempty"`
+}
+
+// Store persists the API-controlled state
+type Store interface {
+	Load(ctx context.Context) (*State, error) // nil, nil if never set
+	Save(ctx context.Context, state *State) error
+}
+
+// RedisStore shares the state between replicas
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a Redis-backed state store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Load reads the state from Redis
+func (s *RedisStore) Load(ctx context.Context) (*State, error) {
+	data, err := s.client.Get(ctx, stateKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load read-only state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode read-only state: %w", err)
+	}
+	return &state, nil
+}
+
+// Save writes the state to Redis (no expiry: maintenance must be ended explicitly)
+func (s *RedisStore) Save(ctx context.Context, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, stateKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save read-only state: %w", err)
+	}
+	return nil
+}
+
+// MemoryStore keeps the state in-process (single replica, or tests)
+type MemoryStore struct {
+	mu    sync.Mutex
+	state *State
+}
+
+// NewMemoryStore creates an in-process state store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Load returns a copy of the stored state
+func (s *MemoryStore) Load(ctx context.Context) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return nil, nil
+	}
+	state := *s.state
+	return &state, nil
+}
+
+// Save stores a copy of the state
+func (s *MemoryStore) Save(ctx context.Context, state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *state
+	s.state = &copied
+	return nil
+}
+
+// Switch combines the config-forced state with the shared API state
+type Switch struct {
+	store    Store
+	forced   *State // Non-nil when config sets read-only
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	current State
+}
+
+// NewSwitch creates the switch; call Refresh/Run to pick up the shared state
+func NewSwitch(cfg config.MaintenanceConfig, store Store) *Switch {
+	s := &Switch{store: store, interval: cfg.RefreshInterval, now: time.Now}
+	if s.interval <= 0 {
+		s.interval = 2 * time.Second
+	}
+	if cfg.ReadOnly {
+		since := s.now()
+		s.forced = &State{ReadOnly: true, Reason: cfg.Reason, Source: SourceConfig, Since: &since}
+		s.current = *s.forced
+	}
+	return s
+}
+
+// Current returns the effective state from memory
+func (s *Switch) Current() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// ReadOnly reports whether mutating operations must be rejected, and why
+func (s *Switch) ReadOnly() (bool, string) {
+	state := s.Current()
+	return state.ReadOnly, state.Reason
+}
+
+// Set changes the shared state and applies it locally right away
+func (s *Switch) Set(ctx context.Context, readOnly bool, reason, updatedBy string) (State, error) {
+	if s.forced != nil && !readOnly {
+		return s.Current(), ErrForcedByConfig
+	}
+	state := &State{ReadOnly: readOnly, Reason: reason, Source: SourceAPI, UpdatedBy: updatedBy}
+	if readOnly {
+		since := s.now()
+		if prev := s.Current(); prev.ReadOnly && prev.Since != nil {
+			since = *prev.Since
+		}
+		state.Since = &since
+	}
+	if err := s.store.Save(ctx, state); err != nil {
+		return s.Current(), err
+	}
+	s.apply(state)
+	return s.Current(), nil
+}
+
+// Refresh reloads the shared state from the store
+func (s *Switch) Refresh(ctx context.Context) error {
+	state, err := s.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	s.apply(state)
+	return nil
+}
+
+// Run refreshes the shared state until ctx is done. On store errors the last known
+// state is kept, so an outage neither enables nor lifts read-only mode.
+func (s *Switch) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				logger.WarnCtx(ctx, "Failed to refresh read-only state: %v", err)
+			}
+		}
+	}
+}
+
+// apply sets the effective state; config-forced read-only wins over the API state
+func (s *Switch) apply(state *State) {
+	effective := State{}
+	switch {
+	case s.forced != nil:
+		effective = *s.forced
+		if state != nil && state.ReadOnly && state.Reason != "" {
+			effective.Reason = state.Reason
+		}
+	case state != nil:
+		effective = *state
+	}
+
+	s.mu.Lock()
+	prev := s.current
+	s.current = effective
+	s.mu.Unlock()
+
+	if prev.ReadOnly != effective.ReadOnly {
+		if effective.ReadOnly {
+			logger.WarnCtx(context.Background(), "Read-only mode enabled (source=%s): %s", effective.Source, effective.Reason)
+		} else {
+			logger.InfoCtx(context.Background(), "Read-only mode disabled")
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/config"
+)
+
+func TestSwitch_SetAndRefresh(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	a := NewSwitch(config.MaintenanceConfig{}, store)
+	b := NewSwitch(config.MaintenanceConfig{}, store)
+
+	readOnly, _ := a.ReadOnly()
+	assert.False(t, readOnly)
+
+	state, err := a.Set(ctx, true, "db migration", "ops")
+	require.NoError(t, err)
+	assert.True(t, state.ReadOnly)
+	assert.Equal(t, SourceAPI, state.Source)
+	require.NotNil(t, state.Since)
+
+	// Other replicas pick it up on refresh
+	require.NoError(t, b.Refresh(ctx))
+	readOnly, reason := b.ReadOnly()
+	assert.True(t, readOnly)
+	assert.Equal(t, "db migration", reason)
+
+	// Updating the reason keeps the original start time
+	updated, err := a.Set(ctx, true, "db migration, step 2", "ops")
+	require.NoError(t, err)
+	assert.Equal(t, *state.Since, *updated.Since)
+
+	_, err = a.Set(ctx, false, "", "ops")
+	require.NoError(t, err)
+	require.NoError(t, b.Refresh(ctx))
+	readOnly, _ = b.ReadOnly()
+	assert.False(t, readOnly)
+}
+
+func TestSwitch_ForcedByConfig(t *testing.T) {
+	ctx := context.Background()
+	sw := NewSwitch(config.MaintenanceConfig{ReadOnly: true, Reason: "planned"}, NewMemoryStore())
+
+	readOnly, reason := sw.ReadOnly()
+	assert.True(t, readOnly)
+	assert.Equal(t, "planned", reason)
+
+	_, err := sw.Set(ctx, false, "", "ops")
+	assert.ErrorIs(t, err, ErrForcedByConfig)
+
+	// The API may still change the reason
+	state, err := sw.Set(ctx, true, "extended", "ops")
+	require.NoError(t, err)
+	assert.Equal(t, SourceConfig, state.Source)
+	assert.Equal(t, "extended", state.Reason)
+}
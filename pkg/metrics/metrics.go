@@ -0,0 +1,245 @@
+// Package metrics keeps the control plane's own metrics (informer lag, callback latency, ...)
+// and renders them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LatencyBuckets are histogram buckets in seconds, from 5ms to 5 minutes
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Default is the registry served on /metrics
+var Default = NewRegistry()
+
+// collector is a metric family of a registry
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds metric families in registration order
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText writes every metric family in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the default registry
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = Default.WriteText(w)
+	})
+}
+
+// family is the name, help and label names shared by the series of a metric
+type family struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (f *family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s: got %d label values, want %d", f.name, len(values), len(f.labels)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func (f *family) writeHeader(w *bufio.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, kind)
+}
+
+// labelString renders {a="1",b="2"}, with extra appended after the family labels
+func (f *family) labelString(values []string, extra ...string) string {
+	if len(f.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(f.labels)+len(extra)/2)
+	for i, name := range f.labels {
+		pairs = append(pairs, name+`="`+escapeLabelValue(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabelValue(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// CounterVec is a counter per label values
+type CounterVec struct {
+	family
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+// NewCounterVec registers a counter family
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{family: family{name: name, help: help, labels: labels}, series: make(map[string]*counterSeries)}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the counter of the label values
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v (>= 0) to the counter of the label values
+func (c *CounterVec) Add(v float64, values ...string) {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+// Value returns the counter of the label values
+func (c *CounterVec) Value(values ...string) float64 {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.series[key]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeHeader(w, "counter")
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelString(s.values), formatFloat(s.value))
+	}
+}
+
+// HistogramVec is a histogram per label values
+type HistogramVec struct {
+	family
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // Per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec registers a histogram family with the given upper bounds (sorted ascending)
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{family: family{name: name, help: help, labels: labels}, buckets: buckets, series: make(map[string]*histogramSeries)}
+	r.register(h)
+	return h
+}
+
+// Observe records a value for the label values
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// Count returns the number of observations of the label values
+func (h *HistogramVec) Count(values ...string) uint64 {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[key]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writeHeader(w, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(s.values, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelString(s.values), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelString(s.values), s.count)
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
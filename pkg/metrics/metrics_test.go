@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterVec(t *testing.T) {
+	r := NewRegistry()
+	errors := r.NewCounterVec("waverless_test_errors_total", "Test errors.", "callback")
+	errors.Inc("pod_status")
+	errors.Add(2, "pod_status")
+	errors.Inc(`say "hi"`)
+
+	assert.Equal(t, 3.0, errors.Value("pod_status"))
+	assert.Equal(t, 0.0, errors.Value("pod_delete"))
+	assert.Panics(t, func() { errors.Inc() })
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+	assert.Equal(t, `# HELP waverless_test_errors_total Test errors.
+# TYPE waverless_test_errors_total counter
+waverless_test_errors_total{callback="pod_status"} 3
+waverless_test_errors_total{callback="say \"hi\""} 1
+`, buf.String())
+}
+
+func TestHistogramVec(t *testing.T) {
+	r := NewRegistry()
+	latency := r.NewHistogramVec("waverless_test_latency_seconds", "Test latency.", []float64{0.1, 1})
+	latency.Observe(0.05)
+	latency.Observe(0.1)
+	latency.Observe(0.5)
+	latency.Observe(3)
+
+	assert.Equal(t, uint64(4), latency.Count())
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+	assert.Equal(t, `# HELP waverless_test_latency_seconds Test latency.
+# TYPE waverless_test_latency_seconds histogram
+waverless_test_latency_seconds_bucket{le="0.1"} 2
+waverless_test_latency_seconds_bucket{le="1"} 3
+waverless_test_latency_seconds_bucket{le="+Inf"} 4
+waverless_test_latency_seconds_sum 3.65
+waverless_test_latency_seconds_count 4
+`, buf.String())
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "version=0.0.4")
+}
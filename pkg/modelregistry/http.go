@@ -0,0 +1,122 @@
+package modelregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPRegistry reads model versions from a custom registry serving
+//
+//	GET <address>/models/<model>/versions/<version>
+//	GET <address>/models/<model>/latest-approved
+//
+// both answering a ModelVersion JSON document, or 404 when there is no such version, and
+//
+//	GET <address>/models/<model>/versions?limit=<n>&offset=<n>
+//
+// answering {"versions": [ModelVersion...]}, newest first.
+type HTTPRegistry struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewHTTPRegistry creates a client for the custom registry at address
+func NewHTTPRegistry(address, token string) *HTTPRegistry {
+	return &HTTPRegistry{
+		baseURL: strings.TrimSuffix(address, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (r *HTTPRegistry) Name() string { return "http" }
+
+// GetVersion returns a version of a model
+func (r *HTTPRegistry) GetVersion(ctx context.Context, model, version string) (*ModelVersion, error) {
+	mv, err := r.get(ctx, "/models/"+url.PathEscape(model)+"/versions/"+url.PathEscape(version))
+	if err != nil {
+		return nil, err
+	}
+	if mv.Version == "" {
+		mv.Version = version
+	}
+	return mv, nil
+}
+
+// GetLatestApproved returns the newest approved version of a model
+func (r *HTTPRegistry) GetLatestApproved(ctx context.Context, model string) (*ModelVersion, error) {
+	mv, err := r.get(ctx, "/models/"+url.PathEscape(model)+"/latest-approved")
+	if err != nil {
+		return nil, err
+	}
+	if mv.Version == "" {
+		return nil, fmt.Errorf("model registry returned the latest approved version of %s without a version", model)
+	}
+	return mv, nil
+}
+
+// ListVersions returns versions of a model, newest first; none if the model does not exist
+func (r *HTTPRegistry) ListVersions(ctx context.Context, model string, limit, offset int) ([]*ModelVersion, error) {
+	query := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(offset)}}
+	var resp struct {
+		Versions []*ModelVersion `json:"versions"`
+	}
+	err := r.do(ctx, "/models/"+url.PathEscape(model)+"/versions?"+query.Encode(), &resp)
+	if errors.Is(err, ErrNotFound) {
+		return []*ModelVersion{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Versions) > limit {
+		resp.Versions = resp.Versions[:limit]
+	}
+	return resp.Versions, nil
+}
+
+func (r *HTTPRegistry) get(ctx context.Context, path string) (*ModelVersion, error) {
+	var mv ModelVersion
+	if err := r.do(ctx, path, &mv); err != nil {
+		return nil, err
+	}
+	return &mv, nil
+}
+
+func (r *HTTPRegistry) do(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create model registry request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("model registry request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("model registry %s: status %d: %s", strings.SplitN(path, "?", 2)[0], resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode model registry response: %w", err)
+	}
+	return nil
+}
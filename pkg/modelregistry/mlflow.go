@@ -0,0 +1,212 @@
+package modelregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ImageTag is the MLflow model version tag holding the worker image of the version
+	ImageTag = "waverless.image"
+	// EnvTagPrefix prefixes model version tags that become env vars, e.g. waverless.env.MODEL_PATH
+	EnvTagPrefix = "waverless.env."
+
+	// defaultApprovedStage is the stage versions approved for deployment are in
+	defaultApprovedStage = "Production"
+)
+
+// MLflowRegistry reads model versions from the MLflow model registry. The image and env of a
+// version are read from its tags (ImageTag, EnvTagPrefix). The latest approved version is the
+// version holding the approved alias if one is configured, else the newest version in the
+// approved stage.
+type MLflowRegistry struct {
+	baseURL       string
+	token         string
+	approvedStage string
+	approvedAlias string
+	client        *http.Client
+}
+
+// NewMLflowRegistry creates a registry client for the MLflow tracking server at trackingURI
+func NewMLflowRegistry(trackingURI, token, approvedStage, approvedAlias string) *MLflowRegistry {
+	if approvedStage == "" {
+		approvedStage = defaultApprovedStage
+	}
+	return &MLflowRegistry{
+		baseURL:       strings.TrimSuffix(trackingURI, "/"),
+		token:         token,
+		approvedStage: approvedStage,
+		approvedAlias: approvedAlias,
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (r *MLflowRegistry) Name() string { return "mlflow" }
+
+// mlflowModelVersion is a model version as returned by the MLflow REST API
+type mlflowModelVersion struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	CurrentStage string `json:"current_stage"`
+	Tags         []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"tags"`
+}
+
+// GetVersion returns a version of a model
+func (r *MLflowRegistry) GetVersion(ctx context.Context, model, version string) (*ModelVersion, error) {
+	query := url.Values{"name": {model}, "version": {version}}
+	var resp struct {
+		ModelVersion *mlflowModelVersion `json:"model_version"`
+	}
+	if err := r.do(ctx, http.MethodGet, "/api/2.0/mlflow/model-versions/get?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.ModelVersion == nil {
+		return nil, ErrNotFound
+	}
+	return r.toModelVersion(resp.ModelVersion), nil
+}
+
+// GetLatestApproved returns the version holding the approved alias, or the newest version in
+// the approved stage
+func (r *MLflowRegistry) GetLatestApproved(ctx context.Context, model string) (*ModelVersion, error) {
+	if r.approvedAlias != "" {
+		query := url.Values{"name": {model}, "alias": {r.approvedAlias}}
+		var resp struct {
+			ModelVersion *mlflowModelVersion `json:"model_version"`
+		}
+		if err := r.do(ctx, http.MethodGet, "/api/2.0/mlflow/registered-models/alias?"+query.Encode(), nil, &resp); err != nil {
+			return nil, err
+		}
+		if resp.ModelVersion == nil {
+			return nil, ErrNotFound
+		}
+		return r.toModelVersion(resp.ModelVersion), nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"name": model, "stages": []string{r.approvedStage}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mlflow request: %w", err)
+	}
+	var resp struct {
+		ModelVersions []*mlflowModelVersion `json:"model_versions"`
+	}
+	if err := r.do(ctx, http.MethodPost, "/api/2.0/mlflow/registered-models/get-latest-versions", body, &resp); err != nil {
+		return nil, err
+	}
+
+	var latest *mlflowModelVersion
+	for _, mv := range resp.ModelVersions {
+		if latest == nil || versionNumber(mv.Version) > versionNumber(latest.Version) {
+			latest = mv
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+	return r.toModelVersion(latest), nil
+}
+
+// ListVersions returns versions of a model, newest first. MLflow pages its searches by token,
+// so the first offset+limit versions are searched and the page is cut from them.
+func (r *MLflowRegistry) ListVersions(ctx context.Context, model string, limit, offset int) ([]*ModelVersion, error) {
+	query := url.Values{
+		"filter":      {fmt.Sprintf("name='%s'", strings.ReplaceAll(model, "'", "\\'"))},
+		"max_results": {strconv.Itoa(offset + limit)},
+		"order_by":    {"version_number DESC"},
+	}
+	var resp struct {
+		ModelVersions []*mlflowModelVersion `json:"model_versions"`
+	}
+	if err := r.do(ctx, http.MethodGet, "/api/2.0/mlflow/model-versions/search?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	versions := make([]*ModelVersion, 0, limit)
+	for i, mv := range resp.ModelVersions {
+		if i >= offset && len(versions) < limit {
+			versions = append(versions, r.toModelVersion(mv))
+		}
+	}
+	return versions, nil
+}
+
+// toModelVersion reads the deployment settings of an MLflow model version from its tags
+func (r *MLflowRegistry) toModelVersion(mv *mlflowModelVersion) *ModelVersion {
+	result := &ModelVersion{
+		Model:   mv.Name,
+		Version: mv.Version,
+		Stage:   mv.CurrentStage,
+		URL:     r.baseURL + "/#/models/" + url.PathEscape(mv.Name) + "/versions/" + url.PathEscape(mv.Version),
+	}
+	for _, tag := range mv.Tags {
+		switch {
+		case tag.Key == ImageTag:
+			result.Image = strings.TrimSpace(tag.Value)
+		case strings.HasPrefix(tag.Key, EnvTagPrefix) && len(tag.Key) > len(EnvTagPrefix):
+			if result.Env == nil {
+				result.Env = make(map[string]string)
+			}
+			result.Env[strings.TrimPrefix(tag.Key, EnvTagPrefix)] = tag.Value
+		}
+	}
+	return result
+}
+
+// versionNumber orders MLflow versions, which are sequence numbers
+func versionNumber(version string) int {
+	n, _ := strconv.Atoi(version)
+	return n
+}
+
+func (r *MLflowRegistry) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create mlflow request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mlflow request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			ErrorCode string `json:"error_code"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		if resp.StatusCode == http.StatusNotFound || apiErr.ErrorCode == "RESOURCE_DOES_NOT_EXIST" {
+			return ErrNotFound
+		}
+		return fmt.Errorf("mlflow %s: status %d: %s", strings.SplitN(path, "?", 2)[0], resp.StatusCode, strings.TrimSpace(string(truncate(data, 1024))))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode mlflow response: %w", err)
+	}
+	return nil
+}
+
+func truncate(data []byte, max int) []byte {
+	if len(data) > max {
+		return data[:max]
+	}
+	return data
+}
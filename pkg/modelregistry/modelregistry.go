@@ -0,0 +1,94 @@
+// Package modelregistry resolves model versions registered in a model registry (MLflow, or a
+// custom HTTP registry) into the image and env an endpoint is deployed with, so endpoints can
+// be deployed by model name and version instead of by image.
+package modelregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LatestApproved requests the newest version of a model approved for deployment
+const LatestApproved = "latest"
+
+// ErrNotFound is returned when a model or model version does not exist in the registry
+var ErrNotFound = errors.New("model version not found")
+
+// ErrNoImage is returned when a model version does not name the image it is served with
+var ErrNoImage = errors.New("model version has no image registered")
+
+// ModelVersion is a model version as registered, with the deployment settings it carries
+type ModelVersion struct {
+	Registry string            `json:"registry"`        // Registry kind it was resolved from, e.g. mlflow
+	Model    string            `json:"model"`           // Registered model name
+	Version  string            `json:"version"`         // Version in the registry
+	Stage    string            `json:"stage,omitempty"` // Approval stage or status in the registry
+	Image    string            `json:"image"`           // Worker image the version is served with
+	Env      map[string]string `json:"env,omitempty"`   // Env vars the version is served with
+	URL      string            `json:"url,omitempty"`   // The version in the registry UI, for lineage
+}
+
+// Registry reads model versions from a model registry
+type Registry interface {
+	// Name returns the registry kind, e.g. mlflow
+	Name() string
+	// GetVersion returns a version of a model; ErrNotFound if it does not exist
+	GetVersion(ctx context.Context, model, version string) (*ModelVersion, error)
+	// GetLatestApproved returns the newest version of a model approved for deployment;
+	// ErrNotFound if there is none
+	GetLatestApproved(ctx context.Context, model string) (*ModelVersion, error)
+	// ListVersions returns up to limit versions of a model, newest first, skipping the first
+	// offset; none if the model does not exist
+	ListVersions(ctx context.Context, model string, limit, offset int) ([]*ModelVersion, error)
+}
+
+// Resolve returns version of model, or its latest approved version when version is empty or
+// "latest". The version must name the image it is served with.
+func Resolve(ctx context.Context, r Registry, model, version string) (*ModelVersion, error) {
+	model = strings.TrimSpace(model)
+	version = strings.TrimSpace(version)
+	if model == "" {
+		return nil, fmt.Errorf("model name is required")
+	}
+
+	var mv *ModelVersion
+	var err error
+	if version == "" || version == LatestApproved {
+		mv, err = r.GetLatestApproved(ctx, model)
+	} else {
+		mv, err = r.GetVersion(ctx, model, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mv.Registry = r.Name()
+	if mv.Model == "" {
+		mv.Model = model
+	}
+	if mv.Image == "" {
+		return nil, fmt.Errorf("%w: model %s version %s", ErrNoImage, mv.Model, mv.Version)
+	}
+	return mv, nil
+}
+
+// ListVersions returns up to limit versions of model, newest first, skipping the first offset
+func ListVersions(ctx context.Context, r Registry, model string, limit, offset int) ([]*ModelVersion, error) {
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return nil, fmt.Errorf("model name is required")
+	}
+	versions, err := r.ListVersions(ctx, model, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, mv := range versions {
+		mv.Registry = r.Name()
+		if mv.Model == "" {
+			mv.Model = model
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,157 @@
+package modelregistry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mlflowVersion(version, stage, image string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":          "flux",
+		"version":       version,
+		"current_stage": stage,
+		"tags": []map[string]string{
+			{"key": ImageTag, "value": image},
+			{"key": EnvTagPrefix + "MODEL_PATH", "value": "s3://models/flux/" + version},
+			{"key": "owner", "value": "research"},
+		},
+	}
+}
+
+func TestMLflowRegistry(t *testing.T) {
+	var token string
+	var stages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/model-versions/get":
+			if r.URL.Query().Get("version") != "3" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error_code":"RESOURCE_DOES_NOT_EXIST"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"model_version": mlflowVersion("3", "Staging", "repo/flux:v3")})
+		case "/api/2.0/mlflow/registered-models/get-latest-versions":
+			var body struct {
+				Stages []string `json:"stages"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			stages = body.Stages
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"model_versions": []interface{}{
+				mlflowVersion("9", "Production", "repo/flux:v9"),
+				mlflowVersion("12", "Production", "repo/flux:v12"),
+			}})
+		case "/api/2.0/mlflow/model-versions/search":
+			assert.Equal(t, "name='flux'", r.URL.Query().Get("filter"))
+			assert.Equal(t, "3", r.URL.Query().Get("max_results"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"model_versions": []interface{}{
+				mlflowVersion("12", "Production", "repo/flux:v12"),
+				mlflowVersion("11", "Archived", "repo/flux:v11"),
+				mlflowVersion("10", "None", ""),
+			}})
+		case "/api/2.0/mlflow/registered-models/alias":
+			assert.Equal(t, "champion", r.URL.Query().Get("alias"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"model_version": mlflowVersion("7", "None", "repo/flux:v7")})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	r := NewMLflowRegistry(srv.URL+"/", "secret", "", "")
+
+	mv, err := Resolve(context.Background(), r, "flux", "3")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", token)
+	assert.Equal(t, &ModelVersion{
+		Registry: "mlflow",
+		Model:    "flux",
+		Version:  "3",
+		Stage:    "Staging",
+		Image:    "repo/flux:v3",
+		Env:      map[string]string{"MODEL_PATH": "s3://models/flux/3"},
+		URL:      srv.URL + "/#/models/flux/versions/3",
+	}, mv)
+
+	_, err = Resolve(context.Background(), r, "flux", "4")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Newest version in the approved stage, numerically ordered
+	mv, err = Resolve(context.Background(), r, "flux", LatestApproved)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Production"}, stages)
+	assert.Equal(t, "12", mv.Version)
+	assert.Equal(t, "repo/flux:v12", mv.Image)
+
+	// The first offset+limit versions are searched, the page is cut from them
+	versions, err := ListVersions(context.Background(), r, "flux", 2, 1)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "11", versions[0].Version)
+	assert.Equal(t, "mlflow", versions[0].Registry)
+	assert.Equal(t, "10", versions[1].Version)
+	assert.Empty(t, versions[1].Image)
+
+	// The approved alias takes precedence over the stage
+	r = NewMLflowRegistry(srv.URL, "", "Production", "champion")
+	mv, err = Resolve(context.Background(), r, "flux", "")
+	require.NoError(t, err)
+	assert.Equal(t, "7", mv.Version)
+}
+
+func TestHTTPRegistry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/models/flux/versions/2":
+			_ = json.NewEncoder(w).Encode(&ModelVersion{Image: "repo/flux:v2", Env: map[string]string{"STEPS": "20"}})
+		case "/models/flux/latest-approved":
+			_ = json.NewEncoder(w).Encode(&ModelVersion{Version: "5", Stage: "approved", Image: "repo/flux:v5", URL: "https://registry/flux/5"})
+		case "/models/flux/versions":
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+			assert.Equal(t, "4", r.URL.Query().Get("offset"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"versions": []*ModelVersion{
+				{Version: "1", Image: "repo/flux:v1"},
+			}})
+		case "/models/noimage/versions/1":
+			_ = json.NewEncoder(w).Encode(&ModelVersion{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	r := NewHTTPRegistry(srv.URL, "")
+
+	mv, err := Resolve(context.Background(), r, "flux", "2")
+	require.NoError(t, err)
+	assert.Equal(t, &ModelVersion{Registry: "http", Model: "flux", Version: "2", Image: "repo/flux:v2", Env: map[string]string{"STEPS": "20"}}, mv)
+
+	mv, err = Resolve(context.Background(), r, "flux", "latest")
+	require.NoError(t, err)
+	assert.Equal(t, "5", mv.Version)
+	assert.Equal(t, "https://registry/flux/5", mv.URL)
+
+	_, err = Resolve(context.Background(), r, "missing", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = Resolve(context.Background(), r, "noimage", "1")
+	assert.ErrorIs(t, err, ErrNoImage)
+
+	_, err = Resolve(context.Background(), r, " ", "1")
+	assert.Error(t, err)
+
+	versions, err := ListVersions(context.Background(), r, "flux", 2, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []*ModelVersion{{Registry: "http", Model: "flux", Version: "1", Image: "repo/flux:v1"}}, versions)
+
+	// A model the registry does not know has no versions
+	versions, err = ListVersions(context.Background(), r, "missing", 2, 0)
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+}
@@ -116,6 +116,15 @@ func (a *Aggregator) GetRealtimeMetrics(ctx context.Context, endpoint string) (*
 	}, nil
 }
 
+// GetColdStartStats returns cold start metrics of workers created in [from, to), grouped by endpoint or spec
+func (a *Aggregator) GetColdStartStats(ctx context.Context, endpoint, groupBy string, from, to time.Time) ([]*ColdStartStats, error) {
+	samples, err := a.repo.ListColdStartSamples(ctx, endpoint, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeColdStartStats(samples, groupBy), nil
+}
+
 // GetMinuteStats returns minute-level statistics
 func (a *Aggregator) GetMinuteStats(ctx context.Context, endpoint string, from, to time.Time) ([]*MinuteStatResponse, error) {
 	stats, err := a.repo.GetMinuteStats(ctx, endpoint, from, to)
@@ -0,0 +1,130 @@
+package monitoring
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"waverless/pkg/store/mysql"
+)
+
+// Cold start grouping dimensions
+const (
+	ColdStartGroupByEndpoint = "endpoint"
+	ColdStartGroupBySpec     = "spec"
+)
+
+// PhaseStats summarizes the durations of one cold start phase
+type PhaseStats struct {
+	Count int     `json:"count"`
+	AvgMs float64 `json:"avg_ms"`
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// ColdStartStats cold start metrics for one endpoint or spec
+type ColdStartStats struct {
+	Key     string `json:"key"`
+	Workers int    `json:"workers"`
+	// PodStart pod created -> container started (scheduling + image pull)
+	PodStart PhaseStats `json:"pod_start"`
+	// Registration container started -> first worker heartbeat (model load / handler init)
+	Registration PhaseStats `json:"registration"`
+	// FirstTask worker registered -> first task finished
+	FirstTask PhaseStats `json:"first_task"`
+	// TimeToFirstTask pod created -> first task finished
+	TimeToFirstTask PhaseStats `json:"time_to_first_task"`
+}
+
+// ComputeColdStartStats groups worker samples by endpoint or spec and computes per-phase percentiles.
+// Phases with missing or out-of-order timestamps are skipped for that worker.
+func ComputeColdStartStats(samples []*mysql.ColdStartSample, groupBy string) []*ColdStartStats {
+	type phases struct {
+		workers                                    int
+		podStart, registration, firstTask, overall []float64
+	}
+	groups := make(map[string]*phases)
+
+	for _, s := range samples {
+		key := s.Endpoint
+		if groupBy == ColdStartGroupBySpec {
+			key = s.SpecName
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &phases{}
+			groups[key] = g
+		}
+		g.workers++
+		if d, ok := phaseMs(s.PodCreatedAt, s.PodStartedAt); ok {
+			g.podStart = append(g.podStart, d)
+		}
+		if d, ok := phaseMs(s.PodStartedAt, s.RegisteredAt); ok {
+			g.registration = append(g.registration, d)
+		}
+		if d, ok := phaseMs(s.RegisteredAt, s.FirstTaskCompletedAt); ok {
+			g.firstTask = append(g.firstTask, d)
+		}
+		if d, ok := phaseMs(s.PodCreatedAt, s.FirstTaskCompletedAt); ok {
+			g.overall = append(g.overall, d)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]*ColdStartStats, 0, len(keys))
+	for _, k := range keys {
+		g := groups[k]
+		result = append(result, &ColdStartStats{
+			Key:             k,
+			Workers:         g.workers,
+			PodStart:        summarize(g.podStart),
+			Registration:    summarize(g.registration),
+			FirstTask:       summarize(g.firstTask),
+			TimeToFirstTask: summarize(g.overall),
+		})
+	}
+	return result
+}
+
+func phaseMs(from, to *time.Time) (float64, bool) {
+	if from == nil || to == nil || to.Before(*from) {
+		return 0, false
+	}
+	return float64(to.Sub(*from).Milliseconds()), true
+}
+
+func summarize(values []float64) PhaseStats {
+	if len(values) == 0 {
+		return PhaseStats{}
+	}
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return PhaseStats{
+		Count: len(values),
+		AvgMs: sum / float64(len(values)),
+		P50Ms: percentile(values, 0.50),
+		P95Ms: percentile(values, 0.95),
+		MaxMs: values[len(values)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
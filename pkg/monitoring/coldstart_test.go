@@ -0,0 +1,61 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"waverless/pkg/store/mysql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func coldStartSample(endpoint, spec string, base time.Time, podStartS, registerS, firstTaskS int) *mysql.ColdStartSample {
+	created := base
+	started := created.Add(time.Duration(podStartS) * time.Second)
+	registered := started.Add(time.Duration(registerS) * time.Second)
+	firstTask := registered.Add(time.Duration(firstTaskS) * time.Second)
+	return &mysql.ColdStartSample{
+		Endpoint:             endpoint,
+		SpecName:             spec,
+		PodCreatedAt:         &created,
+		PodStartedAt:         &started,
+		RegisteredAt:         &registered,
+		FirstTaskCompletedAt: &firstTask,
+	}
+}
+
+// TestComputeColdStartStats tests per-phase percentiles grouped by endpoint and spec.
+func TestComputeColdStartStats(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var samples []*mysql.ColdStartSample
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, coldStartSample("ep-a", "h200", base, i, 10, 1))
+	}
+	samples = append(samples, coldStartSample("ep-b", "h200", base, 100, 5, 1))
+	// Worker still starting: only pod created
+	samples = append(samples, &mysql.ColdStartSample{Endpoint: "ep-b", SpecName: "h200", PodCreatedAt: &base})
+
+	byEndpoint := ComputeColdStartStats(samples, ColdStartGroupByEndpoint)
+	require.Len(t, byEndpoint, 2)
+
+	a := byEndpoint[0]
+	assert.Equal(t, "ep-a", a.Key)
+	assert.Equal(t, 20, a.Workers)
+	assert.Equal(t, 20, a.PodStart.Count)
+	assert.Equal(t, 10000.0, a.PodStart.P50Ms)
+	assert.Equal(t, 19000.0, a.PodStart.P95Ms)
+	assert.Equal(t, 20000.0, a.PodStart.MaxMs)
+	assert.Equal(t, 10000.0, a.Registration.P95Ms)
+	assert.Equal(t, 10500.0+11000, a.TimeToFirstTask.AvgMs)
+
+	b := byEndpoint[1]
+	assert.Equal(t, 2, b.Workers)
+	assert.Equal(t, 1, b.PodStart.Count)
+
+	bySpec := ComputeColdStartStats(samples, ColdStartGroupBySpec)
+	require.Len(t, bySpec, 1)
+	assert.Equal(t, "h200", bySpec[0].Key)
+	assert.Equal(t, 22, bySpec[0].Workers)
+	assert.Equal(t, 21, bySpec[0].FirstTask.Count)
+}
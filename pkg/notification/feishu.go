@@ -45,14 +45,24 @@ func NewFeishuNotifier() *FeishuNotifier {
 	}
 }
 
+// NewFeishuNotifierWithURL creates a Feishu notifier for a specific bot webhook URL
+func NewFeishuNotifierWithURL(webhookURL string) *FeishuNotifier {
+	return &FeishuNotifier{
+		webhookURL: webhookURL,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
 // ImageUpdateNotification represents an image update notification
 type ImageUpdateNotification struct {
-	Endpoint      string
-	CurrentImage  string
-	NewImageTag   string
-	ImagePrefix   string
-	DetectedAt    time.Time
-	DetectionType string // "webhook" or "manual"
+	Endpoint      string    `json:"endpoint"`
+	CurrentImage  string    `json:"currentImage"`
+	NewImageTag   string    `json:"newImageTag"`
+	ImagePrefix   string    `json:"imagePrefix,omitempty"`
+	DetectedAt    time.Time `json:"detectedAt"`
+	DetectionType string    `json:"detectionType"` // "webhook" or "manual"
 }
 
 // SendImageUpdateNotification sends image update notification to Feishu
@@ -180,3 +190,263 @@ func (f *FeishuNotifier) buildImageUpdateMessage(notification *ImageUpdateNotifi
 		},
 	}
 }
+
+// EndpointHealthNotification represents an endpoint health status transition
+type EndpointHealthNotification struct {
+	Endpoint       string    `json:"endpoint"`
+	PreviousStatus string    `json:"previousStatus"`
+	Status         string    `json:"status"`
+	Reason         string    `json:"reason,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	Trigger        string    `json:"trigger,omitempty"`
+	ChangedAt      time.Time `json:"changedAt"`
+}
+
+// SendEndpointHealthNotification sends endpoint health change notification to Feishu
+func (f *FeishuNotifier) SendEndpointHealthNotification(ctx context.Context, notification *EndpointHealthNotification) error {
+	if f.webhookURL == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(f.buildEndpointHealthMessage(notification))
+	if err != nil {
+		return fmt.Errorf("failed to marshal Feishu message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", f.webhookURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Feishu notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Feishu API returned status code: %d", resp.StatusCode)
+	}
+
+	logger.InfoCtx(ctx, "Feishu health notification sent successfully for endpoint: %s", notification.Endpoint)
+	return nil
+}
+
+// buildEndpointHealthMessage builds a Feishu message card for endpoint health changes
+func (f *FeishuNotifier) buildEndpointHealthMessage(notification *EndpointHealthNotification) map[string]interface{} {
+	template := "green"
+	title := "✅ Endpoint Recovered"
+	switch notification.Status {
+	case "UNHEALTHY":
+		template = "red"
+		title = "🚨 Endpoint Unhealthy"
+	case "DEGRADED":
+		template = "orange"
+		title = "⚠️ Endpoint Degraded"
+	}
+
+	message := notification.Message
+	if message == "" {
+		message = "-"
+	}
+	if notification.Reason != "" {
+		message = fmt.Sprintf("[%s] %s", notification.Reason, message)
+	}
+
+	return map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"template": template,
+				"title": map[string]interface{}{
+					"content": title,
+					"tag":     "plain_text",
+				},
+			},
+			"elements": []interface{}{
+				map[string]interface{}{
+					"tag": "div",
+					"text": map[string]interface{}{
+						"content": fmt.Sprintf("**Endpoint**: %s\n**Status**: %s → %s", notification.Endpoint, notification.PreviousStatus, notification.Status),
+						"tag":     "lark_md",
+					},
+				},
+				map[string]interface{}{
+					"tag": "hr",
+				},
+				map[string]interface{}{
+					"tag": "div",
+					"text": map[string]interface{}{
+						"content": fmt.Sprintf("**Reason**: %s\n**Trigger**: %s\n**Time**: %s", message, notification.Trigger, notification.ChangedAt.Format("2006-01-02 15:04:05")),
+						"tag":     "lark_md",
+					},
+				},
+			},
+		},
+	}
+}
+
+// DiskPressureNotification represents a worker pod evicted for disk usage
+type DiskPressureNotification struct {
+	Endpoint         string    `json:"endpoint"`
+	Pod              string    `json:"pod"`
+	Scope            string    `json:"scope,omitempty"`            // limit (pod exceeded its limit) or node (node disk full)
+	Message          string    `json:"message,omitempty"`          // Eviction message
+	EphemeralStorage string    `json:"ephemeralStorage,omitempty"` // Ephemeral storage limit at the time of the eviction
+	BumpedTo         string    `json:"bumpedTo,omitempty"`         // New limit when it was raised automatically
+	OccurredAt       time.Time `json:"occurredAt"`
+}
+
+// Text renders the notification as a plain text message
+func (n *DiskPressureNotification) Text() string {
+	text := fmt.Sprintf("[Waverless] 💾 Disk pressure on endpoint %s\nPod: %s\nScope: %s\nReason: %s", n.Endpoint, n.Pod, n.Scope, n.Message)
+	if n.BumpedTo != "" {
+		text += fmt.Sprintf("\nEphemeral storage raised: %s → %s", n.EphemeralStorage, n.BumpedTo)
+	}
+	return text
+}
+
+// AnomalyNotification represents a usage anomaly detected on an endpoint
+type AnomalyNotification struct {
+	Endpoint    string    `json:"endpoint"`
+	Kind        string    `json:"kind"`        // failure_rate_spike, gpu_hours_surge or queue_latency_runaway
+	Message     string    `json:"message"`     // What was observed, e.g. "failure rate 34.0% (41 of 120 tasks failed), usually 1.2%"
+	Current     float64   `json:"current"`     // Value in the detection window
+	Baseline    float64   `json:"baseline"`    // Usual value (median of the baseline hours)
+	Threshold   float64   `json:"threshold"`   // Value that triggered the alert
+	Window      string    `json:"window"`      // Detection window, e.g. "15m0s"
+	Sensitivity string    `json:"sensitivity"` // low, normal or high
+	DetectedAt  time.Time `json:"detectedAt"`
+}
+
+// Text renders the notification as a plain text message
+func (n *AnomalyNotification) Text() string {
+	return fmt.Sprintf("[Waverless] 📈 Usage anomaly on endpoint %s: %s\n%s over the last %s (sensitivity: %s)\nTime: %s",
+		n.Endpoint, n.Kind, n.Message, n.Window, n.Sensitivity, n.DetectedAt.Format("2006-01-02 15:04:05"))
+}
+
+// QuotaNotification warns that a project is close to, or forecast to reach, one of its quotas
+type QuotaNotification struct {
+	Project          string    `json:"project"`
+	Resource         string    `json:"resource"` // gpus or replicas
+	Level            string    `json:"level"`    // warning or exceeded
+	Limit            float64   `json:"limit"`
+	Used             float64   `json:"used"`
+	TrendPerDay      float64   `json:"trendPerDay"`                // Growth of the daily peak usage per day
+	DaysToExhaustion *float64  `json:"daysToExhaustion,omitempty"` // nil when usage is not growing
+	Reason           string    `json:"reason"`
+	DetectedAt       time.Time `json:"detectedAt"`
+}
+
+// Text renders the notification as a plain text message
+func (n *QuotaNotification) Text() string {
+	text := fmt.Sprintf("[Waverless] ⏳ Project %s %s quota %s: %g of %g in use\n%s",
+		n.Project, n.Resource, n.Level, n.Used, n.Limit, n.Reason)
+	if n.DaysToExhaustion != nil {
+		text += fmt.Sprintf("\nForecast exhaustion: %.1f days", *n.DaysToExhaustion)
+	}
+	return text + "\nTime: " + n.DetectedAt.Format("2006-01-02 15:04:05")
+}
+
+// JobRunNotification represents the final outcome of a scheduled job run
+type JobRunNotification struct {
+	Schedule    string     `json:"schedule"`
+	Job         string     `json:"job"`    // Batch job of the last attempt
+	Status      string     `json:"status"` // succeeded or failed
+	Attempt     int        `json:"attempt"`
+	ExitCode    *int       `json:"exitCode,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	GPUHours    float64    `json:"gpuHours"`
+	ScheduledAt time.Time  `json:"scheduledAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// Text renders the notification as a plain text message
+func (n *JobRunNotification) Text() string {
+	if n.Status != "failed" {
+		return fmt.Sprintf("[Waverless] ✅ Scheduled job %s succeeded\nRun: %s (attempt %d)\nGPU hours: %.2f",
+			n.Schedule, n.Job, n.Attempt, n.GPUHours)
+	}
+	text := fmt.Sprintf("[Waverless] ❌ Scheduled job %s failed after %d attempt(s)\nRun: %s", n.Schedule, n.Attempt, n.Job)
+	if n.ExitCode != nil {
+		text += fmt.Sprintf("\nExit code: %d", *n.ExitCode)
+	}
+	if n.Message != "" {
+		text += "\nReason: " + n.Message
+	}
+	return text
+}
+
+// LoadTestNotification represents a finished load test run and its comparison with the
+// baseline run
+type LoadTestNotification struct {
+	LoadTest      string    `json:"loadTest"`
+	Endpoint      string    `json:"endpoint"`
+	ReportID      int64     `json:"reportId"`
+	Status        string    `json:"status"` // succeeded or failed
+	Image         string    `json:"image"`
+	BaselineImage string    `json:"baselineImage,omitempty"`
+	Regressed     bool      `json:"regressed"`
+	Regressions   []string  `json:"regressions,omitempty"`
+	Requests      int       `json:"requests"`
+	ErrorRate     float64   `json:"errorRate"`
+	P99Ms         float64   `json:"p99Ms"`
+	Error         string    `json:"error,omitempty"`
+	FinishedAt    time.Time `json:"finishedAt"`
+}
+
+// Text renders the notification as a plain text message
+func (n *LoadTestNotification) Text() string {
+	if n.Status == "failed" {
+		return fmt.Sprintf("[Waverless] ❌ Load test %s of endpoint %s failed\nImage: %s\nReason: %s\nReport: %d",
+			n.LoadTest, n.Endpoint, n.Image, n.Error, n.ReportID)
+	}
+	icon, verdict := "✅", "passed"
+	if n.Regressed {
+		icon, verdict = "⚠️", "regressed"
+	}
+	text := fmt.Sprintf("[Waverless] %s Load test %s of endpoint %s %s\nImage: %s", icon, n.LoadTest, n.Endpoint, verdict, n.Image)
+	if n.BaselineImage != "" {
+		text += "\nBaseline: " + n.BaselineImage
+	}
+	text += fmt.Sprintf("\nRequests: %d, error rate %.2f%%, p99 %.0fms", n.Requests, n.ErrorRate*100, n.P99Ms)
+	for _, r := range n.Regressions {
+		text += "\n- " + r
+	}
+	return text + fmt.Sprintf("\nReport: %d", n.ReportID)
+}
+
+// SendText sends a plain text message to Feishu
+func (f *FeishuNotifier) SendText(ctx context.Context, text string) error {
+	if f.webhookURL == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"msg_type": "text",
+		"content":  map[string]interface{}{"text": text},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Feishu message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", f.webhookURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Feishu notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Feishu API returned status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Event types delivered to integrations
+const (
+	EventTaskCompleted  = "task.completed"
+	EventTaskFailed     = "task.failed"
+	EventEndpointHealth = "endpoint.health"        // Data: *EndpointHealthNotification
+	EventImageUpdate    = "image.update"           // Data: *ImageUpdateNotification
+	EventDiskPressure   = "endpoint.disk_pressure" // Data: *DiskPressureNotification
+	EventAnomaly        = "endpoint.anomaly"       // Data: *AnomalyNotification
+	EventEnvChanged     = "endpoint.env_changed"   // Data: the env change record (no secret values)
+	EventJobSucceeded   = "job.succeeded"          // Data: *JobRunNotification
+	EventJobFailed      = "job.failed"             // Data: *JobRunNotification (after the last retry)
+	EventHookRun        = "endpoint.hook_run"      // Data: the lifecycle hook run record
+	EventQuota          = "project.quota"          // Data: *QuotaNotification
+	EventLoadTest       = "loadtest.finished"      // Data: *LoadTestNotification
+	EventTest           = "test"
+)
+
+// Webhook delivery headers
+const (
+	HeaderEvent     = "X-Waverless-Event"
+	HeaderDelivery  = "X-Waverless-Delivery"
+	HeaderTimestamp = "X-Waverless-Timestamp"
+	HeaderSignature = "X-Waverless-Signature" // sha256=<hex HMAC of "<timestamp>.<body>">
+)
+
+// Event is something integrations can subscribe to
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Endpoint  string      `json:"endpoint,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookSender posts events as signed JSON
+type WebhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender creates a webhook sender
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	return &WebhookSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts an event to url, signed with secret when set. It returns the response status
+// code (0 if no response) and an error for transport failures and non-2xx responses.
+func (w *WebhookSender) Send(ctx context.Context, url, secret string, event *Event) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return w.post(ctx, url, secret, event.Type, event.ID, body)
+}
+
+// SendPayload posts an arbitrary payload, e.g. a task result in RunPod format
+func (w *WebhookSender) SendPayload(ctx context.Context, url, secret, eventType, deliveryID string, payload interface{}) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return w.post(ctx, url, secret, eventType, deliveryID, body)
+}
+
+func (w *WebhookSender) post(ctx context.Context, url, secret, eventType, deliveryID string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Waverless/1.0")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	if secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(HeaderTimestamp, ts)
+		req.Header.Set(HeaderSignature, "sha256="+Sign(secret, ts, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" that receivers recompute to
+// verify a delivery
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookSender_Send(t *testing.T) {
+	var gotSignature, gotTimestamp, gotEvent string
+	var gotBody []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(HeaderSignature)
+		gotTimestamp = r.Header.Get(HeaderTimestamp)
+		gotEvent = r.Header.Get(HeaderEvent)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(5 * time.Second)
+	event := &Event{ID: "d-1", Type: EventTaskCompleted, Endpoint: "flux", Data: map[string]string{"id": "t-1"}}
+
+	code, err := sender.Send(context.Background(), server.URL, "s3cret", event)
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("Send() = %d, %v", code, err)
+	}
+	if gotEvent != EventTaskCompleted {
+		t.Errorf("event header = %q", gotEvent)
+	}
+	if want := "sha256=" + Sign("s3cret", gotTimestamp, gotBody); gotSignature != want {
+		t.Errorf("signature = %q, want %q", gotSignature, want)
+	}
+
+	// Unsigned without a secret
+	if _, err := sender.Send(context.Background(), server.URL, "", event); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if gotSignature != "" {
+		t.Errorf("unexpected signature without secret: %q", gotSignature)
+	}
+
+	status = http.StatusBadGateway
+	code, err = sender.Send(context.Background(), server.URL, "", event)
+	if err == nil || code != http.StatusBadGateway || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Send() = %d, %v, want 502 error", code, err)
+	}
+}
@@ -0,0 +1,295 @@
+// Package operation runs long-running API actions (deploys, rollouts, backfills) in the
+// background and keeps their status, progress and outcome in a shared store, so that a client
+// can poll any replica for an operation ID and cancel it.
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/google/uuid"
+)
+
+// Heartbeat defaults
+const (
+	DefaultHeartbeatInterval = 10 * time.Second
+	DefaultStaleAfter        = 2 * time.Minute // Active operations without a heartbeat this long are failed
+)
+
+// maxErrorLength keeps errors within the error column
+const maxErrorLength = 1024
+
+// ErrNotFound is returned for unknown operation IDs
+var ErrNotFound = errors.New("operation not found")
+
+// ErrFinished is returned when cancelling an operation that has already finished
+var ErrFinished = errors.New("operation already finished")
+
+// store is the operation persistence, implemented by mysql.OperationRepository
+type store interface {
+	Create(ctx context.Context, op *model.Operation) error
+	Get(ctx context.Context, id string) (*model.Operation, error)
+	List(ctx context.Context, filter mysql.OperationFilter, limit, offset int) ([]*model.Operation, error)
+	Count(ctx context.Context, filter mysql.OperationFilter) (int64, error)
+	SaveProgress(ctx context.Context, op *model.Operation) (bool, error)
+	Finish(ctx context.Context, op *model.Operation) (bool, error)
+	Cancel(ctx context.Context, id string) (bool, error)
+	FailStale(ctx context.Context, before time.Time, reason string) (int64, error)
+}
+
+// Func is the work of an operation. It reports progress through p and must return soon after
+// ctx is cancelled.
+type Func func(ctx context.Context, p *Progress) error
+
+// Progress is the progress of a running operation, stored on every heartbeat and at the end
+type Progress struct {
+	mu      sync.Mutex
+	percent int
+	message string
+	result  model.JSONMap
+}
+
+// Update sets the completion percent (0-100) and a description of the current step
+func (p *Progress) Update(percent int, message string) {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.percent, p.message = percent, message
+}
+
+// SetResult sets a field of the operation's result
+func (p *Progress) SetResult(key string, value interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.result == nil {
+		p.result = make(model.JSONMap)
+	}
+	p.result[key] = value
+}
+
+func (p *Progress) snapshot() (int, string, model.JSONMap) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var result model.JSONMap
+	if p.result != nil {
+		result = make(model.JSONMap, len(p.result))
+		for k, v := range p.result {
+			result[k] = v
+		}
+	}
+	return p.percent, p.message, result
+}
+
+// Runner runs operations on this replica and answers for the operations of all replicas
+type Runner struct {
+	store      store
+	owner      string
+	heartbeat  time.Duration
+	staleAfter time.Duration
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+// NewRunner creates a runner; owner identifies this replica in the operations it runs
+func NewRunner(store store, owner string) *Runner {
+	return &Runner{
+		store:      store,
+		owner:      owner,
+		heartbeat:  DefaultHeartbeatInterval,
+		staleAfter: DefaultStaleAfter,
+		running:    make(map[string]context.CancelFunc),
+	}
+}
+
+// Start stores a pending operation and runs fn in the background. The returned operation is
+// the pending one; poll Get for its progress.
+func (r *Runner) Start(ctx context.Context, opType, target, createdBy string, fn Func) (*model.Operation, error) {
+	now := time.Now()
+	op := &model.Operation{
+		ID:        "op-" + uuid.NewString(),
+		Type:      opType,
+		Target:    target,
+		Status:    model.OperationPending,
+		Owner:     r.owner,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := r.store.Create(ctx, op); err != nil {
+		return nil, err
+	}
+	pending := *op
+
+	// Detached from the request, which ends with the response
+	runCtx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	r.running[op.ID] = cancel
+	r.mu.Unlock()
+
+	logger.InfoCtx(ctx, "operation %s started: type=%s, target=%s, createdBy=%s", op.ID, opType, target, createdBy)
+	go r.run(runCtx, cancel, op, fn)
+	return &pending, nil
+}
+
+// run executes fn, saving progress on every heartbeat until it returns
+func (r *Runner) run(ctx context.Context, cancel context.CancelFunc, op *model.Operation, fn Func) {
+	defer func() {
+		r.mu.Lock()
+		delete(r.running, op.ID)
+		r.mu.Unlock()
+		cancel()
+	}()
+
+	bg := context.Background()
+	now := time.Now()
+	op.Status = model.OperationRunning
+	op.StartedAt = &now
+	if active, err := r.store.SaveProgress(bg, op); err != nil {
+		logger.WarnCtx(bg, "failed to save operation %s: %v", op.ID, err)
+	} else if !active {
+		logger.InfoCtx(bg, "operation %s was cancelled before it started", op.ID)
+		return
+	}
+
+	progress := &Progress{}
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("operation panicked: %v", p)
+			}
+		}()
+		done <- fn(ctx, progress)
+	}()
+
+	ticker := time.NewTicker(r.heartbeat)
+	defer ticker.Stop()
+	var err error
+	for waiting := true; waiting; {
+		select {
+		case err = <-done:
+			waiting = false
+		case <-ticker.C:
+			op.Progress, op.Message, _ = progress.snapshot()
+			active, saveErr := r.store.SaveProgress(bg, op)
+			if saveErr != nil {
+				logger.WarnCtx(bg, "failed to save progress of operation %s: %v", op.ID, saveErr)
+				continue
+			}
+			if !active {
+				// Cancelled through another replica; keep waiting for fn to wind down
+				cancel()
+			}
+		}
+	}
+	r.finish(bg, ctx, op, progress, err)
+}
+
+// finish stores the outcome of an operation
+func (r *Runner) finish(bg, runCtx context.Context, op *model.Operation, progress *Progress, err error) {
+	now := time.Now()
+	op.CompletedAt = &now
+	op.Progress, op.Message, op.Result = progress.snapshot()
+	switch {
+	case err == nil:
+		op.Status = model.OperationSucceeded
+		op.Progress = 100
+	case runCtx.Err() != nil && errors.Is(err, context.Canceled):
+		op.Status = model.OperationCancelled
+	default:
+		op.Status = model.OperationFailed
+		op.Error = truncate(err.Error(), maxErrorLength)
+	}
+
+	stored, storeErr := r.store.Finish(bg, op)
+	switch {
+	case storeErr != nil:
+		logger.ErrorCtx(bg, "failed to store outcome of operation %s (%s): %v", op.ID, op.Status, storeErr)
+	case !stored:
+		logger.InfoCtx(bg, "operation %s stopped after it was cancelled", op.ID)
+	case op.Status == model.OperationFailed:
+		logger.WarnCtx(bg, "operation %s failed: type=%s, target=%s: %s", op.ID, op.Type, op.Target, op.Error)
+	default:
+		logger.InfoCtx(bg, "operation %s %s: type=%s, target=%s", op.ID, op.Status, op.Type, op.Target)
+	}
+}
+
+// Get returns an operation
+func (r *Runner) Get(ctx context.Context, id string) (*model.Operation, error) {
+	op, err := r.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return op, nil
+}
+
+// List returns the most recent operations matching the filter, skipping the first offset
+func (r *Runner) List(ctx context.Context, filter mysql.OperationFilter, limit, offset int) ([]*model.Operation, error) {
+	return r.store.List(ctx, filter, limit, offset)
+}
+
+// Count returns the number of operations matching the filter
+func (r *Runner) Count(ctx context.Context, filter mysql.OperationFilter) (int64, error) {
+	return r.store.Count(ctx, filter)
+}
+
+// Cancel cancels a pending or running operation. It stops right away when it runs on this
+// replica, otherwise on the owner's next heartbeat.
+func (r *Runner) Cancel(ctx context.Context, id string) (*model.Operation, error) {
+	cancelled, err := r.store.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cancelled {
+		r.mu.Lock()
+		if cancel, ok := r.running[id]; ok {
+			cancel()
+		}
+		r.mu.Unlock()
+	}
+
+	op, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return op, fmt.Errorf("%w: %s is %s", ErrFinished, id, op.Status)
+	}
+	return op, nil
+}
+
+// FailStale fails the active operations whose owner stopped sending heartbeats, e.g. because
+// the replica was restarted in the middle of a rollout
+func (r *Runner) FailStale(ctx context.Context) (int64, error) {
+	failed, err := r.store.FailStale(ctx, time.Now().Add(-r.staleAfter), "operation owner stopped sending heartbeats")
+	if err != nil {
+		return 0, err
+	}
+	if failed > 0 {
+		logger.WarnCtx(ctx, "failed %d operations without a heartbeat for %s", failed, r.staleAfter)
+	}
+	return failed, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
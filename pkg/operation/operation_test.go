@@ -0,0 +1,248 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"waverless/pkg/store/mysql"
+	"waverless/pkg/store/mysql/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore keeps operations in memory with the repository's active-only update semantics
+type memoryStore struct {
+	mu  sync.Mutex
+	ops map[string]*model.Operation
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{ops: make(map[string]*model.Operation)}
+}
+
+func (s *memoryStore) Create(_ context.Context, op *model.Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *op
+	s.ops[op.ID] = &stored
+	return nil
+}
+
+func (s *memoryStore) Get(_ context.Context, id string) (*model.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.ops[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *op
+	return &copied, nil
+}
+
+func (s *memoryStore) List(_ context.Context, filter mysql.OperationFilter, limit, offset int) ([]*model.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ops []*model.Operation
+	for _, op := range s.ops {
+		if matchesFilter(op, filter) && len(ops) < offset+limit {
+			copied := *op
+			ops = append(ops, &copied)
+		}
+	}
+	if offset >= len(ops) {
+		return nil, nil
+	}
+	return ops[offset:], nil
+}
+
+func (s *memoryStore) Count(_ context.Context, filter mysql.OperationFilter) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count int64
+	for _, op := range s.ops {
+		if matchesFilter(op, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func matchesFilter(op *model.Operation, filter mysql.OperationFilter) bool {
+	return (filter.Type == "" || op.Type == filter.Type) && (filter.Target == "" || op.Target == filter.Target) &&
+		(filter.Status == "" || op.Status == filter.Status)
+}
+
+func (s *memoryStore) update(op *model.Operation) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.ops[op.ID]
+	if !ok || stored.Done() {
+		return false
+	}
+	copied := *op
+	s.ops[op.ID] = &copied
+	return true
+}
+
+func (s *memoryStore) SaveProgress(_ context.Context, op *model.Operation) (bool, error) {
+	return s.update(op), nil
+}
+
+func (s *memoryStore) Finish(_ context.Context, op *model.Operation) (bool, error) {
+	return s.update(op), nil
+}
+
+func (s *memoryStore) Cancel(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.ops[id]
+	if !ok || op.Done() {
+		return false, nil
+	}
+	op.Status = model.OperationCancelled
+	return true, nil
+}
+
+func (s *memoryStore) FailStale(_ context.Context, before time.Time, reason string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var failed int64
+	for _, op := range s.ops {
+		if !op.Done() && op.UpdatedAt.Before(before) {
+			op.Status, op.Error = model.OperationFailed, reason
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+func waitDone(t *testing.T, r *Runner, id string) *model.Operation {
+	t.Helper()
+	var op *model.Operation
+	require.Eventually(t, func() bool {
+		var err error
+		op, err = r.Get(context.Background(), id)
+		require.NoError(t, err)
+		r.mu.Lock()
+		_, running := r.running[id]
+		r.mu.Unlock()
+		return op.Done() && !running
+	}, time.Second, 5*time.Millisecond)
+	return op
+}
+
+func TestRunner_Succeeds(t *testing.T) {
+	r := NewRunner(newMemoryStore(), "waverless-0")
+	op, err := r.Start(context.Background(), model.OperationTypeDeploy, "flux", "alice", func(_ context.Context, p *Progress) error {
+		p.Update(50, "deployment created")
+		p.SetResult("endpoint", "flux")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.OperationPending, op.Status)
+	assert.Equal(t, "waverless-0", op.Owner)
+
+	op = waitDone(t, r, op.ID)
+	assert.Equal(t, model.OperationSucceeded, op.Status)
+	assert.Equal(t, 100, op.Progress)
+	assert.Equal(t, "deployment created", op.Message)
+	assert.Equal(t, model.JSONMap{"endpoint": "flux"}, op.Result)
+	assert.NotNil(t, op.StartedAt)
+	assert.NotNil(t, op.CompletedAt)
+}
+
+func TestRunner_Fails(t *testing.T) {
+	r := NewRunner(newMemoryStore(), "waverless-0")
+	op, err := r.Start(context.Background(), model.OperationTypeRollout, "flux", "", func(context.Context, *Progress) error {
+		return errors.New("image pull failed")
+	})
+	require.NoError(t, err)
+	op = waitDone(t, r, op.ID)
+	assert.Equal(t, model.OperationFailed, op.Status)
+	assert.Equal(t, "image pull failed", op.Error)
+
+	op, err = r.Start(context.Background(), model.OperationTypeRollout, "flux", "", func(context.Context, *Progress) error {
+		panic("boom")
+	})
+	require.NoError(t, err)
+	op = waitDone(t, r, op.ID)
+	assert.Equal(t, model.OperationFailed, op.Status)
+	assert.Contains(t, op.Error, "panicked")
+}
+
+func TestRunner_Cancel(t *testing.T) {
+	r := NewRunner(newMemoryStore(), "waverless-0")
+	started := make(chan struct{})
+	op, err := r.Start(context.Background(), model.OperationTypeRollout, "flux", "", func(ctx context.Context, _ *Progress) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+	<-started
+
+	cancelled, err := r.Cancel(context.Background(), op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OperationCancelled, cancelled.Status)
+	assert.Equal(t, model.OperationCancelled, waitDone(t, r, op.ID).Status)
+
+	// Finished operations can't be cancelled
+	_, err = r.Cancel(context.Background(), op.ID)
+	assert.ErrorIs(t, err, ErrFinished)
+	_, err = r.Cancel(context.Background(), "op-missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRunner_CancelledByAnotherReplica(t *testing.T) {
+	store := newMemoryStore()
+	owner := NewRunner(store, "waverless-0")
+	owner.heartbeat = 10 * time.Millisecond
+	other := NewRunner(store, "waverless-1")
+
+	started := make(chan struct{})
+	op, err := owner.Start(context.Background(), model.OperationTypeGPUUsageBackfill, "", "", func(ctx context.Context, p *Progress) error {
+		p.Update(10, "backfilling")
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+	<-started
+
+	// The owner notices on its next heartbeat
+	_, err = other.Cancel(context.Background(), op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OperationCancelled, waitDone(t, owner, op.ID).Status)
+}
+
+func TestRunner_FailStale(t *testing.T) {
+	store := newMemoryStore()
+	r := NewRunner(store, "waverless-0")
+	require.NoError(t, store.Create(context.Background(), &model.Operation{
+		ID: "op-1", Status: model.OperationRunning, UpdatedAt: time.Now().Add(-time.Hour),
+	}))
+	require.NoError(t, store.Create(context.Background(), &model.Operation{
+		ID: "op-2", Status: model.OperationRunning, UpdatedAt: time.Now(),
+	}))
+
+	failed, err := r.FailStale(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), failed)
+	op, err := r.Get(context.Background(), "op-1")
+	require.NoError(t, err)
+	assert.Equal(t, model.OperationFailed, op.Status)
+}
+
+func TestProgress_Clamps(t *testing.T) {
+	p := &Progress{}
+	p.Update(150, "done")
+	percent, _, _ := p.snapshot()
+	assert.Equal(t, 100, percent)
+	p.Update(-1, "")
+	percent, _, _ = p.snapshot()
+	assert.Equal(t, 0, percent)
+}
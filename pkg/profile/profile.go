@@ -0,0 +1,133 @@
+// Package profile defines endpoint resource profiles: presets that expand to concrete
+// autoscaler, queue and dispatch settings so that users pick an intent ("latency",
+// "throughput", "economy") instead of tuning every knob by hand.
+package profile
+
+import (
+	"sort"
+
+	"waverless/pkg/interfaces"
+)
+
+// Profile names
+const (
+	Latency    = "latency"
+	Throughput = "throughput"
+	Economy    = "economy"
+)
+
+// Settings are the endpoint knobs a profile sets. MaxReplicas is left alone: it is a budget,
+// not a tuning choice.
+type Settings struct {
+	MinReplicas        int  `json:"minReplicas"`
+	ScaleUpThreshold   int  `json:"scaleUpThreshold"`
+	ScaleDownIdleTime  int  `json:"scaleDownIdleTime"`
+	ScaleUpCooldown    int  `json:"scaleUpCooldown"`
+	ScaleDownCooldown  int  `json:"scaleDownCooldown"`
+	EvaluationInterval int  `json:"evaluationInterval"` // 0 = global interval
+	QueueWaitSLO       int  `json:"queueWaitSLO"`       // 0 = none
+	MaxPendingTasks    int  `json:"maxPendingTasks"`
+	Priority           int  `json:"priority"`
+	EnableDynamicPrio  bool `json:"enableDynamicPrio"`
+	HighLoadThreshold  int  `json:"highLoadThreshold"`
+	PriorityBoost      int  `json:"priorityBoost"`
+}
+
+// Profile is a named preset
+type Profile struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Settings    Settings `json:"settings"`
+}
+
+var profiles = map[string]*Profile{
+	Latency: {
+		Name:        Latency,
+		Description: "Interactive traffic: keeps a warm replica, scales up on the first queued task and down reluctantly",
+		Settings: Settings{
+			MinReplicas:        1,
+			ScaleUpThreshold:   1,
+			ScaleDownIdleTime:  900,
+			ScaleUpCooldown:    10,
+			ScaleDownCooldown:  120,
+			EvaluationInterval: 5,
+			QueueWaitSLO:       10,
+			MaxPendingTasks:    1,
+			Priority:           70,
+			EnableDynamicPrio:  true,
+			HighLoadThreshold:  5,
+			PriorityBoost:      20,
+		},
+	},
+	Throughput: {
+		Name:        Throughput,
+		Description: "Steady batch-like traffic: lets a queue build up to keep workers busy and scales to zero when idle",
+		Settings: Settings{
+			MinReplicas:        0,
+			ScaleUpThreshold:   4,
+			ScaleDownIdleTime:  300,
+			ScaleUpCooldown:    30,
+			ScaleDownCooldown:  60,
+			EvaluationInterval: 0,
+			QueueWaitSLO:       120,
+			MaxPendingTasks:    100,
+			Priority:           50,
+			EnableDynamicPrio:  true,
+			HighLoadThreshold:  20,
+			PriorityBoost:      20,
+		},
+	},
+	Economy: {
+		Name:        Economy,
+		Description: "Cost first: scales up only on a long queue, releases GPUs quickly and yields to other endpoints",
+		Settings: Settings{
+			MinReplicas:        0,
+			ScaleUpThreshold:   10,
+			ScaleDownIdleTime:  60,
+			ScaleUpCooldown:    120,
+			ScaleDownCooldown:  30,
+			EvaluationInterval: 60,
+			QueueWaitSLO:       0,
+			MaxPendingTasks:    1000,
+			Priority:           20,
+			EnableDynamicPrio:  false,
+			HighLoadThreshold:  10,
+			PriorityBoost:      0,
+		},
+	},
+}
+
+// Get returns the profile with the given name
+func Get(name string) (*Profile, bool) {
+	p, ok := profiles[name]
+	return p, ok
+}
+
+// List returns all profiles sorted by name
+func List() []*Profile {
+	list := make([]*Profile, 0, len(profiles))
+	for _, p := range profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Apply sets the profile's settings on meta and records the profile name
+func (p *Profile) Apply(meta *interfaces.EndpointMetadata) {
+	s := p.Settings
+	meta.Profile = p.Name
+	meta.MinReplicas = s.MinReplicas
+	meta.ScaleUpThreshold = s.ScaleUpThreshold
+	meta.ScaleDownIdleTime = s.ScaleDownIdleTime
+	meta.ScaleUpCooldown = s.ScaleUpCooldown
+	meta.ScaleDownCooldown = s.ScaleDownCooldown
+	meta.EvaluationInterval = s.EvaluationInterval
+	meta.QueueWaitSLO = s.QueueWaitSLO
+	meta.MaxPendingTasks = s.MaxPendingTasks
+	meta.Priority = s.Priority
+	enableDynamicPrio := s.EnableDynamicPrio
+	meta.EnableDynamicPrio = &enableDynamicPrio
+	meta.HighLoadThreshold = s.HighLoadThreshold
+	meta.PriorityBoost = s.PriorityBoost
+}
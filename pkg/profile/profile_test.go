@@ -0,0 +1,44 @@
+package profile
+
+import (
+	"testing"
+
+	"waverless/pkg/autoscaler"
+	"waverless/pkg/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	var names []string
+	for _, p := range List() {
+		names = append(names, p.Name)
+		s := p.Settings
+		assert.True(t, s.EvaluationInterval == 0 || s.EvaluationInterval >= autoscaler.MinEvaluationInterval, p.Name)
+		assert.GreaterOrEqual(t, s.Priority, 0, p.Name)
+		assert.LessOrEqual(t, s.Priority, 100, p.Name)
+		assert.Positive(t, s.ScaleUpThreshold, p.Name)
+		assert.Positive(t, s.MaxPendingTasks, p.Name)
+	}
+	assert.Equal(t, []string{Economy, Latency, Throughput}, names)
+}
+
+func TestApply(t *testing.T) {
+	p, ok := Get(Latency)
+	require.True(t, ok)
+
+	meta := &interfaces.EndpointMetadata{MaxReplicas: 4, Priority: 10, CustomMetricName: "batch_queue"}
+	p.Apply(meta)
+	assert.Equal(t, Latency, meta.Profile)
+	assert.Equal(t, 1, meta.MinReplicas)
+	assert.Equal(t, 4, meta.MaxReplicas)
+	assert.Equal(t, 70, meta.Priority)
+	assert.Equal(t, 10, meta.QueueWaitSLO)
+	require.NotNil(t, meta.EnableDynamicPrio)
+	assert.True(t, *meta.EnableDynamicPrio)
+	assert.Equal(t, "batch_queue", meta.CustomMetricName)
+
+	_, ok = Get("fastest")
+	assert.False(t, ok)
+}
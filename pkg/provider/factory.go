@@ -8,6 +8,7 @@ import (
 	"waverless/pkg/deploy/docker"
 	"waverless/pkg/deploy/k8s"
 	"waverless/pkg/deploy/novita"
+	"waverless/pkg/deploy/runpod"
 	"waverless/pkg/interfaces"
 )
 
@@ -39,6 +40,7 @@ func init() {
 	RegisterDeploymentProvider("kubernetes", k8s.NewK8sDeploymentProvider)
 	RegisterDeploymentProvider("docker", docker.NewDockerDeploymentProvider)
 	RegisterDeploymentProvider("novita", novita.NewNovitaDeploymentProvider)
+	RegisterDeploymentProvider("runpod", runpod.NewRunPodDeploymentProvider)
 }
 
 func (f *ProviderFactory) CreateDeploymentProvider(providerType string) (interfaces.DeploymentProvider, error) {
@@ -56,22 +58,39 @@ func (f *ProviderFactory) CreateDeploymentProvider(providerType string) (interfa
 
 // BusinessProviders business providers collection
 type BusinessProviders struct {
+	// Deployment routes every endpoint to the provider it is pinned to
 	Deployment interfaces.DeploymentProvider
+	// Registry holds the configured deployment providers, the default first
+	Registry *ProviderRegistry
 }
 
 // CreateBusinessProviders creates business providers
 func (f *ProviderFactory) CreateBusinessProviders() (*BusinessProviders, error) {
 	deploymentType := "k8s"
-	if f.cfg.Providers != nil && f.cfg.Providers.Deployment != "" {
-		deploymentType = f.cfg.Providers.Deployment
+	var additional []string
+	if f.cfg.Providers != nil {
+		if f.cfg.Providers.Deployment != "" {
+			deploymentType = f.cfg.Providers.Deployment
+		}
+		additional = f.cfg.Providers.Additional
 	}
 
-	deploymentProvider, err := f.CreateDeploymentProvider(deploymentType)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create deployment provider: %w", err)
+	registry := NewProviderRegistry()
+	for _, providerType := range append([]string{deploymentType}, additional...) {
+		if registry.Get(providerType) != nil {
+			continue
+		}
+		deploymentProvider, err := f.CreateDeploymentProvider(providerType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create deployment provider %s: %w", providerType, err)
+		}
+		if err := registry.Register(providerType, deploymentProvider); err != nil {
+			return nil, err
+		}
 	}
 
 	return &BusinessProviders{
-		Deployment: deploymentProvider,
+		Deployment: NewRouter(registry),
+		Registry:   registry,
 	}, nil
 }
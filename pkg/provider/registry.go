@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"waverless/pkg/interfaces"
+)
+
+// providerAliases maps alternative provider names to the name endpoints are pinned with
+var providerAliases = map[string]string{
+	"kubernetes": "k8s",
+}
+
+// CanonicalName returns the name a deployment provider is registered and pinned under
+func CanonicalName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := providerAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// ProviderRegistry holds the configured deployment providers by name. The first registered
+// provider is the default for endpoints that do not pin one.
+type ProviderRegistry struct {
+	names     []string
+	providers map[string]interfaces.DeploymentProvider
+}
+
+// NewProviderRegistry creates an empty provider registry
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{providers: make(map[string]interfaces.DeploymentProvider)}
+}
+
+// Register adds a provider under its canonical name
+func (r *ProviderRegistry) Register(name string, p interfaces.DeploymentProvider) error {
+	name = CanonicalName(name)
+	if name == "" || p == nil {
+		return fmt.Errorf("deployment provider name and instance are required")
+	}
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("deployment provider %s is registered twice", name)
+	}
+	r.names = append(r.names, name)
+	r.providers[name] = p
+	return nil
+}
+
+// Get returns the provider registered under name, nil if there is none
+func (r *ProviderRegistry) Get(name string) interfaces.DeploymentProvider {
+	return r.providers[CanonicalName(name)]
+}
+
+// DefaultName returns the name of the default provider
+func (r *ProviderRegistry) DefaultName() string {
+	if len(r.names) == 0 {
+		return ""
+	}
+	return r.names[0]
+}
+
+// Default returns the default provider, nil if none is registered
+func (r *ProviderRegistry) Default() interfaces.DeploymentProvider {
+	return r.providers[r.DefaultName()]
+}
+
+// Names returns the registered provider names, the default first
+func (r *ProviderRegistry) Names() []string {
+	return append([]string(nil), r.names...)
+}
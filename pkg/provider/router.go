@@ -0,0 +1,333 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// pinCacheTTL bounds how long a looked up endpoint pin is reused
+const pinCacheTTL = 30 * time.Second
+
+// EndpointLookup returns the provider an existing endpoint is pinned to. found is false for
+// endpoints that do not exist; an empty provider of an existing endpoint means the default.
+type EndpointLookup func(ctx context.Context, endpoint string) (provider string, found bool, err error)
+
+type pinEntry struct {
+	provider string
+	expires  time.Time
+}
+
+// Router dispatches every endpoint to the provider it is pinned to. Endpoints are pinned when
+// they are first deployed and cannot move to another provider afterwards.
+type Router struct {
+	registry *ProviderRegistry
+	lookup   EndpointLookup
+
+	mu   sync.Mutex
+	pins map[string]pinEntry
+}
+
+var (
+	_ interfaces.DeploymentProvider = (*Router)(nil)
+	_ interfaces.ProviderRouter     = (*Router)(nil)
+)
+
+// NewRouter creates a router over the providers of registry
+func NewRouter(registry *ProviderRegistry) *Router {
+	return &Router{
+		registry: registry,
+		pins:     make(map[string]pinEntry),
+	}
+}
+
+// SetEndpointLookup sets how the pinned provider of existing endpoints is resolved (for dependency injection)
+func (r *Router) SetEndpointLookup(lookup EndpointLookup) {
+	r.lookup = lookup
+}
+
+// Registry returns the registry the router dispatches to
+func (r *Router) Registry() *ProviderRegistry {
+	return r.registry
+}
+
+// Route returns the name and provider serving endpoint
+func (r *Router) Route(ctx context.Context, endpoint, requested string) (string, interfaces.DeploymentProvider, error) {
+	requested = CanonicalName(requested)
+
+	pinned, found, err := r.pinnedProvider(ctx, endpoint)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve deployment provider of endpoint %s: %w", endpoint, err)
+	}
+
+	name := requested
+	if found {
+		name = pinned
+		if name == "" {
+			name = r.registry.DefaultName()
+		}
+		if requested != "" && requested != name {
+			return "", nil, interfaces.ProviderErrorf(interfaces.ErrConflict,
+				"endpoint %s runs on provider %s, it cannot move to %s", endpoint, name, requested)
+		}
+	}
+	if name == "" {
+		name = r.registry.DefaultName()
+	}
+
+	p := r.registry.Get(name)
+	if p == nil {
+		return "", nil, interfaces.ProviderErrorf(interfaces.ErrNotFound, "deployment provider %s is not configured", name)
+	}
+	return name, p, nil
+}
+
+// Providers returns all providers, the default first
+func (r *Router) Providers() []interfaces.DeploymentProvider {
+	names := r.registry.Names()
+	providers := make([]interfaces.DeploymentProvider, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, r.registry.Get(name))
+	}
+	return providers
+}
+
+func (r *Router) pinnedProvider(ctx context.Context, endpoint string) (string, bool, error) {
+	if r.lookup == nil || endpoint == "" {
+		return "", false, nil
+	}
+
+	r.mu.Lock()
+	entry, ok := r.pins[endpoint]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.provider, true, nil
+	}
+
+	provider, found, err := r.lookup(ctx, endpoint)
+	if err != nil || !found {
+		return "", false, err
+	}
+	provider = CanonicalName(provider)
+	r.pin(endpoint, provider)
+	return provider, true, nil
+}
+
+func (r *Router) pin(endpoint, provider string) {
+	r.mu.Lock()
+	r.pins[endpoint] = pinEntry{provider: provider, expires: time.Now().Add(pinCacheTTL)}
+	r.mu.Unlock()
+}
+
+func (r *Router) unpin(endpoint string) {
+	r.mu.Lock()
+	delete(r.pins, endpoint)
+	r.mu.Unlock()
+}
+
+func (r *Router) route(ctx context.Context, endpoint string) (interfaces.DeploymentProvider, error) {
+	_, p, err := r.Route(ctx, endpoint, "")
+	return p, err
+}
+
+// Deploy deploys on the requested provider and pins the endpoint to it
+func (r *Router) Deploy(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
+	name, p, err := r.Route(ctx, req.Endpoint, req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Deploy(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.pin(req.Endpoint, name)
+	return resp, nil
+}
+
+func (r *Router) GetApp(ctx context.Context, endpoint string) (*interfaces.AppInfo, error) {
+	p, err := r.route(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetApp(ctx, endpoint)
+}
+
+// ListApps lists the applications of all providers
+func (r *Router) ListApps(ctx context.Context) ([]*interfaces.AppInfo, error) {
+	var apps []*interfaces.AppInfo
+	for _, name := range r.registry.Names() {
+		providerApps, err := r.registry.Get(name).ListApps(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list apps of provider %s: %w", name, err)
+		}
+		apps = append(apps, providerApps...)
+	}
+	return apps, nil
+}
+
+func (r *Router) DeleteApp(ctx context.Context, endpoint string) error {
+	p, err := r.route(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if err := p.DeleteApp(ctx, endpoint); err != nil {
+		return err
+	}
+	r.unpin(endpoint)
+	return nil
+}
+
+func (r *Router) GetAppLogs(ctx context.Context, endpoint string, lines int, podName ...string) (string, error) {
+	p, err := r.route(ctx, endpoint)
+	if err != nil {
+		return "", err
+	}
+	return p.GetAppLogs(ctx, endpoint, lines, podName...)
+}
+
+func (r *Router) ScaleApp(ctx context.Context, endpoint string, replicas int) error {
+	p, err := r.route(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	return p.ScaleApp(ctx, endpoint, replicas)
+}
+
+func (r *Router) GetAppStatus(ctx context.Context, endpoint string) (*interfaces.AppStatus, error) {
+	p, err := r.route(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetAppStatus(ctx, endpoint)
+}
+
+// ListSpecs lists the specs of all providers; for duplicate names the earlier provider wins
+func (r *Router) ListSpecs(ctx context.Context) ([]*interfaces.SpecInfo, error) {
+	var specs []*interfaces.SpecInfo
+	seen := make(map[string]bool)
+	for _, name := range r.registry.Names() {
+		providerSpecs, err := r.registry.Get(name).ListSpecs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list specs of provider %s: %w", name, err)
+		}
+		for _, spec := range providerSpecs {
+			if seen[spec.Name] {
+				continue
+			}
+			seen[spec.Name] = true
+			specs = append(specs, spec)
+		}
+	}
+	return specs, nil
+}
+
+// GetSpec returns the spec from the first provider that has it, the default first
+func (r *Router) GetSpec(ctx context.Context, specName string) (*interfaces.SpecInfo, error) {
+	var firstErr error
+	for _, p := range r.Providers() {
+		spec, err := p.GetSpec(ctx, specName)
+		if err == nil && spec != nil {
+			return spec, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = fmt.Errorf("spec %s not found", specName)
+	}
+	return nil, firstErr
+}
+
+func (r *Router) PreviewDeploymentYAML(ctx context.Context, req *interfaces.DeployRequest) (string, error) {
+	_, p, err := r.Route(ctx, req.Endpoint, req.Provider)
+	if err != nil {
+		return "", err
+	}
+	return p.PreviewDeploymentYAML(ctx, req)
+}
+
+func (r *Router) UpdateDeployment(ctx context.Context, req *interfaces.UpdateDeploymentRequest) (*interfaces.DeployResponse, error) {
+	p, err := r.route(ctx, req.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return p.UpdateDeployment(ctx, req)
+}
+
+// WatchReplicas watches replicas on all providers. It only fails if no provider can be watched,
+// providers without watch support are logged and skipped.
+func (r *Router) WatchReplicas(ctx context.Context, callback interfaces.ReplicaCallback) error {
+	var lastErr error
+	watching := 0
+	for _, name := range r.registry.Names() {
+		if err := r.registry.Get(name).WatchReplicas(ctx, callback); err != nil {
+			logger.Warnf("Failed to watch replicas of provider %s: %v", name, err)
+			lastErr = err
+			continue
+		}
+		watching++
+	}
+	if watching == 0 && lastErr != nil {
+		return lastErr
+	}
+	return nil
+}
+
+func (r *Router) GetPods(ctx context.Context, endpoint string) ([]*interfaces.PodInfo, error) {
+	p, err := r.route(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetPods(ctx, endpoint)
+}
+
+func (r *Router) DescribePod(ctx context.Context, endpoint string, podName string) (*interfaces.PodDetail, error) {
+	p, err := r.route(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return p.DescribePod(ctx, endpoint, podName)
+}
+
+func (r *Router) GetPodYAML(ctx context.Context, endpoint string, podName string) (string, error) {
+	p, err := r.route(ctx, endpoint)
+	if err != nil {
+		return "", err
+	}
+	return p.GetPodYAML(ctx, endpoint, podName)
+}
+
+// ListPVCs lists the PVCs of the default provider
+func (r *Router) ListPVCs(ctx context.Context) ([]*interfaces.PVCInfo, error) {
+	return r.registry.Default().ListPVCs(ctx)
+}
+
+// GetDefaultEnv returns the default environment of the default provider
+func (r *Router) GetDefaultEnv(ctx context.Context) (map[string]string, error) {
+	return r.registry.Default().GetDefaultEnv(ctx)
+}
+
+// IsPodTerminating reports whether any provider sees the worker terminating. Worker IDs are
+// not scoped to endpoints, so all providers are asked.
+func (r *Router) IsPodTerminating(ctx context.Context, podName string) (bool, error) {
+	var lastErr error
+	for _, p := range r.Providers() {
+		terminating, err := p.IsPodTerminating(ctx, podName)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if terminating {
+			return true, nil
+		}
+	}
+	if len(r.registry.Names()) == 1 && lastErr != nil {
+		return false, lastErr
+	}
+	return false, nil
+}
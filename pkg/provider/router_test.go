@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/interfaces"
+)
+
+// fakeProvider records deployments; methods the tests do not use panic through the nil embed
+type fakeProvider struct {
+	interfaces.DeploymentProvider
+	name     string
+	deployed []string
+	apps     []*interfaces.AppInfo
+	specs    []*interfaces.SpecInfo
+}
+
+func (f *fakeProvider) Deploy(ctx context.Context, req *interfaces.DeployRequest) (*interfaces.DeployResponse, error) {
+	f.deployed = append(f.deployed, req.Endpoint)
+	return &interfaces.DeployResponse{Endpoint: req.Endpoint}, nil
+}
+
+func (f *fakeProvider) ScaleApp(ctx context.Context, endpoint string, replicas int) error {
+	f.deployed = append(f.deployed, endpoint)
+	return nil
+}
+
+func (f *fakeProvider) DeleteApp(ctx context.Context, endpoint string) error { return nil }
+
+func (f *fakeProvider) ListApps(ctx context.Context) ([]*interfaces.AppInfo, error) {
+	return f.apps, nil
+}
+
+func (f *fakeProvider) ListSpecs(ctx context.Context) ([]*interfaces.SpecInfo, error) {
+	return f.specs, nil
+}
+
+func newTestRouter(t *testing.T, pins map[string]string) (*Router, *fakeProvider, *fakeProvider) {
+	k8s := &fakeProvider{name: "k8s"}
+	runpod := &fakeProvider{name: "runpod"}
+	registry := NewProviderRegistry()
+	require.NoError(t, registry.Register("kubernetes", k8s))
+	require.NoError(t, registry.Register("runpod", runpod))
+
+	router := NewRouter(registry)
+	router.SetEndpointLookup(func(ctx context.Context, endpoint string) (string, bool, error) {
+		if endpoint == "broken" {
+			return "", false, errors.New("db down")
+		}
+		provider, found := pins[endpoint]
+		return provider, found, nil
+	})
+	return router, k8s, runpod
+}
+
+func TestProviderRegistry(t *testing.T) {
+	registry := NewProviderRegistry()
+	k8s := &fakeProvider{name: "k8s"}
+	require.NoError(t, registry.Register("Kubernetes", k8s))
+	require.NoError(t, registry.Register("novita", &fakeProvider{name: "novita"}))
+
+	assert.Error(t, registry.Register("k8s", &fakeProvider{}), "aliases are duplicates")
+	assert.Error(t, registry.Register("", &fakeProvider{}))
+	assert.Equal(t, "k8s", registry.DefaultName())
+	assert.Same(t, k8s, registry.Default())
+	assert.Same(t, k8s, registry.Get("kubernetes"))
+	assert.Nil(t, registry.Get("runpod"))
+	assert.Equal(t, []string{"k8s", "novita"}, registry.Names())
+}
+
+func TestRouterRoute(t *testing.T) {
+	ctx := context.Background()
+	router, k8s, runpod := newTestRouter(t, map[string]string{"legacy": "", "burst": "runpod"})
+
+	name, p, err := router.Route(ctx, "new", "")
+	require.NoError(t, err)
+	assert.Equal(t, "k8s", name, "new endpoints default to the first provider")
+	assert.Same(t, k8s, p)
+
+	name, p, err = router.Route(ctx, "new", "RunPod")
+	require.NoError(t, err)
+	assert.Equal(t, "runpod", name)
+	assert.Same(t, runpod, p)
+
+	_, p, err = router.Route(ctx, "legacy", "")
+	require.NoError(t, err)
+	assert.Same(t, k8s, p, "an empty pin means the default provider")
+
+	_, p, err = router.Route(ctx, "burst", "")
+	require.NoError(t, err)
+	assert.Same(t, runpod, p)
+
+	_, _, err = router.Route(ctx, "burst", "k8s")
+	assert.ErrorIs(t, err, interfaces.ErrConflict, "pinned endpoints cannot move")
+
+	_, _, err = router.Route(ctx, "new", "novita")
+	assert.ErrorIs(t, err, interfaces.ErrNotFound)
+
+	_, _, err = router.Route(ctx, "broken", "")
+	assert.Error(t, err)
+	assert.Same(t, router, interfaces.ResolveProvider(ctx, router, "broken", ""), "failed routing resolves to the router")
+}
+
+func TestRouterDispatch(t *testing.T) {
+	ctx := context.Background()
+	pins := map[string]string{"burst": "runpod"}
+	router, k8s, runpod := newTestRouter(t, pins)
+
+	_, err := router.Deploy(ctx, &interfaces.DeployRequest{Endpoint: "fresh", Provider: "runpod"})
+	require.NoError(t, err)
+	require.NoError(t, router.ScaleApp(ctx, "burst", 2))
+	require.NoError(t, router.ScaleApp(ctx, "other", 1))
+	assert.Equal(t, []string{"fresh", "burst"}, runpod.deployed)
+	assert.Equal(t, []string{"other"}, k8s.deployed)
+
+	// The deploy pinned the endpoint before its metadata lookup reflects it
+	require.NoError(t, router.ScaleApp(ctx, "fresh", 3))
+	assert.Equal(t, []string{"fresh", "burst", "fresh"}, runpod.deployed)
+
+	// Deleting evicts the pin, the endpoint may come back elsewhere
+	require.NoError(t, router.DeleteApp(ctx, "fresh"))
+	_, p, err := router.Route(ctx, "fresh", "k8s")
+	require.NoError(t, err)
+	assert.Same(t, k8s, p)
+}
+
+func TestRouterAggregates(t *testing.T) {
+	ctx := context.Background()
+	router, k8s, runpod := newTestRouter(t, nil)
+	k8s.apps = []*interfaces.AppInfo{{Name: "a"}}
+	runpod.apps = []*interfaces.AppInfo{{Name: "b"}}
+	k8s.specs = []*interfaces.SpecInfo{{Name: "h100", DisplayName: "k8s"}}
+	runpod.specs = []*interfaces.SpecInfo{{Name: "h100", DisplayName: "runpod"}, {Name: "a100"}}
+
+	apps, err := router.ListApps(ctx)
+	require.NoError(t, err)
+	assert.Len(t, apps, 2)
+
+	specs, err := router.ListSpecs(ctx)
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	assert.Equal(t, "k8s", specs[0].DisplayName, "the default provider wins duplicate spec names")
+	assert.Equal(t, "a100", specs[1].Name)
+}
@@ -0,0 +1,123 @@
+package quota
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Quota resources
+const (
+	ResourceGPUs     = "gpus"
+	ResourceReplicas = "replicas"
+)
+
+// Headroom levels
+const (
+	LevelOK       = "ok"
+	LevelWarning  = "warning"  // Close to the limit, or forecast to reach it soon
+	LevelExceeded = "exceeded" // At or over the limit
+)
+
+// minHistoryDays is the number of daily peaks needed before a trend is fitted
+const minHistoryDays = 3
+
+// Point is the peak usage of one day
+type Point struct {
+	Day   time.Time `json:"day"`
+	Value float64   `json:"value"`
+}
+
+// Thresholds decide when a headroom becomes a warning
+type Thresholds struct {
+	WarnPercent float64 // Usage of the limit, in percent
+	WarnDays    float64 // Forecast days to exhaustion
+}
+
+// Headroom is the usage and forecast of one quota resource
+type Headroom struct {
+	Resource    string  `json:"resource"` // gpus or replicas
+	Limit       float64 `json:"limit"`
+	Used        float64 `json:"used"`
+	UsedPercent float64 `json:"usedPercent"`
+	// TrendPerDay is the growth of the daily peak per day, fitted by least squares over the
+	// history (0 with less than minHistoryDays days of history)
+	TrendPerDay float64 `json:"trendPerDay"`
+	// DaysToExhaustion is when the trend reaches the limit; nil when usage is flat or shrinking
+	DaysToExhaustion *float64 `json:"daysToExhaustion,omitempty"`
+	Level            string   `json:"level"`
+	Reason           string   `json:"reason,omitempty"`
+	History          []Point  `json:"history"`
+}
+
+// Forecast computes the headroom of a resource from its current usage and daily peaks.
+// The trend starts from the larger of the current usage and the last peak, so a quiet
+// moment right now does not hide a peak the project regularly reaches.
+func Forecast(resource string, limit, used float64, history []Point, thresholds Thresholds) *Headroom {
+	sorted := append([]Point{}, history...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Day.Before(sorted[j].Day) })
+
+	h := &Headroom{
+		Resource: resource,
+		Limit:    limit,
+		Used:     used,
+		Level:    LevelOK,
+		History:  sorted,
+	}
+	if limit <= 0 {
+		return h
+	}
+	h.UsedPercent = used / limit * 100
+
+	if len(sorted) >= minHistoryDays {
+		h.TrendPerDay = slopePerDay(sorted)
+	}
+	base := used
+	if len(sorted) > 0 && sorted[len(sorted)-1].Value > base {
+		base = sorted[len(sorted)-1].Value
+	}
+	if base >= limit {
+		days := 0.0
+		h.DaysToExhaustion = &days
+	} else if h.TrendPerDay > 0 {
+		days := (limit - base) / h.TrendPerDay
+		h.DaysToExhaustion = &days
+	}
+
+	switch {
+	case used >= limit:
+		h.Level = LevelExceeded
+		h.Reason = fmt.Sprintf("%s %g of %g in use", resource, used, limit)
+	case h.UsedPercent >= thresholds.WarnPercent:
+		h.Level = LevelWarning
+		h.Reason = fmt.Sprintf("%s %.0f%% of the quota in use (%g of %g)", resource, h.UsedPercent, used, limit)
+	case h.DaysToExhaustion != nil && *h.DaysToExhaustion <= thresholds.WarnDays:
+		h.Level = LevelWarning
+		h.Reason = fmt.Sprintf("%s forecast to reach the quota of %g in %.1f days (daily peak growing by %.2f/day)", resource, limit, *h.DaysToExhaustion, h.TrendPerDay)
+	}
+	return h
+}
+
+// slopePerDay fits value = a + b*day by least squares and returns b
+func slopePerDay(points []Point) float64 {
+	origin := points[0].Day
+	n := float64(len(points))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.Day.Sub(origin).Hours() / 24
+		sumX += x
+		sumY += p.Value
+		sumXY += x * p.Value
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	if math.IsNaN(slope) || math.IsInf(slope, 0) {
+		return 0
+	}
+	return slope
+}
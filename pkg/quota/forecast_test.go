@@ -0,0 +1,71 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testThresholds = Thresholds{WarnPercent: 80, WarnDays: 14}
+
+func dailyPeaks(values ...float64) []Point {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]Point, 0, len(values))
+	for i, v := range values {
+		points = append(points, Point{Day: start.AddDate(0, 0, i), Value: v})
+	}
+	return points
+}
+
+func TestForecast_GrowingUsage(t *testing.T) {
+	// Peak grows by 2 GPUs a day: 10 -> 20, limit 40 is 10 days away
+	h := Forecast(ResourceGPUs, 40, 16, dailyPeaks(10, 12, 14, 16, 18, 20), testThresholds)
+
+	assert.InDelta(t, 2, h.TrendPerDay, 1e-9)
+	require.NotNil(t, h.DaysToExhaustion)
+	assert.InDelta(t, 10, *h.DaysToExhaustion, 1e-9)
+	assert.Equal(t, LevelWarning, h.Level)
+	assert.Contains(t, h.Reason, "forecast")
+	assert.InDelta(t, 40, h.UsedPercent, 1e-9)
+}
+
+func TestForecast_FlatUsage(t *testing.T) {
+	h := Forecast(ResourceReplicas, 100, 20, dailyPeaks(20, 21, 19, 20, 20), testThresholds)
+
+	assert.Nil(t, h.DaysToExhaustion)
+	assert.Equal(t, LevelOK, h.Level)
+	assert.Empty(t, h.Reason)
+}
+
+func TestForecast_SlowGrowthBeyondHorizon(t *testing.T) {
+	h := Forecast(ResourceGPUs, 100, 10, dailyPeaks(10, 11, 12), testThresholds)
+
+	require.NotNil(t, h.DaysToExhaustion)
+	assert.InDelta(t, 88, *h.DaysToExhaustion, 1e-9)
+	assert.Equal(t, LevelOK, h.Level)
+}
+
+func TestForecast_HighUsage(t *testing.T) {
+	h := Forecast(ResourceGPUs, 10, 9, nil, testThresholds)
+	assert.Equal(t, LevelWarning, h.Level)
+	assert.Contains(t, h.Reason, "90%")
+
+	h = Forecast(ResourceGPUs, 10, 10, nil, testThresholds)
+	assert.Equal(t, LevelExceeded, h.Level)
+	require.NotNil(t, h.DaysToExhaustion)
+	assert.Zero(t, *h.DaysToExhaustion)
+}
+
+func TestForecast_ShortHistoryHasNoTrend(t *testing.T) {
+	h := Forecast(ResourceGPUs, 100, 10, dailyPeaks(10, 50), testThresholds)
+	assert.Zero(t, h.TrendPerDay)
+	assert.Nil(t, h.DaysToExhaustion)
+}
+
+func TestForecast_NoLimit(t *testing.T) {
+	h := Forecast(ResourceGPUs, 0, 10, dailyPeaks(1, 2, 3), testThresholds)
+	assert.Equal(t, LevelOK, h.Level)
+	assert.Nil(t, h.DaysToExhaustion)
+}
@@ -0,0 +1,216 @@
+// Package ratelimit smooths calls to provider APIs that throttle per credential. Calls made
+// with the same credential share one bucket, in Redis when several control-plane replicas
+// use the same key, and wait for their turn instead of failing with 429s.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/logger"
+)
+
+// Bucket hands out call slots. Reserve books the next slot of key and returns how long the
+// caller must wait for it; when that exceeds maxWait nothing is booked and ok is false.
+type Bucket interface {
+	Reserve(ctx context.Context, key string, rate float64, burst int, maxWait time.Duration) (wait time.Duration, ok bool, err error)
+}
+
+const bucketKeyPrefix = "ratelimit:"
+
+// reserveScript implements GCRA: the key holds the theoretical arrival time (TAT) in
+// milliseconds. Redis time is used so that replicas with skewed clocks agree.
+const reserveScript = `
+	local t = redis.call("time")
+	local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+	local interval = tonumber(ARGV[1])
+	local tolerance = interval * (tonumber(ARGV[2]) - 1)
+	local tat = tonumber(redis.call("get", KEYS[1]) or "0")
+	if tat < now then
+		tat = now
+	end
+	local wait = tat - tolerance - now
+	if wait < 0 then
+		wait = 0
+	end
+	if wait > tonumber(ARGV[3]) then
+		return {0, wait}
+	end
+	tat = tat + interval
+	redis.call("set", KEYS[1], tostring(tat), "px", math.ceil(tat - now) + 1000)
+	return {1, wait}
+`
+
+// RedisBucket shares buckets between replicas
+type RedisBucket struct {
+	client *redis.Client
+}
+
+// NewRedisBucket creates a Redis-backed bucket
+func NewRedisBucket(client *redis.Client) *RedisBucket {
+	return &RedisBucket{client: client}
+}
+
+// Reserve books the next slot of key in Redis
+func (b *RedisBucket) Reserve(ctx context.Context, key string, rate float64, burst int, maxWait time.Duration) (time.Duration, bool, error) {
+	interval := 1000 / rate
+	result, err := b.client.Eval(ctx, reserveScript, []string{bucketKeyPrefix + key}, interval, burst, maxWait.Milliseconds()).Slice()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to reserve rate limit slot %s: %w", key, err)
+	}
+	if len(result) != 2 {
+		return 0, false, fmt.Errorf("unexpected rate limit reply for %s: %v", key, result)
+	}
+	ok, _ := result[0].(int64)
+	waitMs, _ := result[1].(int64)
+	return time.Duration(waitMs) * time.Millisecond, ok == 1, nil
+}
+
+// MemoryBucket is an in-process bucket, used when running a single replica without Redis
+type MemoryBucket struct {
+	mu   sync.Mutex
+	tats map[string]time.Time
+	now  func() time.Time
+}
+
+// NewMemoryBucket creates an in-process bucket
+func NewMemoryBucket() *MemoryBucket {
+	return &MemoryBucket{tats: make(map[string]time.Time), now: time.Now}
+}
+
+// Reserve books the next slot of key
+func (b *MemoryBucket) Reserve(ctx context.Context, key string, rate float64, burst int, maxWait time.Duration) (time.Duration, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	interval := time.Duration(float64(time.Second) / rate)
+	tat := b.tats[key]
+	if tat.Before(now) {
+		tat = now
+	}
+	wait := max(tat.Add(-interval*time.Duration(burst-1)).Sub(now), 0)
+	if wait > maxWait {
+		return wait, false, nil
+	}
+	b.tats[key] = tat.Add(interval)
+	return wait, true, nil
+}
+
+// Key identifies a provider credential without exposing it
+func Key(provider, credential string) string {
+	sum := sha256.Sum256([]byte(credential))
+	return provider + ":" + hex.EncodeToString(sum[:6])
+}
+
+// Stats are the queue and wait metrics of a limiter
+type Stats struct {
+	Key         string  `json:"key"`
+	QPS         float64 `json:"qps"`
+	Burst       int     `json:"burst"`
+	Calls       int64   `json:"calls"`       // Calls let through
+	Delayed     int64   `json:"delayed"`     // Calls that had to wait
+	Rejected    int64   `json:"rejected"`    // Calls refused because the wait exceeded the max wait
+	Waiting     int64   `json:"waiting"`     // Calls waiting now (queue length)
+	TotalWaitMs int64   `json:"totalWaitMs"` // Summed over all calls
+	MaxWaitMs   int64   `json:"maxWaitMs"`   // Longest wait so far
+	AvgWaitMs   float64 `json:"avgWaitMs"`   // Average wait of the delayed calls
+	Errors      int64   `json:"errors"`      // Bucket errors; calls go through unthrottled meanwhile
+}
+
+// Limiter throttles the calls made with one provider credential
+type Limiter struct {
+	bucket  Bucket
+	key     string
+	rate    float64
+	burst   int
+	maxWait time.Duration
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// NewLimiter creates a limiter for key allowing rate calls per second with bursts of burst.
+// A call waits at most maxWait for its slot. rate <= 0 disables limiting (returns nil).
+func NewLimiter(bucket Bucket, key string, rate float64, burst int, maxWait time.Duration) *Limiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		bucket:  bucket,
+		key:     key,
+		rate:    rate,
+		burst:   burst,
+		maxWait: maxWait,
+		stats:   Stats{Key: key, QPS: rate, Burst: burst},
+	}
+}
+
+// Wait blocks until the call may be made. It returns a throttled provider error when the
+// queue is longer than maxWait, and ctx's error when ctx ends first. A nil limiter never
+// waits, and neither does a limiter whose bucket fails: provider calls must not depend on Redis.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	wait, ok, err := l.bucket.Reserve(ctx, l.key, l.rate, l.burst, l.maxWait)
+	if err != nil {
+		logger.WarnCtx(ctx, "rate limiter %s unavailable, call not throttled: %v", l.key, err)
+		l.record(func(s *Stats) { s.Errors++; s.Calls++ })
+		return nil
+	}
+	if !ok {
+		l.record(func(s *Stats) { s.Rejected++ })
+		return interfaces.NewProviderError(interfaces.ErrThrottled,
+			fmt.Errorf("rate limit %s: next call slot is %v away (max wait %v)", l.key, wait.Round(time.Millisecond), l.maxWait))
+	}
+	if wait <= 0 {
+		l.record(func(s *Stats) { s.Calls++ })
+		return nil
+	}
+
+	l.record(func(s *Stats) { s.Waiting++ })
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// The booked slot is not given back; it only delays later calls by one interval
+		l.record(func(s *Stats) { s.Waiting-- })
+		return ctx.Err()
+	case <-timer.C:
+	}
+	l.record(func(s *Stats) {
+		s.Waiting--
+		s.Calls++
+		s.Delayed++
+		s.TotalWaitMs += wait.Milliseconds()
+		s.MaxWaitMs = max(s.MaxWaitMs, wait.Milliseconds())
+	})
+	return nil
+}
+
+// Stats returns the limiter's metrics
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := l.stats
+	if stats.Delayed > 0 {
+		stats.AvgWaitMs = float64(stats.TotalWaitMs) / float64(stats.Delayed)
+	}
+	return stats
+}
+
+func (l *Limiter) record(update func(*Stats)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	update(&l.stats)
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"waverless/pkg/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBucketReserve(t *testing.T) {
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	b := NewMemoryBucket()
+	b.now = func() time.Time { return now }
+
+	// A burst of 3 goes through, then calls are spaced 500ms apart
+	for i := 0; i < 3; i++ {
+		wait, ok, err := b.Reserve(context.Background(), "k", 2, 3, time.Second)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Zero(t, wait)
+	}
+	wait, ok, _ := b.Reserve(context.Background(), "k", 2, 3, time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+	wait, ok, _ = b.Reserve(context.Background(), "k", 2, 3, time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	// Past the max wait nothing is booked
+	wait, ok, _ = b.Reserve(context.Background(), "k", 2, 3, time.Second)
+	assert.False(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, wait)
+
+	// Other keys have their own bucket, and slots come back over time
+	_, ok, _ = b.Reserve(context.Background(), "other", 2, 3, 0)
+	assert.True(t, ok)
+	now = now.Add(2 * time.Second)
+	wait, ok, _ = b.Reserve(context.Background(), "k", 2, 3, 0)
+	assert.True(t, ok)
+	assert.Zero(t, wait)
+}
+
+func TestLimiterWait(t *testing.T) {
+	l := NewLimiter(NewMemoryBucket(), "novita:test", 50, 1, 30*time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond) // Two 20ms slots, minus timer slack
+
+	stats := l.Stats()
+	assert.Equal(t, int64(3), stats.Calls)
+	assert.Equal(t, int64(2), stats.Delayed)
+	assert.Zero(t, stats.Waiting)
+	assert.Positive(t, stats.AvgWaitMs)
+}
+
+func TestLimiterRejects(t *testing.T) {
+	l := NewLimiter(NewMemoryBucket(), "novita:test", 1, 1, 0)
+	require.NoError(t, l.Wait(context.Background()))
+
+	err := l.Wait(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, interfaces.ErrThrottled))
+	assert.Equal(t, int64(1), l.Stats().Rejected)
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	var l *Limiter = NewLimiter(NewMemoryBucket(), "k", 0, 1, 0)
+	assert.Nil(t, l)
+	assert.NoError(t, l.Wait(context.Background()))
+}
+
+func TestKey(t *testing.T) {
+	key := Key("novita", "sk-secret")
+	assert.Equal(t, key, Key("novita", "sk-secret"))
+	assert.NotEqual(t, key, Key("novita", "sk-other"))
+	assert.NotContains(t, key, "secret")
+}
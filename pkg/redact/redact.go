@@ -0,0 +1,128 @@
+// Package redact masks sensitive data (prompts with emails, phone numbers, tokens) in worker
+// log lines before they are returned by the log APIs or shipped to external sinks.
+//
+// A rule either names a builtin pattern or carries its own RE2 regular expression:
+//
+//	email        e-mail addresses
+//	phone        international and North American phone numbers
+//	credit_card  13-19 digit card numbers, optionally grouped
+//	ipv4         IPv4 addresses
+//	bearer_token "Bearer <token>" credentials
+//	api_key      key=value / key: value pairs named like api_key, token, secret, password
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Limits keep a rule set cheap enough to run on every log line
+const (
+	MaxRules         = 50
+	MaxPatternLength = 512
+)
+
+// Builtin patterns
+var builtins = map[string]string{
+	"email":        `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	"phone":        `(?:\+\d{1,3}[ .-]?)?(?:\(\d{3}\)|\d{3})[ .-]?\d{3}[ .-]?\d{4}\b`,
+	"credit_card":  `\b(?:\d[ -]?){12,18}\d\b`,
+	"ipv4":         `\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`,
+	"bearer_token": `(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`,
+	"api_key":      `(?i)\b(?:api[_-]?key|access[_-]?token|token|secret|password)\b["']?\s*[:=]\s*["']?[^\s"',}]+`,
+}
+
+// Builtins returns the names of the builtin patterns
+func Builtins() []string {
+	return []string{"email", "phone", "credit_card", "ipv4", "bearer_token", "api_key"}
+}
+
+// Rule masks every match of a pattern
+type Rule struct {
+	Name        string `json:"name"`
+	Builtin     string `json:"builtin,omitempty"`     // Builtin pattern name (or set Pattern)
+	Pattern     string `json:"pattern,omitempty"`     // RE2 regular expression
+	Replacement string `json:"replacement,omitempty"` // Default "[REDACTED:<name>]"; may reference groups ($1)
+}
+
+// Validate checks a rule set
+func Validate(rules []Rule) error {
+	_, err := Compile(rules)
+	return err
+}
+
+type compiledRule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Redactor applies a compiled rule set. A nil Redactor leaves lines unchanged.
+type Redactor struct {
+	rules []compiledRule
+}
+
+// Compile validates and compiles a rule set
+func Compile(rules []Rule) (*Redactor, error) {
+	if len(rules) > MaxRules {
+		return nil, fmt.Errorf("at most %d redaction rules are allowed", MaxRules)
+	}
+	seen := make(map[string]bool, len(rules))
+	r := &Redactor{rules: make([]compiledRule, 0, len(rules))}
+	for i, rule := range rules {
+		name := strings.TrimSpace(rule.Name)
+		if name == "" {
+			return nil, fmt.Errorf("rules[%d]: name is required", i)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("rules[%d]: duplicate rule name %q", i, name)
+		}
+		seen[name] = true
+
+		pattern := rule.Pattern
+		switch {
+		case rule.Builtin != "" && rule.Pattern != "":
+			return nil, fmt.Errorf("rule %s: set either builtin or pattern, not both", name)
+		case rule.Builtin != "":
+			p, ok := builtins[rule.Builtin]
+			if !ok {
+				return nil, fmt.Errorf("rule %s: unknown builtin %q (supported: %s)", name, rule.Builtin, strings.Join(Builtins(), ", "))
+			}
+			pattern = p
+		case pattern == "":
+			return nil, fmt.Errorf("rule %s: builtin or pattern is required", name)
+		case len(pattern) > MaxPatternLength:
+			return nil, fmt.Errorf("rule %s: pattern longer than %d characters", name, MaxPatternLength)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: invalid pattern: %w", name, err)
+		}
+		if re.MatchString("") {
+			return nil, fmt.Errorf("rule %s: pattern matches the empty string", name)
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = fmt.Sprintf("[REDACTED:%s]", name)
+		}
+		r.rules = append(r.rules, compiledRule{re: re, replacement: replacement})
+	}
+	return r, nil
+}
+
+// Empty reports whether the redactor has no rules
+func (r *Redactor) Empty() bool {
+	return r == nil || len(r.rules) == 0
+}
+
+// Redact masks every rule match in s, rules applied in order
+func (r *Redactor) Redact(s string) string {
+	if r.Empty() {
+		return s
+	}
+	for _, rule := range r.rules {
+		s = rule.re.ReplaceAllString(s, rule.replacement)
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact_Builtins(t *testing.T) {
+	r, err := Compile([]Rule{
+		{Name: "email", Builtin: "email"},
+		{Name: "phone", Builtin: "phone"},
+		{Name: "card", Builtin: "credit_card"},
+		{Name: "bearer", Builtin: "bearer_token"},
+		{Name: "keys", Builtin: "api_key"},
+		{Name: "ip", Builtin: "ipv4"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "prompt from [REDACTED:email]: a cat", r.Redact("prompt from jane.doe@example.com: a cat"))
+	assert.Equal(t, "call [REDACTED:phone] now", r.Redact("call +1 415-555-0123 now"))
+	assert.Equal(t, "card [REDACTED:card] ok", r.Redact("card 4111 1111 1111 1111 ok"))
+	assert.Equal(t, "Authorization: [REDACTED:bearer]", r.Redact("Authorization: Bearer eyJhbGciOi.abc-def"))
+	assert.Equal(t, `{"[REDACTED:keys]", "steps": 20}`, r.Redact(`{"api_key": "sk-123", "steps": 20}`))
+	assert.Equal(t, "client [REDACTED:ip] connected", r.Redact("client 10.0.12.7 connected"))
+	assert.Equal(t, "step 12/20 done in 1.5s", r.Redact("step 12/20 done in 1.5s"))
+}
+
+func TestRedact_CustomPattern(t *testing.T) {
+	r, err := Compile([]Rule{
+		{Name: "prompt", Pattern: `("prompt":\s*)"[^"]*"`, Replacement: `$1"***"`},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{"prompt": "***", "seed": 1}`, r.Redact(`{"prompt": "portrait of John Smith", "seed": 1}`))
+}
+
+func TestRedact_NilAndEmpty(t *testing.T) {
+	var r *Redactor
+	assert.True(t, r.Empty())
+	assert.Equal(t, "a@b.io", r.Redact("a@b.io"))
+
+	r, err := Compile(nil)
+	require.NoError(t, err)
+	assert.True(t, r.Empty())
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	cases := map[string][]Rule{
+		"missing name":       {{Builtin: "email"}},
+		"duplicate name":     {{Name: "a", Builtin: "email"}, {Name: "a", Builtin: "phone"}},
+		"unknown builtin":    {{Name: "a", Builtin: "ssn"}},
+		"builtin and regexp": {{Name: "a", Builtin: "email", Pattern: "x"}},
+		"no pattern":         {{Name: "a"}},
+		"bad regexp":         {{Name: "a", Pattern: "("}},
+		"matches empty":      {{Name: "a", Pattern: "x*"}},
+	}
+	for name, rules := range cases {
+		_, err := Compile(rules)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,84 @@
+package resource
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthChangeEvent describes an endpoint health status transition.
+type HealthChangeEvent struct {
+	Endpoint       string    `json:"endpoint"`
+	PreviousStatus string    `json:"previousStatus"`
+	Status         string    `json:"status"`
+	Reason         string    `json:"reason,omitempty"` // Health reason code, e.g. IMAGE_PULL_FAILED
+	Message        string    `json:"message,omitempty"`
+	Trigger        string    `json:"trigger"` // periodic, worker_failure, worker_recovered, worker_deleted, deployment_status
+	ChangedAt      time.Time `json:"changedAt"`
+}
+
+// Health recompute trigger reasons
+const (
+	HealthTriggerPeriodic         = "periodic"
+	HealthTriggerWorkerFailure    = "worker_failure"
+	HealthTriggerWorkerRecovered  = "worker_recovered"
+	HealthTriggerWorkerDeleted    = "worker_deleted"
+	HealthTriggerDeploymentStatus = "deployment_status"
+)
+
+// healthDebouncer coalesces bursts of recompute requests per endpoint.
+// The first request for an endpoint arms a timer; further requests within the
+// delay window are merged into the pending run, keeping the first trigger reason.
+type healthDebouncer struct {
+	delay   time.Duration
+	run     func(endpoint, trigger string)
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+func newHealthDebouncer(delay time.Duration, run func(endpoint, trigger string)) *healthDebouncer {
+	return &healthDebouncer{
+		delay:   delay,
+		run:     run,
+		pending: make(map[string]*time.Timer),
+	}
+}
+
+// Trigger schedules a recompute for the endpoint unless one is already pending.
+func (d *healthDebouncer) Trigger(endpoint, trigger string) {
+	if endpoint == "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.pending[endpoint]; ok {
+		return
+	}
+
+	d.pending[endpoint] = time.AfterFunc(d.delay, func() {
+		d.mu.Lock()
+		delete(d.pending, endpoint)
+		d.mu.Unlock()
+
+		d.run(endpoint, trigger)
+	})
+}
+
+// Stop cancels all pending recomputes.
+func (d *healthDebouncer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for endpoint, timer := range d.pending {
+		timer.Stop()
+		delete(d.pending, endpoint)
+	}
+}
+
+// Pending returns the number of endpoints with a scheduled recompute.
+func (d *healthDebouncer) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pending)
+}
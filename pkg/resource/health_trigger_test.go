@@ -0,0 +1,75 @@
+package resource
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHealthDebouncer_CoalescesPerEndpoint verifies bursts of triggers run once per endpoint.
+func TestHealthDebouncer_CoalescesPerEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	runs := make(map[string][]string)
+
+	d := newHealthDebouncer(20*time.Millisecond, func(endpoint, trigger string) {
+		mu.Lock()
+		defer mu.Unlock()
+		runs[endpoint] = append(runs[endpoint], trigger)
+	})
+
+	d.Trigger("ep-a", HealthTriggerWorkerFailure)
+	d.Trigger("ep-a", HealthTriggerDeploymentStatus)
+	d.Trigger("ep-a", HealthTriggerWorkerDeleted)
+	d.Trigger("ep-b", HealthTriggerWorkerRecovered)
+	d.Trigger("", HealthTriggerWorkerFailure)
+	assert.Equal(t, 2, d.Pending())
+
+	assert.Eventually(t, func() bool { return d.Pending() == 0 }, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{HealthTriggerWorkerFailure}, runs["ep-a"])
+	assert.Equal(t, []string{HealthTriggerWorkerRecovered}, runs["ep-b"])
+	assert.Len(t, runs, 2)
+}
+
+// TestHealthDebouncer_RetriggerAfterRun verifies a new trigger after a run schedules again.
+func TestHealthDebouncer_RetriggerAfterRun(t *testing.T) {
+	var mu sync.Mutex
+	count := 0
+
+	d := newHealthDebouncer(5*time.Millisecond, func(endpoint, trigger string) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+
+	d.Trigger("ep", HealthTriggerWorkerFailure)
+	assert.Eventually(t, func() bool { return d.Pending() == 0 }, time.Second, time.Millisecond)
+	d.Trigger("ep", HealthTriggerWorkerRecovered)
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return count == 2
+	}, time.Second, time.Millisecond)
+}
+
+// TestHealthDebouncer_Stop verifies pending recomputes are cancelled.
+func TestHealthDebouncer_Stop(t *testing.T) {
+	called := make(chan struct{}, 1)
+	d := newHealthDebouncer(50*time.Millisecond, func(endpoint, trigger string) {
+		called <- struct{}{}
+	})
+
+	d.Trigger("ep", HealthTriggerWorkerFailure)
+	d.Stop()
+	assert.Equal(t, 0, d.Pending())
+
+	select {
+	case <-called:
+		t.Fatal("recompute should not run after Stop")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
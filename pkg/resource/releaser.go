@@ -31,14 +31,25 @@ type ResourceReleaserConfig struct {
 	// MaxRetries is the maximum number of termination retries before giving up.
 	// Default: 3
 	MaxRetries int `yaml:"maxRetries"`
+
+	// HealthRecomputeDebounce is the delay used to coalesce event-triggered
+	// health recomputes (worker failure/recovery, deployment status changes).
+	// Default: 2 seconds
+	HealthRecomputeDebounce time.Duration `yaml:"healthRecomputeDebounce"`
+
+	// AvoidUnhealthyGPUNodes keeps new workers off the node of a worker replaced for a
+	// failed GPU health check (requires a provider implementing NodeAvoider).
+	// Default: false
+	AvoidUnhealthyGPUNodes bool `yaml:"avoidUnhealthyGPUNodes"`
 }
 
 // DefaultResourceReleaserConfig returns the default configuration for ResourceReleaser.
 func DefaultResourceReleaserConfig() *ResourceReleaserConfig {
 	return &ResourceReleaserConfig{
-		ImagePullTimeout: 5 * time.Minute,
-		CheckInterval:    30 * time.Second,
-		MaxRetries:       3,
+		ImagePullTimeout:        5 * time.Minute,
+		CheckInterval:           30 * time.Second,
+		MaxRetries:              3,
+		HealthRecomputeDebounce: 2 * time.Second,
 	}
 }
 
@@ -46,6 +57,7 @@ func DefaultResourceReleaserConfig() *ResourceReleaserConfig {
 type failedWorkerInfo struct {
 	firstFailureTime time.Time
 	retryCount       int
+	terminated       bool // GPU unhealthy worker whose pod is being replaced
 }
 
 // ResourceReleaser monitors workers with image pull failures and releases resources
@@ -73,6 +85,15 @@ type ResourceReleaser struct {
 
 	// running indicates if the releaser is currently running
 	running bool
+
+	// baseCtx is the context passed to Start, used by debounced health recomputes
+	baseCtx context.Context
+
+	// healthDebouncer coalesces event-triggered health recomputes per endpoint
+	healthDebouncer *healthDebouncer
+
+	// onHealthChange is invoked when an endpoint's health status transitions
+	onHealthChange func(ctx context.Context, event *HealthChangeEvent)
 }
 
 // NewResourceReleaser creates a new ResourceReleaser with the given dependencies.
@@ -94,13 +115,61 @@ func NewResourceReleaser(
 	if config == nil {
 		config = DefaultResourceReleaserConfig()
 	}
+	debounce := config.HealthRecomputeDebounce
+	if debounce <= 0 {
+		debounce = DefaultResourceReleaserConfig().HealthRecomputeDebounce
+	}
 
-	return &ResourceReleaser{
+	r := &ResourceReleaser{
 		deployProvider: deployProvider,
 		workerRepo:     workerRepo,
 		endpointRepo:   endpointRepo,
 		config:         config,
 	}
+	r.healthDebouncer = newHealthDebouncer(debounce, r.recomputeHealth)
+	return r
+}
+
+// SetHealthChangeHandler registers a callback invoked whenever an endpoint's
+// health status changes (e.g. HEALTHY -> DEGRADED). The callback runs synchronously
+// in the recompute path and should not block.
+func (r *ResourceReleaser) SetHealthChangeHandler(handler func(ctx context.Context, event *HealthChangeEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onHealthChange = handler
+}
+
+// TriggerHealthRecompute schedules an immediate (debounced) health recompute for an endpoint.
+// It is called from worker failure/recovery and deployment status events so that health
+// reflects reality without waiting for the next periodic check.
+func (r *ResourceReleaser) TriggerHealthRecompute(endpoint, trigger string) {
+	r.healthDebouncer.Trigger(endpoint, trigger)
+}
+
+// recomputeHealth is the debounced recompute callback.
+func (r *ResourceReleaser) recomputeHealth(endpoint, trigger string) {
+	r.mu.RLock()
+	ctx := r.baseCtx
+	r.mu.RUnlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	logger.Debug("Recomputing endpoint health status",
+		zap.String("endpoint", endpoint),
+		zap.String("trigger", trigger),
+	)
+
+	if err := r.updateEndpointHealthStatus(ctx, endpoint, trigger); err != nil {
+		logger.Error("Failed to recompute endpoint health status",
+			zap.String("endpoint", endpoint),
+			zap.String("trigger", trigger),
+			zap.Error(err),
+		)
+	}
 }
 
 // Start starts the resource releaser background job.
@@ -119,6 +188,7 @@ func (r *ResourceReleaser) Start(ctx context.Context) {
 		return
 	}
 	r.running = true
+	r.baseCtx = ctx
 	r.mu.Unlock()
 
 	logger.Info("ResourceReleaser started",
@@ -139,6 +209,7 @@ func (r *ResourceReleaser) Start(ctx context.Context) {
 			r.mu.Lock()
 			r.running = false
 			r.mu.Unlock()
+			r.healthDebouncer.Stop()
 			logger.Info("ResourceReleaser stopped")
 			return
 		case <-ticker.C:
@@ -176,6 +247,19 @@ func (r *ResourceReleaser) CheckAndRelease(ctx context.Context) {
 		return
 	}
 
+	// Workers whose GPU health check failed are replaced right away; they do not count
+	// against the endpoint's health (the GPU, not the image, is at fault)
+	gpuUnhealthyWorkers, err := r.workerRepo.GetWorkersByFailureType(ctx, string(interfaces.FailureTypeGPUUnhealthy))
+	if err != nil {
+		logger.Error("Failed to get workers with unhealthy GPUs",
+			zap.Error(err),
+		)
+	} else {
+		for _, worker := range gpuUnhealthyWorkers {
+			r.replaceGPUUnhealthyWorker(ctx, worker)
+		}
+	}
+
 	// Combine both types of failed workers
 	workers := append(imagePullWorkers, containerCrashWorkers...)
 
@@ -302,7 +386,7 @@ func (r *ResourceReleaser) terminateWorker(ctx context.Context, worker *model.Wo
 	}
 
 	// Check if the provider supports worker termination
-	terminator, ok := r.deployProvider.(interfaces.WorkerTerminator)
+	terminator, ok := interfaces.ResolveProvider(ctx, r.deployProvider, worker.Endpoint, "").(interfaces.WorkerTerminator)
 	if !ok {
 		logger.Warn("Deploy provider does not support worker termination",
 			zap.String("workerID", worker.WorkerID),
@@ -354,6 +438,66 @@ func (r *ResourceReleaser) terminateWorker(ctx context.Context, worker *model.Wo
 	r.failedWorkers.Delete(worker.WorkerID)
 }
 
+// replaceGPUUnhealthyWorker terminates the pod of a worker whose GPU health check failed, so
+// that its deployment replaces it, and keeps new workers off its node when configured. The
+// worker is already DRAINING, so it gets no new tasks while its pod shuts down gracefully.
+func (r *ResourceReleaser) replaceGPUUnhealthyWorker(ctx context.Context, worker *model.Worker) {
+	occurredAt := time.Now()
+	if worker.FailureOccurredAt != nil {
+		occurredAt = *worker.FailureOccurredAt
+	}
+	info := r.getOrCreateFailedWorkerInfo(worker.WorkerID, occurredAt)
+	if info.terminated || info.retryCount >= r.config.MaxRetries {
+		return
+	}
+
+	terminator, ok := interfaces.ResolveProvider(ctx, r.deployProvider, worker.Endpoint, "").(interfaces.WorkerTerminator)
+	if !ok {
+		logger.Warn("Deploy provider does not support worker termination, GPU unhealthy worker is only drained",
+			zap.String("workerID", worker.WorkerID),
+		)
+		info.terminated = true
+		r.failedWorkers.Store(worker.WorkerID, info)
+		return
+	}
+
+	node := worker.NodeName()
+	if r.config.AvoidUnhealthyGPUNodes && node != "" {
+		if avoider, ok := interfaces.ResolveProvider(ctx, r.deployProvider, worker.Endpoint, "").(interfaces.NodeAvoider); ok {
+			if err := avoider.AvoidNode(ctx, node, worker.FailureReason); err != nil {
+				logger.Error("Failed to avoid node of GPU unhealthy worker",
+					zap.String("workerID", worker.WorkerID),
+					zap.String("node", node),
+					zap.Error(err),
+				)
+			} else {
+				logger.Info("Avoiding node of GPU unhealthy worker for new workers",
+					zap.String("workerID", worker.WorkerID),
+					zap.String("node", node),
+				)
+			}
+		}
+	}
+
+	logger.Info("Replacing worker with unhealthy GPU",
+		zap.String("workerID", worker.WorkerID),
+		zap.String("endpoint", worker.Endpoint),
+		zap.String("node", node),
+		zap.String("reason", worker.FailureReason),
+	)
+	if err := terminator.TerminateWorker(ctx, worker.Endpoint, worker.WorkerID, "GPU_UNHEALTHY: "+worker.FailureReason); err != nil {
+		logger.Error("Failed to terminate GPU unhealthy worker",
+			zap.String("workerID", worker.WorkerID),
+			zap.String("endpoint", worker.Endpoint),
+			zap.Error(err),
+		)
+		info.retryCount++
+	} else {
+		info.terminated = true
+	}
+	r.failedWorkers.Store(worker.WorkerID, info)
+}
+
 // updateWorkerTimeoutStatus updates the worker's failure type to TIMEOUT.
 func (r *ResourceReleaser) updateWorkerTimeoutStatus(ctx context.Context, worker *model.Worker) {
 	// Use time.Now() for consistency with how GORM stores time
@@ -383,7 +527,8 @@ func (r *ResourceReleaser) updateWorkerTimeoutStatus(ctx context.Context, worker
 //   - If F = N (all workers failed), health_status is "UNHEALTHY"
 //
 // Failed workers include both IMAGE_PULL_FAILED and CONTAINER_CRASH types.
-// The health_message will use the worker's failure_reason for more intuitive error display.
+// The health_message will use the worker's failure_reason for more intuitive error display,
+// while health_reason records the failure type code that callers branch on.
 //
 // When status becomes UNHEALTHY due to image or container issues, this method also scales down
 // the deployment to 0 replicas to prevent K8s from creating new pods that will fail.
@@ -398,6 +543,23 @@ func (r *ResourceReleaser) updateWorkerTimeoutStatus(ctx context.Context, worker
 //
 // Validates: Requirements 5.4, 5.5, 6.4
 func (r *ResourceReleaser) UpdateEndpointHealthStatus(ctx context.Context, endpoint string) error {
+	return r.updateEndpointHealthStatus(ctx, endpoint, HealthTriggerPeriodic)
+}
+
+// updateEndpointHealthStatus derives and persists endpoint health, emitting a
+// HealthChangeEvent when the status differs from the stored one.
+func (r *ResourceReleaser) updateEndpointHealthStatus(ctx context.Context, endpoint, trigger string) error {
+	// Load current status to detect transitions (best effort)
+	var previousStatus string
+	if current, err := r.endpointRepo.Get(ctx, endpoint); err != nil {
+		logger.Warn("Failed to load endpoint before health update",
+			zap.String("endpoint", endpoint),
+			zap.Error(err),
+		)
+	} else if current != nil {
+		previousStatus = current.HealthStatus
+	}
+
 	// Get all active workers for this endpoint (excludes OFFLINE)
 	workers, err := r.workerRepo.GetByEndpoint(ctx, endpoint)
 	if err != nil {
@@ -408,6 +570,7 @@ func (r *ResourceReleaser) UpdateEndpointHealthStatus(ctx context.Context, endpo
 	totalWorkers := len(workers)
 	failedWorkers := 0
 	var firstFailureReason string
+	healthReason := model.HealthReasonNone
 	for _, w := range workers {
 		if w.FailureType == string(interfaces.FailureTypeImagePull) ||
 			w.FailureType == string(interfaces.FailureTypeContainerCrash) {
@@ -416,6 +579,10 @@ func (r *ResourceReleaser) UpdateEndpointHealthStatus(ctx context.Context, endpo
 			if firstFailureReason == "" && w.FailureReason != "" {
 				firstFailureReason = w.FailureReason
 			}
+			// Image failures take precedence: they block scale-up until the image changes
+			if healthReason != model.HealthReasonImagePullFailed {
+				healthReason = model.HealthReason(w.FailureType)
+			}
 		}
 	}
 
@@ -448,10 +615,22 @@ func (r *ResourceReleaser) UpdateEndpointHealthStatus(ctx context.Context, endpo
 	}
 
 	// Update endpoint health status in database
-	if err := r.endpointRepo.UpdateHealthStatus(ctx, endpoint, string(healthStatus), healthMessage); err != nil {
+	if err := r.endpointRepo.UpdateHealthStatus(ctx, endpoint, string(healthStatus), healthReason, healthMessage); err != nil {
 		return err
 	}
 
+	if previousStatus != "" && previousStatus != string(healthStatus) {
+		r.emitHealthChange(ctx, &HealthChangeEvent{
+			Endpoint:       endpoint,
+			PreviousStatus: previousStatus,
+			Status:         string(healthStatus),
+			Reason:         string(healthReason),
+			Message:        healthMessage,
+			Trigger:        trigger,
+			ChangedAt:      time.Now(),
+		})
+	}
+
 	// Property 8: When endpoint becomes UNHEALTHY, scale down to 0 to prevent K8s from creating new pods
 	// This is necessary because K8s Deployment controller will automatically create new pods
 	// when existing pods are terminated, bypassing the Autoscaler's blocking logic.
@@ -484,6 +663,24 @@ func (r *ResourceReleaser) UpdateEndpointHealthStatus(ctx context.Context, endpo
 	return nil
 }
 
+// emitHealthChange logs the transition and invokes the registered handler.
+func (r *ResourceReleaser) emitHealthChange(ctx context.Context, event *HealthChangeEvent) {
+	logger.Info("Endpoint health status changed",
+		zap.String("endpoint", event.Endpoint),
+		zap.String("from", event.PreviousStatus),
+		zap.String("to", event.Status),
+		zap.String("trigger", event.Trigger),
+		zap.String("message", event.Message),
+	)
+
+	r.mu.RLock()
+	handler := r.onHealthChange
+	r.mu.RUnlock()
+	if handler != nil {
+		handler(ctx, event)
+	}
+}
+
 // cleanupTrackedWorkers removes workers from tracking that are no longer in failed state.
 func (r *ResourceReleaser) cleanupTrackedWorkers(ctx context.Context) {
 	r.failedWorkers.Range(func(key, value interface{}) bool {
@@ -497,9 +694,16 @@ func (r *ResourceReleaser) cleanupTrackedWorkers(ctx context.Context) {
 			return true
 		}
 
-		// Worker is no longer in IMAGE_PULL_FAILED or CONTAINER_CRASH state, remove from tracking
+		// Worker's pod is gone, remove from tracking
+		if worker.Status == "OFFLINE" {
+			r.failedWorkers.Delete(workerID)
+			return true
+		}
+
+		// Worker is no longer in IMAGE_PULL_FAILED, CONTAINER_CRASH or GPU_UNHEALTHY state, remove from tracking
 		if worker.FailureType != string(interfaces.FailureTypeImagePull) &&
-			worker.FailureType != string(interfaces.FailureTypeContainerCrash) {
+			worker.FailureType != string(interfaces.FailureTypeContainerCrash) &&
+			worker.FailureType != string(interfaces.FailureTypeGPUUnhealthy) {
 			r.failedWorkers.Delete(workerID)
 		}
 
@@ -143,6 +143,7 @@ type mockDeployProvider struct {
 	interfaces.DeploymentProvider                   // Embed interface to satisfy all methods
 	terminatedWorkers             map[string]string // workerID -> reason
 	scaledEndpoints               map[string]int    // endpoint -> replicas
+	avoidedNodes                  map[string]string // node -> reason
 	mu                            sync.RWMutex
 }
 
@@ -171,6 +172,17 @@ func (m *mockDeployProvider) UpdateDeployment(ctx context.Context, req *interfac
 	return &interfaces.DeployResponse{Endpoint: req.Endpoint}, nil
 }
 
+// AvoidNode implements the NodeAvoider interface for testing.
+func (m *mockDeployProvider) AvoidNode(ctx context.Context, node, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.avoidedNodes == nil {
+		m.avoidedNodes = make(map[string]string)
+	}
+	m.avoidedNodes[node] = reason
+	return nil
+}
+
 func (m *mockDeployProvider) WasTerminated(workerID string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -496,6 +508,44 @@ func TestResourceReleaser_TerminateWorker_Success(t *testing.T) {
 	assert.True(t, provider.WasTerminated("worker1"))
 }
 
+// TestResourceReleaser_ReplaceGPUUnhealthyWorker tests that GPU unhealthy workers are replaced
+// right away, once, and that their node is avoided only when configured.
+func TestResourceReleaser_ReplaceGPUUnhealthyWorker(t *testing.T) {
+	failureTime := time.Now()
+	newWorker := func(id, node string) *model.Worker {
+		return &model.Worker{
+			WorkerID:          id,
+			PodName:           id,
+			Endpoint:          "test-endpoint",
+			FailureType:       string(interfaces.FailureTypeGPUUnhealthy),
+			FailureReason:     "GPU health check failed: 2 uncorrectable ECC errors on GPU 0",
+			FailureOccurredAt: &failureTime,
+			RuntimeState:      model.JSONMap{"nodeName": node},
+		}
+	}
+	ctx := context.Background()
+
+	provider := newMockDeployProvider()
+	releaser := NewResourceReleaser(provider, nil, nil, &ResourceReleaserConfig{MaxRetries: 3})
+	releaser.replaceGPUUnhealthyWorker(ctx, newWorker("worker1", "gpu-node-1"))
+
+	assert.True(t, provider.WasTerminated("worker1"), "no timeout applies to GPU unhealthy workers")
+	assert.Contains(t, provider.GetTerminationReason("worker1"), "GPU_UNHEALTHY")
+	assert.Empty(t, provider.avoidedNodes, "nodes are only avoided when configured")
+
+	// Already replaced: not terminated again while its pod shuts down
+	delete(provider.terminatedWorkers, "worker1")
+	releaser.replaceGPUUnhealthyWorker(ctx, newWorker("worker1", "gpu-node-1"))
+	assert.False(t, provider.WasTerminated("worker1"))
+
+	provider = newMockDeployProvider()
+	releaser = NewResourceReleaser(provider, nil, nil, &ResourceReleaserConfig{MaxRetries: 3, AvoidUnhealthyGPUNodes: true})
+	releaser.replaceGPUUnhealthyWorker(ctx, newWorker("worker2", "gpu-node-2"))
+
+	assert.True(t, provider.WasTerminated("worker2"))
+	assert.Contains(t, provider.avoidedNodes, "gpu-node-2")
+}
+
 // TestResourceReleaser_ProviderWithoutTerminator tests handling of providers without termination support.
 func TestResourceReleaser_ProviderWithoutTerminator(t *testing.T) {
 	// Create a provider that doesn't implement WorkerTerminator
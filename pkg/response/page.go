@@ -0,0 +1,109 @@
+// Package response holds the shapes shared by client-facing API responses: the pagination
+// envelope of list responses and RFC3339 timestamps.
+package response
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultLimit is the page size when the request doesn't set one
+	DefaultLimit = 50
+	// MaxLimit caps the page size a request may ask for
+	MaxLimit = 500
+
+	cursorPrefix = "o:"
+)
+
+// Page is the envelope of every list response: {items, nextCursor, total?}
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"nextCursor"`      // Cursor of the next page, null on the last page
+	Total      *int64  `json:"total,omitempty"` // Item count across all pages, when it is cheap to know
+}
+
+// PageRequest is the page a list request asks for
+type PageRequest struct {
+	Limit  int
+	Offset int
+}
+
+// ParsePageRequest parses the limit and cursor query parameters of a list request. An empty
+// limit means DefaultLimit; limits above MaxLimit are capped.
+func ParsePageRequest(limit, cursor string) (PageRequest, error) {
+	req := PageRequest{Limit: DefaultLimit}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return req, fmt.Errorf("invalid limit %q: must be a positive integer", limit)
+		}
+		req.Limit = min(n, MaxLimit)
+	}
+	if cursor != "" {
+		offset, err := DecodeCursor(cursor)
+		if err != nil {
+			return req, err
+		}
+		req.Offset = offset
+	}
+	return req, nil
+}
+
+// EncodeCursor returns the opaque cursor of the page starting at offset
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeCursor returns the offset of a cursor made by EncodeCursor
+func DecodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return offset, nil
+}
+
+// NewPage builds the page of items fetched at req.Offset. With a total, the next cursor is set
+// while items remain past this page. Without one, the caller fetches up to req.Limit+1 items:
+// the extra item is dropped and only tells that another page exists.
+func NewPage[T any](items []T, req PageRequest, total *int64) Page[T] {
+	hasMore := false
+	if total != nil {
+		hasMore = int64(req.Offset+len(items)) < *total
+	} else if len(items) > req.Limit {
+		items, hasMore = items[:req.Limit], true
+	}
+	if items == nil {
+		items = []T{}
+	}
+	page := Page[T]{Items: items, Total: total}
+	if hasMore {
+		next := EncodeCursor(req.Offset + len(items))
+		page.NextCursor = &next
+	}
+	return page
+}
+
+// Slice pages a list that is already in memory
+func Slice[T any](all []T, req PageRequest) Page[T] {
+	total := int64(len(all))
+	start := min(req.Offset, len(all))
+	end := min(start+req.Limit, len(all))
+	return NewPage(all[start:end], req, &total)
+}
+
+// Map converts the items of a page, keeping its cursor and total
+func Map[T, U any](page Page[T], convert func(T) U) Page[U] {
+	items := make([]U, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = convert(item)
+	}
+	return Page[U]{Items: items, NextCursor: page.NextCursor, Total: page.Total}
+}
@@ -0,0 +1,88 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	req, err := ParsePageRequest("", "")
+	require.NoError(t, err)
+	assert.Equal(t, PageRequest{Limit: DefaultLimit}, req)
+
+	req, err = ParsePageRequest("10", EncodeCursor(30))
+	require.NoError(t, err)
+	assert.Equal(t, PageRequest{Limit: 10, Offset: 30}, req)
+
+	req, err = ParsePageRequest("100000", "")
+	require.NoError(t, err)
+	assert.Equal(t, MaxLimit, req.Limit)
+
+	for _, tc := range [][2]string{{"0", ""}, {"ten", ""}, {"", "not-a-cursor"}, {"", EncodeCursor(-1)}} {
+		_, err := ParsePageRequest(tc[0], tc[1])
+		assert.Error(t, err, "%v", tc)
+	}
+}
+
+func TestNewPage_WithTotal(t *testing.T) {
+	total := int64(5)
+	page := NewPage([]int{1, 2}, PageRequest{Limit: 2}, &total)
+	assert.Equal(t, []int{1, 2}, page.Items)
+	require.NotNil(t, page.NextCursor)
+	offset, err := DecodeCursor(*page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, 2, offset)
+
+	page = NewPage([]int{5}, PageRequest{Limit: 2, Offset: 4}, &total)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestNewPage_WithoutTotal(t *testing.T) {
+	// One item past the limit: another page exists
+	page := NewPage([]string{"a", "b", "c"}, PageRequest{Limit: 2, Offset: 4}, nil)
+	assert.Equal(t, []string{"a", "b"}, page.Items)
+	require.NotNil(t, page.NextCursor)
+	offset, _ := DecodeCursor(*page.NextCursor)
+	assert.Equal(t, 6, offset)
+
+	page = NewPage([]string(nil), PageRequest{Limit: 2}, nil)
+	assert.Equal(t, []string{}, page.Items)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestSliceAndJSON(t *testing.T) {
+	all := []int{1, 2, 3, 4, 5}
+	page := Slice(all, PageRequest{Limit: 2, Offset: 2})
+	assert.Equal(t, []int{3, 4}, page.Items)
+
+	data, err := json.Marshal(Map(Slice(all, PageRequest{Limit: 2, Offset: 4}), func(i int) int { return i * 10 }))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":[50],"nextCursor":null,"total":5}`, string(data))
+
+	data, err = json.Marshal(Slice(all, PageRequest{Limit: 2, Offset: 10}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":[],"nextCursor":null,"total":5}`, string(data))
+}
+
+func TestTimeJSON(t *testing.T) {
+	type item struct {
+		CreatedAt   Time `json:"createdAt"`
+		CompletedAt Time `json:"completedAt"`
+	}
+	created := time.Date(2026, 10, 16, 9, 30, 15, 123456789, time.FixedZone("CST", 8*3600))
+
+	data, err := json.Marshal(item{CreatedAt: NewTime(created), CompletedAt: NewTimePtr(nil)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"createdAt":"2026-10-16T01:30:15Z","completedAt":null}`, string(data))
+
+	var decoded item
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, time.Time(decoded.CreatedAt).Equal(created.Truncate(time.Second)))
+	assert.True(t, decoded.CompletedAt.IsZero())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"createdAt":"yesterday"}`), &decoded))
+}
@@ -0,0 +1,62 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Time is a timestamp that marshals as RFC3339 in UTC; the zero time marshals as null
+type Time time.Time
+
+// NewTime converts a time
+func NewTime(t time.Time) Time {
+	return Time(t)
+}
+
+// NewTimePtr converts an optional time (nil = null)
+func NewTimePtr(t *time.Time) Time {
+	if t == nil {
+		return Time{}
+	}
+	return Time(*t)
+}
+
+// IsZero reports whether the time is unset
+func (t Time) IsZero() bool {
+	return time.Time(t).IsZero()
+}
+
+// String formats the time as RFC3339 in UTC ("" for the zero time)
+func (t Time) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return time.Time(t).UTC().Format(time.RFC3339)
+}
+
+// MarshalJSON implements json.Marshaler
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Time) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil || *s == "" {
+		*t = Time{}
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", *s, err)
+	}
+	*t = Time(parsed)
+	return nil
+}
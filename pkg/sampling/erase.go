@@ -0,0 +1,55 @@
+package sampling
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+)
+
+// EraseSubject removes (or, with anonymize, blanks the payloads of) the samples of a data
+// subject in an uploaded gzip JSON lines object. It returns the rewritten object, the number
+// of samples erased and the number of samples left; remaining=0 means the object can be deleted.
+func EraseSubject(data []byte, subject string, anonymize bool) (out []byte, erased, remaining int, err error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid sample object: %w", err)
+	}
+	defer gz.Close()
+
+	var kept []*Sample
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024) // Samples may carry base64 images
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var sample Sample
+		if err := json.Unmarshal(line, &sample); err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid sample line: %w", err)
+		}
+		if subject != "" && sample.Subject == subject {
+			erased++
+			if !anonymize {
+				continue
+			}
+			sample.Input = nil
+			sample.Output = nil
+			sample.Error = ""
+			sample.Subject = ""
+		}
+		kept = append(kept, &sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to read sample object: %w", err)
+	}
+	if erased == 0 || len(kept) == 0 {
+		return nil, erased, len(kept), nil
+	}
+	if out, err = encodeJSONLinesGzip(kept); err != nil {
+		return nil, 0, 0, err
+	}
+	return out, erased, len(kept), nil
+}
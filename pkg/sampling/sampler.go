@@ -0,0 +1,249 @@
+// Package sampling copies input/output pairs of a fraction of finished tasks to a
+// datasets store (object storage) for offline quality evaluation. Samples pass
+// through PII scrubbers before they are buffered and uploaded as gzip JSON lines.
+package sampling
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"waverless/pkg/export"
+	"waverless/pkg/logger"
+)
+
+// Sample is one captured task
+type Sample struct {
+	TaskID      string                 `json:"taskId"`
+	Endpoint    string                 `json:"endpoint"`
+	WorkerID    string                 `json:"workerId,omitempty"`
+	Status      string                 `json:"status"`
+	Input       map[string]interface{} `json:"input"`
+	Output      map[string]interface{} `json:"output,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	Subject     string                 `json:"subject,omitempty"` // Hashed data subject key of the task
+	ExecutionMs int64                  `json:"executionMs"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	CompletedAt time.Time              `json:"completedAt"`
+}
+
+// Options tune the sample buffer
+type Options struct {
+	BatchSize     int           // Samples per uploaded object (default: 500)
+	FlushInterval time.Duration // Max time a sample stays buffered (default: 1m)
+	QueueSize     int           // Buffered samples before new ones are dropped (default: 10000)
+}
+
+// Stats are counters since startup
+type Stats struct {
+	Captured int64 `json:"captured"`
+	Dropped  int64 `json:"dropped"`  // Queue full
+	Uploaded int64 `json:"uploaded"` // Samples written to the store
+	Failed   int64 `json:"failed"`   // Samples lost to upload errors
+}
+
+// UploadHook is called with the samples of every successfully uploaded object
+type UploadHook func(ctx context.Context, key string, samples []*Sample)
+
+// Sampler scrubs captured samples and uploads them in batches, per endpoint.
+// Capture never blocks the caller: when the queue is full the sample is dropped.
+type Sampler struct {
+	store     export.ObjectStore
+	scrubbers []Scrubber
+	opts      Options
+	queue     chan *Sample
+	seq       atomic.Int64
+	onUpload  UploadHook
+
+	captured, dropped, uploaded, failed atomic.Int64
+
+	mu      sync.Mutex
+	started bool
+	done    chan struct{}
+}
+
+// NewSampler creates a sampler writing to store
+func NewSampler(store export.ObjectStore, opts Options, scrubbers ...Scrubber) *Sampler {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Minute
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 10000
+	}
+	return &Sampler{
+		store:     store,
+		scrubbers: scrubbers,
+		opts:      opts,
+		queue:     make(chan *Sample, opts.QueueSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// SetUploadHook sets the hook called after each uploaded object
+func (s *Sampler) SetUploadHook(hook UploadHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpload = hook
+}
+
+// AddScrubber registers a PII scrubbing hook, run after the built-in ones
+func (s *Sampler) AddScrubber(scrubber Scrubber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scrubbers = append(s.scrubbers, scrubber)
+}
+
+// Capture runs the rule-specific scrubbers, then the registered ones, and queues the sample.
+// Returns false if the sample was dropped because the queue is full.
+func (s *Sampler) Capture(sample *Sample, ruleScrubbers ...Scrubber) bool {
+	s.mu.Lock()
+	scrubbers := make([]Scrubber, 0, len(ruleScrubbers)+len(s.scrubbers))
+	scrubbers = append(scrubbers, ruleScrubbers...)
+	scrubbers = append(scrubbers, s.scrubbers...)
+	s.mu.Unlock()
+	for _, scrubber := range scrubbers {
+		scrubber.Scrub(sample)
+	}
+
+	select {
+	case s.queue <- sample:
+		s.captured.Add(1)
+		return true
+	default:
+		s.dropped.Add(1)
+		return false
+	}
+}
+
+// Stats returns the sampler counters
+func (s *Sampler) Stats() Stats {
+	return Stats{
+		Captured: s.captured.Load(),
+		Dropped:  s.dropped.Load(),
+		Uploaded: s.uploaded.Load(),
+		Failed:   s.failed.Load(),
+	}
+}
+
+// Start runs the upload loop until ctx is cancelled; buffered samples are flushed on exit
+func (s *Sampler) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.opts.FlushInterval)
+		defer ticker.Stop()
+
+		pending := make(map[string][]*Sample)
+		count := 0
+		flush := func(flushCtx context.Context) {
+			for endpoint, samples := range pending {
+				s.upload(flushCtx, endpoint, samples)
+			}
+			pending = make(map[string][]*Sample)
+			count = 0
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Drain what is already queued, then upload with a fresh deadline
+			drain:
+				for {
+					select {
+					case sample := <-s.queue:
+						pending[sample.Endpoint] = append(pending[sample.Endpoint], sample)
+					default:
+						break drain
+					}
+				}
+				flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				flush(flushCtx)
+				cancel()
+				return
+			case sample := <-s.queue:
+				pending[sample.Endpoint] = append(pending[sample.Endpoint], sample)
+				count++
+				if count >= s.opts.BatchSize {
+					flush(ctx)
+				}
+			case <-ticker.C:
+				flush(ctx)
+			}
+		}
+	}()
+}
+
+// Wait blocks until the upload loop has flushed and exited
+func (s *Sampler) Wait() {
+	<-s.done
+}
+
+// upload writes the samples of one endpoint as a gzip JSON lines object
+func (s *Sampler) upload(ctx context.Context, endpoint string, samples []*Sample) {
+	if len(samples) == 0 {
+		return
+	}
+	data, err := encodeJSONLinesGzip(samples)
+	if err != nil {
+		s.failed.Add(int64(len(samples)))
+		logger.ErrorCtx(ctx, "sampling: failed to encode %d samples of %s: %v", len(samples), endpoint, err)
+		return
+	}
+	key := ObjectKey(endpoint, samples[0].CompletedAt, s.seq.Add(1))
+	location, err := s.store.PutObject(ctx, key, data, "application/x-ndjson")
+	if err != nil {
+		s.failed.Add(int64(len(samples)))
+		logger.ErrorCtx(ctx, "sampling: failed to upload %d samples of %s: %v", len(samples), endpoint, err)
+		return
+	}
+	s.uploaded.Add(int64(len(samples)))
+	logger.DebugCtx(ctx, "sampling: uploaded %d samples of %s to %s", len(samples), endpoint, location)
+
+	s.mu.Lock()
+	hook := s.onUpload
+	s.mu.Unlock()
+	if hook != nil {
+		hook(ctx, key, samples)
+	}
+}
+
+// ObjectKey returns the partitioned object key of a sample batch
+func ObjectKey(endpoint string, t time.Time, seq int64) string {
+	return path.Join(
+		"samples",
+		endpoint,
+		"dt="+t.UTC().Format("2006-01-02"),
+		fmt.Sprintf("%s-%d-%d.jsonl.gz", endpoint, t.UnixMilli(), seq),
+	)
+}
+
+func encodeJSONLinesGzip(samples []*Sample) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, sample := range samples {
+		if err := enc.Encode(sample); err != nil {
+			return nil, err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
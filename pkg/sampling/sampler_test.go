@@ -0,0 +1,182 @@
+package sampling
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"waverless/pkg/export"
+)
+
+func TestFieldScrubber(t *testing.T) {
+	input := map[string]interface{}{
+		"prompt": "hello",
+		"User":   map[string]interface{}{"Email": "a@b.io", "name": "ann"},
+		"items":  []interface{}{map[string]interface{}{"api_key": "secret"}},
+	}
+	sample := &Sample{Input: input}
+
+	NewFieldScrubber([]string{"email", "API_KEY"}).Scrub(sample)
+
+	assert.Equal(t, "hello", sample.Input["prompt"])
+	assert.Equal(t, Redacted, sample.Input["User"].(map[string]interface{})["Email"])
+	assert.Equal(t, "ann", sample.Input["User"].(map[string]interface{})["name"])
+	assert.Equal(t, Redacted, sample.Input["items"].([]interface{})[0].(map[string]interface{})["api_key"])
+	assert.Equal(t, "a@b.io", input["User"].(map[string]interface{})["Email"], "the task record is not modified")
+}
+
+func TestPatternScrubber(t *testing.T) {
+	scrubber, unknown := NewPatternScrubber([]string{"email", "phone", "credit_card", "ssn"})
+	assert.Equal(t, []string{"ssn"}, unknown)
+
+	sample := &Sample{
+		Input:  map[string]interface{}{"text": "mail jane.doe@example.com or call +1 415-555-0100", "at": "2026-10-15 12:00:00", "ts": "1760529600000", "n": 3},
+		Output: map[string]interface{}{"cards": []interface{}{"4111 1111 1111 1111"}},
+		Error:  "failed for bob@example.org",
+	}
+	scrubber.Scrub(sample)
+
+	assert.Equal(t, "mail "+Redacted+" or call "+Redacted, sample.Input["text"])
+	assert.Equal(t, "2026-10-15 12:00:00", sample.Input["at"], "dates are not phone numbers")
+	assert.Equal(t, "1760529600000", sample.Input["ts"], "numbers failing the Luhn check are kept")
+	assert.Equal(t, 3, sample.Input["n"])
+	assert.Equal(t, []interface{}{Redacted}, sample.Output["cards"])
+	assert.Equal(t, "failed for "+Redacted, sample.Error)
+}
+
+func TestSamplerFlushesPerEndpointOnShutdown(t *testing.T) {
+	dir := t.TempDir()
+	store, err := export.NewLocalSink(dir)
+	require.NoError(t, err)
+
+	sampler := NewSampler(store, Options{FlushInterval: time.Hour}, NewFieldScrubber([]string{"token"}))
+	var hookCalls int
+	sampler.AddScrubber(ScrubberFunc(func(s *Sample) { hookCalls++ }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sampler.Start(ctx)
+
+	done := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	assert.True(t, sampler.Capture(&Sample{TaskID: "t1", Endpoint: "llm", Input: map[string]interface{}{"token": "x"}, CompletedAt: done}))
+	assert.True(t, sampler.Capture(&Sample{TaskID: "t2", Endpoint: "llm", CompletedAt: done}, NewFieldScrubber([]string{"prompt"})))
+	assert.True(t, sampler.Capture(&Sample{TaskID: "t3", Endpoint: "sd", CompletedAt: done}))
+
+	cancel()
+	sampler.Wait()
+
+	assert.Equal(t, 3, hookCalls)
+	assert.Equal(t, Stats{Captured: 3, Uploaded: 3}, sampler.Stats())
+
+	files, err := filepath.Glob(filepath.Join(dir, "samples", "llm", "dt=2026-10-15", "*.jsonl.gz"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	samples := readSamples(t, files[0])
+	require.Len(t, samples, 2)
+	assert.Equal(t, "t1", samples[0].TaskID)
+	assert.Equal(t, Redacted, samples[0].Input["token"])
+}
+
+func TestSamplerDropsWhenQueueFull(t *testing.T) {
+	store, err := export.NewLocalSink(t.TempDir())
+	require.NoError(t, err)
+
+	sampler := NewSampler(store, Options{QueueSize: 1}) // Not started, nothing drains the queue
+	assert.True(t, sampler.Capture(&Sample{TaskID: "t1", Endpoint: "llm"}))
+	assert.False(t, sampler.Capture(&Sample{TaskID: "t2", Endpoint: "llm"}))
+	assert.Equal(t, int64(1), sampler.Stats().Dropped)
+}
+
+func readSamples(t *testing.T, file string) []*Sample {
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	var samples []*Sample
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var s Sample
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &s))
+		samples = append(samples, &s)
+	}
+	require.NoError(t, scanner.Err())
+	return samples
+}
+
+func TestEraseSubject(t *testing.T) {
+	data, err := encodeJSONLinesGzip([]*Sample{
+		{TaskID: "t1", Endpoint: "llm", Subject: "s1", Input: map[string]interface{}{"prompt": "hi"}, Error: "bad"},
+		{TaskID: "t2", Endpoint: "llm", Subject: "s2", Input: map[string]interface{}{"prompt": "yo"}},
+	})
+	require.NoError(t, err)
+
+	out, erased, remaining, err := EraseSubject(data, "s1", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	assert.Equal(t, 1, remaining)
+	samples := decodeSamples(t, out)
+	require.Len(t, samples, 1)
+	assert.Equal(t, "t2", samples[0].TaskID)
+
+	out, erased, _, err = EraseSubject(data, "s1", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	samples = decodeSamples(t, out)
+	require.Len(t, samples, 2)
+	assert.Nil(t, samples[0].Input)
+	assert.Empty(t, samples[0].Error)
+	assert.Empty(t, samples[0].Subject)
+	assert.Equal(t, "yo", samples[1].Input["prompt"])
+
+	out, erased, remaining, err = EraseSubject(data, "unknown", false)
+	require.NoError(t, err)
+	assert.Nil(t, out, "unchanged objects are not rewritten")
+	assert.Equal(t, 0, erased)
+	assert.Equal(t, 2, remaining)
+}
+
+func TestSamplerUploadHook(t *testing.T) {
+	store, err := export.NewLocalSink(t.TempDir())
+	require.NoError(t, err)
+
+	sampler := NewSampler(store, Options{FlushInterval: time.Hour})
+	var keys []string
+	sampler.SetUploadHook(func(ctx context.Context, key string, samples []*Sample) {
+		keys = append(keys, key)
+		assert.Len(t, samples, 1)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	sampler.Start(ctx)
+	assert.True(t, sampler.Capture(&Sample{TaskID: "t1", Endpoint: "llm", Subject: "s1"}))
+	cancel()
+	sampler.Wait()
+
+	require.Len(t, keys, 1)
+	data, err := store.GetObject(context.Background(), keys[0])
+	require.NoError(t, err)
+	assert.Equal(t, "s1", decodeSamples(t, data)[0].Subject)
+}
+
+func decodeSamples(t *testing.T, data []byte) []*Sample {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	var samples []*Sample
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var s Sample
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &s))
+		samples = append(samples, &s)
+	}
+	return samples
+}
@@ -0,0 +1,176 @@
+package sampling
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces scrubbed values
+const Redacted = "[REDACTED]"
+
+// Scrubber removes PII from a sample before it leaves the cluster.
+// Scrubbers run in registration order and modify the sample in place.
+type Scrubber interface {
+	Scrub(sample *Sample)
+}
+
+// ScrubberFunc adapts a function to the Scrubber interface
+type ScrubberFunc func(sample *Sample)
+
+// Scrub calls f(sample)
+func (f ScrubberFunc) Scrub(sample *Sample) { f(sample) }
+
+// FieldScrubber redacts the values of the given keys (case-insensitive) at any depth of
+// the input and output documents
+type FieldScrubber struct {
+	fields map[string]bool
+}
+
+// NewFieldScrubber creates a scrubber for the given field names
+func NewFieldScrubber(fields []string) *FieldScrubber {
+	s := &FieldScrubber{fields: make(map[string]bool, len(fields))}
+	for _, f := range fields {
+		s.fields[strings.ToLower(f)] = true
+	}
+	return s
+}
+
+// Scrub redacts matching fields of the sample input and output
+func (s *FieldScrubber) Scrub(sample *Sample) {
+	if len(s.fields) == 0 {
+		return
+	}
+	sample.Input = walkMap(sample.Input, s.redactField)
+	sample.Output = walkMap(sample.Output, s.redactField)
+}
+
+func (s *FieldScrubber) redactField(key string, value interface{}) interface{} {
+	if s.fields[strings.ToLower(key)] {
+		return Redacted
+	}
+	return value
+}
+
+// piiPattern is a regexp with an optional check that filters false positives
+type piiPattern struct {
+	re    *regexp.Regexp
+	valid func(match string) bool
+}
+
+// Built-in PII patterns usable by name in the sampling configuration
+var builtinPatterns = map[string]*piiPattern{
+	"email":       {re: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	"phone":       {re: regexp.MustCompile(`(?:\+\d{1,3}[\s\-.]?)?\(?\b\d{3}\)?[\s\-.]?\d{3}[\s\-.]?\d{4}\b`)},
+	"credit_card": {re: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`), valid: luhnValid},
+	"ipv4":        {re: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)},
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum (card numbers do,
+// most other long numbers such as millisecond timestamps do not)
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// BuiltinPatternNames lists the names accepted by NewPatternScrubber
+func BuiltinPatternNames() []string {
+	return []string{"email", "phone", "credit_card", "ipv4"}
+}
+
+// PatternScrubber redacts PII patterns inside every string value of the sample
+type PatternScrubber struct {
+	patterns []*piiPattern
+}
+
+// NewPatternScrubber creates a scrubber from built-in pattern names; unknown names are returned
+func NewPatternScrubber(names []string) (*PatternScrubber, []string) {
+	s := &PatternScrubber{}
+	var unknown []string
+	for _, name := range names {
+		if p, ok := builtinPatterns[name]; ok {
+			s.patterns = append(s.patterns, p)
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	return s, unknown
+}
+
+// Scrub redacts pattern matches in the sample input, output and error
+func (s *PatternScrubber) Scrub(sample *Sample) {
+	if len(s.patterns) == 0 {
+		return
+	}
+	redact := func(_ string, value interface{}) interface{} {
+		if str, ok := value.(string); ok {
+			return s.redactString(str)
+		}
+		return value
+	}
+	sample.Input = walkMap(sample.Input, redact)
+	sample.Output = walkMap(sample.Output, redact)
+	sample.Error = s.redactString(sample.Error)
+}
+
+func (s *PatternScrubber) redactString(str string) string {
+	for _, p := range s.patterns {
+		if p.valid == nil {
+			str = p.re.ReplaceAllString(str, Redacted)
+			continue
+		}
+		str = p.re.ReplaceAllStringFunc(str, func(match string) string {
+			if p.valid(match) {
+				return Redacted
+			}
+			return match
+		})
+	}
+	return str
+}
+
+// walkMap returns a copy of m with fn applied to every (key, value) pair at any depth.
+// Copying keeps the task record itself untouched.
+func walkMap(m map[string]interface{}, fn func(key string, value interface{}) interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		v = fn(k, v)
+		if v != Redacted {
+			v = walkValue(v, fn)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func walkValue(v interface{}, fn func(key string, value interface{}) interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return walkMap(val, fn)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = walkValue(fn("", item), fn)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+// fairShareWindowFactor is how many tasks per batch slot fair share looks at, so a tenant
+// with a burst at the head of the queue does not hide the others
+const fairShareWindowFactor = 8
+
+func init() {
+	Register("fair-share", func() Scheduler { return fairShare{} })
+}
+
+// fairShare takes turns across tenants: each pull assigns the oldest task of every tenant in
+// the window before a second task of any tenant, tenants ordered by their oldest task.
+// Untagged tasks share one turn.
+type fairShare struct{}
+
+func (fairShare) Name() string { return "fair-share" }
+
+func (fairShare) Window(req *Request) int {
+	return req.BatchSize * fairShareWindowFactor
+}
+
+func (fairShare) Select(req *Request) []int64 {
+	var order []string
+	queues := make(map[string][]Task)
+	for _, t := range req.Candidates {
+		if _, ok := queues[t.Tenant]; !ok {
+			order = append(order, t.Tenant)
+		}
+		queues[t.Tenant] = append(queues[t.Tenant], t)
+	}
+
+	ids := make([]int64, 0, req.BatchSize)
+	for len(ids) < req.BatchSize {
+		picked := false
+		for _, tenant := range order {
+			if len(ids) == req.BatchSize {
+				break
+			}
+			if q := queues[tenant]; len(q) > 0 {
+				ids = append(ids, q[0].ID)
+				queues[tenant] = q[1:]
+				picked = true
+			}
+		}
+		if !picked {
+			break
+		}
+	}
+	return ids
+}
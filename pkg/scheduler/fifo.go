@@ -0,0 +1,47 @@
+package scheduler
+
+import "time"
+
+// idleReserveMaxAge bounds how long a task is left for idle peers. Older tasks go to whichever
+// worker pulls, so a peer that reports idle but never pulls cannot hold tasks back.
+const idleReserveMaxAge = 5 * time.Second
+
+func init() {
+	Register(DefaultPolicy, func() Scheduler { return fifo{} })
+}
+
+// fifo assigns tasks oldest first and prefers the least-loaded workers: a busy worker leaves
+// the oldest fresh tasks to idle peers, one per idle peer, and takes the ones behind them.
+// Pulls of idle workers behave as plain FIFO.
+type fifo struct{}
+
+func (fifo) Name() string { return DefaultPolicy }
+
+func (fifo) Window(req *Request) int {
+	return req.BatchSize + idlePeers(req)
+}
+
+func (fifo) Select(req *Request) []int64 {
+	skip := 0
+	for idle := idlePeers(req); skip < idle && skip < len(req.Candidates); skip++ {
+		if req.Now.Sub(req.Candidates[skip].CreatedAt) >= idleReserveMaxAge {
+			break
+		}
+	}
+	return firstIDs(req.Candidates[skip:], req.BatchSize)
+}
+
+// idlePeers counts the peers without jobs when the pulling worker has some; idle workers do
+// not look at their peers
+func idlePeers(req *Request) int {
+	if req.Worker.JobsInProgress == 0 {
+		return 0
+	}
+	idle := 0
+	for _, peer := range req.Peers() {
+		if peer.ID != req.Worker.ID && peer.JobsInProgress == 0 {
+			idle++
+		}
+	}
+	return idle
+}
@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ShadowStats compares the picks of an endpoint's shadow policy with those of its policy
+type ShadowStats struct {
+	Endpoint      string  `json:"endpoint"`
+	Policy        string  `json:"policy"`
+	ShadowPolicy  string  `json:"shadowPolicy"`
+	Pulls         int64   `json:"pulls"`         // Pulls with candidates
+	Agreed        int64   `json:"agreed"`        // Pulls where both picked the same tasks in the same order
+	Assigned      int64   `json:"assigned"`      // Tasks the policy assigned
+	ShadowPicked  int64   `json:"shadowPicked"`  // Tasks the shadow policy would have assigned
+	Overlap       int64   `json:"overlap"`       // Tasks picked by both
+	AgreementRate float64 `json:"agreementRate"` // Agreed / Pulls
+	PositionDelta float64 `json:"positionDelta"` // Mean window position of shadow picks minus that of assigned tasks; >0 = shadow picks newer tasks
+
+	pickedPositions int64
+	shadowPositions int64
+}
+
+// Policies resolves the policy and the shadow policy of each endpoint and keeps the shadow
+// comparison. It is safe for concurrent use.
+type Policies struct {
+	def       Scheduler
+	endpoints map[string]Scheduler
+	shadows   map[string]Scheduler
+
+	mu    sync.Mutex
+	stats map[string]*ShadowStats
+}
+
+// NewPolicies resolves policy names: defaultPolicy for all endpoints ("" = fifo), endpoints
+// overriding it per endpoint, shadow naming the shadow policy of an endpoint
+func NewPolicies(defaultPolicy string, endpoints, shadow map[string]string) (*Policies, error) {
+	if defaultPolicy == "" {
+		defaultPolicy = DefaultPolicy
+	}
+	def, err := New(defaultPolicy)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policies{
+		def:       def,
+		endpoints: make(map[string]Scheduler, len(endpoints)),
+		shadows:   make(map[string]Scheduler, len(shadow)),
+		stats:     make(map[string]*ShadowStats),
+	}
+	for endpoint, name := range endpoints {
+		if p.endpoints[endpoint], err = New(name); err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", endpoint, err)
+		}
+	}
+	for endpoint, name := range shadow {
+		if p.shadows[endpoint], err = New(name); err != nil {
+			return nil, fmt.Errorf("endpoint %s shadow: %w", endpoint, err)
+		}
+	}
+	return p, nil
+}
+
+// For returns the policy of an endpoint
+func (p *Policies) For(endpoint string) Scheduler {
+	if s, ok := p.endpoints[endpoint]; ok {
+		return s
+	}
+	return p.def
+}
+
+// ShadowFor returns the shadow policy of an endpoint, nil without one
+func (p *Policies) ShadowFor(endpoint string) Scheduler {
+	return p.shadows[endpoint]
+}
+
+// RecordShadow records the picks of both policies for one pull of req
+func (p *Policies) RecordShadow(req *Request, picked, shadowPicked []int64) {
+	if len(req.Candidates) == 0 {
+		return
+	}
+	position := make(map[int64]int, len(req.Candidates))
+	for i, t := range req.Candidates {
+		position[t.ID] = i
+	}
+	inPicked := make(map[int64]bool, len(picked))
+	for _, id := range picked {
+		inPicked[id] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st, ok := p.stats[req.Endpoint]
+	if !ok {
+		st = &ShadowStats{Endpoint: req.Endpoint, Policy: p.For(req.Endpoint).Name(), ShadowPolicy: p.ShadowFor(req.Endpoint).Name()}
+		p.stats[req.Endpoint] = st
+	}
+	st.Pulls++
+	if equalIDs(picked, shadowPicked) {
+		st.Agreed++
+	}
+	st.Assigned += int64(len(picked))
+	st.ShadowPicked += int64(len(shadowPicked))
+	for _, id := range shadowPicked {
+		if inPicked[id] {
+			st.Overlap++
+		}
+		st.shadowPositions += int64(position[id])
+	}
+	for _, id := range picked {
+		st.pickedPositions += int64(position[id])
+	}
+}
+
+// ShadowReport returns the shadow comparison of every endpoint with a shadow policy
+func (p *Policies) ShadowReport() []*ShadowStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	report := make([]*ShadowStats, 0, len(p.shadows))
+	for endpoint, shadow := range p.shadows {
+		st := ShadowStats{Endpoint: endpoint, Policy: p.For(endpoint).Name(), ShadowPolicy: shadow.Name()}
+		if recorded, ok := p.stats[endpoint]; ok {
+			st = *recorded
+		}
+		if st.Pulls > 0 {
+			st.AgreementRate = float64(st.Agreed) / float64(st.Pulls)
+		}
+		if st.Assigned > 0 && st.ShadowPicked > 0 {
+			st.PositionDelta = float64(st.shadowPositions)/float64(st.ShadowPicked) - float64(st.pickedPositions)/float64(st.Assigned)
+		}
+		report = append(report, &st)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Endpoint < report[j].Endpoint })
+	return report
+}
+
+// Assignments lists the policy of every endpoint that overrides the default
+func (p *Policies) Assignments() (def string, endpoints, shadows map[string]string) {
+	endpoints = make(map[string]string, len(p.endpoints))
+	for endpoint, s := range p.endpoints {
+		endpoints[endpoint] = s.Name()
+	}
+	shadows = make(map[string]string, len(p.shadows))
+	for endpoint, s := range p.shadows {
+		shadows[endpoint] = s.Name()
+	}
+	return p.def.Name(), endpoints, shadows
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
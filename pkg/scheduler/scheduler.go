@@ -0,0 +1,132 @@
+// Package scheduler decides which queued tasks a pulling worker gets. Workers pull; for every
+// pull the dispatcher locks the oldest pending tasks of the endpoint (the window) and asks the
+// endpoint's Scheduler which of them to assign.
+//
+// Policies are registered by name and selected per endpoint. A shadow policy may run next to
+// an endpoint's policy on the same pulls without assigning anything, to compare the two before
+// switching.
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPolicy is the policy of endpoints without a configured one
+const DefaultPolicy = "fifo"
+
+// MaxWindow caps the tasks locked per pull, whatever a policy asks for
+const MaxWindow = 100
+
+// Worker is a worker of the endpoint: the one pulling or a peer
+type Worker struct {
+	ID             string
+	JobsInProgress int
+}
+
+// Task is a pending task offered to a scheduler
+type Task struct {
+	ID        int64 // Queue position: lower is older
+	TaskID    string
+	CreatedAt time.Time
+	Tenant    string // Tenant key (the hashed subject key), "" = untagged
+}
+
+// Request is one pull of a worker
+type Request struct {
+	Endpoint   string
+	Worker     Worker
+	BatchSize  int
+	Candidates []Task // Oldest first, at most the policy's window
+	Now        time.Time
+
+	// LoadPeers returns the other active workers of the endpoint; called at most once
+	LoadPeers func() []Worker
+
+	peers       []Worker
+	peersLoaded bool
+}
+
+// Peers returns the other active workers of the endpoint, loading them on first use
+func (r *Request) Peers() []Worker {
+	if !r.peersLoaded {
+		if r.LoadPeers != nil {
+			r.peers = r.LoadPeers()
+		}
+		r.peersLoaded = true
+	}
+	return r.peers
+}
+
+// Scheduler is a task assignment policy
+type Scheduler interface {
+	// Name is the name the policy is registered with
+	Name() string
+	// Window returns how many of the oldest pending tasks to offer for the pull; Candidates is
+	// not set yet
+	Window(req *Request) int
+	// Select returns the IDs of the candidates to assign, in assignment order, at most
+	// req.BatchSize
+	Select(req *Request) []int64
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]func() Scheduler)
+)
+
+// Register makes a policy available under name. Registering a name twice replaces it.
+func Register(name string, factory func() Scheduler) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// New creates the policy registered under name
+func New(name string) (Scheduler, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown scheduler policy %q (available: %v)", name, Available())
+	}
+	return factory(), nil
+}
+
+// Available lists the registered policy names
+func Available() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Window clamps the window of s for req to [BatchSize, MaxWindow]
+func Window(s Scheduler, req *Request) int {
+	window := s.Window(req)
+	if window < req.BatchSize {
+		window = req.BatchSize
+	}
+	if window > MaxWindow {
+		window = MaxWindow
+	}
+	return window
+}
+
+// firstIDs returns the IDs of the first n tasks
+func firstIDs(tasks []Task, n int) []int64 {
+	if n > len(tasks) {
+		n = len(tasks)
+	}
+	ids := make([]int64, 0, n)
+	for _, t := range tasks[:n] {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+// queue returns n tasks queued one second apart, the newest a second before testNow
+func queue(n int, tenants ...string) []Task {
+	tasks := make([]Task, 0, n)
+	for i := 0; i < n; i++ {
+		t := Task{ID: int64(i + 1), CreatedAt: testNow.Add(-time.Duration(n-i) * time.Second)}
+		if len(tenants) > 0 {
+			t.Tenant = tenants[i%len(tenants)]
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+func TestFIFO(t *testing.T) {
+	s, err := New("fifo")
+	require.NoError(t, err)
+
+	peersLoaded := 0
+	idle := &Request{BatchSize: 2, Candidates: queue(3), Now: testNow, LoadPeers: func() []Worker {
+		peersLoaded++
+		return nil
+	}}
+	assert.Equal(t, 2, s.Window(idle))
+	assert.Equal(t, []int64{1, 2}, s.Select(idle))
+	assert.Zero(t, peersLoaded, "idle workers do not look at their peers")
+
+	peers := []Worker{{ID: "w1", JobsInProgress: 1}, {ID: "w2"}, {ID: "w3"}, {ID: "w4", JobsInProgress: 2}}
+	busy := &Request{Worker: Worker{ID: "w1", JobsInProgress: 1}, BatchSize: 1, Now: testNow, LoadPeers: func() []Worker {
+		peersLoaded++
+		return peers
+	}}
+	assert.Equal(t, 3, s.Window(busy))
+	busy.Candidates = queue(3)
+	assert.Equal(t, []int64{3}, s.Select(busy), "the two oldest tasks are left to the idle peers")
+	assert.Equal(t, 1, peersLoaded)
+
+	busy.Candidates = queue(2)
+	assert.Empty(t, s.Select(busy))
+
+	// Tasks waiting too long go to whoever pulls
+	busy.Candidates = queue(3)
+	busy.Candidates[0].CreatedAt = testNow.Add(-time.Minute)
+	assert.Equal(t, []int64{1}, s.Select(busy))
+}
+
+func TestFairShare(t *testing.T) {
+	s, err := New("fair-share")
+	require.NoError(t, err)
+
+	// a has a burst at the head of the queue
+	candidates := []Task{{ID: 1, Tenant: "a"}, {ID: 2, Tenant: "a"}, {ID: 3, Tenant: "a"}, {ID: 4, Tenant: "b"}, {ID: 5}, {ID: 6, Tenant: "b"}}
+	req := &Request{BatchSize: 4, Candidates: candidates, Now: testNow}
+	assert.Equal(t, 32, s.Window(req))
+	assert.Equal(t, []int64{1, 4, 5, 2}, s.Select(req))
+
+	req.BatchSize = 10
+	assert.Equal(t, []int64{1, 4, 5, 2, 6, 3}, s.Select(req))
+}
+
+func TestWindow(t *testing.T) {
+	s, _ := New("fair-share")
+	assert.Equal(t, MaxWindow, Window(s, &Request{BatchSize: 50}))
+	assert.Equal(t, 8, Window(s, &Request{BatchSize: 1}))
+}
+
+func TestNewPolicies(t *testing.T) {
+	p, err := NewPolicies("", map[string]string{"llm": "fair-share"}, map[string]string{"flux": "fair-share"})
+	require.NoError(t, err)
+	assert.Equal(t, "fifo", p.For("flux").Name())
+	assert.Equal(t, "fair-share", p.For("llm").Name())
+	assert.Nil(t, p.ShadowFor("llm"))
+	assert.Equal(t, "fair-share", p.ShadowFor("flux").Name())
+
+	_, err = NewPolicies("fifo", map[string]string{"llm": "random"}, nil)
+	assert.ErrorContains(t, err, `endpoint llm: unknown scheduler policy "random"`)
+}
+
+func TestShadowReport(t *testing.T) {
+	p, err := NewPolicies("fifo", nil, map[string]string{"flux": "fair-share", "idle": "fair-share"})
+	require.NoError(t, err)
+
+	req := &Request{Endpoint: "flux", BatchSize: 2, Candidates: queue(4, "a", "a", "b", "b")}
+	p.RecordShadow(req, []int64{1, 2}, []int64{1, 3})
+	p.RecordShadow(req, []int64{1, 2}, []int64{1, 2})
+
+	report := p.ShadowReport()
+	require.Len(t, report, 2)
+	flux := report[0]
+	assert.Equal(t, "flux", flux.Endpoint)
+	assert.Equal(t, "fifo", flux.Policy)
+	assert.Equal(t, "fair-share", flux.ShadowPolicy)
+	assert.Equal(t, int64(2), flux.Pulls)
+	assert.Equal(t, int64(1), flux.Agreed)
+	assert.Equal(t, int64(3), flux.Overlap)
+	assert.Equal(t, 0.5, flux.AgreementRate)
+	assert.Equal(t, 0.25, flux.PositionDelta)
+
+	assert.Equal(t, "idle", report[1].Endpoint)
+	assert.Zero(t, report[1].Pulls)
+}
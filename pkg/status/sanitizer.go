@@ -116,6 +116,16 @@ var ContainerCrashErrorMappings = map[string]SanitizedError{
 		Suggestion:  "Please check container configuration and startup command",
 		ErrorCode:   "CONTAINER_CANNOT_RUN",
 	},
+	"CUDAOutOfMemory": {
+		UserMessage: "GPU ran out of memory",
+		Suggestion:  "Please reduce batch size or model size, or select a GPU with more memory",
+		ErrorCode:   "CONTAINER_CUDA_OOM",
+	},
+	"SeccompDenied": {
+		UserMessage: "Container was blocked by the security profile",
+		Suggestion:  "Please check if the application requires system calls not allowed by the seccomp profile",
+		ErrorCode:   "CONTAINER_SECCOMP_DENIED",
+	},
 
 	// Novita specific errors
 	"container_crashed": {
@@ -160,6 +170,16 @@ var ResourceLimitErrorMappings = map[string]SanitizedError{
 		Suggestion:  "Please try again later, system is looking for available resources",
 		ErrorCode:   "RESOURCE_UNSCHEDULABLE",
 	},
+	"DiskPressure": {
+		UserMessage: "Node ran out of disk space",
+		Suggestion:  "Please reduce disk usage or increase ephemeral storage, or try again later",
+		ErrorCode:   "RESOURCE_DISK_PRESSURE",
+	},
+	"Evicted": {
+		UserMessage: "Worker was evicted due to node resource pressure",
+		Suggestion:  "Please try again later or select a larger spec",
+		ErrorCode:   "RESOURCE_EVICTED",
+	},
 
 	// Novita specific errors
 	"insufficient_resources": {
@@ -207,6 +227,15 @@ var TimeoutErrorMappings = map[string]SanitizedError{
 	},
 }
 
+// GPUUnhealthyErrorMappings contains default mappings for GPU_UNHEALTHY type.
+var GPUUnhealthyErrorMappings = map[string]SanitizedError{
+	"default": {
+		UserMessage: "Worker GPU failed its health check",
+		Suggestion:  "The worker is being replaced automatically, no action is needed",
+		ErrorCode:   "GPU_UNHEALTHY",
+	},
+}
+
 // UnknownErrorMappings contains default mappings for UNKNOWN type.
 var UnknownErrorMappings = map[string]SanitizedError{
 	"default": {
@@ -228,6 +257,7 @@ func NewStatusSanitizer() *StatusSanitizer {
 	s.errorMappings[interfaces.FailureTypeContainerCrash] = ContainerCrashErrorMappings
 	s.errorMappings[interfaces.FailureTypeResourceLimit] = ResourceLimitErrorMappings
 	s.errorMappings[interfaces.FailureTypeTimeout] = TimeoutErrorMappings
+	s.errorMappings[interfaces.FailureTypeGPUUnhealthy] = GPUUnhealthyErrorMappings
 	s.errorMappings[interfaces.FailureTypeUnknown] = UnknownErrorMappings
 
 	return s
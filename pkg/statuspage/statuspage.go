@@ -0,0 +1,94 @@
+// Package statuspage derives the client-visible status of endpoints for external status pages:
+// a coarse health state, queue saturation and the success rate of the last 24 hours.
+//
+// Only endpoints that opt in through their exposure label are shown, and the exposure decides
+// how much is shown. Derivation is pure; loading endpoints and statistics is up to the caller.
+package statuspage
+
+import "math"
+
+// Status is the client-visible state of an endpoint or a project
+type Status string
+
+const (
+	StatusOperational Status = "operational"
+	StatusPaused      Status = "paused"   // Task dispatch paused by an operator
+	StatusDegraded    Status = "degraded" // Some workers failing
+	StatusOutage      Status = "outage"   // All workers failing or the image cannot be pulled
+)
+
+// severity orders statuses for Worst; paused is shown but ranks below failing workers
+var severity = map[Status]int{
+	StatusOperational: 0,
+	StatusPaused:      1,
+	StatusDegraded:    2,
+	StatusOutage:      3,
+}
+
+// Exposure is how much of an endpoint a status page shows, taken from its exposure label
+type Exposure string
+
+const (
+	ExposureNone   Exposure = ""       // Not listed
+	ExposureHealth Exposure = "health" // Status only
+	ExposureFull   Exposure = "full"   // Status, queue saturation and success rate
+)
+
+// ParseExposure maps an exposure label value to an Exposure; unknown values hide the endpoint
+func ParseExposure(value string) Exposure {
+	switch Exposure(value) {
+	case ExposureHealth, ExposureFull:
+		return Exposure(value)
+	default:
+		return ExposureNone
+	}
+}
+
+// EndpointStatus maps an endpoint's health status (HEALTHY, DEGRADED, UNHEALTHY) and dispatch
+// pause to a Status. Failing workers outrank a pause.
+func EndpointStatus(healthStatus string, dispatchPaused bool) Status {
+	switch healthStatus {
+	case "UNHEALTHY":
+		return StatusOutage
+	case "DEGRADED":
+		return StatusDegraded
+	}
+	if dispatchPaused {
+		return StatusPaused
+	}
+	return StatusOperational
+}
+
+// Worst returns the most severe of statuses, operational when there are none
+func Worst(statuses ...Status) Status {
+	worst := StatusOperational
+	for _, s := range statuses {
+		if severity[s] > severity[worst] {
+			worst = s
+		}
+	}
+	return worst
+}
+
+// Saturation is the pending tasks of an endpoint relative to its max pending tasks, rounded to
+// two decimals. Values of 1 and above mean new submissions are not recommended.
+func Saturation(pending int64, maxPending int) float64 {
+	if maxPending <= 0 {
+		maxPending = 1
+	}
+	return round2(float64(pending) / float64(maxPending))
+}
+
+// SuccessRate is the completed share of finished tasks rounded to four decimals, nil when no
+// task finished
+func SuccessRate(completed, failed int) *float64 {
+	if completed+failed <= 0 {
+		return nil
+	}
+	rate := math.Round(float64(completed)/float64(completed+failed)*10000) / 10000
+	return &rate
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,47 @@
+package statuspage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExposure(t *testing.T) {
+	assert.Equal(t, ExposureFull, ParseExposure("full"))
+	assert.Equal(t, ExposureHealth, ParseExposure("health"))
+	assert.Equal(t, ExposureNone, ParseExposure(""))
+	assert.Equal(t, ExposureNone, ParseExposure("public"))
+}
+
+func TestEndpointStatus(t *testing.T) {
+	assert.Equal(t, StatusOperational, EndpointStatus("HEALTHY", false))
+	assert.Equal(t, StatusPaused, EndpointStatus("HEALTHY", true))
+	assert.Equal(t, StatusDegraded, EndpointStatus("DEGRADED", true))
+	assert.Equal(t, StatusOutage, EndpointStatus("UNHEALTHY", false))
+}
+
+func TestWorst(t *testing.T) {
+	assert.Equal(t, StatusOperational, Worst())
+	assert.Equal(t, StatusPaused, Worst(StatusOperational, StatusPaused))
+	assert.Equal(t, StatusOutage, Worst(StatusDegraded, StatusOutage, StatusPaused))
+}
+
+func TestSaturation(t *testing.T) {
+	assert.Equal(t, 0.0, Saturation(0, 10))
+	assert.Equal(t, 0.33, Saturation(1, 3))
+	assert.Equal(t, 2.5, Saturation(25, 10))
+	assert.Equal(t, 3.0, Saturation(3, 0), "max pending defaults to 1")
+}
+
+func TestSuccessRate(t *testing.T) {
+	assert.Nil(t, SuccessRate(0, 0))
+
+	rate := SuccessRate(997, 3)
+	require.NotNil(t, rate)
+	assert.Equal(t, 0.997, *rate)
+
+	rate = SuccessRate(0, 5)
+	require.NotNil(t, rate)
+	assert.Equal(t, 0.0, *rate)
+}
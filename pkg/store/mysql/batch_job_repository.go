@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/interfaces"
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// BatchJobRepository handles one-off job persistence
+type BatchJobRepository struct {
+	ds *Datastore
+}
+
+// NewBatchJobRepository creates a new batch job repository
+func NewBatchJobRepository(ds *Datastore) *BatchJobRepository {
+	return &BatchJobRepository{ds: ds}
+}
+
+// Create saves a new job
+func (r *BatchJobRepository) Create(ctx context.Context, job *model.BatchJob) error {
+	if err := r.ds.DB(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	return nil
+}
+
+// Get returns a job by name, nil if it does not exist
+func (r *BatchJobRepository) Get(ctx context.Context, name string) (*model.BatchJob, error) {
+	var job model.BatchJob
+	err := r.ds.DB(ctx).Where("name = ?", name).First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return &job, nil
+}
+
+// List returns the most recent jobs, newest first; an empty status lists all jobs
+func (r *BatchJobRepository) List(ctx context.Context, status string, limit, offset int) ([]*model.BatchJob, error) {
+	var jobs []*model.BatchJob
+	query := r.ds.DB(ctx).Omit("logs")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Count returns the number of jobs; an empty status counts all jobs
+func (r *BatchJobRepository) Count(ctx context.Context, status string) (int64, error) {
+	var count int64
+	query := r.ds.DB(ctx).Model(&model.BatchJob{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	return count, nil
+}
+
+// ListActive returns the jobs that have not finished yet
+func (r *BatchJobRepository) ListActive(ctx context.Context) ([]*model.BatchJob, error) {
+	var jobs []*model.BatchJob
+	err := r.ds.DB(ctx).Omit("logs").
+		Where("status IN ?", []string{interfaces.JobPhasePending, interfaces.JobPhaseRunning}).
+		Order("id ASC").Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// ListBySchedule returns the most recent runs of a schedule, newest first
+func (r *BatchJobRepository) ListBySchedule(ctx context.Context, schedule string, limit int) ([]*model.BatchJob, error) {
+	var jobs []*model.BatchJob
+	err := r.ds.DB(ctx).Omit("logs").Where("schedule_name = ?", schedule).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule runs: %w", err)
+	}
+	return jobs, nil
+}
+
+// TrimSchedule deletes the finished runs of a schedule beyond the newest keep
+func (r *BatchJobRepository) TrimSchedule(ctx context.Context, schedule string, keep int) (int64, error) {
+	var ids []int64
+	err := r.ds.DB(ctx).Model(&model.BatchJob{}).
+		Where("schedule_name = ? AND status NOT IN ?", schedule, []string{interfaces.JobPhasePending, interfaces.JobPhaseRunning}).
+		Order("created_at DESC, id DESC").Offset(keep).Limit(1000).Pluck("id", &ids).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to list old schedule runs: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.ds.DB(ctx).Where("id IN ?", ids).Delete(&model.BatchJob{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old schedule runs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Update updates the given columns of a job that has not finished yet. Returns false when the
+// job finished in the meantime (e.g. it was cancelled), so a finished job is never overwritten.
+func (r *BatchJobRepository) Update(ctx context.Context, name string, updates map[string]interface{}) (bool, error) {
+	result := r.ds.DB(ctx).Model(&model.BatchJob{}).
+		Where("name = ? AND status IN ?", name, []string{interfaces.JobPhasePending, interfaces.JobPhaseRunning}).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete removes a job
+func (r *BatchJobRepository) Delete(ctx context.Context, name string) error {
+	if err := r.ds.DB(ctx).Where("name = ?", name).Delete(&model.BatchJob{}).Error; err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+	return nil
+}
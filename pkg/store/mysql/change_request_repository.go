@@ -0,0 +1,98 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// ChangeRequestRepository handles change request persistence
+type ChangeRequestRepository struct {
+	ds *Datastore
+}
+
+// NewChangeRequestRepository creates a new change request repository
+func NewChangeRequestRepository(ds *Datastore) *ChangeRequestRepository {
+	return &ChangeRequestRepository{ds: ds}
+}
+
+// Create stores a new change request
+func (r *ChangeRequestRepository) Create(ctx context.Context, cr *model.ChangeRequest) error {
+	if err := r.ds.DB(ctx).Create(cr).Error; err != nil {
+		return fmt.Errorf("failed to create change request: %w", err)
+	}
+	return nil
+}
+
+// Get returns a change request by ID, nil if it does not exist
+func (r *ChangeRequestRepository) Get(ctx context.Context, id int64) (*model.ChangeRequest, error) {
+	var cr model.ChangeRequest
+	if err := r.ds.DB(ctx).Where("id = ?", id).First(&cr).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get change request: %w", err)
+	}
+	return &cr, nil
+}
+
+// List returns change requests, newest first, optionally filtered by endpoint and status
+func (r *ChangeRequestRepository) List(ctx context.Context, endpoint, status string, limit, offset int) ([]*model.ChangeRequest, error) {
+	var requests []*model.ChangeRequest
+	if err := r.filtered(ctx, endpoint, status).Order("id DESC").Limit(limit).Offset(offset).Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("failed to list change requests: %w", err)
+	}
+	return requests, nil
+}
+
+// Count returns the number of change requests, optionally filtered by endpoint and status
+func (r *ChangeRequestRepository) Count(ctx context.Context, endpoint, status string) (int64, error) {
+	var count int64
+	if err := r.filtered(ctx, endpoint, status).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count change requests: %w", err)
+	}
+	return count, nil
+}
+
+func (r *ChangeRequestRepository) filtered(ctx context.Context, endpoint, status string) *gorm.DB {
+	query := r.ds.DB(ctx).Model(&model.ChangeRequest{})
+	if endpoint != "" {
+		query = query.Where("endpoint = ?", endpoint)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return query
+}
+
+// Transition moves a change request from one status to another, applying updates.
+// It reports false if the request was no longer in the from status (e.g. another
+// replica reviewed it first).
+func (r *ChangeRequestRepository) Transition(ctx context.Context, id int64, from, to string, updates map[string]interface{}) (bool, error) {
+	values := map[string]interface{}{"status": to}
+	for k, v := range updates {
+		values[k] = v
+	}
+	result := r.ds.DB(ctx).Model(&model.ChangeRequest{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(values)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update change request: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ExpirePending marks pending change requests past their expiry as expired
+func (r *ChangeRequestRepository) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	result := r.ds.DB(ctx).Model(&model.ChangeRequest{}).
+		Where("status = ? AND expires_at <= ?", model.ChangeStatusPending, now).
+		Update("status", model.ChangeStatusExpired)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to expire change requests: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
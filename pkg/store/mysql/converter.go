@@ -70,6 +70,16 @@ func ToAutoscalerConfigDomain(mysqlConfig *AutoscalerConfig) *interfaces.Endpoin
 		EnableDynamicPrio: mysqlConfig.EnableDynamicPrio,
 		HighLoadThreshold: mysqlConfig.HighLoadThreshold,
 		PriorityBoost:     mysqlConfig.PriorityBoost,
+
+		// Custom metric scaling
+		CustomMetricName:   mysqlConfig.CustomMetricName,
+		CustomMetricTarget: mysqlConfig.CustomMetricTarget,
+		GroupName:          mysqlConfig.GroupName,
+		EvaluationInterval: mysqlConfig.EvaluationInterval,
+		QueueWaitSLO:       mysqlConfig.QueueWaitSLO,
+		MetricSources:      ToMetricSourcesDomain(mysqlConfig.MetricSources),
+		ReplicaSchedules:   ToReplicaSchedulesDomain(mysqlConfig.ReplicaSchedules),
+		Profile:            mysqlConfig.Profile,
 		// Note: Runtime state fields are not stored in MySQL
 	}
 }
@@ -96,7 +106,79 @@ func FromAutoscalerConfigDomain(domainConfig *interfaces.EndpointConfig) *Autosc
 		HighLoadThreshold: domainConfig.HighLoadThreshold,
 		PriorityBoost:     domainConfig.PriorityBoost,
 		Enabled:           true, // Default enabled
+
+		// Custom metric scaling
+		CustomMetricName:   domainConfig.CustomMetricName,
+		CustomMetricTarget: domainConfig.CustomMetricTarget,
+		GroupName:          domainConfig.GroupName,
+		EvaluationInterval: domainConfig.EvaluationInterval,
+		QueueWaitSLO:       domainConfig.QueueWaitSLO,
+		MetricSources:      FromMetricSourcesDomain(domainConfig.MetricSources),
+		ReplicaSchedules:   FromReplicaSchedulesDomain(domainConfig.ReplicaSchedules),
+		Profile:            domainConfig.Profile,
+	}
+}
+
+// ToMetricSourcesDomain converts stored metric sources to domain metric sources
+func ToMetricSourcesDomain(sources AutoscalerMetricSources) []interfaces.MetricSource {
+	if len(sources) == 0 {
+		return nil
+	}
+	result := make([]interfaces.MetricSource, len(sources))
+	for i, s := range sources {
+		result[i] = interfaces.MetricSource{Type: s.Type, Target: s.Target, Query: s.Query, TargetType: s.TargetType}
+	}
+	return result
+}
+
+// FromMetricSourcesDomain converts domain metric sources to stored metric sources
+func FromMetricSourcesDomain(sources []interfaces.MetricSource) AutoscalerMetricSources {
+	if len(sources) == 0 {
+		return nil
+	}
+	result := make(AutoscalerMetricSources, len(sources))
+	for i, s := range sources {
+		result[i] = AutoscalerMetricSource{Type: s.Type, Target: s.Target, Query: s.Query, TargetType: s.TargetType}
+	}
+	return result
+}
+
+// ToReplicaSchedulesDomain converts stored replica schedules to domain replica schedules
+func ToReplicaSchedulesDomain(schedules AutoscalerReplicaSchedules) []interfaces.ReplicaSchedule {
+	if len(schedules) == 0 {
+		return nil
+	}
+	result := make([]interfaces.ReplicaSchedule, len(schedules))
+	for i, s := range schedules {
+		result[i] = interfaces.ReplicaSchedule{
+			Name:            s.Name,
+			Cron:            s.Cron,
+			DurationMinutes: s.DurationMinutes,
+			Timezone:        s.Timezone,
+			MinReplicas:     s.MinReplicas,
+			MaxReplicas:     s.MaxReplicas,
+		}
+	}
+	return result
+}
+
+// FromReplicaSchedulesDomain converts domain replica schedules to stored replica schedules
+func FromReplicaSchedulesDomain(schedules []interfaces.ReplicaSchedule) AutoscalerReplicaSchedules {
+	if len(schedules) == 0 {
+		return nil
+	}
+	result := make(AutoscalerReplicaSchedules, len(schedules))
+	for i, s := range schedules {
+		result[i] = AutoscalerReplicaSchedule{
+			Name:            s.Name,
+			Cron:            s.Cron,
+			DurationMinutes: s.DurationMinutes,
+			Timezone:        s.Timezone,
+			MinReplicas:     s.MinReplicas,
+			MaxReplicas:     s.MaxReplicas,
+		}
 	}
+	return result
 }
 
 // ToScalingEventDomain converts MySQL ScalingEvent to domain ScalingEvent
@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// DataDeletionRepository handles data deletion requests and the sample subject index
+type DataDeletionRepository struct {
+	ds *Datastore
+}
+
+// NewDataDeletionRepository creates a new data deletion repository
+func NewDataDeletionRepository(ds *Datastore) *DataDeletionRepository {
+	return &DataDeletionRepository{ds: ds}
+}
+
+// Create stores a data deletion request
+func (r *DataDeletionRepository) Create(ctx context.Context, req *model.DataDeletionRequest) error {
+	if err := r.ds.DB(ctx).Create(req).Error; err != nil {
+		return fmt.Errorf("failed to create data deletion request: %w", err)
+	}
+	return nil
+}
+
+// Update saves the status and report of a data deletion request
+func (r *DataDeletionRepository) Update(ctx context.Context, req *model.DataDeletionRequest) error {
+	err := r.ds.DB(ctx).Model(&model.DataDeletionRequest{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+		"status":       req.Status,
+		"report":       req.Report,
+		"completed_at": req.CompletedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update data deletion request: %w", err)
+	}
+	return nil
+}
+
+// Get returns a data deletion request, nil if it does not exist
+func (r *DataDeletionRepository) Get(ctx context.Context, id int64) (*model.DataDeletionRequest, error) {
+	var req model.DataDeletionRequest
+	if err := r.ds.DB(ctx).Where("id = ?", id).First(&req).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get data deletion request: %w", err)
+	}
+	return &req, nil
+}
+
+// List returns data deletion requests, newest first, optionally of one subject
+func (r *DataDeletionRepository) List(ctx context.Context, subjectHash string, limit int) ([]*model.DataDeletionRequest, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := r.ds.DB(ctx).Order("id DESC").Limit(limit)
+	if subjectHash != "" {
+		query = query.Where("subject_hash = ?", subjectHash)
+	}
+	var reqs []*model.DataDeletionRequest
+	if err := query.Find(&reqs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list data deletion requests: %w", err)
+	}
+	return reqs, nil
+}
+
+// IndexSamples records the sample objects holding samples of data subjects
+func (r *DataDeletionRepository) IndexSamples(ctx context.Context, entries []*model.SampleSubject) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := r.ds.DB(ctx).CreateInBatches(entries, 500).Error; err != nil {
+		return fmt.Errorf("failed to index sample subjects: %w", err)
+	}
+	return nil
+}
+
+// ListSampleObjects returns the distinct sample objects holding samples of a subject
+func (r *DataDeletionRepository) ListSampleObjects(ctx context.Context, subjectHash string) ([]string, error) {
+	var keys []string
+	err := r.ds.DB(ctx).Model(&model.SampleSubject{}).
+		Where("subject_hash = ?", subjectHash).
+		Distinct("object_key").
+		Pluck("object_key", &keys).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sample objects: %w", err)
+	}
+	return keys, nil
+}
+
+// DeleteSampleIndex removes the index entries of a subject in an erased object
+func (r *DataDeletionRepository) DeleteSampleIndex(ctx context.Context, subjectHash, objectKey string) error {
+	err := r.ds.DB(ctx).Where("subject_hash = ? AND object_key = ?", subjectHash, objectKey).Delete(&model.SampleSubject{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete sample index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// EncryptionKeyRepository handles wrapped data key persistence
+type EncryptionKeyRepository struct {
+	ds *Datastore
+}
+
+// NewEncryptionKeyRepository creates a new encryption key repository
+func NewEncryptionKeyRepository(ds *Datastore) *EncryptionKeyRepository {
+	return &EncryptionKeyRepository{ds: ds}
+}
+
+// Latest returns the newest data key of a project, nil if it has none
+func (r *EncryptionKeyRepository) Latest(ctx context.Context, project string) (*model.EncryptionKey, error) {
+	var key model.EncryptionKey
+	err := r.ds.DB(ctx).Where("project = ?", project).Order("version DESC").First(&key).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	return &key, nil
+}
+
+// Get returns a data key version, nil if it does not exist
+func (r *EncryptionKeyRepository) Get(ctx context.Context, project string, version int) (*model.EncryptionKey, error) {
+	var key model.EncryptionKey
+	err := r.ds.DB(ctx).Where("project = ? AND version = ?", project, version).First(&key).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	return &key, nil
+}
+
+// ListVersions returns all data key versions of a project, newest first
+func (r *EncryptionKeyRepository) ListVersions(ctx context.Context, project string) ([]*model.EncryptionKey, error) {
+	var keys []*model.EncryptionKey
+	if err := r.ds.DB(ctx).Where("project = ?", project).Order("version DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list encryption keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Create stores key as the next version of its project and sets key.Version
+func (r *EncryptionKeyRepository) Create(ctx context.Context, key *model.EncryptionKey) error {
+	return r.ds.ExecTx(ctx, func(txCtx context.Context) error {
+		var maxVersion int
+		if err := r.ds.DB(txCtx).Model(&model.EncryptionKey{}).
+			Where("project = ?", key.Project).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return fmt.Errorf("failed to get latest encryption key version: %w", err)
+		}
+		key.Version = maxVersion + 1
+		if err := r.ds.DB(txCtx).Create(key).Error; err != nil {
+			return fmt.Errorf("failed to create encryption key: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FinishedCounts are the finished tasks of an endpoint by outcome
+type FinishedCounts struct {
+	Endpoint  string
+	Completed int64
+	Failed    int64
+}
+
+// TripCircuit opens the circuit breaker of an endpoint whose breaker is closed. ok is false
+// when it was already open or half-open.
+func (r *EndpointRepository) TripCircuit(ctx context.Context, endpointName, reason string) (ok bool, err error) {
+	result := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ? AND circuit_state = ''", endpointName).
+		Updates(map[string]interface{}{
+			"circuit_state":         "open",
+			"circuit_reason":        reason,
+			"circuit_since":         time.Now(),
+			"circuit_probe_task":    "",
+			"circuit_failed_probes": 0,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to trip circuit breaker: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// HalfOpenCircuit lets an open circuit breaker admit a probe
+func (r *EndpointRepository) HalfOpenCircuit(ctx context.Context, endpointName string) (ok bool, err error) {
+	result := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ? AND circuit_state = ?", endpointName, "open").
+		Updates(map[string]interface{}{
+			"circuit_state":      "half_open",
+			"circuit_since":      time.Now(),
+			"circuit_probe_task": "",
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to half-open circuit breaker: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReopenCircuit opens a half-open circuit breaker again after a failed probe
+func (r *EndpointRepository) ReopenCircuit(ctx context.Context, endpointName string) (ok bool, err error) {
+	result := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ? AND circuit_state = ?", endpointName, "half_open").
+		Updates(map[string]interface{}{
+			"circuit_state":         "open",
+			"circuit_since":         time.Now(),
+			"circuit_probe_task":    "",
+			"circuit_failed_probes": gorm.Expr("circuit_failed_probes + 1"),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reopen circuit breaker: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CloseCircuit closes the circuit breaker of an endpoint. ok is false when it was closed.
+// circuit_since keeps the closing time so failures from before are not counted again.
+func (r *EndpointRepository) CloseCircuit(ctx context.Context, endpointName string) (ok bool, err error) {
+	result := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ? AND circuit_state <> ''", endpointName).
+		Updates(map[string]interface{}{
+			"circuit_state":         "",
+			"circuit_reason":        "",
+			"circuit_since":         time.Now(),
+			"circuit_probe_task":    "",
+			"circuit_failed_probes": 0,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to close circuit breaker: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimCircuitProbe admits taskID as the probe of a half-open circuit breaker. ok is false
+// when another task is the probe already.
+func (r *EndpointRepository) ClaimCircuitProbe(ctx context.Context, endpointName, taskID string) (ok bool, err error) {
+	result := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ? AND circuit_state = ? AND circuit_probe_task = ''", endpointName, "half_open").
+		Update("circuit_probe_task", taskID)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim circuit probe: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseCircuitProbe lets a half-open circuit breaker admit another probe instead of taskID
+func (r *EndpointRepository) ReleaseCircuitProbe(ctx context.Context, endpointName, taskID string) error {
+	err := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ? AND circuit_probe_task = ?", endpointName, taskID).
+		Update("circuit_probe_task", "").Error
+	if err != nil {
+		return fmt.Errorf("failed to release circuit probe: %w", err)
+	}
+	return nil
+}
+
+// ListCircuitBroken returns the endpoints whose circuit breaker is open or half-open
+func (r *EndpointRepository) ListCircuitBroken(ctx context.Context) ([]*Endpoint, error) {
+	var endpoints []*Endpoint
+	err := r.ds.DB(ctx).
+		Where("circuit_state <> '' AND status != ?", "deleted").
+		Find(&endpoints).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints with open circuit breaker: %w", err)
+	}
+	return endpoints, nil
+}
+
+// CountFinishedByEndpoint counts the tasks completed and failed since since, per endpoint
+func (r *TaskRepository) CountFinishedByEndpoint(ctx context.Context, since time.Time) ([]*FinishedCounts, error) {
+	var counts []*FinishedCounts
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Select("endpoint, COALESCE(SUM(status = 'COMPLETED'), 0) AS completed, COALESCE(SUM(status = 'FAILED'), 0) AS failed").
+		Where("completed_at >= ? AND status IN ?", since, []string{"COMPLETED", "FAILED"}).
+		Group("endpoint").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count finished tasks: %w", err)
+	}
+	return counts, nil
+}
+
+// CountFinishedSince counts the tasks of an endpoint completed and failed since since
+func (r *TaskRepository) CountFinishedSince(ctx context.Context, endpoint string, since time.Time) (*FinishedCounts, error) {
+	counts := &FinishedCounts{Endpoint: endpoint}
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Select("COALESCE(SUM(status = 'COMPLETED'), 0) AS completed, COALESCE(SUM(status = 'FAILED'), 0) AS failed").
+		Where("endpoint = ? AND completed_at >= ? AND status IN ?", endpoint, since, []string{"COMPLETED", "FAILED"}).
+		Scan(counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count finished tasks: %w", err)
+	}
+	return counts, nil
+}
+
+// CountFinishedStartedSince counts the tasks of an endpoint started since since that
+// completed or failed
+func (r *TaskRepository) CountFinishedStartedSince(ctx context.Context, endpoint string, since time.Time) (*FinishedCounts, error) {
+	counts := &FinishedCounts{Endpoint: endpoint}
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Select("COALESCE(SUM(status = 'COMPLETED'), 0) AS completed, COALESCE(SUM(status = 'FAILED'), 0) AS failed").
+		Where("endpoint = ? AND started_at >= ? AND status IN ?", endpoint, since, []string{"COMPLETED", "FAILED"}).
+		Scan(counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count finished tasks: %w", err)
+	}
+	return counts, nil
+}
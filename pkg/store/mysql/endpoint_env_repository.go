@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// UpdateEnv replaces the env of an endpoint when its env version is still expectedVersion and
+// records the change as the next version. ok is false when another change came first.
+func (r *EndpointRepository) UpdateEnv(ctx context.Context, endpointName string, expectedVersion int, env JSONMap, secretEnv JSONStringArray, change *model.EndpointEnvChange) (ok bool, err error) {
+	err = r.ds.ExecTx(ctx, func(txCtx context.Context) error {
+		result := r.ds.DB(txCtx).Model(&Endpoint{}).
+			Where("endpoint = ? AND env_version = ?", endpointName, expectedVersion).
+			Updates(map[string]interface{}{
+				"env":         env,
+				"secret_env":  secretEnv,
+				"env_version": gorm.Expr("env_version + 1"),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update endpoint env: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		ok = true
+		change.Endpoint = endpointName
+		change.Version = expectedVersion + 1
+		if err := r.ds.DB(txCtx).Create(change).Error; err != nil {
+			return fmt.Errorf("failed to record endpoint env change: %w", err)
+		}
+		return nil
+	})
+	return ok, err
+}
+
+// BumpEnvVersion records an env change made outside UpdateEnv (the env was already saved)
+// as the endpoint's next env version
+func (r *EndpointRepository) BumpEnvVersion(ctx context.Context, endpointName string, change *model.EndpointEnvChange) error {
+	return r.ds.ExecTx(ctx, func(txCtx context.Context) error {
+		if err := r.ds.DB(txCtx).Model(&Endpoint{}).
+			Where("endpoint = ?", endpointName).
+			Update("env_version", gorm.Expr("env_version + 1")).Error; err != nil {
+			return fmt.Errorf("failed to bump endpoint env version: %w", err)
+		}
+		var version int
+		if err := r.ds.DB(txCtx).Model(&Endpoint{}).
+			Where("endpoint = ?", endpointName).
+			Select("env_version").
+			Scan(&version).Error; err != nil {
+			return fmt.Errorf("failed to get endpoint env version: %w", err)
+		}
+		change.Endpoint = endpointName
+		change.Version = version
+		if err := r.ds.DB(txCtx).Create(change).Error; err != nil {
+			return fmt.Errorf("failed to record endpoint env change: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListEnvChanges returns the env changes of an endpoint, newest first
+func (r *EndpointRepository) ListEnvChanges(ctx context.Context, endpointName string, limit int) ([]*model.EndpointEnvChange, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	var changes []*model.EndpointEnvChange
+	err := r.ds.DB(ctx).
+		Where("endpoint = ?", endpointName).
+		Order("version DESC").
+		Limit(limit).
+		Find(&changes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint env changes: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EndpointGroupRepository handles endpoint group persistence
+type EndpointGroupRepository struct {
+	ds *Datastore
+}
+
+// NewEndpointGroupRepository creates a new endpoint group repository
+func NewEndpointGroupRepository(ds *Datastore) *EndpointGroupRepository {
+	return &EndpointGroupRepository{ds: ds}
+}
+
+// List returns all groups ordered by name
+func (r *EndpointGroupRepository) List(ctx context.Context) ([]*model.EndpointGroup, error) {
+	var groups []*model.EndpointGroup
+	if err := r.ds.DB(ctx).Order("name ASC").Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to list endpoint groups: %w", err)
+	}
+	return groups, nil
+}
+
+// Get returns a group by name, nil if it does not exist
+func (r *EndpointGroupRepository) Get(ctx context.Context, name string) (*model.EndpointGroup, error) {
+	var group model.EndpointGroup
+	err := r.ds.DB(ctx).Where("name = ?", name).First(&group).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get endpoint group: %w", err)
+	}
+	return &group, nil
+}
+
+// Upsert creates or updates a group by name
+func (r *EndpointGroupRepository) Upsert(ctx context.Context, group *model.EndpointGroup) error {
+	err := r.ds.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"display_name", "description", "max_replicas", "max_gpu_count", "work_stealing", "updated_at"}),
+	}).Create(group).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert endpoint group: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a group and detaches its members
+func (r *EndpointGroupRepository) Delete(ctx context.Context, name string) error {
+	return r.ds.ExecTx(ctx, func(ctx context.Context) error {
+		if err := r.ds.DB(ctx).Model(&AutoscalerConfig{}).Where("group_name = ?", name).Update("group_name", "").Error; err != nil {
+			return fmt.Errorf("failed to detach endpoint group members: %w", err)
+		}
+		if err := r.ds.DB(ctx).Where("name = ?", name).Delete(&model.EndpointGroup{}).Error; err != nil {
+			return fmt.Errorf("failed to delete endpoint group: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListMembers returns the names of endpoints assigned to a group
+func (r *EndpointGroupRepository) ListMembers(ctx context.Context, name string) ([]string, error) {
+	var members []string
+	err := r.ds.DB(ctx).Model(&AutoscalerConfig{}).Where("group_name = ?", name).Order("endpoint ASC").Pluck("endpoint", &members).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint group members: %w", err)
+	}
+	return members, nil
+}
+
+// stealMember is a member of a work stealing group as loaded by ListStealSiblings
+type stealMember struct {
+	Endpoint string `gorm:"column:endpoint"`
+	Image    string `gorm:"column:image"`
+	SpecName string `gorm:"column:spec_name"`
+	Status   string `gorm:"column:status"`
+}
+
+// ListStealSiblings returns the members of the endpoint's group that its workers may take
+// tasks from: the group has work stealing enabled and the member runs the same image and spec
+func (r *EndpointGroupRepository) ListStealSiblings(ctx context.Context, endpoint string) ([]string, error) {
+	var members []stealMember
+	err := r.ds.DB(ctx).Raw(`SELECT m.endpoint, e.image, e.spec_name, e.status FROM autoscaler_configs me
+		JOIN endpoint_groups g ON g.name = me.group_name AND g.work_stealing = 1
+		JOIN autoscaler_configs m ON m.group_name = me.group_name
+		JOIN endpoints e ON e.endpoint = m.endpoint
+		WHERE me.endpoint = ? AND me.group_name <> ''`, endpoint).Scan(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work stealing siblings: %w", err)
+	}
+	return stealSiblings(endpoint, members), nil
+}
+
+// stealSiblings picks the members of a work stealing group that run the endpoint's image and
+// spec, in name order. Deleted endpoints neither steal nor are stolen from.
+func stealSiblings(endpoint string, members []stealMember) []string {
+	var self *stealMember
+	for i := range members {
+		if members[i].Endpoint == endpoint {
+			self = &members[i]
+		}
+	}
+	if self == nil || self.Status == "deleted" {
+		return nil
+	}
+
+	var siblings []string
+	for _, m := range members {
+		if m.Endpoint != endpoint && m.Status != "deleted" && m.Image == self.Image && m.SpecName == self.SpecName {
+			siblings = append(siblings, m.Endpoint)
+		}
+	}
+	sort.Strings(siblings)
+	return siblings
+}
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStealSiblings(t *testing.T) {
+	members := []stealMember{
+		{Endpoint: "flux-eu", Image: "flux:v2", SpecName: "h100", Status: "Running"},
+		{Endpoint: "flux-us", Image: "flux:v2", SpecName: "h100", Status: "Running"},
+		{Endpoint: "flux-asia", Image: "flux:v2", SpecName: "h100", Status: "Running"},
+		{Endpoint: "flux-canary", Image: "flux:v3", SpecName: "h100", Status: "Running"},
+		{Endpoint: "flux-small", Image: "flux:v2", SpecName: "a10", Status: "Running"},
+		{Endpoint: "flux-old", Image: "flux:v2", SpecName: "h100", Status: "deleted"},
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		members  []stealMember
+		expected []string
+	}{
+		{
+			name:     "same image and spec, in name order",
+			endpoint: "flux-eu",
+			members:  members,
+			expected: []string{"flux-asia", "flux-us"},
+		},
+		{
+			name:     "different image has no siblings",
+			endpoint: "flux-canary",
+			members:  members,
+			expected: nil,
+		},
+		{
+			name:     "deleted endpoint does not steal",
+			endpoint: "flux-old",
+			members:  members,
+			expected: nil,
+		},
+		{
+			name:     "not a member",
+			endpoint: "sdxl",
+			members:  members,
+			expected: nil,
+		},
+		{
+			name:     "group without stealing loads no members",
+			endpoint: "flux-eu",
+			members:  nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, stealSiblings(tt.endpoint, tt.members))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// EndpointLogRedactionRepository handles endpoint log redaction rule persistence
+type EndpointLogRedactionRepository struct {
+	ds *Datastore
+}
+
+// NewEndpointLogRedactionRepository creates a new endpoint log redaction repository
+func NewEndpointLogRedactionRepository(ds *Datastore) *EndpointLogRedactionRepository {
+	return &EndpointLogRedactionRepository{ds: ds}
+}
+
+// Latest returns the newest rule version of an endpoint, nil if it has none
+func (r *EndpointLogRedactionRepository) Latest(ctx context.Context, endpoint string) (*model.EndpointLogRedaction, error) {
+	var rules model.EndpointLogRedaction
+	err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("version DESC").First(&rules).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get endpoint log redaction rules: %w", err)
+	}
+	return &rules, nil
+}
+
+// ListVersions returns all rule versions of an endpoint, newest first
+func (r *EndpointLogRedactionRepository) ListVersions(ctx context.Context, endpoint string) ([]*model.EndpointLogRedaction, error) {
+	var versions []*model.EndpointLogRedaction
+	if err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list endpoint log redaction versions: %w", err)
+	}
+	return versions, nil
+}
+
+// Create stores rules as the next version of its endpoint and sets rules.Version
+func (r *EndpointLogRedactionRepository) Create(ctx context.Context, rules *model.EndpointLogRedaction) error {
+	return r.ds.ExecTx(ctx, func(txCtx context.Context) error {
+		var maxVersion int
+		if err := r.ds.DB(txCtx).Model(&model.EndpointLogRedaction{}).
+			Where("endpoint = ?", rules.Endpoint).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return fmt.Errorf("failed to get latest endpoint log redaction version: %w", err)
+		}
+		rules.Version = maxVersion + 1
+		if err := r.ds.DB(txCtx).Create(rules).Error; err != nil {
+			return fmt.Errorf("failed to create endpoint log redaction rules: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SetDispatchPause pauses task dispatch of an endpoint. Pausing a paused endpoint updates the
+// mode and reason. found is false when the endpoint does not exist.
+func (r *EndpointRepository) SetDispatchPause(ctx context.Context, endpointName, mode, reason, pausedBy string) (found bool, err error) {
+	now := time.Now()
+	result := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ?", endpointName).
+		Updates(map[string]interface{}{
+			"dispatch_paused": true,
+			"pause_mode":      mode,
+			"pause_reason":    reason,
+			"paused_by":       pausedBy,
+			"paused_at":       &now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to pause endpoint dispatch: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClearDispatchPause resumes task dispatch of an endpoint. wasPaused is false when the
+// endpoint does not exist or was not paused.
+func (r *EndpointRepository) ClearDispatchPause(ctx context.Context, endpointName string) (wasPaused bool, err error) {
+	result := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ? AND dispatch_paused = ?", endpointName, true).
+		Updates(map[string]interface{}{
+			"dispatch_paused": false,
+			"pause_mode":      "",
+			"pause_reason":    "",
+			"paused_by":       "",
+			"paused_at":       nil,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to resume endpoint dispatch: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsDispatchPaused reports whether task dispatch of an endpoint is paused. It reads a single
+// column since workers ask on every pull.
+func (r *EndpointRepository) IsDispatchPaused(ctx context.Context, endpointName string) (bool, error) {
+	var paused []bool
+	err := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("endpoint = ?", endpointName).
+		Limit(1).
+		Pluck("dispatch_paused", &paused).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check endpoint dispatch pause: %w", err)
+	}
+	return len(paused) > 0 && paused[0], nil
+}
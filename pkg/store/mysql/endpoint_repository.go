@@ -5,17 +5,20 @@ import (
 	"fmt"
 	"time"
 
+	"waverless/pkg/store/mysql/model"
+
 	"gorm.io/gorm"
 )
 
 // EndpointRepository handles endpoint persistence in MySQL
 type EndpointRepository struct {
-	ds *Datastore
+	ds          *Datastore
+	runtimeSync *runtimeStateSync
 }
 
 // NewEndpointRepository creates a new endpoint repository
 func NewEndpointRepository(ds *Datastore) *EndpointRepository {
-	return &EndpointRepository{ds: ds}
+	return &EndpointRepository{ds: ds, runtimeSync: newRuntimeStateSync(DefaultRuntimeStateRefresh)}
 }
 
 // Create creates a new endpoint
@@ -36,13 +39,23 @@ func (r *EndpointRepository) Get(ctx context.Context, endpointName string) (*End
 	return &endpoint, nil
 }
 
-// Update updates an endpoint
+// endpointUpdateOmits are the columns only written by the env, dispatch pause and circuit
+// breaker methods, so saving a stale read cannot undo a concurrent change
+var endpointUpdateOmits = []string{
+	"env_version", "secret_env",
+	"dispatch_paused", "pause_mode", "pause_reason", "paused_by", "paused_at",
+	"circuit_state", "circuit_reason", "circuit_since", "circuit_probe_task", "circuit_failed_probes",
+}
+
+// Update updates an endpoint. See endpointUpdateOmits for the columns it leaves alone.
 func (r *EndpointRepository) Update(ctx context.Context, endpoint *Endpoint) error {
-	return r.ds.DB(ctx).Save(endpoint).Error
+	r.runtimeSync.forget(endpoint.Endpoint)
+	return r.ds.DB(ctx).Omit(endpointUpdateOmits...).Save(endpoint).Error
 }
 
 // Delete soft deletes an endpoint by setting status to 'deleted'
 func (r *EndpointRepository) Delete(ctx context.Context, endpointName string) error {
+	r.runtimeSync.forget(endpointName)
 	return r.ds.DB(ctx).Model(&Endpoint{}).
 		Where("endpoint = ?", endpointName).
 		Update("status", "deleted").Error
@@ -50,6 +63,7 @@ func (r *EndpointRepository) Delete(ctx context.Context, endpointName string) er
 
 // HardDelete physically deletes an endpoint from database
 func (r *EndpointRepository) HardDelete(ctx context.Context, endpointName string) error {
+	r.runtimeSync.forget(endpointName)
 	return r.ds.DB(ctx).Where("endpoint = ?", endpointName).Delete(&Endpoint{}).Error
 }
 
@@ -65,6 +79,31 @@ func (r *EndpointRepository) List(ctx context.Context) ([]*Endpoint, error) {
 	return endpoints, nil
 }
 
+// ListPage retrieves a page of the endpoints that are not deleted, ordered by name
+func (r *EndpointRepository) ListPage(ctx context.Context, limit, offset int) ([]*Endpoint, error) {
+	var endpoints []*Endpoint
+	err := r.ds.DB(ctx).
+		Where("status != ?", "deleted").
+		Order("endpoint ASC").Limit(limit).Offset(offset).
+		Find(&endpoints).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// Count returns the number of endpoints that are not deleted
+func (r *EndpointRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.ds.DB(ctx).Model(&Endpoint{}).
+		Where("status != ?", "deleted").
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count endpoints: %w", err)
+	}
+	return count, nil
+}
+
 // ListAll retrieves all endpoints including deleted ones
 func (r *EndpointRepository) ListAll(ctx context.Context) ([]*Endpoint, error) {
 	var endpoints []*Endpoint
@@ -103,6 +142,7 @@ func (r *EndpointRepository) Exists(ctx context.Context, endpointName string) (b
 
 // UpdateStatus updates endpoint status
 func (r *EndpointRepository) UpdateStatus(ctx context.Context, endpointName string, status string) error {
+	r.runtimeSync.forget(endpointName)
 	return r.ds.DB(ctx).Model(&Endpoint{}).
 		Where("endpoint = ?", endpointName).
 		Updates(map[string]interface{}{
@@ -111,8 +151,16 @@ func (r *EndpointRepository) UpdateStatus(ctx context.Context, endpointName stri
 		}).Error
 }
 
-// UpdateRuntimeState updates endpoint status and runtime state from K8s (merges with existing)
+// UpdateRuntimeState updates endpoint status and runtime state from K8s (merges with existing).
+// Nothing is written when status and state equal the last write, unless it is due for refresh.
 func (r *EndpointRepository) UpdateRuntimeState(ctx context.Context, endpointName, status string, runtimeState map[string]interface{}) error {
+	now := time.Now()
+	fingerprint := runtimeStateFingerprint(status, runtimeState)
+	write, refresh := r.runtimeSync.check(endpointName, fingerprint, now)
+	if !write {
+		return nil
+	}
+
 	// First get existing runtime_state to merge
 	var endpoint Endpoint
 	if err := r.ds.DB(ctx).Where("endpoint = ?", endpointName).First(&endpoint).Error; err == nil {
@@ -126,13 +174,18 @@ func (r *EndpointRepository) UpdateRuntimeState(ctx context.Context, endpointNam
 		}
 	}
 
-	return r.ds.DB(ctx).Model(&Endpoint{}).
+	err := r.ds.DB(ctx).Model(&Endpoint{}).
 		Where("endpoint = ?", endpointName).
 		Updates(map[string]interface{}{
 			"status":        status,
 			"runtime_state": JSONMap(runtimeState),
 			"updated_at":    gorm.Expr("CURRENT_TIMESTAMP(3)"),
 		}).Error
+	if err != nil {
+		return err
+	}
+	r.runtimeSync.written(endpointName, fingerprint, now, refresh)
+	return nil
 }
 
 // GetBySpecName queries endpoints by Spec name
@@ -155,16 +208,18 @@ func (r *EndpointRepository) GetBySpecName(ctx context.Context, specName string)
 //   - ctx: Context for database operations
 //   - endpointName: The name of the endpoint to update
 //   - healthStatus: The new health status (HEALTHY, DEGRADED, UNHEALTHY)
-//   - healthMessage: Optional message describing the health status
+//   - healthReason: Machine-readable reason code (empty when HEALTHY)
+//   - healthMessage: Optional display message describing the health status
 //
 // Returns:
 //   - error if the database update fails
 //
 // Validates: Requirements 5.4, 6.3, 6.4
-func (r *EndpointRepository) UpdateHealthStatus(ctx context.Context, endpointName, healthStatus, healthMessage string) error {
+func (r *EndpointRepository) UpdateHealthStatus(ctx context.Context, endpointName, healthStatus string, healthReason model.HealthReason, healthMessage string) error {
 	now := time.Now()
 	updates := map[string]interface{}{
 		"health_status":        healthStatus,
+		"health_reason":        string(healthReason),
 		"last_health_check_at": now,
 		"updated_at":           gorm.Expr("CURRENT_TIMESTAMP(3)"),
 	}
@@ -204,7 +259,7 @@ func (r *EndpointRepository) GetByHealthStatus(ctx context.Context, healthStatus
 // IsBlockedDueToImageFailure checks if an endpoint is blocked from creating new workers
 // due to image-related failures. An endpoint is blocked when:
 // - health_status is UNHEALTHY
-// - health_message indicates image-related issues
+// - health_reason is an image failure code
 //
 // This implements Property 8: Failed Endpoint Prevents New Pods from the design document.
 //
@@ -214,7 +269,7 @@ func (r *EndpointRepository) GetByHealthStatus(ctx context.Context, healthStatus
 //
 // Returns:
 //   - blocked: true if the endpoint is blocked from creating new workers
-//   - reason: the reason for blocking (empty if not blocked)
+//   - reason: the display message for blocking (empty if not blocked)
 //   - error: if the database query fails
 //
 // Validates: Requirements 5.5
@@ -227,48 +282,18 @@ func (r *EndpointRepository) IsBlockedDueToImageFailure(ctx context.Context, end
 		return false, "", nil // Endpoint doesn't exist, not blocked
 	}
 
-	// Check if endpoint is UNHEALTHY due to image issues
-	if endpoint.HealthStatus == "UNHEALTHY" {
-		// Check if the health message indicates image-related issues
-		if endpoint.HealthMessage != nil && isImageRelatedFailure(*endpoint.HealthMessage) {
-			return true, *endpoint.HealthMessage, nil
-		}
+	if !isBlockingHealth(endpoint.HealthStatus, model.HealthReason(endpoint.HealthReason)) {
+		return false, "", nil
 	}
 
-	return false, "", nil
-}
-
-// isImageRelatedFailure checks if the health message indicates an image-related failure.
-// This is used to determine if new Pod creation should be blocked.
-func isImageRelatedFailure(healthMessage string) bool {
-	// Common image-related failure messages
-	imageRelatedKeywords := []string{
-		"image",                 // English
-		"Image",                 // English (capitalized)
-		"IMAGE",                 // English (uppercase)
-		"Worker startup failed", // Worker startup failed
-	}
-
-	for _, keyword := range imageRelatedKeywords {
-		if contains(healthMessage, keyword) {
-			return true
-		}
+	reason = endpoint.HealthReason
+	if endpoint.HealthMessage != nil && *endpoint.HealthMessage != "" {
+		reason = *endpoint.HealthMessage
 	}
-	return false
+	return true, reason, nil
 }
 
-// contains checks if s contains substr (simple string contains check)
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
-		(len(s) > 0 && len(substr) > 0 && findSubstring(s, substr)))
-}
-
-// findSubstring checks if substr exists in s
-func findSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
+// isBlockingHealth checks if the health status and reason code block new workers
+func isBlockingHealth(healthStatus string, healthReason model.HealthReason) bool {
+	return healthStatus == string(model.HealthStatusUnhealthy) && healthReason.BlocksScaleUp()
 }
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// EndpointTransformRepository handles endpoint transform persistence
+type EndpointTransformRepository struct {
+	ds *Datastore
+}
+
+// NewEndpointTransformRepository creates a new endpoint transform repository
+func NewEndpointTransformRepository(ds *Datastore) *EndpointTransformRepository {
+	return &EndpointTransformRepository{ds: ds}
+}
+
+// Latest returns the newest version of an endpoint's transform, nil if it has none
+func (r *EndpointTransformRepository) Latest(ctx context.Context, endpoint string) (*model.EndpointTransform, error) {
+	var t model.EndpointTransform
+	err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("version DESC").First(&t).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get endpoint transform: %w", err)
+	}
+	return &t, nil
+}
+
+// Get returns a specific version, nil if it does not exist
+func (r *EndpointTransformRepository) Get(ctx context.Context, endpoint string, version int) (*model.EndpointTransform, error) {
+	var t model.EndpointTransform
+	err := r.ds.DB(ctx).Where("endpoint = ? AND version = ?", endpoint, version).First(&t).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get endpoint transform version: %w", err)
+	}
+	return &t, nil
+}
+
+// ListVersions returns all versions of an endpoint's transform, newest first
+func (r *EndpointTransformRepository) ListVersions(ctx context.Context, endpoint string) ([]*model.EndpointTransform, error) {
+	var versions []*model.EndpointTransform
+	if err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list endpoint transform versions: %w", err)
+	}
+	return versions, nil
+}
+
+// Create stores t as the next version of its endpoint and sets t.Version
+func (r *EndpointTransformRepository) Create(ctx context.Context, t *model.EndpointTransform) error {
+	return r.ds.ExecTx(ctx, func(txCtx context.Context) error {
+		var maxVersion int
+		if err := r.ds.DB(txCtx).Model(&model.EndpointTransform{}).
+			Where("endpoint = ?", t.Endpoint).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return fmt.Errorf("failed to get latest endpoint transform version: %w", err)
+		}
+		t.Version = maxVersion + 1
+		if err := r.ds.DB(txCtx).Create(t).Error; err != nil {
+			return fmt.Errorf("failed to create endpoint transform: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EndpointWarningRepository handles endpoint warning persistence
+type EndpointWarningRepository struct {
+	ds *Datastore
+}
+
+// NewEndpointWarningRepository creates a new endpoint warning repository
+func NewEndpointWarningRepository(ds *Datastore) *EndpointWarningRepository {
+	return &EndpointWarningRepository{ds: ds}
+}
+
+// Record creates a warning or refreshes it (message, details, last seen) and counts the occurrence
+func (r *EndpointWarningRepository) Record(ctx context.Context, warning *model.EndpointWarning) error {
+	err := r.ds.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "endpoint"}, {Name: "code"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"source":       warning.Source,
+			"message":      warning.Message,
+			"details":      warning.Details,
+			"worker_id":    warning.WorkerID,
+			"sdk_version":  warning.SDKVersion,
+			"last_seen_at": warning.LastSeenAt,
+			"occurrences":  gorm.Expr("occurrences + 1"),
+		}),
+	}).Create(warning).Error
+	if err != nil {
+		return fmt.Errorf("failed to record endpoint warning: %w", err)
+	}
+	return nil
+}
+
+// List returns the warnings of an endpoint, most recent first
+func (r *EndpointWarningRepository) List(ctx context.Context, endpoint string) ([]*model.EndpointWarning, error) {
+	var warnings []*model.EndpointWarning
+	if err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("last_seen_at DESC").Find(&warnings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list endpoint warnings: %w", err)
+	}
+	return warnings, nil
+}
+
+// Resolve deletes the warnings of a source for an endpoint except the given codes
+func (r *EndpointWarningRepository) Resolve(ctx context.Context, endpoint, source string, keepCodes []string) (int64, error) {
+	query := r.ds.DB(ctx).Where("endpoint = ? AND source = ?", endpoint, source)
+	if len(keepCodes) > 0 {
+		query = query.Where("code NOT IN ?", keepCodes)
+	}
+	result := query.Delete(&model.EndpointWarning{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to resolve endpoint warnings: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
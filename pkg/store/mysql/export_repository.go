@@ -0,0 +1,98 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+// ExportRepository reads rows for analytics export and persists export checkpoints
+type ExportRepository struct {
+	ds *Datastore
+}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository(ds *Datastore) *ExportRepository {
+	return &ExportRepository{ds: ds}
+}
+
+// GetCheckpoint returns the checkpoint for a dataset, nil if it has never been exported
+func (r *ExportRepository) GetCheckpoint(ctx context.Context, dataset string) (*model.ExportCheckpoint, error) {
+	var checkpoint model.ExportCheckpoint
+	err := r.ds.DB(ctx).Where("dataset = ?", dataset).First(&checkpoint).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get export checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// ListCheckpoints returns the checkpoints of all datasets
+func (r *ExportRepository) ListCheckpoints(ctx context.Context) ([]*model.ExportCheckpoint, error) {
+	var checkpoints []*model.ExportCheckpoint
+	err := r.ds.DB(ctx).Order("dataset ASC").Find(&checkpoints).Error
+	return checkpoints, err
+}
+
+// SaveCheckpoint creates or updates the checkpoint of a dataset
+func (r *ExportRepository) SaveCheckpoint(ctx context.Context, checkpoint *model.ExportCheckpoint) error {
+	checkpoint.UpdatedAt = time.Now()
+	return r.ds.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dataset"}},
+		UpdateAll: true,
+	}).Create(checkpoint).Error
+}
+
+// ListGPUUsageRecordsAfter returns GPU usage records with id > afterID created before the given time, ordered by id
+func (r *ExportRepository) ListGPUUsageRecordsAfter(ctx context.Context, afterID int64, createdBefore time.Time, limit int) ([]*model.GPUUsageRecord, error) {
+	var records []*model.GPUUsageRecord
+	err := r.ds.DB(ctx).
+		Where("id > ? AND created_at < ?", afterID, createdBefore).
+		Order("id ASC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gpu usage records for export: %w", err)
+	}
+	return records, nil
+}
+
+// ListTaskEventsAfter returns task events with id > afterID recorded before the given time, ordered by id
+func (r *ExportRepository) ListTaskEventsAfter(ctx context.Context, afterID int64, recordedBefore time.Time, limit int) ([]*model.TaskEvent, error) {
+	var events []*model.TaskEvent
+	err := r.ds.DB(ctx).
+		Where("id > ? AND event_time < ?", afterID, recordedBefore).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task events for journal: %w", err)
+	}
+	return events, nil
+}
+
+// ListFinishedTasksAfter returns finished tasks ordered by (completed_at, id) after the given cursor,
+// completed before completedBefore. Input/output payloads are not loaded.
+func (r *ExportRepository) ListFinishedTasksAfter(ctx context.Context, afterTime time.Time, afterID int64, completedBefore time.Time, limit int) ([]*model.Task, error) {
+	var tasks []*model.Task
+	err := r.ds.DB(ctx).
+		Select("id", "task_id", "endpoint", "status", "error", "worker_id", "created_at", "updated_at", "started_at", "completed_at").
+		Where("status IN ?", []string{"COMPLETED", "FAILED", "CANCELLED"}).
+		Where("completed_at IS NOT NULL AND completed_at < ?", completedBefore).
+		Where("(completed_at > ? OR (completed_at = ? AND id > ?))", afterTime, afterTime, afterID).
+		Order("completed_at ASC, id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list finished tasks for export: %w", err)
+	}
+	return tasks, nil
+}
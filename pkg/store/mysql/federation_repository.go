@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FederationRepository handles federated endpoint persistence
+type FederationRepository struct {
+	ds *Datastore
+}
+
+// NewFederationRepository creates a new federation repository
+func NewFederationRepository(ds *Datastore) *FederationRepository {
+	return &FederationRepository{ds: ds}
+}
+
+// List returns all federated endpoints ordered by name
+func (r *FederationRepository) List(ctx context.Context) ([]*model.FederatedEndpoint, error) {
+	var federations []*model.FederatedEndpoint
+	if err := r.ds.DB(ctx).Order("name ASC").Find(&federations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list federated endpoints: %w", err)
+	}
+	return federations, nil
+}
+
+// Get returns a federated endpoint by name, nil if it does not exist
+func (r *FederationRepository) Get(ctx context.Context, name string) (*model.FederatedEndpoint, error) {
+	var federation model.FederatedEndpoint
+	err := r.ds.DB(ctx).Where("name = ?", name).First(&federation).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get federated endpoint: %w", err)
+	}
+	return &federation, nil
+}
+
+// Save creates or updates a federated endpoint and replaces its members
+func (r *FederationRepository) Save(ctx context.Context, federation *model.FederatedEndpoint, members []*model.FederationMember) error {
+	return r.ds.ExecTx(ctx, func(ctx context.Context) error {
+		err := r.ds.DB(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"display_name", "description", "updated_at"}),
+		}).Create(federation).Error
+		if err != nil {
+			return fmt.Errorf("failed to save federated endpoint: %w", err)
+		}
+		if err := r.ds.DB(ctx).Where("federation = ?", federation.Name).Delete(&model.FederationMember{}).Error; err != nil {
+			return fmt.Errorf("failed to clear federation members: %w", err)
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		if err := r.ds.DB(ctx).Create(&members).Error; err != nil {
+			return fmt.Errorf("failed to save federation members: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete removes a federated endpoint and its members (the backing endpoints are kept)
+func (r *FederationRepository) Delete(ctx context.Context, name string) error {
+	return r.ds.ExecTx(ctx, func(ctx context.Context) error {
+		if err := r.ds.DB(ctx).Where("federation = ?", name).Delete(&model.FederationMember{}).Error; err != nil {
+			return fmt.Errorf("failed to delete federation members: %w", err)
+		}
+		if err := r.ds.DB(ctx).Where("name = ?", name).Delete(&model.FederatedEndpoint{}).Error; err != nil {
+			return fmt.Errorf("failed to delete federated endpoint: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListMembers returns the members of a federated endpoint ordered by priority
+func (r *FederationRepository) ListMembers(ctx context.Context, name string) ([]*model.FederationMember, error) {
+	var members []*model.FederationMember
+	err := r.ds.DB(ctx).Where("federation = ?", name).Order("priority DESC, endpoint ASC").Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list federation members: %w", err)
+	}
+	return members, nil
+}
+
+// UpdateRegionWeights sets the traffic weight of the members in each region
+func (r *FederationRepository) UpdateRegionWeights(ctx context.Context, name string, weights map[string]int) error {
+	return r.ds.ExecTx(ctx, func(ctx context.Context) error {
+		for region, weight := range weights {
+			err := r.ds.DB(ctx).Model(&model.FederationMember{}).
+				Where("federation = ? AND region = ?", name, region).
+				Update("weight", weight).Error
+			if err != nil {
+				return fmt.Errorf("failed to update weight of region %s: %w", region, err)
+			}
+		}
+		return nil
+	})
+}
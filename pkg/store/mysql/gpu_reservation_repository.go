@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// GPUReservationRepository handles GPU reservation persistence
+type GPUReservationRepository struct {
+	ds *Datastore
+}
+
+// NewGPUReservationRepository creates a new GPU reservation repository
+func NewGPUReservationRepository(ds *Datastore) *GPUReservationRepository {
+	return &GPUReservationRepository{ds: ds}
+}
+
+// Create inserts a reservation
+func (r *GPUReservationRepository) Create(ctx context.Context, reservation *model.GPUReservation) error {
+	if err := r.ds.DB(ctx).Create(reservation).Error; err != nil {
+		return fmt.Errorf("failed to create GPU reservation: %w", err)
+	}
+	return nil
+}
+
+// Get returns a reservation by name, nil if it does not exist
+func (r *GPUReservationRepository) Get(ctx context.Context, name string) (*model.GPUReservation, error) {
+	var reservation model.GPUReservation
+	err := r.ds.DB(ctx).Where("name = ?", name).First(&reservation).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get GPU reservation: %w", err)
+	}
+	return &reservation, nil
+}
+
+// ListOverlapping returns reservations whose window overlaps [from, to), ordered by start
+// time. Empty statuses match every status.
+func (r *GPUReservationRepository) ListOverlapping(ctx context.Context, from, to time.Time, statuses ...string) ([]*model.GPUReservation, error) {
+	query := r.ds.DB(ctx).Where("start_time < ? AND end_time > ?", to, from)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	var reservations []*model.GPUReservation
+	if err := query.Order("start_time ASC, name ASC").Find(&reservations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list GPU reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+// ListByStatus returns reservations in the given statuses, ordered by start time
+func (r *GPUReservationRepository) ListByStatus(ctx context.Context, statuses ...string) ([]*model.GPUReservation, error) {
+	var reservations []*model.GPUReservation
+	err := r.ds.DB(ctx).Where("status IN ?", statuses).Order("start_time ASC, name ASC").Find(&reservations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GPU reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+// UpdateStatus moves a reservation from one status to another; false when it was no longer
+// in the expected status (another replica got there first)
+func (r *GPUReservationRepository) UpdateStatus(ctx context.Context, id int64, from, to string, updates map[string]interface{}) (bool, error) {
+	values := map[string]interface{}{"status": to}
+	for k, v := range updates {
+		values[k] = v
+	}
+	result := r.ds.DB(ctx).Model(&model.GPUReservation{}).Where("id = ? AND status = ?", id, from).Updates(values)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update GPU reservation status: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
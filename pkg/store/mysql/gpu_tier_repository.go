@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+// GPUTierRepository handles GPU tier persistence
+type GPUTierRepository struct {
+	ds *Datastore
+}
+
+// NewGPUTierRepository creates a new GPU tier repository
+func NewGPUTierRepository(ds *Datastore) *GPUTierRepository {
+	return &GPUTierRepository{ds: ds}
+}
+
+// ListByEndpoint returns the tiers of an endpoint in routing order
+func (r *GPUTierRepository) ListByEndpoint(ctx context.Context, endpoint string) ([]*model.GPUTier, error) {
+	var tiers []*model.GPUTier
+	err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("position ASC, id ASC").Find(&tiers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GPU tiers: %w", err)
+	}
+	return tiers, nil
+}
+
+// ListParents returns the endpoints that route tasks to the given tier endpoint
+func (r *GPUTierRepository) ListParents(ctx context.Context, tierEndpoint string) ([]string, error) {
+	var parents []string
+	err := r.ds.DB(ctx).Model(&model.GPUTier{}).Where("tier_endpoint = ?", tierEndpoint).
+		Distinct().Order("endpoint ASC").Pluck("endpoint", &parents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GPU tier parents: %w", err)
+	}
+	return parents, nil
+}
+
+// Replace replaces the tiers of an endpoint; no tiers removes them
+func (r *GPUTierRepository) Replace(ctx context.Context, endpoint string, tiers []*model.GPUTier) error {
+	return r.ds.ExecTx(ctx, func(ctx context.Context) error {
+		if err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Delete(&model.GPUTier{}).Error; err != nil {
+			return fmt.Errorf("failed to clear GPU tiers: %w", err)
+		}
+		if len(tiers) == 0 {
+			return nil
+		}
+		if err := r.ds.DB(ctx).Create(&tiers).Error; err != nil {
+			return fmt.Errorf("failed to save GPU tiers: %w", err)
+		}
+		return nil
+	})
+}
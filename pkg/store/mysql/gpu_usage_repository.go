@@ -0,0 +1,483 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+// gpuUsageBucketLayout is the layout used when MySQL returns formatted time buckets
+const gpuUsageBucketLayout = "2006-01-02 15:04:05"
+
+// GPUUsageRepository handles GPU usage records and their aggregated statistics
+type GPUUsageRepository struct {
+	ds *Datastore
+}
+
+// NewGPUUsageRepository creates a new GPU usage repository
+func NewGPUUsageRepository(ds *Datastore) *GPUUsageRepository {
+	return &GPUUsageRepository{ds: ds}
+}
+
+// CreateRecord inserts a GPU usage record, ignoring duplicates for the same task.
+// Returns true if a new row was inserted.
+func (r *GPUUsageRepository) CreateRecord(ctx context.Context, record *model.GPUUsageRecord) (bool, error) {
+	result := r.ds.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create gpu usage record: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetTasksWithoutGPURecords returns finished tasks in [from, to) that have no GPU usage record yet.
+// Results are ordered by tasks.id and start after afterID, so callers can page through
+// tasks that are intentionally skipped (e.g. CPU-only specs) without looping forever.
+func (r *GPUUsageRepository) GetTasksWithoutGPURecords(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]*model.Task, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+
+	var tasks []*model.Task
+	err := r.tasksWithoutGPURecords(ctx, from, to).
+		Select("t.id, t.task_id, t.endpoint, t.status, t.worker_id, t.created_at, t.updated_at, t.started_at, t.completed_at").
+		Where("t.id > ?", afterID).
+		Order("t.id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks without gpu records: %w", err)
+	}
+	return tasks, nil
+}
+
+// CountTasksWithoutGPURecords counts finished tasks in [from, to) that have no GPU usage record
+func (r *GPUUsageRepository) CountTasksWithoutGPURecords(ctx context.Context, from, to time.Time) (int64, error) {
+	var count int64
+	if err := r.tasksWithoutGPURecords(ctx, from, to).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count tasks without gpu records: %w", err)
+	}
+	return count, nil
+}
+
+func (r *GPUUsageRepository) tasksWithoutGPURecords(ctx context.Context, from, to time.Time) *gorm.DB {
+	return r.ds.DB(ctx).
+		Table("tasks t").
+		Joins("LEFT JOIN gpu_usage_records g ON g.task_id = t.task_id").
+		Where("g.id IS NULL").
+		Where("t.status IN ?", []string{"COMPLETED", "FAILED"}).
+		Where("t.started_at IS NOT NULL AND t.completed_at IS NOT NULL").
+		Where("t.completed_at >= ? AND t.completed_at < ?", from, to)
+}
+
+// CountFinishedTasks counts tasks with a run time (started and completed) finished in [from, to)
+func (r *GPUUsageRepository) CountFinishedTasks(ctx context.Context, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.ds.DB(ctx).Model(&model.Task{}).
+		Where("status IN ?", []string{"COMPLETED", "FAILED"}).
+		Where("started_at IS NOT NULL AND completed_at IS NOT NULL").
+		Where("completed_at >= ? AND completed_at < ?", from, to).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count finished tasks: %w", err)
+	}
+	return count, nil
+}
+
+// SumRecords returns the number of GPU usage records completed in [from, to) and their GPU hours
+func (r *GPUUsageRepository) SumRecords(ctx context.Context, from, to time.Time) (int64, float64, error) {
+	var sum struct {
+		Records  int64
+		GPUHours float64
+	}
+	err := r.ds.DB(ctx).Model(&model.GPUUsageRecord{}).
+		Select("COUNT(*) AS records, COALESCE(SUM(gpu_hours), 0) AS gpu_hours").
+		Where("completed_at >= ? AND completed_at < ?", from, to).
+		Scan(&sum).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sum gpu usage records: %w", err)
+	}
+	return sum.Records, sum.GPUHours, nil
+}
+
+// CountRecordsSince counts GPU usage records completed at or after the given time
+func (r *GPUUsageRepository) CountRecordsSince(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	err := r.ds.DB(ctx).Model(&model.GPUUsageRecord{}).
+		Where("completed_at >= ?", since).
+		Count(&count).Error
+	return count, err
+}
+
+// GetEarliestRecordCompletedAt returns the completion time of the oldest record, or nil if there are no records
+func (r *GPUUsageRepository) GetEarliestRecordCompletedAt(ctx context.Context) (*time.Time, error) {
+	var record model.GPUUsageRecord
+	err := r.ds.DB(ctx).Order("completed_at ASC").First(&record).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get earliest gpu usage record: %w", err)
+	}
+	return &record.CompletedAt, nil
+}
+
+// ListRecords returns GPU usage records completed in [from, to)
+func (r *GPUUsageRepository) ListRecords(ctx context.Context, from, to time.Time) ([]*model.GPUUsageRecord, error) {
+	var records []*model.GPUUsageRecord
+	err := r.ds.DB(ctx).
+		Where("completed_at >= ? AND completed_at < ?", from, to).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gpu usage records: %w", err)
+	}
+	return records, nil
+}
+
+// GetDistinctMinuteBuckets returns the distinct minutes in [from, to) that contain GPU usage records
+func (r *GPUUsageRepository) GetDistinctMinuteBuckets(ctx context.Context, from, to time.Time) ([]time.Time, error) {
+	var buckets []string
+	err := r.ds.DB(ctx).Model(&model.GPUUsageRecord{}).
+		Where("completed_at >= ? AND completed_at < ?", from, to).
+		Distinct().
+		Pluck("DATE_FORMAT(completed_at, '%Y-%m-%d %H:%i:00')", &buckets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distinct minute buckets: %w", err)
+	}
+	return parseBuckets(buckets, gpuUsageBucketLayout)
+}
+
+// GetDistinctHourBuckets returns the distinct hours in [from, to) that contain minute statistics
+func (r *GPUUsageRepository) GetDistinctHourBuckets(ctx context.Context, from, to time.Time) ([]time.Time, error) {
+	var buckets []string
+	err := r.ds.DB(ctx).Model(&model.GPUUsageStatisticsMinute{}).
+		Where("time_bucket >= ? AND time_bucket < ?", from, to).
+		Distinct().
+		Pluck("DATE_FORMAT(time_bucket, '%Y-%m-%d %H:00:00')", &buckets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distinct hour buckets: %w", err)
+	}
+	return parseBuckets(buckets, gpuUsageBucketLayout)
+}
+
+// GetDistinctDayBuckets returns the distinct days in [from, to) that contain hourly statistics
+func (r *GPUUsageRepository) GetDistinctDayBuckets(ctx context.Context, from, to time.Time) ([]time.Time, error) {
+	var buckets []string
+	err := r.ds.DB(ctx).Model(&model.GPUUsageStatisticsHourly{}).
+		Where("time_bucket >= ? AND time_bucket < ?", from, to).
+		Distinct().
+		Pluck("DATE_FORMAT(time_bucket, '%Y-%m-%d')", &buckets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distinct day buckets: %w", err)
+	}
+	return parseBuckets(buckets, "2006-01-02")
+}
+
+// ListMinuteStatsInRange returns minute statistics of all scopes in [from, to)
+func (r *GPUUsageRepository) ListMinuteStatsInRange(ctx context.Context, from, to time.Time) ([]*model.GPUUsageStatisticsMinute, error) {
+	var stats []*model.GPUUsageStatisticsMinute
+	err := r.ds.DB(ctx).Where("time_bucket >= ? AND time_bucket < ?", from, to).Find(&stats).Error
+	return stats, err
+}
+
+// ListHourlyStatsInRange returns hourly statistics of all scopes in [from, to)
+func (r *GPUUsageRepository) ListHourlyStatsInRange(ctx context.Context, from, to time.Time) ([]*model.GPUUsageStatisticsHourly, error) {
+	var stats []*model.GPUUsageStatisticsHourly
+	err := r.ds.DB(ctx).Where("time_bucket >= ? AND time_bucket < ?", from, to).Find(&stats).Error
+	return stats, err
+}
+
+// UpsertMinuteStats creates or updates minute-level statistics
+func (r *GPUUsageRepository) UpsertMinuteStats(ctx context.Context, stats []*model.GPUUsageStatisticsMinute) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return r.ds.DB(ctx).Clauses(gpuUsageStatsOnConflict()).Create(&stats).Error
+}
+
+// UpsertHourlyStats creates or updates hourly statistics
+func (r *GPUUsageRepository) UpsertHourlyStats(ctx context.Context, stats []*model.GPUUsageStatisticsHourly) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return r.ds.DB(ctx).Clauses(gpuUsageStatsOnConflict()).Create(&stats).Error
+}
+
+// UpsertDailyStats creates or updates daily statistics
+func (r *GPUUsageRepository) UpsertDailyStats(ctx context.Context, stats []*model.GPUUsageStatisticsDaily) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return r.ds.DB(ctx).Clauses(gpuUsageStatsOnConflict()).Create(&stats).Error
+}
+
+// GetMinuteStats retrieves minute statistics for a scope in [from, to)
+func (r *GPUUsageRepository) GetMinuteStats(ctx context.Context, scopeType, scopeValue string, from, to time.Time) ([]*model.GPUUsageStatisticsMinute, error) {
+	var stats []*model.GPUUsageStatisticsMinute
+	err := r.scopedStatsQuery(ctx, &model.GPUUsageStatisticsMinute{}, scopeType, scopeValue, from, to).Find(&stats).Error
+	return stats, err
+}
+
+// GetHourlyStats retrieves hourly statistics for a scope in [from, to)
+func (r *GPUUsageRepository) GetHourlyStats(ctx context.Context, scopeType, scopeValue string, from, to time.Time) ([]*model.GPUUsageStatisticsHourly, error) {
+	var stats []*model.GPUUsageStatisticsHourly
+	err := r.scopedStatsQuery(ctx, &model.GPUUsageStatisticsHourly{}, scopeType, scopeValue, from, to).Find(&stats).Error
+	return stats, err
+}
+
+// GetDailyStats retrieves daily statistics for a scope in [from, to)
+func (r *GPUUsageRepository) GetDailyStats(ctx context.Context, scopeType, scopeValue string, from, to time.Time) ([]*model.GPUUsageStatisticsDaily, error) {
+	var stats []*model.GPUUsageStatisticsDaily
+	err := r.scopedStatsQuery(ctx, &model.GPUUsageStatisticsDaily{}, scopeType, scopeValue, from, to).Find(&stats).Error
+	return stats, err
+}
+
+// GPUUsageScopeRanking is the aggregated usage of one scope value over a time range
+type GPUUsageScopeRanking struct {
+	ScopeValue     string  `json:"scope_value"`
+	TotalTasks     int64   `json:"total_tasks"`
+	CompletedTasks int64   `json:"completed_tasks"`
+	FailedTasks    int64   `json:"failed_tasks"`
+	FailureRate    float64 `json:"failure_rate"`
+	TotalGPUHours  float64 `json:"total_gpu_hours"`
+	MaxGPUCount    int     `json:"max_gpu_count"`
+	Utilization    float64 `json:"utilization"` // GPU hours / (max GPU count * range hours)
+}
+
+// gpuUsageRankingOrders maps supported order_by values to SQL expressions
+var gpuUsageRankingOrders = map[string]string{
+	"gpu_hours":    "total_gpu_hours",
+	"tasks":        "total_tasks",
+	"failed_tasks": "failed_tasks",
+	"failure_rate": "failure_rate",
+	"utilization":  "utilization",
+}
+
+// IsValidGPUUsageRankingOrder reports whether orderBy is supported by GetTopScopes
+func IsValidGPUUsageRankingOrder(orderBy string) bool {
+	_, ok := gpuUsageRankingOrders[orderBy]
+	return ok
+}
+
+// GetTopScopes ranks scope values of a scope type over [from, to) using the given granularity table.
+// orderBy is one of gpu_hours, tasks, failed_tasks, failure_rate, utilization.
+func (r *GPUUsageRepository) GetTopScopes(ctx context.Context, granularity, scopeType string, from, to time.Time, orderBy string, ascending bool, limit int) ([]*GPUUsageScopeRanking, error) {
+	var table interface{}
+	switch granularity {
+	case model.GPUUsageGranularityMinute:
+		table = &model.GPUUsageStatisticsMinute{}
+	case model.GPUUsageGranularityDaily:
+		table = &model.GPUUsageStatisticsDaily{}
+	default:
+		table = &model.GPUUsageStatisticsHourly{}
+	}
+
+	orderExpr, ok := gpuUsageRankingOrders[orderBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid order_by: %s", orderBy)
+	}
+	direction := "DESC"
+	if ascending {
+		direction = "ASC"
+	}
+
+	rangeHours := to.Sub(from).Hours()
+	if rangeHours <= 0 {
+		rangeHours = 1
+	}
+
+	var rankings []*GPUUsageScopeRanking
+	err := r.ds.DB(ctx).Model(table).
+		Select(`scope_value,
+			SUM(total_tasks) AS total_tasks,
+			SUM(completed_tasks) AS completed_tasks,
+			SUM(failed_tasks) AS failed_tasks,
+			COALESCE(SUM(failed_tasks) / NULLIF(SUM(total_tasks), 0), 0) AS failure_rate,
+			SUM(total_gpu_hours) AS total_gpu_hours,
+			MAX(max_gpu_count) AS max_gpu_count,
+			COALESCE(SUM(total_gpu_hours) / NULLIF(MAX(max_gpu_count) * ?, 0), 0) AS utilization`, rangeHours).
+		Where("scope_type = ? AND time_bucket >= ? AND time_bucket < ?", scopeType, from, to).
+		Group("scope_value").
+		Order(orderExpr + " " + direction + ", scope_value ASC").
+		Limit(limit).
+		Scan(&rankings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank gpu usage scopes: %w", err)
+	}
+	return rankings, nil
+}
+
+// GetAggregationState returns the aggregation state for a granularity, or nil if it has never run
+func (r *GPUUsageRepository) GetAggregationState(ctx context.Context, granularity string) (*model.GPUUsageAggregationState, error) {
+	var state model.GPUUsageAggregationState
+	err := r.ds.DB(ctx).Where("granularity = ?", granularity).First(&state).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get gpu usage aggregation state: %w", err)
+	}
+	return &state, nil
+}
+
+// ListAggregationStates returns the aggregation state of all granularities
+func (r *GPUUsageRepository) ListAggregationStates(ctx context.Context) ([]*model.GPUUsageAggregationState, error) {
+	var states []*model.GPUUsageAggregationState
+	err := r.ds.DB(ctx).Order("granularity ASC").Find(&states).Error
+	return states, err
+}
+
+// SaveAggregationState creates or updates the aggregation state for a granularity
+func (r *GPUUsageRepository) SaveAggregationState(ctx context.Context, state *model.GPUUsageAggregationState) error {
+	state.UpdatedAt = time.Now()
+	return r.ds.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "granularity"}},
+		UpdateAll: true,
+	}).Create(state).Error
+}
+
+// CleanupOldRecords removes GPU usage records completed before the given time
+func (r *GPUUsageRepository) CleanupOldRecords(ctx context.Context, before time.Time) (int64, error) {
+	result := r.ds.DB(ctx).Where("completed_at < ?", before).Delete(&model.GPUUsageRecord{})
+	return result.RowsAffected, result.Error
+}
+
+// CleanupOldMinuteStats removes minute statistics older than the given time
+func (r *GPUUsageRepository) CleanupOldMinuteStats(ctx context.Context, before time.Time) (int64, error) {
+	result := r.ds.DB(ctx).Where("time_bucket < ?", before).Delete(&model.GPUUsageStatisticsMinute{})
+	return result.RowsAffected, result.Error
+}
+
+// CleanupOldHourlyStats removes hourly statistics older than the given time
+func (r *GPUUsageRepository) CleanupOldHourlyStats(ctx context.Context, before time.Time) (int64, error) {
+	result := r.ds.DB(ctx).Where("time_bucket < ?", before).Delete(&model.GPUUsageStatisticsHourly{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *GPUUsageRepository) scopedStatsQuery(ctx context.Context, table interface{}, scopeType, scopeValue string, from, to time.Time) *gorm.DB {
+	if scopeType == "" {
+		scopeType = model.GPUUsageScopeGlobal
+	}
+	query := r.ds.DB(ctx).Model(table).
+		Where("scope_type = ? AND time_bucket >= ? AND time_bucket < ?", scopeType, from, to)
+	if scopeValue != "" {
+		query = query.Where("scope_value = ?", scopeValue)
+	}
+	return query.Order("time_bucket ASC, scope_value ASC")
+}
+
+func gpuUsageStatsOnConflict() clause.OnConflict {
+	return clause.OnConflict{
+		Columns:   []clause.Column{{Name: "time_bucket"}, {Name: "scope_type"}, {Name: "scope_value"}},
+		UpdateAll: true,
+	}
+}
+
+func parseBuckets(values []string, layout string) ([]time.Time, error) {
+	buckets := make([]time.Time, 0, len(values))
+	for _, v := range values {
+		t, err := time.ParseInLocation(layout, v, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time bucket %q: %w", v, err)
+		}
+		buckets = append(buckets, t)
+	}
+	return buckets, nil
+}
+
+// CreateBackfillJob stores a new backfill job
+func (r *GPUUsageRepository) CreateBackfillJob(ctx context.Context, job *model.GPUUsageBackfillJob) error {
+	if err := r.ds.DB(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create gpu usage backfill job: %w", err)
+	}
+	return nil
+}
+
+// GetBackfillJob returns a backfill job, or nil if it does not exist
+func (r *GPUUsageRepository) GetBackfillJob(ctx context.Context, id int64) (*model.GPUUsageBackfillJob, error) {
+	var job model.GPUUsageBackfillJob
+	err := r.ds.DB(ctx).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get gpu usage backfill job: %w", err)
+	}
+	return &job, nil
+}
+
+// ListBackfillJobs returns the most recent backfill jobs, optionally filtered by status
+func (r *GPUUsageRepository) ListBackfillJobs(ctx context.Context, status string, limit, offset int) ([]*model.GPUUsageBackfillJob, error) {
+	var jobs []*model.GPUUsageBackfillJob
+	query := r.ds.DB(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list gpu usage backfill jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// CountBackfillJobs returns the number of backfill jobs, optionally filtered by status
+func (r *GPUUsageRepository) CountBackfillJobs(ctx context.Context, status string) (int64, error) {
+	var count int64
+	query := r.ds.DB(ctx).Model(&model.GPUUsageBackfillJob{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count gpu usage backfill jobs: %w", err)
+	}
+	return count, nil
+}
+
+// GetActiveBackfillJob returns the oldest pending or running backfill job, or nil if there is none
+func (r *GPUUsageRepository) GetActiveBackfillJob(ctx context.Context) (*model.GPUUsageBackfillJob, error) {
+	var job model.GPUUsageBackfillJob
+	err := r.ds.DB(ctx).
+		Where("status IN ?", []string{model.GPUUsageBackfillPending, model.GPUUsageBackfillRunning}).
+		Order("id ASC").First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active gpu usage backfill job: %w", err)
+	}
+	return &job, nil
+}
+
+// SaveBackfillJobProgress stores the progress of an active backfill job. Returns false when the
+// job is no longer pending or running (e.g. it was cancelled meanwhile).
+func (r *GPUUsageRepository) SaveBackfillJobProgress(ctx context.Context, job *model.GPUUsageBackfillJob) (bool, error) {
+	job.UpdatedAt = time.Now()
+	result := r.ds.DB(ctx).Model(&model.GPUUsageBackfillJob{}).
+		Where("id = ? AND status IN ?", job.ID, []string{model.GPUUsageBackfillPending, model.GPUUsageBackfillRunning}).
+		Select("*").Omit("id", "created_at", "created_by").
+		Updates(job)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to save gpu usage backfill job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CancelBackfillJob cancels a pending or running backfill job. Returns false when the job is
+// not active.
+func (r *GPUUsageRepository) CancelBackfillJob(ctx context.Context, id int64) (bool, error) {
+	now := time.Now()
+	result := r.ds.DB(ctx).Model(&model.GPUUsageBackfillJob{}).
+		Where("id = ? AND status IN ?", id, []string{model.GPUUsageBackfillPending, model.GPUUsageBackfillRunning}).
+		Updates(map[string]interface{}{
+			"status":       model.GPUUsageBackfillCancelled,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel gpu usage backfill job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// ImageCopyRepository handles image copy provenance persistence
+type ImageCopyRepository struct {
+	ds *Datastore
+}
+
+// NewImageCopyRepository creates a new image copy repository
+func NewImageCopyRepository(ds *Datastore) *ImageCopyRepository {
+	return &ImageCopyRepository{ds: ds}
+}
+
+// Create stores a copy outcome
+func (r *ImageCopyRepository) Create(ctx context.Context, record *model.ImageCopyRecord) error {
+	if err := r.ds.DB(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to create image copy record: %w", err)
+	}
+	return nil
+}
+
+// ListByEndpoint returns the most recent copy records for an endpoint
+func (r *ImageCopyRepository) ListByEndpoint(ctx context.Context, endpoint string, limit int) ([]*model.ImageCopyRecord, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var records []*model.ImageCopyRecord
+	err := r.ds.DB(ctx).
+		Where("endpoint = ?", endpoint).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list image copy records: %w", err)
+	}
+	return records, nil
+}
+
+// GetByTargetImage returns the most recent successful copy that produced an internal image reference
+func (r *ImageCopyRepository) GetByTargetImage(ctx context.Context, targetImage string) (*model.ImageCopyRecord, error) {
+	var record model.ImageCopyRecord
+	err := r.ds.DB(ctx).
+		Where("target_image = ? AND status = ?", targetImage, model.ImageCopyStatusSucceeded).
+		Order("created_at DESC, id DESC").
+		First(&record).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get image copy record: %w", err)
+	}
+	return &record, nil
+}
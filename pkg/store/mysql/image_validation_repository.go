@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// ImageValidationRepository handles image validation history persistence
+type ImageValidationRepository struct {
+	ds *Datastore
+}
+
+// NewImageValidationRepository creates a new image validation repository
+func NewImageValidationRepository(ds *Datastore) *ImageValidationRepository {
+	return &ImageValidationRepository{ds: ds}
+}
+
+// Create stores a validation outcome
+func (r *ImageValidationRepository) Create(ctx context.Context, record *model.ImageValidationRecord) error {
+	if err := r.ds.DB(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to create image validation record: %w", err)
+	}
+	return nil
+}
+
+// ListByEndpoint returns the most recent validation records for an endpoint
+func (r *ImageValidationRepository) ListByEndpoint(ctx context.Context, endpoint string, limit int) ([]*model.ImageValidationRecord, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var records []*model.ImageValidationRecord
+	err := r.ds.DB(ctx).
+		Where("endpoint = ?", endpoint).
+		Order("checked_at DESC, id DESC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list image validation records: %w", err)
+	}
+	return records, nil
+}
+
+// GetLastVerified returns the most recent successful (exists and accessible) validation for an endpoint
+func (r *ImageValidationRepository) GetLastVerified(ctx context.Context, endpoint string) (*model.ImageValidationRecord, error) {
+	var record model.ImageValidationRecord
+	err := r.ds.DB(ctx).
+		Where("endpoint = ? AND `exists` = ? AND accessible = ?", endpoint, true, true).
+		Order("checked_at DESC, id DESC").
+		First(&record).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get last verified image validation: %w", err)
+	}
+	return &record, nil
+}
+
+// CleanupOldRecords removes validation records checked before the given time
+func (r *ImageValidationRepository) CleanupOldRecords(ctx context.Context, before time.Time) (int64, error) {
+	result := r.ds.DB(ctx).Where("checked_at < ?", before).Delete(&model.ImageValidationRecord{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old image validation records: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IntegrationRepository handles integration persistence
+type IntegrationRepository struct {
+	ds *Datastore
+}
+
+// NewIntegrationRepository creates a new integration repository
+func NewIntegrationRepository(ds *Datastore) *IntegrationRepository {
+	return &IntegrationRepository{ds: ds}
+}
+
+// List returns all integrations ordered by name
+func (r *IntegrationRepository) List(ctx context.Context) ([]*model.Integration, error) {
+	var integrations []*model.Integration
+	if err := r.ds.DB(ctx).Order("name ASC").Find(&integrations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list integrations: %w", err)
+	}
+	return integrations, nil
+}
+
+// Get returns an integration by name, nil if it does not exist
+func (r *IntegrationRepository) Get(ctx context.Context, name string) (*model.Integration, error) {
+	var integration model.Integration
+	if err := r.ds.DB(ctx).Where("name = ?", name).First(&integration).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get integration: %w", err)
+	}
+	return &integration, nil
+}
+
+// Upsert creates or updates an integration by name; delivery state is kept
+func (r *IntegrationRepository) Upsert(ctx context.Context, integration *model.Integration) error {
+	err := r.ds.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"kind", "url", "events", "endpoints", "secret", "max_retries", "enabled", "description", "updated_at"}),
+	}).Create(integration).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert integration: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an integration
+func (r *IntegrationRepository) Delete(ctx context.Context, name string) error {
+	if err := r.ds.DB(ctx).Where("name = ?", name).Delete(&model.Integration{}).Error; err != nil {
+		return fmt.Errorf("failed to delete integration: %w", err)
+	}
+	return nil
+}
+
+// RecordDelivery updates the delivery state of an integration after a delivery finished
+// (successfully or after its last retry)
+func (r *IntegrationRepository) RecordDelivery(ctx context.Context, id int64, at time.Time, attempts, statusCode int, deliveryErr string) error {
+	updates := map[string]interface{}{
+		"last_delivery_at": at,
+		"last_status_code": statusCode,
+		"last_attempts":    attempts,
+		"last_error":       deliveryErr,
+		"total_deliveries": gorm.Expr("total_deliveries + 1"),
+	}
+	if deliveryErr == "" {
+		updates["last_status"] = model.DeliveryStatusSuccess
+		updates["consecutive_failures"] = 0
+	} else {
+		updates["last_status"] = model.DeliveryStatusFailed
+		updates["consecutive_failures"] = gorm.Expr("consecutive_failures + 1")
+		updates["total_failures"] = gorm.Expr("total_failures + 1")
+	}
+	if err := r.ds.DB(ctx).Model(&model.Integration{}).Where("id = ?", id).UpdateColumns(updates).Error; err != nil {
+		return fmt.Errorf("failed to record integration delivery: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// JobScheduleRepository handles recurring job schedule persistence
+type JobScheduleRepository struct {
+	ds *Datastore
+}
+
+// NewJobScheduleRepository creates a new job schedule repository
+func NewJobScheduleRepository(ds *Datastore) *JobScheduleRepository {
+	return &JobScheduleRepository{ds: ds}
+}
+
+// Create saves a new schedule
+func (r *JobScheduleRepository) Create(ctx context.Context, schedule *model.JobSchedule) error {
+	if err := r.ds.DB(ctx).Create(schedule).Error; err != nil {
+		return fmt.Errorf("failed to save job schedule: %w", err)
+	}
+	return nil
+}
+
+// Get returns a schedule by name, nil if it does not exist
+func (r *JobScheduleRepository) Get(ctx context.Context, name string) (*model.JobSchedule, error) {
+	var schedule model.JobSchedule
+	err := r.ds.DB(ctx).Where("name = ?", name).First(&schedule).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// List returns all schedules ordered by name
+func (r *JobScheduleRepository) List(ctx context.Context) ([]*model.JobSchedule, error) {
+	var schedules []*model.JobSchedule
+	if err := r.ds.DB(ctx).Order("name ASC").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list job schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// Save updates all columns of a schedule
+func (r *JobScheduleRepository) Save(ctx context.Context, schedule *model.JobSchedule) error {
+	if err := r.ds.DB(ctx).Save(schedule).Error; err != nil {
+		return fmt.Errorf("failed to update job schedule: %w", err)
+	}
+	return nil
+}
+
+// Update updates the given columns of a schedule
+func (r *JobScheduleRepository) Update(ctx context.Context, name string, updates map[string]interface{}) error {
+	if err := r.ds.DB(ctx).Model(&model.JobSchedule{}).Where("name = ?", name).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update job schedule: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a schedule; its runs are kept
+func (r *JobScheduleRepository) Delete(ctx context.Context, name string) error {
+	if err := r.ds.DB(ctx).Where("name = ?", name).Delete(&model.JobSchedule{}).Error; err != nil {
+		return fmt.Errorf("failed to delete job schedule: %w", err)
+	}
+	return nil
+}
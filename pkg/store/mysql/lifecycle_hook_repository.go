@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+)
+
+// LifecycleHookRepository handles lifecycle hook and hook run persistence
+type LifecycleHookRepository struct {
+	ds *Datastore
+}
+
+// NewLifecycleHookRepository creates a new lifecycle hook repository
+func NewLifecycleHookRepository(ds *Datastore) *LifecycleHookRepository {
+	return &LifecycleHookRepository{ds: ds}
+}
+
+// ListByEndpoint returns the hooks of an endpoint in execution order
+func (r *LifecycleHookRepository) ListByEndpoint(ctx context.Context, endpoint string) ([]*model.LifecycleHook, error) {
+	var hooks []*model.LifecycleHook
+	err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("position ASC, id ASC").Find(&hooks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lifecycle hooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// Replace replaces the hooks of an endpoint; no hooks removes them
+func (r *LifecycleHookRepository) Replace(ctx context.Context, endpoint string, hooks []*model.LifecycleHook) error {
+	return r.ds.ExecTx(ctx, func(ctx context.Context) error {
+		if err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Delete(&model.LifecycleHook{}).Error; err != nil {
+			return fmt.Errorf("failed to clear lifecycle hooks: %w", err)
+		}
+		if len(hooks) == 0 {
+			return nil
+		}
+		if err := r.ds.DB(ctx).Create(&hooks).Error; err != nil {
+			return fmt.Errorf("failed to save lifecycle hooks: %w", err)
+		}
+		return nil
+	})
+}
+
+// CreateRun records a hook execution
+func (r *LifecycleHookRepository) CreateRun(ctx context.Context, run *model.LifecycleHookRun) error {
+	if err := r.ds.DB(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("failed to record lifecycle hook run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns returns the most recent hook executions of an endpoint, newest first
+func (r *LifecycleHookRepository) ListRuns(ctx context.Context, endpoint string, limit int) ([]*model.LifecycleHookRun, error) {
+	var runs []*model.LifecycleHookRun
+	err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("started_at DESC, id DESC").Limit(limit).Find(&runs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lifecycle hook runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,185 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// LoadTestRepository handles load test and load test report persistence
+type LoadTestRepository struct {
+	ds *Datastore
+}
+
+// NewLoadTestRepository creates a new load test repository
+func NewLoadTestRepository(ds *Datastore) *LoadTestRepository {
+	return &LoadTestRepository{ds: ds}
+}
+
+// Create saves a new load test
+func (r *LoadTestRepository) Create(ctx context.Context, test *model.LoadTest) error {
+	if err := r.ds.DB(ctx).Create(test).Error; err != nil {
+		return fmt.Errorf("failed to save load test: %w", err)
+	}
+	return nil
+}
+
+// Get returns a load test by name, nil if it does not exist
+func (r *LoadTestRepository) Get(ctx context.Context, name string) (*model.LoadTest, error) {
+	var test model.LoadTest
+	err := r.ds.DB(ctx).Where("name = ?", name).First(&test).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get load test: %w", err)
+	}
+	return &test, nil
+}
+
+// List returns all load tests ordered by name
+func (r *LoadTestRepository) List(ctx context.Context) ([]*model.LoadTest, error) {
+	var tests []*model.LoadTest
+	if err := r.ds.DB(ctx).Order("name ASC").Find(&tests).Error; err != nil {
+		return nil, fmt.Errorf("failed to list load tests: %w", err)
+	}
+	return tests, nil
+}
+
+// Save updates all columns of a load test
+func (r *LoadTestRepository) Save(ctx context.Context, test *model.LoadTest) error {
+	if err := r.ds.DB(ctx).Save(test).Error; err != nil {
+		return fmt.Errorf("failed to update load test: %w", err)
+	}
+	return nil
+}
+
+// Update updates the given columns of a load test
+func (r *LoadTestRepository) Update(ctx context.Context, name string, updates map[string]interface{}) error {
+	if err := r.ds.DB(ctx).Model(&model.LoadTest{}).Where("name = ?", name).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update load test: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a load test; its reports are kept
+func (r *LoadTestRepository) Delete(ctx context.Context, name string) error {
+	if err := r.ds.DB(ctx).Where("name = ?", name).Delete(&model.LoadTest{}).Error; err != nil {
+		return fmt.Errorf("failed to delete load test: %w", err)
+	}
+	return nil
+}
+
+// CreateReport saves the report of a run that is starting
+func (r *LoadTestRepository) CreateReport(ctx context.Context, report *model.LoadTestReport) error {
+	if err := r.ds.DB(ctx).Create(report).Error; err != nil {
+		return fmt.Errorf("failed to save load test report: %w", err)
+	}
+	return nil
+}
+
+// GetReport returns a report with its curves, nil if it does not exist
+func (r *LoadTestRepository) GetReport(ctx context.Context, id int64) (*model.LoadTestReport, error) {
+	var report model.LoadTestReport
+	err := r.ds.DB(ctx).Where("id = ?", id).First(&report).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get load test report: %w", err)
+	}
+	return &report, nil
+}
+
+// ListReports returns the most recent reports of a load test, newest first, without their
+// curves and comparison
+func (r *LoadTestRepository) ListReports(ctx context.Context, test string, limit, offset int) ([]*model.LoadTestReport, error) {
+	var reports []*model.LoadTestReport
+	err := r.ds.DB(ctx).Omit("report", "comparison").
+		Where("load_test = ?", test).
+		Order("created_at DESC, id DESC").Limit(limit).Offset(offset).
+		Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load test reports: %w", err)
+	}
+	return reports, nil
+}
+
+// CountReports returns the number of reports of a load test
+func (r *LoadTestRepository) CountReports(ctx context.Context, test string) (int64, error) {
+	var count int64
+	if err := r.ds.DB(ctx).Model(&model.LoadTestReport{}).Where("load_test = ?", test).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count load test reports: %w", err)
+	}
+	return count, nil
+}
+
+// ListRunningReports returns the reports of runs that have not finished
+func (r *LoadTestRepository) ListRunningReports(ctx context.Context) ([]*model.LoadTestReport, error) {
+	var reports []*model.LoadTestReport
+	err := r.ds.DB(ctx).Omit("report", "comparison").
+		Where("status = ?", model.LoadTestReportRunning).
+		Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list running load test reports: %w", err)
+	}
+	return reports, nil
+}
+
+// FindBaseline returns the newest succeeded report of a load test older than beforeID that ran
+// another image than image, nil if there is none
+func (r *LoadTestRepository) FindBaseline(ctx context.Context, test, image string, beforeID int64) (*model.LoadTestReport, error) {
+	var report model.LoadTestReport
+	err := r.ds.DB(ctx).
+		Where("load_test = ? AND status = ? AND image <> ? AND id < ?", test, model.LoadTestReportSucceeded, image, beforeID).
+		Order("id DESC").First(&report).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find baseline report: %w", err)
+	}
+	return &report, nil
+}
+
+// UpdateReport updates the given columns of a report that is still running. Returns false when
+// the report was finished in the meantime.
+func (r *LoadTestRepository) UpdateReport(ctx context.Context, id int64, updates map[string]interface{}) (bool, error) {
+	result := r.ds.DB(ctx).Model(&model.LoadTestReport{}).
+		Where("id = ? AND status = ?", id, model.LoadTestReportRunning).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update load test report: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SetOperation links a report to the operation running it
+func (r *LoadTestRepository) SetOperation(ctx context.Context, id int64, operationID string) error {
+	if err := r.ds.DB(ctx).Model(&model.LoadTestReport{}).Where("id = ?", id).Update("operation_id", operationID).Error; err != nil {
+		return fmt.Errorf("failed to update load test report: %w", err)
+	}
+	return nil
+}
+
+// TrimReports deletes the finished reports of a load test beyond the newest keep
+func (r *LoadTestRepository) TrimReports(ctx context.Context, test string, keep int) (int64, error) {
+	var ids []int64
+	err := r.ds.DB(ctx).Model(&model.LoadTestReport{}).
+		Where("load_test = ? AND status <> ?", test, model.LoadTestReportRunning).
+		Order("created_at DESC, id DESC").Offset(keep).Limit(1000).Pluck("id", &ids).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to list old load test reports: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.ds.DB(ctx).Where("id IN ?", ids).Delete(&model.LoadTestReport{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old load test reports: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -1,6 +1,11 @@
 package model
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 // AutoscalerConfig MySQL model for autoscaler_configs table
 type AutoscalerConfig struct {
@@ -25,6 +30,21 @@ type AutoscalerConfig struct {
 	// "disabled" = force disable autoscaling for this endpoint
 	// "enabled" = force enable autoscaling for this endpoint
 	AutoscalerEnabled *string   `gorm:"column:autoscaler_enabled;type:varchar(20)" json:"autoscaler_enabled,omitempty"`
+	// Custom metric scaling: setpoint per worker for the gauge reported in heartbeats (0 = disabled)
+	CustomMetricName   string  `gorm:"column:custom_metric_name;type:varchar(100)" json:"custom_metric_name,omitempty"`
+	CustomMetricTarget float64 `gorm:"column:custom_metric_target;type:double;not null;default:0" json:"custom_metric_target"`
+	// Endpoint group sharing a replica / GPU budget (empty = none)
+	GroupName string `gorm:"column:group_name;type:varchar(255);not null;default:'';index:idx_group_name" json:"group_name,omitempty"`
+	// Autoscaler evaluation interval in seconds (0 = the global interval)
+	EvaluationInterval int `gorm:"column:evaluation_interval;type:int;not null;default:0" json:"evaluation_interval"`
+	// Longest a task should wait in the queue, in seconds; scale-ups start early to keep it (0 = none)
+	QueueWaitSLO int `gorm:"column:queue_wait_slo;type:int;not null;default:0" json:"queue_wait_slo"`
+	// Metric sources the endpoint is also scaled on (GPU utilization, latency, Prometheus query)
+	MetricSources AutoscalerMetricSources `gorm:"column:metric_sources;type:json" json:"metric_sources,omitempty"`
+	// Cron-based windows overriding min/max replicas
+	ReplicaSchedules AutoscalerReplicaSchedules `gorm:"column:replica_schedules;type:json" json:"replica_schedules,omitempty"`
+	// Resource profile the settings were last expanded from (empty = none)
+	Profile string `gorm:"column:profile;type:varchar(20);not null;default:''" json:"profile,omitempty"`
 	// Time tracking fields (for autoscaler decisions)
 	LastTaskTime     *time.Time `gorm:"column:last_task_time;type:datetime(3)" json:"last_task_time,omitempty"`     // Last task completion time (for idle time calculation)
 	LastScaleTime    *time.Time `gorm:"column:last_scale_time;type:datetime(3)" json:"last_scale_time,omitempty"`   // Last scaling time (for cooldown)
@@ -37,3 +57,75 @@ type AutoscalerConfig struct {
 func (AutoscalerConfig) TableName() string {
 	return "autoscaler_configs"
 }
+
+// AutoscalerMetricSource is a metric an endpoint is scaled on
+type AutoscalerMetricSource struct {
+	Type       string  `json:"type"`
+	Target     float64 `json:"target"`
+	Query      string  `json:"query,omitempty"`
+	TargetType string  `json:"targetType,omitempty"`
+}
+
+// AutoscalerMetricSources is a JSON column of metric sources
+type AutoscalerMetricSources []AutoscalerMetricSource
+
+// Scan implements sql.Scanner interface
+func (m *AutoscalerMetricSources) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal AutoscalerMetricSources value: %v", value)
+	}
+	result := make([]AutoscalerMetricSource, 0)
+	err := json.Unmarshal(bytes, &result)
+	*m = AutoscalerMetricSources(result)
+	return err
+}
+
+// Value implements driver.Valuer interface
+func (m AutoscalerMetricSources) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// AutoscalerReplicaSchedule is a cron-based window overriding the min/max replicas of an endpoint
+type AutoscalerReplicaSchedule struct {
+	Name            string `json:"name"`
+	Cron            string `json:"cron"`
+	DurationMinutes int    `json:"durationMinutes"`
+	Timezone        string `json:"timezone,omitempty"`
+	MinReplicas     int    `json:"minReplicas"`
+	MaxReplicas     int    `json:"maxReplicas"`
+}
+
+// AutoscalerReplicaSchedules is a JSON column of replica schedules
+type AutoscalerReplicaSchedules []AutoscalerReplicaSchedule
+
+// Scan implements sql.Scanner interface
+func (m *AutoscalerReplicaSchedules) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal AutoscalerReplicaSchedules value: %v", value)
+	}
+	result := make([]AutoscalerReplicaSchedule, 0)
+	err := json.Unmarshal(bytes, &result)
+	*m = AutoscalerReplicaSchedules(result)
+	return err
+}
+
+// Value implements driver.Valuer interface
+func (m AutoscalerReplicaSchedules) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// BatchJobStatusCancelled is the status of a job stopped through the API; the other statuses
+// are the provider job phases (pending, running, succeeded, failed)
+const BatchJobStatusCancelled = "cancelled"
+
+// BatchJob is a one-off run of an image on a spec (e.g. fine-tuning or evaluation): it runs
+// to completion once, outside the endpoint/queue model
+type BatchJob struct {
+	ID             int64           `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name           string          `gorm:"column:name;type:varchar(63);not null;uniqueIndex:uk_name" json:"name"`
+	SpecName       string          `gorm:"column:spec_name;type:varchar(100);not null" json:"spec_name"`
+	Image          string          `gorm:"column:image;type:varchar(500);not null" json:"image"`
+	Command        JSONStringArray `gorm:"column:command;type:json" json:"command,omitempty"`
+	Args           JSONStringArray `gorm:"column:args;type:json" json:"args,omitempty"`
+	Env            JSONMap         `gorm:"column:env;type:json" json:"env,omitempty"`
+	VolumeMounts   JSONMap         `gorm:"column:volume_mounts;type:json" json:"volume_mounts,omitempty"` // Mount path -> PVC name
+	GpuCount       int             `gorm:"column:gpu_count;type:int;not null;default:0" json:"gpu_count"` // Allocated GPUs, 0 on CPU specs
+	GPUType        string          `gorm:"column:gpu_type;type:varchar(100);not null;default:''" json:"gpu_type,omitempty"`
+	ShmSize        string          `gorm:"column:shm_size;type:varchar(20);not null;default:''" json:"shm_size,omitempty"`
+	TimeoutSeconds int             `gorm:"column:timeout_seconds;type:int;not null;default:0" json:"timeout_seconds"` // 0 = no limit
+	Status         string          `gorm:"column:status;type:varchar(20);not null;default:pending;index:idx_status" json:"status"`
+	Message        string          `gorm:"column:message;type:varchar(1024);not null;default:''" json:"message,omitempty"` // Why the job is pending or failed
+	ExitCode       *int            `gorm:"column:exit_code" json:"exit_code,omitempty"`
+	PodName        string          `gorm:"column:pod_name;type:varchar(255);not null;default:''" json:"pod_name,omitempty"`
+	GPUHours       float64         `gorm:"column:gpu_hours;type:decimal(12,4);not null;default:0" json:"gpu_hours"` // Recorded when the job finishes
+	Logs           string          `gorm:"column:logs;type:mediumtext" json:"-"`                                    // Log tail kept after the provider removes the job
+	CreatedBy      string          `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	ScheduleName   string          `gorm:"column:schedule_name;type:varchar(45);not null;default:'';index:idx_schedule_created,priority:1" json:"schedule_name,omitempty"` // Recurring schedule that started the run
+	Attempt        int             `gorm:"column:attempt;type:int;not null;default:0" json:"attempt,omitempty"`                                                            // Attempt of a scheduled run, 1 = first (0 = not scheduled)
+	ScheduledAt    *time.Time      `gorm:"column:scheduled_at;type:datetime(3)" json:"scheduled_at,omitempty"`                                                             // Fire time of a scheduled run, shared by its retries
+	StartedAt      *time.Time      `gorm:"column:started_at;type:datetime(3)" json:"started_at,omitempty"`
+	CompletedAt    *time.Time      `gorm:"column:completed_at;type:datetime(3)" json:"completed_at,omitempty"`
+	CreatedAt      time.Time       `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime;index:idx_created_at;index:idx_schedule_created,priority:2" json:"created_at"`
+	UpdatedAt      time.Time       `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for BatchJob
+func (BatchJob) TableName() string {
+	return "batch_jobs"
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Change request operations
+const (
+	ChangeOpDeploy                = "deploy"                  // POST /endpoints (re)deploy
+	ChangeOpUpdateDeployment      = "update_deployment"       // PATCH /endpoints/:name/deployment
+	ChangeOpUpdateConfig          = "update_config"           // PUT /endpoints/:name
+	ChangeOpDelete                = "delete"                  // DELETE /endpoints/:name
+	ChangeOpUpdateEnv             = "update_env"              // PATCH /endpoints/:name/env
+	ChangeOpSaveHooks             = "save_hooks"              // PUT /endpoints/:name/hooks
+	ChangeOpDeleteHooks           = "delete_hooks"            // DELETE /endpoints/:name/hooks
+	ChangeOpSaveTransform         = "save_transform"          // PUT /endpoints/:name/transforms
+	ChangeOpRollbackTransform     = "rollback_transform"      // POST /endpoints/:name/transforms/rollback
+	ChangeOpSaveGPUTiers          = "save_gpu_tiers"          // PUT /endpoints/:name/gpu-tiers
+	ChangeOpDeleteGPUTiers        = "delete_gpu_tiers"        // DELETE /endpoints/:name/gpu-tiers
+	ChangeOpSaveSampling          = "save_sampling"           // PUT /endpoints/:name/sampling
+	ChangeOpDeleteSampling        = "delete_sampling"         // DELETE /endpoints/:name/sampling
+	ChangeOpSaveLogRedaction      = "save_log_redaction"      // PUT /endpoints/:name/log-redaction
+	ChangeOpPauseDispatch         = "pause_dispatch"          // POST /endpoints/:name/pause
+	ChangeOpResumeDispatch        = "resume_dispatch"         // POST /endpoints/:name/resume
+	ChangeOpCloseCircuit          = "close_circuit"           // POST /endpoints/:name/circuit/close
+	ChangeOpUpdateAutoscaling     = "update_autoscaling"      // PUT /autoscaler/endpoints/:name
+	ChangeOpCreateReplicaSchedule = "create_replica_schedule" // POST /autoscaler/endpoints/:name/schedules
+	ChangeOpUpdateReplicaSchedule = "update_replica_schedule" // PUT /autoscaler/endpoints/:name/schedules/:schedule
+	ChangeOpDeleteReplicaSchedule = "delete_replica_schedule" // DELETE /autoscaler/endpoints/:name/schedules/:schedule
+)
+
+// Change request statuses
+const (
+	ChangeStatusPending  = "pending"  // Waiting for a second approver
+	ChangeStatusApproved = "approved" // Approved, executing
+	ChangeStatusExecuted = "executed" // Approved and applied
+	ChangeStatusFailed   = "failed"   // Approved but the operation failed
+	ChangeStatusRejected = "rejected" // Rejected or withdrawn
+	ChangeStatusExpired  = "expired"  // Not reviewed before ExpiresAt
+)
+
+// ChangeRequest is a mutating endpoint operation held for approval
+type ChangeRequest struct {
+	ID            int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint      string     `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint_created,priority:1" json:"endpoint"`
+	Operation     string     `gorm:"column:operation;type:varchar(32);not null" json:"operation"`
+	Payload       JSONMap    `gorm:"column:payload;type:json" json:"payload,omitempty"` // The original request body
+	Status        string     `gorm:"column:status;type:varchar(16);not null;index:idx_status" json:"status"`
+	RequestedBy   string     `gorm:"column:requested_by;type:varchar(255);not null" json:"requested_by"`
+	ReviewedBy    string     `gorm:"column:reviewed_by;type:varchar(255);not null;default:''" json:"reviewed_by,omitempty"`
+	ReviewComment string     `gorm:"column:review_comment;type:varchar(500);not null;default:''" json:"review_comment,omitempty"`
+	Error         string     `gorm:"column:error;type:varchar(1024);not null;default:''" json:"error,omitempty"` // Execution error
+	ExpiresAt     time.Time  `gorm:"column:expires_at;type:datetime(3);not null" json:"expires_at"`
+	ReviewedAt    *time.Time `gorm:"column:reviewed_at;type:datetime(3)" json:"reviewed_at,omitempty"`
+	ExecutedAt    *time.Time `gorm:"column:executed_at;type:datetime(3)" json:"executed_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime;index:idx_endpoint_created,priority:2" json:"created_at"`
+}
+
+// TableName specifies the table name for ChangeRequest
+func (ChangeRequest) TableName() string {
+	return "change_requests"
+}
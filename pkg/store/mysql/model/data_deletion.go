@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Data deletion modes
+const (
+	DataDeletionModePurge     = "purge"     // Delete tasks, their events and samples
+	DataDeletionModeAnonymize = "anonymize" // Keep task rows for statistics, erase payloads
+)
+
+// Data deletion request status
+const (
+	DataDeletionStatusCompleted = "completed" // Everything of the subject was erased
+	DataDeletionStatusPartial   = "partial"   // Active tasks or failed objects remain; run again later
+	DataDeletionStatusFailed    = "failed"
+)
+
+// DataDeletionRequest records a data deletion request of a subject and its report
+type DataDeletionRequest struct {
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	SubjectHash string     `gorm:"column:subject_hash;type:varchar(64);not null;index:idx_subject_hash" json:"subject_hash"`
+	Mode        string     `gorm:"column:mode;type:varchar(20);not null" json:"mode"`
+	Status      string     `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Report      JSONMap    `gorm:"column:report;type:json" json:"report"`                               // Counts, skipped tasks and errors
+	RequestedBy string     `gorm:"column:requested_by;type:varchar(255)" json:"requested_by,omitempty"` // X-Requested-By of the request
+	CreatedAt   time.Time  `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	CompletedAt *time.Time `gorm:"column:completed_at;type:datetime(3)" json:"completed_at,omitempty"`
+}
+
+// TableName specifies the table name for DataDeletionRequest
+func (DataDeletionRequest) TableName() string {
+	return "data_deletion_requests"
+}
+
+// SampleSubject indexes which sample object holds a sample of a data subject, so deletion
+// requests can rewrite exactly those objects
+type SampleSubject struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	SubjectHash string    `gorm:"column:subject_hash;type:varchar(64);not null;index:idx_subject_hash" json:"subject_hash"`
+	TaskID      string    `gorm:"column:task_id;type:varchar(255);not null" json:"task_id"`
+	Endpoint    string    `gorm:"column:endpoint;type:varchar(255);not null" json:"endpoint"`
+	ObjectKey   string    `gorm:"column:object_key;type:varchar(1000);not null" json:"object_key"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for SampleSubject
+func (SampleSubject) TableName() string {
+	return "sample_subjects"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// EncryptionKey is one data key version of a project, stored wrapped by a KMS.
+// The highest version is used to encrypt new task payloads; older versions stay for decryption.
+type EncryptionKey struct {
+	ID         int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Project    string    `gorm:"column:project;type:varchar(255);not null;uniqueIndex:uk_project_version,priority:1" json:"project"`
+	Version    int       `gorm:"column:version;type:int;not null;uniqueIndex:uk_project_version,priority:2" json:"version"`
+	WrappedKey string    `gorm:"column:wrapped_key;type:text;not null" json:"-"`  // Data key encrypted by the KMS
+	KMS        string    `gorm:"column:kms;type:varchar(50);not null" json:"kms"` // KMS that wrapped the key (local, vault-transit)
+	CreatedAt  time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for EncryptionKey
+func (EncryptionKey) TableName() string {
+	return "encryption_keys"
+}
@@ -11,31 +11,72 @@ const (
 	HealthStatusUnhealthy HealthStatus = "UNHEALTHY" // All workers failed or image issue
 )
 
+// HealthReason is the machine-readable cause of a non-HEALTHY status.
+// Logic branches on the reason; health_message is for display only.
+// Values match the worker failure types they are derived from.
+type HealthReason string
+
+const (
+	HealthReasonNone            HealthReason = ""
+	HealthReasonImagePullFailed HealthReason = "IMAGE_PULL_FAILED" // Workers cannot pull the image
+	HealthReasonContainerCrash  HealthReason = "CONTAINER_CRASH"   // Workers crash after starting
+)
+
+// BlocksScaleUp reports whether adding workers cannot help until the image is changed
+func (r HealthReason) BlocksScaleUp() bool {
+	return r == HealthReasonImagePullFailed
+}
+
+// Pause modes: what happens to submissions while task dispatch of an endpoint is paused
+const (
+	PauseModeQueue  = "queue"  // Submissions are queued and dispatched after resume
+	PauseModeReject = "reject" // Submissions are rejected
+)
+
 // Endpoint MySQL model for endpoints table
 type Endpoint struct {
-	ID                int64      `gorm:"primaryKey;autoIncrement" json:"id"`
-	Endpoint          string     `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:idx_endpoint_unique" json:"endpoint"`
-	SpecName          string     `gorm:"column:spec_name;type:varchar(100);not null" json:"spec_name"`
-	Description       string     `gorm:"column:description;type:varchar(500);not null;default:''" json:"description"`
-	Image             string     `gorm:"column:image;type:varchar(500);not null" json:"image"`
-	ImagePrefix       string     `gorm:"column:image_prefix;type:varchar(500);not null;default:''" json:"image_prefix"`
-	ImageDigest       string     `gorm:"column:image_digest;type:varchar(255);not null;default:''" json:"image_digest"`
-	ImageLastChecked  *time.Time `gorm:"column:image_last_checked;type:datetime(3)" json:"image_last_checked"`
-	LatestImage       string     `gorm:"column:latest_image;type:varchar(500);not null;default:''" json:"latest_image"`
-	Replicas          int        `gorm:"column:replicas;type:int;not null;default:1" json:"replicas"`
-	GpuCount          int        `gorm:"column:gpu_count;type:int;not null;default:1" json:"gpu_count"`
-	TaskTimeout       int        `gorm:"column:task_timeout;type:int;not null;default:0" json:"task_timeout"`
-	EnablePtrace      bool       `gorm:"column:enable_ptrace;type:tinyint(1);not null;default:0" json:"enable_ptrace"`
-	MaxPendingTasks   int        `gorm:"column:max_pending_tasks;type:int;not null;default:1" json:"max_pending_tasks"`
-	Env               JSONMap    `gorm:"column:env;type:json" json:"env"`
-	Labels            JSONMap    `gorm:"column:labels;type:json" json:"labels"`
-	RuntimeState      JSONMap    `gorm:"column:runtime_state;type:json" json:"runtime_state"` // K8s runtime: namespace, readyReplicas, availableReplicas, shmSize, volumeMounts
-	Status            string     `gorm:"column:status;type:varchar(50);not null;default:active;index:idx_status" json:"status"`
-	HealthStatus      string     `gorm:"column:health_status;type:varchar(16);not null;default:HEALTHY;index:idx_health_status" json:"health_status"`
-	HealthMessage     *string    `gorm:"column:health_message;type:varchar(512)" json:"health_message,omitempty"`
-	LastHealthCheckAt *time.Time `gorm:"column:last_health_check_at;type:datetime(3)" json:"last_health_check_at,omitempty"`
-	CreatedAt         time.Time  `gorm:"column:created_at;type:datetime(3);not null;default:CURRENT_TIMESTAMP(3);index:idx_created_at" json:"created_at"`
-	UpdatedAt         time.Time  `gorm:"column:updated_at;type:datetime(3);not null;default:CURRENT_TIMESTAMP(3)" json:"updated_at"`
+	ID                  int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Endpoint            string          `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:idx_endpoint_unique" json:"endpoint"`
+	Provider            string          `gorm:"column:provider;type:varchar(32);not null;default:''" json:"provider"` // Deployment provider the endpoint is pinned to (empty = default)
+	SpecName            string          `gorm:"column:spec_name;type:varchar(100);not null" json:"spec_name"`
+	Description         string          `gorm:"column:description;type:varchar(500);not null;default:''" json:"description"`
+	Image               string          `gorm:"column:image;type:varchar(500);not null" json:"image"`
+	ImagePrefix         string          `gorm:"column:image_prefix;type:varchar(500);not null;default:''" json:"image_prefix"`
+	ImageDigest         string          `gorm:"column:image_digest;type:varchar(255);not null;default:''" json:"image_digest"`
+	ImageLastChecked    *time.Time      `gorm:"column:image_last_checked;type:datetime(3)" json:"image_last_checked"`
+	LatestImage         string          `gorm:"column:latest_image;type:varchar(500);not null;default:''" json:"latest_image"`
+	ModelRegistry       string          `gorm:"column:model_registry;type:varchar(32);not null;default:''" json:"model_registry"`               // Registry the image was resolved from (empty = deployed by image)
+	ModelName           string          `gorm:"column:model_name;type:varchar(255);not null;default:'';index:idx_model_name" json:"model_name"` // Registered model name
+	ModelVersion        string          `gorm:"column:model_version;type:varchar(64);not null;default:''" json:"model_version"`
+	ModelURL            string          `gorm:"column:model_url;type:varchar(1000);not null;default:''" json:"model_url"` // The version in the registry
+	Replicas            int             `gorm:"column:replicas;type:int;not null;default:1" json:"replicas"`
+	GpuCount            int             `gorm:"column:gpu_count;type:int;not null;default:1" json:"gpu_count"`
+	TaskTimeout         int             `gorm:"column:task_timeout;type:int;not null;default:0" json:"task_timeout"`
+	EnablePtrace        bool            `gorm:"column:enable_ptrace;type:tinyint(1);not null;default:0" json:"enable_ptrace"`
+	RunPodCompatOff     bool            `gorm:"column:runpod_compat_disabled;type:tinyint(1);not null;default:0" json:"runpod_compat_disabled"` // Stored inverted so existing rows keep the layer enabled
+	MaxPendingTasks     int             `gorm:"column:max_pending_tasks;type:int;not null;default:1" json:"max_pending_tasks"`
+	Env                 JSONMap         `gorm:"column:env;type:json" json:"env"`
+	EnvVersion          int             `gorm:"column:env_version;type:int;not null;default:0" json:"env_version"` // Bumped by every env change, for optimistic concurrency
+	SecretEnv           JSONStringArray `gorm:"column:secret_env;type:json" json:"secret_env"`                     // Names of secret env vars; values only live in the provider's secret store
+	Labels              JSONMap         `gorm:"column:labels;type:json" json:"labels"`
+	DispatchPaused      bool            `gorm:"column:dispatch_paused;type:tinyint(1);not null;default:0" json:"dispatch_paused"` // Workers get no tasks; replicas are kept
+	PauseMode           string          `gorm:"column:pause_mode;type:varchar(16);not null;default:''" json:"pause_mode"`         // queue or reject (PauseMode* constants)
+	PauseReason         string          `gorm:"column:pause_reason;type:varchar(512);not null;default:''" json:"pause_reason"`
+	PausedBy            string          `gorm:"column:paused_by;type:varchar(255);not null;default:''" json:"paused_by"`
+	PausedAt            *time.Time      `gorm:"column:paused_at;type:datetime(3)" json:"paused_at,omitempty"`
+	CircuitState        string          `gorm:"column:circuit_state;type:varchar(16);not null;default:''" json:"circuit_state"` // '', open or half_open (circuit.State* constants)
+	CircuitReason       string          `gorm:"column:circuit_reason;type:varchar(512);not null;default:''" json:"circuit_reason"`
+	CircuitSince        *time.Time      `gorm:"column:circuit_since;type:datetime(3)" json:"circuit_since,omitempty"`                      // Last circuit state change
+	CircuitProbeTask    string          `gorm:"column:circuit_probe_task;type:varchar(255);not null;default:''" json:"circuit_probe_task"` // Task admitted while half-open
+	CircuitFailedProbes int             `gorm:"column:circuit_failed_probes;type:int;not null;default:0" json:"circuit_failed_probes"`
+	RuntimeState        JSONMap         `gorm:"column:runtime_state;type:json" json:"runtime_state"` // K8s runtime: namespace, readyReplicas, availableReplicas, shmSize, volumeMounts
+	Status              string          `gorm:"column:status;type:varchar(50);not null;default:active;index:idx_status" json:"status"`
+	HealthStatus        string          `gorm:"column:health_status;type:varchar(16);not null;default:HEALTHY;index:idx_health_status" json:"health_status"`
+	HealthReason        string          `gorm:"column:health_reason;type:varchar(64);not null;default:''" json:"health_reason"`
+	HealthMessage       *string         `gorm:"column:health_message;type:varchar(512)" json:"health_message,omitempty"`
+	LastHealthCheckAt   *time.Time      `gorm:"column:last_health_check_at;type:datetime(3)" json:"last_health_check_at,omitempty"`
+	CreatedAt           time.Time       `gorm:"column:created_at;type:datetime(3);not null;default:CURRENT_TIMESTAMP(3);index:idx_created_at" json:"created_at"`
+	UpdatedAt           time.Time       `gorm:"column:updated_at;type:datetime(3);not null;default:CURRENT_TIMESTAMP(3)" json:"updated_at"`
 }
 
 // TableName specifies the table name for Endpoint
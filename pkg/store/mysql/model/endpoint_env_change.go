@@ -0,0 +1,66 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Sources of endpoint env changes
+const (
+	EnvChangeSourceAPI    = "env_api"    // PATCH /endpoints/:name/env
+	EnvChangeSourceDeploy = "deployment" // Env replaced by a deploy or deployment update
+)
+
+// EnvVarChange is one env var added, removed or changed
+type EnvVarChange struct {
+	Name   string `json:"name"`
+	Op     string `json:"op"`               // added, removed, changed
+	Secret bool   `json:"secret,omitempty"` // Secret values are never recorded
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// EnvVarChanges is a JSON column of env var changes
+type EnvVarChanges []EnvVarChange
+
+// Scan implements sql.Scanner interface
+func (c *EnvVarChanges) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal EnvVarChanges value: %v", value)
+	}
+	result := make([]EnvVarChange, 0)
+	err := json.Unmarshal(bytes, &result)
+	*c = EnvVarChanges(result)
+	return err
+}
+
+// Value implements driver.Valuer interface
+func (c EnvVarChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// EndpointEnvChange records one env version of an endpoint (the env activity feed)
+type EndpointEnvChange struct {
+	ID        int64         `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint  string        `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint_version,priority:1" json:"endpoint"`
+	Version   int           `gorm:"column:version;type:int;not null;index:idx_endpoint_version,priority:2" json:"version"` // Env version after the change
+	Source    string        `gorm:"column:source;type:varchar(32);not null" json:"source"`
+	Changes   EnvVarChanges `gorm:"column:changes;type:json" json:"changes"`
+	ChangedBy string        `gorm:"column:changed_by;type:varchar(255);not null;default:''" json:"changed_by,omitempty"` // X-Requested-By of the request
+	CreatedAt time.Time     `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for EndpointEnvChange
+func (EndpointEnvChange) TableName() string {
+	return "endpoint_env_changes"
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// EndpointGroup is a set of endpoints sharing a replica / GPU budget.
+// Members reference the group through AutoscalerConfig.GroupName.
+type EndpointGroup struct {
+	ID           int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name         string    `gorm:"column:name;type:varchar(255);not null;uniqueIndex:uk_name" json:"name"`
+	DisplayName  string    `gorm:"column:display_name;type:varchar(255);not null;default:''" json:"display_name"`
+	Description  string    `gorm:"column:description;type:varchar(512);not null;default:''" json:"description"`
+	MaxReplicas  int       `gorm:"column:max_replicas;type:int;not null;default:0" json:"max_replicas"`          // Total replicas across members (0 = unlimited)
+	MaxGPUCount  int       `gorm:"column:max_gpu_count;type:int;not null;default:0" json:"max_gpu_count"`        // Total GPUs across members (0 = unlimited)
+	WorkStealing bool      `gorm:"column:work_stealing;type:tinyint(1);not null;default:0" json:"work_stealing"` // Idle workers take tasks of members with the same image and spec
+	CreatedAt    time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for EndpointGroup
+func (EndpointGroup) TableName() string {
+	return "endpoint_groups"
+}
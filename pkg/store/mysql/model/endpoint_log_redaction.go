@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// EndpointLogRedaction is one immutable version of an endpoint's log redaction rules.
+// Versions are never updated, so the table doubles as the audit trail of rule changes.
+type EndpointLogRedaction struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint  string    `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:uk_endpoint_version,priority:1" json:"endpoint"`
+	Version   int       `gorm:"column:version;type:int;not null;uniqueIndex:uk_endpoint_version,priority:2" json:"version"`
+	Rules     JSONMap   `gorm:"column:rules;type:json;not null" json:"rules"`                    // {"rules": [redact.Rule]}
+	Comment   string    `gorm:"column:comment;type:varchar(500)" json:"comment,omitempty"`       // Change note
+	ChangedBy string    `gorm:"column:changed_by;type:varchar(255)" json:"changed_by,omitempty"` // X-Requested-By of the change
+	CreatedAt time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for EndpointLogRedaction
+func (EndpointLogRedaction) TableName() string {
+	return "endpoint_log_redactions"
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// EndpointTransform is one immutable version of an endpoint's input/output transform
+type EndpointTransform struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint  string    `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:uk_endpoint_version,priority:1" json:"endpoint"`
+	Version   int       `gorm:"column:version;type:int;not null;uniqueIndex:uk_endpoint_version,priority:2" json:"version"`
+	Spec      JSONMap   `gorm:"column:spec;type:json;not null" json:"spec"`                // transform.Spec
+	Comment   string    `gorm:"column:comment;type:varchar(500)" json:"comment,omitempty"` // Change note
+	CreatedAt time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for EndpointTransform
+func (EndpointTransform) TableName() string {
+	return "endpoint_transforms"
+}
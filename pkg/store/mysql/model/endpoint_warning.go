@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Endpoint warning sources
+const (
+	EndpointWarningSourceHandshake = "handshake" // Worker startup handshake
+	EndpointWarningSourceAnomaly   = "anomaly"   // Usage anomaly detection
+)
+
+// EndpointWarning is a structured, deduplicated warning about an endpoint (one row per
+// endpoint and code). It is resolved (deleted) once the condition is no longer reported.
+type EndpointWarning struct {
+	ID          int64           `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint    string          `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:uk_endpoint_code,priority:1" json:"endpoint"`
+	Code        string          `gorm:"column:code;type:varchar(64);not null;uniqueIndex:uk_endpoint_code,priority:2" json:"code"`
+	Source      string          `gorm:"column:source;type:varchar(32);not null" json:"source"`
+	Message     string          `gorm:"column:message;type:varchar(1024);not null" json:"message"`
+	Details     JSONStringArray `gorm:"column:details;type:json" json:"details,omitempty"`                                 // e.g. missing env var names
+	WorkerID    string          `gorm:"column:worker_id;type:varchar(255);not null;default:''" json:"worker_id,omitempty"` // Last worker that reported it
+	SDKVersion  string          `gorm:"column:sdk_version;type:varchar(64);not null;default:''" json:"sdk_version,omitempty"`
+	Occurrences int64           `gorm:"column:occurrences;type:bigint;not null;default:1" json:"occurrences"`
+	FirstSeenAt time.Time       `gorm:"column:first_seen_at;type:datetime(3);not null" json:"first_seen_at"`
+	LastSeenAt  time.Time       `gorm:"column:last_seen_at;type:datetime(3);not null" json:"last_seen_at"`
+}
+
+// TableName specifies the table name for EndpointWarning
+func (EndpointWarning) TableName() string {
+	return "endpoint_warnings"
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ExportCheckpoint tracks how far a dataset has been exported to external analytics storage
+type ExportCheckpoint struct {
+	Dataset        string     `gorm:"column:dataset;type:varchar(100);primaryKey" json:"dataset"`
+	LastID         int64      `gorm:"column:last_id;not null;default:0" json:"last_id"`             // Last exported row ID
+	LastTime       *time.Time `gorm:"column:last_time;type:datetime(3)" json:"last_time,omitempty"` // Last exported cursor time (for time-ordered datasets)
+	RowsExported   int64      `gorm:"column:rows_exported;not null;default:0" json:"rows_exported"` // Total rows exported
+	LastExportedAt *time.Time `gorm:"column:last_exported_at;type:datetime(3)" json:"last_exported_at,omitempty"`
+	LastObject     string     `gorm:"column:last_object;type:varchar(1024);not null;default:''" json:"last_object"` // Last object key / table written
+	LastError      string     `gorm:"column:last_error;type:varchar(1024);not null;default:''" json:"last_error"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;type:datetime(3);not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for ExportCheckpoint
+func (ExportCheckpoint) TableName() string {
+	return "export_checkpoints"
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// FederatedEndpoint is a logical endpoint backed by endpoints in several regions/clusters.
+// Tasks submitted to it are routed to one of its members.
+type FederatedEndpoint struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"column:name;type:varchar(255);not null;uniqueIndex:uk_name" json:"name"`
+	DisplayName string    `gorm:"column:display_name;type:varchar(255);not null;default:''" json:"display_name"`
+	Description string    `gorm:"column:description;type:varchar(512);not null;default:''" json:"description"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for FederatedEndpoint
+func (FederatedEndpoint) TableName() string {
+	return "federated_endpoints"
+}
+
+// FederationMember is a backing endpoint of a federated endpoint in one region
+type FederationMember struct {
+	ID         int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Federation string    `gorm:"column:federation;type:varchar(255);not null;uniqueIndex:uk_federation_endpoint,priority:1" json:"federation"`
+	Endpoint   string    `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:uk_federation_endpoint,priority:2;index:idx_endpoint" json:"endpoint"`
+	Region     string    `gorm:"column:region;type:varchar(100);not null" json:"region"`
+	Priority   int       `gorm:"column:priority;type:int;not null;default:0" json:"priority"` // Higher = preferred (e.g. nearer to clients)
+	Weight     int       `gorm:"column:weight;type:int;not null;default:100" json:"weight"`   // Percent of routed traffic kept, the rest spills to the next member
+	Enabled    bool      `gorm:"column:enabled;type:tinyint(1);not null;default:1" json:"enabled"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for FederationMember
+func (FederationMember) TableName() string {
+	return "federation_members"
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// GPU reservation statuses
+const (
+	GPUReservationScheduled = "scheduled" // Waiting for the window to start
+	GPUReservationActive    = "active"    // Window running, replicas converted
+	GPUReservationCompleted = "completed" // Window over, min replicas restored
+	GPUReservationCancelled = "cancelled" // Cancelled before or during the window
+)
+
+// GPUReservation holds capacity (spec × replicas) for an endpoint during a time window,
+// e.g. ahead of a product launch
+type GPUReservation struct {
+	ID                  int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name                string     `gorm:"column:name;type:varchar(255);not null;uniqueIndex:uk_name" json:"name"`
+	Endpoint            string     `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint" json:"endpoint"`
+	SpecName            string     `gorm:"column:spec_name;type:varchar(255);not null" json:"spec_name"`
+	Replicas            int        `gorm:"column:replicas;type:int;not null" json:"replicas"`
+	GPUCount            int        `gorm:"column:gpu_count;type:int;not null;default:0" json:"gpu_count"` // GPUs of all reserved replicas
+	Priority            int        `gorm:"column:priority;type:int;not null;default:50" json:"priority"`  // Only lower-priority scale-ups are blocked
+	StartTime           time.Time  `gorm:"column:start_time;type:datetime(3);not null;index:idx_window,priority:1" json:"start_time"`
+	EndTime             time.Time  `gorm:"column:end_time;type:datetime(3);not null;index:idx_window,priority:2" json:"end_time"`
+	Status              string     `gorm:"column:status;type:varchar(16);not null;index:idx_status" json:"status"`
+	PreviousMinReplicas int        `gorm:"column:previous_min_replicas;type:int;not null;default:0" json:"previous_min_replicas"` // Restored when the window ends
+	Description         string     `gorm:"column:description;type:varchar(512);not null;default:''" json:"description"`
+	CreatedBy           string     `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	ActivatedAt         *time.Time `gorm:"column:activated_at;type:datetime(3)" json:"activated_at,omitempty"`
+	CreatedAt           time.Time  `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GPUReservation
+func (GPUReservation) TableName() string {
+	return "gpu_reservations"
+}
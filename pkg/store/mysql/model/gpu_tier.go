@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// GPUTier routes tasks of a heterogeneous endpoint to another endpoint running the same image
+// on a different spec (e.g. A10 next to A100) when the tier's rules accept them
+type GPUTier struct {
+	ID            int64           `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint      string          `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:uk_endpoint_tier,priority:1" json:"endpoint"`
+	TierEndpoint  string          `gorm:"column:tier_endpoint;type:varchar(255);not null;uniqueIndex:uk_endpoint_tier,priority:2;index:idx_tier_endpoint" json:"tier_endpoint"`
+	Position      int             `gorm:"column:position;type:int;not null;default:0" json:"position"`               // Tiers are tried in ascending position
+	MaxInputBytes int             `gorm:"column:max_input_bytes;type:int;not null;default:0" json:"max_input_bytes"` // Largest accepted input, 0 = any size
+	Precisions    JSONStringArray `gorm:"column:precisions;type:json" json:"precisions,omitempty"`                   // Accepted requested precisions, empty = any
+	CreatedAt     time.Time       `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time       `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GPUTier
+func (GPUTier) TableName() string {
+	return "endpoint_gpu_tiers"
+}
@@ -0,0 +1,181 @@
+package model
+
+import "time"
+
+// GPU usage aggregation scopes
+const (
+	GPUUsageScopeGlobal   = "global"
+	GPUUsageScopeEndpoint = "endpoint"
+	GPUUsageScopeSpec     = "spec"
+	GPUUsageScopeWorker   = "worker"
+	GPUUsageScopeGPUType  = "gpu_type" // GPU type the tasks actually ran on
+)
+
+// GPU usage aggregation granularities
+const (
+	GPUUsageGranularityMinute = "minute"
+	GPUUsageGranularityHourly = "hourly"
+	GPUUsageGranularityDaily  = "daily"
+)
+
+// GPUUsageRecord task-level GPU usage record (one row per finished task)
+type GPUUsageRecord struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID          string    `gorm:"column:task_id;type:varchar(255);not null;uniqueIndex:uk_task_id" json:"task_id"`
+	Endpoint        string    `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint_completed,priority:1" json:"endpoint"`
+	WorkerID        string    `gorm:"column:worker_id;type:varchar(255)" json:"worker_id"`
+	SpecName        string    `gorm:"column:spec_name;type:varchar(255);index:idx_spec_name" json:"spec_name"`
+	GPUCount        int       `gorm:"column:gpu_count;not null" json:"gpu_count"`
+	GPUType         string    `gorm:"column:gpu_type;type:varchar(100)" json:"gpu_type"`
+	GPUMemoryGB     int       `gorm:"column:gpu_memory_gb" json:"gpu_memory_gb"`
+	StartedAt       time.Time `gorm:"column:started_at;type:datetime(3);not null" json:"started_at"`
+	CompletedAt     time.Time `gorm:"column:completed_at;type:datetime(3);not null;index:idx_endpoint_completed,priority:2;index:idx_completed_at" json:"completed_at"`
+	DurationSeconds int       `gorm:"column:duration_seconds;not null" json:"duration_seconds"`
+	GPUHours        float64   `gorm:"column:gpu_hours;type:decimal(12,4);not null" json:"gpu_hours"`
+	Status          string    `gorm:"column:status;type:varchar(50)" json:"status"`
+	CreatedAt       time.Time `gorm:"column:created_at;type:datetime(3);not null;default:CURRENT_TIMESTAMP(3)" json:"created_at"`
+}
+
+// TableName specifies the table name for GPUUsageRecord
+func (GPUUsageRecord) TableName() string {
+	return "gpu_usage_records"
+}
+
+// GPUUsageStatisticsMinute minute-level GPU usage aggregation
+type GPUUsageStatisticsMinute struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TimeBucket      time.Time `gorm:"column:time_bucket;type:datetime;not null;uniqueIndex:uk_time_scope,priority:1;index:idx_time_bucket;index:idx_scope_time,priority:2" json:"time_bucket"`
+	ScopeType       string    `gorm:"column:scope_type;type:varchar(50);not null;uniqueIndex:uk_time_scope,priority:2;index:idx_scope_time,priority:1" json:"scope_type"` // global, endpoint, spec, worker
+	ScopeValue      string    `gorm:"column:scope_value;type:varchar(255);not null;uniqueIndex:uk_time_scope,priority:3" json:"scope_value"`
+	TotalTasks      int       `gorm:"column:total_tasks;default:0" json:"total_tasks"`
+	CompletedTasks  int       `gorm:"column:completed_tasks;default:0" json:"completed_tasks"`
+	FailedTasks     int       `gorm:"column:failed_tasks;default:0" json:"failed_tasks"`
+	TotalGPUSeconds int64     `gorm:"column:total_gpu_seconds;default:0" json:"total_gpu_seconds"`
+	TotalGPUHours   float64   `gorm:"column:total_gpu_hours;type:decimal(12,4);default:0" json:"total_gpu_hours"`
+	AvgGPUCount     float64   `gorm:"column:avg_gpu_count;type:decimal(10,2);default:0" json:"avg_gpu_count"`
+	MaxGPUCount     int       `gorm:"column:max_gpu_count;default:0" json:"max_gpu_count"`
+	PeriodStart     time.Time `gorm:"column:period_start;type:datetime(3);not null" json:"period_start"`
+	PeriodEnd       time.Time `gorm:"column:period_end;type:datetime(3);not null" json:"period_end"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;type:datetime(3);not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for GPUUsageStatisticsMinute
+func (GPUUsageStatisticsMinute) TableName() string {
+	return "gpu_usage_statistics_minute"
+}
+
+// GPUUsageStatisticsHourly hourly GPU usage aggregation (rolled up from minute stats)
+type GPUUsageStatisticsHourly struct {
+	ID              int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TimeBucket      time.Time  `gorm:"column:time_bucket;type:datetime;not null;uniqueIndex:uk_time_scope,priority:1;index:idx_time_bucket;index:idx_scope_time,priority:2" json:"time_bucket"`
+	ScopeType       string     `gorm:"column:scope_type;type:varchar(50);not null;uniqueIndex:uk_time_scope,priority:2;index:idx_scope_time,priority:1" json:"scope_type"`
+	ScopeValue      string     `gorm:"column:scope_value;type:varchar(255);not null;uniqueIndex:uk_time_scope,priority:3" json:"scope_value"`
+	TotalTasks      int        `gorm:"column:total_tasks;default:0" json:"total_tasks"`
+	CompletedTasks  int        `gorm:"column:completed_tasks;default:0" json:"completed_tasks"`
+	FailedTasks     int        `gorm:"column:failed_tasks;default:0" json:"failed_tasks"`
+	TotalGPUSeconds int64      `gorm:"column:total_gpu_seconds;default:0" json:"total_gpu_seconds"`
+	TotalGPUHours   float64    `gorm:"column:total_gpu_hours;type:decimal(12,4);default:0" json:"total_gpu_hours"`
+	AvgGPUCount     float64    `gorm:"column:avg_gpu_count;type:decimal(10,2);default:0" json:"avg_gpu_count"`
+	MaxGPUCount     int        `gorm:"column:max_gpu_count;default:0" json:"max_gpu_count"`
+	PeakMinute      *time.Time `gorm:"column:peak_minute;type:datetime" json:"peak_minute,omitempty"`
+	PeakGPUHours    float64    `gorm:"column:peak_gpu_hours;type:decimal(12,4);default:0" json:"peak_gpu_hours"`
+	PeriodStart     time.Time  `gorm:"column:period_start;type:datetime(3);not null" json:"period_start"`
+	PeriodEnd       time.Time  `gorm:"column:period_end;type:datetime(3);not null" json:"period_end"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;type:datetime(3);not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for GPUUsageStatisticsHourly
+func (GPUUsageStatisticsHourly) TableName() string {
+	return "gpu_usage_statistics_hourly"
+}
+
+// GPUUsageStatisticsDaily daily GPU usage aggregation (rolled up from hourly stats)
+type GPUUsageStatisticsDaily struct {
+	ID              int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TimeBucket      time.Time  `gorm:"column:time_bucket;type:date;not null;uniqueIndex:uk_time_scope,priority:1;index:idx_time_bucket;index:idx_scope_time,priority:2" json:"time_bucket"`
+	ScopeType       string     `gorm:"column:scope_type;type:varchar(50);not null;uniqueIndex:uk_time_scope,priority:2;index:idx_scope_time,priority:1" json:"scope_type"`
+	ScopeValue      string     `gorm:"column:scope_value;type:varchar(255);not null;uniqueIndex:uk_time_scope,priority:3" json:"scope_value"`
+	TotalTasks      int        `gorm:"column:total_tasks;default:0" json:"total_tasks"`
+	CompletedTasks  int        `gorm:"column:completed_tasks;default:0" json:"completed_tasks"`
+	FailedTasks     int        `gorm:"column:failed_tasks;default:0" json:"failed_tasks"`
+	TotalGPUSeconds int64      `gorm:"column:total_gpu_seconds;default:0" json:"total_gpu_seconds"`
+	TotalGPUHours   float64    `gorm:"column:total_gpu_hours;type:decimal(12,4);default:0" json:"total_gpu_hours"`
+	AvgGPUCount     float64    `gorm:"column:avg_gpu_count;type:decimal(10,2);default:0" json:"avg_gpu_count"`
+	MaxGPUCount     int        `gorm:"column:max_gpu_count;default:0" json:"max_gpu_count"`
+	PeakHour        *time.Time `gorm:"column:peak_hour;type:datetime" json:"peak_hour,omitempty"`
+	PeakGPUHours    float64    `gorm:"column:peak_gpu_hours;type:decimal(12,4);default:0" json:"peak_gpu_hours"`
+	PeriodStart     time.Time  `gorm:"column:period_start;type:datetime(3);not null" json:"period_start"`
+	PeriodEnd       time.Time  `gorm:"column:period_end;type:datetime(3);not null" json:"period_end"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;type:datetime(3);not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for GPUUsageStatisticsDaily
+func (GPUUsageStatisticsDaily) TableName() string {
+	return "gpu_usage_statistics_daily"
+}
+
+// GPUUsageAggregationState tracks the aggregation watermark per granularity.
+// Shared by all replicas so whichever instance holds the aggregation lock can resume where the previous leader stopped.
+type GPUUsageAggregationState struct {
+	Granularity    string     `gorm:"column:granularity;type:varchar(20);primaryKey" json:"granularity"`
+	Watermark      time.Time  `gorm:"column:watermark;type:datetime;not null" json:"watermark"` // Buckets before this time are fully aggregated
+	LastRunAt      *time.Time `gorm:"column:last_run_at;type:datetime(3)" json:"last_run_at,omitempty"`
+	LastDurationMs int64      `gorm:"column:last_duration_ms;default:0" json:"last_duration_ms"`
+	LastBuckets    int        `gorm:"column:last_buckets;default:0" json:"last_buckets"` // Buckets aggregated in last run
+	LastError      string     `gorm:"column:last_error;type:varchar(1024);not null;default:''" json:"last_error"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;type:datetime(3);not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for GPUUsageAggregationState
+func (GPUUsageAggregationState) TableName() string {
+	return "gpu_usage_aggregation_state"
+}
+
+// GPU usage backfill job statuses
+const (
+	GPUUsageBackfillPending   = "pending"
+	GPUUsageBackfillRunning   = "running"
+	GPUUsageBackfillCompleted = "completed"
+	GPUUsageBackfillFailed    = "failed"
+	GPUUsageBackfillCancelled = "cancelled"
+)
+
+// GPU usage backfill job phases
+const (
+	GPUUsageBackfillPhaseRecords     = "records"     // Creating missing records, cursor = last task id
+	GPUUsageBackfillPhaseReaggregate = "reaggregate" // Rebuilding statistics day by day, cursor = next day
+	GPUUsageBackfillPhaseReport      = "report"      // Building the consistency report
+)
+
+// GPUUsageBackfillJob is a long-running, resumable backfill of GPU usage records. Progress is
+// stored after every batch, so whichever replica runs the backfill job continues from the cursor.
+type GPUUsageBackfillJob struct {
+	ID               int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Status           string          `gorm:"column:status;type:varchar(20);not null;index:idx_status" json:"status"`
+	Phase            string          `gorm:"column:phase;type:varchar(20);not null" json:"phase"`
+	FromTime         time.Time       `gorm:"column:from_time;type:datetime(3);not null" json:"from_time"`
+	ToTime           time.Time       `gorm:"column:to_time;type:datetime(3);not null" json:"to_time"`
+	BatchSize        int             `gorm:"column:batch_size;not null" json:"batch_size"`
+	RateLimit        int             `gorm:"column:rate_limit;not null" json:"rate_limit"`             // Tasks per second (0 = unthrottled)
+	TotalTasks       int64           `gorm:"column:total_tasks;not null;default:0" json:"total_tasks"` // Tasks without records when the job was created
+	CursorTaskID     int64           `gorm:"column:cursor_task_id;not null;default:0" json:"cursor_task_id"`
+	CursorDay        *time.Time      `gorm:"column:cursor_day;type:datetime" json:"cursor_day,omitempty"`
+	TasksProcessed   int64           `gorm:"column:tasks_processed;not null;default:0" json:"tasks_processed"`
+	RecordsCreated   int64           `gorm:"column:records_created;not null;default:0" json:"records_created"`
+	RecordsSkipped   int64           `gorm:"column:records_skipped;not null;default:0" json:"records_skipped"`
+	ErrorCount       int64           `gorm:"column:error_count;not null;default:0" json:"error_count"`
+	Errors           JSONStringArray `gorm:"column:errors;type:json" json:"errors,omitempty"` // First errors, capped
+	DaysReaggregated int             `gorm:"column:days_reaggregated;not null;default:0" json:"days_reaggregated"`
+	Report           JSONMap         `gorm:"column:report;type:json" json:"report,omitempty"` // Consistency report of a finished job
+	LastError        string          `gorm:"column:last_error;type:varchar(1024);not null;default:''" json:"last_error,omitempty"`
+	CreatedBy        string          `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	StartedAt        *time.Time      `gorm:"column:started_at;type:datetime(3)" json:"started_at,omitempty"`
+	CompletedAt      *time.Time      `gorm:"column:completed_at;type:datetime(3)" json:"completed_at,omitempty"`
+	CreatedAt        time.Time       `gorm:"column:created_at;type:datetime(3);not null" json:"created_at"`
+	UpdatedAt        time.Time       `gorm:"column:updated_at;type:datetime(3);not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for GPUUsageBackfillJob
+func (GPUUsageBackfillJob) TableName() string {
+	return "gpu_usage_backfill_jobs"
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Image copy statuses
+const (
+	ImageCopyStatusSucceeded = "succeeded"
+	ImageCopyStatusFailed    = "failed"
+)
+
+// ImageCopyRecord is the provenance of an image copied into the internal registry
+type ImageCopyRecord struct {
+	ID           int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint     string    `gorm:"column:endpoint;type:varchar(255);not null;default:'';index:idx_endpoint_created,priority:1" json:"endpoint"`
+	SourceImage  string    `gorm:"column:source_image;type:varchar(512);not null" json:"source_image"`
+	SourceDigest string    `gorm:"column:source_digest;type:varchar(100);not null;default:''" json:"source_digest"`
+	TargetImage  string    `gorm:"column:target_image;type:varchar(512);not null;default:'';index:idx_target_image,length:191" json:"target_image"` // Internal reference pinned by digest
+	MediaType    string    `gorm:"column:media_type;type:varchar(100);not null;default:''" json:"media_type"`
+	Status       string    `gorm:"column:status;type:varchar(20);not null" json:"status"` // succeeded, failed
+	BlobsCopied  int       `gorm:"column:blobs_copied;not null;default:0" json:"blobs_copied"`
+	BlobsSkipped int       `gorm:"column:blobs_skipped;not null;default:0" json:"blobs_skipped"`
+	BytesCopied  int64     `gorm:"column:bytes_copied;not null;default:0" json:"bytes_copied"`
+	Error        string    `gorm:"column:error;type:varchar(1024);not null;default:''" json:"error"`
+	DurationMs   int64     `gorm:"column:duration_ms;not null;default:0" json:"duration_ms"`
+	CreatedAt    time.Time `gorm:"column:created_at;type:datetime(3);not null;index:idx_endpoint_created,priority:2" json:"created_at"`
+}
+
+// TableName specifies the table name for ImageCopyRecord
+func (ImageCopyRecord) TableName() string {
+	return "image_copy_records"
+}
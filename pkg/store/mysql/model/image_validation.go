@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Image validation sources
+const (
+	ImageValidationSourceDeploy = "deploy" // Validation performed as part of a deploy request
+	ImageValidationSourceAPI    = "api"    // Validation requested via the validate API
+)
+
+// ImageValidationRecord stores the outcome of an image validation, per endpoint
+type ImageValidationRecord struct {
+	ID         int64           `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint   string          `gorm:"column:endpoint;type:varchar(255);not null;default:'';index:idx_endpoint_checked,priority:1" json:"endpoint"`
+	Image      string          `gorm:"column:image;type:varchar(512);not null" json:"image"`
+	Source     string          `gorm:"column:source;type:varchar(20);not null" json:"source"` // deploy, api
+	Valid      bool            `gorm:"column:valid;not null" json:"valid"`                    // Image reference format is valid
+	Exists     bool            `gorm:"column:exists;not null" json:"exists"`
+	Accessible bool            `gorm:"column:accessible;not null" json:"accessible"`
+	Rejected   bool            `gorm:"column:rejected;not null;default:false" json:"rejected"` // Deploy was rejected because of this result
+	Digest     string          `gorm:"column:digest;type:varchar(100);not null;default:''" json:"digest"`
+	MediaType  string          `gorm:"column:media_type;type:varchar(100);not null;default:''" json:"media_type"`
+	SizeBytes  int64           `gorm:"column:size_bytes;not null;default:0" json:"size_bytes"`
+	Platforms  JSONStringArray `gorm:"column:platforms;type:json" json:"platforms,omitempty"`
+	Error      string          `gorm:"column:error;type:varchar(1024);not null;default:''" json:"error"`
+	Warning    string          `gorm:"column:warning;type:varchar(1024);not null;default:''" json:"warning"`
+	DurationMs int64           `gorm:"column:duration_ms;not null;default:0" json:"duration_ms"`
+	CacheHit   bool            `gorm:"column:cache_hit;not null;default:false" json:"cache_hit"`
+	CheckedAt  time.Time       `gorm:"column:checked_at;type:datetime(3);not null;index:idx_endpoint_checked,priority:2" json:"checked_at"`
+}
+
+// TableName specifies the table name for ImageValidationRecord
+func (ImageValidationRecord) TableName() string {
+	return "image_validation_records"
+}
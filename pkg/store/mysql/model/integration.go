@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Integration kinds
+const (
+	IntegrationKindWebhook = "webhook" // Signed JSON POST of the event
+	IntegrationKindFeishu  = "feishu"  // Feishu (Lark) bot message card
+)
+
+// Integration delivery outcomes
+const (
+	DeliveryStatusSuccess = "success"
+	DeliveryStatusFailed  = "failed"
+)
+
+// Integration is a registered external callback that receives selected events
+type Integration struct {
+	ID          int64           `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name        string          `gorm:"column:name;type:varchar(255);not null;uniqueIndex:uk_name" json:"name"`
+	Kind        string          `gorm:"column:kind;type:varchar(32);not null;default:webhook" json:"kind"`
+	URL         string          `gorm:"column:url;type:varchar(1000);not null" json:"url"`
+	Events      JSONStringArray `gorm:"column:events;type:json" json:"events"`                        // Event types; empty = referenced by tasks only
+	Endpoints   JSONStringArray `gorm:"column:endpoints;type:json" json:"endpoints,omitempty"`        // Endpoint filter; empty = all endpoints
+	Secret      string          `gorm:"column:secret;type:varchar(255);not null;default:''" json:"-"` // HMAC signing secret
+	MaxRetries  int             `gorm:"column:max_retries;type:int;not null;default:3" json:"max_retries"`
+	Enabled     bool            `gorm:"column:enabled;type:tinyint(1);not null;default:1" json:"enabled"`
+	Description string          `gorm:"column:description;type:varchar(500);not null;default:''" json:"description,omitempty"`
+
+	// Delivery state
+	ConsecutiveFailures int        `gorm:"column:consecutive_failures;type:int;not null;default:0" json:"consecutive_failures"`
+	TotalDeliveries     int64      `gorm:"column:total_deliveries;type:bigint;not null;default:0" json:"total_deliveries"`
+	TotalFailures       int64      `gorm:"column:total_failures;type:bigint;not null;default:0" json:"total_failures"`
+	LastDeliveryAt      *time.Time `gorm:"column:last_delivery_at;type:datetime(3)" json:"last_delivery_at,omitempty"`
+	LastStatus          string     `gorm:"column:last_status;type:varchar(16);not null;default:''" json:"last_status,omitempty"` // success, failed
+	LastStatusCode      int        `gorm:"column:last_status_code;type:int;not null;default:0" json:"last_status_code,omitempty"`
+	LastError           string     `gorm:"column:last_error;type:varchar(1024);not null;default:''" json:"last_error,omitempty"`
+	LastAttempts        int        `gorm:"column:last_attempts;type:int;not null;default:0" json:"last_attempts,omitempty"` // Attempts of the last delivery
+
+	CreatedAt time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for Integration
+func (Integration) TableName() string {
+	return "integrations"
+}
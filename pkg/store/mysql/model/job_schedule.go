@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// Concurrency policies of a job schedule, applied when a run is due while earlier runs are
+// still active
+const (
+	JobConcurrencyForbid  = "forbid"  // Skip the new run
+	JobConcurrencyReplace = "replace" // Cancel the active runs, then start the new one
+	JobConcurrencyAllow   = "allow"   // Start the new run next to the active ones
+)
+
+// JobSchedule starts a batch job on a cron schedule (e.g. nightly evaluations). Its runs are
+// batch jobs named <schedule>-<fire time>, with -r<n> appended for retries.
+type JobSchedule struct {
+	ID                int64           `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name              string          `gorm:"column:name;type:varchar(45);not null;uniqueIndex:uk_name" json:"name"`
+	Cron              string          `gorm:"column:cron;type:varchar(100);not null" json:"cron"`
+	Timezone          string          `gorm:"column:timezone;type:varchar(64);not null;default:'UTC'" json:"timezone"`
+	SpecName          string          `gorm:"column:spec_name;type:varchar(100);not null" json:"spec_name"`
+	Image             string          `gorm:"column:image;type:varchar(500);not null" json:"image"`
+	Command           JSONStringArray `gorm:"column:command;type:json" json:"command,omitempty"`
+	Args              JSONStringArray `gorm:"column:args;type:json" json:"args,omitempty"`
+	Env               JSONMap         `gorm:"column:env;type:json" json:"env,omitempty"`
+	VolumeMounts      JSONMap         `gorm:"column:volume_mounts;type:json" json:"volume_mounts,omitempty"` // Mount path -> PVC name
+	GpuCount          int             `gorm:"column:gpu_count;type:int;not null;default:0" json:"gpu_count"`
+	ShmSize           string          `gorm:"column:shm_size;type:varchar(20);not null;default:''" json:"shm_size,omitempty"`
+	TimeoutSeconds    int             `gorm:"column:timeout_seconds;type:int;not null;default:0" json:"timeout_seconds"`
+	ConcurrencyPolicy string          `gorm:"column:concurrency_policy;type:varchar(10);not null;default:'forbid'" json:"concurrency_policy"`
+	MaxRetries        int             `gorm:"column:max_retries;type:int;not null;default:0" json:"max_retries"`                 // Retries of a failed run
+	RetryDelaySeconds int             `gorm:"column:retry_delay_seconds;type:int;not null;default:0" json:"retry_delay_seconds"` // Wait after a failure before retrying
+	HistoryLimit      int             `gorm:"column:history_limit;type:int;not null;default:20" json:"history_limit"`            // Finished runs kept
+	Notify            JSONStringArray `gorm:"column:notify;type:json" json:"notify,omitempty"`                                   // Integrations told about failed runs
+	NotifyOnSuccess   bool            `gorm:"column:notify_on_success;not null;default:false" json:"notify_on_success"`
+	Suspended         bool            `gorm:"column:suspended;not null;default:false" json:"suspended"`
+	NextRunAt         *time.Time      `gorm:"column:next_run_at;type:datetime(3);index:idx_next_run_at" json:"next_run_at,omitempty"` // Nil while suspended
+	LastScheduledAt   *time.Time      `gorm:"column:last_scheduled_at;type:datetime(3)" json:"last_scheduled_at,omitempty"`
+	LastRunName       string          `gorm:"column:last_run_name;type:varchar(63);not null;default:''" json:"last_run_name,omitempty"`
+	Message           string          `gorm:"column:message;type:varchar(512);not null;default:''" json:"message,omitempty"` // Why the last fire started no run
+	CreatedBy         string          `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	CreatedAt         time.Time       `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time       `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for JobSchedule
+func (JobSchedule) TableName() string {
+	return "job_schedules"
+}
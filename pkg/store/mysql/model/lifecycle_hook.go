@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// LifecycleHook is a hook an endpoint runs at a lifecycle point (see pkg/hooks)
+type LifecycleHook struct {
+	ID             int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint       string    `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:uk_endpoint_name,priority:1" json:"endpoint"`
+	Name           string    `gorm:"column:name;type:varchar(64);not null;uniqueIndex:uk_endpoint_name,priority:2" json:"name"`
+	Phase          string    `gorm:"column:phase;type:varchar(32);not null" json:"phase"`                                 // pre_deploy, post_deploy or pre_delete
+	Position       int       `gorm:"column:position;type:int;not null;default:0" json:"position"`                         // Hooks of a phase run in ascending position
+	Action         JSONMap   `gorm:"column:action;type:json;not null" json:"action"`                                      // {"http": ...} or {"job": ...}
+	TimeoutSeconds int       `gorm:"column:timeout_seconds;type:int;not null;default:0" json:"timeout_seconds"`           // 0 = action default
+	FailurePolicy  string    `gorm:"column:failure_policy;type:varchar(16);not null" json:"failure_policy"`               // block or warn
+	UpdatedBy      string    `gorm:"column:updated_by;type:varchar(255);not null;default:''" json:"updated_by,omitempty"` // X-Requested-By of the change
+	CreatedAt      time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for LifecycleHook
+func (LifecycleHook) TableName() string {
+	return "endpoint_lifecycle_hooks"
+}
+
+// LifecycleHookRun records one execution of a lifecycle hook (the hook activity feed)
+type LifecycleHookRun struct {
+	ID            int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint      string    `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint_started,priority:1" json:"endpoint"`
+	HookName      string    `gorm:"column:hook_name;type:varchar(64);not null" json:"hook_name"`
+	Phase         string    `gorm:"column:phase;type:varchar(32);not null" json:"phase"`
+	Operation     string    `gorm:"column:operation;type:varchar(32);not null" json:"operation"` // deploy, update or delete
+	Action        string    `gorm:"column:action;type:varchar(16);not null" json:"action"`       // http or job
+	FailurePolicy string    `gorm:"column:failure_policy;type:varchar(16);not null" json:"failure_policy"`
+	Status        string    `gorm:"column:status;type:varchar(16);not null" json:"status"`                       // succeeded, failed or timed_out
+	Blocked       bool      `gorm:"column:blocked;type:tinyint(1);not null;default:0" json:"blocked"`            // The failure stopped the operation or the remaining hooks
+	StatusCode    int       `gorm:"column:status_code;type:int;not null;default:0" json:"status_code,omitempty"` // HTTP status or job exit code
+	Output        string    `gorm:"column:output;type:text" json:"output,omitempty"`                             // Response body or job log tail
+	Error         string    `gorm:"column:error;type:varchar(1024)" json:"error,omitempty"`
+	DurationMs    int64     `gorm:"column:duration_ms;type:bigint;not null;default:0" json:"duration_ms"`
+	StartedAt     time.Time `gorm:"column:started_at;type:datetime(3);not null;index:idx_endpoint_started,priority:2" json:"started_at"`
+}
+
+// TableName specifies the table name for LifecycleHookRun
+func (LifecycleHookRun) TableName() string {
+	return "endpoint_lifecycle_hook_runs"
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Load test report statuses
+const (
+	LoadTestReportRunning   = "running"
+	LoadTestReportSucceeded = "succeeded" // The run finished; it may still have regressed
+	LoadTestReportFailed    = "failed"
+	LoadTestReportCancelled = "cancelled"
+)
+
+// LoadTest is a load test of a staging endpoint: an RPS profile of tasks built from a payload
+// corpus, run on a cron schedule or on demand. Each run stores a LoadTestReport.
+type LoadTest struct {
+	ID                   int64            `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name                 string           `gorm:"column:name;type:varchar(63);not null;uniqueIndex:uk_name" json:"name"`
+	Endpoint             string           `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint" json:"endpoint"`
+	Cron                 string           `gorm:"column:cron;type:varchar(100);not null;default:''" json:"cron,omitempty"` // Empty = on demand only
+	Timezone             string           `gorm:"column:timezone;type:varchar(64);not null;default:'UTC'" json:"timezone"`
+	Profile              LoadTestStages   `gorm:"column:profile;type:json;not null" json:"profile"`
+	Payloads             LoadTestPayloads `gorm:"column:payloads;type:json;not null" json:"payloads"` // Task inputs, sent in turn
+	TaskTimeoutSeconds   int              `gorm:"column:task_timeout_seconds;type:int;not null;default:600" json:"task_timeout_seconds"`
+	MaxLatencyIncrease   float64          `gorm:"column:max_latency_increase;not null;default:0.2" json:"max_latency_increase"`        // Relative, per latency percentile
+	MaxErrorRateIncrease float64          `gorm:"column:max_error_rate_increase;not null;default:0.01" json:"max_error_rate_increase"` // Absolute
+	HistoryLimit         int              `gorm:"column:history_limit;type:int;not null;default:30" json:"history_limit"`              // Finished reports kept
+	Notify               JSONStringArray  `gorm:"column:notify;type:json" json:"notify,omitempty"`                                     // Integrations told about failed and regressed runs
+	Suspended            bool             `gorm:"column:suspended;not null;default:false" json:"suspended"`
+	NextRunAt            *time.Time       `gorm:"column:next_run_at;type:datetime(3);index:idx_next_run_at" json:"next_run_at,omitempty"` // Nil while suspended or without cron
+	LastRunAt            *time.Time       `gorm:"column:last_run_at;type:datetime(3)" json:"last_run_at,omitempty"`
+	LastReportID         int64            `gorm:"column:last_report_id;not null;default:0" json:"last_report_id,omitempty"`
+	Message              string           `gorm:"column:message;type:varchar(512);not null;default:''" json:"message,omitempty"` // Why the last fire started no run
+	CreatedBy            string           `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	CreatedAt            time.Time        `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time        `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for LoadTest
+func (LoadTest) TableName() string {
+	return "load_tests"
+}
+
+// LoadTestReport is the outcome of one run of a load test. The summary columns allow listing
+// and trending without decoding the report.
+type LoadTestReport struct {
+	ID           int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	LoadTest     string     `gorm:"column:load_test;type:varchar(63);not null;index:idx_load_test_created,priority:1" json:"load_test"`
+	Endpoint     string     `gorm:"column:endpoint;type:varchar(255);not null" json:"endpoint"`
+	OperationID  string     `gorm:"column:operation_id;type:varchar(64);not null;default:''" json:"operation_id,omitempty"`
+	Status       string     `gorm:"column:status;type:varchar(20);not null;index:idx_status" json:"status"`
+	Image        string     `gorm:"column:image;type:varchar(500);not null;default:''" json:"image"` // Endpoint image when the run started
+	ModelVersion string     `gorm:"column:model_version;type:varchar(100);not null;default:''" json:"model_version,omitempty"`
+	TriggeredBy  string     `gorm:"column:triggered_by;type:varchar(255);not null;default:''" json:"triggered_by,omitempty"` // Requester, or schedule for cron runs
+	Requests     int        `gorm:"column:requests;not null;default:0" json:"requests"`
+	ErrorRate    float64    `gorm:"column:error_rate;not null;default:0" json:"error_rate"`
+	P50Ms        float64    `gorm:"column:p50_ms;not null;default:0" json:"p50_ms"`
+	P99Ms        float64    `gorm:"column:p99_ms;not null;default:0" json:"p99_ms"`
+	AchievedRPS  float64    `gorm:"column:achieved_rps;not null;default:0" json:"achieved_rps"`
+	PeakReplicas int        `gorm:"column:peak_replicas;not null;default:0" json:"peak_replicas"`
+	BaselineID   int64      `gorm:"column:baseline_id;not null;default:0" json:"baseline_id,omitempty"` // Report compared against, 0 = none
+	Regressed    bool       `gorm:"column:regressed;not null;default:false" json:"regressed"`
+	Error        string     `gorm:"column:error;type:varchar(1024);not null;default:''" json:"error,omitempty"`
+	Report       JSONMap    `gorm:"column:report;type:json" json:"report,omitempty"`         // Summary, curves and autoscaler behavior
+	Comparison   JSONMap    `gorm:"column:comparison;type:json" json:"comparison,omitempty"` // Against the baseline
+	StartedAt    time.Time  `gorm:"column:started_at;type:datetime(3);not null" json:"started_at"`
+	FinishedAt   *time.Time `gorm:"column:finished_at;type:datetime(3)" json:"finished_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime;index:idx_load_test_created,priority:2" json:"created_at"`
+}
+
+// TableName specifies the table name for LoadTestReport
+func (LoadTestReport) TableName() string {
+	return "load_test_reports"
+}
+
+// LoadTestStage is a step of the RPS profile of a load test
+type LoadTestStage struct {
+	DurationSeconds int     `json:"durationSeconds"`
+	RPS             float64 `json:"rps"`
+	Ramp            bool    `json:"ramp,omitempty"`
+}
+
+// LoadTestStages is a JSON column of profile stages
+type LoadTestStages []LoadTestStage
+
+// Scan implements sql.Scanner interface
+func (m *LoadTestStages) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal LoadTestStages value: %v", value)
+	}
+	result := make([]LoadTestStage, 0)
+	err := json.Unmarshal(bytes, &result)
+	*m = LoadTestStages(result)
+	return err
+}
+
+// Value implements driver.Valuer interface
+func (m LoadTestStages) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// LoadTestPayloads is a JSON column of task inputs
+type LoadTestPayloads []map[string]interface{}
+
+// Scan implements sql.Scanner interface
+func (m *LoadTestPayloads) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal LoadTestPayloads value: %v", value)
+	}
+	result := make([]map[string]interface{}, 0)
+	err := json.Unmarshal(bytes, &result)
+	*m = LoadTestPayloads(result)
+	return err
+}
+
+// Value implements driver.Valuer interface
+func (m LoadTestPayloads) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Operation statuses
+const (
+	OperationPending   = "pending"
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCancelled = "cancelled"
+)
+
+// Operation types
+const (
+	OperationTypeDeploy           = "deploy"             // Create an endpoint
+	OperationTypeRollout          = "rollout"            // Update a deployment and wait for its workers
+	OperationTypeGPUUsageBackfill = "gpu_usage_backfill" // Create missing GPU usage records (a resumable backfill job)
+	OperationTypeBlueGreen        = "blue_green"         // Update a deployment through a candidate deployment
+	OperationTypeLoadTest         = "load_test"          // Run a load test against a staging endpoint
+	OperationTypeTaskReplay       = "task_replay"        // Replay a time window of tasks (a replay job)
+	OperationTypeBatchJob         = "batch_job"          // Run a one-off batch job
+)
+
+// Operation is a long-running API action. The replica that accepted the request runs it and
+// stores its progress; any replica answers polls, and a cancel stored here is picked up by
+// the owner on its next heartbeat.
+type Operation struct {
+	ID          string     `gorm:"column:id;type:varchar(64);primaryKey" json:"id"`
+	Type        string     `gorm:"column:type;type:varchar(50);not null;index:idx_type_created,priority:1" json:"type"`
+	Target      string     `gorm:"column:target;type:varchar(255);not null;default:'';index:idx_target" json:"target,omitempty"` // e.g. the endpoint
+	Status      string     `gorm:"column:status;type:varchar(20);not null;index:idx_status" json:"status"`
+	Progress    int        `gorm:"column:progress;not null;default:0" json:"progress"` // Percent
+	Message     string     `gorm:"column:message;type:varchar(1024);not null;default:''" json:"message,omitempty"`
+	Error       string     `gorm:"column:error;type:varchar(1024);not null;default:''" json:"error,omitempty"`
+	Result      JSONMap    `gorm:"column:result;type:json" json:"result,omitempty"`
+	Owner       string     `gorm:"column:owner;type:varchar(255);not null;default:''" json:"owner,omitempty"` // Replica running the operation
+	CreatedBy   string     `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	StartedAt   *time.Time `gorm:"column:started_at;type:datetime(3)" json:"started_at,omitempty"`
+	CompletedAt *time.Time `gorm:"column:completed_at;type:datetime(3)" json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at;type:datetime(3);not null;index:idx_type_created,priority:2" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;type:datetime(3);not null" json:"updated_at"` // Heartbeat while active
+}
+
+// TableName specifies the table name for Operation
+func (Operation) TableName() string {
+	return "operations"
+}
+
+// Done reports whether the operation has finished
+func (o *Operation) Done() bool {
+	return o.Status == OperationSucceeded || o.Status == OperationFailed || o.Status == OperationCancelled
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// RegistryMirror maps an upstream registry to an in-cluster mirror / pull-through cache
+type RegistryMirror struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Upstream    string    `gorm:"column:upstream;type:varchar(255);not null;uniqueIndex:uk_upstream" json:"upstream"` // Normalized upstream registry host, e.g. docker.io
+	Mirror      string    `gorm:"column:mirror;type:varchar(512);not null" json:"mirror"`                             // Mirror registry with optional path prefix
+	Enabled     bool      `gorm:"column:enabled;not null;default:true" json:"enabled"`
+	Description string    `gorm:"column:description;type:varchar(512);not null;default:''" json:"description"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for RegistryMirror
+func (RegistryMirror) TableName() string {
+	return "registry_mirrors"
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// SamplingRule copies a fraction of an endpoint's finished tasks to the datasets store
+type SamplingRule struct {
+	ID            int64           `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint      string          `gorm:"column:endpoint;type:varchar(255);not null;uniqueIndex:uk_endpoint" json:"endpoint"`
+	SampleRate    float64         `gorm:"column:sample_rate;type:decimal(7,6);not null;default:0" json:"sample_rate"` // 0-1, e.g. 0.01 = 1% of tasks
+	IncludeFailed bool            `gorm:"column:include_failed;type:tinyint(1);not null;default:0" json:"include_failed"`
+	ScrubFields   JSONStringArray `gorm:"column:scrub_fields;type:json" json:"scrub_fields,omitempty"` // Input/output keys redacted before upload
+	Enabled       bool            `gorm:"column:enabled;type:tinyint(1);not null;default:1" json:"enabled"`
+	CreatedAt     time.Time       `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time       `gorm:"column:updated_at;type:datetime(3);not null;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for SamplingRule
+func (SamplingRule) TableName() string {
+	return "sampling_rules"
+}
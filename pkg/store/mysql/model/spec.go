@@ -14,6 +14,7 @@ type Spec struct {
 	Memory           string `gorm:"column:memory;type:varchar(50);not null" json:"memory"`
 	GPU              string `gorm:"column:gpu;type:varchar(50)" json:"gpu"`
 	GPUType          string `gorm:"column:gpu_type;type:varchar(100)" json:"gpu_type"`
+	GPUTypeFallbacks JSONStringArray `gorm:"column:gpu_type_fallbacks;type:json" json:"gpu_type_fallbacks"` // GPU types used in order when gpu_type has no capacity
 	EphemeralStorage string `gorm:"column:ephemeral_storage;type:varchar(50);not null" json:"ephemeral_storage"`
 	ShmSize          string `gorm:"column:shm_size;type:varchar(50)" json:"shm_size"`       // Shared memory size (e.g., "1Gi", "512Mi")
 	ResourceType     string `gorm:"column:resource_type;type:varchar(20);not null;default:serverless" json:"resource_type"` // fixed, serverless
@@ -11,7 +11,7 @@ import (
 type Task struct {
 	ID          int64       `gorm:"primaryKey;autoIncrement" json:"id"`
 	TaskID      string      `gorm:"column:task_id;type:varchar(255);not null;uniqueIndex:idx_task_id_unique" json:"task_id"`
-	Endpoint    string      `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint_status,priority:1" json:"endpoint"`
+	Endpoint    string      `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint_status,priority:1;index:idx_endpoint_replay,priority:1" json:"endpoint"`
 	Input       JSONMap     `gorm:"column:input;type:json;not null" json:"input"`
 	Status      string      `gorm:"column:status;type:varchar(50);not null;index:idx_status;index:idx_endpoint_status,priority:2" json:"status"`
 	Output      JSONMap     `gorm:"column:output;type:json" json:"output"`
@@ -23,6 +23,27 @@ type Task struct {
 	StartedAt   *time.Time  `gorm:"column:started_at;type:datetime(3)" json:"started_at"`
 	CompletedAt *time.Time  `gorm:"column:completed_at;type:datetime(3);index:idx_completed_at" json:"completed_at"`
 	Extend      *TaskExtend `gorm:"column:extend;type:json" json:"extend,omitempty"`
+	// TransformVersion is the endpoint transform version applied to the input (0 = none);
+	// the output transform of the same version is applied at completion
+	TransformVersion int `gorm:"column:transform_version;type:int;not null;default:0" json:"transform_version,omitempty"`
+	// SubjectHash is the SHA-256 of the caller-supplied data subject key, used to find the
+	// task for data deletion requests; the key itself is never stored
+	SubjectHash string `gorm:"column:subject_hash;type:varchar(64);index:idx_subject_hash" json:"-"`
+	// ReplayOf is the task_id of the task whose input this task replays ("" = not a replay);
+	// ReplayJobID is the replay job that submitted it (0 = replayed on its own)
+	ReplayOf    string `gorm:"column:replay_of;type:varchar(255);not null;default:'';index:idx_endpoint_replay,priority:2" json:"replay_of,omitempty"`
+	ReplayJobID int64  `gorm:"column:replay_job_id;not null;default:0;index:idx_replay_job_id" json:"replay_job_id,omitempty"`
+	// HedgeOf is the task_id of the task this task duplicates on a second worker ("" = not a
+	// hedge). HedgeOutcome is set on the original: racing, original or hedge (which copy
+	// reported first); HedgeSavedMs is how much earlier a winning hedge reported than the
+	// original, when the original reported at all.
+	HedgeOf      string `gorm:"column:hedge_of;type:varchar(255);not null;default:'';index:idx_hedge_of" json:"hedge_of,omitempty"`
+	HedgeOutcome string `gorm:"column:hedge_outcome;type:varchar(16);not null;default:''" json:"hedge_outcome,omitempty"`
+	HedgeSavedMs *int64 `gorm:"column:hedge_saved_ms" json:"hedge_saved_ms,omitempty"`
+	// InputSealed and OutputSealed record that the payload is stored sealed with the project's
+	// data key. Only flagged payloads are opened, whatever an unflagged payload looks like.
+	InputSealed  bool `gorm:"column:input_sealed;not null;default:false" json:"-"`
+	OutputSealed bool `gorm:"column:output_sealed;not null;default:false" json:"-"`
 }
 
 // TaskExtend task execution history (stored in JSON)
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Task replay job statuses
+const (
+	TaskReplayPending   = "pending"
+	TaskReplayRunning   = "running"
+	TaskReplayCompleted = "completed"
+	TaskReplayCancelled = "cancelled"
+)
+
+// TaskReplayJob replays the tasks of an endpoint submitted in a time window into a target
+// endpoint at a fixed rate. Progress is stored after every batch, so whichever replica runs
+// the replay job continues from the cursor.
+type TaskReplayJob struct {
+	ID             int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Status         string          `gorm:"column:status;type:varchar(20);not null;index:idx_status" json:"status"`
+	SourceEndpoint string          `gorm:"column:source_endpoint;type:varchar(255);not null" json:"source_endpoint"`
+	TargetEndpoint string          `gorm:"column:target_endpoint;type:varchar(255);not null" json:"target_endpoint"`
+	FromTime       time.Time       `gorm:"column:from_time;type:datetime(3);not null" json:"from_time"`
+	ToTime         time.Time       `gorm:"column:to_time;type:datetime(3);not null" json:"to_time"`
+	Statuses       JSONStringArray `gorm:"column:statuses;type:json" json:"statuses,omitempty"` // Task statuses replayed (empty = all finished)
+	RateLimit      int             `gorm:"column:rate_limit;not null" json:"rate_limit"`        // Tasks per second
+	MaxTasks       int             `gorm:"column:max_tasks;not null" json:"max_tasks"`
+	CursorTaskID   int64           `gorm:"column:cursor_task_id;not null;default:0" json:"cursor_task_id"`
+	Submitted      int64           `gorm:"column:submitted;not null;default:0" json:"submitted"`
+	Skipped        int64           `gorm:"column:skipped;not null;default:0" json:"skipped"` // Source tasks without input (anonymized or undecryptable)
+	LastError      string          `gorm:"column:last_error;type:varchar(1024);not null;default:''" json:"last_error,omitempty"`
+	CreatedBy      string          `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	StartedAt      *time.Time      `gorm:"column:started_at;type:datetime(3)" json:"started_at,omitempty"`
+	CompletedAt    *time.Time      `gorm:"column:completed_at;type:datetime(3)" json:"completed_at,omitempty"`
+	CreatedAt      time.Time       `gorm:"column:created_at;type:datetime(3);not null" json:"created_at"`
+	UpdatedAt      time.Time       `gorm:"column:updated_at;type:datetime(3);not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for TaskReplayJob
+func (TaskReplayJob) TableName() string {
+	return "task_replay_jobs"
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Worker represents a worker record in database
 type Worker struct {
@@ -13,22 +16,35 @@ type Worker struct {
 	CurrentJobs          int        `gorm:"column:current_jobs;default:0"`
 	JobsInProgress       string     `gorm:"column:jobs_in_progress;type:text"` // JSON array of task IDs
 	Version              string     `gorm:"column:version"`
+	Capabilities         *string    `gorm:"column:capabilities;type:text"`       // JSON array of announced capabilities, NULL if never declared
+	HandshakeWarnings    *string    `gorm:"column:handshake_warnings;type:text"` // JSON array of warning codes of the last handshake, NULL if never handshaked
+	CustomMetric         *float64   `gorm:"column:custom_metric"`                // Last custom autoscaling gauge reported in a heartbeat
+	CustomMetricAt       *time.Time `gorm:"column:custom_metric_at"`             // Time of the last custom metric report
 	PodCreatedAt         *time.Time `gorm:"column:pod_created_at"`
 	PodStartedAt         *time.Time `gorm:"column:pod_started_at"`
 	PodReadyAt           *time.Time `gorm:"column:pod_ready_at"`
 	ColdStartDurationMs  *int64     `gorm:"column:cold_start_duration_ms"`
+	RegisteredAt         *time.Time `gorm:"column:registered_at"`           // First heartbeat received from the worker
+	FirstTaskCompletedAt *time.Time `gorm:"column:first_task_completed_at"` // First task finished by the worker (success or failure)
 	LastHeartbeat        time.Time  `gorm:"column:last_heartbeat;not null"`
 	LastTaskTime         *time.Time `gorm:"column:last_task_time"`
 	TotalTasksCompleted  int64      `gorm:"column:total_tasks_completed;default:0"`
 	TotalTasksFailed     int64      `gorm:"column:total_tasks_failed;default:0"`
 	TotalExecutionTimeMs int64      `gorm:"column:total_execution_time_ms;default:0"`
-	RuntimeState         JSONMap    `gorm:"column:runtime_state;type:json"` // Pod runtime: phase, status, reason, message, ip, nodeName
+	RuntimeState         JSONMap    `gorm:"column:runtime_state;type:json"` // Pod runtime: phase, status, reason, message, ip, nodeName, imageDigest, gpuType
 	CreatedAt            time.Time  `gorm:"column:created_at;not null"`
 	UpdatedAt            time.Time  `gorm:"column:updated_at;not null"`
 	TerminatedAt         *time.Time `gorm:"column:terminated_at"` // Time when worker reached terminal state (pod deleted)
+	DrainReason          string     `gorm:"column:drain_reason"`  // Why the worker is DRAINING, e.g. node maintenance
+
+	// Quarantine: no new tasks, the pod is kept for inspection
+	QuarantinedAt    *time.Time `gorm:"column:quarantined_at"`    // Set while the worker is quarantined
+	QuarantineReason string     `gorm:"column:quarantine_reason"` // Why the worker is quarantined
+	QuarantinedBy    string     `gorm:"column:quarantined_by"`    // Operator who quarantined the worker
+	QuarantineBanID  *int64     `gorm:"column:quarantine_ban_id"` // Ban that quarantined the worker, nil if quarantined manually
 
 	// Failure tracking fields for image validation and status transparency
-	FailureType       string     `gorm:"column:failure_type"`              // IMAGE_PULL_FAILED, CONTAINER_CRASH, RESOURCE_LIMIT, TIMEOUT, UNKNOWN
+	FailureType       string     `gorm:"column:failure_type"`              // IMAGE_PULL_FAILED, CONTAINER_CRASH, RESOURCE_LIMIT, TIMEOUT, GPU_UNHEALTHY, UNKNOWN
 	FailureReason     string     `gorm:"column:failure_reason"`            // Sanitized user-friendly message
 	FailureDetails    string     `gorm:"column:failure_details;type:text"` // JSON with full details for debugging
 	FailureOccurredAt *time.Time `gorm:"column:failure_occurred_at"`       // Timestamp when failure was detected
@@ -37,3 +53,34 @@ type Worker struct {
 func (Worker) TableName() string {
 	return "workers"
 }
+
+// CapabilityList decodes the announced capabilities, nil if the worker never declared any
+func (w *Worker) CapabilityList() []string {
+	if w == nil || w.Capabilities == nil {
+		return nil
+	}
+	capabilities := []string{}
+	if err := json.Unmarshal([]byte(*w.Capabilities), &capabilities); err != nil {
+		return nil
+	}
+	return capabilities
+}
+
+// NodeName returns the node of the worker's pod from its runtime state, empty if unknown
+func (w *Worker) NodeName() string {
+	nodeName, _ := w.RuntimeState["nodeName"].(string)
+	return nodeName
+}
+
+// ImageDigest returns the image digest of the worker container from its runtime state, empty if unknown
+func (w *Worker) ImageDigest() string {
+	digest, _ := w.RuntimeState["imageDigest"].(string)
+	return digest
+}
+
+// GPUType returns the GPU type of the worker's node from its runtime state, empty if unknown
+// or the spec has no GPU type fallbacks (the spec's gpuType applies then)
+func (w *Worker) GPUType() string {
+	gpuType, _ := w.RuntimeState["gpuType"].(string)
+	return gpuType
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Worker ban kinds
+const (
+	WorkerBanNode  = "node"  // Value is a node name
+	WorkerBanImage = "image" // Value is an image digest (sha256:...)
+)
+
+// WorkerBan keeps the workers on a node, or running an image digest, from receiving new tasks
+type WorkerBan struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Kind      string    `gorm:"column:kind;type:varchar(20);not null;uniqueIndex:uk_kind_value,priority:1" json:"kind"`
+	Value     string    `gorm:"column:value;type:varchar(255);not null;uniqueIndex:uk_kind_value,priority:2" json:"value"`
+	Reason    string    `gorm:"column:reason;type:varchar(512);not null;default:''" json:"reason,omitempty"`
+	CreatedBy string    `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for WorkerBan
+func (WorkerBan) TableName() string {
+	return "worker_bans"
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Delivery statuses of a worker broadcast
+const (
+	BroadcastDeliveryPending = "pending" // Not acknowledged yet (sent with every heartbeat until acknowledged or expired)
+	BroadcastDeliveryAcked   = "acked"   // The worker applied the message
+	BroadcastDeliveryFailed  = "failed"  // The worker reported it could not apply the message
+)
+
+// WorkerBroadcast is a control message sent to every worker of an endpoint, e.g. to flush a
+// cache after the model weights were updated
+type WorkerBroadcast struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Endpoint  string    `gorm:"column:endpoint;type:varchar(255);not null;index:idx_endpoint_created,priority:1" json:"endpoint"`
+	Type      string    `gorm:"column:type;type:varchar(100);not null" json:"type"` // e.g. "weights-updated", "flush-cache"
+	Payload   JSONMap   `gorm:"column:payload;type:json" json:"payload,omitempty"`
+	CreatedBy string    `gorm:"column:created_by;type:varchar(255);not null;default:''" json:"created_by,omitempty"`
+	ExpiresAt time.Time `gorm:"column:expires_at;type:datetime(3);not null" json:"expires_at"` // Not delivered after this time
+	CreatedAt time.Time `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime;index:idx_endpoint_created,priority:2" json:"created_at"`
+}
+
+// TableName specifies the table name for WorkerBroadcast
+func (WorkerBroadcast) TableName() string {
+	return "worker_broadcasts"
+}
+
+// WorkerBroadcastDelivery tracks a broadcast for one of the workers it was addressed to
+type WorkerBroadcastDelivery struct {
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	BroadcastID int64      `gorm:"column:broadcast_id;not null;uniqueIndex:uk_broadcast_worker,priority:1" json:"broadcast_id"`
+	WorkerID    string     `gorm:"column:worker_id;type:varchar(255);not null;uniqueIndex:uk_broadcast_worker,priority:2;index:idx_worker_status,priority:1" json:"worker_id"`
+	Status      string     `gorm:"column:status;type:varchar(20);not null;default:pending;index:idx_worker_status,priority:2" json:"status"`
+	Message     string     `gorm:"column:message;type:varchar(512);not null;default:''" json:"message,omitempty"` // Reported by the worker with a failure
+	SentAt      *time.Time `gorm:"column:sent_at;type:datetime(3)" json:"sent_at,omitempty"`                      // First heartbeat that carried the message
+	AckedAt     *time.Time `gorm:"column:acked_at;type:datetime(3)" json:"acked_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at;type:datetime(3);not null;autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for WorkerBroadcastDelivery
+func (WorkerBroadcastDelivery) TableName() string {
+	return "worker_broadcast_deliveries"
+}
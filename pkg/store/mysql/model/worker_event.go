@@ -11,6 +11,7 @@ const (
 	EventWorkerTaskPulled    WorkerEventType = "WORKER_TASK_PULLED"    // Worker pulled a task
 	EventWorkerTaskCompleted WorkerEventType = "WORKER_TASK_COMPLETED" // Worker completed a task
 	EventWorkerOffline       WorkerEventType = "WORKER_OFFLINE"        // Worker went offline
+	EventWorkerDebugAttached WorkerEventType = "WORKER_DEBUG_ATTACHED" // Ephemeral debug container attached (audit)
 )
 
 // WorkerEvent represents a worker lifecycle event
@@ -15,8 +15,12 @@ type (
 	WorkerResourceSnapshot = model.WorkerResourceSnapshot
 
 	// Custom JSON types
-	JSONMap         = model.JSONMap
-	JSONStringArray = model.JSONStringArray
+	JSONMap                    = model.JSONMap
+	JSONStringArray            = model.JSONStringArray
+	AutoscalerMetricSource     = model.AutoscalerMetricSource
+	AutoscalerMetricSources    = model.AutoscalerMetricSources
+	AutoscalerReplicaSchedule  = model.AutoscalerReplicaSchedule
+	AutoscalerReplicaSchedules = model.AutoscalerReplicaSchedules
 )
 
 // Re-export helper functions
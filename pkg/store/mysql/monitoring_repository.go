@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -561,6 +562,16 @@ func (r *MonitoringRepository) SaveWorkerEvent(ctx context.Context, event *model
 	return r.ds.DB(ctx).Create(event).Error
 }
 
+// SaveWorkerEventOnce inserts a worker event unless one with the same event_id exists.
+// Returns false if the event was already recorded (e.g. by another replica).
+func (r *MonitoringRepository) SaveWorkerEventOnce(ctx context.Context, event *model.WorkerEvent) (bool, error) {
+	result := r.ds.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // CountWorkerEvents counts events for a worker by type
 func (r *MonitoringRepository) CountWorkerEvents(ctx context.Context, workerID, eventType string, count *int64) {
 	r.ds.DB(ctx).Model(&model.WorkerEvent{}).Where("worker_id = ? AND event_type = ?", workerID, eventType).Count(count)
@@ -583,3 +594,34 @@ func (r *MonitoringRepository) CleanupOldWorkerEvents(ctx context.Context, befor
 	}
 	return total, nil
 }
+
+// ColdStartSample holds the lifecycle timestamps of one worker used for cold start metrics
+type ColdStartSample struct {
+	WorkerID             string     `gorm:"column:worker_id"`
+	Endpoint             string     `gorm:"column:endpoint"`
+	SpecName             string     `gorm:"column:spec_name"`
+	Image                string     `gorm:"column:image"`
+	PodCreatedAt         *time.Time `gorm:"column:pod_created_at"`
+	PodStartedAt         *time.Time `gorm:"column:pod_started_at"`
+	RegisteredAt         *time.Time `gorm:"column:registered_at"`
+	FirstTaskCompletedAt *time.Time `gorm:"column:first_task_completed_at"`
+}
+
+// ListColdStartSamples returns lifecycle timestamps of workers whose pods were created in [from, to).
+// endpoint is optional.
+func (r *MonitoringRepository) ListColdStartSamples(ctx context.Context, endpoint string, from, to time.Time) ([]*ColdStartSample, error) {
+	query := r.ds.DB(ctx).
+		Table("workers w").
+		Select("w.worker_id, w.endpoint, COALESCE(e.spec_name, '') AS spec_name, COALESCE(e.image, '') AS image, w.pod_created_at, w.pod_started_at, w.registered_at, w.first_task_completed_at").
+		Joins("LEFT JOIN endpoints e ON e.endpoint = w.endpoint").
+		Where("w.pod_created_at >= ? AND w.pod_created_at < ?", from, to)
+	if endpoint != "" {
+		query = query.Where("w.endpoint = ?", endpoint)
+	}
+
+	var samples []*ColdStartSample
+	if err := query.Scan(&samples).Error; err != nil {
+		return nil, fmt.Errorf("failed to list cold start samples: %w", err)
+	}
+	return samples, nil
+}
@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// activeOperationStatuses are the statuses of operations that have not finished
+var activeOperationStatuses = []string{model.OperationPending, model.OperationRunning}
+
+// OperationFilter narrows an operation list; empty fields match everything
+type OperationFilter struct {
+	Type   string
+	Target string
+	Status string
+}
+
+// OperationRepository handles long-running operations in MySQL
+type OperationRepository struct {
+	ds *Datastore
+}
+
+// NewOperationRepository creates a new operation repository
+func NewOperationRepository(ds *Datastore) *OperationRepository {
+	return &OperationRepository{ds: ds}
+}
+
+// Create stores a new operation
+func (r *OperationRepository) Create(ctx context.Context, op *model.Operation) error {
+	if err := r.ds.DB(ctx).Create(op).Error; err != nil {
+		return fmt.Errorf("failed to create operation: %w", err)
+	}
+	return nil
+}
+
+// Get returns an operation, or nil if it does not exist
+func (r *OperationRepository) Get(ctx context.Context, id string) (*model.Operation, error) {
+	var op model.Operation
+	err := r.ds.DB(ctx).Where("id = ?", id).First(&op).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get operation: %w", err)
+	}
+	return &op, nil
+}
+
+// List returns the most recent operations matching the filter, skipping the first offset
+func (r *OperationRepository) List(ctx context.Context, filter OperationFilter, limit, offset int) ([]*model.Operation, error) {
+	var ops []*model.Operation
+	if err := r.filtered(ctx, filter).Order("created_at DESC").Limit(limit).Offset(offset).Find(&ops).Error; err != nil {
+		return nil, fmt.Errorf("failed to list operations: %w", err)
+	}
+	return ops, nil
+}
+
+// Count returns the number of operations matching the filter
+func (r *OperationRepository) Count(ctx context.Context, filter OperationFilter) (int64, error) {
+	var count int64
+	if err := r.filtered(ctx, filter).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count operations: %w", err)
+	}
+	return count, nil
+}
+
+func (r *OperationRepository) filtered(ctx context.Context, filter OperationFilter) *gorm.DB {
+	query := r.ds.DB(ctx).Model(&model.Operation{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Target != "" {
+		query = query.Where("target = ?", filter.Target)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return query
+}
+
+// SaveProgress stores the status, progress and message of an active operation and renews its
+// heartbeat. Returns false when the operation is no longer pending or running (it was
+// cancelled, or failed because its owner looked dead).
+func (r *OperationRepository) SaveProgress(ctx context.Context, op *model.Operation) (bool, error) {
+	op.UpdatedAt = time.Now()
+	result := r.ds.DB(ctx).Model(&model.Operation{}).
+		Where("id = ? AND status IN ?", op.ID, activeOperationStatuses).
+		Updates(map[string]interface{}{
+			"status":     op.Status,
+			"progress":   op.Progress,
+			"message":    op.Message,
+			"started_at": op.StartedAt,
+			"updated_at": op.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to save operation progress: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Finish stores the outcome of an active operation. Returns false when it was no longer
+// pending or running; the stored outcome (e.g. cancelled) is kept then.
+func (r *OperationRepository) Finish(ctx context.Context, op *model.Operation) (bool, error) {
+	op.UpdatedAt = time.Now()
+	result := r.ds.DB(ctx).Model(&model.Operation{}).
+		Where("id = ? AND status IN ?", op.ID, activeOperationStatuses).
+		Updates(map[string]interface{}{
+			"status":       op.Status,
+			"progress":     op.Progress,
+			"message":      op.Message,
+			"error":        op.Error,
+			"result":       op.Result,
+			"started_at":   op.StartedAt,
+			"completed_at": op.CompletedAt,
+			"updated_at":   op.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to finish operation: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Cancel cancels a pending or running operation. Returns false when it is not active.
+func (r *OperationRepository) Cancel(ctx context.Context, id string) (bool, error) {
+	now := time.Now()
+	result := r.ds.DB(ctx).Model(&model.Operation{}).
+		Where("id = ? AND status IN ?", id, activeOperationStatuses).
+		Updates(map[string]interface{}{
+			"status":       model.OperationCancelled,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel operation: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FailStale fails the active operations whose heartbeat is older than before
+func (r *OperationRepository) FailStale(ctx context.Context, before time.Time, reason string) (int64, error) {
+	now := time.Now()
+	result := r.ds.DB(ctx).Model(&model.Operation{}).
+		Where("status IN ? AND updated_at < ?", activeOperationStatuses, before).
+		Updates(map[string]interface{}{
+			"status":       model.OperationFailed,
+			"error":        reason,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to fail stale operations: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
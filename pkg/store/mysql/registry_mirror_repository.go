@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RegistryMirrorRepository handles registry mirror mapping persistence
+type RegistryMirrorRepository struct {
+	ds *Datastore
+}
+
+// NewRegistryMirrorRepository creates a new registry mirror repository
+func NewRegistryMirrorRepository(ds *Datastore) *RegistryMirrorRepository {
+	return &RegistryMirrorRepository{ds: ds}
+}
+
+// List returns all mirror mappings ordered by upstream
+func (r *RegistryMirrorRepository) List(ctx context.Context) ([]*model.RegistryMirror, error) {
+	var mirrors []*model.RegistryMirror
+	if err := r.ds.DB(ctx).Order("upstream ASC").Find(&mirrors).Error; err != nil {
+		return nil, fmt.Errorf("failed to list registry mirrors: %w", err)
+	}
+	return mirrors, nil
+}
+
+// Get returns the mapping for an upstream registry
+func (r *RegistryMirrorRepository) Get(ctx context.Context, upstream string) (*model.RegistryMirror, error) {
+	var mirror model.RegistryMirror
+	err := r.ds.DB(ctx).Where("upstream = ?", upstream).First(&mirror).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get registry mirror: %w", err)
+	}
+	return &mirror, nil
+}
+
+// Upsert creates or updates the mapping for an upstream registry
+func (r *RegistryMirrorRepository) Upsert(ctx context.Context, mirror *model.RegistryMirror) error {
+	err := r.ds.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "upstream"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mirror", "enabled", "description", "updated_at"}),
+	}).Create(mirror).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert registry mirror: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the mapping for an upstream registry
+func (r *RegistryMirrorRepository) Delete(ctx context.Context, upstream string) error {
+	if err := r.ds.DB(ctx).Where("upstream = ?", upstream).Delete(&model.RegistryMirror{}).Error; err != nil {
+		return fmt.Errorf("failed to delete registry mirror: %w", err)
+	}
+	return nil
+}
@@ -16,6 +16,31 @@ type Repository struct {
 	SpecCapacity     *SpecCapacityRepository
 	Worker           *WorkerRepository
 	Monitoring       *MonitoringRepository
+	GPUUsage         *GPUUsageRepository
+	Export           *ExportRepository
+	ImageValidation  *ImageValidationRepository
+	RegistryMirror   *RegistryMirrorRepository
+	ImageCopy        *ImageCopyRepository
+	EndpointGroup    *EndpointGroupRepository
+	Federation       *FederationRepository
+	SamplingRule     *SamplingRuleRepository
+	Transform        *EndpointTransformRepository
+	LogRedaction     *EndpointLogRedactionRepository
+	ChangeRequest    *ChangeRequestRepository
+	Integration      *IntegrationRepository
+	EndpointWarning  *EndpointWarningRepository
+	GPUReservation   *GPUReservationRepository
+	EncryptionKey    *EncryptionKeyRepository
+	DataDeletion     *DataDeletionRepository
+	TaskReplay       *TaskReplayRepository
+	GPUTier          *GPUTierRepository
+	WorkerBroadcast  *WorkerBroadcastRepository
+	BatchJob         *BatchJobRepository
+	JobSchedule      *JobScheduleRepository
+	LifecycleHook    *LifecycleHookRepository
+	WorkerBan        *WorkerBanRepository
+	Operation        *OperationRepository
+	LoadTest         *LoadTestRepository
 }
 
 // NewRepository creates a new MySQL repository with all sub-repositories
@@ -37,9 +62,51 @@ func NewRepository(dsn string, proxyConfig *config.ProxyConfig) (*Repository, er
 		SpecCapacity:     NewSpecCapacityRepository(ds),
 		Worker:           NewWorkerRepository(ds),
 		Monitoring:       NewMonitoringRepository(ds),
+		GPUUsage:         NewGPUUsageRepository(ds),
+		Export:           NewExportRepository(ds),
+		ImageValidation:  NewImageValidationRepository(ds),
+		RegistryMirror:   NewRegistryMirrorRepository(ds),
+		ImageCopy:        NewImageCopyRepository(ds),
+		EndpointGroup:    NewEndpointGroupRepository(ds),
+		Federation:       NewFederationRepository(ds),
+		SamplingRule:     NewSamplingRuleRepository(ds),
+		Transform:        NewEndpointTransformRepository(ds),
+		LogRedaction:     NewEndpointLogRedactionRepository(ds),
+		ChangeRequest:    NewChangeRequestRepository(ds),
+		Integration:      NewIntegrationRepository(ds),
+		EndpointWarning:  NewEndpointWarningRepository(ds),
+		GPUReservation:   NewGPUReservationRepository(ds),
+		EncryptionKey:    NewEncryptionKeyRepository(ds),
+		DataDeletion:     NewDataDeletionRepository(ds),
+		TaskReplay:       NewTaskReplayRepository(ds),
+		GPUTier:          NewGPUTierRepository(ds),
+		WorkerBroadcast:  NewWorkerBroadcastRepository(ds),
+		BatchJob:         NewBatchJobRepository(ds),
+		JobSchedule:      NewJobScheduleRepository(ds),
+		LifecycleHook:    NewLifecycleHookRepository(ds),
+		WorkerBan:        NewWorkerBanRepository(ds),
+		Operation:        NewOperationRepository(ds),
+		LoadTest:         NewLoadTestRepository(ds),
 	}, nil
 }
 
+// RuntimeStateSyncStats reports how many endpoint and worker runtime_state writes were skipped
+// because the state had not changed
+func (r *Repository) RuntimeStateSyncStats() *RuntimeStateSyncReport {
+	return &RuntimeStateSyncReport{
+		Refresh:   DefaultRuntimeStateRefresh.String(),
+		Endpoints: r.Endpoint.runtimeSync.stats(),
+		Workers:   r.Worker.runtimeSync.stats(),
+	}
+}
+
+// ResetRuntimeStateSync forgets every persisted runtime state so the next event of each endpoint
+// and worker is written. Call it on leadership changes: another replica may have written since.
+func (r *Repository) ResetRuntimeStateSync() {
+	r.Endpoint.runtimeSync.reset()
+	r.Worker.runtimeSync.reset()
+}
+
 // GetDatastore returns the underlying datastore for transaction support
 func (r *Repository) GetDatastore() *Datastore {
 	return r.ds
@@ -0,0 +1,117 @@
+package mysql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DefaultRuntimeStateRefresh is how long an unchanged runtime state is skipped before it is
+// written again, repairing rows changed behind the syncer's back
+const DefaultRuntimeStateRefresh = 10 * time.Minute
+
+// RuntimeStateSyncStats counts runtime_state syncs and how many of them were not written
+type RuntimeStateSyncStats struct {
+	Writes           int64   `json:"writes"`            // Changed states persisted
+	Refreshes        int64   `json:"refreshes"`         // Unchanged states persisted by the periodic full refresh
+	Skipped          int64   `json:"skipped"`           // Unchanged states not persisted
+	Tracked          int     `json:"tracked"`           // Rows whose last written state is remembered
+	ReductionPercent float64 `json:"reduction_percent"` // Share of syncs that did not hit MySQL
+}
+
+// RuntimeStateSyncReport is the runtime_state write reduction of endpoints and workers
+type RuntimeStateSyncReport struct {
+	Refresh   string                `json:"refresh"`
+	Endpoints RuntimeStateSyncStats `json:"endpoints"`
+	Workers   RuntimeStateSyncStats `json:"workers"`
+}
+
+// runtimeStateSync remembers the last runtime state written per row so watch events that
+// carry no material change (informer resyncs, unrelated field updates) skip MySQL
+type runtimeStateSync struct {
+	refresh time.Duration
+
+	mu        sync.Mutex
+	last      map[string]syncedState
+	writes    int64
+	refreshes int64
+	skipped   int64
+}
+
+type syncedState struct {
+	fingerprint string
+	writtenAt   time.Time
+}
+
+func newRuntimeStateSync(refresh time.Duration) *runtimeStateSync {
+	return &runtimeStateSync{refresh: refresh, last: make(map[string]syncedState)}
+}
+
+// runtimeStateFingerprint hashes a status and runtime state; json.Marshal sorts map keys
+func runtimeStateFingerprint(status string, state map[string]interface{}) string {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "" // Never matches, so the state is always written
+	}
+	sum := sha256.Sum256(append([]byte(status+"\x00"), data...))
+	return hex.EncodeToString(sum[:])
+}
+
+// check reports whether a state must be written: it changed, or it was last written more than
+// the refresh interval ago (refresh=true). Skips are counted here, writes in written.
+func (s *runtimeStateSync) check(key, fingerprint string, now time.Time) (write, refresh bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.last[key]
+	if !ok || fingerprint == "" || last.fingerprint != fingerprint {
+		return true, false
+	}
+	if now.Sub(last.writtenAt) >= s.refresh {
+		return true, true
+	}
+	s.skipped++
+	return false, false
+}
+
+// written records a successful write
+func (s *runtimeStateSync) written(key, fingerprint string, now time.Time, refresh bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last[key] = syncedState{fingerprint: fingerprint, writtenAt: now}
+	if refresh {
+		s.refreshes++
+	} else {
+		s.writes++
+	}
+}
+
+// forget drops the remembered state of a row written or deleted by another path
+func (s *runtimeStateSync) forget(key string) {
+	s.mu.Lock()
+	delete(s.last, key)
+	s.mu.Unlock()
+}
+
+// reset drops every remembered state, so the next event of each row is written
+func (s *runtimeStateSync) reset() {
+	s.mu.Lock()
+	s.last = make(map[string]syncedState)
+	s.mu.Unlock()
+}
+
+func (s *runtimeStateSync) stats() RuntimeStateSyncStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := RuntimeStateSyncStats{
+		Writes:    s.writes,
+		Refreshes: s.refreshes,
+		Skipped:   s.skipped,
+		Tracked:   len(s.last),
+	}
+	if total := s.writes + s.refreshes + s.skipped; total > 0 {
+		stats.ReductionPercent = float64(s.skipped) * 100 / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeStateFingerprint(t *testing.T) {
+	a := runtimeStateFingerprint("Running", map[string]interface{}{"readyReplicas": 2, "namespace": "wavespeed"})
+	b := runtimeStateFingerprint("Running", map[string]interface{}{"namespace": "wavespeed", "readyReplicas": 2})
+	assert.Equal(t, a, b, "key order must not matter")
+	assert.NotEqual(t, a, runtimeStateFingerprint("Pending", map[string]interface{}{"readyReplicas": 2, "namespace": "wavespeed"}))
+	assert.NotEqual(t, a, runtimeStateFingerprint("Running", map[string]interface{}{"readyReplicas": 1, "namespace": "wavespeed"}))
+}
+
+func TestRuntimeStateSync_SkipsUnchangedUntilRefresh(t *testing.T) {
+	s := newRuntimeStateSync(10 * time.Minute)
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	write, refresh := s.check("flux", "v1", now)
+	assert.True(t, write)
+	assert.False(t, refresh)
+	s.written("flux", "v1", now, refresh)
+
+	write, _ = s.check("flux", "v1", now.Add(time.Minute))
+	assert.False(t, write, "unchanged state is skipped")
+
+	write, refresh = s.check("flux", "v2", now.Add(2*time.Minute))
+	assert.True(t, write)
+	assert.False(t, refresh)
+	s.written("flux", "v2", now.Add(2*time.Minute), refresh)
+
+	write, refresh = s.check("flux", "v2", now.Add(12*time.Minute))
+	assert.True(t, write, "unchanged state is rewritten after the refresh interval")
+	assert.True(t, refresh)
+	s.written("flux", "v2", now.Add(12*time.Minute), refresh)
+
+	stats := s.stats()
+	assert.Equal(t, int64(2), stats.Writes)
+	assert.Equal(t, int64(1), stats.Refreshes)
+	assert.Equal(t, int64(1), stats.Skipped)
+	assert.Equal(t, 1, stats.Tracked)
+	assert.Equal(t, 25.0, stats.ReductionPercent)
+}
+
+func TestRuntimeStateSync_ForgetAndReset(t *testing.T) {
+	s := newRuntimeStateSync(10 * time.Minute)
+	now := time.Now()
+	s.written("flux", "v1", now, false)
+	s.written("sd", "v1", now, false)
+
+	// Another write path changed the row
+	s.forget("flux")
+	write, _ := s.check("flux", "v1", now)
+	assert.True(t, write)
+
+	// A failed write is not recorded, so the next event retries it
+	write, _ = s.check("flux", "v1", now)
+	assert.True(t, write)
+
+	s.reset()
+	write, _ = s.check("sd", "v1", now)
+	assert.True(t, write)
+	assert.Equal(t, 0, s.stats().Tracked)
+}
@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SamplingRuleRepository handles sampling rule persistence
+type SamplingRuleRepository struct {
+	ds *Datastore
+}
+
+// NewSamplingRuleRepository creates a new sampling rule repository
+func NewSamplingRuleRepository(ds *Datastore) *SamplingRuleRepository {
+	return &SamplingRuleRepository{ds: ds}
+}
+
+// List returns all rules ordered by endpoint
+func (r *SamplingRuleRepository) List(ctx context.Context) ([]*model.SamplingRule, error) {
+	var rules []*model.SamplingRule
+	if err := r.ds.DB(ctx).Order("endpoint ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sampling rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Get returns the rule of an endpoint, nil if it does not exist
+func (r *SamplingRuleRepository) Get(ctx context.Context, endpoint string) (*model.SamplingRule, error) {
+	var rule model.SamplingRule
+	err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).First(&rule).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sampling rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// Upsert creates or updates the rule of an endpoint
+func (r *SamplingRuleRepository) Upsert(ctx context.Context, rule *model.SamplingRule) error {
+	err := r.ds.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sample_rate", "include_failed", "scrub_fields", "enabled", "updated_at"}),
+	}).Create(rule).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert sampling rule: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the rule of an endpoint
+func (r *SamplingRuleRepository) Delete(ctx context.Context, endpoint string) error {
+	if err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Delete(&model.SamplingRule{}).Error; err != nil {
+		return fmt.Errorf("failed to delete sampling rule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HedgeTotals are the hedging counters of an endpoint over a time range
+type HedgeTotals struct {
+	Hedged           int64
+	HedgeWins        int64
+	OriginalWins     int64
+	Racing           int64
+	SavedMs          int64
+	SavedMeasured    int64
+	HedgeExecutionMs int64
+}
+
+// RecentExecutionTimes returns the execution times of the latest completed tasks of an
+// endpoint finished since since, at most limit
+func (r *TaskRepository) RecentExecutionTimes(ctx context.Context, endpoint string, since time.Time, limit int) ([]time.Duration, error) {
+	var rows []struct {
+		StartedAt   time.Time
+		CompletedAt time.Time
+	}
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Select("started_at, completed_at").
+		Where("endpoint = ? AND status = ? AND completed_at >= ? AND started_at IS NOT NULL", endpoint, "COMPLETED", since).
+		Order("completed_at DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load execution times: %w", err)
+	}
+	times := make([]time.Duration, 0, len(rows))
+	for _, row := range rows {
+		times = append(times, row.CompletedAt.Sub(row.StartedAt))
+	}
+	return times, nil
+}
+
+// ListHedgeCandidates returns the in-progress tasks of an endpoint started before
+// startedBefore that neither are nor have a hedge, longest running first
+func (r *TaskRepository) ListHedgeCandidates(ctx context.Context, endpoint string, startedBefore time.Time, limit int) ([]*Task, error) {
+	var tasks []*Task
+	err := r.ds.DB(ctx).
+		Where("endpoint = ? AND status = ? AND started_at < ? AND hedge_of = '' AND hedge_outcome = ''", endpoint, "IN_PROGRESS", startedBefore).
+		Order("started_at ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hedge candidates: %w", err)
+	}
+	if err := r.openTasks(ctx, tasks...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// MarkHedged starts the race of an in-progress task against its hedge. ok is false when the
+// task finished or was hedged in the meantime.
+func (r *TaskRepository) MarkHedged(ctx context.Context, taskID string) (ok bool, err error) {
+	result := r.ds.DB(ctx).Model(&Task{}).
+		Where("task_id = ? AND status = ? AND hedge_outcome = ''", taskID, "IN_PROGRESS").
+		Update("hedge_outcome", "racing")
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark task hedged: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ResolveHedge ends the race of a hedged task with outcome. won is false when the race was
+// already decided, i.e. the other copy reported first.
+func (r *TaskRepository) ResolveHedge(ctx context.Context, taskID, outcome string) (won bool, err error) {
+	result := r.ds.DB(ctx).Model(&Task{}).
+		Where("task_id = ? AND hedge_outcome = ?", taskID, "racing").
+		Update("hedge_outcome", outcome)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to resolve hedge: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetHedgeOf returns the hedge of a task, nil if it has none
+func (r *TaskRepository) GetHedgeOf(ctx context.Context, taskID string) (*Task, error) {
+	var task Task
+	err := r.ds.DB(ctx).Where("hedge_of = ?", taskID).First(&task).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get hedge: %w", err)
+	}
+	return &task, nil
+}
+
+// SetHedgeSaved records how much earlier the winning hedge of a task reported than the task
+func (r *TaskRepository) SetHedgeSaved(ctx context.Context, taskID string, savedMs int64) error {
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Where("task_id = ? AND hedge_saved_ms IS NULL", taskID).
+		Update("hedge_saved_ms", savedMs).Error
+	if err != nil {
+		return fmt.Errorf("failed to record hedge saving: %w", err)
+	}
+	return nil
+}
+
+// FilterHedgeLosers returns the tasks among taskIDs that a worker still runs although their
+// hedge already reported the result
+func (r *TaskRepository) FilterHedgeLosers(ctx context.Context, taskIDs []string, workerID string) ([]string, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	var matched []string
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Where("task_id IN ? AND worker_id = ? AND hedge_outcome = ?", taskIDs, workerID, "hedge").
+		Pluck("task_id", &matched).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter hedge losers: %w", err)
+	}
+	return matched, nil
+}
+
+// GetHedgeTotals sums the hedging counters of the tasks of an endpoint created in [from, to)
+func (r *TaskRepository) GetHedgeTotals(ctx context.Context, endpoint string, from, to time.Time) (*HedgeTotals, error) {
+	var totals HedgeTotals
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Select(`COUNT(*) AS hedged,
+			COALESCE(SUM(hedge_outcome = 'hedge'), 0) AS hedge_wins,
+			COALESCE(SUM(hedge_outcome = 'original'), 0) AS original_wins,
+			COALESCE(SUM(hedge_outcome = 'racing'), 0) AS racing,
+			COALESCE(SUM(hedge_saved_ms), 0) AS saved_ms,
+			COALESCE(SUM(hedge_saved_ms IS NOT NULL), 0) AS saved_measured`).
+		Where("endpoint = ? AND hedge_outcome <> '' AND created_at >= ? AND created_at < ?", endpoint, from, to).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum hedged tasks: %w", err)
+	}
+
+	// Hedges are created after their original, so a few at the end of the range may be missed
+	err = r.ds.DB(ctx).Model(&Task{}).
+		Select("COALESCE(SUM(TIMESTAMPDIFF(MICROSECOND, started_at, completed_at)) DIV 1000, 0)").
+		Where("endpoint = ? AND hedge_of <> '' AND created_at >= ? AND created_at < ? AND started_at IS NOT NULL AND completed_at IS NOT NULL", endpoint, from, to).
+		Scan(&totals.HedgeExecutionMs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum hedge execution time: %w", err)
+	}
+	return &totals, nil
+}
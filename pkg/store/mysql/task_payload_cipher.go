@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+)
+
+// PayloadCipher encrypts task input/output before they are written and decrypts them after
+// they are read, so encryption at rest is transparent to everything above the repository.
+// Encrypt reports whether it sealed the payload; payloads of endpoints without encryption
+// must pass through unchanged and unsealed. The repository records the result on the task
+// row and only calls Decrypt for payloads flagged sealed.
+type PayloadCipher interface {
+	Encrypt(ctx context.Context, endpoint, taskID, field string, payload JSONMap) (JSONMap, bool, error)
+	Decrypt(ctx context.Context, taskID, field string, payload JSONMap) (JSONMap, error)
+}
+
+// sealedColumns maps the payload columns to the columns that flag them sealed
+var sealedColumns = map[string]string{"input": "input_sealed", "output": "output_sealed"}
+
+// SetPayloadCipher sets the task payload cipher (for dependency injection)
+func (r *TaskRepository) SetPayloadCipher(cipher PayloadCipher) {
+	r.cipher = cipher
+}
+
+// sealTask encrypts the payloads of a task in place and flags the sealed ones
+func (r *TaskRepository) sealTask(ctx context.Context, task *Task) error {
+	if r.cipher == nil {
+		return nil
+	}
+	input, inputSealed, err := r.cipher.Encrypt(ctx, task.Endpoint, task.TaskID, "input", task.Input)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt task input: %w", err)
+	}
+	output, outputSealed, err := r.cipher.Encrypt(ctx, task.Endpoint, task.TaskID, "output", task.Output)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt task output: %w", err)
+	}
+	task.Input, task.Output = input, output
+	task.InputSealed, task.OutputSealed = inputSealed, outputSealed
+	return nil
+}
+
+// openTasks decrypts the flagged payloads of tasks in place; the flags are cleared, as the
+// payloads in memory are plaintext
+func (r *TaskRepository) openTasks(ctx context.Context, tasks ...*Task) error {
+	if r.cipher == nil {
+		return nil
+	}
+	for _, task := range tasks {
+		if task.InputSealed {
+			input, err := r.cipher.Decrypt(ctx, task.TaskID, "input", task.Input)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt input of task %s: %w", task.TaskID, err)
+			}
+			task.Input, task.InputSealed = input, false
+		}
+		if task.OutputSealed {
+			output, err := r.cipher.Decrypt(ctx, task.TaskID, "output", task.Output)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt output of task %s: %w", task.TaskID, err)
+			}
+			task.Output, task.OutputSealed = output, false
+		}
+	}
+	return nil
+}
+
+// sealUpdates returns updates with input/output encrypted and their sealed flags set; the
+// caller's map is not modified
+func (r *TaskRepository) sealUpdates(ctx context.Context, taskID string, updates map[string]interface{}) (map[string]interface{}, error) {
+	if r.cipher == nil {
+		return updates, nil
+	}
+	_, hasInput := updates["input"]
+	_, hasOutput := updates["output"]
+	if !hasInput && !hasOutput {
+		return updates, nil
+	}
+
+	var endpoints []string
+	if err := r.ds.DB(ctx).Model(&Task{}).Where("task_id = ?", taskID).Pluck("endpoint", &endpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to get task endpoint: %w", err)
+	}
+	if len(endpoints) == 0 {
+		return updates, nil // Nothing to update
+	}
+
+	sealed := make(map[string]interface{}, len(updates)+2)
+	for key, value := range updates {
+		sealed[key] = value
+	}
+	for key, flag := range sealedColumns {
+		value, ok := updates[key]
+		if !ok {
+			continue
+		}
+		var payload JSONMap
+		switch v := value.(type) {
+		case JSONMap:
+			payload = v
+		case map[string]interface{}:
+			payload = v
+		}
+		// Any other value (nil, an expression) is not a payload and is stored unsealed
+		encrypted, ok, err := r.cipher.Encrypt(ctx, endpoints[0], taskID, key, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt task %s: %w", key, err)
+		}
+		if ok {
+			sealed[key] = encrypted
+		}
+		sealed[flag] = ok
+	}
+	return sealed, nil
+}
@@ -0,0 +1,162 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/logger"
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// activeReplayStatuses are the statuses of replay jobs that still submit tasks
+var activeReplayStatuses = []string{model.TaskReplayPending, model.TaskReplayRunning}
+
+// TaskReplayRepository handles task replay jobs in MySQL
+type TaskReplayRepository struct {
+	ds *Datastore
+}
+
+// NewTaskReplayRepository creates a new task replay repository
+func NewTaskReplayRepository(ds *Datastore) *TaskReplayRepository {
+	return &TaskReplayRepository{ds: ds}
+}
+
+// Create stores a new replay job
+func (r *TaskReplayRepository) Create(ctx context.Context, job *model.TaskReplayJob) error {
+	if err := r.ds.DB(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create task replay job: %w", err)
+	}
+	return nil
+}
+
+// Get returns a replay job, or nil if it does not exist
+func (r *TaskReplayRepository) Get(ctx context.Context, id int64) (*model.TaskReplayJob, error) {
+	var job model.TaskReplayJob
+	err := r.ds.DB(ctx).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get task replay job: %w", err)
+	}
+	return &job, nil
+}
+
+// List returns the most recent replay jobs, optionally filtered by status and target endpoint
+func (r *TaskReplayRepository) List(ctx context.Context, status, targetEndpoint string, limit, offset int) ([]*model.TaskReplayJob, error) {
+	var jobs []*model.TaskReplayJob
+	if err := r.filtered(ctx, status, targetEndpoint).Order("id DESC").Limit(limit).Offset(offset).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list task replay jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Count returns the number of replay jobs, optionally filtered by status and target endpoint
+func (r *TaskReplayRepository) Count(ctx context.Context, status, targetEndpoint string) (int64, error) {
+	var count int64
+	if err := r.filtered(ctx, status, targetEndpoint).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count task replay jobs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *TaskReplayRepository) filtered(ctx context.Context, status, targetEndpoint string) *gorm.DB {
+	query := r.ds.DB(ctx).Model(&model.TaskReplayJob{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if targetEndpoint != "" {
+		query = query.Where("target_endpoint = ?", targetEndpoint)
+	}
+	return query
+}
+
+// ListActive returns the pending and running replay jobs, oldest first
+func (r *TaskReplayRepository) ListActive(ctx context.Context) ([]*model.TaskReplayJob, error) {
+	var jobs []*model.TaskReplayJob
+	if err := r.ds.DB(ctx).Where("status IN ?", activeReplayStatuses).Order("id ASC").Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list active task replay jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// SaveProgress stores the progress of an active replay job. Returns false when the job is no
+// longer pending or running (e.g. it was cancelled meanwhile).
+func (r *TaskReplayRepository) SaveProgress(ctx context.Context, job *model.TaskReplayJob) (bool, error) {
+	job.UpdatedAt = time.Now()
+	result := r.ds.DB(ctx).Model(&model.TaskReplayJob{}).
+		Where("id = ? AND status IN ?", job.ID, activeReplayStatuses).
+		Select("*").Omit("id", "created_at", "created_by").
+		Updates(job)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to save task replay job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Cancel cancels a pending or running replay job. Returns false when the job is not active.
+func (r *TaskReplayRepository) Cancel(ctx context.Context, id int64) (bool, error) {
+	now := time.Now()
+	result := r.ds.DB(ctx).Model(&model.TaskReplayJob{}).
+		Where("id = ? AND status IN ?", id, activeReplayStatuses).
+		Updates(map[string]interface{}{
+			"status":       model.TaskReplayCancelled,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel task replay job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListReplaySources returns the next tasks of an endpoint created in [from, to) after afterID,
+// with decrypted input. Replays are never replayed again. A task whose input cannot be
+// decrypted (e.g. its key was destroyed) is returned without input, like an anonymized task,
+// so it is skipped instead of stalling the job.
+func (r *TaskRepository) ListReplaySources(ctx context.Context, endpoint string, from, to time.Time, statuses []string, afterID int64, limit int) ([]*Task, error) {
+	var tasks []*Task
+	query := r.ds.DB(ctx).
+		Select("id", "task_id", "endpoint", "input", "input_sealed", "status", "transform_version", "subject_hash").
+		Where("endpoint = ? AND created_at >= ? AND created_at < ? AND id > ? AND replay_of = ''", endpoint, from, to, afterID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	if err := query.Order("id ASC").Limit(limit).Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list replay source tasks: %w", err)
+	}
+	for _, task := range tasks {
+		if err := r.openTasks(ctx, task); err != nil {
+			logger.WarnCtx(ctx, "replay source task %s skipped: %v", task.TaskID, err)
+			task.Input, task.Output = nil, nil
+		}
+	}
+	return tasks, nil
+}
+
+// CountReplaysByStatus counts the replay tasks of an endpoint (endpoint != "") or of a replay
+// job (jobID > 0) by status
+func (r *TaskRepository) CountReplaysByStatus(ctx context.Context, endpoint string, jobID int64) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	query := r.ds.DB(ctx).Model(&Task{}).Select("status, COUNT(*) AS count")
+	if endpoint != "" {
+		query = query.Where("endpoint = ? AND replay_of <> '' AND hedge_of = ''", endpoint)
+	}
+	if jobID > 0 {
+		query = query.Where("replay_job_id = ?", jobID)
+	}
+	if err := query.Group("status").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count replay tasks: %w", err)
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
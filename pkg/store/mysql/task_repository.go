@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,7 +12,8 @@ import (
 
 // TaskRepository handles task persistence in MySQL
 type TaskRepository struct {
-	ds *Datastore
+	ds     *Datastore
+	cipher PayloadCipher // Optional encryption of input/output at rest
 }
 
 // NewTaskRepository creates a new task repository
@@ -21,7 +23,14 @@ func NewTaskRepository(ds *Datastore) *TaskRepository {
 
 // Create creates a new task
 func (r *TaskRepository) Create(ctx context.Context, task *Task) error {
-	return r.ds.DB(ctx).Create(task).Error
+	input, output := task.Input, task.Output
+	if err := r.sealTask(ctx, task); err != nil {
+		return err
+	}
+	err := r.ds.DB(ctx).Create(task).Error
+	task.Input, task.Output = input, output
+	task.InputSealed, task.OutputSealed = false, false
+	return err
 }
 
 // Get retrieves a task by ID
@@ -34,11 +43,18 @@ func (r *TaskRepository) Get(ctx context.Context, taskID string) (*Task, error)
 		}
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
+	if err := r.openTasks(ctx, &task); err != nil {
+		return nil, err
+	}
 	return &task, nil
 }
 
 // UpdateFields updates specific fields of a task by task_id
 func (r *TaskRepository) UpdateFields(ctx context.Context, taskID string, updates map[string]interface{}) error {
+	updates, err := r.sealUpdates(ctx, taskID, updates)
+	if err != nil {
+		return err
+	}
 	return r.ds.DB(ctx).Model(&Task{}).
 		Where("task_id = ?", taskID).
 		Updates(updates).Error
@@ -48,6 +64,10 @@ func (r *TaskRepository) UpdateFields(ctx context.Context, taskID string, update
 // This prevents concurrent updates by ensuring the task status matches expectedStatus before updating
 // Returns error if task not found or status doesn't match expectedStatus
 func (r *TaskRepository) UpdateFieldsWithStatus(ctx context.Context, taskID string, expectedStatus string, updates map[string]interface{}) error {
+	updates, err := r.sealUpdates(ctx, taskID, updates)
+	if err != nil {
+		return err
+	}
 	result := r.ds.DB(ctx).Model(&Task{}).
 		Where("task_id = ? AND status = ?", taskID, expectedStatus).
 		Updates(updates)
@@ -130,6 +150,40 @@ func (r *TaskRepository) GetTasksByWorker(ctx context.Context, workerID string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks by worker: %w", err)
 	}
+	if err := r.openTasks(ctx, tasks...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// FilterTaskIDsByStatus returns the subset of task IDs that are in the given status
+func (r *TaskRepository) FilterTaskIDsByStatus(ctx context.Context, taskIDs []string, status string) ([]string, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	var matched []string
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Where("task_id IN ? AND status = ?", taskIDs, status).
+		Pluck("task_id", &matched).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter tasks by status: %w", err)
+	}
+	return matched, nil
+}
+
+// GetTimings returns the status and timestamps of tasks, without input and output
+func (r *TaskRepository) GetTimings(ctx context.Context, taskIDs []string) ([]*Task, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	var tasks []*Task
+	err := r.ds.DB(ctx).
+		Select("task_id", "status", "created_at", "started_at", "completed_at").
+		Where("task_id IN ?", taskIDs).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task timings: %w", err)
+	}
 	return tasks, nil
 }
 
@@ -145,6 +199,30 @@ func (r *TaskRepository) CountByEndpointAndStatus(ctx context.Context, endpoint,
 	return count, nil
 }
 
+// CountPendingByEndpoints counts the pending tasks of each of the endpoints; endpoints
+// without pending tasks are left out
+func (r *TaskRepository) CountPendingByEndpoints(ctx context.Context, endpoints []string) (map[string]int64, error) {
+	var rows []struct {
+		Endpoint string
+		Count    int64
+	}
+	if len(endpoints) > 0 {
+		err := r.ds.DB(ctx).Model(&Task{}).
+			Select("endpoint, COUNT(*) AS count").
+			Where("endpoint IN ? AND status = ?", endpoints, "PENDING").
+			Group("endpoint").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count pending tasks: %w", err)
+		}
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Endpoint] = row.Count
+	}
+	return counts, nil
+}
+
 // CountByStatus counts tasks by status globally
 func (r *TaskRepository) CountByStatus(ctx context.Context, status string) (int64, error) {
 	var count int64
@@ -197,6 +275,9 @@ func (r *TaskRepository) ListWithTaskID(ctx context.Context, filters map[string]
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tasks: %w", err)
 	}
+	if err := r.openTasks(ctx, tasks...); err != nil {
+		return nil, err
+	}
 	return tasks, nil
 }
 
@@ -271,6 +352,9 @@ func (r *TaskRepository) GetPendingTasksByEndpoint(ctx context.Context, endpoint
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending tasks: %w", err)
 	}
+	if err := r.openTasks(ctx, tasks...); err != nil {
+		return nil, err
+	}
 	return tasks, nil
 }
 
@@ -310,17 +394,21 @@ func (r *TaskRepository) SelectPendingTasksForUpdate(ctx context.Context, endpoi
 // SelectAndAssignTasks atomically selects PENDING tasks and assigns them to worker in one transaction
 // This prevents race condition where multiple workers grab the same task
 func (r *TaskRepository) SelectAndAssignTasks(ctx context.Context, endpoint string, limit int, workerID string) ([]*Task, error) {
+	return r.SelectAndAssignTasksWith(ctx, endpoint, limit, workerID, nil)
+}
+
+// TaskChooser picks the tasks to assign among the locked PENDING candidates (oldest first,
+// without payloads) and returns their IDs in assignment order
+type TaskChooser func(candidates []*Task) []int64
+
+// SelectAndAssignTasksWith is SelectAndAssignTasks with the assigned tasks picked by choose
+// among the oldest window PENDING tasks. A nil choose assigns all of them.
+func (r *TaskRepository) SelectAndAssignTasksWith(ctx context.Context, endpoint string, window int, workerID string, choose TaskChooser) ([]*Task, error) {
 	var assignedTasks []*Task
 
 	err := r.ds.ExecTx(ctx, func(txCtx context.Context) error {
 		// 1. SELECT FOR UPDATE to lock PENDING tasks
-		var tasks []*Task
-		err := r.ds.DB(txCtx).
-			Where("endpoint = ? AND status = ?", endpoint, "PENDING").
-			Order("id ASC").
-			Limit(limit).
-			Clauses(clause.Locking{Strength: "UPDATE"}).
-			Find(&tasks).Error
+		tasks, err := r.lockPendingTasks(txCtx, endpoint, window, workerID, choose)
 		if err != nil {
 			return fmt.Errorf("failed to select pending tasks: %w", err)
 		}
@@ -352,9 +440,54 @@ func (r *TaskRepository) SelectAndAssignTasks(ctx context.Context, endpoint stri
 		return nil, err
 	}
 
+	// Decrypt after the transaction: Save above must write the stored (encrypted) payloads back
+	if err := r.openTasks(ctx, assignedTasks...); err != nil {
+		return nil, err
+	}
 	return assignedTasks, nil
 }
 
+// lockPendingTasks locks the oldest window PENDING tasks of an endpoint and returns the ones
+// chosen, in the chosen order. Candidates are read without payloads; only the chosen tasks
+// are loaded in full. The hedge of a task is never offered to the worker running the task.
+func (r *TaskRepository) lockPendingTasks(txCtx context.Context, endpoint string, window int, workerID string, choose TaskChooser) ([]*Task, error) {
+	pending := r.ds.DB(txCtx).
+		Where("endpoint = ? AND status = ?", endpoint, "PENDING").
+		Where("(hedge_of = '' OR NOT EXISTS (SELECT 1 FROM tasks original WHERE original.task_id = tasks.hedge_of AND original.worker_id = ?))", workerID).
+		Order("id ASC").
+		Limit(window).
+		Clauses(clause.Locking{Strength: "UPDATE"})
+
+	var tasks []*Task
+	if choose == nil {
+		err := pending.Find(&tasks).Error
+		return tasks, err
+	}
+
+	var candidates []*Task
+	if err := pending.Select("id", "task_id", "created_at", "subject_hash").Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	ids := choose(candidates)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// The chosen rows are locked already
+	if err := r.ds.DB(txCtx).Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	order := make(map[int64]int, len(ids))
+	for i, id := range ids {
+		order[id] = i
+	}
+	sort.Slice(tasks, func(i, j int) bool { return order[tasks[i].ID] < order[tasks[j].ID] })
+	return tasks, nil
+}
+
 // AssignTasksToWorker atomically assigns tasks to worker (CAS update)
 // Uses status as CAS condition to ensure only PENDING tasks will be updated
 // This function completes all updates in one transaction: status, worker_id, started_at, extend
@@ -419,6 +552,9 @@ func (r *TaskRepository) AssignTasksToWorker(ctx context.Context, taskIDs []stri
 		return nil, err
 	}
 
+	if err := r.openTasks(ctx, updatedTasks...); err != nil {
+		return nil, err
+	}
 	return updatedTasks, nil
 }
 
@@ -445,3 +581,97 @@ func (r *TaskRepository) CleanupOldTasks(ctx context.Context, before time.Time)
 	}
 	return total, nil
 }
+
+// finishedTaskStatuses are the statuses whose tasks no worker touches anymore
+var finishedTaskStatuses = []string{"COMPLETED", "FAILED", "CANCELLED", "TIMEOUT"}
+
+// ListBySubject retrieves tasks of a data subject after the given id, oldest first.
+// Only id, task_id, endpoint and status are loaded.
+func (r *TaskRepository) ListBySubject(ctx context.Context, subjectHash string, afterID int64, limit int) ([]*Task, error) {
+	var tasks []*Task
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Select("id", "task_id", "endpoint", "status").
+		Where("subject_hash = ? AND id > ?", subjectHash, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks by subject: %w", err)
+	}
+	return tasks, nil
+}
+
+// PurgeTasks deletes finished tasks and their events; tasks that are still active are kept
+func (r *TaskRepository) PurgeTasks(ctx context.Context, taskIDs []string) (int64, error) {
+	if len(taskIDs) == 0 {
+		return 0, nil
+	}
+	var purged int64
+	err := r.ds.ExecTx(ctx, func(txCtx context.Context) error {
+		finished, err := r.lockFinished(txCtx, taskIDs)
+		if err != nil || len(finished) == 0 {
+			return err
+		}
+		result := r.ds.DB(txCtx).Where("task_id IN ?", finished).Delete(&Task{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge tasks: %w", result.Error)
+		}
+		purged = result.RowsAffected
+		if err := r.ds.DB(txCtx).Where("task_id IN ?", finished).Delete(&TaskEvent{}).Error; err != nil {
+			return fmt.Errorf("failed to purge task events: %w", err)
+		}
+		return nil
+	})
+	return purged, err
+}
+
+// AnonymizeTasks erases the payloads, errors and webhook of finished tasks and unlinks them
+// from their subject; the rows stay for statistics and billing
+func (r *TaskRepository) AnonymizeTasks(ctx context.Context, taskIDs []string) (int64, error) {
+	if len(taskIDs) == 0 {
+		return 0, nil
+	}
+	var anonymized int64
+	err := r.ds.ExecTx(ctx, func(txCtx context.Context) error {
+		finished, err := r.lockFinished(txCtx, taskIDs)
+		if err != nil || len(finished) == 0 {
+			return err
+		}
+		result := r.ds.DB(txCtx).Model(&Task{}).
+			Where("task_id IN ?", finished).
+			Updates(map[string]interface{}{
+				"input":         JSONMap{},
+				"input_sealed":  false,
+				"output":        nil,
+				"output_sealed": false,
+				"error":         "",
+				"webhook_url":   "",
+				"subject_hash":  "",
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to anonymize tasks: %w", result.Error)
+		}
+		anonymized = result.RowsAffected
+		err = r.ds.DB(txCtx).Model(&TaskEvent{}).
+			Where("task_id IN ?", finished).
+			Updates(map[string]interface{}{"error_message": "", "metadata": nil}).Error
+		if err != nil {
+			return fmt.Errorf("failed to anonymize task events: %w", err)
+		}
+		return nil
+	})
+	return anonymized, err
+}
+
+// lockFinished locks the finished tasks among taskIDs (SELECT FOR UPDATE) and returns their IDs
+func (r *TaskRepository) lockFinished(ctx context.Context, taskIDs []string) ([]string, error) {
+	var finished []string
+	err := r.ds.DB(ctx).Model(&Task{}).
+		Where("task_id IN ? AND status IN ?", taskIDs, finishedTaskStatuses).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Pluck("task_id", &finished).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock finished tasks: %w", err)
+	}
+	return finished, nil
+}
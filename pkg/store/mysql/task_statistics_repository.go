@@ -78,7 +78,8 @@ func (r *TaskStatisticsRepository) RefreshGlobalStatistics(ctx context.Context)
 
 	var stats TaskStats
 	// OPTIMIZATION: Use UNION ALL with idx_status index to avoid full table scan
-	// Each subquery uses the idx_status index efficiently
+	// Each subquery uses the idx_status index efficiently; replays and hedges are counted apart
+	// (CountReplaysByStatus, GetHedgeTotals)
 	err := r.ds.DB(ctx).Raw(`
 		SELECT
 			SUM(pending_count) as pending_count,
@@ -89,19 +90,19 @@ func (r *TaskStatisticsRepository) RefreshGlobalStatistics(ctx context.Context)
 			SUM(total_count) as total_count
 		FROM (
 			SELECT COUNT(*) as pending_count, 0 as in_progress_count, 0 as completed_count, 0 as failed_count, 0 as cancelled_count, COUNT(*) as total_count
-			FROM tasks WHERE status = 'PENDING'
+			FROM tasks WHERE status = 'PENDING' AND replay_of = '' AND hedge_of = ''
 			UNION ALL
 			SELECT 0, COUNT(*), 0, 0, 0, COUNT(*)
-			FROM tasks WHERE status = 'IN_PROGRESS'
+			FROM tasks WHERE status = 'IN_PROGRESS' AND replay_of = '' AND hedge_of = ''
 			UNION ALL
 			SELECT 0, 0, COUNT(*), 0, 0, COUNT(*)
-			FROM tasks WHERE status = 'COMPLETED'
+			FROM tasks WHERE status = 'COMPLETED' AND replay_of = '' AND hedge_of = ''
 			UNION ALL
 			SELECT 0, 0, 0, COUNT(*), 0, COUNT(*)
-			FROM tasks WHERE status = 'FAILED'
+			FROM tasks WHERE status = 'FAILED' AND replay_of = '' AND hedge_of = ''
 			UNION ALL
 			SELECT 0, 0, 0, 0, COUNT(*), COUNT(*)
-			FROM tasks WHERE status = 'CANCELLED'
+			FROM tasks WHERE status = 'CANCELLED' AND replay_of = '' AND hedge_of = ''
 		) AS status_counts
 	`).Scan(&stats).Error
 	if err != nil {
@@ -164,7 +165,7 @@ func (r *TaskStatisticsRepository) RefreshEndpointStatistics(ctx context.Context
 
 	var stats TaskStats
 	// OPTIMIZATION: Use UNION ALL with idx_endpoint_status composite index
-	// Each subquery can use the index (endpoint, status) efficiently
+	// Each subquery can use the index (endpoint, status) efficiently; replays and hedges are counted apart
 	err := r.ds.DB(ctx).Raw(`
 		SELECT
 			? as endpoint,
@@ -176,19 +177,19 @@ func (r *TaskStatisticsRepository) RefreshEndpointStatistics(ctx context.Context
 			SUM(total_count) as total_count
 		FROM (
 			SELECT COUNT(*) as pending_count, 0 as in_progress_count, 0 as completed_count, 0 as failed_count, 0 as cancelled_count, COUNT(*) as total_count
-			FROM tasks WHERE endpoint = ? AND status = 'PENDING'
+			FROM tasks WHERE endpoint = ? AND status = 'PENDING' AND replay_of = '' AND hedge_of = ''
 			UNION ALL
 			SELECT 0, COUNT(*), 0, 0, 0, COUNT(*)
-			FROM tasks WHERE endpoint = ? AND status = 'IN_PROGRESS'
+			FROM tasks WHERE endpoint = ? AND status = 'IN_PROGRESS' AND replay_of = '' AND hedge_of = ''
 			UNION ALL
 			SELECT 0, 0, COUNT(*), 0, 0, COUNT(*)
-			FROM tasks WHERE endpoint = ? AND status = 'COMPLETED'
+			FROM tasks WHERE endpoint = ? AND status = 'COMPLETED' AND replay_of = '' AND hedge_of = ''
 			UNION ALL
 			SELECT 0, 0, 0, COUNT(*), 0, COUNT(*)
-			FROM tasks WHERE endpoint = ? AND status = 'FAILED'
+			FROM tasks WHERE endpoint = ? AND status = 'FAILED' AND replay_of = '' AND hedge_of = ''
 			UNION ALL
 			SELECT 0, 0, 0, 0, COUNT(*), COUNT(*)
-			FROM tasks WHERE endpoint = ? AND status = 'CANCELLED'
+			FROM tasks WHERE endpoint = ? AND status = 'CANCELLED' AND replay_of = '' AND hedge_of = ''
 		) AS status_counts
 	`, endpoint, endpoint, endpoint, endpoint, endpoint, endpoint).Scan(&stats).Error
 	if err != nil {
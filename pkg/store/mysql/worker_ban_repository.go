@@ -0,0 +1,89 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// WorkerBanRepository handles node and image ban persistence
+type WorkerBanRepository struct {
+	ds *Datastore
+}
+
+// NewWorkerBanRepository creates a new worker ban repository
+func NewWorkerBanRepository(ds *Datastore) *WorkerBanRepository {
+	return &WorkerBanRepository{ds: ds}
+}
+
+// Create saves a ban
+func (r *WorkerBanRepository) Create(ctx context.Context, ban *model.WorkerBan) error {
+	if err := r.ds.DB(ctx).Create(ban).Error; err != nil {
+		return fmt.Errorf("failed to save worker ban: %w", err)
+	}
+	return nil
+}
+
+// Get returns a ban by ID, nil if it does not exist
+func (r *WorkerBanRepository) Get(ctx context.Context, id int64) (*model.WorkerBan, error) {
+	var ban model.WorkerBan
+	err := r.ds.DB(ctx).Where("id = ?", id).First(&ban).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get worker ban: %w", err)
+	}
+	return &ban, nil
+}
+
+// GetByValue returns the ban of a node or image digest, nil if it is not banned
+func (r *WorkerBanRepository) GetByValue(ctx context.Context, kind, value string) (*model.WorkerBan, error) {
+	var ban model.WorkerBan
+	err := r.ds.DB(ctx).Where("kind = ? AND value = ?", kind, value).First(&ban).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get worker ban: %w", err)
+	}
+	return &ban, nil
+}
+
+// List returns every ban, newest first
+func (r *WorkerBanRepository) List(ctx context.Context) ([]*model.WorkerBan, error) {
+	var bans []*model.WorkerBan
+	if err := r.ds.DB(ctx).Order("created_at DESC, id DESC").Find(&bans).Error; err != nil {
+		return nil, fmt.Errorf("failed to list worker bans: %w", err)
+	}
+	return bans, nil
+}
+
+// ListPage returns a page of bans, newest first
+func (r *WorkerBanRepository) ListPage(ctx context.Context, limit, offset int) ([]*model.WorkerBan, error) {
+	var bans []*model.WorkerBan
+	if err := r.ds.DB(ctx).Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&bans).Error; err != nil {
+		return nil, fmt.Errorf("failed to list worker bans: %w", err)
+	}
+	return bans, nil
+}
+
+// Count returns the number of bans
+func (r *WorkerBanRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.ds.DB(ctx).Model(&model.WorkerBan{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count worker bans: %w", err)
+	}
+	return count, nil
+}
+
+// Delete removes a ban
+func (r *WorkerBanRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.ds.DB(ctx).Where("id = ?", id).Delete(&model.WorkerBan{}).Error; err != nil {
+		return fmt.Errorf("failed to delete worker ban: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"waverless/pkg/store/mysql/model"
+
+	"gorm.io/gorm"
+)
+
+// PendingBroadcast is a broadcast still to be acknowledged by a worker
+type PendingBroadcast struct {
+	ID      int64         `gorm:"column:id"`
+	Type    string        `gorm:"column:type"`
+	Payload model.JSONMap `gorm:"column:payload"`
+}
+
+// WorkerBroadcastRepository handles worker broadcast persistence
+type WorkerBroadcastRepository struct {
+	ds *Datastore
+}
+
+// NewWorkerBroadcastRepository creates a new worker broadcast repository
+func NewWorkerBroadcastRepository(ds *Datastore) *WorkerBroadcastRepository {
+	return &WorkerBroadcastRepository{ds: ds}
+}
+
+// Create saves a broadcast with a pending delivery for each addressed worker
+func (r *WorkerBroadcastRepository) Create(ctx context.Context, broadcast *model.WorkerBroadcast, workerIDs []string) error {
+	return r.ds.ExecTx(ctx, func(ctx context.Context) error {
+		if err := r.ds.DB(ctx).Create(broadcast).Error; err != nil {
+			return fmt.Errorf("failed to save worker broadcast: %w", err)
+		}
+		if len(workerIDs) == 0 {
+			return nil
+		}
+		deliveries := make([]*model.WorkerBroadcastDelivery, 0, len(workerIDs))
+		for _, workerID := range workerIDs {
+			deliveries = append(deliveries, &model.WorkerBroadcastDelivery{
+				BroadcastID: broadcast.ID,
+				WorkerID:    workerID,
+				Status:      model.BroadcastDeliveryPending,
+			})
+		}
+		if err := r.ds.DB(ctx).Create(&deliveries).Error; err != nil {
+			return fmt.Errorf("failed to save worker broadcast deliveries: %w", err)
+		}
+		return nil
+	})
+}
+
+// Get returns a broadcast by ID, nil if it does not exist
+func (r *WorkerBroadcastRepository) Get(ctx context.Context, id int64) (*model.WorkerBroadcast, error) {
+	var broadcast model.WorkerBroadcast
+	err := r.ds.DB(ctx).Where("id = ?", id).First(&broadcast).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get worker broadcast: %w", err)
+	}
+	return &broadcast, nil
+}
+
+// ListByEndpoint returns the most recent broadcasts of an endpoint, newest first
+func (r *WorkerBroadcastRepository) ListByEndpoint(ctx context.Context, endpoint string, limit int) ([]*model.WorkerBroadcast, error) {
+	var broadcasts []*model.WorkerBroadcast
+	err := r.ds.DB(ctx).Where("endpoint = ?", endpoint).Order("created_at DESC, id DESC").Limit(limit).Find(&broadcasts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker broadcasts: %w", err)
+	}
+	return broadcasts, nil
+}
+
+// ListDeliveries returns the deliveries of a broadcast ordered by worker
+func (r *WorkerBroadcastRepository) ListDeliveries(ctx context.Context, broadcastID int64) ([]*model.WorkerBroadcastDelivery, error) {
+	var deliveries []*model.WorkerBroadcastDelivery
+	err := r.ds.DB(ctx).Where("broadcast_id = ?", broadcastID).Order("worker_id ASC").Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker broadcast deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// CountDeliveries returns the delivery count per status of each broadcast
+func (r *WorkerBroadcastRepository) CountDeliveries(ctx context.Context, broadcastIDs []int64) (map[int64]map[string]int, error) {
+	counts := make(map[int64]map[string]int, len(broadcastIDs))
+	if len(broadcastIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		BroadcastID int64  `gorm:"column:broadcast_id"`
+		Status      string `gorm:"column:status"`
+		Count       int    `gorm:"column:count"`
+	}
+	err := r.ds.DB(ctx).Model(&model.WorkerBroadcastDelivery{}).
+		Select("broadcast_id, status, COUNT(*) AS count").
+		Where("broadcast_id IN ?", broadcastIDs).
+		Group("broadcast_id, status").Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count worker broadcast deliveries: %w", err)
+	}
+	for _, row := range rows {
+		if counts[row.BroadcastID] == nil {
+			counts[row.BroadcastID] = make(map[string]int)
+		}
+		counts[row.BroadcastID][row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// ListPending returns the unexpired broadcasts a worker has not acknowledged yet, oldest first,
+// and marks them sent
+func (r *WorkerBroadcastRepository) ListPending(ctx context.Context, workerID string, now time.Time) ([]*PendingBroadcast, error) {
+	var pending []*PendingBroadcast
+	err := r.ds.DB(ctx).Table("worker_broadcast_deliveries d").
+		Select("b.id, b.type, b.payload").
+		Joins("JOIN worker_broadcasts b ON b.id = d.broadcast_id").
+		Where("d.worker_id = ? AND d.status = ? AND b.expires_at > ?", workerID, model.BroadcastDeliveryPending, now).
+		Order("b.id ASC").Scan(&pending).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending worker broadcasts: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	ids := make([]int64, 0, len(pending))
+	for _, p := range pending {
+		ids = append(ids, p.ID)
+	}
+	err = r.ds.DB(ctx).Model(&model.WorkerBroadcastDelivery{}).
+		Where("worker_id = ? AND broadcast_id IN ? AND sent_at IS NULL", workerID, ids).
+		Update("sent_at", now).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark worker broadcasts sent: %w", err)
+	}
+	return pending, nil
+}
+
+// Acknowledge records a worker's acknowledgement of a broadcast (acked or failed). Only pending
+// deliveries change, so a late duplicate acknowledgement is ignored.
+func (r *WorkerBroadcastRepository) Acknowledge(ctx context.Context, workerID string, broadcastID int64, status, message string, now time.Time) (bool, error) {
+	result := r.ds.DB(ctx).Model(&model.WorkerBroadcastDelivery{}).
+		Where("worker_id = ? AND broadcast_id = ? AND status = ?", workerID, broadcastID, model.BroadcastDeliveryPending).
+		Updates(map[string]interface{}{"status": status, "message": message, "acked_at": now})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to acknowledge worker broadcast: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -10,16 +10,18 @@ import (
 	"waverless/pkg/store/mysql/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // WorkerRepository handles worker database operations
 type WorkerRepository struct {
-	ds *Datastore
+	ds          *Datastore
+	runtimeSync *runtimeStateSync // keyed by pod name
 }
 
 // NewWorkerRepository creates a new worker repository
 func NewWorkerRepository(ds *Datastore) *WorkerRepository {
-	return &WorkerRepository{ds: ds}
+	return &WorkerRepository{ds: ds, runtimeSync: newRuntimeStateSync(DefaultRuntimeStateRefresh)}
 }
 
 // UpdateHeartbeat updates worker heartbeat, status and jobs (sets status to ONLINE/BUSY)
@@ -43,6 +45,7 @@ func (r *WorkerRepository) UpdateHeartbeat(ctx context.Context, workerID, endpoi
 		"current_jobs":     currentJobs,
 		"jobs_in_progress": string(jobsJSON),
 		"last_heartbeat":   now,
+		"registered_at":    gorm.Expr("COALESCE(registered_at, ?)", now),
 		"updated_at":       now,
 	}
 	// Only update version if provided (don't overwrite with empty)
@@ -72,21 +75,31 @@ func (r *WorkerRepository) UpdateHeartbeat(ctx context.Context, workerID, endpoi
 			Version:        version,
 			LastHeartbeat:  now,
 			LastTaskTime:   &now,
+			RegisteredAt:   &now,
 			CreatedAt:      now,
 			UpdatedAt:      now,
 		}
-		return r.ds.DB(ctx).Create(worker).Error
+		// Another replica may create the row concurrently for the same first heartbeat;
+		// in that case apply the update to the row it created
+		created := r.ds.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(worker)
+		if created.Error != nil {
+			return created.Error
+		}
+		if created.RowsAffected == 0 {
+			return r.ds.DB(ctx).Model(&model.Worker{}).
+				Where("worker_id = ?", workerID).
+				Updates(updates).Error
+		}
 	}
 
 	return nil
 }
 
-// UpsertFromPod creates or updates worker from pod watch events (status STARTING until heartbeat)
-func (r *WorkerRepository) UpsertFromPod(ctx context.Context, podName, endpoint, phase, status, reason, message, ip, nodeName string, createdAt, startedAt *time.Time) error {
+// UpsertFromPod creates or updates worker from pod watch events (status STARTING until heartbeat).
+// Events that leave the runtime state unchanged are skipped until the state is due for refresh.
+func (r *WorkerRepository) UpsertFromPod(ctx context.Context, podName, endpoint, phase, status, reason, message, ip, nodeName, imageDigest, gpuType string, createdAt, startedAt *time.Time) error {
 	now := time.Now()
 
-	logger.InfoCtx(ctx, "UpsertFromPod: pod_name=%s, endpoint=%s, phase=%s, status=%s, reason=%s", podName, endpoint, phase, status, reason)
-
 	runtimeState := map[string]interface{}{
 		"phase":    phase,
 		"status":   status,
@@ -95,6 +108,12 @@ func (r *WorkerRepository) UpsertFromPod(ctx context.Context, podName, endpoint,
 		"ip":       ip,
 		"nodeName": nodeName,
 	}
+	if imageDigest != "" {
+		runtimeState["imageDigest"] = imageDigest
+	}
+	if gpuType != "" {
+		runtimeState["gpuType"] = gpuType
+	}
 	if createdAt != nil {
 		runtimeState["createdAt"] = createdAt.Format(time.RFC3339)
 	}
@@ -102,6 +121,15 @@ func (r *WorkerRepository) UpsertFromPod(ctx context.Context, podName, endpoint,
 		runtimeState["startedAt"] = startedAt.Format(time.RFC3339)
 	}
 
+	fingerprint := runtimeStateFingerprint("", runtimeState)
+	write, refresh := r.runtimeSync.check(podName, fingerprint, now)
+	if !write {
+		logger.DebugCtx(ctx, "UpsertFromPod: runtime state of pod_name=%s unchanged, skipping", podName)
+		return nil
+	}
+
+	logger.InfoCtx(ctx, "UpsertFromPod: pod_name=%s, endpoint=%s, phase=%s, status=%s, reason=%s", podName, endpoint, phase, status, reason)
+
 	updates := map[string]interface{}{
 		"runtime_state": JSONMap(runtimeState),
 		"updated_at":    now,
@@ -170,6 +198,7 @@ func (r *WorkerRepository) UpsertFromPod(ctx context.Context, podName, endpoint,
 		logger.InfoCtx(ctx, "UpsertFromPod: updated %d worker(s) for pod_name=%s", result.RowsAffected, podName)
 	}
 
+	r.runtimeSync.written(podName, fingerprint, now, refresh)
 	return nil
 }
 
@@ -184,6 +213,49 @@ func (r *WorkerRepository) UpdateLastTaskTime(ctx context.Context, workerID stri
 		}).Error
 }
 
+// UpdateCapabilities stores the capabilities announced by the worker
+func (r *WorkerRepository) UpdateCapabilities(ctx context.Context, workerID string, capabilities []string) error {
+	capabilitiesJSON, err := json.Marshal(capabilities)
+	if err != nil {
+		return err
+	}
+	return r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("worker_id = ?", workerID).
+		Updates(map[string]interface{}{
+			"capabilities": string(capabilitiesJSON),
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+// UpdateHandshakeWarnings stores the warning codes of the worker's last handshake
+func (r *WorkerRepository) UpdateHandshakeWarnings(ctx context.Context, workerID string, codes []string) error {
+	if codes == nil {
+		codes = []string{}
+	}
+	codesJSON, err := json.Marshal(codes)
+	if err != nil {
+		return err
+	}
+	return r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("worker_id = ?", workerID).
+		Updates(map[string]interface{}{
+			"handshake_warnings": string(codesJSON),
+			"updated_at":         time.Now(),
+		}).Error
+}
+
+// UpdateCustomMetric stores the custom autoscaling gauge reported by the worker
+func (r *WorkerRepository) UpdateCustomMetric(ctx context.Context, workerID string, value float64) error {
+	now := time.Now()
+	return r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("worker_id = ?", workerID).
+		Updates(map[string]interface{}{
+			"custom_metric":    value,
+			"custom_metric_at": now,
+			"updated_at":       now,
+		}).Error
+}
+
 // UpdateStatus updates worker status
 func (r *WorkerRepository) UpdateStatus(ctx context.Context, workerID string, status string) error {
 	return r.ds.DB(ctx).Model(&model.Worker{}).
@@ -194,6 +266,67 @@ func (r *WorkerRepository) UpdateStatus(ctx context.Context, workerID string, st
 		}).Error
 }
 
+// MarkDraining marks a worker DRAINING and records why
+func (r *WorkerRepository) MarkDraining(ctx context.Context, workerID, reason string) error {
+	return r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("worker_id = ?", workerID).
+		Updates(map[string]interface{}{
+			"status":       constants.WorkerStatusDraining.String(),
+			"drain_reason": reason,
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+// Quarantine stops a worker from receiving new tasks and records why. banID is the ban that
+// matched the worker, nil for a manual quarantine.
+func (r *WorkerRepository) Quarantine(ctx context.Context, workerID, reason, by string, banID *int64) error {
+	now := time.Now()
+	return r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("worker_id = ?", workerID).
+		Updates(map[string]interface{}{
+			"quarantined_at":    now,
+			"quarantine_reason": reason,
+			"quarantined_by":    by,
+			"quarantine_ban_id": banID,
+			"updated_at":        now,
+		}).Error
+}
+
+// Release lifts the quarantine of a worker
+func (r *WorkerRepository) Release(ctx context.Context, workerID string) error {
+	return r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("worker_id = ?", workerID).
+		Updates(releaseUpdates()).Error
+}
+
+// ReleaseByBan lifts the quarantine of every worker quarantined by a ban
+func (r *WorkerRepository) ReleaseByBan(ctx context.Context, banID int64) (int64, error) {
+	result := r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("quarantine_ban_id = ?", banID).
+		Updates(releaseUpdates())
+	return result.RowsAffected, result.Error
+}
+
+// GetByQuarantineBans lists the active workers quarantined by any of the bans
+func (r *WorkerRepository) GetByQuarantineBans(ctx context.Context, banIDs []int64) ([]*model.Worker, error) {
+	var workers []*model.Worker
+	if len(banIDs) == 0 {
+		return workers, nil
+	}
+	err := r.ds.DB(ctx).Where("quarantine_ban_id IN ? AND status != ?", banIDs, constants.WorkerStatusOffline).Find(&workers).Error
+	return workers, err
+}
+
+func releaseUpdates() map[string]interface{} {
+	return map[string]interface{}{
+		"quarantined_at":    nil,
+		"quarantine_reason": "",
+		"quarantined_by":    "",
+		"quarantine_ban_id": nil,
+		"updated_at":        time.Now(),
+	}
+}
+
 // IncrementTaskStats increments task completion statistics
 func (r *WorkerRepository) IncrementTaskStats(ctx context.Context, workerID string, completed bool, executionTimeMs int64) error {
 	return r.IncrementTaskStatsAt(ctx, workerID, completed, executionTimeMs, time.Now())
@@ -203,6 +336,7 @@ func (r *WorkerRepository) IncrementTaskStats(ctx context.Context, workerID stri
 func (r *WorkerRepository) IncrementTaskStatsAt(ctx context.Context, workerID string, completed bool, executionTimeMs int64, completedAt time.Time) error {
 	updates := map[string]interface{}{
 		"last_task_time":          completedAt,
+		"first_task_completed_at": gorm.Expr("COALESCE(first_task_completed_at, ?)", completedAt),
 		"total_execution_time_ms": gorm.Expr("total_execution_time_ms + ?", executionTimeMs),
 		"updated_at":              completedAt,
 	}
@@ -251,6 +385,33 @@ func (r *WorkerRepository) GetByEndpoint(ctx context.Context, endpoint string) (
 	return workers, err
 }
 
+// ListByEndpoint lists a page of the active workers of an endpoint, oldest first
+func (r *WorkerRepository) ListByEndpoint(ctx context.Context, endpoint string, limit, offset int) ([]*model.Worker, error) {
+	var workers []*model.Worker
+	err := r.ds.DB(ctx).Where("endpoint = ? AND status != ?", endpoint, constants.WorkerStatusOffline).
+		Order("id ASC").Limit(limit).Offset(offset).
+		Find(&workers).Error
+	return workers, err
+}
+
+// CountByEndpoint counts the active workers of an endpoint
+func (r *WorkerRepository) CountByEndpoint(ctx context.Context, endpoint string) (int64, error) {
+	var count int64
+	err := r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("endpoint = ? AND status != ?", endpoint, constants.WorkerStatusOffline).
+		Count(&count).Error
+	return count, err
+}
+
+// CountReady counts the workers of an endpoint that take tasks (ONLINE or BUSY)
+func (r *WorkerRepository) CountReady(ctx context.Context, endpoint string) (int64, error) {
+	var count int64
+	err := r.ds.DB(ctx).Model(&model.Worker{}).
+		Where("endpoint = ? AND status IN ?", endpoint, []string{constants.WorkerStatusOnline.String(), constants.WorkerStatusBusy.String()}).
+		Count(&count).Error
+	return count, err
+}
+
 // GetByEndpointForSync lists workers for an endpoint including recently terminated ones
 // Used by Portal for billing sync - includes OFFLINE workers terminated within the last hour
 func (r *WorkerRepository) GetByEndpointForSync(ctx context.Context, endpoint string) ([]*model.Worker, error) {
@@ -292,6 +453,7 @@ func (r *WorkerRepository) GetStaleWorkers(ctx context.Context, threshold time.T
 
 // MarkOfflineByPodName marks a specific worker as offline by pod name
 func (r *WorkerRepository) MarkOfflineByPodName(ctx context.Context, podName string) error {
+	r.runtimeSync.forget(podName)
 	now := time.Now()
 	return r.ds.DB(ctx).Model(&model.Worker{}).
 		Where("pod_name = ?", podName).
@@ -305,6 +467,7 @@ func (r *WorkerRepository) MarkOfflineByPodName(ctx context.Context, podName str
 
 // Delete deletes a worker record
 func (r *WorkerRepository) Delete(ctx context.Context, workerID string) error {
+	r.runtimeSync.forget(workerID) // Pod-created workers use the pod name as worker ID
 	return r.ds.DB(ctx).Where("worker_id = ?", workerID).Delete(&model.Worker{}).Error
 }
 
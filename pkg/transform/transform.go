@@ -0,0 +1,208 @@
+// Package transform applies lightweight, declarative transforms to task input (at submit)
+// and output (at completion) so small contract changes do not need a new worker image.
+//
+// A transform is a list of operations on dotted paths into the JSON document:
+//
+//	rename  move the value at path to `to`
+//	copy    copy the value at path to `to`
+//	default set path to value when it is missing or null
+//	set     set path to value
+//	remove  delete path
+//	redact  replace the value at path with "[REDACTED]" when present
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Redacted replaces values removed by the redact operation
+const Redacted = "[REDACTED]"
+
+// Supported operations
+const (
+	OpRename  = "rename"
+	OpCopy    = "copy"
+	OpDefault = "default"
+	OpSet     = "set"
+	OpRemove  = "remove"
+	OpRedact  = "redact"
+)
+
+// Op is a single transform operation
+type Op struct {
+	Op    string      `json:"op"`              // rename, copy, default, set, remove, redact
+	Path  string      `json:"path"`            // Dotted path, e.g. "params.temperature"
+	To    string      `json:"to,omitempty"`    // Destination path (rename, copy)
+	Value interface{} `json:"value,omitempty"` // Value (default, set)
+}
+
+// Spec is the transform of one endpoint
+type Spec struct {
+	Input  []Op `json:"input,omitempty"`  // Applied to task input at submit time
+	Output []Op `json:"output,omitempty"` // Applied to task output at completion
+}
+
+// Validate checks every operation of the spec
+func (s *Spec) Validate() error {
+	for i, op := range s.Input {
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("input[%d]: %w", i, err)
+		}
+	}
+	for i, op := range s.Output {
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("output[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (o *Op) validate() error {
+	if o.Path == "" || hasEmptySegment(o.Path) {
+		return fmt.Errorf("invalid path %q", o.Path)
+	}
+	switch o.Op {
+	case OpRename, OpCopy:
+		if o.To == "" || hasEmptySegment(o.To) {
+			return fmt.Errorf("%s requires a valid destination path, got %q", o.Op, o.To)
+		}
+	case OpDefault, OpSet:
+		if o.Value == nil {
+			return fmt.Errorf("%s requires a value", o.Op)
+		}
+	case OpRemove, OpRedact:
+	default:
+		return fmt.Errorf("unsupported op %q", o.Op)
+	}
+	return nil
+}
+
+func hasEmptySegment(path string) bool {
+	for _, seg := range strings.Split(path, ".") {
+		if seg == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns a transformed copy of doc; doc itself is not modified.
+// Paths through non-object values are skipped rather than failing the task.
+func Apply(ops []Op, doc map[string]interface{}) (map[string]interface{}, error) {
+	if len(ops) == 0 {
+		return doc, nil
+	}
+	out, err := deepCopy(doc)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		switch op.Op {
+		case OpRename:
+			if v, ok := get(out, op.Path); ok {
+				remove(out, op.Path)
+				set(out, op.To, v)
+			}
+		case OpCopy:
+			if v, ok := get(out, op.Path); ok {
+				c, err := deepCopyValue(v)
+				if err != nil {
+					return nil, err
+				}
+				set(out, op.To, c)
+			}
+		case OpDefault:
+			if v, ok := get(out, op.Path); !ok || v == nil {
+				set(out, op.Path, op.Value)
+			}
+		case OpSet:
+			set(out, op.Path, op.Value)
+		case OpRemove:
+			remove(out, op.Path)
+		case OpRedact:
+			if _, ok := get(out, op.Path); ok {
+				set(out, op.Path, Redacted)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported op %q", op.Op)
+		}
+	}
+	return out, nil
+}
+
+// get returns the value at path
+func get(doc map[string]interface{}, path string) (interface{}, bool) {
+	segs := strings.Split(path, ".")
+	cur := doc
+	for _, seg := range segs[:len(segs)-1] {
+		next, ok := cur[seg].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur = next
+	}
+	v, ok := cur[segs[len(segs)-1]]
+	return v, ok
+}
+
+// set writes value at path, creating intermediate objects; a non-object on the way aborts
+func set(doc map[string]interface{}, path string, value interface{}) {
+	segs := strings.Split(path, ".")
+	cur := doc
+	for _, seg := range segs[:len(segs)-1] {
+		switch next := cur[seg].(type) {
+		case map[string]interface{}:
+			cur = next
+		case nil:
+			m := make(map[string]interface{})
+			cur[seg] = m
+			cur = m
+		default:
+			return
+		}
+	}
+	cur[segs[len(segs)-1]] = value
+}
+
+// remove deletes path
+func remove(doc map[string]interface{}, path string) {
+	segs := strings.Split(path, ".")
+	cur := doc
+	for _, seg := range segs[:len(segs)-1] {
+		next, ok := cur[seg].(map[string]interface{})
+		if !ok {
+			return
+		}
+		cur = next
+	}
+	delete(cur, segs[len(segs)-1])
+}
+
+func deepCopy(doc map[string]interface{}) (map[string]interface{}, error) {
+	if doc == nil {
+		return make(map[string]interface{}), nil
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy document: %w", err)
+	}
+	out := make(map[string]interface{})
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to copy document: %w", err)
+	}
+	return out, nil
+}
+
+func deepCopyValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy value: %w", err)
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to copy value: %w", err)
+	}
+	return out, nil
+}
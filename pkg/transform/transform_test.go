@@ -0,0 +1,67 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	input := map[string]interface{}{
+		"prompt": "a cat",
+		"params": map[string]interface{}{"steps": float64(20)},
+		"user":   map[string]interface{}{"email": "a@b.io"},
+		"debug":  true,
+	}
+	ops := []Op{
+		{Op: OpRename, Path: "prompt", To: "inputs.text"},
+		{Op: OpDefault, Path: "params.steps", Value: 30},
+		{Op: OpDefault, Path: "params.guidance", Value: 7.5},
+		{Op: OpCopy, Path: "params", To: "legacy_params"},
+		{Op: OpSet, Path: "version", Value: "v2"},
+		{Op: OpRedact, Path: "user.email"},
+		{Op: OpRedact, Path: "user.phone"},
+		{Op: OpRemove, Path: "debug"},
+	}
+
+	out, err := Apply(ops, input)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"inputs":        map[string]interface{}{"text": "a cat"},
+		"params":        map[string]interface{}{"steps": float64(20), "guidance": 7.5},
+		"legacy_params": map[string]interface{}{"steps": float64(20), "guidance": 7.5},
+		"user":          map[string]interface{}{"email": Redacted},
+		"version":       "v2",
+	}, out)
+	assert.Equal(t, "a cat", input["prompt"], "the original document is not modified")
+	assert.Equal(t, true, input["debug"])
+}
+
+func TestApply_SkipsPathsThroughScalars(t *testing.T) {
+	out, err := Apply([]Op{
+		{Op: OpSet, Path: "prompt.text", Value: "x"},
+		{Op: OpRename, Path: "missing.field", To: "other"},
+	}, map[string]interface{}{"prompt": "a cat"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"prompt": "a cat"}, out)
+}
+
+func TestSpecValidate(t *testing.T) {
+	valid := &Spec{
+		Input:  []Op{{Op: OpRename, Path: "a", To: "b"}, {Op: OpDefault, Path: "c.d", Value: 1}},
+		Output: []Op{{Op: OpRedact, Path: "secret"}},
+	}
+	assert.NoError(t, valid.Validate())
+
+	for name, spec := range map[string]*Spec{
+		"unknown op":     {Input: []Op{{Op: "eval", Path: "a"}}},
+		"empty path":     {Input: []Op{{Op: OpRemove, Path: ""}}},
+		"empty segment":  {Output: []Op{{Op: OpRemove, Path: "a..b"}}},
+		"rename no dest": {Input: []Op{{Op: OpRename, Path: "a"}}},
+		"set no value":   {Input: []Op{{Op: OpSet, Path: "a"}}},
+	} {
+		assert.Error(t, spec.Validate(), name)
+	}
+}
@@ -0,0 +1,46 @@
+// Package wake supports wake-on-request for endpoints scaled to zero: a submission may wait a
+// bounded time for the first worker, and is otherwise told when to come back based on the
+// endpoint's recent cold starts.
+package wake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ColdStart estimates how long an endpoint takes from pod creation to its first worker: the
+// median of recent cold starts, fallback without any
+func ColdStart(samples []time.Duration, fallback time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return fallback
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return samples[(len(samples)-1)/2]
+}
+
+// RetryAfter returns the Retry-After delay of a submission that waited waited for a cold
+// start estimated at estimate: the expected remaining time in whole seconds, at least 1s
+func RetryAfter(estimate, waited time.Duration) time.Duration {
+	remaining := (estimate - waited + time.Second - 1).Truncate(time.Second)
+	return max(remaining, time.Second)
+}
+
+// ParseWait parses the wait_ready parameter of a submission: a number of seconds, or true to
+// wait for the whole budget. The wait is capped at budget; empty and false mean no wait.
+func ParseWait(v string, budget time.Duration) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return min(time.Duration(seconds)*time.Second, budget), nil
+	}
+	switch v {
+	case "true":
+		return budget, nil
+	case "false":
+		return 0, nil
+	}
+	return 0, fmt.Errorf("invalid wait_ready %q: expected seconds or true", v)
+}
@@ -0,0 +1,43 @@
+package wake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColdStart(t *testing.T) {
+	assert.Equal(t, 30*time.Second, ColdStart(nil, 30*time.Second))
+	assert.Equal(t, 40*time.Second, ColdStart([]time.Duration{90 * time.Second, 40 * time.Second, 20 * time.Second}, 0))
+	assert.Equal(t, 20*time.Second, ColdStart([]time.Duration{40 * time.Second, 20 * time.Second}, 0))
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryAfter(40*time.Second, 10*time.Second))
+	assert.Equal(t, 3*time.Second, RetryAfter(2500*time.Millisecond, 0), "rounded up to whole seconds")
+	assert.Equal(t, time.Second, RetryAfter(10*time.Second, time.Minute), "cold start overdue")
+}
+
+func TestParseWait(t *testing.T) {
+	budget := time.Minute
+	for v, want := range map[string]time.Duration{
+		"":      0,
+		"false": 0,
+		"0":     0,
+		"true":  budget,
+		"1":     time.Second,
+		"15":    15 * time.Second,
+		"600":   budget,
+	} {
+		got, err := ParseWait(v, budget)
+		require.NoError(t, err, v)
+		assert.Equal(t, want, got, v)
+	}
+
+	for _, v := range []string{"-1", "soon", "1.5"} {
+		_, err := ParseWait(v, budget)
+		assert.ErrorContains(t, err, "invalid wait_ready", v)
+	}
+}
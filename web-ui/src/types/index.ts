@@ -48,6 +48,7 @@ export interface AppInfo {
   enablePtrace?: boolean; // Enable SYS_PTRACE capability for debugging
   // Health status fields
   healthStatus?: string; // HEALTHY, DEGRADED, UNHEALTHY
+  healthReason?: string; // Health reason code (IMAGE_PULL_FAILED, CONTAINER_CRASH)
   healthMessage?: string; // User-friendly health message
 }
 
@@ -105,6 +106,11 @@ export interface PVCInfo {
   createdAt: string;
 }
 
+export interface EgressPolicy {
+  allowedCIDRs?: string[]; // CIDR blocks or single IPs
+  allowedHosts?: string[]; // DNS names, resolved when the policy is rendered
+}
+
 export interface DeployRequest {
   endpoint: string;
   specName: string;
@@ -118,6 +124,8 @@ export interface DeployRequest {
   volumeMounts?: VolumeMount[];
   shmSize?: string; // Shared memory size (e.g., "1Gi", "512Mi")
   enablePtrace?: boolean; // Enable SYS_PTRACE capability (only for fixed resource pools)
+  egressPolicy?: EgressPolicy; // Deny all egress except the allowlist (K8s only)
+  restrictedServiceAccount?: boolean; // Run workers under a service account without API access (K8s only)
   // Auto-scaling configuration (optional)
   minReplicas?: number;
   maxReplicas?: number;
@@ -220,6 +228,7 @@ export interface Worker {
   last_heartbeat: string;
   last_task_time?: string; // Last time a task was completed (for idle tracking)
   version?: string;
+  capabilities?: string[]; // Announced capabilities (streaming, cancellation, checkpointing, batch)
   registered_at: string;
 }
 